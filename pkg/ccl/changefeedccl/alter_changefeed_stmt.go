@@ -206,6 +206,11 @@ func alterChangefeedPlanHook(
 
 		newDetails := jobRecord.Details.(jobspb.ChangefeedDetails)
 		newDetails.Opts[changefeedbase.OptInitialScan] = ``
+		// Keep the transactional ID, so that the altered changefeed still aborts
+		// the transactions its kafka producers left open.
+		if newDetails.KafkaTransactionalID != `` && prevDetails.KafkaTransactionalID != `` {
+			newDetails.KafkaTransactionalID = prevDetails.KafkaTransactionalID
+		}
 
 		// newStatementTime will either be the StatementTime of the job prior to the
 		// alteration, or it will be the high watermark of the job.
@@ -466,6 +471,8 @@ func generateAndValidateNewTargets(
 			TableID:           targetSpec.TableID,
 			FamilyName:        targetSpec.FamilyName,
 			StatementTimeName: string(targetSpec.StatementTimeName),
+			DatabaseName:      targetSpec.DatabaseName,
+			SchemaName:        targetSpec.SchemaName,
 		}
 		return nil
 	})
@@ -746,8 +753,10 @@ func generateNewProgress(
 	prevHighWater := prevProgress.GetHighWater()
 	changefeedProgress := prevProgress.GetChangefeed()
 	ptsRecord := uuid.UUID{}
+	var kafkaTransactionalIDs []string
 	if changefeedProgress != nil {
 		ptsRecord = changefeedProgress.ProtectedTimestampRecord
+		kafkaTransactionalIDs = changefeedProgress.KafkaTransactionalIDs
 	}
 
 	haveHighwater := !(prevHighWater == nil || prevHighWater.IsEmpty())
@@ -792,6 +801,7 @@ func generateNewProgress(
 						Spans: existingTargetSpans,
 					},
 					ProtectedTimestampRecord: ptsRecord,
+					KafkaTransactionalIDs:    kafkaTransactionalIDs,
 				},
 			},
 		}
@@ -822,6 +832,7 @@ func generateNewProgress(
 					Spans: mergedSpanGroup.Slice(),
 				},
 				ProtectedTimestampRecord: ptsRecord,
+				KafkaTransactionalIDs:    kafkaTransactionalIDs,
 			},
 		},
 	}
@@ -169,6 +169,9 @@ type avroMetadata map[string]interface{}
 type avroEnvelopeOpts struct {
 	beforeField, afterField, recordField bool
 	updatedField, resolvedField          bool
+	// sourceField, opField and tsMsField are the metadata fields of debezium
+	// envelopes.
+	sourceField, opField, tsMsField bool
 }
 
 // avroEnvelopeRecord is an `avroRecord` that wraps a changed SQL row and some
@@ -178,6 +181,7 @@ type avroEnvelopeRecord struct {
 
 	opts                  avroEnvelopeOpts
 	before, after, record *avroDataRecord
+	source                *avroRecord
 }
 
 // typeToAvroSchema converts a database type to an avro field
@@ -603,6 +607,16 @@ func typeToAvroSchema(typ *types.T) (*avroSchemaField, error) {
 				return tree.ParseDJSON(x.(string))
 			},
 		)
+	case types.JsonpathFamily:
+		setNullable(
+			avroSchemaString,
+			func(d tree.Datum, _ interface{}) (interface{}, error) {
+				return d.(*tree.DJsonpath).Jsonpath.String(), nil
+			},
+			func(x interface{}) (tree.Datum, error) {
+				return tree.ParseDJsonpath(x.(string))
+			},
+		)
 	case types.TSQueryFamily:
 		setNullable(
 			avroSchemaString,
@@ -940,6 +954,31 @@ func envelopeToAvroSchema(
 		}
 		schema.Fields = append(schema.Fields, afterField)
 	}
+	if opts.sourceField {
+		schema.source = debeziumSourceToAvroSchema(topic, namespace)
+		sourceField := &avroSchemaField{
+			Name:       `source`,
+			SchemaType: []avroSchemaType{avroSchemaNull, schema.source},
+			Default:    nil,
+		}
+		schema.Fields = append(schema.Fields, sourceField)
+	}
+	if opts.opField {
+		opField := &avroSchemaField{
+			Name:       `op`,
+			SchemaType: []avroSchemaType{avroSchemaNull, avroSchemaString},
+			Default:    nil,
+		}
+		schema.Fields = append(schema.Fields, opField)
+	}
+	if opts.tsMsField {
+		tsMsField := &avroSchemaField{
+			Name:       `ts_ms`,
+			SchemaType: []avroSchemaType{avroSchemaNull, avroSchemaLong},
+			Default:    nil,
+		}
+		schema.Fields = append(schema.Fields, tsMsField)
+	}
 	if opts.updatedField {
 		updatedField := &avroSchemaField{
 			SchemaType: []avroSchemaType{avroSchemaNull, avroSchemaString},
@@ -977,6 +1016,55 @@ func envelopeToAvroSchema(
 	return schema, nil
 }
 
+// debeziumSourceToAvroSchema returns the avro record schema of the source
+// block of debezium envelopes.
+func debeziumSourceToAvroSchema(topic, namespace string) *avroRecord {
+	field := func(name string, typ avroSchemaType) *avroSchemaField {
+		return &avroSchemaField{
+			Name:       name,
+			SchemaType: []avroSchemaType{avroSchemaNull, typ},
+			Default:    nil,
+		}
+	}
+	return &avroRecord{
+		Name:       SQLNameToAvroName(topic) + `_source`,
+		SchemaType: `record`,
+		Namespace:  namespace,
+		Fields: []*avroSchemaField{
+			field(`version`, avroSchemaString),
+			field(`connector`, avroSchemaString),
+			field(`name`, avroSchemaString),
+			field(`cluster_id`, avroSchemaString),
+			field(`db`, avroSchemaString),
+			field(`schema`, avroSchemaString),
+			field(`table`, avroSchemaString),
+			field(`snapshot`, avroSchemaString),
+			field(`ts_ms`, avroSchemaLong),
+			field(`mvcc_timestamp`, avroSchemaString),
+		},
+	}
+}
+
+// avroNative returns the go "native" representation of the source block, as
+// expected by the codec of debeziumSourceToAvroSchema.
+func (s debeziumSource) avroNative() map[string]interface{} {
+	str := func(v string) interface{} {
+		return goavro.Union(avroUnionKey(avroSchemaString), v)
+	}
+	return map[string]interface{}{
+		`version`:        str(s.version),
+		`connector`:      str(s.connector),
+		`name`:           str(s.name),
+		`cluster_id`:     str(s.clusterID),
+		`db`:             str(s.db),
+		`schema`:         str(s.schema),
+		`table`:          str(s.table),
+		`snapshot`:       str(s.snapshot),
+		`ts_ms`:          goavro.Union(avroUnionKey(avroSchemaLong), s.tsMs),
+		`mvcc_timestamp`: str(s.mvccTimestamp),
+	}
+}
+
 // BinaryFromRow encodes the given metadata and row data into avro's defined
 // binary format.
 func (r *avroEnvelopeRecord) BinaryFromRow(
@@ -1019,6 +1107,42 @@ func (r *avroEnvelopeRecord) BinaryFromRow(
 		}
 	}
 
+	if r.opts.sourceField {
+		native[`source`] = nil
+		if u, ok := meta[`source`]; ok {
+			delete(meta, `source`)
+			source, ok := u.(debeziumSource)
+			if !ok {
+				return nil, changefeedbase.WithTerminalError(
+					errors.Errorf(`unknown metadata source type: %T`, u))
+			}
+			native[`source`] = goavro.Union(avroUnionKey(r.source), source.avroNative())
+		}
+	}
+	if r.opts.opField {
+		native[`op`] = nil
+		if u, ok := meta[`op`]; ok {
+			delete(meta, `op`)
+			op, ok := u.(string)
+			if !ok {
+				return nil, changefeedbase.WithTerminalError(
+					errors.Errorf(`unknown metadata op type: %T`, u))
+			}
+			native[`op`] = goavro.Union(avroUnionKey(avroSchemaString), op)
+		}
+	}
+	if r.opts.tsMsField {
+		native[`ts_ms`] = nil
+		if u, ok := meta[`ts_ms`]; ok {
+			delete(meta, `ts_ms`)
+			ts, ok := u.(hlc.Timestamp)
+			if !ok {
+				return nil, changefeedbase.WithTerminalError(
+					errors.Errorf(`unknown metadata timestamp type: %T`, u))
+			}
+			native[`ts_ms`] = goavro.Union(avroUnionKey(avroSchemaLong), timestampToMillis(ts))
+		}
+	}
 	if r.opts.updatedField {
 		native[`updated`] = nil
 		if u, ok := meta[`updated`]; ok {
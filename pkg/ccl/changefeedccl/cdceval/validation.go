@@ -266,7 +266,7 @@ func normalizeSelectClause(
 			if udts == nil {
 				udts = make(map[oid.Oid]struct{})
 			}
-			udts[typ.Oid()] = struct{}{}
+			udts[typ.UserDefinedTypeOID()] = struct{}{}
 		}
 		return typ, nil
 	}
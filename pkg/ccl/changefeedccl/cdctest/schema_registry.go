@@ -29,9 +29,10 @@ type SchemaRegistry struct {
 	statusCode int
 	mu         struct {
 		syncutil.Mutex
-		idAlloc  int32
-		schemas  map[int32]string
-		subjects map[string]int32
+		idAlloc     int32
+		schemas     map[int32]string
+		schemaTypes map[int32]string
+		subjects    map[string]int32
 	}
 }
 
@@ -73,6 +74,7 @@ func StartTestSchemaRegistryWithTLS(
 func makeTestSchemaRegistry() *SchemaRegistry {
 	r := &SchemaRegistry{}
 	r.mu.schemas = make(map[int32]string)
+	r.mu.schemaTypes = make(map[int32]string)
 	r.mu.subjects = make(map[string]int32)
 	r.server = httptest.NewUnstartedServer(http.HandlerFunc(r.requestHandler))
 	return r
@@ -105,13 +107,23 @@ func (r *SchemaRegistry) SchemaForSubject(subject string) string {
 	return r.mu.schemas[r.mu.subjects[subject]]
 }
 
-func (r *SchemaRegistry) registerSchema(subject string, schema string) int32 {
+// SchemaTypeForSubject returns the type of the schema registered for the
+// specified subject. The type is empty for Avro schemas registered without
+// an explicit type.
+func (r *SchemaRegistry) SchemaTypeForSubject(subject string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.mu.schemaTypes[r.mu.subjects[subject]]
+}
+
+func (r *SchemaRegistry) registerSchema(subject string, schema string, schemaType string) int32 {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	id := r.mu.idAlloc
 	r.mu.idAlloc++
 	r.mu.schemas[id] = schema
+	r.mu.schemaTypes[id] = schemaType
 	r.mu.subjects[subject] = id
 	return id
 }
@@ -157,7 +169,8 @@ func (r *SchemaRegistry) requestHandler(hw http.ResponseWriter, hr *http.Request
 // register is an http handler for the underlying server which registers schemas.
 func (r *SchemaRegistry) register(hw http.ResponseWriter, hr *http.Request) (err error) {
 	type confluentSchemaVersionRequest struct {
-		Schema string `json:"schema"`
+		Schema     string `json:"schema"`
+		SchemaType string `json:"schemaType"`
 	}
 	type confluentSchemaVersionResponse struct {
 		ID int32 `json:"id"`
@@ -173,7 +186,7 @@ func (r *SchemaRegistry) register(hw http.ResponseWriter, hr *http.Request) (err
 	}
 
 	subject := strings.Split(hr.URL.Path, "/")[2]
-	id := r.registerSchema(subject, req.Schema)
+	id := r.registerSchema(subject, req.Schema, req.SchemaType)
 	res, err := json.Marshal(confluentSchemaVersionResponse{ID: id})
 	if err != nil {
 		return err
@@ -65,6 +65,8 @@ func AllTargets(cd jobspb.ChangefeedDetails) (targets changefeedbase.Targets) {
 					TableID:           ts.TableID,
 					FamilyName:        ts.FamilyName,
 					StatementTimeName: changefeedbase.StatementTimeName(ts.StatementTimeName),
+					DatabaseName:      ts.DatabaseName,
+					SchemaName:        ts.SchemaName,
 				})
 			}
 		}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdceval"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobsprofiler"
 	"github.com/cockroachdb/cockroach/pkg/kv"
@@ -257,6 +258,13 @@ func startDistChangefeed(
 	if err != nil {
 		return err
 	}
+	if details.KafkaTransactionalID != `` {
+		if err := fenceSinkTransactions(
+			ctx, execCtx, jobID, details, initialHighWater, localState, p,
+		); err != nil {
+			return err
+		}
+	}
 
 	execPlan := func(ctx context.Context) error {
 		// Derive a separate context so that we can shut down the changefeed
@@ -316,6 +324,117 @@ func startDistChangefeed(
 	return ctxgroup.GoAndWait(ctx, execPlan)
 }
 
+// fenceSinkTransactions prepares the sink of a changefeed with the
+// exactly_once option for the aggregators of the given plan. The aggregators
+// of the previous plan may have watched other spans, and some of them may
+// still be running, for instance on a node which lost contact with this one,
+// so the transactional IDs of their producers are fenced off first. The
+// frontiers committed with their last transactions can then no longer change,
+// and the new aggregators resume from them. The transactional IDs of the new
+// aggregators are recorded in the job along with those of the previous
+// producers whose frontiers are still ahead of the highwater, so that the next
+// plan fences them off and resumes from their frontiers in turn.
+func fenceSinkTransactions(
+	ctx context.Context,
+	execCtx sql.JobExecContext,
+	jobID jobspb.JobID,
+	details jobspb.ChangefeedDetails,
+	initialHighWater hlc.Timestamp,
+	localState *cachedState,
+	p *sql.PhysicalPlan,
+) error {
+	changefeedProgress := localState.progress.GetChangefeed()
+	if changefeedProgress == nil {
+		return errors.AssertionFailedf("changefeed %d has no changefeed progress", jobID)
+	}
+	execCfg := execCtx.ExecCfg()
+	prevIDs := changefeedProgress.KafkaTransactionalIDs
+	commits, err := abortSinkTransactions(ctx, &execCfg.DistSQLSrv.ServerConfig, details,
+		execCtx.User(), jobID, prevIDs)
+	if err != nil {
+		return err
+	}
+
+	var ids []string
+	seen := make(map[string]struct{})
+	for _, proc := range p.Processors {
+		if spec := proc.Spec.Core.ChangeAggregator; spec != nil {
+			ids = append(ids, spec.KafkaTransactionalID)
+			seen[spec.KafkaTransactionalID] = struct{}{}
+		}
+	}
+
+	var committed []jobspb.ResolvedSpan
+	var obsolete []string
+	if err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		committed, obsolete = nil, nil
+		for i, id := range prevIDs {
+			if commits[i].seq == 0 {
+				// Nothing was ever committed under this ID.
+				continue
+			}
+			frontier, err := readSinkTransactionFrontier(ctx, txn, jobID, id, commits[i])
+			if err != nil {
+				return err
+			}
+			aheadOfHighWater := initialHighWater.IsEmpty()
+			for _, rs := range frontier.ResolvedSpans {
+				if initialHighWater.Less(rs.Timestamp) {
+					aheadOfHighWater = true
+				}
+			}
+			if _, ok := seen[id]; !ok {
+				if !aheadOfHighWater {
+					// The highwater covers every row this ID committed, so its
+					// frontier is no longer needed.
+					obsolete = append(obsolete, id)
+					continue
+				}
+				ids = append(ids, id)
+				seen[id] = struct{}{}
+			}
+			committed = append(committed, frontier.ResolvedSpans...)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, proc := range p.Processors {
+		spec := proc.Spec.Core.ChangeAggregator
+		if spec == nil {
+			continue
+		}
+		for _, rs := range committed {
+			for _, w := range spec.Watches {
+				if w.Span.Overlaps(rs.Span) {
+					spec.KafkaCommittedSpans = append(spec.KafkaCommittedSpans, rs)
+					break
+				}
+			}
+		}
+	}
+
+	if err := execCfg.JobRegistry.UpdateJobWithTxn(ctx, jobID, nil, /* txn */
+		func(txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+			if err := md.CheckRunningOrReverting(); err != nil {
+				return err
+			}
+			for _, id := range obsolete {
+				if err := deleteSinkTransactionFrontiers(ctx, txn, jobID, id); err != nil {
+					return err
+				}
+			}
+			md.Progress.GetChangefeed().KafkaTransactionalIDs = ids
+			ju.UpdateProgress(md.Progress)
+			return nil
+		},
+	); err != nil {
+		return err
+	}
+	changefeedProgress.KafkaTransactionalIDs = ids
+	return nil
+}
+
 // The bin packing choice gives preference to leaseholder replicas if possible.
 var replicaOracleChoice = replicaoracle.BinPackingChoice
 
@@ -446,6 +565,10 @@ func makePlan(
 				JobID:      jobID,
 				Select:     execinfrapb.Expression{Expr: details.Select},
 			}
+			if details.KafkaTransactionalID != `` {
+				aggregatorSpecs[i].KafkaTransactionalID = aggregatorTransactionalID(
+					details.KafkaTransactionalID, sp.Spans)
+			}
 		}
 
 		// NB: This SpanFrontier processor depends on the set of tracked spans being
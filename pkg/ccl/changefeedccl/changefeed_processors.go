@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
@@ -67,6 +68,18 @@ type changeAggregator struct {
 	// sink is the Sink to write rows to. Resolved timestamps are never written
 	// by changeAggregator.
 	sink EventSink
+	// dlq, if non-nil, records rows which could not be encoded or emitted. It
+	// is flushed whenever the sink is.
+	dlq deadLetterQueue
+	// dlqMemMon accounts for the records buffered by dlq.
+	dlqMemMon *mon.BytesMonitor
+	// txns, if non-nil, makes the aggregator emit each row exactly once to
+	// the sink in transactions (exactly_once). A transaction is committed
+	// along with the frontier right before resolved spans are forwarded to the
+	// changeFrontier.
+	txns *sinkTransactions
+	// txnsMemMon accounts for the rows held back by txns.
+	txnsMemMon *mon.BytesMonitor
 	// changedRowBuf, if non-nil, contains changed rows to be emitted. Anything
 	// queued in `resolvedSpanBuf` is dependent on these having been emitted, so
 	// this one must be empty before moving on to that one.
@@ -81,6 +94,10 @@ type changeAggregator struct {
 	// span was forwarded to the frontier
 	recentKVCount uint64
 
+	// txnRows, if non-nil, counts the rows emitted per transaction since the
+	// last time a resolved span was forwarded to the frontier.
+	txnRows *transactionRowCounter
+
 	// eventProducer produces the next event from the kv feed.
 	eventProducer kvevent.Reader
 	// eventConsumer consumes the event.
@@ -330,7 +347,7 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 	}
 
 	ca.sink, err = getEventSink(ctx, ca.flowCtx.Cfg, ca.spec.Feed, timestampOracle,
-		ca.spec.User(), ca.spec.JobID, recorder)
+		ca.spec.User(), ca.spec.JobID, recorder, ca.spec.KafkaTransactionalID)
 	if err != nil {
 		err = changefeedbase.MarkRetryableError(err)
 		ca.MoveToDraining(err)
@@ -344,6 +361,41 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 		ca.changedRowBuf = &b.buf
 	}
 
+	// TODO(yevgeniy): Introduce separate changefeed monitor that's a parent
+	// for all changefeeds to control memory allocated to all changefeeds.
+	pool := ca.flowCtx.Cfg.BackfillerMonitor
+	if ca.knobs.MemMonitor != nil {
+		pool = ca.knobs.MemMonitor
+	}
+
+	ca.dlqMemMon = mon.NewMonitorInheritWithLimit("dead-letter-queue",
+		changefeedbase.DeadLetterQueueMaxBufferedBytes.Get(&ca.flowCtx.Cfg.Settings.SV), pool)
+	ca.dlqMemMon.StartNoReserved(ctx, pool)
+	ca.dlq, err = makeDeadLetterQueue(ctx, ca.flowCtx.Cfg, ca.spec.Feed, opts,
+		ca.spec.User(), ca.spec.JobID, ca.dlqMemMon, ca.sliMetrics)
+	if err != nil {
+		err = changefeedbase.MarkRetryableError(err)
+		ca.MoveToDraining(err)
+		ca.cancel()
+		return
+	}
+	if s, ok := ca.sink.(sinkWithDeadLetterQueue); ok && ca.dlq != nil {
+		s.setDeadLetterQueue(ca.dlq)
+	}
+	if s, ok := ca.sink.(transactionalSink); ok && ca.spec.KafkaTransactionalID != `` {
+		ca.txnsMemMon = mon.NewMonitorInheritWithLimit("exactly-once",
+			changefeedbase.ExactlyOnceMaxHeldBytes.Get(&ca.flowCtx.Cfg.Settings.SV), pool)
+		ca.txnsMemMon.StartNoReserved(ctx, pool)
+		ca.txns, err = makeSinkTransactions(ctx, s, ca.spec.KafkaTransactionalID,
+			ca.spec.JobID, ca.flowCtx.Cfg.DB, ca.frontier, ca.txnsMemMon)
+		if err != nil {
+			err = changefeedbase.MarkRetryableError(err)
+			ca.MoveToDraining(err)
+			ca.cancel()
+			return
+		}
+	}
+
 	// If the initial scan was disabled the highwater would've already been forwarded
 	needsInitialScan := ca.frontier.Frontier().IsEmpty()
 
@@ -355,12 +407,6 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 		kvFeedHighWater = ca.spec.Feed.StatementTime
 	}
 
-	// TODO(yevgeniy): Introduce separate changefeed monitor that's a parent
-	// for all changefeeds to control memory allocated to all changefeeds.
-	pool := ca.flowCtx.Cfg.BackfillerMonitor
-	if ca.knobs.MemMonitor != nil {
-		pool = ca.knobs.MemMonitor
-	}
 	limit := changefeedbase.PerChangefeedMemLimit.Get(&ca.flowCtx.Cfg.Settings.SV)
 	ca.eventProducer, ca.kvFeedDoneCh, ca.errCh, err = ca.startKVFeed(ctx, spans, kvFeedHighWater, needsInitialScan, feed, pool, limit, opts)
 	if err != nil {
@@ -368,10 +414,13 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 		ca.cancel()
 		return
 	}
+	if opts.EmitTransactionMarkers() {
+		ca.txnRows = makeTransactionRowCounter()
+	}
 	ca.sink = &errorWrapperSink{wrapped: ca.sink}
 	ca.eventConsumer, ca.sink, err = newEventConsumer(
 		ctx, ca.flowCtx.Cfg, ca.spec, feed, ca.frontier, kvFeedHighWater,
-		ca.sink, ca.metrics, ca.sliMetrics, ca.knobs)
+		ca.sink, ca.dlq, ca.txnRows, ca.metrics, ca.sliMetrics, ca.knobs)
 	if err != nil {
 		ca.MoveToDraining(err)
 		ca.cancel()
@@ -561,6 +610,13 @@ func (ca *changeAggregator) setupSpansAndFrontier() (spans []roachpb.Span, err e
 			return nil, err
 		}
 	}
+	// The rows up to the frontiers committed with the last kafka transactions
+	// were emitted, though they may not have been checkpointed.
+	for _, committed := range ca.spec.KafkaCommittedSpans {
+		if _, err := ca.frontier.Forward(committed.Span, committed.Timestamp); err != nil {
+			return nil, err
+		}
+	}
 	return spans, nil
 }
 
@@ -596,6 +652,18 @@ func (ca *changeAggregator) close() {
 		// Best effort: context is often cancel by now, so we expect to see an error
 		_ = ca.sink.Close()
 	}
+	if ca.dlq != nil {
+		_ = ca.dlq.Close(ca.Ctx())
+	}
+	if ca.dlqMemMon != nil {
+		ca.dlqMemMon.Stop(ca.Ctx())
+	}
+	if ca.txns != nil {
+		ca.txns.close(ca.Ctx())
+	}
+	if ca.txnsMemMon != nil {
+		ca.txnsMemMon.Stop(ca.Ctx())
+	}
 
 	// The sliMetrics registry may hold on to some state for each aggregator
 	// (ex. last known resolved timestamp). De-register the aggregator so this
@@ -703,6 +771,12 @@ func (ca *changeAggregator) computeTrailingMetadata(meta *execinfrapb.Changefeed
 		// Shutdown may be called even if Start didn't succeed.
 		return
 	}
+	if ca.txnRows != nil {
+		// Transaction markers are emitted by the frontier before it checkpoints.
+		// A checkpoint built from our frontier could advance past transactions
+		// whose markers were never emitted.
+		return
+	}
 
 	// Before emitting trailing metadata, we must flush any buffered events.
 	// Note: we are not flushing KV feed -- blocking buffer may still have buffered
@@ -716,6 +790,11 @@ func (ca *changeAggregator) computeTrailingMetadata(meta *execinfrapb.Changefeed
 		// which in this case is nothing.
 		return
 	}
+	// Uncommitted rows are aborted when the sink is closed, so they must be
+	// committed before the checkpoint may include them.
+	if err := ca.commitSinkTransaction(); err != nil {
+		return
+	}
 
 	// Build out the list of frontier spans.
 	ca.frontier.Entries(func(r roachpb.Span, ts hlc.Timestamp) (done span.OpResult) {
@@ -747,6 +826,9 @@ func (ca *changeAggregator) tick() error {
 			ca.sliMetrics.AdmitLatency.RecordValue(timeutil.Since(event.Timestamp().GoTime()).Nanoseconds())
 		}
 		ca.recentKVCount++
+		if ca.txns != nil {
+			return ca.txns.consumeEvent(ca.Ctx(), event)
+		}
 		return ca.eventConsumer.ConsumeEvent(ca.Ctx(), event)
 	case kvevent.TypeResolved:
 		a := event.DetachAlloc()
@@ -770,10 +852,35 @@ func (ca *changeAggregator) tick() error {
 }
 
 func (ca *changeAggregator) flushBufferedEvents() error {
+	if ca.txns != nil {
+		// Held rows which have been resolved since the last flush belong in the
+		// next transaction.
+		if err := ca.txns.release(ca.Ctx(), ca.eventConsumer.ConsumeEvent); err != nil {
+			return err
+		}
+	}
 	if err := ca.eventConsumer.Flush(ca.Ctx()); err != nil {
 		return err
 	}
-	return ca.sink.Flush(ca.Ctx())
+	if err := ca.sink.Flush(ca.Ctx()); err != nil {
+		return err
+	}
+	// Rows may be dead-lettered by the sink while it flushes, so the dead
+	// letter queue must be flushed after it.
+	if ca.dlq != nil {
+		return ca.dlq.Flush(ca.Ctx())
+	}
+	return nil
+}
+
+// commitSinkTransaction commits the sink's transaction along with the
+// frontier, if it has one. It must be called after flushBufferedEvents, so
+// that the transaction includes every row up to the frontier.
+func (ca *changeAggregator) commitSinkTransaction() error {
+	if ca.txns == nil {
+		return nil
+	}
+	return ca.txns.commit(ca.Ctx())
 }
 
 // noteResolvedSpan periodically flushes Frontier progress from the current
@@ -847,6 +954,9 @@ func (ca *changeAggregator) flushFrontier() error {
 	if err := ca.flushBufferedEvents(); err != nil {
 		return err
 	}
+	if err := ca.commitSinkTransaction(); err != nil {
+		return err
+	}
 
 	// Iterate frontier spans and build a list of spans to emit.
 	var batch jobspb.ResolvedSpans
@@ -874,6 +984,10 @@ func (ca *changeAggregator) emitResolved(batch jobspb.ResolvedSpans) error {
 			RecentKvCount: ca.recentKVCount,
 		},
 	}
+	if ca.txnRows != nil {
+		// The sink has been flushed, so every counted row has been emitted.
+		progressUpdate.TransactionRows = ca.txnRows.take()
+	}
 	updateBytes, err := protoutil.Marshal(&progressUpdate)
 	if err != nil {
 		return err
@@ -936,6 +1050,9 @@ type changeFrontier struct {
 	freqEmitResolved time.Duration
 	// lastEmitResolved is the last time a resolved timestamp was emitted.
 	lastEmitResolved time.Time
+	// txnMarkers, if non-nil, emits transaction markers to the sink as
+	// transactions are resolved.
+	txnMarkers *transactionMarkerEmitter
 
 	// lastProtectedTimestampUpdate is the last time the protected timestamp
 	// record was updated to the frontier's highwater mark
@@ -1171,6 +1288,7 @@ func newChangeFrontierProcessor(
 	if cf.encoder, err = getEncoder(
 		encodingOpts, AllTargets(spec.Feed), spec.Feed.Select != "",
 		makeExternalConnectionProvider(ctx, flowCtx.Cfg.DB), sliMertics,
+		makeClusterInfo(flowCtx.Cfg.ExecutorConfig.(*sql.ExecutorConfig)),
 	); err != nil {
 		return nil, err
 	}
@@ -1200,8 +1318,13 @@ func (cf *changeFrontier) Start(ctx context.Context) {
 	cf.metrics = cf.flowCtx.Cfg.JobRegistry.MetricsStruct().Changefeed.(*Metrics)
 
 	// Pass a nil oracle because this sink is only used to emit resolved timestamps
-	// but the oracle is only used when emitting row updates.
-	var nilOracle timestampLowerBoundOracle
+	// but the oracle is only used when emitting row updates. Commit markers
+	// are the exception.
+	var oracle timestampLowerBoundOracle
+	if changefeedbase.MakeStatementOptions(cf.spec.Feed.Opts).EmitTransactionMarkers() {
+		cf.txnMarkers = makeTransactionMarkerEmitter()
+		oracle = cf.txnMarkers
+	}
 	var err error
 	sli, err := cf.metrics.getSLIMetrics(cf.spec.Feed.Opts[changefeedbase.OptMetricsScope])
 	if err != nil {
@@ -1209,7 +1332,7 @@ func (cf *changeFrontier) Start(ctx context.Context) {
 		return
 	}
 	cf.sliMetrics = sli
-	cf.sink, err = getResolvedTimestampSink(ctx, cf.flowCtx.Cfg, cf.spec.Feed, nilOracle,
+	cf.sink, err = getResolvedTimestampSink(ctx, cf.flowCtx.Cfg, cf.spec.Feed, oracle,
 		cf.spec.User(), cf.spec.JobID, sli)
 
 	if err != nil {
@@ -1271,6 +1394,10 @@ func (cf *changeFrontier) Start(ctx context.Context) {
 		}
 	}
 
+	if cf.txnMarkers != nil {
+		cf.txnMarkers.emitted = cf.highWaterAtStart
+	}
+
 	func() {
 		cf.metrics.mu.Lock()
 		defer cf.metrics.mu.Unlock()
@@ -1414,6 +1541,9 @@ func (cf *changeFrontier) noteAggregatorProgress(d rowenc.EncDatum) error {
 	}
 
 	cf.maybeMarkJobIdle(resolvedSpans.Stats.RecentKvCount)
+	if cf.txnMarkers != nil {
+		cf.txnMarkers.add(resolvedSpans.TransactionRows)
+	}
 
 	for _, resolved := range resolvedSpans.ResolvedSpans {
 		// Inserting a timestamp less than the one the changefeed flow started at
@@ -1445,6 +1575,14 @@ func (cf *changeFrontier) forwardFrontier(resolved jobspb.ResolvedSpan) error {
 
 	maybeLogBehindSpan(cf.Ctx(), "coordinator", cf.frontier, frontierChanged, &cf.flowCtx.Cfg.Settings.SV)
 
+	// Markers must be flushed before the frontier is checkpointed, since they
+	// are not emitted again for transactions below the job's high-water mark.
+	if frontierChanged && cf.txnMarkers != nil {
+		if err := cf.txnMarkers.emit(cf.Ctx(), cf.sink.(EventSink), cf.frontier.Frontier()); err != nil {
+			return err
+		}
+	}
+
 	// If frontier changed, we emit resolved timestamp.
 	emitResolved := frontierChanged
 
@@ -1518,9 +1656,13 @@ func (cf *changeFrontier) maybeCheckpointJob(
 	// highwater mark remains fixed while other spans may significantly outpace
 	// it, therefore to avoid losing that progress on changefeed resumption we
 	// also store as many of those leading spans as we can in the job progress
-	updateCheckpoint :=
-		(inBackfill || cf.frontier.hasLaggingSpans(cf.spec.Feed.StatementTime, &cf.js.settings.SV)) &&
-			cf.js.canCheckpointSpans()
+	//
+	// Lagging spans are not checkpointed when emitting transaction markers: on
+	// resumption, only the spans which were not checkpointed would replay their
+	// rows, and transactions would be marked with partial row counts.
+	checkpointLaggingSpans := cf.txnMarkers == nil &&
+		cf.frontier.hasLaggingSpans(cf.spec.Feed.StatementTime, &cf.js.settings.SV)
+	updateCheckpoint := (inBackfill || checkpointLaggingSpans) && cf.js.canCheckpointSpans()
 
 	// If the highwater has moved an empty checkpoint will be saved
 	var checkpoint jobspb.ChangefeedProgress_Checkpoint
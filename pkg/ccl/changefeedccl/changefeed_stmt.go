@@ -41,6 +41,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
@@ -498,7 +499,11 @@ func createChangefeedJobRecord(
 		}
 	}
 	if checkPrivs {
-		if err := authorizeUserToCreateChangefeed(ctx, p, sinkURI, hasSelectPrivOnAllTables, hasChangefeedPrivOnAllTables, opts.GetConfluentSchemaRegistry()); err != nil {
+		dlqOpts, err := opts.GetDeadLetterQueueOptions()
+		if err != nil {
+			return nil, err
+		}
+		if err := authorizeUserToCreateChangefeed(ctx, p, sinkURI, hasSelectPrivOnAllTables, hasChangefeedPrivOnAllTables, opts.GetConfluentSchemaRegistry(), dlqOpts.SinkURI); err != nil {
 			return nil, err
 		}
 	}
@@ -584,7 +589,8 @@ func createChangefeedJobRecord(
 		return nil, err
 	}
 	if _, err := getEncoder(encodingOpts, AllTargets(details), details.Select != "",
-		makeExternalConnectionProvider(ctx, p.ExecCfg().InternalDB), nil); err != nil {
+		makeExternalConnectionProvider(ctx, p.ExecCfg().InternalDB), nil,
+		makeClusterInfo(p.ExecCfg())); err != nil {
 		return nil, err
 	}
 
@@ -632,6 +638,19 @@ func createChangefeedJobRecord(
 
 	if details.SinkURI == `` {
 
+		if opts.IsSet(changefeedbase.OptDLQTable) || opts.IsSet(changefeedbase.OptDLQSink) {
+			return nil, errors.Errorf(`%s='%s' is not supported for sinkless changefeeds`,
+				changefeedbase.OptOnRowError, changefeedbase.OptOnRowErrorDLQ)
+		}
+		if opts.EmitTransactionMarkers() {
+			return nil, errors.Errorf(`%s is not supported for sinkless changefeeds`,
+				changefeedbase.OptTransactionMarkers)
+		}
+		if opts.ExactlyOnce() {
+			return nil, errors.Errorf(`%s is not supported for sinkless changefeeds`,
+				changefeedbase.OptExactlyOnce)
+		}
+
 		if details.Select != `` {
 			if err := utilccl.CheckEnterpriseEnabled(
 				p.ExecCfg().Settings, "CHANGEFEED",
@@ -676,11 +695,17 @@ func createChangefeedJobRecord(
 	// but are inappropriate for the provided sink.
 	// TODO: Ideally those option validations would happen in validateDetails()
 	// earlier, like the others.
+	if err := validateDeadLetterQueue(ctx, p, jobID, details, opts); err != nil {
+		return nil, err
+	}
 	err = validateSink(ctx, p, jobID, details, opts)
 	if err != nil {
 		return nil, err
 	}
 	details.Opts = opts.AsMap()
+	if opts.ExactlyOnce() {
+		details.KafkaTransactionalID = newKafkaTransactionalID(jobID)
+	}
 
 	if locFilter := details.Opts[changefeedbase.OptExecutionLocality]; locFilter != "" {
 		if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V23_1) {
@@ -850,12 +875,14 @@ func getTargetsAndTables(
 				}
 			}
 		} else {
-
-			name, err := getChangefeedTargetName(ctx, td, p.ExecCfg(), p.Txn(), fullTableName)
-
+			tbName, err := getQualifiedTableNameObj(ctx, p.ExecCfg(), p.Txn(), td)
 			if err != nil {
 				return nil, nil, err
 			}
+			name := td.GetName()
+			if fullTableName {
+				name = tbName.String()
+			}
 
 			tables[td.GetID()] = jobspb.ChangefeedTargetTable{
 				StatementTimeName: name,
@@ -873,6 +900,8 @@ func getTargetsAndTables(
 				TableID:           td.GetID(),
 				FamilyName:        string(ct.FamilyName),
 				StatementTimeName: tables[td.GetID()].StatementTimeName,
+				DatabaseName:      tbName.Catalog(),
+				SchemaName:        tbName.Schema(),
 			}
 		}
 		if dup, isDup := seen[targets[i]]; isDup {
@@ -959,6 +988,110 @@ func validateSink(
 	return nil
 }
 
+// validateDeadLetterQueue checks that the user creating the changefeed can
+// write to its dead letter queue, if any, and that a dead letter sink does not
+// emit to the topics of the changefeed. The dead letter table is replaced with
+// its fully qualified name so that it resolves the same way on every node
+// running the changefeed.
+func validateDeadLetterQueue(
+	ctx context.Context,
+	p sql.PlanHookState,
+	jobID jobspb.JobID,
+	details jobspb.ChangefeedDetails,
+	opts changefeedbase.StatementOptions,
+) error {
+	dlqOpts, err := opts.GetDeadLetterQueueOptions()
+	if err != nil || !dlqOpts.Enabled {
+		return err
+	}
+
+	if dlqOpts.Table == `` {
+		if err := validateDeadLetterTopics(
+			AllTargets(details), details.SinkURI, dlqOpts.SinkURI,
+		); err != nil {
+			return err
+		}
+		dlq, err := makeDeadLetterQueue(ctx, &p.ExecCfg().DistSQLSrv.ServerConfig,
+			details, opts, p.User(), jobID, nil /* memMon */, nil /* metrics */)
+		if err != nil {
+			return errors.Wrapf(err, "invalid %s", changefeedbase.OptDLQSink)
+		}
+		return dlq.Close(ctx)
+	}
+
+	un, err := parser.ParseTableName(dlqOpts.Table)
+	if err != nil {
+		return errors.Wrapf(err, "invalid %s", changefeedbase.OptDLQTable)
+	}
+	tn := un.ToTableName()
+	prefix, _, err := p.ResolveMutableTableDescriptor(ctx, &tn, true /* required */, tree.ResolveRequireTableDesc)
+	if err != nil {
+		return errors.Wrapf(err, "invalid %s", changefeedbase.OptDLQTable)
+	}
+	qualified := tree.MakeTableNameFromPrefix(prefix.NamePrefix(), tn.ObjectName)
+
+	// Inserting nothing checks that the table has the expected columns and
+	// that the user is allowed to write to it.
+	stmt := fmt.Sprintf(
+		`INSERT INTO %s (%s) SELECT 0:::INT8, '':::STRING, '':::BYTES, '':::BYTES, '':::STRING, 0:::DECIMAL WHERE false`,
+		qualified.String(), deadLetterTableColumns)
+	if _, err := p.InternalSQLTxn().ExecEx(ctx, "changefeed-validate-dead-letter-table", p.Txn(),
+		sessiondata.InternalExecutorOverride{User: p.User()}, stmt,
+	); err != nil {
+		return errors.Wrapf(err, "invalid %s", changefeedbase.OptDLQTable)
+	}
+	opts.SetDeadLetterTable(qualified.String())
+	return nil
+}
+
+// validateDeadLetterTopics checks that a dead letter sink addressing the same
+// system as the changefeed's sink does not emit to any of the changefeed's
+// topics, in which case dead-lettered records would be mixed with the rows.
+// Records are emitted to the topics of their rows, named according to the
+// topic parameters of the dead letter sink URI.
+func validateDeadLetterTopics(
+	targets changefeedbase.Targets, sinkURI string, dlqSinkURI string,
+) error {
+	topicNames := func(uri string) (map[string]struct{}, *url.URL, error) {
+		u, err := url.Parse(uri)
+		if err != nil {
+			return nil, nil, err
+		}
+		q := u.Query()
+		tn, err := MakeTopicNamer(targets,
+			WithPrefix(q.Get(changefeedbase.SinkParamTopicPrefix)),
+			WithSingleName(q.Get(changefeedbase.SinkParamTopicName)))
+		if err != nil {
+			return nil, nil, err
+		}
+		names := make(map[string]struct{})
+		for _, name := range tn.DisplayNamesSlice() {
+			names[name] = struct{}{}
+		}
+		return names, u, nil
+	}
+	topics, u, err := topicNames(sinkURI)
+	if err != nil {
+		return err
+	}
+	dlqTopics, dlqU, err := topicNames(dlqSinkURI)
+	if err != nil {
+		return errors.Wrapf(err, "invalid %s", changefeedbase.OptDLQSink)
+	}
+	if u.Scheme != dlqU.Scheme || u.Host != dlqU.Host {
+		return nil
+	}
+	for topic := range dlqTopics {
+		if _, ok := topics[topic]; ok {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"%s emits to topic %s of the changefeed; use its %s or %s "+
+					"parameter to name distinct topics", changefeedbase.OptDLQSink, topic,
+				changefeedbase.SinkParamTopicPrefix, changefeedbase.SinkParamTopicName)
+		}
+	}
+	return nil
+}
+
 func requiresKeyInValue(s Sink) bool {
 	switch s.getConcreteType() {
 	case sinkTypeCloudstorage, sinkTypeWebhook:
@@ -1472,18 +1605,6 @@ func (b *changefeedResumer) maybeCleanUpProtectedTimestamp(
 	}
 }
 
-// getQualifiedTableName returns the database-qualified name of the table
-// or view represented by the provided descriptor.
-func getQualifiedTableName(
-	ctx context.Context, execCfg *sql.ExecutorConfig, txn *kv.Txn, desc catalog.TableDescriptor,
-) (string, error) {
-	tbName, err := getQualifiedTableNameObj(ctx, execCfg, txn, desc)
-	if err != nil {
-		return "", err
-	}
-	return tbName.String(), nil
-}
-
 // getQualifiedTableNameObj returns the database-qualified name of the table
 // or view represented by the provided descriptor.
 func getQualifiedTableNameObj(
@@ -1506,20 +1627,6 @@ func getQualifiedTableNameObj(
 	return tbName, nil
 }
 
-// getChangefeedTargetName gets a table name with or without the dots
-func getChangefeedTargetName(
-	ctx context.Context,
-	desc catalog.TableDescriptor,
-	execCfg *sql.ExecutorConfig,
-	txn *kv.Txn,
-	qualified bool,
-) (string, error) {
-	if qualified {
-		return getQualifiedTableName(ctx, execCfg, txn, desc)
-	}
-	return desc.GetName(), nil
-}
-
 func logChangefeedCreateTelemetry(ctx context.Context, jr *jobs.Record, isTransformation bool) {
 	var changefeedEventDetails eventpb.CommonChangefeedEventDetails
 	if jr != nil {
@@ -782,6 +782,91 @@ func TestChangefeedEnvelope(t *testing.T) {
 	cdcTest(t, testFn, feedTestRestrictSinks("sinkless", "enterprise", "kafka"))
 }
 
+func TestChangefeedDebeziumEnvelope(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	type debeziumEvent struct {
+		Before map[string]interface{} `json:"before"`
+		After  map[string]interface{} `json:"after"`
+		Source struct {
+			Connector string `json:"connector"`
+			DB        string `json:"db"`
+			Schema    string `json:"schema"`
+			Table     string `json:"table"`
+			Snapshot  string `json:"snapshot"`
+		} `json:"source"`
+		Op string `json:"op"`
+	}
+	// nextEvent returns the key and the decoded value of the next message, or
+	// a nil value if the message is a tombstone.
+	nextEvent := func(t *testing.T, f cdctest.TestFeed) (string, *debeziumEvent) {
+		t.Helper()
+		msgs, err := readNextMessages(context.Background(), f, 1)
+		require.NoError(t, err)
+		if len(msgs[0].Value) == 0 {
+			return string(msgs[0].Key), nil
+		}
+		var ev debeziumEvent
+		require.NoError(t, json.Unmarshal(msgs[0].Value, &ev))
+		return string(msgs[0].Key), &ev
+	}
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a')`)
+
+		sqlDB.ExpectErr(t, `envelope=debezium is not supported with CDC queries`,
+			`CREATE CHANGEFEED INTO 'kafka://nope' WITH envelope='debezium' AS SELECT * FROM foo`)
+		sqlDB.ExpectErr(t, `updated is not supported with envelope=debezium`,
+			`CREATE CHANGEFEED FOR foo INTO 'kafka://nope' WITH envelope='debezium', updated`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH envelope='debezium'`)
+		defer closeFeed(t, foo)
+
+		// Rows emitted by the initial scan are snapshot reads.
+		key, ev := nextEvent(t, foo)
+		require.Equal(t, `[1]`, key)
+		require.Equal(t, `r`, ev.Op)
+		require.Equal(t, `true`, ev.Source.Snapshot)
+		require.Equal(t, `cockroachdb`, ev.Source.Connector)
+		require.Equal(t, []string{`d`, `public`, `foo`},
+			[]string{ev.Source.DB, ev.Source.Schema, ev.Source.Table})
+		require.Nil(t, ev.Before)
+		require.Equal(t, map[string]interface{}{`a`: float64(1), `b`: `a`}, ev.After)
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'b')`)
+		key, ev = nextEvent(t, foo)
+		require.Equal(t, `[2]`, key)
+		require.Equal(t, `c`, ev.Op)
+		require.Equal(t, `false`, ev.Source.Snapshot)
+		require.Nil(t, ev.Before)
+		require.Equal(t, map[string]interface{}{`a`: float64(2), `b`: `b`}, ev.After)
+
+		sqlDB.Exec(t, `UPDATE foo SET b = 'c' WHERE a = 1`)
+		key, ev = nextEvent(t, foo)
+		require.Equal(t, `[1]`, key)
+		require.Equal(t, `u`, ev.Op)
+		require.Equal(t, map[string]interface{}{`a`: float64(1), `b`: `a`}, ev.Before)
+		require.Equal(t, map[string]interface{}{`a`: float64(1), `b`: `c`}, ev.After)
+
+		// Deletes are followed by a tombstone.
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 2`)
+		key, ev = nextEvent(t, foo)
+		require.Equal(t, `[2]`, key)
+		require.Equal(t, `d`, ev.Op)
+		require.Equal(t, map[string]interface{}{`a`: float64(2), `b`: `b`}, ev.Before)
+		require.Nil(t, ev.After)
+		key, ev = nextEvent(t, foo)
+		require.Equal(t, `[2]`, key)
+		require.Nil(t, ev)
+	}
+
+	// some sinks are incompatible with envelope
+	cdcTest(t, testFn, feedTestRestrictSinks("sinkless", "enterprise", "kafka"))
+}
+
 func TestChangefeedFullTableName(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -5263,6 +5348,12 @@ func TestChangefeedErrors(t *testing.T) {
 		`CREATE CHANGEFEED FOR foo INTO $1 WITH topic_in_value, format='experimental_avro'`,
 		`kafka://nope`,
 	)
+	// Neither does the protobuf format.
+	sqlDB.ExpectErrWithTimeout(
+		t, `key_in_value is not supported with format=protobuf`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH key_in_value, format='protobuf'`,
+		`kafka://nope`,
+	)
 
 	// Unordered flag required for some options, disallowed for others.
 	sqlDB.ExpectErrWithTimeout(t, `resolved timestamps cannot be guaranteed to be correct in unordered mode`, `CREATE CHANGEFEED FOR foo WITH resolved, unordered`)
@@ -5288,6 +5379,11 @@ func TestChangefeedErrors(t *testing.T) {
 		`CREATE CHANGEFEED FOR foo INTO $1 WITH envelope='key_only'`,
 		`experimental-nodelocal://1/bar`,
 	)
+	sqlDB.ExpectErrWithTimeout(
+		t, `this sink is incompatible with format=protobuf`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format='protobuf'`,
+		`experimental-nodelocal://1/bar`,
+	)
 
 	// WITH key_in_value requires envelope=wrapped
 	sqlDB.ExpectErrWithTimeout(
@@ -8529,6 +8625,259 @@ func TestChangefeedKafkaMessageTooLarge(t *testing.T) {
 	cdcTest(t, testFn, feedTestForceSink(`kafka`))
 }
 
+func TestChangefeedDeadLetterQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		knobs := mustBeKafkaFeedFactory(f).knobs
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `CREATE TABLE dlq (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			job_id INT8, table_name STRING, key BYTES, row BYTES, error STRING, mvcc_timestamp DECIMAL
+		)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'ok'), (2, 'poison'), (3, 'ok')`)
+
+		sqlDB.ExpectErr(t, `invalid dlq_table`,
+			`CREATE CHANGEFEED FOR foo INTO 'kafka://nope' WITH on_row_error='dlq', dlq_table='missing'`)
+		sqlDB.ExpectErr(t, `invalid dlq_table`,
+			`CREATE CHANGEFEED FOR foo INTO 'kafka://nope' WITH on_row_error='dlq', dlq_table='foo'`)
+		sqlDB.ExpectErr(t, `dlq_sink emits to topic foo of the changefeed`,
+			`CREATE CHANGEFEED FOR foo INTO 'kafka://nope' WITH on_row_error='dlq', dlq_sink='kafka://nope'`)
+		sqlDB.ExpectErr(t, `dlq_sink emits to topic cf_foo of the changefeed`,
+			`CREATE CHANGEFEED FOR foo INTO 'kafka://nope?topic_prefix=cf_' WITH on_row_error='dlq', dlq_sink='kafka://nope?topic_name=cf_foo'`)
+
+		knobs.kafkaInterceptor = func(m *sarama.ProducerMessage, client kafkaClient) error {
+			if m.Value == nil {
+				return nil
+			}
+			value, err := m.Value.Encode()
+			if err != nil {
+				return err
+			}
+			if strings.Contains(string(value), `poison`) {
+				return sarama.ErrMessageSizeTooLarge
+			}
+			return nil
+		}
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH on_row_error='dlq', dlq_table='dlq'`)
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "ok"}}`,
+			`foo: [3]->{"after": {"a": 3, "b": "ok"}}`,
+		})
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (4, 'ok')`)
+		assertPayloads(t, foo, []string{
+			`foo: [4]->{"after": {"a": 4, "b": "ok"}}`,
+		})
+
+		jobID := foo.(cdctest.EnterpriseTestFeed).JobID()
+		sqlDB.CheckQueryResultsRetry(t, fmt.Sprintf(`
+			SELECT table_name, convert_from(key, 'UTF8'), convert_from(row, 'UTF8'),
+			       error LIKE '%%Message was too large%%', mvcc_timestamp > 0
+			FROM dlq WHERE job_id = %d`, jobID),
+			[][]string{{`foo`, `[2]`, `{"after": {"a": 2, "b": "poison"}}`, `true`, `true`}},
+		)
+
+		var description string
+		sqlDB.QueryRow(t, `SELECT description FROM [SHOW JOB $1]`, jobID).Scan(&description)
+		require.Contains(t, description, `dlq_table = 'd.public.dlq'`)
+	}
+
+	cdcTest(t, testFn, feedTestForceSink(`kafka`))
+}
+
+func TestChangefeedTransactionMarkers(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0)`)
+
+		sqlDB.ExpectErr(t, `requires the transaction_id option`,
+			`CREATE CHANGEFEED FOR foo INTO 'kafka://nope' WITH transaction_markers`)
+		sqlDB.ExpectErr(t, `transaction_markers is not supported for sinkless changefeeds`,
+			`CREATE CHANGEFEED FOR foo WITH transaction_id, transaction_markers`)
+
+		feed := feed(t, f,
+			`CREATE CHANGEFEED FOR foo, bar WITH transaction_id, transaction_markers, resolved='10ms'`)
+		defer closeFeed(t, feed)
+
+		// Rows emitted by the rangefeeds' catch-up scans carry no transaction
+		// ID. Wait for a resolved timestamp from after the changefeed started,
+		// which is only emitted once the catch-up scans have finished.
+		ctx, cancel := context.WithTimeout(context.Background(), assertPayloadsTimeout())
+		defer cancel()
+		msgs, err := readNextMessages(ctx, feed, 1)
+		require.NoError(t, err)
+		require.Equal(t, `foo: [0]->{"after": {"a": 0}, "transaction_id": null}`,
+			fmt.Sprintf(`%s: %s->%s`, msgs[0].Topic, msgs[0].Key, msgs[0].Value))
+		started := s.Server.Clock().Now()
+		for {
+			if resolved, _ := expectResolvedTimestamp(t, feed); started.LessEq(resolved) {
+				break
+			}
+		}
+
+		// The first transaction commits its intents, the second is a 1PC write.
+		sqlDB.Exec(t, `BEGIN; INSERT INTO foo VALUES (1), (2); INSERT INTO bar VALUES (1); COMMIT`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3)`)
+
+		// Four rows, and a BEGIN and COMMIT marker for each of the two
+		// transactions.
+		msgs, err = readNextMessages(ctx, feed, 8)
+		require.NoError(t, err)
+
+		type rowValue struct {
+			TransactionID *string `json:"transaction_id"`
+		}
+		rowsByTxn := make(map[string][]string)
+		markersByTxn := make(map[string][]transactionMarker)
+		for _, m := range msgs {
+			if m.Topic == transactionMarkerTopicName {
+				var marker transactionMarker
+				require.NoError(t, json.Unmarshal(m.Value, &marker))
+				require.NotNil(t, marker.TransactionID)
+				require.Equal(t,
+					fmt.Sprintf(`["%s","%s"]`, *marker.TransactionID, marker.CommitTimestamp),
+					string(m.Key))
+				markersByTxn[*marker.TransactionID] = append(markersByTxn[*marker.TransactionID], marker)
+				continue
+			}
+			var v rowValue
+			require.NoError(t, json.Unmarshal(m.Value, &v))
+			require.NotNil(t, v.TransactionID, `%s: %s->%s`, m.Topic, m.Key, m.Value)
+			rowsByTxn[*v.TransactionID] = append(rowsByTxn[*v.TransactionID],
+				fmt.Sprintf(`%s: %s`, m.Topic, m.Key))
+		}
+
+		require.Len(t, rowsByTxn, 2)
+		require.Len(t, markersByTxn, 2)
+		for txnID, rows := range rowsByTxn {
+			sort.Strings(rows)
+			markers := markersByTxn[txnID]
+			require.Len(t, markers, 2)
+			commitTS := markers[0].CommitTimestamp
+			commit := transactionMarker{Status: `COMMIT`, TransactionID: &txnID, CommitTimestamp: commitTS}
+			switch len(rows) {
+			case 3:
+				require.Equal(t, []string{`bar: [1]`, `foo: [1]`, `foo: [2]`}, rows)
+				commit.EventCount = 3
+				commit.DataCollections = []transactionDataCollection{
+					{DataCollection: `bar`, EventCount: 1},
+					{DataCollection: `foo`, EventCount: 2},
+				}
+			case 1:
+				require.Equal(t, []string{`foo: [3]`}, rows)
+				commit.EventCount = 1
+				commit.DataCollections = []transactionDataCollection{
+					{DataCollection: `foo`, EventCount: 1},
+				}
+			default:
+				t.Fatalf(`unexpected rows written by transaction %s: %v`, txnID, rows)
+			}
+			require.Equal(t, []transactionMarker{
+				{Status: `BEGIN`, TransactionID: &txnID, CommitTimestamp: commitTS},
+				commit,
+			}, markers)
+		}
+	}
+
+	cdcTest(t, testFn, feedTestForceSink(`kafka`))
+}
+
+func TestChangefeedExactlyOnce(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		ctx := context.Background()
+		registry := s.Server.JobRegistry().(*jobs.Registry)
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0)`)
+
+		sqlDB.ExpectErr(t, `exactly_once is not supported for sinkless changefeeds`,
+			`CREATE CHANGEFEED FOR foo WITH exactly_once`)
+		sqlDB.ExpectErr(t, `this sink is incompatible with option exactly_once`,
+			`CREATE CHANGEFEED FOR foo INTO 'webhook-https://fake-host' WITH exactly_once`)
+
+		testFeed := feed(t, f, `CREATE CHANGEFEED FOR foo WITH exactly_once`)
+		defer closeFeed(t, testFeed)
+
+		var tsStr string
+		sqlDB.QueryRow(t,
+			`INSERT INTO foo VALUES (1) RETURNING cluster_logical_timestamp()`).Scan(&tsStr)
+		assertPayloads(t, testFeed, []string{
+			`foo: [0]->{"after": {"a": 0}}`,
+			`foo: [1]->{"after": {"a": 1}}`,
+		})
+
+		feed, ok := testFeed.(cdctest.EnterpriseTestFeed)
+		require.True(t, ok)
+
+		// Rows are only forwarded to the changeFrontier once their kafka
+		// transaction has committed along with the aggregator's frontier.
+		ts := parseTimeToHLC(t, tsStr)
+		testutils.SucceedsSoon(t, func() error {
+			if hw := loadProgress(t, feed, registry).GetHighWater(); hw == nil || hw.Less(ts) {
+				return errors.Newf("highwater %s is not past %s", hw, ts)
+			}
+			return nil
+		})
+		var frontiers int
+		sqlDB.QueryRow(t, `SELECT count(*) FROM system.job_info
+WHERE job_id = $1 AND info_key LIKE '~changefeed-txn-frontier-%'`, feed.JobID()).Scan(&frontiers)
+		require.NotZero(t, frontiers)
+		loadTransactionalID := func() string {
+			job, err := registry.LoadJob(ctx, feed.JobID())
+			require.NoError(t, err)
+			details, ok := job.Details().(jobspb.ChangefeedDetails)
+			require.True(t, ok)
+			return details.KafkaTransactionalID
+		}
+		id := loadTransactionalID()
+		require.Regexp(t, fmt.Sprintf(`^crdb-changefeed-%d-`, feed.JobID()), id)
+
+		// The transactional IDs of the aggregators' producers are recorded in
+		// the job, so that they are fenced off when the changefeed is replanned.
+		loadProducerIDs := func() []string {
+			job, err := registry.LoadJob(ctx, feed.JobID())
+			require.NoError(t, err)
+			return job.Progress().GetChangefeed().KafkaTransactionalIDs
+		}
+		producerIDs := loadProducerIDs()
+		require.NotEmpty(t, producerIDs)
+		for _, producerID := range producerIDs {
+			require.Regexp(t, fmt.Sprintf(`^%s-[0-9a-f]{16}$`, id), producerID)
+		}
+
+		// Altering the changefeed keeps its transactional IDs, so that its
+		// producers still fence off the ones it had before.
+		sqlDB.Exec(t, `PAUSE JOB $1`, feed.JobID())
+		waitForJobStatus(sqlDB, t, feed.JobID(), `paused`)
+		sqlDB.Exec(t, fmt.Sprintf(`ALTER CHANGEFEED %d SET diff`, feed.JobID()))
+		require.Equal(t, id, loadTransactionalID())
+		require.Equal(t, producerIDs, loadProducerIDs())
+
+		// The resumed changefeed fences off the previous producers and resumes
+		// from the frontiers they committed, so no row is emitted again.
+		sqlDB.Exec(t, `RESUME JOB $1`, feed.JobID())
+		waitForJobStatus(sqlDB, t, feed.JobID(), `running`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2)`)
+		assertPayloads(t, testFeed, []string{
+			`foo: [2]->{"after": {"a": 2}, "before": null}`,
+		})
+	}
+
+	cdcTest(t, testFn, feedTestForceSink(`kafka`), feedTestNoExternalConnection)
+}
+
 // Regression for #85902.
 func TestRedactedSchemaRegistry(t *testing.T) {
 	defer leaktest.AfterTest(t)()
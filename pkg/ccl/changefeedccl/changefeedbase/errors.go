@@ -101,6 +101,12 @@ func MarkRetryableError(cause error) error {
 	return errors.Mark(cause, &retryableError{})
 }
 
+// IsRetryableError returns true if the error was marked as retryable by
+// MarkRetryableError.
+func IsRetryableError(err error) bool {
+	return errors.Is(err, &retryableError{})
+}
+
 type drainHelper interface {
 	IsDraining() bool
 }
@@ -47,6 +47,10 @@ type FormatType string
 // OnErrorType configures the job behavior when an error occurs.
 type OnErrorType string
 
+// OnRowErrorType configures the behavior when a single row cannot be encoded
+// or emitted.
+type OnRowErrorType string
+
 // SchemaChangeEventClass defines a set of schema change event types which
 // trigger the action defined by the SchemaChangeEventPolicy.
 type SchemaChangeEventClass string
@@ -90,6 +94,8 @@ const (
 	OptMinCheckpointFrequency       = `min_checkpoint_frequency`
 	OptUpdatedTimestamps            = `updated`
 	OptMVCCTimestamps               = `mvcc_timestamp`
+	OptTransactionID                = `transaction_id`
+	OptTransactionMarkers           = `transaction_markers`
 	OptDiff                         = `diff`
 	OptCompression                  = `compression`
 	OptSchemaChangeEvents           = `schema_change_events`
@@ -99,12 +105,16 @@ const (
 	OptWebhookAuthHeader            = `webhook_auth_header`
 	OptWebhookClientTimeout         = `webhook_client_timeout`
 	OptOnError                      = `on_error`
+	OptOnRowError                   = `on_row_error`
+	OptDLQTable                     = `dlq_table`
+	OptDLQSink                      = `dlq_sink`
 	OptMetricsScope                 = `metrics_label`
 	OptUnordered                    = `unordered`
 	OptVirtualColumns               = `virtual_columns`
 	OptExecutionLocality            = `execution_locality`
 	OptLaggingRangesThreshold       = `lagging_ranges_threshold`
 	OptLaggingRangesPollingInterval = `lagging_ranges_polling_interval`
+	OptExactlyOnce                  = `exactly_once`
 
 	OptVirtualColumnsOmitted VirtualColumnVisibility = `omitted`
 	OptVirtualColumnsNull    VirtualColumnVisibility = `null`
@@ -150,15 +160,25 @@ const (
 	OptEnvelopeDeprecatedRow EnvelopeType = `deprecated_row`
 	OptEnvelopeWrapped       EnvelopeType = `wrapped`
 	OptEnvelopeBare          EnvelopeType = `bare`
+	OptEnvelopeDebezium      EnvelopeType = `debezium`
 
-	OptFormatJSON    FormatType = `json`
-	OptFormatAvro    FormatType = `avro`
-	OptFormatCSV     FormatType = `csv`
-	OptFormatParquet FormatType = `parquet`
+	OptFormatJSON     FormatType = `json`
+	OptFormatAvro     FormatType = `avro`
+	OptFormatCSV      FormatType = `csv`
+	OptFormatParquet  FormatType = `parquet`
+	OptFormatProtobuf FormatType = `protobuf`
 
 	OptOnErrorFail  OnErrorType = `fail`
 	OptOnErrorPause OnErrorType = `pause`
 
+	// OptOnRowErrorFail indicates that a row which cannot be encoded or
+	// emitted fails the changefeed, subject to on_error.
+	OptOnRowErrorFail OnRowErrorType = `fail`
+	// OptOnRowErrorDLQ indicates that a row which cannot be encoded or emitted
+	// is recorded in the dead letter queue named by dlq_table or dlq_sink and
+	// the changefeed moves on.
+	OptOnRowErrorDLQ OnRowErrorType = `dlq`
+
 	DeprecatedOptFormatAvro                   = `experimental_avro`
 	DeprecatedSinkSchemeCloudStorageAzure     = `experimental-azure`
 	DeprecatedSinkSchemeCloudStorageGCS       = `experimental-gs`
@@ -329,8 +349,8 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptCursor:                             timestampOption,
 	OptCustomKeyColumn:                    stringOption,
 	OptEndTime:                            timestampOption,
-	OptEnvelope:                           enum("row", "key_only", "wrapped", "deprecated_row", "bare"),
-	OptFormat:                             enum("json", "avro", "csv", "experimental_avro", "parquet"),
+	OptEnvelope:                           enum("row", "key_only", "wrapped", "deprecated_row", "bare", "debezium"),
+	OptFormat:                             enum("json", "avro", "csv", "experimental_avro", "parquet", "protobuf"),
 	OptFullTableName:                      flagOption,
 	OptKeyInValue:                         flagOption,
 	OptTopicInValue:                       flagOption,
@@ -338,6 +358,8 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptMinCheckpointFrequency:             durationOption.thatCanBeZero(),
 	OptUpdatedTimestamps:                  flagOption,
 	OptMVCCTimestamps:                     flagOption,
+	OptTransactionID:                      flagOption,
+	OptTransactionMarkers:                 flagOption,
 	OptDiff:                               flagOption,
 	OptCompression:                        enum("gzip", "zstd"),
 	OptSchemaChangeEvents:                 enum("column_changes", "default"),
@@ -354,12 +376,16 @@ var ChangefeedOptionExpectValues = map[string]OptionPermittedValues{
 	OptWebhookAuthHeader:                  stringOption,
 	OptWebhookClientTimeout:               durationOption,
 	OptOnError:                            enum("pause", "fail"),
+	OptOnRowError:                         enum("fail", "dlq"),
+	OptDLQTable:                           stringOption,
+	OptDLQSink:                            stringOption,
 	OptMetricsScope:                       stringOption,
 	OptUnordered:                          flagOption,
 	OptVirtualColumns:                     enum("omitted", "null"),
 	OptExecutionLocality:                  stringOption,
 	OptLaggingRangesThreshold:             durationOption,
 	OptLaggingRangesPollingInterval:       durationOption,
+	OptExactlyOnce:                        flagOption,
 }
 
 // CommonOptions is options common to all sinks
@@ -367,9 +393,9 @@ var CommonOptions = makeStringSet(OptCursor, OptEndTime, OptEnvelope,
 	OptFormat, OptFullTableName,
 	OptKeyInValue, OptTopicInValue,
 	OptResolvedTimestamps, OptUpdatedTimestamps,
-	OptMVCCTimestamps, OptDiff, OptSplitColumnFamilies,
+	OptMVCCTimestamps, OptTransactionID, OptTransactionMarkers, OptDiff, OptSplitColumnFamilies,
 	OptSchemaChangeEvents, OptSchemaChangePolicy,
-	OptOnError,
+	OptOnError, OptOnRowError, OptDLQTable, OptDLQSink,
 	OptInitialScan, OptNoInitialScan, OptInitialScanOnly, OptUnordered, OptCustomKeyColumn,
 	OptMinCheckpointFrequency, OptMetricsScope, OptVirtualColumns, Topics, OptExpirePTSAfter,
 	OptExecutionLocality, OptLaggingRangesThreshold, OptLaggingRangesPollingInterval,
@@ -379,7 +405,7 @@ var CommonOptions = makeStringSet(OptCursor, OptEndTime, OptEnvelope,
 var SQLValidOptions map[string]struct{} = nil
 
 // KafkaValidOptions is options exclusive to Kafka sink
-var KafkaValidOptions = makeStringSet(OptAvroSchemaPrefix, OptConfluentSchemaRegistry, OptKafkaSinkConfig)
+var KafkaValidOptions = makeStringSet(OptAvroSchemaPrefix, OptConfluentSchemaRegistry, OptKafkaSinkConfig, OptExactlyOnce)
 
 // CloudStorageValidOptions is options exclusive to cloud storage sink
 var CloudStorageValidOptions = makeStringSet(OptCompression)
@@ -400,7 +426,7 @@ var ExternalConnectionValidOptions = unionStringSets(SQLValidOptions, KafkaValid
 
 // CaseInsensitiveOpts options which supports case Insensitive value
 var CaseInsensitiveOpts = makeStringSet(OptFormat, OptEnvelope, OptCompression, OptSchemaChangeEvents,
-	OptSchemaChangePolicy, OptOnError, OptInitialScan)
+	OptSchemaChangePolicy, OptOnError, OptOnRowError, OptInitialScan)
 
 // RetiredOptions are the options which are no longer active.
 var RetiredOptions = makeStringSet(DeprecatedOptProtectDataFromGCOnPause)
@@ -430,6 +456,30 @@ var RedactedOptions = map[string]redactionFunc{
 	OptWebhookAuthHeader:       redactSimple,
 	SinkParamClientKey:         redactSimple,
 	OptConfluentSchemaRegistry: RedactUserFromURI,
+	OptDLQSink:                 redactSinkURI,
+}
+
+// redactSinkURI removes the user and any credentials passed as query
+// parameters from a sink URI.
+func redactSinkURI(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	for _, p := range []string{
+		SinkParamSASLPassword, SinkParamCACert, SinkParamClientCert,
+		SinkParamClientKey, SinkParamConfluentAPISecret,
+	} {
+		if q.Has(p) {
+			q.Set(p, `redacted`)
+		}
+	}
+	u.RawQuery = q.Encode()
+	if u.User != nil {
+		u.User = url.User(`redacted`)
+	}
+	return u.String(), nil
 }
 
 // NoLongerExperimental aliases options prefixed with experimental that no longer need to be
@@ -449,11 +499,11 @@ type OptionsSet map[string]struct{}
 // InitialScanOnlyUnsupportedOptions is options that are not supported with the
 // initial scan only option
 var InitialScanOnlyUnsupportedOptions OptionsSet = makeStringSet(OptEndTime, OptResolvedTimestamps, OptDiff,
-	OptMVCCTimestamps, OptUpdatedTimestamps)
+	OptMVCCTimestamps, OptUpdatedTimestamps, OptTransactionID, OptTransactionMarkers)
 
 // ParquetFormatUnsupportedOptions is options that are not supported with the
 // parquet format.
-var ParquetFormatUnsupportedOptions OptionsSet = makeStringSet(OptTopicInValue)
+var ParquetFormatUnsupportedOptions OptionsSet = makeStringSet(OptTopicInValue, OptDLQTable, OptDLQSink)
 
 // AlterChangefeedUnsupportedOptions are changefeed options that we do not allow
 // users to alter.
@@ -513,10 +563,16 @@ func makeDirectedInvertedIndex(pairs []dependentOption) map[string][]dependentOp
 
 var incompatibleOptionsMap = makeInvertedIndex([]incompatibleOptions{
 	{opt1: OptUnordered, opt2: OptResolvedTimestamps, reason: `resolved timestamps cannot be guaranteed to be correct in unordered mode`},
+	{opt1: OptUnordered, opt2: OptTransactionMarkers, reason: `transactions cannot be known to be complete in unordered mode`},
+	{opt1: OptTransactionMarkers, opt2: OptDLQTable, reason: `rows sent to a dead letter queue would be missing from the row counts of their transaction`},
+	{opt1: OptTransactionMarkers, opt2: OptDLQSink, reason: `rows sent to a dead letter queue would be missing from the row counts of their transaction`},
+	{opt1: OptExactlyOnce, opt2: OptDLQTable, reason: `a kafka transaction cannot be committed once the broker rejects one of its messages`},
+	{opt1: OptExactlyOnce, opt2: OptDLQSink, reason: `a kafka transaction cannot be committed once the broker rejects one of its messages`},
 })
 
 var dependentOptionsMap = makeDirectedInvertedIndex([]dependentOption{
 	{opt1: OptCustomKeyColumn, opt2: OptUnordered, reason: `using a value other than the primary key as the message key means end-to-end ordering cannot be preserved`},
+	{opt1: OptTransactionMarkers, opt2: OptTransactionID, reason: `markers are matched to rows by the transaction ID attached to each row`},
 })
 
 // MakeStatementOptions wraps and canonicalizes the options we get
@@ -764,6 +820,7 @@ type EncodingOptions struct {
 	TopicInValue      bool
 	UpdatedTimestamps bool
 	MVCCTimestamps    bool
+	TransactionID     bool
 	Diff              bool
 	AvroSchemaPrefix  string
 	SchemaRegistryURI string
@@ -809,7 +866,10 @@ func (s StatementOptions) GetEncodingOptions() (EncodingOptions, error) {
 	_, o.TopicInValue = s.m[OptTopicInValue]
 	_, o.UpdatedTimestamps = s.m[OptUpdatedTimestamps]
 	_, o.MVCCTimestamps = s.m[OptMVCCTimestamps]
+	_, o.TransactionID = s.m[OptTransactionID]
+	// The debezium envelope always includes the previous version of the row.
 	_, o.Diff = s.m[OptDiff]
+	o.Diff = o.Diff || o.Envelope == OptEnvelopeDebezium
 
 	o.SchemaRegistryURI = s.m[OptConfluentSchemaRegistry]
 	o.AvroSchemaPrefix = s.m[OptAvroSchemaPrefix]
@@ -827,6 +887,35 @@ func (e EncodingOptions) Validate() error {
 			OptEnvelope, OptEnvelopeRow, OptFormat, OptFormatAvro,
 		)
 	}
+	if e.Envelope == OptEnvelopeDebezium {
+		if e.Format != OptFormatJSON && e.Format != OptFormatAvro {
+			return errors.Errorf(`%s=%s is only usable with %s=%s or %s=%s`,
+				OptEnvelope, OptEnvelopeDebezium, OptFormat, OptFormatJSON, OptFormat, OptFormatAvro)
+		}
+		// The source block of debezium envelopes already carries the event
+		// timestamps, and debezium consumers do not expect any other fields.
+		unsupported := []struct {
+			k string
+			b bool
+		}{
+			{OptKeyInValue, e.KeyInValue},
+			{OptTopicInValue, e.TopicInValue},
+			{OptUpdatedTimestamps, e.UpdatedTimestamps},
+			{OptMVCCTimestamps, e.MVCCTimestamps},
+			{OptTransactionID, e.TransactionID},
+		}
+		for _, v := range unsupported {
+			if v.b {
+				return errors.Errorf(`%s is not supported with %s=%s`,
+					v.k, OptEnvelope, OptEnvelopeDebezium)
+			}
+		}
+		return nil
+	}
+	if e.TransactionID && e.Format != OptFormatJSON {
+		return errors.Errorf(`%s is only usable with %s=%s`,
+			OptTransactionID, OptFormat, OptFormatJSON)
+	}
 	if e.Envelope != OptEnvelopeWrapped && e.Format != OptFormatJSON && e.Format != OptFormatParquet {
 		requiresWrap := []struct {
 			k string
@@ -891,6 +980,7 @@ type Filters struct {
 // GetFilters returns a populated Filters.
 func (s StatementOptions) GetFilters() Filters {
 	_, withDiff := s.m[OptDiff]
+	withDiff = withDiff || s.m[OptEnvelope] == string(OptEnvelopeDebezium)
 	return Filters{
 		WithDiff: withDiff,
 	}
@@ -992,6 +1082,21 @@ func (s StatementOptions) IncludeVirtual() bool {
 	return s.m[OptVirtualColumns] == string(OptVirtualColumnsNull)
 }
 
+// EmitTransactionMarkers returns true if BEGIN and COMMIT markers should be
+// emitted for every transaction.
+func (s StatementOptions) EmitTransactionMarkers() bool {
+	_, ok := s.m[OptTransactionMarkers]
+	return ok
+}
+
+// ExactlyOnce returns true if rows should be written to the sink in kafka
+// transactions, each of which commits the resolved timestamps of the rows it
+// contains, so that no row is emitted twice.
+func (s StatementOptions) ExactlyOnce() bool {
+	_, ok := s.m[OptExactlyOnce]
+	return ok
+}
+
 // KeyOnly returns true if we are using the 'key_only' envelope.
 func (s StatementOptions) KeyOnly() bool {
 	return s.m[OptEnvelope] == string(OptEnvelopeKeyOnly)
@@ -1075,6 +1180,57 @@ func (s StatementOptions) GetOnError() (OnErrorType, error) {
 	return OnErrorType(v), nil
 }
 
+// GetOnRowError returns the desired behavior when a single row cannot be
+// encoded or emitted.
+func (s StatementOptions) GetOnRowError() (OnRowErrorType, error) {
+	v, err := s.getEnumValue(OptOnRowError)
+	if err != nil || v == `` {
+		return OptOnRowErrorFail, err
+	}
+	return OnRowErrorType(v), nil
+}
+
+// DeadLetterQueueOptions describe where rows that cannot be encoded or
+// emitted are recorded. Exactly one of Table and SinkURI is set when the
+// dead letter queue is enabled.
+type DeadLetterQueueOptions struct {
+	Enabled bool
+	Table   string
+	SinkURI string
+}
+
+// GetDeadLetterQueueOptions validates and returns the dead letter queue
+// configuration.
+func (s StatementOptions) GetDeadLetterQueueOptions() (DeadLetterQueueOptions, error) {
+	onRowError, err := s.GetOnRowError()
+	if err != nil {
+		return DeadLetterQueueOptions{}, err
+	}
+	o := DeadLetterQueueOptions{Table: s.m[OptDLQTable], SinkURI: s.m[OptDLQSink]}
+	if onRowError != OptOnRowErrorDLQ {
+		for _, opt := range []string{OptDLQTable, OptDLQSink} {
+			if s.IsSet(opt) {
+				return DeadLetterQueueOptions{}, errors.Newf(
+					`%s requires %s='%s'`, opt, OptOnRowError, OptOnRowErrorDLQ)
+			}
+		}
+		return DeadLetterQueueOptions{}, nil
+	}
+	if (o.Table == ``) == (o.SinkURI == ``) {
+		return DeadLetterQueueOptions{}, errors.Newf(
+			`%s='%s' requires exactly one of %s or %s`,
+			OptOnRowError, OptOnRowErrorDLQ, OptDLQTable, OptDLQSink)
+	}
+	o.Enabled = true
+	return o, nil
+}
+
+// SetDeadLetterTable replaces the dead letter table with its fully qualified
+// name so that it resolves the same way wherever the changefeed runs.
+func (s StatementOptions) SetDeadLetterTable(name string) {
+	s.m[OptDLQTable] = name
+}
+
 func describeEnum(strs ...string) string {
 	switch len(strs) {
 	case 1:
@@ -1133,6 +1289,12 @@ func (s StatementOptions) ValidateForCreateChangefeed(isPredicateChangefeed bool
 			return errors.Newf(`%s=%s is only usable with %s`, OptFormat, OptFormatCSV, OptInitialScanOnly)
 		}
 	}
+	if _, err := s.GetDeadLetterQueueOptions(); err != nil {
+		return err
+	}
+	if isPredicateChangefeed && s.m[OptEnvelope] == string(OptEnvelopeDebezium) {
+		return errors.Newf(`%s=%s is not supported with CDC queries`, OptEnvelope, OptEnvelopeDebezium)
+	}
 	// Right now parquet does not support any of these options
 	if s.m[OptFormat] == string(OptFormatParquet) {
 		if err := validateUnsupportedOptions(ParquetFormatUnsupportedOptions, fmt.Sprintf("format=%s", OptFormatParquet)); err != nil {
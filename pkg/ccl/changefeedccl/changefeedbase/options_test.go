@@ -44,6 +44,21 @@ func TestOptionsValidations(t *testing.T) {
 		{map[string]string{"initial_scan_only": "", "resolved": ""}, true, "cannot specify both initial_scan='only'"},
 		{map[string]string{"initial_scan_only": "", "resolved": ""}, true, "cannot specify both initial_scan='only'"},
 		{map[string]string{"key_column": "b"}, false, "requires the unordered option"},
+		{map[string]string{"on_row_error": "dlq", "dlq_table": "d"}, false, ""},
+		{map[string]string{"on_row_error": "dlq", "dlq_sink": "kafka://d"}, false, ""},
+		{map[string]string{"on_row_error": "skip"}, false, "unknown on_row_error"},
+		{map[string]string{"on_row_error": "dlq"}, false, "requires exactly one of dlq_table or dlq_sink"},
+		{map[string]string{"on_row_error": "dlq", "dlq_table": "d", "dlq_sink": "kafka://d"}, false, "requires exactly one of dlq_table or dlq_sink"},
+		{map[string]string{"dlq_table": "d"}, false, "dlq_table requires on_row_error='dlq'"},
+		{map[string]string{"on_row_error": "fail", "dlq_sink": "kafka://d"}, false, "dlq_sink requires on_row_error='dlq'"},
+		{map[string]string{"format": "parquet", "on_row_error": "dlq", "dlq_table": "d"}, false, "cannot specify both format=parquet and dlq_table"},
+		{map[string]string{"transaction_id": "", "transaction_markers": ""}, false, ""},
+		{map[string]string{"transaction_markers": ""}, false, "requires the transaction_id option"},
+		{map[string]string{"transaction_id": "", "transaction_markers": "", "unordered": ""}, false, "not usable with"},
+		{map[string]string{"transaction_id": "", "transaction_markers": "", "on_row_error": "dlq", "dlq_table": "d"}, false, "not usable with"},
+		{map[string]string{"initial_scan_only": "", "transaction_id": ""}, false, "cannot specify both initial_scan='only' and transaction_id"},
+		{map[string]string{"exactly_once": ""}, false, ""},
+		{map[string]string{"exactly_once": "", "on_row_error": "dlq", "dlq_sink": "kafka://d"}, false, "not usable with"},
 	}
 
 	for _, test := range tests {
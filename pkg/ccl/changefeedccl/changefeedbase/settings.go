@@ -50,6 +50,29 @@ var PerChangefeedMemLimit = settings.RegisterByteSizeSetting(
 	1<<29, // 512MiB
 	settings.WithPublic)
 
+// DeadLetterQueueMaxBufferedBytes is the maximum size of the records that a
+// change aggregator buffers for its dead letter queue between flushes.
+var DeadLetterQueueMaxBufferedBytes = settings.RegisterByteSizeSetting(
+	settings.ApplicationLevel,
+	"changefeed.dead_letter_queue.max_buffered_bytes",
+	"the maximum size of the rows that a changefeed aggregator buffers for its "+
+		"dead letter queue between flushes; rows which do not fit fail the changefeed",
+	64<<20, // 64 MiB
+	settings.PositiveInt,
+)
+
+// ExactlyOnceMaxHeldBytes is the maximum size of the rows that a change
+// aggregator of a changefeed with the exactly_once option holds back until
+// their spans are resolved.
+var ExactlyOnceMaxHeldBytes = settings.RegisterByteSizeSetting(
+	settings.ApplicationLevel,
+	"changefeed.exactly_once.max_held_bytes",
+	"the maximum size of the rows that a changefeed aggregator with the exactly_once "+
+		"option holds back until they are resolved; rows which do not fit fail the changefeed",
+	256<<20, // 256 MiB
+	settings.PositiveInt,
+)
+
 // SlowSpanLogThreshold controls when we will log slow spans.
 var SlowSpanLogThreshold = settings.RegisterDurationSetting(
 	settings.ApplicationLevel,
@@ -20,6 +20,10 @@ type Target struct {
 	TableID           descpb.ID
 	FamilyName        string
 	StatementTimeName StatementTimeName
+	// DatabaseName and SchemaName are the names of the database and schema
+	// containing the table when it was added to the changefeed, if known.
+	DatabaseName string
+	SchemaName   string
 }
 
 // StatementTimeName is the original way a table was referred to when it was added to
@@ -0,0 +1,349 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	gojson "encoding/json"
+	"fmt"
+	"time"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// When on_row_error='dlq' is set, a row which cannot be encoded, or which the
+// sink rejects for reasons specific to that message (e.g. it exceeds the
+// kafka broker's maximum message size), is recorded in a dead letter queue
+// instead of failing the changefeed. The dead letter queue is flushed along
+// with the sink, so a row is never considered processed by the changefeed's
+// checkpoint until it has either been emitted or dead-lettered.
+//
+// A dead letter table must be created by the user beforehand and have (at
+// least) the following columns:
+//
+//	CREATE TABLE dlq (
+//	  job_id INT8,
+//	  table_name STRING,
+//	  key BYTES,
+//	  row BYTES,
+//	  error STRING,
+//	  mvcc_timestamp DECIMAL
+//	)
+//
+// A dead letter sink receives one JSON message per record with the same
+// fields, where key and row are base64 encoded. Records are emitted to the
+// topic of the row they came from, subject to the topic naming parameters of
+// the dead letter sink URI. If the dead letter sink is the changefeed's sink,
+// these parameters must name topics distinct from the changefeed's.
+//
+// Records are buffered in memory until the next flush, and are accounted for
+// in the changefeed's memory pool, up to
+// changefeed.dead_letter_queue.max_buffered_bytes. When the buffer is full,
+// rows fail the changefeed as if there was no dead letter queue.
+//
+// The row is the message the changefeed attempted to emit, if encoding
+// succeeded, or a JSON rendering of the row's columns otherwise.
+
+// deadLetterTableColumns are the columns written to a dead letter table.
+const deadLetterTableColumns = `job_id, table_name, key, row, error, mvcc_timestamp`
+
+// deadLetterRecord is a row which could not be encoded or emitted.
+type deadLetterRecord struct {
+	topic TopicDescriptor
+	key   []byte
+	row   []byte
+	mvcc  hlc.Timestamp
+	err   error
+}
+
+// deadLetterQueue records rows which could not be encoded or emitted.
+// Add buffers a record, or returns an error if the buffer is full, and Flush
+// durably writes all records added before it was called. Records may be added
+// by sink goroutines, so implementations must be safe for concurrent use.
+type deadLetterQueue interface {
+	Add(ctx context.Context, r deadLetterRecord) error
+	Flush(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+// sinkWithDeadLetterQueue is implemented by sinks which can hand messages
+// rejected by the downstream system to a dead letter queue.
+type sinkWithDeadLetterQueue interface {
+	setDeadLetterQueue(dlq deadLetterQueue)
+}
+
+// makeDeadLetterQueue returns the dead letter queue configured for the
+// changefeed, or nil if on_row_error='dlq' is not set. The buffered records
+// are accounted for in memMon, if set.
+func makeDeadLetterQueue(
+	ctx context.Context,
+	cfg *execinfra.ServerConfig,
+	feed jobspb.ChangefeedDetails,
+	opts changefeedbase.StatementOptions,
+	user username.SQLUsername,
+	jobID jobspb.JobID,
+	memMon *mon.BytesMonitor,
+	metrics *sliMetrics,
+) (deadLetterQueue, error) {
+	dlqOpts, err := opts.GetDeadLetterQueueOptions()
+	if err != nil || !dlqOpts.Enabled {
+		return nil, err
+	}
+	if dlqOpts.Table != `` {
+		q := &tableDeadLetterQueue{
+			db:      cfg.ExecutorConfig.(*sql.ExecutorConfig).InternalDB,
+			user:    user,
+			jobID:   jobID,
+			table:   dlqOpts.Table,
+			metrics: metrics,
+		}
+		q.init(memMon)
+		return q, nil
+	}
+
+	// The dead letter sink only ever receives JSON records, so it is built
+	// with none of the options of the changefeed's own sink.
+	feed.SinkURI = dlqOpts.SinkURI
+	feed.Opts = map[string]string{
+		changefeedbase.OptFormat:   string(changefeedbase.OptFormatJSON),
+		changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeBare),
+	}
+	var nilOracle timestampLowerBoundOracle
+	sink, err := getAndDialSink(ctx, cfg, feed, nilOracle, user, jobID, (*sliMetrics)(nil))
+	if err != nil {
+		return nil, err
+	}
+	q := &sinkDeadLetterQueue{sink: sink, jobID: jobID, metrics: metrics}
+	q.init(memMon)
+	return q, nil
+}
+
+// deadLetterRecordOverhead is the memory used by a buffered record, besides
+// its key, row and error.
+const deadLetterRecordOverhead = int64(unsafe.Sizeof(deadLetterRecord{}))
+
+// deadLetterBuffer holds records which have not been flushed yet.
+type deadLetterBuffer struct {
+	mu struct {
+		syncutil.Mutex
+		// acc, if set, accounts for the memory of the records which were
+		// added and not flushed yet.
+		acc     *mon.BoundAccount
+		records []deadLetterRecord
+		// size is the memory accounted for records.
+		size int64
+	}
+}
+
+// init sets up the accounting of the buffered records in memMon, if set.
+func (b *deadLetterBuffer) init(memMon *mon.BytesMonitor) {
+	if memMon != nil {
+		acc := memMon.MakeBoundAccount()
+		b.mu.acc = &acc
+	}
+}
+
+// Add implements the deadLetterQueue interface.
+func (b *deadLetterBuffer) Add(ctx context.Context, r deadLetterRecord) error {
+	size := deadLetterRecordOverhead + int64(len(r.key)+len(r.row)+len(r.err.Error()))
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.mu.acc.Grow(ctx, size); err != nil {
+		return errors.Wrap(err, "buffering dead letter queue record")
+	}
+	b.mu.records = append(b.mu.records, r)
+	b.mu.size += size
+	return nil
+}
+
+// take returns the buffered records, and the memory accounted for them which
+// must be released once they are flushed.
+func (b *deadLetterBuffer) take() ([]deadLetterRecord, int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	records, size := b.mu.records, b.mu.size
+	b.mu.records, b.mu.size = nil, 0
+	return records, size
+}
+
+// release releases the memory of records which were taken.
+func (b *deadLetterBuffer) release(ctx context.Context, size int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.mu.acc.Shrink(ctx, size)
+}
+
+// close releases the memory of all records.
+func (b *deadLetterBuffer) close(ctx context.Context) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.mu.acc != nil {
+		b.mu.acc.Close(ctx)
+	}
+	b.mu.records, b.mu.size = nil, 0
+}
+
+// recordDeadLetterFlush updates metrics after writing records to a dead
+// letter queue. Records which failed to be written are dropped: the error
+// fails the flush, and the changefeed will retry the rows from its last
+// checkpoint.
+func recordDeadLetterFlush(metrics *sliMetrics, records []deadLetterRecord, err error) error {
+	if metrics != nil {
+		if err != nil {
+			metrics.DeadLetterErrors.Inc(1)
+		} else {
+			metrics.DeadLetterRows.Inc(int64(len(records)))
+		}
+	}
+	return errors.Wrap(err, "writing to dead letter queue")
+}
+
+// tableDeadLetterQueue writes records to a SQL table as the user who
+// created the changefeed.
+type tableDeadLetterQueue struct {
+	deadLetterBuffer
+	db      isql.DB
+	user    username.SQLUsername
+	jobID   jobspb.JobID
+	table   string
+	metrics *sliMetrics
+}
+
+var _ deadLetterQueue = (*tableDeadLetterQueue)(nil)
+
+// Flush implements the deadLetterQueue interface.
+func (q *tableDeadLetterQueue) Flush(ctx context.Context) error {
+	records, size := q.take()
+	if len(records) == 0 {
+		return nil
+	}
+	defer q.release(ctx, size)
+	stmt := fmt.Sprintf(`INSERT INTO %s (%s) VALUES ($1, $2, $3, $4, $5, $6)`,
+		q.table, deadLetterTableColumns)
+	err := q.db.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		for _, r := range records {
+			if _, err := txn.ExecEx(ctx, "changefeed-dead-letter", txn.KV(),
+				sessiondata.InternalExecutorOverride{User: q.user}, stmt,
+				int64(q.jobID), r.topic.GetTableName(), r.key, r.row, r.err.Error(),
+				eval.TimestampToDecimalDatum(r.mvcc),
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return recordDeadLetterFlush(q.metrics, records, err)
+}
+
+// Close implements the deadLetterQueue interface.
+func (q *tableDeadLetterQueue) Close(ctx context.Context) error {
+	q.close(ctx)
+	return nil
+}
+
+// deadLetterMessage is the JSON representation of a record emitted to a dead
+// letter sink.
+type deadLetterMessage struct {
+	JobID         int64  `json:"job_id"`
+	TableName     string `json:"table_name"`
+	Key           []byte `json:"key"`
+	Row           []byte `json:"row"`
+	Error         string `json:"error"`
+	MVCCTimestamp string `json:"mvcc_timestamp"`
+}
+
+// sinkDeadLetterQueue emits records to a sink. It must only be flushed from
+// a single goroutine, like the sinks themselves.
+type sinkDeadLetterQueue struct {
+	deadLetterBuffer
+	sink    Sink
+	jobID   jobspb.JobID
+	metrics *sliMetrics
+}
+
+var _ deadLetterQueue = (*sinkDeadLetterQueue)(nil)
+
+// Flush implements the deadLetterQueue interface.
+func (q *sinkDeadLetterQueue) Flush(ctx context.Context) error {
+	records, size := q.take()
+	if len(records) == 0 {
+		return nil
+	}
+	defer q.release(ctx, size)
+	err := func() error {
+		for _, r := range records {
+			value, err := gojson.Marshal(deadLetterMessage{
+				JobID:         int64(q.jobID),
+				TableName:     r.topic.GetTableName(),
+				Key:           r.key,
+				Row:           r.row,
+				Error:         r.err.Error(),
+				MVCCTimestamp: r.mvcc.AsOfSystemTime(),
+			})
+			if err != nil {
+				return err
+			}
+			if err := q.sink.EmitRow(
+				ctx, r.topic, r.key, value, r.mvcc, r.mvcc, kvevent.Alloc{},
+			); err != nil {
+				return err
+			}
+		}
+		return q.sink.Flush(ctx)
+	}()
+	return recordDeadLetterFlush(q.metrics, records, err)
+}
+
+// Close implements the deadLetterQueue interface.
+func (q *sinkDeadLetterQueue) Close(ctx context.Context) error {
+	q.close(ctx)
+	return q.sink.Close()
+}
+
+// isDeadLetterError returns true if err is specific to the row being
+// processed, rather than a transient or changefeed-wide failure which should
+// be handled by retrying the changefeed.
+func isDeadLetterError(ctx context.Context, err error) bool {
+	return ctx.Err() == nil && !changefeedbase.IsRetryableError(err)
+}
+
+// deadLetterRowJSON renders the columns of a row which could not be encoded
+// as a JSON object. Columns which cannot be converted to JSON are rendered
+// as strings.
+func deadLetterRowJSON(row cdcevent.Row) []byte {
+	b := json.NewObjectBuilder(len(row.ResultColumns()))
+	if err := row.ForAllColumns().Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
+		j, err := tree.AsJSON(d, sessiondatapb.DataConversionConfig{}, time.UTC)
+		if err != nil {
+			j = json.FromString(tree.AsStringWithFlags(d, tree.FmtExport))
+		}
+		b.Add(col.Name, j)
+		return nil
+	}); err != nil {
+		return []byte(row.DebugString())
+	}
+	return []byte(b.Build().String())
+}
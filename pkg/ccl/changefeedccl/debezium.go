@@ -0,0 +1,121 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/build"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+// The debezium envelope emits change events in the format produced by the
+// Debezium connectors, so that existing Debezium consumers (e.g. Kafka Connect
+// sinks) can process them. Every event value has the form:
+//
+//	{
+//	  "before": <the row before the change, or null>,
+//	  "after": <the row after the change, or null>,
+//	  "source": {
+//	    "version": ..., "connector": "cockroachdb", "name": <cluster name>,
+//	    "cluster_id": ..., "db": ..., "schema": ..., "table": ...,
+//	    "snapshot": "true" | "false", "ts_ms": ..., "mvcc_timestamp": ...
+//	  },
+//	  "op": "c" | "u" | "d" | "r",
+//	  "ts_ms": <the updated timestamp of the event in milliseconds>
+//	}
+//
+// Deletes are followed by a tombstone, which is a message with the same key
+// and a null value.
+
+// debeziumConnector is the connector name reported in the source block of
+// debezium envelopes.
+const debeziumConnector = `cockroachdb`
+
+// Debezium operation codes, reported in the op field of debezium envelopes.
+const (
+	debeziumOpCreate = `c`
+	debeziumOpUpdate = `u`
+	debeziumOpDelete = `d`
+	debeziumOpRead   = `r`
+)
+
+// clusterInfo identifies the cluster running a changefeed. It is reported in
+// the source block of debezium envelopes.
+type clusterInfo struct {
+	clusterID   string
+	clusterName string
+}
+
+func makeClusterInfo(execCfg *sql.ExecutorConfig) clusterInfo {
+	return clusterInfo{
+		clusterID:   execCfg.NodeInfo.LogicalClusterID().String(),
+		clusterName: execCfg.RPCContext.ClusterName(),
+	}
+}
+
+// debeziumOp returns the debezium operation code of a row change. Rows
+// emitted by initial scans and backfills are reported as snapshot reads.
+// Distinguishing inserts from updates requires the previous row, which is why
+// the debezium envelope implies the diff option.
+func debeziumOp(evCtx eventContext, updated, prev cdcevent.Row) string {
+	switch {
+	case updated.IsDeleted():
+		return debeziumOpDelete
+	case evCtx.backfill:
+		return debeziumOpRead
+	case prev.IsInitialized() && prev.HasValues() && !prev.IsDeleted():
+		return debeziumOpUpdate
+	default:
+		return debeziumOpCreate
+	}
+}
+
+// debeziumSource holds the values of the source block of a debezium envelope,
+// which describes where and when a change happened.
+type debeziumSource struct {
+	version, connector, name, clusterID string
+	db, schema, table                   string
+	snapshot                            string
+	tsMs                                int64
+	mvccTimestamp                       string
+}
+
+// makeDebeziumSource returns the source block for the given row. The database
+// and schema names are the ones recorded when the table was added to the
+// changefeed, and are left empty if they are unknown.
+func makeDebeziumSource(
+	cluster clusterInfo, targets changefeedbase.Targets, evCtx eventContext, row cdcevent.Row,
+) debeziumSource {
+	s := debeziumSource{
+		version:       build.BinaryVersion(),
+		connector:     debeziumConnector,
+		name:          cluster.clusterName,
+		clusterID:     cluster.clusterID,
+		table:         row.TableName,
+		snapshot:      strconv.FormatBool(evCtx.backfill),
+		tsMs:          timestampToMillis(evCtx.mvcc),
+		mvccTimestamp: timestampToString(evCtx.mvcc),
+	}
+	if target, ok := targets.FindByTableIDAndFamilyName(row.TableID, row.FamilyName); ok {
+		s.db = target.DatabaseName
+		s.schema = target.SchemaName
+	}
+	return s
+}
+
+// timestampToMillis returns the wall time of the timestamp in milliseconds
+// since the Unix epoch.
+func timestampToMillis(t hlc.Timestamp) int64 {
+	return t.WallTime / int64(time.Millisecond)
+}
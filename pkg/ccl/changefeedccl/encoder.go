@@ -47,14 +47,22 @@ func getEncoder(
 	encodeForQuery bool,
 	p externalConnectionProvider,
 	sliMetrics *sliMetrics,
+	cluster clusterInfo,
 ) (Encoder, error) {
 	switch opts.Format {
 	case changefeedbase.OptFormatJSON:
-		return makeJSONEncoder(jsonEncoderOptions{EncodingOptions: opts, encodeForQuery: encodeForQuery})
+		return makeJSONEncoder(jsonEncoderOptions{
+			EncodingOptions: opts,
+			encodeForQuery:  encodeForQuery,
+			targets:         targets,
+			cluster:         cluster,
+		})
 	case changefeedbase.OptFormatAvro, changefeedbase.DeprecatedOptFormatAvro:
-		return newConfluentAvroEncoder(opts, targets, p, sliMetrics)
+		return newConfluentAvroEncoder(opts, targets, p, sliMetrics, cluster)
 	case changefeedbase.OptFormatCSV:
 		return newCSVEncoder(opts), nil
+	case changefeedbase.OptFormatProtobuf:
+		return newProtobufEncoder(opts, targets, p, sliMetrics)
 	case changefeedbase.OptFormatParquet:
 		//We will return no encoder for parquet format because there is a separate
 		//sink implemented for parquet format for cloud storage, which does the job
@@ -38,6 +38,7 @@ type confluentAvroEncoder struct {
 	targets                   changefeedbase.Targets
 	envelopeType              changefeedbase.EnvelopeType
 	customKeyColumn           string
+	cluster                   clusterInfo
 
 	keyCache   *cache.UnorderedCache // [tableIDAndVersion]confluentRegisteredKeySchema
 	valueCache *cache.UnorderedCache // [tableIDAndVersionPair]confluentRegisteredEnvelopeSchema
@@ -79,12 +80,14 @@ func newConfluentAvroEncoder(
 	targets changefeedbase.Targets,
 	p externalConnectionProvider,
 	sliMetrics *sliMetrics,
+	cluster clusterInfo,
 ) (*confluentAvroEncoder, error) {
 	e := &confluentAvroEncoder{
 		schemaPrefix:            opts.AvroSchemaPrefix,
 		targets:                 targets,
 		virtualColumnVisibility: opts.VirtualColumns,
 		envelopeType:            opts.Envelope,
+		cluster:                 cluster,
 	}
 
 	e.updatedField = opts.UpdatedTimestamps
@@ -122,17 +125,27 @@ func newConfluentAvroEncoder(
 // Get the raw SQL-formatted string for a table name
 // and apply full_table_name and avro_schema_prefix options
 func (e *confluentAvroEncoder) rawTableName(eventMeta cdcevent.Metadata) (string, error) {
-	target, found := e.targets.FindByTableIDAndFamilyName(eventMeta.TableID, eventMeta.FamilyName)
+	return schemaRegistryTableName(e.targets, e.schemaPrefix, eventMeta)
+}
+
+// schemaRegistryTableName returns the raw SQL-formatted name of the table of
+// the event, with the full_table_name and avro_schema_prefix options applied.
+// It is the name from which schema registry subjects and schema names are
+// derived.
+func schemaRegistryTableName(
+	targets changefeedbase.Targets, schemaPrefix string, eventMeta cdcevent.Metadata,
+) (string, error) {
+	target, found := targets.FindByTableIDAndFamilyName(eventMeta.TableID, eventMeta.FamilyName)
 	if !found {
 		return eventMeta.TableName, errors.Newf("Could not find Target for %s", eventMeta)
 	}
 	switch target.Type {
 	case jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY:
-		return e.schemaPrefix + string(target.StatementTimeName), nil
+		return schemaPrefix + string(target.StatementTimeName), nil
 	case jobspb.ChangefeedTargetSpecification_EACH_FAMILY:
-		return fmt.Sprintf("%s%s.%s", e.schemaPrefix, target.StatementTimeName, eventMeta.FamilyName), nil
+		return fmt.Sprintf("%s%s.%s", schemaPrefix, target.StatementTimeName, eventMeta.FamilyName), nil
 	case jobspb.ChangefeedTargetSpecification_COLUMN_FAMILY:
-		return fmt.Sprintf("%s%s.%s", e.schemaPrefix, target.StatementTimeName, target.FamilyName), nil
+		return fmt.Sprintf("%s%s.%s", schemaPrefix, target.StatementTimeName, target.FamilyName), nil
 	default:
 		return "", errors.AssertionFailedf("Found a matching target with unimplemented type %s", target.Type)
 	}
@@ -248,11 +261,18 @@ func (e *confluentAvroEncoder) EncodeValue(
 		// In the wrapped envelope, row data goes in the "after" field. In the raw envelope,
 		// it goes in the "record" field. In the "key_only" envelope it's omitted.
 		// This means metadata can safely go at the top level as there are never arbitrary column names
-		// for it to conflict with.
-		if e.envelopeType == changefeedbase.OptEnvelopeWrapped {
+		// for it to conflict with. The debezium envelope is like the wrapped
+		// envelope, with debezium's metadata fields.
+		switch e.envelopeType {
+		case changefeedbase.OptEnvelopeWrapped:
 			opts = avroEnvelopeOpts{afterField: true, beforeField: e.beforeField, updatedField: e.updatedField}
 			afterDataSchema = currentSchema
-		} else {
+		case changefeedbase.OptEnvelopeDebezium:
+			opts = avroEnvelopeOpts{
+				beforeField: true, afterField: true, sourceField: true, opField: true, tsMsField: true,
+			}
+			afterDataSchema = currentSchema
+		default:
 			opts = avroEnvelopeOpts{recordField: true, updatedField: e.updatedField}
 			recordDataSchema = currentSchema
 		}
@@ -283,6 +303,13 @@ func (e *confluentAvroEncoder) EncodeValue(
 			`updated`: evCtx.updated,
 		}
 	}
+	if registered.schema.opts.sourceField {
+		meta = map[string]interface{}{
+			`source`: makeDebeziumSource(e.cluster, e.targets, evCtx, updatedRow),
+			`op`:     debeziumOp(evCtx, updatedRow, prevRow),
+			`ts_ms`:  evCtx.updated,
+		}
+	}
 
 	// https://docs.confluent.io/current/schema-registry/docs/serializer-formatter.html#wire-format
 	header := []byte{
@@ -335,5 +362,5 @@ func (e *confluentAvroEncoder) EncodeResolvedTimestamp(
 func (e *confluentAvroEncoder) register(
 	ctx context.Context, schema *avroRecord, subject string,
 ) (int32, error) {
-	return e.schemaRegistry.RegisterSchemaForSubject(ctx, subject, schema.codec.Schema())
+	return e.schemaRegistry.RegisterSchemaForSubject(ctx, subject, schema.codec.Schema(), confluentSchemaTypeAvro)
 }
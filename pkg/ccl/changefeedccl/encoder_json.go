@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/cache"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 )
 
@@ -36,22 +37,29 @@ import (
 // to its value. Updated timestamps in rows and resolved timestamp payloads are
 // stored in a sub-object under the `__crdb__` key in the top-level JSON object.
 type jsonEncoder struct {
-	updatedField, mvccTimestampField, beforeField, keyInValue, topicInValue bool
-	envelopeType                                                            changefeedbase.EnvelopeType
+	updatedField, mvccTimestampField, transactionIDField, beforeField bool
+	keyInValue, topicInValue                                          bool
+	envelopeType                                                      changefeedbase.EnvelopeType
 
 	buf             bytes.Buffer
 	versionEncoder  func(ed *cdcevent.EventDescriptor, isPrev bool) *versionEncoder
 	envelopeEncoder func(evCtx eventContext, updated, prev cdcevent.Row) (json.JSON, error)
 	customKeyColumn string
+
+	// targets and cluster are used to populate the source block of debezium
+	// envelopes.
+	targets changefeedbase.Targets
+	cluster clusterInfo
 }
 
 var _ Encoder = &jsonEncoder{}
 
 func canJSONEncodeMetadata(e changefeedbase.EnvelopeType) bool {
 	// bare envelopes use the _crdb_ key to avoid collisions with column names.
-	// wrapped envelopes can put metadata at the top level because the columns
-	// are nested under the "after:" key.
-	return e == changefeedbase.OptEnvelopeBare || e == changefeedbase.OptEnvelopeWrapped
+	// wrapped and debezium envelopes can put metadata at the top level because
+	// the columns are nested under the "after:" key.
+	return e == changefeedbase.OptEnvelopeBare || e == changefeedbase.OptEnvelopeWrapped ||
+		e == changefeedbase.OptEnvelopeDebezium
 }
 
 // getCachedOrCreate returns cached object, or creates and caches new one.
@@ -73,6 +81,8 @@ type jsonEncoderVersionKey struct {
 type jsonEncoderOptions struct {
 	changefeedbase.EncodingOptions
 	encodeForQuery bool
+	targets        changefeedbase.Targets
+	cluster        clusterInfo
 }
 
 func makeJSONEncoder(opts jsonEncoderOptions) (*jsonEncoder, error) {
@@ -81,12 +91,15 @@ func makeJSONEncoder(opts jsonEncoderOptions) (*jsonEncoder, error) {
 		envelopeType:       opts.Envelope,
 		updatedField:       opts.UpdatedTimestamps,
 		mvccTimestampField: opts.MVCCTimestamps,
+		transactionIDField: opts.TransactionID,
 		customKeyColumn:    opts.CustomKeyColumn,
 		// In the bare envelope we don't output diff directly, it's incorporated into the
 		// projection as desired.
 		beforeField:  opts.Diff && opts.Envelope != changefeedbase.OptEnvelopeBare,
 		keyInValue:   opts.KeyInValue,
 		topicInValue: opts.TopicInValue,
+		targets:      opts.targets,
+		cluster:      opts.cluster,
 		versionEncoder: func(ed *cdcevent.EventDescriptor, isPrev bool) *versionEncoder {
 			key := jsonEncoderVersionKey{
 				CacheKey: cdcevent.CacheKey{
@@ -116,11 +129,16 @@ func makeJSONEncoder(opts jsonEncoderOptions) (*jsonEncoder, error) {
 		}
 	}
 
-	if e.envelopeType == changefeedbase.OptEnvelopeWrapped {
+	switch e.envelopeType {
+	case changefeedbase.OptEnvelopeWrapped:
 		if err := e.initWrappedEnvelope(); err != nil {
 			return nil, err
 		}
-	} else {
+	case changefeedbase.OptEnvelopeDebezium:
+		if err := e.initDebeziumEnvelope(); err != nil {
+			return nil, err
+		}
+	default:
 		if err := e.initRawEnvelope(); err != nil {
 			return nil, err
 		}
@@ -236,6 +254,9 @@ func (e *jsonEncoder) initRawEnvelope() error {
 	if e.mvccTimestampField {
 		metaKeys = append(metaKeys, "mvcc_timestamp")
 	}
+	if e.transactionIDField {
+		metaKeys = append(metaKeys, "transaction_id")
+	}
 	if e.keyInValue {
 		metaKeys = append(metaKeys, "key")
 	}
@@ -272,6 +293,12 @@ func (e *jsonEncoder) initRawEnvelope() error {
 			}
 		}
 
+		if e.transactionIDField {
+			if err := metaBuilder.Set("transaction_id", transactionIDJSON(evCtx)); err != nil {
+				return nil, err
+			}
+		}
+
 		if e.keyInValue {
 			if err := ve.encodeKeyInValue(updated, metaBuilder); err != nil {
 				return nil, err
@@ -310,6 +337,9 @@ func (e *jsonEncoder) initWrappedEnvelope() error {
 	if e.mvccTimestampField {
 		keys = append(keys, "mvcc_timestamp")
 	}
+	if e.transactionIDField {
+		keys = append(keys, "transaction_id")
+	}
 	b, err := json.NewFixedKeysObjectBuilder(keys)
 	if err != nil {
 		return err
@@ -366,6 +396,87 @@ func (e *jsonEncoder) initWrappedEnvelope() error {
 			}
 		}
 
+		if e.transactionIDField {
+			if err := b.Set("transaction_id", transactionIDJSON(evCtx)); err != nil {
+				return nil, err
+			}
+		}
+
+		return b.Build()
+	}
+	return nil
+}
+
+func (e *jsonEncoder) initDebeziumEnvelope() error {
+	b, err := json.NewFixedKeysObjectBuilder([]string{"before", "after", "source", "op", "ts_ms"})
+	if err != nil {
+		return err
+	}
+	sb, err := json.NewFixedKeysObjectBuilder([]string{
+		"version", "connector", "name", "cluster_id", "db", "schema", "table",
+		"snapshot", "ts_ms", "mvcc_timestamp",
+	})
+	if err != nil {
+		return err
+	}
+
+	const emitDeletedRowAsNull = true
+	e.envelopeEncoder = func(evCtx eventContext, updated, prev cdcevent.Row) (json.JSON, error) {
+		after, err := e.versionEncoder(updated.EventDescriptor, false).rowAsGoNative(updated, emitDeletedRowAsNull, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := b.Set("after", after); err != nil {
+			return nil, err
+		}
+
+		var before json.JSON
+		if prev.IsInitialized() && !prev.IsDeleted() {
+			before, err = e.versionEncoder(prev.EventDescriptor, true).rowAsGoNative(prev, emitDeletedRowAsNull, nil)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			before = json.NullJSONValue
+		}
+		if err := b.Set("before", before); err != nil {
+			return nil, err
+		}
+
+		source := makeDebeziumSource(e.cluster, e.targets, evCtx, updated)
+		for _, f := range []struct {
+			k string
+			v json.JSON
+		}{
+			{"version", json.FromString(source.version)},
+			{"connector", json.FromString(source.connector)},
+			{"name", json.FromString(source.name)},
+			{"cluster_id", json.FromString(source.clusterID)},
+			{"db", json.FromString(source.db)},
+			{"schema", json.FromString(source.schema)},
+			{"table", json.FromString(source.table)},
+			{"snapshot", json.FromString(source.snapshot)},
+			{"ts_ms", json.FromInt64(source.tsMs)},
+			{"mvcc_timestamp", json.FromString(source.mvccTimestamp)},
+		} {
+			if err := sb.Set(f.k, f.v); err != nil {
+				return nil, err
+			}
+		}
+		sourceJSON, err := sb.Build()
+		if err != nil {
+			return nil, err
+		}
+		if err := b.Set("source", sourceJSON); err != nil {
+			return nil, err
+		}
+
+		if err := b.Set("op", json.FromString(debeziumOp(evCtx, updated, prev))); err != nil {
+			return nil, err
+		}
+		if err := b.Set("ts_ms", json.FromInt64(timestampToMillis(evCtx.updated))); err != nil {
+			return nil, err
+		}
 		return b.Build()
 	}
 	return nil
@@ -400,7 +511,8 @@ func (e *jsonEncoder) EncodeResolvedTimestamp(
 		`resolved`: eval.TimestampToDecimalDatum(resolved).Decimal.String(),
 	}
 	var jsonEntries interface{}
-	if e.envelopeType == changefeedbase.OptEnvelopeWrapped {
+	if e.envelopeType == changefeedbase.OptEnvelopeWrapped ||
+		e.envelopeType == changefeedbase.OptEnvelopeDebezium {
 		jsonEntries = meta
 	} else {
 		jsonEntries = map[string]interface{}{
@@ -410,6 +522,15 @@ func (e *jsonEncoder) EncodeResolvedTimestamp(
 	return gojson.Marshal(jsonEntries)
 }
 
+// transactionIDJSON returns the ID of the transaction which wrote the event,
+// or null if it is unknown.
+func transactionIDJSON(evCtx eventContext) json.JSON {
+	if evCtx.txnID.Equal(uuid.Nil) {
+		return json.NullJSONValue
+	}
+	return json.FromString(evCtx.txnID.String())
+}
+
 var placeholderCtx = eventContext{topic: "topic"}
 
 // EncodeAsJSONChangefeedWithFlags implements the crdb_internal.to_json_as_changefeed_with_flags
@@ -0,0 +1,627 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Field numbers of the envelope messages. They are the same for every table,
+// so that consumers can tell the fields of row and resolved messages apart
+// without looking at their schemas.
+const (
+	protobufAfterFieldNumber         = 1
+	protobufBeforeFieldNumber        = 2
+	protobufUpdatedFieldNumber       = 3
+	protobufMVCCTimestampFieldNumber = 4
+	protobufResolvedFieldNumber      = 5
+)
+
+// protobufEncoder encodes changefeed entries as protocol buffers. The message
+// types are generated from the table descriptors: keys are messages of the
+// primary key columns, and rows are messages with one optional field per
+// column, numbered by column ID so that the generated schemas stay compatible
+// across schema changes. In the wrapped envelope, values are envelope messages
+// holding the rows and the requested metadata. In the other envelopes, values
+// are the row messages themselves.
+//
+// If a schema registry is configured, the generated .proto files are
+// registered with it and the messages are prefixed with the Confluent wire
+// format header. Otherwise, the messages are emitted as is.
+type protobufEncoder struct {
+	schemaRegistry                                schemaRegistry // nil if no registry is configured
+	schemaPrefix                                  string
+	updatedField, mvccTimestampField, beforeField bool
+	targets                                       changefeedbase.Targets
+	envelopeType                                  changefeedbase.EnvelopeType
+	customKeyColumn                               string
+
+	keyCache   *cache.UnorderedCache // [tableIDAndVersion]*protobufSchema
+	valueCache *cache.UnorderedCache // [tableIDAndVersionPair]*protobufSchema
+
+	// resolvedCache doesn't need to be bounded like the other caches because
+	// the number of topics is fixed per changefeed.
+	resolvedCache map[string]*protobufSchema
+
+	formatter *tree.FmtCtx
+	buf       []byte
+}
+
+var _ Encoder = &protobufEncoder{}
+
+// protobufSchema is a message type generated for a changefeed key or value,
+// along with the id it was registered with in the schema registry.
+type protobufSchema struct {
+	// desc is the encoded message, which is always the first message of its
+	// file.
+	desc protoreflect.MessageDescriptor
+	// rows are the row messages of the file, in the order they were added. The
+	// first one is the encoded message for keys and unwrapped values, and the
+	// after field for wrapped values. The second one, if any, is the before
+	// field.
+	rows []*protobufRowMessage
+	// text is the .proto source of the file.
+	text       string
+	registryID int32
+}
+
+// protobufRowMessage is a message type with one field per column of a row.
+type protobufRowMessage struct {
+	desc protoreflect.MessageDescriptor
+	// fieldByCol maps column ordinals to the fields of the message.
+	fieldByCol map[int]protoreflect.FieldDescriptor
+}
+
+func newProtobufEncoder(
+	opts changefeedbase.EncodingOptions,
+	targets changefeedbase.Targets,
+	p externalConnectionProvider,
+	sliMetrics *sliMetrics,
+) (*protobufEncoder, error) {
+	e := &protobufEncoder{
+		schemaPrefix:       opts.AvroSchemaPrefix,
+		updatedField:       opts.UpdatedTimestamps,
+		mvccTimestampField: opts.MVCCTimestamps,
+		beforeField:        opts.Diff,
+		targets:            targets,
+		envelopeType:       opts.Envelope,
+		customKeyColumn:    opts.CustomKeyColumn,
+		formatter:          tree.NewFmtCtx(tree.FmtExport),
+	}
+
+	if opts.KeyInValue {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptKeyInValue, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+	if opts.TopicInValue {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptTopicInValue, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+
+	if len(opts.SchemaRegistryURI) != 0 {
+		reg, err := newConfluentSchemaRegistry(opts.SchemaRegistryURI, p, sliMetrics)
+		if err != nil {
+			return nil, err
+		}
+		e.schemaRegistry = reg
+	}
+
+	e.keyCache = cache.NewUnorderedCache(encoderCacheConfig)
+	e.valueCache = cache.NewUnorderedCache(encoderCacheConfig)
+	e.resolvedCache = make(map[string]*protobufSchema)
+	return e, nil
+}
+
+// keyColumns returns the columns of the key of the given row.
+func (e *protobufEncoder) keyColumns(row cdcevent.Row) (cdcevent.Iterator, error) {
+	if e.customKeyColumn != "" {
+		return row.DatumNamed(e.customKeyColumn)
+	}
+	return row.ForEachKeyColumn(), nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *protobufEncoder) EncodeKey(ctx context.Context, row cdcevent.Row) ([]byte, error) {
+	schema, err := e.keySchema(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	it, err := e.keyColumns(row)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := schema.rows[0].messageFromRow(it, e.formatter)
+	if err != nil {
+		return nil, err
+	}
+	return e.marshal(schema, msg)
+}
+
+// keySchema returns the schema of the keys of the given row, generating and
+// registering it if needed.
+func (e *protobufEncoder) keySchema(ctx context.Context, row cdcevent.Row) (*protobufSchema, error) {
+	// No familyID in the cache key for keys because it's the same schema for all families
+	cacheKey := tableIDAndVersion{tableID: row.TableID, version: row.Version}
+	if v, ok := e.keyCache.Get(cacheKey); ok {
+		return v.(*protobufSchema), nil
+	}
+
+	tableName, err := schemaRegistryTableName(e.targets, e.schemaPrefix, row.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	it, err := e.keyColumns(row)
+	if err != nil {
+		return nil, err
+	}
+	b := newProtobufSchemaBuilder(SQLNameToAvroName(tableName) + `_key`)
+	if err := b.addRowMessage(SQLNameToAvroName(tableName), it); err != nil {
+		return nil, err
+	}
+	schema, err := b.build()
+	if err != nil {
+		return nil, err
+	}
+
+	// NB: This uses the kafka name escaper because it has to match the name
+	// of the kafka topic.
+	subject := SQLNameToKafkaName(tableName) + confluentSubjectSuffixKey
+	if err := e.register(ctx, schema, subject); err != nil {
+		return nil, err
+	}
+	e.keyCache.Add(cacheKey, schema)
+	return schema, nil
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *protobufEncoder) EncodeValue(
+	ctx context.Context, evCtx eventContext, updatedRow cdcevent.Row, prevRow cdcevent.Row,
+) ([]byte, error) {
+	if e.envelopeType == changefeedbase.OptEnvelopeKeyOnly {
+		return nil, nil
+	}
+	if e.envelopeType != changefeedbase.OptEnvelopeWrapped && updatedRow.IsDeleted() {
+		// Outside of the wrapped envelope, deletes are tombstones.
+		return nil, nil
+	}
+
+	schema, err := e.valueSchema(ctx, updatedRow, prevRow)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.envelopeType != changefeedbase.OptEnvelopeWrapped {
+		msg, err := schema.rows[0].messageFromRow(updatedRow.ForEachColumn(), e.formatter)
+		if err != nil {
+			return nil, err
+		}
+		return e.marshal(schema, msg)
+	}
+
+	envelope := dynamicpb.NewMessage(schema.desc)
+	fields := schema.desc.Fields()
+	if !updatedRow.IsDeleted() {
+		after, err := schema.rows[0].messageFromRow(updatedRow.ForEachColumn(), e.formatter)
+		if err != nil {
+			return nil, err
+		}
+		envelope.Set(fields.ByNumber(protobufAfterFieldNumber), protoreflect.ValueOfMessage(after))
+	}
+	if len(schema.rows) > 1 && prevRow.HasValues() && !prevRow.IsDeleted() {
+		before, err := schema.rows[1].messageFromRow(prevRow.ForEachColumn(), e.formatter)
+		if err != nil {
+			return nil, err
+		}
+		envelope.Set(fields.ByNumber(protobufBeforeFieldNumber), protoreflect.ValueOfMessage(before))
+	}
+	if e.updatedField {
+		envelope.Set(fields.ByNumber(protobufUpdatedFieldNumber),
+			protoreflect.ValueOfString(timestampToString(evCtx.updated)))
+	}
+	if e.mvccTimestampField {
+		envelope.Set(fields.ByNumber(protobufMVCCTimestampFieldNumber),
+			protoreflect.ValueOfString(timestampToString(evCtx.mvcc)))
+	}
+	return e.marshal(schema, envelope)
+}
+
+// valueSchema returns the schema of the values of the given rows, generating
+// and registering it if needed.
+func (e *protobufEncoder) valueSchema(
+	ctx context.Context, updatedRow cdcevent.Row, prevRow cdcevent.Row,
+) (*protobufSchema, error) {
+	withBefore := e.envelopeType == changefeedbase.OptEnvelopeWrapped && e.beforeField &&
+		prevRow.IsInitialized()
+
+	var cacheKey tableIDAndVersionPair
+	if withBefore {
+		cacheKey[0] = tableIDAndVersion{
+			tableID: prevRow.TableID, version: prevRow.Version, familyID: prevRow.FamilyID,
+		}
+	}
+	cacheKey[1] = tableIDAndVersion{
+		tableID: updatedRow.TableID, version: updatedRow.Version, familyID: updatedRow.FamilyID,
+	}
+	if v, ok := e.valueCache.Get(cacheKey); ok {
+		return v.(*protobufSchema), nil
+	}
+
+	name, err := schemaRegistryTableName(e.targets, e.schemaPrefix, updatedRow.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	rowName := SQLNameToAvroName(name)
+
+	var b *protobufSchemaBuilder
+	if e.envelopeType == changefeedbase.OptEnvelopeWrapped {
+		b = newProtobufSchemaBuilder(rowName + `_envelope`)
+		envelope := b.addMessage(rowName + `_envelope`)
+		addProtobufMessageField(envelope, `after`, protobufAfterFieldNumber, rowName)
+		if withBefore {
+			addProtobufMessageField(envelope, `before`, protobufBeforeFieldNumber, rowName+`_before`)
+		}
+		if e.updatedField {
+			addProtobufStringField(envelope, `updated`, protobufUpdatedFieldNumber)
+		}
+		if e.mvccTimestampField {
+			addProtobufStringField(envelope, `mvcc_timestamp`, protobufMVCCTimestampFieldNumber)
+		}
+	} else {
+		b = newProtobufSchemaBuilder(rowName)
+	}
+	if err := b.addRowMessage(rowName, updatedRow.ForEachColumn()); err != nil {
+		return nil, err
+	}
+	if withBefore {
+		if err := b.addRowMessage(rowName+`_before`, prevRow.ForEachColumn()); err != nil {
+			return nil, err
+		}
+	}
+	schema, err := b.build()
+	if err != nil {
+		return nil, err
+	}
+
+	// NB: This uses the kafka name escaper because it has to match the name
+	// of the kafka topic.
+	subject := SQLNameToKafkaName(name) + confluentSubjectSuffixValue
+	if err := e.register(ctx, schema, subject); err != nil {
+		return nil, err
+	}
+	e.valueCache.Add(cacheKey, schema)
+	return schema, nil
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *protobufEncoder) EncodeResolvedTimestamp(
+	ctx context.Context, topic string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	schema, ok := e.resolvedCache[topic]
+	if !ok {
+		name := SQLNameToAvroName(topic) + `_envelope`
+		b := newProtobufSchemaBuilder(name)
+		addProtobufStringField(b.addMessage(name), `resolved`, protobufResolvedFieldNumber)
+		var err error
+		if schema, err = b.build(); err != nil {
+			return nil, err
+		}
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(topic) + confluentSubjectSuffixValue
+		if err := e.register(ctx, schema, subject); err != nil {
+			return nil, err
+		}
+		e.resolvedCache[topic] = schema
+	}
+
+	msg := dynamicpb.NewMessage(schema.desc)
+	msg.Set(schema.desc.Fields().ByNumber(protobufResolvedFieldNumber),
+		protoreflect.ValueOfString(timestampToString(resolved)))
+	return e.marshal(schema, msg)
+}
+
+// register registers the schema with the schema registry, if there is one.
+func (e *protobufEncoder) register(
+	ctx context.Context, schema *protobufSchema, subject string,
+) (err error) {
+	if e.schemaRegistry == nil {
+		return nil
+	}
+	schema.registryID, err = e.schemaRegistry.RegisterSchemaForSubject(
+		ctx, subject, schema.text, confluentSchemaTypeProtobuf)
+	return err
+}
+
+// marshal serializes the message, prefixed with the Confluent wire format
+// header if a schema registry is configured.
+func (e *protobufEncoder) marshal(schema *protobufSchema, msg proto.Message) ([]byte, error) {
+	e.buf = e.buf[:0]
+	if e.schemaRegistry != nil {
+		// https://docs.confluent.io/platform/current/schema-registry/fundamentals/serdes-develop/index.html#wire-format
+		//
+		// The header is followed by the indexes of the message in its file. The
+		// encoded message is always the first message of its file, whose indexes
+		// are encoded as a single 0.
+		e.buf = append(e.buf,
+			changefeedbase.ConfluentAvroWireFormatMagic,
+			0, 0, 0, 0, // Placeholder for the ID.
+			0, // Message indexes.
+		)
+		binary.BigEndian.PutUint32(e.buf[1:5], uint32(schema.registryID))
+	}
+	var err error
+	e.buf, err = proto.MarshalOptions{Deterministic: true}.MarshalAppend(e.buf, msg)
+	return e.buf, err
+}
+
+// messageFromRow returns a message holding the datums of the row.
+func (m *protobufRowMessage) messageFromRow(
+	it cdcevent.Iterator, formatter *tree.FmtCtx,
+) (*dynamicpb.Message, error) {
+	msg := dynamicpb.NewMessage(m.desc)
+	if err := it.Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
+		field, ok := m.fieldByCol[col.Ordinal()]
+		if !ok {
+			return errors.AssertionFailedf(
+				"column %s not found in protobuf message %s", col.Name, m.desc.FullName())
+		}
+		if d == tree.DNull {
+			return nil
+		}
+		v, err := datumToProtobufValue(d, field.Kind(), formatter)
+		if err != nil {
+			return err
+		}
+		msg.Set(field, v)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// columnTypeToProtobufType returns the type of the protobuf field of a column.
+// Types without a lossless protobuf equivalent are encoded as their text
+// representation.
+func columnTypeToProtobufType(typ *types.T) descriptorpb.FieldDescriptorProto_Type {
+	switch typ.Family() {
+	case types.BoolFamily:
+		return descriptorpb.FieldDescriptorProto_TYPE_BOOL
+	case types.IntFamily:
+		return descriptorpb.FieldDescriptorProto_TYPE_INT64
+	case types.FloatFamily:
+		return descriptorpb.FieldDescriptorProto_TYPE_DOUBLE
+	case types.BytesFamily:
+		return descriptorpb.FieldDescriptorProto_TYPE_BYTES
+	default:
+		return descriptorpb.FieldDescriptorProto_TYPE_STRING
+	}
+}
+
+// datumToProtobufValue converts a non-NULL datum to the value of a protobuf
+// field of the given kind.
+func datumToProtobufValue(
+	d tree.Datum, kind protoreflect.Kind, formatter *tree.FmtCtx,
+) (protoreflect.Value, error) {
+	if kind == protoreflect.StringKind {
+		if s, ok := tree.AsDString(d); ok {
+			return protoreflect.ValueOfString(string(s)), nil
+		}
+		formatter.Reset()
+		formatter.FormatNode(d)
+		return protoreflect.ValueOfString(formatter.String()), nil
+	}
+	switch t := tree.UnwrapDOidWrapper(d).(type) {
+	case *tree.DBool:
+		return protoreflect.ValueOfBool(bool(*t)), nil
+	case *tree.DInt:
+		return protoreflect.ValueOfInt64(int64(*t)), nil
+	case *tree.DFloat:
+		return protoreflect.ValueOfFloat64(float64(*t)), nil
+	case *tree.DBytes:
+		return protoreflect.ValueOfBytes([]byte(*t)), nil
+	}
+	return protoreflect.Value{}, errors.AssertionFailedf(
+		"cannot encode %T as a protobuf %s", d, kind)
+}
+
+// protobufSchemaBuilder accumulates the messages of a generated .proto file.
+type protobufSchemaBuilder struct {
+	file *descriptorpb.FileDescriptorProto
+	// rowCols holds the column ordinals of the fields of each row message, in
+	// the order the row messages were added.
+	rowCols []rowMessageCols
+}
+
+type rowMessageCols struct {
+	name string
+	cols []int
+}
+
+func newProtobufSchemaBuilder(name string) *protobufSchemaBuilder {
+	return &protobufSchemaBuilder{
+		file: &descriptorpb.FileDescriptorProto{
+			Name:   proto.String(name + `.proto`),
+			Syntax: proto.String(`proto3`),
+		},
+	}
+}
+
+// addMessage adds an empty message to the file. The first message added is
+// the one that gets encoded.
+func (b *protobufSchemaBuilder) addMessage(name string) *descriptorpb.DescriptorProto {
+	msg := &descriptorpb.DescriptorProto{Name: proto.String(name)}
+	b.file.MessageType = append(b.file.MessageType, msg)
+	return msg
+}
+
+// addRowMessage adds a message with an optional field for every column of
+// the iterator. Fields are numbered by column ID, so that they keep their
+// numbers when columns are added or dropped.
+func (b *protobufSchemaBuilder) addRowMessage(name string, it cdcevent.Iterator) error {
+	var cols []cdcevent.ResultColumn
+	if err := it.Col(func(col cdcevent.ResultColumn) error {
+		cols = append(cols, col)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// Column IDs are used as field numbers when they are all valid field
+	// numbers; otherwise, fields are numbered by position.
+	useIDs := true
+	seen := make(map[uint32]struct{}, len(cols))
+	for _, col := range cols {
+		n := protoreflect.FieldNumber(col.PGAttributeNum)
+		_, dup := seen[col.PGAttributeNum]
+		if !n.IsValid() || dup {
+			useIDs = false
+			break
+		}
+		seen[col.PGAttributeNum] = struct{}{}
+	}
+
+	msg := b.addMessage(name)
+	rc := rowMessageCols{name: name}
+	numbers := make([]int32, len(cols))
+	fieldNames := make([]string, len(cols))
+	usedNames := make(map[string]struct{}, 2*len(cols))
+	// Proto3 rejects field names which are equal once lowercased and stripped
+	// of underscores, which distinct column names can be. Such names are made
+	// unique by appending the field number.
+	conflictKeys := make(map[string]struct{}, len(cols))
+	conflictKey := func(s string) string {
+		return strings.ReplaceAll(strings.ToLower(s), `_`, ``)
+	}
+	for i, col := range cols {
+		numbers[i] = int32(i + 1)
+		if useIDs {
+			numbers[i] = int32(col.PGAttributeNum)
+		}
+		fieldNames[i] = SQLNameToAvroName(col.Name)
+		for {
+			if _, ok := conflictKeys[conflictKey(fieldNames[i])]; !ok {
+				break
+			}
+			fieldNames[i] = fmt.Sprintf(`%s_%d`, fieldNames[i], numbers[i])
+		}
+		conflictKeys[conflictKey(fieldNames[i])] = struct{}{}
+		usedNames[fieldNames[i]] = struct{}{}
+	}
+	for i, col := range cols {
+		// Optional fields of proto3 are declared with a synthetic oneof, which
+		// must not clash with the names of the fields.
+		oneofName := `_` + fieldNames[i]
+		for _, ok := usedNames[oneofName]; ok; _, ok = usedNames[oneofName] {
+			oneofName = `X` + oneofName
+		}
+		usedNames[oneofName] = struct{}{}
+		msg.OneofDecl = append(msg.OneofDecl, &descriptorpb.OneofDescriptorProto{
+			Name: proto.String(oneofName),
+		})
+		msg.Field = append(msg.Field, &descriptorpb.FieldDescriptorProto{
+			Name:           proto.String(fieldNames[i]),
+			Number:         proto.Int32(numbers[i]),
+			Label:          descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:           columnTypeToProtobufType(col.Typ).Enum(),
+			OneofIndex:     proto.Int32(int32(i)),
+			Proto3Optional: proto.Bool(true),
+		})
+		rc.cols = append(rc.cols, col.Ordinal())
+	}
+	b.rowCols = append(b.rowCols, rc)
+	return nil
+}
+
+// addProtobufMessageField adds a field of the given message type.
+func addProtobufMessageField(
+	msg *descriptorpb.DescriptorProto, name string, number int32, typeName string,
+) {
+	msg.Field = append(msg.Field, &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		Number:   proto.Int32(number),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+		TypeName: proto.String(`.` + typeName),
+	})
+}
+
+// addProtobufStringField adds a string field.
+func addProtobufStringField(msg *descriptorpb.DescriptorProto, name string, number int32) {
+	msg.Field = append(msg.Field, &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(number),
+		Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+	})
+}
+
+// build validates the file and returns its schema.
+func (b *protobufSchemaBuilder) build() (*protobufSchema, error) {
+	fd, err := protodesc.NewFile(b.file, nil /* resolver */)
+	if err != nil {
+		return nil, errors.NewAssertionErrorWithWrappedErrf(err, "invalid protobuf schema")
+	}
+	schema := &protobufSchema{
+		desc: fd.Messages().Get(0),
+		text: protobufSchemaText(b.file),
+	}
+	for _, rc := range b.rowCols {
+		desc := fd.Messages().ByName(protoreflect.Name(rc.name))
+		m := &protobufRowMessage{
+			desc:       desc,
+			fieldByCol: make(map[int]protoreflect.FieldDescriptor, len(rc.cols)),
+		}
+		for i, col := range rc.cols {
+			m.fieldByCol[col] = desc.Fields().Get(i)
+		}
+		schema.rows = append(schema.rows, m)
+	}
+	return schema, nil
+}
+
+// protobufSchemaText renders the file as the source of a .proto file, which
+// is how protobuf schemas are registered with the schema registry.
+func protobufSchemaText(file *descriptorpb.FileDescriptorProto) string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "syntax = %q;\n", file.GetSyntax())
+	for _, msg := range file.MessageType {
+		fmt.Fprintf(&buf, "\nmessage %s {\n", msg.GetName())
+		for _, field := range msg.Field {
+			buf.WriteString("  ")
+			if field.GetProto3Optional() {
+				buf.WriteString("optional ")
+			}
+			typeName := strings.ToLower(strings.TrimPrefix(field.GetType().String(), `TYPE_`))
+			if field.GetType() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE {
+				typeName = strings.TrimPrefix(field.GetTypeName(), `.`)
+			}
+			fmt.Fprintf(&buf, "%s %s = %d;\n", typeName, field.GetName(), field.GetNumber())
+		}
+		buf.WriteString("}\n")
+	}
+	return buf.String()
+}
@@ -9,9 +9,12 @@
 package changefeedccl
 
 import (
+	"bytes"
 	"context"
 	gosql "database/sql"
 	"encoding/base64"
+	"encoding/binary"
+	gojson "encoding/json"
 	"fmt"
 	"math/rand"
 	"net/url"
@@ -20,6 +23,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach-go/v2/crdb"
+	"github.com/cockroachdb/cockroach/pkg/build"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
@@ -40,6 +44,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/workload/ledger"
 	"github.com/cockroachdb/cockroach/pkg/workload/workloadsql"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
 )
 
 func TestEncoders(t *testing.T) {
@@ -240,7 +247,7 @@ func TestEncoders(t *testing.T) {
 				return
 			}
 			require.NoError(t, o.Validate())
-			e, err := getEncoder(o, targets, false, nil, nil)
+			e, err := getEncoder(o, targets, false, nil, nil, clusterInfo{})
 			require.NoError(t, err)
 
 			rowInsert := cdcevent.TestingMakeEventRow(tableDesc, 0, row, false)
@@ -271,6 +278,406 @@ func TestEncoders(t *testing.T) {
 	}
 }
 
+func TestDebeziumEncoders(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	defer build.TestingOverrideVersion("v23.2.0")()
+
+	tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+	require.NoError(t, err)
+	row := rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.NewDString(`bar`)},
+	}
+	prev := rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.NewDString(`baz`)},
+	}
+	ts := hlc.Timestamp{WallTime: 3000000, Logical: 2}
+	cluster := clusterInfo{clusterID: `cid`, clusterName: `cname`}
+
+	targets := changefeedbase.Targets{}
+	targets.Add(changefeedbase.Target{
+		Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+		TableID:           tableDesc.GetID(),
+		StatementTimeName: changefeedbase.StatementTimeName(tableDesc.GetName()),
+		DatabaseName:      `d`,
+		SchemaName:        `public`,
+	})
+
+	type event struct {
+		name            string
+		updated, before rowenc.EncDatumRow
+		deleted         bool
+		backfill        bool
+	}
+	events := []event{
+		{name: `insert`, updated: row},
+		{name: `update`, updated: row, before: prev},
+		{name: `delete`, updated: row, before: row, deleted: true},
+		{name: `backfill`, updated: row, backfill: true},
+	}
+
+	jsonSource := func(snapshot bool) string {
+		return fmt.Sprintf(`{"cluster_id": "cid", "connector": "cockroachdb", "db": "d", `+
+			`"mvcc_timestamp": "3000000.0000000002", "name": "cname", "schema": "public", `+
+			`"snapshot": "%t", "table": "foo", "ts_ms": 3, "version": "v23.2.0"}`, snapshot)
+	}
+	avroSource := func(snapshot bool) string {
+		return fmt.Sprintf(`{"foo_source":{"cluster_id":{"string":"cid"},`+
+			`"connector":{"string":"cockroachdb"},"db":{"string":"d"},`+
+			`"mvcc_timestamp":{"string":"3000000.0000000002"},"name":{"string":"cname"},`+
+			`"schema":{"string":"public"},"snapshot":{"string":"%t"},"table":{"string":"foo"},`+
+			`"ts_ms":{"long":3},"version":{"string":"v23.2.0"}}}`, snapshot)
+	}
+	expecteds := map[changefeedbase.FormatType]map[string]string{
+		changefeedbase.OptFormatJSON: {
+			`insert`: `[1]->{"after": {"a": 1, "b": "bar"}, "before": null, "op": "c", ` +
+				`"source": ` + jsonSource(false) + `, "ts_ms": 3}`,
+			`update`: `[1]->{"after": {"a": 1, "b": "bar"}, "before": {"a": 1, "b": "baz"}, "op": "u", ` +
+				`"source": ` + jsonSource(false) + `, "ts_ms": 3}`,
+			`delete`: `[1]->{"after": null, "before": {"a": 1, "b": "bar"}, "op": "d", ` +
+				`"source": ` + jsonSource(false) + `, "ts_ms": 3}`,
+			`backfill`: `[1]->{"after": {"a": 1, "b": "bar"}, "before": null, "op": "r", ` +
+				`"source": ` + jsonSource(true) + `, "ts_ms": 3}`,
+			`resolved`: `{"resolved":"3000000.0000000002"}`,
+		},
+		changefeedbase.OptFormatAvro: {
+			`insert`: `{"a":{"long":1}}->{"after":{"foo":{"a":{"long":1},"b":{"string":"bar"}}},` +
+				`"before":null,"op":{"string":"c"},"source":` + avroSource(false) + `,"ts_ms":{"long":3}}`,
+			`update`: `{"a":{"long":1}}->{"after":{"foo":{"a":{"long":1},"b":{"string":"bar"}}},` +
+				`"before":{"foo_before":{"a":{"long":1},"b":{"string":"baz"}}},` +
+				`"op":{"string":"u"},"source":` + avroSource(false) + `,"ts_ms":{"long":3}}`,
+			`delete`: `{"a":{"long":1}}->{"after":null,` +
+				`"before":{"foo_before":{"a":{"long":1},"b":{"string":"bar"}}},` +
+				`"op":{"string":"d"},"source":` + avroSource(false) + `,"ts_ms":{"long":3}}`,
+			`backfill`: `{"a":{"long":1}}->{"after":{"foo":{"a":{"long":1},"b":{"string":"bar"}}},` +
+				`"before":null,"op":{"string":"r"},"source":` + avroSource(true) + `,"ts_ms":{"long":3}}`,
+			`resolved`: `{"resolved":{"string":"3000000.0000000002"}}`,
+		},
+	}
+
+	for _, f := range []changefeedbase.FormatType{changefeedbase.OptFormatJSON, changefeedbase.OptFormatAvro} {
+		t.Run(string(f), func(t *testing.T) {
+			opts, err := changefeedbase.MakeStatementOptions(map[string]string{
+				changefeedbase.OptFormat:   string(f),
+				changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeDebezium),
+			}).GetEncodingOptions()
+			require.NoError(t, err)
+			require.True(t, opts.Diff)
+
+			rowStringFn := func(k, v []byte) string { return fmt.Sprintf(`%s->%s`, k, v) }
+			resolvedStringFn := func(r []byte) string { return string(r) }
+			if f == changefeedbase.OptFormatAvro {
+				reg := cdctest.StartTestSchemaRegistry()
+				defer reg.Close()
+				opts.SchemaRegistryURI = reg.URL()
+				rowStringFn = func(k, v []byte) string {
+					return fmt.Sprintf(`%s->%s`, avroToJSON(t, reg, k), avroToJSON(t, reg, v))
+				}
+				resolvedStringFn = func(r []byte) string { return string(avroToJSON(t, reg, r)) }
+				defer func() {
+					assertRegisteredSubjects(t, reg, []string{`foo-key`, `foo-value`})
+				}()
+			}
+
+			e, err := getEncoder(opts, targets, false, nil, nil, cluster)
+			require.NoError(t, err)
+
+			for _, ev := range events {
+				updatedRow := cdcevent.TestingMakeEventRow(tableDesc, 0, ev.updated, ev.deleted)
+				prevRow := cdcevent.TestingMakeEventRow(tableDesc, 0, ev.before, false)
+				evCtx := eventContext{updated: ts, mvcc: ts, backfill: ev.backfill}
+
+				key, err := e.EncodeKey(context.Background(), updatedRow)
+				require.NoError(t, err)
+				key = append([]byte(nil), key...)
+				value, err := e.EncodeValue(context.Background(), evCtx, updatedRow, prevRow)
+				require.NoError(t, err)
+				require.Equal(t, expecteds[f][ev.name], rowStringFn(key, value), ev.name)
+			}
+
+			resolved, err := e.EncodeResolvedTimestamp(context.Background(), tableDesc.GetName(), ts)
+			require.NoError(t, err)
+			require.Equal(t, expecteds[f][`resolved`], resolvedStringFn(resolved))
+		})
+	}
+
+	for _, tc := range []struct {
+		opts map[string]string
+		err  string
+	}{
+		{
+			opts: map[string]string{changefeedbase.OptFormat: `csv`},
+			err:  `envelope=debezium is only usable with format=json or format=avro`,
+		},
+		{
+			opts: map[string]string{changefeedbase.OptUpdatedTimestamps: ``},
+			err:  `updated is not supported with envelope=debezium`,
+		},
+		{
+			opts: map[string]string{changefeedbase.OptKeyInValue: ``},
+			err:  `key_in_value is not supported with envelope=debezium`,
+		},
+	} {
+		tc.opts[changefeedbase.OptEnvelope] = string(changefeedbase.OptEnvelopeDebezium)
+		_, err := changefeedbase.MakeStatementOptions(tc.opts).GetEncodingOptions()
+		require.EqualError(t, err, tc.err)
+	}
+}
+
+func TestProtobufEncoder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c BOOL, d DECIMAL)`)
+	require.NoError(t, err)
+	dec, err := tree.ParseDDecimal(`1.5`)
+	require.NoError(t, err)
+	row := rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.NewDString(`bar`)},
+		rowenc.EncDatum{Datum: tree.DBoolTrue},
+		rowenc.EncDatum{Datum: dec},
+	}
+	prev := rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.NewDString(`baz`)},
+		rowenc.EncDatum{Datum: tree.DNull},
+		rowenc.EncDatum{Datum: tree.DNull},
+	}
+	ts := hlc.Timestamp{WallTime: 3000000, Logical: 2}
+
+	targets := changefeedbase.Targets{}
+	targets.Add(changefeedbase.Target{
+		Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+		TableID:           tableDesc.GetID(),
+		StatementTimeName: changefeedbase.StatementTimeName(tableDesc.GetName()),
+	})
+
+	type event struct {
+		name            string
+		updated, before rowenc.EncDatumRow
+		deleted         bool
+	}
+	events := []event{
+		{name: `insert`, updated: row},
+		{name: `update`, updated: row, before: prev},
+		{name: `delete`, updated: row, before: row, deleted: true},
+	}
+
+	const tsStr = `3000000.0000000002`
+	const after = `{"a":"1","b":"bar","c":true,"d":"1.5"}`
+	tests := []struct {
+		opts      map[string]string
+		expecteds map[string]string
+	}{
+		{
+			opts: map[string]string{
+				changefeedbase.OptDiff:              ``,
+				changefeedbase.OptUpdatedTimestamps: ``,
+				changefeedbase.OptMVCCTimestamps:    ``,
+			},
+			expecteds: map[string]string{
+				`insert`: `{"a":"1"}->{"after":` + after +
+					`,"updated":"` + tsStr + `","mvcc_timestamp":"` + tsStr + `"}`,
+				`update`: `{"a":"1"}->{"after":` + after + `,"before":{"a":"1","b":"baz"}` +
+					`,"updated":"` + tsStr + `","mvcc_timestamp":"` + tsStr + `"}`,
+				`delete`: `{"a":"1"}->{"before":` + after +
+					`,"updated":"` + tsStr + `","mvcc_timestamp":"` + tsStr + `"}`,
+			},
+		},
+		{
+			opts: map[string]string{changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeBare)},
+			expecteds: map[string]string{
+				`insert`: `{"a":"1"}->` + after,
+				`update`: `{"a":"1"}->` + after,
+				`delete`: `{"a":"1"}->`,
+			},
+		},
+		{
+			opts: map[string]string{changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeKeyOnly)},
+			expecteds: map[string]string{
+				`insert`: `{"a":"1"}->`,
+				`update`: `{"a":"1"}->`,
+				`delete`: `{"a":"1"}->`,
+			},
+		},
+	}
+
+	for _, withRegistry := range []bool{false, true} {
+		for _, tc := range tests {
+			tc.opts[changefeedbase.OptFormat] = string(changefeedbase.OptFormatProtobuf)
+			name := fmt.Sprintf("registry=%t/%v", withRegistry, tc.opts)
+			t.Run(name, func(t *testing.T) {
+				opts, err := changefeedbase.MakeStatementOptions(tc.opts).GetEncodingOptions()
+				require.NoError(t, err)
+				var reg *cdctest.SchemaRegistry
+				if withRegistry {
+					reg = cdctest.StartTestSchemaRegistry()
+					defer reg.Close()
+					opts.SchemaRegistryURI = reg.URL()
+				}
+
+				enc, err := getEncoder(opts, targets, false, nil, nil, clusterInfo{})
+				require.NoError(t, err)
+				e := enc.(*protobufEncoder)
+
+				// toJSON decodes an encoded message of the given schema.
+				toJSON := func(schema *protobufSchema, b []byte) string {
+					if len(b) == 0 {
+						return ``
+					}
+					if withRegistry {
+						require.Equal(t, changefeedbase.ConfluentAvroWireFormatMagic, b[0])
+						require.Equal(t, uint32(schema.registryID), binary.BigEndian.Uint32(b[1:5]))
+						require.Equal(t, byte(0), b[5])
+						b = b[6:]
+					}
+					msg := dynamicpb.NewMessage(schema.desc)
+					require.NoError(t, proto.Unmarshal(b, msg))
+					j, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+					require.NoError(t, err)
+					var buf bytes.Buffer
+					require.NoError(t, gojson.Compact(&buf, j))
+					return buf.String()
+				}
+
+				ctx := context.Background()
+				for _, ev := range events {
+					updatedRow := cdcevent.TestingMakeEventRow(tableDesc, 0, ev.updated, ev.deleted)
+					prevRow := cdcevent.TestingMakeEventRow(tableDesc, 0, ev.before, false)
+					evCtx := eventContext{updated: ts, mvcc: ts}
+
+					key, err := e.EncodeKey(ctx, updatedRow)
+					require.NoError(t, err)
+					keySchema, err := e.keySchema(ctx, updatedRow)
+					require.NoError(t, err)
+					keyJSON := toJSON(keySchema, key)
+
+					value, err := e.EncodeValue(ctx, evCtx, updatedRow, prevRow)
+					require.NoError(t, err)
+					var valueJSON string
+					if len(value) > 0 {
+						valueSchema, err := e.valueSchema(ctx, updatedRow, prevRow)
+						require.NoError(t, err)
+						valueJSON = toJSON(valueSchema, value)
+					}
+					require.Equal(t, tc.expecteds[ev.name], keyJSON+`->`+valueJSON, ev.name)
+				}
+
+				resolved, err := e.EncodeResolvedTimestamp(ctx, tableDesc.GetName(), ts)
+				require.NoError(t, err)
+				require.Equal(t, `{"resolved":"`+tsStr+`"}`, toJSON(e.resolvedCache[`foo`], resolved))
+
+				if withRegistry {
+					assertRegisteredSubjects(t, reg, []string{`foo-key`, `foo-value`})
+					require.Equal(t, `PROTOBUF`, reg.SchemaTypeForSubject(`foo-key`))
+					require.Equal(t, `PROTOBUF`, reg.SchemaTypeForSubject(`foo-value`))
+				}
+			})
+		}
+	}
+
+	t.Run("schema", func(t *testing.T) {
+		opts, err := changefeedbase.MakeStatementOptions(map[string]string{
+			changefeedbase.OptFormat:            string(changefeedbase.OptFormatProtobuf),
+			changefeedbase.OptDiff:              ``,
+			changefeedbase.OptUpdatedTimestamps: ``,
+		}).GetEncodingOptions()
+		require.NoError(t, err)
+		reg := cdctest.StartTestSchemaRegistry()
+		defer reg.Close()
+		opts.SchemaRegistryURI = reg.URL()
+		e, err := getEncoder(opts, targets, false, nil, nil, clusterInfo{})
+		require.NoError(t, err)
+
+		ctx := context.Background()
+		updatedRow := cdcevent.TestingMakeEventRow(tableDesc, 0, row, false)
+		prevRow := cdcevent.TestingMakeEventRow(tableDesc, 0, prev, false)
+		_, err = e.EncodeKey(ctx, updatedRow)
+		require.NoError(t, err)
+		_, err = e.EncodeValue(ctx, eventContext{updated: ts, mvcc: ts}, updatedRow, prevRow)
+		require.NoError(t, err)
+
+		require.Equal(t, `syntax = "proto3";
+
+message foo {
+  optional int64 a = 1;
+}
+`, reg.SchemaForSubject(`foo-key`))
+		require.Equal(t, `syntax = "proto3";
+
+message foo_envelope {
+  foo after = 1;
+  foo_before before = 2;
+  string updated = 3;
+}
+
+message foo {
+  optional int64 a = 1;
+  optional string b = 2;
+  optional bool c = 3;
+  optional string d = 4;
+}
+
+message foo_before {
+  optional int64 a = 1;
+  optional string b = 2;
+  optional bool c = 3;
+  optional string d = 4;
+}
+`, reg.SchemaForSubject(`foo-value`))
+	})
+
+	t.Run("conflicting field names", func(t *testing.T) {
+		barDesc, err := parseTableDesc(`CREATE TABLE bar (a INT PRIMARY KEY, "A" INT, a_ INT)`)
+		require.NoError(t, err)
+		barTargets := changefeedbase.Targets{}
+		barTargets.Add(changefeedbase.Target{
+			Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+			TableID:           barDesc.GetID(),
+			StatementTimeName: changefeedbase.StatementTimeName(barDesc.GetName()),
+		})
+		opts, err := changefeedbase.MakeStatementOptions(map[string]string{
+			changefeedbase.OptFormat:   string(changefeedbase.OptFormatProtobuf),
+			changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeBare),
+		}).GetEncodingOptions()
+		require.NoError(t, err)
+		reg := cdctest.StartTestSchemaRegistry()
+		defer reg.Close()
+		opts.SchemaRegistryURI = reg.URL()
+		e, err := getEncoder(opts, barTargets, false, nil, nil, clusterInfo{})
+		require.NoError(t, err)
+
+		barRow := cdcevent.TestingMakeEventRow(barDesc, 0, rowenc.EncDatumRow{
+			rowenc.EncDatum{Datum: tree.NewDInt(1)},
+			rowenc.EncDatum{Datum: tree.NewDInt(2)},
+			rowenc.EncDatum{Datum: tree.NewDInt(3)},
+		}, false)
+		_, err = e.EncodeValue(context.Background(), eventContext{updated: ts, mvcc: ts}, barRow, cdcevent.Row{})
+		require.NoError(t, err)
+		require.Equal(t, `syntax = "proto3";
+
+message bar {
+  optional int64 a = 1;
+  optional int64 A_2 = 2;
+  optional int64 a__3 = 3;
+}
+`, reg.SchemaForSubject(`bar-value`))
+	})
+
+	for _, opt := range []string{changefeedbase.OptKeyInValue, changefeedbase.OptTopicInValue} {
+		opts, err := changefeedbase.MakeStatementOptions(map[string]string{
+			changefeedbase.OptFormat: string(changefeedbase.OptFormatProtobuf),
+			opt:                      ``,
+		}).GetEncodingOptions()
+		require.NoError(t, err)
+		_, err = getEncoder(opts, targets, false, nil, nil, clusterInfo{})
+		require.EqualError(t, err, opt+` is not supported with format=protobuf`)
+	}
+}
+
 func TestAvroEncoder(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -386,7 +793,7 @@ func TestAvroEncoderWithTLS(t *testing.T) {
 				StatementTimeName: changefeedbase.StatementTimeName(tableDesc.GetName()),
 			})
 
-			e, err := getEncoder(opts, targets, false, nil, nil)
+			e, err := getEncoder(opts, targets, false, nil, nil, clusterInfo{})
 			require.NoError(t, err)
 
 			rowInsert := cdcevent.TestingMakeEventRow(tableDesc, 0, row, false)
@@ -418,7 +825,7 @@ func TestAvroEncoderWithTLS(t *testing.T) {
 			defer noCertReg.Close()
 			opts.SchemaRegistryURI = noCertReg.URL()
 
-			enc, err := getEncoder(opts, targets, false, nil, nil)
+			enc, err := getEncoder(opts, targets, false, nil, nil, clusterInfo{})
 			require.NoError(t, err)
 			_, err = enc.EncodeKey(context.Background(), rowInsert)
 			require.Regexp(t, "x509", err)
@@ -431,7 +838,7 @@ func TestAvroEncoderWithTLS(t *testing.T) {
 			defer wrongCertReg.Close()
 			opts.SchemaRegistryURI = wrongCertReg.URL()
 
-			enc, err = getEncoder(opts, targets, false, nil, nil)
+			enc, err = getEncoder(opts, targets, false, nil, nil, clusterInfo{})
 			require.NoError(t, err)
 			_, err = enc.EncodeKey(context.Background(), rowInsert)
 			require.Regexp(t, `contacting confluent schema registry.*: x509`, err)
@@ -921,7 +1328,7 @@ func BenchmarkEncoders(b *testing.B) {
 		b.ReportAllocs()
 		b.StopTimer()
 
-		encoder, err := getEncoder(opts, targets, false, nil, nil)
+		encoder, err := getEncoder(opts, targets, false, nil, nil, clusterInfo{})
 		if err != nil {
 			b.Fatal(err)
 		}
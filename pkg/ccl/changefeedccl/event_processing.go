@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 )
 
@@ -39,6 +40,12 @@ type eventContext struct {
 	updated, mvcc hlc.Timestamp
 	// topic is set to the string to be included if TopicInValue is true
 	topic string
+	// backfill is true if the event was emitted by an initial scan or a
+	// schema change backfill rather than by a change to the row.
+	backfill bool
+	// txnID is the ID of the transaction which wrote the row, or nil if it is
+	// unknown.
+	txnID uuid.UUID
 }
 
 type eventConsumer interface {
@@ -66,6 +73,12 @@ type kvEventToRowConsumer struct {
 
 	metrics *sliMetrics
 
+	// dlq, if set, records rows which cannot be encoded.
+	dlq deadLetterQueue
+
+	// txnRows, if set, counts the emitted rows of each transaction.
+	txnRows *transactionRowCounter
+
 	// This pacer is used to incorporate event consumption to elastic CPU
 	// control. This helps ensure that event encoding/decoding does not throttle
 	// foreground SQL traffic.
@@ -86,6 +99,8 @@ func newEventConsumer(
 	spanFrontier frontier,
 	cursor hlc.Timestamp,
 	sink EventSink,
+	dlq deadLetterQueue,
+	txnRows *transactionRowCounter,
 	metrics *Metrics,
 	sliMetrics *sliMetrics,
 	knobs TestingKnobs,
@@ -101,7 +116,8 @@ func newEventConsumer(
 	makeConsumer := func(s EventSink, frontier frontier) (eventConsumer, error) {
 		var err error
 		encoder, err := getEncoder(encodingOpts, feed.Targets, spec.Select.Expr != "",
-			makeExternalConnectionProvider(ctx, cfg.DB), sliMetrics)
+			makeExternalConnectionProvider(ctx, cfg.DB), sliMetrics,
+			makeClusterInfo(cfg.ExecutorConfig.(*sql.ExecutorConfig)))
 		if err != nil {
 			return nil, err
 		}
@@ -135,7 +151,7 @@ func newEventConsumer(
 		}
 
 		execCfg := cfg.ExecutorConfig.(*sql.ExecutorConfig)
-		return newKVEventToRowConsumer(ctx, execCfg, frontier, cursor, s,
+		return newKVEventToRowConsumer(ctx, execCfg, frontier, cursor, s, dlq, txnRows,
 			encoder, feed, spec, knobs, topicNamer, sliMetrics, pacer)
 	}
 
@@ -210,6 +226,8 @@ func newKVEventToRowConsumer(
 	frontier frontier,
 	cursor hlc.Timestamp,
 	sink EventSink,
+	dlq deadLetterQueue,
+	txnRows *transactionRowCounter,
 	encoder Encoder,
 	details ChangefeedConfig,
 	spec execinfrapb.ChangeAggregatorSpec,
@@ -243,6 +261,8 @@ func newKVEventToRowConsumer(
 		encoder:              encoder,
 		decoder:              decoder,
 		sink:                 sink,
+		dlq:                  dlq,
+		txnRows:              txnRows,
 		cursor:               cursor,
 		details:              details,
 		knobs:                knobs,
@@ -372,7 +392,10 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 		}
 	}
 
-	return c.encodeAndEmit(ctx, updatedRow, prevRow, schemaTimestamp, ev.DetachAlloc())
+	return c.encodeAndEmit(
+		ctx, updatedRow, prevRow, schemaTimestamp, !ev.BackfillTimestamp().IsEmpty(), ev.TxnID(),
+		ev.DetachAlloc(),
+	)
 }
 
 func (c *kvEventToRowConsumer) encodeAndEmit(
@@ -380,6 +403,8 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 	updatedRow cdcevent.Row,
 	prevRow cdcevent.Row,
 	schemaTS hlc.Timestamp,
+	backfill bool,
+	txnID uuid.UUID,
 	alloc kvevent.Alloc,
 ) error {
 	topic, err := c.topicForEvent(updatedRow.Metadata)
@@ -400,8 +425,10 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 	}
 
 	evCtx := eventContext{
-		updated: schemaTS,
-		mvcc:    updatedRow.MvccTimestamp,
+		updated:  schemaTS,
+		mvcc:     updatedRow.MvccTimestamp,
+		backfill: backfill,
+		txnID:    txnID,
 	}
 
 	if c.topicNamer != nil {
@@ -427,14 +454,14 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 	var keyCopy, valueCopy []byte
 	encodedKey, err := c.encoder.EncodeKey(ctx, updatedRow)
 	if err != nil {
-		return err
+		return c.maybeDeadLetter(ctx, topic, nil /* key */, updatedRow, err, alloc)
 	}
 	c.scratch, keyCopy = c.scratch.Copy(encodedKey, 0 /* extraCap */)
 	// TODO(yevgeniy): Some refactoring is needed in the encoder: namely, prevRow
 	// might not be available at all when working with changefeed expressions.
 	encodedValue, err := c.encoder.EncodeValue(ctx, evCtx, updatedRow, prevRow)
 	if err != nil {
-		return err
+		return c.maybeDeadLetter(ctx, topic, keyCopy, updatedRow, err, alloc)
 	}
 	c.scratch, valueCopy = c.scratch.Copy(encodedValue, 0 /* extraCap */)
 
@@ -450,6 +477,48 @@ func (c *kvEventToRowConsumer) encodeAndEmit(
 	if log.V(3) {
 		log.Infof(ctx, `r %s: %s -> %s`, updatedRow.TableName, keyCopy, valueCopy)
 	}
+	if c.txnRows != nil && !backfill {
+		c.txnRows.add(updatedRow.MvccTimestamp, txnID, topic.GetTableName())
+	}
+
+	// Debezium consumers expect each delete to be followed by a tombstone, a
+	// message with the same key and a null value, so that log compaction can
+	// eventually remove every message for the deleted key.
+	if c.encodingOpts.Envelope == changefeedbase.OptEnvelopeDebezium && updatedRow.IsDeleted() {
+		if err := c.sink.EmitRow(
+			ctx, topic, keyCopy, nil /* value */, schemaTS, updatedRow.MvccTimestamp, kvevent.Alloc{},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maybeDeadLetter hands a row which could not be encoded to the dead letter
+// queue, if one is configured, the error is specific to the row and the dead
+// letter queue has room for it. Otherwise, the error is returned.
+func (c *kvEventToRowConsumer) maybeDeadLetter(
+	ctx context.Context,
+	topic TopicDescriptor,
+	key []byte,
+	row cdcevent.Row,
+	cause error,
+	alloc kvevent.Alloc,
+) error {
+	if c.dlq == nil || !isDeadLetterError(ctx, cause) {
+		return cause
+	}
+	log.VInfof(ctx, 2, "sending row %s to dead letter queue: %v", row.Metadata.DebugString(), cause)
+	if err := c.dlq.Add(ctx, deadLetterRecord{
+		topic: topic,
+		key:   key,
+		row:   deadLetterRowJSON(row),
+		mvcc:  row.MvccTimestamp,
+		err:   cause,
+	}); err != nil {
+		return errors.WithSecondaryError(cause, err)
+	}
+	alloc.Release(ctx)
 	return nil
 }
 
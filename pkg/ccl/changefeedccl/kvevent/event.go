@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 )
 
@@ -163,6 +164,13 @@ func (e *Event) PrevKeyValue() roachpb.KeyValue {
 	return roachpb.KeyValue{Key: v.Key, Value: v.PrevValue}
 }
 
+// TxnID returns the ID of the transaction which wrote the value of this KV
+// event. It is nil if the rangefeed value did not carry it, e.g. because it
+// was emitted by a catch-up scan or a backfill.
+func (e *Event) TxnID() uuid.UUID {
+	return e.ev.Val.TxnID
+}
+
 func (e *Event) boundaryType() jobspb.ResolvedSpan_BoundaryType {
 	switch e.et {
 	case resolvedNone:
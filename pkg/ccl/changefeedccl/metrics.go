@@ -72,6 +72,8 @@ type AggMetrics struct {
 	InternalRetryMessageCount   *aggmetric.AggGauge
 	SchemaRegistrations         *aggmetric.AggCounter
 	SchemaRegistryRetries       *aggmetric.AggCounter
+	DeadLetterRows              *aggmetric.AggCounter
+	DeadLetterErrors            *aggmetric.AggCounter
 	AggregatorProgress          *aggmetric.AggGauge
 	CheckpointProgress          *aggmetric.AggGauge
 	LaggingRanges               *aggmetric.AggGauge
@@ -141,6 +143,8 @@ type sliMetrics struct {
 	InternalRetryMessageCount   *aggmetric.Gauge
 	SchemaRegistrations         *aggmetric.Counter
 	SchemaRegistryRetries       *aggmetric.Counter
+	DeadLetterRows              *aggmetric.Counter
+	DeadLetterErrors            *aggmetric.Counter
 	AggregatorProgress          *aggmetric.Gauge
 	CheckpointProgress          *aggmetric.Gauge
 	LaggingRanges               *aggmetric.Gauge
@@ -665,6 +669,18 @@ func newAggregateMetrics(histogramWindow time.Duration) *AggMetrics {
 		Measurement: "Registrations",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaDeadLetterRows := metric.Metadata{
+		Name:        "changefeed.dlq.rows",
+		Help:        "Rows written to a dead letter queue because they could not be encoded or emitted",
+		Measurement: "Rows",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaDeadLetterErrors := metric.Metadata{
+		Name:        "changefeed.dlq.errors",
+		Help:        "Number of failed attempts to write rows to a dead letter queue",
+		Measurement: "Errors",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaChangefeedParallelIOQueueNanos := metric.Metadata{
 		Name: "changefeed.parallel_io_queue_nanos",
 		Help: "Time that outgoing requests to the sink spend waiting in a queue due to" +
@@ -809,6 +825,8 @@ func newAggregateMetrics(histogramWindow time.Duration) *AggMetrics {
 		InternalRetryMessageCount: b.Gauge(metaInternalRetryMessageCount),
 		SchemaRegistryRetries:     b.Counter(metaSchemaRegistryRetriesCount),
 		SchemaRegistrations:       b.Counter(metaSchemaRegistryRegistrations),
+		DeadLetterRows:            b.Counter(metaDeadLetterRows),
+		DeadLetterErrors:          b.Counter(metaDeadLetterErrors),
 		AggregatorProgress:        b.FunctionalGauge(metaAggregatorProgress, functionalGaugeMinFn),
 		CheckpointProgress:        b.FunctionalGauge(metaCheckpointProgress, functionalGaugeMinFn),
 		LaggingRanges:             b.Gauge(metaLaggingRangePercentage),
@@ -876,6 +894,8 @@ func (a *AggMetrics) getOrCreateScope(scope string) (*sliMetrics, error) {
 		InternalRetryMessageCount:   a.InternalRetryMessageCount.AddChild(scope),
 		SchemaRegistryRetries:       a.SchemaRegistryRetries.AddChild(scope),
 		SchemaRegistrations:         a.SchemaRegistrations.AddChild(scope),
+		DeadLetterRows:              a.DeadLetterRows.AddChild(scope),
+		DeadLetterErrors:            a.DeadLetterErrors.AddChild(scope),
 		LaggingRanges:               a.LaggingRanges.AddChild(scope),
 		CloudstorageBufferedBytes:   a.CloudstorageBufferedBytes.AddChild(scope),
 	}
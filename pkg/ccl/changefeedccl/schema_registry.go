@@ -30,21 +30,46 @@ import (
 
 const confluentSchemaContentType = `application/vnd.schemaregistry.v1+json`
 
+// confluentSchemaType is the type of a schema registered with the schema
+// registry.
+type confluentSchemaType string
+
+const (
+	// confluentSchemaTypeAvro is the type of Avro schemas. It is the registry's
+	// default, so it is left out of registration requests for compatibility with
+	// registries that predate support for other schema types.
+	confluentSchemaTypeAvro confluentSchemaType = ``
+	// confluentSchemaTypeProtobuf is the type of protocol buffer schemas, which
+	// are registered as the text of a .proto file.
+	confluentSchemaTypeProtobuf confluentSchemaType = `PROTOBUF`
+)
+
 type schemaRegistry interface {
 	// Ping tests the connectivity to the schema registry. A nil
 	// error is returned if the schema registry appears to be
 	// available.
 	Ping(ctx context.Context) error
 
-	// RegisterSchemaForSubject registers the given schema for the
-	// given subject. The returned int32 is a schema ID that can
-	// be used in Avro wire messages or in other calls to the
-	// schema registry.
-	RegisterSchemaForSubject(ctx context.Context, subject string, schema string) (int32, error)
+	// RegisterSchemaForSubject registers the given schema of the
+	// given type for the given subject. The returned int32 is a
+	// schema ID that can be used in Avro or protobuf wire messages
+	// or in other calls to the schema registry.
+	RegisterSchemaForSubject(
+		ctx context.Context, subject string, schema string, schemaType confluentSchemaType,
+	) (int32, error)
 }
 
 type confluentSchemaVersionRequest struct {
-	Schema string `json:"schema"`
+	Schema     string              `json:"schema"`
+	SchemaType confluentSchemaType `json:"schemaType,omitempty"`
+}
+
+// String returns the name of the schema type.
+func (t confluentSchemaType) String() string {
+	if t == confluentSchemaTypeAvro {
+		return `AVRO`
+	}
+	return string(t)
 }
 
 type confluentSchemaVersionResponse struct {
@@ -223,18 +248,18 @@ func (r *confluentSchemaRegistry) Ping(ctx context.Context) error {
 }
 
 // RegisterSchemaForSubject registers the given schema for the given
-// subject. The schema type is assumed to be AVRO.
+// subject. An empty schema type means AVRO.
 //
 //	https://docs.confluent.io/platform/current/schema-registry/develop/api.html#post--subjects-(string-%20subject)-versions
 func (r *confluentSchemaRegistry) RegisterSchemaForSubject(
-	ctx context.Context, subject string, schema string,
+	ctx context.Context, subject string, schema string, schemaType confluentSchemaType,
 ) (int32, error) {
 	u := r.urlForPath(fmt.Sprintf("subjects/%s/versions", subject))
 	if log.V(1) {
-		log.Infof(ctx, "registering avro schema %s %s", u, schema)
+		log.Infof(ctx, "registering %s schema %s %s", schemaType.String(), u, schema)
 	}
 
-	req := confluentSchemaVersionRequest{Schema: schema}
+	req := confluentSchemaVersionRequest{Schema: schema, SchemaType: schemaType}
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(req); err != nil {
 		return 0, err
@@ -316,8 +341,9 @@ func (r *confluentSchemaRegistry) urlForPath(relPath string) string {
 }
 
 type schemaRegistryCacheKey struct {
-	subject string
-	schema  string
+	subject    string
+	schema     string
+	schemaType confluentSchemaType
 }
 
 type schemaRegistryCache struct {
@@ -353,10 +379,10 @@ func (csr *schemaRegistryWithCache) Ping(ctx context.Context) error {
 
 // RegisterSchemaForSubject implements the schemaRegistry interface.
 func (csr *schemaRegistryWithCache) RegisterSchemaForSubject(
-	ctx context.Context, subject string, schema string,
+	ctx context.Context, subject string, schema string, schemaType confluentSchemaType,
 ) (int32, error) {
 	cacheKey := schemaRegistryCacheKey{
-		subject: subject, schema: schema,
+		subject: subject, schema: schema, schemaType: schemaType,
 	}
 	csr.cache.mu.Lock()
 	defer csr.cache.mu.Unlock()
@@ -364,7 +390,7 @@ func (csr *schemaRegistryWithCache) RegisterSchemaForSubject(
 	if ok {
 		return id, nil
 	}
-	id, err := csr.base.RegisterSchemaForSubject(ctx, subject, schema)
+	id, err := csr.base.RegisterSchemaForSubject(ctx, subject, schema, schemaType)
 	if err == nil {
 		csr.cache.Add(cacheKey, id)
 	}
@@ -113,7 +113,7 @@ func TestConfluentSchemaRegistrySharedCache(t *testing.T) {
 		go func() {
 			r, err := newConfluentSchemaRegistry(regServer.URL(), nil, nil)
 			require.NoError(t, err)
-			_, err = r.RegisterSchemaForSubject(context.Background(), "subject1", "schema")
+			_, err = r.RegisterSchemaForSubject(context.Background(), "subject1", "schema", confluentSchemaTypeAvro)
 			require.NoError(t, err)
 			wg.Done()
 
@@ -128,7 +128,7 @@ func TestConfluentSchemaRegistrySharedCache(t *testing.T) {
 		go func(i int) {
 			r, err := newConfluentSchemaRegistry(regServer.URL(), nil, nil)
 			require.NoError(t, err)
-			_, err = r.RegisterSchemaForSubject(context.Background(), "subject1", fmt.Sprintf("schema1%d", i))
+			_, err = r.RegisterSchemaForSubject(context.Background(), "subject1", fmt.Sprintf("schema1%d", i), confluentSchemaTypeAvro)
 			require.NoError(t, err)
 			wg.Done()
 
@@ -137,6 +137,15 @@ func TestConfluentSchemaRegistrySharedCache(t *testing.T) {
 	wg.Wait()
 	require.Equal(t, 11, regServer.RegistrationCount())
 
+	// Registrations of the same schema text with a different type don't share a
+	// cache, and the type is passed along to the registry.
+	r, err := newConfluentSchemaRegistry(regServer.URL(), nil, nil)
+	require.NoError(t, err)
+	_, err = r.RegisterSchemaForSubject(context.Background(), "subject2", "schema", confluentSchemaTypeProtobuf)
+	require.NoError(t, err)
+	require.Equal(t, 12, regServer.RegistrationCount())
+	require.Equal(t, "PROTOBUF", regServer.SchemaTypeForSubject("subject2"))
+	require.Equal(t, "", regServer.SchemaTypeForSubject("subject1"))
 }
 
 func TestConfluentSchemaRegistryPing(t *testing.T) {
@@ -180,7 +189,7 @@ func TestConfluentSchemaRegistryRetryMetrics(t *testing.T) {
 		require.NoError(t, err)
 		ctx, cancel := context.WithCancel(context.Background())
 		go func() {
-			_, err = reg.RegisterSchemaForSubject(ctx, "subject1", "schema1")
+			_, err = reg.RegisterSchemaForSubject(ctx, "subject1", "schema1", confluentSchemaTypeAvro)
 		}()
 		require.NoError(t, err)
 		testutils.SucceedsSoon(t, func() error {
@@ -116,6 +116,42 @@ type SinkWithTopics interface {
 	Topics() []string
 }
 
+// transactionalSink is implemented by sinks which can write messages in
+// transactions, such that consumers only see messages once the transaction
+// they were emitted in commits.
+type transactionalSink interface {
+	// setTransactionalID makes the sink emit messages in transactions under
+	// the given ID. It must be called before the sink is dialed.
+	setTransactionalID(id string)
+	// pendingTransaction returns true if messages were emitted since the last
+	// transaction was committed.
+	pendingTransaction() bool
+	// commitTransaction flushes the sink and commits every message emitted
+	// since it was last called, along with the given commit, which becomes
+	// visible atomically with them. Closing the sink aborts the messages
+	// emitted since then.
+	commitTransaction(ctx context.Context, c sinkTransactionCommit) error
+	// lastCommittedTransaction returns the commit of the last transaction
+	// committed under the sink's transactional ID, or the zero commit if none
+	// was. Transactions which were not committed before the sink was dialed
+	// have been aborted by then.
+	lastCommittedTransaction(ctx context.Context) (sinkTransactionCommit, error)
+}
+
+// sinkTransactionCommit identifies a transaction committed by a
+// transactionalSink.
+type sinkTransactionCommit struct {
+	// seq is the sequence number of the transaction, which is one more than
+	// that of the previous transaction under the same transactional ID.
+	seq int64
+	// token identifies the producer which committed the transaction, since a
+	// producer which was fenced off may still try to commit a transaction with
+	// the same sequence number.
+	token string
+}
+
+// getEventSink returns a dialed sink for the rows of a changefeed. If
+// transactionalID is set, the sink emits them in transactions under that ID.
 func getEventSink(
 	ctx context.Context,
 	serverCfg *execinfra.ServerConfig,
@@ -124,8 +160,53 @@ func getEventSink(
 	user username.SQLUsername,
 	jobID jobspb.JobID,
 	m metricsRecorder,
+	transactionalID string,
 ) (EventSink, error) {
-	return getAndDialSink(ctx, serverCfg, feedCfg, timestampOracle, user, jobID, m)
+	sink, err := getSink(ctx, serverCfg, feedCfg, timestampOracle, user, jobID, m)
+	if err != nil {
+		return nil, err
+	}
+	if transactionalID != `` {
+		s, ok := sink.(transactionalSink)
+		if !ok {
+			return nil, errors.AssertionFailedf(
+				`%s is not supported by %T`, changefeedbase.OptExactlyOnce, sink)
+		}
+		s.setTransactionalID(transactionalID)
+	}
+	return sink, sink.Dial()
+}
+
+// abortSinkTransactions fences off the producers which emitted the rows of
+// the changefeed under the given transactional IDs. Their uncommitted
+// transactions are aborted, and they can no longer commit any transaction,
+// even if their aggregators are still running. It returns the commit of the
+// last transaction under each ID, which can then no longer change.
+func abortSinkTransactions(
+	ctx context.Context,
+	serverCfg *execinfra.ServerConfig,
+	feedCfg jobspb.ChangefeedDetails,
+	user username.SQLUsername,
+	jobID jobspb.JobID,
+	transactionalIDs []string,
+) ([]sinkTransactionCommit, error) {
+	var nilOracle timestampLowerBoundOracle
+	commits := make([]sinkTransactionCommit, 0, len(transactionalIDs))
+	for _, id := range transactionalIDs {
+		// Dialing a sink with a transactional ID fences off the previous
+		// producers with that ID.
+		sink, err := getEventSink(ctx, serverCfg, feedCfg, nilOracle, user, jobID,
+			(*sliMetrics)(nil), id)
+		if err != nil {
+			return nil, err
+		}
+		c, err := sink.(transactionalSink).lastCommittedTransaction(ctx)
+		if err := errors.CombineErrors(err, sink.Close()); err != nil {
+			return nil, err
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
 }
 
 func getResolvedTimestampSink(
@@ -234,7 +315,16 @@ func getSink(
 			return makeNullSink(sinkURL{URL: u}, metricsBuilder(nullIsAccounted))
 		case isKafkaSink(u):
 			return validateOptionsAndMakeSink(changefeedbase.KafkaValidOptions, func() (Sink, error) {
-				return makeKafkaSink(ctx, sinkURL{URL: u}, AllTargets(feedCfg), opts.GetKafkaConfigJSON(), serverCfg.Settings, metricsBuilder)
+				checkpointFrequency := changefeedbase.DefaultMinCheckpointFrequency
+				freq, err := opts.GetMinCheckpointFrequency()
+				if err != nil {
+					return nil, err
+				}
+				if freq != nil {
+					checkpointFrequency = *freq
+				}
+				return makeKafkaSink(ctx, sinkURL{URL: u}, AllTargets(feedCfg), opts.GetKafkaConfigJSON(),
+					opts.ExactlyOnce(), checkpointFrequency, serverCfg.Settings, metricsBuilder)
 			})
 		case isWebhookSink(u):
 			webhookOpts, err := opts.GetWebhookSinkOptions()
@@ -26,14 +26,18 @@ import (
 	"github.com/Shopify/sarama"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/util/bufalloc"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/logtags"
 	"golang.org/x/oauth2"
@@ -74,6 +78,7 @@ type kafkaSinkKnobs struct {
 	OverrideClientInit              func(config *sarama.Config) (kafkaClient, error)
 	OverrideAsyncProducerFromClient func(kafkaClient) (sarama.AsyncProducer, error)
 	OverrideSyncProducerFromClient  func(kafkaClient) (sarama.SyncProducer, error)
+	OverrideFetchCommittedOffsets   func(client kafkaClient, group string) (*sarama.OffsetFetchResponse, error)
 }
 
 var _ sarama.StdLogger = (*kafkaLogAdapter)(nil)
@@ -146,12 +151,137 @@ type kafkaSink struct {
 	}
 
 	disableInternalRetry bool
+
+	// dlq, if set, records messages which the broker rejected for reasons
+	// specific to the message instead of failing the flush.
+	dlq deadLetterQueue
+
+	// inTxn is set while a transactional producer has a transaction open, and
+	// txnTopic is then the topic of its first message. Only accessed by the
+	// client goroutine.
+	inTxn    bool
+	txnTopic string
 }
 
+var _ sinkWithDeadLetterQueue = (*kafkaSink)(nil)
+var _ transactionalSink = (*kafkaSink)(nil)
+
 func (s *kafkaSink) getConcreteType() sinkType {
 	return sinkTypeKafka
 }
 
+// setDeadLetterQueue implements the sinkWithDeadLetterQueue interface.
+func (s *kafkaSink) setDeadLetterQueue(dlq deadLetterQueue) {
+	s.dlq = dlq
+}
+
+// setTransactionalID implements the transactionalSink interface. When the
+// sink is dialed, kafka fences off any other producer with the same
+// transactional ID and aborts its open transaction.
+func (s *kafkaSink) setTransactionalID(id string) {
+	s.kafkaCfg.Producer.Transaction.ID = id
+}
+
+// pendingTransaction implements the transactionalSink interface.
+func (s *kafkaSink) pendingTransaction() bool {
+	return s.inTxn
+}
+
+// commitTransaction implements the transactionalSink interface. The commit is
+// recorded as the offset of partition 0 of the transaction's first topic in
+// the consumer group named after the transactional ID, which kafka commits
+// atomically with the transaction's messages. The token is the offset's
+// metadata.
+func (s *kafkaSink) commitTransaction(ctx context.Context, c sinkTransactionCommit) error {
+	if !s.inTxn {
+		return nil
+	}
+	// Surface the errors of messages which were not acknowledged before
+	// committing, since a committed transaction may not be missing any of them.
+	if err := s.Flush(ctx); err != nil {
+		return err
+	}
+	token := c.token
+	if err := s.producer.AddOffsetsToTxn(map[string][]*sarama.PartitionOffsetMetadata{
+		s.txnTopic: {{Partition: 0, Offset: c.seq, Metadata: &token}},
+	}, s.kafkaCfg.Producer.Transaction.ID); err != nil {
+		return errors.Wrap(err, `adding commit to kafka transaction`)
+	}
+	if err := s.producer.CommitTxn(); err != nil {
+		return errors.Wrap(err, `committing kafka transaction`)
+	}
+	s.inTxn = false
+	return nil
+}
+
+// lastCommittedTransaction implements the transactionalSink interface. The
+// commit of the last transaction has the highest offset in the consumer group
+// named after the transactional ID, since each transaction commits a higher
+// offset than the previous one, though possibly for another topic.
+func (s *kafkaSink) lastCommittedTransaction(ctx context.Context) (sinkTransactionCommit, error) {
+	group := s.kafkaCfg.Producer.Transaction.ID
+	var resp *sarama.OffsetFetchResponse
+	var err error
+	if s.knobs.OverrideFetchCommittedOffsets != nil {
+		resp, err = s.knobs.OverrideFetchCommittedOffsets(s.client, group)
+	} else {
+		resp, err = fetchCommittedOffsets(s.client.(sarama.Client), group)
+	}
+	if err != nil {
+		return sinkTransactionCommit{}, errors.Wrapf(err,
+			`fetching the last committed kafka transaction of %s`, group)
+	}
+	if resp.Err != sarama.ErrNoError {
+		return sinkTransactionCommit{}, errors.Wrapf(resp.Err,
+			`fetching the last committed kafka transaction of %s`, group)
+	}
+	var last sinkTransactionCommit
+	for _, partitions := range resp.Blocks {
+		for _, block := range partitions {
+			if block.Err != sarama.ErrNoError {
+				return sinkTransactionCommit{}, errors.Wrapf(block.Err,
+					`fetching the last committed kafka transaction of %s`, group)
+			}
+			// Partitions without a committed offset have an offset of -1.
+			if block.Offset > last.seq {
+				last = sinkTransactionCommit{seq: block.Offset, token: block.Metadata}
+			}
+		}
+	}
+	return last, nil
+}
+
+// fetchCommittedOffsets fetches the offsets of every partition committed in
+// the given consumer group.
+func fetchCommittedOffsets(client sarama.Client, group string) (*sarama.OffsetFetchResponse, error) {
+	coordinator, err := client.Coordinator(group)
+	if err != nil {
+		return nil, err
+	}
+	// From version 2 on, a request without partitions fetches all of them.
+	return coordinator.FetchOffset(&sarama.OffsetFetchRequest{Version: 2, ConsumerGroup: group})
+}
+
+// abortTransaction aborts the open transaction. Aborting waits for every
+// message of the transaction to be acknowledged, so the acknowledgements,
+// which nothing else reads once the worker is stopped, are discarded
+// meanwhile.
+func (s *kafkaSink) abortTransaction() error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.producer.AbortTxn()
+	}()
+	for {
+		select {
+		case err := <-errCh:
+			s.inTxn = false
+			return err
+		case <-s.producer.Successes():
+		case <-s.producer.Errors():
+		}
+	}
+}
+
 type compressionCodec sarama.CompressionCodec
 
 var saramaCompressionCodecOptions = map[string]sarama.CompressionCodec{
@@ -198,6 +328,12 @@ type saramaConfig struct {
 	RequiredAcks string `json:",omitempty"`
 
 	Version string `json:",omitempty"`
+
+	// Transaction describes settings specific to transactional producers.
+	// See sarama.Config.Producer.Transaction
+	Transaction struct {
+		Timeout jsonDuration `json:",omitempty"`
+	}
 }
 
 func (c saramaConfig) Validate() error {
@@ -340,6 +476,15 @@ func (s *kafkaSink) Close() error {
 		s.worker.Wait()
 	}
 
+	// Abort the open transaction so that consumers do not have to wait for the
+	// next producer with our transactional ID, or for the transaction to time
+	// out, to read past it.
+	if s.inTxn {
+		if err := s.abortTransaction(); err != nil {
+			log.Warningf(s.ctx, "aborting kafka transaction: %v", err)
+		}
+	}
+
 	if s.producer != nil {
 		// Ignore errors related to outstanding messages since we're either shutting
 		// down or beginning to retry regardless
@@ -356,6 +501,7 @@ type messageMetadata struct {
 	alloc         kvevent.Alloc
 	updateMetrics recordOneMessageCallback
 	mvcc          hlc.Timestamp
+	topic         TopicDescriptor
 }
 
 // EmitRow implements the Sink interface.
@@ -371,11 +517,14 @@ func (s *kafkaSink) EmitRow(
 		return err
 	}
 
+	md := messageMetadata{
+		alloc: alloc, mvcc: mvcc, topic: topicDescr, updateMetrics: s.metrics.recordOneMessage(),
+	}
 	msg := &sarama.ProducerMessage{
 		Topic:    topic,
 		Key:      sarama.ByteEncoder(key),
 		Value:    sarama.ByteEncoder(value),
-		Metadata: messageMetadata{alloc: alloc, mvcc: mvcc, updateMetrics: s.metrics.recordOneMessage()},
+		Metadata: md,
 	}
 	s.stats.startMessage(int64(msg.Key.Length() + msg.Value.Length()))
 	return s.emitMessage(ctx, msg)
@@ -471,6 +620,12 @@ func (s *kafkaSink) Flush(ctx context.Context) error {
 	}
 }
 
+func (s *kafkaSink) inflight() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mu.inflight
+}
+
 func (s *kafkaSink) startInflightMessage(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -487,6 +642,12 @@ func (s *kafkaSink) startInflightMessage(ctx context.Context) error {
 }
 
 func (s *kafkaSink) emitMessage(ctx context.Context, msg *sarama.ProducerMessage) error {
+	if s.producer.IsTransactional() && !s.inTxn {
+		if err := s.producer.BeginTxn(); err != nil {
+			return errors.Wrap(err, `beginning kafka transaction`)
+		}
+		s.inTxn, s.txnTopic = true, msg.Topic
+	}
 	if err := s.startInflightMessage(ctx); err != nil {
 		return err
 	}
@@ -511,6 +672,51 @@ func (s *kafkaSink) isInternalRetryable(err error) bool {
 	return errors.As(err, &kError) && kError == sarama.ErrMessageSizeTooLarge
 }
 
+// isMessageError returns true if the broker rejected a message because of
+// something specific to that message, such that retrying it will not help.
+func isMessageError(err error) bool {
+	var kError sarama.KError
+	if !errors.As(err, &kError) {
+		return false
+	}
+	switch kError {
+	case sarama.ErrMessageSizeTooLarge, sarama.ErrInvalidMessage,
+		sarama.ErrInvalidMessageSize, sarama.ErrInvalidRecord:
+		return true
+	default:
+		return false
+	}
+}
+
+// maybeDeadLetter hands a message the broker rejected to the dead letter
+// queue, if one is configured, the error is specific to the message and the
+// dead letter queue has room for it. It returns true if the message was
+// dead-lettered.
+func (s *kafkaSink) maybeDeadLetter(msg *sarama.ProducerMessage, ackError error) bool {
+	if s.dlq == nil || !isMessageError(ackError) {
+		return false
+	}
+	m, ok := msg.Metadata.(messageMetadata)
+	if !ok {
+		return false
+	}
+	// ByteEncoder never fails to encode.
+	key, _ := msg.Key.Encode()
+	value, _ := msg.Value.Encode()
+	log.VInfof(s.ctx, 2, "sending message for topic %s to dead letter queue: %v", msg.Topic, ackError)
+	if err := s.dlq.Add(s.ctx, deadLetterRecord{
+		topic: m.topic,
+		key:   key,
+		row:   value,
+		mvcc:  m.mvcc,
+		err:   ackError,
+	}); err != nil {
+		log.Warningf(s.ctx, "could not send message for topic %s to dead letter queue: %v", msg.Topic, err)
+		return false
+	}
+	return true
+}
+
 func (s *kafkaSink) workerLoop() {
 	defer s.worker.Done()
 
@@ -616,8 +822,12 @@ func (s *kafkaSink) workerLoop() {
 	}
 }
 
-func (s *kafkaSink) finishProducerMessage(ackMsg *sarama.ProducerMessage, ackError error) {
+// finishProducerMessage releases the resources held by a message once it has
+// been acknowledged. It returns false if ackError must be surfaced by the
+// next flush, and true if the message was emitted or dead-lettered.
+func (s *kafkaSink) finishProducerMessage(ackMsg *sarama.ProducerMessage, ackError error) bool {
 	s.mu.AssertHeld()
+	deadLettered := ackError != nil && s.maybeDeadLetter(ackMsg, ackError)
 	if m, ok := ackMsg.Metadata.(messageMetadata); ok {
 		if ackError == nil {
 			sz := ackMsg.Key.Length() + ackMsg.Value.Length()
@@ -626,20 +836,43 @@ func (s *kafkaSink) finishProducerMessage(ackMsg *sarama.ProducerMessage, ackErr
 		}
 		m.alloc.Release(s.ctx)
 	}
-	if s.mu.flushErr == nil && ackError != nil {
+	if ackError == nil || deadLettered {
+		return true
+	}
+	if s.mu.flushErr == nil {
 		s.mu.flushErr = ackError
 	}
+	return false
 }
 
-func (s *kafkaSink) handleBufferedRetries(msgs []*sarama.ProducerMessage, retryErr error) error {
+func (s *kafkaSink) handleBufferedRetries(
+	msgs []*sarama.ProducerMessage, retryErr error,
+) (retErr error) {
 	lastSendErr := retryErr
 	activeConfig := s.kafkaCfg
 	log.Infof(s.ctx, "kafka sink handling %d buffered messages for internal retry", len(msgs))
 
+	// msgErrs holds the error for each message that failed in the last
+	// attempt, if a dead letter queue is configured. Messages which aren't in
+	// the map were delivered.
+	var msgErrs map[*sarama.ProducerMessage]error
+
 	// Ensure memory for messages are always cleaned up
 	defer func() {
+		allFinished := true
 		for _, msg := range msgs {
-			s.finishProducerMessage(msg, lastSendErr)
+			msgErr := lastSendErr
+			if msgErrs != nil {
+				msgErr = msgErrs[msg]
+			}
+			if !s.finishProducerMessage(msg, msgErr) {
+				allFinished = false
+			}
+		}
+		// If every message that failed was dead-lettered, the retry succeeded
+		// as far as the changefeed is concerned.
+		if allFinished && isMessageError(retErr) {
+			retErr = nil
 		}
 	}()
 
@@ -679,6 +912,7 @@ func (s *kafkaSink) handleBufferedRetries(msgs []*sarama.ProducerMessage, retryE
 
 		// SendMessages will attempt to send all messages into an AsyncProducer with
 		// the client's config and then block until the results come in.
+		msgErrs = nil
 		lastSendErr = newProducer.SendMessages(msgs)
 		if lastSendErr != nil {
 			// nolint:errcmp
@@ -687,6 +921,12 @@ func (s *kafkaSink) handleBufferedRetries(msgs []*sarama.ProducerMessage, retryE
 				// were likely from a single partition and therefore would've been
 				// marked with the same error.
 				lastSendErr = sendErrs[0].Err
+				if s.dlq != nil {
+					msgErrs = make(map[*sarama.ProducerMessage]error, len(sendErrs))
+					for _, sendErr := range sendErrs {
+						msgErrs[sendErr.Msg] = sendErr.Err
+					}
+				}
 			}
 		}
 
@@ -848,6 +1088,9 @@ func (c *saramaConfig) Apply(kafka *sarama.Config) error {
 		kafka.Producer.RequiredAcks = parsedAcks
 	}
 	kafka.Producer.Compression = sarama.CompressionCodec(c.Compression)
+	if c.Transaction.Timeout != 0 {
+		kafka.Producer.Transaction.Timeout = time.Duration(c.Transaction.Timeout)
+	}
 	return nil
 }
 
@@ -1077,8 +1320,18 @@ func buildConfluentKafkaConfig(u sinkURL) (kafkaDialConfig, error) {
 	return dialConfig, nil
 }
 
+// kafkaTransactionTimeoutFactor is the minimum ratio of the timeout of the
+// kafka transactions of a changefeed to its checkpoint frequency. Transactions
+// are committed when the aggregators checkpoint, which they do at most once
+// per checkpoint frequency, and later if their frontier lags.
+const kafkaTransactionTimeoutFactor = 4
+
 func buildKafkaConfig(
-	ctx context.Context, u sinkURL, jsonStr changefeedbase.SinkSpecificJSONConfig,
+	ctx context.Context,
+	u sinkURL,
+	jsonStr changefeedbase.SinkSpecificJSONConfig,
+	transactional bool,
+	checkpointFrequency time.Duration,
 ) (*sarama.Config, error) {
 	dialConfig, err := buildDialConfig(u)
 	if err != nil {
@@ -1159,6 +1412,34 @@ func buildKafkaConfig(
 	if err := saramaCfg.Apply(config); err != nil {
 		return nil, errors.Wrap(err, "failed to apply kafka client configuration")
 	}
+
+	if transactional {
+		// Transactional producers must be idempotent, which requires all
+		// in-sync replicas to acknowledge every message, and at most one
+		// inflight request per broker so that retries cannot reorder messages.
+		if saramaCfg.RequiredAcks != `` && config.Producer.RequiredAcks != sarama.WaitForAll {
+			return nil, errors.Errorf(`%s requires RequiredAcks to be "ALL"; check %s option`,
+				changefeedbase.OptExactlyOnce, changefeedbase.OptKafkaSinkConfig)
+		}
+		config.Producer.RequiredAcks = sarama.WaitForAll
+		config.Producer.Idempotent = true
+		config.Net.MaxOpenRequests = 1
+
+		// The brokers abort the transactions which stay open for longer than
+		// their timeout, which would fail the changefeed, so the timeout must
+		// leave room for a few checkpoints.
+		minTimeout := kafkaTransactionTimeoutFactor * checkpointFrequency
+		if saramaCfg.Transaction.Timeout != 0 {
+			if time.Duration(saramaCfg.Transaction.Timeout) < minTimeout {
+				return nil, errors.Errorf(
+					`%s requires Transaction.Timeout to be at least %s, %d times the %s; check %s option`,
+					changefeedbase.OptExactlyOnce, minTimeout, kafkaTransactionTimeoutFactor,
+					changefeedbase.OptMinCheckpointFrequency, changefeedbase.OptKafkaSinkConfig)
+			}
+		} else if config.Producer.Transaction.Timeout < minTimeout {
+			config.Producer.Transaction.Timeout = minTimeout
+		}
+	}
 	return config, nil
 }
 
@@ -1167,6 +1448,8 @@ func makeKafkaSink(
 	u sinkURL,
 	targets changefeedbase.Targets,
 	jsonStr changefeedbase.SinkSpecificJSONConfig,
+	transactional bool,
+	checkpointFrequency time.Duration,
 	settings *cluster.Settings,
 	mb metricsRecorderBuilder,
 ) (Sink, error) {
@@ -1176,7 +1459,7 @@ func makeKafkaSink(
 		return nil, errors.Errorf(`%s is not yet supported`, changefeedbase.SinkParamSchemaTopic)
 	}
 
-	config, err := buildKafkaConfig(ctx, u, jsonStr)
+	config, err := buildKafkaConfig(ctx, u, jsonStr, transactional, checkpointFrequency)
 	if err != nil {
 		return nil, err
 	}
@@ -1189,7 +1472,10 @@ func makeKafkaSink(
 		return nil, err
 	}
 
-	internalRetryEnabled := settings != nil && changefeedbase.BatchReductionRetryEnabled.Get(&settings.SV)
+	// Internal retries resend messages with another producer, outside of the
+	// transaction they were emitted in.
+	internalRetryEnabled := settings != nil && changefeedbase.BatchReductionRetryEnabled.Get(&settings.SV) &&
+		!transactional
 
 	sink := &kafkaSink{
 		ctx:                  ctx,
@@ -1208,6 +1494,30 @@ func makeKafkaSink(
 	return sink, nil
 }
 
+// newKafkaTransactionalID returns the ID to persist in the details of a new
+// changefeed job with the exactly_once option. It has a random suffix, so
+// that the changefeeds of different clusters writing to the same kafka cluster
+// cannot fence each other off.
+func newKafkaTransactionalID(jobID jobspb.JobID) string {
+	return fmt.Sprintf(`crdb-changefeed-%d-%s`, jobID, uuid.MakeV4().Short())
+}
+
+// aggregatorTransactionalID returns the transactional ID of the kafka producer
+// of the changefeed's aggregator which watches the given spans. Since the
+// aggregators of a changefeed watch disjoint spans, their producers don't
+// fence each other off, wherever they run; an aggregator that is planned with
+// the same spans when the changefeed restarts fences off the one it replaces.
+func aggregatorTransactionalID(jobTransactionalID string, spans []roachpb.Span) string {
+	h := fnv.New64a()
+	var buf []byte
+	for _, sp := range spans {
+		buf = encoding.EncodeBytesAscending(buf[:0], sp.Key)
+		buf = encoding.EncodeBytesAscending(buf, sp.EndKey)
+		_, _ = h.Write(buf)
+	}
+	return fmt.Sprintf(`%s-%016x`, jobTransactionalID, h.Sum64())
+}
+
 type kafkaStats struct {
 	outstandingBytes    int64
 	outstandingMessages int64
@@ -10,6 +10,7 @@ package changefeedccl
 
 import (
 	"context"
+	gojson "encoding/json"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -24,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
@@ -44,9 +46,19 @@ type asyncProducerMock struct {
 	inputCh     chan *sarama.ProducerMessage
 	successesCh chan *sarama.ProducerMessage
 	errorsCh    chan *sarama.ProducerError
-	mu          struct {
+	// txns, if set, makes this a transactional producer with the given
+	// transactional ID and epoch.
+	txns  *fakeKafkaTransactions
+	txnID string
+	epoch int
+	mu    struct {
 		syncutil.Mutex
 		outstanding []*sarama.ProducerMessage
+		txnStatus   sarama.ProducerTxnStatusFlag
+		// txnMsgs are the messages written in the open transaction, and
+		// txnOffsets the offsets added to it per consumer group.
+		txnMsgs    []*sarama.ProducerMessage
+		txnOffsets map[string]map[string][]*sarama.PartitionOffsetMetadata
 	}
 }
 
@@ -72,20 +84,156 @@ func (p *asyncProducerMock) Close() error {
 	close(p.errorsCh)
 	return nil
 }
-func (p *asyncProducerMock) IsTransactional() bool                   { panic(`unimplemented`) }
-func (p *asyncProducerMock) BeginTxn() error                         { panic(`unimplemented`) }
-func (p *asyncProducerMock) CommitTxn() error                        { panic(`unimplemented`) }
-func (p *asyncProducerMock) AbortTxn() error                         { panic(`unimplemented`) }
-func (p *asyncProducerMock) TxnStatus() sarama.ProducerTxnStatusFlag { panic(`unimplemented`) }
+func (p *asyncProducerMock) IsTransactional() bool { return p.txns != nil }
+func (p *asyncProducerMock) BeginTxn() error {
+	if p.txns == nil {
+		return sarama.ErrNonTransactedProducer
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.mu.txnStatus != sarama.ProducerTxnFlagReady {
+		return sarama.ErrTransactionNotReady
+	}
+	p.mu.txnStatus = sarama.ProducerTxnFlagInTransaction
+	return nil
+}
+func (p *asyncProducerMock) CommitTxn() error { return p.finishTxn(true /* commit */) }
+func (p *asyncProducerMock) AbortTxn() error  { return p.finishTxn(false /* commit */) }
+func (p *asyncProducerMock) TxnStatus() sarama.ProducerTxnStatusFlag {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.mu.txnStatus
+}
 func (p *asyncProducerMock) AddOffsetsToTxn(
-	_ map[string][]*sarama.PartitionOffsetMetadata, _ string,
+	offsets map[string][]*sarama.PartitionOffsetMetadata, groupID string,
 ) error {
-	panic(`unimplemented`)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.mu.txnStatus != sarama.ProducerTxnFlagInTransaction {
+		return sarama.ErrTransactionNotReady
+	}
+	if p.mu.txnOffsets == nil {
+		p.mu.txnOffsets = make(map[string]map[string][]*sarama.PartitionOffsetMetadata)
+	}
+	p.mu.txnOffsets[groupID] = offsets
+	return nil
 }
 func (p *asyncProducerMock) AddMessageToTxn(_ *sarama.ConsumerMessage, _ string, _ *string) error {
 	panic(`unimplemented`)
 }
 
+// fakeKafkaTransactions simulates the transaction coordinator of a kafka
+// cluster. Messages written by transactional producers only become visible to
+// consumers once their transaction commits. A new producer fences off the
+// previous producer with the same transactional ID, whose open transaction can
+// then never commit.
+type fakeKafkaTransactions struct {
+	syncutil.Mutex
+	epochs    map[string]int
+	committed []*sarama.ProducerMessage
+	// offsets are the committed offsets of each consumer group.
+	offsets map[string]map[string]map[int32]*sarama.OffsetFetchResponseBlock
+}
+
+func newFakeKafkaTransactions() *fakeKafkaTransactions {
+	return &fakeKafkaTransactions{
+		epochs:  make(map[string]int),
+		offsets: make(map[string]map[string]map[int32]*sarama.OffsetFetchResponseBlock),
+	}
+}
+
+// fetchOffsets returns the committed offsets of a consumer group.
+func (k *fakeKafkaTransactions) fetchOffsets(
+	_ kafkaClient, group string,
+) (*sarama.OffsetFetchResponse, error) {
+	k.Lock()
+	defer k.Unlock()
+	resp := &sarama.OffsetFetchResponse{
+		Blocks: make(map[string]map[int32]*sarama.OffsetFetchResponseBlock),
+	}
+	for topic, partitions := range k.offsets[group] {
+		resp.Blocks[topic] = make(map[int32]*sarama.OffsetFetchResponseBlock)
+		for partition, block := range partitions {
+			b := *block
+			resp.Blocks[topic][partition] = &b
+		}
+	}
+	return resp, nil
+}
+
+// newProducer returns a transactional producer, fencing off any previous
+// producer with the same transactional ID.
+func (k *fakeKafkaTransactions) newProducer(id string, bufSize int) *asyncProducerMock {
+	k.Lock()
+	defer k.Unlock()
+	k.epochs[id]++
+	p := newAsyncProducerMock(bufSize)
+	p.txns, p.txnID, p.epoch = k, id, k.epochs[id]
+	p.mu.txnStatus = sarama.ProducerTxnFlagReady
+	return p
+}
+
+// committedKeys returns the keys of the committed messages.
+func (k *fakeKafkaTransactions) committedKeys() []string {
+	k.Lock()
+	defer k.Unlock()
+	var keys []string
+	for _, m := range k.committed {
+		key, _ := m.Key.Encode()
+		keys = append(keys, string(key))
+	}
+	return keys
+}
+
+// writeToTxn adds a message received by a transactional producer to its open
+// transaction.
+func (p *asyncProducerMock) writeToTxn(m *sarama.ProducerMessage) {
+	if p.txns == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.mu.txnMsgs = append(p.mu.txnMsgs, m)
+}
+
+func (p *asyncProducerMock) finishTxn(commit bool) error {
+	if p.txns == nil {
+		return sarama.ErrNonTransactedProducer
+	}
+	p.txns.Lock()
+	defer p.txns.Unlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.txns.epochs[p.txnID] != p.epoch {
+		p.mu.txnStatus = sarama.ProducerTxnFlagInError | sarama.ProducerTxnFlagFatalError
+		return sarama.ErrProducerFenced
+	}
+	if p.mu.txnStatus != sarama.ProducerTxnFlagInTransaction {
+		return sarama.ErrTransactionNotReady
+	}
+	if commit {
+		p.txns.committed = append(p.txns.committed, p.mu.txnMsgs...)
+		for group, offsets := range p.mu.txnOffsets {
+			if p.txns.offsets[group] == nil {
+				p.txns.offsets[group] = make(map[string]map[int32]*sarama.OffsetFetchResponseBlock)
+			}
+			for topic, partitions := range offsets {
+				if p.txns.offsets[group][topic] == nil {
+					p.txns.offsets[group][topic] = make(map[int32]*sarama.OffsetFetchResponseBlock)
+				}
+				for _, o := range partitions {
+					p.txns.offsets[group][topic][o.Partition] = &sarama.OffsetFetchResponseBlock{
+						Offset: o.Offset, Metadata: *o.Metadata,
+					}
+				}
+			}
+		}
+	}
+	p.mu.txnMsgs, p.mu.txnOffsets = nil, nil
+	p.mu.txnStatus = sarama.ProducerTxnFlagReady
+	return nil
+}
+
 type syncProducerMock struct {
 	overrideSend func(*sarama.ProducerMessage) error
 }
@@ -149,6 +297,7 @@ func (p *asyncProducerMock) consumeAndSucceed() (cleanup func()) {
 			case <-done:
 				return
 			case m := <-p.inputCh:
+				p.writeToTxn(m)
 				p.successesCh <- m
 			}
 		}
@@ -801,6 +950,263 @@ func TestKafkaSinkTracksMemory(t *testing.T) {
 	require.EqualValues(t, 0, pool.used())
 }
 
+func TestKafkaSinkDeadLetterQueue(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	p := newAsyncProducerMock(1)
+	sink, cleanup := makeTestKafkaSink(
+		t, noTopicPrefix, defaultTopicName, p, "t")
+	defer cleanup()
+	// Dead-letter rejected messages right away rather than retrying them with
+	// smaller batches first.
+	sink.disableInternalRetry = true
+
+	dlqProducer := newAsyncProducerMock(1)
+	dlqSink, dlqCleanup := makeTestKafkaSink(
+		t, noTopicPrefix, "dlq", dlqProducer, "t")
+	defer dlqCleanup()
+	dlq := &sinkDeadLetterQueue{sink: dlqSink, jobID: 42}
+	sink.setDeadLetterQueue(dlq)
+
+	// A message rejected for being too large is dead-lettered instead of
+	// failing the flush.
+	var pool testAllocPool
+	mvcc := hlc.Timestamp{WallTime: 1, Logical: 2}
+	require.NoError(t, sink.EmitRow(
+		ctx, topic(`t`), []byte(`1`), []byte(`v1`), zeroTS, mvcc, pool.alloc()))
+	m1 := <-p.inputCh
+	require.NoError(t, sink.EmitRow(
+		ctx, topic(`t`), []byte(`2`), []byte(`v2`), zeroTS, zeroTS, pool.alloc()))
+	m2 := <-p.inputCh
+	go func() {
+		p.errorsCh <- &sarama.ProducerError{Msg: m1, Err: sarama.ErrMessageSizeTooLarge}
+	}()
+	go func() { p.successesCh <- m2 }()
+	require.NoError(t, sink.Flush(ctx))
+	require.EqualValues(t, 0, pool.used())
+
+	dlqMsgs := make(chan *sarama.ProducerMessage, 1)
+	go func() {
+		m := <-dlqProducer.inputCh
+		dlqMsgs <- m
+		dlqProducer.successesCh <- m
+	}()
+	require.NoError(t, dlq.Flush(ctx))
+	m := <-dlqMsgs
+	require.Equal(t, `dlq`, m.Topic)
+	key, err := m.Key.Encode()
+	require.NoError(t, err)
+	require.Equal(t, []byte(`1`), key)
+	value, err := m.Value.Encode()
+	require.NoError(t, err)
+	var record deadLetterMessage
+	require.NoError(t, gojson.Unmarshal(value, &record))
+	require.Contains(t, record.Error, sarama.ErrMessageSizeTooLarge.Error())
+	record.Error = ``
+	require.Equal(t, deadLetterMessage{
+		JobID:         42,
+		TableName:     `t`,
+		Key:           []byte(`1`),
+		Row:           []byte(`v1`),
+		MVCCTimestamp: `1.0000000002`,
+	}, record)
+
+	// Errors which aren't specific to the message still fail the flush.
+	require.NoError(t, sink.EmitRow(
+		ctx, topic(`t`), []byte(`3`), []byte(`v3`), zeroTS, zeroTS, pool.alloc()))
+	m3 := <-p.inputCh
+	go func() {
+		p.errorsCh <- &sarama.ProducerError{Msg: m3, Err: errors.New("m3")}
+	}()
+	require.Regexp(t, "m3", sink.Flush(ctx))
+	records, _ := dlq.take()
+	require.Empty(t, records)
+	require.EqualValues(t, 0, pool.used())
+}
+
+func TestDeadLetterBufferMemoryLimit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	mm := startMonitorWithBudget(2048)
+	defer mm.Stop(ctx)
+	var buf deadLetterBuffer
+	buf.init(mm)
+	defer buf.close(ctx)
+
+	record := func(size int) deadLetterRecord {
+		return deadLetterRecord{
+			topic: topic(`t`),
+			row:   make([]byte, size),
+			err:   errors.New("e"),
+		}
+	}
+	require.NoError(t, buf.Add(ctx, record(1024)))
+	require.Regexp(t, "buffering dead letter queue record", buf.Add(ctx, record(1024)))
+
+	// The memory of the buffered records is released once they are flushed.
+	records, size := buf.take()
+	require.Len(t, records, 1)
+	require.Equal(t, deadLetterRecordOverhead+1024+1, size)
+	buf.release(ctx, size)
+	require.NoError(t, buf.Add(ctx, record(1024)))
+}
+
+func TestKafkaSinkTransactions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	u, err := url.Parse(`kafka://localhost:9092`)
+	require.NoError(t, err)
+	cfg, err := buildKafkaConfig(ctx, sinkURL{URL: u}, ``, true /* transactional */, 30*time.Second)
+	require.NoError(t, err)
+	require.True(t, cfg.Producer.Idempotent)
+	require.Equal(t, sarama.WaitForAll, cfg.Producer.RequiredAcks)
+	require.Equal(t, 1, cfg.Net.MaxOpenRequests)
+	_, err = buildKafkaConfig(ctx, sinkURL{URL: u}, `{"RequiredAcks": "ONE"}`, true /* transactional */, 30*time.Second)
+	require.Regexp(t, `exactly_once requires RequiredAcks to be "ALL"`, err)
+
+	// The transaction timeout leaves room for a few checkpoints.
+	require.Equal(t, 2*time.Minute, cfg.Producer.Transaction.Timeout)
+	cfg, err = buildKafkaConfig(ctx, sinkURL{URL: u}, ``, true /* transactional */, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, cfg.Producer.Transaction.Timeout)
+	cfg, err = buildKafkaConfig(ctx, sinkURL{URL: u}, `{"Transaction": {"Timeout": "5m"}}`, true /* transactional */, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Minute, cfg.Producer.Transaction.Timeout)
+	_, err = buildKafkaConfig(ctx, sinkURL{URL: u}, `{"Transaction": {"Timeout": "3m"}}`, true /* transactional */, time.Minute)
+	require.Regexp(t, `exactly_once requires Transaction.Timeout to be at least 4m0s`, err)
+
+	txns := newFakeKafkaTransactions()
+	makeSink := func(id string) (*kafkaSink, *asyncProducerMock) {
+		topics, err := MakeTopicNamer(makeChangefeedTargets(`t`), WithSanitizeFn(SQLNameToKafkaName))
+		require.NoError(t, err)
+		var p *asyncProducerMock
+		s := &kafkaSink{
+			ctx:                  ctx,
+			topics:               topics,
+			kafkaCfg:             &sarama.Config{},
+			metrics:              (*sliMetrics)(nil),
+			disableInternalRetry: true,
+			knobs: kafkaSinkKnobs{
+				OverrideAsyncProducerFromClient: func(client kafkaClient) (sarama.AsyncProducer, error) {
+					p = txns.newProducer(client.Config().Producer.Transaction.ID, unbuffered)
+					return p, nil
+				},
+				OverrideClientInit: func(config *sarama.Config) (kafkaClient, error) {
+					return &fakeKafkaClient{config}, nil
+				},
+				OverrideFetchCommittedOffsets: txns.fetchOffsets,
+			},
+		}
+		s.setTransactionalID(id)
+		require.NoError(t, s.Dial())
+		return s, p
+	}
+	lastCommit := func(s *kafkaSink) sinkTransactionCommit {
+		c, err := s.lastCommittedTransaction(ctx)
+		require.NoError(t, err)
+		return c
+	}
+	emit := func(s *kafkaSink, keys ...string) {
+		for _, k := range keys {
+			require.NoError(t, s.EmitRow(
+				ctx, topic(`t`), []byte(k), []byte(k), zeroTS, zeroTS, zeroAlloc))
+		}
+	}
+
+	// The transactional IDs of aggregators are derived from their spans.
+	spansA := []roachpb.Span{{Key: roachpb.Key(`a`), EndKey: roachpb.Key(`b`)}}
+	spansB := []roachpb.Span{{Key: roachpb.Key(`b`), EndKey: roachpb.Key(`c`)}}
+	idA := aggregatorTransactionalID(`crdb-changefeed-1-abc`, spansA)
+	idB := aggregatorTransactionalID(`crdb-changefeed-1-abc`, spansB)
+	require.Regexp(t, `^crdb-changefeed-1-abc-[0-9a-f]{16}$`, idA)
+	require.NotEqual(t, idA, idB)
+	require.Equal(t, idA, aggregatorTransactionalID(`crdb-changefeed-1-abc`, spansA))
+
+	sink1, p1 := makeSink(idA)
+	require.Equal(t, idA, p1.txnID)
+	stop1 := p1.consumeAndSucceed()
+	require.Equal(t, sinkTransactionCommit{}, lastCommit(sink1))
+
+	// Flushed messages are not visible until the transaction commits, and
+	// neither is its commit.
+	emit(sink1, `1`, `2`)
+	require.NoError(t, sink1.Flush(ctx))
+	require.True(t, sink1.pendingTransaction())
+	require.Empty(t, txns.committedKeys())
+	require.Equal(t, sinkTransactionCommit{}, lastCommit(sink1))
+	c1 := sinkTransactionCommit{seq: 1, token: `a`}
+	require.NoError(t, sink1.commitTransaction(ctx, c1))
+	require.False(t, sink1.pendingTransaction())
+	require.Equal(t, []string{`1`, `2`}, txns.committedKeys())
+	require.Equal(t, c1, lastCommit(sink1))
+
+	// The changefeed fails after emitting more messages, but before
+	// committing them.
+	emit(sink1, `3`, `4`)
+	require.NoError(t, sink1.Flush(ctx))
+
+	// The restarted changefeed resumes from the last commit, and emits these
+	// messages again. Its producer fences off the previous one, whose
+	// transaction can no longer commit.
+	sink2, p2 := makeSink(idA)
+	stop2 := p2.consumeAndSucceed()
+	require.Equal(t, c1, lastCommit(sink2))
+	require.ErrorIs(t, sink1.commitTransaction(ctx, sinkTransactionCommit{seq: 2, token: `a`}),
+		sarama.ErrProducerFenced)
+	require.Equal(t, c1, lastCommit(sink2))
+	emit(sink2, `3`, `4`)
+	c2 := sinkTransactionCommit{seq: 2, token: `b`}
+	require.NoError(t, sink2.commitTransaction(ctx, c2))
+	require.Equal(t, []string{`1`, `2`, `3`, `4`}, txns.committedKeys())
+	require.Equal(t, c2, lastCommit(sink2))
+
+	// Committing without emitting anything is a no-op.
+	require.NoError(t, sink2.commitTransaction(ctx, sinkTransactionCommit{seq: 3, token: `b`}))
+	require.Equal(t, c2, lastCommit(sink2))
+
+	// Closing the sink aborts its open transaction.
+	emit(sink2, `5`)
+	require.NoError(t, sink2.Flush(ctx))
+	stop2()
+	require.NoError(t, sink2.Close())
+	require.Equal(t, sarama.ProducerTxnFlagReady, p2.TxnStatus())
+	require.Equal(t, []string{`1`, `2`, `3`, `4`}, txns.committedKeys())
+
+	// The changefeed is replanned with other spans while the aggregator of its
+	// previous plan is still running. The new aggregator doesn't fence it off.
+	sink3, p3 := makeSink(idA)
+	stop3 := p3.consumeAndSucceed()
+	sink4, p4 := makeSink(idB)
+	stop4 := p4.consumeAndSucceed()
+	emit(sink3, `6`)
+	emit(sink4, `7`)
+	require.NoError(t, sink4.commitTransaction(ctx, sinkTransactionCommit{seq: 1, token: `d`}))
+	require.Equal(t, []string{`1`, `2`, `3`, `4`, `7`}, txns.committedKeys())
+	require.Equal(t, c2, lastCommit(sink3))
+
+	// Dialing a sink with the transactional ID of the previous plan, as the
+	// coordinator does before it starts the new aggregators, fences it off.
+	fence, _ := makeSink(idA)
+	require.NoError(t, fence.Close())
+	require.ErrorIs(t, sink3.commitTransaction(ctx, sinkTransactionCommit{seq: 3, token: `c`}),
+		sarama.ErrProducerFenced)
+	require.Equal(t, c2, lastCommit(fence))
+	require.Equal(t, []string{`1`, `2`, `3`, `4`, `7`}, txns.committedKeys())
+
+	stop1()
+	require.NoError(t, sink1.Close())
+	stop3()
+	require.NoError(t, sink3.Close())
+	stop4()
+	require.NoError(t, sink4.Close())
+}
+
 func TestSinkConfigParsing(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
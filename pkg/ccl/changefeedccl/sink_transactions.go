@@ -0,0 +1,236 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/span"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+// sinkTransactions makes a change aggregator of a changefeed with the
+// exactly_once option emit each row exactly once to its transactional sink.
+//
+// Every transaction contains exactly the rows at or below the aggregator's
+// frontier when it commits: rows above the frontier at their key are held
+// back until their span is resolved, and rows at or below it were emitted by
+// an earlier transaction, so they are dropped. Before a transaction commits,
+// the frontier is written to the job info under the transaction's sequence
+// number and the token of the aggregator, and the sequence number and token
+// are committed along with the transaction's rows. Once the coordinator has
+// fenced off the producers of a previous plan, their last commits can no
+// longer change, and the new aggregators forward their frontiers to the
+// frontiers recorded for these commits, so they neither miss nor repeat any
+// row. A frontier written for a transaction which then failed to commit is
+// never read, since its sequence number or token is not the committed one.
+type sinkTransactions struct {
+	sink     transactionalSink
+	id       string
+	jobID    jobspb.JobID
+	db       isql.DB
+	frontier span.Frontier
+
+	// last is the commit of the last transaction under id, possibly by a
+	// producer of a previous aggregator.
+	last sinkTransactionCommit
+	// token identifies the transactions committed by this aggregator.
+	token string
+
+	// held are the rows above the frontier, in the order they were received.
+	// Their memory is accounted in acc.
+	held []kvevent.Event
+	acc  mon.BoundAccount
+}
+
+// makeSinkTransactions returns the sinkTransactions of an aggregator whose
+// sink was dialed with the transactional ID id. Held rows are accounted in
+// memMon.
+func makeSinkTransactions(
+	ctx context.Context,
+	sink transactionalSink,
+	id string,
+	jobID jobspb.JobID,
+	db isql.DB,
+	frontier span.Frontier,
+	memMon *mon.BytesMonitor,
+) (*sinkTransactions, error) {
+	last, err := sink.lastCommittedTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &sinkTransactions{
+		sink:     sink,
+		id:       id,
+		jobID:    jobID,
+		db:       db,
+		frontier: frontier,
+		last:     last,
+		token:    uuid.MakeV4().String(),
+		acc:      memMon.MakeBoundAccount(),
+	}, nil
+}
+
+// consumeEvent holds a row back until the frontier at its key reaches its
+// timestamp, after which release hands it to the event consumer. Rows which
+// were already resolved are dropped, since they were emitted by a committed
+// transaction.
+func (t *sinkTransactions) consumeEvent(ctx context.Context, ev kvevent.Event) error {
+	// The memory of held rows is accounted separately, so that holding them
+	// does not keep the kvfeed from buffering the resolved spans which
+	// release them.
+	a := ev.DetachAlloc()
+	a.Release(ctx)
+	if ev.Timestamp().LessEq(frontierAtKey(t.frontier, ev.KV().Key)) {
+		return nil
+	}
+	size := int64(ev.ApproximateSize())
+	if err := t.acc.Grow(ctx, size); err != nil {
+		return errors.WithHintf(errors.Wrap(err, "holding back unresolved row"),
+			"consider increasing the %s setting",
+			changefeedbase.ExactlyOnceMaxHeldBytes.Name())
+	}
+	t.held = append(t.held, ev)
+	return nil
+}
+
+// release hands the held rows which are now at or below the frontier to
+// consume, in the order they were received.
+func (t *sinkTransactions) release(
+	ctx context.Context, consume func(context.Context, kvevent.Event) error,
+) error {
+	held := t.held[:0]
+	var released int64
+	for i, ev := range t.held {
+		if frontierAtKey(t.frontier, ev.KV().Key).Less(ev.Timestamp()) {
+			held = append(held, ev)
+			continue
+		}
+		if err := consume(ctx, ev); err != nil {
+			// Keep the rows which were not consumed yet accounted.
+			t.held = append(held, t.held[i+1:]...)
+			t.acc.Shrink(ctx, released)
+			return err
+		}
+		released += int64(ev.ApproximateSize())
+	}
+	for i := len(held); i < len(t.held); i++ {
+		t.held[i] = kvevent.Event{}
+	}
+	t.held = held
+	t.acc.Shrink(ctx, released)
+	return nil
+}
+
+// commit commits the sink's transaction along with the frontier, if any row
+// was emitted since the last commit. Every held row at or below the frontier
+// must have been released and flushed.
+func (t *sinkTransactions) commit(ctx context.Context) error {
+	if !t.sink.pendingTransaction() {
+		return nil
+	}
+	c := sinkTransactionCommit{seq: t.last.seq + 1, token: t.token}
+	var resolved jobspb.ResolvedSpans
+	t.frontier.Entries(func(sp roachpb.Span, ts hlc.Timestamp) span.OpResult {
+		resolved.ResolvedSpans = append(resolved.ResolvedSpans,
+			jobspb.ResolvedSpan{Span: sp, Timestamp: ts})
+		return span.ContinueMatch
+	})
+	value, err := protoutil.Marshal(&resolved)
+	if err != nil {
+		return err
+	}
+	if err := t.db.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		info := jobs.InfoStorageForJob(txn, t.jobID)
+		if err := info.Write(ctx, sinkTransactionCommitKey(t.id, c), value); err != nil {
+			return err
+		}
+		// The frontier of the last commit is still needed if this one fails.
+		return info.DeleteRange(ctx, sinkTransactionCommitKeyPrefix(t.id),
+			sinkTransactionCommitKey(t.id, sinkTransactionCommit{seq: t.last.seq}))
+	}); err != nil {
+		return errors.Wrap(err, "recording kafka transaction frontier")
+	}
+	if err := t.sink.commitTransaction(ctx, c); err != nil {
+		return err
+	}
+	t.last = c
+	return nil
+}
+
+// close releases the memory of the held rows.
+func (t *sinkTransactions) close(ctx context.Context) {
+	t.held = nil
+	t.acc.Close(ctx)
+}
+
+// frontierAtKey returns the timestamp of the frontier at the given key.
+func frontierAtKey(f span.Frontier, key roachpb.Key) (ts hlc.Timestamp) {
+	f.SpanEntries(roachpb.Span{Key: key, EndKey: key.Next()},
+		func(_ roachpb.Span, entryTS hlc.Timestamp) span.OpResult {
+			ts = entryTS
+			return span.StopMatch
+		})
+	return ts
+}
+
+const sinkTransactionCommitInfoKeyPrefix = "~changefeed-txn-frontier-"
+
+// sinkTransactionCommitKeyPrefix returns the prefix of the job info keys of
+// the frontiers committed under the given transactional ID.
+func sinkTransactionCommitKeyPrefix(id string) string {
+	return fmt.Sprintf("%s%s-", sinkTransactionCommitInfoKeyPrefix, id)
+}
+
+// sinkTransactionCommitKey returns the job info key of the frontier committed
+// by the given transaction. Keys sort by sequence number.
+func sinkTransactionCommitKey(id string, c sinkTransactionCommit) string {
+	return fmt.Sprintf("%s%020d-%s", sinkTransactionCommitKeyPrefix(id), c.seq, c.token)
+}
+
+// readSinkTransactionFrontier returns the frontier recorded for the given
+// committed transaction.
+func readSinkTransactionFrontier(
+	ctx context.Context, txn isql.Txn, jobID jobspb.JobID, id string, c sinkTransactionCommit,
+) (jobspb.ResolvedSpans, error) {
+	var resolved jobspb.ResolvedSpans
+	value, ok, err := jobs.InfoStorageForJob(txn, jobID).Get(ctx, sinkTransactionCommitKey(id, c))
+	if err != nil {
+		return resolved, err
+	}
+	if !ok {
+		return resolved, errors.AssertionFailedf(
+			"no frontier recorded for kafka transaction %d of %s", c.seq, id)
+	}
+	if err := protoutil.Unmarshal(value, &resolved); err != nil {
+		return resolved, err
+	}
+	return resolved, nil
+}
+
+// deleteSinkTransactionFrontiers deletes the frontiers recorded for the
+// transactions committed under the given transactional ID.
+func deleteSinkTransactionFrontiers(
+	ctx context.Context, txn isql.Txn, jobID jobspb.JobID, id string,
+) error {
+	prefix := sinkTransactionCommitKeyPrefix(id)
+	return jobs.InfoStorageForJob(txn, jobID).DeleteRange(
+		ctx, prefix, string(roachpb.Key(prefix).PrefixEnd()))
+}
@@ -1649,9 +1649,34 @@ type fakeKafkaSink struct {
 	tg     *teeGroup
 	feedCh chan *sarama.ProducerMessage
 	knobs  *sinkKnobs
+	// txns coordinates the transactions of exactly_once changefeeds.
+	// Messages are sent to the feed as soon as they are acknowledged, whether
+	// or not their transaction commits.
+	txns *fakeKafkaTransactions
 }
 
 var _ Sink = (*fakeKafkaSink)(nil)
+var _ transactionalSink = (*fakeKafkaSink)(nil)
+
+// setTransactionalID implements the transactionalSink interface.
+func (s *fakeKafkaSink) setTransactionalID(id string) {
+	s.Sink.(*kafkaSink).setTransactionalID(id)
+}
+
+// pendingTransaction implements the transactionalSink interface.
+func (s *fakeKafkaSink) pendingTransaction() bool {
+	return s.Sink.(*kafkaSink).pendingTransaction()
+}
+
+// commitTransaction implements the transactionalSink interface.
+func (s *fakeKafkaSink) commitTransaction(ctx context.Context, c sinkTransactionCommit) error {
+	return s.Sink.(*kafkaSink).commitTransaction(ctx, c)
+}
+
+// lastCommittedTransaction implements the transactionalSink interface.
+func (s *fakeKafkaSink) lastCommittedTransaction(ctx context.Context) (sinkTransactionCommit, error) {
+	return s.Sink.(*kafkaSink).lastCommittedTransaction(ctx)
+}
 
 // Dial implements Sink interface
 func (s *fakeKafkaSink) Dial() error {
@@ -1661,12 +1686,17 @@ func (s *fakeKafkaSink) Dial() error {
 		return client, nil
 	}
 
+	kafka.knobs.OverrideFetchCommittedOffsets = s.txns.fetchOffsets
 	kafka.knobs.OverrideAsyncProducerFromClient = func(client kafkaClient) (sarama.AsyncProducer, error) {
 		// The producer we give to kafka sink ignores close call.
 		// This is because normally, kafka sinks owns the producer and so it closes it.
 		// But in this case, if we let the sink close this producer, the test will panic
 		// because we will attempt to send acknowledgements on a closed channel.
-		producer := &asyncIgnoreCloseProducer{newAsyncProducerMock(100)}
+		mock := newAsyncProducerMock(100)
+		if id := client.Config().Producer.Transaction.ID; id != `` {
+			mock = s.txns.newProducer(id, 100)
+		}
+		producer := &asyncIgnoreCloseProducer{mock}
 
 		interceptor := func(m *sarama.ProducerMessage) bool {
 			if s.knobs != nil && s.knobs.kafkaInterceptor != nil {
@@ -1803,12 +1833,14 @@ func (k *kafkaFeedFactory) Feed(create string, args ...interface{}) (cdctest.Tes
 	// Fixed sized buffer is probably okay at this point, but we should probably
 	// have  a proper fix.
 	feedCh := make(chan *sarama.ProducerMessage, 1024)
+	txns := newFakeKafkaTransactions()
 	wrapSink := func(s Sink) Sink {
 		return &fakeKafkaSink{
 			Sink:   s,
 			tg:     tg,
 			feedCh: feedCh,
 			knobs:  k.knobs,
+			txns:   txns,
 		}
 	}
 
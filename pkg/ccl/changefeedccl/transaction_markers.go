@@ -0,0 +1,286 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	gojson "encoding/json"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+)
+
+// Rangefeeds publish every committed value with the ID of the transaction
+// which wrote it. The transaction_id option attaches that ID to every row. It
+// is null for rows whose rangefeed value carried no ID: rows emitted by initial
+// scans and backfills, rows written non-transactionally, and rows which were
+// emitted by a rangefeed catch-up scan, e.g. after the changefeed restarted or
+// a range split, or which were published by a node that does not populate the
+// ID yet.
+//
+// With transaction_markers, the change frontier emits a BEGIN and a COMMIT
+// marker for every transaction which wrote rows to the
+// transactionMarkerTopicName topic, once the changefeed's resolved timestamp
+// reaches the transaction's commit timestamp. At that point all rows written
+// by the transaction, across all ranges, have been flushed to the sink. The
+// aggregators count the rows they emit per transaction and table, and send the
+// counts to the frontier along with their resolved spans. The COMMIT marker
+// carries the totals:
+//
+//	{"status": "BEGIN", "transaction_id": "0ce3a2a4-...",
+//	 "commit_timestamp": "1700000000000000000.0000000000"}
+//	{"status": "COMMIT", "transaction_id": "0ce3a2a4-...",
+//	 "commit_timestamp": "1700000000000000000.0000000000",
+//	 "event_count": 3, "data_collections": [
+//	   {"data_collection": "bar", "event_count": 1},
+//	   {"data_collection": "foo", "event_count": 2}]}
+//
+// Rows without a transaction ID cannot be attributed to a transaction. They
+// are instead covered by markers with a null transaction_id for their commit
+// timestamp, which may span several transactions that committed at exactly
+// the same timestamp; consumers can match them by the rows' mvcc_timestamp.
+//
+// Markers are keyed by the transaction ID and commit timestamp and, like rows,
+// may be emitted more than once if the changefeed restarts. Rows replayed
+// after a restart carry no transaction ID, so they are covered by the markers
+// of their commit timestamp. If only some ranges fall back to a catch-up scan,
+// the rows of a transaction may be split between its own markers and those of
+// its commit timestamp.
+
+// transactionMarkerTopicName is the name of the topic transaction markers are
+// emitted to, before any sink-specific prefix or renaming is applied.
+const transactionMarkerTopicName = `transactions`
+
+// transactionMarkerTopic describes the topic transaction markers are emitted
+// to.
+type transactionMarkerTopic struct{}
+
+var _ TopicDescriptor = transactionMarkerTopic{}
+
+// GetNameComponents implements the TopicDescriptor interface.
+func (transactionMarkerTopic) GetNameComponents() (changefeedbase.StatementTimeName, []string) {
+	return transactionMarkerTopicName, nil
+}
+
+// GetTopicIdentifier implements the TopicDescriptor interface. Tables never
+// have a zero ID, so this does not collide with the topic of any table.
+func (transactionMarkerTopic) GetTopicIdentifier() TopicIdentifier {
+	return TopicIdentifier{}
+}
+
+// GetVersion implements the TopicDescriptor interface.
+func (transactionMarkerTopic) GetVersion() descpb.DescriptorVersion {
+	return 0
+}
+
+// GetTargetSpecification implements the TopicDescriptor interface.
+func (transactionMarkerTopic) GetTargetSpecification() changefeedbase.Target {
+	return changefeedbase.Target{
+		Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+		StatementTimeName: transactionMarkerTopicName,
+	}
+}
+
+// GetTableName implements the TopicDescriptor interface.
+func (transactionMarkerTopic) GetTableName() string {
+	return transactionMarkerTopicName
+}
+
+// transactionKey identifies the rows committed by a transaction, or, if the
+// transaction ID is nil, the rows without a transaction ID committed at a
+// timestamp.
+type transactionKey struct {
+	ts    hlc.Timestamp
+	txnID uuid.UUID
+}
+
+// less orders transactions by commit timestamp, then by ID.
+func (k transactionKey) less(o transactionKey) bool {
+	if !k.ts.Equal(o.ts) {
+		return k.ts.Less(o.ts)
+	}
+	return bytes.Compare(k.txnID.GetBytes(), o.txnID.GetBytes()) < 0
+}
+
+// transactionTable identifies the rows of a table written by a transaction.
+type transactionTable struct {
+	transactionKey
+	table string
+}
+
+// transactionRowCounter counts the rows emitted by a changeAggregator per
+// transaction and table. Rows may be emitted by parallel event consumers, so
+// it is safe for concurrent use.
+type transactionRowCounter struct {
+	mu struct {
+		syncutil.Mutex
+		rows map[transactionTable]int64
+	}
+}
+
+func makeTransactionRowCounter() *transactionRowCounter {
+	c := &transactionRowCounter{}
+	c.mu.rows = make(map[transactionTable]int64)
+	return c
+}
+
+// add records that a row of the table written by the transaction txnID,
+// which committed at ts, was emitted. txnID is nil if the row's transaction
+// is unknown.
+func (c *transactionRowCounter) add(ts hlc.Timestamp, txnID uuid.UUID, table string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.rows[transactionTable{transactionKey: transactionKey{ts: ts, txnID: txnID}, table: table}]++
+}
+
+// take returns and resets the counts recorded since it was last called.
+func (c *transactionRowCounter) take() []jobspb.ResolvedSpans_TransactionRows {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.mu.rows) == 0 {
+		return nil
+	}
+	counts := make([]jobspb.ResolvedSpans_TransactionRows, 0, len(c.mu.rows))
+	for t, n := range c.mu.rows {
+		counts = append(counts, jobspb.ResolvedSpans_TransactionRows{
+			Timestamp: t.ts,
+			TxnID:     t.txnID,
+			TableName: t.table,
+			Rows:      n,
+		})
+	}
+	c.mu.rows = make(map[transactionTable]int64)
+	return counts
+}
+
+// transactionMarker is the JSON representation of a BEGIN or COMMIT marker.
+// TransactionID is nil for the markers of the rows without a transaction ID
+// committed at a timestamp.
+type transactionMarker struct {
+	Status          string                      `json:"status"`
+	TransactionID   *string                     `json:"transaction_id"`
+	CommitTimestamp string                      `json:"commit_timestamp"`
+	EventCount      int64                       `json:"event_count,omitempty"`
+	DataCollections []transactionDataCollection `json:"data_collections,omitempty"`
+}
+
+// transactionDataCollection is the number of rows of a table written by a
+// transaction.
+type transactionDataCollection struct {
+	DataCollection string `json:"data_collection"`
+	EventCount     int64  `json:"event_count"`
+}
+
+// transactionMarkerEmitter is used by the changeFrontier to emit markers for
+// transactions as their commit timestamps are resolved.
+type transactionMarkerEmitter struct {
+	// pending maps the transactions whose commit timestamps have not been
+	// resolved yet to their row counts per table.
+	pending map[transactionKey]map[string]int64
+	// emitted is the resolved timestamp up to which markers have been emitted.
+	emitted hlc.Timestamp
+}
+
+var _ timestampLowerBoundOracle = (*transactionMarkerEmitter)(nil)
+
+func makeTransactionMarkerEmitter() *transactionMarkerEmitter {
+	return &transactionMarkerEmitter{pending: make(map[transactionKey]map[string]int64)}
+}
+
+// add records row counts sent by an aggregator.
+func (m *transactionMarkerEmitter) add(counts []jobspb.ResolvedSpans_TransactionRows) {
+	for _, c := range counts {
+		k := transactionKey{ts: c.Timestamp, txnID: c.TxnID}
+		tables, ok := m.pending[k]
+		if !ok {
+			tables = make(map[string]int64)
+			m.pending[k] = tables
+		}
+		tables[c.TableName] += c.Rows
+	}
+}
+
+// inclusiveLowerBoundTS implements the timestampLowerBoundOracle interface.
+// Markers emitted from now on are for commit timestamps after the timestamp up
+// to which markers have already been emitted.
+func (m *transactionMarkerEmitter) inclusiveLowerBoundTS() hlc.Timestamp {
+	return m.emitted.Next()
+}
+
+// emit emits markers, in commit timestamp order, for every pending
+// transaction which committed at or before resolved and flushes the sink. It
+// must be called before resolved is checkpointed, since markers are not
+// emitted again for commit timestamps at or below the changefeed's high-water
+// mark.
+func (m *transactionMarkerEmitter) emit(
+	ctx context.Context, sink EventSink, resolved hlc.Timestamp,
+) error {
+	var ready []transactionKey
+	for k := range m.pending {
+		if k.ts.LessEq(resolved) {
+			ready = append(ready, k)
+		}
+	}
+	if len(ready) == 0 {
+		m.emitted.Forward(resolved)
+		return nil
+	}
+	sort.Slice(ready, func(i, j int) bool { return ready[i].less(ready[j]) })
+
+	for _, k := range ready {
+		commitTS := timestampToString(k.ts)
+		var txnID *string
+		if !k.txnID.Equal(uuid.Nil) {
+			id := k.txnID.String()
+			txnID = &id
+		}
+		key, err := gojson.Marshal([]*string{txnID, &commitTS})
+		if err != nil {
+			return err
+		}
+		begin := transactionMarker{Status: `BEGIN`, TransactionID: txnID, CommitTimestamp: commitTS}
+		commit := transactionMarker{Status: `COMMIT`, TransactionID: txnID, CommitTimestamp: commitTS}
+		for table, n := range m.pending[k] {
+			commit.EventCount += n
+			commit.DataCollections = append(commit.DataCollections,
+				transactionDataCollection{DataCollection: table, EventCount: n})
+		}
+		sort.Slice(commit.DataCollections, func(i, j int) bool {
+			return commit.DataCollections[i].DataCollection < commit.DataCollections[j].DataCollection
+		})
+
+		for _, marker := range []transactionMarker{begin, commit} {
+			value, err := gojson.Marshal(marker)
+			if err != nil {
+				return err
+			}
+			if err := sink.EmitRow(
+				ctx, transactionMarkerTopic{}, key, value, k.ts, k.ts, kvevent.Alloc{},
+			); err != nil {
+				return err
+			}
+		}
+	}
+	if err := sink.Flush(ctx); err != nil {
+		return err
+	}
+
+	for _, k := range ready {
+		delete(m.pending, k)
+	}
+	m.emitted.Forward(resolved)
+	return nil
+}
@@ -0,0 +1,219 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uint128"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink records emitted messages as `topic: key->value` strings.
+type recordingSink struct {
+	testSink
+	msgs    []string
+	flushes int
+}
+
+var _ EventSink = (*recordingSink)(nil)
+
+func (s *recordingSink) Dial() error  { return nil }
+func (s *recordingSink) Close() error { return nil }
+
+func (s *recordingSink) EmitRow(
+	ctx context.Context,
+	topic TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	name, _ := topic.GetNameComponents()
+	s.msgs = append(s.msgs, fmt.Sprintf(`%s: %s->%s`, name, key, value))
+	return nil
+}
+
+func (s *recordingSink) Flush(ctx context.Context) error {
+	s.flushes++
+	return nil
+}
+
+func TestTransactionRowCounter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ts1 := hlc.Timestamp{WallTime: 1}
+	ts2 := hlc.Timestamp{WallTime: 2}
+	txn1 := uuid.FromUint128(uint128.FromInts(0, 1))
+	txn2 := uuid.FromUint128(uint128.FromInts(0, 2))
+	c := makeTransactionRowCounter()
+	require.Nil(t, c.take())
+
+	// Transactions which commit at the same timestamp are counted separately.
+	c.add(ts1, txn1, `foo`)
+	c.add(ts1, txn1, `foo`)
+	c.add(ts1, txn1, `bar`)
+	c.add(ts1, txn2, `foo`)
+	c.add(ts2, uuid.Nil, `foo`)
+	counts := c.take()
+	sort.Slice(counts, func(i, j int) bool {
+		ki := transactionKey{ts: counts[i].Timestamp, txnID: counts[i].TxnID}
+		kj := transactionKey{ts: counts[j].Timestamp, txnID: counts[j].TxnID}
+		if ki != kj {
+			return ki.less(kj)
+		}
+		return counts[i].TableName < counts[j].TableName
+	})
+	require.Equal(t, []jobspb.ResolvedSpans_TransactionRows{
+		{Timestamp: ts1, TxnID: txn1, TableName: `bar`, Rows: 1},
+		{Timestamp: ts1, TxnID: txn1, TableName: `foo`, Rows: 2},
+		{Timestamp: ts1, TxnID: txn2, TableName: `foo`, Rows: 1},
+		{Timestamp: ts2, TableName: `foo`, Rows: 1},
+	}, counts)
+	require.Nil(t, c.take())
+}
+
+func TestTransactionMarkerEmitter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	ts1 := hlc.Timestamp{WallTime: 1}
+	ts2 := hlc.Timestamp{WallTime: 2}
+	ts3 := hlc.Timestamp{WallTime: 3}
+	txn1 := uuid.FromUint128(uint128.FromInts(0, 1))
+	txn2 := uuid.FromUint128(uint128.FromInts(0, 2))
+
+	m := makeTransactionMarkerEmitter()
+	// Counts for the same transaction may come from several aggregators.
+	m.add([]jobspb.ResolvedSpans_TransactionRows{
+		{Timestamp: ts2, TableName: `foo`, Rows: 1},
+		{Timestamp: ts1, TxnID: txn2, TableName: `foo`, Rows: 2},
+	})
+	m.add([]jobspb.ResolvedSpans_TransactionRows{
+		{Timestamp: ts1, TxnID: txn2, TableName: `bar`, Rows: 1},
+		{Timestamp: ts1, TxnID: txn2, TableName: `foo`, Rows: 1},
+		{Timestamp: ts1, TxnID: txn1, TableName: `foo`, Rows: 1},
+	})
+
+	var sink recordingSink
+	require.NoError(t, m.emit(ctx, &sink, ts1))
+	require.Equal(t, []string{
+		`transactions: ["` + txn1.String() + `","1.0000000000"]->{"status":"BEGIN",` +
+			`"transaction_id":"` + txn1.String() + `","commit_timestamp":"1.0000000000"}`,
+		`transactions: ["` + txn1.String() + `","1.0000000000"]->{"status":"COMMIT",` +
+			`"transaction_id":"` + txn1.String() + `","commit_timestamp":"1.0000000000",` +
+			`"event_count":1,"data_collections":[{"data_collection":"foo","event_count":1}]}`,
+		`transactions: ["` + txn2.String() + `","1.0000000000"]->{"status":"BEGIN",` +
+			`"transaction_id":"` + txn2.String() + `","commit_timestamp":"1.0000000000"}`,
+		`transactions: ["` + txn2.String() + `","1.0000000000"]->{"status":"COMMIT",` +
+			`"transaction_id":"` + txn2.String() + `","commit_timestamp":"1.0000000000",` +
+			`"event_count":4,"data_collections":[{"data_collection":"bar","event_count":1},` +
+			`{"data_collection":"foo","event_count":3}]}`,
+	}, sink.msgs)
+	require.Equal(t, 1, sink.flushes)
+	require.Equal(t, ts1.Next(), m.inclusiveLowerBoundTS())
+
+	// Nothing is emitted, nor flushed, until another commit timestamp resolves.
+	sink.msgs = nil
+	require.NoError(t, m.emit(ctx, &sink, ts1))
+	require.Empty(t, sink.msgs)
+	require.Equal(t, 1, sink.flushes)
+
+	// Rows without a transaction ID are covered by the markers of their commit
+	// timestamp.
+	require.NoError(t, m.emit(ctx, &sink, ts3))
+	require.Equal(t, []string{
+		`transactions: [null,"2.0000000000"]->{"status":"BEGIN","transaction_id":null,` +
+			`"commit_timestamp":"2.0000000000"}`,
+		`transactions: [null,"2.0000000000"]->{"status":"COMMIT","transaction_id":null,` +
+			`"commit_timestamp":"2.0000000000","event_count":1,` +
+			`"data_collections":[{"data_collection":"foo","event_count":1}]}`,
+	}, sink.msgs)
+	require.Equal(t, 2, sink.flushes)
+	require.Equal(t, ts3.Next(), m.inclusiveLowerBoundTS())
+}
+
+func TestJSONEncoderTransactionID(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+	require.NoError(t, err)
+	row := cdcevent.TestingMakeEventRow(tableDesc, 0, rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.NewDString(`bar`)},
+	}, false)
+	prevRow := cdcevent.TestingMakeEventRow(tableDesc, 0, nil, false)
+	ts := hlc.Timestamp{WallTime: 1, Logical: 2}
+	txnID := uuid.FromUint128(uint128.FromInts(0, 1))
+
+	targets := changefeedbase.Targets{}
+	targets.Add(changefeedbase.Target{
+		Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+		TableID:           tableDesc.GetID(),
+		StatementTimeName: changefeedbase.StatementTimeName(tableDesc.GetName()),
+	})
+
+	for _, tc := range []struct {
+		envelope changefeedbase.EnvelopeType
+		txnID    uuid.UUID
+		expected string
+	}{
+		{
+			envelope: changefeedbase.OptEnvelopeWrapped,
+			txnID:    txnID,
+			expected: `{"after": {"a": 1, "b": "bar"}, "transaction_id": "` + txnID.String() + `"}`,
+		},
+		{
+			// E.g. a backfill or a catch-up scan.
+			envelope: changefeedbase.OptEnvelopeWrapped,
+			expected: `{"after": {"a": 1, "b": "bar"}, "transaction_id": null}`,
+		},
+		{
+			envelope: changefeedbase.OptEnvelopeBare,
+			txnID:    txnID,
+			expected: `{"__crdb__": {"transaction_id": "` + txnID.String() + `"}, "a": 1, "b": "bar"}`,
+		},
+	} {
+		t.Run(fmt.Sprintf("%s/txn=%s", tc.envelope, tc.txnID.Short()), func(t *testing.T) {
+			opts, err := changefeedbase.MakeStatementOptions(map[string]string{
+				changefeedbase.OptEnvelope:      string(tc.envelope),
+				changefeedbase.OptTransactionID: ``,
+			}).GetEncodingOptions()
+			require.NoError(t, err)
+			e, err := getEncoder(opts, targets, false, nil, nil, clusterInfo{})
+			require.NoError(t, err)
+
+			evCtx := eventContext{updated: ts, mvcc: ts, txnID: tc.txnID}
+			value, err := e.EncodeValue(context.Background(), evCtx, row, prevRow)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, string(value))
+		})
+	}
+
+	_, err = changefeedbase.MakeStatementOptions(map[string]string{
+		changefeedbase.OptFormat:        string(changefeedbase.OptFormatAvro),
+		changefeedbase.OptTransactionID: ``,
+	}).GetEncodingOptions()
+	require.EqualError(t, err, `transaction_id is only usable with format=json`)
+}
@@ -123,6 +123,13 @@ func TestLogic_tmp(t *testing.T) {
 	logictest.RunLogicTests(t, serverArgs, configIdx, glob)
 }
 
+func TestTenantLogic_advisory_locks(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "advisory_locks")
+}
+
 func TestTenantLogic_aggregate(
 	t *testing.T,
 ) {
@@ -571,6 +578,13 @@ func TestTenantLogic_default(
 	runLogicTest(t, "default")
 }
 
+func TestTenantLogic_deferrable_constraints(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "deferrable_constraints")
+}
+
 func TestTenantLogic_delete(
 	t *testing.T,
 ) {
@@ -795,6 +809,13 @@ func TestTenantLogic_exclude_data_from_backup(
 	runLogicTest(t, "exclude_data_from_backup")
 }
 
+func TestTenantLogic_exclusion_constraints(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "exclusion_constraints")
+}
+
 func TestTenantLogic_experimental_distsql_planning(
 	t *testing.T,
 ) {
@@ -970,6 +991,13 @@ func TestTenantLogic_group_join(
 	runLogicTest(t, "group_join")
 }
 
+func TestTenantLogic_grouping_sets(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "grouping_sets")
+}
+
 func TestTenantLogic_hash_join(
 	t *testing.T,
 ) {
@@ -1152,6 +1180,13 @@ func TestTenantLogic_json_index(
 	runLogicTest(t, "json_index")
 }
 
+func TestTenantLogic_jsonpath(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "jsonpath")
+}
+
 func TestTenantLogic_kv_builtin_functions_tenant(
 	t *testing.T,
 ) {
@@ -1166,6 +1201,13 @@ func TestTenantLogic_limit(
 	runLogicTest(t, "limit")
 }
 
+func TestTenantLogic_listen_notify(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "listen_notify")
+}
+
 func TestTenantLogic_lock_timeout(
 	t *testing.T,
 ) {
@@ -1201,6 +1243,13 @@ func TestTenantLogic_materialized_view(
 	runLogicTest(t, "materialized_view")
 }
 
+func TestTenantLogic_materialized_view_incremental(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "materialized_view_incremental")
+}
+
 func TestTenantLogic_merge_join(
 	t *testing.T,
 ) {
@@ -1474,6 +1523,20 @@ func TestTenantLogic_propagate_input_ordering(
 	runLogicTest(t, "propagate_input_ordering")
 }
 
+func TestTenantLogic_publication(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "publication")
+}
+
+func TestTenantLogic_range(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "range")
+}
+
 func TestTenantLogic_read_committed(
 	t *testing.T,
 ) {
@@ -1593,6 +1656,13 @@ func TestTenantLogic_routine_schema_change(
 	runLogicTest(t, "routine_schema_change")
 }
 
+func TestTenantLogic_row_level_security(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "row_level_security")
+}
+
 func TestTenantLogic_row_level_ttl(
 	t *testing.T,
 ) {
@@ -2055,6 +2125,13 @@ func TestTenantLogic_table(
 	runLogicTest(t, "table")
 }
 
+func TestTenantLogic_tablesample(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "tablesample")
+}
+
 func TestTenantLogic_target_names(
 	t *testing.T,
 ) {
@@ -2104,6 +2181,13 @@ func TestTenantLogic_tenant_span_stats(
 	runLogicTest(t, "tenant_span_stats")
 }
 
+func TestTenantLogic_text_search_config(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "text_search_config")
+}
+
 func TestTenantLogic_time(
 	t *testing.T,
 ) {
@@ -2216,6 +2300,13 @@ func TestTenantLogic_udf(
 	runLogicTest(t, "udf")
 }
 
+func TestTenantLogic_udf_aggregate(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "udf_aggregate")
+}
+
 func TestTenantLogic_udf_delete(
 	t *testing.T,
 ) {
@@ -2272,6 +2363,13 @@ func TestTenantLogic_udf_options(
 	runLogicTest(t, "udf_options")
 }
 
+func TestTenantLogic_udf_params(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "udf_params")
+}
+
 func TestTenantLogic_udf_prepare(
 	t *testing.T,
 ) {
@@ -300,6 +300,59 @@ const (
 	// progress columns from system.jobs table.
 	V24_1_DropPayloadAndProgressFromSystemJobsTable
 
+	// V24_1_JsonpathType is the version at which the jsonpath type can be used
+	// in descriptors.
+	V24_1_JsonpathType
+
+	// V24_1_RangeTypes is the version at which range and multirange types can
+	// be used in descriptors.
+	V24_1_RangeTypes
+
+	// V24_1_ExclusionConstraints is the version at which exclusion constraints
+	// can be added to tables.
+	V24_1_ExclusionConstraints
+
+	// V24_1_TriggerPrivilege is the version at which the TRIGGER privilege can
+	// be stored in table descriptors.
+	V24_1_TriggerPrivilege
+
+	// V24_1_RowLevelSecurity is the version at which row-level security policies
+	// and modes can be stored in table descriptors.
+	V24_1_RowLevelSecurity
+
+	// V24_1_Triggers is the version at which triggers can be stored in table
+	// descriptors.
+	V24_1_Triggers
+
+	// V24_1_Domains is the version at which domain types can be stored in type
+	// descriptors.
+	V24_1_Domains
+
+	// V24_1_UserDefinedAggregates is the version at which user-defined aggregate
+	// functions can be stored in function descriptors.
+	V24_1_UserDefinedAggregates
+
+	// V24_1_RoutineParamClasses is the version at which OUT, INOUT and VARIADIC
+	// parameters, including those declared by RETURNS TABLE, can be stored in
+	// function descriptors.
+	V24_1_RoutineParamClasses
+
+	// V24_1_Publications is the version at which publications can be stored in
+	// database descriptors and subscription jobs can be created.
+	V24_1_Publications
+
+	// V24_1_DeferrableConstraints is the version at which constraints can be
+	// declared DEFERRABLE and SET CONSTRAINTS can be used.
+	V24_1_DeferrableConstraints
+
+	// V24_1_TextSearchConfigurations is the version at which text search
+	// configurations and dictionaries can be stored in database descriptors.
+	V24_1_TextSearchConfigurations
+
+	// V24_1_Notifications is the version at which all nodes serve the Notify RPC,
+	// so that LISTEN and NOTIFY can be used.
+	V24_1_Notifications
+
 	numKeys
 )
 
@@ -368,6 +421,20 @@ var versionTable = [numKeys]roachpb.Version{
 	// *************************************************
 
 	V24_1_DropPayloadAndProgressFromSystemJobsTable: {Major: 23, Minor: 2, Internal: 4},
+	V24_1_JsonpathType: {Major: 23, Minor: 2, Internal: 6},
+	V24_1_RangeTypes:   {Major: 23, Minor: 2, Internal: 8},
+
+	V24_1_ExclusionConstraints:     {Major: 23, Minor: 2, Internal: 10},
+	V24_1_TriggerPrivilege:         {Major: 23, Minor: 2, Internal: 12},
+	V24_1_RowLevelSecurity:         {Major: 23, Minor: 2, Internal: 14},
+	V24_1_Triggers:                 {Major: 23, Minor: 2, Internal: 16},
+	V24_1_Domains:                  {Major: 23, Minor: 2, Internal: 18},
+	V24_1_UserDefinedAggregates:    {Major: 23, Minor: 2, Internal: 20},
+	V24_1_RoutineParamClasses:      {Major: 23, Minor: 2, Internal: 22},
+	V24_1_Publications:             {Major: 23, Minor: 2, Internal: 24},
+	V24_1_DeferrableConstraints:    {Major: 23, Minor: 2, Internal: 26},
+	V24_1_TextSearchConfigurations: {Major: 23, Minor: 2, Internal: 28},
+	V24_1_Notifications:            {Major: 23, Minor: 2, Internal: 30},
 }
 
 // Latest is always the highest version key. This is the maximum logical cluster
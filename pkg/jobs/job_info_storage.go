@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlliveness"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/errors"
@@ -248,6 +249,106 @@ func (i InfoStorage) Write(ctx context.Context, infoKey string, value []byte) er
 	return i.write(ctx, infoKey, value)
 }
 
+// GetMany fetches the latest info records for the given info keys. The values
+// are returned in the order of the keys, and are nil for the keys that have no
+// record.
+func (i InfoStorage) GetMany(ctx context.Context, infoKeys []string) ([][]byte, error) {
+	if i.txn == nil {
+		return nil, errors.New("cannot access the job info table without an associated txn")
+	}
+	values := make([][]byte, len(infoKeys))
+	if len(infoKeys) == 0 {
+		return values, nil
+	}
+
+	ctx, sp := tracing.ChildSpan(ctx, "get-many-job-info")
+	defer sp.Finish()
+
+	keys := tree.NewDArray(types.String)
+	indexes := make(map[string][]int, len(infoKeys))
+	for idx, infoKey := range infoKeys {
+		if _, ok := indexes[infoKey]; !ok {
+			if err := keys.Append(tree.NewDString(infoKey)); err != nil {
+				return nil, err
+			}
+		}
+		indexes[infoKey] = append(indexes[infoKey], idx)
+	}
+	rows, err := i.txn.QueryBufferedEx(
+		ctx, "job-info-get-many", i.txn.KV(),
+		sessiondata.NodeUserSessionDataOverride,
+		"SELECT info_key, value FROM system.job_info WHERE job_id = $1 AND info_key = ANY($2) ORDER BY info_key, written DESC",
+		i.j.ID(), keys,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var prevKey string
+	for _, row := range rows {
+		key, ok := row[0].(*tree.DString)
+		if !ok {
+			return nil, errors.AssertionFailedf("job info: expected info_key to be string (was %T)", row[0])
+		}
+		infoKey := string(*key)
+		if infoKey == prevKey {
+			continue
+		}
+		prevKey = infoKey
+		value, ok := row[1].(*tree.DBytes)
+		if !ok {
+			return nil, errors.AssertionFailedf("job info: expected value to be DBytes (was %T)", row[1])
+		}
+		for _, idx := range indexes[infoKey] {
+			values[idx] = []byte(*value)
+		}
+	}
+	return values, nil
+}
+
+// WriteMany is like Write for several info records, which it writes with a
+// constant number of statements. The info keys must be distinct.
+func (i InfoStorage) WriteMany(ctx context.Context, infoKeys []string, values [][]byte) error {
+	if len(infoKeys) != len(values) {
+		return errors.AssertionFailedf("%d info keys but %d values", len(infoKeys), len(values))
+	}
+	if len(infoKeys) == 0 {
+		return nil
+	}
+	keys := tree.NewDArray(types.String)
+	vals := tree.NewDArray(types.Bytes)
+	for idx, infoKey := range infoKeys {
+		if values[idx] == nil {
+			return errors.AssertionFailedf("missing value (infoKey %q)", infoKey)
+		}
+		if err := keys.Append(tree.NewDString(infoKey)); err != nil {
+			return err
+		}
+		if err := vals.Append(tree.NewDBytes(tree.DBytes(values[idx]))); err != nil {
+			return err
+		}
+	}
+	return i.doWrite(ctx, func(ctx context.Context, j *Job, txn isql.Txn) error {
+		// First clear out any older revisions of these infos.
+		_, err := txn.ExecEx(
+			ctx, "write-many-job-info-delete", txn.KV(),
+			sessiondata.NodeUserSessionDataOverride,
+			"DELETE FROM system.job_info WHERE job_id = $1 AND info_key = ANY($2)",
+			j.ID(), keys,
+		)
+		if err != nil {
+			return err
+		}
+		_, err = txn.ExecEx(
+			ctx, "write-many-job-info-insert", txn.KV(),
+			sessiondata.NodeUserSessionDataOverride,
+			`INSERT INTO system.job_info (job_id, info_key, written, value)
+SELECT $1, info_key, now(), value FROM ROWS FROM (unnest($2::STRING[]), unnest($3::BYTES[])) AS t (info_key, value)`,
+			j.ID(), keys, vals,
+		)
+		return err
+	})
+}
+
 // Delete removes the info record for the provided infoKey.
 func (i InfoStorage) Delete(ctx context.Context, infoKey string) error {
 	return i.write(ctx, infoKey, nil /* value */)
@@ -291,3 +291,46 @@ func TestAccessorsWithWrongSQLLivenessSession(t *testing.T) {
 		})
 	}))
 }
+
+func TestJobInfoGetWriteMany(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s := serverutils.StartServerOnly(t, base.TestServerArgs{})
+	ctx := context.Background()
+	defer s.Stopper().Stop(ctx)
+
+	idb := s.InternalDB().(isql.DB)
+	r := s.JobRegistry().(*jobs.Registry)
+	job, err := r.CreateJobWithTxn(ctx, jobs.Record{
+		Details:  jobspb.BackupDetails{},
+		Progress: jobspb.BackupProgress{},
+		Username: username.TestUserName(),
+	}, 1, nil /* txn */)
+	require.NoError(t, err)
+
+	kA, kB, kC := "🔑A", "🔑B", "🔑C"
+	v1, v2, v3 := []byte("val1"), []byte("val2"), []byte("val3")
+	getMany := func(keys ...string) (values [][]byte) {
+		require.NoError(t, idb.Txn(ctx, func(ctx context.Context, txn isql.Txn) (err error) {
+			values, err = job.InfoStorage(txn).GetMany(ctx, keys)
+			return err
+		}))
+		return values
+	}
+
+	require.NoError(t, idb.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		infoStorage := job.InfoStorage(txn)
+		if err := infoStorage.Write(ctx, kA, v1); err != nil {
+			return err
+		}
+		return infoStorage.WriteMany(ctx, []string{kB, kC}, [][]byte{v1, v2})
+	}))
+	require.Equal(t, [][]byte{v1, v1, v2, nil}, getMany(kA, kB, kC, "missing"))
+
+	// WriteMany replaces the existing records.
+	require.NoError(t, idb.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		return job.InfoStorage(txn).WriteMany(ctx, []string{kC, kA}, [][]byte{v3, v2})
+	}))
+	require.Equal(t, [][]byte{v2, v1, v3, v3}, getMany(kA, kB, kC, kC))
+	require.Empty(t, getMany())
+}
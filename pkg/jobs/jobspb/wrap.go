@@ -46,6 +46,7 @@ var (
 	_ Details = AutoConfigTaskDetails{}
 	_ Details = AutoUpdateSQLActivityDetails{}
 	_ Details = MVCCStatisticsJobDetails{}
+	_ Details = SubscriptionDetails{}
 )
 
 // ProgressDetails is a marker interface for job progress details proto structs.
@@ -71,6 +72,7 @@ var (
 	_ ProgressDetails = AutoConfigTaskProgress{}
 	_ ProgressDetails = AutoUpdateSQLActivityProgress{}
 	_ ProgressDetails = MVCCStatisticsJobProgress{}
+	_ ProgressDetails = SubscriptionProgress{}
 )
 
 // Type returns the payload's job type and panics if the type is invalid.
@@ -213,6 +215,8 @@ func DetailsType(d isPayload_Details) (Type, error) {
 		return TypeAutoUpdateSQLActivity, nil
 	case *Payload_MvccStatisticsDetails:
 		return TypeMVCCStatisticsUpdate, nil
+	case *Payload_Subscription:
+		return TypeSubscription, nil
 	default:
 		return TypeUnspecified, errors.Newf("Payload.Type called on a payload with an unknown details type: %T", d)
 	}
@@ -258,6 +262,7 @@ var JobDetailsForEveryJobType = map[Type]Details{
 	TypeAutoConfigTask:               AutoConfigTaskDetails{},
 	TypeAutoUpdateSQLActivity:        AutoUpdateSQLActivityDetails{},
 	TypeMVCCStatisticsUpdate:         MVCCStatisticsJobDetails{},
+	TypeSubscription:                 SubscriptionDetails{},
 }
 
 // WrapProgressDetails wraps a ProgressDetails object in the protobuf wrapper
@@ -315,6 +320,8 @@ func WrapProgressDetails(details ProgressDetails) interface {
 		return &Progress_UpdateSqlActivity{UpdateSqlActivity: &d}
 	case MVCCStatisticsJobProgress:
 		return &Progress_MvccStatisticsProgress{MvccStatisticsProgress: &d}
+	case SubscriptionProgress:
+		return &Progress_Subscription{Subscription: &d}
 	default:
 		panic(errors.AssertionFailedf("WrapProgressDetails: unknown progress type %T", d))
 	}
@@ -370,6 +377,8 @@ func (p *Payload) UnwrapDetails() Details {
 		return *d.AutoUpdateSqlActivities
 	case *Payload_MvccStatisticsDetails:
 		return *d.MvccStatisticsDetails
+	case *Payload_Subscription:
+		return *d.Subscription
 	default:
 		return nil
 	}
@@ -425,6 +434,8 @@ func (p *Progress) UnwrapDetails() ProgressDetails {
 		return *d.UpdateSqlActivity
 	case *Progress_MvccStatisticsProgress:
 		return *d.MvccStatisticsProgress
+	case *Progress_Subscription:
+		return *d.Subscription
 	default:
 		return nil
 	}
@@ -504,6 +515,8 @@ func WrapPayloadDetails(details Details) interface {
 		return &Payload_AutoUpdateSqlActivities{AutoUpdateSqlActivities: &d}
 	case MVCCStatisticsJobDetails:
 		return &Payload_MvccStatisticsDetails{MvccStatisticsDetails: &d}
+	case SubscriptionDetails:
+		return &Payload_Subscription{Subscription: &d}
 	default:
 		panic(errors.AssertionFailedf("jobs.WrapPayloadDetails: unknown details type %T", d))
 	}
@@ -539,7 +552,7 @@ const (
 func (Type) SafeValue() {}
 
 // NumJobTypes is the number of jobs types.
-const NumJobTypes = 25
+const NumJobTypes = 26
 
 // ChangefeedDetailsMarshaler allows for dependency injection of
 // cloud.SanitizeExternalStorageURI to avoid the dependency from this
@@ -411,6 +411,13 @@ const (
 	CommentsTablePrimaryKeyIndexID           = 1
 	CommentsTableCommentColFamID             = 4
 
+	// SqllivenessAdvisoryLocksIndexID is the index ID, under the sqlliveness
+	// table, of the span that holds the KV locks representing advisory locks.
+	// The table has no index with this ID, and no values are ever written to
+	// the span; it only holds locks, which belong with the sessions that own
+	// them.
+	SqllivenessAdvisoryLocksIndexID = 1000
+
 	// Reserved IDs for other system tables. Note that some of these IDs refer
 	// to "Ranges" instead of a Table - these IDs are needed to store custom
 	// configuration for non-table ranges (e.g. Zone Configs).
@@ -140,6 +140,16 @@ func rangeFeedValue(key roachpb.Key, val roachpb.Value) *kvpb.RangeFeedEvent {
 	return rangeFeedValueWithPrev(key, val, roachpb.Value{})
 }
 
+func rangeFeedValueWithTxnID(
+	key roachpb.Key, val roachpb.Value, txnID uuid.UUID,
+) *kvpb.RangeFeedEvent {
+	return makeRangeFeedEvent(&kvpb.RangeFeedValue{
+		Key:   key,
+		Value: val,
+		TxnID: txnID,
+	})
+}
+
 func rangeFeedCheckpoint(span roachpb.Span, ts hlc.Timestamp) *kvpb.RangeFeedEvent {
 	return makeRangeFeedEvent(&kvpb.RangeFeedCheckpoint{
 		Span:       span,
@@ -520,12 +530,13 @@ func TestProcessorBasic(t *testing.T) {
 	h.syncEventAndRegistrations()
 	require.Equal(t,
 		[]*kvpb.RangeFeedEvent{
-			rangeFeedValue(
+			rangeFeedValueWithTxnID(
 				roachpb.Key("e"),
 				roachpb.Value{
 					RawBytes:  []byte("ival"),
 					Timestamp: hlc.Timestamp{WallTime: 13},
 				},
+				txn2,
 			),
 			rangeFeedCheckpoint(
 				roachpb.Span{Key: roachpb.Key("a"), EndKey: roachpb.Key("m")},
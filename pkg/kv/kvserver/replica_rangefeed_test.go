@@ -40,6 +40,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/require"
 	"go.etcd.io/raft/v3"
@@ -305,10 +306,12 @@ func TestReplicaRangefeed(t *testing.T) {
 	}
 	// Insert a second key transactionally.
 	ts3 := initTime.Add(0, 3)
+	var txn3ID uuid.UUID
 	if err := store1.DB().Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
 		if err := txn.SetFixedTimestamp(ctx, ts3); err != nil {
 			return err
 		}
+		txn3ID = txn.ID()
 		return txn.Put(ctx, roachpb.Key("m"), []byte("val3"))
 	}); err != nil {
 		t.Fatal(err)
@@ -327,10 +330,12 @@ func TestReplicaRangefeed(t *testing.T) {
 
 	// Update the originally incremented key transactionally.
 	ts5 := initTime.Add(0, 5)
+	var txn5ID uuid.UUID
 	if err := store1.DB().Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
 		if err := txn.SetFixedTimestamp(ctx, ts5); err != nil {
 			return err
 		}
+		txn5ID = txn.ID()
 		_, err := txn.Inc(ctx, incArgs.Key, 7)
 		return err
 	}); err != nil {
@@ -456,6 +461,19 @@ func TestReplicaRangefeed(t *testing.T) {
 	_, err = kv.SendWrappedWith(ctx, db, kvpb.Header{Timestamp: ts13}, pArgs)
 	require.Nil(t, err)
 
+	// Insert a key transactionally with 1PC.
+	ts14 := initTime.Add(0, 14)
+	var txn14ID uuid.UUID
+	pErr = store1.DB().Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		pErr = txn.SetFixedTimestamp(ctx, ts14)
+		require.Nil(t, pErr)
+		txn14ID = txn.ID()
+		b := txn.NewBatch()
+		b.Put(roachpb.Key("p"), []byte("val14"))
+		return txn.CommitInBatch(ctx, b)
+	})
+	require.Nil(t, pErr)
+
 	// Wait for all streams to observe the expected events.
 	expVal2 := roachpb.MakeValueFromBytesAndTimestamp([]byte("val2"), ts2)
 	expVal3 := roachpb.MakeValueFromBytesAndTimestamp([]byte("val3"), ts3)
@@ -476,18 +494,20 @@ func TestReplicaRangefeed(t *testing.T) {
 	expVal12NoTS := expVal12
 	expVal12NoTS.Timestamp = hlc.Timestamp{}
 	expVal13 := roachpb.MakeValueFromBytesAndTimestamp([]byte("val13"), ts13)
+	expVal14 := roachpb.MakeValueFromBytesAndTimestamp([]byte("val14"), ts14)
+	expVal14.InitChecksum([]byte("p")) // kv.Txn sets value checksum
 	expEvents = append(expEvents, []*kvpb.RangeFeedEvent{
 		{Val: &kvpb.RangeFeedValue{
 			Key: roachpb.Key("c"), Value: expVal2,
 		}},
 		{Val: &kvpb.RangeFeedValue{
-			Key: roachpb.Key("m"), Value: expVal3,
+			Key: roachpb.Key("m"), Value: expVal3, TxnID: txn3ID,
 		}},
 		{Val: &kvpb.RangeFeedValue{
 			Key: roachpb.Key("b"), Value: expVal4, PrevValue: expVal1NoTS,
 		}},
 		{Val: &kvpb.RangeFeedValue{
-			Key: roachpb.Key("b"), Value: expVal5, PrevValue: expVal4NoTS,
+			Key: roachpb.Key("b"), Value: expVal5, PrevValue: expVal4NoTS, TxnID: txn5ID,
 		}},
 		{SST: &kvpb.RangeFeedSSTable{
 			// Binary representation of Data may be modified by SST rewrite, see checkForExpEvents.
@@ -510,6 +530,10 @@ func TestReplicaRangefeed(t *testing.T) {
 			// val2 as a previous value of the next event.
 			Key: roachpb.Key("o"), Value: expVal13, PrevValue: expVal12NoTS,
 		}},
+		{Val: &kvpb.RangeFeedValue{
+			// 1PC writes are attributed to their transaction as well.
+			Key: roachpb.Key("p"), Value: expVal14, TxnID: txn14ID,
+		}},
 	}...)
 	// here
 	checkForExpEvents(expEvents)
@@ -580,6 +580,16 @@ func (r *Replica) evaluate1PC(
 		batch = r.store.TODOEngine().NewBatch()
 		ms.Reset()
 	} else {
+		// The stripped batch was evaluated without the transaction, so its
+		// writes were logged as non-transactional. Attribute them to the
+		// transaction for rangefeeds.
+		if res.LogicalOpLog != nil {
+			for _, op := range res.LogicalOpLog.Ops {
+				if wv, ok := op.GetValue().(*enginepb.MVCCWriteValueOp); ok {
+					wv.TxnID = ba.Txn.ID
+				}
+			}
+		}
 		// Run commit trigger manually.
 		innerResult, err := batcheval.RunCommitTrigger(ctx, rec, batch, ms, etArg, clonedTxn)
 		if err != nil {
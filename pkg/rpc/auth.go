@@ -412,6 +412,57 @@ func checkRootOrNodeInScope(
 		serverTenantID, security.FormatUserScopes(certUserScope))
 }
 
+// CheckNodeCaller returns an error unless the RPC handled with the given
+// context was sent by a node of the cluster. The RPC authentication accepts
+// the root client cert, which is also used by the CLI and by the gRPC gateway
+// on behalf of HTTP clients; this check can be used by handlers for RPCs
+// that are only meant to be sent by the servers themselves.
+//
+// Direct calls that don't go through gRPC, requests made through the
+// internal client adapter, and requests from the SQL servers of the same
+// tenant are allowed, as are all requests on insecure servers.
+func (rpcCtx *Context) CheckNodeCaller(ctx context.Context) error {
+	if rpcCtx.ContextOptions.Insecure {
+		return nil
+	}
+	return checkNodeCaller(ctx, rpcCtx.tenID)
+}
+
+func checkNodeCaller(ctx context.Context, serverTenantID roachpb.TenantID) error {
+	if _, localRequest := grpcutil.IsLocalRequestContext(ctx); localRequest {
+		return nil
+	}
+	if _, ok := grpcpeer.FromContext(ctx); !ok {
+		// Not a gRPC request.
+		return nil
+	}
+	if tenID, ok := roachpb.ClientTenantFromContext(ctx); ok {
+		if tenID == serverTenantID {
+			// A SQL server of the same tenant.
+			return nil
+		}
+		// A tenant server calling into the KV layer.
+		return status.Errorf(codes.PermissionDenied,
+			"need node client cert to perform this RPC (caller is tenant %v)", tenID)
+	}
+	clientCert, err := getClientCert(ctx)
+	if err != nil {
+		return err
+	}
+	certUserScope, err := security.GetCertificateUserScope(clientCert)
+	if err != nil {
+		return err
+	}
+	for _, scope := range certUserScope {
+		if (scope.Global || scope.TenantID == serverTenantID) && scope.Username == username.NodeUser {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied,
+		"need node client cert to perform this RPC (cert is valid for %s)",
+		security.FormatUserScopes(certUserScope))
+}
+
 // contextForRequest sets up the context.Context for use by
 // the API handler. It covers two cases:
 //
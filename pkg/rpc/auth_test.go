@@ -213,6 +213,62 @@ func TestAuthenticateTenant(t *testing.T) {
 	}
 }
 
+func TestCheckNodeCaller(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	correctOU := []string{security.TenantsOU}
+	stid := roachpb.SystemTenantID
+	tenTen := roachpb.MustMakeTenantID(10)
+	for _, tc := range []struct {
+		systemID         roachpb.TenantID
+		ous              []string
+		commonName       string
+		clientTenantInMD string
+		expErr           string
+	}{
+		{systemID: stid, commonName: "node"},
+		{systemID: stid, commonName: "root",
+			expErr: `need node client cert to perform this RPC \(cert is valid for "root" on all tenants\)`},
+		{systemID: stid, ous: correctOU, commonName: "10",
+			expErr: `need node client cert to perform this RPC \(caller is tenant 10\)`},
+		{systemID: stid, commonName: "node", clientTenantInMD: "10",
+			expErr: `need node client cert to perform this RPC \(caller is tenant 10\)`},
+		{systemID: tenTen, commonName: "node"},
+		{systemID: tenTen, commonName: "root",
+			expErr: `need node client cert to perform this RPC \(cert is valid for "root" on all tenants\)`},
+		{systemID: tenTen, ous: correctOU, commonName: "10"},
+		{systemID: tenTen, commonName: "node", clientTenantInMD: "10"},
+	} {
+		t.Run(fmt.Sprintf("from %v to %v (md %q)", tc.commonName, tc.systemID, tc.clientTenantInMD), func(t *testing.T) {
+			cert := &x509.Certificate{
+				Subject: pkix.Name{
+					CommonName:         tc.commonName,
+					OrganizationalUnit: tc.ous,
+				},
+			}
+			tlsInfo := credentials.TLSInfo{
+				State: tls.ConnectionState{
+					PeerCertificates: []*x509.Certificate{cert},
+				},
+			}
+			p := peer.Peer{AuthInfo: tlsInfo}
+			ctx := peer.NewContext(context.Background(), &p)
+			if tc.clientTenantInMD != "" {
+				md := metadata.MD{"client-tid": []string{tc.clientTenantInMD}}
+				ctx = metadata.NewIncomingContext(ctx, md)
+			}
+
+			err := rpc.TestingCheckNodeCaller(ctx, tc.systemID)
+			if tc.expErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Equal(t, codes.PermissionDenied, status.Code(err))
+				require.Regexp(t, tc.expErr, err)
+			}
+		})
+	}
+}
+
 func prefix(tenID uint64, key string) string {
 	tenPrefix := keys.MakeTenantPrefix(roachpb.MustMakeTenantID(tenID))
 	return string(append(tenPrefix, []byte(key)...))
@@ -53,6 +53,16 @@ func TestingAuthenticateTenant(
 	}
 }
 
+// TestingCheckNodeCaller authenticates the request from a context and checks
+// whether it was sent by a node, for testing.
+func TestingCheckNodeCaller(ctx context.Context, serverTenantID roachpb.TenantID) error {
+	authnRes, err := kvAuth{tenant: tenantAuthorizer{tenantID: serverTenantID}}.authenticate(ctx)
+	if err != nil {
+		return err
+	}
+	return checkNodeCaller(contextForRequest(ctx, authnRes), serverTenantID)
+}
+
 // TestingAuthorizeTenantRequest performs authorization of a tenant request
 // for testing.
 func TestingAuthorizeTenantRequest(
@@ -93,6 +93,19 @@ func ForwardHTTPAuthInfoToRPCCalls(ctx context.Context, r *http.Request) context
 	return metadata.NewOutgoingContext(ctx, md)
 }
 
+// HasSQLIdentityInIncomingRPCContext returns whether the incoming RPC
+// context carries the SQL identity of an HTTP API client, as populated by
+// TranslateHTTPAuthInfoToGRPCMetadata() or
+// ForwardSQLIdentityThroughRPCCalls().
+func HasSQLIdentityInIncomingRPCContext(ctx context.Context) bool {
+	md, ok := grpcutil.FastFromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	_, ok = md[webSessionUserKeyStr]
+	return ok
+}
+
 // UserFromIncomingRPCContext is to be used in RPC API handlers. It
 // assumes the SQL identity was populated in the context implicitly by
 // gRPC via translateHTTPAuthInfoToGRPCMetadata(), or explicitly via
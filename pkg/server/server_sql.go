@@ -962,6 +962,9 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*SQLServer, error) {
 		NodesStatusServer:       cfg.nodesStatusServer,
 		SQLStatusServer:         cfg.sqlStatusServer,
 		SessionRegistry:         cfg.sessionRegistry,
+		NotificationRegistry:    sql.NewNotificationRegistry(cfg.Settings),
+		AdvisoryLockRegistry:    sql.NewAdvisoryLockRegistry(cfg.Settings, cfg.sqlStatusServer),
+		ReplicationSlotRegistry: sql.NewReplicationSlotRegistry(),
 		ClosedSessionCache:      cfg.closedSessionCache,
 		ContentionRegistry:      contentionRegistry,
 		SQLLiveness:             cfg.sqlLivenessProvider,
@@ -1477,6 +1480,9 @@ func (s *SQLServer) preStart(
 	s.leaseMgr.SetRegionPrefix(regionPhysicalRep)
 
 	s.execCfg.ContentionRegistry.Start(ctx, stopper)
+	if err := s.execCfg.NotificationRegistry.Start(ctx, stopper, s.execCfg.SQLStatusServer); err != nil {
+		return err
+	}
 
 	// Start the sql liveness subsystem. We'll need it to get a session.
 	s.sqlLivenessProvider.Start(ctx, regionPhysicalRep)
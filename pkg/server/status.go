@@ -3430,6 +3430,69 @@ func (s *statusServer) CancelQueryByKey(
 	return client.CancelQueryByKey(ctx, req)
 }
 
+// Notify delivers the notifications sent by a committed transaction to the
+// sessions listening on their channels. Like NOTIFY itself, sending a
+// notification doesn't require any privileges, so the endpoint can only be
+// called by the SQL layer of the servers themselves, which have already
+// checked that the notifications were sent by a committed transaction. When
+// the notifications are delivered on all nodes, the nodes that could not be
+// reached are reported in the response, so that the caller can retry them.
+func (s *statusServer) Notify(
+	ctx context.Context, req *serverpb.NotifyRequest,
+) (*serverpb.NotifyResponse, error) {
+	ctx = s.AnnotateCtx(ctx)
+
+	if authserver.HasSQLIdentityInIncomingRPCContext(ctx) {
+		return nil, status.Errorf(codes.PermissionDenied,
+			"notifications cannot be sent through the HTTP API")
+	}
+	if err := s.rpcCtx.CheckNodeCaller(ctx); err != nil {
+		return nil, err
+	}
+
+	response := &serverpb.NotifyResponse{}
+	localReq := &serverpb.NotifyRequest{
+		NodeID:        "local",
+		Notifications: req.Notifications,
+	}
+
+	if len(req.NodeID) > 0 {
+		requestedNodeID, local, err := s.parseNodeID(req.NodeID)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+		if local {
+			s.sqlServer.execCfg.NotificationRegistry.Deliver(ctx, req.Notifications)
+			return response, nil
+		}
+		statusClient, err := s.dialNode(ctx, requestedNodeID)
+		if err != nil {
+			return nil, err
+		}
+		return statusClient.Notify(ctx, localReq)
+	}
+
+	nodeFn := func(ctx context.Context, statusClient serverpb.StatusClient, _ roachpb.NodeID) (*serverpb.NotifyResponse, error) {
+		return statusClient.Notify(ctx, localReq)
+	}
+	if err := iterateNodes(ctx, s.serverIterator, s.stopper, "deliver notifications",
+		noTimeout,
+		s.dialNode,
+		nodeFn,
+		func(nodeID roachpb.NodeID, resp *serverpb.NotifyResponse) {
+			// Nothing to do here.
+		},
+		func(nodeID roachpb.NodeID, nodeFnError error) {
+			// The caller retries the nodes that failed.
+			log.VInfof(ctx, 1, "failed to deliver notifications to n%d: %v", nodeID, nodeFnError)
+			response.FailedNodeIDs = append(response.FailedNodeIDs, nodeID)
+		},
+	); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
 // ListContentionEvents returns a list of contention events on all nodes in the
 // cluster.
 func (s *statusServer) ListContentionEvents(
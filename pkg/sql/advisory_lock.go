@@ -0,0 +1,985 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/clusterunique"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+)
+
+// Advisory locks are implemented with KV intents, so that they conflict
+// across all the nodes of the cluster. A session acquires each lock in a
+// dedicated KV transaction which is kept open (and heartbeated) until the
+// session releases the lock, at which point the transaction is rolled back.
+// The transaction never commits, so the keys it writes are never materialized:
+// its intents are removed when it is rolled back, or by intent resolution once
+// it is abandoned, for instance if the node holding the lock crashes.
+//
+// An exclusive lock is an intent on the lock's key. A shared lock is an intent
+// on a key that is unique to the transaction, under the lock's key. To acquire
+// a lock, a transaction first writes its intent, and then waits for the
+// conflicting intents of other transactions: an exclusive lock waits for all
+// the intents under the lock's key, and a shared lock only waits for the
+// intent on the lock's key. Since each side writes before it reads, two
+// concurrent acquisitions in conflicting modes can't both miss each other; if
+// they end up waiting for each other, KV's deadlock detection aborts one of
+// the transactions and the acquisition is retried.
+//
+// A session's own acquisitions never conflict with each other: repeated
+// acquisitions of a lock are counted, and the lock is only released once
+// every acquisition has been released, like in Postgres.
+//
+// Deadlock detection
+//
+// KV detects deadlocks between transactions: a transaction that waits for a
+// lock pushes the lock holder, and the txnwait queue of the pushee's record
+// finds cycles of pushes. It can't detect deadlocks between sessions that
+// hold some advisory locks while waiting for others, because each lock is
+// held by a different transaction and the transaction that waits holds
+// nothing: if session 1 holds lock A in txn1 and waits for lock B in txn2,
+// while session 2 holds B in txn3 and waits for A in txn4, KV sees txn2
+// waiting for txn3 and txn4 waiting for txn1, which is not a cycle. Holding
+// all of a session's locks in a single transaction would let KV see the
+// cycle, but locks couldn't then be released individually.
+//
+// Instead, the AdvisoryLockRegistry checks for cycles between the sessions on
+// its node before a session starts waiting, and fails the acquisition that
+// would close a cycle with a deadlock error, like Postgres. The check is exact
+// for sessions on the same node, since the registry sees their locks and
+// waits under its mutex.
+//
+// Deadlocks that involve sessions on different nodes are detected while the
+// sessions wait: every sql.advisory_lock.deadlock_detection_interval, the
+// registry of each node that has waiting sessions builds the wait-for graph of
+// the cluster from the locks reported by the session registries of all the
+// nodes (the same data as crdb_internal.cluster_advisory_locks), and looks for
+// a cycle through each of its waiting sessions. A single listing of the
+// cluster's sessions is shared by all the waiting sessions of the node. Since
+// the registries of the nodes aren't read atomically, a cycle is only reported
+// once it has been seen by two consecutive checks. Every node with a session
+// in the cycle finds it, so only the session with the greatest ID, the victim,
+// fails its acquisition with a deadlock error, which lets the other sessions
+// in the cycle make progress.
+
+// advisoryLockDeadlockDetectionInterval is the interval at which each node
+// checks its sessions waiting for advisory locks for deadlocks with sessions
+// on other nodes.
+var advisoryLockDeadlockDetectionInterval = settings.RegisterDurationSetting(
+	settings.ApplicationLevel,
+	"sql.advisory_lock.deadlock_detection_interval",
+	"interval at which each node checks its sessions waiting for advisory locks "+
+		"for deadlocks with sessions on other nodes (0 disables the check)",
+	time.Second,
+	settings.NonNegativeDuration,
+)
+
+// advisoryLockID identifies an advisory lock. As in Postgres, advisory locks
+// are scoped to a database.
+type advisoryLockID struct {
+	databaseID descpb.ID
+	key        eval.AdvisoryLockKey
+}
+
+// advisoryLockKey returns the KV key that represents the advisory lock. The
+// keys of advisory locks are under a span of the sqlliveness table that is not
+// used by any of its indexes, so they neither use up a reserved descriptor ID
+// nor overlap with the keyspace of a user table.
+func advisoryLockKey(codec keys.SQLCodec, id advisoryLockID) roachpb.Key {
+	k := codec.IndexPrefix(keys.SqllivenessID, keys.SqllivenessAdvisoryLocksIndexID)
+	k = encoding.EncodeUvarintAscending(k, uint64(id.databaseID))
+	k = encoding.EncodeUvarintAscending(k, uint64(id.key.ObjSubID))
+	k = encoding.EncodeUvarintAscending(k, uint64(id.key.ClassID))
+	return encoding.EncodeUvarintAscending(k, uint64(id.key.ObjID))
+}
+
+// advisoryLockSharedKey returns the key used by txn to hold a shared lock on
+// the advisory lock with the given key.
+func advisoryLockSharedKey(key roachpb.Key, txn *kv.Txn) roachpb.Key {
+	return encoding.EncodeBytesAscending(key.Clone(), txn.ID().GetBytes())
+}
+
+// heldAdvisoryLock is an advisory lock held by a session.
+type heldAdvisoryLock struct {
+	// txn is the KV transaction holding the lock in the mode it was first
+	// acquired in.
+	txn *kv.Txn
+	// upgradeTxn is the KV transaction holding the exclusive lock, if the lock
+	// was first acquired in shared mode and then upgraded.
+	upgradeTxn *kv.Txn
+	// exclusive is set if the session holds an exclusive lock rather than a
+	// shared one. Once a lock is upgraded, it stays exclusive until it is
+	// released.
+	exclusive bool
+	// sessionCounts and xactCounts are the numbers of session-level and
+	// transaction-level acquisitions of the lock, indexed by mode.
+	sessionCounts [2]int
+	xactCounts    [2]int
+}
+
+func (l *heldAdvisoryLock) held(mode eval.AdvisoryLockMode) bool {
+	return l.sessionCounts[mode] > 0 || l.xactCounts[mode] > 0
+}
+
+func (l *heldAdvisoryLock) released() bool {
+	return !l.held(eval.AdvisoryLockExclusive) && !l.held(eval.AdvisoryLockShared)
+}
+
+// advisoryLockWait describes an advisory lock a session is waiting for.
+type advisoryLockWait struct {
+	id   advisoryLockID
+	mode eval.AdvisoryLockMode
+}
+
+// advisoryLockWaiter is a session waiting for an advisory lock, as part of a
+// cycle of sessions waiting for each other.
+type advisoryLockWaiter struct {
+	// sessionID is the ID of the session, as bytes.
+	sessionID string
+	pid       uint32
+	wait      advisoryLockWait
+}
+
+// newAdvisoryLockDeadlockError returns the error of an acquisition that would
+// wait forever because of the given cycle of sessions. Each session in the
+// cycle is blocked by the next one, and the last one by the first one.
+func newAdvisoryLockDeadlockError(cycle []advisoryLockWaiter) error {
+	var buf strings.Builder
+	for i, u := range cycle {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		fmt.Fprintf(&buf,
+			"Process %d waits for %s on advisory lock [%d,%d,%d,%d]; blocked by process %d.",
+			u.pid, u.wait.mode, u.wait.id.databaseID, u.wait.id.key.ClassID, u.wait.id.key.ObjID,
+			u.wait.id.key.ObjSubID, cycle[(i+1)%len(cycle)].pid)
+	}
+	return errors.WithDetail(pgerror.New(pgcode.DeadlockDetected, "deadlock detected"), buf.String())
+}
+
+// advisoryLockSession tracks the advisory locks held by a session. It is only
+// modified by the session's goroutine, which can read it without locking; the
+// mutex protects it from the readers of the registry.
+type advisoryLockSession struct {
+	registry  *AdvisoryLockRegistry
+	db        *kv.DB
+	codec     keys.SQLCodec
+	sessionID clusterunique.ID
+	pid       uint32
+
+	mu struct {
+		syncutil.Mutex
+		locks map[advisoryLockID]*heldAdvisoryLock
+		// waiting is set while the session waits for a lock.
+		waiting *advisoryLockWait
+	}
+}
+
+func newAdvisoryLockSession(
+	registry *AdvisoryLockRegistry,
+	db *kv.DB,
+	codec keys.SQLCodec,
+	sessionID clusterunique.ID,
+	pid uint32,
+) *advisoryLockSession {
+	s := &advisoryLockSession{
+		registry:  registry,
+		db:        db,
+		codec:     codec,
+		sessionID: sessionID,
+		pid:       pid,
+	}
+	s.mu.locks = make(map[advisoryLockID]*heldAdvisoryLock)
+	registry.register(s)
+	return s
+}
+
+// acquire acquires the advisory lock in the given mode. If wait is false, it
+// returns false rather than waiting for a conflicting lock held by another
+// session.
+func (s *advisoryLockSession) acquire(
+	ctx context.Context,
+	id advisoryLockID,
+	mode eval.AdvisoryLockMode,
+	xact bool,
+	wait bool,
+	lockTimeout time.Duration,
+) (bool, error) {
+	l, ok := s.mu.locks[id]
+	if !ok {
+		txn, acquired, err := s.lockWithRetry(ctx, id, mode, wait, lockTimeout, nil /* sharedTxn */)
+		if !acquired || err != nil {
+			return false, err
+		}
+		l = &heldAdvisoryLock{txn: txn, exclusive: mode == eval.AdvisoryLockExclusive}
+	} else if mode == eval.AdvisoryLockExclusive && !l.exclusive {
+		// Upgrade the lock in another transaction, so that the shared lock is
+		// kept if the upgrade fails.
+		txn, acquired, err := s.lockWithRetry(ctx, id, mode, wait, lockTimeout, l.txn)
+		if !acquired || err != nil {
+			return false, err
+		}
+		s.mu.Lock()
+		l.upgradeTxn = txn
+		l.exclusive = true
+		s.mu.Unlock()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if xact {
+		l.xactCounts[mode]++
+	} else {
+		l.sessionCounts[mode]++
+	}
+	s.mu.locks[id] = l
+	return true, nil
+}
+
+// lockWithRetry acquires the KV lock on the advisory lock's key in a new
+// transaction, which it returns if the lock is acquired. If the transaction is
+// aborted, for instance to break a deadlock with a concurrent acquisition, the
+// acquisition is retried in another transaction. If sharedTxn is set, it holds
+// a shared lock which is being upgraded and doesn't conflict with the lock.
+func (s *advisoryLockSession) lockWithRetry(
+	ctx context.Context,
+	id advisoryLockID,
+	mode eval.AdvisoryLockMode,
+	wait bool,
+	lockTimeout time.Duration,
+	sharedTxn *kv.Txn,
+) (_ *kv.Txn, acquired bool, _ error) {
+	for {
+		txn := s.db.NewTxn(ctx, "advisory lock")
+		acquired, err := s.lock(ctx, txn, id, mode, wait, lockTimeout, sharedTxn)
+		if acquired && err == nil {
+			return txn, true, nil
+		}
+		s.rollback(ctx, txn)
+		if !errors.HasType(err, (*kvpb.TransactionRetryWithProtoRefreshError)(nil)) {
+			return nil, false, err
+		}
+	}
+}
+
+// lock acquires the KV lock on the advisory lock's key in txn. The intents of
+// sharedTxn, if set, are ignored.
+func (s *advisoryLockSession) lock(
+	ctx context.Context,
+	txn *kv.Txn,
+	id advisoryLockID,
+	mode eval.AdvisoryLockMode,
+	wait bool,
+	lockTimeout time.Duration,
+	sharedTxn *kv.Txn,
+) (bool, error) {
+	key := advisoryLockKey(s.codec, id)
+	// Write the intent that represents the lock, and then wait for the
+	// conflicting intents of other transactions.
+	write, read := txn.NewBatch(), txn.NewBatch()
+	if mode == eval.AdvisoryLockExclusive {
+		write.Put(key, true)
+		if sharedTxn != nil {
+			sharedKey := advisoryLockSharedKey(key, sharedTxn)
+			read.ScanForUpdate(key.Next(), sharedKey, kvpb.BestEffort)
+			read.ScanForUpdate(sharedKey.Next(), key.PrefixEnd(), kvpb.BestEffort)
+		} else {
+			read.ScanForUpdate(key.Next(), key.PrefixEnd(), kvpb.BestEffort)
+		}
+	} else {
+		write.Put(advisoryLockSharedKey(key, txn), true)
+		read.GetForShare(key, kvpb.BestEffort)
+	}
+	if wait {
+		if err := s.registry.startWaiting(s, &advisoryLockWait{id: id, mode: mode}); err != nil {
+			return false, err
+		}
+		defer s.setWaiting(nil)
+		// Deadlocks with sessions on other nodes cancel the wait with a
+		// deadlock error as the cause.
+		var cancel context.CancelCauseFunc
+		ctx, cancel = context.WithCancelCause(ctx)
+		defer cancel(nil)
+		defer s.registry.detectDeadlocks(s, cancel)()
+	}
+	for _, b := range []*kv.Batch{write, read} {
+		if wait {
+			b.Header.LockTimeout = lockTimeout
+		} else {
+			b.Header.WaitPolicy = lock.WaitPolicy_Error
+		}
+		err := txn.Run(ctx, b)
+		if cause := context.Cause(ctx); err != nil && cause != nil &&
+			pgerror.GetPGCode(cause) == pgcode.DeadlockDetected {
+			return false, cause
+		}
+		var wiErr *kvpb.WriteIntentError
+		if errors.As(err, &wiErr) {
+			if !wait {
+				return false, nil
+			}
+			if wiErr.Reason == kvpb.WriteIntentError_REASON_LOCK_TIMEOUT {
+				return false, pgerror.New(pgcode.LockNotAvailable,
+					"canceling statement due to lock timeout on advisory lock")
+			}
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// findClusterWaitCycle returns the sessions of the cluster that wait for each
+// other through the session with the given ID, starting with that session, or
+// nil if its wait can't deadlock. Each session in the returned cycle is
+// blocked by the next one, and the last one is blocked by the first one. The
+// sessions must be sorted by ID, so that consecutive checks find the same
+// cycle.
+func findClusterWaitCycle(sessions []serverpb.Session, self []byte) []advisoryLockWaiter {
+	visited := make(map[string]struct{})
+	var visit func(waiter *serverpb.Session, w advisoryLockWait) []advisoryLockWaiter
+	visit = func(waiter *serverpb.Session, w advisoryLockWait) []advisoryLockWaiter {
+		u := advisoryLockWaiter{sessionID: string(waiter.ID), pid: waiter.PGBackendPID, wait: w}
+		for i := range sessions {
+			t := &sessions[i]
+			if bytes.Equal(t.ID, waiter.ID) || !advisoryLockBlocks(t, w) {
+				continue
+			}
+			if bytes.Equal(t.ID, self) {
+				return []advisoryLockWaiter{u}
+			}
+			if _, ok := visited[string(t.ID)]; ok {
+				continue
+			}
+			visited[string(t.ID)] = struct{}{}
+			if tw, ok := advisoryLockWaitingFor(t); ok {
+				if cycle := visit(t, tw); cycle != nil {
+					return append([]advisoryLockWaiter{u}, cycle...)
+				}
+			}
+		}
+		return nil
+	}
+	for i := range sessions {
+		if bytes.Equal(sessions[i].ID, self) {
+			if w, ok := advisoryLockWaitingFor(&sessions[i]); ok {
+				return visit(&sessions[i], w)
+			}
+		}
+	}
+	return nil
+}
+
+// advisoryLockWaitingFor returns the advisory lock a session of the cluster is
+// waiting for, if any.
+func advisoryLockWaitingFor(sess *serverpb.Session) (advisoryLockWait, bool) {
+	for _, l := range sess.AdvisoryLocks {
+		if !l.Granted {
+			mode := eval.AdvisoryLockShared
+			if l.Exclusive {
+				mode = eval.AdvisoryLockExclusive
+			}
+			return advisoryLockWait{id: advisoryLockIDFromProto(l), mode: mode}, true
+		}
+	}
+	return advisoryLockWait{}, false
+}
+
+// advisoryLockBlocks returns whether a session of the cluster holds a lock
+// that conflicts with the given wait of another session.
+func advisoryLockBlocks(sess *serverpb.Session, w advisoryLockWait) bool {
+	for _, l := range sess.AdvisoryLocks {
+		if l.Granted && advisoryLockIDFromProto(l) == w.id &&
+			(w.mode == eval.AdvisoryLockExclusive || l.Exclusive) {
+			return true
+		}
+	}
+	return false
+}
+
+func advisoryLockIDFromProto(l serverpb.AdvisoryLock) advisoryLockID {
+	return advisoryLockID{
+		databaseID: descpb.ID(l.DatabaseID),
+		key: eval.AdvisoryLockKey{
+			ClassID:  l.ClassID,
+			ObjID:    l.ObjID,
+			ObjSubID: uint16(l.ObjSubID),
+		},
+	}
+}
+
+func equalAdvisoryLockCycles(a, b []advisoryLockWaiter) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// isAdvisoryLockVictim returns whether the first session of the cycle, which
+// is the session that found it, has the greatest ID of the cycle. Every
+// session in a cycle finds it, and only the victim fails its acquisition.
+func isAdvisoryLockVictim(cycle []advisoryLockWaiter) bool {
+	for _, u := range cycle[1:] {
+		if u.sessionID > cycle[0].sessionID {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *advisoryLockSession) setWaiting(w *advisoryLockWait) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.waiting = w
+}
+
+// waitingFor returns the advisory lock the session is waiting for, if any.
+func (s *advisoryLockSession) waitingFor() *advisoryLockWait {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mu.waiting
+}
+
+// blocks returns whether the session holds a lock that conflicts with the
+// given wait of another session.
+func (s *advisoryLockSession) blocks(w *advisoryLockWait) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.mu.locks[w.id]
+	return ok && (w.mode == eval.AdvisoryLockExclusive || l.exclusive)
+}
+
+// release releases a session-level acquisition of the advisory lock in the
+// given mode. It returns false if the session doesn't hold the lock.
+func (s *advisoryLockSession) release(
+	ctx context.Context, id advisoryLockID, mode eval.AdvisoryLockMode,
+) bool {
+	l, ok := s.mu.locks[id]
+	if !ok || l.sessionCounts[mode] == 0 {
+		return false
+	}
+	s.mu.Lock()
+	l.sessionCounts[mode]--
+	s.mu.Unlock()
+	s.maybeReleaseLock(ctx, id, l)
+	return true
+}
+
+// releaseAll releases the session-level acquisitions of all advisory locks.
+func (s *advisoryLockSession) releaseAll(ctx context.Context) {
+	for id, l := range s.mu.locks {
+		s.mu.Lock()
+		l.sessionCounts = [2]int{}
+		s.mu.Unlock()
+		s.maybeReleaseLock(ctx, id, l)
+	}
+}
+
+// releaseXact releases the transaction-level acquisitions of all advisory
+// locks. It is called when a transaction finishes or restarts.
+func (s *advisoryLockSession) releaseXact(ctx context.Context) {
+	for id, l := range s.mu.locks {
+		s.mu.Lock()
+		l.xactCounts = [2]int{}
+		s.mu.Unlock()
+		s.maybeReleaseLock(ctx, id, l)
+	}
+}
+
+// maybeReleaseLock releases the KV lock of the advisory lock if the session no
+// longer holds it.
+func (s *advisoryLockSession) maybeReleaseLock(
+	ctx context.Context, id advisoryLockID, l *heldAdvisoryLock,
+) {
+	if !l.released() {
+		return
+	}
+	s.mu.Lock()
+	delete(s.mu.locks, id)
+	s.mu.Unlock()
+	if l.upgradeTxn != nil {
+		s.rollback(ctx, l.upgradeTxn)
+	}
+	s.rollback(ctx, l.txn)
+}
+
+// rollback rolls back a transaction used to hold an advisory lock. Releasing a
+// lock is not cancelable, so the rollback doesn't use the statement's context.
+func (s *advisoryLockSession) rollback(ctx context.Context, txn *kv.Txn) {
+	ctx = logtags.AddTags(context.Background(), logtags.FromContext(ctx))
+	if err := txn.Rollback(ctx); err != nil {
+		log.Warningf(ctx, "error releasing advisory lock: %v", err)
+	}
+}
+
+// close releases all the advisory locks held by the session.
+func (s *advisoryLockSession) close(ctx context.Context) {
+	for id, l := range s.mu.locks {
+		s.mu.Lock()
+		l.sessionCounts = [2]int{}
+		l.xactCounts = [2]int{}
+		s.mu.Unlock()
+		s.maybeReleaseLock(ctx, id, l)
+	}
+	s.registry.deregister(s)
+}
+
+// AdvisoryLockRegistry keeps track of the sessions on this node that hold
+// advisory locks, for introspection and deadlock detection.
+type AdvisoryLockRegistry struct {
+	settings *cluster.Settings
+	// status lists the sessions of the cluster, to detect deadlocks with
+	// sessions on other nodes. If it is nil, such deadlocks aren't detected.
+	status serverpb.SQLStatusServer
+
+	mu struct {
+		syncutil.Mutex
+		sessions map[*advisoryLockSession]struct{}
+		// detecting holds the sessions on this node that wait for an advisory
+		// lock and are checked for deadlocks with sessions on other nodes.
+		detecting map[*advisoryLockSession]*advisoryLockDetection
+		// stopDetector stops the deadlock detector goroutine. It is set while
+		// the goroutine runs, which is only while detecting is not empty.
+		stopDetector context.CancelFunc
+	}
+}
+
+// advisoryLockDetection is a session checked for deadlocks with sessions on
+// other nodes by the deadlock detector of the registry.
+type advisoryLockDetection struct {
+	// cancel cancels the session's wait with a deadlock error as the cause.
+	cancel context.CancelCauseFunc
+	// prev is the cycle found by the previous check.
+	prev []advisoryLockWaiter
+}
+
+// NewAdvisoryLockRegistry creates a new AdvisoryLockRegistry with no sessions.
+func NewAdvisoryLockRegistry(
+	settings *cluster.Settings, status serverpb.SQLStatusServer,
+) *AdvisoryLockRegistry {
+	r := &AdvisoryLockRegistry{settings: settings, status: status}
+	r.mu.sessions = make(map[*advisoryLockSession]struct{})
+	r.mu.detecting = make(map[*advisoryLockSession]*advisoryLockDetection)
+	return r
+}
+
+func (r *AdvisoryLockRegistry) register(s *advisoryLockSession) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.sessions[s] = struct{}{}
+}
+
+func (r *AdvisoryLockRegistry) deregister(s *advisoryLockSession) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.mu.sessions, s)
+}
+
+// startWaiting records that the session is about to wait for an advisory
+// lock. It returns an error instead if the session would then wait, directly
+// or through other sessions on this node, for itself.
+func (r *AdvisoryLockRegistry) startWaiting(s *advisoryLockSession, w *advisoryLockWait) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cycle := r.findWaitCycleLocked(s, w); cycle != nil {
+		waiters := make([]advisoryLockWaiter, len(cycle))
+		for i, u := range cycle {
+			uw := u.waitingFor()
+			if u == s {
+				uw = w
+			}
+			waiters[i] = advisoryLockWaiter{
+				sessionID: string(u.sessionID.GetBytes()), pid: u.pid, wait: *uw,
+			}
+		}
+		return newAdvisoryLockDeadlockError(waiters)
+	}
+	s.setWaiting(w)
+	return nil
+}
+
+// detectDeadlocks checks periodically whether the session, which waits for an
+// advisory lock, is part of a deadlock with sessions on other nodes. If the
+// session is the victim of such a deadlock, its wait is canceled with a
+// deadlock error as the cause. The returned function stops the checks.
+//
+// A single goroutine per node checks all the waiting sessions of the node,
+// using one listing of the sessions of the cluster per check, so that the
+// cost of detection doesn't grow with the number of waiting sessions.
+func (r *AdvisoryLockRegistry) detectDeadlocks(
+	s *advisoryLockSession, cancel context.CancelCauseFunc,
+) (stop func()) {
+	if r.status == nil || advisoryLockDeadlockDetectionInterval.Get(&r.settings.SV) == 0 {
+		return func() {}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.detecting[s] = &advisoryLockDetection{cancel: cancel}
+	if r.mu.stopDetector == nil {
+		var ctx context.Context
+		ctx, r.mu.stopDetector = context.WithCancel(context.Background())
+		go r.runDeadlockDetector(ctx)
+	}
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.stopDetectingLocked(s)
+	}
+}
+
+// stopDetectingLocked stops checking the session for deadlocks, and stops the
+// deadlock detector if no other session is checked.
+func (r *AdvisoryLockRegistry) stopDetectingLocked(s *advisoryLockSession) {
+	delete(r.mu.detecting, s)
+	if len(r.mu.detecting) == 0 && r.mu.stopDetector != nil {
+		r.mu.stopDetector()
+		r.mu.stopDetector = nil
+	}
+}
+
+// runDeadlockDetector checks the sessions of the node that wait for advisory
+// locks for deadlocks with sessions on other nodes, until ctx is canceled.
+//
+// The session registries of the nodes are not read atomically, so a cycle is
+// only reported once two consecutive checks find it. Every session in a cycle
+// finds it, and only the victim, which has the greatest ID, fails its
+// acquisition.
+func (r *AdvisoryLockRegistry) runDeadlockDetector(ctx context.Context) {
+	var timer timeutil.Timer
+	defer timer.Stop()
+	for {
+		interval := advisoryLockDeadlockDetectionInterval.Get(&r.settings.SV)
+		if interval == 0 {
+			// Detection was disabled; the sessions that are still waiting are
+			// no longer checked.
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if ctx.Err() == nil {
+				for s := range r.mu.detecting {
+					r.stopDetectingLocked(s)
+				}
+			}
+			return
+		}
+		timer.Reset(interval)
+		select {
+		case <-timer.C:
+			timer.Read = true
+		case <-ctx.Done():
+			return
+		}
+		resp, err := r.status.ListSessions(ctx, &serverpb.ListSessionsRequest{
+			ExcludeClosedSessions: true,
+		})
+		if err != nil {
+			log.VEventf(ctx, 2, "unable to check for advisory lock deadlocks: %v", err)
+			continue
+		}
+		sessions := resp.Sessions
+		sort.Slice(sessions, func(i, j int) bool {
+			return bytes.Compare(sessions[i].ID, sessions[j].ID) < 0
+		})
+		if done := func() bool {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if ctx.Err() != nil {
+				// Another detector may have started since this one was stopped.
+				return true
+			}
+			for s, d := range r.mu.detecting {
+				cycle := findClusterWaitCycle(sessions, s.sessionID.GetBytes())
+				if cycle != nil && equalAdvisoryLockCycles(cycle, d.prev) && isAdvisoryLockVictim(cycle) {
+					d.cancel(newAdvisoryLockDeadlockError(cycle))
+					r.stopDetectingLocked(s)
+					continue
+				}
+				d.prev = cycle
+			}
+			return false
+		}(); done {
+			return
+		}
+	}
+}
+
+// findWaitCycleLocked returns the sessions that would wait for each other if
+// s waited for w, starting with s, or nil if waiting can't deadlock. Each
+// session in the returned cycle is blocked by the next one, and the last one
+// is blocked by s.
+func (r *AdvisoryLockRegistry) findWaitCycleLocked(
+	s *advisoryLockSession, w *advisoryLockWait,
+) []*advisoryLockSession {
+	visited := make(map[*advisoryLockSession]struct{})
+	var visit func(waiter *advisoryLockSession, w *advisoryLockWait) []*advisoryLockSession
+	visit = func(waiter *advisoryLockSession, w *advisoryLockWait) []*advisoryLockSession {
+		for t := range r.mu.sessions {
+			if t == waiter || !t.blocks(w) {
+				continue
+			}
+			if t == s {
+				return []*advisoryLockSession{waiter}
+			}
+			if _, ok := visited[t]; ok {
+				continue
+			}
+			visited[t] = struct{}{}
+			if tw := t.waitingFor(); tw != nil {
+				if cycle := visit(t, tw); cycle != nil {
+					return append([]*advisoryLockSession{waiter}, cycle...)
+				}
+			}
+		}
+		return nil
+	}
+	return visit(s, w)
+}
+
+// advisoryLockInfo describes an advisory lock held or awaited by a session.
+type advisoryLockInfo struct {
+	advisoryLockID
+	mode    eval.AdvisoryLockMode
+	pid     uint32
+	granted bool
+}
+
+// locks returns the advisory locks held or awaited by the sessions on this
+// node, ordered by session and lock.
+func (r *AdvisoryLockRegistry) locks() []advisoryLockInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ret []advisoryLockInfo
+	for s := range r.mu.sessions {
+		ret = append(ret, s.locks()...)
+	}
+	sortAdvisoryLockInfos(ret)
+	return ret
+}
+
+// locks returns the advisory locks held or awaited by the session.
+func (s *advisoryLockSession) locks() []advisoryLockInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ret []advisoryLockInfo
+	for id, l := range s.mu.locks {
+		for _, mode := range []eval.AdvisoryLockMode{eval.AdvisoryLockExclusive, eval.AdvisoryLockShared} {
+			if l.held(mode) {
+				ret = append(ret, advisoryLockInfo{
+					advisoryLockID: id, mode: mode, pid: s.pid, granted: true,
+				})
+			}
+		}
+	}
+	if w := s.mu.waiting; w != nil {
+		ret = append(ret, advisoryLockInfo{advisoryLockID: w.id, mode: w.mode, pid: s.pid})
+	}
+	return ret
+}
+
+// serialize returns the advisory locks held or awaited by the session, for
+// the session registry.
+func (s *advisoryLockSession) serialize() []serverpb.AdvisoryLock {
+	infos := s.locks()
+	sortAdvisoryLockInfos(infos)
+	ret := make([]serverpb.AdvisoryLock, len(infos))
+	for i, l := range infos {
+		ret[i] = serverpb.AdvisoryLock{
+			DatabaseID: uint32(l.databaseID),
+			ClassID:    l.key.ClassID,
+			ObjID:      l.key.ObjID,
+			ObjSubID:   uint32(l.key.ObjSubID),
+			Exclusive:  l.mode == eval.AdvisoryLockExclusive,
+			Granted:    l.granted,
+		}
+	}
+	return ret
+}
+
+func sortAdvisoryLockInfos(infos []advisoryLockInfo) {
+	sort.Slice(infos, func(i, j int) bool {
+		a, b := &infos[i], &infos[j]
+		if a.pid != b.pid {
+			return a.pid < b.pid
+		}
+		if a.databaseID != b.databaseID {
+			return a.databaseID < b.databaseID
+		}
+		if a.key.ObjSubID != b.key.ObjSubID {
+			return a.key.ObjSubID < b.key.ObjSubID
+		}
+		if a.key.ClassID != b.key.ClassID {
+			return a.key.ClassID < b.key.ClassID
+		}
+		if a.key.ObjID != b.key.ObjID {
+			return a.key.ObjID < b.key.ObjID
+		}
+		return a.mode < b.mode
+	})
+}
+
+type advisoryLocker interface {
+	// acquire acquires an advisory lock in the current database.
+	acquire(
+		ctx context.Context, id advisoryLockID, mode eval.AdvisoryLockMode, xact bool, wait bool,
+	) (bool, error)
+	// release releases a session-level acquisition of an advisory lock.
+	release(ctx context.Context, id advisoryLockID, mode eval.AdvisoryLockMode) (bool, error)
+	// releaseAll releases all the session-level advisory locks of the session.
+	releaseAll(ctx context.Context) error
+}
+
+type connExAdvisoryLockerAccessor struct {
+	ex *connExecutor
+}
+
+func (c connExAdvisoryLockerAccessor) check() error {
+	if c.ex.extraTxnState.fromOuterTxn || c.ex.executorType == executorTypeInternal ||
+		c.ex.server.cfg.AdvisoryLockRegistry == nil {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"advisory locks cannot be used in this context")
+	}
+	return nil
+}
+
+func (c connExAdvisoryLockerAccessor) acquire(
+	ctx context.Context, id advisoryLockID, mode eval.AdvisoryLockMode, xact bool, wait bool,
+) (bool, error) {
+	if err := c.check(); err != nil {
+		return false, err
+	}
+	ex := c.ex
+	if ex.advisoryLocks == nil {
+		// The session is serialized by other goroutines while holding ex.mu.
+		ex.mu.Lock()
+		ex.advisoryLocks = newAdvisoryLockSession(
+			ex.server.cfg.AdvisoryLockRegistry,
+			ex.server.cfg.DB,
+			ex.server.cfg.Codec,
+			ex.planner.extendedEvalCtx.SessionID,
+			ex.queryCancelKey.GetPGBackendPID(),
+		)
+		ex.mu.Unlock()
+	}
+	return ex.advisoryLocks.acquire(ctx, id, mode, xact, wait, ex.sessionData().LockTimeout)
+}
+
+func (c connExAdvisoryLockerAccessor) release(
+	ctx context.Context, id advisoryLockID, mode eval.AdvisoryLockMode,
+) (bool, error) {
+	if err := c.check(); err != nil {
+		return false, err
+	}
+	if c.ex.advisoryLocks == nil {
+		return false, nil
+	}
+	return c.ex.advisoryLocks.release(ctx, id, mode), nil
+}
+
+func (c connExAdvisoryLockerAccessor) releaseAll(ctx context.Context) error {
+	if err := c.check(); err != nil {
+		return err
+	}
+	if c.ex.advisoryLocks != nil {
+		c.ex.advisoryLocks.releaseAll(ctx)
+	}
+	return nil
+}
+
+// emptyAdvisoryLocker is the default impl used by the planner when the
+// connExecutor is not available.
+type emptyAdvisoryLocker struct{}
+
+func (emptyAdvisoryLocker) acquire(
+	context.Context, advisoryLockID, eval.AdvisoryLockMode, bool, bool,
+) (bool, error) {
+	return false, pgerror.New(pgcode.FeatureNotSupported,
+		"advisory locks cannot be used in this context")
+}
+
+func (emptyAdvisoryLocker) release(
+	context.Context, advisoryLockID, eval.AdvisoryLockMode,
+) (bool, error) {
+	return false, pgerror.New(pgcode.FeatureNotSupported,
+		"advisory locks cannot be used in this context")
+}
+
+func (emptyAdvisoryLocker) releaseAll(context.Context) error {
+	return pgerror.New(pgcode.FeatureNotSupported,
+		"advisory locks cannot be used in this context")
+}
+
+// advisoryLockID returns the ID of the advisory lock with the given key in the
+// current database.
+func (p *planner) advisoryLockID(ctx context.Context, key eval.AdvisoryLockKey) (advisoryLockID, error) {
+	if p.CurrentDatabase() == "" {
+		return advisoryLockID{}, pgerror.New(pgcode.UndefinedDatabase,
+			"advisory locks cannot be used without a current database")
+	}
+	db, err := p.Descriptors().ByNameWithLeased(p.txn).Get().Database(ctx, p.CurrentDatabase())
+	if err != nil {
+		return advisoryLockID{}, err
+	}
+	return advisoryLockID{databaseID: db.GetID(), key: key}, nil
+}
+
+// AcquireAdvisoryLock is part of the eval.Planner interface.
+func (p *planner) AcquireAdvisoryLock(
+	ctx context.Context, key eval.AdvisoryLockKey, mode eval.AdvisoryLockMode, xact bool, wait bool,
+) (bool, error) {
+	id, err := p.advisoryLockID(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	return p.advisoryLocker.acquire(ctx, id, mode, xact, wait)
+}
+
+// ReleaseAdvisoryLock is part of the eval.Planner interface.
+func (p *planner) ReleaseAdvisoryLock(
+	ctx context.Context, key eval.AdvisoryLockKey, mode eval.AdvisoryLockMode,
+) (bool, error) {
+	id, err := p.advisoryLockID(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	return p.advisoryLocker.release(ctx, id, mode)
+}
+
+// ReleaseAllAdvisoryLocks is part of the eval.Planner interface.
+func (p *planner) ReleaseAllAdvisoryLocks(ctx context.Context) error {
+	return p.advisoryLocker.releaseAll(ctx)
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql_test
+
+import (
+	"context"
+	gosql "database/sql"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAdvisoryLockDeadlockAcrossNodes checks that deadlocks between sessions
+// connected to different nodes are detected: one of the sessions fails its
+// acquisition with a deadlock error, which lets the other one acquire its lock
+// once the victim releases its own. The same deadlock between sessions on a
+// single node is detected before waiting, see the advisory_locks logic test.
+func TestAdvisoryLockDeadlockAcrossNodes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := serverutils.StartCluster(t, 2 /* numNodes */, base.TestClusterArgs{})
+	defer tc.Stopper().Stop(ctx)
+
+	sqlDB := sqlutils.MakeSQLRunner(tc.ServerConn(0))
+	sqlDB.Exec(t, "SET CLUSTER SETTING sql.advisory_lock.deadlock_detection_interval = '10ms'")
+
+	// Advisory locks are held by sessions, so each side uses a single
+	// connection.
+	var conns [2]*gosql.Conn
+	for i := range conns {
+		conn, err := tc.ServerConn(i).Conn(ctx)
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+		conns[i] = conn
+	}
+
+	_, err := conns[0].ExecContext(ctx, "SELECT pg_advisory_lock(1)")
+	require.NoError(t, err)
+	_, err = conns[1].ExecContext(ctx, "SELECT pg_advisory_lock(2)")
+	require.NoError(t, err)
+
+	// The session on the first node waits for the lock held by the session on
+	// the second node.
+	var errChs [2]chan error
+	acquire := func(i int, query string) {
+		errChs[i] = make(chan error, 1)
+		go func() {
+			_, err := conns[i].ExecContext(ctx, query)
+			errChs[i] <- err
+		}()
+	}
+	acquire(0, "SELECT pg_advisory_lock(2)")
+	sqlDB.CheckQueryResultsRetry(t, `
+SELECT node_id, objid FROM crdb_internal.cluster_advisory_locks WHERE NOT granted`,
+		[][]string{{"1", "2"}},
+	)
+
+	// The session on the second node then waits for the lock held by the
+	// session on the first node, which closes the cycle.
+	acquire(1, "SELECT pg_advisory_lock(1)")
+
+	// The deadlock is detected, and the victim's acquisition fails.
+	var victim int
+	select {
+	case err = <-errChs[0]:
+		victim = 0
+	case err = <-errChs[1]:
+		victim = 1
+	}
+	pqErr := (*pq.Error)(nil)
+	require.ErrorAs(t, err, &pqErr)
+	require.Equal(t, pgcode.DeadlockDetected.String(), string(pqErr.Code), "%v", err)
+
+	// Releasing the victim's lock lets the other session acquire it.
+	_, err = conns[victim].ExecContext(ctx, "SELECT pg_advisory_unlock_all()")
+	require.NoError(t, err)
+	require.NoError(t, <-errChs[1-victim])
+	_, err = conns[1-victim].ExecContext(ctx, "SELECT pg_advisory_unlock_all()")
+	require.NoError(t, err)
+}
@@ -170,6 +170,9 @@ func (n *alterDefaultPrivilegesNode) startExec(params runParams) error {
 	); err != nil {
 		return err
 	}
+	if err := params.p.checkTriggerPrivilegeVersion(params.ctx, privileges); err != nil {
+		return err
+	}
 
 	if len(n.schemaDescs) == 0 {
 		return n.alterDefaultPrivilegesForDatabase(params, targetRoles, objectType, grantees, privileges, grantOption)
@@ -227,6 +230,9 @@ func (n *alterDefaultPrivilegesNode) alterDefaultPrivilegesForSchemas(
 				); err != nil {
 					return err
 				}
+				if objectType == privilege.Tables && !params.p.triggerPrivilegeActive(params.ctx) {
+					defaultPrivs.RemoveKind(privilege.TRIGGER, objectType)
+				}
 			}
 
 			eventDetails := eventpb.CommonSQLPrivilegeEventDetails{}
@@ -310,6 +316,9 @@ func (n *alterDefaultPrivilegesNode) alterDefaultPrivilegesForDatabase(
 			); err != nil {
 				return err
 			}
+			if objectType == privilege.Tables && !params.p.triggerPrivilegeActive(params.ctx) {
+				defaultPrivs.RemoveKind(privilege.TRIGGER, objectType)
+			}
 		}
 
 		eventDetails := eventpb.CommonSQLPrivilegeEventDetails{}
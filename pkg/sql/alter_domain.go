@@ -0,0 +1,430 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+)
+
+type alterDomainNode struct {
+	n    *tree.AlterDomain
+	desc *typedesc.Mutable
+}
+
+// alterDomainNode implements planNode. We set n here to satisfy the linter.
+var _ planNode = &alterDomainNode{n: nil}
+
+// AlterDomain changes the constraints of a domain.
+// Privileges: ownership of the domain.
+func (p *planner) AlterDomain(ctx context.Context, n *tree.AlterDomain) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"ALTER DOMAIN",
+	); err != nil {
+		return nil, err
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_1_Domains) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to alter domains",
+			clusterversion.V24_1_Domains.Version())
+	}
+
+	// Resolve the domain.
+	_, desc, err := p.ResolveMutableTypeDescriptor(ctx, n.Domain, true /* required */)
+	if err != nil {
+		return nil, err
+	}
+	if desc.Kind != descpb.TypeDescriptor_DOMAIN {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"%q is not a domain", tree.AsStringWithFQNames(n.Domain, &p.semaCtx.Annotations))
+	}
+
+	// The user needs ownership privilege to alter the domain.
+	if err := p.canModifyType(ctx, desc); err != nil {
+		return nil, err
+	}
+
+	return &alterDomainNode{
+		n:    n,
+		desc: desc,
+	}, nil
+}
+
+func (n *alterDomainNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeAlterCounterWithExtra("domain", n.n.Cmd.TelemetryName()))
+
+	// New constraints are enforced for new values as soon as the new version of
+	// the domain is leased. The existing values are validated by the type schema
+	// change job, once no lease on a previous version of the domain remains.
+	domain := n.desc.Domain
+	switch t := n.n.Cmd.(type) {
+	case *tree.AlterDomainAddConstraint:
+		check, err := makeDomainCheckConstraint(params, domain, n.desc.Name, &t.Constraint)
+		if err != nil {
+			return err
+		}
+		if t.NotValid {
+			check.Validity = descpb.ConstraintValidity_Unvalidated
+		} else {
+			if err := params.p.checkDomainColumnsArePublic(params.ctx, n.desc); err != nil {
+				return err
+			}
+			check.Validity = descpb.ConstraintValidity_Validating
+		}
+		domain.CheckConstraints = append(domain.CheckConstraints, check)
+	case *tree.AlterDomainDropConstraint:
+		idx := findDomainCheckConstraint(domain, string(t.Constraint))
+		if idx < 0 {
+			if !t.IfExists {
+				return pgerror.Newf(pgcode.UndefinedObject,
+					"constraint %q of domain %q does not exist", t.Constraint, n.desc.Name)
+			}
+			params.p.BufferClientNotice(params.ctx, pgnotice.Newf(
+				"constraint %q of domain %q does not exist, skipping", t.Constraint, n.desc.Name))
+			return nil
+		}
+		domain.CheckConstraints = append(domain.CheckConstraints[:idx], domain.CheckConstraints[idx+1:]...)
+	case *tree.AlterDomainValidateConstraint:
+		idx := findDomainCheckConstraint(domain, string(t.Constraint))
+		if idx < 0 {
+			return pgerror.Newf(pgcode.UndefinedObject,
+				"constraint %q of domain %q does not exist", t.Constraint, n.desc.Name)
+		}
+		check := &domain.CheckConstraints[idx]
+		if check.Validity != descpb.ConstraintValidity_Unvalidated {
+			return nil
+		}
+		if err := params.p.checkDomainColumnsArePublic(params.ctx, n.desc); err != nil {
+			return err
+		}
+		check.Validity = descpb.ConstraintValidity_Validating
+	case *tree.AlterDomainSetNotNull:
+		if domain.NotNull || domain.ValidatingNotNull {
+			return nil
+		}
+		if err := params.p.checkDomainColumnsArePublic(params.ctx, n.desc); err != nil {
+			return err
+		}
+		domain.ValidatingNotNull = true
+	case *tree.AlterDomainDropNotNull:
+		if !domain.NotNull && !domain.ValidatingNotNull {
+			return nil
+		}
+		domain.NotNull, domain.ValidatingNotNull = false, false
+	default:
+		return errors.AssertionFailedf("unknown alter domain cmd %s", t)
+	}
+
+	if err := params.p.writeTypeSchemaChange(
+		params.ctx, n.desc, tree.AsStringWithFQNames(n.n, params.p.Ann()),
+	); err != nil {
+		return err
+	}
+	// Write a log event.
+	return params.p.logEvent(params.ctx,
+		n.desc.ID,
+		&eventpb.AlterType{
+			TypeName: tree.AsStringWithFQNames(n.n.Domain, params.p.Ann()),
+		})
+}
+
+// findDomainCheckConstraint returns the index of the CHECK constraint of the
+// domain with the given name, or -1 if there is no such constraint.
+func findDomainCheckConstraint(domain *descpb.TypeDescriptor_Domain, name string) int {
+	for i := range domain.CheckConstraints {
+		if domain.CheckConstraints[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// validateDomainCheckConstraint returns an error if a value of the given
+// domain stored in a column violates the serialized CHECK constraint
+// expression.
+func validateDomainCheckConstraint(
+	ctx context.Context, txn descs.Txn, desc catalog.TypeDescriptor, exprStr string,
+) error {
+	expr, err := parser.ParseExpr(exprStr)
+	if err != nil {
+		return err
+	}
+	violates := func(value tree.Expr) (tree.Expr, error) {
+		valueExpr, err := eval.ReplaceDomainValue(expr, value)
+		if err != nil {
+			return nil, err
+		}
+		return &tree.NotExpr{Expr: valueExpr}, nil
+	}
+	return validateDomainColumns(ctx, txn, desc, violates,
+		func(tbl catalog.TableDescriptor, col catalog.Column) error {
+			return pgerror.Newf(pgcode.CheckViolation,
+				"column %q of table %q contains values that violate the new constraint",
+				col.GetName(), tbl.GetName())
+		})
+}
+
+// validateDomainNotNull returns an error if a column contains NULL values of
+// the given domain.
+func validateDomainNotNull(ctx context.Context, txn descs.Txn, desc catalog.TypeDescriptor) error {
+	violates := func(value tree.Expr) (tree.Expr, error) {
+		return &tree.IsNullExpr{Expr: value}, nil
+	}
+	return validateDomainColumns(ctx, txn, desc, violates,
+		func(tbl catalog.TableDescriptor, col catalog.Column) error {
+			return pgerror.Newf(pgcode.NotNullViolation,
+				"column %q of table %q contains null values", col.GetName(), tbl.GetName())
+		})
+}
+
+// validateDomainColumns runs a validation query for each column that stores
+// values of the given domain, and returns the error built by makeErr for the
+// first column that contains a value for which the violates expression is
+// true.
+func validateDomainColumns(
+	ctx context.Context,
+	txn descs.Txn,
+	desc catalog.TypeDescriptor,
+	violates func(value tree.Expr) (tree.Expr, error),
+	makeErr func(tbl catalog.TableDescriptor, col catalog.Column) error,
+) error {
+	cols, domainIDs, err := findDomainColumns(ctx, txn.Descriptors(), txn.KV(), desc)
+	if err != nil {
+		return err
+	}
+	for _, c := range cols {
+		if !c.col.Public() {
+			return domainColumnNotPublicError(c.tbl, c.col)
+		}
+		colExpr, _, err := domainViolationExpr(
+			c.col.GetType(), domainIDs, &tree.ColumnItem{ColumnName: tree.Name(c.col.GetName())}, violates,
+		)
+		if err != nil {
+			return err
+		}
+		query := fmt.Sprintf(`SELECT 1 FROM [%d AS t] WHERE %s LIMIT 1`,
+			c.tbl.GetID(), tree.Serialize(colExpr))
+		log.Infof(ctx, "validating domain constraint with query %q", query)
+		row, err := txn.QueryRowEx(
+			ctx,
+			"validate domain constraint",
+			txn.KV(),
+			sessiondata.RootUserSessionDataOverride,
+			query,
+		)
+		if err != nil {
+			return err
+		}
+		if row != nil {
+			return makeErr(c.tbl, c.col)
+		}
+	}
+	return nil
+}
+
+// domainViolationExpr returns an expression that is true if the given value of
+// type typ contains a value of one of the given domains for which the
+// violates expression is true. Values of the domains may be nested in arrays
+// and tuples. ok is false if typ cannot contain values of the domains.
+func domainViolationExpr(
+	typ *types.T,
+	domainIDs catalog.DescriptorIDSet,
+	value tree.Expr,
+	violates func(value tree.Expr) (tree.Expr, error),
+) (_ tree.Expr, ok bool, _ error) {
+	if typ.IsDomain() && domainIDs.Contains(typedesc.GetUserDefinedTypeDescID(typ)) {
+		expr, err := violates(value)
+		return expr, err == nil, err
+	}
+	switch typ.Family() {
+	case types.ArrayFamily:
+		// Check each element of the array:
+		//
+		//   EXISTS (SELECT 1 FROM generate_subscripts(value, 1) AS e(i)
+		//           WHERE <violates((value)[e.i])>)
+		//
+		elem, err := parser.ParseExpr(fmt.Sprintf(`(%s)[e.i]`, tree.Serialize(value)))
+		if err != nil {
+			return nil, false, err
+		}
+		elemExpr, ok, err := domainViolationExpr(typ.ArrayContents(), domainIDs, elem, violates)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+		sub, err := parser.ParseExpr(fmt.Sprintf(
+			`EXISTS (SELECT 1 FROM generate_subscripts(%s, 1) AS e(i) WHERE %s)`,
+			tree.Serialize(value), tree.Serialize(elemExpr),
+		))
+		return sub, err == nil, err
+	case types.TupleFamily:
+		// Check each element of the tuple, unless the tuple itself is NULL. Note
+		// that IS NOT NULL would also skip tuples with some NULL elements.
+		var res tree.Expr
+		for i, elemTyp := range typ.TupleContents() {
+			elem := &tree.ColumnAccessExpr{Expr: &tree.ParenExpr{Expr: value}, ByIndex: true, ColIndex: i}
+			elemExpr, ok, err := domainViolationExpr(elemTyp, domainIDs, elem, violates)
+			if err != nil {
+				return nil, false, err
+			}
+			if !ok {
+				continue
+			}
+			if res == nil {
+				res = elemExpr
+			} else {
+				res = &tree.OrExpr{Left: res, Right: elemExpr}
+			}
+		}
+		if res == nil {
+			return nil, false, nil
+		}
+		return &tree.AndExpr{
+			Left: &tree.ComparisonExpr{
+				Operator: treecmp.MakeComparisonOperator(treecmp.IsDistinctFrom),
+				Left:     value,
+				Right:    tree.DNull,
+			},
+			Right: &tree.ParenExpr{Expr: res},
+		}, true, nil
+	}
+	return nil, false, nil
+}
+
+// domainColumn is a column that stores values of a domain.
+type domainColumn struct {
+	tbl catalog.TableDescriptor
+	col catalog.Column
+}
+
+// findDomainColumns returns the columns that store values of the given
+// domain, either directly or nested in arrays, composite types or other
+// domains, along with the IDs of the domain and of the domains defined over
+// it. Columns that are being dropped are ignored, but columns that are being
+// added are included.
+func findDomainColumns(
+	ctx context.Context, descsCol *descs.Collection, txn *kv.Txn, desc catalog.TypeDescriptor,
+) (cols []domainColumn, domainIDs catalog.DescriptorIDSet, _ error) {
+	// Collect the tables that reference the domain, and the types defined over
+	// it, whose own references need to be followed too.
+	var tableIDs, visited catalog.DescriptorIDSet
+	toVisit := []catalog.TypeDescriptor{desc}
+	visited.Add(desc.GetID())
+	domainIDs.Add(desc.GetID())
+	for len(toVisit) > 0 {
+		typ := toVisit[0]
+		toVisit = toVisit[1:]
+		for _, id := range typ.GetReferencingDescriptorIDs() {
+			if visited.Contains(id) {
+				continue
+			}
+			visited.Add(id)
+			d, err := descsCol.ByIDWithLeased(txn).WithoutNonPublic().Get().Desc(ctx, id)
+			if err != nil {
+				return nil, catalog.DescriptorIDSet{}, err
+			}
+			switch d := d.(type) {
+			case catalog.TableDescriptor:
+				if d.IsPhysicalTable() {
+					tableIDs.Add(id)
+				}
+			case catalog.TypeDescriptor:
+				if d.AsDomainTypeDescriptor() != nil {
+					domainIDs.Add(id)
+				}
+				toVisit = append(toVisit, d)
+			}
+		}
+	}
+	for _, id := range tableIDs.Ordered() {
+		tbl, err := descsCol.ByIDWithLeased(txn).WithoutNonPublic().Get().Table(ctx, id)
+		if err != nil {
+			return nil, catalog.DescriptorIDSet{}, err
+		}
+		for _, col := range tbl.AllColumns() {
+			if col.Dropped() || !typeContainsDomain(col.GetType(), domainIDs) {
+				continue
+			}
+			cols = append(cols, domainColumn{tbl: tbl, col: col})
+		}
+	}
+	return cols, domainIDs, nil
+}
+
+// typeContainsDomain returns true if values of the given type can contain
+// values of one of the given domains.
+func typeContainsDomain(typ *types.T, domainIDs catalog.DescriptorIDSet) bool {
+	if typ.IsDomain() && domainIDs.Contains(typedesc.GetUserDefinedTypeDescID(typ)) {
+		return true
+	}
+	switch typ.Family() {
+	case types.ArrayFamily:
+		return typeContainsDomain(typ.ArrayContents(), domainIDs)
+	case types.TupleFamily:
+		for _, elemTyp := range typ.TupleContents() {
+			if typeContainsDomain(elemTyp, domainIDs) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkDomainColumnsArePublic returns an error if a column that stores values
+// of the given domain is being added to a table. The existing values of such a
+// column cannot be validated until the column is public.
+func (p *planner) checkDomainColumnsArePublic(
+	ctx context.Context, desc catalog.TypeDescriptor,
+) error {
+	cols, _, err := findDomainColumns(ctx, p.Descriptors(), p.txn, desc)
+	if err != nil {
+		return err
+	}
+	for _, c := range cols {
+		if !c.col.Public() {
+			return domainColumnNotPublicError(c.tbl, c.col)
+		}
+	}
+	return nil
+}
+
+func domainColumnNotPublicError(tbl catalog.TableDescriptor, col catalog.Column) error {
+	return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+		"column %q of table %q is being added; try again once the schema change completes",
+		col.GetName(), tbl.GetName())
+}
+
+func (n *alterDomainNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *alterDomainNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *alterDomainNode) Close(ctx context.Context)           {}
+func (n *alterDomainNode) ReadingOwnWrites()                   {}
@@ -16,7 +16,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
-	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/decodeusername"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -24,7 +23,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
-	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 )
@@ -67,7 +65,7 @@ func (p *planner) AlterFunctionOptions(
 func (n *alterFunctionOptionsNode) startExec(params runParams) error {
 	telemetry.Inc(sqltelemetry.SchemaChangeAlterCounter("function"))
 
-	fnDesc, err := params.p.mustGetMutableFunctionForAlter(params.ctx, &n.n.Function)
+	fnDesc, err := params.p.mustGetMutableFunctionForAlter(params.ctx, &n.n.Function, false /* aggregate */)
 	if err != nil {
 		return err
 	}
@@ -156,7 +154,7 @@ func (n *alterFunctionRenameNode) startExec(params runParams) error {
 	// TODO(chengxiong): add validation that a function can not be altered if it's
 	// referenced by other objects. This is needed when want to allow function
 	// references.
-	fnDesc, err := params.p.mustGetMutableFunctionForAlter(params.ctx, &n.n.Function)
+	fnDesc, err := params.p.mustGetMutableFunctionForAlter(params.ctx, &n.n.Function, n.n.Aggregate)
 	if err != nil {
 		return err
 	}
@@ -204,7 +202,7 @@ func (n *alterFunctionRenameNode) startExec(params runParams) error {
 
 	scDesc.RemoveFunction(fnDesc.GetName(), fnDesc.GetID())
 	fnDesc.SetName(string(n.n.NewName))
-	scDesc.AddFunction(fnDesc.GetName(), toSchemaOverloadSignature(fnDesc))
+	scDesc.AddFunction(fnDesc.GetName(), fnDesc.ToFunctionSignature())
 	if err := params.p.writeFuncSchemaChange(params.ctx, fnDesc); err != nil {
 		return err
 	}
@@ -245,7 +243,7 @@ func (p *planner) AlterFunctionSetOwner(
 
 func (n *alterFunctionSetOwnerNode) startExec(params runParams) error {
 	telemetry.Inc(sqltelemetry.SchemaChangeAlterCounter("function"))
-	fnDesc, err := params.p.mustGetMutableFunctionForAlter(params.ctx, &n.n.Function)
+	fnDesc, err := params.p.mustGetMutableFunctionForAlter(params.ctx, &n.n.Function, n.n.Aggregate)
 	if err != nil {
 		return err
 	}
@@ -319,7 +317,7 @@ func (n *alterFunctionSetSchemaNode) startExec(params runParams) error {
 	// TODO(chengxiong): add validation that a function can not be altered if it's
 	// referenced by other objects. This is needed when want to allow function
 	// references.
-	fnDesc, err := params.p.mustGetMutableFunctionForAlter(params.ctx, &n.n.Function)
+	fnDesc, err := params.p.mustGetMutableFunctionForAlter(params.ctx, &n.n.Function, n.n.Aggregate)
 	if err != nil {
 		return err
 	}
@@ -398,7 +396,7 @@ func (n *alterFunctionSetSchemaNode) startExec(params runParams) error {
 	if err := params.p.writeSchemaDesc(params.ctx, sourceSc); err != nil {
 		return err
 	}
-	targetSc.AddFunction(fnDesc.GetName(), toSchemaOverloadSignature(fnDesc))
+	targetSc.AddFunction(fnDesc.GetName(), fnDesc.ToFunctionSignature())
 	if err := params.p.writeSchemaDesc(params.ctx, targetSc); err != nil {
 		return err
 	}
@@ -439,12 +437,17 @@ func (n *alterFunctionDepExtensionNode) Values() tree.Datums                 { r
 func (n *alterFunctionDepExtensionNode) Close(ctx context.Context)           {}
 
 func (p *planner) mustGetMutableFunctionForAlter(
-	ctx context.Context, routineObj *tree.RoutineObj,
+	ctx context.Context, routineObj *tree.RoutineObj, aggregate bool,
 ) (*funcdesc.Mutable, error) {
 	ol, err := p.matchRoutine(ctx, routineObj, true /*required*/, tree.UDFRoutine|tree.ProcedureRoutine)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkAggregateKind(
+		ol, routineObj, aggregate, "Use ALTER AGGREGATE to change aggregate functions.",
+	); err != nil {
+		return nil, err
+	}
 	fnID := funcdesc.UserDefinedFunctionOIDToID(ol.Oid)
 	mut, err := p.checkPrivilegesForDropFunction(ctx, fnID)
 	if err != nil {
@@ -452,17 +455,3 @@ func (p *planner) mustGetMutableFunctionForAlter(
 	}
 	return mut, nil
 }
-
-func toSchemaOverloadSignature(fnDesc *funcdesc.Mutable) descpb.SchemaDescriptor_FunctionSignature {
-	ret := descpb.SchemaDescriptor_FunctionSignature{
-		ID:          fnDesc.GetID(),
-		ArgTypes:    make([]*types.T, len(fnDesc.GetParams())),
-		ReturnType:  fnDesc.ReturnType.Type,
-		ReturnSet:   fnDesc.ReturnType.ReturnSet,
-		IsProcedure: fnDesc.IsProcedure(),
-	}
-	for i := range fnDesc.Params {
-		ret.ArgTypes[i] = fnDesc.Params[i].Type
-	}
-	return ret
-}
@@ -101,6 +101,14 @@ func (p *planner) AlterTable(ctx context.Context, n *tree.AlterTable) (planNode,
 			"must be owner of table %s or have CREATE privilege on table %s",
 			tree.Name(tableDesc.GetName()), tree.Name(tableDesc.GetName()))
 	}
+	for _, cmd := range n.Cmds {
+		if _, ok := cmd.(*tree.AlterTableSetRowLevelSecurity); ok {
+			if err := p.checkCanAlterRowLevelSecurity(ctx, tableDesc); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
 
 	// Disallow schema changes if this table's schema is locked, unless it is to
 	// set/reset the "schema_locked" storage parameter.
@@ -308,6 +316,16 @@ func (n *alterTableNode) startExec(params runParams) error {
 					StoreColumnNames: d.Storing.ToStrings(),
 					CreatedAtNanos:   params.EvalContext().GetTxnTimestamp(time.Microsecond).UnixNano(),
 				}
+				// A deferrable unique constraint is backed by a non-unique index and
+				// enforced like a UNIQUE WITHOUT INDEX constraint. See NewTableDesc.
+				deferrable := d.Deferrable != tree.ConstraintNotDeferrable
+				if deferrable {
+					if err := validateDeferrableUniqueConstraint(d); err != nil {
+						return err
+					}
+					idx.Name = ""
+					idx.Unique = false
+				}
 				if err := idx.FillColumns(d.Columns); err != nil {
 					return err
 				}
@@ -342,6 +360,21 @@ func (n *alterTableNode) startExec(params runParams) error {
 				); err != nil {
 					return err
 				}
+				if deferrable {
+					if err := addUniqueWithoutIndexTableDef(
+						params.ctx,
+						params.EvalContext(),
+						params.SessionData(),
+						d,
+						n.tableDesc,
+						*tn,
+						NonEmptyTable,
+						t.ValidationBehavior,
+						params.p.SemaCtx(),
+					); err != nil {
+						return err
+					}
+				}
 
 				// We need to allocate IDs upfront in the event we need to update the zone config
 				// in the same transaction.
@@ -366,6 +399,20 @@ func (n *alterTableNode) startExec(params runParams) error {
 						return err
 					}
 				}
+			case *tree.ExclusionConstraintTableDef:
+				if err := addExclusionConstraintTableDef(
+					params.ctx,
+					params.EvalContext(),
+					d,
+					n.tableDesc,
+					*tn,
+					NonEmptyTable,
+					t.ValidationBehavior,
+					params.p.SemaCtx(),
+				); err != nil {
+					return err
+				}
+
 			case *tree.CheckConstraintTableDef:
 				var err error
 				params.p.runWithOptions(resolveFlags{contextDatabaseID: n.tableDesc.ParentID}, func() {
@@ -595,6 +642,17 @@ func (n *alterTableNode) startExec(params runParams) error {
 					return err
 				}
 				uwoi.UniqueWithoutIndexDesc().Validity = descpb.ConstraintValidity_Validated
+			} else if excl := c.AsExclusion(); excl != nil {
+				if err := validateExclusionConstraintInTxn(
+					params.ctx,
+					params.p.InternalSQLTxn(),
+					n.tableDesc,
+					params.p.User(),
+					name,
+				); err != nil {
+					return err
+				}
+				excl.ExclusionDesc().Validity = descpb.ConstraintValidity_Validated
 			} else {
 				return pgerror.Newf(pgcode.WrongObjectType,
 					"constraint %q of relation %q is not a foreign key, check, or unique without index"+
@@ -706,6 +764,10 @@ func (n *alterTableNode) startExec(params runParams) error {
 			}
 			descriptorChanged = descriptorChanged || changed
 
+		case *tree.AlterTableSetRowLevelSecurity:
+			changed := setRowLevelSecurityMode(n.tableDesc, t.Mode)
+			descriptorChanged = descriptorChanged || changed
+
 		case *tree.AlterTableInjectStats:
 			sd, ok := n.statsData[i]
 			if !ok {
@@ -857,6 +919,9 @@ func (n *alterTableNode) startExec(params runParams) error {
 	if addedMutations {
 		mutationID = n.tableDesc.ClusterVersion().NextMutationID
 	}
+	if err := params.p.updateTextSearchConfigReferences(params.ctx, n.tableDesc); err != nil {
+		return err
+	}
 	if err := params.p.writeSchemaChange(
 		params.ctx, n.tableDesc, mutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
 	); err != nil {
@@ -1413,6 +1478,9 @@ func validateConstraintNameIsNotUsed(
 	case *tree.ForeignKeyConstraintTableDef:
 		name = d.Name
 		hasIfNotExists = d.IfNotExists
+	case *tree.ExclusionConstraintTableDef:
+		name = d.Name
+		hasIfNotExists = d.IfNotExists
 	case *tree.UniqueConstraintTableDef:
 		name = d.Name
 		hasIfNotExists = d.IfNotExists
@@ -1541,6 +1609,56 @@ func (p *planner) updateFKBackReferenceName(
 	return errors.Errorf("missing backreference for foreign key %s", ref.Name)
 }
 
+// policyUsesColumn returns whether either of the expressions of the given
+// row-level security policy refers to the column.
+func policyUsesColumn(
+	tableDesc catalog.TableDescriptor, policy *descpb.PolicyDescriptor, colID descpb.ColumnID,
+) (bool, error) {
+	for _, exprStr := range []string{policy.UsingExpr, policy.WithCheckExpr} {
+		if exprStr == "" {
+			continue
+		}
+		expr, err := parser.ParseExpr(exprStr)
+		if err != nil {
+			return false, err
+		}
+		colIDs, err := schemaexpr.ExtractColumnIDs(tableDesc, expr)
+		if err != nil {
+			return false, err
+		}
+		if colIDs.Contains(colID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// triggerUsesColumn returns true if the given trigger fires on an UPDATE OF
+// the column, or if its WHEN condition refers to the column of the NEW or OLD
+// row.
+func triggerUsesColumn(trigger *descpb.TriggerDescriptor, col catalog.Column) (bool, error) {
+	for _, ev := range trigger.Events {
+		for _, colID := range ev.ColumnIDs {
+			if colID == col.GetID() {
+				return true, nil
+			}
+		}
+	}
+	if trigger.WhenExpr == "" {
+		return false, nil
+	}
+	expr, err := parser.ParseExpr(trigger.WhenExpr)
+	if err != nil {
+		return false, err
+	}
+	var found bool
+	_, err = schemaexpr.ReplaceTriggerColumnRefs(expr, func(_ string, colName tree.Name) (tree.Expr, error) {
+		found = found || colName == col.ColName()
+		return nil, nil
+	})
+	return found, err
+}
+
 func dropColumnImpl(
 	params runParams,
 	tn *tree.TableName,
@@ -1596,6 +1714,61 @@ func dropColumnImpl(
 		return nil, err
 	}
 
+	// A trigger that refers to the column has to be dropped along with it,
+	// which requires CASCADE.
+	triggers := tableDesc.Triggers[:0]
+	for i := range tableDesc.Triggers {
+		trigger := tableDesc.Triggers[i]
+		usesColumn, err := triggerUsesColumn(&trigger, colToDrop)
+		if err != nil {
+			return nil, err
+		}
+		if !usesColumn {
+			triggers = append(triggers, trigger)
+			continue
+		}
+		if t.DropBehavior != tree.DropCascade {
+			return nil, errors.WithHint(
+				pgerror.Newf(pgcode.DependentObjectsStillExist,
+					"cannot drop column %s because trigger %q on table %q depends on it",
+					t.Column, trigger.Name, tableDesc.GetName()),
+				"Use DROP ... CASCADE to drop the dependent objects too.",
+			)
+		}
+		fnDesc, err := params.p.Descriptors().MutableByID(params.p.Txn()).Function(params.ctx, trigger.FuncID)
+		if err != nil {
+			return nil, err
+		}
+		fnDesc.RemoveTriggerReference(tableDesc.GetID(), trigger.ID)
+		if err := params.p.writeFuncSchemaChange(params.ctx, fnDesc); err != nil {
+			return nil, err
+		}
+	}
+	tableDesc.Triggers = triggers
+
+	// A row-level security policy that refers to the column has to be dropped
+	// along with it, which requires CASCADE.
+	policies := tableDesc.Policies[:0]
+	for _, policy := range tableDesc.Policies {
+		usesColumn, err := policyUsesColumn(tableDesc, &policy, colToDrop.GetID())
+		if err != nil {
+			return nil, err
+		}
+		if usesColumn {
+			if t.DropBehavior != tree.DropCascade {
+				return nil, errors.WithHint(
+					pgerror.Newf(pgcode.DependentObjectsStillExist,
+						"cannot drop column %s because policy %q on table %q depends on it",
+						t.Column, policy.Name, tableDesc.GetName()),
+					"Use DROP ... CASCADE to drop the dependent objects too.",
+				)
+			}
+			continue
+		}
+		policies = append(policies, policy)
+	}
+	tableDesc.Policies = policies
+
 	// If the dropped column uses a sequence, remove references to it from that sequence.
 	if colToDrop.NumUsesSequences() > 0 {
 		if err := params.p.removeSequenceDependencies(params.ctx, tableDesc, colToDrop); err != nil {
@@ -1745,6 +1918,31 @@ func dropColumnImpl(
 		}
 	}
 
+	// Drop exclusion constraints which reference the column.
+	for _, excl := range tableDesc.EnforcedExclusionConstraints() {
+		if excl.Dropped() {
+			continue
+		}
+		refersToCol := excl.CollectKeyColumnIDs().Contains(colToDrop.GetID())
+		if !refersToCol && excl.IsPartial() {
+			expr, err := parser.ParseExpr(excl.GetPredicate())
+			if err != nil {
+				return nil, err
+			}
+			colIDs, err := schemaexpr.ExtractColumnIDs(tableDesc, expr)
+			if err != nil {
+				return nil, err
+			}
+			refersToCol = colIDs.Contains(colToDrop.GetID())
+		}
+		if !refersToCol {
+			continue
+		}
+		if err := tableDesc.DropConstraint(excl, nil /* removeFKBackRef */, nil /* removeFnBackRef */); err != nil {
+			return nil, err
+		}
+	}
+
 	// Drop check constraints which reference the column.
 	for _, check := range tableDesc.CheckConstraints() {
 		if check.Dropped() {
@@ -2025,6 +2223,25 @@ func (p *planner) tryRemoveFKBackReferences(
 	return nil
 }
 
+// setRowLevelSecurityMode applies an ENABLE/DISABLE/FORCE/NO FORCE ROW LEVEL
+// SECURITY command to the table descriptor, and returns whether the descriptor
+// was changed.
+func setRowLevelSecurityMode(desc *tabledesc.Mutable, mode tree.RowLevelSecurityMode) bool {
+	var flag *bool
+	var value bool
+	switch mode {
+	case tree.RowLevelSecurityEnable, tree.RowLevelSecurityDisable:
+		flag, value = &desc.RowLevelSecurityEnabled, mode == tree.RowLevelSecurityEnable
+	case tree.RowLevelSecurityForce, tree.RowLevelSecurityNoForce:
+		flag, value = &desc.RowLevelSecurityForced, mode == tree.RowLevelSecurityForce
+	}
+	if *flag == value {
+		return false
+	}
+	*flag = value
+	return true
+}
+
 func checkTableSchemaUnlocked(desc catalog.TableDescriptor) (ret error) {
 	if desc != nil && desc.IsSchemaLocked() {
 		return sqlerrors.NewSchemaChangeOnLockedTableErr(desc.GetName())
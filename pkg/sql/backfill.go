@@ -534,6 +534,26 @@ func (sc *SchemaChanger) dropConstraints(
 						constraint,
 					)
 				}
+			} else if constraint.AsExclusion() != nil {
+				found := false
+				for j, c := range scTable.Exclusions {
+					if c.Name == constraint.GetName() {
+						scTable.Exclusions = append(
+							scTable.Exclusions[:j],
+							scTable.Exclusions[j+1:]...,
+						)
+						found = true
+						break
+					}
+				}
+				if !found {
+					log.VEventf(
+						ctx, 2,
+						"backfiller tried to drop constraint %s but it was not found, "+
+							"presumably due to a retry or rollback",
+						constraint,
+					)
+				}
 			}
 		}
 		if err := txn.Descriptors().WriteDescToBatch(
@@ -711,6 +731,28 @@ func (sc *SchemaChanger) addConstraints(
 					scTable.UniqueWithoutIndexConstraints =
 						append(scTable.UniqueWithoutIndexConstraints, *uwi.UniqueWithoutIndexDesc())
 				}
+			} else if excl := constraint.AsExclusion(); excl != nil {
+				found := false
+				for i := range scTable.Exclusions {
+					c := &scTable.Exclusions[i]
+					if c.Name == constraint.GetName() {
+						log.VEventf(
+							ctx, 2,
+							"backfiller tried to add constraint %s but found existing constraint %+v, "+
+								"presumably due to a retry or rollback",
+							constraint, c,
+						)
+						// Ensure the constraint on the descriptor is set to Validating, in
+						// case we're in the middle of rolling back DROP CONSTRAINT
+						c.Validity = descpb.ConstraintValidity_Validating
+						found = true
+						break
+					}
+				}
+				if !found {
+					scTable.Exclusions =
+						append(scTable.Exclusions, *excl.ExclusionDesc())
+				}
 			}
 		}
 		if err := txn.Descriptors().WriteDescToBatch(
@@ -817,6 +859,10 @@ func (sc *SchemaChanger) validateConstraints(
 					if err := validateUniqueWithoutIndexConstraintInTxn(ctx, txn, desc, evalCtx.SessionData().User(), c.GetName()); err != nil {
 						return err
 					}
+				} else if c.AsExclusion() != nil {
+					if err := validateExclusionConstraintInTxn(ctx, txn, desc, evalCtx.SessionData().User(), c.GetName()); err != nil {
+						return err
+					}
 				} else {
 					return errors.Errorf("unsupported constraint type: %s", c)
 				}
@@ -2533,6 +2579,16 @@ func runSchemaChangesInTxn(
 							break
 						}
 					}
+				} else if c.AsExclusion() != nil {
+					for i := range tableDesc.Exclusions {
+						if tableDesc.Exclusions[i].Name == c.GetName() {
+							tableDesc.Exclusions = append(
+								tableDesc.Exclusions[:i],
+								tableDesc.Exclusions[i+1:]...,
+							)
+							break
+						}
+					}
 				} else {
 					return errors.AssertionFailedf("unsupported constraint type: %s", c)
 				}
@@ -2562,6 +2618,9 @@ func runSchemaChangesInTxn(
 		} else if uwi := c.AsUniqueWithoutIndex(); uwi != nil {
 			ctu.ConstraintType = descpb.ConstraintToUpdate_UNIQUE_WITHOUT_INDEX
 			ctu.UniqueWithoutIndexConstraint = *uwi.UniqueWithoutIndexDesc()
+		} else if excl := c.AsExclusion(); excl != nil {
+			ctu.ConstraintType = descpb.ConstraintToUpdate_EXCLUSION
+			ctu.ExclusionConstraint = *excl.ExclusionDesc()
 		} else {
 			return errors.AssertionFailedf("unknown constraint type: %s", c)
 		}
@@ -2616,6 +2675,19 @@ func runSchemaChangesInTxn(
 				}
 				uwi.UniqueWithoutIndexDesc().Validity = descpb.ConstraintValidity_Validated
 			}
+		} else if excl := c.AsExclusion(); excl != nil {
+			if excl.GetConstraintValidity() == descpb.ConstraintValidity_Validating {
+				if err := validateExclusionConstraintInTxn(
+					ctx,
+					planner.InternalSQLTxn(),
+					tableDesc,
+					planner.User(),
+					c.GetName(),
+				); err != nil {
+					return err
+				}
+				excl.ExclusionDesc().Validity = descpb.ConstraintValidity_Validated
+			}
 		} else {
 			return errors.AssertionFailedf("unsupported constraint type: %s", c)
 		}
@@ -2657,6 +2729,8 @@ func runSchemaChangesInTxn(
 			}
 		} else if uwi := c.AsUniqueWithoutIndex(); uwi != nil {
 			tableDesc.UniqueWithoutIndexConstraints = append(tableDesc.UniqueWithoutIndexConstraints, *uwi.UniqueWithoutIndexDesc())
+		} else if excl := c.AsExclusion(); excl != nil {
+			tableDesc.Exclusions = append(tableDesc.Exclusions, *excl.ExclusionDesc())
 		} else {
 			return errors.AssertionFailedf("unsupported constraint type: %s", c)
 		}
@@ -2825,6 +2899,42 @@ func validateUniqueWithoutIndexConstraintInTxn(
 		})
 }
 
+// validateExclusionConstraintInTxn validates an exclusion constraint within
+// the provided transaction. If the provided table descriptor version is newer
+// than the cluster version, it will be used in the InternalExecutor that
+// performs the validation query.
+//
+// It operates entirely on the current goroutine and is thus able to
+// reuse an existing kv.Txn safely.
+func validateExclusionConstraintInTxn(
+	ctx context.Context,
+	txn isql.Txn,
+	tableDesc *tabledesc.Mutable,
+	user username.SQLUsername,
+	constraintName string,
+) error {
+	var syntheticDescs []catalog.Descriptor
+	if tableDesc.Version > tableDesc.ClusterVersion().Version {
+		syntheticDescs = append(syntheticDescs, tableDesc)
+	}
+	var excl catalog.ExclusionConstraint
+	for _, c := range tableDesc.ExclusionConstraints() {
+		if c.GetName() == constraintName {
+			excl = c
+			break
+		}
+	}
+	if excl == nil {
+		return errors.AssertionFailedf("exclusion constraint %s does not exist", constraintName)
+	}
+
+	return txn.WithSyntheticDescriptors(
+		syntheticDescs,
+		func() error {
+			return validateExclusionConstraint(ctx, tableDesc, excl, txn, user)
+		})
+}
+
 // columnBackfillInTxn backfills columns for all mutation columns in
 // the mutation list.
 //
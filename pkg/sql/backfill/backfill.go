@@ -21,11 +21,13 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/faketreeeval"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -233,6 +235,9 @@ func (cb *ColumnBackfiller) InitForDistributedUse(
 		if err := typedesc.HydrateTypesInDescriptor(ctx, desc, &resolver); err != nil {
 			return err
 		}
+		if err := installTextSearchResolver(ctx, flowCtx, txn.KV(), desc, evalCtx); err != nil {
+			return err
+		}
 		// Set up a SemaContext to type check the default and computed expressions.
 		semaCtx := tree.MakeSemaContext()
 		semaCtx.TypeResolver = &resolver
@@ -668,6 +673,9 @@ func (ib *IndexBackfiller) InitForDistributedUse(
 		if err = typedesc.HydrateTypesInDescriptor(ctx, desc, &resolver); err != nil {
 			return err
 		}
+		if err = installTextSearchResolver(ctx, flowCtx, txn.KV(), desc, evalCtx); err != nil {
+			return err
+		}
 		// Set up a SemaContext to type check the default and computed expressions.
 		semaCtx := tree.MakeSemaContext()
 		semaCtx.TypeResolver = &resolver
@@ -694,6 +702,35 @@ func (ib *IndexBackfiller) InitForDistributedUse(
 	return ib.init(evalCtx, predicates, colExprs, mon)
 }
 
+// installTextSearchResolver sets up evalCtx to resolve the user-defined text
+// search configurations and dictionaries used by the expressions of the
+// backfill in the database of the table. Remote flows and schema change jobs
+// have no session whose current database could be used instead.
+func installTextSearchResolver(
+	ctx context.Context,
+	flowCtx *execinfra.FlowCtx,
+	txn *kv.Txn,
+	desc catalog.TableDescriptor,
+	evalCtx *eval.Context,
+) error {
+	planner, ok := evalCtx.Planner.(*faketreeeval.DummyEvalPlanner)
+	if !ok {
+		return nil
+	}
+	db, err := flowCtx.Descriptors.ByIDWithLeased(txn).WithoutNonPublic().Get().Database(ctx, desc.GetParentID())
+	if err != nil {
+		return err
+	}
+	if len(db.GetTextSearchConfigurations()) == 0 && len(db.GetTextSearchDictionaries()) == 0 {
+		return nil
+	}
+	evalCtx.Planner = &faketreeeval.DummyEvalPlanner{
+		Monitor:    planner.Monitor,
+		TextSearch: dbdesc.NewTextSearchResolver(db, nil),
+	}
+	return nil
+}
+
 // Close releases the resources used by the IndexBackfiller.
 func (ib *IndexBackfiller) Close(ctx context.Context) {
 	if ib.mon != nil {
@@ -351,6 +351,21 @@ func (p *PrivilegeDescriptor) Revoke(
 	return nil
 }
 
+// RemoveKind removes a privilege, along with its grant option, from every
+// user in the descriptor. Users left without any privileges are removed.
+// The ALL privilege is not expanded, so users holding ALL are unaffected.
+func (p *PrivilegeDescriptor) RemoveKind(kind privilege.Kind) {
+	users := p.Users[:0]
+	for _, u := range p.Users {
+		u.Privileges &^= kind.Mask()
+		u.WithGrantOption &^= kind.Mask()
+		if u.Privileges != 0 {
+			users = append(users, u)
+		}
+	}
+	p.Users = users
+}
+
 // ValidateSuperuserPrivileges ensures that superusers have exactly the maximum
 // allowed privilege set for the object.
 // It requires the ID of the descriptor it is applied on to determine whether it
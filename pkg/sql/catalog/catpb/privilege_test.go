@@ -92,6 +92,7 @@ func TestPrivilege(t *testing.T) {
 					{Kind: privilege.CREATE},
 					{Kind: privilege.DELETE},
 					{Kind: privilege.DROP},
+					{Kind: privilege.TRIGGER},
 					{Kind: privilege.UPDATE},
 					{Kind: privilege.ZONECONFIG},
 				}},
@@ -129,12 +130,12 @@ func TestPrivilege(t *testing.T) {
 			},
 			privilege.Type,
 		},
-		// Ensure revoking BACKUP, CHANGEFEED, CREATE, DROP, SELECT, INSERT, DELETE, UPDATE, ZONECONFIG
+		// Ensure revoking BACKUP, CHANGEFEED, CREATE, DROP, SELECT, INSERT, DELETE, UPDATE, ZONECONFIG, TRIGGER
 		// from a user with ALL privilege on a table leaves the user with no privileges.
 		{testUser,
 			privilege.List{privilege.ALL},
 			privilege.List{privilege.BACKUP, privilege.CHANGEFEED, privilege.CREATE, privilege.DROP, privilege.SELECT, privilege.INSERT,
-				privilege.DELETE, privilege.UPDATE, privilege.ZONECONFIG},
+				privilege.DELETE, privilege.UPDATE, privilege.ZONECONFIG, privilege.TRIGGER},
 			[]catpb.UserPrivilege{
 				{User: username.AdminRoleName(), Privileges: []privilege.Privilege{{Kind: privilege.ALL, GrantOption: true}}},
 			},
@@ -609,7 +610,7 @@ func TestRevokeWithGrantOption(t *testing.T) {
 			true,
 			privilege.List{privilege.CREATE},
 			privilege.List{privilege.ALL},
-			privilege.List{privilege.BACKUP, privilege.CHANGEFEED, privilege.DROP, privilege.SELECT, privilege.INSERT, privilege.DELETE, privilege.UPDATE, privilege.ZONECONFIG},
+			privilege.List{privilege.BACKUP, privilege.CHANGEFEED, privilege.DROP, privilege.SELECT, privilege.INSERT, privilege.DELETE, privilege.UPDATE, privilege.ZONECONFIG, privilege.TRIGGER},
 			false},
 		{catpb.NewPrivilegeDescriptor(testUser, privilege.List{privilege.ALL}, privilege.List{privilege.ALL}, username.AdminRoleName()),
 			testUser, privilege.Table,
@@ -643,8 +644,8 @@ func TestRevokeWithGrantOption(t *testing.T) {
 			testUser, privilege.Table,
 			false,
 			privilege.List{privilege.CREATE},
-			privilege.List{privilege.BACKUP, privilege.CHANGEFEED, privilege.DROP, privilege.SELECT, privilege.INSERT, privilege.DELETE, privilege.UPDATE, privilege.ZONECONFIG},
-			privilege.List{privilege.BACKUP, privilege.CHANGEFEED, privilege.DROP, privilege.SELECT, privilege.INSERT, privilege.DELETE, privilege.UPDATE, privilege.ZONECONFIG},
+			privilege.List{privilege.BACKUP, privilege.CHANGEFEED, privilege.DROP, privilege.SELECT, privilege.INSERT, privilege.DELETE, privilege.UPDATE, privilege.ZONECONFIG, privilege.TRIGGER},
+			privilege.List{privilege.BACKUP, privilege.CHANGEFEED, privilege.DROP, privilege.SELECT, privilege.INSERT, privilege.DELETE, privilege.UPDATE, privilege.ZONECONFIG, privilege.TRIGGER},
 			false},
 		{catpb.NewPrivilegeDescriptor(testUser, privilege.List{privilege.SELECT, privilege.INSERT}, privilege.List{privilege.INSERT}, username.AdminRoleName()),
 			testUser, privilege.Table,
@@ -684,3 +685,38 @@ func TestRevokeWithGrantOption(t *testing.T) {
 		}
 	}
 }
+
+// TestRemoveKind tests that RemoveKind strips a privilege and its grant option
+// from every user without expanding ALL.
+func TestRemoveKind(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testUser := username.TestUserName()
+	barUser := username.MakeSQLUsernameFromPreNormalizedString("bar")
+	bazUser := username.MakeSQLUsernameFromPreNormalizedString("baz")
+
+	descriptor := catpb.NewBasePrivilegeDescriptor(username.AdminRoleName())
+	descriptor.Grant(testUser, privilege.List{privilege.ALL}, true /* withGrantOption */)
+	descriptor.Grant(barUser, privilege.List{privilege.SELECT, privilege.TRIGGER}, true /* withGrantOption */)
+	descriptor.Grant(bazUser, privilege.List{privilege.TRIGGER}, false /* withGrantOption */)
+
+	descriptor.RemoveKind(privilege.TRIGGER)
+
+	testPrivs, ok := descriptor.FindUser(testUser)
+	if !ok {
+		t.Fatalf("expected %s to be present", testUser)
+	}
+	if testPrivs.Privileges != privilege.ALL.Mask() || testPrivs.WithGrantOption != privilege.ALL.Mask() {
+		t.Errorf("expected %s to keep ALL, got %v", testUser, testPrivs)
+	}
+	barPrivs, ok := descriptor.FindUser(barUser)
+	if !ok {
+		t.Fatalf("expected %s to be present", barUser)
+	}
+	if barPrivs.Privileges != privilege.SELECT.Mask() || barPrivs.WithGrantOption != privilege.SELECT.Mask() {
+		t.Errorf("expected %s to have only SELECT, got %v", barUser, barPrivs)
+	}
+	if _, ok := descriptor.FindUser(bazUser); ok {
+		t.Errorf("expected %s to be removed", bazUser)
+	}
+}
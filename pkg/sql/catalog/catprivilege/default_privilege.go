@@ -109,6 +109,18 @@ func (d *immutable) grantOrRevokeDefaultPrivilegesHelper(
 	return nil
 }
 
+// RemoveKind removes a privilege from the default privileges of every role
+// for the specified object type.
+func (d *Mutable) RemoveKind(kind privilege.Kind, targetObject privilege.TargetObjectType) {
+	for i := range d.defaultPrivilegeDescriptor.DefaultPrivilegesPerRole {
+		perObject := d.defaultPrivilegeDescriptor.DefaultPrivilegesPerRole[i].DefaultPrivilegesPerObject
+		if privs, ok := perObject[targetObject]; ok {
+			privs.RemoveKind(kind)
+			perObject[targetObject] = privs
+		}
+	}
+}
+
 // GrantDefaultPrivileges grants privileges for the specified users.
 func (d *Mutable) GrantDefaultPrivileges(
 	role catpb.DefaultPrivilegesRole,
@@ -140,6 +140,22 @@ func ValidateColumnDefType(ctx context.Context, version clusterversion.Handle, t
 			)
 		}
 
+	case types.JsonpathFamily:
+		if !version.IsActive(ctx, clusterversion.V24_1_JsonpathType) {
+			return pgerror.Newf(
+				pgcode.FeatureNotSupported,
+				"jsonpath not supported until version 24.1",
+			)
+		}
+
+	case types.RangeFamily, types.MultirangeFamily:
+		if !version.IsActive(ctx, clusterversion.V24_1_RangeTypes) {
+			return pgerror.Newf(
+				pgcode.FeatureNotSupported,
+				"range types not supported until version 24.1",
+			)
+		}
+
 	default:
 		return pgerror.Newf(pgcode.InvalidTableDefinition,
 			"value type %s cannot be used for table columns", t.String())
@@ -192,6 +208,7 @@ func ColumnTypeIsOnlyInvertedIndexable(t *types.T) bool {
 	case types.GeographyFamily:
 	case types.GeometryFamily:
 	case types.TSVectorFamily:
+	case types.RangeFamily, types.MultirangeFamily:
 	default:
 		return false
 	}
@@ -211,7 +228,9 @@ func MustBeValueEncoded(semanticType *types.T) bool {
 		}
 	case types.TupleFamily, types.GeographyFamily, types.GeometryFamily:
 		return true
-	case types.TSVectorFamily, types.TSQueryFamily:
+	case types.TSVectorFamily, types.TSQueryFamily, types.JsonpathFamily:
+		return true
+	case types.RangeFamily, types.MultirangeFamily:
 		return true
 	}
 	return false
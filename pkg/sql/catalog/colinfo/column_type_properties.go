@@ -84,10 +84,14 @@ func CanHaveCompositeKeyEncoding(typ *types.T) bool {
 		types.Box2DFamily,
 		types.PGLSNFamily,
 		types.RefCursorFamily,
+		types.TriggerFamily,
 		types.VoidFamily,
 		types.EncodedKeyFamily,
 		types.TSQueryFamily,
-		types.TSVectorFamily:
+		types.TSVectorFamily,
+		types.JsonpathFamily,
+		types.RangeFamily,
+		types.MultirangeFamily:
 		return false
 	case types.UnknownFamily,
 		types.AnyFamily:
@@ -360,3 +360,18 @@ var IdentifySystemColumns = ResultColumns{
 	{Name: "xlogpos", Typ: types.String},
 	{Name: "dbname", Typ: types.String},
 }
+
+// CreateReplicationSlotColumns is the schema for CREATE_REPLICATION_SLOT.
+var CreateReplicationSlotColumns = ResultColumns{
+	{Name: "slot_name", Typ: types.String},
+	{Name: "consistent_point", Typ: types.String},
+	{Name: "snapshot_name", Typ: types.String},
+	{Name: "output_plugin", Typ: types.String},
+}
+
+// ReadReplicationSlotColumns is the schema for READ_REPLICATION_SLOT.
+var ReadReplicationSlotColumns = ResultColumns{
+	{Name: "slot_type", Typ: types.String},
+	{Name: "restart_lsn", Typ: types.String},
+	{Name: "restart_tli", Typ: types.Int},
+}
@@ -256,6 +256,71 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 	}
 
 	desc.maybeValidateSystemDatabaseSchemaVersion(vea)
+	desc.validatePublications(vea)
+	desc.validateTextSearchObjects(vea)
+}
+
+// validatePublications checks that the publications have distinct names, and
+// that the publications for all tables don't list tables.
+func (desc *immutable) validatePublications(vea catalog.ValidationErrorAccumulator) {
+	names := make(map[string]struct{}, len(desc.Publications))
+	for i := range desc.Publications {
+		pub := &desc.Publications[i]
+		if pub.Name == "" {
+			vea.Report(errors.AssertionFailedf("empty publication name"))
+		}
+		if _, ok := names[pub.Name]; ok {
+			vea.Report(errors.AssertionFailedf("duplicate publication name: %q", pub.Name))
+		}
+		names[pub.Name] = struct{}{}
+		if pub.AllTables && len(pub.TableIDs) > 0 {
+			vea.Report(errors.AssertionFailedf(
+				"publication %q for all tables has table IDs %v", pub.Name, pub.TableIDs,
+			))
+		}
+	}
+}
+
+// validateTextSearchObjects checks that the text search dictionaries and
+// configurations have distinct names, and that the configurations map each
+// token type at most once.
+func (desc *immutable) validateTextSearchObjects(vea catalog.ValidationErrorAccumulator) {
+	dictNames := make(map[string]struct{}, len(desc.TextSearchDictionaries))
+	for i := range desc.TextSearchDictionaries {
+		dict := &desc.TextSearchDictionaries[i]
+		if dict.Name == "" {
+			vea.Report(errors.AssertionFailedf("empty text search dictionary name"))
+		}
+		if _, ok := dictNames[dict.Name]; ok {
+			vea.Report(errors.AssertionFailedf("duplicate text search dictionary name: %q", dict.Name))
+		}
+		dictNames[dict.Name] = struct{}{}
+	}
+	configNames := make(map[string]struct{}, len(desc.TextSearchConfigurations))
+	for i := range desc.TextSearchConfigurations {
+		config := &desc.TextSearchConfigurations[i]
+		if config.Name == "" {
+			vea.Report(errors.AssertionFailedf("empty text search configuration name"))
+		}
+		if _, ok := configNames[config.Name]; ok {
+			vea.Report(errors.AssertionFailedf("duplicate text search configuration name: %q", config.Name))
+		}
+		configNames[config.Name] = struct{}{}
+		tokenTypes := make(map[string]struct{}, len(config.Mappings))
+		for _, m := range config.Mappings {
+			if _, ok := tokenTypes[m.TokenType]; ok {
+				vea.Report(errors.AssertionFailedf(
+					"text search configuration %q maps token type %q more than once", config.Name, m.TokenType,
+				))
+			}
+			tokenTypes[m.TokenType] = struct{}{}
+			if len(m.Dictionaries) == 0 {
+				vea.Report(errors.AssertionFailedf(
+					"text search configuration %q maps token type %q to no dictionaries", config.Name, m.TokenType,
+				))
+			}
+		}
+	}
 }
 
 // validateMultiRegion performs checks specific to multi-region DBs.
@@ -552,6 +617,127 @@ func (desc *Mutable) AddSchemaToDatabase(
 	desc.Schemas[schemaName] = schemaInfo
 }
 
+// GetPublication implements the DatabaseDescriptor interface.
+func (desc *immutable) GetPublication(name string) *descpb.PublicationDescriptor {
+	for i := range desc.Publications {
+		if desc.Publications[i].Name == name {
+			return &desc.Publications[i]
+		}
+	}
+	return nil
+}
+
+// AddPublication adds a publication to the database.
+func (desc *Mutable) AddPublication(pub descpb.PublicationDescriptor) {
+	desc.Publications = append(desc.Publications, pub)
+}
+
+// RemovePublication removes the publication with the given name from the
+// database, if it exists.
+func (desc *Mutable) RemovePublication(name string) {
+	for i := range desc.Publications {
+		if desc.Publications[i].Name == name {
+			desc.Publications = append(desc.Publications[:i], desc.Publications[i+1:]...)
+			return
+		}
+	}
+}
+
+// GetTextSearchDictionary implements the DatabaseDescriptor interface.
+func (desc *immutable) GetTextSearchDictionary(
+	name string,
+) *descpb.TextSearchDictionaryDescriptor {
+	for i := range desc.TextSearchDictionaries {
+		if desc.TextSearchDictionaries[i].Name == name {
+			return &desc.TextSearchDictionaries[i]
+		}
+	}
+	return nil
+}
+
+// GetTextSearchConfiguration implements the DatabaseDescriptor interface.
+func (desc *immutable) GetTextSearchConfiguration(
+	name string,
+) *descpb.TextSearchConfigurationDescriptor {
+	for i := range desc.TextSearchConfigurations {
+		if desc.TextSearchConfigurations[i].Name == name {
+			return &desc.TextSearchConfigurations[i]
+		}
+	}
+	return nil
+}
+
+// AddTextSearchDictionary adds a text search dictionary to the database.
+func (desc *Mutable) AddTextSearchDictionary(dict descpb.TextSearchDictionaryDescriptor) {
+	desc.TextSearchDictionaries = append(desc.TextSearchDictionaries, dict)
+}
+
+// RemoveTextSearchDictionary removes the text search dictionary with the given
+// name from the database, if it exists.
+func (desc *Mutable) RemoveTextSearchDictionary(name string) {
+	for i := range desc.TextSearchDictionaries {
+		if desc.TextSearchDictionaries[i].Name == name {
+			desc.TextSearchDictionaries = append(
+				desc.TextSearchDictionaries[:i], desc.TextSearchDictionaries[i+1:]...,
+			)
+			return
+		}
+	}
+}
+
+// AddTextSearchConfiguration adds a text search configuration to the
+// database.
+func (desc *Mutable) AddTextSearchConfiguration(config descpb.TextSearchConfigurationDescriptor) {
+	desc.TextSearchConfigurations = append(desc.TextSearchConfigurations, config)
+}
+
+// AddTextSearchConfigurationReference records that the table or view with the
+// given ID uses the text search configuration with the given name. It returns
+// false if the configuration doesn't exist or the reference already exists.
+func (desc *Mutable) AddTextSearchConfigurationReference(name string, id descpb.ID) bool {
+	config := desc.GetTextSearchConfiguration(name)
+	if config == nil {
+		return false
+	}
+	for _, ref := range config.DependedOnBy {
+		if ref == id {
+			return false
+		}
+	}
+	config.DependedOnBy = append(config.DependedOnBy, id)
+	return true
+}
+
+// RemoveTextSearchConfigurationReference removes the reference of the table or
+// view with the given ID to the text search configuration with the given name.
+// It returns false if there is no such reference.
+func (desc *Mutable) RemoveTextSearchConfigurationReference(name string, id descpb.ID) bool {
+	config := desc.GetTextSearchConfiguration(name)
+	if config == nil {
+		return false
+	}
+	for i, ref := range config.DependedOnBy {
+		if ref == id {
+			config.DependedOnBy = append(config.DependedOnBy[:i], config.DependedOnBy[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveTextSearchConfiguration removes the text search configuration with
+// the given name from the database, if it exists.
+func (desc *Mutable) RemoveTextSearchConfiguration(name string) {
+	for i := range desc.TextSearchConfigurations {
+		if desc.TextSearchConfigurations[i].Name == name {
+			desc.TextSearchConfigurations = append(
+				desc.TextSearchConfigurations[:i], desc.TextSearchConfigurations[i+1:]...,
+			)
+			return
+		}
+	}
+}
+
 // GetDeclarativeSchemaChangerState is part of the catalog.MutableDescriptor
 // interface.
 func (desc *immutable) GetDeclarativeSchemaChangerState() *scpb.DescriptorState {
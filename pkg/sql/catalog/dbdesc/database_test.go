@@ -113,6 +113,50 @@ func TestValidateDatabaseDesc(t *testing.T) {
 				Privileges:   catpb.NewBaseDatabasePrivilegeDescriptor(username.RootUserName()),
 			},
 		},
+		{
+			`duplicate publication name: "p"`,
+			descpb.DatabaseDescriptor{
+				Name:         "db",
+				ID:           200,
+				Privileges:   catpb.NewBaseDatabasePrivilegeDescriptor(username.RootUserName()),
+				Publications: []descpb.PublicationDescriptor{{Name: "p"}, {Name: "p", AllTables: true}},
+			},
+		},
+		{
+			`publication "p" for all tables has table IDs [104]`,
+			descpb.DatabaseDescriptor{
+				Name:         "db",
+				ID:           200,
+				Privileges:   catpb.NewBaseDatabasePrivilegeDescriptor(username.RootUserName()),
+				Publications: []descpb.PublicationDescriptor{{Name: "p", AllTables: true, TableIDs: []descpb.ID{104}}},
+			},
+		},
+		{
+			`duplicate text search dictionary name: "d"`,
+			descpb.DatabaseDescriptor{
+				Name:       "db",
+				ID:         200,
+				Privileges: catpb.NewBaseDatabasePrivilegeDescriptor(username.RootUserName()),
+				TextSearchDictionaries: []descpb.TextSearchDictionaryDescriptor{
+					{Name: "d", Template: "simple"}, {Name: "d", Template: "synonym"},
+				},
+			},
+		},
+		{
+			`text search configuration "c" maps token type "word" more than once`,
+			descpb.DatabaseDescriptor{
+				Name:       "db",
+				ID:         200,
+				Privileges: catpb.NewBaseDatabasePrivilegeDescriptor(username.RootUserName()),
+				TextSearchConfigurations: []descpb.TextSearchConfigurationDescriptor{{
+					Name: "c",
+					Mappings: []descpb.TextSearchConfigurationDescriptor_Mapping{
+						{TokenType: "word", Dictionaries: []string{"simple"}},
+						{TokenType: "word", Dictionaries: []string{"english_stem"}},
+					},
+				}},
+			},
+		},
 	}
 	for i, d := range testData {
 		t.Run(d.err, func(t *testing.T) {
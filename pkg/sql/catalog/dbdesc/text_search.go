@@ -0,0 +1,209 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package dbdesc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
+)
+
+// TextSearchConfigRef returns the reference to the text search configuration
+// with the given name in the given database. The expressions of tables and
+// views store such references instead of the names of the user-defined
+// configurations they use, so that they use the same configurations in all
+// sessions, whatever their current database.
+func TextSearchConfigRef(dbID descpb.ID, name string) string {
+	return fmt.Sprintf("@%d.%s", dbID, name)
+}
+
+// ParseTextSearchConfigRef returns the database ID and the name of the text
+// search configuration of a reference returned by TextSearchConfigRef. It
+// returns false if the given string is not such a reference.
+func ParseTextSearchConfigRef(ref string) (dbID descpb.ID, name string, ok bool) {
+	if !strings.HasPrefix(ref, "@") {
+		return 0, "", false
+	}
+	i := strings.IndexByte(ref, '.')
+	if i < 0 {
+		return 0, "", false
+	}
+	id, err := strconv.ParseUint(ref[1:i], 10, 32)
+	if err != nil {
+		return 0, "", false
+	}
+	return descpb.ID(id), ref[i+1:], true
+}
+
+// DatabaseGetter returns the descriptor of the database with the given ID.
+type DatabaseGetter func(ctx context.Context, id descpb.ID) (catalog.DatabaseDescriptor, error)
+
+// TextSearchResolver resolves the built-in text search configurations and
+// dictionaries, and the ones defined in a database. The configurations and
+// dictionaries built from the database descriptor are cached, so that they
+// are not rebuilt for each row. Descriptors are immutable, so the cache is
+// valid for as long as the resolver.
+type TextSearchResolver struct {
+	db      catalog.DatabaseDescriptor
+	configs map[string]*tsearch.Config
+	dicts   map[string]*tsearch.Dictionary
+
+	// getDatabase, if set, is used to resolve references to the
+	// configurations of other databases, with the resolvers in others.
+	getDatabase DatabaseGetter
+	others      map[descpb.ID]*TextSearchResolver
+}
+
+// NewTextSearchResolver returns a TextSearchResolver for the given database,
+// which may be nil if only the built-in objects can be resolved. If
+// getDatabase is nil, references to the configurations of other databases
+// cannot be resolved.
+func NewTextSearchResolver(
+	db catalog.DatabaseDescriptor, getDatabase DatabaseGetter,
+) *TextSearchResolver {
+	return &TextSearchResolver{
+		db:          db,
+		configs:     make(map[string]*tsearch.Config),
+		dicts:       make(map[string]*tsearch.Dictionary),
+		getDatabase: getDatabase,
+	}
+}
+
+// Database returns the database of the resolver.
+func (r *TextSearchResolver) Database() catalog.DatabaseDescriptor {
+	return r.db
+}
+
+// Config returns the text search configuration with the given name, which is
+// either the name of a configuration of the database of the resolver, or a
+// reference returned by TextSearchConfigRef.
+func (r *TextSearchResolver) Config(ctx context.Context, name string) (*tsearch.Config, error) {
+	if tsearch.IsBuiltinConfig(name) {
+		return tsearch.GetBuiltinConfig(name)
+	}
+	if dbID, refName, ok := ParseTextSearchConfigRef(name); ok {
+		if r.db == nil || r.db.GetID() != dbID {
+			other, err := r.otherDatabase(ctx, dbID)
+			if err != nil {
+				return nil, err
+			}
+			return other.Config(ctx, refName)
+		}
+		name = refName
+	}
+	if config, ok := r.configs[name]; ok {
+		return config, nil
+	}
+	if r.db == nil || r.db.GetTextSearchConfiguration(name) == nil {
+		return nil, pgerror.Newf(pgcode.UndefinedObject, "text search configuration %q does not exist", name)
+	}
+	desc := r.db.GetTextSearchConfiguration(name)
+	config := &tsearch.Config{
+		Name:     desc.Name,
+		Mappings: make(map[tsearch.TokenType][]*tsearch.Dictionary, len(desc.Mappings)),
+	}
+	for _, m := range desc.Mappings {
+		tokenType, err := tsearch.TokenTypeFromName(m.TokenType)
+		if err != nil {
+			return nil, err
+		}
+		for _, dictName := range m.Dictionaries {
+			dict, err := r.Dictionary(dictName)
+			if err != nil {
+				return nil, err
+			}
+			config.Mappings[tokenType] = append(config.Mappings[tokenType], dict)
+		}
+	}
+	r.configs[name] = config
+	return config, nil
+}
+
+// otherDatabase returns the resolver of the database with the given ID, which
+// is not the database of r.
+func (r *TextSearchResolver) otherDatabase(
+	ctx context.Context, id descpb.ID,
+) (*TextSearchResolver, error) {
+	if r.getDatabase == nil {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"cross database text search configuration references are not supported")
+	}
+	db, err := r.getDatabase(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	other := r.others[id]
+	if other == nil || other.db != db {
+		other = NewTextSearchResolver(db, r.getDatabase)
+		if r.others == nil {
+			r.others = make(map[descpb.ID]*TextSearchResolver)
+		}
+		r.others[id] = other
+	}
+	return other, nil
+}
+
+// Dictionary returns the text search dictionary with the given name.
+func (r *TextSearchResolver) Dictionary(name string) (*tsearch.Dictionary, error) {
+	if tsearch.IsBuiltinDictionary(name) {
+		return tsearch.GetBuiltinDictionary(name)
+	}
+	if dict, ok := r.dicts[name]; ok {
+		return dict, nil
+	}
+	var desc *descpb.TextSearchDictionaryDescriptor
+	if r.db != nil {
+		desc = r.db.GetTextSearchDictionary(name)
+	}
+	if desc == nil {
+		return nil, pgerror.Newf(pgcode.UndefinedObject, "text search dictionary %q does not exist", name)
+	}
+	template, err := tsearch.DictionaryTemplateFromName(desc.Template)
+	if err != nil {
+		return nil, err
+	}
+	dict := &tsearch.Dictionary{
+		Name:     desc.Name,
+		Template: template,
+		Language: desc.Language,
+		Accept:   desc.Accept,
+	}
+	if desc.StopWords != "" || len(desc.StopWordList) > 0 {
+		dict.StopWords = make(map[string]struct{}, len(desc.StopWordList))
+		if desc.StopWords != "" {
+			stopWords, err := tsearch.GetStopWords(desc.StopWords)
+			if err != nil {
+				return nil, err
+			}
+			for word := range stopWords {
+				dict.StopWords[word] = struct{}{}
+			}
+		}
+		for _, word := range desc.StopWordList {
+			dict.StopWords[word] = struct{}{}
+		}
+	}
+	if len(desc.Synonyms) > 0 {
+		dict.Synonyms = make(map[string]string, len(desc.Synonyms))
+		for _, s := range desc.Synonyms {
+			dict.Synonyms[s.Word] = s.Synonym
+		}
+	}
+	r.dicts[name] = dict
+	return dict, nil
+}
@@ -74,6 +74,12 @@ type IndexID = catid.IndexID
 // ConstraintID is a custom type for TableDescriptor constraint IDs.
 type ConstraintID = catid.ConstraintID
 
+// TriggerID is a custom type for TableDescriptor trigger IDs.
+type TriggerID = catid.TriggerID
+
+// PolicyID is a custom type for TableDescriptor policy IDs.
+type PolicyID = catid.PolicyID
+
 // DescriptorVersion is a custom type for TableDescriptor Versions.
 type DescriptorVersion uint64
 
@@ -306,6 +312,17 @@ func (opts *TableDescriptor_SequenceOpts) EffectiveCacheSize() int64 {
 	return opts.CacheSize
 }
 
+// FuncIDs returns the IDs of the functions that the aggregate is defined with,
+// in the order transition and final function. The final function is omitted if
+// it is not set.
+func (agg *FunctionDescriptor_Aggregate) FuncIDs() []ID {
+	ids := []ID{agg.TransitionFuncID}
+	if agg.FinalFuncID != InvalidID {
+		ids = append(ids, agg.FinalFuncID)
+	}
+	return ids
+}
+
 // SafeValue implements the redact.SafeValue interface.
 func (ConstraintValidity) SafeValue() {}
 
@@ -295,6 +295,23 @@ type DatabaseDescriptor interface {
 	// HasPublicSchemaWithDescriptor returns true iff the database has a public
 	// schema which itself has a descriptor.
 	HasPublicSchemaWithDescriptor() bool
+	// GetPublications returns the publications defined in this database.
+	GetPublications() []descpb.PublicationDescriptor
+	// GetPublication returns the publication with the given name, or nil if it
+	// doesn't exist.
+	GetPublication(name string) *descpb.PublicationDescriptor
+	// GetTextSearchDictionaries returns the text search dictionaries defined in
+	// this database.
+	GetTextSearchDictionaries() []descpb.TextSearchDictionaryDescriptor
+	// GetTextSearchDictionary returns the text search dictionary with the given
+	// name, or nil if it doesn't exist.
+	GetTextSearchDictionary(name string) *descpb.TextSearchDictionaryDescriptor
+	// GetTextSearchConfigurations returns the text search configurations
+	// defined in this database.
+	GetTextSearchConfigurations() []descpb.TextSearchConfigurationDescriptor
+	// GetTextSearchConfiguration returns the text search configuration with the
+	// given name, or nil if it doesn't exist.
+	GetTextSearchConfiguration(name string) *descpb.TextSearchConfigurationDescriptor
 }
 
 // TableDescriptor is an interface around the table descriptor types.
@@ -629,6 +646,11 @@ type TableDescriptor interface {
 	// GetDependsOnFunctions returns the IDs of all functions that this view
 	// depends on. It's only non-nil if IsView is true.
 	GetDependsOnFunctions() []descpb.ID
+	// GetTriggers returns the triggers defined on this table.
+	GetTriggers() []descpb.TriggerDescriptor
+	// GetPolicies returns the row-level security policies defined on this
+	// table.
+	GetPolicies() []descpb.PolicyDescriptor
 
 	// AllConstraints returns all constraints in this table, regardless if
 	// they're enforced yet or not. The ordering of the constraints within this
@@ -680,6 +702,13 @@ type TableDescriptor interface {
 	// in the same order.
 	EnforcedUniqueConstraintsWithoutIndex() []UniqueWithoutIndexConstraint
 
+	// ExclusionConstraints returns the subset of exclusion constraints in
+	// AllConstraints for this table, in the same order.
+	ExclusionConstraints() []ExclusionConstraint
+	// EnforcedExclusionConstraints returns the subset of exclusion constraints
+	// in EnforcedConstraints for this table, in the same order.
+	EnforcedExclusionConstraints() []ExclusionConstraint
+
 	// CheckConstraintColumns returns the slice of columns referenced by a check
 	// constraint.
 	CheckConstraintColumns(ck CheckConstraint) []Column
@@ -692,6 +721,8 @@ type TableDescriptor interface {
 	// UniqueWithoutIndexColumns returns the slice of columns which are
 	// defined as unique by a non-index-backed constraint.
 	UniqueWithoutIndexColumns(uwoi UniqueWithoutIndexConstraint) []Column
+	// ExclusionColumns returns the slice of columns in an exclusion constraint.
+	ExclusionColumns(ec ExclusionConstraint) []Column
 
 	// GetLocalityConfig returns the locality config for this table, which
 	// describes the table's multi-region locality policy if one is set (e.g.
@@ -759,6 +790,12 @@ type TableDescriptor interface {
 	// IsSchemaLocked returns true if we don't allow performing schema changes
 	// on this table descriptor.
 	IsSchemaLocked() bool
+	// IsRowLevelSecurityEnabled returns true if the row-level security policies
+	// of this table are enforced.
+	IsRowLevelSecurityEnabled() bool
+	// IsRowLevelSecurityForced returns true if the row-level security policies
+	// of this table are also enforced for the table owner.
+	IsRowLevelSecurityForced() bool
 }
 
 // MutableTableDescriptor is both a MutableDescriptor and a TableDescriptor.
@@ -815,6 +852,10 @@ type TypeDescriptor interface {
 	// nil otherwise.
 	AsCompositeTypeDescriptor() CompositeTypeDescriptor
 
+	// AsDomainTypeDescriptor returns this instance cast to DomainTypeDescriptor
+	// if this type is a domain, nil otherwise.
+	AsDomainTypeDescriptor() DomainTypeDescriptor
+
 	// AsTableImplicitRecordTypeDescriptor returns this instance cast to
 	// TableImplicitRecordTypeDescriptor if this type is an implicit table record
 	// type, nil otherwise.
@@ -902,6 +943,37 @@ type CompositeTypeDescriptor interface {
 	GetElementType(ordinal int) *types.T
 }
 
+// DomainTypeDescriptor is the TypeDescriptor subtype for domains, which are
+// base types with optional NOT NULL and CHECK constraints.
+type DomainTypeDescriptor interface {
+	NonAliasTypeDescriptor
+
+	// BaseType returns the type that the domain is defined over.
+	BaseType() *types.T
+
+	// IsNotNull returns true if the domain does not allow NULL values,
+	// including while the NOT NULL constraint is being validated.
+	IsNotNull() bool
+
+	// NumCheckConstraints returns the number of CHECK constraints of the
+	// domain.
+	NumCheckConstraints() int
+
+	// GetCheckConstraintName returns the name of the CHECK constraint at the
+	// given ordinal.
+	GetCheckConstraintName(ordinal int) string
+
+	// GetCheckConstraintExpr returns the serialized expression of the CHECK
+	// constraint at the given ordinal, in which the value being checked is
+	// referenced as VALUE.
+	GetCheckConstraintExpr(ordinal int) string
+
+	// IsCheckConstraintValidated returns true if the values of the domain
+	// stored in existing columns are known to satisfy the CHECK constraint at
+	// the given ordinal.
+	IsCheckConstraintValidated(ordinal int) bool
+}
+
 // TableImplicitRecordTypeDescriptor is the TypeDescriptor subtype for the
 // record type implicitly defined by a table.
 type TableImplicitRecordTypeDescriptor interface {
@@ -982,6 +1054,10 @@ type FunctionDescriptor interface {
 	// IsProcedure returns true if the descriptor represents a procedure. It
 	// returns false if the descriptor represents a user-defined function.
 	IsProcedure() bool
+
+	// IsAggregate returns true if the descriptor represents a user-defined
+	// aggregate function.
+	IsAggregate() bool
 }
 
 // FilterDroppedDescriptor returns an error if the descriptor state is DROP.
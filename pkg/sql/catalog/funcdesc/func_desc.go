@@ -175,6 +175,11 @@ func (desc *immutable) GetReferencedDescIDs() (catalog.DescriptorIDSet, error) {
 	for _, dep := range desc.DependedOnBy {
 		ret.Add(dep.ID)
 	}
+	if desc.Aggregate != nil {
+		for _, id := range desc.Aggregate.FuncIDs() {
+			ret.Add(id)
+		}
+	}
 
 	return ret, nil
 }
@@ -228,6 +233,18 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 			vea.Report(errors.AssertionFailedf("invalid type id %d in depends-on-types references #%d", typeID, i))
 		}
 	}
+
+	if agg := desc.Aggregate; agg != nil {
+		if agg.TransitionFuncID == descpb.InvalidID {
+			vea.Report(errors.AssertionFailedf("transition function not set for aggregate"))
+		}
+		if agg.StateType == nil {
+			vea.Report(errors.AssertionFailedf("state type not set for aggregate"))
+		}
+		if desc.IsProcedure() {
+			vea.Report(errors.AssertionFailedf("procedure cannot be an aggregate"))
+		}
+	}
 }
 
 // ValidateForwardReferences implements the catalog.Descriptor interface.
@@ -257,6 +274,17 @@ func (desc *immutable) ValidateForwardReferences(
 	for _, typeID := range desc.DependsOnTypes {
 		vea.Report(catalog.ValidateOutboundTypeRef(typeID, vdg))
 	}
+
+	if desc.Aggregate != nil {
+		for _, fnID := range desc.Aggregate.FuncIDs() {
+			fn, err := vdg.GetFunctionDescriptor(fnID)
+			if err != nil {
+				vea.Report(errors.NewAssertionErrorWithWrappedErrf(err, "invalid aggregate support function reference"))
+			} else if fn.Dropped() {
+				vea.Report(errors.AssertionFailedf("aggregate support function %q (%d) is dropped", fn.GetName(), fn.GetID()))
+			}
+		}
+	}
 }
 
 // ValidateBackReferences implements the catalog.Descriptor interface.
@@ -278,13 +306,60 @@ func (desc *immutable) ValidateBackReferences(
 		vea.Report(catalog.ValidateOutboundTypeRefBackReference(desc.GetID(), typ))
 	}
 
-	// Currently, we don't support cross function references yet.
-	// So here we assume that all inbound references are from tables.
+	if desc.Aggregate != nil {
+		for _, fnID := range desc.Aggregate.FuncIDs() {
+			fn, err := vdg.GetFunctionDescriptor(fnID)
+			if err != nil {
+				continue
+			}
+			vea.Report(desc.validateOutboundFuncRefBackReference(fn))
+		}
+	}
+
+	// The only functions that can reference other functions are aggregates,
+	// which reference their support functions. All other inbound references
+	// are from tables.
 	for _, by := range desc.DependedOnBy {
+		if d, err := vdg.GetDescriptor(by.ID); err == nil && d.DescriptorType() == catalog.Function {
+			vea.Report(desc.validateInboundFuncRef(by, vdg))
+			continue
+		}
 		vea.Report(desc.validateInboundTableRef(by, vdg))
 	}
 }
 
+func (desc *immutable) validateOutboundFuncRefBackReference(fn catalog.FunctionDescriptor) error {
+	for _, by := range fn.GetDependedOnBy() {
+		if by.ID == desc.GetID() {
+			return nil
+		}
+	}
+	return errors.AssertionFailedf("depends-on function %q (%d) has no corresponding depended-on-by back reference",
+		fn.GetName(), fn.GetID())
+}
+
+func (desc *immutable) validateInboundFuncRef(
+	by descpb.FunctionDescriptor_Reference, vdg catalog.ValidationDescGetter,
+) error {
+	backRefFn, err := vdg.GetFunctionDescriptor(by.ID)
+	if err != nil {
+		return errors.NewAssertionErrorWithWrappedErrf(err, "invalid depended-on-by function back reference")
+	}
+	if backRefFn.Dropped() {
+		return errors.AssertionFailedf("depended-on-by function %q (%d) is dropped",
+			backRefFn.GetName(), backRefFn.GetID())
+	}
+	if agg := backRefFn.FuncDesc().Aggregate; agg != nil {
+		for _, id := range agg.FuncIDs() {
+			if id == desc.GetID() {
+				return nil
+			}
+		}
+	}
+	return errors.AssertionFailedf("depended-on-by function %q (%d) has no corresponding depends-on forward reference",
+		backRefFn.GetName(), by.ID)
+}
+
 func (desc *immutable) validateFuncExistsInSchema(scDesc catalog.SchemaDescriptor) error {
 	// Check that parent Schema contains the matching function signature.
 	if _, ok := scDesc.GetFunction(desc.GetName()); !ok {
@@ -371,6 +446,22 @@ func (desc *immutable) validateInboundTableRef(
 			cstID, backRefTbl.GetName(), backRefTbl.GetID(), desc.GetName(), desc.GetID(),
 		)
 	}
+
+	for _, triggerID := range by.TriggerIDs {
+		trigger := catalog.FindTriggerByID(backRefTbl, triggerID)
+		if trigger == nil {
+			return errors.AssertionFailedf("depended-on-by relation %q (%d) does not have a trigger with ID %d",
+				backRefTbl.GetName(), by.ID, triggerID)
+		}
+		if trigger.FuncID == desc.GetID() {
+			foundInTable = true
+			continue
+		}
+		return errors.AssertionFailedf(
+			"trigger %d in depended-on-by relation %q (%d) does not have reference to function %q (%d)",
+			triggerID, backRefTbl.GetName(), backRefTbl.GetID(), desc.GetName(), desc.GetID(),
+		)
+	}
 	if foundInTable {
 		return nil
 	}
@@ -418,14 +509,19 @@ func (desc *immutable) GetRawBytesInStorage() []byte {
 // ForEachUDTDependentForHydration implements the catalog.Descriptor interface.
 func (desc *immutable) ForEachUDTDependentForHydration(fn func(t *types.T) error) error {
 	for _, p := range desc.Params {
-		if !catid.IsOIDUserDefined(p.Type.Oid()) {
+		if !p.Type.UserDefined() {
 			continue
 		}
 		if err := fn(p.Type); err != nil {
 			return iterutil.Map(err)
 		}
 	}
-	if !catid.IsOIDUserDefined(desc.ReturnType.Type.Oid()) {
+	if desc.Aggregate != nil && desc.Aggregate.StateType.UserDefined() {
+		if err := fn(desc.Aggregate.StateType); err != nil {
+			return iterutil.Map(err)
+		}
+	}
+	if !desc.ReturnType.Type.UserDefined() {
 		return nil
 	}
 	return iterutil.Map(fn(desc.ReturnType.Type))
@@ -635,14 +731,69 @@ func (desc *Mutable) RemoveColumnReference(id descpb.ID, colID descpb.ColumnID)
 	}
 }
 
+// AddTriggerReference adds back reference to a trigger to the function.
+func (desc *Mutable) AddTriggerReference(id descpb.ID, triggerID descpb.TriggerID) error {
+	for _, dep := range desc.DependsOn {
+		if dep == id {
+			return errors.Errorf(
+				"cannot add dependency from descriptor %d to function %s (%d) because there will be a dependency cycle", id, desc.GetName(), desc.GetID(),
+			)
+		}
+	}
+	for i := range desc.DependedOnBy {
+		if desc.DependedOnBy[i].ID == id {
+			for _, existing := range desc.DependedOnBy[i].TriggerIDs {
+				if existing == triggerID {
+					return nil
+				}
+			}
+			desc.DependedOnBy[i].TriggerIDs = append(desc.DependedOnBy[i].TriggerIDs, triggerID)
+			sort.Slice(desc.DependedOnBy[i].TriggerIDs, func(a, b int) bool {
+				return desc.DependedOnBy[i].TriggerIDs[a] < desc.DependedOnBy[i].TriggerIDs[b]
+			})
+			return nil
+		}
+	}
+	desc.DependedOnBy = append(
+		desc.DependedOnBy,
+		descpb.FunctionDescriptor_Reference{
+			ID:         id,
+			TriggerIDs: []descpb.TriggerID{triggerID},
+		},
+	)
+	sort.Slice(desc.DependedOnBy, func(i, j int) bool {
+		return desc.DependedOnBy[i].ID < desc.DependedOnBy[j].ID
+	})
+	return nil
+}
+
+// RemoveTriggerReference removes back reference to a trigger from the
+// function.
+func (desc *Mutable) RemoveTriggerReference(id descpb.ID, triggerID descpb.TriggerID) {
+	for i := range desc.DependedOnBy {
+		if desc.DependedOnBy[i].ID == id {
+			var ids []descpb.TriggerID
+			for _, existing := range desc.DependedOnBy[i].TriggerIDs {
+				if existing != triggerID {
+					ids = append(ids, existing)
+				}
+			}
+			desc.DependedOnBy[i].TriggerIDs = ids
+			desc.maybeRemoveTableReference(id)
+			return
+		}
+	}
+}
+
 // maybeRemoveTableReference removes a table's references from the function if
-// the column, index and constraint references are all empty. This function is
-// only used internally when removing an individual column, index or constraint
-// reference.
+// the column, index, constraint and trigger references are all empty. This function is
+// only used internally when removing an individual column, index, constraint
+// or trigger reference.
 func (desc *Mutable) maybeRemoveTableReference(id descpb.ID) {
 	var ret []descpb.FunctionDescriptor_Reference
 	for _, ref := range desc.DependedOnBy {
-		if ref.ID == id && len(ref.ColumnIDs) == 0 && len(ref.IndexIDs) == 0 && len(ref.ConstraintIDs) == 0 {
+		if ref.ID == id && len(ref.ColumnIDs) == 0 && len(ref.IndexIDs) == 0 &&
+			len(ref.ConstraintIDs) == 0 && len(ref.TriggerIDs) == 0 {
 			continue
 		}
 		ret = append(ret, ref)
@@ -650,6 +801,21 @@ func (desc *Mutable) maybeRemoveTableReference(id descpb.ID) {
 	desc.DependedOnBy = ret
 }
 
+// AddFunctionReference adds a back reference to an aggregate function that
+// uses the function as one of its support functions.
+func (desc *Mutable) AddFunctionReference(id descpb.ID) {
+	for i := range desc.DependedOnBy {
+		if desc.DependedOnBy[i].ID == id {
+			return
+		}
+	}
+	desc.DependedOnBy = append(desc.DependedOnBy, descpb.FunctionDescriptor_Reference{ID: id})
+	sort.Slice(desc.DependedOnBy, func(i, j int) bool {
+		return desc.DependedOnBy[i].ID < desc.DependedOnBy[j].ID
+	})
+}
+
+// RemoveReference removes all back references from the given descriptor.
 func (desc *Mutable) RemoveReference(id descpb.ID) {
 	var ret []descpb.FunctionDescriptor_Reference
 	for _, ref := range desc.DependedOnBy {
@@ -712,14 +878,23 @@ func (desc *immutable) ToOverload() (ret *tree.Overload, err error) {
 		Language:   desc.getCreateExprLang(),
 	}
 
-	argTypes := make(tree.ParamTypes, 0, len(desc.Params))
-	for _, param := range desc.Params {
-		argTypes = append(
-			argTypes,
-			tree.ParamType{Name: param.Name, Typ: param.Type},
-		)
+	ret.RoutineParams = make(tree.RoutineParams, len(desc.Params))
+	paramNames := make([]string, 0, len(desc.Params))
+	paramTypes := make([]*types.T, 0, len(desc.Params))
+	var variadic bool
+	for i, param := range desc.Params {
+		ret.RoutineParams[i] = tree.RoutineParam{
+			Name:  tree.Name(param.Name),
+			Type:  param.Type,
+			Class: toTreeNodeParamClass(param.Class),
+		}
+		if ret.RoutineParams[i].IsSignatureParam(desc.IsProcedure()) {
+			paramNames = append(paramNames, param.Name)
+			paramTypes = append(paramTypes, param.Type)
+			variadic = param.Class == catpb.Function_Param_VARIADIC
+		}
 	}
-	ret.Types = argTypes
+	ret.Types = tree.MakeRoutineTypeList(paramNames, paramTypes, variadic)
 	ret.Volatility, err = desc.getOverloadVolatility()
 	if err != nil {
 		return nil, err
@@ -731,10 +906,44 @@ func (desc *immutable) ToOverload() (ret *tree.Overload, err error) {
 	if desc.ReturnType.ReturnSet {
 		ret.Class = tree.GeneratorClass
 	}
+	if agg := desc.Aggregate; agg != nil {
+		ret.Class = tree.AggregateClass
+		ret.UserDefinedAggregate = &tree.UserDefinedAggregate{
+			TransitionFunc: catid.FuncIDToOID(agg.TransitionFuncID),
+			StateType:      agg.StateType,
+			InitCond:       agg.InitCond,
+		}
+		if agg.FinalFuncID != descpb.InvalidID {
+			ret.UserDefinedAggregate.FinalFunc = catid.FuncIDToOID(agg.FinalFuncID)
+		}
+	}
 
 	return ret, nil
 }
 
+// ToFunctionSignature returns the signature of the function that is cached in
+// the descriptor of its parent schema. The argument types only include the
+// parameters that identify the function among its overloads, see
+// tree.RoutineParam.IsSignatureParam.
+func (desc *immutable) ToFunctionSignature() descpb.SchemaDescriptor_FunctionSignature {
+	sig := descpb.SchemaDescriptor_FunctionSignature{
+		ID:          desc.GetID(),
+		ArgTypes:    make([]*types.T, 0, len(desc.Params)),
+		ReturnType:  desc.ReturnType.Type,
+		ReturnSet:   desc.ReturnType.ReturnSet,
+		IsProcedure: desc.IsProcedure(),
+		IsAggregate: desc.IsAggregate(),
+	}
+	for _, param := range desc.Params {
+		if param.Class == catpb.Function_Param_OUT && !desc.IsProcedure() {
+			continue
+		}
+		sig.ArgTypes = append(sig.ArgTypes, param.Type)
+		sig.IsVariadic = param.Class == catpb.Function_Param_VARIADIC
+	}
+	return sig
+}
+
 func (desc *immutable) getOverloadVolatility() (volatility.V, error) {
 	var ret volatility.V
 	switch desc.Volatility {
@@ -810,6 +1019,11 @@ func (desc *immutable) IsProcedure() bool {
 	return desc.FunctionDescriptor.IsProcedure
 }
 
+// IsAggregate implements the FunctionDescriptor interface.
+func (desc *immutable) IsAggregate() bool {
+	return desc.FunctionDescriptor.Aggregate != nil
+}
+
 func (desc *immutable) getCreateExprLang() tree.RoutineLanguage {
 	switch desc.Lang {
 	case catpb.Function_SQL:
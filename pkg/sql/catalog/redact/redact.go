@@ -66,6 +66,10 @@ func redactTableDescriptor(d *descpb.TableDescriptor) (errs []error) {
 		uwi := &d.UniqueWithoutIndexConstraints[i]
 		handleErr(errors.Wrapf(redactUniqueWithoutIndexConstraint(uwi), "constraint #%d", uwi.ConstraintID))
 	}
+	for i := range d.Exclusions {
+		excl := &d.Exclusions[i]
+		handleErr(errors.Wrapf(redactExprStr(&excl.Predicate), "constraint #%d", excl.ConstraintID))
+	}
 	for _, m := range d.Mutations {
 		if idx := m.GetIndex(); idx != nil {
 			handleErr(errors.Wrapf(redactIndex(idx), "index #%d", idx.ID))
@@ -81,6 +85,9 @@ func redactTableDescriptor(d *descpb.TableDescriptor) (errs []error) {
 			case descpb.ConstraintToUpdate_UNIQUE_WITHOUT_INDEX:
 				uwi := &ctu.UniqueWithoutIndexConstraint
 				handleErr(errors.Wrapf(redactUniqueWithoutIndexConstraint(uwi), "constraint #%d", uwi.ConstraintID))
+			case descpb.ConstraintToUpdate_EXCLUSION:
+				excl := &ctu.ExclusionConstraint
+				handleErr(errors.Wrapf(redactExprStr(&excl.Predicate), "constraint #%d", excl.ConstraintID))
 			}
 		}
 	}
@@ -226,6 +226,29 @@ func TableDescs(
 			}
 		}
 
+		// Rewrite the table IDs of exclusion constraints in the same way.
+		for i := range table.Exclusions {
+			excl := &table.Exclusions[i]
+			if rewrite, ok := descriptorRewrites[excl.TableID]; ok {
+				excl.TableID = rewrite.ID
+			} else {
+				return errors.AssertionFailedf("cannot restore %q because referenced table ID in "+
+					"ExclusionConstraint %d was not found", table.Name, excl.TableID)
+			}
+		}
+		for idx := range table.Mutations {
+			if c := table.Mutations[idx].GetConstraint(); c != nil &&
+				c.ConstraintType == descpb.ConstraintToUpdate_EXCLUSION {
+				excl := &c.ExclusionConstraint
+				if rewrite, ok := descriptorRewrites[excl.TableID]; ok {
+					excl.TableID = rewrite.ID
+				} else {
+					return errors.AssertionFailedf("cannot restore %q because referenced table ID in "+
+						"ExclusionConstraint %d was not found", table.Name, excl.TableID)
+				}
+			}
+		}
+
 		if table.IsSequence() && table.SequenceOpts.HasOwner() {
 			if ownerRewrite, ok := descriptorRewrites[table.SequenceOpts.SequenceOwner.OwnerTableID]; ok {
 				table.SequenceOpts.SequenceOwner.OwnerTableID = ownerRewrite.ID
@@ -983,6 +1006,19 @@ func DatabaseDescs(
 			return err
 		}
 		db.Schemas = newSchemas
+
+		// Rewrite the tables of the publications. Tables that are not restored
+		// are removed from the publications.
+		for i := range db.Publications {
+			pub := &db.Publications[i]
+			tableIDs := pub.TableIDs[:0]
+			for _, id := range pub.TableIDs {
+				if rewrite, ok := descriptorRewrites[id]; ok {
+					tableIDs = append(tableIDs, rewrite.ID)
+				}
+			}
+			pub.TableIDs = tableIDs
+		}
 	}
 	return nil
 }
@@ -82,14 +82,14 @@ func (desc *immutable) ForEachUDTDependentForHydration(fn func(t *types.T) error
 	for _, f := range desc.Functions {
 		for _, sig := range f.Signatures {
 			for _, typ := range sig.ArgTypes {
-				if !catid.IsOIDUserDefined(typ.Oid()) {
+				if !typ.UserDefined() {
 					continue
 				}
 				if err := fn(typ); err != nil {
 					return iterutil.Map(err)
 				}
 			}
-			if !catid.IsOIDUserDefined(sig.ReturnType.Oid()) {
+			if !sig.ReturnType.UserDefined() {
 				continue
 			}
 			if err := fn(sig.ReturnType); err != nil {
@@ -526,14 +526,10 @@ func (desc *immutable) GetResolvedFuncDefinition(
 		if funcDescPb.Signatures[i].ReturnSet {
 			overload.Class = tree.GeneratorClass
 		}
-		paramTypes := make(tree.ParamTypes, 0, len(sig.ArgTypes))
-		for _, paramType := range sig.ArgTypes {
-			paramTypes = append(
-				paramTypes,
-				tree.ParamType{Typ: paramType},
-			)
+		if sig.IsAggregate {
+			overload.Class = tree.AggregateClass
 		}
-		overload.Types = paramTypes
+		overload.Types = tree.MakeRoutineTypeList(nil /* names */, sig.ArgTypes, sig.IsVariadic)
 		prefixedOverload := tree.MakeQualifiedOverload(desc.GetName(), overload)
 		funcDef.Overloads = append(funcDef.Overloads, prefixedOverload)
 	}
@@ -182,6 +182,76 @@ func RenameColumn(expr string, from tree.Name, to tree.Name) (string, error) {
 	return renamed.String(), nil
 }
 
+// ReplaceTriggerColumnRefs returns a copy of the WHEN condition of a trigger
+// in which each reference to a column of the NEW or OLD row, written as
+// NEW.col or (NEW).col, is replaced with the result of fn. fn is passed the
+// name of the row, "new" or "old", and the name of the column. The reference
+// is kept as is if fn returns nil.
+func ReplaceTriggerColumnRefs(
+	expr tree.Expr, fn func(row string, col tree.Name) (tree.Expr, error),
+) (tree.Expr, error) {
+	return tree.SimpleVisit(expr, func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		var row string
+		var col tree.Name
+		switch t := expr.(type) {
+		case *tree.UnresolvedName:
+			// NEW.col and OLD.col are parsed as two-part names.
+			if t.NumParts != 2 || t.Star {
+				return false, expr, nil
+			}
+			row, col = t.Parts[1], tree.Name(t.Parts[0])
+		case *tree.ColumnAccessExpr:
+			// (NEW).col is parsed as a column access.
+			if t.ByIndex {
+				return true, expr, nil
+			}
+			base := t.Expr
+			for {
+				paren, ok := base.(*tree.ParenExpr)
+				if !ok {
+					break
+				}
+				base = paren.Expr
+			}
+			name, ok := base.(*tree.UnresolvedName)
+			if !ok || name.NumParts != 1 || name.Star {
+				return true, expr, nil
+			}
+			row, col = name.Parts[0], t.ColName
+		default:
+			return true, expr, nil
+		}
+		if row != "new" && row != "old" {
+			return false, expr, nil
+		}
+		replacement, err := fn(row, col)
+		if err != nil || replacement == nil {
+			return false, expr, err
+		}
+		return false, replacement, nil
+	})
+}
+
+// RenameTriggerColumn replaces any reference to the column from of the NEW or
+// OLD row in the WHEN condition of a trigger with to, and returns a serialized
+// representation of the new expression.
+func RenameTriggerColumn(expr string, from tree.Name, to tree.Name) (string, error) {
+	parsed, err := parser.ParseExpr(expr)
+	if err != nil {
+		return "", err
+	}
+	renamed, err := ReplaceTriggerColumnRefs(parsed, func(row string, col tree.Name) (tree.Expr, error) {
+		if col != from {
+			return nil, nil
+		}
+		return &tree.UnresolvedName{NumParts: 2, Parts: tree.NameParts{string(to), row}}, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return tree.Serialize(renamed), nil
+}
+
 // iterColDescriptors iterates over the expression's variable columns and
 // calls f on each.
 //
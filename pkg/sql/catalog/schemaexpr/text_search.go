@@ -0,0 +1,126 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemaexpr
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// textSearchConfigMinArgs maps the text search builtins that take the
+// configuration as their first argument to the number of arguments of their
+// overloads that do. The configuration is matched conservatively: an overload
+// of ts_headline takes the document rather than the configuration as its first
+// argument, which is only replaced if it happens to be the name of a
+// configuration.
+var textSearchConfigMinArgs = map[string]int{
+	"to_tsvector":          2,
+	"to_tsquery":           2,
+	"plainto_tsquery":      2,
+	"phraseto_tsquery":     2,
+	"websearch_to_tsquery": 2,
+	"ts_headline":          3,
+	"json_to_tsvector":     3,
+	"jsonb_to_tsvector":    3,
+}
+
+// ReplaceTextSearchConfigs walks the given expression, and calls replace with
+// the constant text search configuration passed as the first argument of each
+// call to a text search builtin. The configuration is replaced with the
+// returned string. The expression is returned unchanged if no configuration
+// is replaced.
+func ReplaceTextSearchConfigs(
+	expr tree.Expr, replace func(config string) (string, error),
+) (tree.Expr, error) {
+	return tree.SimpleVisit(expr, textSearchConfigReplacer(replace))
+}
+
+// ReplaceTextSearchConfigsInStmt is like ReplaceTextSearchConfigs, for the
+// expressions of a statement.
+func ReplaceTextSearchConfigsInStmt(
+	stmt tree.Statement, replace func(config string) (string, error),
+) (tree.Statement, error) {
+	return tree.SimpleStmtVisit(stmt, textSearchConfigReplacer(replace))
+}
+
+func textSearchConfigReplacer(
+	replace func(config string) (string, error),
+) tree.SimpleVisitFn {
+	return func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		f, ok := expr.(*tree.FuncExpr)
+		if !ok || len(f.Exprs) == 0 {
+			return true, expr, nil
+		}
+		fn, ok := f.Func.FunctionReference.(*tree.UnresolvedName)
+		if !ok {
+			return true, expr, nil
+		}
+		minArgs, ok := textSearchConfigMinArgs[strings.ToLower(fn.Parts[0])]
+		if !ok || len(f.Exprs) < minArgs {
+			return true, expr, nil
+		}
+		arg, err := replaceTextSearchConfigArg(f.Exprs[0], replace)
+		if err != nil || arg == f.Exprs[0] {
+			return true, expr, err
+		}
+		newFunc := *f
+		newFunc.Exprs = append(tree.Exprs{arg}, f.Exprs[1:]...)
+		return true, &newFunc, nil
+	}
+}
+
+// replaceTextSearchConfigArg returns the configuration argument of a text
+// search builtin with its constant configuration replaced, or the argument
+// itself if it is not a constant or is not replaced.
+func replaceTextSearchConfigArg(
+	arg tree.Expr, replace func(config string) (string, error),
+) (tree.Expr, error) {
+	switch t := arg.(type) {
+	case *tree.AnnotateTypeExpr:
+		inner, err := replaceTextSearchConfigArg(t.Expr, replace)
+		if err != nil || inner == t.Expr {
+			return arg, err
+		}
+		newArg := *t
+		newArg.Expr = inner
+		return &newArg, nil
+	case *tree.CastExpr:
+		inner, err := replaceTextSearchConfigArg(t.Expr, replace)
+		if err != nil || inner == t.Expr {
+			return arg, err
+		}
+		newArg := *t
+		newArg.Expr = inner
+		return &newArg, nil
+	case *tree.ParenExpr:
+		inner, err := replaceTextSearchConfigArg(t.Expr, replace)
+		if err != nil || inner == t.Expr {
+			return arg, err
+		}
+		newArg := *t
+		newArg.Expr = inner
+		return &newArg, nil
+	case *tree.StrVal:
+		config, err := replace(t.RawString())
+		if err != nil || config == t.RawString() {
+			return arg, err
+		}
+		return tree.NewStrVal(config), nil
+	case *tree.DString:
+		config, err := replace(string(*t))
+		if err != nil || config == string(*t) {
+			return arg, err
+		}
+		return tree.NewDString(config), nil
+	}
+	return arg, nil
+}
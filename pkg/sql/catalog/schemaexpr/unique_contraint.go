@@ -55,3 +55,32 @@ func ValidateUniqueWithoutIndexPredicate(
 	}
 	return expr, nil
 }
+
+// ValidateExclusionPredicate verifies that an expression is a valid exclusion
+// constraint predicate. If the expression is valid, it returns the serialized
+// expression with the columns dequalified. The expression must satisfy the
+// same conditions as a unique without index predicate.
+func ValidateExclusionPredicate(
+	ctx context.Context,
+	tn tree.TableName,
+	desc catalog.TableDescriptor,
+	pred tree.Expr,
+	semaCtx *tree.SemaContext,
+	version clusterversion.ClusterVersion,
+) (string, error) {
+	expr, _, _, err := DequalifyAndValidateExpr(
+		ctx,
+		desc,
+		pred,
+		types.Bool,
+		tree.ExclusionPredicateExpr,
+		semaCtx,
+		volatility.Immutable,
+		&tn,
+		version,
+	)
+	if err != nil {
+		return "", err
+	}
+	return expr, nil
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/semenumpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/intsets"
@@ -86,6 +87,10 @@ type ConstraintProvider interface {
 	// AsUniqueWithoutIndex returns the corresponding
 	// UniqueWithoutIndexConstraint if there is one, nil otherwise.
 	AsUniqueWithoutIndex() UniqueWithoutIndexConstraint
+
+	// AsExclusion returns the corresponding ExclusionConstraint if there is
+	// one, nil otherwise.
+	AsExclusion() ExclusionConstraint
 }
 
 // Mutation is an interface around a table descriptor mutation.
@@ -102,8 +107,9 @@ type Mutation interface {
 	AsIndex() Index
 
 	// AsConstraintWithoutIndex returns the corresponding WithoutIndexConstraint
-	// if the mutation is on a check constraint or on a foreign key constraint or
-	// on a non-index-backed unique constraint, nil otherwise.
+	// if the mutation is on a check constraint, on a foreign key constraint, on
+	// a non-index-backed unique constraint or on an exclusion constraint, nil
+	// otherwise.
 	AsConstraintWithoutIndex() WithoutIndexConstraint
 
 	// AsPrimaryKeySwap returns the corresponding PrimaryKeySwap if the mutation
@@ -586,6 +592,10 @@ type ForeignKeyConstraint interface {
 
 	// Match returns the type of algorithm used to match composite keys.
 	Match() semenumpb.Match
+
+	// Deferrability returns whether the checks for this constraint may be
+	// deferred until the transaction commits.
+	Deferrability() semenumpb.Deferrability
 }
 
 // UniqueWithoutIndexConstraint is an interface around a unique constraint
@@ -599,6 +609,49 @@ type UniqueWithoutIndexConstraint interface {
 
 	// ParentTableID returns the ID of the table this constraint applies to.
 	ParentTableID() descpb.ID
+
+	// Deferrability returns whether the checks for this constraint may be
+	// deferred until the transaction commits.
+	Deferrability() semenumpb.Deferrability
+}
+
+// ExclusionConstraint is an interface around an exclusion constraint, which
+// guarantees that no two rows of the table satisfy all of the comparisons
+// between the values of its key columns.
+type ExclusionConstraint interface {
+	WithoutIndexConstraint
+
+	// ExclusionDesc returns the underlying descriptor protobuf.
+	ExclusionDesc() *descpb.ExclusionConstraint
+
+	// ParentTableID returns the ID of the table this constraint applies to.
+	ParentTableID() descpb.ID
+
+	// NumKeyColumns returns the number of columns in this exclusion
+	// constraint.
+	NumKeyColumns() int
+
+	// GetKeyColumnID returns the ID of the column in the exclusion constraint
+	// at ordinal `columnOrdinal`.
+	GetKeyColumnID(columnOrdinal int) descpb.ColumnID
+
+	// GetOperator returns the operator with which the values of the column at
+	// ordinal `columnOrdinal` are compared.
+	GetOperator(columnOrdinal int) treecmp.ComparisonOperator
+
+	// CollectKeyColumnIDs returns the columns in the exclusion constraint in a
+	// new TableColSet.
+	CollectKeyColumnIDs() TableColSet
+
+	// IsPartial returns true iff this is a partial exclusion constraint.
+	IsPartial() bool
+
+	// GetPredicate returns the partial predicate if there is one, "" otherwise.
+	GetPredicate() string
+
+	// GetIndexMethod returns the access method given in the USING clause of
+	// the constraint, if any.
+	GetIndexMethod() string
 }
 
 // PrimaryKeySwap is an interface around a primary key swap mutation.
@@ -1031,6 +1084,8 @@ func GetConstraintType(c Constraint) catconstants.ConstraintType {
 		return catconstants.ConstraintTypeFK
 	} else if c.AsUniqueWithoutIndex() != nil {
 		return catconstants.ConstraintTypeUniqueWithoutIndex
+	} else if c.AsExclusion() != nil {
+		return catconstants.ConstraintTypeExclusion
 	} else if c.AsUniqueWithIndex() != nil {
 		if c.AsUniqueWithIndex().GetEncodingType() == catenumpb.PrimaryIndexEncoding {
 			return catconstants.ConstraintTypePK
@@ -1176,6 +1231,41 @@ func MustFindConstraintWithName(tbl TableDescriptor, name string) (Constraint, e
 // silence the linter
 var _ = MustFindConstraintWithName
 
+// FindTriggerByID traverses the trigger descriptors on the table descriptor
+// and returns the first trigger with the desired ID, or nil if none was found.
+func FindTriggerByID(tbl TableDescriptor, id descpb.TriggerID) *descpb.TriggerDescriptor {
+	triggers := tbl.GetTriggers()
+	for i := range triggers {
+		if triggers[i].ID == id {
+			return &triggers[i]
+		}
+	}
+	return nil
+}
+
+// FindTriggerByName is like FindTriggerByID but with names instead of IDs.
+func FindTriggerByName(tbl TableDescriptor, name string) *descpb.TriggerDescriptor {
+	triggers := tbl.GetTriggers()
+	for i := range triggers {
+		if triggers[i].Name == name {
+			return &triggers[i]
+		}
+	}
+	return nil
+}
+
+// FindPolicyByName traverses the policy descriptors on the table descriptor
+// and returns the first policy with the desired name, or nil if none was found.
+func FindPolicyByName(tbl TableDescriptor, name string) *descpb.PolicyDescriptor {
+	policies := tbl.GetPolicies()
+	for i := range policies {
+		if policies[i].Name == name {
+			return &policies[i]
+		}
+	}
+	return nil
+}
+
 // FindFamilyByID traverses the family descriptors on the table descriptor
 // and returns the first column family with the desired ID, or nil if none was
 // found.
@@ -16,6 +16,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/semenumpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/util"
 )
 
@@ -43,6 +44,11 @@ func (c constraintBase) AsUniqueWithIndex() catalog.UniqueWithIndexConstraint {
 	return nil
 }
 
+// AsExclusion implements the catalog.ConstraintProvider interface.
+func (c constraintBase) AsExclusion() catalog.ExclusionConstraint {
+	return nil
+}
+
 type checkConstraint struct {
 	constraintBase
 	desc *descpb.TableDescriptor_CheckConstraint
@@ -146,6 +152,12 @@ func (c uniqueWithoutIndexConstraint) ParentTableID() descpb.ID {
 	return c.desc.TableID
 }
 
+// Deferrability implements the catalog.UniqueWithoutIndexConstraint
+// interface.
+func (c uniqueWithoutIndexConstraint) Deferrability() semenumpb.Deferrability {
+	return c.desc.Deferrability
+}
+
 // IsValidReferencedUniqueConstraint implements the catalog.UniqueConstraint
 // interface.
 func (c uniqueWithoutIndexConstraint) IsValidReferencedUniqueConstraint(
@@ -220,6 +232,108 @@ func (c uniqueWithoutIndexConstraint) IsEnforced() bool {
 	return !c.IsMutation() || c.WriteAndDeleteOnly()
 }
 
+type exclusionConstraint struct {
+	constraintBase
+	desc *descpb.ExclusionConstraint
+}
+
+var _ catalog.ExclusionConstraint = (*exclusionConstraint)(nil)
+
+// exclusionOperators maps the symbols stored in the Operators of exclusion
+// constraint descriptors to the corresponding comparison operators.
+var exclusionOperators = func() map[string]treecmp.ComparisonOperator {
+	m := make(map[string]treecmp.ComparisonOperator, treecmp.NumComparisonOperatorSymbols)
+	for sym := treecmp.ComparisonOperatorSymbol(0); sym < treecmp.NumComparisonOperatorSymbols; sym++ {
+		m[sym.String()] = treecmp.MakeComparisonOperator(sym)
+	}
+	return m
+}()
+
+// ExclusionDesc implements the catalog.ExclusionConstraint interface.
+func (c exclusionConstraint) ExclusionDesc() *descpb.ExclusionConstraint {
+	return c.desc
+}
+
+// ParentTableID implements the catalog.ExclusionConstraint interface.
+func (c exclusionConstraint) ParentTableID() descpb.ID {
+	return c.desc.TableID
+}
+
+// NumKeyColumns implements the catalog.ExclusionConstraint interface.
+func (c exclusionConstraint) NumKeyColumns() int {
+	return len(c.desc.ColumnIDs)
+}
+
+// GetKeyColumnID implements the catalog.ExclusionConstraint interface.
+func (c exclusionConstraint) GetKeyColumnID(columnOrdinal int) descpb.ColumnID {
+	return c.desc.ColumnIDs[columnOrdinal]
+}
+
+// GetOperator implements the catalog.ExclusionConstraint interface.
+func (c exclusionConstraint) GetOperator(columnOrdinal int) treecmp.ComparisonOperator {
+	return exclusionOperators[c.desc.Operators[columnOrdinal]]
+}
+
+// CollectKeyColumnIDs implements the catalog.ExclusionConstraint interface.
+func (c exclusionConstraint) CollectKeyColumnIDs() catalog.TableColSet {
+	return catalog.MakeTableColSet(c.desc.ColumnIDs...)
+}
+
+// IsPartial implements the catalog.ExclusionConstraint interface.
+func (c exclusionConstraint) IsPartial() bool {
+	return c.desc.Predicate != ""
+}
+
+// GetPredicate implements the catalog.ExclusionConstraint interface.
+func (c exclusionConstraint) GetPredicate() string {
+	return c.desc.Predicate
+}
+
+// GetIndexMethod implements the catalog.ExclusionConstraint interface.
+func (c exclusionConstraint) GetIndexMethod() string {
+	return c.desc.IndexMethod
+}
+
+// GetConstraintID implements the catalog.Constraint interface.
+func (c exclusionConstraint) GetConstraintID() descpb.ConstraintID {
+	return c.desc.ConstraintID
+}
+
+// GetConstraintValidity implements the catalog.Constraint interface.
+func (c exclusionConstraint) GetConstraintValidity() descpb.ConstraintValidity {
+	return c.desc.Validity
+}
+
+// IsConstraintValidated implements the catalog.Constraint interface.
+func (c exclusionConstraint) IsConstraintValidated() bool {
+	return c.desc.Validity == descpb.ConstraintValidity_Validated
+}
+
+// IsConstraintUnvalidated implements the catalog.Constraint interface.
+func (c exclusionConstraint) IsConstraintUnvalidated() bool {
+	return c.desc.Validity == descpb.ConstraintValidity_Unvalidated
+}
+
+// GetName implements the catalog.Constraint interface.
+func (c exclusionConstraint) GetName() string {
+	return c.desc.Name
+}
+
+// AsExclusion implements the catalog.ConstraintProvider interface.
+func (c exclusionConstraint) AsExclusion() catalog.ExclusionConstraint {
+	return &c
+}
+
+// String implements the catalog.Constraint interface.
+func (c exclusionConstraint) String() string {
+	return fmt.Sprintf("%+v", c.desc)
+}
+
+// IsEnforced implements the catalog.Constraint interface.
+func (c exclusionConstraint) IsEnforced() bool {
+	return !c.IsMutation() || c.WriteAndDeleteOnly()
+}
+
 type foreignKeyConstraint struct {
 	constraintBase
 	desc *descpb.ForeignKeyConstraint
@@ -292,6 +406,11 @@ func (c foreignKeyConstraint) Match() semenumpb.Match {
 	return c.desc.Match
 }
 
+// Deferrability implements the catalog.ForeignKeyConstraint interface.
+func (c foreignKeyConstraint) Deferrability() semenumpb.Deferrability {
+	return c.desc.Deferrability
+}
+
 // GetConstraintID implements the catalog.Constraint interface.
 func (c foreignKeyConstraint) GetConstraintID() descpb.ConstraintID {
 	return c.desc.ConstraintID
@@ -343,6 +462,7 @@ type constraintCache struct {
 	fks, fksEnforced       []catalog.ForeignKeyConstraint
 	uwis, uwisEnforced     []catalog.UniqueWithIndexConstraint
 	uwois, uwoisEnforced   []catalog.UniqueWithoutIndexConstraint
+	excls, exclsEnforced   []catalog.ExclusionConstraint
 	fkBackRefs             []catalog.ForeignKeyConstraint
 }
 
@@ -358,7 +478,9 @@ func newConstraintCache(
 	capFKs := numEnforcedFKs + len(mutations.fks)
 	numEnforcedUWOIs := len(desc.UniqueWithoutIndexConstraints)
 	capUWOIs := numEnforcedUWOIs + len(mutations.uniqueWithoutIndexes)
-	capAll := capChecks + capFKs + capUWOIs + capUWIs
+	numEnforcedExcls := len(desc.Exclusions)
+	capExcls := numEnforcedExcls + len(mutations.exclusions)
+	capAll := capChecks + capFKs + capUWOIs + capExcls + capUWIs
 	// Pre-allocate slices which are known not to be empty:
 	// physical tables always have at least one index-backed unique constraint
 	// in the form of the primary key.
@@ -473,6 +595,37 @@ func newConstraintCache(
 			}
 		}
 	}
+	// Populate with exclusion constraints.
+	if capExcls > 0 {
+		c.excls = make([]catalog.ExclusionConstraint, 0, capExcls)
+		var byID util.FastIntMap
+		var exclsBackingStructs []exclusionConstraint
+		if numEnforcedExcls > 0 {
+			exclsBackingStructs = make([]exclusionConstraint, numEnforcedExcls)
+			for i := range desc.Exclusions {
+				exclsBackingStructs[i].desc = &desc.Exclusions[i]
+				excl := &exclsBackingStructs[i]
+				byID.Set(int(excl.desc.ConstraintID), i)
+				c.all = append(c.all, excl)
+				c.allEnforced = append(c.allEnforced, excl)
+				c.excls = append(c.excls, excl)
+				c.exclsEnforced = append(c.exclsEnforced, excl)
+			}
+		}
+		for _, m := range mutations.exclusions {
+			excl := m.AsExclusion()
+			if ordinal, found := byID.Get(int(excl.GetConstraintID())); found {
+				exclsBackingStructs[ordinal].maybeMutation = excl.(*exclusionConstraint).maybeMutation
+			} else {
+				c.all = append(c.all, excl)
+				c.excls = append(c.excls, excl)
+				if m.WriteAndDeleteOnly() {
+					c.allEnforced = append(c.allEnforced, excl)
+					c.exclsEnforced = append(c.exclsEnforced, excl)
+				}
+			}
+		}
+	}
 	// Populate foreign key back-reference slice.
 	// These are not constraints on this table, but having them wrapped in the
 	// catalog.ForeignKeyConstraint interface is useful.
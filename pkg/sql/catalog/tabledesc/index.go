@@ -473,6 +473,11 @@ func (w index) AsUniqueWithoutIndex() catalog.UniqueWithoutIndexConstraint {
 	return nil
 }
 
+// AsExclusion implements the catalog.ConstraintProvider interface.
+func (w index) AsExclusion() catalog.ExclusionConstraint {
+	return nil
+}
+
 // AsUniqueWithIndex implements the catalog.ConstraintProvider interface.
 func (w index) AsUniqueWithIndex() catalog.UniqueWithIndexConstraint {
 	if w.Primary() {
@@ -23,6 +23,7 @@ var _ catalog.TableElementMaybeMutation = maybeMutation{}
 var _ catalog.TableElementMaybeMutation = checkConstraint{}
 var _ catalog.TableElementMaybeMutation = foreignKeyConstraint{}
 var _ catalog.TableElementMaybeMutation = uniqueWithoutIndexConstraint{}
+var _ catalog.TableElementMaybeMutation = exclusionConstraint{}
 var _ catalog.TableElementMaybeMutation = primaryKeySwap{}
 var _ catalog.TableElementMaybeMutation = computedColumnSwap{}
 var _ catalog.TableElementMaybeMutation = materializedViewRefresh{}
@@ -228,6 +229,7 @@ type mutation struct {
 	check              catalog.CheckConstraint
 	foreignKey         catalog.ForeignKeyConstraint
 	uniqueWithoutIndex catalog.UniqueWithoutIndexConstraint
+	exclusion          catalog.ExclusionConstraint
 	pkSwap             catalog.PrimaryKeySwap
 	ccSwap             catalog.ComputedColumnSwap
 	mvRefresh          catalog.MaterializedViewRefresh
@@ -255,6 +257,9 @@ func (m mutation) AsConstraintWithoutIndex() catalog.WithoutIndexConstraint {
 	if m.foreignKey != nil {
 		return m.foreignKey
 	}
+	if m.exclusion != nil {
+		return m.exclusion
+	}
 	return m.uniqueWithoutIndex
 }
 
@@ -273,6 +278,11 @@ func (m mutation) AsUniqueWithoutIndex() catalog.UniqueWithoutIndexConstraint {
 	return m.uniqueWithoutIndex
 }
 
+// AsExclusion implements the catalog.ConstraintProvider interface.
+func (m mutation) AsExclusion() catalog.ExclusionConstraint {
+	return m.exclusion
+}
+
 // AsUniqueWithIndex implements the catalog.ConstraintProvider interface.
 func (m mutation) AsUniqueWithIndex() catalog.UniqueWithIndexConstraint {
 	if m.index == nil {
@@ -317,6 +327,7 @@ type mutationCache struct {
 	columns                           []catalog.Mutation
 	indexes                           []catalog.Mutation
 	checks, fks, uniqueWithoutIndexes []catalog.Mutation
+	exclusions                        []catalog.Mutation
 }
 
 // newMutationCache returns a fresh fully-populated mutationCache struct for the
@@ -334,6 +345,7 @@ func newMutationCache(desc *descpb.TableDescriptor) *mutationCache {
 	var checks []checkConstraint
 	var fks []foreignKeyConstraint
 	var uniqueWithoutIndexes []uniqueWithoutIndexConstraint
+	var exclusions []exclusionConstraint
 	var pkSwaps []primaryKeySwap
 	var ccSwaps []computedColumnSwap
 	var mvRefreshes []materializedViewRefresh
@@ -385,6 +397,12 @@ func newMutationCache(desc *descpb.TableDescriptor) *mutationCache {
 					desc:           &pb.UniqueWithoutIndexConstraint,
 				})
 				backingStructs[i].uniqueWithoutIndex = &uniqueWithoutIndexes[len(uniqueWithoutIndexes)-1]
+			case descpb.ConstraintToUpdate_EXCLUSION:
+				exclusions = append(exclusions, exclusionConstraint{
+					constraintBase: constraintBase{maybeMutation: mm},
+					desc:           &pb.ExclusionConstraint,
+				})
+				backingStructs[i].exclusion = &exclusions[len(exclusions)-1]
 
 			}
 		} else if pb := m.GetPrimaryKeySwap(); pb != nil {
@@ -435,6 +453,9 @@ func newMutationCache(desc *descpb.TableDescriptor) *mutationCache {
 	if len(uniqueWithoutIndexes) > 0 {
 		c.uniqueWithoutIndexes = make([]catalog.Mutation, 0, len(uniqueWithoutIndexes))
 	}
+	if len(exclusions) > 0 {
+		c.exclusions = make([]catalog.Mutation, 0, len(exclusions))
+	}
 	for _, m := range c.all {
 		if col := m.AsColumn(); col != nil {
 			c.columns = append(c.columns, m)
@@ -446,6 +467,8 @@ func newMutationCache(desc *descpb.TableDescriptor) *mutationCache {
 			c.fks = append(c.fks, m)
 		} else if uwoi := m.AsUniqueWithoutIndex(); uwoi != nil {
 			c.uniqueWithoutIndexes = append(c.uniqueWithoutIndexes, m)
+		} else if excl := m.AsExclusion(); excl != nil {
+			c.exclusions = append(c.exclusions, m)
 		}
 	}
 	return &c
@@ -284,6 +284,12 @@ func ForEachExprStringInTableDesc(descI catalog.TableDescriptor, f func(expr *st
 		}
 		return nil
 	}
+	doExcl := func(excl *descpb.ExclusionConstraint) error {
+		if excl.Predicate != "" {
+			return f(&excl.Predicate)
+		}
+		return nil
+	}
 
 	// Process columns.
 	for i := range desc.Columns {
@@ -315,6 +321,13 @@ func ForEachExprStringInTableDesc(descI catalog.TableDescriptor, f func(expr *st
 		}
 	}
 
+	// Process exclusion constraints.
+	for i := range desc.Exclusions {
+		if err := doExcl(&desc.Exclusions[i]); err != nil {
+			return err
+		}
+	}
+
 	// Process all non-index mutations.
 	for _, mut := range desc.Mutations {
 		if c := mut.GetColumn(); c != nil {
@@ -334,6 +347,12 @@ func ForEachExprStringInTableDesc(descI catalog.TableDescriptor, f func(expr *st
 				return err
 			}
 		}
+		if c := mut.GetConstraint(); c != nil &&
+			c.ConstraintType == descpb.ConstraintToUpdate_EXCLUSION {
+			if err := doExcl(&c.ExclusionConstraint); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -382,6 +401,9 @@ func (desc *wrapper) GetAllReferencedFunctionIDs() (catalog.DescriptorIDSet, err
 			ret.Add(id)
 		}
 	}
+	for i := range desc.Triggers {
+		ret.Add(desc.Triggers[i].FuncID)
+	}
 	// TODO(chengxiong): add logic to extract references from indexes when UDFs
 	// are allowed in them.
 	return ret.Union(catalog.MakeDescriptorIDSet(desc.DependsOnFunctions...)), nil
@@ -1181,6 +1203,17 @@ func (desc *Mutable) RemoveColumnFromFamilyAndPrimaryIndex(colID descpb.ColumnID
 	}
 }
 
+// RemovePolicy removes the row-level security policy with the given ID from
+// the table, if it exists.
+func (desc *Mutable) RemovePolicy(id descpb.PolicyID) {
+	for i := range desc.Policies {
+		if desc.Policies[i].ID == id {
+			desc.Policies = append(desc.Policies[:i], desc.Policies[i+1:]...)
+			return
+		}
+	}
+}
+
 func (desc *Mutable) removeColumnFromFamily(colID descpb.ColumnID) {
 	for i := range desc.Families {
 		for j, c := range desc.Families[i].ColumnIDs {
@@ -1292,6 +1325,25 @@ func (desc *Mutable) DropConstraint(
 				return nil
 			}
 		}
+	} else if excl := constraint.AsExclusion(); excl != nil {
+		// Search through the descriptor's exclusion constraints and delete the
+		// one that we're supposed to be deleting.
+		for i := range desc.Exclusions {
+			ref := &desc.Exclusions[i]
+			if ref.Name == excl.GetName() {
+				// If the constraint is unvalidated, there's no assumption that it must
+				// hold for all rows, so it can be dropped immediately.
+				if excl.IsConstraintUnvalidated() {
+					desc.Exclusions = append(
+						desc.Exclusions[:i], desc.Exclusions[i+1:]...,
+					)
+					return nil
+				}
+				ref.Validity = descpb.ConstraintValidity_Dropping
+				desc.AddExclusionMutation(ref, descpb.DescriptorMutation_DROP)
+				return nil
+			}
+		}
 	} else if ck := constraint.AsCheck(); ck != nil {
 		for i, c := range desc.Checks {
 			if c.Name == ck.GetName() {
@@ -1371,6 +1423,8 @@ func (desc *Mutable) RenameConstraint(
 		fk.ForeignKeyDesc().Name = newName
 	} else if uwoi := constraint.AsUniqueWithoutIndex(); uwoi != nil {
 		uwoi.UniqueWithoutIndexDesc().Name = newName
+	} else if excl := constraint.AsExclusion(); excl != nil {
+		excl.ExclusionDesc().Name = newName
 	} else {
 		return unimplemented.Newf(fmt.Sprintf("rename-constraint-%T", constraint),
 			"constraint %q has unsupported type", tree.ErrNameString(constraint.GetName()))
@@ -1525,6 +1579,28 @@ func (desc *Mutable) MakeMutationComplete(m descpb.DescriptorMutation) error {
 						t.Constraint.UniqueWithoutIndexConstraint.Validity,
 					)
 				}
+			case descpb.ConstraintToUpdate_EXCLUSION:
+				switch t.Constraint.ExclusionConstraint.Validity {
+				case descpb.ConstraintValidity_Validating:
+					// Constraint already added, just mark it as Validated.
+					for i := range desc.Exclusions {
+						ec := &desc.Exclusions[i]
+						if ec.ConstraintID == t.Constraint.ExclusionConstraint.ConstraintID {
+							ec.Validity = descpb.ConstraintValidity_Validated
+							break
+						}
+					}
+				case descpb.ConstraintValidity_Unvalidated, descpb.ConstraintValidity_Validated:
+					// add the constraint to the list of exclusion constraints on the
+					// table descriptor.
+					desc.Exclusions = append(
+						desc.Exclusions, t.Constraint.ExclusionConstraint,
+					)
+				default:
+					return errors.AssertionFailedf("invalid constraint validity state: %d",
+						t.Constraint.ExclusionConstraint.Validity,
+					)
+				}
 			case descpb.ConstraintToUpdate_NOT_NULL:
 				// Remove the dummy check constraint that was in place during
 				// validation.
@@ -1635,6 +1711,12 @@ func (desc *Mutable) MakeMutationComplete(m descpb.DescriptorMutation) error {
 			// indexes with the new indexes that have been backfilled already.
 			desc.SetPrimaryIndex(t.MaterializedViewRefresh.NewPrimaryIndex)
 			desc.SetPublicNonPrimaryIndexes(t.MaterializedViewRefresh.NewIndexes)
+			// The data in the new indexes reflects the view query as of the
+			// refresh timestamp, unless the refresh did not backfill them.
+			desc.MaterializedViewRefreshTime = hlc.Timestamp{}
+			if t.MaterializedViewRefresh.ShouldBackfill {
+				desc.MaterializedViewRefreshTime = t.MaterializedViewRefresh.AsOf
+			}
 		}
 
 	case descpb.DescriptorMutation_DROP:
@@ -1795,6 +1877,24 @@ func (desc *Mutable) AddUniqueWithoutIndexMutation(
 	desc.addIndexMutationMaybeWithTempIndex(m)
 }
 
+// AddExclusionMutation adds an exclusion constraint mutation to
+// desc.Mutations.
+func (desc *Mutable) AddExclusionMutation(
+	ec *descpb.ExclusionConstraint, direction descpb.DescriptorMutation_Direction,
+) {
+	m := descpb.DescriptorMutation{
+		Descriptor_: &descpb.DescriptorMutation_Constraint{
+			Constraint: &descpb.ConstraintToUpdate{
+				ConstraintType:      descpb.ConstraintToUpdate_EXCLUSION,
+				Name:                ec.Name,
+				ExclusionConstraint: *ec,
+			},
+		},
+		Direction: direction,
+	}
+	desc.addIndexMutationMaybeWithTempIndex(m)
+}
+
 // MakeNotNullCheckConstraint creates a dummy check constraint equivalent to a
 // NOT NULL constraint on a column, so that NOT NULL constraints can be added
 // and dropped correctly in the schema changer. This function mutates inuseNames
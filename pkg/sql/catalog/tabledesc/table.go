@@ -393,6 +393,17 @@ func (desc *wrapper) EnforcedUniqueConstraintsWithoutIndex() []catalog.UniqueWit
 	return desc.getExistingOrNewConstraintCache().uwoisEnforced
 }
 
+// ExclusionConstraints implements the catalog.TableDescriptor interface.
+func (desc *wrapper) ExclusionConstraints() []catalog.ExclusionConstraint {
+	return desc.getExistingOrNewConstraintCache().excls
+}
+
+// EnforcedExclusionConstraints implements the catalog.TableDescriptor
+// interface.
+func (desc *wrapper) EnforcedExclusionConstraints() []catalog.ExclusionConstraint {
+	return desc.getExistingOrNewConstraintCache().exclsEnforced
+}
+
 // InitTableDescriptor returns a blank TableDescriptor.
 func InitTableDescriptor(
 	id, parentID, parentSchemaID descpb.ID,
@@ -492,6 +503,34 @@ func RenameColumnInTable(
 		}
 	}
 
+	// Rename the column in row-level security policies.
+	for i := range tableDesc.Policies {
+		policy := &tableDesc.Policies[i]
+		if policy.UsingExpr != "" {
+			if err := renameInExpr(&policy.UsingExpr); err != nil {
+				return err
+			}
+		}
+		if policy.WithCheckExpr != "" {
+			if err := renameInExpr(&policy.WithCheckExpr); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Rename the column in the WHEN conditions of triggers.
+	for i := range tableDesc.Triggers {
+		trigger := &tableDesc.Triggers[i]
+		if trigger.WhenExpr == "" {
+			continue
+		}
+		newExpr, err := schemaexpr.RenameTriggerColumn(trigger.WhenExpr, col.ColName(), newName)
+		if err != nil {
+			return err
+		}
+		trigger.WhenExpr = newExpr
+	}
+
 	// Rename the column in computed columns.
 	for i := range tableDesc.Columns {
 		if otherCol := &tableDesc.Columns[i]; otherCol.IsComputed() {
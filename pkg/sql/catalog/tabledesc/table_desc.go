@@ -561,6 +561,19 @@ func (desc *wrapper) UniqueWithoutIndexColumns(
 	return ret
 }
 
+// ExclusionColumns implements the TableDescriptor interface.
+func (desc *wrapper) ExclusionColumns(ec catalog.ExclusionConstraint) []catalog.Column {
+	n := ec.NumKeyColumns()
+	if ec.ParentTableID() != desc.GetID() || n == 0 {
+		return nil
+	}
+	ret := make([]catalog.Column, n)
+	for i := 0; i < n; i++ {
+		ret[i] = catalog.FindColumnByID(desc, ec.GetKeyColumnID(i))
+	}
+	return ret
+}
+
 // IndexFetchSpecKeyAndSuffixColumns implements the TableDescriptor interface.
 func (desc *wrapper) IndexFetchSpecKeyAndSuffixColumns(
 	idx catalog.Index,
@@ -637,3 +650,13 @@ func (desc *wrapper) ForEachUDTDependentForHydration(fn func(t *types.T) error)
 func (desc *wrapper) IsSchemaLocked() bool {
 	return desc.SchemaLocked
 }
+
+// IsRowLevelSecurityEnabled implements the TableDescriptor interface.
+func (desc *wrapper) IsRowLevelSecurityEnabled() bool {
+	return desc.RowLevelSecurityEnabled
+}
+
+// IsRowLevelSecurityForced implements the TableDescriptor interface.
+func (desc *wrapper) IsRowLevelSecurityForced() bool {
+	return desc.RowLevelSecurityForced
+}
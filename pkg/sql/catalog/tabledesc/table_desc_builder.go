@@ -864,6 +864,12 @@ func maybeAddConstraintIDs(desc *descpb.TableDescriptor) (hasChanged bool) {
 		idPtrs = append(idPtrs, &uwoi.ConstraintID)
 		uwoiByName[uwoi.Name] = uwoi
 	}
+	exclByName := make(map[string]*descpb.ExclusionConstraint)
+	for i := range desc.Exclusions {
+		excl := &desc.Exclusions[i]
+		idPtrs = append(idPtrs, &excl.ConstraintID)
+		exclByName[excl.Name] = excl
+	}
 	for _, m := range desc.GetMutations() {
 		if idx := m.GetIndex(); idx != nil && idx.Unique && !idx.UseDeletePreservingEncoding {
 			idPtrs = append(idPtrs, &idx.ConstraintID)
@@ -875,6 +881,8 @@ func maybeAddConstraintIDs(desc *descpb.TableDescriptor) (hasChanged bool) {
 				idPtrs = append(idPtrs, &c.ForeignKey.ConstraintID)
 			case descpb.ConstraintToUpdate_UNIQUE_WITHOUT_INDEX:
 				idPtrs = append(idPtrs, &c.UniqueWithoutIndexConstraint.ConstraintID)
+			case descpb.ConstraintToUpdate_EXCLUSION:
+				idPtrs = append(idPtrs, &c.ExclusionConstraint.ConstraintID)
 			}
 		}
 	}
@@ -914,6 +922,10 @@ func maybeAddConstraintIDs(desc *descpb.TableDescriptor) (hasChanged bool) {
 				if other, ok := uwoiByName[c.UniqueWithoutIndexConstraint.Name]; ok {
 					c.UniqueWithoutIndexConstraint.ConstraintID = other.ConstraintID
 				}
+			case descpb.ConstraintToUpdate_EXCLUSION:
+				if other, ok := exclByName[c.ExclusionConstraint.Name]; ok {
+					c.ExclusionConstraint.ConstraintID = other.ConstraintID
+				}
 			}
 		}
 	}
@@ -199,6 +199,11 @@ func (desc *wrapper) ValidateForwardReferences(
 		}
 	}
 
+	// Check all functions referenced by triggers exist.
+	for i := range desc.Triggers {
+		vea.Report(desc.validateOutboundFuncRef(desc.Triggers[i].FuncID, vdg))
+	}
+
 	// Check enforced outbound foreign keys.
 	for _, fk := range desc.EnforcedOutboundForeignKeys() {
 		vea.Report(desc.validateOutboundFK(fk.ForeignKeyDesc(), vdg))
@@ -263,6 +268,16 @@ func (desc *wrapper) ValidateBackReferences(
 		}
 	}
 
+	// Check back-references in functions referenced by triggers.
+	for i := range desc.Triggers {
+		fn, err := vdg.GetFunctionDescriptor(desc.Triggers[i].FuncID)
+		if err != nil {
+			vea.Report(err)
+			continue
+		}
+		vea.Report(desc.validateOutboundFuncRefBackReferenceForTrigger(fn, desc.Triggers[i].ID))
+	}
+
 	// For views, check dependent relations.
 	if desc.IsView() {
 		for _, id := range desc.DependsOnTypes {
@@ -376,6 +391,23 @@ func (desc *wrapper) validateOutboundFuncRefBackReferenceForColumn(
 		ref.GetName(), ref.GetID())
 }
 
+func (desc *wrapper) validateOutboundFuncRefBackReferenceForTrigger(
+	ref catalog.FunctionDescriptor, triggerID descpb.TriggerID,
+) error {
+	for _, dep := range ref.GetDependedOnBy() {
+		if dep.ID != desc.GetID() {
+			continue
+		}
+		for _, id := range dep.TriggerIDs {
+			if id == triggerID {
+				return nil
+			}
+		}
+	}
+	return errors.AssertionFailedf("depends-on function %q (%d) has no corresponding depended-on-by back reference",
+		ref.GetName(), ref.GetID())
+}
+
 func (desc *wrapper) validateInboundFunctionRef(
 	by descpb.TableDescriptor_Reference, vdg catalog.ValidationDescGetter,
 ) error {
@@ -865,10 +897,13 @@ func (desc *wrapper) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 	// actually a table, not if it's just a view.
 	if desc.IsPhysicalTable() {
 		desc.validateConstraintNamesAndIDs(vea)
+		desc.validateTriggers(vea)
+		desc.validatePolicies(vea)
 		newErrs := []error{
 			desc.validateColumnFamilies(columnsByID),
 			desc.validateCheckConstraints(columnsByID),
 			desc.validateUniqueWithoutIndexConstraints(columnsByID),
+			desc.validateExclusionConstraints(columnsByID),
 			desc.validateTableIndexes(columnsByID, vea.IsActive),
 			desc.validatePartitioning(),
 		}
@@ -1101,6 +1136,114 @@ func (desc *wrapper) validateConstraintNamesAndIDs(vea catalog.ValidationErrorAc
 
 }
 
+// validateTriggers validates that the table's triggers have valid, unique
+// names and IDs.
+func (desc *wrapper) validateTriggers(vea catalog.ValidationErrorAccumulator) {
+	names := make(map[string]struct{}, len(desc.Triggers))
+	ids := make(map[descpb.TriggerID]struct{}, len(desc.Triggers))
+	for i := range desc.Triggers {
+		trigger := &desc.Triggers[i]
+		if trigger.Name == "" {
+			vea.Report(pgerror.Newf(pgcode.Syntax, "empty trigger name"))
+		}
+		if trigger.ID == 0 {
+			vea.Report(errors.AssertionFailedf(
+				"trigger ID was missing for trigger %q", trigger.Name))
+		} else if trigger.ID >= desc.NextTriggerID {
+			vea.Report(errors.AssertionFailedf(
+				"trigger %q has ID %d not less than NextTriggerID value %d for table",
+				trigger.Name, trigger.ID, desc.NextTriggerID))
+		}
+		if _, found := names[trigger.Name]; found {
+			vea.Report(pgerror.Newf(pgcode.DuplicateObject,
+				"duplicate trigger name: %q", trigger.Name))
+		}
+		names[trigger.Name] = struct{}{}
+		if _, found := ids[trigger.ID]; found {
+			vea.Report(pgerror.Newf(pgcode.DuplicateObject,
+				"trigger ID %d in trigger %q already in use", trigger.ID, trigger.Name))
+		}
+		ids[trigger.ID] = struct{}{}
+		if trigger.FuncID == descpb.InvalidID {
+			vea.Report(errors.AssertionFailedf(
+				"invalid function ID %d in trigger %q", trigger.FuncID, trigger.Name))
+		}
+		if len(trigger.Events) == 0 {
+			vea.Report(errors.AssertionFailedf(
+				"trigger %q has no events", trigger.Name))
+		}
+		for _, ev := range trigger.Events {
+			for _, colID := range ev.ColumnIDs {
+				if catalog.FindColumnByID(desc, colID) == nil {
+					vea.Report(errors.AssertionFailedf(
+						"trigger %q references unknown column ID %d", trigger.Name, colID))
+				}
+			}
+		}
+	}
+}
+
+// validatePolicies validates that the table's row-level security policies have
+// valid, unique names and IDs, and well-formed expressions.
+func (desc *wrapper) validatePolicies(vea catalog.ValidationErrorAccumulator) {
+	names := make(map[string]struct{}, len(desc.Policies))
+	ids := make(map[descpb.PolicyID]struct{}, len(desc.Policies))
+	for i := range desc.Policies {
+		policy := &desc.Policies[i]
+		if policy.Name == "" {
+			vea.Report(pgerror.Newf(pgcode.Syntax, "empty policy name"))
+		}
+		if policy.ID == 0 {
+			vea.Report(errors.AssertionFailedf(
+				"policy ID was missing for policy %q", policy.Name))
+		} else if policy.ID >= desc.NextPolicyID {
+			vea.Report(errors.AssertionFailedf(
+				"policy %q has ID %d not less than NextPolicyID value %d for table",
+				policy.Name, policy.ID, desc.NextPolicyID))
+		}
+		if _, found := names[policy.Name]; found {
+			vea.Report(pgerror.Newf(pgcode.DuplicateObject,
+				"duplicate policy name: %q", policy.Name))
+		}
+		names[policy.Name] = struct{}{}
+		if _, found := ids[policy.ID]; found {
+			vea.Report(pgerror.Newf(pgcode.DuplicateObject,
+				"policy ID %d in policy %q already in use", policy.ID, policy.Name))
+		}
+		ids[policy.ID] = struct{}{}
+		if len(policy.RoleNames) == 0 {
+			vea.Report(errors.AssertionFailedf(
+				"policy %q has no roles", policy.Name))
+		}
+		if policy.Command == descpb.PolicyDescriptor_INSERT && policy.UsingExpr != "" {
+			vea.Report(errors.AssertionFailedf(
+				"INSERT policy %q cannot have a USING expression", policy.Name))
+		}
+		if (policy.Command == descpb.PolicyDescriptor_SELECT ||
+			policy.Command == descpb.PolicyDescriptor_DELETE) && policy.WithCheckExpr != "" {
+			vea.Report(errors.AssertionFailedf(
+				"%s policy %q cannot have a WITH CHECK expression", policy.Command, policy.Name))
+		}
+		for _, exprStr := range []string{policy.UsingExpr, policy.WithCheckExpr} {
+			if exprStr == "" {
+				continue
+			}
+			expr, err := parser.ParseExpr(exprStr)
+			if err != nil {
+				vea.Report(err)
+				continue
+			}
+			valid, err := schemaexpr.HasValidColumnReferences(desc, expr)
+			if err != nil {
+				vea.Report(err)
+			} else if !valid {
+				vea.Report(errors.Newf("policy %q refers to unknown columns in expression: %s",
+					policy.Name, exprStr))
+			}
+		}
+	}
+}
+
 func (desc *wrapper) validateColumns() error {
 	columnIDs := make(map[descpb.ColumnID]*descpb.ColumnDescriptor, len(desc.Columns))
 	columnNames := make(map[string]descpb.ColumnID, len(desc.Columns))
@@ -1384,6 +1527,73 @@ func (desc *wrapper) validateUniqueWithoutIndexConstraints(
 	return nil
 }
 
+// validateExclusionConstraints validates that exclusion constraints are well
+// formed. Checks include validating the column IDs, the operators and the
+// predicate.
+func (desc *wrapper) validateExclusionConstraints(
+	columnsByID map[descpb.ColumnID]catalog.Column,
+) error {
+	for _, c := range desc.ExclusionConstraints() {
+		if len(c.GetName()) == 0 {
+			return pgerror.Newf(pgcode.Syntax, "empty exclusion constraint name")
+		}
+
+		// Verify that the table ID is valid.
+		if c.ParentTableID() != desc.ID {
+			return errors.Newf(
+				"TableID mismatch for exclusion constraint %q: \"%d\" doesn't match descriptor: \"%d\"",
+				c.GetName(), c.ParentTableID(), desc.ID,
+			)
+		}
+
+		if c.NumKeyColumns() == 0 {
+			return errors.Newf("exclusion constraint %q has no columns", c.GetName())
+		}
+		if len(c.ExclusionDesc().Operators) != c.NumKeyColumns() {
+			return errors.Newf(
+				"exclusion constraint %q has %d columns but %d operators",
+				c.GetName(), c.NumKeyColumns(), len(c.ExclusionDesc().Operators),
+			)
+		}
+
+		// Verify that the constraint's column IDs and operators are valid.
+		for i, n := 0, c.NumKeyColumns(); i < n; i++ {
+			colID := c.GetKeyColumnID(i)
+			if _, ok := columnsByID[colID]; !ok {
+				return errors.Newf(
+					"exclusion constraint %q contains unknown column \"%d\"", c.GetName(), colID,
+				)
+			}
+			if _, ok := exclusionOperators[c.ExclusionDesc().Operators[i]]; !ok {
+				return errors.Newf(
+					"exclusion constraint %q contains unknown operator %q",
+					c.GetName(), c.ExclusionDesc().Operators[i],
+				)
+			}
+		}
+
+		if c.IsPartial() {
+			expr, err := parser.ParseExpr(c.GetPredicate())
+			if err != nil {
+				return err
+			}
+			valid, err := schemaexpr.HasValidColumnReferences(desc, expr)
+			if err != nil {
+				return err
+			}
+			if !valid {
+				return errors.Newf(
+					"partial exclusion constraint %q refers to unknown columns in predicate: %s",
+					c.GetName(),
+					c.GetPredicate(),
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
 // validateTableIndexes validates that indexes are well formed. Checks include
 // validating the columns involved in the index, verifying the index names and
 // IDs are unique, and the family of the primary key is 0. This does not check
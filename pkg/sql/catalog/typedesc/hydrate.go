@@ -159,4 +159,16 @@ func ensureTypeMetadataIsHydrated(
 			}
 		}
 	}
+	if d := maybeDesc.AsDomainTypeDescriptor(); d != nil {
+		tm.DomainData = &types.DomainMetadata{
+			NotNull:          d.IsNotNull(),
+			CheckConstraints: make([]types.DomainCheckConstraint, d.NumCheckConstraints()),
+		}
+		for i := range tm.DomainData.CheckConstraints {
+			tm.DomainData.CheckConstraints[i] = types.DomainCheckConstraint{
+				Name: d.GetCheckConstraintName(i),
+				Expr: d.GetCheckConstraintExpr(i),
+			}
+		}
+	}
 }
@@ -43,6 +43,7 @@ var _ catalog.EnumTypeDescriptor = (*immutable)(nil)
 var _ catalog.RegionEnumTypeDescriptor = (*immutable)(nil)
 var _ catalog.AliasTypeDescriptor = (*immutable)(nil)
 var _ catalog.CompositeTypeDescriptor = (*immutable)(nil)
+var _ catalog.DomainTypeDescriptor = (*immutable)(nil)
 var _ catalog.TypeDescriptor = (*Mutable)(nil)
 var _ catalog.MutableDescriptor = (*Mutable)(nil)
 
@@ -146,7 +147,7 @@ func UserDefinedTypeOIDToID(oid oid.Oid) descpb.ID {
 
 // GetUserDefinedTypeDescID gets the type descriptor ID from a user defined type.
 func GetUserDefinedTypeDescID(t *types.T) descpb.ID {
-	return UserDefinedTypeOIDToID(t.Oid())
+	return UserDefinedTypeOIDToID(t.UserDefinedTypeOID())
 }
 
 // GetUserDefinedArrayTypeDescID gets the ID of the array type descriptor from a user
@@ -508,6 +509,33 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 		if desc.Composite == nil {
 			vea.Report(errors.AssertionFailedf("COMPOSITE type desc has nil composite type"))
 		}
+	case descpb.TypeDescriptor_DOMAIN:
+		if desc.Domain == nil {
+			vea.Report(errors.AssertionFailedf("DOMAIN type desc has nil domain"))
+			break
+		}
+		if desc.ArrayTypeID != descpb.InvalidID {
+			vea.Report(errors.AssertionFailedf("DOMAIN type desc has array type ID %d", desc.ArrayTypeID))
+		}
+		if desc.Domain.BaseType == nil {
+			vea.Report(errors.AssertionFailedf("DOMAIN type desc has nil base type"))
+		} else if desc.Domain.BaseType.UserDefined() {
+			vea.Report(errors.AssertionFailedf("DOMAIN type desc has user-defined base type %s",
+				desc.Domain.BaseType.SQLStringForError()))
+		}
+		names := make(map[string]struct{}, len(desc.Domain.CheckConstraints))
+		for _, c := range desc.Domain.CheckConstraints {
+			if c.Name == "" {
+				vea.Report(errors.AssertionFailedf("DOMAIN type desc has unnamed check constraint"))
+			}
+			if _, ok := names[c.Name]; ok {
+				vea.Report(errors.AssertionFailedf("duplicate domain check constraint %q", c.Name))
+			}
+			names[c.Name] = struct{}{}
+			if c.Expr == "" {
+				vea.Report(errors.AssertionFailedf("domain check constraint %q has empty expression", c.Name))
+			}
+		}
 	case descpb.TypeDescriptor_TABLE_IMPLICIT_RECORD_TYPE:
 		vea.Report(errors.AssertionFailedf("invalid type descriptor: kind %s should never be serialized or validated", desc.Kind.String()))
 	default:
@@ -768,6 +796,8 @@ func (desc *immutable) AsTypesT() *types.T {
 			contents,
 			labels,
 		)
+	case descpb.TypeDescriptor_DOMAIN:
+		return types.MakeDomain(catid.TypeIDToOID(desc.GetID()), desc.Domain.BaseType)
 	}
 	panic(errors.AssertionFailedf("unsupported descriptor kind %s", desc.Kind.String()))
 }
@@ -932,6 +962,9 @@ func (desc *immutable) GetIDClosure() (ret catalog.DescriptorIDSet) {
 		for _, e := range desc.Composite.Elements {
 			GetTypeDescriptorClosure(e.ElementType).ForEach(ret.Add)
 		}
+	case descpb.TypeDescriptor_DOMAIN:
+		// Domains don't have array types, and their base types are never
+		// user-defined.
 	default:
 		// Otherwise, take the array type ID.
 		ret.Add(desc.ArrayTypeID)
@@ -957,6 +990,10 @@ func GetTypeDescriptorClosure(typ *types.T) (ret catalog.DescriptorIDSet) {
 	}
 	// Collect the type's descriptor ID.
 	ret.Add(GetUserDefinedTypeDescID(typ))
+	if typ.IsDomain() {
+		// Domains don't have array types.
+		return ret
+	}
 	switch typ.Family() {
 	case types.ArrayFamily:
 		// If we have an array type, then collect all types in the contents.
@@ -1006,6 +1043,14 @@ func (desc *immutable) AsCompositeTypeDescriptor() catalog.CompositeTypeDescript
 	return nil
 }
 
+// AsDomainTypeDescriptor implements the catalog.TypeDescriptor interface.
+func (desc *immutable) AsDomainTypeDescriptor() catalog.DomainTypeDescriptor {
+	if desc.Kind == descpb.TypeDescriptor_DOMAIN {
+		return desc
+	}
+	return nil
+}
+
 // AsTableImplicitRecordTypeDescriptor implements the catalog.TypeDescriptor
 // interface.
 func (desc *immutable) AsTableImplicitRecordTypeDescriptor() catalog.TableImplicitRecordTypeDescriptor {
@@ -1032,6 +1077,39 @@ func (desc *immutable) GetElementType(ordinal int) *types.T {
 	return desc.Composite.Elements[ordinal].ElementType
 }
 
+// BaseType implements the catalog.DomainTypeDescriptor interface.
+func (desc *immutable) BaseType() *types.T {
+	return desc.Domain.BaseType
+}
+
+// IsNotNull implements the catalog.DomainTypeDescriptor interface.
+func (desc *immutable) IsNotNull() bool {
+	return desc.Domain.NotNull || desc.Domain.ValidatingNotNull
+}
+
+// NumCheckConstraints implements the catalog.DomainTypeDescriptor interface.
+func (desc *immutable) NumCheckConstraints() int {
+	return len(desc.Domain.CheckConstraints)
+}
+
+// GetCheckConstraintName implements the catalog.DomainTypeDescriptor
+// interface.
+func (desc *immutable) GetCheckConstraintName(ordinal int) string {
+	return desc.Domain.CheckConstraints[ordinal].Name
+}
+
+// GetCheckConstraintExpr implements the catalog.DomainTypeDescriptor
+// interface.
+func (desc *immutable) GetCheckConstraintExpr(ordinal int) string {
+	return desc.Domain.CheckConstraints[ordinal].Expr
+}
+
+// IsCheckConstraintValidated implements the catalog.DomainTypeDescriptor
+// interface.
+func (desc *immutable) IsCheckConstraintValidated(ordinal int) bool {
+	return desc.Domain.CheckConstraints[ordinal].Validity == descpb.ConstraintValidity_Validated
+}
+
 // ForEachRegionInSuperRegion implements the catalog.RegionEnumTypeDescriptor
 // interface.
 func (desc *immutable) ForEachRegionInSuperRegion(
@@ -277,7 +277,7 @@ func nonMatchingRowQuery(
 func validateForeignKey(
 	ctx context.Context,
 	txn isql.Txn,
-	srcTable *tabledesc.Mutable,
+	srcTable catalog.TableDescriptor,
 	targetTable catalog.TableDescriptor,
 	fk *descpb.ForeignKeyConstraint,
 	indexIDForValidation descpb.IndexID,
@@ -302,7 +302,7 @@ func validateForeignKey(
 
 		log.Infof(ctx, "validating MATCH FULL FK %q (%q [%v] -> %q [%v]) with query %q",
 			fk.Name,
-			srcTable.Name, colNames,
+			srcTable.GetName(), colNames,
 			targetTable.GetName(), referencedColumnNames,
 			query,
 		)
@@ -327,7 +327,7 @@ func validateForeignKey(
 
 	log.Infof(ctx, "validating FK %q (%q [%v] -> %q [%v]) with query %q",
 		fk.Name,
-		srcTable.Name, colNames, targetTable.GetName(), referencedColumnNames,
+		srcTable.GetName(), colNames, targetTable.GetName(), referencedColumnNames,
 		query,
 	)
 
@@ -339,7 +339,7 @@ func validateForeignKey(
 	if values.Len() > 0 {
 		return pgerror.WithConstraintName(pgerror.Newf(pgcode.ForeignKeyViolation,
 			"foreign key violation: %q row %s has no match in %q",
-			srcTable.Name, formatValues(colNames, values), targetTable.GetName()), fk.Name)
+			srcTable.GetName(), formatValues(colNames, values), targetTable.GetName()), fk.Name)
 	}
 	return nil
 }
@@ -700,6 +700,147 @@ func validateUniqueConstraint(
 	return nil
 }
 
+// conflictingRowQuery generates and returns a query for pairs of rows that
+// violate the given exclusion constraint. Two rows conflict if they are
+// different rows of the table and all of the constraint's operators return
+// true when comparing their values.
+//
+// For example, an exclusion constraint EXCLUDE (a WITH =, b WITH &&) on the
+// table "tbl" with primary key k would require the following query:
+//
+// SELECT l.a, l.b, r.a, r.b
+// FROM (SELECT a, b, k FROM tbl) AS l
+// JOIN (SELECT a, b, k FROM tbl) AS r ON l.a = r.a AND l.b && r.b
+// WHERE (l.k) != (r.k)
+// LIMIT 1
+//
+// If the constraint is partial, its predicate is added to the WHERE clause of
+// both subqueries.
+func conflictingRowQuery(
+	srcTbl catalog.TableDescriptor, excl catalog.ExclusionConstraint,
+) (sql string, colNames []string, _ error) {
+	colNames = make([]string, excl.NumKeyColumns())
+	var cols catalog.TableColSet
+	for i := range colNames {
+		col, err := catalog.MustFindColumnByID(srcTbl, excl.GetKeyColumnID(i))
+		if err != nil {
+			return "", nil, err
+		}
+		colNames[i] = col.GetName()
+		cols.Add(col.GetID())
+	}
+	pkColNames := make([]string, 0, srcTbl.GetPrimaryIndex().NumKeyColumns())
+	for i := 0; i < srcTbl.GetPrimaryIndex().NumKeyColumns(); i++ {
+		name := srcTbl.GetPrimaryIndex().GetKeyColumnName(i)
+		pkColNames = append(pkColNames, name)
+		cols.Add(srcTbl.GetPrimaryIndex().GetKeyColumnID(i))
+	}
+	allColNames, err := catalog.ColumnNamesForIDs(srcTbl, cols.Ordered())
+	if err != nil {
+		return "", nil, err
+	}
+
+	srcCols := make([]string, len(allColNames))
+	for i, n := range allColNames {
+		srcCols[i] = tree.NameString(n)
+	}
+	where := ""
+	if excl.IsPartial() {
+		where = fmt.Sprintf(" WHERE (%s)", excl.GetPredicate())
+	}
+	subquery := fmt.Sprintf(
+		`(SELECT %s FROM [%d AS tbl]%s)`, strings.Join(srcCols, ", "), srcTbl.GetID(), where,
+	)
+
+	outCols := make([]string, 0, 2*len(colNames))
+	for _, side := range []string{"l", "r"} {
+		for _, n := range colNames {
+			outCols = append(outCols, fmt.Sprintf("%s.%s", side, tree.NameString(n)))
+		}
+	}
+	on := make([]string, len(colNames))
+	for i, n := range colNames {
+		on[i] = fmt.Sprintf(
+			"l.%[1]s %[2]s r.%[1]s", tree.NameString(n), excl.GetOperator(i).String(),
+		)
+	}
+	lPK := make([]string, len(pkColNames))
+	rPK := make([]string, len(pkColNames))
+	for i, n := range pkColNames {
+		lPK[i] = "l." + tree.NameString(n)
+		rPK[i] = "r." + tree.NameString(n)
+	}
+
+	query := fmt.Sprintf(
+		`SELECT %[1]s FROM %[2]s AS l JOIN %[2]s AS r ON %[3]s WHERE (%[4]s) != (%[5]s) LIMIT 1`,
+		strings.Join(outCols, ", "), // 1
+		subquery,                    // 2
+		strings.Join(on, " AND "),   // 3
+		strings.Join(lPK, ", "),     // 4
+		strings.Join(rPK, ", "),     // 5
+	)
+	return query, colNames, nil
+}
+
+// validateExclusionConstraint verifies that no two rows in the srcTable
+// conflict according to the given exclusion constraint.
+//
+// It operates entirely on the current goroutine and is thus able to
+// reuse an existing kv.Txn safely.
+func validateExclusionConstraint(
+	ctx context.Context,
+	srcTable catalog.TableDescriptor,
+	excl catalog.ExclusionConstraint,
+	txn isql.Txn,
+	user username.SQLUsername,
+) error {
+	query, colNames, err := conflictingRowQuery(srcTable, excl)
+	if err != nil {
+		return err
+	}
+
+	log.Infof(ctx, "validating exclusion constraint %q (%q [%v]) with query %q",
+		excl.GetName(),
+		srcTable.GetName(),
+		colNames,
+		query,
+	)
+
+	sessionDataOverride := sessiondata.NoSessionDataOverride
+	sessionDataOverride.User = user
+	values, err := txn.QueryRowEx(
+		ctx, "validate exclusion constraint", txn.KV(), sessionDataOverride, query,
+	)
+	if err != nil {
+		return err
+	}
+	if values.Len() > 0 {
+		formatKey := func(vals tree.Datums) string {
+			valuesStr := make([]string, len(vals))
+			for i := range vals {
+				valuesStr[i] = vals[i].String()
+			}
+			return fmt.Sprintf("(%s)=(%s)", strings.Join(colNames, ", "), strings.Join(valuesStr, ", "))
+		}
+		n := len(colNames)
+		// Note: this error message mirrors the message produced by Postgres
+		// when it fails to add an exclusion constraint due to conflicting rows.
+		return errors.WithDetail(
+			pgerror.WithConstraintName(
+				pgerror.Newf(
+					pgcode.ExclusionViolation,
+					"could not create exclusion constraint %q", excl.GetName(),
+				),
+				excl.GetName(),
+			),
+			fmt.Sprintf(
+				"Key %s conflicts with key %s.", formatKey(values[:n]), formatKey(values[n:]),
+			),
+		)
+	}
+	return nil
+}
+
 // ValidateTTLScheduledJobsInCurrentDB is part of the EvalPlanner interface.
 func (p *planner) ValidateTTLScheduledJobsInCurrentDB(ctx context.Context) error {
 	dbName := p.CurrentDatabase()
@@ -857,6 +857,11 @@ func NewColOperator(
 						core.TableReader.LockingWaitPolicy == descpb.ScanLockingWaitPolicy_SKIP_LOCKED {
 						return false
 					}
+					// Sampling requires the full key of each row, which is
+					// not available in the direct scans either.
+					if core.TableReader.Sample != nil {
+						return false
+					}
 					// At the moment, the ColBatchDirectScan cannot handle Gets
 					// (it's not clear whether it is worth to handle them via
 					// the same path as for Scans and ReverseScans (which could
@@ -966,6 +971,20 @@ func NewColOperator(
 				break
 			}
 
+			aggInput, aggInputTypes := inputs[0].Root, spec.Input[0].ColumnTypes
+			if len(aggSpec.GroupingSets) > 0 {
+				// The grouping sets are computed by the hash aggregation of the
+				// input emitted once for each grouping set, with the ordinal of
+				// the set as an additional grouping column.
+				aggInput = colexec.NewGroupingSetsExpanderOp(
+					getStreamingAllocator(ctx, args), aggInput, aggInputTypes,
+					aggSpec.GroupCols, aggSpec.GroupingSets,
+				)
+				setIdxCol := uint32(len(aggInputTypes))
+				aggInputTypes = append(aggInputTypes[:len(aggInputTypes):len(aggInputTypes)], types.Int)
+				aggSpec = colexec.GroupingSetsAggregatorSpec(aggSpec, setIdxCol)
+			}
+
 			var needHash bool
 			needHash, err = needHashAggregator(aggSpec)
 			if err != nil {
@@ -974,13 +993,13 @@ func NewColOperator(
 			// Make a copy of the evalCtx since we're modifying it below.
 			evalCtx := flowCtx.NewEvalCtx()
 			newAggArgs := &colexecagg.NewAggregatorArgs{
-				Input:      inputs[0].Root,
-				InputTypes: spec.Input[0].ColumnTypes,
+				Input:      aggInput,
+				InputTypes: aggInputTypes,
 				Spec:       aggSpec,
 				EvalCtx:    evalCtx,
 			}
 			newAggArgs.Constructors, newAggArgs.ConstArguments, newAggArgs.OutputTypes, err = colexecagg.ProcessAggregations(
-				ctx, evalCtx, args.ExprHelper.SemaCtx, aggSpec.Aggregations, spec.Input[0].ColumnTypes,
+				ctx, evalCtx, args.ExprHelper.SemaCtx, aggSpec.Aggregations, aggInputTypes,
 			)
 			if err != nil {
 				return r, err
@@ -1024,7 +1043,7 @@ func NewColOperator(
 					newHashAggArgs, sqArgs, hashAggregatorMemMonitorName := makeNewHashAggregatorArgs(
 						ctx, flowCtx, args, opName, newAggArgs, factory,
 					)
-					sqArgs.Types = spec.Input[0].ColumnTypes
+					sqArgs.Types = aggInputTypes
 					inMemoryHashAggregator := colexec.NewHashAggregator(
 						ctx, newHashAggArgs, sqArgs,
 					)
@@ -1041,7 +1060,7 @@ func NewColOperator(
 					// error even when used by the external hash aggregator).
 					evalCtx.SingleDatumAggMemAccount = ehaMemAccount
 					diskSpiller := colexecdisk.NewOneInputDiskSpiller(
-						inputs[0].Root, inMemoryHashAggregator.(colexecop.BufferingInMemoryOperator),
+						aggInput, inMemoryHashAggregator.(colexecop.BufferingInMemoryOperator),
 						hashAggregatorMemMonitorName,
 						func(input colexecop.Operator) colexecop.Operator {
 							newAggArgs := *newAggArgs
@@ -1082,6 +1101,13 @@ func NewColOperator(
 				result.Root = colexec.NewOrderedAggregator(ctx, newAggArgs)
 				result.ToClose = append(result.ToClose, result.Root.(colexecop.Closer))
 			}
+			if len(core.Aggregator.GroupingSets) > 0 {
+				result.Root = colexec.NewGroupingSetsEmptySetsOp(
+					getStreamingAllocator(ctx, args), result.Root, result.ColumnTypes,
+					core.Aggregator.GroupingSets, evalCtx, newAggArgs.Constructors,
+					newAggArgs.ConstArguments,
+				)
+			}
 
 		case core.Distinct != nil:
 			if err := checkNumIn(inputs, 1); err != nil {
@@ -2240,6 +2266,10 @@ func planCastOperator(
 	factory coldata.ColumnFactory,
 	evalCtx *eval.Context,
 ) (op colexecop.Operator, resultIdx int, typs []*types.T, err error) {
+	if toType.IsDomain() {
+		// The constraints of domains are only checked by the row-by-row engine.
+		return nil, 0, nil, errors.Errorf("unhandled cast to domain %s", toType.SQLStringForError())
+	}
 	outputIdx := len(columnTypes)
 	op, err = colexecbase.GetCastOperator(colmem.NewAllocator(ctx, acc, factory), input, inputIdx, outputIdx, fromType, toType, evalCtx)
 	typs = append(columnTypes, toType)
@@ -0,0 +1,304 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra/execagg"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// groupingSetsExpanderOp is an operator that emits every input batch once for
+// each grouping set of a GROUPING SETS, ROLLUP or CUBE clause. In the batch of
+// a grouping set, the grouping columns that are not part of the set are
+// replaced by vectors of NULLs, and an INT8 column with the ordinal of the set
+// is appended. Grouping the emitted batches by the grouping columns and the
+// ordinal computes all of the grouping sets in a single pass over the input.
+//
+// The vectors of the input batches are shared with the emitted batches, so the
+// consumer must not modify them.
+type groupingSetsExpanderOp struct {
+	colexecop.OneInputHelper
+
+	groupCols []uint32
+	// inSet[i][j] is true if groupCols[j] is part of the i-th grouping set.
+	inSet [][]bool
+
+	// nullVecs contains a vector of NULLs for each of the groupCols.
+	nullVecs []coldata.Vec
+	// setIdxVecs contains a vector filled with the ordinal of each grouping
+	// set.
+	setIdxVecs []coldata.Vec
+
+	output coldata.Batch
+	batch  coldata.Batch
+	// nextSet is the ordinal of the next grouping set for which batch is
+	// emitted.
+	nextSet int
+}
+
+var _ colexecop.Operator = &groupingSetsExpanderOp{}
+
+// NewGroupingSetsExpanderOp returns a new operator that emits every input
+// batch once for each of the grouping sets. The output has the input columns
+// followed by an INT8 column with the ordinal of the grouping set.
+func NewGroupingSetsExpanderOp(
+	allocator *colmem.Allocator,
+	input colexecop.Operator,
+	inputTypes []*types.T,
+	groupCols []uint32,
+	groupingSets []execinfrapb.AggregatorSpec_GroupingSet,
+) colexecop.Operator {
+	e := &groupingSetsExpanderOp{
+		OneInputHelper: colexecop.MakeOneInputHelper(input),
+		groupCols:      groupCols,
+		inSet:          make([][]bool, len(groupingSets)),
+		nullVecs:       make([]coldata.Vec, len(groupCols)),
+		setIdxVecs:     make([]coldata.Vec, len(groupingSets)),
+	}
+	for i, set := range groupingSets {
+		e.inSet[i] = make([]bool, len(groupCols))
+		for _, col := range set.Cols {
+			found := false
+			for j, groupCol := range groupCols {
+				if groupCol == col {
+					e.inSet[i][j] = true
+					found = true
+				}
+			}
+			if !found {
+				colexecerror.InternalError(errors.AssertionFailedf(
+					"grouping set column %d is not a grouping column", col,
+				))
+			}
+		}
+	}
+	capacity := coldata.BatchSize()
+	for j, col := range groupCols {
+		e.nullVecs[j] = allocator.NewMemColumn(inputTypes[col], capacity)
+		e.nullVecs[j].Nulls().SetNulls()
+	}
+	for i := range e.setIdxVecs {
+		e.setIdxVecs[i] = allocator.NewMemColumn(types.Int, capacity)
+		setIdx := e.setIdxVecs[i].Int64()
+		for k := range setIdx {
+			setIdx[k] = int64(i)
+		}
+	}
+	outputTypes := make([]*types.T, len(inputTypes)+1)
+	copy(outputTypes, inputTypes)
+	outputTypes[len(inputTypes)] = types.Int
+	e.output = coldata.NewMemBatchNoCols(outputTypes, capacity)
+	return e
+}
+
+func (e *groupingSetsExpanderOp) Next() coldata.Batch {
+	if e.batch == nil || e.nextSet == len(e.inSet) {
+		e.batch = e.Input.Next()
+		e.nextSet = 0
+		if e.batch.Length() == 0 {
+			return coldata.ZeroBatch
+		}
+	}
+	setIdx := e.nextSet
+	e.nextSet++
+
+	width := e.batch.Width()
+	for i := 0; i < width; i++ {
+		e.output.ReplaceCol(e.batch.ColVec(i), i)
+	}
+	for j, col := range e.groupCols {
+		if !e.inSet[setIdx][j] {
+			e.output.ReplaceCol(e.nullVecs[j], int(col))
+		}
+	}
+	e.output.ReplaceCol(e.setIdxVecs[setIdx], width)
+	// The consumer might modify the selection vector of the output batch, so
+	// we copy the selection vector of the input batch for every grouping set.
+	n := e.batch.Length()
+	if sel := e.batch.Selection(); sel != nil {
+		e.output.SetSelection(true)
+		copy(e.output.Selection()[:n], sel[:n])
+	} else {
+		e.output.SetSelection(false)
+	}
+	e.output.SetLength(n)
+	return e.output
+}
+
+// groupingSetsEmptySetsOp is an operator that passes through the output of an
+// aggregator produced over the output of a groupingSetsExpanderOp. Once the
+// input is exhausted, it emits a row for each empty grouping set for which the
+// aggregator didn't emit a row, which happens when the input of the aggregator
+// is empty. The results of the aggregate functions in such rows are the
+// results over an empty input.
+type groupingSetsEmptySetsOp struct {
+	colexecop.OneInputHelper
+
+	allocator      *colmem.Allocator
+	outputTypes    []*types.T
+	evalCtx        *eval.Context
+	constructors   []execagg.AggregateConstructor
+	constArguments []tree.Datums
+
+	// seen[i] is true if the input contained a row for the i-th grouping set.
+	// It is only maintained for empty grouping sets.
+	seen []bool
+	// emptySets contains the ordinals of the empty grouping sets.
+	emptySets []int
+	// pending contains the ordinals of the empty grouping sets that are still
+	// to be emitted after the input is exhausted.
+	pending []int
+	done    bool
+
+	output coldata.Batch
+	vecs   coldata.TypedVecs
+	row    rowenc.EncDatumRow
+	dalloc tree.DatumAlloc
+}
+
+var _ colexecop.Operator = &groupingSetsEmptySetsOp{}
+
+// NewGroupingSetsEmptySetsOp returns a new operator that adds the rows for the
+// empty grouping sets to the output of an aggregator over an empty input. The
+// last column of the input is the ordinal of the grouping set, and the other
+// columns are the results of the aggregate functions given by constructors.
+func NewGroupingSetsEmptySetsOp(
+	allocator *colmem.Allocator,
+	input colexecop.Operator,
+	outputTypes []*types.T,
+	groupingSets []execinfrapb.AggregatorSpec_GroupingSet,
+	evalCtx *eval.Context,
+	constructors []execagg.AggregateConstructor,
+	constArguments []tree.Datums,
+) colexecop.Operator {
+	o := &groupingSetsEmptySetsOp{
+		OneInputHelper: colexecop.MakeOneInputHelper(input),
+		allocator:      allocator,
+		outputTypes:    outputTypes,
+		evalCtx:        evalCtx,
+		constructors:   constructors,
+		constArguments: constArguments,
+		seen:           make([]bool, len(groupingSets)),
+	}
+	for i := range groupingSets {
+		if len(groupingSets[i].Cols) == 0 {
+			o.emptySets = append(o.emptySets, i)
+		}
+	}
+	return o
+}
+
+func (o *groupingSetsEmptySetsOp) Next() coldata.Batch {
+	if !o.done {
+		batch := o.Input.Next()
+		n := batch.Length()
+		if n > 0 {
+			if len(o.emptySets) > 0 {
+				setIdxs := batch.ColVec(batch.Width() - 1).Int64()
+				if sel := batch.Selection(); sel != nil {
+					for _, k := range sel[:n] {
+						o.seen[setIdxs[k]] = true
+					}
+				} else {
+					for k := 0; k < n; k++ {
+						o.seen[setIdxs[k]] = true
+					}
+				}
+			}
+			return batch
+		}
+		o.done = true
+		for _, setIdx := range o.emptySets {
+			if !o.seen[setIdx] {
+				o.pending = append(o.pending, setIdx)
+			}
+		}
+		if len(o.pending) > 0 {
+			o.row = o.emptyInputResults()
+		}
+	}
+	if len(o.pending) == 0 {
+		return coldata.ZeroBatch
+	}
+
+	var reallocated bool
+	o.output, reallocated = o.allocator.ResetMaybeReallocateNoMemLimit(
+		o.outputTypes, o.output, len(o.pending),
+	)
+	if reallocated {
+		o.vecs.SetBatch(o.output)
+	}
+	n := o.output.Capacity()
+	if n > len(o.pending) {
+		n = len(o.pending)
+	}
+	o.allocator.PerformOperation(o.output.ColVecs(), func() {
+		for k := 0; k < n; k++ {
+			o.row[len(o.row)-1] = rowenc.EncDatum{Datum: tree.NewDInt(tree.DInt(o.pending[k]))}
+			EncDatumRowToColVecs(o.row, k, o.vecs, o.outputTypes, &o.dalloc)
+		}
+	})
+	o.pending = o.pending[n:]
+	o.output.SetLength(n)
+	return o.output
+}
+
+// emptyInputResults returns a row with the results of the aggregate functions
+// over an empty input. The last column, the ordinal of the grouping set, is
+// left unset.
+func (o *groupingSetsEmptySetsOp) emptyInputResults() rowenc.EncDatumRow {
+	row := make(rowenc.EncDatumRow, len(o.outputTypes))
+	for i, constructor := range o.constructors {
+		fn := constructor(o.evalCtx, o.constArguments[i])
+		result, err := fn.Result()
+		fn.Close(o.Ctx)
+		if err != nil {
+			colexecerror.ExpectedError(err)
+		}
+		if result == nil {
+			result = tree.DNull
+		}
+		row[i] = rowenc.EncDatum{Datum: result}
+	}
+	return row
+}
+
+// GroupingSetsAggregatorSpec returns the spec of the aggregation that computes
+// the grouping sets of spec over the output of a groupingSetsExpanderOp: the
+// ordinal of the grouping set, at index setIdxCol of the input, is an
+// additional grouping column, and it is output after the aggregations.
+func GroupingSetsAggregatorSpec(
+	spec *execinfrapb.AggregatorSpec, setIdxCol uint32,
+) *execinfrapb.AggregatorSpec {
+	newSpec := *spec
+	newSpec.GroupingSets = nil
+	newSpec.GroupCols = make([]uint32, len(spec.GroupCols), len(spec.GroupCols)+1)
+	copy(newSpec.GroupCols, spec.GroupCols)
+	newSpec.GroupCols = append(newSpec.GroupCols, setIdxCol)
+	newSpec.Aggregations = make([]execinfrapb.AggregatorSpec_Aggregation, len(spec.Aggregations), len(spec.Aggregations)+1)
+	copy(newSpec.Aggregations, spec.Aggregations)
+	newSpec.Aggregations = append(newSpec.Aggregations, execinfrapb.AggregatorSpec_Aggregation{
+		Func:   execinfrapb.AnyNotNull,
+		ColIdx: []uint32{setIdxCol},
+	})
+	return &newSpec
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexectestutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+func TestGroupingSetsExpander(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	tuples := colexectestutils.Tuples{{1, 2, 3}, {4, 5, 6}}
+	// GROUP BY ROLLUP (@1, @2).
+	groupingSets := []execinfrapb.AggregatorSpec_GroupingSet{
+		{Cols: []uint32{0, 1}},
+		{Cols: []uint32{0}},
+		{},
+	}
+	expected := colexectestutils.Tuples{
+		{1, 2, 3, 0},
+		{4, 5, 6, 0},
+		{1, nil, 3, 1},
+		{4, nil, 6, 1},
+		{nil, nil, 3, 2},
+		{nil, nil, 6, 2},
+	}
+	colexectestutils.RunTestsWithoutAllNullsInjection(
+		t, testAllocator, []colexectestutils.Tuples{tuples}, nil, expected,
+		colexectestutils.UnorderedVerifier,
+		func(input []colexecop.Operator) (colexecop.Operator, error) {
+			return NewGroupingSetsExpanderOp(
+				testAllocator, input[0], types.MakeIntCols(3), []uint32{0, 1}, groupingSets,
+			), nil
+		},
+	)
+}
@@ -191,6 +191,10 @@ type cFetcherArgs struct {
 	// the last one returned on the NextBatch calls if the caller wishes to keep
 	// multiple batches at the same time.
 	alwaysReallocate bool
+	// sampler, if sampling, determines which rows are returned by the fetcher
+	// (TABLESAMPLE BERNOULLI). All other rows are discarded once they have been
+	// decoded.
+	sampler rowinfra.KeySampler
 }
 
 // noOutputColumn is a sentinel value to denote that a system column is not
@@ -281,6 +285,9 @@ type cFetcher struct {
 		lastRowPrefix roachpb.Key
 		// firstKeyOfRow, if set, is the first key in the current row.
 		firstKeyOfRow roachpb.Key
+		// rowNotSampled is true if the current row is not part of the sample,
+		// and should be discarded when it is finalized.
+		rowNotSampled bool
 		// prettyValueBuf is a temp buffer used to create strings for tracing.
 		prettyValueBuf *bytes.Buffer
 
@@ -816,6 +823,10 @@ func (cf *cFetcher) NextBatch(ctx context.Context) (coldata.Batch, error) {
 				}
 				cf.machine.lastRowPrefix = cf.machine.nextKV.Key[:prefixLen+(origRemainingBytesLen-len(remainingBytes))]
 			}
+			// Decide whether the row is part of the sample now, since
+			// lastRowPrefix might no longer be valid by the time the row is
+			// finalized.
+			cf.machine.rowNotSampled = cf.sampler.Sampling() && !cf.sampler.Keep(cf.machine.lastRowPrefix)
 
 			familyID, err := cf.getCurrentColumnFamilyID()
 			if err != nil {
@@ -908,6 +919,16 @@ func (cf *cFetcher) NextBatch(ctx context.Context) (coldata.Batch, error) {
 			}
 
 		case stateFinalizeRow:
+			if cf.machine.rowNotSampled {
+				// The row is not part of the sample, so the next row will
+				// overwrite its values. Only the nulls set while decoding it
+				// need to be cleared.
+				for _, nulls := range cf.machine.colvecs.Nulls {
+					nulls.UnsetNull(cf.machine.rowIdx)
+				}
+				cf.shiftState()
+				continue
+			}
 			// Populate the timestamp system column if needed. We have to do it
 			// on a per row basis since each row can be modified at a different
 			// time.
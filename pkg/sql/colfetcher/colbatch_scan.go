@@ -355,6 +355,10 @@ func NewColBatchScan(
 		kvFetcherMemAcc,
 		flowCtx.EvalCtx.TestingKnobs.ForceProductionValues,
 	)
+	var sampler rowinfra.KeySampler
+	if spec.Sample != nil {
+		sampler = rowinfra.MakeKeySampler(spec.Sample.Probability, spec.Sample.Seed)
+	}
 	fetcher := cFetcherPool.Get().(*cFetcher)
 	fetcher.cFetcherArgs = cFetcherArgs{
 		execinfra.GetWorkMemLimit(flowCtx),
@@ -363,6 +367,7 @@ func NewColBatchScan(
 		true, /* singleUse */
 		execstats.ShouldCollectStats(ctx, flowCtx.CollectStats),
 		false, /* alwaysReallocate */
+		sampler,
 	}
 	if err = fetcher.Init(fetcherAllocator, kvFetcher, tableArgs); err != nil {
 		fetcher.Release()
@@ -604,7 +604,8 @@ func NewColIndexJoin(
 		flowCtx.TraceKV,
 		false, /* singleUse */
 		execstats.ShouldCollectStats(ctx, flowCtx.CollectStats),
-		false, /* alwaysReallocate */
+		false,                 /* alwaysReallocate */
+		rowinfra.KeySampler{}, /* sampler */
 	}
 	if err = fetcher.Init(
 		fetcherAllocator, kvFetcher, tableArgs,
@@ -340,6 +340,7 @@ type Server struct {
 
 	reCache           *tree.RegexpCache
 	toCharFormatCache *tochar.FormatCache
+	domainCheckCache  *eval.DomainCheckCache
 
 	// pool is the parent monitor for all session monitors.
 	pool *mon.BytesMonitor
@@ -453,6 +454,7 @@ func NewServer(
 		insights:                insightsProvider,
 		reCache:                 tree.NewRegexpCache(512),
 		toCharFormatCache:       tochar.NewFormatCache(512),
+		domainCheckCache:        eval.NewDomainCheckCache(512),
 		indexUsageStats: idxusage.NewLocalIndexUsageStats(&idxusage.Config{
 			ChannelSize: idxusage.DefaultChannelSize,
 			Setting:     cfg.Settings,
@@ -1128,6 +1130,7 @@ func (s *Server) newConnExecutor(
 		portals:      make(map[string]PreparedPortal),
 	}
 	ex.extraTxnState.prepStmtsNamespaceMemAcc = ex.sessionMon.MakeBoundAccount()
+	ex.extraTxnState.deferredConstraints.memAcc = ex.sessionMon.MakeBoundAccount()
 	dsdp := catsessiondata.NewDescriptorSessionDataStackProvider(sdMutIterator.sds)
 	ex.extraTxnState.descCollection = s.cfg.CollectionFactory.NewCollection(
 		ctx, descs.WithDescriptorSessionDataProvider(dsdp), descs.WithMonitor(ex.sessionMon),
@@ -1218,7 +1221,7 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 	ex.extraTxnState.prepStmtsNamespaceAtTxnRewindPos.closeAllPortals(
 		ctx, &ex.extraTxnState.prepStmtsNamespaceMemAcc,
 	)
-	if err := ex.extraTxnState.sqlCursors.closeAll(false /* errorOnWithHold */); err != nil {
+	if err := ex.extraTxnState.sqlCursors.closeAll(false /* keepHeld */); err != nil {
 		log.Warningf(ctx, "error closing cursors: %v", err)
 	}
 
@@ -1253,6 +1256,12 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 	}
 
 	ex.resetExtraTxnState(ctx, txnEvent{eventType: txnEvType}, payloadErr)
+	if ex.notificationListener != nil {
+		ex.notificationListener.unlistenAll()
+	}
+	if ex.advisoryLocks != nil {
+		ex.advisoryLocks.close(ctx)
+	}
 	if ex.hasCreatedTemporarySchema && !ex.server.cfg.TestingKnobs.DisableTempObjectsCleanupOnSessionExit {
 		err := cleanupSessionTempObjects(
 			ctx,
@@ -1280,6 +1289,7 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 			ctx, &ex.extraTxnState.prepStmtsNamespaceMemAcc,
 		)
 		ex.extraTxnState.prepStmtsNamespaceMemAcc.Close(ctx)
+		ex.extraTxnState.deferredConstraints.memAcc.Close(ctx)
 	}
 
 	if ex.sessionTracing.Enabled() {
@@ -1475,7 +1485,9 @@ type connExecutor struct {
 		// sqlCursors contains the list of SQL CURSORs the session currently has
 		// access to.
 		// Cursors are bound to an explicit transaction and they're all destroyed
-		// once the transaction finishes.
+		// once the transaction finishes, except for WITH HOLD cursors, which are
+		// materialized when their transaction commits and are kept until they
+		// are closed or the session ends.
 		sqlCursors cursorMap
 
 		// shouldExecuteOnTxnFinish indicates that ex.onTxnFinish will be called
@@ -1566,6 +1578,16 @@ type connExecutor struct {
 		// createdSequences keeps track of sequences created in the current transaction.
 		// The map key is the sequence descpb.ID.
 		createdSequences map[descpb.ID]struct{}
+
+		// deferredConstraints keeps track of the constraints whose checks were
+		// deferred until the current transaction commits (see SET CONSTRAINTS),
+		// along with the keys of the rows that need to be checked.
+		deferredConstraints txnDeferredConstraints
+
+		// notifications keeps track of the notifications to send and the
+		// LISTEN/UNLISTEN statements to apply when the current transaction
+		// commits.
+		notifications txnNotifications
 	}
 
 	// sessionDataStack contains the user-configurable connection variables.
@@ -1666,6 +1688,14 @@ type connExecutor struct {
 	// temporary schema, which requires special cleanup on close.
 	hasCreatedTemporarySchema bool
 
+	// notificationListener tracks the channels this session is listening on.
+	// It is created by the first committed LISTEN.
+	notificationListener *notificationListener
+
+	// advisoryLocks tracks the advisory locks held by this session. It is
+	// created by the first acquisition of an advisory lock, while holding mu.
+	advisoryLocks *advisoryLockSession
+
 	// stmtDiagnosticsRecorder is used to track which queries need to have
 	// information collected.
 	stmtDiagnosticsRecorder *stmtdiagnostics.Registry
@@ -1976,6 +2006,8 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent, pay
 	ex.extraTxnState.upgradedToSerializable = false
 	ex.extraTxnState.hasAdminRoleCache = HasAdminRoleCache{}
 	ex.extraTxnState.createdSequences = nil
+	ex.extraTxnState.deferredConstraints.reset(ctx)
+	ex.extraTxnState.notifications = txnNotifications{}
 
 	if ex.extraTxnState.fromOuterTxn {
 		if ex.extraTxnState.shouldResetSyntheticDescriptors {
@@ -1997,11 +2029,18 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent, pay
 		ctx, &ex.extraTxnState.prepStmtsNamespaceMemAcc,
 	)
 
-	// Close all cursors.
-	if err := ex.extraTxnState.sqlCursors.closeAll(false /* errorOnWithHold */); err != nil {
+	// Close all cursors, except for held ones.
+	if err := ex.extraTxnState.sqlCursors.closeAll(true /* keepHeld */); err != nil {
 		log.Warningf(ctx, "error closing cursors: %v", err)
 	}
 
+	// Transaction-level advisory locks are released when the transaction
+	// finishes. They are also released when it restarts, since the statements
+	// that acquired them run again.
+	if ex.advisoryLocks != nil {
+		ex.advisoryLocks.releaseXact(ctx)
+	}
+
 	switch ev.eventType {
 	case txnCommit, txnRollback:
 		ex.extraTxnState.prepStmtsNamespaceAtTxnRewindPos.closeAllPortals(
@@ -2428,6 +2467,15 @@ func (ex *connExecutor) execCmd() (retErr error) {
 		//   was created when the statement started executing (via the
 		//   reset() method).
 		ex.statsCollector.PhaseTimes().SetSessionPhaseTime(sessionphase.SessionQueryServiced, timeutil.Now())
+	case StartReplication:
+		ex.phaseTimes.SetSessionPhaseTime(sessionphase.SessionQueryReceived, tcmd.TimeReceived)
+		ex.phaseTimes.SetSessionPhaseTime(sessionphase.SessionStartParse, tcmd.ParseStart)
+		ex.phaseTimes.SetSessionPhaseTime(sessionphase.SessionEndParse, tcmd.ParseEnd)
+		replRes := ex.clientComm.CreateReplicationResult(tcmd, pos)
+		res = replRes
+		stmtCtx := withStatement(ctx, tcmd.Stmt)
+		ev, payload = ex.execStartReplication(stmtCtx, tcmd, replRes)
+		ex.statsCollector.PhaseTimes().SetSessionPhaseTime(sessionphase.SessionQueryServiced, timeutil.Now())
 	case DrainRequest:
 		// We received a drain request. We terminate immediately if we're not in a
 		// transaction. If we are in a transaction, we'll finish as soon as a Sync
@@ -2441,6 +2489,10 @@ func (ex *connExecutor) execCmd() (retErr error) {
 	case Flush:
 		// Closing the res will flush the connection's buffer.
 		res = ex.clientComm.CreateFlushResult(pos)
+	case DeliverNotifications:
+		// Closing the res will deliver the pending notifications if we're not
+		// in a transaction.
+		res = ex.clientComm.CreateDeliverNotificationsResult(pos)
 	default:
 		panic(errors.AssertionFailedf("unsupported command type: %T", cmd))
 	}
@@ -2466,7 +2518,7 @@ func (ex *connExecutor) execCmd() (retErr error) {
 			// txnState.finishSQLTxn() is being called, as the underlying resources of
 			// pausable portals hasn't been cleared yet.
 			ex.extraTxnState.prepStmtsNamespace.closeAllPausablePortals(ctx, &ex.extraTxnState.prepStmtsNamespaceMemAcc)
-			if err := ex.extraTxnState.sqlCursors.closeAll(false /* errorOnWithHold */); err != nil {
+			if err := ex.extraTxnState.sqlCursors.closeAll(true /* keepHeld */); err != nil {
 				log.Warningf(ctx, "error closing cursors: %v", err)
 			}
 		}
@@ -2685,10 +2737,14 @@ func (ex *connExecutor) updateTxnRewindPosMaybe(
 				// Can't advance.
 			case CopyOut:
 				// Can't advance.
+			case StartReplication:
+				// Can't advance.
 			case DrainRequest:
 				canAdvance = true
 			case Flush:
 				canAdvance = true
+			case DeliverNotifications:
+				canAdvance = true
 			default:
 				panic(errors.AssertionFailedf("unsupported cmd: %T", cmd))
 			}
@@ -3596,6 +3652,7 @@ func (ex *connExecutor) initEvalCtx(ctx context.Context, evalCtx *extendedEvalCo
 			SessionDataStack:               ex.sessionDataStack,
 			ReCache:                        ex.server.reCache,
 			ToCharFormatCache:              ex.server.toCharFormatCache,
+			DomainCheckCache:               ex.server.domainCheckCache,
 			SQLStatsController:             ex.server.sqlStatsController,
 			SchemaTelemetryController:      ex.server.schemaTelemetryController,
 			IndexUsageStatsController:      ex.server.indexUsageStatsController,
@@ -3698,6 +3755,9 @@ func (ex *connExecutor) initPlanner(ctx context.Context, p *planner) {
 	p.preparedStatements = ex.getPrepStmtsAccessor()
 	p.sqlCursors = ex.getCursorAccessor()
 	p.createdSequences = ex.getCreatedSequencesAccessor()
+	p.deferredConstraints = ex.getDeferredConstraintsAccessor()
+	p.notifier = ex.getNotifierAccessor()
+	p.advisoryLocker = ex.getAdvisoryLockerAccessor()
 
 	p.queryCacheSession.Init()
 	p.optPlanningCtx.init(p)
@@ -3870,6 +3930,7 @@ func (ex *connExecutor) txnStateTransitionsApplyWrapper(
 			}
 		}
 		ex.notifyStatsRefresherOfNewTables(ex.Ctx())
+		ex.commitNotifications(ex.Ctx())
 
 		// If there is any descriptor has new version. We want to make sure there is
 		// only one version of the descriptor in all nodes. In schema changer jobs,
@@ -3963,6 +4024,11 @@ func (ex *connExecutor) initStatementResult(
 	// ANALYZE), then the columns will be set later.
 	if ex.planner.instrumentation.outputMode == unmodifiedOutput &&
 		ast.StatementReturnType() == tree.Rows {
+		if _, isCall := ast.(*tree.Call); isCall && len(cols) == 0 {
+			// Only procedures with OUT or INOUT parameters return a result, so
+			// there is no row description for other procedures.
+			return nil
+		}
 		// Note that this call is necessary even if cols is nil.
 		res.SetColumns(ctx, cols)
 	}
@@ -4151,6 +4217,11 @@ func (ex *connExecutor) serialize() serverpb.Session {
 		sessionActiveTime = time.Duration(sessionActiveTime.Nanoseconds() + timeutil.Since(startedAt).Nanoseconds())
 	}
 
+	var advisoryLocks []serverpb.AdvisoryLock
+	if ex.advisoryLocks != nil {
+		advisoryLocks = ex.advisoryLocks.serialize()
+	}
+
 	return serverpb.Session{
 		Username:        sd.SessionUser().Normalized(),
 		ClientAddress:   remoteStr,
@@ -4170,6 +4241,7 @@ func (ex *connExecutor) serialize() serverpb.Session {
 		Status:                     status,
 		TotalActiveTime:            sessionActiveTime,
 		PGBackendPID:               ex.planner.extendedEvalCtx.QueryCancelKey.GetPGBackendPID(),
+		AdvisoryLocks:              advisoryLocks,
 	}
 }
 
@@ -4191,6 +4263,24 @@ func (ex *connExecutor) getCreatedSequencesAccessor() createdSequences {
 	}
 }
 
+func (ex *connExecutor) getDeferredConstraintsAccessor() deferredConstraints {
+	return connExDeferredConstraintsAccessor{
+		ex: ex,
+	}
+}
+
+func (ex *connExecutor) getNotifierAccessor() notifier {
+	return connExNotifierAccessor{
+		ex: ex,
+	}
+}
+
+func (ex *connExecutor) getAdvisoryLockerAccessor() advisoryLocker {
+	return connExAdvisoryLockerAccessor{
+		ex: ex,
+	}
+}
+
 // sessionEventf logs a message to the session event log (if any).
 func (ex *connExecutor) sessionEventf(ctx context.Context, format string, args ...interface{}) {
 	if log.ExpensiveLogEnabled(ctx, 2) {
@@ -1454,7 +1454,14 @@ func (ex *connExecutor) commitSQLTransactionInternal(ctx context.Context) (retEr
 		ex.recordDDLTxnTelemetry(failed)
 	}()
 
-	if err := ex.extraTxnState.sqlCursors.closeAll(true /* errorOnWithHold */); err != nil {
+	// Materialize the WITH HOLD cursors declared in this transaction so that
+	// they outlive it, and close all other cursors.
+	if err := ex.extraTxnState.sqlCursors.holdCursors(
+		ctx, ex.planner.ExtendedEvalContext(), ex.sessionMon,
+	); err != nil {
+		return err
+	}
+	if err := ex.extraTxnState.sqlCursors.closeAll(true /* keepHeld */); err != nil {
 		return err
 	}
 
@@ -1481,6 +1488,13 @@ func (ex *connExecutor) commitSQLTransactionInternal(ctx context.Context) (retEr
 		ex.state.mu.txn.ConfigureStepping(ctx, prevSteppingMode)
 	}
 
+	// Validate the constraints whose checks were deferred until COMMIT. This
+	// needs to see all the writes performed by the transaction, so it happens
+	// after the read sequence has been stepped.
+	if err := ex.planner.validateDeferredConstraints(ctx, nil /* filter */); err != nil {
+		return err
+	}
+
 	if err := ex.createJobs(ctx); err != nil {
 		return err
 	}
@@ -1581,7 +1595,7 @@ func (ex *connExecutor) createJobs(ctx context.Context) error {
 func (ex *connExecutor) rollbackSQLTransaction(
 	ctx context.Context, stmt tree.Statement,
 ) (fsm.Event, fsm.EventPayload) {
-	if err := ex.extraTxnState.sqlCursors.closeAll(false /* errorOnWithHold */); err != nil {
+	if err := ex.extraTxnState.sqlCursors.closeAll(true /* keepHeld */); err != nil {
 		return ex.makeErrEvent(err, stmt)
 	}
 
@@ -101,10 +101,12 @@ func (ex *connExecutor) execSavepointInOpenState(
 	}
 
 	sp := savepoint{
-		name:            s.Name,
-		commitOnRelease: commitOnRelease,
-		kvToken:         token,
-		numDDL:          ex.extraTxnState.numDDL,
+		name:             s.Name,
+		commitOnRelease:  commitOnRelease,
+		kvToken:          token,
+		numDDL:           ex.extraTxnState.numDDL,
+		numNotifications: len(ex.extraTxnState.notifications.notifications),
+		numListenActions: len(ex.extraTxnState.notifications.listenActions),
 	}
 	savepoints.push(sp)
 	ex.sessionDataStack.PushTopClone()
@@ -303,6 +305,8 @@ func (ex *connExecutor) execRollbackToSavepointInAbortedState(
 // popSavepointsToIdx pops savepoints and SessionData elements related to
 // the savepoint up to the given idx.
 func (ex *connExecutor) popSavepointsToIdx(stmt tree.Statement, idx int) error {
+	entry := &ex.extraTxnState.savepoints[idx]
+	ex.extraTxnState.notifications.rollbackTo(entry.numNotifications, entry.numListenActions)
 	if err := ex.reportSessionDataChanges(func() error {
 		numPoppedElems := len(ex.extraTxnState.savepoints) - idx
 		ex.extraTxnState.savepoints.popToIdx(idx)
@@ -357,6 +361,12 @@ type savepoint struct {
 	// more DDL statements were executed since the savepoint's creation.
 	// TODO(knz): support partial DDL cancellation in pending txns.
 	numDDL int
+
+	// The number of notifications and LISTEN/UNLISTEN statements recorded in
+	// the transaction at the time the savepoint was created. Rolling back to
+	// the savepoint discards the ones recorded afterwards.
+	numNotifications int
+	numListenActions int
 }
 
 type savepointStack []savepoint
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser/statements"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/pgrepltree"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -429,6 +430,97 @@ func (c CopyOut) String() string {
 
 var _ Command = CopyOut{}
 
+// StartReplication is the command for streaming changes from a logical
+// replication slot, which uses the Copy-both pgwire subprotocol.
+type StartReplication struct {
+	ParsedStmt statements.Statement[tree.Statement]
+	Stmt       *pgrepltree.StartReplication
+	// Feedback receives the messages sent by the client while changes are
+	// streamed.
+	Feedback *ReplicationFeedback
+	// TimeReceived is the time at which the message was received
+	// from the client. Used to compute the service latency.
+	TimeReceived time.Time
+	// ParseStart/ParseEnd are the timing info for parsing of the query. Used for
+	// stats reporting.
+	ParseStart time.Time
+	ParseEnd   time.Time
+}
+
+// command implements the Command interface.
+func (StartReplication) command() string { return "start replication" }
+
+// isExtendedProtocolCmd implements the Command interface.
+func (e StartReplication) isExtendedProtocolCmd() bool { return false }
+
+func (c StartReplication) String() string {
+	s := "(empty)"
+	if c.Stmt != nil {
+		s = c.Stmt.String()
+	}
+	return fmt.Sprintf("StartReplication: %s", s)
+}
+
+var _ Command = StartReplication{}
+
+// ReplicationFeedback carries the messages sent by the client while changes
+// are streamed from a replication slot. Unlike for COPY FROM, the network
+// routine keeps reading from the connection during the Copy-both
+// subprotocol: it pushes the CopyData and CopyDone messages that it receives,
+// and the connExecutor consumes them.
+type ReplicationFeedback struct {
+	msgs      chan replicationClientMsg
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// replicationClientMsg is a message sent by the client while changes are
+// streamed.
+type replicationClientMsg struct {
+	// copyData is the payload of a CopyData message.
+	copyData []byte
+	// copyDone is set for a CopyDone message.
+	copyDone bool
+}
+
+// NewReplicationFeedback creates a new ReplicationFeedback.
+func NewReplicationFeedback() *ReplicationFeedback {
+	return &ReplicationFeedback{
+		msgs: make(chan replicationClientMsg, 16),
+		done: make(chan struct{}),
+	}
+}
+
+// PushCopyData pushes the payload of a CopyData message. The payload is
+// copied. The message is dropped if the streaming already ended.
+func (f *ReplicationFeedback) PushCopyData(ctx context.Context, data []byte) error {
+	return f.push(ctx, replicationClientMsg{copyData: append([]byte(nil), data...)})
+}
+
+// PushCopyDone pushes a CopyDone message, which ends the streaming. The
+// message is dropped if the streaming already ended.
+func (f *ReplicationFeedback) PushCopyDone(ctx context.Context) error {
+	return f.push(ctx, replicationClientMsg{copyDone: true})
+}
+
+func (f *ReplicationFeedback) push(ctx context.Context, msg replicationClientMsg) error {
+	select {
+	case f.msgs <- msg:
+		return nil
+	case <-f.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close is called once the streaming ended. Messages pushed afterwards are
+// dropped, like Postgres ignores the copy messages received after a failed
+// copy.
+func (f *ReplicationFeedback) close() {
+	f.closeOnce.Do(func() { close(f.done) })
+}
+
 // DrainRequest represents a notice that the server is draining and command
 // processing should stop soon.
 //
@@ -467,6 +559,24 @@ func (s SendError) String() string {
 
 var _ Command = SendError{}
 
+// DeliverNotifications is a command pushed by the client connection when
+// asynchronous notifications arrive for the session (see LISTEN). It is used to
+// deliver them to a client which is idle, and is a no-op if a transaction is in
+// progress (the notifications are then delivered once it finishes).
+type DeliverNotifications struct{}
+
+// command implements the Command interface.
+func (DeliverNotifications) command() string { return "deliver notifications" }
+
+// isExtendedProtocolCmd implements the Command interface.
+func (e DeliverNotifications) isExtendedProtocolCmd() bool { return false }
+
+func (DeliverNotifications) String() string {
+	return "DeliverNotifications"
+}
+
+var _ Command = DeliverNotifications{}
+
 // NewStmtBuf creates a StmtBuf.
 func NewStmtBuf() *StmtBuf {
 	var buf StmtBuf
@@ -741,8 +851,19 @@ type ClientComm interface {
 	CreateCopyInResult(cmd CopyIn, pos CmdPos) CopyInResult
 	// CreateCopyOutResult creates a result for a Copy-out command.
 	CreateCopyOutResult(cmd CopyOut, pos CmdPos) CopyOutResult
+	// CreateReplicationResult creates a result for a StartReplication command.
+	CreateReplicationResult(cmd StartReplication, pos CmdPos) ReplicationResult
 	// CreateDrainResult creates a result for a Drain command.
 	CreateDrainResult(pos CmdPos) DrainResult
+	// CreateDeliverNotificationsResult creates a result for a
+	// DeliverNotifications command.
+	CreateDeliverNotificationsResult(pos CmdPos) DeliverNotificationsResult
+
+	// SendNotification queues an asynchronous notification for delivery to the
+	// client. The notification is delivered once no transaction is in progress.
+	// Unlike the other methods, it can be called from any goroutine, and it
+	// doesn't block.
+	SendNotification(ctx context.Context, senderPID uint32, channel, payload string)
 
 	// LockCommunication ensures that no further results are delivered to the
 	// client. The returned ClientLock can be queried to see what results have
@@ -949,6 +1070,13 @@ type DrainResult interface {
 	ResultBase
 }
 
+// DeliverNotificationsResult represents the result of a DeliverNotifications
+// command. When this result is closed outside of a transaction, the pending
+// notifications are delivered to the client.
+type DeliverNotificationsResult interface {
+	ResultBase
+}
+
 // EmptyQueryResult represents the result of an empty query (a query
 // representing a blank string).
 type EmptyQueryResult interface {
@@ -981,6 +1109,25 @@ type CopyOutResult interface {
 	SendCopyDone(ctx context.Context) error
 }
 
+// ReplicationResult represents the result of a StartReplication command.
+// Closing this result sends a CommandComplete message to the client.
+type ReplicationResult interface {
+	ResultBase
+
+	// SendCopyBoth sends the copy both response to the client, which starts the
+	// streaming of changes.
+	SendCopyBoth(ctx context.Context) error
+
+	// SendCopyData adds a COPY data message to the result.
+	SendCopyData(ctx context.Context, copyData []byte, isHeader bool) error
+
+	// SendCopyDone sends the copy done response to the client.
+	SendCopyDone(ctx context.Context) error
+
+	// Flush sends the buffered messages to the client.
+	Flush(ctx context.Context) error
+}
+
 // ClientLock is an interface returned by ClientComm.lockCommunication(). It
 // represents a lock on the delivery of results to a SQL client. While such a
 // lock is used, no more results are delivered. The lock itself can be used to
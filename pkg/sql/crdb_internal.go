@@ -129,6 +129,7 @@ var crdbInternal = virtualSchema{
 		catconstants.CrdbInternalCatalogDescriptorTableID:           crdbInternalCatalogDescriptorTable,
 		catconstants.CrdbInternalCatalogNamespaceTableID:            crdbInternalCatalogNamespaceTable,
 		catconstants.CrdbInternalCatalogZonesTableID:                crdbInternalCatalogZonesTable,
+		catconstants.CrdbInternalClusterAdvisoryLocksTableID:        crdbInternalClusterAdvisoryLocksTable,
 		catconstants.CrdbInternalClusterContendedIndexesViewID:      crdbInternalClusterContendedIndexesView,
 		catconstants.CrdbInternalClusterContendedKeysViewID:         crdbInternalClusterContendedKeysView,
 		catconstants.CrdbInternalClusterContendedTablesViewID:       crdbInternalClusterContendedTablesView,
@@ -173,6 +174,7 @@ var crdbInternal = virtualSchema{
 		catconstants.CrdbInternalLocalTransactionsTableID:           crdbInternalLocalTxnsTable,
 		catconstants.CrdbInternalLocalSessionsTableID:               crdbInternalLocalSessionsTable,
 		catconstants.CrdbInternalLocalMetricsTableID:                crdbInternalLocalMetricsTable,
+		catconstants.CrdbInternalNodeAdvisoryLocksTableID:           crdbInternalNodeAdvisoryLocksTable,
 		catconstants.CrdbInternalNodeExecutionInsightsTableID:       crdbInternalNodeExecutionInsightsTable,
 		catconstants.CrdbInternalNodeMemoryMonitorsTableID:          crdbInternalNodeMemoryMonitors,
 		catconstants.CrdbInternalNodeStmtStatsTableID:               crdbInternalNodeStmtStatsTable,
@@ -2948,6 +2950,91 @@ func populateSessionsTable(
 	return nil
 }
 
+const advisoryLocksSchemaPattern = `
+CREATE TABLE crdb_internal.%s (
+  node_id        INT NOT NULL,   -- the node on which the session is running
+  session_id     STRING,         -- the ID of the session
+  user_name      STRING,         -- the user of the session
+  pg_backend_pid INT,            -- the numerical ID of the session, as in pg_locks
+  database_id    INT,            -- the ID of the database the lock belongs to
+  classid        OID,            -- the key of the lock, as in pg_locks
+  objid          OID,
+  objsubid       INT,
+  mode           STRING,         -- ExclusiveLock or ShareLock
+  granted        BOOL            -- whether the lock is held, rather than awaited
+)`
+
+// crdbInternalNodeAdvisoryLocksTable exposes the advisory locks held or
+// awaited by the sessions on the current node. The results are dependent on
+// the current user.
+var crdbInternalNodeAdvisoryLocksTable = virtualSchemaTable{
+	comment: "advisory locks of the sessions visible by current user (RAM; local node only)",
+	schema:  fmt.Sprintf(advisoryLocksSchemaPattern, "node_advisory_locks"),
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		req, err := p.makeSessionsRequest(ctx, true /* excludeClosed */)
+		if err != nil {
+			return err
+		}
+		response, err := p.extendedEvalCtx.SQLStatusServer.ListLocalSessions(ctx, &req)
+		if err != nil {
+			return err
+		}
+		return populateAdvisoryLocksTable(ctx, addRow, response)
+	},
+}
+
+// crdbInternalClusterAdvisoryLocksTable exposes the advisory locks held or
+// awaited by the sessions on the entire cluster. The result is dependent on
+// the current user.
+var crdbInternalClusterAdvisoryLocksTable = virtualSchemaTable{
+	comment: "advisory locks of the sessions visible to current user (cluster RPC; expensive!)",
+	schema:  fmt.Sprintf(advisoryLocksSchemaPattern, "cluster_advisory_locks"),
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		req, err := p.makeSessionsRequest(ctx, true /* excludeClosed */)
+		if err != nil {
+			return err
+		}
+		response, err := p.extendedEvalCtx.SQLStatusServer.ListSessions(ctx, &req)
+		if err != nil {
+			return err
+		}
+		return populateAdvisoryLocksTable(ctx, addRow, response)
+	},
+}
+
+func populateAdvisoryLocksTable(
+	ctx context.Context, addRow func(...tree.Datum) error, response *serverpb.ListSessionsResponse,
+) error {
+	for _, session := range response.Sessions {
+		sessionID := getSessionID(session)
+		for _, l := range session.AdvisoryLocks {
+			mode := eval.AdvisoryLockShared
+			if l.Exclusive {
+				mode = eval.AdvisoryLockExclusive
+			}
+			if err := addRow(
+				tree.NewDInt(tree.DInt(session.NodeID)),
+				sessionID,
+				tree.NewDString(session.Username),
+				tree.NewDInt(tree.DInt(session.PGBackendPID)),
+				tree.NewDInt(tree.DInt(l.DatabaseID)),
+				tree.NewDOid(oid.Oid(l.ClassID)),
+				tree.NewDOid(oid.Oid(l.ObjID)),
+				tree.NewDInt(tree.DInt(l.ObjSubID)),
+				tree.NewDString(mode.String()),
+				tree.MakeDBool(tree.DBool(l.Granted)),
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, rpcErr := range response.Errors {
+		log.Warningf(ctx, "%v", rpcErr.Message)
+	}
+	return nil
+}
+
 var crdbInternalClusterContendedTablesView = virtualSchemaView{
 	schema: `
 CREATE VIEW crdb_internal.cluster_contended_tables (
@@ -3571,6 +3658,28 @@ func createRoutinePopulate(
 				// otherwise.
 				continue
 			}
+			if fnDesc.IsAggregate() {
+				aggNode, err := p.aggregateToCreateExpr(ctx, fnDesc)
+				if err != nil {
+					return err
+				}
+				aggNode.Name.ObjectNamePrefix = tree.ObjectNamePrefix{
+					ExplicitSchema: true,
+					SchemaName:     tree.Name(fnIDToScName[fnDesc.GetID()]),
+				}
+				if err := addRow(
+					tree.NewDInt(tree.DInt(fnIDToDBID[fnDesc.GetID()])), // database_id
+					tree.NewDString(fnIDToDBName[fnDesc.GetID()]),       // database_name
+					tree.NewDInt(tree.DInt(fnIDToScID[fnDesc.GetID()])), // schema_id
+					tree.NewDString(fnIDToScName[fnDesc.GetID()]),       // schema_name
+					tree.NewDInt(tree.DInt(fnDesc.GetID())),             // function_id
+					tree.NewDString(fnDesc.GetName()),                   // function_name
+					tree.NewDString(tree.AsString(aggNode)),             // create_statement
+				); err != nil {
+					return err
+				}
+				continue
+			}
 			treeNode, err := fnDesc.ToCreateExpr()
 			treeNode.Name.ObjectNamePrefix = tree.ObjectNamePrefix{
 				ExplicitSchema: true,
@@ -0,0 +1,506 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+)
+
+type createAggregateNode struct {
+	n *tree.CreateAggregate
+}
+
+// CreateAggregate creates a user-defined aggregate function.
+// Privileges: CREATE on the schema, EXECUTE on the support functions.
+func (p *planner) CreateAggregate(ctx context.Context, n *tree.CreateAggregate) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE AGGREGATE",
+	); err != nil {
+		return nil, err
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_1_UserDefinedAggregates) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create aggregate functions",
+			clusterversion.V24_1_UserDefinedAggregates.Version())
+	}
+	return &createAggregateNode{n: n}, nil
+}
+
+func (n *createAggregateNode) ReadingOwnWrites() {}
+
+// aggregateDef holds the resolved components of a CREATE AGGREGATE statement.
+type aggregateDef struct {
+	params     []descpb.FunctionDescriptor_Parameter
+	paramTypes []*types.T
+	stateType  *types.T
+	returnType *types.T
+	transition *funcdesc.Mutable
+	final      *funcdesc.Mutable
+	initCond   *string
+}
+
+func (n *createAggregateNode) startExec(params runParams) error {
+	p := params.p
+	name := n.n.Name.ToUnresolvedObjectName()
+	dbDesc, scDesc, prefix, err := p.ResolveTargetObject(params.ctx, name)
+	if err != nil {
+		return err
+	}
+	if dbDesc.GetID() == keys.SystemDatabaseID {
+		return errors.New("cannot create an aggregate in the system database")
+	}
+	if scDesc.SchemaKind() == catalog.SchemaTemporary {
+		return unimplemented.NewWithIssue(104687, "cannot create UDFs under a temporary schema")
+	}
+	if err := p.canCreateOnSchema(
+		params.ctx, scDesc.GetID(), dbDesc.GetID(), p.User(), skipCheckPublicSchema,
+	); err != nil {
+		return err
+	}
+	n.n.Name.ObjectNamePrefix = prefix
+
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("aggregate"))
+
+	var def aggregateDef
+	var existing *tree.QualifiedOverload
+	p.runWithOptions(resolveFlags{contextDatabaseID: dbDesc.GetID()}, func() {
+		if def, err = n.resolveDefinition(params); err != nil {
+			return
+		}
+		existing, err = p.matchRoutine(params.ctx, &tree.RoutineObj{
+			FuncName: n.n.Name,
+			Params:   n.n.Params,
+		}, false /* required */, tree.UDFRoutine|tree.ProcedureRoutine)
+	})
+	if err != nil {
+		return err
+	}
+
+	var aggDesc *funcdesc.Mutable
+	if existing != nil {
+		if !n.n.Replace {
+			return pgerror.Newf(
+				pgcode.DuplicateFunction,
+				"function %q already exists with same argument types",
+				n.n.Name.Object(),
+			)
+		}
+		aggDesc, err = p.checkPrivilegesForDropFunction(params.ctx, funcdesc.UserDefinedFunctionOIDToID(existing.Oid))
+		if err != nil {
+			return err
+		}
+		if err := n.replaceAggregate(params, aggDesc, &def); err != nil {
+			return err
+		}
+	} else {
+		aggDesc, err = n.createAggregate(params, dbDesc, scDesc, &def)
+		if err != nil {
+			return err
+		}
+	}
+
+	fnName := tree.MakeQualifiedRoutineName(dbDesc.GetName(), scDesc.GetName(), n.n.Name.Object())
+	return p.logEvent(params.ctx, aggDesc.GetID(), &eventpb.CreateFunction{
+		FunctionName: fnName.FQString(),
+		IsReplace:    existing != nil,
+	})
+}
+
+func (*createAggregateNode) Next(params runParams) (bool, error) { return false, nil }
+func (*createAggregateNode) Values() tree.Datums                 { return tree.Datums{} }
+func (*createAggregateNode) Close(ctx context.Context)           {}
+
+// resolveDefinition resolves the parameter types, the state type, and the
+// support functions of the aggregate, and checks that they fit together.
+func (n *createAggregateNode) resolveDefinition(params runParams) (aggregateDef, error) {
+	var def aggregateDef
+	p := params.p
+	def.params = make([]descpb.FunctionDescriptor_Parameter, len(n.n.Params))
+	def.paramTypes = make([]*types.T, len(n.n.Params))
+	for i, param := range n.n.Params {
+		switch param.Class {
+		case tree.RoutineParamIn:
+		case tree.RoutineParamVariadic:
+			return def, unimplemented.New("CREATE AGGREGATE", "variadic aggregates are not supported")
+		default:
+			return def, pgerror.New(pgcode.InvalidFunctionDefinition, "aggregates cannot have output arguments")
+		}
+		pbParam, err := makeFunctionParam(params.ctx, param, p)
+		if err != nil {
+			return def, err
+		}
+		def.params[i] = pbParam
+		def.paramTypes[i] = pbParam.Type
+	}
+
+	var sfunc, finalfunc tree.ResolvableTypeReference
+	for _, opt := range n.n.Options {
+		switch strings.ToLower(string(opt.Name)) {
+		case "sfunc":
+			sfunc = opt.Type
+		case "stype":
+			if opt.Type == nil {
+				return def, pgerror.New(pgcode.Syntax, "aggregate stype must be a type name")
+			}
+			typ, err := tree.ResolveType(params.ctx, opt.Type, p)
+			if err != nil {
+				return def, err
+			}
+			def.stateType = typ
+		case "finalfunc":
+			finalfunc = opt.Type
+		case "combinefunc":
+			// Plans with user-defined aggregates are not distributed, so the
+			// aggregation is always single-stage and there are no partial states
+			// to combine.
+			return def, unimplemented.New("CREATE AGGREGATE",
+				"aggregates with a combine function are not supported")
+		case "initcond":
+			s, err := aggregateInitCondString(opt)
+			if err != nil {
+				return def, err
+			}
+			def.initCond = &s
+		default:
+			return def, pgerror.Newf(pgcode.Syntax, "aggregate attribute %q not recognized", opt.Name)
+		}
+	}
+	if def.stateType == nil {
+		return def, pgerror.New(pgcode.InvalidFunctionDefinition, "aggregate stype must be specified")
+	}
+	if sfunc == nil {
+		return def, pgerror.New(pgcode.InvalidFunctionDefinition, "aggregate sfunc must be specified")
+	}
+	if def.stateType.Identical(types.Trigger) || def.stateType.Family() == types.VoidFamily {
+		return def, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"aggregate transition data type cannot be %s", def.stateType.SQLString())
+	}
+
+	var err error
+	transitionArgs := append([]*types.T{def.stateType}, def.paramTypes...)
+	def.transition, err = p.resolveAggregateSupportFunction(params.ctx, sfunc, transitionArgs, def.stateType, "transition")
+	if err != nil {
+		return def, err
+	}
+	if def.initCond == nil && def.transition.GetNullInputBehavior() != catpb.Function_CALLED_ON_NULL_INPUT &&
+		(len(def.paramTypes) == 0 || !def.paramTypes[0].Equivalent(def.stateType)) {
+		return def, pgerror.New(pgcode.InvalidFunctionDefinition,
+			"must not omit initial value when transition function is strict and transition type is not compatible with input type")
+	}
+	def.returnType = def.stateType
+	if finalfunc != nil {
+		def.final, err = p.resolveAggregateSupportFunction(params.ctx, finalfunc, []*types.T{def.stateType}, nil /* returnType */, "final")
+		if err != nil {
+			return def, err
+		}
+		def.returnType = def.final.GetReturnType().Type
+	}
+	if def.initCond != nil {
+		if _, _, err := tree.ParseAndRequireString(def.stateType, *def.initCond, p.EvalContext()); err != nil {
+			return def, errors.Wrap(err, "invalid aggregate initial value")
+		}
+	}
+	return def, nil
+}
+
+// aggregateInitCondString returns the string form of the INITCOND option.
+// The initial value is stored as a string, as in Postgres, and is converted to
+// the state type when the aggregate is invoked.
+func aggregateInitCondString(opt tree.AggregateOption) (string, error) {
+	switch t := opt.Value.(type) {
+	case *tree.StrVal:
+		return t.RawString(), nil
+	case *tree.NumVal, *tree.UnaryExpr:
+		// Negative numeric constants are parsed as a unary minus.
+		return tree.AsString(t), nil
+	}
+	return "", pgerror.New(pgcode.Syntax, "aggregate initcond must be a string or numeric constant")
+}
+
+// resolveAggregateSupportFunction resolves the user-defined function with the
+// given name and parameter types. If returnType is non-nil, the function must
+// return that type. The kind of the support function is used in error
+// messages.
+func (p *planner) resolveAggregateSupportFunction(
+	ctx context.Context,
+	ref tree.ResolvableTypeReference,
+	paramTypes []*types.T,
+	returnType *types.T,
+	kind string,
+) (*funcdesc.Mutable, error) {
+	name, ok := ref.(*tree.UnresolvedObjectName)
+	if !ok {
+		return nil, pgerror.Newf(pgcode.Syntax, "invalid %s function name %s", kind, ref.SQLString())
+	}
+	routineName := name.ToRoutineName()
+	path := p.CurrentSearchPath()
+	fnDef, err := p.ResolveFunction(ctx, tree.MakeUnresolvedFunctionName(name.ToUnresolvedName()), &path)
+	if err != nil {
+		return nil, err
+	}
+	ol, err := fnDef.MatchOverload(paramTypes, routineName.Schema(), &path, tree.UDFRoutine)
+	if err != nil {
+		return nil, err
+	}
+	if ol.Type == tree.BuiltinRoutine {
+		return nil, unimplemented.Newf("CREATE AGGREGATE",
+			"%s function %s must be a user-defined function", kind, fnDef.Name)
+	}
+	if ol.Class == tree.AggregateClass {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"%s function %s must not be an aggregate", kind, fnDef.Name)
+	}
+	fnDesc, err := p.Descriptors().MutableByID(p.Txn()).Function(
+		ctx, funcdesc.UserDefinedFunctionOIDToID(ol.Oid),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, fnDesc, privilege.EXECUTE); err != nil {
+		return nil, err
+	}
+	if fnDesc.GetReturnType().ReturnSet {
+		return nil, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"%s function %s must not return a set", kind, fnDef.Name)
+	}
+	if returnType != nil && !fnDesc.GetReturnType().Type.Identical(returnType) {
+		return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+			"return type of %s function %s is not %s", kind, fnDef.Name, returnType.SQLString())
+	}
+	return fnDesc, nil
+}
+
+func (n *createAggregateNode) createAggregate(
+	params runParams, dbDesc catalog.DatabaseDescriptor, scDesc catalog.SchemaDescriptor, def *aggregateDef,
+) (*funcdesc.Mutable, error) {
+	p := params.p
+	id, err := params.EvalContext().DescIDGenerator.GenerateUniqueDescID(params.ctx)
+	if err != nil {
+		return nil, err
+	}
+	privileges, err := catprivilege.CreatePrivilegesFromDefaultPrivileges(
+		dbDesc.GetDefaultPrivilegeDescriptor(),
+		scDesc.GetDefaultPrivilegeDescriptor(),
+		dbDesc.GetID(),
+		params.SessionData().User(),
+		privilege.Routines,
+	)
+	if err != nil {
+		return nil, err
+	}
+	desc := funcdesc.NewMutableFunctionDescriptor(
+		id,
+		dbDesc.GetID(),
+		scDesc.GetID(),
+		n.n.Name.Object(),
+		def.params,
+		def.returnType,
+		false, /* returnSet */
+		false, /* isProcedure */
+		privileges,
+	)
+	if err := n.addAggregateReferences(params, &desc, def); err != nil {
+		return nil, err
+	}
+	if err := p.createDescriptor(
+		params.ctx, &desc, tree.AsStringWithFQNames(&n.n.Name, params.Ann()),
+	); err != nil {
+		return nil, err
+	}
+	mutScDesc, err := p.Descriptors().MutableByID(p.Txn()).Schema(params.ctx, scDesc.GetID())
+	if err != nil {
+		return nil, err
+	}
+	mutScDesc.AddFunction(desc.GetName(), desc.ToFunctionSignature())
+	if err := p.writeSchemaDescChange(params.ctx, mutScDesc, "Create Aggregate"); err != nil {
+		return nil, err
+	}
+	return &desc, nil
+}
+
+func (n *createAggregateNode) replaceAggregate(
+	params runParams, desc *funcdesc.Mutable, def *aggregateDef,
+) error {
+	if !desc.IsAggregate() {
+		kind := "function"
+		if desc.IsProcedure() {
+			kind = "procedure"
+		}
+		return errors.WithDetailf(
+			pgerror.Newf(pgcode.WrongObjectType, "cannot change routine kind"),
+			"%q is a %s", desc.Name, kind,
+		)
+	}
+	if !desc.ReturnType.Type.Identical(def.returnType) {
+		return pgerror.Newf(pgcode.InvalidFunctionDefinition, "cannot change return type of existing function")
+	}
+	// Remove the existing references before adding the new ones.
+	if err := params.p.removeAggregateFunctionReferences(params.ctx, desc); err != nil {
+		return err
+	}
+	jobDesc := fmt.Sprintf("updating type back reference %d for aggregate %d", desc.DependsOnTypes, desc.ID)
+	if err := params.p.removeTypeBackReferences(params.ctx, desc.DependsOnTypes, desc.ID, jobDesc); err != nil {
+		return err
+	}
+	desc.Params = def.params
+	if err := n.addAggregateReferences(params, desc, def); err != nil {
+		return err
+	}
+	return params.p.writeFuncSchemaChange(params.ctx, desc)
+}
+
+// addAggregateReferences sets the aggregate definition of the descriptor, and
+// adds back references to the aggregate in its support functions and in the
+// user-defined types it uses.
+func (n *createAggregateNode) addAggregateReferences(
+	params runParams, desc *funcdesc.Mutable, def *aggregateDef,
+) error {
+	p := params.p
+	desc.Aggregate = &descpb.FunctionDescriptor_Aggregate{
+		TransitionFuncID: def.transition.GetID(),
+		StateType:        def.stateType,
+		InitCond:         def.initCond,
+	}
+	if def.final != nil {
+		desc.Aggregate.FinalFuncID = def.final.GetID()
+	}
+	for _, fn := range []*funcdesc.Mutable{def.transition, def.final} {
+		if fn == nil {
+			continue
+		}
+		if fn.GetParentID() != desc.GetParentID() {
+			return pgerror.Newf(pgcode.FeatureNotSupported, "the aggregate cannot refer to other databases")
+		}
+		fn.AddFunctionReference(desc.GetID())
+		if err := p.writeFuncSchemaChange(params.ctx, fn); err != nil {
+			return err
+		}
+	}
+
+	var typeIDs catalog.DescriptorIDSet
+	for _, typ := range append([]*types.T{def.stateType, def.returnType}, def.paramTypes...) {
+		for t := typ; t != nil; t = t.ArrayContents() {
+			if t.UserDefined() {
+				typeIDs.Add(typedesc.GetUserDefinedTypeDescID(t))
+			}
+			if t.Family() != types.ArrayFamily {
+				break
+			}
+		}
+	}
+	for _, id := range typeIDs.Ordered() {
+		if isTable, err := p.descIsTable(params.ctx, id); err != nil {
+			return err
+		} else if isTable {
+			return unimplemented.New("CREATE AGGREGATE", "aggregates using table row types are not supported")
+		}
+		jobDesc := fmt.Sprintf("updating type back reference %d for aggregate %d", id, desc.ID)
+		if err := p.addTypeBackReference(params.ctx, id, desc.ID, jobDesc); err != nil {
+			return err
+		}
+	}
+	desc.DependsOnTypes = typeIDs.Ordered()
+	return nil
+}
+
+// removeAggregateFunctionReferences removes the back references to the
+// aggregate from its support functions.
+func (p *planner) removeAggregateFunctionReferences(
+	ctx context.Context, desc *funcdesc.Mutable,
+) error {
+	for _, id := range desc.Aggregate.FuncIDs() {
+		fn, err := p.Descriptors().MutableByID(p.txn).Function(ctx, id)
+		if err != nil {
+			return err
+		}
+		if fn.Dropped() {
+			continue
+		}
+		fn.RemoveReference(desc.GetID())
+		if err := p.writeFuncSchemaChange(ctx, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// aggregateToCreateExpr returns a CREATE AGGREGATE statement for the given
+// aggregate. The support functions are qualified with their schema names.
+func (p *planner) aggregateToCreateExpr(
+	ctx context.Context, desc catalog.FunctionDescriptor,
+) (*tree.CreateAggregate, error) {
+	agg := desc.FuncDesc().Aggregate
+	ret := &tree.CreateAggregate{
+		Name:   tree.MakeRoutineNameFromPrefix(tree.ObjectNamePrefix{}, tree.Name(desc.GetName())),
+		Params: make(tree.RoutineParams, len(desc.GetParams())),
+	}
+	for i, param := range desc.GetParams() {
+		ret.Params[i] = tree.RoutineParam{
+			Name:  tree.Name(param.Name),
+			Type:  param.Type,
+			Class: tree.RoutineParamIn,
+		}
+	}
+	funcOption := func(name string, id descpb.ID) error {
+		fnDesc, err := p.Descriptors().ByIDWithLeased(p.txn).Get().Function(ctx, id)
+		if err != nil {
+			return err
+		}
+		scDesc, err := p.Descriptors().ByIDWithLeased(p.txn).Get().Schema(ctx, fnDesc.GetParentSchemaID())
+		if err != nil {
+			return err
+		}
+		fnName, err := tree.NewUnresolvedObjectName(
+			2 /* numParts */, [3]string{fnDesc.GetName(), scDesc.GetName()}, 0, /* annotationIdx */
+		)
+		if err != nil {
+			return err
+		}
+		ret.Options = append(ret.Options, tree.AggregateOption{Name: tree.Name(name), Type: fnName})
+		return nil
+	}
+	if err := funcOption("sfunc", agg.TransitionFuncID); err != nil {
+		return nil, err
+	}
+	ret.Options = append(ret.Options, tree.AggregateOption{Name: "stype", Type: agg.StateType})
+	if agg.FinalFuncID != descpb.InvalidID {
+		if err := funcOption("finalfunc", agg.FinalFuncID); err != nil {
+			return nil, err
+		}
+	}
+	if agg.InitCond != nil {
+		ret.Options = append(ret.Options, tree.AggregateOption{Name: "initcond", Value: tree.NewStrVal(*agg.InitCond)})
+	}
+	return ret, nil
+}
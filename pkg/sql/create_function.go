@@ -135,24 +135,7 @@ func (n *createFunctionNode) createNewFunction(
 		return err
 	}
 
-	returnType, err := tree.ResolveType(params.ctx, n.cf.ReturnType.Type, params.p)
-	if err != nil {
-		return err
-	}
-	paramTypes := make([]*types.T, len(udfDesc.Params))
-	for i, param := range udfDesc.Params {
-		paramTypes[i] = param.Type
-	}
-	scDesc.AddFunction(
-		udfDesc.GetName(),
-		descpb.SchemaDescriptor_FunctionSignature{
-			ID:          udfDesc.GetID(),
-			ArgTypes:    paramTypes,
-			ReturnType:  returnType,
-			ReturnSet:   udfDesc.ReturnType.ReturnSet,
-			IsProcedure: udfDesc.IsProcedure(),
-		},
-	)
+	scDesc.AddFunction(udfDesc.GetName(), udfDesc.ToFunctionSignature())
 	if err := params.p.writeSchemaDescChange(params.ctx, scDesc, "Create Function"); err != nil {
 		return err
 	}
@@ -164,6 +147,14 @@ func (n *createFunctionNode) replaceFunction(udfDesc *funcdesc.Mutable, params r
 	// TODO(chengxiong): add validation that the function is not referenced. This
 	// is needed when we start allowing function references from other objects.
 
+	if udfDesc.IsAggregate() {
+		return errors.WithDetailf(
+			pgerror.Newf(pgcode.WrongObjectType, "cannot change routine kind"),
+			"%q is an aggregate function",
+			udfDesc.Name,
+		)
+	}
+
 	if n.cf.IsProcedure && !udfDesc.IsProcedure() {
 		return errors.WithDetailf(
 			pgerror.Newf(pgcode.WrongObjectType, "cannot change routine kind"),
@@ -180,19 +171,11 @@ func (n *createFunctionNode) replaceFunction(udfDesc *funcdesc.Mutable, params r
 		)
 	}
 
-	// Make sure parameter names are not changed.
-	for i := range n.cf.Params {
-		if string(n.cf.Params[i].Name) != udfDesc.Params[i].Name {
-			return pgerror.Newf(
-				pgcode.InvalidFunctionDefinition, "cannot change name of input parameter %q", udfDesc.Params[i].Name,
-			)
-		}
-	}
-
 	// Make sure return type is the same. The signature of user-defined types may
 	// change, as long as the same type is referenced. If this is the case, we
-	// must update the return type.
-	retType, err := tree.ResolveType(params.ctx, n.cf.ReturnType.Type, params.p)
+	// must update the return type. Note that the return type includes the
+	// output parameters of the routine.
+	retType, err := n.cf.ResolveReturnType(params.ctx, params.p)
 	if err != nil {
 		return err
 	}
@@ -205,6 +188,36 @@ func (n *createFunctionNode) replaceFunction(udfDesc *funcdesc.Mutable, params r
 		udfDesc.ReturnType.Type = retType
 	}
 
+	// Make sure the names of the input parameters are not changed. The
+	// parameters are replaced, since the output parameters, and whether an
+	// input parameter is also an output parameter, may change.
+	pbParams := make([]descpb.FunctionDescriptor_Parameter, len(n.cf.Params))
+	var oldInputs []descpb.FunctionDescriptor_Parameter
+	for _, param := range udfDesc.Params {
+		if param.Class != catpb.Function_Param_OUT {
+			oldInputs = append(oldInputs, param)
+		}
+	}
+	for i := range n.cf.Params {
+		pbParams[i], err = makeFunctionParam(params.ctx, n.cf.Params[i], params.p)
+		if err != nil {
+			return err
+		}
+	}
+	var inputIdx int
+	for i := range n.cf.Params {
+		if !n.cf.Params[i].IsInParam() {
+			continue
+		}
+		if inputIdx < len(oldInputs) && string(n.cf.Params[i].Name) != oldInputs[inputIdx].Name {
+			return pgerror.Newf(
+				pgcode.InvalidFunctionDefinition, "cannot change name of input parameter %q", oldInputs[inputIdx].Name,
+			)
+		}
+		inputIdx++
+	}
+	udfDesc.Params = pbParams
+
 	resetFuncOption(udfDesc)
 	if err := validateVolatilityInOptions(n.cf.Options, udfDesc); err != nil {
 		return err
@@ -244,7 +257,6 @@ func (n *createFunctionNode) getMutableFuncDesc(
 	scDesc catalog.SchemaDescriptor, params runParams,
 ) (fnDesc *funcdesc.Mutable, isNew bool, err error) {
 	// Resolve parameter types.
-	paramTypes := make([]*types.T, len(n.cf.Params))
 	pbParams := make([]descpb.FunctionDescriptor_Parameter, len(n.cf.Params))
 	paramNameSeen := make(map[tree.Name]struct{})
 	for i, param := range n.cf.Params {
@@ -262,13 +274,12 @@ func (n *createFunctionNode) getMutableFuncDesc(
 			return nil, false, err
 		}
 		pbParams[i] = pbParam
-		paramTypes[i] = pbParam.Type
 	}
 
 	// Try to look up an existing function.
 	routineObj := tree.RoutineObj{
 		FuncName: n.cf.Name,
-		Params:   n.cf.Params,
+		Params:   n.cf.SignatureParams(),
 	}
 	existing, err := params.p.matchRoutine(params.ctx, &routineObj,
 		false /* required */, tree.UDFRoutine|tree.ProcedureRoutine)
@@ -298,7 +309,7 @@ func (n *createFunctionNode) getMutableFuncDesc(
 		return nil, false, err
 	}
 
-	returnType, err := tree.ResolveType(params.ctx, n.cf.ReturnType.Type, params.p)
+	returnType, err := n.cf.ResolveReturnType(params.ctx, params.p)
 	if err != nil {
 		return nil, false, err
 	}
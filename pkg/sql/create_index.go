@@ -437,6 +437,18 @@ func populateInvertedIndexDescriptor(
 		default:
 			return newUndefinedOpclassError(invCol.OpClass)
 		}
+	case types.RangeFamily:
+		switch invCol.OpClass {
+		case "range_ops", "":
+		default:
+			return newUndefinedOpclassError(invCol.OpClass)
+		}
+	case types.MultirangeFamily:
+		switch invCol.OpClass {
+		case "multirange_ops", "":
+		default:
+			return newUndefinedOpclassError(invCol.OpClass)
+		}
 	default:
 		return tabledesc.NewInvalidInvertedColumnError(column.GetName(), column.GetType().Name())
 	}
@@ -845,6 +857,9 @@ func (n *createIndexNode) startExec(params runParams) error {
 	indexName := index.Name
 
 	mutationID := n.tableDesc.ClusterVersion().NextMutationID
+	if err := params.p.updateTextSearchConfigReferences(params.ctx, n.tableDesc); err != nil {
+		return err
+	}
 	if err := params.p.writeSchemaChange(
 		params.ctx, n.tableDesc, mutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
 	); err != nil {
@@ -0,0 +1,240 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/decodeusername"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+type createPolicyNode struct {
+	n         *tree.CreatePolicy
+	tableDesc *tabledesc.Mutable
+	roles     []username.SQLUsername
+}
+
+// policyTypeValue allows the conversion between a tree.PolicyType and a
+// descpb.PolicyDescriptor_Type.
+var policyTypeValue = [...]descpb.PolicyDescriptor_Type{
+	tree.PolicyPermissive:  descpb.PolicyDescriptor_PERMISSIVE,
+	tree.PolicyRestrictive: descpb.PolicyDescriptor_RESTRICTIVE,
+}
+
+// policyTypeType allows the conversion between a descpb.PolicyDescriptor_Type
+// and a tree.PolicyType.
+var policyTypeType = [...]tree.PolicyType{
+	descpb.PolicyDescriptor_PERMISSIVE:  tree.PolicyPermissive,
+	descpb.PolicyDescriptor_RESTRICTIVE: tree.PolicyRestrictive,
+}
+
+// policyCommandValue allows the conversion between a tree.PolicyCommand and a
+// descpb.PolicyDescriptor_Command.
+var policyCommandValue = [...]descpb.PolicyDescriptor_Command{
+	tree.PolicyCommandAll:    descpb.PolicyDescriptor_ALL,
+	tree.PolicyCommandSelect: descpb.PolicyDescriptor_SELECT,
+	tree.PolicyCommandInsert: descpb.PolicyDescriptor_INSERT,
+	tree.PolicyCommandUpdate: descpb.PolicyDescriptor_UPDATE,
+	tree.PolicyCommandDelete: descpb.PolicyDescriptor_DELETE,
+}
+
+// policyCommandType allows the conversion between a
+// descpb.PolicyDescriptor_Command and a tree.PolicyCommand.
+var policyCommandType = [...]tree.PolicyCommand{
+	descpb.PolicyDescriptor_ALL:    tree.PolicyCommandAll,
+	descpb.PolicyDescriptor_SELECT: tree.PolicyCommandSelect,
+	descpb.PolicyDescriptor_INSERT: tree.PolicyCommandInsert,
+	descpb.PolicyDescriptor_UPDATE: tree.PolicyCommandUpdate,
+	descpb.PolicyDescriptor_DELETE: tree.PolicyCommandDelete,
+}
+
+// checkCanAlterRowLevelSecurity returns an error if the cluster version does
+// not yet allow row-level security to be stored in table descriptors, or if
+// the current user does not own the table. Policies and the row-level
+// security mode restrict which rows other users can see, so only the owner
+// may change them, as in postgres; the CREATE privilege is not enough.
+func (p *planner) checkCanAlterRowLevelSecurity(
+	ctx context.Context, tableDesc catalog.TableDescriptor,
+) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_1_RowLevelSecurity) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use row-level security",
+			clusterversion.V24_1_RowLevelSecurity.Version())
+	}
+	hasOwnership, err := p.HasOwnership(ctx, tableDesc)
+	if err != nil {
+		return err
+	}
+	if !hasOwnership {
+		return pgerror.Newf(pgcode.InsufficientPrivilege,
+			"must be owner of table %s", tree.Name(tableDesc.GetName()))
+	}
+	return nil
+}
+
+// CreatePolicy creates a row-level security policy on a table.
+// Privileges: ownership of the table.
+//
+//	notes: postgres requires ownership of the table.
+func (p *planner) CreatePolicy(ctx context.Context, n *tree.CreatePolicy) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE POLICY",
+	); err != nil {
+		return nil, err
+	}
+
+	_, tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &n.TableName, true /* required */, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc.IsVirtualTable() || tableDesc.IsSequence() {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"%q is not a table", tableDesc.GetName())
+	}
+	if err := p.checkCanAlterRowLevelSecurity(ctx, tableDesc); err != nil {
+		return nil, err
+	}
+	// Disallow schema changes if this table's schema is locked.
+	if err := checkTableSchemaUnlocked(tableDesc); err != nil {
+		return nil, err
+	}
+
+	switch n.Cmd {
+	case tree.PolicyCommandInsert:
+		if n.Using != nil {
+			return nil, pgerror.New(pgcode.Syntax,
+				"only WITH CHECK expression allowed for INSERT")
+		}
+	case tree.PolicyCommandSelect, tree.PolicyCommandDelete:
+		if n.WithCheck != nil {
+			return nil, pgerror.New(pgcode.Syntax,
+				"WITH CHECK cannot be applied to SELECT or DELETE")
+		}
+	}
+
+	roles, err := decodeusername.FromRoleSpecList(
+		p.SessionData(), username.PurposeValidation, n.Roles,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.validateRoles(ctx, roles, true /* isPublicValid */); err != nil {
+		return nil, err
+	}
+
+	return &createPolicyNode{n: n, tableDesc: tableDesc, roles: roles}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE POLICY performs multiple KV operations on descriptors
+// and expects to see its own writes.
+func (n *createPolicyNode) ReadingOwnWrites() {}
+
+func (n *createPolicyNode) startExec(params runParams) error {
+	p := params.p
+	ctx := params.ctx
+	tableDesc := n.tableDesc
+
+	name := string(n.n.Name)
+	if name == "" {
+		return pgerror.New(pgcode.Syntax, "empty policy name")
+	}
+	if catalog.FindPolicyByName(tableDesc, name) != nil {
+		return pgerror.Newf(pgcode.DuplicateObject,
+			"policy %q for table %q already exists", name, tableDesc.GetName())
+	}
+
+	policy := descpb.PolicyDescriptor{
+		Name:    name,
+		Type:    policyTypeValue[n.n.Type],
+		Command: policyCommandValue[n.n.Cmd],
+	}
+
+	// A policy without any roles applies to everyone. Naming PUBLIC alongside
+	// other roles is equivalent to naming PUBLIC alone.
+	seen := make(map[username.SQLUsername]struct{}, len(n.roles))
+	for _, role := range n.roles {
+		if role.IsPublicRole() {
+			policy.RoleNames = []string{username.PublicRole}
+			break
+		}
+		if _, ok := seen[role]; ok {
+			continue
+		}
+		seen[role] = struct{}{}
+		policy.RoleNames = append(policy.RoleNames, role.Normalized())
+	}
+	if len(policy.RoleNames) == 0 {
+		policy.RoleNames = []string{username.PublicRole}
+	}
+
+	validateExpr := func(expr tree.Expr, exprCtx tree.SchemaExprContext) (string, error) {
+		serialized, _, _, err := schemaexpr.DequalifyAndValidateExpr(
+			ctx,
+			tableDesc,
+			expr,
+			types.Bool,
+			exprCtx,
+			&p.semaCtx,
+			volatility.Volatile,
+			&n.n.TableName,
+			params.ExecCfg().Settings.Version.ActiveVersion(ctx),
+		)
+		return serialized, err
+	}
+	if n.n.Using != nil {
+		expr, err := validateExpr(n.n.Using, tree.PolicyUsingExpr)
+		if err != nil {
+			return err
+		}
+		policy.UsingExpr = expr
+	}
+	if n.n.WithCheck != nil {
+		expr, err := validateExpr(n.n.WithCheck, tree.PolicyWithCheckExpr)
+		if err != nil {
+			return err
+		}
+		policy.WithCheckExpr = expr
+	}
+
+	if tableDesc.NextPolicyID == 0 {
+		tableDesc.NextPolicyID = 1
+	}
+	policy.ID = tableDesc.NextPolicyID
+	tableDesc.NextPolicyID++
+	tableDesc.Policies = append(tableDesc.Policies, policy)
+
+	if err := validateDescriptor(ctx, p, tableDesc); err != nil {
+		return err
+	}
+
+	return p.writeSchemaChange(
+		ctx, tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()))
+}
+
+func (n *createPolicyNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createPolicyNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createPolicyNode) Close(context.Context)        {}
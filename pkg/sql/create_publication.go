@@ -0,0 +1,218 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+type createPublicationNode struct {
+	n      *tree.CreatePublication
+	dbDesc *dbdesc.Mutable
+	pub    descpb.PublicationDescriptor
+}
+
+// CreatePublication creates a publication in the current database.
+// Privileges: CREATE on the database, ownership of the published tables, and
+// the admin role for FOR ALL TABLES.
+//
+//	notes: postgres requires superuser for FOR ALL TABLES too.
+func (p *planner) CreatePublication(
+	ctx context.Context, n *tree.CreatePublication,
+) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE PUBLICATION",
+	); err != nil {
+		return nil, err
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_1_Publications) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create publications",
+			clusterversion.V24_1_Publications.Version())
+	}
+	dbDesc, err := p.mutablePublicationDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name := string(n.Name)
+	if dbDesc.GetPublication(name) != nil {
+		return nil, pgerror.Newf(pgcode.DuplicateObject, "publication %q already exists", name)
+	}
+	pub := descpb.PublicationDescriptor{Name: name, AllTables: n.AllTables}
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if n.AllTables && !hasAdmin {
+		return nil, pgerror.New(pgcode.InsufficientPrivilege,
+			"only users with the admin role are allowed to create a publication FOR ALL TABLES")
+	}
+	seen := make(map[descpb.ID]struct{}, len(n.Tables))
+	for i := range n.Tables {
+		_, tableDesc, err := p.ResolveMutableTableDescriptor(
+			ctx, &n.Tables[i], true /* required */, tree.ResolveRequireTableDesc,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if !tableDesc.IsPhysicalTable() || tableDesc.IsSequence() || tableDesc.IsTemporary() {
+			return nil, pgerror.Newf(pgcode.WrongObjectType,
+				"cannot add relation %q to publication", tableDesc.GetName())
+		}
+		if tableDesc.GetParentID() != dbDesc.GetID() {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot add relation %q of another database to publication", tableDesc.GetName())
+		}
+		if !hasAdmin {
+			if hasOwnership, err := p.HasOwnership(ctx, tableDesc); err != nil {
+				return nil, err
+			} else if !hasOwnership {
+				return nil, pgerror.Newf(pgcode.InsufficientPrivilege,
+					"must be owner of table %s", tableDesc.GetName())
+			}
+		}
+		if err := checkReplicatedTable(tableDesc); err != nil {
+			return nil, err
+		}
+		if _, ok := seen[tableDesc.GetID()]; ok {
+			return nil, pgerror.Newf(pgcode.DuplicateObject,
+				"relation %q is already member of publication %q", tableDesc.GetName(), name)
+		}
+		seen[tableDesc.GetID()] = struct{}{}
+		pub.TableIDs = append(pub.TableIDs, tableDesc.GetID())
+	}
+	return &createPublicationNode{n: n, dbDesc: dbDesc, pub: pub}, nil
+}
+
+// mutablePublicationDatabase returns the current database, in which
+// publications are created and dropped, after checking that the user has the
+// CREATE privilege on it.
+func (p *planner) mutablePublicationDatabase(ctx context.Context) (*dbdesc.Mutable, error) {
+	if p.CurrentDatabase() == "" {
+		return nil, pgerror.New(pgcode.UndefinedDatabase,
+			"cannot use publications without being connected to a database")
+	}
+	dbDesc, err := p.Descriptors().MutableByName(p.txn).Database(ctx, p.CurrentDatabase())
+	if err != nil {
+		return nil, err
+	}
+	if dbDesc.GetID() == keys.SystemDatabaseID {
+		return nil, pgerror.New(pgcode.InvalidObjectDefinition,
+			"cannot use publications in the system database")
+	}
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	return dbDesc, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE PUBLICATION performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *createPublicationNode) ReadingOwnWrites() {}
+
+func (n *createPublicationNode) startExec(params runParams) error {
+	n.dbDesc.AddPublication(n.pub)
+	if err := validateDescriptor(params.ctx, params.p, n.dbDesc); err != nil {
+		return err
+	}
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (n *createPublicationNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createPublicationNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createPublicationNode) Close(context.Context)        {}
+
+type dropPublicationNode struct {
+	n      *tree.DropPublication
+	dbDesc *dbdesc.Mutable
+}
+
+// DropPublication drops publications of the current database.
+// Privileges: CREATE on the database.
+//
+//	notes: postgres requires ownership of the publication.
+func (p *planner) DropPublication(ctx context.Context, n *tree.DropPublication) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP PUBLICATION",
+	); err != nil {
+		return nil, err
+	}
+	dbDesc, err := p.mutablePublicationDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range n.Names {
+		if dbDesc.GetPublication(string(name)) == nil && !n.IfExists {
+			return nil, errPublicationDoesNotExist(string(name))
+		}
+	}
+	return &dropPublicationNode{n: n, dbDesc: dbDesc}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because DROP PUBLICATION performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *dropPublicationNode) ReadingOwnWrites() {}
+
+func (n *dropPublicationNode) startExec(params runParams) error {
+	var dropped bool
+	for _, name := range n.n.Names {
+		if n.dbDesc.GetPublication(string(name)) != nil {
+			n.dbDesc.RemovePublication(string(name))
+			dropped = true
+		}
+	}
+	if !dropped {
+		return nil
+	}
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (n *dropPublicationNode) Next(runParams) (bool, error) { return false, nil }
+func (n *dropPublicationNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *dropPublicationNode) Close(context.Context)        {}
+
+func errPublicationDoesNotExist(name string) error {
+	return pgerror.Newf(pgcode.UndefinedObject, "publication %q does not exist", name)
+}
+
+// publicationIncludesTable returns whether the changes of a table of the
+// publication's database are published.
+func publicationIncludesTable(pub *descpb.PublicationDescriptor, tableID descpb.ID) bool {
+	if pub.AllTables {
+		return true
+	}
+	for _, id := range pub.TableIDs {
+		if id == tableID {
+			return true
+		}
+	}
+	return false
+}
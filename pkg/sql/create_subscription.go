@@ -0,0 +1,376 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/paramparse"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsn"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/pgoutput"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type createSubscriptionNode struct {
+	n       *tree.CreateSubscription
+	db      catalog.DatabaseDescriptor
+	details jobspb.SubscriptionDetails
+}
+
+// CreateSubscription creates a subscription, which replicates the changes to
+// the tables of publications of another cluster into the tables with the same
+// names in the current database. The subscription is run by a job.
+// Privileges: admin.
+//
+//	notes: postgres requires the pg_create_subscription role.
+func (p *planner) CreateSubscription(
+	ctx context.Context, n *tree.CreateSubscription,
+) (planNode, error) {
+	if err := p.requireAdminForSubscriptions(ctx, "CREATE SUBSCRIPTION"); err != nil {
+		return nil, err
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_1_Publications) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create subscriptions",
+			clusterversion.V24_1_Publications.Version())
+	}
+	if p.CurrentDatabase() == "" {
+		return nil, pgerror.New(pgcode.UndefinedDatabase,
+			"cannot create a subscription without being connected to a database")
+	}
+	db, err := p.Descriptors().ByNameWithLeased(p.txn).Get().Database(ctx, p.CurrentDatabase())
+	if err != nil {
+		return nil, err
+	}
+	uri, err := p.ExprEvaluator("CREATE SUBSCRIPTION").String(ctx, n.ConnectionURI)
+	if err != nil {
+		return nil, err
+	}
+	details := jobspb.SubscriptionDetails{
+		Name:          string(n.Name),
+		DatabaseID:    db.GetID(),
+		ConnectionURI: uri,
+		SlotName:      string(n.Name),
+		CreatedSlot:   true,
+		CopyData:      true,
+	}
+	for _, name := range n.Publications {
+		details.Publications = append(details.Publications, string(name))
+	}
+	if err := p.evalSubscriptionOptions(ctx, n.Options, &details); err != nil {
+		return nil, err
+	}
+	if err := checkReplicationSlotName(details.SlotName); err != nil {
+		return nil, err
+	}
+	if details.CreatedSlot && !p.extendedEvalCtx.TxnImplicit {
+		return nil, pgerror.New(pgcode.ActiveSQLTransaction,
+			"CREATE SUBSCRIPTION ... WITH (create_slot = true) cannot run inside a transaction block")
+	}
+
+	subs, err := listSubscriptions(ctx, p.InternalSQLTxn())
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range subs {
+		if sub.details.Name == details.Name {
+			return nil, pgerror.Newf(pgcode.DuplicateObject,
+				"subscription %q already exists", details.Name)
+		}
+	}
+	return &createSubscriptionNode{n: n, db: db, details: details}, nil
+}
+
+// evalSubscriptionOptions evaluates the options of CREATE SUBSCRIPTION.
+func (p *planner) evalSubscriptionOptions(
+	ctx context.Context, opts tree.StorageParams, details *jobspb.SubscriptionDetails,
+) error {
+	eval := p.ExprEvaluator("CREATE SUBSCRIPTION")
+	seen := make(map[string]struct{}, len(opts))
+	for _, opt := range opts {
+		key := string(opt.Key)
+		if _, ok := seen[key]; ok {
+			return pgerror.Newf(pgcode.Syntax, "conflicting or redundant options")
+		}
+		seen[key] = struct{}{}
+		value := paramparse.UnresolvedNameToStrVal(opt.Value)
+		var err error
+		switch key {
+		case "create_slot":
+			details.CreatedSlot, err = eval.Bool(ctx, value)
+		case "copy_data":
+			details.CopyData, err = eval.Bool(ctx, value)
+		case "slot_name":
+			details.SlotName, err = eval.String(ctx, value)
+		default:
+			return pgerror.Newf(pgcode.Syntax, "unrecognized subscription parameter: %q", key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if details.CopyData && !details.CreatedSlot {
+		// The existing rows are copied at the consistent point of the slot, which
+		// is only known when the slot is created.
+		return pgerror.New(pgcode.InvalidParameterValue,
+			"copy_data = true requires create_slot = true")
+	}
+	return nil
+}
+
+func (n *createSubscriptionNode) startExec(params runParams) error {
+	ctx, p := params.ctx, params.p
+	conn, err := connectToPublisher(ctx, n.details.ConnectionURI, false /* replication */)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close(ctx) }()
+
+	rows, err := queryPublisher(ctx, conn, fmt.Sprintf(
+		`SELECT pubname FROM pg_catalog.pg_publication WHERE pubname IN (%s)`,
+		publisherStringList(n.details.Publications),
+	))
+	if err != nil {
+		return err
+	}
+	found := make(map[string]struct{}, len(rows))
+	for _, row := range rows {
+		found[string(row[0])] = struct{}{}
+	}
+	for _, name := range n.details.Publications {
+		if _, ok := found[name]; !ok {
+			return pgerror.Newf(pgcode.UndefinedObject,
+				"publication %q does not exist on the publisher", name)
+		}
+	}
+
+	tables, err := publishedTables(ctx, conn, n.details.Publications)
+	if err != nil {
+		return err
+	}
+	for _, t := range tables {
+		sc, err := p.Descriptors().ByNameWithLeased(p.txn).Get().Schema(ctx, n.db, t.schema)
+		if err != nil {
+			return err
+		}
+		tbl, err := p.Descriptors().ByNameWithLeased(p.txn).Get().Table(ctx, n.db, sc, t.name)
+		if err != nil {
+			return err
+		}
+		if err := checkSubscribedTable(tbl); err != nil {
+			return err
+		}
+	}
+
+	if n.details.CreatedSlot {
+		replConn, err := connectToPublisher(ctx, n.details.ConnectionURI, true /* replication */)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = replConn.Close(ctx) }()
+		rows, err := queryPublisher(ctx, replConn, fmt.Sprintf(
+			"CREATE_REPLICATION_SLOT %s LOGICAL %s", n.details.SlotName, pgoutput.PluginName,
+		))
+		if err != nil {
+			return err
+		}
+		if len(rows) != 1 || len(rows[0]) < 2 {
+			return errors.Newf("unexpected result of CREATE_REPLICATION_SLOT: %v", rows)
+		}
+		consistentPoint, err := lsn.ParseLSN(string(rows[0][1]))
+		if err != nil {
+			return errors.Wrap(err, "parsing the consistent point of the replication slot")
+		}
+		n.details.SnapshotLSN = uint64(consistentPoint)
+	}
+
+	description, err := redactedCreateSubscription(n.n, n.details.ConnectionURI, params.Ann())
+	if err != nil {
+		return err
+	}
+	reg := p.ExecCfg().JobRegistry
+	record := jobs.Record{
+		JobID:       reg.MakeJobID(),
+		Description: description,
+		Username:    p.User(),
+		Details:     n.details,
+		Progress:    jobspb.SubscriptionProgress{},
+	}
+	_, err = reg.CreateAdoptableJobWithTxn(ctx, record, record.JobID, p.InternalSQLTxn())
+	return err
+}
+
+func (n *createSubscriptionNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createSubscriptionNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createSubscriptionNode) Close(context.Context)        {}
+
+// redactedCreateSubscription formats a CREATE SUBSCRIPTION statement with the
+// password of the connection string redacted, for use as the description of
+// the subscription's job.
+func redactedCreateSubscription(
+	n *tree.CreateSubscription, uri string, ann *tree.Annotations,
+) (string, error) {
+	redacted, err := redactConnectionURI(uri)
+	if err != nil {
+		return "", err
+	}
+	stmt := *n
+	stmt.ConnectionURI = tree.NewDString(redacted)
+	return tree.AsStringWithFQNames(&stmt, ann), nil
+}
+
+// redactConnectionURI returns the connection string of a publisher with its
+// password redacted.
+func redactConnectionURI(uri string) (string, error) {
+	redacted, err := cloud.SanitizeExternalStorageURI(uri, []string{"password"})
+	if err != nil {
+		return "", pgerror.Wrap(err, pgcode.InvalidParameterValue, "invalid connection string")
+	}
+	return redacted, nil
+}
+
+type dropSubscriptionNode struct {
+	n     *tree.DropSubscription
+	jobID jobspb.JobID
+}
+
+// DropSubscription drops a subscription by canceling its job. The replication
+// slot created for the subscription is dropped when the job is reverted.
+// Privileges: admin.
+//
+//	notes: postgres requires ownership of the subscription.
+func (p *planner) DropSubscription(ctx context.Context, n *tree.DropSubscription) (planNode, error) {
+	if err := p.requireAdminForSubscriptions(ctx, "DROP SUBSCRIPTION"); err != nil {
+		return nil, err
+	}
+	subs, err := listSubscriptions(ctx, p.InternalSQLTxn())
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range subs {
+		if sub.details.Name == string(n.Name) {
+			return &dropSubscriptionNode{n: n, jobID: sub.id}, nil
+		}
+	}
+	if !n.IfExists {
+		return nil, pgerror.Newf(pgcode.UndefinedObject, "subscription %q does not exist", n.Name)
+	}
+	return newZeroNode(nil /* columns */), nil
+}
+
+func (n *dropSubscriptionNode) startExec(params runParams) error {
+	job, err := params.p.ExecCfg().JobRegistry.LoadJobWithTxn(
+		params.ctx, n.jobID, params.p.InternalSQLTxn(),
+	)
+	if err != nil {
+		return err
+	}
+	return job.WithTxn(params.p.InternalSQLTxn()).CancelRequested(params.ctx)
+}
+
+func (n *dropSubscriptionNode) Next(runParams) (bool, error) { return false, nil }
+func (n *dropSubscriptionNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *dropSubscriptionNode) Close(context.Context)        {}
+
+// requireAdminForSubscriptions checks that the user has the admin role, which
+// is required to manage subscriptions.
+func (p *planner) requireAdminForSubscriptions(ctx context.Context, op string) error {
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	if !hasAdmin {
+		return pgerror.Newf(pgcode.InsufficientPrivilege,
+			"only users with the admin role are allowed to %s", op)
+	}
+	return nil
+}
+
+// connectToPublisher connects to the publishing cluster of a subscription. If
+// replication is set, the connection is a logical replication connection, on
+// which the replication commands can be executed.
+func connectToPublisher(ctx context.Context, uri string, replication bool) (*pgconn.PgConn, error) {
+	cfg, err := pgconn.ParseConfig(uri)
+	if err != nil {
+		return nil, pgerror.Wrap(err, pgcode.InvalidParameterValue, "invalid connection string")
+	}
+	if replication {
+		cfg.RuntimeParams["replication"] = "database"
+	}
+	conn, err := pgconn.ConnectConfig(ctx, cfg)
+	if err != nil {
+		return nil, pgerror.Wrap(err, pgcode.ConnectionFailure, "could not connect to the publisher")
+	}
+	return conn, nil
+}
+
+// queryPublisher executes a query on the publishing cluster and returns the
+// rows of its result, in the text format.
+func queryPublisher(ctx context.Context, conn *pgconn.PgConn, query string) ([][][]byte, error) {
+	results, err := conn.Exec(ctx, query).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+	return results[len(results)-1].Rows, nil
+}
+
+// publishedTable is a table of a publication of the publishing cluster.
+type publishedTable struct {
+	schema, name string
+}
+
+// publishedTables returns the tables of the given publications of the
+// publishing cluster.
+func publishedTables(
+	ctx context.Context, conn *pgconn.PgConn, publications []string,
+) ([]publishedTable, error) {
+	rows, err := queryPublisher(ctx, conn, fmt.Sprintf(
+		`SELECT DISTINCT schemaname, tablename FROM pg_catalog.pg_publication_tables
+WHERE pubname IN (%s) ORDER BY 1, 2`,
+		publisherStringList(publications),
+	))
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]publishedTable, len(rows))
+	for i, row := range rows {
+		tables[i] = publishedTable{schema: string(row[0]), name: string(row[1])}
+	}
+	return tables, nil
+}
+
+// publisherStringList formats the given strings as a list of SQL string
+// literals.
+func publisherStringList(strs []string) string {
+	var b bytes.Buffer
+	for i, s := range strs {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		lexbase.EncodeSQLString(&b, s)
+	}
+	return b.String()
+}
@@ -388,8 +388,9 @@ func (n *createTableNode) startExec(params runParams) error {
 		}
 
 		// If we have a single statement txn we want to run CTAS async, and
-		// consequently ensure it gets queued as a SchemaChange.
-		if params.extendedEvalCtx.TxnIsSingleStmt {
+		// consequently ensure it gets queued as a SchemaChange. With WITH NO
+		// DATA, there is nothing to backfill and the table is public right away.
+		if params.extendedEvalCtx.TxnIsSingleStmt && !n.n.WithNoData {
 			desc.State = descpb.DescriptorState_ADD
 		}
 	} else {
@@ -437,6 +438,12 @@ func (n *createTableNode) startExec(params runParams) error {
 		}
 	}
 
+	// Replace the names of the text search configurations with references and
+	// update the back references in the database.
+	if err := params.p.updateTextSearchConfigReferences(params.ctx, desc); err != nil {
+		return err
+	}
+
 	// Descriptor written to store here.
 	if err := params.p.createDescriptor(
 		params.ctx,
@@ -520,7 +527,7 @@ func (n *createTableNode) startExec(params runParams) error {
 
 	// If we are in a multi-statement txn or the source has placeholders, we
 	// execute the CTAS query synchronously.
-	if n.n.As() && !params.extendedEvalCtx.TxnIsSingleStmt {
+	if n.n.As() && !n.n.WithNoData && !params.extendedEvalCtx.TxnIsSingleStmt {
 		err = func() error {
 			// The data fill portion of CREATE AS must operate on a read snapshot,
 			// so that it doesn't end up observing its own writes.
@@ -672,6 +679,7 @@ func addUniqueWithoutIndexColumnTableDef(
 		string(d.Unique.ConstraintName),
 		[]string{string(d.Name)},
 		"", /* predicate */
+		tree.ConstraintNotDeferrable,
 		ts,
 		validationBehavior,
 	); err != nil {
@@ -682,7 +690,9 @@ func addUniqueWithoutIndexColumnTableDef(
 
 // addUniqueWithoutIndexTableDef runs various checks on the given
 // UniqueConstraintTableDef before adding it as a UNIQUE WITHOUT INDEX
-// constraint to the given table descriptor.
+// constraint to the given table descriptor. If the definition is not WITHOUT
+// INDEX, it is a deferrable unique constraint, and the caller is responsible
+// for creating the non-unique index that backs it.
 func addUniqueWithoutIndexTableDef(
 	ctx context.Context,
 	evalCtx *eval.Context,
@@ -694,20 +704,27 @@ func addUniqueWithoutIndexTableDef(
 	validationBehavior tree.ValidationBehavior,
 	semaCtx *tree.SemaContext,
 ) error {
-	if !sessionData.EnableUniqueWithoutIndexConstraints {
-		return pgerror.New(pgcode.FeatureNotSupported,
-			"unique constraints without an index are not yet supported",
-		)
-	}
-	if len(d.Storing) > 0 {
-		return pgerror.New(pgcode.FeatureNotSupported,
-			"unique constraints without an index cannot store columns",
-		)
+	if d.Deferrable != tree.ConstraintNotDeferrable {
+		if err := checkDeferrableConstraintsActive(ctx, evalCtx.Settings.Version); err != nil {
+			return err
+		}
 	}
-	if d.PartitionByIndex.ContainsPartitions() {
-		return pgerror.New(pgcode.FeatureNotSupported,
-			"partitioned unique constraints without an index are not supported",
-		)
+	if d.WithoutIndex {
+		if !sessionData.EnableUniqueWithoutIndexConstraints {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"unique constraints without an index are not yet supported",
+			)
+		}
+		if len(d.Storing) > 0 {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"unique constraints without an index cannot store columns",
+			)
+		}
+		if d.PartitionByIndex.ContainsPartitions() {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"partitioned unique constraints without an index are not supported",
+			)
+		}
 	}
 	if d.Invisibility.Value != 0.0 {
 		// Theoretically, this should never happen because this is not supported by
@@ -735,13 +752,32 @@ func addUniqueWithoutIndexTableDef(
 		colNames[i] = string(d.Columns[i].Column)
 	}
 	if err := ResolveUniqueWithoutIndexConstraint(
-		ctx, desc, string(d.Name), colNames, predicate, ts, validationBehavior,
+		ctx, desc, string(d.Name), colNames, predicate, d.Deferrable, ts, validationBehavior,
 	); err != nil {
 		return err
 	}
 	return nil
 }
 
+// validateDeferrableUniqueConstraint checks that the given unique constraint,
+// which is marked DEFERRABLE, can be backed by a non-unique index. See
+// addUniqueWithoutIndexTableDef.
+func validateDeferrableUniqueConstraint(d *tree.UniqueConstraintTableDef) error {
+	if d.Sharded != nil {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"deferrable unique constraints cannot be hash sharded",
+		)
+	}
+	for _, elem := range d.Columns {
+		if elem.Expr != nil {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"deferrable unique constraints cannot be defined on expressions",
+			)
+		}
+	}
+	return nil
+}
+
 // ResolveUniqueWithoutIndexConstraint looks up the columns mentioned in a
 // UNIQUE WITHOUT INDEX constraint and adds metadata representing that
 // constraint to the descriptor.
@@ -755,6 +791,7 @@ func ResolveUniqueWithoutIndexConstraint(
 	constraintName string,
 	colNames []string,
 	predicate string,
+	deferrability tree.ConstraintDeferrability,
 	ts TableState,
 	validationBehavior tree.ValidationBehavior,
 ) error {
@@ -803,23 +840,173 @@ func ResolveUniqueWithoutIndexConstraint(
 	}
 
 	uc := descpb.UniqueWithoutIndexConstraint{
+		Name:          constraintName,
+		TableID:       tbl.ID,
+		ColumnIDs:     columnIDs,
+		Predicate:     predicate,
+		Validity:      validity,
+		ConstraintID:  tbl.NextConstraintID,
+		Deferrability: tree.ConstraintDeferrabilityValue[deferrability],
+	}
+	tbl.NextConstraintID++
+	if ts == NewTable {
+		tbl.UniqueWithoutIndexConstraints = append(tbl.UniqueWithoutIndexConstraints, uc)
+	} else {
+		tbl.AddUniqueWithoutIndexMutation(&uc, descpb.DescriptorMutation_ADD)
+	}
+
+	return nil
+}
+
+// exclusionIndexMethods are the access methods accepted in the USING clause of
+// an exclusion constraint. The constraint is not backed by an index, so the
+// method only matters for compatibility with Postgres.
+var exclusionIndexMethods = map[string]struct{}{
+	"btree": {},
+	"gist":  {},
+}
+
+// addExclusionConstraintTableDef runs various checks on the given
+// ExclusionConstraintTableDef before adding it as an exclusion constraint to
+// the given table descriptor.
+func addExclusionConstraintTableDef(
+	ctx context.Context,
+	evalCtx *eval.Context,
+	d *tree.ExclusionConstraintTableDef,
+	desc *tabledesc.Mutable,
+	tn tree.TableName,
+	ts TableState,
+	validationBehavior tree.ValidationBehavior,
+	semaCtx *tree.SemaContext,
+) error {
+	if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.V24_1_ExclusionConstraints) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create exclusion constraints",
+			clusterversion.V24_1_ExclusionConstraints.Version())
+	}
+	method := d.Using
+	if method == "" {
+		method = "btree"
+	}
+	if _, ok := exclusionIndexMethods[method]; !ok {
+		return pgerror.Newf(pgcode.UndefinedObject, "access method %q does not exist", d.Using)
+	}
+
+	// If there is a predicate, validate it.
+	var predicate string
+	if d.Predicate != nil {
+		var err error
+		predicate, err = schemaexpr.ValidateExclusionPredicate(
+			ctx, tn, desc, d.Predicate, semaCtx, evalCtx.Settings.Version.ActiveVersionOrEmpty(ctx),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ResolveExclusionConstraint(
+		desc, string(d.Name), method, d.Elems, predicate, ts, validationBehavior,
+	)
+}
+
+// ResolveExclusionConstraint looks up the columns mentioned in an EXCLUDE
+// constraint, checks that their types support the given operators, and adds
+// metadata representing that constraint to the descriptor.
+//
+// The passed validationBehavior is used to determine whether or not preexisting
+// rows in the table need to be validated against the exclusion constraint
+// being added. This only applies for existing tables, not new tables.
+func ResolveExclusionConstraint(
+	tbl *tabledesc.Mutable,
+	constraintName string,
+	indexMethod string,
+	elems tree.ExclusionConstraintElems,
+	predicate string,
+	ts TableState,
+	validationBehavior tree.ValidationBehavior,
+) error {
+	columnIDs := make(descpb.ColumnIDs, len(elems))
+	operators := make([]string, len(elems))
+	colNames := make([]string, len(elems))
+	for i, elem := range elems {
+		col, err := tbl.FindActiveOrNewColumnByName(elem.Column)
+		if err != nil {
+			return err
+		}
+		if err := checkExclusionOperator(col, elem.Operator); err != nil {
+			return err
+		}
+		columnIDs[i] = col.GetID()
+		operators[i] = elem.Operator.Symbol.String()
+		colNames[i] = col.GetName()
+	}
+
+	// Verify we are not writing a constraint over the same name.
+	if constraintName == "" {
+		constraintName = tabledesc.GenerateUniqueName(
+			fmt.Sprintf("%s_%s_excl", tbl.GetName(), strings.Join(colNames, "_")),
+			func(p string) bool {
+				return catalog.FindConstraintByName(tbl, p) != nil
+			},
+		)
+	} else {
+		if c := catalog.FindConstraintByName(tbl, constraintName); c != nil {
+			return pgerror.Newf(pgcode.DuplicateObject, "duplicate constraint name: %q", constraintName)
+		}
+	}
+
+	validity := descpb.ConstraintValidity_Validated
+	if ts != NewTable {
+		if validationBehavior == tree.ValidationSkip {
+			validity = descpb.ConstraintValidity_Unvalidated
+		} else {
+			validity = descpb.ConstraintValidity_Validating
+		}
+	}
+
+	ec := descpb.ExclusionConstraint{
 		Name:         constraintName,
 		TableID:      tbl.ID,
 		ColumnIDs:    columnIDs,
+		Operators:    operators,
 		Predicate:    predicate,
 		Validity:     validity,
 		ConstraintID: tbl.NextConstraintID,
+		IndexMethod:  indexMethod,
 	}
 	tbl.NextConstraintID++
 	if ts == NewTable {
-		tbl.UniqueWithoutIndexConstraints = append(tbl.UniqueWithoutIndexConstraints, uc)
+		tbl.Exclusions = append(tbl.Exclusions, ec)
 	} else {
-		tbl.AddUniqueWithoutIndexMutation(&uc, descpb.DescriptorMutation_ADD)
+		tbl.AddExclusionMutation(&ec, descpb.DescriptorMutation_ADD)
 	}
 
 	return nil
 }
 
+// checkExclusionOperator returns an error if op cannot be used to compare the
+// values of col in an exclusion constraint. Only commutative operators are
+// allowed, since the constraint must hold regardless of which of two
+// conflicting rows was written first.
+func checkExclusionOperator(col catalog.Column, op treecmp.ComparisonOperator) error {
+	sym := op.Symbol
+	switch sym {
+	case treecmp.EQ, treecmp.Overlaps, treecmp.Adjacent:
+	case treecmp.NE:
+		// NE is evaluated as the negation of EQ.
+		sym = treecmp.EQ
+	default:
+		return pgerror.Newf(pgcode.WrongObjectType, "operator %s is not commutative", op)
+	}
+	if _, ok := tree.CmpOps[sym].LookupImpl(col.GetType(), col.GetType()); !ok {
+		return pgerror.Newf(pgcode.UndefinedObject,
+			"operator %s is not supported for column %q of type %s",
+			op, col.GetName(), col.GetType().SQLString(),
+		)
+	}
+	return nil
+}
+
 // ResolveFK looks up the tables and columns mentioned in a `REFERENCES`
 // constraint and adds metadata representing that constraint to the descriptor.
 // It may, in doing so, add to or alter descriptors in the passed in `backrefs`
@@ -858,6 +1045,11 @@ func ResolveFK(
 	validationBehavior tree.ValidationBehavior,
 	evalCtx *eval.Context,
 ) error {
+	if d.Deferrable != tree.ConstraintNotDeferrable {
+		if err := checkDeferrableConstraintsActive(ctx, evalCtx.Settings.Version); err != nil {
+			return err
+		}
+	}
 	var originColSet catalog.TableColSet
 	originCols := make([]catalog.Column, len(d.FromCols))
 	for i, fromCol := range d.FromCols {
@@ -1053,6 +1245,7 @@ func ResolveFK(
 		OnUpdate:            tree.ForeignKeyReferenceActionValue[d.Actions.Update],
 		Match:               tree.CompositeKeyMatchMethodValue[d.Match],
 		ConstraintID:        tbl.NextConstraintID,
+		Deferrability:       tree.ConstraintDeferrabilityValue[d.Deferrable],
 	}
 	tbl.NextConstraintID++
 	if ts == NewTable {
@@ -1237,7 +1430,7 @@ func newTableDescIfAs(
 			// resolving it again.
 			typ := d.Type.(*types.T)
 			if typ.UserDefined() {
-				tn, typDesc, err := params.p.GetTypeDescriptor(params.ctx, typedesc.UserDefinedTypeOIDToID(typ.Oid()))
+				tn, typDesc, err := params.p.GetTypeDescriptor(params.ctx, typedesc.GetUserDefinedTypeDescID(typ))
 				if err != nil {
 					return nil, err
 				}
@@ -1260,6 +1453,11 @@ func newTableDescIfAs(
 	if err != nil {
 		return nil, err
 	}
+	if p.WithNoData {
+		// The table is never populated from the source query, so it is not
+		// treated as a CREATE TABLE AS table afterwards.
+		return desc, nil
+	}
 	createQuery, err := getFinalSourceQuery(params, p.AsSource, evalContext)
 	if err != nil {
 		return nil, err
@@ -1922,10 +2120,21 @@ func NewTableDesc(
 				// We will add the unique constraint below.
 				break
 			}
+			// A deferrable unique constraint cannot be enforced by a unique index,
+			// which would reject duplicate keys before the deferred checks run.
+			// Instead, it is backed by a non-unique index on the same columns, and
+			// it is enforced like a UNIQUE WITHOUT INDEX constraint (added below).
+			deferrable := d.Deferrable != tree.ConstraintNotDeferrable
+			if deferrable {
+				if err := validateDeferrableUniqueConstraint(d); err != nil {
+					return nil, err
+				}
+			}
 			// If the index is named, ensure that the name is unique. Unnamed
 			// indexes will be given a unique auto-generated name later on when
-			// AllocateIDs is called.
-			if d.Name != "" {
+			// AllocateIDs is called. The name of a deferrable constraint belongs to
+			// the constraint rather than to its index.
+			if d.Name != "" && !deferrable {
 				if idx := catalog.FindIndexByName(&desc, d.Name.String()); idx != nil {
 					return nil, pgerror.Newf(pgcode.DuplicateRelation, "duplicate index name: %q", d.Name)
 				}
@@ -1960,6 +2169,10 @@ func NewTableDesc(
 				NotVisible:       d.Invisibility.Value != 0.0,
 				Invisibility:     d.Invisibility.Value,
 			}
+			if deferrable {
+				idx.Name = ""
+				idx.Unique = false
+			}
 			columns := d.Columns
 			if d.Sharded != nil {
 				if d.PrimaryKey && n.PartitionByTable.ContainsPartitions() && !n.PartitionByTable.All {
@@ -2040,7 +2253,8 @@ func NewTableDesc(
 					return nil, err
 				}
 			}
-		case *tree.CheckConstraintTableDef, *tree.ForeignKeyConstraintTableDef, *tree.FamilyTableDef:
+		case *tree.CheckConstraintTableDef, *tree.ForeignKeyConstraintTableDef, *tree.FamilyTableDef,
+			*tree.ExclusionConstraintTableDef:
 			// pass, handled below.
 
 		default:
@@ -2200,7 +2414,7 @@ func NewTableDesc(
 			}
 
 		case *tree.UniqueConstraintTableDef:
-			if d.WithoutIndex {
+			if d.WithoutIndex || d.Deferrable != tree.ConstraintNotDeferrable {
 				if err := addUniqueWithoutIndexTableDef(
 					ctx, evalCtx, sessionData, d, &desc, n.Table, NewTable, tree.ValidationDefault, semaCtx,
 				); err != nil {
@@ -2211,6 +2425,13 @@ func NewTableDesc(
 		case *tree.IndexTableDef, *tree.FamilyTableDef, *tree.LikeTableDef:
 			// Pass, handled above.
 
+		case *tree.ExclusionConstraintTableDef:
+			if err := addExclusionConstraintTableDef(
+				ctx, evalCtx, d, &desc, n.Table, NewTable, tree.ValidationDefault, semaCtx,
+			); err != nil {
+				return nil, err
+			}
+
 		case *tree.CheckConstraintTableDef:
 			ck, err := ckBuilder.Build(d, version)
 			if err != nil {
@@ -2658,6 +2879,30 @@ func replaceLikeTableOpts(n *tree.CreateTable, params runParams) (tree.TableDefs
 					}
 				}
 			}
+			for _, c := range td.EnforcedExclusionConstraints() {
+				def := tree.ExclusionConstraintTableDef{
+					Name:  tree.Name(c.GetName()),
+					Using: c.GetIndexMethod(),
+					Elems: make(tree.ExclusionConstraintElems, c.NumKeyColumns()),
+				}
+				for i := range def.Elems {
+					col, err := catalog.MustFindColumnByID(td, c.GetKeyColumnID(i))
+					if err != nil {
+						return nil, err
+					}
+					def.Elems[i] = tree.ExclusionConstraintElem{
+						Column:   col.ColName(),
+						Operator: c.GetOperator(i),
+					}
+				}
+				if c.IsPartial() {
+					def.Predicate, err = parser.ParseExpr(c.GetPredicate())
+					if err != nil {
+						return nil, err
+					}
+				}
+				defs = append(defs, &def)
+			}
 		}
 		if opts.Has(tree.LikeTableOptIndexes) {
 			for _, idx := range td.NonDropIndexes() {
@@ -0,0 +1,321 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+type createTriggerNode struct {
+	n         *tree.CreateTrigger
+	tableDesc *tabledesc.Mutable
+	fnDesc    *funcdesc.Mutable
+}
+
+// CreateTrigger creates a trigger on a table.
+// Privileges: TRIGGER on table, EXECUTE on the trigger function.
+//
+//	notes: postgres requires the same privileges.
+func (p *planner) CreateTrigger(ctx context.Context, n *tree.CreateTrigger) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE TRIGGER",
+	); err != nil {
+		return nil, err
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_1_Triggers) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create triggers",
+			clusterversion.V24_1_Triggers.Version())
+	}
+
+	_, tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &n.TableName, true /* required */, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc.IsVirtualTable() || tableDesc.IsSequence() {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"%q is not a table", tableDesc.GetName())
+	}
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.TRIGGER); err != nil {
+		return nil, err
+	}
+	// Disallow schema changes if this table's schema is locked.
+	if err := checkTableSchemaUnlocked(tableDesc); err != nil {
+		return nil, err
+	}
+
+	if n.ActionTime == tree.TriggerActionTimeInsteadOf {
+		return nil, errors.WithDetail(
+			pgerror.Newf(pgcode.WrongObjectType, "%q is a table", tableDesc.GetName()),
+			"Tables cannot have INSTEAD OF triggers.",
+		)
+	}
+	for _, ev := range n.Events {
+		if ev.EventType == tree.TriggerEventTruncate {
+			// TRUNCATE is a schema change rather than a mutation, so there is no
+			// query plan in which to execute the trigger function.
+			return nil, unimplemented.NewWithIssuef(28296, "TRUNCATE triggers are not supported")
+		}
+	}
+
+	if n.When != nil {
+		if err := p.checkTriggerWhen(ctx, tableDesc, n); err != nil {
+			return nil, err
+		}
+	}
+
+	fnDesc, err := p.resolveTriggerFunction(ctx, n.FuncName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &createTriggerNode{n: n, tableDesc: tableDesc, fnDesc: fnDesc}, nil
+}
+
+// resolveTriggerFunction resolves the function executed by a trigger, and
+// verifies that it is a user-defined PL/pgSQL function with no parameters that
+// returns TRIGGER.
+func (p *planner) resolveTriggerFunction(
+	ctx context.Context, name *tree.UnresolvedName,
+) (*funcdesc.Mutable, error) {
+	routineName, err := name.ToRoutineName()
+	if err != nil {
+		return nil, err
+	}
+	path := p.CurrentSearchPath()
+	fnDef, err := p.ResolveFunction(ctx, tree.MakeUnresolvedFunctionName(name), &path)
+	if err != nil {
+		return nil, err
+	}
+	ol, err := fnDef.MatchOverload(
+		[]*types.T{} /* paramTypes */, routineName.Schema(), &path, tree.UDFRoutine,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if ol.Type == tree.BuiltinRoutine {
+		return nil, pgerror.Newf(pgcode.InvalidObjectDefinition,
+			"function %s must return type trigger", fnDef.Name)
+	}
+	fnDesc, err := p.Descriptors().MutableByID(p.Txn()).Function(
+		ctx, funcdesc.UserDefinedFunctionOIDToID(ol.Oid),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if fnDesc.GetReturnType().Type.Family() != types.TriggerFamily {
+		return nil, pgerror.Newf(pgcode.InvalidObjectDefinition,
+			"function %s must return type trigger", fnDesc.GetName())
+	}
+	if fnDesc.GetLanguage() != catpb.Function_PLPGSQL {
+		return nil, unimplemented.NewWithIssuef(28296,
+			"trigger functions must be written in PL/pgSQL")
+	}
+	if err := p.CheckPrivilege(ctx, fnDesc, privilege.EXECUTE); err != nil {
+		return nil, err
+	}
+	return fnDesc, nil
+}
+
+// checkTriggerWhen type-checks the WHEN condition of a trigger. The condition
+// of a row-level trigger can refer to the NEW row, unless the trigger fires on
+// DELETE, and to the OLD row, unless it fires on INSERT. The condition of a
+// statement-level trigger can refer to neither.
+func (p *planner) checkTriggerWhen(
+	ctx context.Context, tableDesc catalog.TableDescriptor, n *tree.CreateTrigger,
+) error {
+	cols := tableDesc.VisibleColumns()
+	contents := make([]*types.T, len(cols))
+	labels := make([]string, len(cols))
+	for i, col := range cols {
+		contents[i] = col.GetType()
+		labels[i] = col.GetName()
+	}
+	rowType := types.MakeLabeledTuple(contents, labels)
+
+	// Replace the references to the NEW and OLD rows with indexed variables of
+	// the row type.
+	var refersToNew, refersToOld bool
+	rowRef := func(row string) tree.Expr {
+		if row == "new" {
+			refersToNew = true
+			return tree.NewOrdinalReference(0)
+		}
+		refersToOld = true
+		return tree.NewOrdinalReference(1)
+	}
+	expr, err := schemaexpr.ReplaceTriggerColumnRefs(
+		n.When, func(row string, col tree.Name) (tree.Expr, error) {
+			return &tree.ColumnAccessExpr{Expr: rowRef(row), ColName: col}, nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+	expr, err = tree.SimpleVisit(expr, func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		if name, ok := expr.(*tree.UnresolvedName); ok && name.NumParts == 1 && !name.Star {
+			if row := name.Parts[0]; row == "new" || row == "old" {
+				return false, rowRef(row), nil
+			}
+		}
+		return true, expr, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if n.ForEach != tree.TriggerForEachRow && (refersToNew || refersToOld) {
+		return pgerror.New(pgcode.InvalidObjectDefinition,
+			"statement trigger's WHEN condition cannot reference column values")
+	}
+	for _, ev := range n.Events {
+		if ev.EventType == tree.TriggerEventInsert && refersToOld {
+			return pgerror.New(pgcode.InvalidObjectDefinition,
+				"INSERT trigger's WHEN condition cannot reference OLD values")
+		}
+		if ev.EventType == tree.TriggerEventDelete && refersToNew {
+			return pgerror.New(pgcode.InvalidObjectDefinition,
+				"DELETE trigger's WHEN condition cannot reference NEW values")
+		}
+	}
+
+	defer p.semaCtx.Properties.Restore(p.semaCtx.Properties)
+	p.semaCtx.Properties.Require("trigger WHEN conditions", tree.RejectSpecial|tree.RejectSubqueries)
+	p.semaCtx.IVarContainer = triggerRowContainer{rowType: rowType}
+	defer func() { p.semaCtx.IVarContainer = nil }()
+	_, err = tree.TypeCheckAndRequire(ctx, expr, &p.semaCtx, types.Bool, "WHEN")
+	return err
+}
+
+// triggerRowContainer is the IndexedVarContainer used to type-check the WHEN
+// condition of a trigger, in which the indexed variables 0 and 1 are the NEW
+// and OLD rows.
+type triggerRowContainer struct {
+	rowType *types.T
+}
+
+var _ tree.IndexedVarContainer = triggerRowContainer{}
+
+// IndexedVarResolvedType is part of the tree.IndexedVarContainer interface.
+func (c triggerRowContainer) IndexedVarResolvedType(idx int) *types.T {
+	return c.rowType
+}
+
+// IndexedVarNodeFormatter is part of the tree.IndexedVarContainer interface.
+func (c triggerRowContainer) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	n := tree.Name("new")
+	if idx == 1 {
+		n = "old"
+	}
+	return &n
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE TRIGGER performs multiple KV operations on descriptors
+// and expects to see its own writes.
+func (n *createTriggerNode) ReadingOwnWrites() {}
+
+func (n *createTriggerNode) startExec(params runParams) error {
+	p := params.p
+	ctx := params.ctx
+	tableDesc := n.tableDesc
+
+	name := string(n.n.Name)
+	if name == "" {
+		return pgerror.New(pgcode.Syntax, "empty trigger name")
+	}
+
+	trigger := descpb.TriggerDescriptor{
+		Name:       name,
+		ActionTime: tree.TriggerActionTimeValue[n.n.ActionTime],
+		ForEachRow: n.n.ForEach == tree.TriggerForEachRow,
+		FuncID:     n.fnDesc.GetID(),
+		FuncArgs:   n.n.FuncArgs,
+		Enabled:    true,
+	}
+	for _, ev := range n.n.Events {
+		event := &descpb.TriggerDescriptor_Event{Type: tree.TriggerEventTypeValue[ev.EventType]}
+		for _, colName := range ev.Columns {
+			col, err := catalog.MustFindColumnByTreeName(tableDesc, colName)
+			if err != nil {
+				return err
+			}
+			event.ColumnIDs = append(event.ColumnIDs, col.GetID())
+		}
+		trigger.Events = append(trigger.Events, event)
+	}
+	if n.n.When != nil {
+		trigger.WhenExpr = tree.Serialize(n.n.When)
+	}
+
+	if existing := catalog.FindTriggerByName(tableDesc, name); existing != nil {
+		if !n.n.Replace {
+			return pgerror.Newf(pgcode.DuplicateObject,
+				"trigger %q for relation %q already exists", name, tableDesc.GetName())
+		}
+		if existing.FuncID != trigger.FuncID {
+			oldFnDesc, err := p.Descriptors().MutableByID(p.Txn()).Function(ctx, existing.FuncID)
+			if err != nil {
+				return err
+			}
+			oldFnDesc.RemoveTriggerReference(tableDesc.GetID(), existing.ID)
+			if err := p.writeFuncSchemaChange(ctx, oldFnDesc); err != nil {
+				return err
+			}
+		}
+		trigger.ID = existing.ID
+		*existing = trigger
+	} else {
+		if tableDesc.NextTriggerID == 0 {
+			tableDesc.NextTriggerID = 1
+		}
+		trigger.ID = tableDesc.NextTriggerID
+		tableDesc.NextTriggerID++
+		tableDesc.Triggers = append(tableDesc.Triggers, trigger)
+	}
+
+	if err := n.fnDesc.AddTriggerReference(tableDesc.GetID(), trigger.ID); err != nil {
+		return err
+	}
+	if err := p.writeFuncSchemaChange(ctx, n.fnDesc); err != nil {
+		return err
+	}
+
+	if err := validateDescriptor(ctx, p, tableDesc); err != nil {
+		return err
+	}
+
+	return p.writeSchemaChange(
+		ctx, tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()))
+}
+
+func (n *createTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createTriggerNode) Close(context.Context)        {}
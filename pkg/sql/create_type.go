@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/enum"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -30,7 +31,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
@@ -323,6 +326,15 @@ func (p *planner) createUserDefinedType(params runParams, n *createTypeNode) err
 		return params.p.createCompositeWithID(
 			params, id, n.n.CompositeTypeList, n.dbDesc, n.typeName,
 		)
+	case tree.Domain:
+		if !p.execCfg.Settings.Version.IsActive(params.ctx, clusterversion.V24_1_Domains) {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"version %v must be finalized to create domains",
+				clusterversion.V24_1_Domains.Version())
+		}
+		return params.p.createDomainWithID(
+			params, id, n.n.DomainBaseType, n.n.DomainConstraints, n.dbDesc, n.typeName,
+		)
 	}
 	return unimplemented.NewWithIssue(25123, "CREATE TYPE")
 }
@@ -464,6 +476,121 @@ func CreateCompositeTypeDesc(
 	}).BuildCreatedMutableType(), nil
 }
 
+// CreateDomainTypeDesc creates a new domain type descriptor.
+func CreateDomainTypeDesc(
+	params runParams,
+	id descpb.ID,
+	baseTypeRef tree.ResolvableTypeReference,
+	constraints []tree.DomainConstraint,
+	dbDesc catalog.DatabaseDescriptor,
+	schema catalog.SchemaDescriptor,
+	typeName *tree.TypeName,
+) (*typedesc.Mutable, error) {
+	baseType, err := tree.ResolveType(params.ctx, baseTypeRef, params.p.semaCtx.TypeResolver)
+	if err != nil {
+		return nil, err
+	}
+	if err := tree.CheckUnsupportedType(params.ctx, &params.p.semaCtx, baseType); err != nil {
+		return nil, err
+	}
+	if baseType.UserDefined() {
+		return nil, unimplemented.NewWithIssue(27796,
+			"domains over user-defined types are not yet supported")
+	}
+	switch baseType.Family() {
+	case types.ArrayFamily, types.TupleFamily:
+		return nil, unimplemented.NewWithIssuef(27796,
+			"domains over %s types are not yet supported", baseType.Family().Name())
+	}
+
+	domain := &descpb.TypeDescriptor_Domain{BaseType: baseType}
+	var nullabilitySet bool
+	for i := range constraints {
+		c := &constraints[i]
+		if c.Check == nil {
+			notNull := c.Nullability == tree.NotNull
+			if nullabilitySet && domain.NotNull != notNull {
+				return nil, pgerror.New(pgcode.Syntax, "conflicting NULL/NOT NULL constraints")
+			}
+			domain.NotNull, nullabilitySet = notNull, true
+			continue
+		}
+		check, err := makeDomainCheckConstraint(params, domain, typeName.Object(), c)
+		if err != nil {
+			return nil, err
+		}
+		domain.CheckConstraints = append(domain.CheckConstraints, check)
+	}
+
+	privs, err := catprivilege.CreatePrivilegesFromDefaultPrivileges(
+		dbDesc.GetDefaultPrivilegeDescriptor(),
+		schema.GetDefaultPrivilegeDescriptor(),
+		dbDesc.GetID(),
+		params.SessionData().User(),
+		privilege.Types,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return typedesc.NewBuilder(&descpb.TypeDescriptor{
+		Name:           typeName.Type(),
+		ID:             id,
+		ParentID:       dbDesc.GetID(),
+		ParentSchemaID: schema.GetID(),
+		Kind:           descpb.TypeDescriptor_DOMAIN,
+		Domain:         domain,
+		Version:        1,
+		Privileges:     privs,
+	}).BuildCreatedMutableType(), nil
+}
+
+// makeDomainCheckConstraint validates the CHECK constraint c of the domain
+// with the given name and returns its descriptor representation. If c is
+// unnamed, a name that is not used by the existing constraints of the domain
+// is generated for it.
+func makeDomainCheckConstraint(
+	params runParams, domain *descpb.TypeDescriptor_Domain, domainName string, c *tree.DomainConstraint,
+) (descpb.TypeDescriptor_Domain_CheckConstraint, error) {
+	inUse := func(name string) bool {
+		for i := range domain.CheckConstraints {
+			if domain.CheckConstraints[i].Name == name {
+				return true
+			}
+		}
+		return false
+	}
+	name := string(c.Name)
+	if name == "" {
+		name = domainName + "_check"
+		for i := 1; inUse(name); i++ {
+			name = fmt.Sprintf("%s_check%d", domainName, i)
+		}
+	} else if inUse(name) {
+		return descpb.TypeDescriptor_Domain_CheckConstraint{}, pgerror.Newf(pgcode.DuplicateObject,
+			"constraint %q for domain %q already exists", name, domainName)
+	}
+
+	// Type check the expression with VALUE standing for a value of the base
+	// type of the domain.
+	value := &tree.CastExpr{Expr: tree.DNull, Type: domain.BaseType, SyntaxMode: tree.CastShort}
+	expr, err := eval.ReplaceDomainValue(c.Check, value)
+	if err != nil {
+		return descpb.TypeDescriptor_Domain_CheckConstraint{}, err
+	}
+	if _, err := schemaexpr.SanitizeVarFreeExpr(
+		params.ctx, expr, types.Bool, tree.CheckConstraintExpr, params.p.SemaCtx(),
+		volatility.Immutable, false, /* allowAssignmentCast */
+	); err != nil {
+		return descpb.TypeDescriptor_Domain_CheckConstraint{}, err
+	}
+	return descpb.TypeDescriptor_Domain_CheckConstraint{
+		Name:     name,
+		Expr:     tree.Serialize(c.Check),
+		Validity: descpb.ConstraintValidity_Validated,
+	}, nil
+}
+
 func (p *planner) createEnumWithID(
 	params runParams,
 	id descpb.ID,
@@ -516,6 +643,28 @@ func (p *planner) createCompositeWithID(
 	return nil
 }
 
+func (p *planner) createDomainWithID(
+	params runParams,
+	id descpb.ID,
+	baseType tree.ResolvableTypeReference,
+	constraints []tree.DomainConstraint,
+	dbDesc catalog.DatabaseDescriptor,
+	typeName *tree.TypeName,
+) error {
+	// Generate a key in the namespace table and a new id for this type.
+	schema, err := getCreateTypeParams(params, typeName, dbDesc)
+	if err != nil {
+		return err
+	}
+
+	typeDesc, err := CreateDomainTypeDesc(params, id, baseType, constraints, dbDesc, schema, typeName)
+	if err != nil {
+		return err
+	}
+
+	return p.finishCreateType(params, id, typeName, typeDesc, dbDesc, schema)
+}
+
 func (p *planner) finishCreateType(
 	params runParams,
 	id descpb.ID,
@@ -525,13 +674,16 @@ func (p *planner) finishCreateType(
 	schema catalog.SchemaDescriptor,
 ) error {
 	// Create the implicit array type for this type before finishing the type.
-	arrayTypeID, err := p.createArrayType(params, typeName, typeDesc, dbDesc, schema.GetID())
-	if err != nil {
-		return err
-	}
+	// Domains don't have array types.
+	if typeDesc.Kind != descpb.TypeDescriptor_DOMAIN {
+		arrayTypeID, err := p.createArrayType(params, typeName, typeDesc, dbDesc, schema.GetID())
+		if err != nil {
+			return err
+		}
 
-	// Update the typeDesc with the created array type ID.
-	typeDesc.ArrayTypeID = arrayTypeID
+		// Update the typeDesc with the created array type ID.
+		typeDesc.ArrayTypeID = arrayTypeID
+	}
 
 	// Now create the type after the implicit array type as been created.
 	if err := p.createDescriptor(params.ctx, typeDesc, typeName.String()); err != nil {
@@ -278,6 +278,10 @@ func (n *createViewNode) startExec(params runParams) error {
 				desc.DependsOnTypes = append(desc.DependsOnTypes, orderedTypeDeps.Ordered()...)
 				newDesc = &desc
 
+				if err := params.p.updateTextSearchConfigReferences(params.ctx, newDesc); err != nil {
+					return err
+				}
+
 				if err = params.p.createDescriptor(
 					params.ctx,
 					newDesc,
@@ -615,7 +619,7 @@ func serializeUserDefinedTypesLang(
 		if !t.UserDefined() {
 			return typ, nil
 		}
-		return &tree.OIDTypeReference{OID: t.Oid()}, nil
+		return &tree.OIDTypeReference{OID: t.UserDefinedTypeOID()}, nil
 	}
 
 	fmtCtx := tree.NewFmtCtx(tree.FmtSimple)
@@ -778,6 +782,10 @@ func (p *planner) replaceViewDesc(
 		toReplace.DependsOnTypes = append(toReplace.DependsOnTypes, backrefID)
 	}
 
+	if err := p.updateTextSearchConfigReferences(ctx, toReplace); err != nil {
+		return nil, err
+	}
+
 	// Since we are replacing an existing view here, we need to write the new
 	// descriptor into place.
 	if err := p.writeSchemaChange(ctx, toReplace, descpb.InvalidMutationID,
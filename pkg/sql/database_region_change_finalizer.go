@@ -229,7 +229,7 @@ func (r *databaseRegionChangeFinalizer) repartitionRegionalByRowTables(
 		for i := range tableDesc.Columns {
 			col := &tableDesc.Columns[i]
 			if col.Type.UserDefined() {
-				tid := typedesc.UserDefinedTypeOIDToID(col.Type.Oid())
+				tid := typedesc.GetUserDefinedTypeDescID(col.Type)
 				if tid == r.typeID {
 					col.Type.TypeMeta = types.UserDefinedTypeMetadata{}
 				}
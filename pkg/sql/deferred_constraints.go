@@ -0,0 +1,634 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/memsize"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/semenumpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// deferredConstraint identifies a constraint whose checks were deferred until
+// the end of the transaction. Foreign keys are always identified by their
+// origin (referencing) table.
+type deferredConstraint struct {
+	tableID      descpb.ID
+	constraintID descpb.ConstraintID
+}
+
+// deferredConstraintKeys accumulates the distinct keys that must be checked
+// for a deferred constraint.
+type deferredConstraintKeys struct {
+	keys []tree.Datums
+	seen map[string]struct{}
+}
+
+// txnDeferredConstraints keeps track of the constraints whose checks were
+// deferred in the current transaction.
+type txnDeferredConstraints struct {
+	constraints map[deferredConstraint]*deferredConstraintKeys
+	// memAcc accounts for the keys recorded in constraints.
+	memAcc mon.BoundAccount
+}
+
+// reset forgets about all the deferred constraints and releases the memory
+// used by their keys.
+func (t *txnDeferredConstraints) reset(ctx context.Context) {
+	t.constraints = nil
+	t.memAcc.Clear(ctx)
+}
+
+// deferredConstraintChecks are the keys to check for a deferred constraint.
+type deferredConstraintChecks struct {
+	constraint deferredConstraint
+	keys       []tree.Datums
+}
+
+type deferredConstraints interface {
+	// addDeferredConstraintKey records that the check of the given constraint
+	// for the given key was skipped by a statement in the current transaction
+	// and must be performed before it commits.
+	addDeferredConstraintKey(ctx context.Context, c deferredConstraint, key tree.Datums) error
+	// takeDeferredConstraints returns the constraints recorded in the current
+	// transaction that satisfy the filter, in a deterministic order, and
+	// forgets about them. A nil filter matches all the constraints.
+	takeDeferredConstraints(filter func(deferredConstraint) bool) []deferredConstraintChecks
+}
+
+type connExDeferredConstraintsAccessor struct {
+	ex *connExecutor
+}
+
+func (c connExDeferredConstraintsAccessor) addDeferredConstraintKey(
+	ctx context.Context, dc deferredConstraint, key tree.Datums,
+) error {
+	if c.ex.extraTxnState.fromOuterTxn {
+		// The transaction is committed by somebody else, so we would never get
+		// the chance to validate the constraint.
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"deferred constraints cannot be checked in this context")
+	}
+	t := &c.ex.extraTxnState.deferredConstraints
+	if t.constraints == nil {
+		// Lazily allocate.
+		t.constraints = make(map[deferredConstraint]*deferredConstraintKeys)
+	}
+	k := t.constraints[dc]
+	if k == nil {
+		k = &deferredConstraintKeys{seen: make(map[string]struct{})}
+		t.constraints[dc] = k
+	}
+	encoded := tree.AsStringWithFlags(&key, tree.FmtParsable)
+	if _, ok := k.seen[encoded]; ok {
+		return nil
+	}
+	// The key is stored both as datums and in its encoded form.
+	sz := memsize.MapEntryOverhead + memsize.String + int64(len(encoded)) + memsize.DatumsOverhead
+	for i := range key {
+		sz += memsize.DatumOverhead + int64(key[i].Size())
+	}
+	if err := t.memAcc.Grow(ctx, sz); err != nil {
+		return errors.Wrap(err, "recording deferred constraint check")
+	}
+	k.seen[encoded] = struct{}{}
+	k.keys = append(k.keys, key)
+	return nil
+}
+
+func (c connExDeferredConstraintsAccessor) takeDeferredConstraints(
+	filter func(deferredConstraint) bool,
+) []deferredConstraintChecks {
+	m := c.ex.extraTxnState.deferredConstraints.constraints
+	if len(m) == 0 {
+		return nil
+	}
+	var ret []deferredConstraintChecks
+	for dc, k := range m {
+		if filter != nil && !filter(dc) {
+			continue
+		}
+		ret = append(ret, deferredConstraintChecks{constraint: dc, keys: k.keys})
+		delete(m, dc)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		a, b := ret[i].constraint, ret[j].constraint
+		if a.tableID != b.tableID {
+			return a.tableID < b.tableID
+		}
+		return a.constraintID < b.constraintID
+	})
+	return ret
+}
+
+// checkDeferrableConstraintsActive returns an error if constraints cannot be
+// deferred yet, since nodes running older binaries would check them
+// immediately.
+func checkDeferrableConstraintsActive(ctx context.Context, version clusterversion.Handle) error {
+	if !version.IsActive(ctx, clusterversion.V24_1_DeferrableConstraints) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use deferrable constraints",
+			clusterversion.V24_1_DeferrableConstraints.Version())
+	}
+	return nil
+}
+
+// emptyDeferredConstraints is the default impl used by the planner when the
+// connExecutor is not available.
+type emptyDeferredConstraints struct{}
+
+func (emptyDeferredConstraints) addDeferredConstraintKey(
+	context.Context, deferredConstraint, tree.Datums,
+) error {
+	return errors.AssertionFailedf("addDeferredConstraintKey not supported in emptyDeferredConstraints")
+}
+
+func (emptyDeferredConstraints) takeDeferredConstraints(
+	func(deferredConstraint) bool,
+) []deferredConstraintChecks {
+	return nil
+}
+
+// deferredCheckKind describes which constraint checks a mutation would have
+// performed, and therefore which of them may have been deferred.
+type deferredCheckKind uint8
+
+const (
+	// deferInsertChecks covers the outbound foreign key checks and the
+	// uniqueness checks performed for new rows.
+	deferInsertChecks deferredCheckKind = 1 << iota
+	// deferUpdateChecks covers the checks performed for updated rows: those
+	// for new rows, plus the NO ACTION inbound foreign key checks.
+	deferUpdateChecks
+	// deferDeleteChecks covers the NO ACTION inbound foreign key checks
+	// performed for deleted rows.
+	deferDeleteChecks
+)
+
+// deferredCheck describes a deferred constraint check that applies to the rows
+// written to a table.
+type deferredCheck struct {
+	constraint deferredConstraint
+	// cols are the columns of the written table that make up the key to check.
+	cols []descpb.ColumnID
+	// newRows is true if the check applies to the rows being inserted (and to
+	// the new values of the updated rows), and false if it applies to the rows
+	// being deleted (and to the old values of the updated rows).
+	newRows bool
+	// matchFull is true for MATCH FULL foreign keys, for which keys with some
+	// (but not all) NULL values must be checked.
+	matchFull bool
+	// partial is true for partial unique constraints, which may be affected by
+	// an update that doesn't modify the key.
+	partial bool
+}
+
+// deferredCheckRecorder records the keys of the rows written by a mutation that
+// must be checked for deferred constraints before the transaction commits. A
+// nil recorder records nothing.
+type deferredCheckRecorder struct {
+	cmpCtx tree.CompareContext
+	dc     deferredConstraints
+	checks []deferredCheck
+}
+
+// recordInsert records the keys of an inserted row. colMap maps column IDs to
+// ordinals in values.
+func (r *deferredCheckRecorder) recordInsert(
+	ctx context.Context, values tree.Datums, colMap catalog.TableColMap,
+) error {
+	if r == nil {
+		return nil
+	}
+	for i := range r.checks {
+		if c := &r.checks[i]; c.newRows {
+			if err := r.record(ctx, c, values, colMap); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// recordDelete records the keys of a deleted row. colMap maps column IDs to
+// ordinals in values.
+func (r *deferredCheckRecorder) recordDelete(
+	ctx context.Context, values tree.Datums, colMap catalog.TableColMap,
+) error {
+	if r == nil {
+		return nil
+	}
+	for i := range r.checks {
+		if c := &r.checks[i]; !c.newRows {
+			if err := r.record(ctx, c, values, colMap); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// recordUpdate records the keys of an updated row that were modified by the
+// update. oldValues and newValues share the same layout, described by colMap.
+func (r *deferredCheckRecorder) recordUpdate(
+	ctx context.Context, oldValues, newValues tree.Datums, colMap catalog.TableColMap,
+) error {
+	if r == nil {
+		return nil
+	}
+	for i := range r.checks {
+		c := &r.checks[i]
+		if !c.partial {
+			changed, err := r.keyChanged(c, oldValues, newValues, colMap)
+			if err != nil {
+				return err
+			}
+			if !changed {
+				continue
+			}
+		}
+		values := oldValues
+		if c.newRows {
+			values = newValues
+		}
+		if err := r.record(ctx, c, values, colMap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keyChanged returns whether any of the key columns of the check differ
+// between the old and the new values of an updated row. Key columns that
+// were not fetched are not being updated.
+func (r *deferredCheckRecorder) keyChanged(
+	c *deferredCheck, oldValues, newValues tree.Datums, colMap catalog.TableColMap,
+) (bool, error) {
+	for _, col := range c.cols {
+		ord, ok := colMap.Get(col)
+		if !ok {
+			continue
+		}
+		cmp, err := oldValues[ord].CompareError(r.cmpCtx, newValues[ord])
+		if err != nil {
+			return false, err
+		}
+		if cmp != 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *deferredCheckRecorder) record(
+	ctx context.Context,
+	c *deferredCheck, values tree.Datums, colMap catalog.TableColMap,
+) error {
+	key := make(tree.Datums, len(c.cols))
+	numNulls := 0
+	for i, col := range c.cols {
+		ord, ok := colMap.Get(col)
+		if !ok {
+			return errors.AssertionFailedf(
+				"column %d of deferred constraint %d is not available", col, c.constraint.constraintID,
+			)
+		}
+		key[i] = values[ord]
+		if key[i] == tree.DNull {
+			numNulls++
+		}
+	}
+	// Keys with NULL values always satisfy the constraint, except for MATCH
+	// FULL foreign keys, for which only all-NULL keys do.
+	if numNulls == len(key) || (numNulls > 0 && !c.matchFull) {
+		return nil
+	}
+	return r.dc.addDeferredConstraintKey(ctx, c.constraint, key)
+}
+
+// deferConstraintChecks determines the constraints on the given table whose
+// checks of the given kind are deferred until COMMIT under the current SET
+// CONSTRAINTS mode. The optimizer has already omitted those checks from the
+// plan. It returns a recorder for the keys of the written rows that need to be
+// checked, or nil if nothing was deferred. If a recorder is returned, the
+// caller must not auto-commit so that the constraints can be checked before
+// the transaction commits.
+func (p *planner) deferConstraintChecks(
+	desc catalog.TableDescriptor, kind deferredCheckKind,
+) (*deferredCheckRecorder, error) {
+	sd := p.SessionData()
+	var checks []deferredCheck
+	isDeferred := func(tableID descpb.ID, c catalog.Constraint, d semenumpb.Deferrability) bool {
+		mode := sd.ConstraintsModeFor(uint32(tableID), c.GetName())
+		return tree.ConstraintDeferrabilityType[d].IsDeferred(mode)
+	}
+	if kind&(deferInsertChecks|deferUpdateChecks) != 0 {
+		for _, fk := range desc.OutboundForeignKeys() {
+			if !isDeferred(desc.GetID(), fk, fk.Deferrability()) {
+				continue
+			}
+			checks = append(checks, deferredCheck{
+				constraint: deferredConstraint{tableID: desc.GetID(), constraintID: fk.GetConstraintID()},
+				cols:       fk.ForeignKeyDesc().OriginColumnIDs,
+				newRows:    true,
+				matchFull:  fk.Match() == semenumpb.Match_FULL,
+			})
+		}
+		for _, uwi := range desc.EnforcedUniqueConstraintsWithoutIndex() {
+			if !isDeferred(desc.GetID(), uwi, uwi.Deferrability()) {
+				continue
+			}
+			checks = append(checks, deferredCheck{
+				constraint: deferredConstraint{tableID: desc.GetID(), constraintID: uwi.GetConstraintID()},
+				cols:       uwi.UniqueWithoutIndexDesc().ColumnIDs,
+				newRows:    true,
+				partial:    uwi.IsPartial(),
+			})
+		}
+	}
+	if kind&(deferUpdateChecks|deferDeleteChecks) != 0 {
+		for _, fk := range desc.InboundForeignKeys() {
+			// An upsert may both update and delete rows (MERGE), but only deletes
+			// with a NO ACTION delete action and updates with a NO ACTION update
+			// action are checked.
+			if (kind&deferDeleteChecks == 0 || fk.OnDelete() != semenumpb.ForeignKeyAction_NO_ACTION) &&
+				(kind&deferUpdateChecks == 0 || fk.OnUpdate() != semenumpb.ForeignKeyAction_NO_ACTION) {
+				continue
+			}
+			if !isDeferred(fk.GetOriginTableID(), fk, fk.Deferrability()) {
+				continue
+			}
+			checks = append(checks, deferredCheck{
+				constraint: deferredConstraint{tableID: fk.GetOriginTableID(), constraintID: fk.GetConstraintID()},
+				cols:       fk.ForeignKeyDesc().ReferencedColumnIDs,
+			})
+		}
+	}
+	if len(checks) == 0 {
+		return nil, nil
+	}
+	// The checks performed at commit time don't lock the rows they read, so
+	// concurrent transactions could invalidate them before this one commits.
+	if level := p.EvalContext().TxnIsoLevel; level != isolation.Serializable {
+		return nil, unimplemented.Newf(
+			"deferred constraints",
+			"deferred constraint checks are not supported under %s isolation", level.StringLower(),
+		)
+	}
+	return &deferredCheckRecorder{
+		cmpCtx: p.EvalContext(),
+		dc:     p.deferredConstraints,
+		checks: checks,
+	}, nil
+}
+
+// validateDeferredConstraints performs the checks that were deferred in the
+// current transaction for the constraints that satisfy the filter (all of them
+// if the filter is nil). Only the keys of the rows written by the transaction
+// are checked. Constraints (or tables) which were dropped in the meantime are
+// skipped.
+func (p *planner) validateDeferredConstraints(
+	ctx context.Context, filter func(deferredConstraint) bool,
+) error {
+	for _, dc := range p.deferredConstraints.takeDeferredConstraints(filter) {
+		if err := p.validateDeferredConstraint(ctx, dc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deferredCheckBatchSize is the maximum number of keys checked by a single
+// query.
+const deferredCheckBatchSize = 100
+
+// deferredCheckBatchBytes is the maximum size of the keys formatted into a
+// single query, unless a single key is larger.
+const deferredCheckBatchBytes = 64 << 10
+
+func (p *planner) validateDeferredConstraint(ctx context.Context, dc deferredConstraintChecks) error {
+	tbl, err := p.Descriptors().ByID(p.txn).Get().Table(ctx, dc.constraint.tableID)
+	if err != nil {
+		if pgerror.GetPGCode(err) == pgcode.UndefinedTable {
+			return nil
+		}
+		return err
+	}
+	if tbl.Dropped() {
+		return nil
+	}
+	c := catalog.FindConstraintByID(tbl, dc.constraint.constraintID)
+	if c == nil || !c.IsEnforced() {
+		return nil
+	}
+	for len(dc.keys) > 0 {
+		batch := nextDeferredCheckBatch(dc.keys)
+		dc.keys = dc.keys[len(batch):]
+		if fk := c.AsForeignKey(); fk != nil {
+			err = p.checkDeferredForeignKey(ctx, tbl, fk, batch)
+		} else if uwi := c.AsUniqueWithoutIndex(); uwi != nil {
+			err = p.checkDeferredUniqueConstraint(ctx, tbl, uwi, batch)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nextDeferredCheckBatch returns the keys at the start of the given slice that
+// can be checked by a single query. The batch contains at least one key.
+func nextDeferredCheckBatch(keys []tree.Datums) []tree.Datums {
+	var size int
+	for i := range keys {
+		if i == deferredCheckBatchSize || (i > 0 && size >= deferredCheckBatchBytes) {
+			return keys[:i]
+		}
+		for _, d := range keys[i] {
+			size += int(d.Size())
+		}
+	}
+	return keys
+}
+
+// checkDeferredForeignKey verifies that none of the given keys is used by a
+// row of the origin table without having a match in the referenced table.
+//
+// For example, a foreign key from child(a, b) to parent(x, y) is checked with
+// the following query:
+//
+// SELECT k1, k2 FROM (VALUES (...), (...)) AS v(k1, k2)
+// WHERE EXISTS (
+//
+//	SELECT 1 FROM child AS s
+//	WHERE s.a IS NOT DISTINCT FROM v.k1 AND s.b IS NOT DISTINCT FROM v.k2
+//
+// ) AND NOT EXISTS (
+//
+//	SELECT 1 FROM parent AS t WHERE t.x = v.k1 AND t.y = v.k2
+//
+// )
+// LIMIT 1
+func (p *planner) checkDeferredForeignKey(
+	ctx context.Context,
+	srcTable catalog.TableDescriptor,
+	fk catalog.ForeignKeyConstraint,
+	keys []tree.Datums,
+) error {
+	targetTable, err := p.Descriptors().ByID(p.txn).Get().Table(ctx, fk.GetReferencedTableID())
+	if err != nil {
+		return err
+	}
+	fkDesc := fk.ForeignKeyDesc()
+	originColNames, err := catalog.ColumnNamesForIDs(srcTable, fkDesc.OriginColumnIDs)
+	if err != nil {
+		return err
+	}
+	referencedColNames, err := catalog.ColumnNamesForIDs(targetTable, fkDesc.ReferencedColumnIDs)
+	if err != nil {
+		return err
+	}
+	keyCols := deferredCheckKeyCols(len(originColNames))
+	srcWhere := make([]string, len(keyCols))
+	targetWhere := make([]string, len(keyCols))
+	for i := range keyCols {
+		srcWhere[i] = fmt.Sprintf(
+			"s.%s IS NOT DISTINCT FROM v.%s", tree.NameString(originColNames[i]), keyCols[i],
+		)
+		targetWhere[i] = fmt.Sprintf("t.%s = v.%s", tree.NameString(referencedColNames[i]), keyCols[i])
+	}
+	query := fmt.Sprintf(
+		`SELECT %[1]s FROM (VALUES %[2]s) AS v(%[1]s)
+		 WHERE EXISTS (SELECT 1 FROM [%[3]d AS src] AS s WHERE %[4]s)
+		 AND NOT EXISTS (SELECT 1 FROM [%[5]d AS target] AS t WHERE %[6]s) LIMIT 1`,
+		strings.Join(keyCols, ", "),        // 1
+		deferredCheckValues(keys),          // 2
+		srcTable.GetID(),                   // 3
+		strings.Join(srcWhere, " AND "),    // 4
+		targetTable.GetID(),                // 5
+		strings.Join(targetWhere, " AND "), // 6
+	)
+	log.VEventf(ctx, 2, "checking deferred FK %q with query %q", fk.GetName(), query)
+	txn := p.InternalSQLTxn()
+	values, err := txn.QueryRowEx(ctx, "check deferred fk constraint", txn.KV(),
+		sessiondata.NodeUserSessionDataOverride, query)
+	if err != nil {
+		return err
+	}
+	if values.Len() > 0 {
+		return pgerror.WithConstraintName(pgerror.Newf(pgcode.ForeignKeyViolation,
+			"foreign key violation: %q row %s has no match in %q",
+			srcTable.GetName(), formatValues(originColNames, values), targetTable.GetName()), fk.GetName())
+	}
+	return nil
+}
+
+// checkDeferredUniqueConstraint verifies that none of the given keys is
+// duplicated in the table.
+//
+// For example, a unique constraint on columns (a, b) of the table "tbl" is
+// checked with the following query:
+//
+// SELECT k1, k2 FROM (VALUES (...), (...)) AS v(k1, k2)
+// WHERE (SELECT count(*) FROM tbl AS t WHERE t.a = v.k1 AND t.b = v.k2) > 1
+// LIMIT 1
+//
+// The predicate of a partial unique constraint is added to the filter of the
+// subquery.
+func (p *planner) checkDeferredUniqueConstraint(
+	ctx context.Context,
+	tbl catalog.TableDescriptor,
+	uwi catalog.UniqueWithoutIndexConstraint,
+	keys []tree.Datums,
+) error {
+	colNames, err := catalog.ColumnNamesForIDs(tbl, uwi.UniqueWithoutIndexDesc().ColumnIDs)
+	if err != nil {
+		return err
+	}
+	keyCols := deferredCheckKeyCols(len(colNames))
+	where := make([]string, len(keyCols), len(keyCols)+1)
+	for i := range keyCols {
+		where[i] = fmt.Sprintf("t.%s = v.%s", tree.NameString(colNames[i]), keyCols[i])
+	}
+	if uwi.IsPartial() {
+		where = append(where, fmt.Sprintf("(%s)", uwi.GetPredicate()))
+	}
+	query := fmt.Sprintf(
+		`SELECT %[1]s FROM (VALUES %[2]s) AS v(%[1]s)
+		 WHERE (SELECT count(*) FROM [%[3]d AS tbl] AS t WHERE %[4]s) > 1 LIMIT 1`,
+		strings.Join(keyCols, ", "),  // 1
+		deferredCheckValues(keys),    // 2
+		tbl.GetID(),                  // 3
+		strings.Join(where, " AND "), // 4
+	)
+	log.VEventf(ctx, 2, "checking deferred unique constraint %q with query %q", uwi.GetName(), query)
+	txn := p.InternalSQLTxn()
+	values, err := txn.QueryRowEx(ctx, "check deferred unique constraint", txn.KV(),
+		sessiondata.NodeUserSessionDataOverride, query)
+	if err != nil {
+		return err
+	}
+	if values.Len() > 0 {
+		valuesStr := make([]string, len(values))
+		for i := range values {
+			valuesStr[i] = values[i].String()
+		}
+		return errors.WithDetail(
+			pgerror.WithConstraintName(
+				pgerror.Newf(
+					pgcode.UniqueViolation, "duplicate key value violates unique constraint %q", uwi.GetName(),
+				),
+				uwi.GetName(),
+			),
+			fmt.Sprintf(
+				"Key (%s)=(%s) already exists.", strings.Join(colNames, ","), strings.Join(valuesStr, ","),
+			),
+		)
+	}
+	return nil
+}
+
+// deferredCheckKeyCols returns the names of the columns of the VALUES clause
+// that holds the keys to check.
+func deferredCheckKeyCols(n int) []string {
+	cols := make([]string, n)
+	for i := range cols {
+		cols[i] = fmt.Sprintf("k%d", i+1)
+	}
+	return cols
+}
+
+// deferredCheckValues formats the given keys as the rows of a VALUES clause.
+func deferredCheckValues(keys []tree.Datums) string {
+	var buf strings.Builder
+	for i := range keys {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(tree.AsStringWithFlags(&keys[i], tree.FmtParsable))
+	}
+	return buf.String()
+}
@@ -161,6 +161,9 @@ func TryDelegate(
 	case *tree.ShowSchedules:
 		return d.delegateShowSchedules(t)
 
+	case *tree.ShowSubscriptions:
+		return d.delegateShowSubscriptions(t)
+
 	case *tree.ControlJobsForSchedules:
 		return d.delegateJobControl(ControlJobsDelegate{
 			Schedules: t.Schedules,
@@ -265,7 +265,7 @@ SELECT database_name,
 			if err != nil {
 				return nil, err
 			}
-			paramTypes, err := fn.ParamTypes(d.ctx, d.catalog)
+			paramTypes, err := fn.ParamTypes(d.ctx, d.catalog, routineType)
 			if err != nil {
 				return nil, err
 			}
@@ -0,0 +1,65 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package delegate
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+)
+
+// delegateShowSubscriptions implements SHOW SUBSCRIPTIONS, which lists the
+// subscriptions with their progress. The subscriptions are the jobs of type
+// SUBSCRIPTION that did not terminate and were not requested to be canceled.
+// The replication lag is the time since the commit, on the publisher, of the
+// last applied transaction.
+func (d *delegator) delegateShowSubscriptions(n *tree.ShowSubscriptions) (tree.Statement, error) {
+	sqltelemetry.IncrementShowCounter(sqltelemetry.Jobs)
+
+	// Note: the connection_uri of the subscription details is not shown, since
+	// it may contain a password.
+	const query = `
+WITH payload AS (
+  SELECT
+    id,
+    crdb_internal.pb_to_json(
+      'cockroach.sql.jobs.jobspb.Payload',
+      payload, false, true
+    )->'subscription' AS subscription_details
+  FROM
+    crdb_internal.system_jobs
+  WHERE job_type = '%s'
+)
+SELECT
+  subscription_details->>'name' AS subscription_name,
+  job_id,
+  status,
+  running_status,
+  jsonb_array_to_string_array(subscription_details->'publications') AS publications,
+  subscription_details->>'slot_name' AS slot_name,
+  high_water_timestamp,
+  timezone('UTC', now()) - crdb_internal.approximate_timestamp(high_water_timestamp) AS replication_lag
+FROM
+  crdb_internal.jobs
+  INNER JOIN payload ON id = job_id
+WHERE
+  status NOT IN ('%s', '%s', '%s', '%s', '%s', '%s')
+ORDER BY
+  subscription_name`
+
+	return d.parse(fmt.Sprintf(query,
+		jobspb.TypeSubscription, jobs.StatusSucceeded, jobs.StatusFailed, jobs.StatusCanceled,
+		jobs.StatusRevertFailed, jobs.StatusCancelRequested, jobs.StatusReverting,
+	))
+}
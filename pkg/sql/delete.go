@@ -73,7 +73,16 @@ func (d *deleteNode) startExec(params runParams) error {
 			params.p.Mon().MakeBoundAccount(),
 			colinfo.ColTypeInfoFromResCols(d.columns))
 	}
-	return d.run.td.init(params.ctx, params.p.txn, params.EvalContext(), &params.EvalContext().Settings.SV)
+	if err := d.run.td.init(params.ctx, params.p.txn, params.EvalContext(), &params.EvalContext().Settings.SV); err != nil {
+		return err
+	}
+	if rec, err := params.p.deferConstraintChecks(d.run.td.tableDesc(), deferDeleteChecks); err != nil {
+		return err
+	} else if rec != nil {
+		d.run.td.autoCommit = autoCommitDisabled
+		d.run.td.deferredChecks = rec
+	}
+	return nil
 }
 
 // Next is required because batchedPlanNode inherits from planNode, but
@@ -55,6 +55,11 @@ func (n *discardNode) startExec(params runParams) error {
 		// DEALLOCATE ALL
 		params.p.preparedStatements.DeleteAll(params.ctx)
 
+		// UNLISTEN *
+		if err := params.p.notifier.listen("" /* channel */, true /* unlisten */, true /* all */); err != nil {
+			return err
+		}
+
 		// DISCARD SEQUENCES
 		params.p.sessionDataMutatorIterator.applyOnEachMutator(func(m sessionDataMutator) {
 			m.data.SequenceState = sessiondata.NewSequenceState()
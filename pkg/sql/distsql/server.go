@@ -70,6 +70,7 @@ type ServerImpl struct {
 	memMonitor        *mon.BytesMonitor
 	regexpCache       *tree.RegexpCache
 	toCharFormatCache *tochar.FormatCache
+	domainCheckCache  *eval.DomainCheckCache
 }
 
 var _ execinfrapb.DistSQLServer = &ServerImpl{}
@@ -82,6 +83,7 @@ func NewServer(
 		ServerConfig:      cfg,
 		regexpCache:       tree.NewRegexpCache(512),
 		toCharFormatCache: tochar.NewFormatCache(512),
+		domainCheckCache:  eval.NewDomainCheckCache(512),
 		flowRegistry:      flowinfra.NewFlowRegistry(),
 		remoteFlowRunner:  remoteFlowRunner,
 		memMonitor: mon.NewMonitor(
@@ -356,6 +358,7 @@ func (ds *ServerImpl) setupFlow(
 			Codec:                     ds.ServerConfig.Codec,
 			ReCache:                   ds.regexpCache,
 			ToCharFormatCache:         ds.toCharFormatCache,
+			DomainCheckCache:          ds.domainCheckCache,
 			Locality:                  ds.ServerConfig.Locality,
 			OriginalLocality:          ds.ServerConfig.Locality,
 			Tracer:                    ds.ServerConfig.Tracer,
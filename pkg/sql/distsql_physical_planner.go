@@ -14,6 +14,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"math"
 	"reflect"
 	"sort"
 
@@ -42,12 +43,14 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra/execopnode"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execstats"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan/replicaoracle"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
@@ -63,6 +66,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
@@ -324,6 +328,11 @@ func (v *distSQLExprCheckVisitor) VisitPre(expr tree.Expr) (recurse bool, newExp
 			v.err = newQueryNotSupportedErrorf("function %s cannot be executed with distsql", t)
 			return false, expr
 		}
+		if mayUseUserDefinedTextSearchObject(t) {
+			v.err = newQueryNotSupportedErrorf(
+				"function %s with a user-defined text search configuration cannot be executed with distsql", t)
+			return false, expr
+		}
 	case *tree.RoutineExpr:
 		v.err = newQueryNotSupportedErrorf("user-defined routine %s cannot be executed with distsql", t)
 		return false, expr
@@ -369,6 +378,38 @@ func (v *distSQLExprCheckVisitor) VisitPre(expr tree.Expr) (recurse bool, newExp
 
 func (v *distSQLExprCheckVisitor) VisitPost(expr tree.Expr) tree.Expr { return expr }
 
+// mayUseUserDefinedTextSearchObject returns whether a text search function may
+// use a user-defined configuration or dictionary, which can only be resolved
+// by the planner of the gateway. This is the case unless the configuration or
+// dictionary argument is the constant name of a built-in one.
+func mayUseUserDefinedTextSearchObject(f *tree.FuncExpr) bool {
+	if f.ResolvedOverload() == nil {
+		return false
+	}
+	params, ok := f.ResolvedOverload().Types.(tree.ParamTypes)
+	if !ok {
+		return false
+	}
+	for i, p := range params {
+		var isBuiltin func(string) bool
+		switch p.Name {
+		case "config":
+			isBuiltin = tsearch.IsBuiltinConfig
+		case "dict":
+			isBuiltin = tsearch.IsBuiltinDictionary
+		default:
+			continue
+		}
+		if i >= len(f.Exprs) {
+			return false
+		}
+		if name, ok := f.Exprs[i].(*tree.DString); !ok || !isBuiltin(string(*name)) {
+			return true
+		}
+	}
+	return false
+}
+
 // hasOidType returns whether t or its contents include an OID type.
 func hasOidType(t *types.T) bool {
 	switch t.Family() {
@@ -545,6 +586,15 @@ func checkSupportForPlanNode(node planNode) (distRecommendation, error) {
 		if err != nil {
 			return cannotDistribute, err
 		}
+		for _, f := range n.funcs {
+			if f.userDefined != nil {
+				// User-defined aggregates invoke routines, which can only be
+				// evaluated on the gateway.
+				return cannotDistribute, newQueryNotSupportedErrorf(
+					"user-defined aggregate %s cannot be executed with distsql", f.userDefined.Name,
+				)
+			}
+		}
 		// Distribute aggregations if possible.
 		return rec.compose(shouldDistribute), nil
 
@@ -736,6 +786,13 @@ func checkSupportForPlanNode(node planNode) (distRecommendation, error) {
 		if err != nil {
 			return cannotDistribute, err
 		}
+		for _, f := range n.funcs {
+			if f.userDefined != nil {
+				return cannotDistribute, newQueryNotSupportedErrorf(
+					"user-defined aggregate %s cannot be executed with distsql", f.userDefined.Name,
+				)
+			}
+		}
 		for _, f := range n.funcs {
 			if len(f.partitionIdxs) > 0 {
 				// If at least one function has PARTITION BY clause, then we
@@ -1913,6 +1970,13 @@ func initTableReaderSpecTemplate(
 		return nil, execinfrapb.PostProcessSpec{}, err
 	}
 
+	if n.sample.Method == opt.BernoulliTableSample {
+		s.Sample = &execinfrapb.TableSampleSpec{
+			Probability: n.sample.Fraction,
+			Seed:        math.Float64bits(n.sample.Seed),
+		}
+	}
+
 	var post execinfrapb.PostProcessSpec
 	if n.hardLimit != 0 {
 		post.Limit = uint64(n.hardLimit)
@@ -1932,6 +1996,21 @@ func (dsp *DistSQLPlanner) createTableReaders(
 		return nil, err
 	}
 
+	spans := n.spans
+	if n.sample.Method == opt.SystemTableSample {
+		spans, err = dsp.sampleSpansByRange(ctx, planCtx, spans, n.sample)
+		if err != nil {
+			return nil, err
+		}
+		if len(spans) == 0 {
+			// No range was selected, but the fetchers require at least one
+			// span. Look up a single key of the index and reject every row
+			// that it might return.
+			spans = roachpb.Spans{{Key: n.spans[0].Key}}
+			spec.Sample = &execinfrapb.TableSampleSpec{}
+		}
+	}
+
 	p := planCtx.NewPhysicalPlan()
 	err = dsp.planTableReaders(
 		ctx,
@@ -1941,7 +2020,7 @@ func (dsp *DistSQLPlanner) createTableReaders(
 			spec:              spec,
 			post:              post,
 			desc:              n.desc,
-			spans:             n.spans,
+			spans:             spans,
 			reverse:           n.reverse,
 			parallelize:       n.parallelize,
 			estimatedRowCount: n.estimatedRowCount,
@@ -1951,6 +2030,73 @@ func (dsp *DistSQLPlanner) createTableReaders(
 	return p, err
 }
 
+// sampleSpansByRange implements TABLESAMPLE SYSTEM by splitting the given
+// spans at range boundaries and keeping only the pieces of the ranges that are
+// selected by the sample. Whether a range is selected depends only on its start
+// key and the seed, so that a REPEATABLE sample returns the same rows as long
+// as the table and its range boundaries are unchanged.
+//
+// Unlike Postgres, which selects pages of equal size, ranges are selected
+// without regard to their size. Every row is still selected with the sample
+// probability, so the expected number of rows returned is the same, but ranges
+// are much larger and less uniform than pages: the number of rows returned
+// varies a lot more between seeds, and a table that fits in a single range is
+// returned either entirely or not at all. Weighting the ranges by their size
+// would require their stats, which aren't available while planning.
+func (dsp *DistSQLPlanner) sampleSpansByRange(
+	ctx context.Context, planCtx *PlanningCtx, spans roachpb.Spans, sample opt.TableSample,
+) (roachpb.Spans, error) {
+	sampler := rowinfra.MakeKeySampler(sample.Fraction, math.Float64bits(sample.Seed))
+	if !sampler.Sampling() {
+		return spans, nil
+	}
+	it := planCtx.spanIter
+	if it == nil {
+		// Local plans don't always instantiate a span resolver iterator.
+		it = dsp.spanResolver.NewSpanResolverIterator(
+			planCtx.ExtendedEvalCtx.Txn, physicalplan.DefaultReplicaChooser,
+		)
+	}
+	var sampled roachpb.Spans
+	for _, span := range spans {
+		rSpan, err := keys.SpanAddr(span)
+		if err != nil {
+			return nil, err
+		}
+		for it.Seek(ctx, span, kvcoord.Ascending); ; it.Next(ctx) {
+			if !it.Valid() {
+				return nil, it.Error()
+			}
+			desc := it.Desc()
+			if sampler.Keep(desc.StartKey) {
+				if len(span.EndKey) == 0 {
+					// A point lookup lies entirely within a single range.
+					sampled = append(sampled, span)
+				} else {
+					// Clip the range to the span being sampled.
+					piece := span
+					if rSpan.Key.Less(desc.StartKey) {
+						piece.Key = desc.StartKey.AsRawKey()
+					}
+					if desc.EndKey.Less(rSpan.EndKey) {
+						piece.EndKey = desc.EndKey.AsRawKey()
+					}
+					if n := len(sampled); n > 0 && sampled[n-1].EndKey.Equal(piece.Key) {
+						// Merge adjacent pieces of consecutive selected ranges.
+						sampled[n-1].EndKey = piece.EndKey
+					} else {
+						sampled = append(sampled, piece)
+					}
+				}
+			}
+			if !it.NeedAnother() {
+				break
+			}
+		}
+	}
+	return sampled, nil
+}
+
 // tableReaderPlanningInfo is a utility struct that contains the information
 // needed to perform the physical planning of table readers once the specs have
 // been created. See scanNode to get more context on some of the fields.
@@ -2277,6 +2423,7 @@ type aggregatorPlanningInfo struct {
 	isScalar                 bool
 	groupCols                []int
 	groupColOrdering         colinfo.ColumnOrdering
+	groupingSets             [][]int
 	inputMergeOrdering       execinfrapb.Ordering
 	reqOrdering              ReqOrdering
 	allowPartialDistribution bool
@@ -2290,11 +2437,18 @@ func (dsp *DistSQLPlanner) addAggregators(
 	aggregations := make([]execinfrapb.AggregatorSpec_Aggregation, len(n.funcs))
 	argumentsColumnTypes := make([][]*types.T, len(n.funcs))
 	for i, fholder := range n.funcs {
-		funcIdx, err := execinfrapb.GetAggregateFuncIdx(fholder.funcName)
-		if err != nil {
-			return err
+		if fholder.userDefined != nil {
+			aggregations[i].Func = execinfrapb.UserDefinedAgg
+			aggregations[i].UserDefined = makeUserDefinedAggregateSpec(
+				fholder.userDefined, n.columns[i].Typ,
+			)
+		} else {
+			funcIdx, err := execinfrapb.GetAggregateFuncIdx(fholder.funcName)
+			if err != nil {
+				return err
+			}
+			aggregations[i].Func = execinfrapb.AggregatorSpec_Func(funcIdx)
 		}
-		aggregations[i].Func = execinfrapb.AggregatorSpec_Func(funcIdx)
 		aggregations[i].Distinct = fholder.isDistinct
 		for _, renderIdx := range fholder.argRenderIdxs {
 			aggregations[i].ColIdx = append(aggregations[i].ColIdx, uint32(p.PlanToStreamColMap[renderIdx]))
@@ -2321,11 +2475,34 @@ func (dsp *DistSQLPlanner) addAggregators(
 		isScalar:             n.isScalar,
 		groupCols:            n.groupCols,
 		groupColOrdering:     n.groupColOrdering,
+		groupingSets:         n.groupingSets,
 		inputMergeOrdering:   dsp.convertOrdering(planReqOrdering(n.plan), p.PlanToStreamColMap),
 		reqOrdering:          n.reqOrdering,
 	})
 }
 
+// makeUserDefinedAggregateSpec returns the specification of a user-defined
+// aggregate that is evaluated with the given routines. The routines are passed
+// as local expressions, so plans with user-defined aggregates are never
+// distributed.
+func makeUserDefinedAggregateSpec(
+	routines *tree.AggregateRoutines, resultType *types.T,
+) *execinfrapb.AggregatorSpec_UserDefinedAggregate {
+	spec := &execinfrapb.AggregatorSpec_UserDefinedAggregate{
+		Name:       routines.Name,
+		Transition: execinfrapb.Expression{LocalExpr: routines.Transition},
+		StateType:  routines.StateType,
+		ResultType: resultType,
+	}
+	if routines.Final != nil {
+		spec.Final = execinfrapb.Expression{LocalExpr: routines.Final}
+	}
+	if routines.InitCond != nil {
+		spec.InitCond = execinfrapb.Expression{LocalExpr: routines.InitCond}
+	}
+	return spec
+}
+
 // planAggregators plans the aggregator processors. An evaluator stage is added
 // if necessary.
 // Invariants assumed:
@@ -2363,6 +2540,22 @@ func (dsp *DistSQLPlanner) planAggregators(
 		orderedGroupCols[i] = uint32(p.PlanToStreamColMap[c.ColIdx])
 		orderedGroupColSet.Add(c.ColIdx)
 	}
+	var groupingSets []execinfrapb.AggregatorSpec_GroupingSet
+	if info.groupingSets != nil {
+		// Grouping sets are computed by a single aggregator that aggregates
+		// each input row once for every set. The ordinal of the set is output
+		// after the aggregations.
+		if len(orderedGroupCols) > 0 || info.isScalar {
+			return errors.AssertionFailedf("grouping sets require a non-scalar unordered aggregation")
+		}
+		groupingSets = make([]execinfrapb.AggregatorSpec_GroupingSet, len(info.groupingSets))
+		for i, set := range info.groupingSets {
+			groupingSets[i].Cols = make([]uint32, len(set))
+			for j, idx := range set {
+				groupingSets[i].Cols[j] = uint32(p.PlanToStreamColMap[idx])
+			}
+		}
+	}
 
 	// planHashGroupJoin tracks whether we should plan a hash group-join for the
 	// first stage of aggregators (either local if multi-stage or final if
@@ -2381,7 +2574,9 @@ func (dsp *DistSQLPlanner) planAggregators(
 	//      is the same (i.e. both either local or distributed).
 	//      TODO(yuzefovich): we could consider lifting the condition 5. by
 	//      changing the distribution of the hash joiner stager.
-	planHashGroupJoin := planCtx.ExtendedEvalCtx.SessionData().ExperimentalHashGroupJoinEnabled
+	//   6. there are no grouping sets.
+	planHashGroupJoin := planCtx.ExtendedEvalCtx.SessionData().ExperimentalHashGroupJoinEnabled &&
+		groupingSets == nil
 	if planHashGroupJoin { // condition 1.
 		planHashGroupJoin = func() bool {
 			prevStageProc := p.Processors[p.ResultRouters[0]].Spec
@@ -2549,6 +2744,7 @@ func (dsp *DistSQLPlanner) planAggregators(
 			GroupCols:        groupCols,
 			OrderedGroupCols: orderedGroupCols,
 			OutputOrdering:   finalOutputOrdering,
+			GroupingSets:     groupingSets,
 		}
 	} else {
 		// Some aggregations might need multiple aggregation as part of
@@ -2771,6 +2967,23 @@ func (dsp *DistSQLPlanner) planAggregators(
 			GroupCols:        groupCols,
 			OrderedGroupCols: orderedGroupCols,
 			OutputOrdering:   execinfrapb.Ordering{Columns: ordCols},
+			GroupingSets:     groupingSets,
+		}
+		if groupingSets != nil {
+			// The local stage computes the grouping sets, and the final stage
+			// groups by the ordinal of the set in addition to the (partially
+			// NULL) grouping columns. The final stage outputs the ordinal after
+			// the aggregations.
+			setIdx := uint32(len(localAggs))
+			intermediateTypes = append(intermediateTypes, types.Int)
+			finalGroupCols = append(finalGroupCols, setIdx)
+			finalAggs = append(finalAggs, execinfrapb.AggregatorSpec_Aggregation{
+				Func:   execinfrapb.AnyNotNull,
+				ColIdx: []uint32{setIdx},
+			})
+			if needRender {
+				finalPreRenderTypes = append(finalPreRenderTypes, types.Int)
+			}
 		}
 
 		if planHashGroupJoin {
@@ -2856,8 +3069,22 @@ func (dsp *DistSQLPlanner) planAggregators(
 				}
 				finalIdx += len(info.FinalStage)
 			}
+			if groupingSets != nil {
+				setIdx, err := physicalplan.MakeExpression(
+					ctx, h.IndexedVar(len(finalAggs)-1), planCtx, nil, /* indexVarMap */
+				)
+				if err != nil {
+					return err
+				}
+				renderExprs = append(renderExprs, setIdx)
+			}
 			finalAggsPost.RenderExprs = renderExprs
-		} else if len(finalAggs) < len(info.aggregations) {
+		} else if groupingSets != nil && len(finalAggs)-1 < len(info.aggregations) {
+			// We have removed some duplicates, so we need to add a projection
+			// that also passes through the ordinal of the grouping set.
+			finalAggsPost.Projection = true
+			finalAggsPost.OutputColumns = append(finalIdxMap, uint32(len(finalAggs)-1))
+		} else if groupingSets == nil && len(finalAggs) < len(info.aggregations) {
 			// We have removed some duplicates, so we need to add a projection.
 			finalAggsPost.Projection = true
 			finalAggsPost.OutputColumns = finalIdxMap
@@ -2873,6 +3100,10 @@ func (dsp *DistSQLPlanner) planAggregators(
 			argTypes[j] = inputTypes[c]
 		}
 		copy(argTypes[len(agg.ColIdx):], info.argumentsColumnTypes[i])
+		if agg.UserDefined != nil {
+			finalOutTypes[i] = agg.UserDefined.ResultType
+			continue
+		}
 		var err error
 		_, returnTyp, err := execagg.GetAggregateInfo(agg.Func, argTypes...)
 		if err != nil {
@@ -2880,11 +3111,14 @@ func (dsp *DistSQLPlanner) planAggregators(
 		}
 		finalOutTypes[i] = returnTyp
 	}
+	if groupingSets != nil {
+		finalOutTypes = append(finalOutTypes, types.Int)
+	}
 
 	// Update p.PlanToStreamColMap; we will have a simple 1-to-1 mapping of
 	// planNode columns to stream columns because the aggregator
 	// has been programmed to produce the same columns as the groupNode.
-	p.PlanToStreamColMap = identityMap(p.PlanToStreamColMap, len(info.aggregations))
+	p.PlanToStreamColMap = identityMap(p.PlanToStreamColMap, len(finalOutTypes))
 
 	if planHashGroupJoin {
 		prevStageProc := p.Processors[p.ResultRouters[0]].Spec
@@ -2901,10 +3135,13 @@ func (dsp *DistSQLPlanner) planAggregators(
 			finalOutTypes,
 			dsp.convertOrdering(info.reqOrdering, p.PlanToStreamColMap),
 		)
-	} else if len(finalAggsSpec.GroupCols) == 0 || len(p.ResultRouters) == 1 {
-		// No GROUP BY, or we have a single stream. Use a single final aggregator.
-		// If the previous stage was all on a single node, put the final
-		// aggregator there. Otherwise, bring the results back on this node.
+	} else if len(finalAggsSpec.GroupCols) == 0 || len(p.ResultRouters) == 1 ||
+		finalAggsSpec.GroupingSets != nil {
+		// No GROUP BY, or we have a single stream, or the final aggregator
+		// computes the grouping sets and needs to see all input rows. Use a
+		// single final aggregator. If the previous stage was all on a single
+		// node, put the final aggregator there. Otherwise, bring the results
+		// back on this node.
 		node := dsp.gatewaySQLInstanceID
 		if prevStageNode != 0 {
 			node = prevStageNode
@@ -2284,6 +2284,10 @@ func (dsp *DistSQLPlanner) planAndRunPostquery(
 	postqueryResultWriter := &errOnlyResultWriter{}
 	postqueryRecv.resultWriter = postqueryResultWriter
 	postqueryRecv.batchWriter = postqueryResultWriter
+	// The rows produced by a postquery (e.g. the results of the trigger
+	// functions called by an AFTER trigger) are not needed. Checks never
+	// produce rows, since they return an error instead.
+	postqueryRecv.discardRows = true
 	finishedSetupFn, cleanup := getFinishedSetupFn(planner)
 	defer cleanup()
 	dsp.Run(ctx, postqueryPlanCtx, planner.txn, postqueryPhysPlan, postqueryRecv, evalCtx, finishedSetupFn)
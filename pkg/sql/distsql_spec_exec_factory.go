@@ -178,6 +178,9 @@ func (e *distSQLSpecExecFactory) ConstructScan(
 			},
 		)
 	}
+	if params.Sample.IsSampled() {
+		return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: TABLESAMPLE")
+	}
 
 	// Although we don't yet recommend distributing plans where soft limits
 	// propagate to scan nodes because we don't have infrastructure to only
@@ -497,6 +500,7 @@ func (e *distSQLSpecExecFactory) constructAggregators(
 	input exec.Node,
 	groupCols []exec.NodeColumnOrdinal,
 	groupColOrdering colinfo.ColumnOrdering,
+	groupingSets []exec.NodeColumnOrdinalSet,
 	aggregations []exec.AggInfo,
 	reqOrdering exec.OutputOrdering,
 	isScalar bool,
@@ -526,6 +530,11 @@ func (e *distSQLSpecExecFactory) constructAggregators(
 		i := len(groupCols) + j
 		spec := &aggregationSpecs[i]
 		agg := &aggregations[j]
+		if agg.UserDefined != nil {
+			return nil, unimplemented.NewWithIssue(
+				47473, "experimental opt-driven distsql planning: user-defined aggregate",
+			)
+		}
 		argumentsColumnTypes[i], err = populateAggFuncSpec(
 			e.ctx, spec, agg.FuncName, agg.Distinct, agg.ArgCols,
 			agg.ConstArgs, agg.Filter, planCtx, physPlan,
@@ -534,6 +543,13 @@ func (e *distSQLSpecExecFactory) constructAggregators(
 			return nil, err
 		}
 	}
+	var groupingSetIdxs [][]int
+	if groupingSets != nil {
+		groupingSetIdxs = make([][]int, len(groupingSets))
+		for i, set := range groupingSets {
+			groupingSetIdxs[i] = set.Ordered()
+		}
+	}
 	if err := e.dsp.planAggregators(
 		e.ctx,
 		planCtx,
@@ -544,13 +560,18 @@ func (e *distSQLSpecExecFactory) constructAggregators(
 			isScalar:             isScalar,
 			groupCols:            convertNodeOrdinalsToInts(groupCols),
 			groupColOrdering:     groupColOrdering,
+			groupingSets:         groupingSetIdxs,
 			inputMergeOrdering:   physPlan.MergeOrdering,
 			reqOrdering:          ReqOrdering(reqOrdering),
 		},
 	); err != nil {
 		return nil, err
 	}
-	physPlan.ResultColumns = getResultColumnsForGroupBy(physPlan.ResultColumns, groupCols, aggregations)
+	if groupingSets != nil {
+		physPlan.ResultColumns = getResultColumnsForGroupingSets(physPlan.ResultColumns, groupCols, aggregations)
+	} else {
+		physPlan.ResultColumns = getResultColumnsForGroupBy(physPlan.ResultColumns, groupCols, aggregations)
+	}
 	return plan, nil
 }
 
@@ -566,6 +587,7 @@ func (e *distSQLSpecExecFactory) ConstructGroupBy(
 		input,
 		groupCols,
 		groupColOrdering,
+		nil, /* groupingSets */
 		aggregations,
 		reqOrdering,
 		false, /* isScalar */
@@ -579,12 +601,30 @@ func (e *distSQLSpecExecFactory) ConstructScalarGroupBy(
 		input,
 		nil, /* groupCols */
 		nil, /* groupColOrdering */
+		nil, /* groupingSets */
 		aggregations,
 		exec.OutputOrdering{}, /* reqOrdering */
 		true,                  /* isScalar */
 	)
 }
 
+func (e *distSQLSpecExecFactory) ConstructGroupingSets(
+	input exec.Node,
+	groupCols []exec.NodeColumnOrdinal,
+	groupingSets []exec.NodeColumnOrdinalSet,
+	aggregations []exec.AggInfo,
+) (exec.Node, error) {
+	return e.constructAggregators(
+		input,
+		groupCols,
+		nil, /* groupColOrdering */
+		groupingSets,
+		aggregations,
+		exec.OutputOrdering{}, /* reqOrdering */
+		false,                 /* isScalar */
+	)
+}
+
 func (e *distSQLSpecExecFactory) ConstructDistinct(
 	input exec.Node,
 	distinctCols, orderedCols exec.NodeColumnOrdinalSet,
@@ -1002,6 +1042,7 @@ func (e *distSQLSpecExecFactory) ConstructUpsert(
 	arbiterIndexes cat.IndexOrdinals,
 	arbiterConstraints cat.UniqueOrdinals,
 	canaryCol exec.NodeColumnOrdinal,
+	mergeActionCol exec.NodeColumnOrdinal,
 	insertCols exec.TableColumnOrdinalSet,
 	fetchCols exec.TableColumnOrdinalSet,
 	updateCols exec.TableColumnOrdinalSet,
@@ -39,10 +39,14 @@ type dropFunctionNode struct {
 
 // DropFunction drops a function.
 func (p *planner) DropFunction(ctx context.Context, n *tree.DropRoutine) (ret planNode, err error) {
+	stmtLabel := "DROP FUNCTION"
+	if n.Aggregate {
+		stmtLabel = "DROP AGGREGATE"
+	}
 	if err := checkSchemaChangeEnabled(
 		ctx,
 		p.ExecCfg(),
-		"DROP FUNCTION",
+		stmtLabel,
 	); err != nil {
 		return nil, err
 	}
@@ -68,6 +72,11 @@ func (p *planner) DropFunction(ctx context.Context, n *tree.DropRoutine) (ret pl
 		if ol == nil {
 			continue
 		}
+		if err := checkAggregateKind(
+			ol, &fn, n.Aggregate, "Use DROP AGGREGATE to drop aggregate functions.",
+		); err != nil {
+			return nil, err
+		}
 		fnID := funcdesc.UserDefinedFunctionOIDToID(ol.Oid)
 		if fnResolved.Contains(int(fnID)) {
 			continue
@@ -141,7 +150,7 @@ func (p *planner) matchRoutine(
 		return nil, err
 	}
 
-	paramTypes, err := routineObj.ParamTypes(ctx, p)
+	paramTypes, err := routineObj.ParamTypes(ctx, p, routineType)
 	if err != nil {
 		return nil, err
 	}
@@ -163,6 +172,26 @@ func (p *planner) matchRoutine(
 	return &ol, nil
 }
 
+// checkAggregateKind returns an error if the resolved routine is an aggregate
+// and the statement does not target aggregates, or vice versa. The hint is
+// attached to the error in the former case.
+func checkAggregateKind(
+	ol *tree.QualifiedOverload, routineObj *tree.RoutineObj, aggregate bool, hint string,
+) error {
+	isAggregate := ol.Class == tree.AggregateClass
+	if isAggregate && !aggregate {
+		return errors.WithHint(
+			pgerror.Newf(pgcode.WrongObjectType, "%q is an aggregate function", routineObj.FuncName.Object()),
+			hint,
+		)
+	}
+	if !isAggregate && aggregate {
+		return pgerror.Newf(pgcode.WrongObjectType, "function %s%s is not an aggregate",
+			routineObj.FuncName.Object(), ol.Signature(true /* simplify */))
+	}
+	return nil
+}
+
 func (p *planner) checkPrivilegesForDropFunction(
 	ctx context.Context, fnID descpb.ID,
 ) (*funcdesc.Mutable, error) {
@@ -226,6 +255,41 @@ func (p *planner) dropFunctionImpl(ctx context.Context, fnMutable *funcdesc.Muta
 		}
 	}
 
+	// Remove backreferences from the support functions of an aggregate.
+	if fnMutable.IsAggregate() {
+		if err := p.removeAggregateFunctionReferences(ctx, fnMutable); err != nil {
+			return err
+		}
+	}
+
+	// Drop any triggers which execute this UDF. These can only remain at this
+	// point if the drop behavior is CASCADE.
+	for _, ref := range fnMutable.DependedOnBy {
+		if len(ref.TriggerIDs) == 0 {
+			continue
+		}
+		refMutable, err := p.Descriptors().MutableByID(p.txn).Table(ctx, ref.ID)
+		if err != nil {
+			return err
+		}
+		for _, triggerID := range ref.TriggerIDs {
+			for i := range refMutable.Triggers {
+				if refMutable.Triggers[i].ID == triggerID {
+					refMutable.Triggers = append(refMutable.Triggers[:i], refMutable.Triggers[i+1:]...)
+					break
+				}
+			}
+		}
+		if err := p.writeSchemaChange(
+			ctx, refMutable, descpb.InvalidMutationID,
+			fmt.Sprintf("dropping triggers of table %s(%d) which execute function %s(%d)",
+				refMutable.Name, refMutable.ID, fnMutable.Name, fnMutable.ID,
+			),
+		); err != nil {
+			return err
+		}
+	}
+
 	// Remove backreference from types referenced by this UDF.
 	jobDesc := fmt.Sprintf(
 		"updating type backreference %v for function %s(%d)",
@@ -0,0 +1,99 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+type dropPolicyNode struct {
+	n         *tree.DropPolicy
+	tableDesc *tabledesc.Mutable
+	policyID  descpb.PolicyID
+}
+
+// DropPolicy drops a row-level security policy from a table.
+// Privileges: ownership of the table.
+//
+//	notes: postgres requires ownership of the table.
+func (p *planner) DropPolicy(ctx context.Context, n *tree.DropPolicy) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP POLICY",
+	); err != nil {
+		return nil, err
+	}
+
+	_, tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &n.Table, !n.IfExists /* required */, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc == nil {
+		// IfExists specified and table did not exist -- noop.
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	policy := catalog.FindPolicyByName(tableDesc, string(n.Policy))
+	if policy == nil {
+		if n.IfExists {
+			// Noop.
+			return newZeroNode(nil /* columns */), nil
+		}
+		return nil, pgerror.Newf(pgcode.UndefinedObject,
+			"policy %q for table %q does not exist", n.Policy, tableDesc.GetName())
+	}
+
+	if err := p.checkCanAlterRowLevelSecurity(ctx, tableDesc); err != nil {
+		return nil, err
+	}
+
+	// Disallow schema changes if this table's schema is locked.
+	if err := checkTableSchemaUnlocked(tableDesc); err != nil {
+		return nil, err
+	}
+
+	return &dropPolicyNode{n: n, tableDesc: tableDesc, policyID: policy.ID}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because DROP POLICY performs multiple KV operations on descriptors
+// and expects to see its own writes.
+func (n *dropPolicyNode) ReadingOwnWrites() {}
+
+func (n *dropPolicyNode) startExec(params runParams) error {
+	p := params.p
+	ctx := params.ctx
+	tableDesc := n.tableDesc
+
+	// Nothing can depend on a policy, so CASCADE and RESTRICT are equivalent.
+	tableDesc.RemovePolicy(n.policyID)
+
+	if err := validateDescriptor(ctx, p, tableDesc); err != nil {
+		return err
+	}
+
+	return p.writeSchemaChange(
+		ctx, tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()))
+}
+
+func (n *dropPolicyNode) Next(runParams) (bool, error) { return false, nil }
+func (n *dropPolicyNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *dropPolicyNode) Close(context.Context)        {}
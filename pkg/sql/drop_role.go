@@ -80,6 +80,7 @@ type objectAndType struct {
 	ObjectName         string
 	IsDefaultPrivilege bool
 	IsGlobalPrivilege  bool
+	IsPolicyTarget     bool
 	ErrorMessage       error
 }
 
@@ -178,6 +179,26 @@ func (n *DropRoleNode) startExec(params runParams) error {
 					ObjectName: tn.String(),
 				})
 		}
+		// Roles named in a row-level security policy cannot be dropped until the
+		// policy is dropped or altered.
+		for _, policy := range tableDescriptor.GetPolicies() {
+			for _, roleName := range policy.RoleNames {
+				role := username.MakeSQLUsernameFromPreNormalizedString(roleName)
+				if _, ok := userNames[role]; !ok {
+					continue
+				}
+				tn, err := getTableNameFromTableDescriptor(lCtx, tableDescriptor, "")
+				if err != nil {
+					return err
+				}
+				userNames[role] = append(userNames[role], objectAndType{
+					IsPolicyTarget: true,
+					ErrorMessage: errors.Newf(
+						"target of policy %s on table %s", policy.Name, tn.String(),
+					),
+				})
+			}
+		}
 		for _, u := range tableDescriptor.GetPrivileges().Users {
 			if _, ok := userNames[u.User()]; ok {
 				if privilegeObjectFormatter.Len() > 0 {
@@ -334,7 +355,7 @@ func (n *DropRoleNode) startExec(params runParams) error {
 					hasDependentDefaultPrivilege = true
 					objectsMsg.WriteString(fmt.Sprintf("\n%s", obj.ErrorMessage))
 					hints = append(hints, errors.GetAllHints(obj.ErrorMessage)...)
-				} else if obj.IsGlobalPrivilege {
+				} else if obj.IsGlobalPrivilege || obj.IsPolicyTarget {
 					objectsMsg.WriteString(fmt.Sprintf("\n%s", obj.ErrorMessage))
 				} else {
 					objectsMsg.WriteString(fmt.Sprintf("\nowner of %s %s", obj.ObjectType, obj.ObjectName))
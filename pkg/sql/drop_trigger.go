@@ -0,0 +1,115 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+type dropTriggerNode struct {
+	n         *tree.DropTrigger
+	tableDesc *tabledesc.Mutable
+	triggerID descpb.TriggerID
+}
+
+// DropTrigger drops a trigger from a table.
+// Privileges: CREATE on table.
+//
+//	notes: postgres requires ownership of the table.
+func (p *planner) DropTrigger(ctx context.Context, n *tree.DropTrigger) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP TRIGGER",
+	); err != nil {
+		return nil, err
+	}
+
+	_, tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &n.Table, !n.IfExists /* required */, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc == nil {
+		// IfExists specified and table did not exist -- noop.
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	trigger := catalog.FindTriggerByName(tableDesc, string(n.Trigger))
+	if trigger == nil {
+		if n.IfExists {
+			// Noop.
+			return newZeroNode(nil /* columns */), nil
+		}
+		return nil, pgerror.Newf(pgcode.UndefinedObject,
+			"trigger %q for table %q does not exist", n.Trigger, tableDesc.GetName())
+	}
+
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	// Disallow schema changes if this table's schema is locked.
+	if err := checkTableSchemaUnlocked(tableDesc); err != nil {
+		return nil, err
+	}
+
+	return &dropTriggerNode{n: n, tableDesc: tableDesc, triggerID: trigger.ID}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because DROP TRIGGER performs multiple KV operations on descriptors
+// and expects to see its own writes.
+func (n *dropTriggerNode) ReadingOwnWrites() {}
+
+func (n *dropTriggerNode) startExec(params runParams) error {
+	p := params.p
+	ctx := params.ctx
+	tableDesc := n.tableDesc
+
+	// Nothing can depend on a trigger, so CASCADE and RESTRICT are equivalent.
+	for i := range tableDesc.Triggers {
+		trigger := &tableDesc.Triggers[i]
+		if trigger.ID != n.triggerID {
+			continue
+		}
+		fnDesc, err := p.Descriptors().MutableByID(p.Txn()).Function(ctx, trigger.FuncID)
+		if err != nil {
+			return err
+		}
+		fnDesc.RemoveTriggerReference(tableDesc.GetID(), trigger.ID)
+		if err := p.writeFuncSchemaChange(ctx, fnDesc); err != nil {
+			return err
+		}
+		tableDesc.Triggers = append(tableDesc.Triggers[:i], tableDesc.Triggers[i+1:]...)
+		break
+	}
+
+	if err := validateDescriptor(ctx, p, tableDesc); err != nil {
+		return err
+	}
+
+	return p.writeSchemaChange(
+		ctx, tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()))
+}
+
+func (n *dropTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (n *dropTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *dropTriggerNode) Close(context.Context)        {}
@@ -30,32 +30,49 @@ import (
 )
 
 type dropTypeNode struct {
-	n      *tree.DropType
 	toDrop map[descpb.ID]*typedesc.Mutable
 }
 
 // Use to satisfy the linter.
-var _ planNode = &dropTypeNode{n: nil}
+var _ planNode = &dropTypeNode{toDrop: nil}
 
 func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, error) {
+	return p.dropTypes(ctx, "DROP TYPE", n.Names, n.IfExists, n.DropBehavior, false /* domains */)
+}
+
+// DropDomain drops one or more domains.
+// Privileges: ownership of the domains.
+func (p *planner) DropDomain(ctx context.Context, n *tree.DropDomain) (planNode, error) {
+	return p.dropTypes(ctx, "DROP DOMAIN", n.Names, n.IfExists, n.DropBehavior, true /* domains */)
+}
+
+// dropTypes plans a DROP TYPE statement, or a DROP DOMAIN statement if
+// domains is true.
+func (p *planner) dropTypes(
+	ctx context.Context,
+	stmt string,
+	names []*tree.UnresolvedObjectName,
+	ifExists bool,
+	behavior tree.DropBehavior,
+	domains bool,
+) (planNode, error) {
 	if err := checkSchemaChangeEnabled(
 		ctx,
 		p.ExecCfg(),
-		"DROP TYPE",
+		stmt,
 	); err != nil {
 		return nil, err
 	}
 
 	node := &dropTypeNode{
-		n:      n,
 		toDrop: make(map[descpb.ID]*typedesc.Mutable),
 	}
-	if n.DropBehavior == tree.DropCascade {
-		return nil, unimplemented.NewWithIssue(51480, "DROP TYPE CASCADE is not yet supported")
+	if behavior == tree.DropCascade {
+		return nil, unimplemented.NewWithIssuef(51480, "%s CASCADE is not yet supported", stmt)
 	}
-	for _, name := range n.Names {
+	for _, name := range names {
 		// Resolve the desired type descriptor.
-		_, typeDesc, err := p.ResolveMutableTypeDescriptor(ctx, name, !n.IfExists)
+		_, typeDesc, err := p.ResolveMutableTypeDescriptor(ctx, name, !ifExists)
 		if err != nil {
 			return nil, err
 		}
@@ -66,6 +83,11 @@ func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, err
 		if _, ok := node.toDrop[typeDesc.ID]; ok {
 			continue
 		}
+		// As in Postgres, DROP TYPE can drop domains, but DROP DOMAIN can only
+		// drop domains.
+		if domains && typeDesc.Kind != descpb.TypeDescriptor_DOMAIN {
+			return nil, pgerror.Newf(pgcode.WrongObjectType, "%q is not a domain", name)
+		}
 		switch typeDesc.Kind {
 		case descpb.TypeDescriptor_ALIAS:
 			// The implicit array types are not directly droppable.
@@ -94,21 +116,25 @@ func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, err
 		}
 
 		// Check if we can drop the type.
-		if err := p.canDropTypeDesc(ctx, typeDesc, n.DropBehavior); err != nil {
+		if err := p.canDropTypeDesc(ctx, typeDesc, behavior); err != nil {
 			return nil, err
 		}
+		// Record this descriptor for deletion.
+		node.toDrop[typeDesc.ID] = typeDesc
 
+		// Domains don't have array types.
+		if typeDesc.ArrayTypeID == descpb.InvalidID {
+			continue
+		}
 		// Get the array type that needs to be dropped as well.
 		mutArrayDesc, err := p.Descriptors().MutableByID(p.txn).Type(ctx, typeDesc.ArrayTypeID)
 		if err != nil {
 			return nil, err
 		}
 		// Ensure that we can drop the array type as well.
-		if err := p.canDropTypeDesc(ctx, mutArrayDesc, n.DropBehavior); err != nil {
+		if err := p.canDropTypeDesc(ctx, mutArrayDesc, behavior); err != nil {
 			return nil, err
 		}
-		// Record these descriptors for deletion.
-		node.toDrop[typeDesc.ID] = typeDesc
 		node.toDrop[mutArrayDesc.ID] = mutArrayDesc
 	}
 	return node, nil
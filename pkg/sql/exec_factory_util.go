@@ -208,6 +208,17 @@ func getResultColumnsForGroupBy(
 	return columns
 }
 
+// getResultColumnsForGroupingSets returns the result columns of an aggregation
+// that computes grouping sets: the grouping columns, which are NULL when they
+// are not part of a row's grouping set, the aggregations, and the ordinal of
+// the grouping set.
+func getResultColumnsForGroupingSets(
+	inputCols colinfo.ResultColumns, groupCols []exec.NodeColumnOrdinal, aggregations []exec.AggInfo,
+) colinfo.ResultColumns {
+	columns := getResultColumnsForGroupBy(inputCols, groupCols, aggregations)
+	return append(columns, colinfo.ResultColumn{Name: "grouping_set", Typ: types.Int})
+}
+
 // convertNodeOrdinalsToInts converts a slice of exec.NodeColumnOrdinals to a slice
 // of ints.
 func convertNodeOrdinalsToInts(ordinals []exec.NodeColumnOrdinal) []int {
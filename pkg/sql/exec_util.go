@@ -1277,6 +1277,18 @@ type ExecutorConfig struct {
 	StatsRefresher     *stats.Refresher
 	QueryCache         *querycache.C
 
+	// NotificationRegistry keeps track of the sessions on this node listening
+	// on notification channels (see LISTEN).
+	NotificationRegistry *NotificationRegistry
+
+	// AdvisoryLockRegistry keeps track of the sessions on this node holding
+	// advisory locks (see pg_advisory_lock()).
+	AdvisoryLockRegistry *AdvisoryLockRegistry
+
+	// ReplicationSlotRegistry keeps track of the sessions on this node
+	// streaming changes from replication slots (see START_REPLICATION).
+	ReplicationSlotRegistry *ReplicationSlotRegistry
+
 	SchemaChangerMetrics *SchemaChangerMetrics
 	FeatureFlagMetrics   *featureflag.DenialMetrics
 	RowMetrics           *rowinfra.Metrics
@@ -2021,6 +2033,9 @@ func checkResultType(typ *types.T) error {
 	case types.TSVectorFamily:
 	case types.IntervalFamily:
 	case types.JsonFamily:
+	case types.JsonpathFamily:
+	case types.RangeFamily:
+	case types.MultirangeFamily:
 	case types.UuidFamily:
 	case types.INetFamily:
 	case types.OidFamily:
@@ -3252,6 +3267,16 @@ func (m *sessionDataMutator) SetOptimizerMergeJoinsEnabled(val bool) {
 	m.data.OptimizerMergeJoinsEnabled = val
 }
 
+func (m *sessionDataMutator) SetConstraintsMode(val sessiondatapb.ConstraintsMode) {
+	m.data.ConstraintsMode = val
+}
+
+func (m *sessionDataMutator) SetConstraintsModeOverrides(
+	val []sessiondatapb.ConstraintsModeOverride,
+) {
+	m.data.ConstraintsModeOverrides = val
+}
+
 func (m *sessionDataMutator) SetLocalityOptimizedSearch(val bool) {
 	m.data.LocalityOptimizedSearch = val
 }
@@ -81,6 +81,10 @@ func GetAggregateConstructor(
 	aggInfo *execinfrapb.AggregatorSpec_Aggregation,
 	inputTypes []*types.T,
 ) (constructor AggregateConstructor, arguments tree.Datums, outputType *types.T, err error) {
+	if aggInfo.UserDefined != nil {
+		constructor, outputType, err = getUserDefinedAggregateInfo(aggInfo.UserDefined)
+		return
+	}
 	argTypes := make([]*types.T, len(aggInfo.ColIdx)+len(aggInfo.Arguments))
 	for j, c := range aggInfo.ColIdx {
 		if c >= uint32(len(inputTypes)) {
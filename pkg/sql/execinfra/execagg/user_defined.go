@@ -0,0 +1,226 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package execagg
+
+import (
+	"context"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// GetUserDefinedAggregateRoutines extracts the routines of a user-defined
+// aggregate from its specification. The routines are only available in local
+// plans.
+func GetUserDefinedAggregateRoutines(
+	spec *execinfrapb.AggregatorSpec_UserDefinedAggregate,
+) (*tree.AggregateRoutines, error) {
+	transition, ok := spec.Transition.LocalExpr.(*tree.RoutineExpr)
+	if !ok {
+		return nil, errors.AssertionFailedf(
+			"expected local transition routine for user-defined aggregate %s", spec.Name,
+		)
+	}
+	routines := &tree.AggregateRoutines{
+		Name:       spec.Name,
+		Transition: transition,
+		StateType:  spec.StateType,
+	}
+	if !spec.Final.Empty() {
+		if routines.Final, ok = spec.Final.LocalExpr.(*tree.RoutineExpr); !ok {
+			return nil, errors.AssertionFailedf(
+				"expected local final routine for user-defined aggregate %s", spec.Name,
+			)
+		}
+	}
+	if !spec.InitCond.Empty() {
+		if routines.InitCond, ok = spec.InitCond.LocalExpr.(tree.Datum); !ok {
+			return nil, errors.AssertionFailedf(
+				"expected initial state for user-defined aggregate %s", spec.Name,
+			)
+		}
+	}
+	return routines, nil
+}
+
+// getUserDefinedAggregateInfo returns the aggregate constructor and the return
+// type of the given user-defined aggregate.
+func getUserDefinedAggregateInfo(
+	spec *execinfrapb.AggregatorSpec_UserDefinedAggregate,
+) (AggregateConstructor, *types.T, error) {
+	routines, err := GetUserDefinedAggregateRoutines(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	constructAgg := func(evalCtx *eval.Context, _ tree.Datums) eval.AggregateFunc {
+		return NewUserDefinedAggregate(evalCtx, routines)
+	}
+	return constructAgg, spec.ResultType, nil
+}
+
+// GetUserDefinedWindowFunctionInfo returns the window function constructor and
+// the return type of the given user-defined aggregate used as a window
+// function.
+func GetUserDefinedWindowFunctionInfo(
+	spec *execinfrapb.AggregatorSpec_UserDefinedAggregate,
+) (func(*eval.Context) eval.WindowFunc, *types.T, error) {
+	routines, err := GetUserDefinedAggregateRoutines(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	constructAgg := func(evalCtx *eval.Context, _ tree.Datums) eval.AggregateFunc {
+		return NewUserDefinedAggregate(evalCtx, routines)
+	}
+	return builtins.NewFramableAggregateWindowFunc(constructAgg), spec.ResultType, nil
+}
+
+// userDefinedAggregate evaluates a user-defined aggregate by invoking its
+// transition function for each input row, and its final function, if any, on
+// the resulting state. Like in Postgres, a strict transition function is not
+// invoked for rows with NULL arguments, and if the aggregate has no initial
+// state, the first row with non-NULL arguments becomes the state.
+type userDefinedAggregate struct {
+	evalCtx  *eval.Context
+	routines *tree.AggregateRoutines
+
+	// state is the current state of the aggregate.
+	state tree.Datum
+	// noState is true if the aggregate has no initial state and no rows have
+	// been added.
+	noState bool
+	// args is reused to pass the arguments to the transition function.
+	args tree.Datums
+	// ctx is the context of the last call to Add, which is used to evaluate
+	// the final function.
+	ctx context.Context
+
+	// acc accounts for the memory used by the state. If the evaluation
+	// context has a SingleDatumAggMemAccount, acc is shared with the other
+	// aggregates and only the accountedFor bytes are released by this
+	// aggregate. Otherwise, acc is owned by this aggregate.
+	acc          *mon.BoundAccount
+	sharedAcc    bool
+	accountedFor int64
+}
+
+var _ eval.AggregateFunc = &userDefinedAggregate{}
+
+// NewUserDefinedAggregate returns an aggregate function that evaluates a
+// user-defined aggregate with the given routines.
+func NewUserDefinedAggregate(
+	evalCtx *eval.Context, routines *tree.AggregateRoutines,
+) eval.AggregateFunc {
+	a := &userDefinedAggregate{evalCtx: evalCtx, routines: routines}
+	if evalCtx.SingleDatumAggMemAccount != nil {
+		a.acc = evalCtx.SingleDatumAggMemAccount
+		a.sharedAcc = true
+	} else {
+		acc := evalCtx.Planner.Mon().MakeBoundAccount()
+		a.acc = &acc
+	}
+	a.Reset(context.Background())
+	return a
+}
+
+// setState sets the state of the aggregate and updates the memory account to
+// reflect its size.
+func (a *userDefinedAggregate) setState(ctx context.Context, state tree.Datum) error {
+	size := int64(state.Size())
+	if err := a.acc.Grow(ctx, size-a.accountedFor); err != nil {
+		return err
+	}
+	a.accountedFor = size
+	a.state = state
+	return nil
+}
+
+// releaseMemory releases the memory accounted for the state.
+func (a *userDefinedAggregate) releaseMemory(ctx context.Context) {
+	a.acc.Shrink(ctx, a.accountedFor)
+	a.accountedFor = 0
+}
+
+// Add is part of the eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Add(
+	ctx context.Context, firstArg tree.Datum, otherArgs ...tree.Datum,
+) error {
+	a.ctx = ctx
+	a.args = append(a.args[:0], a.state, firstArg)
+	a.args = append(a.args, otherArgs...)
+	if !a.routines.Transition.CalledOnNullInput {
+		for _, arg := range a.args[1:] {
+			if arg == tree.DNull {
+				return nil
+			}
+		}
+		if a.noState {
+			a.noState = false
+			return a.setState(ctx, firstArg)
+		}
+		if a.state == tree.DNull {
+			return nil
+		}
+	}
+	state, err := a.evalCtx.Planner.EvalRoutineExpr(ctx, a.routines.Transition, a.args)
+	if err != nil {
+		return err
+	}
+	a.noState = false
+	return a.setState(ctx, state)
+}
+
+// Result is part of the eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Result() (tree.Datum, error) {
+	if a.routines.Final == nil {
+		return a.state, nil
+	}
+	ctx := a.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return a.evalCtx.Planner.EvalRoutineExpr(ctx, a.routines.Final, tree.Datums{a.state})
+}
+
+// Reset is part of the eval.AggregateFunc interface. The initial state is
+// shared by all groups, so it is not accounted for.
+func (a *userDefinedAggregate) Reset(ctx context.Context) {
+	a.releaseMemory(ctx)
+	a.state = tree.DNull
+	a.noState = true
+	if a.routines.InitCond != nil {
+		a.state = a.routines.InitCond
+		a.noState = false
+	}
+}
+
+// Close is part of the eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Close(ctx context.Context) {
+	if a.sharedAcc {
+		a.releaseMemory(ctx)
+	} else {
+		a.acc.Close(ctx)
+		a.accountedFor = 0
+	}
+}
+
+// Size is part of the eval.AggregateFunc interface. The memory used by the
+// state is accounted for separately, as it changes with each transition.
+func (a *userDefinedAggregate) Size() int64 {
+	return sizeOfUserDefinedAggregate
+}
+
+var sizeOfUserDefinedAggregate = int64(unsafe.Sizeof(userDefinedAggregate{}))
@@ -0,0 +1,94 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package execagg
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/faketreeeval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/stretchr/testify/require"
+)
+
+// concatPlanner evaluates every routine as the concatenation of its two
+// string arguments.
+type concatPlanner struct {
+	faketreeeval.DummyEvalPlanner
+}
+
+func (*concatPlanner) EvalRoutineExpr(
+	_ context.Context, _ *tree.RoutineExpr, args tree.Datums,
+) (tree.Datum, error) {
+	return tree.NewDString(string(tree.MustBeDString(args[0])) + string(tree.MustBeDString(args[1]))), nil
+}
+
+func TestUserDefinedAggregateMemoryAccounting(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	const limit = 64 << 10
+	monitor := mon.NewMonitorWithLimit(
+		"test-mem",
+		mon.MemoryResource,
+		limit,
+		nil,           /* curCount */
+		nil,           /* maxHist */
+		-1,            /* increment */
+		math.MaxInt64, /* noteworthy */
+		st,
+	)
+	monitor.Start(ctx, nil, mon.NewStandaloneBudget(math.MaxInt64))
+	defer monitor.Stop(ctx)
+	acc := monitor.MakeBoundAccount()
+	defer acc.Close(ctx)
+
+	evalCtx := eval.NewTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+	evalCtx.Planner = &concatPlanner{}
+	evalCtx.SingleDatumAggMemAccount = &acc
+
+	routines := &tree.AggregateRoutines{
+		Name:       "my_concat",
+		Transition: &tree.RoutineExpr{CalledOnNullInput: true},
+		InitCond:   tree.NewDString(""),
+	}
+	agg := NewUserDefinedAggregate(evalCtx, routines)
+	defer agg.Close(ctx)
+
+	arg := tree.NewDString(strings.Repeat("a", 1<<10))
+	for i := 0; i < 8; i++ {
+		require.NoError(t, agg.Add(ctx, arg))
+	}
+	// The state is accounted for, but not the states it replaced.
+	require.GreaterOrEqual(t, acc.Used(), int64(8<<10))
+	require.Less(t, acc.Used(), int64(16<<10))
+
+	agg.Reset(ctx)
+	require.Zero(t, acc.Used())
+
+	// The state eventually exceeds the limit of the monitor.
+	var err error
+	for i := 0; i < 2*limit>>10 && err == nil; i++ {
+		err = agg.Add(ctx, arg)
+	}
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "memory budget exceeded"), err)
+}
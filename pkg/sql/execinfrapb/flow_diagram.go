@@ -108,6 +108,13 @@ func (a *AggregatorSpec) summary() (string, []string) {
 	if len(a.OrderedGroupCols) > 0 {
 		details = append(details, fmt.Sprintf("Ordered: %s", colListStr(a.OrderedGroupCols)))
 	}
+	if len(a.GroupingSets) > 0 {
+		sets := make([]string, len(a.GroupingSets))
+		for i := range a.GroupingSets {
+			sets[i] = "(" + colListStr(a.GroupingSets[i].Cols) + ")"
+		}
+		details = append(details, fmt.Sprintf("Grouping sets: %s", strings.Join(sets, ", ")))
+	}
 	for _, agg := range a.Aggregations {
 		var buf bytes.Buffer
 		buf.WriteString(agg.Func.String())
@@ -42,6 +42,10 @@ func (a AggregatorSpec_Aggregation) Equals(b AggregatorSpec_Aggregation) bool {
 	if a.Func != b.Func || a.Distinct != b.Distinct {
 		return false
 	}
+	// User-defined aggregates are only equal if they are the same aggregation.
+	if a.UserDefined != b.UserDefined {
+		return false
+	}
 	if a.FilterColIdx == nil {
 		if b.FilterColIdx != nil {
 			return false
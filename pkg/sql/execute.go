@@ -47,7 +47,7 @@ func (p *planner) fillInPlaceholders(
 		// the latest changes to the type.
 		if typ.UserDefined() {
 			var err error
-			typ, err = p.ResolveTypeByOID(ctx, typ.Oid())
+			typ, err = p.ResolveTypeByOID(ctx, typ.UserDefinedTypeOID())
 			if err != nil {
 				return nil, err
 			}
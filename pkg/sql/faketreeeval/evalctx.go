@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -34,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/rangedesc"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
 )
@@ -168,6 +170,9 @@ func (smf *DummyStreamManagerFactory) GetStreamIngestManager(
 // errors.
 type DummyEvalPlanner struct {
 	Monitor *mon.BytesMonitor
+	// TextSearch, if set, resolves the text search configurations and
+	// dictionaries that are not built in.
+	TextSearch *dbdesc.TextSearchResolver
 }
 
 // ResolveOIDFromString is part of the Planner interface.
@@ -350,6 +355,55 @@ func (*DummyEvalPlanner) PLpgSQLFetchCursor(
 	return nil, errors.WithStack(errEvalPlanner)
 }
 
+// Notify is part of the Planner interface.
+func (*DummyEvalPlanner) Notify(ctx context.Context, channel, payload string) error {
+	return errors.WithStack(errEvalPlanner)
+}
+
+// ListeningChannels is part of the Planner interface.
+func (*DummyEvalPlanner) ListeningChannels() []string {
+	return nil
+}
+
+// AcquireAdvisoryLock is part of the Planner interface.
+func (*DummyEvalPlanner) AcquireAdvisoryLock(
+	context.Context, eval.AdvisoryLockKey, eval.AdvisoryLockMode, bool, bool,
+) (bool, error) {
+	return false, errors.WithStack(errEvalPlanner)
+}
+
+// ReleaseAdvisoryLock is part of the Planner interface.
+func (*DummyEvalPlanner) ReleaseAdvisoryLock(
+	context.Context, eval.AdvisoryLockKey, eval.AdvisoryLockMode,
+) (bool, error) {
+	return false, errors.WithStack(errEvalPlanner)
+}
+
+// ReleaseAllAdvisoryLocks is part of the Planner interface.
+func (*DummyEvalPlanner) ReleaseAllAdvisoryLocks(context.Context) error {
+	return errors.WithStack(errEvalPlanner)
+}
+
+// ResolveTextSearchConfig is part of the Planner interface.
+func (ep *DummyEvalPlanner) ResolveTextSearchConfig(
+	ctx context.Context, name string,
+) (*tsearch.Config, error) {
+	if ep.TextSearch != nil {
+		return ep.TextSearch.Config(ctx, name)
+	}
+	return nil, errors.WithStack(errEvalPlanner)
+}
+
+// ResolveTextSearchDictionary is part of the Planner interface.
+func (ep *DummyEvalPlanner) ResolveTextSearchDictionary(
+	_ context.Context, name string,
+) (*tsearch.Dictionary, error) {
+	if ep.TextSearch != nil {
+		return ep.TextSearch.Dictionary(name)
+	}
+	return nil, errors.WithStack(errEvalPlanner)
+}
+
 var _ eval.Planner = &DummyEvalPlanner{}
 
 var errEvalPlanner = pgerror.New(pgcode.ScalarOperationCannotRunWithoutFullSessionContext,
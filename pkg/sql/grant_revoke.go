@@ -14,6 +14,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
@@ -52,6 +53,9 @@ func (p *planner) Grant(ctx context.Context, n *tree.Grant) (planNode, error) {
 	if err := privilege.ValidatePrivileges(n.Privileges, grantOn); err != nil {
 		return nil, err
 	}
+	if err := p.checkTriggerPrivilegeVersion(ctx, n.Privileges); err != nil {
+		return nil, err
+	}
 
 	grantees, err := decodeusername.FromRoleSpecList(
 		p.SessionData(), username.PurposeValidation, n.Grantees,
@@ -111,6 +115,9 @@ func (p *planner) Revoke(ctx context.Context, n *tree.Revoke) (planNode, error)
 	if err := privilege.ValidatePrivileges(n.Privileges, grantOn); err != nil {
 		return nil, err
 	}
+	if err := p.checkTriggerPrivilegeVersion(ctx, n.Privileges); err != nil {
+		return nil, err
+	}
 
 	grantees, err := decodeusername.FromRoleSpecList(
 		p.SessionData(), username.PurposeValidation, n.Grantees,
@@ -152,6 +159,13 @@ func (p *planner) Revoke(ctx context.Context, n *tree.Revoke) (planNode, error)
 			if err := privDesc.Revoke(grantee, privileges, grantOn, n.GrantOptionFor); err != nil {
 				return false, err
 			}
+			if grantOn == privilege.Table && !p.triggerPrivilegeActive(ctx) {
+				// Revoking from a user that holds ALL expands ALL into the
+				// individual privileges, which now include TRIGGER. Drop it again
+				// so that nodes running older binaries can still validate the
+				// descriptor.
+				privDesc.RemoveKind(privilege.TRIGGER)
+			}
 			granteePrivs, ok = privDesc.FindUser(grantee)
 			// Revoke results in any privilege changes if
 			//   1. grantee's entry is removed from the privilege descriptor, or
@@ -162,6 +176,25 @@ func (p *planner) Revoke(ctx context.Context, n *tree.Revoke) (planNode, error)
 	}, nil
 }
 
+// triggerPrivilegeActive returns whether the TRIGGER privilege can be stored
+// in descriptors.
+func (p *planner) triggerPrivilegeActive(ctx context.Context) bool {
+	return p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_1_TriggerPrivilege)
+}
+
+// checkTriggerPrivilegeVersion returns an error if privileges contains the
+// TRIGGER privilege before the cluster version allows it to be stored.
+func (p *planner) checkTriggerPrivilegeVersion(
+	ctx context.Context, privileges privilege.List,
+) error {
+	if privileges.Contains(privilege.TRIGGER) && !p.triggerPrivilegeActive(ctx) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use the TRIGGER privilege",
+			clusterversion.V24_1_TriggerPrivilege.Version())
+	}
+	return nil
+}
+
 type changePrivilegesNode struct {
 	isGrant         bool
 	withGrantOption bool
@@ -33,6 +33,12 @@ type groupNode struct {
 	// column indices in groupCols can appear in this ordering.
 	groupColOrdering colinfo.ColumnOrdering
 
+	// groupingSets is set if the node computes the grouping sets of a GROUPING
+	// SETS, ROLLUP or CUBE clause. Each grouping set contains the indices of a
+	// subset of the groupCols. The last column of the node is the ordinal of
+	// the grouping set of each row.
+	groupingSets [][]int
+
 	// isScalar is set for "scalar groupby", where we want a result
 	// even if there are no input rows, e.g. SELECT MIN(x) FROM t.
 	isScalar bool
@@ -74,6 +80,9 @@ type aggregateFuncHolder struct {
 	arguments tree.Datums
 	// isDistinct indicates whether only distinct values are aggregated.
 	isDistinct bool
+	// userDefined is set if the function is a user-defined aggregate, which is
+	// evaluated by invoking its routines.
+	userDefined *tree.AggregateRoutines
 }
 
 // newAggregateFuncHolder creates an aggregateFuncHolder.
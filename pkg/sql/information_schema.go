@@ -1464,23 +1464,33 @@ https://www.postgresql.org/docs/9.5/infoschema-table-constraints.html`,
 
 				for _, c := range table.AllConstraints() {
 					kind := catconstants.ConstraintTypeUnique
-					if c.AsCheck() != nil {
+					var deferrability semenumpb.Deferrability
+					if c.AsExclusion() != nil {
+						// Like in Postgres, exclusion constraints are not part of the
+						// SQL standard and are not shown here.
+						continue
+					} else if c.AsCheck() != nil {
 						kind = catconstants.ConstraintTypeCheck
-					} else if c.AsForeignKey() != nil {
+					} else if fk := c.AsForeignKey(); fk != nil {
 						kind = catconstants.ConstraintTypeFK
+						deferrability = fk.Deferrability()
 					} else if u := c.AsUniqueWithIndex(); u != nil && u.Primary() {
 						kind = catconstants.ConstraintTypePK
+					} else if uwoi := c.AsUniqueWithoutIndex(); uwoi != nil {
+						deferrability = uwoi.Deferrability()
 					}
+					isDeferrable := deferrability != semenumpb.Deferrability_NOT_DEFERRABLE
+					initiallyDeferred := deferrability == semenumpb.Deferrability_INITIALLY_DEFERRED
 					if err := addRow(
-						dbNameStr,                     // constraint_catalog
-						scNameStr,                     // constraint_schema
-						tree.NewDString(c.GetName()),  // constraint_name
-						dbNameStr,                     // table_catalog
-						scNameStr,                     // table_schema
-						tbNameStr,                     // table_name
-						tree.NewDString(string(kind)), // constraint_type
-						yesOrNoDatum(false),           // is_deferrable
-						yesOrNoDatum(false),           // initially_deferred
+						dbNameStr,                       // constraint_catalog
+						scNameStr,                       // constraint_schema
+						tree.NewDString(c.GetName()),    // constraint_name
+						dbNameStr,                       // table_catalog
+						scNameStr,                       // table_schema
+						tbNameStr,                       // table_name
+						tree.NewDString(string(kind)),   // constraint_type
+						yesOrNoDatum(isDeferrable),      // is_deferrable
+						yesOrNoDatum(initiallyDeferred), // initially_deferred
 					); err != nil {
 						return err
 					}
@@ -2901,6 +2911,11 @@ func (r roleOptions) createRole() (tree.DBool, error) {
 	return tree.DBool(createRole), err
 }
 
+func (r roleOptions) bypassRLS() (tree.DBool, error) {
+	bypassRLS, err := r.Exists("BYPASSRLS")
+	return tree.DBool(bypassRLS), err
+}
+
 func forEachRoleQuery(ctx context.Context, p *planner) string {
 	return `
 SELECT
@@ -267,7 +267,16 @@ func (n *insertNode) startExec(params runParams) error {
 
 	n.run.initRowContainer(params, n.columns)
 
-	return n.run.ti.init(params.ctx, params.p.txn, params.EvalContext(), &params.EvalContext().Settings.SV)
+	if err := n.run.ti.init(params.ctx, params.p.txn, params.EvalContext(), &params.EvalContext().Settings.SV); err != nil {
+		return err
+	}
+	if rec, err := params.p.deferConstraintChecks(n.run.ti.tableDesc(), deferInsertChecks); err != nil {
+		return err
+	} else if rec != nil {
+		n.run.ti.autoCommit = autoCommitDisabled
+		n.run.ti.deferredChecks = rec
+	}
+	return nil
 }
 
 // Next is required because batchedPlanNode inherits from planNode, but
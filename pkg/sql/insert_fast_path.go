@@ -431,7 +431,16 @@ func (n *insertFastPathNode) startExec(params runParams) error {
 		n.run.uniqSpanInfo = make([]insertFastPathFKUniqSpanInfo, 0, maxSpans)
 	}
 
-	return n.run.ti.init(params.ctx, params.p.txn, params.EvalContext(), &params.EvalContext().Settings.SV)
+	if err := n.run.ti.init(params.ctx, params.p.txn, params.EvalContext(), &params.EvalContext().Settings.SV); err != nil {
+		return err
+	}
+	if rec, err := params.p.deferConstraintChecks(n.run.ti.tableDesc(), deferInsertChecks); err != nil {
+		return err
+	} else if rec != nil {
+		n.run.ti.autoCommit = autoCommitDisabled
+		n.run.ti.deferredChecks = rec
+	}
+	return nil
 }
 
 // Next is required because batchedPlanNode inherits from planNode, but
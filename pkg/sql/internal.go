@@ -1485,11 +1485,31 @@ func (icc *internalClientComm) CreateCopyOutResult(cmd CopyOut, pos CmdPos) Copy
 	panic("unimplemented")
 }
 
+// CreateReplicationResult is part of the ClientComm interface.
+func (icc *internalClientComm) CreateReplicationResult(
+	cmd StartReplication, pos CmdPos,
+) ReplicationResult {
+	panic("unimplemented")
+}
+
 // CreateDrainResult is part of the ClientComm interface.
 func (icc *internalClientComm) CreateDrainResult(pos CmdPos) DrainResult {
 	panic("unimplemented")
 }
 
+// CreateDeliverNotificationsResult is part of the ClientComm interface.
+func (icc *internalClientComm) CreateDeliverNotificationsResult(
+	pos CmdPos,
+) DeliverNotificationsResult {
+	panic("unimplemented")
+}
+
+// SendNotification is part of the ClientComm interface.
+func (icc *internalClientComm) SendNotification(
+	ctx context.Context, senderPID uint32, channel, payload string,
+) {
+}
+
 // Close is part of the ClientLock interface.
 func (icc *internalClientComm) Close() {}
 
@@ -547,6 +547,13 @@ func TestLogic_default(
 	runLogicTest(t, "default")
 }
 
+func TestLogic_deferrable_constraints_mixed(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "deferrable_constraints_mixed")
+}
+
 func TestLogic_delete(
 	t *testing.T,
 ) {
@@ -631,6 +638,13 @@ func TestLogic_distsql_srfs(
 	runLogicTest(t, "distsql_srfs")
 }
 
+func TestLogic_domain_mixed(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "domain_mixed")
+}
+
 func TestLogic_drop_database(
 	t *testing.T,
 ) {
@@ -939,6 +953,13 @@ func TestLogic_group_join(
 	runLogicTest(t, "group_join")
 }
 
+func TestLogic_grouping_sets(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "grouping_sets")
+}
+
 func TestLogic_hash_join(
 	t *testing.T,
 ) {
@@ -1135,6 +1156,13 @@ func TestLogic_limit(
 	runLogicTest(t, "limit")
 }
 
+func TestLogic_listen_notify_mixed(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "listen_notify_mixed")
+}
+
 func TestLogic_locality(
 	t *testing.T,
 ) {
@@ -1450,6 +1478,13 @@ func TestLogic_propagate_input_ordering(
 	runLogicTest(t, "propagate_input_ordering")
 }
 
+func TestLogic_publication_mixed(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "publication_mixed")
+}
+
 func TestLogic_read_committed(
 	t *testing.T,
 ) {
@@ -1562,6 +1597,13 @@ func TestLogic_routine_schema_change(
 	runLogicTest(t, "routine_schema_change")
 }
 
+func TestLogic_row_level_security_mixed(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "row_level_security_mixed")
+}
+
 func TestLogic_row_level_ttl(
 	t *testing.T,
 ) {
@@ -2073,6 +2115,13 @@ func TestLogic_tenant_builtins(
 	runLogicTest(t, "tenant_builtins")
 }
 
+func TestLogic_text_search_config_mixed(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "text_search_config_mixed")
+}
+
 func TestLogic_time(
 	t *testing.T,
 ) {
@@ -2094,6 +2143,13 @@ func TestLogic_timetz(
 	runLogicTest(t, "timetz")
 }
 
+func TestLogic_trigger_privilege_mixed(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "trigger_privilege_mixed")
+}
+
 func TestLogic_trigram_builtins(
 	t *testing.T,
 ) {
@@ -2192,6 +2248,13 @@ func TestLogic_udf(
 	runLogicTest(t, "udf")
 }
 
+func TestLogic_udf_aggregate_mixed(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "udf_aggregate_mixed")
+}
+
 func TestLogic_udf_delete(
 	t *testing.T,
 ) {
@@ -2248,6 +2311,13 @@ func TestLogic_udf_options(
 	runLogicTest(t, "udf_options")
 }
 
+func TestLogic_udf_params_mixed(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "udf_params_mixed")
+}
+
 func TestLogic_udf_prepare(
 	t *testing.T,
 ) {
@@ -0,0 +1,564 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// maxNotificationChannelLength is the maximum length of a channel name, as
+	// in Postgres (where channel names are identifiers).
+	maxNotificationChannelLength = 63
+	// maxNotificationPayloadLength is the maximum length of a notification
+	// payload, as in Postgres.
+	maxNotificationPayloadLength = 7999
+	// notificationDeliveryTimeout bounds the time spent delivering a batch of
+	// notifications to the other nodes, including retries.
+	notificationDeliveryTimeout = 10 * time.Second
+)
+
+// notificationQueueSize limits the size of the notifications that a node has
+// committed but not yet delivered.
+var notificationQueueSize = settings.RegisterByteSizeSetting(
+	settings.ApplicationLevel,
+	"sql.notifications.queue_size",
+	"maximum size of the notifications that a node has committed but not yet "+
+		"delivered to the listening sessions of all nodes; NOTIFY fails while "+
+		"the queue is full",
+	64<<20,
+)
+
+// notificationDeliveryRetryOptions are used to retry delivering notifications
+// to the nodes that could not be reached.
+var notificationDeliveryRetryOptions = retry.Options{
+	InitialBackoff: 50 * time.Millisecond,
+	MaxBackoff:     time.Second,
+	Multiplier:     2,
+}
+
+// notificationChannel identifies a notification channel. As in Postgres,
+// channels are scoped to a database.
+type notificationChannel struct {
+	database string
+	name     string
+}
+
+// NotificationRegistry keeps track of the sessions on this node that are
+// listening on notification channels, and delivers notifications to them. It
+// also queues the notifications committed on this node until they are
+// delivered on all nodes.
+type NotificationRegistry struct {
+	queue notificationQueue
+	mu    struct {
+		syncutil.RWMutex
+		listeners map[notificationChannel]map[*notificationListener]struct{}
+	}
+}
+
+// NewNotificationRegistry creates a new NotificationRegistry with no
+// listeners.
+func NewNotificationRegistry(st *cluster.Settings) *NotificationRegistry {
+	r := &NotificationRegistry{}
+	r.queue.st = st
+	r.queue.wakeCh = make(chan struct{}, 1)
+	r.mu.listeners = make(map[notificationChannel]map[*notificationListener]struct{})
+	return r
+}
+
+// Start starts delivering the notifications committed on this node to the
+// listening sessions of all nodes. Until it is called, notifications are only
+// delivered on this node.
+func (r *NotificationRegistry) Start(
+	ctx context.Context, stopper *stop.Stopper, statusServer serverpb.SQLStatusServer,
+) error {
+	r.queue.statusServer = statusServer
+	return stopper.RunAsyncTask(ctx, "deliver-notifications", func(ctx context.Context) {
+		ctx, cancel := stopper.WithCancelOnQuiesce(ctx)
+		defer cancel()
+		r.queue.run(ctx)
+	})
+}
+
+// Deliver sends the given notifications to the sessions on this node that are
+// listening on their channels. It doesn't block on the sessions, which deliver
+// the notifications to their clients once they aren't in a transaction.
+func (r *NotificationRegistry) Deliver(ctx context.Context, notifications []serverpb.Notification) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for i := range notifications {
+		n := &notifications[i]
+		c := notificationChannel{database: n.Database, name: n.Channel}
+		for l := range r.mu.listeners[c] {
+			l.comm.SendNotification(ctx, n.SenderPID, n.Channel, n.Payload)
+		}
+	}
+}
+
+func (r *NotificationRegistry) register(l *notificationListener, c notificationChannel) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	listeners, ok := r.mu.listeners[c]
+	if !ok {
+		listeners = make(map[*notificationListener]struct{})
+		r.mu.listeners[c] = listeners
+	}
+	listeners[l] = struct{}{}
+}
+
+func (r *NotificationRegistry) deregister(l *notificationListener, c notificationChannel) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	listeners := r.mu.listeners[c]
+	delete(listeners, l)
+	if len(listeners) == 0 {
+		delete(r.mu.listeners, c)
+	}
+}
+
+// notificationQueue holds the notifications committed on this node until they
+// are delivered to the listening sessions of all nodes. Its size is bounded by
+// sql.notifications.queue_size. A single worker delivers the notifications in
+// the order in which they were committed, in batches, and retries the nodes
+// that could not be reached for up to notificationDeliveryTimeout. A
+// notification may therefore be delivered more than once to the sessions of a
+// node which was retried, and notifications are lost if this node crashes
+// before delivering them.
+type notificationQueue struct {
+	st           *cluster.Settings
+	statusServer serverpb.SQLStatusServer
+	// wakeCh is signaled when notifications are added to the queue.
+	wakeCh chan struct{}
+	mu     struct {
+		syncutil.Mutex
+		notifications []serverpb.Notification
+		// size is the total size of the queued notifications, including those
+		// being delivered.
+		size int64
+	}
+}
+
+// notificationsDroppedLogEvery rate limits the warnings about notifications
+// dropped because the queue is full.
+var notificationsDroppedLogEvery = log.Every(10 * time.Second)
+
+func notificationSize(n *serverpb.Notification) int64 {
+	return int64(len(n.Database) + len(n.Channel) + len(n.Payload))
+}
+
+// checkFull returns an error if no more notifications can be queued.
+func (q *notificationQueue) checkFull() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.mu.size >= notificationQueueSize.Get(&q.st.SV) {
+		return errors.WithHintf(
+			pgerror.New(pgcode.ProgramLimitExceeded, "too many notifications in the NOTIFY queue"),
+			"Notifications are delivered to the other nodes more slowly than they are sent. "+
+				"The size of the queue is limited by the %s cluster setting.",
+			notificationQueueSize.Name())
+	}
+	return nil
+}
+
+// push adds the notifications sent by a committed transaction to the queue. If
+// they don't fit, they are dropped, since the transaction can no longer fail.
+func (q *notificationQueue) push(ctx context.Context, notifications []serverpb.Notification) {
+	var size int64
+	for i := range notifications {
+		size += notificationSize(&notifications[i])
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.mu.size+size > notificationQueueSize.Get(&q.st.SV) {
+		if notificationsDroppedLogEvery.ShouldLog() {
+			log.Warningf(ctx, "dropped %d notifications because the queue is full; see %s",
+				len(notifications), notificationQueueSize.Name())
+		}
+		return
+	}
+	q.mu.notifications = append(q.mu.notifications, notifications...)
+	q.mu.size += size
+	select {
+	case q.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers the queued notifications until ctx is canceled.
+func (q *notificationQueue) run(ctx context.Context) {
+	for {
+		select {
+		case <-q.wakeCh:
+		case <-ctx.Done():
+			return
+		}
+		q.mu.Lock()
+		batch := q.mu.notifications
+		q.mu.notifications = nil
+		q.mu.Unlock()
+		if len(batch) == 0 {
+			continue
+		}
+
+		q.deliver(ctx, batch)
+
+		var size int64
+		for i := range batch {
+			size += notificationSize(&batch[i])
+		}
+		q.mu.Lock()
+		q.mu.size -= size
+		q.mu.Unlock()
+	}
+}
+
+// deliver delivers a batch of notifications on all nodes, retrying the nodes
+// that could not be reached.
+func (q *notificationQueue) deliver(ctx context.Context, notifications []serverpb.Notification) {
+	ctx, cancel := context.WithTimeout(ctx, notificationDeliveryTimeout)
+	defer cancel()
+	// The empty node ID delivers the notifications on all nodes, which reports
+	// the nodes that failed so that only these are retried.
+	pending := []string{""}
+	var lastErr error
+	for r := retry.StartWithCtx(ctx, notificationDeliveryRetryOptions); r.Next(); {
+		var failed []string
+		for _, nodeID := range pending {
+			resp, err := q.statusServer.Notify(ctx, &serverpb.NotifyRequest{
+				NodeID:        nodeID,
+				Notifications: notifications,
+			})
+			if err != nil {
+				lastErr = err
+				failed = append(failed, nodeID)
+				continue
+			}
+			for _, id := range resp.FailedNodeIDs {
+				failed = append(failed, id.String())
+			}
+		}
+		if len(failed) == 0 {
+			return
+		}
+		pending = failed
+	}
+	if lastErr == nil {
+		lastErr = ctx.Err()
+	}
+	log.Warningf(ctx, "failed to deliver %d notifications to nodes %v: %v",
+		len(notifications), pending, lastErr)
+}
+
+// notificationListener tracks the channels a session is listening on. It is
+// only accessed by the session's goroutine.
+type notificationListener struct {
+	registry *NotificationRegistry
+	comm     ClientComm
+	channels map[notificationChannel]struct{}
+}
+
+func (l *notificationListener) listen(c notificationChannel) {
+	if _, ok := l.channels[c]; ok {
+		return
+	}
+	l.channels[c] = struct{}{}
+	l.registry.register(l, c)
+}
+
+func (l *notificationListener) unlisten(c notificationChannel) {
+	if _, ok := l.channels[c]; !ok {
+		return
+	}
+	delete(l.channels, c)
+	l.registry.deregister(l, c)
+}
+
+func (l *notificationListener) unlistenAll() {
+	for c := range l.channels {
+		l.unlisten(c)
+	}
+}
+
+// listenAction is a LISTEN or UNLISTEN executed in a transaction.
+type listenAction struct {
+	channel  notificationChannel
+	unlisten bool
+	// all is set for UNLISTEN *.
+	all bool
+}
+
+// txnNotifications holds the effects of the NOTIFY, LISTEN and UNLISTEN
+// statements executed in a transaction. As in Postgres, they only take effect
+// when the transaction commits.
+type txnNotifications struct {
+	notifications []serverpb.Notification
+	// sent is used to send identical notifications only once per transaction,
+	// like Postgres does.
+	sent          map[serverpb.Notification]struct{}
+	listenActions []listenAction
+}
+
+// rollbackTo discards the effects of the statements executed after a savepoint,
+// given the number of notifications and listen actions recorded when it was
+// created.
+func (t *txnNotifications) rollbackTo(numNotifications, numListenActions int) {
+	for _, n := range t.notifications[numNotifications:] {
+		delete(t.sent, n)
+	}
+	t.notifications = t.notifications[:numNotifications]
+	t.listenActions = t.listenActions[:numListenActions]
+}
+
+type notifier interface {
+	// notify records a notification on the given channel of the current
+	// database, to be sent when the current transaction commits.
+	notify(channel, payload string) error
+	// listen records a LISTEN or UNLISTEN on the given channel of the current
+	// database, which takes effect when the current transaction commits. If
+	// all is set, the UNLISTEN applies to all channels.
+	listen(channel string, unlisten, all bool) error
+	// listeningChannels returns the sorted names of the channels the session is
+	// listening on.
+	listeningChannels() []string
+}
+
+type connExNotifierAccessor struct {
+	ex *connExecutor
+}
+
+func (c connExNotifierAccessor) notify(channel, payload string) error {
+	if c.ex.extraTxnState.fromOuterTxn {
+		// The transaction is committed by somebody else, so we would never get
+		// the chance to send the notification.
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"notifications cannot be sent in this context")
+	}
+	t := &c.ex.extraTxnState.notifications
+	n := serverpb.Notification{
+		Database:  c.ex.sessionData().Database,
+		Channel:   channel,
+		Payload:   payload,
+		SenderPID: c.ex.queryCancelKey.GetPGBackendPID(),
+	}
+	if _, ok := t.sent[n]; ok {
+		return nil
+	}
+	if r := c.ex.server.cfg.NotificationRegistry; r != nil {
+		if err := r.queue.checkFull(); err != nil {
+			return err
+		}
+	}
+	if t.sent == nil {
+		// Lazily allocate.
+		t.sent = make(map[serverpb.Notification]struct{})
+	}
+	t.sent[n] = struct{}{}
+	t.notifications = append(t.notifications, n)
+	return nil
+}
+
+func (c connExNotifierAccessor) listen(channel string, unlisten, all bool) error {
+	if c.ex.extraTxnState.fromOuterTxn || c.ex.executorType == executorTypeInternal {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"LISTEN and UNLISTEN cannot be used in this context")
+	}
+	t := &c.ex.extraTxnState.notifications
+	t.listenActions = append(t.listenActions, listenAction{
+		channel:  notificationChannel{database: c.ex.sessionData().Database, name: channel},
+		unlisten: unlisten,
+		all:      all,
+	})
+	return nil
+}
+
+func (c connExNotifierAccessor) listeningChannels() []string {
+	l := c.ex.notificationListener
+	if l == nil {
+		return nil
+	}
+	var ret []string
+	seen := make(map[string]struct{}, len(l.channels))
+	for ch := range l.channels {
+		if _, ok := seen[ch.name]; !ok {
+			seen[ch.name] = struct{}{}
+			ret = append(ret, ch.name)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// emptyNotifier is the default impl used by the planner when the connExecutor
+// is not available.
+type emptyNotifier struct{}
+
+func (emptyNotifier) notify(string, string) error {
+	return pgerror.New(pgcode.FeatureNotSupported,
+		"notifications cannot be sent in this context")
+}
+
+func (emptyNotifier) listen(string, bool, bool) error {
+	return pgerror.New(pgcode.FeatureNotSupported,
+		"LISTEN and UNLISTEN cannot be used in this context")
+}
+
+func (emptyNotifier) listeningChannels() []string {
+	return nil
+}
+
+// commitNotifications applies the LISTEN and UNLISTEN statements executed in
+// the transaction that just committed, then queues its notifications to be
+// delivered to the listening sessions on all nodes. The session doesn't wait
+// for the notifications to be delivered.
+func (ex *connExecutor) commitNotifications(ctx context.Context) {
+	t := &ex.extraTxnState.notifications
+	if len(t.listenActions) > 0 && ex.server.cfg.NotificationRegistry != nil {
+		if ex.notificationListener == nil {
+			ex.notificationListener = &notificationListener{
+				registry: ex.server.cfg.NotificationRegistry,
+				comm:     ex.clientComm,
+				channels: make(map[notificationChannel]struct{}),
+			}
+		}
+		l := ex.notificationListener
+		for _, a := range t.listenActions {
+			switch {
+			case a.all:
+				l.unlistenAll()
+			case a.unlisten:
+				l.unlisten(a.channel)
+			default:
+				l.listen(a.channel)
+			}
+		}
+	}
+	if len(t.notifications) == 0 {
+		return
+	}
+	registry := ex.server.cfg.NotificationRegistry
+	if registry == nil {
+		return
+	}
+	if registry.queue.statusServer == nil {
+		// This can only happen in tests; only deliver to the local sessions.
+		registry.Deliver(ctx, t.notifications)
+		return
+	}
+	registry.queue.push(ctx, t.notifications)
+}
+
+// checkNotificationChannel validates a channel name passed to NOTIFY or
+// pg_notify().
+func checkNotificationChannel(channel string) error {
+	if channel == "" {
+		return pgerror.New(pgcode.InvalidParameterValue, "channel name cannot be empty")
+	}
+	if len(channel) > maxNotificationChannelLength {
+		return pgerror.New(pgcode.InvalidParameterValue, "channel name too long")
+	}
+	return nil
+}
+
+// checkNotificationsActive returns an error if notifications cannot be used
+// yet, since nodes running older binaries don't serve the Notify RPC.
+func checkNotificationsActive(ctx context.Context, st *cluster.Settings) error {
+	if !st.Version.IsActive(ctx, clusterversion.V24_1_Notifications) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use LISTEN and NOTIFY",
+			clusterversion.V24_1_Notifications.Version())
+	}
+	return nil
+}
+
+// Notify is part of the eval.Planner interface.
+func (p *planner) Notify(ctx context.Context, channel, payload string) error {
+	if err := checkNotificationsActive(ctx, p.ExecCfg().Settings); err != nil {
+		return err
+	}
+	if err := checkNotificationChannel(channel); err != nil {
+		return err
+	}
+	if len(payload) > maxNotificationPayloadLength {
+		return pgerror.New(pgcode.InvalidParameterValue, "payload string too long")
+	}
+	return p.notifier.notify(channel, payload)
+}
+
+// ListeningChannels is part of the eval.Planner interface.
+func (p *planner) ListeningChannels() []string {
+	return p.notifier.listeningChannels()
+}
+
+// Listen implements the LISTEN statement.
+// See https://www.postgresql.org/docs/current/sql-listen.html for details.
+func (p *planner) Listen(ctx context.Context, n *tree.Listen) (planNode, error) {
+	return &listenNode{channel: string(n.ChannelName)}, nil
+}
+
+// NotifyStmt implements the NOTIFY statement.
+// See https://www.postgresql.org/docs/current/sql-notify.html for details.
+func (p *planner) NotifyStmt(ctx context.Context, n *tree.Notify) (planNode, error) {
+	node := &notifyNode{channel: string(n.ChannelName)}
+	if n.Payload != nil {
+		node.payload = *n.Payload
+	}
+	return node, nil
+}
+
+type listenNode struct {
+	channel  string
+	unlisten bool
+	all      bool
+}
+
+func (n *listenNode) Next(_ runParams) (bool, error) { return false, nil }
+func (n *listenNode) Values() tree.Datums            { return nil }
+func (n *listenNode) Close(_ context.Context)        {}
+func (n *listenNode) startExec(params runParams) error {
+	if !n.unlisten {
+		if err := checkNotificationsActive(params.ctx, params.ExecCfg().Settings); err != nil {
+			return err
+		}
+	}
+	if !n.all {
+		if err := checkNotificationChannel(n.channel); err != nil {
+			return err
+		}
+	}
+	return params.p.notifier.listen(n.channel, n.unlisten, n.all)
+}
+
+type notifyNode struct {
+	channel string
+	payload string
+}
+
+func (n *notifyNode) Next(_ runParams) (bool, error) { return false, nil }
+func (n *notifyNode) Values() tree.Datums            { return nil }
+func (n *notifyNode) Close(_ context.Context)        {}
+func (n *notifyNode) startExec(params runParams) error {
+	return params.p.Notify(params.ctx, n.channel, n.payload)
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNotifyStatusServer fails to deliver notifications to some nodes.
+type fakeNotifyStatusServer struct {
+	serverpb.SQLStatusServer
+	// unreachable are the nodes reported as failed when notifications are
+	// delivered on all nodes.
+	unreachable []roachpb.NodeID
+	mu          struct {
+		syncutil.Mutex
+		// failures is the number of times delivering to a node fails.
+		failures map[string]int
+		// requests are the node IDs of the Notify requests.
+		requests []string
+	}
+}
+
+func (s *fakeNotifyStatusServer) Notify(
+	ctx context.Context, req *serverpb.NotifyRequest,
+) (*serverpb.NotifyResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.requests = append(s.mu.requests, req.NodeID)
+	if req.NodeID == "" {
+		return &serverpb.NotifyResponse{FailedNodeIDs: s.unreachable}, nil
+	}
+	if s.mu.failures[req.NodeID] > 0 {
+		s.mu.failures[req.NodeID]--
+		return nil, errors.New("node unavailable")
+	}
+	return &serverpb.NotifyResponse{}, nil
+}
+
+func TestNotificationQueueRetriesFailedNodes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	s := &fakeNotifyStatusServer{unreachable: []roachpb.NodeID{2, 3}}
+	s.mu.failures = map[string]int{"3": 2}
+	q := &notificationQueue{st: cluster.MakeTestingClusterSettings(), statusServer: s}
+
+	q.deliver(context.Background(), []serverpb.Notification{{Channel: "foo"}})
+	// Only the nodes that failed are retried, until they succeed.
+	require.Equal(t, []string{"", "2", "3", "3", "3"}, s.mu.requests)
+}
+
+func TestNotificationQueueSize(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	notificationQueueSize.Override(ctx, &st.SV, 10)
+	q := &notificationQueue{st: st, wakeCh: make(chan struct{}, 1)}
+
+	n := serverpb.Notification{Database: "d", Channel: "c", Payload: "payload"}
+	require.NoError(t, q.checkFull())
+	q.push(ctx, []serverpb.Notification{n})
+	require.Len(t, q.mu.notifications, 1)
+	require.Equal(t, int64(9), q.mu.size)
+	require.NoError(t, q.checkFull())
+
+	// Notifications which don't fit are dropped.
+	q.push(ctx, []serverpb.Notification{n})
+	require.Len(t, q.mu.notifications, 1)
+	require.Equal(t, int64(9), q.mu.size)
+
+	notificationQueueSize.Override(ctx, &st.SV, 9)
+	err := q.checkFull()
+	require.Equal(t, pgcode.ProgramLimitExceeded, pgerror.GetPGCode(err))
+	require.ErrorContains(t, err, "too many notifications in the NOTIFY queue")
+}
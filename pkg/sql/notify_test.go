@@ -0,0 +1,152 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql_test
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNotificationDeliveryOrder checks that notifications are only delivered
+// once the transaction that sent them commits, and in the order in which the
+// sender's transactions committed.
+func TestNotificationDeliveryOrder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := serverutils.StartCluster(t, 2 /* numNodes */, base.TestClusterArgs{})
+	defer tc.Stopper().Stop(ctx)
+
+	// The listener and the sender are connected to different nodes, so that
+	// the notifications are delivered through the Notify RPC.
+	connect := func(idx int, conf func(*pgx.ConnConfig)) *pgx.Conn {
+		pgURL, cleanup := sqlutils.PGUrl(
+			t, tc.Server(idx).AdvSQLAddr(), t.Name(), url.User(username.RootUser))
+		defer cleanup()
+		connConfig, err := pgx.ParseConfig(pgURL.String())
+		require.NoError(t, err)
+		if conf != nil {
+			conf(connConfig)
+		}
+		conn, err := pgx.ConnectConfig(ctx, connConfig)
+		require.NoError(t, err)
+		return conn
+	}
+
+	var mu struct {
+		syncutil.Mutex
+		payloads []string
+	}
+	listener := connect(1, func(conf *pgx.ConnConfig) {
+		conf.OnNotification = func(_ *pgconn.PgConn, n *pgconn.Notification) {
+			mu.Lock()
+			defer mu.Unlock()
+			mu.payloads = append(mu.payloads, n.Payload)
+		}
+	})
+	defer func() { _ = listener.Close(ctx) }()
+	sender := connect(0, nil)
+	defer func() { _ = sender.Close(ctx) }()
+
+	// The notifications are read by the listener's connection while it
+	// executes a statement.
+	received := func() []string {
+		_, err := listener.Exec(ctx, "SELECT 1")
+		require.NoError(t, err)
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), mu.payloads...)
+	}
+	waitFor := func(expected ...string) {
+		testutils.SucceedsSoon(t, func() error {
+			if got := received(); !reflect.DeepEqual(got, expected) {
+				return errors.Newf("expected notifications %v, got %v", expected, got)
+			}
+			return nil
+		})
+	}
+
+	_, err := listener.Exec(ctx, "LISTEN foo")
+	require.NoError(t, err)
+
+	// A notification isn't delivered before its transaction commits.
+	txn, err := sender.Begin(ctx)
+	require.NoError(t, err)
+	_, err = txn.Exec(ctx, "NOTIFY foo, 'committed'")
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		require.Empty(t, received())
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.NoError(t, txn.Commit(ctx))
+	waitFor("committed")
+
+	// A notification sent by a transaction that rolls back is never delivered.
+	// Since the notifications of a session are delivered in order, it would be
+	// delivered before the one sent by the next transaction.
+	txn, err = sender.Begin(ctx)
+	require.NoError(t, err)
+	_, err = txn.Exec(ctx, "NOTIFY foo, 'rolled back'")
+	require.NoError(t, err)
+	require.NoError(t, txn.Rollback(ctx))
+	_, err = sender.Exec(ctx, "NOTIFY foo, 'after rollback'")
+	require.NoError(t, err)
+	waitFor("committed", "after rollback")
+
+	// The notifications of consecutive transactions are delivered in the order
+	// in which the transactions committed, even though the sender doesn't wait
+	// for their delivery.
+	expected := []string{"committed", "after rollback"}
+	for i := 0; i < 20; i++ {
+		payload := strconv.Itoa(i)
+		_, err = sender.Exec(ctx, "SELECT pg_notify('foo', $1)", payload)
+		require.NoError(t, err)
+		expected = append(expected, payload)
+	}
+	waitFor(expected...)
+}
+
+// TestNotificationQueueFull checks that notifications cannot be sent while the
+// queue of notifications to deliver is full.
+func TestNotificationQueueFull(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, "SET CLUSTER SETTING sql.notifications.queue_size = 0")
+	sqlDB.ExpectErr(t, "too many notifications in the NOTIFY queue", "NOTIFY foo")
+	sqlDB.ExpectErr(t, "too many notifications in the NOTIFY queue", "SELECT pg_notify('foo', 'bar')")
+
+	sqlDB.Exec(t, "RESET CLUSTER SETTING sql.notifications.queue_size")
+	sqlDB.Exec(t, "NOTIFY foo")
+}
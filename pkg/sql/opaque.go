@@ -105,6 +105,8 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.AlterDatabaseSetZoneConfigExtension(ctx, n)
 	case *tree.AlterDefaultPrivileges:
 		return p.alterDefaultPrivileges(ctx, n)
+	case *tree.AlterDomain:
+		return p.AlterDomain(ctx, n)
 	case *tree.AlterFunctionOptions:
 		return p.AlterFunctionOptions(ctx, n)
 	case *tree.AlterRoutineRename:
@@ -137,6 +139,10 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.alterRenameTenant(ctx, n)
 	case *tree.AlterTenantService:
 		return p.alterTenantService(ctx, n)
+	case *tree.AlterTextSearchConfig:
+		return p.AlterTextSearchConfig(ctx, n)
+	case *tree.AlterTextSearchDictionary:
+		return p.AlterTextSearchDictionary(ctx, n)
 	case *tree.AlterType:
 		return p.AlterType(ctx, n)
 	case *tree.AlterRole:
@@ -164,12 +170,26 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		// it can't have placeholder arguments, and the execution can use the same
 		// logic as if it were a simple query. This matches the Postgres behavior.
 		return &zeroNode{}, nil
+	case *tree.CreateAggregate:
+		return p.CreateAggregate(ctx, n)
 	case *tree.CreateDatabase:
 		return p.CreateDatabase(ctx, n)
 	case *tree.CreateIndex:
 		return p.CreateIndex(ctx, n)
 	case *tree.CreateSchema:
 		return p.CreateSchema(ctx, n)
+	case *tree.CreatePolicy:
+		return p.CreatePolicy(ctx, n)
+	case *tree.CreatePublication:
+		return p.CreatePublication(ctx, n)
+	case *tree.CreateSubscription:
+		return p.CreateSubscription(ctx, n)
+	case *tree.CreateTextSearchConfig:
+		return p.CreateTextSearchConfig(ctx, n)
+	case *tree.CreateTextSearchDictionary:
+		return p.CreateTextSearchDictionary(ctx, n)
+	case *tree.CreateTrigger:
+		return p.CreateTrigger(ctx, n)
 	case *tree.CreateType:
 		return p.CreateType(ctx, n)
 	case *tree.CreateRole:
@@ -192,6 +212,8 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.Discard(ctx, n)
 	case *tree.DropDatabase:
 		return p.DropDatabase(ctx, n)
+	case *tree.DropDomain:
+		return p.DropDomain(ctx, n)
 	case *tree.DropRoutine:
 		return p.DropFunction(ctx, n)
 	case *tree.DropIndex:
@@ -208,6 +230,18 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.DropTable(ctx, n)
 	case *tree.DropTenant:
 		return p.DropTenant(ctx, n)
+	case *tree.DropPolicy:
+		return p.DropPolicy(ctx, n)
+	case *tree.DropPublication:
+		return p.DropPublication(ctx, n)
+	case *tree.DropSubscription:
+		return p.DropSubscription(ctx, n)
+	case *tree.DropTextSearchConfig:
+		return p.DropTextSearchConfig(ctx, n)
+	case *tree.DropTextSearchDictionary:
+		return p.DropTextSearchDictionary(ctx, n)
+	case *tree.DropTrigger:
+		return p.DropTrigger(ctx, n)
 	case *tree.DropType:
 		return p.DropType(ctx, n)
 	case *tree.DropView:
@@ -218,8 +252,12 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.Grant(ctx, n)
 	case *tree.GrantRole:
 		return p.GrantRole(ctx, n)
+	case *tree.Listen:
+		return p.Listen(ctx, n)
 	case *tree.MoveCursor:
 		return p.FetchCursor(ctx, &n.CursorStmt)
+	case *tree.Notify:
+		return p.NotifyStmt(ctx, n)
 	case *tree.ReassignOwnedBy:
 		return p.ReassignOwnedBy(ctx, n)
 	case *tree.RefreshMaterializedView:
@@ -246,6 +284,8 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.SetClusterSetting(ctx, n)
 	case *tree.SetZoneConfig:
 		return p.SetZoneConfig(ctx, n)
+	case *tree.SetConstraints:
+		return p.SetConstraints(ctx, n)
 	case *tree.SetVar:
 		return p.SetVar(ctx, n)
 	case *tree.SetTransaction:
@@ -284,6 +324,12 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.Unlisten(ctx, n)
 	case *pgrepltree.IdentifySystem:
 		return p.IdentifySystem(ctx, n)
+	case *pgrepltree.CreateReplicationSlot:
+		return p.CreateReplicationSlot(ctx, n)
+	case *pgrepltree.DropReplicationSlot:
+		return p.DropReplicationSlot(ctx, n)
+	case *pgrepltree.ReadReplicationSlot:
+		return p.ReadReplicationSlot(ctx, n)
 	case tree.CCLOnlyStatement:
 		plan, err := p.maybePlanHook(ctx, stmt)
 		if plan == nil && err == nil {
@@ -312,6 +358,7 @@ func init() {
 		&tree.AlterDatabaseDropSecondaryRegion{},
 		&tree.AlterDatabaseSetZoneConfigExtension{},
 		&tree.AlterDefaultPrivileges{},
+		&tree.AlterDomain{},
 		&tree.AlterFunctionOptions{},
 		&tree.AlterRoutineRename{},
 		&tree.AlterRoutineSetOwner{},
@@ -328,6 +375,8 @@ func init() {
 		&tree.AlterTenantRename{},
 		&tree.AlterTenantSetClusterSetting{},
 		&tree.AlterTenantService{},
+		&tree.AlterTextSearchConfig{},
+		&tree.AlterTextSearchDictionary{},
 		&tree.AlterType{},
 		&tree.AlterSequence{},
 		&tree.AlterRole{},
@@ -340,6 +389,7 @@ func init() {
 		&tree.CommentOnConstraint{},
 		&tree.CommentOnTable{},
 		&tree.CopyTo{},
+		&tree.CreateAggregate{},
 		&tree.CreateDatabase{},
 		&tree.CreateExtension{},
 		&tree.CreateExternalConnection{},
@@ -347,12 +397,19 @@ func init() {
 		&tree.CreateIndex{},
 		&tree.CreateSchema{},
 		&tree.CreateSequence{},
+		&tree.CreatePolicy{},
+		&tree.CreatePublication{},
+		&tree.CreateSubscription{},
+		&tree.CreateTextSearchConfig{},
+		&tree.CreateTextSearchDictionary{},
+		&tree.CreateTrigger{},
 		&tree.CreateType{},
 		&tree.CreateRole{},
 		&tree.Deallocate{},
 		&tree.DeclareCursor{},
 		&tree.Discard{},
 		&tree.DropDatabase{},
+		&tree.DropDomain{},
 		&tree.DropExternalConnection{},
 		&tree.DropRoutine{},
 		&tree.DropIndex{},
@@ -362,12 +419,20 @@ func init() {
 		&tree.DropSequence{},
 		&tree.DropTable{},
 		&tree.DropTenant{},
+		&tree.DropPolicy{},
+		&tree.DropPublication{},
+		&tree.DropSubscription{},
+		&tree.DropTextSearchConfig{},
+		&tree.DropTextSearchDictionary{},
+		&tree.DropTrigger{},
 		&tree.DropType{},
 		&tree.DropView{},
 		&tree.FetchCursor{},
 		&tree.Grant{},
 		&tree.GrantRole{},
+		&tree.Listen{},
 		&tree.MoveCursor{},
+		&tree.Notify{},
 		&tree.ReassignOwnedBy{},
 		&tree.RefreshMaterializedView{},
 		&tree.RenameColumn{},
@@ -381,6 +446,7 @@ func init() {
 		&tree.Scrub{},
 		&tree.SetClusterSetting{},
 		&tree.SetZoneConfig{},
+		&tree.SetConstraints{},
 		&tree.SetVar{},
 		&tree.SetTransaction{},
 		&tree.SetSessionAuthorizationDefault{},
@@ -401,6 +467,9 @@ func init() {
 		&tree.Unlisten{},
 
 		&pgrepltree.IdentifySystem{},
+		&pgrepltree.CreateReplicationSlot{},
+		&pgrepltree.DropReplicationSlot{},
+		&pgrepltree.ReadReplicationSlot{},
 
 		// CCL statements (without Export which has an optimizer operator).
 		&tree.AlterBackup{},
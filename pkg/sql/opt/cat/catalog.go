@@ -202,6 +202,15 @@ type Catalog interface {
 	// CheckRoleExists returns an error if the role does not exist.
 	CheckRoleExists(ctx context.Context, role username.SQLUsername) error
 
+	// HasOwnership returns true if the current user owns the given object,
+	// either directly or through membership in the owning role.
+	HasOwnership(ctx context.Context, o Object) (bool, error)
+
+	// IsMemberOfRole returns true if the current user is the given role or a
+	// member of it, either directly or indirectly. Every user is a member of
+	// the public role.
+	IsMemberOfRole(ctx context.Context, role username.SQLUsername) (bool, error)
+
 	// Optimizer returns the query Optimizer used to optimize SQL statements
 	// referencing objects in this catalog, if any.
 	Optimizer() interface{}
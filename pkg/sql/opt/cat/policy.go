@@ -0,0 +1,50 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cat
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// Policy is an interface to a row-level security policy, exposing only the
+// information needed by the query optimizer. A policy restricts the rows that
+// the roles it applies to can read or write when row-level security is
+// enabled on its table.
+type Policy interface {
+	// Name is the name of the policy. It is unique within the table.
+	Name() tree.Name
+
+	// Type indicates whether the policy is permissive or restrictive.
+	// Permissive policies are combined using OR, and restrictive policies are
+	// combined using AND.
+	Type() tree.PolicyType
+
+	// Command is the command to which the policy applies.
+	Command() tree.PolicyCommand
+
+	// RoleCount returns the number of roles to which the policy applies.
+	RoleCount() int
+
+	// Role returns the ith role to which the policy applies, where
+	// i < RoleCount. The public role indicates that the policy applies to all
+	// roles.
+	Role(i int) username.SQLUsername
+
+	// UsingExpr is the USING expression of the policy, or the empty string if
+	// there is none. Existing rows are only visible if it evaluates to true.
+	UsingExpr() string
+
+	// WithCheckExpr is the WITH CHECK expression of the policy, or the empty
+	// string if there is none. New rows are rejected unless it evaluates to
+	// true.
+	WithCheckExpr() string
+}
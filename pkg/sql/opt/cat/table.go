@@ -15,6 +15,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
 
@@ -106,6 +107,27 @@ type Table interface {
 	// Check returns the ith check constraint, where i < CheckCount.
 	Check(i int) CheckConstraint
 
+	// TriggerCount returns the number of triggers present on the table.
+	TriggerCount() int
+
+	// Trigger returns the ith trigger, where i < TriggerCount.
+	Trigger(i int) Trigger
+
+	// IsRowLevelSecurityEnabled returns true if the policies of the table are
+	// enforced.
+	IsRowLevelSecurityEnabled() bool
+
+	// IsRowLevelSecurityForced returns true if the policies of the table are
+	// enforced for the owner of the table as well.
+	IsRowLevelSecurityForced() bool
+
+	// PolicyCount returns the number of row-level security policies present on
+	// the table.
+	PolicyCount() int
+
+	// Policy returns the ith row-level security policy, where i < PolicyCount.
+	Policy(i int) Policy
+
 	// FamilyCount returns the number of column families present on the table.
 	// There is always at least one primary family (always family 0) where columns
 	// go if they are not explicitly assigned to another family. The primary
@@ -139,6 +161,14 @@ type Table interface {
 	// i < UniqueCount.
 	Unique(i UniqueOrdinal) UniqueConstraint
 
+	// ExclusionCount returns the number of exclusion constraints defined on this
+	// table.
+	ExclusionCount() int
+
+	// Exclusion returns the ith exclusion constraint defined on this table,
+	// where i < ExclusionCount.
+	Exclusion(i int) ExclusionConstraint
+
 	// Zone returns a table's zone.
 	Zone() Zone
 
@@ -328,6 +358,10 @@ type ForeignKeyConstraint interface {
 	// UpdateReferenceAction returns the action to be performed if the foreign key
 	// constraint would be violated by an update.
 	UpdateReferenceAction() tree.ReferenceAction
+
+	// Deferrability returns whether the checks for this constraint may be
+	// deferred until the end of the transaction (see SET CONSTRAINTS).
+	Deferrability() tree.ConstraintDeferrability
 }
 
 // UniqueConstraint represents a uniqueness constraint. UniqueConstraints may
@@ -375,6 +409,47 @@ type UniqueConstraint interface {
 	// satisfied when building functional dependencies for the table. This enables
 	// additional optimizations, such as omission of uniqueness checks.
 	UniquenessGuaranteedByAnotherIndex() bool
+
+	// Deferrability returns whether the checks for this constraint may be
+	// deferred until the end of the transaction (see SET CONSTRAINTS). Only
+	// constraints that are not enforced by an index can be deferrable.
+	Deferrability() tree.ConstraintDeferrability
+}
+
+// ExclusionConstraint represents an exclusion constraint, which guarantees
+// that no two rows of the table satisfy all of the constraint's comparisons
+// when compared with each other. For example, the following statement makes
+// sure that no two rows have the same value of a and overlapping ranges in b:
+//
+//	ALTER TABLE t ADD CONSTRAINT e EXCLUDE USING gist (a WITH =, b WITH &&);
+//
+// Exclusion constraints are not enforced by an index, so the optimizer must
+// add a check as a postquery to any query that inserts into or updates one
+// of the constraint's columns.
+type ExclusionConstraint interface {
+	// Name of the exclusion constraint.
+	Name() string
+
+	// ColumnCount returns the number of columns in this constraint.
+	ColumnCount() int
+
+	// ColumnOrdinal returns the table column ordinal of the ith column in this
+	// constraint.
+	ColumnOrdinal(tab Table, i int) int
+
+	// Operator returns the comparison operator used for the ith column in this
+	// constraint. Two rows conflict if the operators of all the columns return
+	// true when comparing their values.
+	Operator(i int) treecmp.ComparisonOperator
+
+	// Predicate returns the partial predicate expression and true if the
+	// constraint is a partial exclusion constraint. If it is not, the empty
+	// string and false are returned.
+	Predicate() (string, bool)
+
+	// Validated is true if the constraint is validated (i.e. we know that the
+	// existing data satisfies the constraint).
+	Validated() bool
 }
 
 // UniqueOrdinal identifies a unique constraint (in the context of a Table).
@@ -0,0 +1,60 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cat
+
+import "github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+
+// Trigger is an interface to a table trigger, exposing only the information
+// needed by the query optimizer. A trigger executes a trigger function when a
+// mutation of one of its event types is applied to the table.
+type Trigger interface {
+	// Name is the name of the trigger. It is unique within the table.
+	Name() tree.Name
+
+	// ActionTime indicates whether the trigger fires before or after the
+	// mutation, or instead of it.
+	ActionTime() tree.TriggerActionTime
+
+	// EventCount returns the number of events that fire the trigger.
+	EventCount() int
+
+	// Event returns the ith TriggerEvent, where i < EventCount.
+	Event(i int) TriggerEvent
+
+	// ForEachRow returns true if the trigger fires once for each row affected
+	// by the mutation, and false if it fires once per statement.
+	ForEachRow() bool
+
+	// WhenExpr is the optional WHEN condition of the trigger, or the empty
+	// string if there is no condition. It references the NEW and OLD rows.
+	WhenExpr() string
+
+	// FuncID is the ID of the trigger function.
+	FuncID() StableID
+
+	// FuncArgs are the arguments passed to the trigger function through
+	// TG_ARGV.
+	FuncArgs() tree.Datums
+
+	// Enabled returns true if the trigger is enabled and should fire.
+	Enabled() bool
+}
+
+// TriggerEvent describes one of the mutations that fire a trigger.
+type TriggerEvent struct {
+	// EventType is the type of the mutation.
+	EventType tree.TriggerEventType
+
+	// ColumnOrdinals are the ordinals of the columns listed in an
+	// UPDATE OF column_name [, ...] event. The trigger only fires if one of the
+	// columns is a target of the UPDATE. It is empty for all other events.
+	ColumnOrdinals []int
+}
@@ -113,6 +113,23 @@ func FormatTable(cat Catalog, tab Table, tp treeprinter.Node, redactableValues b
 		}
 	}
 
+	for i := 0; i < tab.ExclusionCount(); i++ {
+		excl := tab.Exclusion(i)
+		var buf bytes.Buffer
+		buf.WriteByte('(')
+		for j := 0; j < excl.ColumnCount(); j++ {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			fmt.Fprintf(&buf, "%s WITH %s", tab.Column(excl.ColumnOrdinal(tab, j)).ColName(), excl.Operator(j))
+		}
+		buf.WriteByte(')')
+		c := child.Childf("CONSTRAINT %s EXCLUDE %s", excl.Name(), buf.String())
+		if pred, isPartial := excl.Predicate(); isPartial {
+			c.Childf("WHERE %s", MaybeMarkRedactable(pred, redactableValues))
+		}
+	}
+
 	// TODO(radu): show stats.
 }
 
@@ -20,3 +20,17 @@ const MaxReorderJoinsLimit = 63
 // SaveTablesDatabase is the name of the database where tables created by
 // the saveTableNode are stored.
 const SaveTablesDatabase = "savetables"
+
+// MergeAction is the value of the MergeActionCol of an Upsert operator that
+// implements a MERGE statement. It tells the execution engine which action to
+// take for an input row.
+type MergeAction int
+
+const (
+	// MergeActionInsert inserts a new row into the target table.
+	MergeActionInsert MergeAction = 1 + iota
+	// MergeActionUpdate updates the matching row of the target table.
+	MergeActionUpdate
+	// MergeActionDelete deletes the matching row of the target table.
+	MergeActionDelete
+)
@@ -153,9 +153,15 @@ func makeCascadeBuilder(b *Builder, mutationWithID opt.WithID) (*cascadeBuilder,
 
 // setupCascade fills in an exec.Cascade struct for the given cascade.
 func (cb *cascadeBuilder) setupCascade(cascade *memo.FKCascade) exec.Cascade {
+	// Some cascades (e.g. statement-level triggers) do not read the buffered
+	// input of the mutation, even if it has one.
+	var buffer exec.Node
+	if cascade.WithID != 0 {
+		buffer = cb.mutationBuffer
+	}
 	return exec.Cascade{
 		FKName: cascade.FKName,
-		Buffer: cb.mutationBuffer,
+		Buffer: buffer,
 		PlanFn: func(
 			ctx context.Context,
 			semaCtx *tree.SemaContext,
@@ -272,7 +278,16 @@ func (cb *cascadeBuilder) planCascade(
 		}
 	}
 
-	o.Memo().SetRoot(relExpr, &physical.Required{})
+	// Cascades that call trigger functions output the results of the calls.
+	// Require all output columns, so that the calls are not pruned.
+	var required physical.Required
+	relExpr.Relational().OutputCols.ForEach(func(col opt.ColumnID) {
+		required.Presentation = append(required.Presentation, opt.AliasedColumn{
+			Alias: md.ColumnMeta(col).Alias,
+			ID:    col,
+		})
+	})
+	o.Memo().SetRoot(relExpr, &required)
 
 	// 3. Assign placeholders if they exist.
 	if factory.Memo().HasPlaceholders() {
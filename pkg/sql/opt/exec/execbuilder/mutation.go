@@ -126,6 +126,10 @@ func (b *Builder) buildInsert(ins *memo.InsertExpr) (execPlan, error) {
 		return execPlan{}, err
 	}
 
+	if err := b.buildFKCascades(ins.WithID, ins.FKCascades); err != nil {
+		return execPlan{}, err
+	}
+
 	return ep, nil
 }
 
@@ -147,6 +151,10 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 	if len(ins.UniqueChecks) != len(ins.FastPathUniqueChecks) {
 		return execPlan{}, false, nil
 	}
+	// Cascades (e.g. AFTER triggers) cannot be executed by the fast path.
+	if len(ins.FKCascades) > 0 {
+		return execPlan{}, false, nil
+	}
 
 	insInput := ins.Input
 	values, ok := insInput.(*memo.ValuesExpr)
@@ -459,10 +467,14 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 	// do not need to be fetched or separately updated (i.e. ups.FetchCols and
 	// ups.UpdateCols are both empty).
 	//
+	// If MergeActionCol != 0, then the Upsert implements a MERGE statement, and
+	// the action column determines whether each row is inserted, updated or
+	// deleted.
+	//
 	// TODO(andyk): Using ensureColumns here can result in an extra Render.
 	// Upgrade execution engine to not require this.
 	cnt := len(ups.InsertCols) + len(ups.FetchCols) + len(ups.UpdateCols) + len(ups.CheckCols) +
-		len(ups.PartialIndexPutCols) + len(ups.PartialIndexDelCols) + 1
+		len(ups.PartialIndexPutCols) + len(ups.PartialIndexDelCols) + 2
 	colList := make(opt.ColList, 0, cnt)
 	colList = appendColsWhenPresent(colList, ups.InsertCols)
 	colList = appendColsWhenPresent(colList, ups.FetchCols)
@@ -470,6 +482,9 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 	if ups.CanaryCol != 0 {
 		colList = append(colList, ups.CanaryCol)
 	}
+	if ups.MergeActionCol != 0 {
+		colList = append(colList, ups.MergeActionCol)
+	}
 	colList = appendColsWhenPresent(colList, ups.CheckCols)
 	colList = appendColsWhenPresent(colList, ups.PartialIndexPutCols)
 	colList = appendColsWhenPresent(colList, ups.PartialIndexDelCols)
@@ -489,6 +504,13 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 			return execPlan{}, err
 		}
 	}
+	mergeActionCol := exec.NodeColumnOrdinal(-1)
+	if ups.MergeActionCol != 0 {
+		mergeActionCol, err = input.getNodeColumnOrdinal(ups.MergeActionCol)
+		if err != nil {
+			return execPlan{}, err
+		}
+	}
 	insertColOrds := ordinalSetFromColList(ups.InsertCols)
 	fetchColOrds := ordinalSetFromColList(ups.FetchCols)
 	updateColOrds := ordinalSetFromColList(ups.UpdateCols)
@@ -500,6 +522,7 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 		ups.ArbiterIndexes,
 		ups.ArbiterConstraints,
 		canaryCol,
+		mergeActionCol,
 		insertColOrds,
 		fetchColOrds,
 		updateColOrds,
@@ -626,6 +649,14 @@ func (b *Builder) tryBuildDeleteRange(del *memo.DeleteExpr) (_ execPlan, ok bool
 		// must be formulated to delete rows from them.
 		return execPlan{}, false, nil
 	}
+	for i, n := 0, tab.InboundForeignKeyCount(); i < n; i++ {
+		if tab.InboundForeignKey(i).Deferrability() != tree.ConstraintNotDeferrable {
+			// The checks of deferrable inbound foreign keys may be deferred until
+			// the end of the transaction, which requires the keys of the deleted
+			// rows; those are not available when deleting whole ranges.
+			return execPlan{}, false, nil
+		}
+	}
 
 	// We can use the fast path if we don't need to buffer the input to the
 	// delete operator (for foreign key checks/cascades).
@@ -782,6 +813,9 @@ func (b *Builder) buildUniqueChecks(checks memo.UniqueChecksExpr) error {
 				}
 				keyVals[i] = row[ord]
 			}
+			if c.Exclusion {
+				return mkExclusionCheckErr(md, c, keyVals)
+			}
 			return mkUniqueCheckErr(md, c, keyVals)
 		}
 		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
@@ -867,6 +901,59 @@ func mkUniqueCheckErr(md *opt.Metadata, c *memo.UniqueChecksItem, keyVals tree.D
 	)
 }
 
+// mkExclusionCheckErr generates a user-friendly error describing an exclusion
+// constraint violation. The keyVals are the values of the
+// cat.ExclusionConstraint columns in the new row, followed by the values of the
+// same columns in the conflicting existing row.
+func mkExclusionCheckErr(
+	md *opt.Metadata, c *memo.UniqueChecksItem, keyVals tree.Datums,
+) error {
+	tabMeta := md.TableMeta(c.Table)
+	ec := tabMeta.Table.Exclusion(c.CheckOrdinal)
+	constraintName := ec.Name()
+	var msg, details bytes.Buffer
+
+	// Generate an error of the form:
+	//   ERROR:  conflicting key value violates exclusion constraint "foo"
+	//   DETAIL: Key (r)=([1,5)) conflicts with existing key (r)=([3,7)).
+	msg.WriteString("conflicting key value violates exclusion constraint ")
+	lexbase.EncodeEscapedSQLIdent(&msg, constraintName)
+
+	var cols bytes.Buffer
+	for i := 0; i < ec.ColumnCount(); i++ {
+		if i > 0 {
+			cols.WriteString(", ")
+		}
+		col := tabMeta.Table.Column(ec.ColumnOrdinal(tabMeta.Table, i))
+		cols.WriteString(string(col.ColName()))
+	}
+	writeKey := func(prefix string, vals tree.Datums) {
+		details.WriteString(prefix)
+		details.WriteString(" (")
+		details.Write(cols.Bytes())
+		details.WriteString(")=(")
+		for i, d := range vals {
+			if i > 0 {
+				details.WriteString(", ")
+			}
+			details.WriteString(d.String())
+		}
+		details.WriteString(")")
+	}
+	n := ec.ColumnCount()
+	writeKey("Key", keyVals[:n])
+	writeKey(" conflicts with existing key", keyVals[n:])
+	details.WriteString(".")
+
+	return errors.WithDetail(
+		pgerror.WithConstraintName(
+			pgerror.Newf(pgcode.ExclusionViolation, "%s", msg.String()),
+			constraintName,
+		),
+		details.String(),
+	)
+}
+
 // mkFastPathUniqueCheckErr is a wrapper for mkUniqueCheckErr in the insert fast
 // path flow, which reorders the keyVals row according to the ordering of the
 // key columns in index `idx`. This is needed because mkUniqueCheckErr assumes
@@ -15,6 +15,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"math/rand"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
@@ -231,6 +232,9 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 	case *memo.GroupByExpr, *memo.ScalarGroupByExpr:
 		ep, err = b.buildGroupBy(e)
 
+	case *memo.GroupingSetsExpr:
+		ep, err = b.buildGroupingSets(t)
+
 	case *memo.DistinctOnExpr, *memo.EnsureDistinctOnExpr, *memo.UpsertDistinctOnExpr,
 		*memo.EnsureUpsertDistinctOnExpr:
 		ep, err = b.buildDistinct(t)
@@ -711,6 +715,13 @@ func (b *Builder) scanParams(
 		}
 	}
 
+	sample := scan.Sample
+	if sample.IsSampled() && !sample.Repeatable {
+		// Without a REPEATABLE clause, every execution of the query selects a
+		// different sample.
+		sample.Seed = rand.Float64()
+	}
+
 	// Figure out if we need to scan in reverse (ScanPrivateCanProvide takes
 	// HardLimit.Reverse() into account).
 	ok, reverse := ordering.ScanPrivateCanProvide(
@@ -731,6 +742,7 @@ func (b *Builder) scanParams(
 		Reverse:            reverse,
 		Parallelize:        parallelize,
 		Locking:            locking,
+		Sample:             sample,
 		EstimatedRowCount:  rowCount,
 		LocalityOptimized:  scan.LocalityOptimized,
 	}, outputMap, nil
@@ -1563,6 +1575,46 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (execPlan, error) {
 	}
 
 	aggregations := *groupBy.Child(1).(*memo.AggregationsExpr)
+	aggInfos, err := b.buildAggInfos(input, aggregations)
+	if err != nil {
+		return execPlan{}, err
+	}
+	for i := range aggregations {
+		ep.outputCols.Set(int(aggregations[i].Col), len(groupingColIdx)+i)
+	}
+
+	if groupBy.Op() == opt.ScalarGroupByOp {
+		ep.root, err = b.factory.ConstructScalarGroupBy(input.root, aggInfos)
+	} else {
+		groupBy := groupBy.(*memo.GroupByExpr)
+		var groupingColOrder colinfo.ColumnOrdering
+		groupingColOrder, err = input.sqlOrdering(ordering.StreamingGroupingColOrdering(
+			&groupBy.GroupingPrivate, &groupBy.RequiredPhysical().Ordering,
+		))
+		if err != nil {
+			return execPlan{}, err
+		}
+		var reqOrdering exec.OutputOrdering
+		reqOrdering, err = ep.reqOrdering(groupBy)
+		if err != nil {
+			return execPlan{}, err
+		}
+		orderType := exec.GroupingOrderType(groupBy.GroupingOrderType(&groupBy.RequiredPhysical().Ordering))
+		ep.root, err = b.factory.ConstructGroupBy(
+			input.root, groupingColIdx, groupingColOrder, aggInfos, reqOrdering, orderType,
+		)
+	}
+	if err != nil {
+		return execPlan{}, err
+	}
+	return ep, nil
+}
+
+// buildAggInfos returns the exec.AggInfo of each of the given aggregations,
+// whose arguments are columns of the given input.
+func (b *Builder) buildAggInfos(
+	input execPlan, aggregations memo.AggregationsExpr,
+) ([]exec.AggInfo, error) {
 	aggInfos := make([]exec.AggInfo, len(aggregations))
 	for i := range aggregations {
 		item := &aggregations[i]
@@ -1572,11 +1624,12 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (execPlan, error) {
 		if aggFilter, ok := agg.(*memo.AggFilterExpr); ok {
 			filter, ok := aggFilter.Filter.(*memo.VariableExpr)
 			if !ok {
-				return execPlan{}, errors.AssertionFailedf("only VariableOp args supported")
+				return nil, errors.AssertionFailedf("only VariableOp args supported")
 			}
+			var err error
 			filterOrd, err = input.getNodeColumnOrdinal(filter.Col)
 			if err != nil {
-				return execPlan{}, err
+				return nil, err
 			}
 			agg = aggFilter.Input
 		}
@@ -1587,63 +1640,90 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (execPlan, error) {
 			agg = aggDistinct.Input
 		}
 
-		name, _ := memo.FindAggregateOverload(agg)
+		var name string
+		var userDefined *tree.AggregateRoutines
+		if udAgg, ok := agg.(*memo.UserDefinedAggExpr); ok {
+			name = udAgg.Def.Name
+			var err error
+			userDefined, err = b.buildUserDefinedAggRoutines(udAgg.Def)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			name, _ = memo.FindAggregateOverload(agg)
+		}
 
 		// Accumulate variable arguments in argCols and constant arguments in
 		// constArgs. Constant arguments must follow variable arguments.
 		var argCols []exec.NodeColumnOrdinal
 		var constArgs tree.Datums
-		for j, n := 0, agg.ChildCount(); j < n; j++ {
-			child := agg.Child(j)
+		for _, child := range memo.ExtractAggArgs(agg) {
 			if variable, ok := child.(*memo.VariableExpr); ok {
 				if len(constArgs) != 0 {
-					return execPlan{}, errors.Errorf("constant args must come after variable args")
+					return nil, errors.Errorf("constant args must come after variable args")
 				}
 				ord, err := input.getNodeColumnOrdinal(variable.Col)
 				if err != nil {
-					return execPlan{}, err
+					return nil, err
 				}
 				argCols = append(argCols, ord)
 			} else {
 				if len(argCols) == 0 {
-					return execPlan{}, errors.Errorf("a constant arg requires at least one variable arg")
+					return nil, errors.Errorf("a constant arg requires at least one variable arg")
 				}
 				constArgs = append(constArgs, memo.ExtractConstDatum(child))
 			}
 		}
 
 		aggInfos[i] = exec.AggInfo{
-			FuncName:   name,
-			Distinct:   distinct,
-			ResultType: item.Agg.DataType(),
-			ArgCols:    argCols,
-			ConstArgs:  constArgs,
-			Filter:     filterOrd,
+			FuncName:    name,
+			Distinct:    distinct,
+			ResultType:  item.Agg.DataType(),
+			ArgCols:     argCols,
+			ConstArgs:   constArgs,
+			Filter:      filterOrd,
+			UserDefined: userDefined,
 		}
-		ep.outputCols.Set(int(item.Col), len(groupingColIdx)+i)
+	}
+	return aggInfos, nil
+}
+
+func (b *Builder) buildGroupingSets(groupingSets *memo.GroupingSetsExpr) (execPlan, error) {
+	input, err := b.buildRelational(groupingSets.Input)
+	if err != nil {
+		return execPlan{}, err
 	}
 
-	if groupBy.Op() == opt.ScalarGroupByOp {
-		ep.root, err = b.factory.ConstructScalarGroupBy(input.root, aggInfos)
-	} else {
-		groupBy := groupBy.(*memo.GroupByExpr)
-		var groupingColOrder colinfo.ColumnOrdering
-		groupingColOrder, err = input.sqlOrdering(ordering.StreamingGroupingColOrdering(
-			&groupBy.GroupingPrivate, &groupBy.RequiredPhysical().Ordering,
-		))
+	var ep execPlan
+	groupingCols := groupingSets.GroupingCols
+	groupingColIdx := make([]exec.NodeColumnOrdinal, 0, groupingCols.Len())
+	for i, ok := groupingCols.Next(0); ok; i, ok = groupingCols.Next(i + 1) {
+		ep.outputCols.Set(int(i), len(groupingColIdx))
+		ord, err := input.getNodeColumnOrdinal(i)
 		if err != nil {
 			return execPlan{}, err
 		}
-		var reqOrdering exec.OutputOrdering
-		reqOrdering, err = ep.reqOrdering(groupBy)
+		groupingColIdx = append(groupingColIdx, ord)
+	}
+
+	sets := make([]exec.NodeColumnOrdinalSet, len(groupingSets.Sets))
+	for i := range groupingSets.Sets {
+		sets[i], err = input.getNodeColumnOrdinalSet(groupingSets.Sets[i])
 		if err != nil {
 			return execPlan{}, err
 		}
-		orderType := exec.GroupingOrderType(groupBy.GroupingOrderType(&groupBy.RequiredPhysical().Ordering))
-		ep.root, err = b.factory.ConstructGroupBy(
-			input.root, groupingColIdx, groupingColOrder, aggInfos, reqOrdering, orderType,
-		)
 	}
+
+	aggInfos, err := b.buildAggInfos(input, groupingSets.Aggregations)
+	if err != nil {
+		return execPlan{}, err
+	}
+	for i := range groupingSets.Aggregations {
+		ep.outputCols.Set(int(groupingSets.Aggregations[i].Col), len(groupingColIdx)+i)
+	}
+	ep.outputCols.Set(int(groupingSets.SetIDCol), len(groupingColIdx)+len(aggInfos))
+
+	ep.root, err = b.factory.ConstructGroupingSets(input.root, groupingColIdx, sets, aggInfos)
 	if err != nil {
 		return execPlan{}, err
 	}
@@ -3194,12 +3274,11 @@ func (b *Builder) buildCall(c *memo.CallExpr) (execPlan, error) {
 		nil,  /* cursorDeclaration */
 	)
 
-	var ep execPlan
-	ep.root, err = b.factory.ConstructCall(r)
+	node, err := b.factory.ConstructCall(r)
 	if err != nil {
 		return execPlan{}, err
 	}
-	return ep, nil
+	return planWithColumns(node, c.Columns), nil
 }
 
 func (b *Builder) resultColumn(id opt.ColumnID) colinfo.ResultColumn {
@@ -3367,20 +3446,36 @@ func (b *Builder) buildWindow(w *memo.WindowExpr) (execPlan, error) {
 	filterIdxs := make([]int, len(w.Windows))
 	exprs := make([]*tree.FuncExpr, len(w.Windows))
 	windowVals := make([]tree.WindowDef, len(w.Windows))
+	var userDefinedAggs []*tree.AggregateRoutines
 
 	for i := range w.Windows {
 		item := &w.Windows[i]
 		fn := b.extractWindowFunction(item.Function)
-		name, overload := memo.FindWindowOverload(fn)
-		if !b.disableTelemetry {
-			telemetry.Inc(sqltelemetry.WindowFunctionCounter(name))
+		var name string
+		var overload *tree.Overload
+		var props *tree.FunctionProperties
+		fnArgs := memo.ExtractAggArgs(fn)
+		if udAgg, ok := fn.(*memo.UserDefinedAggExpr); ok {
+			name = udAgg.Def.Name
+			if userDefinedAggs == nil {
+				userDefinedAggs = make([]*tree.AggregateRoutines, len(w.Windows))
+			}
+			userDefinedAggs[i], err = b.buildUserDefinedAggRoutines(udAgg.Def)
+			if err != nil {
+				return execPlan{}, err
+			}
+		} else {
+			name, overload = memo.FindWindowOverload(fn)
+			if !b.disableTelemetry {
+				telemetry.Inc(sqltelemetry.WindowFunctionCounter(name))
+			}
+			props, _ = builtinsregistry.GetBuiltinProperties(name)
 		}
-		props, _ := builtinsregistry.GetBuiltinProperties(name)
 
-		args := make([]tree.TypedExpr, fn.ChildCount())
-		argIdxs[i] = make([]exec.NodeColumnOrdinal, fn.ChildCount())
-		for j, n := 0, fn.ChildCount(); j < n; j++ {
-			col := fn.Child(j).(*memo.VariableExpr).Col
+		args := make([]tree.TypedExpr, len(fnArgs))
+		argIdxs[i] = make([]exec.NodeColumnOrdinal, len(fnArgs))
+		for j := range fnArgs {
+			col := fnArgs[j].(*memo.VariableExpr).Col
 			indexedVar, err := b.indexedVar(&ctx, b.mem.Metadata(), col)
 			if err != nil {
 				return execPlan{}, err
@@ -3418,6 +3513,22 @@ func (b *Builder) buildWindow(w *memo.WindowExpr) (execPlan, error) {
 			OrderBy:    orderingExprs,
 			Frame:      frame,
 		}
+		if userDefinedAggs != nil && userDefinedAggs[i] != nil {
+			// User-defined aggregates are evaluated with their routines, so the
+			// function expression is only used for display.
+			unresolved := tree.MakeUnresolvedName(name)
+			exprs[i] = tree.NewTypedFuncExpr(
+				tree.ResolvableFunctionReference{FunctionReference: &unresolved},
+				0,
+				args,
+				builtFilter,
+				&windowVals[i],
+				fn.DataType(),
+				nil, /* props */
+				nil, /* overload */
+			)
+			continue
+		}
 		wrappedFn, err := b.wrapFunction(name)
 		if err != nil {
 			return execPlan{}, err
@@ -3466,13 +3577,14 @@ func (b *Builder) buildWindow(w *memo.WindowExpr) (execPlan, error) {
 		return execPlan{}, err
 	}
 	node, err := b.factory.ConstructWindow(input.root, exec.WindowInfo{
-		Cols:       resultCols,
-		Exprs:      exprs,
-		OutputIdxs: outputIdxs,
-		ArgIdxs:    argIdxs,
-		FilterIdxs: filterIdxs,
-		Partition:  partitionIdxs,
-		Ordering:   sqlOrdering,
+		Cols:            resultCols,
+		Exprs:           exprs,
+		OutputIdxs:      outputIdxs,
+		ArgIdxs:         argIdxs,
+		FilterIdxs:      filterIdxs,
+		Partition:       partitionIdxs,
+		Ordering:        sqlOrdering,
+		UserDefinedAggs: userDefinedAggs,
 	})
 	if err != nil {
 		return execPlan{}, err
@@ -3688,6 +3800,7 @@ var boundedStalenessAllowList = map[opt.Operator]struct{}{
 	opt.ProjectOp:          {},
 	opt.GroupByOp:          {},
 	opt.ScalarGroupByOp:    {},
+	opt.GroupingSetsOp:     {},
 	opt.DistinctOnOp:       {},
 	opt.DistributeOp:       {},
 	opt.EnsureDistinctOnOp: {},
@@ -1022,6 +1022,35 @@ func (b *Builder) buildUDF(ctx *buildScalarCtx, scalar opt.ScalarExpr) (tree.Typ
 	), nil
 }
 
+// buildUserDefinedAggRoutines builds the routines that evaluate a
+// user-defined aggregate. The arguments of the routines are placeholders that
+// are replaced for each invocation during execution.
+func (b *Builder) buildUserDefinedAggRoutines(
+	def *memo.UserDefinedAggDefinition,
+) (*tree.AggregateRoutines, error) {
+	ctx := buildScalarCtx{}
+	routines := &tree.AggregateRoutines{
+		Name:      def.Name,
+		StateType: def.StateType,
+	}
+	if def.InitCond != tree.DNull {
+		routines.InitCond = def.InitCond
+	}
+	transition, err := b.buildUDF(&ctx, def.Transition)
+	if err != nil {
+		return nil, err
+	}
+	routines.Transition = transition.(*tree.RoutineExpr)
+	if def.Final != nil {
+		final, err := b.buildUDF(&ctx, def.Final)
+		if err != nil {
+			return nil, err
+		}
+		routines.Final = final.(*tree.RoutineExpr)
+	}
+	return routines, nil
+}
+
 // initRoutineExceptionHandler initializes the exception handler (if any) for
 // the shared BlockState of a group of sub-routines within a PLpgSQL block.
 func (b *Builder) initRoutineExceptionHandler(
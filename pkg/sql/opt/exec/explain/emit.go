@@ -313,6 +313,7 @@ var nodeNames = [...]string{
 	exportOp:               "export",
 	filterOp:               "filter",
 	groupByOp:              "", // This node does not have a fixed name.
+	groupingSetsOp:         "group (grouping sets)",
 	hashJoinOp:             "", // This node does not have a fixed name.
 	indexJoinOp:            "index join",
 	insertFastPathOp:       "insert fast path",
@@ -598,6 +599,10 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 			ob.Attr("limit", "")
 		}
 
+		if a.Params.Sample.IsSampled() {
+			ob.Attr("sample", a.Params.Sample)
+		}
+
 		if a.Params.Parallelize {
 			ob.VAttr("parallel", "")
 		}
@@ -679,6 +684,20 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 			a.Aggregations, nil /* groupCols */, nil /* groupColOrdering */, true, /* isScalar */
 		)
 
+	case groupingSetsOp:
+		a := n.args.(*groupingSetsArgs)
+		inputCols := a.Input.Columns()
+		e.emitGroupByAttributes(
+			inputCols, a.Aggregations, a.GroupCols, nil /* groupColOrdering */, false, /* isScalar */
+		)
+		if len(a.GroupingSets) > 0 && len(inputCols) > 0 {
+			sets := make([]string, len(a.GroupingSets))
+			for i, set := range a.GroupingSets {
+				sets[i] = "(" + printColumnSet(inputCols, set) + ")"
+			}
+			ob.Attr("grouping sets", strings.Join(sets, ", "))
+		}
+
 	case distinctOp:
 		a := n.args.(*distinctArgs)
 		inputCols := a.Input.Columns()
@@ -546,6 +546,30 @@ func (u *unknownTable) Check(i int) cat.CheckConstraint {
 	panic(errors.AssertionFailedf("not implemented"))
 }
 
+func (u *unknownTable) TriggerCount() int {
+	return 0
+}
+
+func (u *unknownTable) Trigger(i int) cat.Trigger {
+	panic(errors.AssertionFailedf("not implemented"))
+}
+
+func (u *unknownTable) IsRowLevelSecurityEnabled() bool {
+	return false
+}
+
+func (u *unknownTable) IsRowLevelSecurityForced() bool {
+	return false
+}
+
+func (u *unknownTable) PolicyCount() int {
+	return 0
+}
+
+func (u *unknownTable) Policy(i int) cat.Policy {
+	panic(errors.AssertionFailedf("not implemented"))
+}
+
 func (u *unknownTable) FamilyCount() int {
 	return 0
 }
@@ -578,6 +602,14 @@ func (u *unknownTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	panic(errors.AssertionFailedf("not implemented"))
 }
 
+func (u *unknownTable) ExclusionCount() int {
+	return 0
+}
+
+func (u *unknownTable) Exclusion(i int) cat.ExclusionConstraint {
+	panic(errors.AssertionFailedf("not implemented"))
+}
+
 func (u *unknownTable) Zone() cat.Zone {
 	return cat.EmptyZone()
 }
@@ -137,6 +137,16 @@ func getResultColumns(
 		a := args.(*scalarGroupByArgs)
 		return groupByColumns(inputs[0], nil /* groupCols */, a.Aggregations), nil
 
+	case groupingSetsOp:
+		if len(inputs) == 0 {
+			return nil, nil
+		}
+		a := args.(*groupingSetsArgs)
+		return appendColumns(
+			groupByColumns(inputs[0], a.GroupCols, a.Aggregations),
+			colinfo.ResultColumn{Name: "grouping_set", Typ: types.Int},
+		), nil
+
 	case windowOp:
 		return args.(*windowArgs).Window.Cols, nil
 
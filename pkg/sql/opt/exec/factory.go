@@ -66,6 +66,10 @@ type ScanParams struct {
 	// Row-level locking properties.
 	Locking opt.Locking
 
+	// If Sample is set, the scan only returns a random subset of the rows. The
+	// seed is always set, even if the sample is not repeatable.
+	Sample opt.TableSample
+
 	EstimatedRowCount float64
 
 	// If true, we are performing a locality optimized search. In order for this
@@ -154,6 +158,11 @@ type AggInfo struct {
 	// Filter is the index of the column, if any, which should be used as the
 	// FILTER condition for the aggregate. If there is no filter, Filter is -1.
 	Filter NodeColumnOrdinal
+
+	// UserDefined is set if the aggregate is a user-defined aggregate, in which
+	// case FuncName is the name of the aggregate and it is evaluated with the
+	// given routines.
+	UserDefined *tree.AggregateRoutines
 }
 
 // WindowInfo represents the information about a window function that must be
@@ -183,6 +192,11 @@ type WindowInfo struct {
 
 	// Ordering is the set of input columns to order on.
 	Ordering colinfo.ColumnOrdering
+
+	// UserDefinedAggs contains the routines of each window function that is a
+	// user-defined aggregate, in the same order as Exprs. The entries for other
+	// window functions are nil.
+	UserDefinedAggs []*tree.AggregateRoutines
 }
 
 // ExplainEnvData represents the data that's going to be displayed in EXPLAIN (env).
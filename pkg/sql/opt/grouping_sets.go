@@ -0,0 +1,44 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package opt
+
+import "strings"
+
+// GroupingSetList is the list of grouping sets of a GROUPING SETS, ROLLUP or
+// CUBE clause. Each grouping set is a subset of the grouping columns. The list
+// may contain duplicate sets, each of which produces its own rows.
+type GroupingSetList []ColSet
+
+// Equals returns true if the two lists contain the same grouping sets in the
+// same order.
+func (l GroupingSetList) Equals(other GroupingSetList) bool {
+	if len(l) != len(other) {
+		return false
+	}
+	for i := range l {
+		if !l[i].Equals(other[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// String implements the fmt.Stringer interface.
+func (l GroupingSetList) String() string {
+	var b strings.Builder
+	for i := range l {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(l[i].String())
+	}
+	return b.String()
+}
@@ -130,6 +130,12 @@ func TryFilterInvertedIndex(
 				index:           index,
 				computedColumns: computedColumns,
 			}
+		case types.RangeFamily, types.MultirangeFamily:
+			filterPlanner = &rangeFilterPlanner{
+				tabID:           tabID,
+				index:           index,
+				computedColumns: computedColumns,
+			}
 		case types.JsonFamily, types.ArrayFamily:
 			filterPlanner = &jsonOrArrayFilterPlanner{
 				tabID:           tabID,
@@ -213,6 +219,12 @@ func TryJoinInvertedIndex(
 			getSpanExpr: getSpanExprForGeometryIndex,
 		}
 	} else {
+		col := index.InvertedColumn().InvertedSourceColumnOrdinal()
+		switch factory.Metadata().Table(tabID).Column(col).DatumType().Family() {
+		case types.RangeFamily, types.MultirangeFamily:
+			// Inverted joins are not supported for range indexes.
+			return nil
+		}
 		joinPlanner = &jsonOrArrayJoinPlanner{
 			factory:   factory,
 			tabID:     tabID,
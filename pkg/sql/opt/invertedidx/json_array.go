@@ -400,6 +400,10 @@ func (j *jsonOrArrayFilterPlanner) extractInvertedFilterConditionFromLeaf(
 		}
 	case *memo.OverlapsExpr:
 		invertedExpr = j.extractArrayOverlapsCondition(ctx, evalCtx, t.Left, t.Right)
+	case *memo.JsonPathExistsExpr:
+		invertedExpr = j.extractJSONPathCondition(t.Left, t.Right, false /* match */)
+	case *memo.JsonPathMatchExpr:
+		invertedExpr = j.extractJSONPathCondition(t.Left, t.Right, true /* match */)
 	}
 
 	if invertedExpr == nil {
@@ -559,6 +563,28 @@ func (j *jsonOrArrayFilterPlanner) extractJSONExistsCondition(
 	return inverted.NonInvertedColExpression{}
 }
 
+// extractJSONPathCondition extracts an InvertedExpression representing an
+// inverted filter over the planner's inverted index, based on the given left
+// and right expression arguments of the @? operator (match=false) or the @@
+// operator (match=true). If an InvertedExpression cannot be generated from the
+// expression, an inverted.NonInvertedColExpression is returned.
+func (j *jsonOrArrayFilterPlanner) extractJSONPathCondition(
+	left, right opt.ScalarExpr, match bool,
+) inverted.Expression {
+	if !isIndexColumn(j.tabID, j.index, left, j.computedColumns) || !memo.CanExtractConstDatum(right) {
+		return inverted.NonInvertedColExpression{}
+	}
+	jp, ok := memo.ExtractConstDatum(right).(*tree.DJsonpath)
+	if !ok {
+		return inverted.NonInvertedColExpression{}
+	}
+	invertedExpr := getInvertedExprForJSONPath(jp.Jsonpath, match)
+	if invertedExpr == nil {
+		return inverted.NonInvertedColExpression{}
+	}
+	return invertedExpr
+}
+
 // extractJSONEqCondition extracts an InvertedExpression representing an
 // inverted filter over the planner's inverted index, based on equality between
 // two scalar expressions. If an InvertedExpression cannot be generated from the
@@ -866,6 +866,65 @@ func TestTryFilterJsonOrArrayIndex(t *testing.T) {
 			indexOrd: jsonOrd,
 			ok:       false,
 		},
+		{
+			// JSONPathExists is supported, but the inverted expression is never
+			// tight.
+			filters:          "j @? '$.a.b'",
+			indexOrd:         jsonOrd,
+			ok:               true,
+			tight:            false,
+			unique:           false,
+			remainingFilters: "j @? '$.a.b'",
+		},
+		{
+			filters:          "j @? '$.a ? (@.b == 1 && @.c > 2)'",
+			indexOrd:         jsonOrd,
+			ok:               true,
+			tight:            false,
+			unique:           false,
+			remainingFilters: "j @? '$.a ? (@.b == 1 && @.c > 2)'",
+		},
+		{
+			// Every document has a root item.
+			filters:  "j @? '$'",
+			indexOrd: jsonOrd,
+			ok:       false,
+		},
+		{
+			// A predicate is true for some documents that do not contain any
+			// of its paths.
+			filters:  "j @? '!($.a == 1)'",
+			indexOrd: jsonOrd,
+			ok:       false,
+		},
+		{
+			filters:  "j @? '$.a == 1 || $.b == 2'",
+			indexOrd: jsonOrd,
+			ok:       false,
+		},
+		{
+			// JSONPathMatch is supported.
+			filters:          "j @@ '$.a[*] == \"foo\"'",
+			indexOrd:         jsonOrd,
+			ok:               true,
+			tight:            false,
+			unique:           false,
+			remainingFilters: "j @@ '$.a[*] == \"foo\"'",
+		},
+		{
+			filters:          "j @@ '$.a == 1 || $.b.type() == \"array\"'",
+			indexOrd:         jsonOrd,
+			ok:               true,
+			tight:            false,
+			unique:           false,
+			remainingFilters: "j @@ '$.a == 1 || $.b.type() == \"array\"'",
+		},
+		{
+			// A path that is not a predicate is never matched.
+			filters:  "j @@ '$.a'",
+			indexOrd: jsonOrd,
+			ok:       false,
+		},
 		{
 			// Overlaps is supported for arrays.
 			// Overlaps with a single element array produces
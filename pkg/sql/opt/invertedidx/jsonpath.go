@@ -0,0 +1,274 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedidx
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
+)
+
+// maxJSONPathPaths is the maximum number of document paths that are tracked
+// for a single jsonpath expression. Lax mode doubles the number of candidate
+// paths at each member accessor, so long paths are only constrained by their
+// first few accessors.
+const maxJSONPathPaths = 64
+
+// getInvertedExprForJSONPath gets an inverted.Expression that constrains a
+// JSON index according to the given jsonpath, when used with the @? operator
+// (match=false) or the @@ operator (match=true). It returns nil if the path
+// cannot be used to constrain the index.
+//
+// The expression is derived from the object keys and scalar values that must
+// be present in any document for which the path returns an item or a true
+// predicate result. Paths are evaluated in lax mode, which returns a superset
+// of the items returned in strict mode: a member accessor applied to an array
+// is applied to its elements, and comparisons unwrap arrays. Every candidate
+// document path is therefore expanded into the paths that do and do not
+// traverse an array at each step. The returned expression is never tight.
+func getInvertedExprForJSONPath(jp jsonpath.Jsonpath, match bool) inverted.Expression {
+	var expr inverted.Expression
+	if match {
+		if !jp.IsPredicate() {
+			return nil
+		}
+		expr = jsonPathPredicate(jp.Expr, nil /* current */)
+	} else {
+		// A predicate always returns a single boolean item, so @? with a
+		// predicate is true for every document.
+		if jp.IsPredicate() {
+			return nil
+		}
+		expr = jsonPathItems(jp.Expr, nil /* current */).existsCond()
+	}
+	if expr == nil {
+		return nil
+	}
+	expr.SetNotTight()
+	return expr
+}
+
+// jsonPathPaths is a set of paths through a JSON document.
+type jsonPathPaths [][]json.InvertedIndexPathStep
+
+// add appends path to the set if it is not already present. It returns false
+// if the set would exceed maxJSONPathPaths.
+func (p *jsonPathPaths) add(seen map[string]struct{}, path []json.InvertedIndexPathStep) bool {
+	var sb strings.Builder
+	for _, step := range path {
+		if step.Array {
+			sb.WriteString("[]")
+		} else {
+			sb.WriteString(step.Key)
+		}
+		sb.WriteByte(0)
+	}
+	if _, ok := seen[sb.String()]; ok {
+		return true
+	}
+	if len(*p) >= maxJSONPathPaths {
+		return false
+	}
+	seen[sb.String()] = struct{}{}
+	*p = append(*p, path)
+	return true
+}
+
+// extend returns the set of paths formed by appending each of the given
+// suffixes to each path, or ok=false if there would be too many paths.
+func (p jsonPathPaths) extend(
+	suffixes ...[]json.InvertedIndexPathStep,
+) (res jsonPathPaths, ok bool) {
+	seen := make(map[string]struct{})
+	for _, path := range p {
+		for _, suffix := range suffixes {
+			newPath := make([]json.InvertedIndexPathStep, 0, len(path)+len(suffix))
+			newPath = append(newPath, path...)
+			newPath = append(newPath, suffix...)
+			if !res.add(seen, newPath) {
+				return nil, false
+			}
+		}
+	}
+	return res, true
+}
+
+// withElements returns the paths along with the paths to their elements,
+// which are the items that lax mode considers when an array is found where
+// a single item is expected.
+func (p jsonPathPaths) withElements() (jsonPathPaths, bool) {
+	return p.extend(nil, []json.InvertedIndexPathStep{{Array: true}})
+}
+
+// jsonPathItemSet describes the items that a jsonpath expression returns.
+type jsonPathItemSet struct {
+	// paths contains the document paths of all returned items. If exact is
+	// false, the items may be anywhere below these paths.
+	paths jsonPathPaths
+	exact bool
+	// cond is a condition that must hold for any item to be returned, or nil.
+	cond inverted.Expression
+}
+
+// jsonPathItems returns the items returned by the given expression, or nil if
+// nothing is known about them. current contains the paths of the @ item of an
+// enclosing filter.
+func jsonPathItems(expr jsonpath.Expr, current jsonPathPaths) *jsonPathItemSet {
+	switch t := expr.(type) {
+	case jsonpath.Root:
+		return &jsonPathItemSet{paths: jsonPathPaths{nil}, exact: true}
+	case jsonpath.Current:
+		if current == nil {
+			return nil
+		}
+		return &jsonPathItemSet{paths: current, exact: true}
+	case jsonpath.Accessor:
+		items := jsonPathItems(t.Base, current)
+		if items == nil || !items.exact {
+			return items
+		}
+		for _, step := range t.Chain {
+			var paths jsonPathPaths
+			var ok bool
+			switch s := step.(type) {
+			case jsonpath.Member:
+				paths, ok = items.paths.extend(
+					[]json.InvertedIndexPathStep{{Key: s.Key}},
+					[]json.InvertedIndexPathStep{{Array: true}, {Key: s.Key}},
+				)
+			case jsonpath.AnyArray, jsonpath.ArrayIndex:
+				paths, ok = items.paths.withElements()
+			case jsonpath.Filter:
+				if paths, ok = items.paths.withElements(); ok {
+					items.cond = jsonPathAnd(items.cond, jsonPathPredicate(s.Predicate, paths))
+				}
+			}
+			if !ok {
+				// The remaining steps can only return items below the paths
+				// found so far.
+				items.exact = false
+				return items
+			}
+			items.paths = paths
+		}
+		return items
+	}
+	return nil
+}
+
+// existsCond returns a condition that must hold for any item to be returned,
+// or nil if there is no such condition.
+func (s *jsonPathItemSet) existsCond() inverted.Expression {
+	if s == nil {
+		return nil
+	}
+	var paths inverted.Expression
+	for _, path := range s.paths {
+		for len(path) > 0 && path[len(path)-1].Array {
+			path = path[:len(path)-1]
+		}
+		if len(path) == 0 {
+			// Every document has a root item.
+			paths = nil
+			break
+		}
+		expr, err := json.EncodePathExistsInvertedIndexSpans(nil /* inKey */, path)
+		if err != nil {
+			panic(err)
+		}
+		paths = jsonPathOr(paths, expr, paths == nil)
+	}
+	return jsonPathAnd(s.cond, paths)
+}
+
+// jsonPathPredicate returns a condition that must hold for the given predicate
+// to be true, or nil if there is no such condition.
+func jsonPathPredicate(expr jsonpath.Expr, current jsonPathPaths) inverted.Expression {
+	switch t := expr.(type) {
+	case jsonpath.Binary:
+		switch t.Op {
+		case jsonpath.OpAnd:
+			return jsonPathAnd(jsonPathPredicate(t.Left, current), jsonPathPredicate(t.Right, current))
+		case jsonpath.OpOr:
+			left := jsonPathPredicate(t.Left, current)
+			right := jsonPathPredicate(t.Right, current)
+			if left == nil || right == nil {
+				return nil
+			}
+			return inverted.Or(left, right)
+		case jsonpath.OpEqual:
+			if val, ok := t.Right.(jsonpath.Scalar); ok {
+				return jsonPathEqual(jsonPathItems(t.Left, current), val.Value)
+			}
+			if val, ok := t.Left.(jsonpath.Scalar); ok {
+				return jsonPathEqual(jsonPathItems(t.Right, current), val.Value)
+			}
+			fallthrough
+		case jsonpath.OpNotEqual, jsonpath.OpLess, jsonpath.OpLessEqual, jsonpath.OpGreater,
+			jsonpath.OpGreaterEqual, jsonpath.OpStartsWith:
+			// A comparison is only true if both operands return items.
+			return jsonPathAnd(
+				jsonPathItems(t.Left, current).existsCond(),
+				jsonPathItems(t.Right, current).existsCond(),
+			)
+		}
+	case jsonpath.Exists:
+		return jsonPathItems(t.Path, current).existsCond()
+	case jsonpath.LikeRegex:
+		return jsonPathItems(t.Expr, current).existsCond()
+	}
+	return nil
+}
+
+// jsonPathEqual returns a condition that must hold for any of the given items
+// to equal val.
+func jsonPathEqual(items *jsonPathItemSet, val json.JSON) inverted.Expression {
+	if items == nil || !items.exact {
+		return items.existsCond()
+	}
+	// Lax mode unwraps arrays in comparisons.
+	paths, ok := items.paths.withElements()
+	if !ok {
+		return items.existsCond()
+	}
+	var vals inverted.Expression
+	for i, path := range paths {
+		expr, err := json.EncodePathValueInvertedIndexSpans(nil /* inKey */, path, val)
+		if err != nil {
+			panic(err)
+		}
+		vals = jsonPathOr(vals, expr, i == 0)
+	}
+	return jsonPathAnd(items.cond, vals)
+}
+
+// jsonPathAnd returns the conjunction of the given conditions, either of which
+// may be nil.
+func jsonPathAnd(left, right inverted.Expression) inverted.Expression {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	return inverted.And(left, right)
+}
+
+// jsonPathOr returns the disjunction of the given conditions, or right if
+// first is true.
+func jsonPathOr(left, right inverted.Expression, first bool) inverted.Expression {
+	if first {
+		return right
+	}
+	return inverted.Or(left, right)
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedidx
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/invertedexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/rangeindex"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+type rangeFilterPlanner struct {
+	tabID           opt.TableID
+	index           cat.Index
+	computedColumns map[opt.ColumnID]opt.ScalarExpr
+}
+
+var _ invertedFilterPlanner = &rangeFilterPlanner{}
+
+// extractInvertedFilterConditionFromLeaf implements the invertedFilterPlanner
+// interface.
+func (r *rangeFilterPlanner) extractInvertedFilterConditionFromLeaf(
+	_ context.Context, _ *eval.Context, expr opt.ScalarExpr,
+) (
+	invertedExpr inverted.Expression,
+	remainingFilters opt.ScalarExpr,
+	_ *invertedexpr.PreFiltererStateForInvertedFilterer,
+) {
+	var left, right opt.ScalarExpr
+	var op func(tree.Datum) (inverted.Expression, error)
+	var commutedOp func(tree.Datum) (inverted.Expression, error)
+	switch e := expr.(type) {
+	case *memo.OverlapsExpr:
+		left, right = e.Left, e.Right
+		op, commutedOp = rangeindex.Overlaps, rangeindex.Overlaps
+	case *memo.ContainsExpr:
+		left, right = e.Left, e.Right
+		op, commutedOp = rangeindex.Contains, rangeindex.ContainedBy
+	case *memo.ContainedByExpr:
+		left, right = e.Left, e.Right
+		op, commutedOp = rangeindex.ContainedBy, rangeindex.Contains
+	default:
+		// Only the above types are supported.
+		return inverted.NonInvertedColExpression{}, expr, nil
+	}
+	var constantVal opt.ScalarExpr
+	if isIndexColumn(r.tabID, r.index, left, r.computedColumns) && memo.CanExtractConstDatum(right) {
+		constantVal = right
+	} else if isIndexColumn(r.tabID, r.index, right, r.computedColumns) && memo.CanExtractConstDatum(left) {
+		// The arguments are commuted, so the commuted form of the operator is
+		// used. For example, x <@ col is evaluated as col @> x.
+		constantVal, op = left, commutedOp
+	} else {
+		// Can only accelerate with a single constant value.
+		return inverted.NonInvertedColExpression{}, expr, nil
+	}
+	d := memo.ExtractConstDatum(constantVal)
+	if d == tree.DNull {
+		return inverted.NonInvertedColExpression{}, expr, nil
+	}
+	var err error
+	invertedExpr, err = op(d)
+	if err != nil || invertedExpr == nil {
+		// An inverted expression could not be extracted.
+		return inverted.NonInvertedColExpression{}, expr, nil
+	}
+
+	// The cell coverings of ranges are approximations, so the inverted
+	// expression is never tight and the remaining filters must be applied
+	// after the inverted index scan.
+	//
+	// We do not currently support pre-filtering for range indexes, so the
+	// returned pre-filter state is nil.
+	return invertedExpr, expr, nil
+}
@@ -203,6 +203,16 @@ func (m *Memo) CheckExpr(e opt.Expr) {
 			}
 		}
 
+	case *GroupingSetsExpr:
+		for i := range t.Sets {
+			if !t.Sets[i].SubsetOf(t.GroupingCols) {
+				panic(errors.AssertionFailedf("grouping set %s is not a subset of the grouping columns", t.Sets[i]))
+			}
+		}
+		if t.GroupingCols.Contains(t.SetIDCol) {
+			panic(errors.AssertionFailedf("grouping set column is a grouping column"))
+		}
+
 	case *IndexJoinExpr:
 		if t.Cols.Empty() {
 			panic(errors.AssertionFailedf("index join with no columns"))
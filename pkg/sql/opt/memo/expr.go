@@ -755,6 +755,33 @@ type ExceptionBlock struct {
 	Actions []*UDFDefinition
 }
 
+// UserDefinedAggDefinition stores details about a user-defined aggregate
+// function.
+type UserDefinedAggDefinition struct {
+	// Name is the name of the aggregate.
+	Name string
+
+	// Typ is the return type of the aggregate.
+	Typ *types.T
+
+	// StateType is the type of the aggregate state.
+	StateType *types.T
+
+	// InitCond is the initial value of the aggregate state. It is DNull if the
+	// aggregate has no initial condition.
+	InitCond tree.Datum
+
+	// Transition is a call to the state transition function of the aggregate.
+	// The arguments of the call are placeholders; the actual arguments are
+	// supplied for each input row during execution.
+	Transition *UDFCallExpr
+
+	// Final is a call to the final function of the aggregate, or nil if the
+	// result of the aggregate is its final state. Like Transition, its
+	// arguments are supplied during execution.
+	Final *UDFCallExpr
+}
+
 // WindowFrame denotes the definition of a window frame for an individual
 // window function, excluding the OFFSET expressions, if present.
 type WindowFrame struct {
@@ -814,7 +841,8 @@ func (s *ScanPrivate) IsCanonical() bool {
 	return s.Index == cat.PrimaryIndex &&
 		s.Constraint == nil &&
 		s.HardLimit == 0 &&
-		!s.LocalityOptimized
+		!s.LocalityOptimized &&
+		!s.Sample.IsSampled()
 }
 
 // IsUnfiltered returns true if the ScanPrivate will produce all rows in the
@@ -824,7 +852,8 @@ func (s *ScanPrivate) IsUnfiltered(md *opt.Metadata) bool {
 		s.InvertedConstraint == nil &&
 		s.HardLimit == 0 &&
 		s.PartialIndexPredicate(md) == nil &&
-		s.Locking.WaitPolicy != tree.LockWaitSkipLocked
+		s.Locking.WaitPolicy != tree.LockWaitSkipLocked &&
+		!s.Sample.IsSampled()
 }
 
 // IsFullIndexScan returns true if the ScanPrivate will produce all rows in the
@@ -832,7 +861,8 @@ func (s *ScanPrivate) IsUnfiltered(md *opt.Metadata) bool {
 func (s *ScanPrivate) IsFullIndexScan(md *opt.Metadata) bool {
 	return (s.Constraint == nil || s.Constraint.IsUnconstrained()) &&
 		s.InvertedConstraint == nil &&
-		s.HardLimit == 0
+		s.HardLimit == 0 &&
+		s.Sample.Method != opt.SystemTableSample
 }
 
 // IsVirtualTable returns true if the table being scanned is a virtual table.
@@ -1308,8 +1338,10 @@ type FKCascades []FKCascade
 
 // FKCascade stores metadata necessary for building a cascading query.
 // Cascading queries are built as needed, after the original query is executed.
+// Cascades are also used to execute the AFTER triggers of a mutation, as well
+// as the BEFORE STATEMENT triggers of the child table of a cascading FK.
 type FKCascade struct {
-	// FKName is the name of the FK constraint.
+	// FKName is the name of the FK constraint, or the name of the trigger.
 	FKName string
 
 	// Builder is an object that can be used as the "optbuilder" for the cascading
@@ -1322,7 +1354,8 @@ type FKCascade struct {
 
 	// OldValues are column IDs from the mutation input that correspond to the
 	// old values of the modified rows. The list maps 1-to-1 to foreign key
-	// columns. Empty if the cascade does not require input.
+	// columns (or to the columns of the OLD row of a trigger). Empty if the
+	// cascade does not require input.
 	OldValues opt.ColList
 
 	// NewValues are column IDs from the mutation input that correspond to the
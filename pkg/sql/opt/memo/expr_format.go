@@ -388,6 +388,18 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 			tp.Childf("error: \"%s\"", private.ErrorOnDup)
 		}
 
+	case *GroupingSetsExpr:
+		if !f.HasFlags(ExprFmtHideColumns) {
+			if !t.GroupingCols.Empty() {
+				f.formatRelColList(e, tp, "grouping columns:", t.GroupingCols.ToList())
+			}
+			tp.Childf("grouping sets: %s", t.Sets)
+			f.formatRelColList(e, tp, "grouping set column:", opt.ColList{t.SetIDCol})
+		}
+		if !f.HasFlags(ExprFmtHidePhysProps) && !t.Ordering.Any() {
+			tp.Childf("internal-ordering: %s", t.Ordering)
+		}
+
 	case *TopKExpr:
 		if !f.HasFlags(ExprFmtHidePhysProps) && !t.Ordering.Any() {
 			tp.Childf("internal-ordering: %s", t.Ordering)
@@ -490,6 +502,9 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		if private.HardLimit.IsSet() {
 			tp.Childf("limit: %s", private.HardLimit)
 		}
+		if private.Sample.IsSampled() {
+			tp.Childf("sample: %s", private.Sample)
+		}
 
 		if private.shouldPrintFlags(md, f.HasFlags(ExprFmtHideNotVisibleIndexInfo)) {
 			var b strings.Builder
@@ -672,6 +687,9 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 			}
 			if t.CanaryCol != 0 {
 				f.formatRelColList(e, tp, "canary column:", opt.ColList{t.CanaryCol})
+				if t.MergeActionCol != 0 {
+					f.formatRelColList(e, tp, "merge action column:", opt.ColList{t.MergeActionCol})
+				}
 				f.formatOptionalColList(e, tp, "fetch columns:", t.FetchCols)
 				f.formatMutationCols(e, tp, "insert-mapping:", t.InsertCols, t.Table)
 				f.formatMutationCols(e, tp, "update-mapping:", t.UpdateCols, t.Table)
@@ -1251,6 +1269,19 @@ func (f *ExprFmtCtx) formatScalarPrivate(scalar opt.ScalarExpr) {
 
 	case *UniqueChecksItem:
 		tab := f.Memo.metadata.TableMeta(t.Table)
+		if t.Exclusion {
+			constraint := tab.Table.Exclusion(t.CheckOrdinal)
+			fmt.Fprintf(f.Buffer, ": %s(", tab.Alias.ObjectName)
+			for i := 0; i < constraint.ColumnCount(); i++ {
+				if i > 0 {
+					f.Buffer.WriteByte(',')
+				}
+				col := tab.Table.Column(constraint.ColumnOrdinal(tab.Table, i))
+				fmt.Fprintf(f.Buffer, "%s %s", col.ColName(), constraint.Operator(i))
+			}
+			f.Buffer.WriteByte(')')
+			break
+		}
 		constraint := tab.Table.Unique(t.CheckOrdinal)
 		fmt.Fprintf(f.Buffer, ": %s(", tab.Alias.ObjectName)
 		for i := 0; i < constraint.ColumnCount(); i++ {
@@ -1662,7 +1693,7 @@ func (f *ExprFmtCtx) formatDependencies(
 		n.Child(f.Buffer.String())
 	}
 	for _, typ := range f.Memo.Metadata().AllUserDefinedTypes() {
-		typeID := catid.UserDefinedOIDToID(typ.Oid())
+		typeID := catid.UserDefinedOIDToID(typ.UserDefinedTypeOID())
 		if typeDeps.Contains(int(typeID)) {
 			n.Child(typ.Name())
 		}
@@ -1717,6 +1748,12 @@ func FormatPrivate(f *ExprFmtCtx, private interface{}, physProps *physical.Requi
 			fmt.Fprintf(f.Buffer, ",ordering=%s", t.Ordering)
 		}
 
+	case *GroupingSetsPrivate:
+		fmt.Fprintf(f.Buffer, " cols=%s,sets=%s", t.GroupingCols.String(), t.Sets.String())
+		if !t.Ordering.Any() {
+			fmt.Fprintf(f.Buffer, ",ordering=%s", t.Ordering)
+		}
+
 	case *SetPrivate:
 		if !t.Ordering.Any() {
 			fmt.Fprintf(f.Buffer, " ordering=%s", t.Ordering)
@@ -1749,6 +1786,9 @@ func FormatPrivate(f *ExprFmtCtx, private interface{}, physProps *physical.Requi
 	case *FunctionPrivate:
 		fmt.Fprintf(f.Buffer, " %s", t.Name)
 
+	case *UserDefinedAggPrivate:
+		fmt.Fprintf(f.Buffer, " %s", t.Def.Name)
+
 	case *WindowsItemPrivate:
 		fmt.Fprintf(f.Buffer, " frame=%q", &t.Frame)
 
@@ -1796,6 +1836,11 @@ func FormatPrivate(f *ExprFmtCtx, private interface{}, physProps *physical.Requi
 	case *JoinPrivate:
 		// Nothing to show; flags are shown separately.
 
+	case *BarrierPrivate:
+		if t.SecurityBarrier {
+			f.Buffer.WriteString(" security")
+		}
+
 	case *ExplainPrivate, *opt.ColSet, *types.T, *ExportPrivate:
 		// Don't show anything, because it's mostly redundant.
 
@@ -133,8 +133,8 @@ func ExtractAggInputColumns(e opt.ScalarExpr) opt.ColSet {
 		panic(errors.AssertionFailedf("not an Aggregate"))
 	}
 
-	for i, n := 0, e.ChildCount(); i < n; i++ {
-		if variable, ok := e.Child(i).(*VariableExpr); ok {
+	for _, arg := range ExtractAggArgs(e) {
+		if variable, ok := arg.(*VariableExpr); ok {
 			res.Add(variable.Col)
 		}
 	}
@@ -142,15 +142,29 @@ func ExtractAggInputColumns(e opt.ScalarExpr) opt.ColSet {
 	return res
 }
 
+// ExtractAggArgs returns the arguments of the given aggregate function. The
+// arguments of most aggregates are their children, but the arguments of a
+// user-defined aggregate are stored in a list.
+func ExtractAggArgs(e opt.ScalarExpr) []opt.ScalarExpr {
+	if udAgg, ok := e.(*UserDefinedAggExpr); ok {
+		return udAgg.Args
+	}
+	args := make([]opt.ScalarExpr, e.ChildCount())
+	for i := range args {
+		args[i] = e.Child(i).(opt.ScalarExpr)
+	}
+	return args
+}
+
 // ExtractAggFirstVar is given an aggregate expression and returns the Variable
 // expression for the first argument, skipping past modifiers like AggDistinct.
 func ExtractAggFirstVar(e opt.ScalarExpr) *VariableExpr {
-	e = ExtractAggFunc(e)
-	if e.ChildCount() == 0 {
+	args := ExtractAggArgs(ExtractAggFunc(e))
+	if len(args) == 0 {
 		panic(errors.AssertionFailedf("aggregate does not have any arguments"))
 	}
 
-	if variable, ok := e.Child(0).(*VariableExpr); ok {
+	if variable, ok := args[0].(*VariableExpr); ok {
 		return variable
 	}
 
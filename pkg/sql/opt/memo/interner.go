@@ -645,6 +645,20 @@ func (h *hasher) HashLocking(val opt.Locking) {
 	h.HashByte(byte(val.WaitPolicy))
 }
 
+func (h *hasher) HashTableSample(val opt.TableSample) {
+	h.HashByte(byte(val.Method))
+	h.HashFloat64(val.Fraction)
+	h.HashBool(val.Repeatable)
+	h.HashFloat64(val.Seed)
+}
+
+func (h *hasher) HashGroupingSetList(val opt.GroupingSetList) {
+	h.HashInt(len(val))
+	for i := range val {
+		h.HashColSet(val[i])
+	}
+}
+
 func (h *hasher) HashInvertedSpans(val inverted.Spans) {
 	for i := range val {
 		span := &val[i]
@@ -777,6 +791,10 @@ func (h *hasher) HashUDFDefinition(val *UDFDefinition) {
 	h.HashUint64(uint64(reflect.ValueOf(val).Pointer()))
 }
 
+func (h *hasher) HashUserDefinedAggDefinition(val *UserDefinedAggDefinition) {
+	h.HashUint64(uint64(reflect.ValueOf(val).Pointer()))
+}
+
 // ----------------------------------------------------------------------
 //
 // Equality functions
@@ -933,6 +951,10 @@ func (h *hasher) IsColListEqual(l, r opt.ColList) bool {
 	return l.Equals(r)
 }
 
+func (h *hasher) IsGroupingSetListEqual(l, r opt.GroupingSetList) bool {
+	return l.Equals(r)
+}
+
 func (h *hasher) IsOptionalColListEqual(l, r opt.OptionalColList) bool {
 	return l.Equals(r)
 }
@@ -1076,6 +1098,10 @@ func (h *hasher) IsLockingEqual(l, r opt.Locking) bool {
 	return l == r
 }
 
+func (h *hasher) IsTableSampleEqual(l, r opt.TableSample) bool {
+	return l == r
+}
+
 func (h *hasher) IsInvertedSpansEqual(l, r inverted.Spans) bool {
 	return l.Equals(r)
 }
@@ -1269,6 +1295,10 @@ func (h *hasher) IsLiteralRowsEqual(l, r *opt.LiteralRows) bool {
 	return l == r
 }
 
+func (h *hasher) IsUserDefinedAggDefinitionEqual(l, r *UserDefinedAggDefinition) bool {
+	return l == r
+}
+
 func (h *hasher) IsUDFDefinitionEqual(l, r *UDFDefinition) bool {
 	if len(l.Body) != len(r.Body) {
 		return false
@@ -539,6 +539,54 @@ func TestInterner(t *testing.T) {
 			},
 		}},
 
+		{hashFn: in.hasher.HashTableSample, eqFn: in.hasher.IsTableSampleEqual, variations: []testVariation{
+			{val1: opt.TableSample{}, val2: opt.TableSample{}, equal: true},
+			{
+				val1:  opt.TableSample{},
+				val2:  opt.TableSample{Method: opt.BernoulliTableSample, Fraction: 0.1},
+				equal: false,
+			},
+			{
+				val1:  opt.TableSample{Method: opt.BernoulliTableSample, Fraction: 0.1},
+				val2:  opt.TableSample{Method: opt.SystemTableSample, Fraction: 0.1},
+				equal: false,
+			},
+			{
+				val1:  opt.TableSample{Method: opt.SystemTableSample, Fraction: 0.1},
+				val2:  opt.TableSample{Method: opt.SystemTableSample, Fraction: 0.2},
+				equal: false,
+			},
+			{
+				val1:  opt.TableSample{Method: opt.SystemTableSample, Fraction: 0.1, Repeatable: true, Seed: 1},
+				val2:  opt.TableSample{Method: opt.SystemTableSample, Fraction: 0.1, Repeatable: true, Seed: 2},
+				equal: false,
+			},
+			{
+				val1:  opt.TableSample{Method: opt.SystemTableSample, Fraction: 0.1, Repeatable: true, Seed: 1},
+				val2:  opt.TableSample{Method: opt.SystemTableSample, Fraction: 0.1, Repeatable: true, Seed: 1},
+				equal: true,
+			},
+		}},
+
+		{hashFn: in.hasher.HashGroupingSetList, eqFn: in.hasher.IsGroupingSetListEqual, variations: []testVariation{
+			{val1: opt.GroupingSetList{}, val2: opt.GroupingSetList{}, equal: true},
+			{
+				val1:  opt.GroupingSetList{opt.MakeColSet(1, 2), opt.MakeColSet(1)},
+				val2:  opt.GroupingSetList{opt.MakeColSet(1, 2), opt.MakeColSet(1)},
+				equal: true,
+			},
+			{
+				val1:  opt.GroupingSetList{opt.MakeColSet(1, 2), opt.MakeColSet(1)},
+				val2:  opt.GroupingSetList{opt.MakeColSet(1), opt.MakeColSet(1, 2)},
+				equal: false,
+			},
+			{
+				val1:  opt.GroupingSetList{opt.ColSet{}},
+				val2:  opt.GroupingSetList{opt.ColSet{}, opt.ColSet{}},
+				equal: false,
+			},
+		}},
+
 		{hashFn: in.hasher.HashFastPathUniqueChecksExpr, eqFn: in.hasher.IsFastPathUniqueChecksExprEqual, variations: []testVariation{
 			{
 				val1:  FastPathUniqueChecksExpr{FastPathUniqueChecksItem{Check: scanNode}},
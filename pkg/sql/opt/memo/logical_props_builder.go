@@ -174,10 +174,10 @@ func (b *logicalPropsBuilder) buildScanProps(scan *ScanExpr, rel *props.Relation
 		}
 		b.updateCardinalityFromTypes(rel.OutputCols, rel)
 	}
-	if scan.Locking.WaitPolicy == tree.LockWaitSkipLocked {
-		// SKIP LOCKED can act like a filter. The minimum cardinality of a scan
-		// should never exceed zero based on the logic above, but this provides
-		// extra safety.
+	if scan.Locking.WaitPolicy == tree.LockWaitSkipLocked || scan.Sample.IsSampled() {
+		// SKIP LOCKED and TABLESAMPLE can act like a filter. The minimum
+		// cardinality of a scan should never exceed zero based on the logic
+		// above, but this provides extra safety.
 		rel.Cardinality = rel.Cardinality.AsLowAs(0)
 	}
 
@@ -552,6 +552,97 @@ func (b *logicalPropsBuilder) buildScalarGroupByProps(
 	b.buildGroupingExprProps(scalarGroupBy, rel)
 }
 
+func (b *logicalPropsBuilder) buildGroupingSetsProps(
+	groupingSets *GroupingSetsExpr, rel *props.Relational,
+) {
+	BuildSharedProps(groupingSets, &rel.Shared, b.evalCtx)
+
+	inputProps := groupingSets.Input.Relational()
+	aggs := groupingSets.Aggregations
+	private := &groupingSets.GroupingSetsPrivate
+	groupingCols := private.GroupingCols
+
+	// Output Columns
+	// --------------
+	// Output columns are the union of grouping columns with columns from the
+	// aggregate projection list, and the grouping set ID column.
+	rel.OutputCols = groupingCols.Copy()
+	for i := range aggs {
+		rel.OutputCols.Add(aggs[i].Col)
+	}
+	rel.OutputCols.Add(private.SetIDCol)
+
+	// Not Null Columns
+	// ----------------
+	// A grouping column is NULL in the rows of the grouping sets that don't
+	// contain it, so the not null setting of an input column is only
+	// propagated if the column is part of every grouping set.
+	rel.NotNullCols = inputProps.NotNullCols.Intersection(groupingCols)
+	hasEmptySet := false
+	for _, set := range private.Sets {
+		rel.NotNullCols.IntersectionWith(set)
+		hasEmptySet = hasEmptySet || set.Empty()
+	}
+	rel.NotNullCols.Add(private.SetIDCol)
+
+	for i := range aggs {
+		item := &aggs[i]
+		agg := ExtractAggFunc(item.Agg)
+
+		// Some aggregates never return NULL, regardless of input.
+		if opt.AggregateIsNeverNull(agg.Op()) {
+			rel.NotNullCols.Add(item.Col)
+			continue
+		}
+
+		// The row of an empty grouping set may have zero input rows, like
+		// ScalarGroupBy.
+		if hasEmptySet || item.Agg.Op() == opt.AggFilterOp {
+			continue
+		}
+		if opt.AggregateIsNeverNullOnNonNullInput(agg.Op()) {
+			inputCols := ExtractAggInputColumns(agg)
+			if inputCols.SubsetOf(inputProps.NotNullCols) {
+				rel.NotNullCols.Add(item.Col)
+			}
+		}
+	}
+
+	// Outer Columns
+	// -------------
+	// Outer columns were derived by BuildSharedProps; remove any that are bound
+	// by input columns.
+	rel.OuterCols.DifferenceWith(inputProps.OutputCols)
+
+	// Functional Dependencies
+	// -----------------------
+	// The dependencies of the input are not inherited, since they don't hold
+	// for the grouping columns that are replaced by NULL. Within each grouping
+	// set, the grouping columns form a strict key.
+	key := groupingCols.Copy()
+	key.Add(private.SetIDCol)
+	rel.FuncDeps.AddStrictKey(key, rel.OutputCols)
+
+	// Cardinality
+	// -----------
+	// Each empty grouping set returns exactly one row. Each other grouping set
+	// acts like a GroupBy.
+	rel.Cardinality = props.ZeroCardinality
+	for _, set := range private.Sets {
+		if set.Empty() {
+			rel.Cardinality = rel.Cardinality.Add(props.OneCardinality)
+		} else {
+			rel.Cardinality = rel.Cardinality.Add(inputProps.Cardinality.AsLowAs(1))
+		}
+	}
+
+	// Statistics
+	// ----------
+	if !b.disableStats {
+		b.sb.buildGroupingSets(groupingSets, rel)
+	}
+}
+
 func (b *logicalPropsBuilder) buildDistinctOnProps(
 	distinctOn *DistinctOnExpr, rel *props.Relational,
 ) {
@@ -1106,7 +1197,7 @@ func (b *logicalPropsBuilder) buildExportProps(export *ExportExpr, rel *props.Re
 }
 
 func (b *logicalPropsBuilder) buildCallProps(c *CallExpr, rel *props.Relational) {
-	b.buildBasicProps(c, opt.ColList{}, rel)
+	b.buildBasicProps(c, c.Columns, rel)
 }
 
 func (b *logicalPropsBuilder) buildTopKProps(topK *TopKExpr, rel *props.Relational) {
@@ -1699,6 +1790,15 @@ func BuildSharedProps(e opt.Expr, shared *props.Shared, evalCtx *eval.Context) {
 		shared.HasUDF = true
 		shared.VolatilitySet.Add(t.Def.Volatility)
 
+	case *UserDefinedAggExpr:
+		// The volatility of a user-defined aggregate is determined by its support
+		// functions.
+		shared.HasUDF = true
+		shared.VolatilitySet.Add(t.Def.Transition.Def.Volatility)
+		if t.Def.Final != nil {
+			shared.VolatilitySet.Add(t.Def.Final.Def.Volatility)
+		}
+
 	default:
 		if opt.IsUnaryOp(e) {
 			inputType := e.Child(0).(opt.ScalarExpr).DataType()
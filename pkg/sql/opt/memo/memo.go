@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
 	"github.com/cockroachdb/errors"
@@ -175,6 +176,8 @@ type Memo struct {
 	useLockOpForSerializable                   bool
 	useProvidedOrderingFix                     bool
 	mergeJoinsEnabled                          bool
+	constraintsMode                            sessiondatapb.ConstraintsMode
+	constraintsModeOverrides                   []sessiondatapb.ConstraintsModeOverride
 
 	// txnIsoLevel is the isolation level under which the plan was created. This
 	// affects the planning of some locking operations, so it must be included in
@@ -248,6 +251,8 @@ func (m *Memo) Init(ctx context.Context, evalCtx *eval.Context) {
 		useLockOpForSerializable:                   evalCtx.SessionData().OptimizerUseLockOpForSerializable,
 		useProvidedOrderingFix:                     evalCtx.SessionData().OptimizerUseProvidedOrderingFix,
 		mergeJoinsEnabled:                          evalCtx.SessionData().OptimizerMergeJoinsEnabled,
+		constraintsMode:                            evalCtx.SessionData().ConstraintsMode,
+		constraintsModeOverrides:                   evalCtx.SessionData().ConstraintsModeOverrides,
 		txnIsoLevel:                                evalCtx.TxnIsoLevel,
 	}
 	m.metadata.Init()
@@ -395,6 +400,10 @@ func (m *Memo) IsStale(
 		m.useLockOpForSerializable != evalCtx.SessionData().OptimizerUseLockOpForSerializable ||
 		m.useProvidedOrderingFix != evalCtx.SessionData().OptimizerUseProvidedOrderingFix ||
 		m.mergeJoinsEnabled != evalCtx.SessionData().OptimizerMergeJoinsEnabled ||
+		m.constraintsMode != evalCtx.SessionData().ConstraintsMode ||
+		!sessiondatapb.ConstraintsModeOverridesEqual(
+			m.constraintsModeOverrides, evalCtx.SessionData().ConstraintsModeOverrides,
+		) ||
 		m.txnIsoLevel != evalCtx.TxnIsoLevel {
 		return true, nil
 	}
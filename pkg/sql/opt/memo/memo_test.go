@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/datapathutils"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
@@ -430,6 +431,20 @@ func TestMemoIsStale(t *testing.T) {
 	evalCtx.SessionData().OptimizerMergeJoinsEnabled = false
 	notStale()
 
+	// Stale SET CONSTRAINTS mode.
+	evalCtx.SessionData().ConstraintsMode = sessiondatapb.ConstraintsDeferred
+	stale()
+	evalCtx.SessionData().ConstraintsMode = sessiondatapb.ConstraintsDefault
+	notStale()
+
+	// Stale SET CONSTRAINTS mode for a single constraint.
+	evalCtx.SessionData().ConstraintsModeOverrides = []sessiondatapb.ConstraintsModeOverride{
+		{TableID: 53, ConstraintName: "abc_fk", Mode: sessiondatapb.ConstraintsDeferred},
+	}
+	stale()
+	evalCtx.SessionData().ConstraintsModeOverrides = nil
+	notStale()
+
 	// User no longer has access to view.
 	catalog.View(tree.NewTableNameWithSchema("t", catconstants.PublicSchemaName, "abcview")).Revoked = true
 	_, err = o.Memo().IsStale(ctx, &evalCtx, catalog)
@@ -453,6 +453,9 @@ func (sb *statisticsBuilder) colStat(colSet opt.ColSet, e RelExpr) *props.Column
 		opt.UpsertDistinctOnOp, opt.EnsureUpsertDistinctOnOp:
 		return sb.colStatGroupBy(colSet, e)
 
+	case opt.GroupingSetsOp:
+		return sb.colStatGroupingSets(colSet, e.(*GroupingSetsExpr))
+
 	case opt.LimitOp:
 		return sb.colStatLimit(colSet, e.(*LimitExpr))
 
@@ -804,8 +807,13 @@ func (sb *statisticsBuilder) buildScan(scan *ScanExpr, relProps *props.Relationa
 
 	// If the constraints and pred are nil, then this scan is an unconstrained
 	// scan on a non-partial index. The stats of the scan are the same as the
-	// underlying table stats.
+	// underlying table stats, scaled down by the sample fraction if the scan is
+	// sampled. Sampled scans are never constrained, since they are not
+	// canonical.
 	if scan.Constraint == nil && scan.InvertedConstraint == nil && pred == nil {
+		if scan.Sample.IsSampled() {
+			s.ApplySelectivity(props.MakeSelectivity(scan.Sample.Fraction))
+		}
 		sb.finalizeFromCardinality(relProps)
 		return
 	}
@@ -2010,6 +2018,87 @@ func (sb *statisticsBuilder) colStatGroupBy(
 	return colStat
 }
 
+// +---------------+
+// | Grouping Sets |
+// +---------------+
+
+func (sb *statisticsBuilder) buildGroupingSets(
+	groupingSets *GroupingSetsExpr, relProps *props.Relational,
+) {
+	s := relProps.Statistics()
+	if zeroCardinality := s.Init(relProps); zeroCardinality {
+		// Short cut if cardinality is 0.
+		return
+	}
+	s.Available = sb.availabilityFromInput(groupingSets)
+
+	// Each grouping set is estimated like a GroupBy on the columns of the set.
+	s.RowCount = 0
+	for _, set := range groupingSets.Sets {
+		s.RowCount += sb.groupingSetRowCount(groupingSets, set)
+	}
+
+	sb.finalizeFromCardinality(relProps)
+}
+
+// groupingSetRowCount returns the estimated number of rows produced for the
+// given grouping set.
+func (sb *statisticsBuilder) groupingSetRowCount(
+	groupingSets *GroupingSetsExpr, set opt.ColSet,
+) float64 {
+	if set.Empty() {
+		// An empty grouping set always returns exactly one row.
+		return 1
+	}
+	inputStats := sb.statsFromChild(groupingSets, 0 /* childIdx */)
+	inputColStat := sb.colStatFromChild(set, groupingSets, 0 /* childIdx */)
+	return min(inputColStat.DistinctCount, inputStats.RowCount)
+}
+
+func (sb *statisticsBuilder) colStatGroupingSets(
+	colSet opt.ColSet, groupingSets *GroupingSetsExpr,
+) *props.ColumnStatistic {
+	relProps := groupingSets.Relational()
+	s := relProps.Statistics()
+	private := &groupingSets.GroupingSetsPrivate
+
+	colStat, _ := s.ColStats.Add(colSet)
+	if !colSet.SubsetOf(private.GroupingCols) {
+		// Some of the requested columns are aggregates or the grouping set ID
+		// column. Estimate that every row has distinct values.
+		colStat.DistinctCount = s.RowCount
+		colStat.NullCount = 0
+	} else {
+		// The values in the rows of each grouping set are the distinct values
+		// of the columns that are part of the set, with NULL for the other
+		// columns.
+		colStat.DistinctCount = 0
+		colStat.NullCount = 0
+		for _, set := range private.Sets {
+			rowCount := sb.groupingSetRowCount(groupingSets, set)
+			cols := colSet.Intersection(set)
+			if cols.Empty() {
+				colStat.DistinctCount++
+				colStat.NullCount += rowCount
+				continue
+			}
+			inputColStat := sb.colStatFromChild(cols, groupingSets, 0 /* childIdx */)
+			colStat.DistinctCount += min(inputColStat.DistinctCount, rowCount)
+			if colSet.SubsetOf(set) {
+				colStat.NullCount += min(1, inputColStat.NullCount)
+			} else {
+				colStat.NullCount += rowCount
+			}
+		}
+	}
+
+	if colSet.Intersects(relProps.NotNullCols) {
+		colStat.NullCount = 0
+	}
+	sb.finalizeFromRowCountAndDistinctCounts(colStat, s)
+	return colStat
+}
+
 // +--------+
 // | Set Op |
 // +--------+
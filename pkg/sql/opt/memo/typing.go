@@ -192,6 +192,7 @@ func init() {
 	typingFuncMap[opt.ArrayFlattenOp] = typeArrayFlatten
 	typingFuncMap[opt.IfErrOp] = typeIfErr
 	typingFuncMap[opt.UDFCallOp] = typeUDFCall
+	typingFuncMap[opt.UserDefinedAggOp] = typeUserDefinedAgg
 
 	// Override default typeAsAggregate behavior for aggregate functions with
 	// a large number of possible overloads or where ReturnType depends on
@@ -391,6 +392,12 @@ func typeUDFCall(e opt.ScalarExpr) *types.T {
 	return e.(*UDFCallExpr).Def.Typ
 }
 
+// typeUserDefinedAgg returns the type of a user-defined aggregate, which is
+// stored in its definition.
+func typeUserDefinedAgg(e opt.ScalarExpr) *types.T {
+	return e.(*UserDefinedAggExpr).Def.Typ
+}
+
 // typeSubquery returns the type of a subquery, which is equal to the type of
 // its first (and only) column.
 func typeSubquery(e opt.ScalarExpr) *types.T {
@@ -141,6 +141,11 @@ type Metadata struct {
 	// as a builtin function.
 	builtinRefsByName map[tree.UnresolvedName]struct{}
 
+	// rowLevelSecurityDeps stores, for each table with row-level security
+	// enabled that the query depends on, how the policies of the table applied
+	// to the user the query was built for.
+	rowLevelSecurityDeps map[cat.StableID]RowLevelSecurityState
+
 	// NOTE! When adding fields here, update Init (if reusing allocated
 	// data structures is desired), CopyFrom and TestMetadata.
 }
@@ -214,6 +219,11 @@ func (md *Metadata) Init() {
 		delete(md.builtinRefsByName, name)
 	}
 
+	rowLevelSecurityDeps := md.rowLevelSecurityDeps
+	for id := range md.rowLevelSecurityDeps {
+		delete(md.rowLevelSecurityDeps, id)
+	}
+
 	// This initialization pattern ensures that fields are not unwittingly
 	// reused. Field reuse must be explicit.
 	*md = Metadata{}
@@ -227,6 +237,7 @@ func (md *Metadata) Init() {
 	md.objectRefsByName = objectRefsByName
 	md.privileges = privileges
 	md.builtinRefsByName = builtinRefsByName
+	md.rowLevelSecurityDeps = rowLevelSecurityDeps
 }
 
 // CopyFrom initializes the metadata with a copy of the provided metadata.
@@ -242,7 +253,7 @@ func (md *Metadata) CopyFrom(from *Metadata, copyScalarFn func(Expr) Expr) {
 		len(md.sequences) != 0 || len(md.views) != 0 || len(md.userDefinedTypes) != 0 ||
 		len(md.userDefinedTypesSlice) != 0 || len(md.dataSourceDeps) != 0 ||
 		len(md.udfDeps) != 0 || len(md.objectRefsByName) != 0 || len(md.privileges) != 0 ||
-		len(md.builtinRefsByName) != 0 {
+		len(md.builtinRefsByName) != 0 || len(md.rowLevelSecurityDeps) != 0 {
 		panic(errors.AssertionFailedf("CopyFrom requires empty destination"))
 	}
 	md.schemas = append(md.schemas, from.schemas...)
@@ -254,7 +265,7 @@ func (md *Metadata) CopyFrom(from *Metadata, copyScalarFn func(Expr) Expr) {
 		}
 		for i := range from.userDefinedTypesSlice {
 			typ := from.userDefinedTypesSlice[i]
-			md.userDefinedTypes[typ.Oid()] = struct{}{}
+			md.userDefinedTypes[typ.UserDefinedTypeOID()] = struct{}{}
 			md.userDefinedTypesSlice = append(md.userDefinedTypesSlice, typ)
 		}
 	}
@@ -315,6 +326,13 @@ func (md *Metadata) CopyFrom(from *Metadata, copyScalarFn func(Expr) Expr) {
 		md.builtinRefsByName[name] = struct{}{}
 	}
 
+	for id, state := range from.rowLevelSecurityDeps {
+		if md.rowLevelSecurityDeps == nil {
+			md.rowLevelSecurityDeps = make(map[cat.StableID]RowLevelSecurityState)
+		}
+		md.rowLevelSecurityDeps[id] = state
+	}
+
 	md.sequences = append(md.sequences, from.sequences...)
 	md.views = append(md.views, from.views...)
 	md.currUniqueID = from.currUniqueID
@@ -406,17 +424,17 @@ func (md *Metadata) CheckDependencies(
 
 	// Check that no referenced user defined types have changed.
 	for _, typ := range md.AllUserDefinedTypes() {
-		id := cat.StableID(catid.UserDefinedOIDToID(typ.Oid()))
+		id := cat.StableID(catid.UserDefinedOIDToID(typ.UserDefinedTypeOID()))
 		if names, ok := md.objectRefsByName[id]; ok {
 			for _, name := range names {
 				toCheck, err := optCatalog.ResolveType(ctx, name)
-				if err != nil || typ.Oid() != toCheck.Oid() ||
+				if err != nil || typ.UserDefinedTypeOID() != toCheck.UserDefinedTypeOID() ||
 					typ.TypeMeta.Version != toCheck.TypeMeta.Version {
 					return false, maybeSwallowMetadataResolveErr(err)
 				}
 			}
 		} else {
-			toCheck, err := optCatalog.ResolveTypeByOID(ctx, typ.Oid())
+			toCheck, err := optCatalog.ResolveTypeByOID(ctx, typ.UserDefinedTypeOID())
 			if err != nil || typ.TypeMeta.Version != toCheck.TypeMeta.Version {
 				return false, maybeSwallowMetadataResolveErr(err)
 			}
@@ -481,6 +499,20 @@ func (md *Metadata) CheckDependencies(
 		}
 	}
 
+	// Check that the row-level security policies of the referenced tables apply
+	// to the current user in the same way as they did to the user the query was
+	// built for.
+	for id, state := range md.rowLevelSecurityDeps {
+		tab, ok := md.dataSourceDeps[id].(cat.Table)
+		if !ok {
+			return false, nil
+		}
+		toCheck, err := GetRowLevelSecurityState(ctx, optCatalog, tab)
+		if err != nil || !state.Equals(&toCheck) {
+			return false, err
+		}
+	}
+
 	return true, nil
 }
 
@@ -548,12 +580,12 @@ func (md *Metadata) AddUserDefinedType(typ *types.T, name *tree.UnresolvedObject
 	if md.userDefinedTypes == nil {
 		md.userDefinedTypes = make(map[oid.Oid]struct{})
 	}
-	if _, ok := md.userDefinedTypes[typ.Oid()]; !ok {
-		md.userDefinedTypes[typ.Oid()] = struct{}{}
+	if _, ok := md.userDefinedTypes[typ.UserDefinedTypeOID()]; !ok {
+		md.userDefinedTypes[typ.UserDefinedTypeOID()] = struct{}{}
 		md.userDefinedTypesSlice = append(md.userDefinedTypesSlice, typ)
 	}
 	if name != nil {
-		id := cat.StableID(catid.UserDefinedOIDToID(typ.Oid()))
+		id := cat.StableID(catid.UserDefinedOIDToID(typ.UserDefinedTypeOID()))
 		md.objectRefsByName[id] = append(md.objectRefsByName[id], name)
 	}
 }
@@ -596,6 +628,35 @@ func (md *Metadata) AddBuiltin(name *tree.UnresolvedObjectName) {
 	md.builtinRefsByName[*name.ToUnresolvedName()] = struct{}{}
 }
 
+// RowLevelSecurityState returns how the row-level security policies of the
+// given table apply to the current user. The result is recorded as a
+// dependency of the query, so that the query is rebuilt if it is reused by a
+// user to whom the policies apply differently.
+func (md *Metadata) RowLevelSecurityState(
+	ctx context.Context, catalog cat.Catalog, tab cat.Table,
+) (RowLevelSecurityState, error) {
+	if state, ok := md.rowLevelSecurityDeps[tab.ID()]; ok {
+		return state, nil
+	}
+	state, err := GetRowLevelSecurityState(ctx, catalog, tab)
+	if err != nil {
+		return RowLevelSecurityState{}, err
+	}
+	if tab.IsRowLevelSecurityEnabled() {
+		md.AddRowLevelSecurityDep(tab.ID(), state)
+	}
+	return state, nil
+}
+
+// AddRowLevelSecurityDep records how the row-level security policies of the
+// table with the given ID applied to the user the query was built for.
+func (md *Metadata) AddRowLevelSecurityDep(id cat.StableID, state RowLevelSecurityState) {
+	if md.rowLevelSecurityDeps == nil {
+		md.rowLevelSecurityDeps = make(map[cat.StableID]RowLevelSecurityState)
+	}
+	md.rowLevelSecurityDeps[id] = state
+}
+
 // AddTable indexes a new reference to a table within the query. Separate
 // references to the same table are assigned different table ids (e.g.  in a
 // self-join query). All columns are added to the metadata. If mutation columns
@@ -1116,3 +1177,8 @@ func (md *Metadata) TestingObjectRefsByName() map[cat.StableID][]*tree.Unresolve
 func (md *Metadata) TestingPrivileges() map[cat.StableID]privilegeBitmap {
 	return md.privileges
 }
+
+// TestingRowLevelSecurityDeps exposes the rowLevelSecurityDeps for testing.
+func (md *Metadata) TestingRowLevelSecurityDeps() map[cat.StableID]RowLevelSecurityState {
+	return md.rowLevelSecurityDeps
+}
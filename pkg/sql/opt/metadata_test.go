@@ -114,6 +114,10 @@ func TestMetadata(t *testing.T) {
 		udfName.ToUnresolvedObjectName(),
 	)
 
+	var rlsState opt.RowLevelSecurityState
+	rlsState.Policies.Add(1)
+	md.AddRowLevelSecurityDep(tab.ID(), rlsState)
+
 	// Call CopyFrom and verify that same objects are present in new metadata.
 	expr := &memo.ProjectExpr{}
 	md.AddWithBinding(1, expr)
@@ -203,6 +207,13 @@ func TestMetadata(t *testing.T) {
 		}
 	}
 
+	newRLSDeps, oldRLSDeps := mdNew.TestingRowLevelSecurityDeps(), md.TestingRowLevelSecurityDeps()
+	for id, state := range oldRLSDeps {
+		if newState := newRLSDeps[id]; !newState.Equals(&state) {
+			t.Fatalf("expected row-level security dependency to be copied")
+		}
+	}
+
 	depsUpToDate, err = md.CheckDependencies(context.Background(), &evalCtx, testCat)
 	if err == nil || depsUpToDate {
 		t.Fatalf("expected table privilege to be revoked in metadata copy")
@@ -15,6 +15,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/intsets"
 )
@@ -78,6 +79,16 @@ func (c *CustomFuncs) neededMutationCols(
 	if private.CanaryCol != 0 {
 		cols.Add(private.CanaryCol)
 	}
+	if private.MergeActionCol != 0 {
+		cols.Add(private.MergeActionCol)
+	}
+
+	// Add the input columns that are read by cascades, which include the NEW
+	// and OLD rows of AFTER triggers.
+	for i := range private.FKCascades {
+		cols.UnionWith(private.FKCascades[i].OldValues.ToSet())
+		cols.UnionWith(private.FKCascades[i].NewValues.ToSet())
+	}
 
 	if private.WithID != 0 {
 		for i := range uniqueChecks {
@@ -146,6 +157,19 @@ func (c *CustomFuncs) NeededMutationFetchCols(
 		}
 	}
 
+	// Retain any FetchCols that are read by cascades, such as the OLD rows of
+	// AFTER triggers.
+	var cascadeCols opt.ColSet
+	for i := range private.FKCascades {
+		cascadeCols.UnionWith(private.FKCascades[i].OldValues.ToSet())
+		cascadeCols.UnionWith(private.FKCascades[i].NewValues.ToSet())
+	}
+	for ord, col := range private.FetchCols {
+		if col != 0 && cascadeCols.Contains(col) {
+			cols.Add(tabMeta.MetaID.ColumnID(ord))
+		}
+	}
+
 	switch op {
 	case opt.UpdateOp, opt.UpsertOp:
 		// Determine set of target table columns that need to be updated.
@@ -223,6 +247,62 @@ func (c *CustomFuncs) NeededMutationFetchCols(
 			}
 		}
 
+		// Add deferrable outbound foreign keys and unique constraints. If their
+		// checks are deferred until the end of the transaction, the execution
+		// engine records the full new keys of the updated rows, so any key that
+		// is partially updated must be fetched. A partial unique constraint may
+		// also be affected by updates to its predicate columns, so its key is
+		// always fetched.
+		for i, n := 0, tabMeta.Table.OutboundForeignKeyCount(); i < n; i++ {
+			outboundFK := tabMeta.Table.OutboundForeignKey(i)
+			if outboundFK.Deferrability() == tree.ConstraintNotDeferrable {
+				continue
+			}
+			var fkCols opt.ColSet
+			for j, m := 0, outboundFK.ColumnCount(); j < m; j++ {
+				ord := outboundFK.OriginColumnOrdinal(tabMeta.Table, j)
+				fkCols.Add(tabMeta.MetaID.ColumnID(ord))
+			}
+			if fkCols.Intersects(updateCols) {
+				cols.UnionWith(fkCols)
+			}
+		}
+		for i, n := 0, tabMeta.Table.UniqueCount(); i < n; i++ {
+			uniq := tabMeta.Table.Unique(i)
+			if uniq.Deferrability() == tree.ConstraintNotDeferrable {
+				continue
+			}
+			var uniqCols opt.ColSet
+			for j, m := 0, uniq.ColumnCount(); j < m; j++ {
+				uniqCols.Add(tabMeta.MetaID.ColumnID(uniq.ColumnOrdinal(tabMeta.Table, j)))
+			}
+			if _, isPartial := uniq.Predicate(); isPartial || uniqCols.Intersects(updateCols) {
+				cols.UnionWith(uniqCols)
+			}
+		}
+
+		// An Upsert that implements a MERGE statement may delete existing rows,
+		// which requires the strict key columns of all indexes, as well as the
+		// columns of inbound foreign keys. Deleted rows are returned with their
+		// existing values, so all returned columns must be fetched as well.
+		if private.MergeActionCol != 0 {
+			for ord, col := range private.ReturnCols {
+				if col != 0 {
+					cols.Add(tabMeta.MetaID.ColumnID(ord))
+				}
+			}
+			for i, n := 0, tabMeta.Table.DeletableIndexCount(); i < n; i++ {
+				cols.UnionWith(tabMeta.IndexKeyColumnsMapInverted(i))
+			}
+			for i, n := 0, tabMeta.Table.InboundForeignKeyCount(); i < n; i++ {
+				inboundFK := tabMeta.Table.InboundForeignKey(i)
+				for j, m := 0, inboundFK.ColumnCount(); j < m; j++ {
+					ord := inboundFK.ReferencedColumnOrdinal(tabMeta.Table, j)
+					cols.Add(tabMeta.MetaID.ColumnID(ord))
+				}
+			}
+		}
+
 	case opt.DeleteOp:
 		// Add in all strict key columns from all indexes, since these are needed
 		// to compose the keys of rows to delete. Include mutation indexes, since
@@ -424,3 +424,39 @@ func (c *CustomFuncs) addConjuncts(
 func (c *CustomFuncs) ForDuplicateRemoval(private *memo.OrdinalityPrivate) (ok bool) {
 	return private.ForDuplicateRemoval
 }
+
+// IsSecurityBarrier returns true if the given Barrier private marks a security
+// barrier.
+func (c *CustomFuncs) IsSecurityBarrier(private *memo.BarrierPrivate) bool {
+	return private.SecurityBarrier
+}
+
+// IsLeakproofFilter returns true if the given filter is leakproof, meaning that
+// evaluating it cannot raise an error or have side effects that depend on the
+// values it is evaluated on.
+func (c *CustomFuncs) IsLeakproofFilter(item *memo.FiltersItem) bool {
+	return item.ScalarProps().VolatilitySet.IsLeakproof()
+}
+
+// ExtractLeakproofFilters returns the leakproof filters in the given list.
+func (c *CustomFuncs) ExtractLeakproofFilters(filters memo.FiltersExpr) memo.FiltersExpr {
+	newFilters := make(memo.FiltersExpr, 0, len(filters))
+	for i := range filters {
+		if c.IsLeakproofFilter(&filters[i]) {
+			newFilters = append(newFilters, filters[i])
+		}
+	}
+	return newFilters
+}
+
+// ExtractNonLeakproofFilters returns the filters in the given list that are
+// not leakproof.
+func (c *CustomFuncs) ExtractNonLeakproofFilters(filters memo.FiltersExpr) memo.FiltersExpr {
+	newFilters := make(memo.FiltersExpr, 0, len(filters))
+	for i := range filters {
+		if !c.IsLeakproofFilter(&filters[i]) {
+			newFilters = append(newFilters, filters[i])
+		}
+	}
+	return newFilters
+}
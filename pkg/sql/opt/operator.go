@@ -146,6 +146,9 @@ var ComparisonOpReverseMap = map[Operator]treecmp.ComparisonOperatorSymbol{
 	BBoxCoversOp:     treecmp.RegMatch,
 	BBoxIntersectsOp: treecmp.Overlaps,
 	TSMatchesOp:      treecmp.TSMatches,
+	JsonPathMatchOp:  treecmp.TSMatches,
+	JsonPathExistsOp: treecmp.JSONPathExists,
+	AdjacentOp:       treecmp.Adjacent,
 }
 
 // BinaryOpReverseMap maps from an optimizer operator type to a semantic tree
@@ -335,7 +338,8 @@ func AggregateIgnoresNulls(op Operator) bool {
 		return true
 
 	case ArrayAggOp, ArrayCatAggOp, ConcatAggOp, ConstAggOp, CountRowsOp,
-		FirstAggOp, JsonAggOp, JsonbAggOp, JsonObjectAggOp, JsonbObjectAggOp:
+		FirstAggOp, JsonAggOp, JsonbAggOp, JsonObjectAggOp, JsonbObjectAggOp,
+		UserDefinedAggOp:
 		return false
 
 	default:
@@ -361,7 +365,7 @@ func AggregateIsNullOnEmpty(op Operator) bool {
 		MergeTransactionStatsOp:
 		return true
 
-	case CountOp, CountRowsOp, RegressionCountOp:
+	case CountOp, CountRowsOp, RegressionCountOp, UserDefinedAggOp:
 		return false
 
 	default:
@@ -391,7 +395,7 @@ func AggregateIsNeverNullOnNonNullInput(op Operator) bool {
 		return true
 
 	case VarianceOp, StdDevOp, CorrOp, CovarSampOp, RegressionInterceptOp,
-		RegressionR2Op, RegressionSlopeOp, STExtentOp, STMakeLineOp:
+		RegressionR2Op, RegressionSlopeOp, STExtentOp, STMakeLineOp, UserDefinedAggOp:
 		// These aggregations can return NULL even with non-null input values.
 		return false
 
@@ -443,7 +447,7 @@ func AggregatesCanMerge(inner, outer Operator) bool {
 		VarPopOp, CovarPopOp, CovarSampOp, RegressionAvgXOp, RegressionAvgYOp,
 		RegressionInterceptOp, RegressionR2Op, RegressionSlopeOp, RegressionSXXOp,
 		RegressionSXYOp, RegressionSYYOp, RegressionCountOp, MergeStatsMetadataOp,
-		MergeStatementStatsOp, MergeTransactionStatsOp:
+		MergeStatementStatsOp, MergeTransactionStatsOp, UserDefinedAggOp:
 		return false
 
 	default:
@@ -469,7 +473,7 @@ func AggregateIgnoresDuplicates(op Operator) bool {
 		CovarSampOp, RegressionAvgXOp, RegressionAvgYOp, RegressionInterceptOp,
 		RegressionR2Op, RegressionSlopeOp, RegressionSXXOp, RegressionSXYOp,
 		RegressionSYYOp, RegressionCountOp, MergeStatsMetadataOp, MergeStatementStatsOp,
-		MergeTransactionStatsOp:
+		MergeTransactionStatsOp, UserDefinedAggOp:
 		return false
 
 	default:
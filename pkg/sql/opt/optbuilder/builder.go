@@ -317,7 +317,7 @@ func (b *Builder) buildStmt(
 	if b.insideViewDef {
 		// A blocklist of statements that can't be used from inside a view.
 		switch stmt := stmt.(type) {
-		case *tree.Delete, *tree.Insert, *tree.Update, *tree.CreateTable, *tree.CreateView,
+		case *tree.Delete, *tree.Insert, *tree.Update, *tree.Merge, *tree.CreateTable, *tree.CreateView,
 			*tree.Split, *tree.Unsplit, *tree.Relocate, *tree.RelocateRange,
 			*tree.ControlJobs, *tree.ControlSchedules, *tree.CancelQueries, *tree.CancelSessions,
 			*tree.CreateRoutine:
@@ -363,6 +363,11 @@ func (b *Builder) buildStmt(
 			return b.buildUpdate(stmt, inScope)
 		})
 
+	case *tree.Merge:
+		return b.processWiths(stmt.With, inScope, func(inScope *scope) *scope {
+			return b.buildMerge(stmt, inScope)
+		})
+
 	case *tree.CreateTable:
 		return b.buildCreateTable(stmt, inScope)
 
@@ -45,6 +45,15 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 	if cf.IsProcedure && !activeVersion.IsActive(clusterversion.V23_2) {
 		panic(unimplemented.New("procedures", "procedures are not yet supported"))
 	}
+	if !activeVersion.IsActive(clusterversion.V24_1_RoutineParamClasses) {
+		for i := range cf.Params {
+			if cf.Params[i].Class != tree.RoutineParamIn {
+				panic(pgerror.Newf(pgcode.FeatureNotSupported,
+					"version %v must be finalized to use OUT, INOUT, VARIADIC or TABLE parameters",
+					clusterversion.V24_1_RoutineParamClasses.Version()))
+			}
+		}
+	}
 
 	sch, resName := b.resolveSchemaForCreateFunction(&cf.Name)
 	schID := b.factory.Metadata().AddSchema(sch)
@@ -152,10 +161,11 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 	}
 
 	// bodyScope is the base scope for each statement in the body. We add the
-	// named parameters to the scope so that references to them in the body can
-	// be resolved.
+	// named input parameters to the scope so that references to them in the
+	// body can be resolved.
 	bodyScope := b.allocScope()
-	var paramTypes tree.ParamTypes
+	var inParams, outParams []tree.ParamType
+	var variadicSeen bool
 	for i := range cf.Params {
 		param := &cf.Params[i]
 		typ, err := tree.ResolveType(b.ctx, param.Type, b.semaCtx.TypeResolver)
@@ -176,27 +186,44 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 			}
 		}
 
-		// Add the parameter to the base scope of the body.
-		paramColName := funcParamColName(param.Name, i)
-		col := b.synthesizeColumn(bodyScope, paramColName, typ, nil /* expr */, nil /* scalar */)
-		col.setParamOrd(i)
+		// A VARIADIC parameter must be an array, and must be the last parameter
+		// in the signature of the routine.
+		if param.Class == tree.RoutineParamVariadic {
+			if typ.Family() != types.ArrayFamily {
+				panic(pgerror.New(pgcode.InvalidFunctionDefinition,
+					"VARIADIC parameter must be an array"))
+			}
+			variadicSeen = true
+		} else if variadicSeen && param.IsSignatureParam(cf.IsProcedure) {
+			panic(pgerror.New(pgcode.InvalidFunctionDefinition,
+				"VARIADIC parameter must be the last input parameter"))
+		}
 
 		// Collect the user defined type dependencies.
 		typedesc.GetTypeDescriptorClosure(typ).ForEach(func(id descpb.ID) {
 			typeDeps.Add(int(id))
 		})
 
-		// Collect the parameters for PLpgSQL routines.
-		if language == tree.RoutineLangPLpgSQL {
-			paramTypes = append(paramTypes, tree.ParamType{
-				Name: param.Name.String(),
-				Typ:  typ,
-			})
+		name := param.Name
+		if param.IsOutParam() {
+			name = tree.Name(outParamName(param.Name, i))
+			outParams = append(outParams, tree.ParamType{Name: string(name), Typ: typ})
+		}
+		if !param.IsInParam() {
+			// Only the input parameters are passed to the body of the routine.
+			continue
 		}
+
+		// Add the parameter to the base scope of the body.
+		paramColName := funcParamColName(name, len(inParams))
+		col := b.synthesizeColumn(bodyScope, paramColName, typ, nil /* expr */, nil /* scalar */)
+		col.setParamOrd(len(inParams))
+		inParams = append(inParams, tree.ParamType{Name: string(name), Typ: typ})
 	}
 
-	// Collect the user defined type dependency of the return type.
-	funcReturnType, err := tree.ResolveType(b.ctx, cf.ReturnType.Type, b.semaCtx.TypeResolver)
+	// Collect the user defined type dependency of the return type. The return
+	// type is synthesized from the output parameters, if there are any.
+	funcReturnType, err := cf.ResolveReturnType(b.ctx, b.semaCtx.TypeResolver)
 	if err != nil {
 		panic(err)
 	}
@@ -212,6 +239,24 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 		typeDeps.Add(int(id))
 	})
 
+	// Trigger functions can only be executed by triggers, which make the NEW
+	// and OLD rows and the TG_ variables available to the function body.
+	isTriggerFunc := funcReturnType.Family() == types.TriggerFamily
+	if isTriggerFunc {
+		if language == tree.RoutineLangSQL {
+			panic(pgerror.New(pgcode.InvalidFunctionDefinition, "SQL functions cannot return type trigger"))
+		}
+		if cf.IsProcedure || cf.ReturnType.SetOf {
+			panic(pgerror.New(pgcode.InvalidFunctionDefinition, "trigger functions must return a single trigger"))
+		}
+		if len(cf.Params) > 0 {
+			panic(errors.WithHint(
+				pgerror.New(pgcode.InvalidFunctionDefinition, "trigger functions cannot have declared arguments"),
+				"The arguments of the trigger can be accessed through TG_NARGS and TG_ARGV instead.",
+			))
+		}
+	}
+
 	targetVolatility := tree.GetRoutineVolatility(cf.Options)
 	fmtCtx := tree.NewFmtCtx(tree.FmtSerializable)
 
@@ -261,12 +306,21 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 			panic(err)
 		}
 
+		if isTriggerFunc {
+			// The types of the NEW and OLD rows depend on the table of the
+			// trigger, so the body of a trigger function cannot be built until the
+			// trigger is created. Only check that the body parses.
+			formatFuncBodyStmt(fmtCtx, stmt.AST, false /* newLine */)
+			afterBuildStmt()
+			break
+		}
+
 		// We need to disable stable function folding because we want to catch the
 		// volatility of stable functions. If folded, we only get a scalar and lose
 		// the volatility.
 		b.factory.FoldingControl().TemporarilyDisallowStableFolds(func() {
 			var plBuilder plpgsqlBuilder
-			plBuilder.init(b, nil /* colRefs */, paramTypes, stmt.AST, funcReturnType)
+			plBuilder.init(b, nil /* colRefs */, inParams, outParams, stmt.AST, funcReturnType)
 			stmtScope = plBuilder.build(stmt.AST, bodyScope)
 		})
 		checkStmtVolatility(targetVolatility, stmtScope, stmt)
@@ -351,12 +405,17 @@ func validateReturnType(
 		)
 	}
 
-	// If return type is RECORD, any column types are valid.
-	if types.IsRecordType(expected) {
+	// If return type is RECORD, any column types are valid. This does not apply
+	// to the record type of a routine with output parameters, which has a field
+	// for each output parameter.
+	if types.IsRecordType(expected) && types.IsWildcardTupleType(expected) {
 		return nil
 	}
 
-	if len(cols) == 1 {
+	// A single column must match the return type, unless the return type is a
+	// tuple and the column is not, in which case the column must match the
+	// single field of the tuple.
+	if len(cols) == 1 && (expected.Family() != types.TupleFamily || cols[0].typ.Family() == types.TupleFamily) {
 		if !expected.Equivalent(cols[0].typ) &&
 			!cast.ValidCast(cols[0].typ, expected, cast.ContextAssignment) {
 			return pgerror.WithCandidateCode(
@@ -12,6 +12,7 @@ package optbuilder
 
 import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinsregistry"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
@@ -93,6 +94,15 @@ func (b *Builder) buildCreateTable(ct *tree.CreateTable, inScope *scope) (outSco
 			scopeCol := b.synthesizeColumn(outScope, scopeColName("rowid"), types.Int, nil /* expr */, fn)
 			input = b.factory.CustomFuncs().ProjectExtraCol(outScope.expr, fn, scopeCol.id)
 		}
+		if ct.WithNoData {
+			// The input query is only needed for the types of its columns, so
+			// make sure that it is never executed.
+			input = b.factory.ConstructLimit(
+				input,
+				b.factory.ConstructConst(tree.NewDInt(0), types.Int),
+				props.OrderingChoice{},
+			)
+		}
 		inputCols = outScope.makePhysicalProps().Presentation
 	} else {
 		// Create dummy empty input.
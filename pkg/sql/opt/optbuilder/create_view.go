@@ -71,7 +71,11 @@ func (b *Builder) buildCreateView(cv *tree.CreateView, inScope *scope) (outScope
 		}
 	}()
 
-	defScope := b.buildStmtAtRoot(cv.AsSource, nil /* desiredTypes */)
+	asSource := cv.AsSource
+	if cv.Recursive {
+		asSource = recursiveViewSource(cv)
+	}
+	defScope := b.buildStmtAtRoot(asSource, nil /* desiredTypes */)
 
 	p := defScope.makePhysicalProps().Presentation
 	if len(cv.ColumnNames) != 0 {
@@ -111,7 +115,7 @@ func (b *Builder) buildCreateView(cv *tree.CreateView, inScope *scope) (outScope
 		&memo.CreateViewPrivate{
 			Syntax:    cv,
 			Schema:    schID,
-			ViewQuery: tree.AsStringWithFlags(cv.AsSource, tree.FmtParsable),
+			ViewQuery: tree.AsStringWithFlags(asSource, tree.FmtParsable),
 			Columns:   p,
 			Deps:      b.schemaDeps,
 			TypeDeps:  b.schemaTypeDeps,
@@ -119,3 +123,37 @@ func (b *Builder) buildCreateView(cv *tree.CreateView, inScope *scope) (outScope
 	)
 	return outScope
 }
+
+// recursiveViewSource returns the query of a recursive view, desugared into a
+// recursive CTE with the same name and columns as the view. As in Postgres,
+//
+//	CREATE RECURSIVE VIEW v (a, b) AS <query>
+//
+// is equivalent to
+//
+//	CREATE VIEW v (a, b) AS WITH RECURSIVE v (a, b) AS (<query>) SELECT a, b FROM v
+//
+// References to the view inside the query resolve to the CTE, so the view
+// does not depend on itself.
+func recursiveViewSource(cv *tree.CreateView) *tree.Select {
+	cteCols := make(tree.ColumnDefList, len(cv.ColumnNames))
+	exprs := make(tree.SelectExprs, len(cv.ColumnNames))
+	for i, name := range cv.ColumnNames {
+		cteCols[i] = tree.ColumnDef{Name: name}
+		exprs[i] = tree.SelectExpr{Expr: tree.NewUnresolvedName(string(name))}
+	}
+	cteName := tree.MakeUnqualifiedTableName(cv.Name.ObjectName)
+	return &tree.Select{
+		With: &tree.With{
+			Recursive: true,
+			CTEList: []*tree.CTE{{
+				Name: tree.AliasClause{Alias: cv.Name.ObjectName, Cols: cteCols},
+				Stmt: cv.AsSource,
+			}},
+		},
+		Select: &tree.SelectClause{
+			Exprs: exprs,
+			From:  tree.From{Tables: tree.TableExprs{&cteName}},
+		},
+	}
+}
@@ -82,6 +82,8 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 
 	var mb mutationBuilder
 	mb.init(b, "delete", tab, alias)
+	mb.initRowLevelSecurity()
+	mb.initTriggers(tree.TriggerEventDelete)
 
 	// Build the input expression that selects the rows that will be deleted:
 	//
@@ -99,17 +101,26 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 		mb.buildDelete(nil /* returning */)
 	}
 
+	// Call the trigger functions of the BEFORE STATEMENT triggers first.
+	mb.buildStatementTriggersBefore()
+
 	return mb.outScope
 }
 
 // buildDelete constructs a Delete operator, possibly wrapped by a Project
 // operator that corresponds to the given RETURNING clause.
 func (mb *mutationBuilder) buildDelete(returning *tree.ReturningExprs) {
+	// Call the trigger functions of the BEFORE DELETE row-level triggers, which
+	// may skip the deletion of some rows.
+	mb.buildRowTriggersBefore(tree.TriggerEventDelete)
+
 	mb.buildFKChecksAndCascadesForDelete()
 
 	// Project partial index DEL boolean columns.
 	mb.projectPartialIndexDelCols()
 
+	mb.buildTriggersAfter()
+
 	private := mb.makeMutationPrivate(returning != nil)
 	for _, col := range mb.extraAccessibleCols {
 		if col.id != 0 {
@@ -96,6 +96,7 @@ func (cb *onDeleteCascadeBuilder) Build(
 
 		var mb mutationBuilder
 		mb.init(b, "delete", cb.childTable, tree.MakeUnqualifiedTableName(cb.childTable.Name()))
+		mb.initTriggers(tree.TriggerEventDelete)
 
 		// Build a semi join of the table with the mutation input.
 		//
@@ -289,6 +290,7 @@ func (cb *onDeleteFastCascadeBuilder) Build(
 
 		var mb mutationBuilder
 		mb.init(b, "delete", cb.childTable, tree.MakeUnqualifiedTableName(cb.childTable.Name()))
+		mb.initTriggers(tree.TriggerEventDelete)
 
 		// Build the input to the delete mutation, which is simply a Scan with a
 		// Select on top.
@@ -440,6 +442,7 @@ func (cb *onDeleteSetBuilder) Build(
 
 		var mb mutationBuilder
 		mb.init(b, "update", cb.childTable, tree.MakeUnqualifiedTableName(cb.childTable.Name()))
+		mb.initTriggers(tree.TriggerEventUpdate)
 
 		// Build a semi join of the table with the mutation input.
 		//
@@ -655,6 +658,7 @@ func (cb *onUpdateCascadeBuilder) Build(
 
 		var mb mutationBuilder
 		mb.init(b, "update", cb.childTable, tree.MakeUnqualifiedTableName(cb.childTable.Name()))
+		mb.initTriggers(tree.TriggerEventUpdate)
 
 		// Build a join of the table with the mutation input.
 		mb.outScope = b.buildUpdateCascadeMutationInput(
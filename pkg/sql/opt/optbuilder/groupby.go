@@ -100,6 +100,10 @@ type groupby struct {
 	// It is used to ensure that the builder does not throw a grouping error
 	// prematurely.
 	buildingGroupingCols bool
+
+	// groupingSets is non-nil if the GROUP BY clause contains GROUPING SETS,
+	// ROLLUP or CUBE. See groupingSetsInfo for more details.
+	groupingSets *groupingSetsInfo
 }
 
 // groupByStrSet is a set of stringified GROUP BY expressions that map to the
@@ -243,11 +247,18 @@ func (a aggregateInfo) isOrderedSetAggregate() bool {
 	}
 }
 
+// isUserDefined returns true if the aggregate was created with CREATE
+// AGGREGATE. The transition function of a user-defined aggregate can be
+// arbitrary, so it is considered to be ordering sensitive.
+func (a aggregateInfo) isUserDefined() bool {
+	return a.def.Overload != nil && a.def.Overload.UserDefinedAggregate != nil
+}
+
 // isOrderingSensitive returns true if the given aggregate operator is
 // ordering sensitive. That is, it can give different results based on the order
 // values are fed to it.
 func (a aggregateInfo) isOrderingSensitive() bool {
-	if a.isOrderedSetAggregate() {
+	if a.isOrderedSetAggregate() || a.isUserDefined() {
 		return true
 	}
 	switch a.def.Name {
@@ -352,8 +363,9 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	}
 
 	// If there are any aggregates that are ordering sensitive, build the
-	// aggregations as window functions over each group.
-	if g.hasNonCommutativeAggregates() {
+	// aggregations as window functions over each group. With grouping sets, the
+	// input is ordered instead (see buildGroupingSetsOrdering).
+	if g.hasNonCommutativeAggregates() && g.groupingSets == nil {
 		return b.buildAggregationAsWindow(groupingColSet, having, fromScope)
 	}
 
@@ -385,7 +397,7 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 
 		// Construct the aggregate function from its name and arguments and store
 		// it in the corresponding scope column.
-		aggCols[i].scalar = b.constructAggregate(agg.def.Name, args)
+		aggCols[i].scalar = b.constructAggregateForDef(&agg.def, args)
 
 		// Wrap the aggregate function with an AggDistinct operator if DISTINCT
 		// was specified in the query.
@@ -406,6 +418,12 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 			aggCols[i].scalar = b.factory.ConstructAggFilter(aggCols[i].scalar, variable)
 		}
 
+		if g.groupingSets != nil && !agg.isCommutative() {
+			// Skip past the columns for the ORDER BY clause, which orders the input
+			// of the GroupingSets (see buildGroupingSetsOrdering).
+			argCols = argCols[b.numOrderByCols(&agg, fromScope):]
+		}
+
 		if agg.isOrderingSensitive() {
 			haveOrderingSensitiveAgg = true
 		}
@@ -426,16 +444,29 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 		g.aggInScope.copyOrdering(fromScope)
 	}
 
-	// Construct the pre-projection, which renders the grouping columns and the
-	// aggregate arguments, as well as any additional order by columns.
-	b.constructProjectForScope(fromScope, g.aggInScope)
+	if g.groupingSets != nil {
+		ordering := g.aggInScope.ordering
+		if g.hasNonCommutativeAggregates() {
+			ordering = b.buildGroupingSetsOrdering(fromScope)
+		}
 
-	g.aggOutScope.expr = b.constructGroupBy(
-		g.aggInScope.expr,
-		groupingColSet,
-		aggCols,
-		g.aggInScope.ordering,
-	)
+		// Construct the pre-projection, which renders the grouping columns and the
+		// aggregate arguments, as well as any additional order by columns.
+		b.constructProjectForScope(fromScope, g.aggInScope)
+
+		g.aggOutScope.expr = b.constructGroupingSets(g.aggInScope.expr, g, aggCols, ordering)
+	} else {
+		// Construct the pre-projection, which renders the grouping columns and the
+		// aggregate arguments, as well as any additional order by columns.
+		b.constructProjectForScope(fromScope, g.aggInScope)
+
+		g.aggOutScope.expr = b.constructGroupBy(
+			g.aggInScope.expr,
+			groupingColSet,
+			aggCols,
+			g.aggInScope.ordering,
+		)
+	}
 
 	// Wrap with having filter if it exists.
 	if having != nil {
@@ -510,15 +541,20 @@ func (b *Builder) buildGroupingList(
 	// used in an aggregate function`. The builder cannot know whether there is
 	// a grouping error until the grouping columns are fully built.
 	g.buildingGroupingCols = true
-	for _, e := range groupBy {
-		b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+	if hasGroupingSets(groupBy) {
+		b.buildGroupingSets(groupBy, selects, projectionsScope, fromScope)
+	} else {
+		for _, e := range groupBy {
+			b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+		}
 	}
 	g.buildingGroupingCols = false
 }
 
 // buildGrouping builds a set of memo groups that represent a GROUP BY
 // expression. The expression (or expressions, if we have a star) is added to
-// groupStrs and to the aggInScope.
+// groupStrs and to the aggInScope. Returns the IDs of the grouping columns for
+// the expression.
 //
 // groupBy          The given GROUP BY expression.
 // selects          The select expressions are needed in case the GROUP BY
@@ -538,7 +574,7 @@ func (b *Builder) buildGroupingList(
 //	as the aggregate function arguments.
 func (b *Builder) buildGrouping(
 	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope, aggInScope *scope,
-) {
+) (cols opt.ColSet) {
 	// Unwrap parenthesized expressions like "((a))" to "a".
 	groupBy = tree.StripParens(groupBy)
 	alias := ""
@@ -636,7 +672,8 @@ func (b *Builder) buildGrouping(
 		// If a grouping column has already been added, don't add it again.
 		// GROUP BY a, a is semantically equivalent to GROUP BY a.
 		exprStr := symbolicExprStr(e)
-		if _, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+		if col, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+			cols.Add(col.id)
 			continue
 		}
 
@@ -646,7 +683,9 @@ func (b *Builder) buildGrouping(
 		col := aggInScope.addColumn(scopeColName(tree.Name(alias)), e)
 		b.buildScalar(e, fromScope, aggInScope, col, nil)
 		fromScope.groupby.groupStrs[exprStr] = col
+		cols.Add(col.id)
 	}
+	return cols
 }
 
 // buildAggArg builds a scalar expression which is used as an input in some form
@@ -775,8 +814,13 @@ func (b *Builder) buildAggregateFunction(
 	return &info
 }
 
-func (b *Builder) constructWindowFn(name string, args []opt.ScalarExpr) opt.ScalarExpr {
-	switch name {
+func (b *Builder) constructWindowFn(
+	def *memo.FunctionPrivate, args []opt.ScalarExpr,
+) opt.ScalarExpr {
+	if def.Overload != nil && def.Overload.UserDefinedAggregate != nil {
+		return b.constructUserDefinedAggregate(def, args)
+	}
+	switch def.Name {
 	case "rank":
 		return b.factory.ConstructRank()
 	case "row_number":
@@ -800,10 +844,22 @@ func (b *Builder) constructWindowFn(name string, args []opt.ScalarExpr) opt.Scal
 	case "nth_value":
 		return b.factory.ConstructNthValue(args[0], args[1])
 	default:
-		return b.constructAggregate(name, args)
+		return b.constructAggregate(def.Name, args)
 	}
 }
 
+// constructAggregateForDef constructs the aggregate function with the given
+// definition. User-defined aggregates are constructed from their support
+// functions, while builtin aggregates are constructed by name.
+func (b *Builder) constructAggregateForDef(
+	def *memo.FunctionPrivate, args []opt.ScalarExpr,
+) opt.ScalarExpr {
+	if def.Overload != nil && def.Overload.UserDefinedAggregate != nil {
+		return b.constructUserDefinedAggregate(def, args)
+	}
+	return b.constructAggregate(def.Name, args)
+}
+
 func (b *Builder) constructAggregate(name string, args []opt.ScalarExpr) opt.ScalarExpr {
 	switch name {
 	case "array_agg":
@@ -938,6 +994,10 @@ func newGroupingError(name tree.Name) error {
 // In the unique index or unique without index cases, all key columns must be
 // marked as NOT NULL to allow the implicit grouping.
 func (b *Builder) allowImplicitGroupingColumn(colID opt.ColumnID, g *groupby) bool {
+	if g.groupingSets != nil {
+		// The key columns may not be part of every grouping set.
+		return false
+	}
 	md := b.factory.Metadata()
 	colMeta := md.ColumnMeta(colID)
 	if colMeta.Table == 0 {
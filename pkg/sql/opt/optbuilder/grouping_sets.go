@@ -0,0 +1,372 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+// This file has builder code specific to GROUP BY clauses with GROUPING SETS,
+// ROLLUP or CUBE.
+//
+// All of the grouping sets are computed by a single GroupingSets expression,
+// which aggregates each input row once per grouping set. The grouping columns
+// that are not part of a grouping set are NULL in the rows of that set, and
+// the ordinal of the grouping set is output as an additional column that is
+// used by the GROUPING function. For example:
+//
+//   SELECT a, b, sum(c) FROM t GROUP BY ROLLUP (a, b)
+//
+//   grouping-sets
+//    ├── columns: a:5 b:6 sum:7 grouping_set:8
+//    ├── grouping columns: a:5 b:6
+//    ├── grouping sets: (5,6), (5), ()
+//    ├── project
+//    │    ├── columns: a:5 b:6 c:3
+//    │    ├── scan t
+//    │    └── projections
+//    │         ├── a:1 [as=a:5]
+//    │         └── b:2 [as=b:6]
+//    └── aggregations
+//         └── sum [as=sum:7]
+//              └── c:3
+//
+// The grouping columns are rendered with new IDs by the pre-projection, since
+// their values in the output of the GroupingSets can differ from their values
+// in the input. A grouping set with no columns returns a row even if the input
+// is empty, like an aggregation without a GROUP BY.
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// maxGroupingSets is the maximum number of grouping sets that a GROUP BY
+	// clause can expand to. It matches the limit in Postgres.
+	maxGroupingSets = 4096
+
+	// maxCubeElements is the maximum number of elements in a CUBE. It matches
+	// the limit in Postgres.
+	maxCubeElements = 12
+
+	// maxGroupingArgs is the maximum number of arguments to GROUPING, which
+	// returns a 32-bit integer in Postgres.
+	maxGroupingArgs = 31
+)
+
+var errTooManyGroupingSets = pgerror.Newf(pgcode.StatementTooComplex,
+	"too many grouping sets present (maximum %d)", maxGroupingSets,
+)
+
+var errGroupingArgs = pgerror.New(pgcode.Grouping,
+	"arguments to GROUPING must be grouping expressions of the associated query level",
+)
+
+// groupingSetsInfo contains information about the grouping sets of a GROUP BY
+// clause with GROUPING SETS, ROLLUP or CUBE.
+type groupingSetsInfo struct {
+	// sets contains the grouping columns of each grouping set. The index of a
+	// grouping set in sets is its identifier.
+	sets []opt.ColSet
+
+	// cols contains all of the grouping columns.
+	cols opt.ColList
+
+	// setIDCol is the column output by the aggregation that contains the
+	// identifier of the grouping set of each row.
+	setIDCol opt.ColumnID
+}
+
+// hasGroupingSets returns true if the given GROUP BY clause contains GROUPING
+// SETS, ROLLUP or CUBE.
+func hasGroupingSets(groupBy tree.GroupBy) bool {
+	for _, e := range groupBy {
+		if _, ok := e.(*tree.GroupingSet); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// expandGroupingSets returns the grouping sets that the given GROUP BY clause
+// expands to. Each grouping set is returned as a list of GROUP BY expressions.
+// The grouping sets of the individual GROUP BY items are combined by taking
+// their cross product, so that:
+//
+//	GROUP BY a, ROLLUP (b, c)
+//
+// is equivalent to:
+//
+//	GROUP BY GROUPING SETS ((a, b, c), (a, b), (a))
+func expandGroupingSets(groupBy tree.GroupBy) []tree.Exprs {
+	sets := []tree.Exprs{nil}
+	for _, e := range groupBy {
+		itemSets := []tree.Exprs{{e}}
+		if gs, ok := e.(*tree.GroupingSet); ok {
+			itemSets = expandGroupingSet(gs)
+		}
+		if len(sets)*len(itemSets) > maxGroupingSets {
+			panic(errTooManyGroupingSets)
+		}
+		product := make([]tree.Exprs, 0, len(sets)*len(itemSets))
+		for _, set := range sets {
+			for _, itemSet := range itemSets {
+				combined := make(tree.Exprs, 0, len(set)+len(itemSet))
+				combined = append(combined, set...)
+				combined = append(combined, itemSet...)
+				product = append(product, combined)
+			}
+		}
+		sets = product
+	}
+	return sets
+}
+
+// expandGroupingSet returns the grouping sets that the given GROUPING SETS,
+// ROLLUP or CUBE expands to. A parenthesized list of expressions is kept as a
+// tuple, which is flattened when the grouping columns are built.
+func expandGroupingSet(gs *tree.GroupingSet) []tree.Exprs {
+	switch gs.Type {
+	case tree.RollupGroupingSet:
+		// ROLLUP (a, b) is equivalent to GROUPING SETS ((a, b), (a), ()).
+		sets := make([]tree.Exprs, 0, len(gs.Exprs)+1)
+		for i := len(gs.Exprs); i >= 0; i-- {
+			sets = append(sets, gs.Exprs[:i])
+		}
+		return sets
+
+	case tree.CubeGroupingSet:
+		// CUBE (a, b) is equivalent to GROUPING SETS ((a, b), (a), (b), ()).
+		n := len(gs.Exprs)
+		if n > maxCubeElements {
+			panic(pgerror.Newf(pgcode.StatementTooComplex,
+				"CUBE is limited to %d elements", maxCubeElements,
+			))
+		}
+		sets := make([]tree.Exprs, 0, 1<<n)
+		for mask := 1<<n - 1; mask >= 0; mask-- {
+			var set tree.Exprs
+			for i := range gs.Exprs {
+				if mask&(1<<(n-1-i)) != 0 {
+					set = append(set, gs.Exprs[i])
+				}
+			}
+			sets = append(sets, set)
+		}
+		return sets
+
+	default:
+		var sets []tree.Exprs
+		for _, e := range gs.Exprs {
+			if t, ok := e.(*tree.GroupingSet); ok {
+				sets = append(sets, expandGroupingSet(t)...)
+			} else {
+				sets = append(sets, tree.Exprs{e})
+			}
+			if len(sets) > maxGroupingSets {
+				panic(errTooManyGroupingSets)
+			}
+		}
+		return sets
+	}
+}
+
+// buildGroupingSets builds the grouping columns of a GROUP BY clause with
+// GROUPING SETS, ROLLUP or CUBE, and initializes the groupingSets field of the
+// groupby.
+func (b *Builder) buildGroupingSets(
+	groupBy tree.GroupBy, selects tree.SelectExprs, projectionsScope, fromScope *scope,
+) {
+	g := fromScope.groupby
+	exprSets := expandGroupingSets(groupBy)
+
+	start := len(g.aggInScope.cols)
+	sets := make([]opt.ColSet, len(exprSets))
+	for i, exprs := range exprSets {
+		for _, e := range exprs {
+			sets[i].UnionWith(b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope))
+		}
+	}
+	end := len(g.aggInScope.cols)
+
+	// Add the grouping set identifier to the aggOutScope. It cannot be
+	// referenced by name.
+	setIDCol := b.synthesizeColumn(
+		g.aggOutScope, scopeColName("").WithMetadataName("grouping_set"), types.Int, nil, nil, /* scalar */
+	)
+	setIDCol.visibility = inaccessible
+	g.groupingSets = &groupingSetsInfo{
+		sets:     make([]opt.ColSet, len(sets)),
+		cols:     make(opt.ColList, 0, end-start),
+		setIDCol: setIDCol.id,
+	}
+
+	var newCols opt.ColMap
+	for i := start; i < end; i++ {
+		col := &g.aggInScope.cols[i]
+		id := col.id
+		if col.scalar == nil {
+			// The grouping columns output by the GroupingSets are NULL in the rows
+			// of the grouping sets that don't contain them, so they cannot reuse
+			// the IDs of input columns.
+			b.populateSynthesizedColumn(col, b.factory.ConstructVariable(col.id))
+			newCols.Set(int(id), i)
+		}
+		g.groupingSets.cols = append(g.groupingSets.cols, col.id)
+		for j := range sets {
+			if sets[j].Contains(id) {
+				g.groupingSets.sets[j].Add(col.id)
+			}
+		}
+	}
+
+	// The columns in groupStrs may point to stale copies of the grouping
+	// columns if the aggInScope columns were reallocated while they were built.
+	for str, col := range g.groupStrs {
+		if i, ok := newCols.Get(int(col.id)); ok {
+			g.groupStrs[str] = &g.aggInScope.cols[i]
+		}
+	}
+}
+
+// constructGroupingSets constructs a GroupingSets expression which computes
+// the given aggregations for all of the grouping sets of g in a single pass
+// over the input. ordering is the ordering of the rows within each group that
+// is required by ordering sensitive aggregates.
+func (b *Builder) constructGroupingSets(
+	input memo.RelExpr, g *groupby, aggCols []scopeColumn, ordering opt.Ordering,
+) memo.RelExpr {
+	aggs := make(memo.AggregationsExpr, 0, len(aggCols))
+
+	// Deduplicate the columns; we don't need to produce the same aggregation
+	// multiple times.
+	var colSet opt.ColSet
+	for i := range aggCols {
+		if id, scalar := aggCols[i].id, aggCols[i].scalar; !colSet.Contains(id) {
+			if scalar == nil {
+				panic(errors.AssertionFailedf("variable as aggregation"))
+			}
+			aggs = append(aggs, b.factory.ConstructAggregationsItem(scalar, id))
+			colSet.Add(id)
+		}
+	}
+
+	gs := g.groupingSets
+	private := memo.GroupingSetsPrivate{
+		GroupingCols: gs.cols.ToSet(),
+		Sets:         opt.GroupingSetList(gs.sets),
+		SetIDCol:     gs.setIDCol,
+	}
+
+	// Unlike GroupBy, the grouping columns are not constant within a group of a
+	// grouping set that doesn't contain all of them, so they cannot be added as
+	// optional columns.
+	private.Ordering.FromOrdering(ordering)
+	return b.factory.ConstructGroupingSets(input, aggs, &private)
+}
+
+// buildGroupingSetsOrdering builds the ORDER BY clauses of the ordering
+// sensitive aggregates of a GROUP BY clause with grouping sets, and returns the
+// ordering. All of the grouping sets are aggregated in a single pass over the
+// input, so the aggregates must have the same ORDER BY clause.
+func (b *Builder) buildGroupingSetsOrdering(fromScope *scope) opt.Ordering {
+	g := fromScope.groupby
+	var ordering opt.Ordering
+	found := false
+	for i := range g.aggs {
+		agg := &g.aggs[i]
+		if agg.isCommutative() {
+			continue
+		}
+		ord := b.buildWindowOrdering(
+			agg.OrderBy, i, agg.def.Name, fromScope, g.aggInScope, false, /* isRangeModeWithOffsets */
+		)
+		if found && !ord.Equals(ordering) {
+			panic(unimplemented.NewWithIssue(46280,
+				"aggregates with different ORDER BY clauses in a GROUP BY with grouping sets",
+			))
+		}
+		ordering, found = ord, true
+	}
+	return ordering
+}
+
+// numOrderByCols returns the number of argument columns that
+// buildAggregateFunction built for the ORDER BY clause of the given aggregate.
+func (b *Builder) numOrderByCols(agg *aggregateInfo, fromScope *scope) int {
+	n := 0
+	for _, o := range agg.OrderBy {
+		te := fromScope.resolveType(o.Expr, types.Any)
+		cols := flattenTuples([]tree.TypedExpr{te})
+		if !b.hasDefaultNullsOrder(o) {
+			n += len(cols)
+		}
+		n += len(cols)
+	}
+	return n
+}
+
+// buildGroupingFunc builds a GROUPING(...) call, which returns a bit mask
+// indicating which of its arguments are not part of the grouping set of the
+// current row. The rightmost argument corresponds to the least-significant
+// bit. The arguments must match GROUP BY expressions.
+func (b *Builder) buildGroupingFunc(
+	f *tree.FuncExpr, inScope, outScope *scope, outCol *scopeColumn,
+) opt.ScalarExpr {
+	g := inScope.groupby
+	if g == nil || inScope.inAgg || g.buildingGroupingCols {
+		panic(errGroupingArgs)
+	}
+	if len(f.Exprs) > maxGroupingArgs {
+		panic(pgerror.Newf(pgcode.TooManyArguments,
+			"GROUPING must have fewer than %d arguments", maxGroupingArgs+1,
+		))
+	}
+	cols := make([]opt.ColumnID, len(f.Exprs))
+	for i, e := range f.Exprs {
+		col, ok := g.groupStrs[symbolicExprStr(e.(tree.TypedExpr))]
+		if !ok {
+			panic(errGroupingArgs)
+		}
+		cols[i] = col.id
+	}
+
+	// Without grouping sets, all of the arguments are always grouped.
+	gs := g.groupingSets
+	if gs == nil {
+		out := b.factory.ConstructConstVal(tree.NewDInt(0), types.Int)
+		return b.finishBuildScalar(f, out, inScope, outScope, outCol)
+	}
+
+	// Build the expression:
+	//   CASE set WHEN 0 THEN <mask of set 0> WHEN 1 THEN <mask of set 1> ... END
+	whens := make(memo.ScalarListExpr, len(gs.sets))
+	for i := range gs.sets {
+		var mask int64
+		for _, col := range cols {
+			mask <<= 1
+			if !gs.sets[i].Contains(col) {
+				mask |= 1
+			}
+		}
+		whens[i] = b.factory.ConstructWhen(
+			b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(i)), types.Int),
+			b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(mask)), types.Int),
+		)
+	}
+	out := b.factory.ConstructCase(
+		b.factory.ConstructVariable(gs.setIDCol), whens, b.factory.ConstructNull(types.Int),
+	)
+	return b.finishBuildScalar(f, out, inScope, outScope, outCol)
+}
@@ -232,6 +232,12 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 	} else {
 		mb.init(b, "insert", tab, alias)
 	}
+	mb.initRowLevelSecurity()
+	if ins.OnConflict != nil && !ins.OnConflict.DoNothing {
+		mb.initTriggers(tree.TriggerEventInsert, tree.TriggerEventUpdate)
+	} else {
+		mb.initTriggers(tree.TriggerEventInsert)
+	}
 
 	// Compute target columns in two cases:
 	//
@@ -350,6 +356,9 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 		mb.buildUpsert(returning)
 	}
 
+	// Call the trigger functions of the BEFORE STATEMENT triggers first.
+	mb.buildStatementTriggersBefore()
+
 	return mb.outScope
 }
 
@@ -372,6 +381,23 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 // of edge cases (that caused real correctness bugs #13437 #13962). As a result,
 // this support was removed and needs to re-enabled. See #14482.
 func (mb *mutationBuilder) needExistingRows() bool {
+	// A MERGE statement may update or delete any of the rows it matches.
+	if mb.mergeActionColID != 0 {
+		return true
+	}
+
+	// The row-level security policies of the table may need to be checked
+	// against the existing rows.
+	if mb.rowLevelSecurity != nil {
+		return true
+	}
+
+	// Triggers must be able to tell inserted rows from updated rows, and
+	// UPDATE triggers are passed the existing rows.
+	if mb.tab.TriggerCount() > 0 {
+		return true
+	}
+
 	if mb.tab.DeletableIndexCount() > 1 {
 		return true
 	}
@@ -669,6 +695,10 @@ func (mb *mutationBuilder) addSynthesizedColsForInsert() {
 	// Add assignment casts for default column values.
 	mb.addAssignmentCasts(mb.insertColIDs)
 
+	// Call the trigger functions of the BEFORE INSERT row-level triggers, which
+	// may replace the inserted values.
+	mb.buildRowTriggersBefore(tree.TriggerEventInsert)
+
 	// Now add all computed columns.
 	mb.addSynthesizedComputedCols(mb.insertColIDs, false /* restrict */)
 
@@ -686,13 +716,20 @@ func (mb *mutationBuilder) buildInsert(returning *tree.ReturningExprs) {
 	// Add any check constraint boolean columns to the input.
 	mb.addCheckConstraintCols(false /* isUpdate */)
 
+	// Reject rows that violate the row-level security policies of the table.
+	mb.addRowLevelSecurityChecks(tree.PolicyCommandInsert, returning)
+
 	// Project partial index PUT boolean columns.
 	mb.projectPartialIndexPutCols()
 
 	mb.buildUniqueChecksForInsert()
 
+	mb.buildExclusionChecks(false /* isUpdate */)
+
 	mb.buildFKChecksForInsert()
 
+	mb.buildTriggersAfter()
+
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructInsert(
 		mb.outScope.expr, mb.uniqueChecks, mb.fastPathUniqueChecks, mb.fkChecks, private,
@@ -900,6 +937,9 @@ func (mb *mutationBuilder) buildUpsert(returning *tree.ReturningExprs) {
 	// Add any check constraint boolean columns to the input.
 	mb.addCheckConstraintCols(false /* isUpdate */)
 
+	// Reject rows that violate the row-level security policies of the table.
+	mb.addRowLevelSecurityChecks(tree.PolicyCommandInsert, returning)
+
 	// Add the partial index predicate expressions to the table metadata.
 	// These expressions are used to prune fetch columns during
 	// normalization.
@@ -920,8 +960,12 @@ func (mb *mutationBuilder) buildUpsert(returning *tree.ReturningExprs) {
 
 	mb.buildUniqueChecksForUpsert()
 
+	mb.buildExclusionChecks(false /* isUpdate */)
+
 	mb.buildFKChecksForUpsert()
 
+	mb.buildTriggersAfter()
+
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructUpsert(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
@@ -0,0 +1,547 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/cast"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// duplicateMergeErrText is error text used when a target row is matched by
+// more than one source row that a MERGE statement acts upon.
+const duplicateMergeErrText = "MERGE command cannot affect row a second time"
+
+// buildMerge builds a memo group for an UpsertOp expression that implements a
+// MERGE statement. The source table is left-joined to the target table using
+// the ON condition, so that every source row is paired either with the target
+// row it matches, or with NULL values. As with UPSERT, a not-null "canary"
+// column of the target table tells the two cases apart. For example:
+//
+//	CREATE TABLE abc (a INT PRIMARY KEY, b INT, c INT)
+//	MERGE INTO abc USING xyz ON a = x
+//	WHEN MATCHED AND z > 0 THEN UPDATE SET b = y
+//	WHEN MATCHED THEN DELETE
+//	WHEN NOT MATCHED THEN INSERT VALUES (x, y, z)
+//
+// would create an input expression similar to this SQL:
+//
+//	SELECT
+//	  x, y, z, fetch_a, fetch_b, fetch_c,
+//	  CASE clause WHEN 1 THEN 2 WHEN 2 THEN 3 WHEN 3 THEN 1 END AS action,
+//	  CASE WHEN clause = 3 THEN x END AS ins_a,
+//	  CASE WHEN clause = 3 THEN y END AS ins_b,
+//	  CASE WHEN clause = 3 THEN z END AS ins_c,
+//	  CASE WHEN clause = 1 THEN y ELSE fetch_b END AS upd_b
+//	FROM (
+//	  SELECT DISTINCT ON (fetch_a) *
+//	  FROM (
+//	    SELECT
+//	      *,
+//	      CASE
+//	        WHEN fetch_a IS NOT NULL AND z > 0 THEN 1
+//	        WHEN fetch_a IS NOT NULL THEN 2
+//	        WHEN fetch_a IS NULL THEN 3
+//	        ELSE 0
+//	      END AS clause
+//	    FROM xyz
+//	    LEFT OUTER JOIN abc AS fetch ON a = x
+//	  )
+//	  WHERE clause != 0
+//	)
+//
+// The "clause" column is the 1-based ordinal of the first WHEN clause that
+// applies to the row, or 0 if no clause applies or the clause is DO NOTHING.
+// Rows that the MERGE statement does not act upon are filtered out, and the
+// remaining rows must be distinct on the target table's primary key, since
+// PostgreSQL does not allow a target row to be modified more than once. Rows
+// that did not match a target row have a NULL primary key, so they are never
+// considered duplicates.
+//
+// The "action" column holds the opt.MergeAction for the clause, which tells the
+// execution engine whether to insert the row, or to update or delete the
+// existing row. Insert and update values are only evaluated for the clauses
+// that provide them. The remaining insert columns are filled in with default
+// and computed values, and the remaining update columns with their existing
+// values, exactly as for UPSERT. See the comment header for Builder.buildInsert
+// for more details on how the insert and update columns are combined.
+//
+// If the table is referenced by foreign keys, the existing values of the
+// deleted rows are projected as well, and drive the same foreign key checks
+// and cascades as a DELETE statement. If row-level security applies to the
+// table, each row must satisfy the policies for its action; unlike for UPDATE
+// and DELETE, a matched row that violates them causes an error rather than
+// being skipped.
+func (b *Builder) buildMerge(merge *tree.Merge, inScope *scope) (outScope *scope) {
+	// Find which table we're working on, check the permissions. Existing
+	// values are always read to evaluate the ON condition.
+	tab, depName, alias, refColumns := b.resolveTableForMutation(merge.Table, privilege.SELECT)
+
+	if tab.IsVirtualTable() {
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"cannot execute MERGE on relation \"%s\"", tab.Name(),
+		))
+	}
+
+	if refColumns != nil {
+		panic(pgerror.Newf(pgcode.Syntax,
+			"cannot specify a list of column IDs with MERGE"))
+	}
+
+	// Check the privileges needed by the actions of the WHEN clauses.
+	var hasInsert, hasUpdate, hasDelete bool
+	for _, when := range merge.Whens {
+		switch when.Action.(type) {
+		case *tree.MergeInsert:
+			hasInsert = true
+		case *tree.MergeUpdate:
+			hasUpdate = true
+		case *tree.MergeDelete:
+			hasDelete = true
+		}
+	}
+	var events []tree.TriggerEventType
+	if hasInsert {
+		b.checkPrivilege(depName, tab, privilege.INSERT)
+		events = append(events, tree.TriggerEventInsert)
+	}
+	if hasUpdate {
+		b.checkPrivilege(depName, tab, privilege.UPDATE)
+		events = append(events, tree.TriggerEventUpdate)
+	}
+	if hasDelete {
+		b.checkPrivilege(depName, tab, privilege.DELETE)
+		events = append(events, tree.TriggerEventDelete)
+	}
+
+	// Check if this table has already been mutated in another subquery.
+	b.checkMultipleMutations(tab, generalMutation)
+
+	var mb mutationBuilder
+	mb.init(b, "merge", tab, alias)
+	mb.initRowLevelSecurity()
+	mb.initTriggers(events...)
+
+	// Build the input expression that joins the source rows to the target
+	// rows, and decides which WHEN clause applies to each of them.
+	mb.buildInputForMerge(inScope, merge.Source, merge.On)
+	clauseColID := mb.addMergeClauseCol(merge.Whens)
+
+	// Project the action of each row, along with the values that it inserts
+	// or updates.
+	mb.addMergeCols(merge.Whens, clauseColID)
+
+	// Call the trigger functions of the BEFORE DELETE row-level triggers, which
+	// may skip the deletion of some rows. The BEFORE INSERT and BEFORE UPDATE
+	// triggers have already been called by addMergeCols.
+	if hasDelete {
+		mb.buildRowTriggersBefore(tree.TriggerEventDelete)
+	}
+
+	// Project the existing values of the deleted rows that are needed by the
+	// foreign key checks and cascades of the referencing tables.
+	if hasDelete && tab.InboundForeignKeyCount() > 0 {
+		mb.addMergeDeleteCols()
+	}
+
+	// Build the final upsert statement, including any returned expressions.
+	if resultsNeeded(merge.Returning) {
+		mb.buildUpsert(merge.Returning.(*tree.ReturningExprs))
+	} else {
+		mb.buildUpsert(nil /* returning */)
+	}
+
+	// Call the trigger functions of the BEFORE STATEMENT triggers first.
+	mb.buildStatementTriggersBefore()
+
+	return mb.outScope
+}
+
+// buildInputForMerge constructs a left outer join between the MERGE source and
+// the target table, using the ON condition as the join condition. All columns
+// of the target table are added to fetchColIDs, and a primary key column is
+// chosen as the canary column.
+func (mb *mutationBuilder) buildInputForMerge(
+	inScope *scope, source tree.TableExpr, on tree.Expr,
+) {
+	sourceScope := mb.b.buildFromTables(tree.TableExprs{source}, noLocking, inScope)
+
+	// Fetch columns from a different instance of the table metadata, as for
+	// UPDATE and DELETE statements.
+	//
+	// NOTE: Include mutation columns, but be careful to never use them for any
+	//       reason other than as "fetch columns". See buildScan comment.
+	mb.fetchScope = mb.b.buildScan(
+		mb.b.addTable(mb.tab, &mb.alias),
+		tableOrdinals(mb.tab, columnKinds{
+			includeMutations: true,
+			includeSystem:    true,
+			includeInverted:  false,
+		}),
+		nil, /* indexFlags */
+		noRowLocking,
+		inScope,
+		false, /* disableNotVisibleIndex */
+	)
+
+	// Set list of columns that will be fetched by the input expression.
+	mb.setFetchColIDs(mb.fetchScope.cols)
+
+	// Check that the same table name is not used multiple times.
+	mb.b.validateJoinTableNames(sourceScope, mb.fetchScope)
+
+	// We create a new scope so that fetchScope is not modified. It will be
+	// used later to build partial index predicate expressions, and we do not
+	// want ambiguities with column names in the source.
+	mb.outScope = mb.fetchScope.replace()
+	mb.outScope.appendColumnsFromScope(sourceScope)
+	mb.outScope.appendColumnsFromScope(mb.fetchScope)
+
+	filter := mb.b.resolveAndBuildScalar(
+		on,
+		types.Bool,
+		exprKindOn,
+		tree.RejectGenerators|tree.RejectWindowApplications|tree.RejectProcedures,
+		mb.outScope,
+	)
+	mb.outScope.expr = mb.b.factory.ConstructLeftJoin(
+		sourceScope.expr,
+		mb.fetchScope.expr,
+		memo.FiltersExpr{mb.b.factory.ConstructFiltersItem(filter)},
+		memo.EmptyJoinPrivate,
+	)
+
+	// Record a not-null "canary" column. After the left-join, this will be
+	// null if the source row did not match a target row, or not null
+	// otherwise.
+	canaryOrd := findNotNullIndexCol(mb.tab.Index(cat.PrimaryIndex))
+	mb.canaryColID = mb.fetchColIDs[canaryOrd]
+}
+
+// addMergeClauseCol projects a column that holds the 1-based ordinal of the
+// first WHEN clause that applies to each row, or 0 if no clause applies or the
+// action of the clause is DO NOTHING. Rows with a 0 ordinal are filtered out,
+// and the remaining rows are checked to match each target row at most once.
+// See the comment header for Builder.buildMerge for an example.
+func (mb *mutationBuilder) addMergeClauseCol(whens tree.MergeWhens) opt.ColumnID {
+	f := mb.b.factory
+	zero := f.ConstructConstVal(tree.NewDInt(0), types.Int)
+	canary := f.ConstructVariable(mb.canaryColID)
+
+	clauses := make(memo.ScalarListExpr, 0, len(whens))
+	for i, when := range whens {
+		var cond opt.ScalarExpr
+		if when.Matched {
+			cond = f.ConstructIsNot(canary, memo.NullSingleton)
+		} else {
+			cond = f.ConstructIs(canary, memo.NullSingleton)
+		}
+		if when.Cond != nil {
+			cond = f.ConstructAnd(cond, mb.b.resolveAndBuildScalar(
+				when.Cond, types.Bool, exprKindMergeWhen, tree.RejectSpecial, mb.outScope,
+			))
+		}
+		val := zero
+		if _, ok := when.Action.(*tree.MergeDoNothing); !ok {
+			val = f.ConstructConstVal(tree.NewDInt(tree.DInt(i+1)), types.Int)
+		}
+		clauses = append(clauses, f.ConstructWhen(cond, val))
+	}
+
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	clauseCol := mb.b.synthesizeColumn(
+		projectionsScope,
+		scopeColName("").WithMetadataName("merge_clause"),
+		types.Int,
+		nil, /* expr */
+		f.ConstructCase(memo.TrueSingleton, clauses, zero),
+	)
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+
+	// Filter out the rows that the MERGE does not act upon.
+	mb.outScope.expr = f.ConstructSelect(
+		mb.outScope.expr,
+		memo.FiltersExpr{f.ConstructFiltersItem(
+			f.ConstructNe(f.ConstructVariable(clauseCol.id), zero),
+		)},
+	)
+
+	// Ensure that each target row is modified at most once. Unmatched rows
+	// have a NULL primary key, so treat NULL values as distinct from one
+	// another.
+	var pkCols opt.ColSet
+	primaryIndex := mb.tab.Index(cat.PrimaryIndex)
+	for i, n := 0, primaryIndex.KeyColumnCount(); i < n; i++ {
+		pkCols.Add(mb.fetchColIDs[primaryIndex.Column(i).Ordinal()])
+	}
+	mb.outScope.ordering = nil
+	mb.outScope = mb.b.buildDistinctOn(
+		pkCols, mb.outScope, true /* nullsAreDistinct */, duplicateMergeErrText,
+	)
+
+	return clauseCol.id
+}
+
+// addMergeCols projects the action column of the MERGE, as well as one column
+// for each table column that is explicitly inserted or updated by any of the
+// WHEN clauses. Each of these columns is a CASE expression on the clause
+// column, so that a value is only evaluated for the rows of the clause that
+// provides it. Default, computed and ON UPDATE values are then synthesized for
+// the remaining columns.
+func (mb *mutationBuilder) addMergeCols(whens tree.MergeWhens, clauseColID opt.ColumnID) {
+	// Values should reject aggregates, generators, etc.
+	scalarProps := &mb.b.semaCtx.Properties
+	defer scalarProps.Restore(*scalarProps)
+	mb.b.semaCtx.Properties.Require("MERGE", tree.RejectSpecial)
+
+	f := mb.b.factory
+	clauseCol := f.ConstructVariable(clauseColID)
+	isClause := func(i int) opt.ScalarExpr {
+		return f.ConstructEq(clauseCol, f.ConstructConstVal(tree.NewDInt(tree.DInt(i+1)), types.Int))
+	}
+
+	// Collect the values assigned to each table column by each clause.
+	insertVals := make([][]opt.ScalarExpr, mb.tab.ColumnCount())
+	updateVals := make([][]opt.ScalarExpr, mb.tab.ColumnCount())
+	addVal := func(vals [][]opt.ScalarExpr, clause, ord int, val opt.ScalarExpr) {
+		if vals[ord] == nil {
+			vals[ord] = make([]opt.ScalarExpr, len(whens))
+		}
+		vals[ord][clause] = val
+	}
+	actions := make(memo.ScalarListExpr, 0, len(whens))
+	for i, when := range whens {
+		var action opt.MergeAction
+		switch t := when.Action.(type) {
+		case *tree.MergeInsert:
+			action = opt.MergeActionInsert
+			if t.DefaultValues() {
+				break
+			}
+			mb.targetColList = mb.targetColList[:0]
+			mb.targetColSet = opt.ColSet{}
+			if len(t.Columns) > 0 {
+				mb.addTargetColsByName(t.Columns)
+				mb.checkNumCols(len(mb.targetColList), len(t.Values))
+			} else {
+				mb.addTargetTableColsForInsert(len(t.Values))
+			}
+			for j, expr := range t.Values {
+				ord := mb.tabID.ColumnOrdinal(mb.targetColList[j])
+				addVal(insertVals, i, ord, mb.buildMergeValue(expr, ord, false /* isUpdate */))
+			}
+
+		case *tree.MergeUpdate:
+			action = opt.MergeActionUpdate
+			mb.targetColList = mb.targetColList[:0]
+			mb.targetColSet = opt.ColSet{}
+			var exprs tree.Exprs
+			for _, set := range t.Exprs {
+				mb.addTargetColsByName(set.Names)
+				if !set.Tuple {
+					exprs = append(exprs, set.Expr)
+					continue
+				}
+				tuple, ok := set.Expr.(*tree.Tuple)
+				if !ok {
+					panic(unimplementedWithIssueDetailf(35713, fmt.Sprintf("%T", set.Expr),
+						"source for a multiple-column MERGE UPDATE item must be a ROW() expression; not supported: %T", set.Expr))
+				}
+				if len(set.Names) != len(tuple.Exprs) {
+					panic(pgerror.Newf(pgcode.Syntax,
+						"number of columns (%d) does not match number of values (%d)",
+						len(set.Names), len(tuple.Exprs)))
+				}
+				exprs = append(exprs, tuple.Exprs...)
+			}
+			for j, expr := range exprs {
+				ord := mb.tabID.ColumnOrdinal(mb.targetColList[j])
+				addVal(updateVals, i, ord, mb.buildMergeValue(expr, ord, true /* isUpdate */))
+			}
+
+		case *tree.MergeDelete:
+			action = opt.MergeActionDelete
+
+		case *tree.MergeDoNothing:
+			continue
+		}
+		actions = append(actions, f.ConstructWhen(
+			f.ConstructConstVal(tree.NewDInt(tree.DInt(i+1)), types.Int),
+			f.ConstructConstVal(tree.NewDInt(tree.DInt(action)), types.Int),
+		))
+	}
+	mb.targetColList = make(opt.ColList, 0, mb.tab.ColumnCount())
+	mb.targetColSet = opt.ColSet{}
+
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	mb.mergeActionColID = mb.b.synthesizeColumn(
+		projectionsScope,
+		scopeColName("").WithMetadataName("merge_action"),
+		types.Int,
+		nil, /* expr */
+		f.ConstructCase(clauseCol, actions, f.ConstructNull(types.Int)),
+	).id
+
+	// Columns that are only inserted by some of the clauses get their default
+	// value for the rows of the other insert clauses.
+	for ord, clauseVals := range insertVals {
+		if clauseVals == nil {
+			continue
+		}
+		var whenExprs memo.ScalarListExpr
+		for i, when := range whens {
+			if _, ok := when.Action.(*tree.MergeInsert); !ok {
+				continue
+			}
+			val := clauseVals[i]
+			if val == nil {
+				val = mb.buildMergeValue(tree.DefaultVal{}, ord, false /* isUpdate */)
+			}
+			whenExprs = append(whenExprs, f.ConstructWhen(isClause(i), val))
+		}
+		tabCol := mb.tab.Column(ord)
+		name := scopeColName(tabCol.ColName()).WithMetadataName(
+			fmt.Sprintf("merge_insert_%s", tabCol.ColName()),
+		)
+		mb.insertColIDs[ord] = mb.b.synthesizeColumn(
+			projectionsScope, name, tabCol.DatumType(), nil, /* expr */
+			f.ConstructCase(memo.TrueSingleton, whenExprs, f.ConstructNull(tabCol.DatumType())),
+		).id
+	}
+
+	// Columns that are only updated by some of the clauses keep their existing
+	// value for the rows of the other update and delete clauses.
+	for ord, clauseVals := range updateVals {
+		if clauseVals == nil {
+			continue
+		}
+		var whenExprs memo.ScalarListExpr
+		for i, val := range clauseVals {
+			if val != nil {
+				whenExprs = append(whenExprs, f.ConstructWhen(isClause(i), val))
+			}
+		}
+		tabCol := mb.tab.Column(ord)
+		name := scopeColName(tabCol.ColName()).WithMetadataName(
+			fmt.Sprintf("merge_update_%s", tabCol.ColName()),
+		)
+		mb.updateColIDs[ord] = mb.b.synthesizeColumn(
+			projectionsScope, name, tabCol.DatumType(), nil, /* expr */
+			f.ConstructCase(memo.TrueSingleton, whenExprs, f.ConstructVariable(mb.fetchColIDs[ord])),
+		).id
+	}
+
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+
+	// Add the default and computed values of the inserted rows. Computed
+	// columns must be derived from the insert columns, rather than from the
+	// fetched columns that would otherwise take precedence (see
+	// mapToReturnColID), so hide the fetched columns while they are built.
+	fetchColIDs := mb.fetchColIDs
+	mb.fetchColIDs = make(opt.OptionalColList, len(fetchColIDs))
+	mb.addSynthesizedColsForInsert()
+	mb.fetchColIDs = fetchColIDs
+
+	// Add the ON UPDATE and computed values of the updated rows.
+	mb.addSynthesizedColsForUpdate()
+}
+
+// addMergeDeleteCols projects one column for each table column that is
+// referenced by an inbound foreign key, which holds the existing value of the
+// column for the rows that are deleted, and NULL for all other rows:
+//
+//	CASE WHEN action = <delete> THEN fetch_a END AS merge_delete_a
+//
+// The columns are recorded in mb.mergeDeleteColIDs and are used to build the
+// foreign key checks and cascades for the deleted rows.
+func (mb *mutationBuilder) addMergeDeleteCols() {
+	f := mb.b.factory
+	isDelete := f.ConstructEq(
+		f.ConstructVariable(mb.mergeActionColID),
+		f.ConstructConstVal(tree.NewDInt(tree.DInt(opt.MergeActionDelete)), types.Int),
+	)
+
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	mb.mergeDeleteColIDs = make(opt.OptionalColList, mb.tab.ColumnCount())
+	for i, n := 0, mb.tab.InboundForeignKeyCount(); i < n; i++ {
+		fk := mb.tab.InboundForeignKey(i)
+		for j, m := 0, fk.ColumnCount(); j < m; j++ {
+			ord := fk.ReferencedColumnOrdinal(mb.tab, j)
+			if mb.mergeDeleteColIDs[ord] != 0 {
+				continue
+			}
+			tabCol := mb.tab.Column(ord)
+			name := scopeColName("").WithMetadataName(
+				fmt.Sprintf("merge_delete_%s", tabCol.ColName()),
+			)
+			mb.mergeDeleteColIDs[ord] = mb.b.synthesizeColumn(
+				projectionsScope, name, tabCol.DatumType(), nil, /* expr */
+				f.ConstructCase(
+					memo.TrueSingleton,
+					memo.ScalarListExpr{
+						f.ConstructWhen(isDelete, f.ConstructVariable(mb.fetchColIDs[ord])),
+					},
+					f.ConstructNull(tabCol.DatumType()),
+				),
+			).id
+		}
+	}
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+}
+
+// buildMergeValue builds a scalar expression for a value that a WHEN clause
+// assigns to the table column with the given ordinal, and casts it to the type
+// of the column.
+func (mb *mutationBuilder) buildMergeValue(expr tree.Expr, ord int, isUpdate bool) opt.ScalarExpr {
+	targetCol := mb.tab.Column(ord)
+
+	// Allow the value to be DEFAULT.
+	if _, ok := expr.(tree.DefaultVal); ok {
+		expr = mb.parseDefaultExpr(mb.tabID.ColumnID(ord))
+	} else if targetCol.IsGeneratedAlwaysAsIdentity() {
+		// GENERATED ALWAYS AS IDENTITY columns are not allowed to be explicitly
+		// written to.
+		if isUpdate {
+			panic(sqlerrors.NewGeneratedAlwaysAsIdentityColumnUpdateError(string(targetCol.ColName())))
+		}
+		panic(sqlerrors.NewGeneratedAlwaysAsIdentityColumnOverrideError(string(targetCol.ColName())))
+	}
+
+	targetType := targetCol.DatumType()
+	texpr := mb.outScope.resolveType(expr, targetType)
+	scalar := mb.b.buildScalar(texpr, mb.outScope, nil, nil, nil)
+
+	// An assignment cast is not necessary if the source and target types are
+	// identical.
+	srcType := texpr.ResolvedType()
+	if srcType.Identical(targetType) {
+		return scalar
+	}
+	if !cast.ValidCast(srcType, targetType, cast.ContextAssignment) {
+		panic(sqlerrors.NewInvalidAssignmentCastError(srcType, targetType, string(targetCol.ColName())))
+	}
+	return mb.b.factory.ConstructAssignmentCast(scalar, targetType)
+}
@@ -140,6 +140,32 @@ type mutationBuilder struct {
 	// an insert; otherwise it's an update.
 	canaryColID opt.ColumnID
 
+	// mergeActionColID is the ID of the column that is used to decide whether
+	// to insert, update or delete each row of a MERGE statement (see
+	// opt.MergeAction). It is 0 for all other statements.
+	mergeActionColID opt.ColumnID
+
+	// mergeDeleteColIDs lists the input column IDs holding the existing values
+	// of the rows deleted by a MERGE statement, for the table columns that are
+	// referenced by inbound foreign keys. The values are NULL for the rows that
+	// are inserted or updated instead. It is nil unless the MERGE statement has
+	// a DELETE action and the table is referenced by a foreign key.
+	mergeDeleteColIDs opt.OptionalColList
+
+	// triggerEvents are the events of the mutation that fire triggers on the
+	// target table (see initTriggers).
+	triggerEvents []tree.TriggerEventType
+
+	// updateTargetOrds is the set of ordinals of the table columns that are
+	// the target of an UPDATE. It is used to decide whether UPDATE OF triggers
+	// fire.
+	updateTargetOrds intsets.Fast
+
+	// rowLevelSecurity describes the row-level security policies of the target
+	// table that apply to the current user. It is nil if the mutation is not
+	// subject to row-level security, as is the case for foreign key cascades.
+	rowLevelSecurity *opt.RowLevelSecurityState
+
 	// arbiters is the set of indexes and unique constraints that are used to
 	// detect conflicts for UPSERT and INSERT ON CONFLICT statements.
 	arbiters arbiterSet
@@ -171,6 +197,11 @@ type mutationBuilder struct {
 	// once and cached for reuse.
 	parsedUniqueConstraintExprs []tree.Expr
 
+	// parsedExclusionConstraintExprs is a cached set of parsed partial
+	// exclusion constraint predicate expressions from the table schema. These
+	// are parsed once and cached for reuse.
+	parsedExclusionConstraintExprs []tree.Expr
+
 	// uniqueChecks contains unique check queries; see buildUnique* methods.
 	uniqueChecks memo.UniqueChecksExpr
 
@@ -201,6 +232,9 @@ type mutationBuilder struct {
 	// uniqueCheckHelper is used to prevent allocating the helper separately.
 	uniqueCheckHelper uniqueCheckHelper
 
+	// exclusionCheckHelper is used to prevent allocating the helper separately.
+	exclusionCheckHelper exclusionCheckHelper
+
 	// arbiterPredicateHelper is used to prevent allocating the helper
 	// separately.
 	arbiterPredicateHelper arbiterPredicateHelper
@@ -312,6 +346,10 @@ func (mb *mutationBuilder) buildInputForUpdate(
 	// Set list of columns that will be fetched by the input expression.
 	mb.setFetchColIDs(mb.fetchScope.cols)
 
+	// Only update rows that are visible to the current user and that the
+	// row-level security policies allow to be updated.
+	mb.addRowLevelSecurityFetchFilter(tree.PolicyCommandUpdate)
+
 	// If there is a FROM clause present, we must join all the tables
 	// together with the table being updated.
 	fromClausePresent := len(from) > 0
@@ -423,6 +461,10 @@ func (mb *mutationBuilder) buildInputForDelete(
 	// Set list of columns that will be fetched by the input expression.
 	mb.setFetchColIDs(mb.fetchScope.cols)
 
+	// Only delete rows that are visible to the current user and that the
+	// row-level security policies allow to be deleted.
+	mb.addRowLevelSecurityFetchFilter(tree.PolicyCommandDelete)
+
 	// USING
 	usingClausePresent := len(using) > 0
 	if usingClausePresent {
@@ -1056,6 +1098,7 @@ func (mb *mutationBuilder) makeMutationPrivate(needResults bool) *memo.MutationP
 		FetchCols:           checkEmptyList(mb.fetchColIDs),
 		UpdateCols:          checkEmptyList(mb.updateColIDs),
 		CanaryCol:           mb.canaryColID,
+		MergeActionCol:      mb.mergeActionColID,
 		ArbiterIndexes:      mb.arbiters.IndexOrdinals(),
 		ArbiterConstraints:  mb.arbiters.UniqueConstraintOrdinals(),
 		CheckCols:           checkEmptyList(mb.checkColIDs),
@@ -1216,14 +1259,14 @@ func (mb *mutationBuilder) parseDefaultExpr(colID opt.ColumnID) tree.Expr {
 			return datum
 		}
 
-		return tree.DNull
+		return castToDomain(col, tree.DNull)
 	}
 
-	return mb.parseColExpr(
+	return castToDomain(col, mb.parseColExpr(
 		colID,
 		mb.parsedColDefaultExprs,
 		exprStr,
-	)
+	))
 }
 
 // parseOnUpdateExpr parses the on update (including nullable) expression for
@@ -1234,11 +1277,23 @@ func (mb *mutationBuilder) parseOnUpdateExpr(colID opt.ColumnID) tree.Expr {
 	}
 
 	ord := mb.tabID.ColumnOrdinal(colID)
-	return mb.parseColExpr(
+	col := mb.tab.Column(ord)
+	return castToDomain(col, mb.parseColExpr(
 		colID,
 		mb.parsedColOnUpdateExprs,
-		mb.tab.Column(ord).OnUpdateExprStr(),
-	)
+		col.OnUpdateExprStr(),
+	))
+}
+
+// castToDomain wraps the default or ON UPDATE expression of a column of a
+// domain type in a cast to the domain, so that the NOT NULL and CHECK
+// constraints of the domain are enforced on the synthesized values like they
+// are on the values provided explicitly.
+func castToDomain(col *cat.Column, expr tree.Expr) tree.Expr {
+	if typ := col.DatumType(); typ.IsDomain() {
+		return &tree.CastExpr{Expr: expr, Type: typ, SyntaxMode: tree.CastShort}
+	}
+	return expr
 }
 
 func (mb *mutationBuilder) parseColExpr(
@@ -1459,6 +1514,7 @@ type checkInputScanType uint8
 const (
 	checkInputScanNewVals checkInputScanType = iota
 	checkInputScanFetchedVals
+	checkInputScanMergeDeletedVals
 )
 
 // buildCheckInputScan constructs an expression that produces the new values of
@@ -1467,9 +1523,9 @@ const (
 // iterates over the input to the mutation operator, or a Values expression with
 // constant insert values inlined.
 //
-// If a WithScan expression is returned, it will scan either the new values or
-// the fetched values for the given table ordinals (which correspond to FK or
-// unique columns).
+// If a WithScan expression is returned, it will scan either the new values,
+// the fetched values, or the values deleted by a MERGE statement for the given
+// table ordinals (which correspond to FK or unique columns).
 //
 // Returns a scope containing the WithScan or Values expression and the output
 // columns from the WithScan. The output columns map 1-to-1 to tabOrdinals. Also
@@ -1488,10 +1544,13 @@ func (mb *mutationBuilder) buildCheckInputScan(
 	outScope.cols = make([]scopeColumn, len(inputCols))
 
 	for i, tabOrd := range tabOrdinals {
-		if typ == checkInputScanNewVals {
+		switch typ {
+		case checkInputScanNewVals:
 			inputCols[i] = mb.mapToReturnColID(tabOrd)
-		} else {
+		case checkInputScanFetchedVals:
 			inputCols[i] = mb.fetchColIDs[tabOrd]
+		case checkInputScanMergeDeletedVals:
+			inputCols[i] = mb.mergeDeleteColIDs[tabOrd]
 		}
 		if inputCols[i] == 0 {
 			panic(errors.AssertionFailedf("no value for check input column (tabOrd=%d)", tabOrd))
@@ -1512,9 +1571,10 @@ func (mb *mutationBuilder) buildCheckInputScan(
 
 		// If a table column is not nullable, NULLs cannot be inserted (the
 		// mutation will fail). So for the purposes of checks, we can treat
-		// these columns as not null.
+		// these columns as not null. This does not hold for the deleted values
+		// of a MERGE, which are NULL for the rows that are not deleted.
 		if mb.outScope.expr.Relational().NotNullCols.Contains(inputCols[i]) ||
-			!mb.tab.Column(tabOrd).IsNullable() {
+			(!mb.tab.Column(tabOrd).IsNullable() && typ != checkInputScanMergeDeletedVals) {
 			notNullOutCols.Add(outCol)
 		}
 	}
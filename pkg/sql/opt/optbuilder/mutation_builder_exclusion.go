@@ -0,0 +1,277 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/intsets"
+	"github.com/cockroachdb/errors"
+)
+
+// buildExclusionChecks builds check queries for the EXCLUDE constraints of the
+// table. Each check returns the pairs of new and existing rows that conflict
+// under the constraint's operators. If isUpdate is true, checks are only built
+// for the constraints that reference an updated column.
+//
+// Exclusion checks are stored alongside the uniqueness checks of the mutation,
+// since both are executed as post-queries that raise an error if any rows are
+// returned.
+func (mb *mutationBuilder) buildExclusionChecks(isUpdate bool) {
+	if mb.tab.ExclusionCount() == 0 {
+		return
+	}
+
+	// Exclusion checks cannot lock the ranges of values they compare against,
+	// so concurrent transactions at weaker isolation levels could insert
+	// conflicting rows without either check noticing.
+	if mb.b.evalCtx.TxnIsoLevel != isolation.Serializable {
+		panic(unimplemented.Newf(
+			"exclusion constraints",
+			"writes to tables with exclusion constraints are not supported under %s isolation",
+			mb.b.evalCtx.TxnIsoLevel.StringLower(),
+		))
+	}
+
+	h := &mb.exclusionCheckHelper
+	for i, n := 0, mb.tab.ExclusionCount(); i < n; i++ {
+		// If this constraint doesn't include the updated columns we don't need to
+		// plan a check.
+		if isUpdate && !mb.exclusionColsUpdated(i) {
+			continue
+		}
+		if h.init(mb, i) {
+			mb.uniqueChecks = append(mb.uniqueChecks, h.buildInsertionCheck())
+		}
+	}
+}
+
+// exclusionColsUpdated returns true if any of the columns for an exclusion
+// constraint are being updated (according to updateColIDs). When the exclusion
+// constraint has a partial predicate, it also returns true if the predicate
+// references any of the columns being updated.
+func (mb *mutationBuilder) exclusionColsUpdated(exclusionOrdinal int) bool {
+	ec := mb.tab.Exclusion(exclusionOrdinal)
+
+	for i, n := 0, ec.ColumnCount(); i < n; i++ {
+		if ord := ec.ColumnOrdinal(mb.tab, i); mb.updateColIDs[ord] != 0 {
+			return true
+		}
+	}
+
+	if _, isPartial := ec.Predicate(); isPartial {
+		pred := mb.parseExclusionConstraintPredicateExpr(exclusionOrdinal)
+		typedPred := mb.fetchScope.resolveAndRequireType(pred, types.Bool)
+
+		var predCols opt.ColSet
+		mb.b.buildScalar(typedPred, mb.fetchScope, nil, nil, &predCols)
+		for colID, ok := predCols.Next(0); ok; colID, ok = predCols.Next(colID + 1) {
+			ord := mb.md.ColumnMeta(colID).Table.ColumnOrdinal(colID)
+			if mb.updateColIDs[ord] != 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// parseExclusionConstraintPredicateExpr parses the predicate of the given
+// partial exclusion constraint and caches it for reuse. This function panics if
+// the exclusion constraint at the given ordinal is not partial.
+func (mb *mutationBuilder) parseExclusionConstraintPredicateExpr(exclusionOrdinal int) tree.Expr {
+	predStr, isPartial := mb.tab.Exclusion(exclusionOrdinal).Predicate()
+	if !isPartial {
+		panic(errors.AssertionFailedf(
+			"exclusion constraint at ordinal %d is not a partial exclusion constraint", exclusionOrdinal,
+		))
+	}
+
+	if mb.parsedExclusionConstraintExprs == nil {
+		mb.parsedExclusionConstraintExprs = make([]tree.Expr, mb.tab.ExclusionCount())
+	}
+
+	// Return expression from the cache, if it was already parsed previously.
+	if mb.parsedExclusionConstraintExprs[exclusionOrdinal] != nil {
+		return mb.parsedExclusionConstraintExprs[exclusionOrdinal]
+	}
+
+	expr, err := parser.ParseExpr(predStr)
+	if err != nil {
+		panic(err)
+	}
+
+	mb.parsedExclusionConstraintExprs[exclusionOrdinal] = expr
+	return expr
+}
+
+// exclusionCheckHelper is a type associated with a single exclusion constraint
+// and is used to build the "leaves" of an exclusion check expression, namely
+// the WithScan of the mutation input and the Scan of the table.
+type exclusionCheckHelper struct {
+	mb *mutationBuilder
+
+	exclusion        cat.ExclusionConstraint
+	exclusionOrdinal int
+
+	// primaryKeyOrdinals are the ordinals of the primary key columns, which are
+	// used to prevent rows from conflicting with themselves.
+	primaryKeyOrdinals intsets.Fast
+
+	// The scope and column ordinals of the scan that will serve as the right
+	// side of the join for the exclusion checks.
+	scanScope    *scope
+	scanOrdinals []int
+}
+
+// init initializes the helper with an exclusion constraint.
+//
+// Returns false if the constraint should be ignored (e.g. because the new
+// values for one of the constrained columns are known to be always NULL, in
+// which case no operator can report a conflict).
+func (h *exclusionCheckHelper) init(mb *mutationBuilder, exclusionOrdinal int) bool {
+	// This initialization pattern ensures that fields are not unwittingly
+	// reused. Field reuse must be explicit.
+	*h = exclusionCheckHelper{
+		mb:               mb,
+		exclusion:        mb.tab.Exclusion(exclusionOrdinal),
+		exclusionOrdinal: exclusionOrdinal,
+	}
+
+	for i, n := 0, h.exclusion.ColumnCount(); i < n; i++ {
+		colID := mb.mapToReturnColID(h.exclusion.ColumnOrdinal(mb.tab, i))
+		if memo.OutputColumnIsAlwaysNull(mb.outScope.expr, colID) {
+			return false
+		}
+	}
+
+	h.primaryKeyOrdinals = getIndexLaxKeyOrdinals(mb.tab.Index(cat.PrimaryIndex))
+	h.scanScope, h.scanOrdinals = h.buildTableScan()
+	return true
+}
+
+// buildInsertionCheck creates an exclusion check for rows which are added to a
+// table. The input to the insertion check will be produced from the input to
+// the mutation operator.
+func (h *exclusionCheckHelper) buildInsertionCheck() memo.UniqueChecksItem {
+	f := h.mb.b.factory
+
+	// Build a self inner join, with the new values on the left and the
+	// existing values on the right. An inner join is used rather than a semi
+	// join so that the conflicting existing row can be shown in the error.
+	exclusionCheckScope, _ := h.mb.buildCheckInputScan(
+		checkInputScanNewVals, h.scanOrdinals, false, /* isFK */
+	)
+
+	// Build the join filters:
+	//   (new_a OP_a existing_a) AND (new_b OP_b existing_b) AND ...
+	//
+	// The operators are resolved through the regular scalar building path so
+	// that type-specific operators such as && and -|- are built correctly.
+	numCols := h.exclusion.ColumnCount()
+	joinFilters := make(memo.FiltersExpr, 0, numCols+3)
+	for i := 0; i < numCols; i++ {
+		ord := h.exclusion.ColumnOrdinal(h.mb.tab, i)
+		cmp := &tree.ComparisonExpr{
+			Operator: h.exclusion.Operator(i),
+			Left:     &exclusionCheckScope.cols[ord],
+			Right:    &h.scanScope.cols[ord],
+		}
+		typedCmp := exclusionCheckScope.resolveAndRequireType(cmp, types.Bool)
+		joinFilters = append(joinFilters, f.ConstructFiltersItem(
+			h.mb.b.buildScalar(typedCmp, exclusionCheckScope, nil, nil, nil),
+		))
+	}
+
+	// If the exclusion constraint is partial, only rows that satisfy the
+	// predicate can conflict, so we add the predicate as a filter on both the
+	// WithScan columns and the Scan columns.
+	if _, isPartial := h.exclusion.Predicate(); isPartial {
+		pred := h.mb.parseExclusionConstraintPredicateExpr(h.exclusionOrdinal)
+
+		typedPred := exclusionCheckScope.resolveAndRequireType(pred, types.Bool)
+		withScanPred := h.mb.b.buildScalar(typedPred, exclusionCheckScope, nil, nil, nil)
+		joinFilters = append(joinFilters, f.ConstructFiltersItem(withScanPred))
+
+		typedPred = h.scanScope.resolveAndRequireType(pred, types.Bool)
+		scanPred := h.mb.b.buildScalar(typedPred, h.scanScope, nil, nil, nil)
+		joinFilters = append(joinFilters, f.ConstructFiltersItem(scanPred))
+	}
+
+	// We need to prevent rows from matching themselves in the join. We can do
+	// this by adding another filter that uses the primary keys to check if two
+	// rows are identical:
+	//    (new_pk1 != existing_pk1) OR (new_pk2 != existing_pk2) OR ...
+	var pkFilter opt.ScalarExpr
+	for i, ok := h.primaryKeyOrdinals.Next(0); ok; i, ok = h.primaryKeyOrdinals.Next(i + 1) {
+		pkFilterLocal := f.ConstructNe(
+			f.ConstructVariable(exclusionCheckScope.cols[i].id),
+			f.ConstructVariable(h.scanScope.cols[i].id),
+		)
+		if pkFilter == nil {
+			pkFilter = pkFilterLocal
+		} else {
+			pkFilter = f.ConstructOr(pkFilter, pkFilterLocal)
+		}
+	}
+	joinFilters = append(joinFilters, f.ConstructFiltersItem(pkFilter))
+
+	join := f.ConstructInnerJoin(
+		exclusionCheckScope.expr, h.scanScope.expr, joinFilters, memo.EmptyJoinPrivate,
+	)
+
+	// Collect the key columns that will be shown in the error message if there
+	// is a conflict: the constrained columns of the new row, followed by the
+	// same columns of the existing row.
+	keyCols := make(opt.ColList, 0, 2*numCols)
+	for i := 0; i < numCols; i++ {
+		keyCols = append(keyCols, exclusionCheckScope.cols[h.exclusion.ColumnOrdinal(h.mb.tab, i)].id)
+	}
+	for i := 0; i < numCols; i++ {
+		keyCols = append(keyCols, h.scanScope.cols[h.exclusion.ColumnOrdinal(h.mb.tab, i)].id)
+	}
+
+	// Create a Project that passes-through only the key columns. This allows
+	// normalization rules to prune any unnecessary columns from the expression.
+	project := f.ConstructProject(join, nil /* projections */, keyCols.ToSet())
+
+	return f.ConstructUniqueChecksItem(project, &memo.UniqueChecksItemPrivate{
+		Table:        h.mb.tabID,
+		CheckOrdinal: h.exclusionOrdinal,
+		KeyCols:      keyCols,
+		Exclusion:    true,
+	})
+}
+
+// buildTableScan builds a Scan of the table. The ordinals of the columns
+// scanned are also returned.
+func (h *exclusionCheckHelper) buildTableScan() (outScope *scope, ordinals []int) {
+	tabMeta := h.mb.b.addTable(h.mb.tab, tree.NewUnqualifiedTableName(h.mb.tab.Name()))
+	ordinals = tableOrdinals(tabMeta.Table, columnKinds{
+		includeMutations: false,
+		includeSystem:    false,
+		includeInverted:  false,
+	})
+	return h.mb.b.buildScan(
+		tabMeta,
+		ordinals,
+		nil, /* indexFlags */
+		noRowLocking,
+		h.mb.b.allocScope(),
+		true, /* disableNotVisibleIndex */
+	), ordinals
+}
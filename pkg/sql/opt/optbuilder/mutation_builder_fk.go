@@ -79,7 +79,7 @@ func (mb *mutationBuilder) buildFKChecksForInsert() {
 
 	h := &mb.fkCheckHelper
 	for i, n := 0, mb.tab.OutboundForeignKeyCount(); i < n; i++ {
-		if h.initWithOutboundFK(mb, i) {
+		if h.initWithOutboundFK(mb, i) && !h.deferred() {
 			mb.fkChecks = append(mb.fkChecks, h.buildInsertionCheck())
 		}
 	}
@@ -167,6 +167,7 @@ func (mb *mutationBuilder) buildFKChecksAndCascadesForDelete() {
 			for i, tabOrd := range h.tabOrdinals {
 				cols[i] = mb.fetchColIDs[tabOrd]
 			}
+			mb.addCascadeTriggersBefore(h.fk, h.otherTab, a, true /* isDelete */)
 			mb.cascades = append(mb.cascades, memo.FKCascade{
 				FKName:    h.fk.Name(),
 				Builder:   builder,
@@ -176,6 +177,10 @@ func (mb *mutationBuilder) buildFKChecksAndCascadesForDelete() {
 			})
 			continue
 		}
+		if h.fk.DeleteReferenceAction() == tree.NoAction && h.deferred() {
+			// The check is performed when the transaction commits.
+			continue
+		}
 
 		withScanScope, _ := mb.buildCheckInputScan(checkInputScanFetchedVals, h.tabOrdinals, true /* isFK */)
 		mb.fkChecks = append(mb.fkChecks, h.buildDeletionCheck(withScanScope.expr, withScanScope.colList()))
@@ -279,7 +284,7 @@ func (mb *mutationBuilder) buildFKChecksForUpdate() {
 	for i, n := 0, mb.tab.OutboundForeignKeyCount(); i < n; i++ {
 		// Verify that at least one FK column is actually updated.
 		if mb.outboundFKColsUpdated(i) {
-			if h.initWithOutboundFK(mb, i) {
+			if h.initWithOutboundFK(mb, i) && !h.deferred() {
 				mb.fkChecks = append(mb.fkChecks, h.buildInsertionCheck())
 			}
 		}
@@ -314,6 +319,7 @@ func (mb *mutationBuilder) buildFKChecksForUpdate() {
 				oldCols[i] = fetchColID
 				newCols[i] = updateColID
 			}
+			mb.addCascadeTriggersBefore(h.fk, h.otherTab, a, false /* isDelete */)
 			mb.cascades = append(mb.cascades, memo.FKCascade{
 				FKName:    h.fk.Name(),
 				Builder:   builder,
@@ -323,6 +329,10 @@ func (mb *mutationBuilder) buildFKChecksForUpdate() {
 			})
 			continue
 		}
+		if h.fk.UpdateReferenceAction() == tree.NoAction && h.deferred() {
+			// The check is performed when the transaction commits.
+			continue
+		}
 
 		// Construct an Except expression for the set difference between "old"
 		// FK values and "new" FK values.
@@ -399,7 +409,7 @@ func (mb *mutationBuilder) buildFKChecksForUpsert() {
 
 	h := &mb.fkCheckHelper
 	for i := 0; i < numOutbound; i++ {
-		if h.initWithOutboundFK(mb, i) {
+		if h.initWithOutboundFK(mb, i) && !h.deferred() {
 			mb.fkChecks = append(mb.fkChecks, h.buildInsertionCheck())
 		}
 	}
@@ -435,6 +445,7 @@ func (mb *mutationBuilder) buildFKChecksForUpsert() {
 				oldCols[i] = fetchColID
 				newCols[i] = updateColID
 			}
+			mb.addCascadeTriggersBefore(h.fk, h.otherTab, a, false /* isDelete */)
 			mb.cascades = append(mb.cascades, memo.FKCascade{
 				FKName:    h.fk.Name(),
 				Builder:   builder,
@@ -444,6 +455,10 @@ func (mb *mutationBuilder) buildFKChecksForUpsert() {
 			})
 			continue
 		}
+		if h.fk.UpdateReferenceAction() == tree.NoAction && h.deferred() {
+			// The check is performed when the transaction commits.
+			continue
+		}
 
 		// Construct an Except expression for the set difference between "old" FK
 		// values and "new" FK values. See buildFKChecksForUpdate for more details.
@@ -472,9 +487,67 @@ func (mb *mutationBuilder) buildFKChecksForUpsert() {
 		)
 		mb.fkChecks = append(mb.fkChecks, h.buildDeletionCheck(deletedRows, oldRowsScope.colList()))
 	}
+
+	if mb.mergeDeleteColIDs != nil {
+		mb.buildFKChecksAndCascadesForMergeDelete()
+	}
 	telemetry.Inc(sqltelemetry.ForeignKeyChecksUseCounter)
 }
 
+// buildFKChecksAndCascadesForMergeDelete builds the FK checks and cascades for
+// the rows deleted by a MERGE statement. They are built as for a DELETE
+// statement (see buildFKChecksAndCascadesForDelete), except that the old values
+// are taken from mb.mergeDeleteColIDs, which are NULL for the rows that are
+// inserted or updated rather than deleted. Such rows are ignored by the
+// semi-joins of the checks and cascades.
+//
+// The fast path cascade is never used, because the rows that are deleted
+// cannot be described by a filter on the target table.
+func (mb *mutationBuilder) buildFKChecksAndCascadesForMergeDelete() {
+	h := &mb.fkCheckHelper
+	for i, n := 0, mb.tab.InboundForeignKeyCount(); i < n; i++ {
+		if !h.initWithInboundFK(mb, i) {
+			continue
+		}
+		if a := h.fk.DeleteReferenceAction(); a != tree.Restrict && a != tree.NoAction {
+			telemetry.Inc(sqltelemetry.ForeignKeyCascadesUseCounter)
+			mb.ensureWithID()
+			var builder memo.CascadeBuilder
+			switch a {
+			case tree.Cascade:
+				builder = newOnDeleteCascadeBuilder(mb.tab, i, h.otherTab)
+			case tree.SetNull, tree.SetDefault:
+				builder = newOnDeleteSetBuilder(mb.tab, i, h.otherTab, a)
+			default:
+				panic(errors.AssertionFailedf("unhandled action type %s", a))
+			}
+
+			cols := make(opt.ColList, len(h.tabOrdinals))
+			for i, tabOrd := range h.tabOrdinals {
+				cols[i] = mb.mergeDeleteColIDs[tabOrd]
+			}
+			mb.addCascadeTriggersBefore(h.fk, h.otherTab, a, true /* isDelete */)
+			mb.cascades = append(mb.cascades, memo.FKCascade{
+				FKName:    h.fk.Name(),
+				Builder:   builder,
+				WithID:    mb.withID,
+				OldValues: cols,
+				NewValues: nil,
+			})
+			continue
+		}
+		if h.fk.DeleteReferenceAction() == tree.NoAction && h.deferred() {
+			// The check is performed when the transaction commits.
+			continue
+		}
+
+		withScanScope, _ := mb.buildCheckInputScan(
+			checkInputScanMergeDeletedVals, h.tabOrdinals, true, /* isFK */
+		)
+		mb.fkChecks = append(mb.fkChecks, h.buildDeletionCheck(withScanScope.expr, withScanScope.colList()))
+	}
+}
+
 // outboundFKColsUpdated returns true if any of the FK columns for an outbound
 // constraint are being updated (according to updateColIDs).
 func (mb *mutationBuilder) outboundFKColsUpdated(fkOrdinal int) bool {
@@ -617,6 +690,14 @@ func (h *fkCheckHelper) initWithInboundFK(mb *mutationBuilder, fkOrdinal int) (o
 	return true
 }
 
+// deferred returns true if the checks for the FK constraint are deferred until
+// the transaction commits (see SET CONSTRAINTS). Only NO ACTION deletion-side
+// checks can be deferred; RESTRICT is always checked immediately.
+func (h *fkCheckHelper) deferred() bool {
+	mode := h.mb.b.evalCtx.SessionData().ConstraintsModeFor(uint32(h.fk.OriginTableID()), h.fk.Name())
+	return h.fk.Deferrability().IsDeferred(mode)
+}
+
 // resolveTable resolves a table StableID. Returns nil if the table is in the
 // process of being added, in which case it is safe to ignore any FK
 // relation with the table.
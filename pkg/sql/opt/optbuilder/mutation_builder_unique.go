@@ -51,6 +51,11 @@ func (mb *mutationBuilder) buildUniqueChecksForInsert() {
 		if !mb.tab.Unique(i).WithoutIndex() {
 			continue
 		}
+		// If this constraint is deferred, it is checked when the transaction
+		// commits instead.
+		if mb.uniqueConstraintIsDeferred(i) {
+			continue
+		}
 		// If this constraint is an arbiter of an INSERT ... ON CONFLICT ... DO
 		// NOTHING clause, we don't need to plan a check (ON CONFLICT ... DO UPDATE
 		// does not go through this code path; that's handled by
@@ -92,6 +97,11 @@ func (mb *mutationBuilder) buildUniqueChecksForUpdate() {
 		if !mb.tab.Unique(i).WithoutIndex() {
 			continue
 		}
+		// If this constraint is deferred, it is checked when the transaction
+		// commits instead.
+		if mb.uniqueConstraintIsDeferred(i) {
+			continue
+		}
 		// If this constraint doesn't include the updated columns we don't need to
 		// plan a check.
 		if !mb.uniqueColsUpdated(i) {
@@ -127,6 +137,11 @@ func (mb *mutationBuilder) buildUniqueChecksForUpsert() {
 		if !mb.tab.Unique(i).WithoutIndex() {
 			continue
 		}
+		// If this constraint is deferred, it is checked when the transaction
+		// commits instead.
+		if mb.uniqueConstraintIsDeferred(i) {
+			continue
+		}
 		// If this constraint is an arbiter of an INSERT ... ON CONFLICT ... DO
 		// UPDATE clause and not updated by the DO UPDATE clause, we don't need to
 		// plan a check (ON CONFLICT ... DO NOTHING does not go through this code
@@ -196,6 +211,14 @@ func (mb *mutationBuilder) uniqueConstraintIsArbiter(uniqueOrdinal int) bool {
 	return mb.arbiters.ContainsUniqueConstraint(uniqueOrdinal)
 }
 
+// uniqueConstraintIsDeferred returns true if the checks for the given unique
+// constraint are deferred until the transaction commits (see SET CONSTRAINTS).
+func (mb *mutationBuilder) uniqueConstraintIsDeferred(uniqueOrdinal int) bool {
+	u := mb.tab.Unique(uniqueOrdinal)
+	mode := mb.b.evalCtx.SessionData().ConstraintsModeFor(uint32(mb.tab.ID()), u.Name())
+	return u.Deferrability().IsDeferred(mode)
+}
+
 // uniqueCheckHelper is a type associated with a single unique constraint and
 // is used to build the "leaves" of a unique check expression, namely the
 // WithScan of the mutation input and the Scan of the table.
@@ -149,6 +149,11 @@ type plpgsqlBuilder struct {
 	// params tracks the names and types for the original function parameters.
 	params []tree.ParamType
 
+	// outParams tracks the names and types for the OUT and INOUT parameters of
+	// the function, in the order they were declared. They are used to build the
+	// result of the function when it has output parameters.
+	outParams []tree.ParamType
+
 	// decls is the set of variable declarations for a PL/pgSQL function.
 	decls []ast.Declaration
 
@@ -187,14 +192,29 @@ type plpgsqlBuilder struct {
 }
 
 func (b *plpgsqlBuilder) init(
-	ob *Builder, colRefs *opt.ColSet, params []tree.ParamType, block *ast.Block, returnType *types.T,
+	ob *Builder,
+	colRefs *opt.ColSet,
+	params, outParams []tree.ParamType,
+	block *ast.Block,
+	returnType *types.T,
 ) {
 	b.ob = ob
 	b.colRefs = colRefs
 	b.params = params
+	b.outParams = outParams
 	b.returnType = returnType
 	b.varTypes = make(map[tree.Name]*types.T)
 	b.cursors = make(map[tree.Name]ast.CursorDeclaration)
+	for _, param := range outParams {
+		if b.isInputParam(param.Name) {
+			// INOUT parameters are passed into the function like other input
+			// parameters, but can be assigned to like variables.
+			b.varTypes[tree.Name(param.Name)] = param.Typ
+			continue
+		}
+		// OUT parameters are modeled as variables that are initialized to NULL.
+		b.decls = append(b.decls, ast.Declaration{Var: tree.Name(param.Name), Typ: param.Typ})
+	}
 	for i := range block.Decls {
 		switch dec := block.Decls[i].(type) {
 		case *ast.Declaration:
@@ -253,7 +273,7 @@ func (b *plpgsqlBuilder) build(block *ast.Block, s *scope) *scope {
 			b.constants[dec.Var] = struct{}{}
 		}
 	}
-	if types.IsRecordType(b.returnType) {
+	if types.IsRecordType(b.returnType) && len(b.outParams) == 0 {
 		// Infer the concrete type by examining the RETURN statements. This has to
 		// happen after building the declaration block because RETURN statements can
 		// reference declared variables.
@@ -293,8 +313,26 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 		switch t := stmt.(type) {
 		case *ast.Return:
 			// RETURN is handled by projecting a single column with the expression
-			// that is being returned.
-			returnScalar := b.buildPLpgSQLExpr(t.Expr, b.returnType, s)
+			// that is being returned. In a function with output parameters, RETURN
+			// returns the current values of the output parameters.
+			var returnScalar opt.ScalarExpr
+			if len(b.outParams) > 0 {
+				if t.Expr != nil {
+					panic(pgerror.New(pgcode.DatatypeMismatch,
+						"RETURN cannot have a parameter in function with OUT parameters",
+					))
+				}
+				returnScalar = b.buildOutParamsReturn(s)
+			} else {
+				expr := t.Expr
+				if expr == nil {
+					if b.returnType.Family() != types.VoidFamily {
+						panic(pgerror.New(pgcode.Syntax, "missing expression"))
+					}
+					expr = tree.DNull
+				}
+				returnScalar = b.buildPLpgSQLExpr(expr, b.returnType, s)
+			}
 			b.addBarrierIfVolatile(s, returnScalar)
 			returnColName := scopeColName("").WithMetadataName(b.makeIdentifier("stmt_return"))
 			returnScope := s.push()
@@ -306,7 +344,11 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 		case *ast.Assignment:
 			// Assignment (:=) is handled by projecting a new column with the same
 			// name as the variable being assigned.
-			s = b.addPLpgSQLAssign(s, t.Var, t.Value)
+			val := t.Value
+			if t.Indirection != "" {
+				val = b.makeFieldAssignExpr(t.Var, t.Indirection, t.Value)
+			}
+			s = b.addPLpgSQLAssign(s, t.Var, val)
 			if b.hasExceptionBlock {
 				// If exception handling is required, we have to start a new
 				// continuation after each variable assignment. This ensures that in the
@@ -573,9 +615,6 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 			// This is handled by calling the plpgsql_open_cursor internal builtin
 			// function in a separate body statement that returns no results, similar
 			// to the RAISE implementation.
-			if t.Scroll == tree.Scroll {
-				panic(unimplemented.NewWithIssue(77102, "DECLARE SCROLL CURSOR"))
-			}
 			openCon := b.makeContinuation("_stmt_open")
 			openCon.def.Volatility = volatility.Volatile
 			_, source, _, err := openCon.s.FindSourceProvidingColumn(b.ob.ctx, t.CurVar)
@@ -817,6 +856,40 @@ func (b *plpgsqlBuilder) addPLpgSQLAssign(inScope *scope, ident ast.Variable, va
 	return assignScope
 }
 
+// makeFieldAssignExpr rewrites an assignment to a single field of a
+// composite-typed variable (e.g. NEW.x := 1) as an assignment to the whole
+// variable. The returned expression is a tuple in which the assigned field
+// takes the new value, and every other field is copied from the current value
+// of the variable.
+func (b *plpgsqlBuilder) makeFieldAssignExpr(
+	ident ast.Variable, field tree.Name, val ast.Expr,
+) ast.Expr {
+	typ := b.resolveVariableForAssign(ident)
+	if typ.Family() != types.TupleFamily || len(typ.TupleLabels()) == 0 {
+		panic(pgerror.Newf(pgcode.Syntax, "\"%s.%s\" is not a known variable", ident, field))
+	}
+	labels := typ.TupleLabels()
+	exprs := make(tree.Exprs, len(labels))
+	found := false
+	for i, fieldTyp := range typ.TupleContents() {
+		if tree.Name(labels[i]) == field {
+			exprs[i] = &tree.CastExpr{Expr: val, Type: fieldTyp, SyntaxMode: tree.CastShort}
+			found = true
+			continue
+		}
+		exprs[i] = &tree.ColumnAccessExpr{
+			Expr:    tree.NewUnresolvedName(string(ident)),
+			ColName: tree.Name(labels[i]),
+		}
+	}
+	if !found {
+		panic(pgerror.Newf(pgcode.UndefinedColumn,
+			"record \"%s\" has no field \"%s\"", ident, field,
+		))
+	}
+	return &tree.Tuple{Exprs: exprs, Labels: labels}
+}
+
 // buildInto handles the mapping from the columns of a SQL statement to the
 // variables in an INTO target.
 func (b *plpgsqlBuilder) buildInto(stmtScope *scope, target []ast.Variable) *scope {
@@ -1150,10 +1223,44 @@ func (b *plpgsqlBuilder) buildExceptions(block *ast.Block) *memo.ExceptionBlock
 	}
 }
 
+// buildOutParamsReturn builds an expression that returns the current values
+// of the output parameters of the routine. A function with a single output
+// parameter returns the value of that parameter; otherwise, the values are
+// combined into a tuple.
+func (b *plpgsqlBuilder) buildOutParamsReturn(s *scope) opt.ScalarExpr {
+	elems := make(memo.ScalarListExpr, len(b.outParams))
+	for i, param := range b.outParams {
+		_, source, _, err := s.FindSourceProvidingColumn(b.ob.ctx, tree.Name(param.Name))
+		if err != nil {
+			panic(err)
+		}
+		elems[i] = b.ob.factory.ConstructVariable(source.(*scopeColumn).id)
+	}
+	if len(elems) == 1 && b.returnType.Identical(b.outParams[0].Typ) {
+		return elems[0]
+	}
+	return b.ob.factory.ConstructTuple(elems, b.returnType)
+}
+
+// isInputParam returns true if the given name belongs to an input parameter of
+// the routine.
+func (b *plpgsqlBuilder) isInputParam(name string) bool {
+	for _, param := range b.params {
+		if param.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // buildEndOfFunctionRaise builds a RAISE statement that throws an error when
 // control reaches the end of a PLpgSQL routine without reaching a RETURN
-// statement.
+// statement. If the routine has output parameters, reaching the end of the
+// routine instead returns their values.
 func (b *plpgsqlBuilder) buildEndOfFunctionRaise(inScope *scope) *scope {
+	if len(b.outParams) > 0 {
+		return b.buildPLpgSQLStatements([]ast.Statement{&ast.Return{}}, inScope)
+	}
 	makeConstStr := func(str string) opt.ScalarExpr {
 		return b.ob.factory.ConstructConstVal(tree.NewDString(str), types.String)
 	}
@@ -1392,7 +1499,7 @@ func (b *plpgsqlBuilder) addBarrierIfVolatile(s *scope, expr opt.ScalarExpr) {
 	var p props.Shared
 	memo.BuildSharedProps(expr, &p, b.ob.evalCtx)
 	if p.VolatilitySet.HasVolatile() {
-		s.expr = b.ob.factory.ConstructBarrier(s.expr)
+		s.expr = b.ob.factory.ConstructBarrier(s.expr, &memo.BarrierPrivate{})
 	}
 }
 
@@ -1534,7 +1641,7 @@ func newRecordTypeVisitor(
 var _ ast.StatementVisitor = &recordTypeVisitor{}
 
 func (r *recordTypeVisitor) Visit(stmt ast.Statement) (newStmt ast.Statement, changed bool) {
-	if retStmt, ok := stmt.(*ast.Return); ok {
+	if retStmt, ok := stmt.(*ast.Return); ok && retStmt.Expr != nil {
 		desired := types.Any
 		if r.typ != types.Unknown {
 			desired = r.typ
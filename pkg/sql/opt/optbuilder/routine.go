@@ -25,8 +25,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/intsets"
 	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
 )
 
 // buildUDF builds a set of memo groups that represents a user-defined function
@@ -54,6 +55,11 @@ func (b *Builder) buildUDF(
 		))
 	}
 
+	if f.ResolvedType().Family() == types.TriggerFamily {
+		panic(pgerror.New(pgcode.FeatureNotSupported,
+			"trigger functions can only be called as triggers"))
+	}
+
 	// Check for execution privileges for user-defined overloads. Built-in
 	// overloads do not need to be checked.
 	if o.Type == tree.UDFRoutine {
@@ -69,7 +75,10 @@ func (b *Builder) buildUDF(
 	if outCol == nil {
 		if isMultiColDataSource {
 			// TODO(harding): Add the returns record property during create function.
-			f.ResolvedOverload().ReturnsRecordType = types.IsRecordType(rtyp)
+			// The output columns of a routine with OUT parameters are named by
+			// the parameters, so they do not need a column definition list.
+			f.ResolvedOverload().ReturnsRecordType = types.IsRecordType(rtyp) &&
+				!f.ResolvedOverload().RoutineParams.HasOutParams()
 			return b.finishBuildGeneratorFunction(f, f.ResolvedOverload(), routine, inScope, outScope, outCol)
 		}
 		if outScope != nil {
@@ -129,12 +138,23 @@ func (b *Builder) buildProcedure(c *tree.Call, inScope *scope) *scope {
 	}
 
 	// Build the routine.
-	routine, _, _ := b.buildRoutine(c.Proc, def, inScope, nil /* colRefs */)
+	routine, rtyp, _ := b.buildRoutine(c.Proc, def, inScope, nil /* colRefs */)
 	routine = b.finishBuildScalar(nil /* texpr */, routine, inScope,
 		nil /* outScope */, nil /* outCol */)
 
+	// A procedure with output parameters returns a single row with a column for
+	// each output parameter.
+	if o.RoutineParams.HasOutParams() {
+		for i := range rtyp.TupleContents() {
+			colName := scopeColName(tree.Name(rtyp.TupleLabels()[i]))
+			b.synthesizeColumn(outScope, colName, rtyp.TupleContents()[i], nil /* expr */, nil /* scalar */)
+		}
+	}
+
 	// Build a call expression.
-	outScope.expr = b.factory.ConstructCall(routine)
+	outScope.expr = b.factory.ConstructCall(
+		routine, &memo.CallPrivate{Columns: colsToColList(outScope.cols)},
+	)
 	return outScope
 }
 
@@ -153,7 +173,7 @@ func (b *Builder) buildRoutine(
 	rtyp := f.ResolvedType()
 	if rtyp.UserDefined() {
 		funcReturnType, err := tree.ResolveType(b.ctx,
-			&tree.OIDTypeReference{OID: rtyp.Oid()}, b.semaCtx.TypeResolver)
+			&tree.OIDTypeReference{OID: rtyp.UserDefinedTypeOID()}, b.semaCtx.TypeResolver)
 		if err != nil {
 			panic(err)
 		}
@@ -163,13 +183,91 @@ func (b *Builder) buildRoutine(
 				"return type mismatch in function declared to return %s", rtyp.Name()))
 		}
 	}
+
+	// Create a new scope for building the statements in the function body. We
+	// start with an empty scope because a statement in the function body cannot
+	// refer to anything from the outer expression. If there are function
+	// parameters, we add them as columns to the scope so that references to
+	// them can be resolved.
+	//
+	// TODO(mgartner): We may need to set bodyScope.atRoot=true to prevent
+	// CTEs that mutate and are not at the top-level.
+	bodyScope := b.allocScope()
+	var args memo.ScalarListExpr
+	var params opt.ColList
+	// inParams and outParams are the input and output parameters of the
+	// routine. INOUT parameters are included in both.
+	var inParams, outParams []tree.ParamType
+	buildArg := func(i int) opt.ScalarExpr {
+		return b.buildScalar(
+			f.Exprs[i].(tree.TypedExpr),
+			inScope,
+			nil, /* outScope */
+			nil, /* outCol */
+			colRefs,
+		)
+	}
+	addParam := func(param tree.ParamType, arg opt.ScalarExpr) {
+		argColName := funcParamColName(tree.Name(param.Name), len(params))
+		col := b.synthesizeColumn(bodyScope, argColName, param.Typ, nil /* expr */, nil /* scalar */)
+		col.setParamOrd(len(params))
+		params = append(params, col.id)
+		args = append(args, arg)
+		inParams = append(inParams, param)
+	}
+	if o.RoutineParams != nil {
+		// Only the input parameters are passed to the body of the routine. The
+		// arguments supplied for the OUT parameters of a procedure are ignored,
+		// and the trailing arguments matching a VARIADIC parameter are collected
+		// into an array, unless the last argument is marked VARIADIC, in which
+		// case it is that array.
+		isProcedure := o.Type == tree.ProcedureRoutine
+		var argIdx int
+		for i := range o.RoutineParams {
+			param := &o.RoutineParams[i]
+			paramType := tree.ParamType{Name: string(param.Name), Typ: param.Type.(*types.T)}
+			if param.IsOutParam() {
+				paramType.Name = outParamName(param.Name, i)
+				outParams = append(outParams, paramType)
+			}
+			if !param.IsSignatureParam(isProcedure) {
+				continue
+			}
+			var arg opt.ScalarExpr
+			if param.Class == tree.RoutineParamVariadic && !f.Variadic {
+				elems := make(memo.ScalarListExpr, 0, len(f.Exprs)-argIdx)
+				for ; argIdx < len(f.Exprs); argIdx++ {
+					elems = append(elems, buildArg(argIdx))
+				}
+				arg = b.factory.ConstructArray(elems, paramType.Typ)
+			} else {
+				arg = buildArg(argIdx)
+				argIdx++
+			}
+			if param.IsInParam() {
+				addParam(paramType, arg)
+			}
+		}
+	} else if o.Types.Length() > 0 {
+		paramTypes, ok := o.Types.(tree.ParamTypes)
+		if !ok {
+			panic(errors.AssertionFailedf("expected parameters of routine %s", def.Name))
+		}
+		for i := range paramTypes {
+			addParam(paramTypes[i], buildArg(i))
+		}
+	}
+
 	// If returning a RECORD type, the function return type needs to be modified
 	// because when we first parse the CREATE FUNCTION, the RECORD is
 	// represented as a tuple with any types and execution requires the types to
 	// be concrete in order to decode them correctly. We can determine the types
 	// from the result columns or tuple of the last statement.
+	//
+	// The return type of a routine with OUT parameters is already concrete.
+	hasOutParams := len(outParams) > 0
 	finishResolveType := func(lastStmtScope *scope) *types.T {
-		if types.IsRecordType(rtyp) {
+		if types.IsRecordType(rtyp) && !hasOutParams {
 			if len(lastStmtScope.cols) == 1 &&
 				lastStmtScope.cols[0].typ.Family() == types.TupleFamily {
 				// When the final statement returns a single tuple, we can use
@@ -191,47 +289,6 @@ func (b *Builder) buildRoutine(
 		return rtyp
 	}
 
-	// Build the argument expressions.
-	var args memo.ScalarListExpr
-	if len(f.Exprs) > 0 {
-		args = make(memo.ScalarListExpr, len(f.Exprs))
-		for i, pexpr := range f.Exprs {
-			args[i] = b.buildScalar(
-				pexpr.(tree.TypedExpr),
-				inScope,
-				nil, /* outScope */
-				nil, /* outCol */
-				colRefs,
-			)
-		}
-	}
-
-	// Create a new scope for building the statements in the function body. We
-	// start with an empty scope because a statement in the function body cannot
-	// refer to anything from the outer expression. If there are function
-	// parameters, we add them as columns to the scope so that references to
-	// them can be resolved.
-	//
-	// TODO(mgartner): We may need to set bodyScope.atRoot=true to prevent
-	// CTEs that mutate and are not at the top-level.
-	bodyScope := b.allocScope()
-	var params opt.ColList
-	if o.Types.Length() > 0 {
-		paramTypes, ok := o.Types.(tree.ParamTypes)
-		if !ok {
-			panic(unimplemented.NewWithIssue(88947,
-				"variadiac user-defined functions are not yet supported"))
-		}
-		params = make(opt.ColList, len(paramTypes))
-		for i := range paramTypes {
-			paramType := &paramTypes[i]
-			argColName := funcParamColName(tree.Name(paramType.Name), i)
-			col := b.synthesizeColumn(bodyScope, argColName, paramType.Typ, nil /* expr */, nil /* scalar */)
-			col.setParamOrd(i)
-			params[i] = col.id
-		}
-	}
-
 	// TODO(mgartner): Once other UDFs can be referenced from within a UDF, a
 	// boolean will not be sufficient to track whether or not we are in a UDF.
 	// We'll need to track the depth of the UDFs we are building expressions
@@ -276,7 +333,7 @@ func (b *Builder) buildRoutine(
 			if i == len(stmts)-1 {
 				rtyp = finishResolveType(stmtScope)
 				expr, physProps, isMultiColDataSource =
-					b.finishBuildLastStmt(stmtScope, bodyScope, isSetReturning, f)
+					b.finishBuildLastStmt(stmtScope, bodyScope, isSetReturning, rtyp)
 			}
 			body[i] = expr
 			bodyProps[i] = physProps
@@ -301,11 +358,11 @@ func (b *Builder) buildRoutine(
 		var expr memo.RelExpr
 		var physProps *physical.Required
 		var plBuilder plpgsqlBuilder
-		plBuilder.init(b, colRefs, o.Types.(tree.ParamTypes), stmt.AST, rtyp)
+		plBuilder.init(b, colRefs, inParams, outParams, stmt.AST, rtyp)
 		stmtScope := plBuilder.build(stmt.AST, bodyScope)
 		rtyp = finishResolveType(stmtScope)
 		expr, physProps, isMultiColDataSource =
-			b.finishBuildLastStmt(stmtScope, bodyScope, isSetReturning, f)
+			b.finishBuildLastStmt(stmtScope, bodyScope, isSetReturning, rtyp)
 		body = []memo.RelExpr{expr}
 		bodyProps = []*physical.Required{physProps}
 	default:
@@ -337,12 +394,11 @@ func (b *Builder) buildRoutine(
 // finishBuildLastStmt manages the columns returned by the last statement of a
 // UDF. Depending on the context and return type of the UDF, this may mean
 // expanding a tuple into multiple columns, or combining multiple columns into
-// a tuple.
+// a tuple. rtyp is the return type of the UDF.
 func (b *Builder) finishBuildLastStmt(
-	stmtScope *scope, bodyScope *scope, isSetReturning bool, f *tree.FuncExpr,
+	stmtScope *scope, bodyScope *scope, isSetReturning bool, rtyp *types.T,
 ) (expr memo.RelExpr, physProps *physical.Required, isMultiColDataSource bool) {
 	expr, physProps = stmtScope.expr, stmtScope.makePhysicalProps()
-	rtyp := f.ResolvedType()
 
 	// Add a LIMIT 1 to the last statement if the UDF is not
 	// set-returning. This is valid because any other rows after the
@@ -394,6 +450,20 @@ func (b *Builder) finishBuildLastStmt(
 		for i := range cols {
 			elems[i] = b.factory.ConstructVariable(cols[i].ID)
 		}
+		// If the tuple type is known, as it is for routines with OUT
+		// parameters, add assignment casts for columns with a different type.
+		if typs := rtyp.TupleContents(); len(typs) == len(cols) && !types.IsWildcardTupleType(rtyp) {
+			for i := range cols {
+				colMeta := b.factory.Metadata().ColumnMeta(cols[i].ID)
+				if colMeta.Type.Identical(typs[i]) {
+					continue
+				}
+				if !cast.ValidCast(colMeta.Type, typs[i], cast.ContextAssignment) {
+					panic(sqlerrors.NewInvalidAssignmentCastError(colMeta.Type, typs[i], colMeta.Alias))
+				}
+				elems[i] = b.factory.ConstructAssignmentCast(elems[i], typs[i])
+			}
+		}
 		tup := b.factory.ConstructTuple(elems, rtyp)
 		stmtScope = bodyScope.push()
 		col := b.synthesizeColumn(stmtScope, scopeColName(""), rtyp, nil /* expr */, tup)
@@ -428,3 +498,92 @@ func (b *Builder) finishBuildLastStmt(
 	}
 	return expr, physProps, isMultiColDataSource
 }
+
+// constructUserDefinedAggregate constructs a user-defined aggregate function
+// with the given definition and arguments. The transition and final functions
+// of the aggregate are built as routines that are invoked for each group
+// during execution.
+func (b *Builder) constructUserDefinedAggregate(
+	def *memo.FunctionPrivate, args []opt.ScalarExpr,
+) opt.ScalarExpr {
+	o := def.Overload
+	agg := o.UserDefinedAggregate
+	b.factory.Metadata().AddUserDefinedFunction(o, nil /* name */)
+	if err := b.catalog.CheckExecutionPrivilege(b.ctx, o.Oid); err != nil {
+		panic(err)
+	}
+
+	aggDef := &memo.UserDefinedAggDefinition{
+		Name:      def.Name,
+		Typ:       def.Typ,
+		StateType: agg.StateType,
+		InitCond:  tree.DNull,
+	}
+	if agg.InitCond != nil {
+		initCond, dependsOnContext, err := tree.ParseAndRequireString(
+			agg.StateType, *agg.InitCond, b.evalCtx,
+		)
+		if err != nil {
+			panic(err)
+		}
+		if dependsOnContext {
+			b.DisableMemoReuse = true
+		}
+		aggDef.InitCond = initCond
+	}
+
+	// The transition function takes the current state followed by the
+	// arguments of the aggregate. The final function takes only the state.
+	transitionTypes := append([]*types.T{agg.StateType}, o.Types.Types()...)
+	aggDef.Transition = b.buildAggregateSupportFunction(agg.TransitionFunc, transitionTypes)
+	if agg.FinalFunc != 0 {
+		aggDef.Final = b.buildAggregateSupportFunction(agg.FinalFunc, []*types.T{agg.StateType})
+	}
+
+	return b.factory.ConstructUserDefinedAgg(
+		args, &memo.UserDefinedAggPrivate{Def: aggDef},
+	)
+}
+
+// buildAggregateSupportFunction builds a call to the support function of a
+// user-defined aggregate with the given OID and parameter types. The arguments
+// of the call are NULL placeholders, since the actual arguments are supplied
+// during execution.
+func (b *Builder) buildAggregateSupportFunction(
+	funcOID oid.Oid, paramTypes []*types.T,
+) *memo.UDFCallExpr {
+	exprs := make(tree.Exprs, len(paramTypes))
+	for i, typ := range paramTypes {
+		exprs[i] = tree.NewTypedCastExpr(tree.DNull, typ)
+	}
+	f := &tree.FuncExpr{
+		Func:  tree.ResolvableFunctionReference{FunctionReference: &tree.FunctionOID{OID: funcOID}},
+		Exprs: exprs,
+	}
+	typedExpr, err := tree.TypeCheck(b.ctx, f, b.semaCtx, types.Any)
+	if err != nil {
+		panic(err)
+	}
+	f, ok := typedExpr.(*tree.FuncExpr)
+	if !ok {
+		panic(errors.AssertionFailedf("expected FuncExpr"))
+	}
+	def, err := f.Func.Resolve(b.ctx, b.semaCtx.SearchPath, b.semaCtx.FunctionResolver)
+	if err != nil {
+		panic(err)
+	}
+
+	// The routine must not be inlined, since it is invoked directly by the
+	// aggregate during execution.
+	var disabledRules intsets.Fast
+	disabledRules.Add(int(opt.InlineUDF))
+	var routine opt.ScalarExpr
+	b.factory.DisableOptimizationRulesTemporarily(disabledRules, func() {
+		routine, _, _ = b.buildRoutine(f, def, b.allocScope(), nil /* colRefs */)
+	})
+	udf, ok := routine.(*memo.UDFCallExpr)
+	if !ok {
+		panic(errors.AssertionFailedf("expected UDFCall"))
+	}
+	return udf
+}
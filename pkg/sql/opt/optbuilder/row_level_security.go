@@ -0,0 +1,276 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinsregistry"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// policyExprKind selects which expression of a row-level security policy is
+// used to restrict a set of rows.
+type policyExprKind uint8
+
+const (
+	// policyUsingExpr selects the USING expression, which determines the
+	// existing rows that are visible.
+	policyUsingExpr policyExprKind = iota
+	// policyWithCheckExpr selects the WITH CHECK expression, which determines
+	// the new rows that can be written. Policies without a WITH CHECK
+	// expression use their USING expression instead.
+	policyWithCheckExpr
+)
+
+// rowLevelSecurityState returns how the row-level security policies of the
+// given table apply to the current user. See opt.RowLevelSecurityState.
+func (b *Builder) rowLevelSecurityState(tab cat.Table) opt.RowLevelSecurityState {
+	// The policies are applied when a view is queried, not when it is created.
+	if !tab.IsRowLevelSecurityEnabled() || b.insideViewDef {
+		return opt.RowLevelSecurityState{Exempt: true}
+	}
+	state, err := b.factory.Metadata().RowLevelSecurityState(b.ctx, b.catalog, tab)
+	if err != nil {
+		panic(err)
+	}
+	return state
+}
+
+// buildPolicyExpr returns the condition that rows of the table must satisfy
+// under the policies for the given command that apply to the current user.
+// Permissive policies are combined using OR and restrictive policies are
+// combined using AND. If no permissive policy applies, no row satisfies the
+// condition.
+func (b *Builder) buildPolicyExpr(
+	tab cat.Table, state *opt.RowLevelSecurityState, cmd tree.PolicyCommand, kind policyExprKind,
+) tree.Expr {
+	var permissive, restrictive tree.Expr
+	state.Policies.ForEach(func(i int) {
+		policy := tab.Policy(i)
+		if policy.Command() != tree.PolicyCommandAll && policy.Command() != cmd {
+			return
+		}
+		exprStr := policy.UsingExpr()
+		if kind == policyWithCheckExpr && policy.WithCheckExpr() != "" {
+			exprStr = policy.WithCheckExpr()
+		}
+		if exprStr == "" {
+			return
+		}
+		expr, err := parser.ParseExpr(exprStr)
+		if err != nil {
+			panic(err)
+		}
+		if policy.Type() == tree.PolicyRestrictive {
+			restrictive = makeAndExpr(restrictive, expr)
+		} else if permissive == nil {
+			permissive = expr
+		} else {
+			permissive = &tree.OrExpr{Left: permissive, Right: expr}
+		}
+	})
+	if permissive == nil {
+		return tree.DBoolFalse
+	}
+	return makeAndExpr(permissive, restrictive)
+}
+
+// makeAndExpr returns the conjunction of the given expressions, either of
+// which may be nil.
+func makeAndExpr(left, right tree.Expr) tree.Expr {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	return &tree.AndExpr{Left: left, Right: right}
+}
+
+// addRowLevelSecurityFilter filters the rows of the table scanned by the given
+// scope down to those that satisfy the USING expressions of the policies for
+// all of the given commands.
+//
+// The filter is placed beneath a security barrier, so that it is evaluated
+// before any other expression of the query that is not leakproof. Otherwise,
+// a user-supplied predicate could raise an error, or have side effects, that
+// depend on the values of rows that the policies hide.
+func (b *Builder) addRowLevelSecurityFilter(
+	tab cat.Table, state *opt.RowLevelSecurityState, s *scope, cmds ...tree.PolicyCommand,
+) {
+	var filter tree.Expr
+	for _, cmd := range cmds {
+		filter = makeAndExpr(filter, b.buildPolicyExpr(tab, state, cmd, policyUsingExpr))
+	}
+	texpr := s.resolveAndRequireType(filter, types.Bool)
+	scalar := b.buildScalar(texpr, s, nil /* outScope */, nil /* outCol */, nil /* colRefs */)
+	s.expr = b.factory.ConstructBarrier(
+		b.factory.ConstructSelect(
+			s.expr,
+			memo.FiltersExpr{b.factory.ConstructFiltersItem(scalar)},
+		),
+		&memo.BarrierPrivate{SecurityBarrier: true},
+	)
+}
+
+// maybeAddRowLevelSecurityFilterForSelect filters the rows of a table scanned
+// by a query down to those that are visible to the current user.
+func (b *Builder) maybeAddRowLevelSecurityFilterForSelect(tab cat.Table, s *scope) {
+	if state := b.rowLevelSecurityState(tab); !state.Exempt {
+		b.addRowLevelSecurityFilter(tab, &state, s, tree.PolicyCommandSelect)
+	}
+}
+
+// initRowLevelSecurity determines whether the row-level security policies of
+// the target table apply to the mutation. It must only be called for mutations
+// issued directly by the user; the mutations performed by foreign key cascades
+// are not subject to policies.
+func (mb *mutationBuilder) initRowLevelSecurity() {
+	if state := mb.b.rowLevelSecurityState(mb.tab); !state.Exempt {
+		mb.rowLevelSecurity = &state
+	}
+}
+
+// addRowLevelSecurityFetchFilter filters the existing rows fetched by an UPDATE
+// or DELETE down to those that the current user can see and modify.
+func (mb *mutationBuilder) addRowLevelSecurityFetchFilter(cmd tree.PolicyCommand) {
+	if mb.rowLevelSecurity == nil {
+		return
+	}
+	mb.b.addRowLevelSecurityFilter(
+		mb.tab, mb.rowLevelSecurity, mb.fetchScope, cmd, tree.PolicyCommandSelect,
+	)
+}
+
+// buildRowLevelSecurityCheck builds a boolean expression that checks rows of
+// the mutation input against the given policy expression. The expression
+// resolves column names using the given scope.
+func (mb *mutationBuilder) buildRowLevelSecurityCheck(
+	s *scope, cmd tree.PolicyCommand, kind policyExprKind,
+) opt.ScalarExpr {
+	expr := mb.b.buildPolicyExpr(mb.tab, mb.rowLevelSecurity, cmd, kind)
+	texpr := s.resolveAndRequireType(expr, types.Bool)
+	return mb.b.buildScalar(texpr, s, nil /* outScope */, nil /* outCol */, nil /* colRefs */)
+}
+
+// addRowLevelSecurityChecks rejects the rows written by an INSERT, UPDATE or
+// UPSERT that violate the policies of the target table. New rows must satisfy
+// the WITH CHECK expressions of the policies for the command, and, if they are
+// returned, be visible under the SELECT policies. An UPSERT that updates an
+// existing row additionally requires that row to be visible under the UPDATE
+// and SELECT policies; unlike for UPDATE, such rows cause an error rather than
+// being skipped. A MERGE applies the checks of the action it chose for each
+// row, and additionally requires the rows it deletes to be visible under the
+// DELETE and SELECT policies.
+//
+// The checks are evaluated by a filter on the mutation input that raises an
+// error for each violating row:
+//
+//	CASE WHEN <check> THEN true
+//	ELSE crdb_internal.row_level_security_violation('<table>') END
+//
+// addRowLevelSecurityChecks must be called after disambiguateColumns, so that
+// column names refer to the new values of the row.
+func (mb *mutationBuilder) addRowLevelSecurityChecks(
+	cmd tree.PolicyCommand, returning *tree.ReturningExprs,
+) {
+	if mb.rowLevelSecurity == nil {
+		return
+	}
+	f := mb.b.factory
+
+	newRowCheck := func(cmd tree.PolicyCommand) opt.ScalarExpr {
+		check := mb.buildRowLevelSecurityCheck(mb.outScope, cmd, policyWithCheckExpr)
+		if returning != nil {
+			check = f.ConstructAnd(check, mb.buildRowLevelSecurityCheck(
+				mb.outScope, tree.PolicyCommandSelect, policyUsingExpr,
+			))
+		}
+		return check
+	}
+
+	existingRowCheck := func(cmd tree.PolicyCommand) opt.ScalarExpr {
+		return f.ConstructAnd(
+			mb.buildRowLevelSecurityCheck(mb.fetchScope, cmd, policyUsingExpr),
+			mb.buildRowLevelSecurityCheck(mb.fetchScope, tree.PolicyCommandSelect, policyUsingExpr),
+		)
+	}
+
+	var check opt.ScalarExpr
+	if mb.mergeActionColID != 0 {
+		// The action column decides whether the row is inserted, or whether it
+		// updates or deletes the existing row that was fetched.
+		action := func(a opt.MergeAction) opt.ScalarExpr {
+			return f.ConstructConstVal(tree.NewDInt(tree.DInt(a)), types.Int)
+		}
+		check = f.ConstructCase(
+			f.ConstructVariable(mb.mergeActionColID),
+			memo.ScalarListExpr{
+				f.ConstructWhen(action(opt.MergeActionInsert), newRowCheck(tree.PolicyCommandInsert)),
+				f.ConstructWhen(action(opt.MergeActionUpdate), f.ConstructAnd(
+					existingRowCheck(tree.PolicyCommandUpdate), newRowCheck(tree.PolicyCommandUpdate),
+				)),
+				f.ConstructWhen(action(opt.MergeActionDelete), existingRowCheck(tree.PolicyCommandDelete)),
+			},
+			memo.FalseSingleton,
+		)
+	} else if cmd == tree.PolicyCommandInsert && mb.canaryColID != 0 {
+		// The row is inserted if the canary column is NULL, and otherwise
+		// updates the existing row that was fetched.
+		updateCheck := f.ConstructAnd(
+			existingRowCheck(tree.PolicyCommandUpdate),
+			newRowCheck(tree.PolicyCommandUpdate),
+		)
+		check = f.ConstructCase(
+			memo.TrueSingleton,
+			memo.ScalarListExpr{
+				f.ConstructWhen(
+					f.ConstructIs(f.ConstructVariable(mb.canaryColID), memo.NullSingleton),
+					newRowCheck(tree.PolicyCommandInsert),
+				),
+			},
+			updateCheck,
+		)
+	} else {
+		check = newRowCheck(cmd)
+	}
+
+	const violationFnName = "crdb_internal.row_level_security_violation"
+	props, overloads := builtinsregistry.GetBuiltinProperties(violationFnName)
+	if len(overloads) != 1 {
+		panic(errors.AssertionFailedf("expected one overload for %s", violationFnName))
+	}
+	violation := f.ConstructFunction(
+		memo.ScalarListExpr{
+			f.ConstructConstVal(tree.NewDString(string(mb.tab.Name())), types.String),
+		},
+		&memo.FunctionPrivate{
+			Name:       violationFnName,
+			Typ:        types.Bool,
+			Properties: props,
+			Overload:   &overloads[0],
+		},
+	)
+	filter := f.ConstructCase(
+		memo.TrueSingleton,
+		memo.ScalarListExpr{f.ConstructWhen(check, memo.TrueSingleton)},
+		violation,
+	)
+	mb.outScope.expr = f.ConstructSelect(
+		mb.outScope.expr,
+		memo.FiltersExpr{f.ConstructFiltersItem(filter)},
+	)
+}
@@ -550,6 +550,9 @@ func (b *Builder) buildFunction(
 	if overload.HasSQLBody() {
 		return b.buildUDF(f, def, inScope, outScope, outCol, colRefs)
 	}
+	if def.Name == "grouping" {
+		return b.buildGroupingFunc(f, inScope, outScope, outCol)
+	}
 	b.factory.Metadata().AddBuiltin(f.Func.ReferenceByName)
 
 	if overload.Class == tree.AggregateClass {
@@ -782,7 +785,16 @@ func (b *Builder) constructComparison(
 		}
 		return b.factory.ConstructOverlaps(left, right)
 	case treecmp.TSMatches:
+		if cmp.Op.LeftType.Family() == types.JsonFamily {
+			// The @@ operator means "matches the jsonpath predicate" when used
+			// with jsonb and jsonpath operands.
+			return b.factory.ConstructJsonPathMatch(left, right)
+		}
 		return b.factory.ConstructTSMatches(left, right)
+	case treecmp.JSONPathExists:
+		return b.factory.ConstructJsonPathExists(left, right)
+	case treecmp.Adjacent:
+		return b.factory.ConstructAdjacent(left, right)
 	}
 	panic(errors.AssertionFailedf("unhandled comparison operator: %s", redact.Safe(cmp.Operator)))
 }
@@ -130,6 +130,7 @@ const (
 	exprKindHaving
 	exprKindLateralJoin
 	exprKindLimit
+	exprKindMergeWhen
 	exprKindOffset
 	exprKindOn
 	exprKindOrderBy
@@ -138,6 +139,8 @@ const (
 	exprKindReturning
 	exprKindSelect
 	exprKindStoreID
+	exprKindTableSample
+	exprKindTriggerWhen
 	exprKindValues
 	exprKindWhere
 	exprKindWindowFrameStart
@@ -153,6 +156,7 @@ var exprKindName = [...]string{
 	exprKindHaving:            "HAVING",
 	exprKindLateralJoin:       "LATERAL JOIN",
 	exprKindLimit:             "LIMIT",
+	exprKindMergeWhen:         "MERGE WHEN",
 	exprKindOffset:            "OFFSET",
 	exprKindOn:                "ON",
 	exprKindOrderBy:           "ORDER BY",
@@ -161,6 +165,8 @@ var exprKindName = [...]string{
 	exprKindReturning:         "RETURNING",
 	exprKindSelect:            "SELECT",
 	exprKindStoreID:           "RELOCATE STORE ID",
+	exprKindTableSample:       "TABLESAMPLE",
+	exprKindTriggerWhen:       "trigger WHEN",
 	exprKindValues:            "VALUES",
 	exprKindWhere:             "WHERE",
 	exprKindWindowFrameStart:  "WINDOW FRAME START",
@@ -1031,6 +1037,11 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 	case *tree.ColumnItem:
 		colI, resolveErr := colinfo.ResolveColumnItem(s.builder.ctx, s, t)
 		if resolveErr != nil {
+			// It may be a reference to a field of a composite-typed column, e.g.
+			// NEW.x in a trigger function.
+			if access := s.resolveCompositeFieldAccess(t); access != nil {
+				return false, access
+			}
 			// It may be a reference to a table, e.g. SELECT tbl FROM tbl.
 			// Attempt to resolve as a TupleStar.
 			if sqlerrors.IsUndefinedColumnError(resolveErr) {
@@ -1287,6 +1298,7 @@ func (s *scope) replaceAggregate(f *tree.FuncExpr, def *tree.ResolvedFunctionDef
 
 	private := memo.FunctionPrivate{
 		Name:       def.Name,
+		Typ:        f.ResolvedType(),
 		Properties: &f.ResolvedOverload().FunctionProperties,
 		Overload:   f.ResolvedOverload(),
 	}
@@ -1394,6 +1406,7 @@ func (s *scope) replaceWindowFn(f *tree.FuncExpr, def *tree.ResolvedFunctionDefi
 		FuncExpr: f,
 		def: memo.FunctionPrivate{
 			Name:       def.Name,
+			Typ:        f.ResolvedType(),
 			Properties: &f.ResolvedOverload().FunctionProperties,
 			Overload:   f.ResolvedOverload(),
 		},
@@ -1699,6 +1712,25 @@ func (s *scope) String() string {
 	return buf.String()
 }
 
+// resolveCompositeFieldAccess attempts to resolve a column item of the form
+// a.b as an access to field b of a composite-typed column a. It returns nil if
+// there is no such column in scope.
+func (s *scope) resolveCompositeFieldAccess(t *tree.ColumnItem) tree.Expr {
+	if t.TableName == nil || t.TableName.NumParts != 1 {
+		return nil
+	}
+	colItem := tree.ColumnItem{ColumnName: tree.Name(t.TableName.Parts[0])}
+	colI, err := colinfo.ResolveColumnItem(s.builder.ctx, s, &colItem)
+	if err != nil {
+		return nil
+	}
+	col := colI.(*scopeColumn)
+	if col.typ.Family() != types.TupleFamily {
+		return nil
+	}
+	return &tree.ColumnAccessExpr{Expr: col, ColName: t.ColumnName}
+}
+
 func columnNameAsTupleStar(colName string) *tree.TupleStar {
 	return &tree.TupleStar{
 		Expr: &tree.UnresolvedName{
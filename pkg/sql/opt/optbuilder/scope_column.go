@@ -297,6 +297,17 @@ func funcParamColName(name tree.Name, ord int) scopeColumnName {
 	return scopeColName(name).WithMetadataName(alias)
 }
 
+// outParamName returns the name of the output parameter with the given name
+// and ordinal among all the parameters of a routine. Unnamed output parameters
+// cannot be referenced, but still need a name in order to be tracked as
+// variables, so they are named "$<ord>".
+func outParamName(name tree.Name, ord int) string {
+	if name == "" {
+		return fmt.Sprintf("$%d", ord+1)
+	}
+	return string(name)
+}
+
 // WithMetadataName returns a copy of s with the metadata name set to the given
 // name. This only affects the name of the column in the metadata. It does not
 // change the name by which the column can be referenced.
@@ -47,10 +47,17 @@ import (
 // including two for the left and right table scans, at least one for the join
 // condition, and one for the join itself.
 //
+// If sample is non-nil, texpr must be a table, and the scan of the table only
+// returns the rows selected by the TABLESAMPLE clause.
+//
 // See Builder.buildStmt for a description of the remaining input and
 // return values.
 func (b *Builder) buildDataSource(
-	texpr tree.TableExpr, indexFlags *tree.IndexFlags, lockCtx lockingContext, inScope *scope,
+	texpr tree.TableExpr,
+	indexFlags *tree.IndexFlags,
+	sample *tree.TableSample,
+	lockCtx lockingContext,
+	inScope *scope,
 ) (outScope *scope) {
 	defer func(prevAtRoot bool, prevInsideDataSource bool) {
 		inScope.atRoot = prevAtRoot
@@ -91,7 +98,15 @@ func (b *Builder) buildDataSource(
 			lockCtx.withoutTargets()
 		}
 
-		outScope = b.buildDataSource(source.Expr, indexFlags, lockCtx, inScope)
+		if source.TableSample != nil {
+			switch source.Expr.(type) {
+			case *tree.TableName, *tree.TableRef:
+			default:
+				checkTableSampleSource(source.TableSample)
+			}
+		}
+
+		outScope = b.buildDataSource(source.Expr, indexFlags, source.TableSample, lockCtx, inScope)
 
 		if source.Ordinality {
 			outScope = b.buildWithOrdinality(outScope)
@@ -110,6 +125,7 @@ func (b *Builder) buildDataSource(
 
 		// CTEs take precedence over other data sources.
 		if cte := inScope.resolveCTE(tn); cte != nil {
+			checkTableSampleSource(sample)
 			lockCtx.locking.ignoreLockingForCTE()
 			outScope = inScope.push()
 			inCols := make(opt.ColList, len(cte.cols), len(cte.cols)+len(inScope.ordering))
@@ -163,21 +179,25 @@ func (b *Builder) buildDataSource(
 			if b.shouldBuildLockOp() {
 				locking = nil
 			}
-			return b.buildScan(
+			outScope = b.buildSampledScan(
 				tabMeta,
 				tableOrdinals(t, columnKinds{
 					includeMutations: false,
 					includeSystem:    true,
 					includeInverted:  false,
 				}),
-				indexFlags, locking, inScope,
+				indexFlags, b.buildTableSample(sample, inScope), locking, inScope,
 				false, /* disableNotVisibleIndex */
 			)
+			b.maybeAddRowLevelSecurityFilterForSelect(t, outScope)
+			return outScope
 
 		case cat.Sequence:
+			checkTableSampleSource(sample)
 			return b.buildSequenceSelect(t, &resName, inScope)
 
 		case cat.View:
+			checkTableSampleSource(sample)
 			return b.buildView(t, &resName, lockCtx, inScope)
 
 		default:
@@ -185,7 +205,7 @@ func (b *Builder) buildDataSource(
 		}
 
 	case *tree.ParenTableExpr:
-		return b.buildDataSource(source.Expr, indexFlags, lockCtx, inScope)
+		return b.buildDataSource(source.Expr, indexFlags, sample, lockCtx, inScope)
 
 	case *tree.RowsFromExpr:
 		return b.buildZip(source.Items, inScope)
@@ -281,8 +301,11 @@ func (b *Builder) buildDataSource(
 
 		switch t := ds.(type) {
 		case cat.Table:
-			outScope = b.buildScanFromTableRef(t, source, indexFlags, lockCtx.locking, inScope)
+			outScope = b.buildScanFromTableRef(
+				t, source, indexFlags, b.buildTableSample(sample, inScope), lockCtx.locking, inScope,
+			)
 		case cat.View:
+			checkTableSampleSource(sample)
 			if source.Columns != nil {
 				panic(pgerror.Newf(pgcode.FeatureNotSupported,
 					"cannot specify an explicit column list when accessing a view by reference"))
@@ -291,6 +314,7 @@ func (b *Builder) buildDataSource(
 
 			outScope = b.buildView(t, &tn, lockCtx, inScope)
 		case cat.Sequence:
+			checkTableSampleSource(sample)
 			tn := tree.MakeUnqualifiedTableName(t.Name())
 			// Any explicitly listed columns are ignored.
 			outScope = b.buildSequenceSelect(t, &tn, inScope)
@@ -460,6 +484,7 @@ func (b *Builder) buildScanFromTableRef(
 	tab cat.Table,
 	ref *tree.TableRef,
 	indexFlags *tree.IndexFlags,
+	sample opt.TableSample,
 	locking lockingSpec,
 	inScope *scope,
 ) (outScope *scope) {
@@ -492,9 +517,11 @@ func (b *Builder) buildScanFromTableRef(
 	if b.shouldBuildLockOp() {
 		locking = nil
 	}
-	return b.buildScan(
-		tabMeta, ordinals, indexFlags, locking, inScope, false, /* disableNotVisibleIndex */
+	outScope = b.buildSampledScan(
+		tabMeta, ordinals, indexFlags, sample, locking, inScope, false, /* disableNotVisibleIndex */
 	)
+	b.maybeAddRowLevelSecurityFilterForSelect(tab, outScope)
+	return outScope
 }
 
 // addTable adds a table to the metadata and returns the TableMeta. The table
@@ -557,6 +584,23 @@ func (b *Builder) buildScan(
 	locking lockingSpec,
 	inScope *scope,
 	disableNotVisibleIndex bool,
+) (outScope *scope) {
+	return b.buildSampledScan(
+		tabMeta, ordinals, indexFlags, opt.TableSample{}, locking, inScope, disableNotVisibleIndex,
+	)
+}
+
+// buildSampledScan is like buildScan, but the scan only returns the subset of
+// the rows of the table that are selected by the given sample. If the sample
+// is the zero value, it is identical to buildScan.
+func (b *Builder) buildSampledScan(
+	tabMeta *opt.TableMeta,
+	ordinals []int,
+	indexFlags *tree.IndexFlags,
+	sample opt.TableSample,
+	locking lockingSpec,
+	inScope *scope,
+	disableNotVisibleIndex bool,
 ) (outScope *scope) {
 	if ordinals == nil {
 		panic(errors.AssertionFailedf("no ordinals"))
@@ -621,6 +665,10 @@ func (b *Builder) buildScan(
 			panic(pgerror.Newf(pgcode.Syntax,
 				"%s not allowed with virtual tables", locking.get().Strength))
 		}
+		if sample.IsSampled() {
+			panic(pgerror.New(pgcode.FeatureNotSupported,
+				"TABLESAMPLE not allowed with virtual tables"))
+		}
 		private := memo.ScanPrivate{Table: tabID, Cols: scanColIDs}
 		outScope.expr = b.factory.ConstructScan(&private)
 
@@ -757,6 +805,13 @@ func (b *Builder) buildScan(
 			))
 		}
 	}
+	if sample.IsSampled() {
+		if private.Flags.ForceIndex || private.Flags.ForceZigzag {
+			panic(pgerror.New(pgcode.FeatureNotSupported,
+				"index hints cannot be used with TABLESAMPLE"))
+		}
+		private.Sample = sample
+	}
 	if b.evalCtx.AsOfSystemTime != nil && b.evalCtx.AsOfSystemTime.BoundedStaleness {
 		private.Flags.NoIndexJoin = true
 		private.Flags.NoZigzagJoin = true
@@ -1393,7 +1448,7 @@ func (b *Builder) buildFromTables(
 func (b *Builder) buildFromTablesRightDeep(
 	tables tree.TableExprs, lockCtx lockingContext, inScope *scope,
 ) (outScope *scope) {
-	outScope = b.buildDataSource(tables[0], nil /* indexFlags */, lockCtx, inScope)
+	outScope = b.buildDataSource(tables[0], nil /* indexFlags */, nil /* sample */, lockCtx, inScope)
 
 	// Recursively build table join.
 	tables = tables[1:]
@@ -1443,7 +1498,7 @@ func (b *Builder) exprIsLateral(t tree.TableExpr) bool {
 func (b *Builder) buildFromWithLateral(
 	tables tree.TableExprs, lockCtx lockingContext, inScope *scope,
 ) (outScope *scope) {
-	outScope = b.buildDataSource(tables[0], nil /* indexFlags */, lockCtx, inScope)
+	outScope = b.buildDataSource(tables[0], nil /* indexFlags */, nil /* sample */, lockCtx, inScope)
 	for i := 1; i < len(tables); i++ {
 		scope := inScope
 		// Lateral expressions need to be able to refer to the expressions that
@@ -1452,7 +1507,7 @@ func (b *Builder) buildFromWithLateral(
 			scope = outScope
 			scope.context = exprKindLateralJoin
 		}
-		tableScope := b.buildDataSource(tables[i], nil /* indexFlags */, lockCtx, scope)
+		tableScope := b.buildDataSource(tables[i], nil /* indexFlags */, nil /* sample */, lockCtx, scope)
 
 		// Check that the same table name is not used multiple times.
 		b.validateJoinTableNames(outScope, tableScope)
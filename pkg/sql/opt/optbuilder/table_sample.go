@@ -0,0 +1,86 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// buildTableSample builds the TABLESAMPLE clause of a table reference. The
+// sample percentage and the REPEATABLE seed must be constant expressions, since
+// they are stored in the ScanPrivate rather than evaluated at execution time.
+// If sample is nil, the zero opt.TableSample is returned.
+func (b *Builder) buildTableSample(sample *tree.TableSample, inScope *scope) opt.TableSample {
+	if sample == nil {
+		return opt.TableSample{}
+	}
+	method, ok := opt.TableSampleMethodFromString(string(sample.Method))
+	if !ok {
+		panic(pgerror.Newf(pgcode.UndefinedObject,
+			"tablesample method %s does not exist", tree.ErrString(&sample.Method)))
+	}
+	if len(sample.Args) != 1 {
+		panic(pgerror.Newf(pgcode.InvalidTablesampleArgument,
+			"tablesample method %s requires 1 argument, not %d", method, len(sample.Args)))
+	}
+
+	percent := b.buildTableSampleArg(sample.Args[0], inScope)
+	if percent == tree.DNull {
+		panic(pgerror.New(pgcode.InvalidTablesampleArgument,
+			"TABLESAMPLE parameter cannot be null"))
+	}
+	p := float64(tree.MustBeDFloat(percent))
+	if math.IsNaN(p) || p < 0 || p > 100 {
+		panic(pgerror.New(pgcode.InvalidTablesampleArgument,
+			"sample percentage must be between 0 and 100"))
+	}
+	res := opt.TableSample{Method: method, Fraction: p / 100}
+
+	if sample.Repeatable != nil {
+		seed := b.buildTableSampleArg(sample.Repeatable, inScope)
+		if seed == tree.DNull {
+			panic(pgerror.New(pgcode.InvalidTablesampleRepeat,
+				"TABLESAMPLE REPEATABLE parameter cannot be null"))
+		}
+		res.Repeatable = true
+		res.Seed = float64(tree.MustBeDFloat(seed))
+	}
+	return res
+}
+
+// buildTableSampleArg builds an argument of a TABLESAMPLE clause and returns
+// its constant value.
+func (b *Builder) buildTableSampleArg(expr tree.Expr, inScope *scope) tree.Datum {
+	arg := b.resolveAndBuildScalar(
+		expr, types.Float, exprKindTableSample, tree.RejectSpecial, inScope,
+	)
+	if !memo.CanExtractConstDatum(arg) {
+		panic(pgerror.New(pgcode.FeatureNotSupported,
+			"TABLESAMPLE arguments must be constant expressions"))
+	}
+	return memo.ExtractConstDatum(arg)
+}
+
+// checkTableSampleSource panics if a TABLESAMPLE clause is applied to a data
+// source that is not a table, such as a view, sequence or CTE.
+func checkTableSampleSource(sample *tree.TableSample) {
+	if sample != nil {
+		panic(pgerror.New(pgcode.WrongObjectType,
+			"TABLESAMPLE clause can only be applied to tables and materialized views"))
+	}
+}
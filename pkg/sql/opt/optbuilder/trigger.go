@@ -0,0 +1,788 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	plpgsql "github.com/cockroachdb/cockroach/pkg/sql/plpgsql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	ast "github.com/cockroachdb/cockroach/pkg/sql/sem/plpgsqltree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/plpgsqltree/utils"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treebin"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/intsets"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// Triggers are planned as follows:
+//
+//   - BEFORE ROW triggers are built into the input of the mutation. The
+//     trigger function is called with the NEW and OLD rows of each row, and the
+//     row it returns replaces the values that are inserted or updated. Rows for
+//     which the trigger function returns NULL are filtered out, which skips the
+//     operation for that row.
+//
+//   - BEFORE STATEMENT triggers of the statement are built as With bindings
+//     that are always materialized, so that the trigger function is called
+//     once before the mutation is executed.
+//
+//   - AFTER ROW and AFTER STATEMENT triggers are queued as cascades that are
+//     executed after the mutation and its foreign key cascades. Row-level
+//     triggers read the buffered mutation input to obtain the NEW and OLD rows.
+//
+// Triggers of the same kind fire in alphabetical order of their names, as in
+// Postgres. Triggers of the child table of a cascading foreign key fire when
+// the cascade is executed.
+
+// initTriggers records the events of the mutation that fire triggers on the
+// target table. An UPSERT, for example, may fire both INSERT and UPDATE
+// triggers.
+func (mb *mutationBuilder) initTriggers(events ...tree.TriggerEventType) {
+	mb.triggerEvents = events
+}
+
+// findTriggers returns the enabled triggers of the table with the given action
+// time and level that fire for the given event, in the order in which they
+// fire. updatedOrds is the set of columns that are the target of an UPDATE; it
+// is used to match UPDATE OF triggers.
+func findTriggers(
+	tab cat.Table,
+	actionTime tree.TriggerActionTime,
+	forEachRow bool,
+	event tree.TriggerEventType,
+	updatedOrds intsets.Fast,
+) []cat.Trigger {
+	var triggers []cat.Trigger
+	for i, n := 0, tab.TriggerCount(); i < n; i++ {
+		trigger := tab.Trigger(i)
+		if !trigger.Enabled() || trigger.ActionTime() != actionTime || trigger.ForEachRow() != forEachRow {
+			continue
+		}
+		for j, m := 0, trigger.EventCount(); j < m; j++ {
+			ev := trigger.Event(j)
+			if ev.EventType != event {
+				continue
+			}
+			if matchesUpdatedCols(ev, updatedOrds) {
+				triggers = append(triggers, trigger)
+				break
+			}
+		}
+	}
+	sort.Slice(triggers, func(i, j int) bool {
+		return triggers[i].Name() < triggers[j].Name()
+	})
+	return triggers
+}
+
+// matchesUpdatedCols returns true if the event applies to an UPDATE of the given
+// columns. An UPDATE OF trigger only fires if one of its columns is updated.
+func matchesUpdatedCols(ev cat.TriggerEvent, updatedOrds intsets.Fast) bool {
+	if len(ev.ColumnOrdinals) == 0 {
+		return true
+	}
+	for _, ord := range ev.ColumnOrdinals {
+		if updatedOrds.Contains(ord) {
+			return true
+		}
+	}
+	return false
+}
+
+// triggerRowOrds returns the ordinals of the table columns that make up the
+// NEW and OLD rows passed to a trigger function.
+func triggerRowOrds(tab cat.Table) []int {
+	ords := make([]int, 0, tab.ColumnCount())
+	for i, n := 0, tab.ColumnCount(); i < n; i++ {
+		col := tab.Column(i)
+		if col.Kind() == cat.Ordinary && col.Visibility() == cat.Visible {
+			ords = append(ords, i)
+		}
+	}
+	return ords
+}
+
+// triggerRowType returns the type of the NEW and OLD rows passed to a trigger
+// function, which is a tuple labeled with the names of the given columns.
+func triggerRowType(tab cat.Table, ords []int) *types.T {
+	contents := make([]*types.T, len(ords))
+	labels := make([]string, len(ords))
+	for i, ord := range ords {
+		col := tab.Column(ord)
+		contents[i] = col.DatumType()
+		labels[i] = string(col.ColName())
+	}
+	return types.MakeLabeledTuple(contents, labels)
+}
+
+// shiftTriggerArgvSubscripts returns a copy of the body of a trigger function
+// in which the subscripts of TG_ARGV are incremented by one. TG_ARGV is passed
+// as an array, which is 1-indexed like all other arrays, whereas in Postgres
+// it is 0-indexed.
+func shiftTriggerArgvSubscripts(body *ast.Block) *ast.Block {
+	shift := func(e tree.Expr) tree.Expr {
+		if e == nil {
+			return nil
+		}
+		return &tree.BinaryExpr{
+			Operator: treebin.MakeBinaryOperator(treebin.Plus),
+			Left:     e,
+			Right:    tree.NewDInt(1),
+		}
+	}
+	v := utils.SQLStmtVisitor{
+		Fn: func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+			ind, ok := expr.(*tree.IndirectionExpr)
+			if !ok {
+				return true, expr, nil
+			}
+			name, ok := ind.Expr.(*tree.UnresolvedName)
+			if !ok || name.NumParts != 1 || name.Parts[0] != "tg_argv" {
+				return true, expr, nil
+			}
+			subscripts := append(tree.ArraySubscripts(nil), ind.Indirection...)
+			first := *subscripts[0]
+			first.Begin, first.End = shift(first.Begin), shift(first.End)
+			subscripts[0] = &first
+			return true, &tree.IndirectionExpr{Expr: ind.Expr, Indirection: subscripts}, nil
+		},
+	}
+	newBody := ast.Walk(&v, body)
+	if v.Err != nil {
+		panic(v.Err)
+	}
+	return newBody.(*ast.Block)
+}
+
+// buildTriggerRow builds a tuple with the values of the given columns. It is
+// NULL if cols is nil, which is the case for the NEW row of a DELETE and the
+// OLD row of an INSERT.
+func (b *Builder) buildTriggerRow(cols opt.ColList, rowType *types.T) opt.ScalarExpr {
+	if cols == nil {
+		return b.factory.ConstructNull(rowType)
+	}
+	elems := make(memo.ScalarListExpr, len(cols))
+	for i, col := range cols {
+		if col == 0 {
+			elems[i] = b.factory.ConstructNull(rowType.TupleContents()[i])
+		} else {
+			elems[i] = b.factory.ConstructVariable(col)
+		}
+	}
+	return b.factory.ConstructTuple(elems, rowType)
+}
+
+// triggerRowCols returns the input columns that provide the NEW or OLD row of
+// a row-level trigger that fires for the given event, or nil if the row does
+// not apply to the event.
+func (mb *mutationBuilder) triggerRowCols(
+	event tree.TriggerEventType, ords []int, isNew bool,
+) opt.ColList {
+	if (isNew && event == tree.TriggerEventDelete) || (!isNew && event == tree.TriggerEventInsert) {
+		return nil
+	}
+	cols := make(opt.ColList, len(ords))
+	for i, ord := range ords {
+		switch {
+		case !isNew:
+			cols[i] = mb.fetchColIDs[ord]
+		case event == tree.TriggerEventInsert:
+			cols[i] = mb.insertColIDs[ord]
+		case mb.updateColIDs[ord] != 0:
+			cols[i] = mb.updateColIDs[ord]
+		default:
+			cols[i] = mb.fetchColIDs[ord]
+		}
+	}
+	return cols
+}
+
+// triggerGuardKind describes how the rows of a mutation that are subject to a
+// trigger event are identified.
+type triggerGuardKind uint8
+
+const (
+	// triggerGuardNone indicates that every row of the mutation is subject to
+	// the event.
+	triggerGuardNone triggerGuardKind = iota
+
+	// triggerGuardCanary indicates that the mutation is an UPSERT, for which the
+	// canary column is NULL for inserted rows and not NULL for updated rows.
+	triggerGuardCanary
+
+	// triggerGuardMergeAction indicates that the mutation is a MERGE, for which
+	// the merge action column holds the opt.MergeAction of each row.
+	triggerGuardMergeAction
+)
+
+// triggerGuard identifies the rows of a mutation that are subject to a trigger
+// event, for mutations that combine multiple events.
+type triggerGuard struct {
+	kind  triggerGuardKind
+	event tree.TriggerEventType
+}
+
+// triggerGuardFor returns the guard for the given event, along with the column
+// that the guard tests. The column is 0 if the guard kind is triggerGuardNone.
+func (mb *mutationBuilder) triggerGuardFor(
+	event tree.TriggerEventType,
+) (triggerGuard, opt.ColumnID) {
+	switch {
+	case mb.mergeActionColID != 0:
+		return triggerGuard{kind: triggerGuardMergeAction, event: event}, mb.mergeActionColID
+	case mb.canaryColID != 0:
+		return triggerGuard{kind: triggerGuardCanary, event: event}, mb.canaryColID
+	}
+	return triggerGuard{event: event}, 0
+}
+
+// build returns a condition that is true for the rows that are subject to the
+// event of the guard, or nil if all rows are subject to it.
+func (g triggerGuard) build(f *norm.Factory, col opt.ColumnID) opt.ScalarExpr {
+	switch g.kind {
+	case triggerGuardCanary:
+		if g.event == tree.TriggerEventInsert {
+			return f.ConstructIs(f.ConstructVariable(col), memo.NullSingleton)
+		}
+		return f.ConstructIsNot(f.ConstructVariable(col), memo.NullSingleton)
+	case triggerGuardMergeAction:
+		var action opt.MergeAction
+		switch g.event {
+		case tree.TriggerEventInsert:
+			action = opt.MergeActionInsert
+		case tree.TriggerEventUpdate:
+			action = opt.MergeActionUpdate
+		default:
+			action = opt.MergeActionDelete
+		}
+		return f.ConstructEq(
+			f.ConstructVariable(col),
+			f.ConstructConstVal(tree.NewDInt(tree.DInt(action)), types.Int),
+		)
+	}
+	return nil
+}
+
+// projectTriggerRows projects the NEW and OLD rows of a row-level trigger as
+// tuple-typed columns, which can be passed to the trigger function and
+// referenced by its WHEN condition.
+func (b *Builder) projectTriggerRows(
+	inScope *scope, rowType *types.T, newRow, oldRow opt.ScalarExpr,
+) (outScope *scope, newCol, oldCol opt.ColumnID) {
+	outScope = inScope.replace()
+	outScope.appendColumnsFromScope(inScope)
+	newCol = b.synthesizeColumn(
+		outScope, scopeColName("").WithMetadataName("new"), rowType, nil /* expr */, newRow,
+	).id
+	oldCol = b.synthesizeColumn(
+		outScope, scopeColName("").WithMetadataName("old"), rowType, nil /* expr */, oldRow,
+	).id
+	b.constructProjectForScope(inScope, outScope)
+	return outScope, newCol, oldCol
+}
+
+// buildTriggerCondition returns the condition under which a trigger fires for
+// a row: the row must be subject to the event of the trigger, and it must
+// satisfy the WHEN condition of the trigger, if any. It returns nil if the
+// trigger fires for every row.
+func (b *Builder) buildTriggerCondition(
+	trigger cat.Trigger, rowType *types.T, newCol, oldCol opt.ColumnID, guard opt.ScalarExpr,
+) opt.ScalarExpr {
+	whenScope := b.allocScope()
+	whenScope.cols = append(whenScope.cols,
+		scopeColumn{name: scopeColName("new"), typ: rowType, id: newCol},
+		scopeColumn{name: scopeColName("old"), typ: rowType, id: oldCol},
+	)
+	when := b.buildTriggerWhen(trigger, whenScope)
+	switch {
+	case guard == nil:
+		return when
+	case when == nil:
+		return guard
+	}
+	return b.factory.ConstructAnd(guard, when)
+}
+
+// buildTriggerWhen builds the WHEN condition of the trigger, or returns nil if
+// it has none. The condition of a row-level trigger can reference the NEW and
+// OLD rows, which are the columns of the given scope.
+func (b *Builder) buildTriggerWhen(trigger cat.Trigger, inScope *scope) opt.ScalarExpr {
+	if trigger.WhenExpr() == "" {
+		return nil
+	}
+	expr, err := parser.ParseExpr(trigger.WhenExpr())
+	if err != nil {
+		panic(err)
+	}
+	return b.resolveAndBuildScalar(
+		expr, types.Bool, exprKindTriggerWhen, tree.RejectSpecial|tree.RejectSubqueries, inScope,
+	)
+}
+
+// buildTriggerFunctionCall builds a call to the trigger function of the given
+// trigger. The NEW and OLD rows are passed to the function along with the
+// special TG_ variables, as parameters of the function. The function returns
+// a row of the given row type.
+func (b *Builder) buildTriggerFunctionCall(
+	tab cat.Table,
+	trigger cat.Trigger,
+	event tree.TriggerEventType,
+	rowType *types.T,
+	newRow, oldRow opt.ScalarExpr,
+) opt.ScalarExpr {
+	funcOID := catid.FuncIDToOID(catid.DescID(trigger.FuncID()))
+	fnName, o, err := b.semaCtx.FunctionResolver.ResolveFunctionByOID(b.ctx, funcOID)
+	if err != nil {
+		panic(err)
+	}
+	if o.Language != tree.RoutineLangPLpgSQL {
+		panic(errors.AssertionFailedf("unexpected trigger function language: %v", o.Language))
+	}
+	b.factory.Metadata().AddUserDefinedFunction(o, nil /* name */)
+	stmt, err := plpgsql.Parse(o.Body)
+	if err != nil {
+		panic(err)
+	}
+	body := shiftTriggerArgvSubscripts(stmt.AST)
+	tn, err := b.catalog.FullyQualifiedName(b.ctx, tab)
+	if err != nil {
+		panic(err)
+	}
+
+	f := b.factory
+	constVal := func(d tree.Datum) opt.ScalarExpr {
+		return f.ConstructConstVal(d, d.ResolvedType())
+	}
+	level := "STATEMENT"
+	if trigger.ForEachRow() {
+		level = "ROW"
+	}
+	// TG_ARGV is 0-indexed as in Postgres. See shiftTriggerArgvSubscripts.
+	funcArgs := trigger.FuncArgs()
+	argv := tree.NewDArray(types.String)
+	for _, arg := range funcArgs {
+		if err := argv.Append(arg); err != nil {
+			panic(err)
+		}
+	}
+	relName := tree.NewDName(string(tab.Name()))
+	params := []struct {
+		name string
+		typ  *types.T
+		arg  opt.ScalarExpr
+	}{
+		{name: "new", typ: rowType, arg: newRow},
+		{name: "old", typ: rowType, arg: oldRow},
+		{name: "tg_name", typ: types.Name, arg: constVal(tree.NewDName(string(trigger.Name())))},
+		{name: "tg_when", typ: types.String, arg: constVal(tree.NewDString(trigger.ActionTime().String()))},
+		{name: "tg_level", typ: types.String, arg: constVal(tree.NewDString(level))},
+		{name: "tg_op", typ: types.String, arg: constVal(tree.NewDString(event.String()))},
+		{name: "tg_relid", typ: types.Oid, arg: constVal(tree.NewDOid(oid.Oid(tab.ID())))},
+		{name: "tg_relname", typ: types.Name, arg: constVal(relName)},
+		{name: "tg_table_name", typ: types.Name, arg: constVal(relName)},
+		{name: "tg_table_schema", typ: types.Name, arg: constVal(tree.NewDName(tn.Schema()))},
+		{name: "tg_nargs", typ: types.Int, arg: constVal(tree.NewDInt(tree.DInt(len(funcArgs))))},
+		{name: "tg_argv", typ: types.StringArray, arg: constVal(argv)},
+	}
+
+	bodyScope := b.allocScope()
+	args := make(memo.ScalarListExpr, len(params))
+	paramCols := make(opt.ColList, len(params))
+	paramTypes := make([]tree.ParamType, len(params))
+	for i := range params {
+		colName := funcParamColName(tree.Name(params[i].name), i)
+		col := b.synthesizeColumn(bodyScope, colName, params[i].typ, nil /* expr */, nil /* scalar */)
+		col.setParamOrd(i)
+		args[i] = params[i].arg
+		paramCols[i] = col.id
+		paramTypes[i] = tree.ParamType{Name: params[i].name, Typ: params[i].typ}
+	}
+
+	prevInsideUDF, prevInsideDataSource := b.insideUDF, b.insideDataSource
+	b.insideUDF, b.insideDataSource = true, false
+	defer func() {
+		b.insideUDF, b.insideDataSource = prevInsideUDF, prevInsideDataSource
+	}()
+	var plBuilder plpgsqlBuilder
+	plBuilder.init(b, nil /* colRefs */, paramTypes, nil /* outParams */, stmt.AST, rowType)
+	// The trigger function can modify the NEW and OLD rows.
+	plBuilder.varTypes["new"] = rowType
+	plBuilder.varTypes["old"] = rowType
+	stmtScope := plBuilder.build(body, bodyScope)
+	expr, physProps, _ := b.finishBuildLastStmt(stmtScope, bodyScope, false /* isSetReturning */, rowType)
+
+	return f.ConstructUDFCall(
+		args,
+		&memo.UDFCallPrivate{
+			Def: &memo.UDFDefinition{
+				Name:              fnName.Object(),
+				Typ:               rowType,
+				Volatility:        volatility.Volatile,
+				CalledOnNullInput: true,
+				RoutineType:       tree.UDFRoutine,
+				Body:              []memo.RelExpr{expr},
+				BodyProps:         []*physical.Required{physProps},
+				Params:            paramCols,
+			},
+		},
+	)
+}
+
+// buildRowTriggersBefore builds the BEFORE ROW triggers that fire for the given
+// event into the input of the mutation. For example, a trigger on an INSERT
+// into table ab would create an input expression similar to this SQL:
+//
+//	SELECT res.a AS a, res.b AS b FROM (
+//	  SELECT CASE WHEN <when> THEN trigger_fn(new, old, ...) ELSE new END AS res
+//	  FROM (SELECT ins_a, ins_b, (ins_a, ins_b) AS new, NULL AS old FROM ...)
+//	)
+//	WHERE res IS DISTINCT FROM NULL
+//
+// The rows returned by the trigger functions replace the values that are
+// inserted or updated, and rows for which a trigger function returns NULL are
+// skipped. Computed columns are never replaced; they are computed from the
+// values returned by the trigger functions.
+func (mb *mutationBuilder) buildRowTriggersBefore(event tree.TriggerEventType) {
+	triggers := findTriggers(
+		mb.tab, tree.TriggerActionTimeBefore, true /* forEachRow */, event, mb.updateTargetOrds,
+	)
+	if len(triggers) == 0 {
+		return
+	}
+	f := mb.b.factory
+	ords := triggerRowOrds(mb.tab)
+	rowType := triggerRowType(mb.tab, ords)
+	guard, guardColID := mb.triggerGuardFor(event)
+	for _, trigger := range triggers {
+		var newCol, oldCol opt.ColumnID
+		mb.outScope, newCol, oldCol = mb.b.projectTriggerRows(
+			mb.outScope,
+			rowType,
+			mb.b.buildTriggerRow(mb.triggerRowCols(event, ords, true /* isNew */), rowType),
+			mb.b.buildTriggerRow(mb.triggerRowCols(event, ords, false /* isNew */), rowType),
+		)
+
+		// The row is passed through unchanged if the trigger does not fire for
+		// it.
+		passthrough := newCol
+		if event == tree.TriggerEventDelete {
+			passthrough = oldCol
+		}
+		result := mb.b.buildTriggerFunctionCall(
+			mb.tab, trigger, event, rowType, f.ConstructVariable(newCol), f.ConstructVariable(oldCol),
+		)
+		cond := mb.b.buildTriggerCondition(trigger, rowType, newCol, oldCol, guard.build(f, guardColID))
+		if cond != nil {
+			result = f.ConstructCase(
+				memo.TrueSingleton,
+				memo.ScalarListExpr{f.ConstructWhen(cond, result)},
+				f.ConstructVariable(passthrough),
+			)
+		}
+		projectionsScope := mb.outScope.replace()
+		projectionsScope.appendColumnsFromScope(mb.outScope)
+		resultColName := scopeColName("").WithMetadataName(fmt.Sprintf("%s_result", trigger.Name()))
+		resultCol := mb.b.synthesizeColumn(projectionsScope, resultColName, rowType, nil /* expr */, result)
+		mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+		mb.outScope = projectionsScope
+
+		// Skip the rows for which the trigger function returned NULL.
+		mb.outScope.expr = f.ConstructSelect(
+			mb.outScope.expr,
+			memo.FiltersExpr{f.ConstructFiltersItem(
+				f.ConstructIsNot(f.ConstructVariable(resultCol.id), memo.NullSingleton),
+			)},
+		)
+		if event == tree.TriggerEventDelete {
+			continue
+		}
+
+		// Replace the inserted or updated values with the row returned by the
+		// trigger function.
+		colIDs := mb.insertColIDs
+		if event == tree.TriggerEventUpdate {
+			colIDs = mb.updateColIDs
+		}
+		projectionsScope = mb.outScope.replace()
+		projectionsScope.appendColumnsFromScope(mb.outScope)
+		for i, ord := range ords {
+			tabCol := mb.tab.Column(ord)
+			if tabCol.IsComputed() {
+				continue
+			}
+			if event == tree.TriggerEventInsert && colIDs[ord] != 0 {
+				// Clear the name of the replaced column, so that references to the
+				// table column (e.g. excluded.a) resolve to the new column. The
+				// names of updated columns are disambiguated later on.
+				for j := range projectionsScope.cols {
+					if projectionsScope.cols[j].id == colIDs[ord] {
+						projectionsScope.cols[j].clearName()
+					}
+				}
+			}
+			colName := scopeColName(tabCol.ColName()).WithMetadataName(
+				fmt.Sprintf("%s_%s", trigger.Name(), tabCol.ColName()),
+			)
+			colIDs[ord] = mb.b.synthesizeColumn(
+				projectionsScope, colName, tabCol.DatumType(), nil, /* expr */
+				f.ConstructColumnAccess(f.ConstructVariable(resultCol.id), memo.TupleOrdinal(i)),
+			).id
+		}
+		mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+		mb.outScope = projectionsScope
+	}
+}
+
+// buildStatementTriggersBefore wraps the mutation expression in With operators
+// that call the trigger functions of the BEFORE STATEMENT triggers that fire
+// for the events of the mutation. The With bindings are always materialized, so
+// each trigger function is called exactly once, before the mutation.
+func (mb *mutationBuilder) buildStatementTriggersBefore() {
+	var triggers []cat.Trigger
+	var events []tree.TriggerEventType
+	for _, event := range mb.triggerEvents {
+		for _, trigger := range findTriggers(
+			mb.tab, tree.TriggerActionTimeBefore, false /* forEachRow */, event, mb.updateTargetOrds,
+		) {
+			triggers = append(triggers, trigger)
+			events = append(events, event)
+		}
+	}
+	// Build the operators from the bottom up, so that the first trigger is
+	// called first.
+	for i := len(triggers) - 1; i >= 0; i-- {
+		binding := mb.b.buildStatementTrigger(mb.tab, triggers[i], events[i])
+		withID := mb.b.factory.Memo().NextWithID()
+		mb.md.AddWithBinding(withID, binding)
+		mb.outScope.expr = mb.b.factory.ConstructWith(binding, mb.outScope.expr, &memo.WithPrivate{
+			ID:   withID,
+			Name: fmt.Sprintf("trigger_%s", triggers[i].Name()),
+			Mtr:  tree.CTEMaterializeAlways,
+		})
+	}
+}
+
+// buildStatementTrigger builds an expression that returns a single row, with
+// the result of calling the trigger function of the given statement-level
+// trigger. The NEW and OLD rows are NULL.
+func (b *Builder) buildStatementTrigger(
+	tab cat.Table, trigger cat.Trigger, event tree.TriggerEventType,
+) memo.RelExpr {
+	f := b.factory
+	rowType := triggerRowType(tab, triggerRowOrds(tab))
+	null := f.ConstructNull(rowType)
+	call := b.buildTriggerFunctionCall(tab, trigger, event, rowType, null, null)
+	inScope := b.allocScope()
+	inScope.expr = f.ConstructNoColsRow()
+	if when := b.buildTriggerWhen(trigger, inScope); when != nil {
+		call = f.ConstructCase(
+			memo.TrueSingleton,
+			memo.ScalarListExpr{f.ConstructWhen(when, call)},
+			null,
+		)
+	}
+	outScope := inScope.push()
+	colName := scopeColName("").WithMetadataName(fmt.Sprintf("%s_result", trigger.Name()))
+	b.synthesizeColumn(outScope, colName, rowType, nil /* expr */, call)
+	b.constructProjectForScope(inScope, outScope)
+	return outScope.expr
+}
+
+// buildTriggersAfter queues the AFTER ROW and AFTER STATEMENT triggers that
+// fire for the events of the mutation. They are executed as cascades, after
+// the mutation and its foreign key cascades. Row-level triggers read the NEW
+// and OLD rows from the buffered mutation input, followed by the column that
+// tells the events of the mutation apart, if any.
+func (mb *mutationBuilder) buildTriggersAfter() {
+	ords := triggerRowOrds(mb.tab)
+	for _, event := range mb.triggerEvents {
+		triggers := findTriggers(
+			mb.tab, tree.TriggerActionTimeAfter, true /* forEachRow */, event, mb.updateTargetOrds,
+		)
+		if len(triggers) == 0 {
+			continue
+		}
+		mb.ensureWithID()
+		guard, guardColID := mb.triggerGuardFor(event)
+		oldValues := mb.triggerRowCols(event, ords, false /* isNew */)
+		if guardColID != 0 {
+			oldValues = append(oldValues, guardColID)
+		}
+		newValues := mb.triggerRowCols(event, ords, true /* isNew */)
+		for _, trigger := range triggers {
+			mb.cascades = append(mb.cascades, memo.FKCascade{
+				FKName: string(trigger.Name()),
+				Builder: &triggerCascadeBuilder{
+					tab:     mb.tab,
+					trigger: trigger,
+					guard:   guard,
+				},
+				WithID:    mb.withID,
+				OldValues: oldValues,
+				NewValues: newValues,
+			})
+		}
+	}
+	for _, event := range mb.triggerEvents {
+		mb.addStatementTriggerCascades(
+			mb.tab, tree.TriggerActionTimeAfter, event, mb.updateTargetOrds, 0, /* withID */
+		)
+	}
+}
+
+// addStatementTriggerCascades queues the statement-level triggers of the given
+// table with the given action time that fire for the event. If withID is 0, the
+// triggers always fire; otherwise they only fire if the buffer with the given
+// ID is not empty.
+//
+// BEFORE STATEMENT triggers are queued in this way for the child table of a
+// cascading foreign key, since the cascading mutation is only built once it is
+// executed.
+func (mb *mutationBuilder) addStatementTriggerCascades(
+	tab cat.Table,
+	actionTime tree.TriggerActionTime,
+	event tree.TriggerEventType,
+	updatedOrds intsets.Fast,
+	withID opt.WithID,
+) {
+	for _, trigger := range findTriggers(tab, actionTime, false /* forEachRow */, event, updatedOrds) {
+		mb.cascades = append(mb.cascades, memo.FKCascade{
+			FKName: string(trigger.Name()),
+			Builder: &triggerCascadeBuilder{
+				tab:     tab,
+				trigger: trigger,
+				guard:   triggerGuard{event: event},
+			},
+			WithID: withID,
+		})
+	}
+}
+
+// addCascadeTriggersBefore queues the BEFORE STATEMENT triggers of the child
+// table of the given cascading foreign key, which fire before the cascading
+// mutation. The triggers are queued before the cascade itself.
+func (mb *mutationBuilder) addCascadeTriggersBefore(
+	fk cat.ForeignKeyConstraint, childTab cat.Table, action tree.ReferenceAction, isDelete bool,
+) {
+	event := tree.TriggerEventUpdate
+	if isDelete && action == tree.Cascade {
+		event = tree.TriggerEventDelete
+	}
+	var updatedOrds intsets.Fast
+	if event == tree.TriggerEventUpdate {
+		for i, n := 0, fk.ColumnCount(); i < n; i++ {
+			updatedOrds.Add(fk.OriginColumnOrdinal(childTab, i))
+		}
+	}
+	mb.addStatementTriggerCascades(
+		childTab, tree.TriggerActionTimeBefore, event, updatedOrds, mb.withID,
+	)
+}
+
+// triggerCascadeBuilder is a memo.CascadeBuilder implementation that builds
+// the calls to the trigger function of a trigger that fires after the
+// mutation: once per row for an AFTER ROW trigger, and once for a statement
+// level trigger. See buildTriggersAfter.
+type triggerCascadeBuilder struct {
+	tab     cat.Table
+	trigger cat.Trigger
+	guard   triggerGuard
+}
+
+var _ memo.CascadeBuilder = &triggerCascadeBuilder{}
+
+// Build is part of the memo.CascadeBuilder interface.
+func (cb *triggerCascadeBuilder) Build(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	evalCtx *eval.Context,
+	catalog cat.Catalog,
+	factoryI interface{},
+	binding opt.WithID,
+	bindingProps *props.Relational,
+	oldValues, newValues opt.ColList,
+) (_ memo.RelExpr, err error) {
+	return buildCascadeHelper(ctx, semaCtx, evalCtx, catalog, factoryI, func(b *Builder) memo.RelExpr {
+		opt.MaybeInjectOptimizerTestingPanic(ctx, evalCtx)
+
+		event := cb.guard.event
+		if !cb.trigger.ForEachRow() {
+			return b.buildStatementTrigger(cb.tab, cb.trigger, event)
+		}
+
+		// Scan the buffered mutation input.
+		f := b.factory
+		md := f.Metadata()
+		inCols := make(opt.ColList, 0, len(oldValues)+len(newValues))
+		inCols = append(inCols, oldValues...)
+		inCols = append(inCols, newValues...)
+		outCols := make(opt.ColList, len(inCols))
+		for i := range inCols {
+			c := md.ColumnMeta(inCols[i])
+			outCols[i] = md.AddColumn(c.Alias, c.Type)
+		}
+		md.AddWithBinding(binding, f.ConstructFakeRel(&memo.FakeRelPrivate{
+			Props: bindingProps,
+		}))
+		inScope := b.allocScope()
+		inScope.expr = f.ConstructWithScan(&memo.WithScanPrivate{
+			With:    binding,
+			InCols:  inCols,
+			OutCols: outCols,
+			ID:      md.NextUniqueID(),
+		})
+		oldCols, newCols := outCols[:len(oldValues)], outCols[len(oldValues):]
+		var guardColID opt.ColumnID
+		if cb.guard.kind != triggerGuardNone {
+			guardColID = oldCols[len(oldCols)-1]
+			oldCols = oldCols[:len(oldCols)-1]
+		}
+		if len(oldCols) == 0 {
+			oldCols = nil
+		}
+		if len(newCols) == 0 {
+			newCols = nil
+		}
+
+		rowType := triggerRowType(cb.tab, triggerRowOrds(cb.tab))
+		outScope, newCol, oldCol := b.projectTriggerRows(
+			inScope, rowType, b.buildTriggerRow(newCols, rowType), b.buildTriggerRow(oldCols, rowType),
+		)
+		cond := b.buildTriggerCondition(cb.trigger, rowType, newCol, oldCol, cb.guard.build(f, guardColID))
+		if cond != nil {
+			outScope.expr = f.ConstructSelect(
+				outScope.expr, memo.FiltersExpr{f.ConstructFiltersItem(cond)},
+			)
+		}
+		call := b.buildTriggerFunctionCall(
+			cb.tab, cb.trigger, event, rowType, f.ConstructVariable(newCol), f.ConstructVariable(oldCol),
+		)
+		callScope := outScope.push()
+		colName := scopeColName("").WithMetadataName(fmt.Sprintf("%s_result", cb.trigger.Name()))
+		b.synthesizeColumn(callScope, colName, rowType, nil /* expr */, call)
+		b.constructProjectForScope(outScope, callScope)
+		return callScope.expr
+	})
+}
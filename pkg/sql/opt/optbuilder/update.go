@@ -96,6 +96,8 @@ func (b *Builder) buildUpdate(upd *tree.Update, inScope *scope) (outScope *scope
 
 	var mb mutationBuilder
 	mb.init(b, "update", tab, alias)
+	mb.initRowLevelSecurity()
+	mb.initTriggers(tree.TriggerEventUpdate)
 
 	// Build the input expression that selects the rows that will be updated:
 	//
@@ -119,6 +121,9 @@ func (b *Builder) buildUpdate(upd *tree.Update, inScope *scope) (outScope *scope
 		mb.buildUpdate(nil /* returning */)
 	}
 
+	// Call the trigger functions of the BEFORE STATEMENT triggers first.
+	mb.buildStatementTriggersBefore()
+
 	return mb.outScope
 }
 
@@ -298,6 +303,14 @@ func (mb *mutationBuilder) addUpdateCols(exprs tree.UpdateExprs) {
 // operator containing any computed columns that need to be updated. This
 // includes write-only mutation columns that are computed.
 func (mb *mutationBuilder) addSynthesizedColsForUpdate() {
+	// Remember the target columns of the UPDATE, which decide whether UPDATE OF
+	// triggers fire.
+	for ord, colID := range mb.updateColIDs {
+		if colID != 0 {
+			mb.updateTargetOrds.Add(ord)
+		}
+	}
+
 	// Allow mutation columns to be referenced by other computed mutation
 	// columns (otherwise the scope will raise an error if a mutation column
 	// is referenced). These do not need to be set back to true again because
@@ -319,6 +332,10 @@ func (mb *mutationBuilder) addSynthesizedColsForUpdate() {
 	// Add assignment casts for default column values.
 	mb.addAssignmentCasts(mb.updateColIDs)
 
+	// Call the trigger functions of the BEFORE UPDATE row-level triggers, which
+	// may replace the updated values.
+	mb.buildRowTriggersBefore(tree.TriggerEventUpdate)
+
 	// Disambiguate names so that references in the computed expression refer to
 	// the correct columns.
 	mb.disambiguateColumns()
@@ -340,6 +357,9 @@ func (mb *mutationBuilder) buildUpdate(returning *tree.ReturningExprs) {
 	// Add any check constraint boolean columns to the input.
 	mb.addCheckConstraintCols(true /* isUpdate */)
 
+	// Reject rows that violate the row-level security policies of the table.
+	mb.addRowLevelSecurityChecks(tree.PolicyCommandUpdate, returning)
+
 	// Add the partial index predicate expressions to the table metadata.
 	// These expressions are used to prune fetch columns during
 	// normalization.
@@ -350,8 +370,12 @@ func (mb *mutationBuilder) buildUpdate(returning *tree.ReturningExprs) {
 
 	mb.buildUniqueChecksForUpdate()
 
+	mb.buildExclusionChecks(true /* isUpdate */)
+
 	mb.buildFKChecksForUpdate()
 
+	mb.buildTriggersAfter()
+
 	private := mb.makeMutationPrivate(returning != nil)
 	for _, col := range mb.extraAccessibleCols {
 		if col.id != 0 {
@@ -143,7 +143,7 @@ func (b *Builder) buildWindow(outScope *scope, inScope *scope) {
 
 		frameIdx := b.findMatchingFrameIndex(&frames, partitions[i], orderings[i])
 
-		fn := b.constructWindowFn(w.def.Name, argLists[i])
+		fn := b.constructWindowFn(&w.def, argLists[i])
 
 		if windowFrames[i].Bounds.StartBound.OffsetExpr != nil {
 			fn = b.factory.ConstructWindowFromOffset(
@@ -250,8 +250,7 @@ func (b *Builder) buildAggregationAsWindow(
 
 	// Create the window frames based on the orderings and groupings specified.
 	argLists := make([][]opt.ScalarExpr, len(g.aggs))
-	partitions := make([]opt.ColSet, len(g.aggs))
-	orderings := make([]props.OrderingChoice, len(g.aggs))
+	orderings := make([]opt.Ordering, len(g.aggs))
 	filterCols := make([]opt.ColumnID, len(g.aggs))
 
 	// Construct the pre-projection, which renders the grouping columns and the
@@ -259,20 +258,16 @@ func (b *Builder) buildAggregationAsWindow(
 	g.aggInScope.appendColumnsFromScope(fromScope)
 	b.constructProjectForScope(fromScope, g.aggInScope)
 
-	// Build the arguments, partitions and orderings for each aggregate.
+	// Build the arguments and orderings for each aggregate.
 	for i, agg := range g.aggs {
 		argExprs := getTypedExprs(agg.Exprs)
 
 		// Build the appropriate arguments.
 		argLists[i] = b.buildWindowArgs(argExprs, i, agg.def.Name, fromScope, g.aggInScope)
 
-		// Build appropriate partitions.
-		partitions[i] = groupingColSet.Copy()
-
 		// Build appropriate orderings.
 		if !agg.isCommutative() {
-			ord := b.buildWindowOrdering(agg.OrderBy, i, agg.def.Name, fromScope, g.aggInScope, false /* isRangeModeWithOffsets */)
-			orderings[i].FromOrdering(ord)
+			orderings[i] = b.buildWindowOrdering(agg.OrderBy, i, agg.def.Name, fromScope, g.aggInScope, false /* isRangeModeWithOffsets */)
 		}
 
 		if agg.Filter != nil {
@@ -281,14 +276,38 @@ func (b *Builder) buildAggregationAsWindow(
 		}
 	}
 
-	// Initialize the aggregate expression.
-	aggregateExpr := g.aggInScope.expr
+	g.aggOutScope.expr = b.constructAggregationAsWindow(
+		g.aggInScope.expr, groupingColSet, g.aggs, argLists, orderings, filterCols, g.aggOutScope,
+	)
 
+	// Wrap with having filter if it exists.
+	if having != nil {
+		input := g.aggOutScope.expr
+		filters := memo.FiltersExpr{b.factory.ConstructFiltersItem(having)}
+		g.aggOutScope.expr = b.factory.ConstructSelect(input, filters)
+	}
+	return g.aggOutScope
+}
+
+// constructAggregationAsWindow constructs the given aggregates as window
+// functions over the input, partitioned by the grouping columns. The window
+// functions are wrapped with a grouping so the values per group are squashed
+// down (see constructWindowGroup). argLists, orderings and filterCols contain
+// the arguments, ordering and filter column (or 0) of each aggregate.
+func (b *Builder) constructAggregationAsWindow(
+	input memo.RelExpr,
+	groupingColSet opt.ColSet,
+	aggInfos []aggregateInfo,
+	argLists [][]opt.ScalarExpr,
+	orderings []opt.Ordering,
+	filterCols []opt.ColumnID,
+	outScope *scope,
+) memo.RelExpr {
 	// frames accumulates the set of distinct window frames we're computing over
 	// so that we can group functions over the same partition and ordering.
-	frames := make([]memo.WindowExpr, 0, len(g.aggs))
-	for i, agg := range g.aggs {
-		fn := b.constructAggregate(agg.def.Name, argLists[i])
+	frames := make([]memo.WindowExpr, 0, len(aggInfos))
+	for i, agg := range aggInfos {
+		fn := b.constructAggregateForDef(&agg.def, argLists[i])
 		if filterCols[i] != 0 {
 			fn = b.factory.ConstructAggFilter(
 				fn,
@@ -296,7 +315,9 @@ func (b *Builder) buildAggregationAsWindow(
 			)
 		}
 
-		frameIdx := b.findMatchingFrameIndex(&frames, partitions[i], orderings[i])
+		var ordering props.OrderingChoice
+		ordering.FromOrdering(orderings[i])
+		frameIdx := b.findMatchingFrameIndex(&frames, groupingColSet.Copy(), ordering)
 
 		frames[frameIdx].Windows = append(frames[frameIdx].Windows,
 			b.factory.ConstructWindowsItem(
@@ -309,6 +330,7 @@ func (b *Builder) buildAggregationAsWindow(
 		)
 	}
 
+	aggregateExpr := input
 	for _, f := range frames {
 		aggregateExpr = b.factory.ConstructWindow(aggregateExpr, f.Windows, &f.WindowPrivate)
 	}
@@ -317,15 +339,7 @@ func (b *Builder) buildAggregationAsWindow(
 	// aggregations built as window functions emit an aggregated value for each row
 	// instead of each group. To rectify this, we must 'squash' the values down by
 	// wrapping it with a GroupBy or ScalarGroupBy.
-	g.aggOutScope.expr = b.constructWindowGroup(aggregateExpr, groupingColSet, g.aggs, g.aggOutScope)
-
-	// Wrap with having filter if it exists.
-	if having != nil {
-		input := g.aggOutScope.expr
-		filters := memo.FiltersExpr{b.factory.ConstructFiltersItem(having)}
-		g.aggOutScope.expr = b.factory.ConstructSelect(input, filters)
-	}
-	return g.aggOutScope
+	return b.constructWindowGroup(aggregateExpr, groupingColSet, aggInfos, outScope)
 }
 
 // getTypedWindowArgs returns the arguments to the window function as
@@ -587,6 +601,9 @@ func (b *Builder) replaceDefaultReturn(
 // value for scalar group by when no rows are returned. The default null value
 // to be applied is also returned.
 func (b *Builder) overrideDefaultNullValue(agg aggregateInfo) (opt.ScalarExpr, bool) {
+	if agg.isUserDefined() {
+		return nil, false
+	}
 	switch agg.def.Name {
 	case "count", "count_rows":
 		return b.factory.ConstructConst(tree.NewDInt(0), types.Int), true
@@ -221,6 +221,13 @@ func init() {
 		buildChildReqOrdering: groupByBuildChildReqOrdering,
 		buildProvidedOrdering: groupByBuildProvided,
 	}
+	funcMap[opt.GroupingSetsOp] = funcs{
+		// The rows of the different grouping sets are interleaved in the output,
+		// so GroupingSets cannot provide an ordering.
+		canProvideOrdering:    canNeverProvideOrdering,
+		buildChildReqOrdering: groupingSetsBuildChildReqOrdering,
+		buildProvidedOrdering: noProvidedOrdering,
+	}
 	funcMap[opt.DistinctOnOp] = funcs{
 		canProvideOrdering:    distinctOnCanProvideOrdering,
 		buildChildReqOrdering: distinctOnBuildChildReqOrdering,
@@ -0,0 +1,75 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package opt
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
+	"github.com/cockroachdb/cockroach/pkg/util/intsets"
+)
+
+// RowLevelSecurityState describes how the row-level security policies of a
+// table apply to the current user. It captures every decision that depends on
+// the identity of the user, so that a query built for one user is not reused
+// for another user to whom different policies apply.
+type RowLevelSecurityState struct {
+	// Exempt is true if the policies of the table are not enforced for the
+	// current user. This is the case if row-level security is not enabled on the
+	// table, if the user has the BYPASSRLS role option (which includes admins),
+	// or if the user owns the table and row-level security is not forced.
+	Exempt bool
+
+	// Policies is the set of ordinals of the policies that apply to one of the
+	// roles of the current user. It is empty if Exempt is true.
+	Policies intsets.Fast
+}
+
+// Equals returns true if the two states are identical.
+func (s *RowLevelSecurityState) Equals(other *RowLevelSecurityState) bool {
+	return s.Exempt == other.Exempt && s.Policies.Equals(other.Policies)
+}
+
+// GetRowLevelSecurityState determines how the row-level security policies of
+// the given table apply to the current user.
+func GetRowLevelSecurityState(
+	ctx context.Context, catalog cat.Catalog, tab cat.Table,
+) (RowLevelSecurityState, error) {
+	if !tab.IsRowLevelSecurityEnabled() {
+		return RowLevelSecurityState{Exempt: true}, nil
+	}
+	bypass, err := catalog.HasRoleOption(ctx, roleoption.BYPASSRLS)
+	if err != nil || bypass {
+		return RowLevelSecurityState{Exempt: true}, err
+	}
+	if !tab.IsRowLevelSecurityForced() {
+		isOwner, err := catalog.HasOwnership(ctx, tab)
+		if err != nil || isOwner {
+			return RowLevelSecurityState{Exempt: true}, err
+		}
+	}
+	var state RowLevelSecurityState
+	for i, n := 0, tab.PolicyCount(); i < n; i++ {
+		policy := tab.Policy(i)
+		for j, m := 0, policy.RoleCount(); j < m; j++ {
+			isMember, err := catalog.IsMemberOfRole(ctx, policy.Role(j))
+			if err != nil {
+				return RowLevelSecurityState{}, err
+			}
+			if isMember {
+				state.Policies.Add(i)
+				break
+			}
+		}
+	}
+	return state, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package opt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TableSampleMethod identifies the sampling method of a TABLESAMPLE clause.
+type TableSampleMethod uint8
+
+const (
+	// NoTableSample indicates that the scan is not sampled.
+	NoTableSample TableSampleMethod = iota
+
+	// BernoulliTableSample selects each row of the table independently with
+	// the sample probability. Every row must still be read from KV, so only
+	// the cost of processing the rows above the scan is reduced.
+	BernoulliTableSample
+
+	// SystemTableSample selects each range of the table independently with the
+	// sample probability, and skips the unselected ranges entirely. This is
+	// much cheaper than BernoulliTableSample, but the sample is clustered, and
+	// since ranges are not weighted by their size, the size of the sample
+	// varies much more than with Postgres' pages.
+	SystemTableSample
+)
+
+// TableSampleMethodFromString returns the TableSampleMethod with the given
+// (case-insensitive) name, and false if there is no such method.
+func TableSampleMethodFromString(name string) (TableSampleMethod, bool) {
+	switch strings.ToLower(name) {
+	case "bernoulli":
+		return BernoulliTableSample, true
+	case "system":
+		return SystemTableSample, true
+	}
+	return NoTableSample, false
+}
+
+// String implements the fmt.Stringer interface.
+func (m TableSampleMethod) String() string {
+	switch m {
+	case NoTableSample:
+		return "none"
+	case BernoulliTableSample:
+		return "bernoulli"
+	case SystemTableSample:
+		return "system"
+	}
+	return fmt.Sprintf("TableSampleMethod(%d)", m)
+}
+
+// TableSample represents the TABLESAMPLE clause of a table scan. The zero
+// value indicates that the scan is not sampled.
+type TableSample struct {
+	// Method is the sampling method.
+	Method TableSampleMethod
+
+	// Fraction is the probability, between 0 and 1, with which each row (or
+	// range, for SYSTEM sampling) is selected.
+	Fraction float64
+
+	// Repeatable is true if the seed was specified with a REPEATABLE clause. If
+	// it is false, Seed is unset and a new seed is chosen every time the query
+	// is executed.
+	Repeatable bool
+
+	// Seed is the seed of the sample, as given by the REPEATABLE clause. The
+	// same seed produces the same sample as long as the underlying table is
+	// unchanged.
+	Seed float64
+}
+
+// IsSampled returns true if the scan is sampled.
+func (s TableSample) IsSampled() bool {
+	return s.Method != NoTableSample
+}
+
+// String implements the fmt.Stringer interface.
+func (s TableSample) String() string {
+	if !s.IsSampled() {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%.6g%%)", s.Method, s.Fraction*100)
+	if s.Repeatable {
+		fmt.Fprintf(&b, " seed=%g", s.Seed)
+	}
+	return b.String()
+}
@@ -358,7 +358,9 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 		switch def := def.(type) {
 		case *tree.UniqueConstraintTableDef:
 			if def.WithoutIndex {
-				tab.addUniqueConstraint(def.Name, def.Columns, def.Predicate, def.WithoutIndex)
+				tab.addUniqueConstraint(
+					def.Name, def.Columns, def.Predicate, def.WithoutIndex, def.Deferrable,
+				)
 			} else if !def.PrimaryKey {
 				tab.addIndex(&def.IndexTableDef, uniqueIndex)
 			}
@@ -366,6 +368,9 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 		case *tree.IndexTableDef:
 			tab.addIndex(def, nonUniqueIndex)
 
+		case *tree.ExclusionConstraintTableDef:
+			tab.addExclusionConstraint(def)
+
 		case *tree.FamilyTableDef:
 			tab.addFamily(def)
 
@@ -377,6 +382,7 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 						tree.IndexElemList{{Column: def.Name}},
 						nil, /* predicate */
 						def.Unique.WithoutIndex,
+						tree.ConstraintNotDeferrable,
 					)
 				} else {
 					tab.addIndex(
@@ -678,6 +684,7 @@ func (tc *Catalog) resolveFK(tab *Table, d *tree.ForeignKeyConstraintTableDef) {
 		matchMethod:              d.Match,
 		deleteAction:             d.Actions.Delete,
 		updateAction:             d.Actions.Update,
+		deferrability:            d.Deferrable,
 	}
 	tab.outboundFKs = append(tab.outboundFKs, fk)
 	targetTable.inboundFKs = append(targetTable.inboundFKs, fk)
@@ -713,7 +720,11 @@ func (tt *Table) addCheckConstraint(check *tree.CheckConstraintTableDef) {
 }
 
 func (tt *Table) addUniqueConstraint(
-	name tree.Name, columns tree.IndexElemList, predicate tree.Expr, withoutIndex bool,
+	name tree.Name,
+	columns tree.IndexElemList,
+	predicate tree.Expr,
+	withoutIndex bool,
+	deferrability tree.ConstraintDeferrability,
 ) {
 	// We don't currently use unique constraints with an index (those are already
 	// tracked with unique indexes), so don't bother adding them.
@@ -734,6 +745,7 @@ func (tt *Table) addUniqueConstraint(
 		columnOrdinals: cols,
 		withoutIndex:   withoutIndex,
 		validated:      true,
+		deferrability:  deferrability,
 	}
 	// Add partial unique constraint predicate.
 	if predicate != nil {
@@ -752,6 +764,26 @@ func (tt *Table) addUniqueConstraint(
 	tt.uniqueConstraints = append(tt.uniqueConstraints, u)
 }
 
+func (tt *Table) addExclusionConstraint(def *tree.ExclusionConstraintTableDef) {
+	e := ExclusionConstraint{
+		name:           string(def.Name),
+		tabID:          tt.TabID,
+		columnOrdinals: make([]int, len(def.Elems)),
+		operators:      make([]treecmp.ComparisonOperator, len(def.Elems)),
+	}
+	for i, elem := range def.Elems {
+		e.columnOrdinals[i] = tt.FindOrdinal(string(elem.Column))
+		e.operators[i] = elem.Operator
+	}
+	if e.name == "" {
+		e.name = fmt.Sprintf("%s_excl", tt.TabName.Table())
+	}
+	if def.Predicate != nil {
+		e.predicate = tree.Serialize(def.Predicate)
+	}
+	tt.exclusionConstraints = append(tt.exclusionConstraints, e)
+}
+
 func (tt *Table) addColumn(def *tree.ColumnTableDef) {
 	ordinal := len(tt.Columns)
 	nullable := !def.PrimaryKey.IsPrimaryKey && def.Nullable.Nullability != tree.NotNull
@@ -874,7 +906,9 @@ func (tt *Table) addIndexWithVersion(
 ) *Index {
 	// Add a unique constraint if this is a primary or unique index.
 	if typ != nonUniqueIndex {
-		tt.addUniqueConstraint(def.Name, def.Columns, def.Predicate, false /* withoutIndex */)
+		tt.addUniqueConstraint(
+			def.Name, def.Columns, def.Predicate, false /* withoutIndex */, tree.ConstraintNotDeferrable,
+		)
 	}
 
 	// The test catalog does not support the hash-sharded index syntactic sugar.
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/treeprinter"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
@@ -81,18 +82,26 @@ func (tc *Catalog) CreateRoutine(c *tree.CreateRoutine) {
 	}
 
 	// Resolve the parameter names and types.
-	paramTypes := make(tree.ParamTypes, len(c.Params))
+	params := make(tree.RoutineParams, len(c.Params))
+	var paramNames []string
+	var paramTypes []*types.T
+	var variadic bool
 	for i := range c.Params {
 		param := &c.Params[i]
 		typ, err := tree.ResolveType(context.Background(), param.Type, tc)
 		if err != nil {
 			panic(err)
 		}
-		paramTypes.SetAt(i, string(param.Name), typ)
+		params[i] = tree.RoutineParam{Name: param.Name, Type: typ, Class: param.Class}
+		if param.IsSignatureParam(c.IsProcedure) {
+			paramNames = append(paramNames, string(param.Name))
+			paramTypes = append(paramTypes, typ)
+			variadic = param.Class == tree.RoutineParamVariadic
+		}
 	}
 
 	// Resolve the return type.
-	retType, err := tree.ResolveType(context.Background(), c.ReturnType.Type, tc)
+	retType, err := c.ResolveReturnType(context.Background(), tc)
 	if err != nil {
 		panic(err)
 	}
@@ -111,7 +120,8 @@ func (tc *Catalog) CreateRoutine(c *tree.CreateRoutine) {
 	tc.currUDFOid++
 	overload := &tree.Overload{
 		Oid:               tc.currUDFOid,
-		Types:             paramTypes,
+		Types:             tree.MakeRoutineTypeList(paramNames, paramTypes, variadic),
+		RoutineParams:     params,
 		ReturnType:        tree.FixedReturnType(retType),
 		Body:              body,
 		Volatility:        v,
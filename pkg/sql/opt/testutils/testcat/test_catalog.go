@@ -35,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/sql/syntheticprivilege"
@@ -327,6 +328,16 @@ func (tc *Catalog) CheckRoleExists(ctx context.Context, role username.SQLUsernam
 	return nil
 }
 
+// HasOwnership is part of the cat.Catalog interface.
+func (tc *Catalog) HasOwnership(ctx context.Context, o cat.Object) (bool, error) {
+	return true, nil
+}
+
+// IsMemberOfRole is part of the cat.Catalog interface.
+func (tc *Catalog) IsMemberOfRole(ctx context.Context, role username.SQLUsername) (bool, error) {
+	return true, nil
+}
+
 // Optimizer is part of the cat.Catalog interface.
 func (tc *Catalog) Optimizer() interface{} {
 	return nil
@@ -734,6 +745,7 @@ type Table struct {
 	Indexes    []*Index
 	Stats      TableStats
 	Checks     []cat.CheckConstraint
+	Triggers   []cat.Trigger
 	Families   []*Family
 	IsVirtual  bool
 	IsSystem   bool
@@ -750,6 +762,8 @@ type Table struct {
 
 	uniqueConstraints []UniqueConstraint
 
+	exclusionConstraints []ExclusionConstraint
+
 	// partitionBy is the partitioning clause that corresponds to the primary
 	// index. Used to initialize the partitioning for the primary index.
 	partitionBy *tree.PartitionBy
@@ -870,6 +884,36 @@ func (tt *Table) Check(i int) cat.CheckConstraint {
 	return tt.Checks[i]
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (tt *Table) TriggerCount() int {
+	return len(tt.Triggers)
+}
+
+// Trigger is part of the cat.Table interface.
+func (tt *Table) Trigger(i int) cat.Trigger {
+	return tt.Triggers[i]
+}
+
+// IsRowLevelSecurityEnabled is part of the cat.Table interface.
+func (tt *Table) IsRowLevelSecurityEnabled() bool {
+	return false
+}
+
+// IsRowLevelSecurityForced is part of the cat.Table interface.
+func (tt *Table) IsRowLevelSecurityForced() bool {
+	return false
+}
+
+// PolicyCount is part of the cat.Table interface.
+func (tt *Table) PolicyCount() int {
+	return 0
+}
+
+// Policy is part of the cat.Table interface.
+func (tt *Table) Policy(i int) cat.Policy {
+	panic(errors.AssertionFailedf("no policies"))
+}
+
 // FamilyCount is part of the cat.Table interface.
 func (tt *Table) FamilyCount() int {
 	return len(tt.Families)
@@ -910,6 +954,16 @@ func (tt *Table) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	return &tt.uniqueConstraints[i]
 }
 
+// ExclusionCount is part of the cat.Table interface.
+func (tt *Table) ExclusionCount() int {
+	return len(tt.exclusionConstraints)
+}
+
+// Exclusion is part of the cat.Table interface.
+func (tt *Table) Exclusion(i int) cat.ExclusionConstraint {
+	return &tt.exclusionConstraints[i]
+}
+
 // Zone is part of the cat.Table interface.
 func (tt *Table) Zone() cat.Zone {
 	zone := zonepb.DefaultZoneConfig()
@@ -1009,7 +1063,7 @@ func (tt *Table) CollectTypes(ord int) (descpb.IDs, error) {
 		}
 	}
 	if col.DatumType() != nil && col.DatumType().UserDefined() {
-		visitor.OIDs[col.DatumType().Oid()] = struct{}{}
+		visitor.OIDs[col.DatumType().UserDefinedTypeOID()] = struct{}{}
 	}
 
 	ids := make(descpb.IDs, 0, len(visitor.OIDs))
@@ -1443,10 +1497,11 @@ type ForeignKeyConstraint struct {
 	originColumnOrdinals     []int
 	referencedColumnOrdinals []int
 
-	validated    bool
-	matchMethod  tree.CompositeKeyMatchMethod
-	deleteAction tree.ReferenceAction
-	updateAction tree.ReferenceAction
+	validated     bool
+	matchMethod   tree.CompositeKeyMatchMethod
+	deleteAction  tree.ReferenceAction
+	updateAction  tree.ReferenceAction
+	deferrability tree.ConstraintDeferrability
 }
 
 var _ cat.ForeignKeyConstraint = &ForeignKeyConstraint{}
@@ -1514,6 +1569,11 @@ func (fk *ForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction {
 	return fk.updateAction
 }
 
+// Deferrability is part of the cat.ForeignKeyConstraint interface.
+func (fk *ForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	return fk.deferrability
+}
+
 // UniqueConstraint implements cat.UniqueConstraint. See that interface
 // for more information on the fields.
 type UniqueConstraint struct {
@@ -1523,6 +1583,7 @@ type UniqueConstraint struct {
 	predicate      string
 	withoutIndex   bool
 	validated      bool
+	deferrability  tree.ConstraintDeferrability
 }
 
 var _ cat.UniqueConstraint = &UniqueConstraint{}
@@ -1574,6 +1635,59 @@ func (u *UniqueConstraint) UniquenessGuaranteedByAnotherIndex() bool {
 	return false
 }
 
+// Deferrability is part of the cat.UniqueConstraint interface.
+func (u *UniqueConstraint) Deferrability() tree.ConstraintDeferrability {
+	return u.deferrability
+}
+
+// ExclusionConstraint implements cat.ExclusionConstraint. See that interface
+// for more information on the fields.
+type ExclusionConstraint struct {
+	name           string
+	tabID          cat.StableID
+	columnOrdinals []int
+	operators      []treecmp.ComparisonOperator
+	predicate      string
+}
+
+var _ cat.ExclusionConstraint = &ExclusionConstraint{}
+
+// Name is part of the cat.ExclusionConstraint interface.
+func (e *ExclusionConstraint) Name() string {
+	return e.name
+}
+
+// ColumnCount is part of the cat.ExclusionConstraint interface.
+func (e *ExclusionConstraint) ColumnCount() int {
+	return len(e.columnOrdinals)
+}
+
+// ColumnOrdinal is part of the cat.ExclusionConstraint interface.
+func (e *ExclusionConstraint) ColumnOrdinal(tab cat.Table, i int) int {
+	if tab.ID() != e.tabID {
+		panic(errors.AssertionFailedf(
+			"invalid table %d passed to ColumnOrdinal (expected %d)",
+			tab.ID(), e.tabID,
+		))
+	}
+	return e.columnOrdinals[i]
+}
+
+// Operator is part of the cat.ExclusionConstraint interface.
+func (e *ExclusionConstraint) Operator(i int) treecmp.ComparisonOperator {
+	return e.operators[i]
+}
+
+// Predicate is part of the cat.ExclusionConstraint interface.
+func (e *ExclusionConstraint) Predicate() (string, bool) {
+	return e.predicate, e.predicate != ""
+}
+
+// Validated is part of the cat.ExclusionConstraint interface.
+func (e *ExclusionConstraint) Validated() bool {
+	return true
+}
+
 // Sequence implements the cat.Sequence interface for testing purposes.
 type Sequence struct {
 	SeqID      cat.StableID
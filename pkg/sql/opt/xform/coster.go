@@ -581,6 +581,9 @@ func (c *coster) ComputeCost(candidate memo.RelExpr, required *physical.Required
 		opt.UpsertDistinctOnOp, opt.EnsureUpsertDistinctOnOp:
 		cost = c.computeGroupingCost(candidate, required)
 
+	case opt.GroupingSetsOp:
+		cost = c.computeGroupingSetsCost(candidate.(*memo.GroupingSetsExpr))
+
 	case opt.LimitOp:
 		cost = c.computeLimitCost(candidate.(*memo.LimitExpr))
 
@@ -786,6 +789,19 @@ func (c *coster) computeScanCost(scan *memo.ScanExpr, required *physical.Require
 	// row cost depends on the size of the columns scanned.
 	perRowCost := c.rowScanCost(scan.Table, scan.Index, scan.Cols)
 
+	// readRatio is the number of rows read from KV for each row produced by the
+	// scan. A SYSTEM sample skips unselected ranges entirely, so it only reads
+	// the sampled rows, and its row count has already been scaled down by the
+	// statistics builder. A BERNOULLI sample, on the other hand, must read every
+	// row of the table and discard the rows that are not selected, and each row
+	// read incurs the CPU cost of deciding whether it is selected.
+	readRatio := 1.0
+	if scan.Sample.Method == opt.BernoulliTableSample {
+		readRatio = 1 / stats.Selectivity.AsFloat()
+		rowCount *= readRatio
+		perRowCost += cpuCostFactor
+	}
+
 	numSpans := 1
 	if scan.Constraint != nil {
 		numSpans = scan.Constraint.Spans.Count()
@@ -838,7 +854,7 @@ func (c *coster) computeScanCost(scan *memo.ScanExpr, required *physical.Require
 	baseCost += c.largeCardinalityCostPenalty(scan.Relational().Cardinality, rowCount)
 
 	if required.LimitHint != 0 {
-		rowCount = math.Min(rowCount, required.LimitHint)
+		rowCount = math.Min(rowCount, required.LimitHint*readRatio)
 	}
 
 	cost := baseCost + memo.Cost(rowCount)*(seqIOCostFactor+perRowCost)
@@ -1430,6 +1446,31 @@ func (c *coster) computeGroupingCost(grouping memo.RelExpr, required *physical.R
 	return cost
 }
 
+func (c *coster) computeGroupingSetsCost(groupingSets *memo.GroupingSetsExpr) memo.Cost {
+	cost := memo.Cost(cpuCostFactor)
+
+	// Add the CPU cost of emitting the rows.
+	outputRowCount := groupingSets.Relational().Statistics().RowCount
+	cost += memo.Cost(outputRowCount) * cpuCostFactor
+
+	// Each input row is aggregated once for every grouping set, using a hash
+	// table keyed by the grouping columns and the ordinal of the set.
+	setCount := len(groupingSets.Sets)
+	groupingColCount := groupingSets.GroupingCols.Len() + 1
+	aggsCount := len(groupingSets.Aggregations)
+	inputRowCount := groupingSets.Input.Relational().Statistics().RowCount
+	expandedRowCount := inputRowCount * float64(setCount)
+	cost += memo.Cost(expandedRowCount) * memo.Cost(aggsCount+groupingColCount) * cpuCostFactor
+
+	// Add the cost to build the hash table, and a cost for buffering rows that
+	// takes into account increased memory pressure and the possibility of
+	// spilling to disk.
+	cost += memo.Cost(expandedRowCount) * cpuCostFactor
+	cost += c.rowBufferCost(outputRowCount)
+
+	return cost
+}
+
 func (c *coster) computeLimitCost(limit *memo.LimitExpr) memo.Cost {
 	// Add the CPU cost of emitting the rows.
 	cost := memo.Cost(limit.Relational().Statistics().RowCount) * cpuCostFactor
@@ -501,6 +501,31 @@ func (oc *optCatalog) CheckRoleExists(ctx context.Context, role username.SQLUser
 	return oc.planner.CheckRoleExists(ctx, role)
 }
 
+// HasOwnership is part of the cat.Catalog interface.
+func (oc *optCatalog) HasOwnership(ctx context.Context, o cat.Object) (bool, error) {
+	desc, err := getDescFromCatalogObjectForPermissions(o)
+	if err != nil {
+		return false, err
+	}
+	return oc.planner.HasOwnership(ctx, desc)
+}
+
+// IsMemberOfRole is part of the cat.Catalog interface.
+func (oc *optCatalog) IsMemberOfRole(
+	ctx context.Context, role username.SQLUsername,
+) (bool, error) {
+	user := oc.planner.User()
+	if role.IsPublicRole() || role == user {
+		return true, nil
+	}
+	memberOf, err := oc.planner.MemberOfWithAdminOption(ctx, user)
+	if err != nil {
+		return false, err
+	}
+	_, ok := memberOf[role]
+	return ok, nil
+}
+
 // Optimizer is part of the cat.Catalog interface.
 func (oc *optCatalog) Optimizer() interface{} {
 	if oc.planner == nil {
@@ -766,6 +791,8 @@ type optTable struct {
 
 	uniqueConstraints []optUniqueConstraint
 
+	exclusionConstraints []optExclusionConstraint
+
 	outboundFKs []optForeignKeyConstraint
 	inboundFKs  []optForeignKeyConstraint
 
@@ -774,6 +801,10 @@ type optTable struct {
 	// constraints for user defined types.
 	checkConstraints []optCheckConstraint
 
+	triggers []optTrigger
+
+	policies []optPolicy
+
 	// colMap is a mapping from unique ColumnID to column ordinal within the
 	// table. This is a common lookup that needs to be fast.
 	colMap catalog.TableColMap
@@ -907,12 +938,29 @@ func newOptTable(
 	ot.uniqueConstraints = make([]optUniqueConstraint, len(ot.desc.EnforcedUniqueConstraintsWithoutIndex()))
 	for i, u := range ot.desc.EnforcedUniqueConstraintsWithoutIndex() {
 		ot.uniqueConstraints[i] = optUniqueConstraint{
-			name:         u.GetName(),
-			table:        ot.ID(),
-			columns:      u.CollectKeyColumnIDs().Ordered(),
-			predicate:    u.GetPredicate(),
-			withoutIndex: true,
-			validity:     u.GetConstraintValidity(),
+			name:          u.GetName(),
+			table:         ot.ID(),
+			columns:       u.CollectKeyColumnIDs().Ordered(),
+			predicate:     u.GetPredicate(),
+			withoutIndex:  true,
+			validity:      u.GetConstraintValidity(),
+			deferrability: tree.ConstraintDeferrabilityType[u.Deferrability()],
+		}
+	}
+
+	// Add exclusion constraints.
+	ot.exclusionConstraints = make([]optExclusionConstraint, len(ot.desc.EnforcedExclusionConstraints()))
+	for i, e := range ot.desc.EnforcedExclusionConstraints() {
+		ot.exclusionConstraints[i] = optExclusionConstraint{
+			name:      e.GetName(),
+			table:     ot.ID(),
+			columns:   e.ExclusionDesc().ColumnIDs,
+			operators: make([]treecmp.ComparisonOperator, e.NumKeyColumns()),
+			predicate: e.GetPredicate(),
+			validity:  e.GetConstraintValidity(),
+		}
+		for j := range ot.exclusionConstraints[i].operators {
+			ot.exclusionConstraints[i].operators[j] = e.GetOperator(j)
 		}
 	}
 
@@ -1017,6 +1065,7 @@ func newOptTable(
 			match:             tree.CompositeKeyMatchMethodType[fk.Match()],
 			deleteAction:      tree.ForeignKeyReferenceActionType[fk.OnDelete()],
 			updateAction:      tree.ForeignKeyReferenceActionType[fk.OnUpdate()],
+			deferrability:     tree.ConstraintDeferrabilityType[fk.Deferrability()],
 		})
 	}
 	for _, fk := range ot.desc.InboundForeignKeys() {
@@ -1030,6 +1079,7 @@ func newOptTable(
 			match:             tree.CompositeKeyMatchMethodType[fk.Match()],
 			deleteAction:      tree.ForeignKeyReferenceActionType[fk.OnDelete()],
 			updateAction:      tree.ForeignKeyReferenceActionType[fk.OnUpdate()],
+			deferrability:     tree.ConstraintDeferrabilityType[fk.Deferrability()],
 		})
 	}
 
@@ -1084,6 +1134,26 @@ func newOptTable(
 	}
 	ot.checkConstraints = append(ot.checkConstraints, synthesizedChecks...)
 
+	// Add triggers.
+	descTriggers := desc.GetTriggers()
+	if len(descTriggers) > 0 {
+		ot.triggers = make([]optTrigger, len(descTriggers))
+		for i := range descTriggers {
+			if err := ot.triggers[i].init(ot, &descTriggers[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Add row-level security policies.
+	descPolicies := desc.GetPolicies()
+	if len(descPolicies) > 0 {
+		ot.policies = make([]optPolicy, len(descPolicies))
+		for i := range descPolicies {
+			ot.policies[i].init(&descPolicies[i])
+		}
+	}
+
 	// Add stats last, now that other metadata is initialized.
 	if stats != nil {
 		ot.stats = make([]optTableStat, len(stats))
@@ -1266,6 +1336,36 @@ func (ot *optTable) Check(i int) cat.CheckConstraint {
 	return &ot.checkConstraints[i]
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (ot *optTable) TriggerCount() int {
+	return len(ot.triggers)
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optTable) Trigger(i int) cat.Trigger {
+	return &ot.triggers[i]
+}
+
+// IsRowLevelSecurityEnabled is part of the cat.Table interface.
+func (ot *optTable) IsRowLevelSecurityEnabled() bool {
+	return ot.desc.IsRowLevelSecurityEnabled()
+}
+
+// IsRowLevelSecurityForced is part of the cat.Table interface.
+func (ot *optTable) IsRowLevelSecurityForced() bool {
+	return ot.desc.IsRowLevelSecurityForced()
+}
+
+// PolicyCount is part of the cat.Table interface.
+func (ot *optTable) PolicyCount() int {
+	return len(ot.policies)
+}
+
+// Policy is part of the cat.Table interface.
+func (ot *optTable) Policy(i int) cat.Policy {
+	return &ot.policies[i]
+}
+
 // FamilyCount is part of the cat.Table interface.
 func (ot *optTable) FamilyCount() int {
 	return 1 + len(ot.families)
@@ -1309,6 +1409,16 @@ func (ot *optTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	return &ot.uniqueConstraints[i]
 }
 
+// ExclusionCount is part of the cat.Table interface.
+func (ot *optTable) ExclusionCount() int {
+	return len(ot.exclusionConstraints)
+}
+
+// Exclusion is part of the cat.Table interface.
+func (ot *optTable) Exclusion(i int) cat.ExclusionConstraint {
+	return &ot.exclusionConstraints[i]
+}
+
 // Zone is part of the cat.Table interface.
 func (ot *optTable) Zone() cat.Zone {
 	return ot.zone
@@ -1745,6 +1855,156 @@ func (op *optPartition) PartitionByListPrefixes() []tree.Datums {
 	return op.datums
 }
 
+// optTrigger implements cat.Trigger. See that interface for more information
+// on the fields.
+type optTrigger struct {
+	name       tree.Name
+	actionTime tree.TriggerActionTime
+	events     []cat.TriggerEvent
+	forEachRow bool
+	whenExpr   string
+	funcID     cat.StableID
+	funcArgs   tree.Datums
+	enabled    bool
+}
+
+var _ cat.Trigger = &optTrigger{}
+
+// init initializes the optTrigger from the given trigger descriptor.
+func (ot *optTrigger) init(tab *optTable, desc *descpb.TriggerDescriptor) error {
+	ot.name = tree.Name(desc.Name)
+	ot.actionTime = tree.TriggerActionTimeType[desc.ActionTime]
+	ot.events = make([]cat.TriggerEvent, len(desc.Events))
+	for i, ev := range desc.Events {
+		ot.events[i].EventType = tree.TriggerEventTypeType[ev.Type]
+		if len(ev.ColumnIDs) > 0 {
+			ot.events[i].ColumnOrdinals = make([]int, len(ev.ColumnIDs))
+			for j, colID := range ev.ColumnIDs {
+				ord, err := tab.lookupColumnOrdinal(colID)
+				if err != nil {
+					return err
+				}
+				ot.events[i].ColumnOrdinals[j] = ord
+			}
+		}
+	}
+	ot.forEachRow = desc.ForEachRow
+	ot.whenExpr = desc.WhenExpr
+	ot.funcID = cat.StableID(desc.FuncID)
+	ot.funcArgs = make(tree.Datums, len(desc.FuncArgs))
+	for i, arg := range desc.FuncArgs {
+		ot.funcArgs[i] = tree.NewDString(arg)
+	}
+	ot.enabled = desc.Enabled
+	return nil
+}
+
+// Name is part of the cat.Trigger interface.
+func (ot *optTrigger) Name() tree.Name {
+	return ot.name
+}
+
+// ActionTime is part of the cat.Trigger interface.
+func (ot *optTrigger) ActionTime() tree.TriggerActionTime {
+	return ot.actionTime
+}
+
+// EventCount is part of the cat.Trigger interface.
+func (ot *optTrigger) EventCount() int {
+	return len(ot.events)
+}
+
+// Event is part of the cat.Trigger interface.
+func (ot *optTrigger) Event(i int) cat.TriggerEvent {
+	return ot.events[i]
+}
+
+// ForEachRow is part of the cat.Trigger interface.
+func (ot *optTrigger) ForEachRow() bool {
+	return ot.forEachRow
+}
+
+// WhenExpr is part of the cat.Trigger interface.
+func (ot *optTrigger) WhenExpr() string {
+	return ot.whenExpr
+}
+
+// FuncID is part of the cat.Trigger interface.
+func (ot *optTrigger) FuncID() cat.StableID {
+	return ot.funcID
+}
+
+// FuncArgs is part of the cat.Trigger interface.
+func (ot *optTrigger) FuncArgs() tree.Datums {
+	return ot.funcArgs
+}
+
+// Enabled is part of the cat.Trigger interface.
+func (ot *optTrigger) Enabled() bool {
+	return ot.enabled
+}
+
+// optPolicy implements cat.Policy. See that interface for more information on
+// the fields.
+type optPolicy struct {
+	name          tree.Name
+	typ           tree.PolicyType
+	command       tree.PolicyCommand
+	roles         []username.SQLUsername
+	usingExpr     string
+	withCheckExpr string
+}
+
+var _ cat.Policy = &optPolicy{}
+
+// init initializes the optPolicy from the given policy descriptor.
+func (op *optPolicy) init(desc *descpb.PolicyDescriptor) {
+	op.name = tree.Name(desc.Name)
+	op.typ = policyTypeType[desc.Type]
+	op.command = policyCommandType[desc.Command]
+	op.roles = make([]username.SQLUsername, len(desc.RoleNames))
+	for i, role := range desc.RoleNames {
+		op.roles[i] = username.MakeSQLUsernameFromPreNormalizedString(role)
+	}
+	op.usingExpr = desc.UsingExpr
+	op.withCheckExpr = desc.WithCheckExpr
+}
+
+// Name is part of the cat.Policy interface.
+func (op *optPolicy) Name() tree.Name {
+	return op.name
+}
+
+// Type is part of the cat.Policy interface.
+func (op *optPolicy) Type() tree.PolicyType {
+	return op.typ
+}
+
+// Command is part of the cat.Policy interface.
+func (op *optPolicy) Command() tree.PolicyCommand {
+	return op.command
+}
+
+// RoleCount is part of the cat.Policy interface.
+func (op *optPolicy) RoleCount() int {
+	return len(op.roles)
+}
+
+// Role is part of the cat.Policy interface.
+func (op *optPolicy) Role(i int) username.SQLUsername {
+	return op.roles[i]
+}
+
+// UsingExpr is part of the cat.Policy interface.
+func (op *optPolicy) UsingExpr() string {
+	return op.usingExpr
+}
+
+// WithCheckExpr is part of the cat.Policy interface.
+func (op *optPolicy) WithCheckExpr() string {
+	return op.withCheckExpr
+}
+
 // optCheckConstraint implements cat.CheckConstraint. See that interface
 // for more information on the fields.
 type optCheckConstraint struct {
@@ -1936,8 +2196,9 @@ type optUniqueConstraint struct {
 	columns   []descpb.ColumnID
 	predicate string
 
-	withoutIndex bool
-	validity     descpb.ConstraintValidity
+	withoutIndex  bool
+	validity      descpb.ConstraintValidity
+	deferrability tree.ConstraintDeferrability
 
 	uniquenessGuaranteedByAnotherIndex bool
 }
@@ -1995,6 +2256,64 @@ func (u *optUniqueConstraint) UniquenessGuaranteedByAnotherIndex() bool {
 	return u.uniquenessGuaranteedByAnotherIndex
 }
 
+// Deferrability is part of the cat.UniqueConstraint interface.
+func (u *optUniqueConstraint) Deferrability() tree.ConstraintDeferrability {
+	return u.deferrability
+}
+
+// optExclusionConstraint implements cat.ExclusionConstraint and represents an
+// exclusion constraint.
+type optExclusionConstraint struct {
+	name string
+
+	table     cat.StableID
+	columns   []descpb.ColumnID
+	operators []treecmp.ComparisonOperator
+	predicate string
+
+	validity descpb.ConstraintValidity
+}
+
+var _ cat.ExclusionConstraint = &optExclusionConstraint{}
+
+// Name is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Name() string {
+	return e.name
+}
+
+// ColumnCount is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) ColumnCount() int {
+	return len(e.columns)
+}
+
+// ColumnOrdinal is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) ColumnOrdinal(tab cat.Table, i int) int {
+	if tab.ID() != e.table {
+		panic(errors.AssertionFailedf(
+			"invalid table %d passed to ColumnOrdinal (expected %d)",
+			tab.ID(), e.table,
+		))
+	}
+	optTab := convertTableToOptTable(tab)
+	ord, _ := optTab.lookupColumnOrdinal(e.columns[i])
+	return ord
+}
+
+// Operator is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Operator(i int) treecmp.ComparisonOperator {
+	return e.operators[i]
+}
+
+// Predicate is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Predicate() (string, bool) {
+	return e.predicate, e.predicate != ""
+}
+
+// Validated is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Validated() bool {
+	return e.validity == descpb.ConstraintValidity_Validated
+}
+
 // optForeignKeyConstraint implements cat.ForeignKeyConstraint and represents a
 // foreign key relationship. Both the origin and the referenced table store the
 // same optForeignKeyConstraint (as an outbound and inbound reference,
@@ -2008,10 +2327,11 @@ type optForeignKeyConstraint struct {
 	referencedTable   cat.StableID
 	referencedColumns []descpb.ColumnID
 
-	validity     descpb.ConstraintValidity
-	match        tree.CompositeKeyMatchMethod
-	deleteAction tree.ReferenceAction
-	updateAction tree.ReferenceAction
+	validity      descpb.ConstraintValidity
+	match         tree.CompositeKeyMatchMethod
+	deleteAction  tree.ReferenceAction
+	updateAction  tree.ReferenceAction
+	deferrability tree.ConstraintDeferrability
 }
 
 var _ cat.ForeignKeyConstraint = &optForeignKeyConstraint{}
@@ -2083,6 +2403,11 @@ func (fk *optForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction
 	return fk.updateAction
 }
 
+// Deferrability is part of the cat.ForeignKeyConstraint interface.
+func (fk *optForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	return fk.deferrability
+}
+
 // optVirtualTable is similar to optTable but is used with virtual tables.
 type optVirtualTable struct {
 	desc catalog.TableDescriptor
@@ -2345,6 +2670,36 @@ func (ot *optVirtualTable) Check(i int) cat.CheckConstraint {
 	}
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (ot *optVirtualTable) TriggerCount() int {
+	return 0
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optVirtualTable) Trigger(i int) cat.Trigger {
+	panic(errors.AssertionFailedf("no triggers"))
+}
+
+// IsRowLevelSecurityEnabled is part of the cat.Table interface.
+func (ot *optVirtualTable) IsRowLevelSecurityEnabled() bool {
+	return false
+}
+
+// IsRowLevelSecurityForced is part of the cat.Table interface.
+func (ot *optVirtualTable) IsRowLevelSecurityForced() bool {
+	return false
+}
+
+// PolicyCount is part of the cat.Table interface.
+func (ot *optVirtualTable) PolicyCount() int {
+	return 0
+}
+
+// Policy is part of the cat.Table interface.
+func (ot *optVirtualTable) Policy(i int) cat.Policy {
+	panic(errors.AssertionFailedf("no policies"))
+}
+
 // FamilyCount is part of the cat.Table interface.
 func (ot *optVirtualTable) FamilyCount() int {
 	return 1
@@ -2385,6 +2740,16 @@ func (ot *optVirtualTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	panic(errors.AssertionFailedf("no unique constraints"))
 }
 
+// ExclusionCount is part of the cat.Table interface.
+func (ot *optVirtualTable) ExclusionCount() int {
+	return 0
+}
+
+// Exclusion is part of the cat.Table interface.
+func (ot *optVirtualTable) Exclusion(i int) cat.ExclusionConstraint {
+	panic(errors.AssertionFailedf("no exclusion constraints"))
+}
+
 // Zone is part of the cat.Table interface.
 func (ot *optVirtualTable) Zone() cat.Zone {
 	panic(errors.AssertionFailedf("no zone"))
@@ -2698,7 +3063,7 @@ func collectTypes(col catalog.Column) (descpb.IDs, error) {
 		}
 	}
 	if typ := col.GetType(); typ != nil && typ.UserDefined() {
-		visitor.OIDs[typ.Oid()] = struct{}{}
+		visitor.OIDs[typ.UserDefinedTypeOID()] = struct{}{}
 	}
 
 	ids := make(descpb.IDs, 0, len(visitor.OIDs))
@@ -171,6 +171,7 @@ func (ef *execFactory) ConstructScan(
 	scan.lockingWaitPolicy = descpb.ToScanLockingWaitPolicy(params.Locking.WaitPolicy)
 	scan.lockingDurability = descpb.ToScanLockingDurability(params.Locking.Durability)
 	scan.localityOptimized = params.LocalityOptimized
+	scan.sample = params.Sample
 	if !ef.isExplain && !ef.planner.SessionData().Internal {
 		idxUsageKey := roachpb.IndexUsageKey{
 			TableID: roachpb.TableID(tabDesc.GetID()),
@@ -532,6 +533,40 @@ func (ef *execFactory) ConstructGroupBy(
 	return n, nil
 }
 
+// ConstructGroupingSets is part of the exec.Factory interface.
+func (ef *execFactory) ConstructGroupingSets(
+	input exec.Node,
+	groupCols []exec.NodeColumnOrdinal,
+	groupingSets []exec.NodeColumnOrdinalSet,
+	aggregations []exec.AggInfo,
+) (exec.Node, error) {
+	inputPlan := input.(planNode)
+	inputCols := planColumns(inputPlan)
+	n := &groupNode{
+		plan:         inputPlan,
+		funcs:        make([]*aggregateFuncHolder, 0, len(groupCols)+len(aggregations)),
+		columns:      getResultColumnsForGroupingSets(inputCols, groupCols, aggregations),
+		groupCols:    convertNodeOrdinalsToInts(groupCols),
+		groupingSets: make([][]int, len(groupingSets)),
+	}
+	for i, set := range groupingSets {
+		n.groupingSets[i] = set.Ordered()
+	}
+	for _, col := range n.groupCols {
+		f := newAggregateFuncHolder(
+			builtins.AnyNotNull,
+			[]int{col},
+			nil,   /* arguments */
+			false, /* isDistinct */
+		)
+		n.funcs = append(n.funcs, f)
+	}
+	if err := ef.addAggregations(n, aggregations); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
 func (ef *execFactory) addAggregations(n *groupNode, aggregations []exec.AggInfo) error {
 	for i := range aggregations {
 		agg := &aggregations[i]
@@ -544,6 +579,7 @@ func (ef *execFactory) addAggregations(n *groupNode, aggregations []exec.AggInfo
 			agg.Distinct,
 		)
 		f.filterRenderIdx = int(agg.Filter)
+		f.userDefined = agg.UserDefined
 
 		n.funcs = append(n.funcs, f)
 	}
@@ -1153,6 +1189,9 @@ func (ef *execFactory) ConstructWindow(root exec.Node, wi exec.WindowInfo) (exec
 			columnOrdering: wi.Ordering,
 			frame:          wi.Exprs[i].WindowDef.Frame,
 		}
+		if wi.UserDefinedAggs != nil {
+			p.funcs[i].userDefined = wi.UserDefinedAggs[i]
+		}
 		if len(wi.Ordering) == 0 {
 			frame := p.funcs[i].frame
 			if frame.Mode == treewindow.RANGE && frame.Bounds.HasOffset() {
@@ -1619,6 +1658,7 @@ func (ef *execFactory) ConstructUpsert(
 	arbiterIndexes cat.IndexOrdinals,
 	arbiterConstraints cat.UniqueOrdinals,
 	canaryCol exec.NodeColumnOrdinal,
+	mergeActionCol exec.NodeColumnOrdinal,
 	insertColOrdSet exec.TableColumnOrdinalSet,
 	fetchColOrdSet exec.TableColumnOrdinalSet,
 	updateColOrdSet exec.TableColumnOrdinalSet,
@@ -1668,6 +1708,20 @@ func (ef *execFactory) ConstructUpsert(
 		return nil, err
 	}
 
+	// Create the table deleter if this upsert implements a MERGE statement,
+	// which may delete existing rows.
+	var rd row.Deleter
+	if mergeActionCol != -1 {
+		rd = row.MakeDeleter(
+			ef.planner.ExecCfg().Codec,
+			tabDesc,
+			fetchCols,
+			&ef.planner.ExecCfg().Settings.SV,
+			internal,
+			ef.planner.ExecCfg().GetRowMetrics(internal),
+		)
+	}
+
 	// Instantiate the upsert node.
 	ups := upsertNodePool.Get().(*upsertNode)
 	*ups = upsertNode{
@@ -1676,11 +1730,13 @@ func (ef *execFactory) ConstructUpsert(
 			checkOrds:  checks,
 			insertCols: ri.InsertCols,
 			tw: optTableUpserter{
-				ri:            ri,
-				canaryOrdinal: int(canaryCol),
-				fetchCols:     fetchCols,
-				updateCols:    updateCols,
-				ru:            ru,
+				ri:                 ri,
+				canaryOrdinal:      int(canaryCol),
+				mergeActionOrdinal: int(mergeActionCol),
+				fetchCols:          fetchCols,
+				updateCols:         updateCols,
+				ru:                 ru,
+				rd:                 rd,
 			},
 		},
 	}
@@ -2187,7 +2243,16 @@ func (ef *execFactory) ConstructExplain(
 
 // ConstructCall is part of the exec.Factory interface.
 func (e *execFactory) ConstructCall(proc *tree.RoutineExpr) (exec.Node, error) {
-	return &callNode{proc: proc}, nil
+	n := &callNode{proc: proc}
+	if typ := proc.ResolvedType(); typ.Family() == types.TupleFamily {
+		// The procedure has output parameters, which are returned as a single
+		// row with a column for each parameter.
+		n.columns = make(colinfo.ResultColumns, len(typ.TupleContents()))
+		for i := range n.columns {
+			n.columns[i] = colinfo.ResultColumn{Name: typ.TupleLabels()[i], Typ: typ.TupleContents()[i]}
+		}
+	}
+	return n, nil
 }
 
 // renderBuilder encapsulates the code to build a renderNode.
@@ -206,6 +206,11 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE TYPE blah AS ENUM ??`, `CREATE TYPE`},
 		{`DROP TYPE ??`, `DROP TYPE`},
 
+		{`CREATE DOMAIN ??`, `CREATE DOMAIN`},
+		{`ALTER DOMAIN ??`, `ALTER DOMAIN`},
+		{`ALTER DOMAIN d ADD ??`, `ALTER DOMAIN`},
+		{`DROP DOMAIN ??`, `DROP DOMAIN`},
+
 		{`CREATE SCHEMA IF ??`, `CREATE SCHEMA`},
 		{`CREATE SCHEMA IF NOT ??`, `CREATE SCHEMA`},
 		{`CREATE SCHEMA bli ??`, `CREATE SCHEMA`},
@@ -309,6 +314,10 @@ func TestContextualHelp(t *testing.T) {
 		{`UPSERT INTO blah VALUES (1) ??`, `VALUES`},
 		{`UPSERT INTO blah TABLE foo ??`, `TABLE`},
 
+		{`MERGE ??`, `MERGE`},
+		{`MERGE INTO blah USING foo ON true WHEN ??`, `MERGE`},
+		{`MERGE INTO blah USING foo ON true WHEN MATCHED THEN ??`, `MERGE`},
+
 		{`UPDATE blah ??`, `UPDATE`},
 		{`UPDATE blah SET ??`, `UPDATE`},
 		{`UPDATE blah SET x = 3 WHERE true ??`, `UPDATE`},
@@ -516,6 +525,9 @@ func TestContextualHelp(t *testing.T) {
 		{`SET LOCAL TIME ??`, `SET LOCAL`},
 		{`SET LOCAL TIME ZONE 'UTC' ??`, `SET LOCAL`},
 
+		{`SET CONSTRAINTS ??`, `SET CONSTRAINTS`},
+		{`SET CONSTRAINTS ALL ??`, `SET CONSTRAINTS`},
+
 		{`SET TRANSACTION ??`, `SET TRANSACTION`},
 		{`SET TRANSACTION ISOLATION LEVEL SNAPSHOT ??`, `SET TRANSACTION`},
 		{`SET TIME ??`, `SET SESSION`},
@@ -581,6 +593,33 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE PROCEDURE ??`, `CREATE PROCEDURE`},
 		{`ALTER PROCEDURE ??`, `ALTER PROCEDURE`},
 		{`DROP PROCEDURE ??`, `DROP PROCEDURE`},
+
+		{`CREATE AGGREGATE ??`, `CREATE AGGREGATE`},
+		{`ALTER AGGREGATE ??`, `ALTER AGGREGATE`},
+		{`DROP AGGREGATE ??`, `DROP AGGREGATE`},
+
+		{`CREATE TRIGGER ??`, `CREATE TRIGGER`},
+		{`DROP TRIGGER ??`, `DROP TRIGGER`},
+
+		{`CREATE POLICY ??`, `CREATE POLICY`},
+		{`DROP POLICY ??`, `DROP POLICY`},
+
+		{`CREATE TEXT SEARCH CONFIGURATION ??`, `CREATE TEXT SEARCH CONFIGURATION`},
+		{`CREATE TEXT SEARCH CONFIGURATION c (??`, `CREATE TEXT SEARCH CONFIGURATION`},
+		{`CREATE TEXT SEARCH DICTIONARY ??`, `CREATE TEXT SEARCH DICTIONARY`},
+		{`ALTER TEXT SEARCH CONFIGURATION ??`, `ALTER TEXT SEARCH CONFIGURATION`},
+		{`ALTER TEXT SEARCH CONFIGURATION c ADD MAPPING ??`, `ALTER TEXT SEARCH CONFIGURATION`},
+		{`ALTER TEXT SEARCH DICTIONARY ??`, `ALTER TEXT SEARCH DICTIONARY`},
+		{`DROP TEXT SEARCH CONFIGURATION ??`, `DROP TEXT SEARCH CONFIGURATION`},
+		{`DROP TEXT SEARCH DICTIONARY ??`, `DROP TEXT SEARCH DICTIONARY`},
+
+		{`CREATE PUBLICATION ??`, `CREATE PUBLICATION`},
+		{`CREATE PUBLICATION p FOR ??`, `CREATE PUBLICATION`},
+		{`DROP PUBLICATION ??`, `DROP PUBLICATION`},
+		{`CREATE SUBSCRIPTION ??`, `CREATE SUBSCRIPTION`},
+		{`CREATE SUBSCRIPTION s CONNECTION 'uri' ??`, `CREATE SUBSCRIPTION`},
+		{`DROP SUBSCRIPTION ??`, `DROP SUBSCRIPTION`},
+		{`SHOW SUBSCRIPTIONS ??`, `SHOW SUBSCRIPTIONS`},
 	}
 
 	// The following checks that the test definition above exercises all
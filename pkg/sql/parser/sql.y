@@ -306,6 +306,27 @@ func (u *sqlSymUnion) enumValueList() tree.EnumValueList {
 func (u *sqlSymUnion) compositeTypeList() []tree.CompositeTypeElem {
     return u.val.([]tree.CompositeTypeElem)
 }
+func (u *sqlSymUnion) domainConstraint() tree.DomainConstraint {
+    return u.val.(tree.DomainConstraint)
+}
+func (u *sqlSymUnion) domainConstraints() []tree.DomainConstraint {
+    if v, ok := u.val.([]tree.DomainConstraint); ok {
+        return v
+    }
+    return nil
+}
+func (u *sqlSymUnion) alterDomainCmd() tree.AlterDomainCmd {
+    return u.val.(tree.AlterDomainCmd)
+}
+func (u *sqlSymUnion) mergeWhen() *tree.MergeWhen {
+    return u.val.(*tree.MergeWhen)
+}
+func (u *sqlSymUnion) mergeWhens() tree.MergeWhens {
+    return u.val.(tree.MergeWhens)
+}
+func (u *sqlSymUnion) mergeAction() tree.MergeAction {
+    return u.val.(tree.MergeAction)
+}
 func (u *sqlSymUnion) unresolvedName() *tree.UnresolvedName {
     return u.val.(*tree.UnresolvedName)
 }
@@ -324,6 +345,9 @@ func (u *sqlSymUnion) tableNames() tree.TableNames {
 func (u *sqlSymUnion) indexFlags() *tree.IndexFlags {
     return u.val.(*tree.IndexFlags)
 }
+func (u *sqlSymUnion) tableSample() *tree.TableSample {
+    return u.val.(*tree.TableSample)
+}
 func (u *sqlSymUnion) arraySubscript() *tree.ArraySubscript {
     return u.val.(*tree.ArraySubscript)
 }
@@ -639,6 +663,12 @@ func (u *sqlSymUnion) idxElem() tree.IndexElem {
 func (u *sqlSymUnion) idxElems() tree.IndexElemList {
     return u.val.(tree.IndexElemList)
 }
+func (u *sqlSymUnion) exclusionConstraintElem() tree.ExclusionConstraintElem {
+    return u.val.(tree.ExclusionConstraintElem)
+}
+func (u *sqlSymUnion) exclusionConstraintElems() tree.ExclusionConstraintElems {
+    return u.val.(tree.ExclusionConstraintElems)
+}
 func (u *sqlSymUnion) indexInvisibility() tree.IndexInvisibility {
     return u.val.(tree.IndexInvisibility)
 }
@@ -732,6 +762,9 @@ func (u *sqlSymUnion) transactionModes() tree.TransactionModes {
 func (u *sqlSymUnion) compositeKeyMatchMethod() tree.CompositeKeyMatchMethod {
   return u.val.(tree.CompositeKeyMatchMethod)
 }
+func (u *sqlSymUnion) constraintDeferrability() tree.ConstraintDeferrability {
+    return u.val.(tree.ConstraintDeferrability)
+}
 func (u *sqlSymUnion) referenceAction() tree.ReferenceAction {
     return u.val.(tree.ReferenceAction)
 }
@@ -850,6 +883,12 @@ func (u *sqlSymUnion) routineParam() tree.RoutineParam {
 func (u *sqlSymUnion) routineParamClass() tree.RoutineParamClass {
     return u.val.(tree.RoutineParamClass)
 }
+func (u *sqlSymUnion) aggregateOptions() tree.AggregateOptions {
+    return u.val.(tree.AggregateOptions)
+}
+func (u *sqlSymUnion) aggregateOption() tree.AggregateOption {
+    return u.val.(tree.AggregateOption)
+}
 func (u *sqlSymUnion) stmts() tree.Statements {
     return u.val.(tree.Statements)
 }
@@ -889,6 +928,24 @@ func (u *sqlSymUnion) beginTransaction() *tree.BeginTransaction {
 func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
     return u.val.(*tree.ShowFingerprintOptions)
 }
+func (u *sqlSymUnion) triggerActionTime() tree.TriggerActionTime {
+    return u.val.(tree.TriggerActionTime)
+}
+func (u *sqlSymUnion) triggerEvent() *tree.TriggerEvent {
+    return u.val.(*tree.TriggerEvent)
+}
+func (u *sqlSymUnion) triggerEvents() []*tree.TriggerEvent {
+    return u.val.([]*tree.TriggerEvent)
+}
+func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
+    return u.val.(tree.TriggerForEach)
+}
+func (u *sqlSymUnion) policyType() tree.PolicyType {
+    return u.val.(tree.PolicyType)
+}
+func (u *sqlSymUnion) policyCommand() tree.PolicyCommand {
+    return u.val.(tree.PolicyCommand)
+}
 %}
 
 // NB: the %token definitions must come before the %type definitions in this
@@ -908,13 +965,13 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 // below; search this file for "Keyword category lists".
 
 // Ordinary key words in alphabetical order.
-%token <str> ABORT ABSOLUTE ACCESS ACTION ADD ADMIN AFTER AGGREGATE
+%token <str> ABORT ABSOLUTE ACCESS ACTION ADD ADJACENT ADMIN AFTER AGGREGATE
 %token <str> ALL ALTER ALWAYS ANALYSE ANALYZE AND AND_AND ANY ANNOTATE_TYPE ARRAY AS ASC AS_JSON AT_AT
 %token <str> ASENSITIVE ASYMMETRIC AT ATOMIC ATTRIBUTE AUTHORIZATION AUTOMATIC AVAILABILITY
 
 %token <str> BACKUP BACKUPS BACKWARD BATCH BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
 %token <str> BUCKET_COUNT
-%token <str> BOOLEAN BOTH BOX2D BUNDLE BY
+%token <str> BOOLEAN BOTH BOX2D BUNDLE BY BYPASSRLS
 
 %token <str> CACHE CALL CALLED CANCEL CANCELQUERY CAPABILITIES CAPABILITY CASCADE CASE CAST CBRT CHANGEFEED CHAR
 %token <str> CHARACTER CHARACTERISTICS CHECK CHECK_FILES CLOSE
@@ -928,9 +985,9 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 
 %token <str> DATA DATABASE DATABASES DATE DAY DEBUG_IDS DEBUG_PAUSE_ON DEC DEBUG_DUMP_METADATA_SST DECIMAL DEFAULT DEFAULTS DEFINER
 %token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DELIMITER DEPENDS DESC DESTINATION DETACHED DETAILS
-%token <str> DISCARD DISTINCT DO DOMAIN DOUBLE DROP
+%token <str> DICTIONARY DISABLE DISCARD DISTINCT DO DOMAIN DOUBLE DROP
 
-%token <str> ELSE ENCODING ENCRYPTED ENCRYPTION_INFO_DIR ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
+%token <str> EACH ELSE ENABLE ENCODING ENCRYPTED ENCRYPTION_INFO_DIR ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
 %token <str> EXISTS EXECUTE EXECUTION EXPERIMENTAL
 %token <str> EXPERIMENTAL_FINGERPRINTS EXPERIMENTAL_REPLICA
 %token <str> EXPERIMENTAL_AUDIT EXPERIMENTAL_RELOCATE
@@ -951,38 +1008,39 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %token <str> IDENTITY
 %token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMMEDIATELY IMMUTABLE IMPORT IN INCLUDE
 %token <str> INCLUDING INCLUDE_ALL_SECONDARY_TENANTS INCLUDE_ALL_VIRTUAL_CLUSTERS INCREMENT INCREMENTAL INCREMENTAL_LOCATION
+%token <str> INCREMENTALLY
 %token <str> INET INET_CONTAINED_BY_OR_EQUALS
 %token <str> INET_CONTAINS_OR_EQUALS INDEX INDEXES INHERITS INJECT INITIALLY
 %token <str> INDEX_BEFORE_PAREN INDEX_BEFORE_NAME_THEN_PAREN INDEX_AFTER_ORDER_BY_BEFORE_AT
-%token <str> INNER INOUT INPUT INSENSITIVE INSERT INT INTEGER
+%token <str> INNER INOUT INPUT INSENSITIVE INSERT INSTEAD INT INTEGER
 %token <str> INTERSECT INTERVAL INTO INTO_DB INVERTED INVOKER IS ISERROR ISNULL ISOLATION
 
-%token <str> JOB JOBS JOIN JSON JSONB JSON_SOME_EXISTS JSON_ALL_EXISTS
+%token <str> JOB JOBS JOIN JSON JSONB JSON_SOME_EXISTS JSON_ALL_EXISTS JSON_PATH_EXISTS
 
 %token <str> KEY KEYS KMS KV
 
 %token <str> LABEL LANGUAGE LAST LATERAL LATEST LC_CTYPE LC_COLLATE
 %token <str> LEADING LEASE LEAST LEAKPROOF LEFT LESS LEVEL LIKE LIMIT
 %token <str> LINESTRING LINESTRINGM LINESTRINGZ LINESTRINGZM
-%token <str> LIST LOCAL LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
+%token <str> LIST LISTEN LOCAL LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
 
-%token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MODIFYSQLCLUSTERSETTING MONTH MOVE
+%token <str> MAPPING MATCH MATCHED MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MODIFYSQLCLUSTERSETTING MONTH MOVE
 %token <str> MULTILINESTRING MULTILINESTRINGM MULTILINESTRINGZ MULTILINESTRINGZM
 %token <str> MULTIPOINT MULTIPOINTM MULTIPOINTZ MULTIPOINTZM
 %token <str> MULTIPOLYGON MULTIPOLYGONM MULTIPOLYGONZ MULTIPOLYGONZM
 
-%token <str> NAN NAME NAMES NATURAL NEVER NEW_DB_NAME NEW_KMS NEXT NO NOCANCELQUERY NOCONTROLCHANGEFEED
+%token <str> NAN NAME NAMES NATURAL NEVER NEW_DB_NAME NEW_KMS NEXT NO NOBYPASSRLS NOCANCELQUERY NOCONTROLCHANGEFEED
 %token <str> NOCONTROLJOB NOCREATEDB NOCREATELOGIN NOCREATEROLE NOLOGIN NOMODIFYCLUSTERSETTING NOREPLICATION
 %token <str> NOSQLLOGIN NO_INDEX_JOIN NO_ZIGZAG_JOIN NO_FULL_SCAN NONE NONVOTERS NORMAL NOT
-%token <str> NOTHING NOTHING_AFTER_RETURNING
+%token <str> NOTHING NOTHING_AFTER_RETURNING NOTIFY
 %token <str> NOTNULL
 %token <str> NOVIEWACTIVITY NOVIEWACTIVITYREDACTED NOVIEWCLUSTERSETTING NOWAIT NULL NULLIF NULLS NUMERIC
 
 %token <str> OF OFF OFFSET OID OIDS OIDVECTOR OLD_KMS ON ONLY OPT OPTION OPTIONS OR
 %token <str> ORDER ORDINALITY OTHERS OUT OUTER OVER OVERLAPS OVERLAY OWNED OWNER OPERATOR
 
-%token <str> PARALLEL PARENT PARTIAL PARTITION PARTITIONS PASSWORD PAUSE PAUSED PHYSICAL PLACEMENT PLACING
-%token <str> PLAN PLANS POINT POINTM POINTZ POINTZM POLYGON POLYGONM POLYGONZ POLYGONZM
+%token <str> PARALLEL PARENT PARSER PARTIAL PARTITION PARTITIONS PASSWORD PAUSE PAUSED PERMISSIVE PHYSICAL PLACEMENT PLACING
+%token <str> PLAN PLANS POINT POINTM POINTZ POINTZM POLICY POLYGON POLYGONM POLYGONZ POLYGONZM
 %token <str> POSITION PRECEDING PRECISION PREPARE PRESERVE PRIMARY PRIOR PRIORITY PRIVILEGES
 %token <str> PROCEDURAL PROCEDURE PROCEDURES PUBLIC PUBLICATION
 
@@ -991,7 +1049,7 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %token <str> RANGE RANGES READ REAL REASON REASSIGN RECURSIVE RECURRING REDACT REF REFERENCES REFRESH
 %token <str> REGCLASS REGION REGIONAL REGIONS REGNAMESPACE REGPROC REGPROCEDURE REGROLE REGTYPE REINDEX
 %token <str> RELATIVE RELOCATE REMOVE_PATH REMOVE_REGIONS RENAME REPEATABLE REPLACE REPLICATION
-%token <str> RELEASE RESET RESTART RESTORE RESTRICT RESTRICTED RESUME RETENTION RETURNING RETURN RETURNS RETRY REVISION_HISTORY
+%token <str> RELEASE RESET RESTART RESTORE RESTRICT RESTRICTED RESTRICTIVE RESUME RETENTION RETURNING RETURN RETURNS RETRY REVISION_HISTORY
 %token <str> REVOKE RIGHT ROLE ROLES ROLLBACK ROLLUP ROUTINES ROW ROWS RSHIFT RULE RUNNING
 
 %token <str> SAVEPOINT SCANS SCATTER SCHEDULE SCHEDULES SCROLL SCHEMA SCHEMA_ONLY SCHEMAS SCRUB
@@ -1000,10 +1058,10 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %token <str> SHARE SHARED SHOW SIMILAR SIMPLE SIZE SKIP SKIP_LOCALITIES_CHECK SKIP_MISSING_FOREIGN_KEYS
 %token <str> SKIP_MISSING_SEQUENCES SKIP_MISSING_SEQUENCE_OWNERS SKIP_MISSING_VIEWS SKIP_MISSING_UDFS SMALLINT SMALLSERIAL
 %token <str> SNAPSHOT SOME SPLIT SQL SQLLOGIN
-%token <str> STABLE START STATE STATISTICS STATUS STDIN STDOUT STOP STREAM STRICT STRING STORAGE STORE STORED STORING SUBSTRING SUPER
-%token <str> SUPPORT SURVIVE SURVIVAL SYMMETRIC SYNTAX SYSTEM SQRT SUBSCRIPTION STATEMENTS
+%token <str> STABLE START STATE STATEMENT STATISTICS STATUS STDIN STDOUT STOP STREAM STRICT STRING STORAGE STORE STORED STORING SUBSTRING SUPER
+%token <str> SUPPORT SURVIVE SURVIVAL SYMMETRIC SYNTAX SYSTEM SQRT SUBSCRIPTION SUBSCRIPTIONS STATEMENTS
 
-%token <str> TABLE TABLES TABLESPACE TEMP TEMPLATE TEMPORARY TENANT TENANT_NAME TENANTS TESTING_RELOCATE TEXT THEN
+%token <str> TABLE TABLES TABLESAMPLE TABLESPACE TEMP TEMPLATE TEMPORARY TENANT TENANT_NAME TENANTS TESTING_RELOCATE TEXT THEN
 %token <str> TIES TIME TIMETZ TIMESTAMP TIMESTAMPTZ TO THROTTLING TRAILING TRACE
 %token <str> TRANSACTION TRANSACTIONS TRANSFER TRANSFORM TREAT TRIGGER TRIM TRUE
 %token <str> TRUNCATE TRUSTED TYPE TYPES
@@ -1069,8 +1127,11 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <tree.Statement> alter_role_stmt
 %type <*tree.SetVar> set_or_reset_clause
 %type <tree.Statement> alter_type_stmt
+%type <tree.Statement> alter_domain_stmt
 %type <tree.Statement> alter_schema_stmt
-%type <tree.Statement> alter_unsupported_stmt
+%type <tree.Statement> alter_aggregate_stmt
+%type <tree.Statement> alter_text_search_config_stmt
+%type <tree.Statement> alter_text_search_dict_stmt
 %type <tree.Statement> alter_func_stmt
 %type <tree.Statement> alter_proc_stmt
 
@@ -1206,15 +1267,36 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <tree.Statement> create_sequence_stmt
 %type <tree.Statement> create_func_stmt
 %type <tree.Statement> create_proc_stmt
+%type <tree.Statement> create_trigger_stmt
+%type <tree.Statement> create_aggregate_stmt
+%type <tree.Statement> create_text_search_config_stmt
+%type <tree.Statement> create_text_search_dict_stmt
+%type <tree.Statement> create_policy_stmt
+%type <tree.Statement> create_publication_stmt
+%type <tree.Statement> create_subscription_stmt
 
 %type <*tree.LikeTenantSpec> opt_like_virtual_cluster
 
+%type <tree.TriggerActionTime> trigger_action_time
+%type <*tree.TriggerEvent> trigger_event
+%type <[]*tree.TriggerEvent> trigger_event_list
+%type <tree.TriggerForEach> opt_trigger_for_each
+
+%type <tree.PolicyType> opt_policy_type
+%type <tree.PolicyCommand> opt_policy_command
+%type <tree.RoleSpecList> opt_policy_roles
+%type <tree.Expr> opt_policy_using opt_policy_with_check
+%type <tree.Expr> opt_trigger_when
+%type <[]string> opt_trigger_func_args trigger_func_args
+%type <str> trigger_func_arg
+
 %type <tree.Statement> create_stats_stmt
 %type <*tree.CreateStatsOptions> opt_create_stats_options
 %type <*tree.CreateStatsOptions> create_stats_option_list
 %type <*tree.CreateStatsOptions> create_stats_option
 
 %type <tree.Statement> create_type_stmt
+%type <tree.Statement> create_domain_stmt
 %type <tree.Statement> delete_stmt
 %type <tree.Statement> discard_stmt
 
@@ -1227,10 +1309,18 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <tree.Statement> drop_schema_stmt
 %type <tree.Statement> drop_table_stmt
 %type <tree.Statement> drop_type_stmt
+%type <tree.Statement> drop_domain_stmt
 %type <tree.Statement> drop_view_stmt
 %type <tree.Statement> drop_sequence_stmt
 %type <tree.Statement> drop_func_stmt
 %type <tree.Statement> drop_proc_stmt
+%type <tree.Statement> drop_trigger_stmt
+%type <tree.Statement> drop_aggregate_stmt
+%type <tree.Statement> drop_text_search_config_stmt
+%type <tree.Statement> drop_text_search_dict_stmt
+%type <tree.Statement> drop_policy_stmt
+%type <tree.Statement> drop_publication_stmt
+%type <tree.Statement> drop_subscription_stmt
 %type <tree.Statement> drop_virtual_cluster_stmt
 %type <bool>           opt_immediate
 
@@ -1270,6 +1360,7 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <tree.Statement> set_session_stmt
 %type <tree.Statement> set_csetting_stmt set_or_reset_csetting_stmt
 %type <tree.Statement> set_transaction_stmt
+%type <tree.Statement> set_constraints_stmt
 %type <tree.Statement> set_exprs_internal
 %type <tree.Statement> generic_set
 %type <tree.Statement> set_rest_more
@@ -1308,6 +1399,7 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <tree.Statement> show_sequences_stmt
 %type <tree.Statement> show_session_stmt
 %type <tree.Statement> show_sessions_stmt
+%type <tree.Statement> show_subscriptions_stmt
 %type <tree.Statement> show_savepoint_stmt
 %type <tree.Statement> show_stats_stmt
 %type <tree.Statement> show_syntax_stmt
@@ -1332,6 +1424,9 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 
 %type <tree.Statement> transaction_stmt legacy_transaction_stmt legacy_begin_stmt legacy_end_stmt
 %type <tree.Statement> truncate_stmt
+%type <tree.Statement> listen_stmt
+%type <tree.Statement> merge_stmt
+%type <tree.Statement> notify_stmt
 %type <tree.Statement> unlisten_stmt
 %type <tree.Statement> update_stmt
 %type <tree.Statement> upsert_stmt
@@ -1458,7 +1553,7 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <[]tree.RangePartition> range_partitions
 %type <empty> opt_all_clause
 %type <empty> opt_privileges_clause
-%type <bool> distinct_clause opt_with_data
+%type <bool> distinct_clause opt_with_data opt_create_as_data
 %type <tree.DistinctOn> distinct_on_clause
 %type <tree.NameList> opt_column_list insert_column_list opt_stats_columns query_stats_cols
 // Note that "no index" variants exist to disable custom ORDER BY <index> syntax
@@ -1480,6 +1575,10 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <[]string> session_var_parts
 %type <tree.SelectExprs> opt_target_list target_list
 %type <tree.UpdateExprs> set_clause_list
+%type <tree.MergeWhens> merge_when_list
+%type <*tree.MergeWhen> merge_when_clause
+%type <tree.MergeAction> merge_matched_action merge_not_matched_action
+%type <tree.Expr> opt_merge_when_cond
 %type <*tree.UpdateExpr> set_clause multiple_set_clause
 %type <tree.ArraySubscripts> array_subscripts
 %type <tree.GroupBy> group_clause
@@ -1541,6 +1640,8 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <*tree.IndexFlags> opt_index_flags
 %type <*tree.IndexFlags> index_flags_param
 %type <*tree.IndexFlags> index_flags_param_list
+%type <*tree.TableSample> opt_tablesample_clause
+%type <tree.Expr> opt_repeatable_clause
 %type <tree.Expr> a_expr b_expr c_expr d_expr typed_literal
 %type <tree.Expr> substr_from substr_for
 %type <tree.Expr> in_expr
@@ -1557,8 +1658,12 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <tree.Expr> numeric_only
 %type <tree.AliasClause> alias_clause opt_alias_clause func_alias_clause opt_func_alias_clause
 %type <bool> opt_ordinality opt_compact
+%type <bool> opt_view_recursive
 %type <*tree.Order> sortby sortby_index
 %type <tree.IndexElem> index_elem index_elem_options create_as_param
+%type <tree.ExclusionConstraintElems> exclude_elem_list
+%type <tree.ExclusionConstraintElem> exclude_elem
+%type <str> opt_exclude_access_method
 %type <tree.TableExpr> table_ref numeric_table_ref func_table
 %type <tree.Exprs> rowsfrom_list
 %type <tree.Expr> rowsfrom_item
@@ -1575,6 +1680,8 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <str> explain_option_name
 %type <[]string> explain_option_list opt_enum_val_list enum_val_list
 %type <[]tree.CompositeTypeElem> composite_type_list opt_composite_type_list
+%type <tree.DomainConstraint> domain_constraint domain_constraint_elem
+%type <[]tree.DomainConstraint> opt_domain_constraint_list domain_constraint_list
 
 %type <tree.ResolvableTypeReference> typename simple_typename cast_target
 %type <*types.T> const_typename
@@ -1624,6 +1731,7 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <tree.ColumnQualification> col_qualification_elem create_as_col_qualification_elem
 %type <tree.CompositeKeyMatchMethod> key_match
 %type <tree.ReferenceActions> reference_actions
+%type <tree.ConstraintDeferrability> opt_deferrable
 %type <tree.ReferenceAction> reference_action reference_on_delete reference_on_update
 
 %type <tree.Expr> func_application func_expr_common_subexpr special_function
@@ -1685,12 +1793,16 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 %type <privilege.TargetObjectType> target_object_type
 
 // User defined function relevant components.
-%type <bool> opt_or_replace opt_return_table opt_return_set opt_no
+%type <bool> opt_or_replace opt_return_set opt_no
 %type <str> param_name routine_as
 %type <tree.RoutineParams> opt_routine_param_with_default_list routine_param_with_default_list func_params func_params_list
+%type <tree.RoutineParams> table_func_column_list
+%type <tree.RoutineParam> table_func_column
 %type <tree.RoutineParam> routine_param_with_default routine_param
 %type <tree.ResolvableTypeReference> routine_return_type routine_param_type
 %type <tree.RoutineOptions> opt_create_routine_opt_list create_routine_opt_list alter_func_opt_list
+%type <tree.AggregateOptions> aggregate_option_list
+%type <tree.AggregateOption> aggregate_option
 %type <tree.RoutineOption> create_routine_opt_item common_routine_opt_item
 %type <tree.RoutineParamClass> routine_param_class
 %type <*tree.UnresolvedObjectName> routine_create_name
@@ -1745,11 +1857,11 @@ func (u *sqlSymUnion) showFingerprintOptions() *tree.ShowFingerprintOptions {
 // funny behavior of UNBOUNDED on the SQL standard, though.
 %nonassoc  UNBOUNDED         // ideally should have same precedence as IDENT
 %nonassoc  IDENT NULL PARTITION RANGE ROWS GROUPS PRECEDING FOLLOWING CUBE ROLLUP
-%left      CONCAT FETCHVAL FETCHTEXT FETCHVAL_PATH FETCHTEXT_PATH REMOVE_PATH AT_AT  // multi-character ops
+%left      CONCAT FETCHVAL FETCHTEXT FETCHVAL_PATH FETCHTEXT_PATH REMOVE_PATH AT_AT JSON_PATH_EXISTS  // multi-character ops
 %left      '|'
 %left      '#'
 %left      '&'
-%left      LSHIFT RSHIFT INET_CONTAINS_OR_EQUALS INET_CONTAINED_BY_OR_EQUALS AND_AND SQRT CBRT
+%left      LSHIFT RSHIFT INET_CONTAINS_OR_EQUALS INET_CONTAINED_BY_OR_EQUALS AND_AND ADJACENT SQRT CBRT
 %left      OPERATOR // if changing the last token before OPERATOR, change all instances of %prec <last token>
 %left      '+' '-'
 %left      '*' '/' FLOORDIV '%'
@@ -1814,6 +1926,8 @@ stmt_without_legacy_transaction:
 | fetch_cursor_stmt          // EXTEND WITH HELP: FETCH
 | move_cursor_stmt           // EXTEND WITH HELP: MOVE
 | reindex_stmt
+| listen_stmt
+| notify_stmt
 | unlisten_stmt
 | show_commit_timestamp_stmt // EXTEND WITH HELP: SHOW COMMIT TIMESTAMP
 
@@ -1824,7 +1938,6 @@ alter_stmt:
   alter_ddl_stmt      // help texts in sub-rule
 | alter_role_stmt     // EXTEND WITH HELP: ALTER ROLE
 | alter_virtual_cluster_stmt   /* SKIP DOC */
-| alter_unsupported_stmt
 | ALTER error         // SHOW HELP: ALTER
 
 alter_ddl_stmt:
@@ -1837,11 +1950,15 @@ alter_ddl_stmt:
 | alter_partition_stmt          // EXTEND WITH HELP: ALTER PARTITION
 | alter_schema_stmt             // EXTEND WITH HELP: ALTER SCHEMA
 | alter_type_stmt               // EXTEND WITH HELP: ALTER TYPE
+| alter_domain_stmt             // EXTEND WITH HELP: ALTER DOMAIN
 | alter_default_privileges_stmt // EXTEND WITH HELP: ALTER DEFAULT PRIVILEGES
 | alter_changefeed_stmt         // EXTEND WITH HELP: ALTER CHANGEFEED
 | alter_backup_stmt             // EXTEND WITH HELP: ALTER BACKUP
 | alter_func_stmt               // EXTEND WITH HELP: ALTER FUNCTION
 | alter_proc_stmt               // EXTEND WITH HELP: ALTER PROCEDURE
+| alter_aggregate_stmt          // EXTEND WITH HELP: ALTER AGGREGATE
+| alter_text_search_config_stmt // EXTEND WITH HELP: ALTER TEXT SEARCH CONFIGURATION
+| alter_text_search_dict_stmt   // EXTEND WITH HELP: ALTER TEXT SEARCH DICTIONARY
 | alter_backup_schedule  // EXTEND WITH HELP: ALTER BACKUP SCHEDULE
 
 // %Help: ALTER TABLE - change the definition of a table
@@ -1876,6 +1993,7 @@ alter_ddl_stmt:
 //   ALTER TABLE ... CONFIGURE ZONE <zoneconfig>
 //   ALTER TABLE ... SET SCHEMA <newschemaname>
 //   ALTER TABLE ... SET LOCALITY [REGIONAL BY [TABLE IN <region> | ROW] | GLOBAL]
+//   ALTER TABLE ... {ENABLE | DISABLE | FORCE | NO FORCE} ROW LEVEL SECURITY
 //
 // Column qualifiers:
 //   [CONSTRAINT <constraintname>] {NULL | NOT NULL | UNIQUE | PRIMARY KEY | CHECK (<expr>) | DEFAULT <expr>}
@@ -2871,6 +2989,23 @@ alter_table_cmd:
   {
     $$.val = &tree.AlterTableSetAudit{Mode: $3.auditMode()}
   }
+  // ALTER TABLE <name> { ENABLE | DISABLE | FORCE | NO FORCE } ROW LEVEL SECURITY
+| ENABLE ROW LEVEL SECURITY
+  {
+    $$.val = &tree.AlterTableSetRowLevelSecurity{Mode: tree.RowLevelSecurityEnable}
+  }
+| DISABLE ROW LEVEL SECURITY
+  {
+    $$.val = &tree.AlterTableSetRowLevelSecurity{Mode: tree.RowLevelSecurityDisable}
+  }
+| FORCE ROW LEVEL SECURITY
+  {
+    $$.val = &tree.AlterTableSetRowLevelSecurity{Mode: tree.RowLevelSecurityForce}
+  }
+| NO FORCE ROW LEVEL SECURITY
+  {
+    $$.val = &tree.AlterTableSetRowLevelSecurity{Mode: tree.RowLevelSecurityNoForce}
+  }
   // ALTER TABLE <name> PARTITION BY ...
 | partition_by_table
   {
@@ -2986,6 +3121,91 @@ opt_validate_behavior:
     $$.val = tree.ValidationDefault
   }
 
+// %Help: ALTER DOMAIN - change the definition of a domain.
+// %Category: DDL
+// %Text: ALTER DOMAIN <type_name> <command>
+//
+// Commands:
+//   ALTER DOMAIN ... ADD [ CONSTRAINT <name> ] CHECK (<expr>) [ NOT VALID ]
+//   ALTER DOMAIN ... DROP CONSTRAINT [IF EXISTS] <name> [ CASCADE | RESTRICT ]
+//   ALTER DOMAIN ... VALIDATE CONSTRAINT <name>
+//   ALTER DOMAIN ... { SET | DROP } NOT NULL
+//
+// %SeeAlso: CREATE DOMAIN, DROP DOMAIN
+alter_domain_stmt:
+  ALTER DOMAIN type_name ADD CONSTRAINT constraint_name CHECK '(' a_expr ')' opt_validate_behavior
+  {
+    $$.val = &tree.AlterDomain{
+      Domain: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainAddConstraint{
+        Constraint: tree.DomainConstraint{
+          Name: tree.Name($6),
+          Check: $9.expr(),
+          Nullability: tree.SilentNull,
+        },
+        NotValid: $11.validationBehavior() == tree.ValidationSkip,
+      },
+    }
+  }
+| ALTER DOMAIN type_name ADD CHECK '(' a_expr ')' opt_validate_behavior
+  {
+    $$.val = &tree.AlterDomain{
+      Domain: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainAddConstraint{
+        Constraint: tree.DomainConstraint{
+          Check: $7.expr(),
+          Nullability: tree.SilentNull,
+        },
+        NotValid: $9.validationBehavior() == tree.ValidationSkip,
+      },
+    }
+  }
+| ALTER DOMAIN type_name DROP CONSTRAINT constraint_name opt_drop_behavior
+  {
+    $$.val = &tree.AlterDomain{
+      Domain: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainDropConstraint{
+        Constraint: tree.Name($6),
+        DropBehavior: $7.dropBehavior(),
+      },
+    }
+  }
+| ALTER DOMAIN type_name DROP CONSTRAINT IF EXISTS constraint_name opt_drop_behavior
+  {
+    $$.val = &tree.AlterDomain{
+      Domain: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainDropConstraint{
+        Constraint: tree.Name($8),
+        IfExists: true,
+        DropBehavior: $9.dropBehavior(),
+      },
+    }
+  }
+| ALTER DOMAIN type_name VALIDATE CONSTRAINT constraint_name
+  {
+    $$.val = &tree.AlterDomain{
+      Domain: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainValidateConstraint{
+        Constraint: tree.Name($6),
+      },
+    }
+  }
+| ALTER DOMAIN type_name SET NOT NULL
+  {
+    $$.val = &tree.AlterDomain{
+      Domain: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainSetNotNull{},
+    }
+  }
+| ALTER DOMAIN type_name DROP NOT NULL
+  {
+    $$.val = &tree.AlterDomain{
+      Domain: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainDropNotNull{},
+    }
+  }
+| ALTER DOMAIN error // SHOW HELP: ALTER DOMAIN
+
 // %Help: ALTER TYPE - change the definition of a type.
 // %Category: DDL
 // %Text: ALTER TYPE <typename> <command>
@@ -3157,6 +3377,7 @@ alter_attribute_action:
 // %Category: Misc
 // %Text:
 // REFRESH MATERIALIZED VIEW [CONCURRENTLY] view_name [WITH [NO] DATA]
+// REFRESH MATERIALIZED VIEW [CONCURRENTLY] view_name INCREMENTALLY
 refresh_stmt:
   REFRESH MATERIALIZED VIEW opt_concurrently view_name opt_clear_data
   {
@@ -3166,6 +3387,14 @@ refresh_stmt:
       RefreshDataOption: $6.refreshDataOption(),
     }
   }
+| REFRESH MATERIALIZED VIEW opt_concurrently view_name INCREMENTALLY
+  {
+    $$.val = &tree.RefreshMaterializedView{
+      Name: $5.unresolvedObjectName(),
+      Concurrently: $4.bool(),
+      Incrementally: true,
+    }
+  }
 | REFRESH error // SHOW HELP: REFRESH
 
 opt_clear_data:
@@ -3903,15 +4132,120 @@ import_format:
     $$ = strings.ToUpper($1)
   }
 
-alter_unsupported_stmt:
-  ALTER DOMAIN error
+// %Help: ALTER AGGREGATE - change the definition of an aggregate function
+// %Category: DDL
+// %Text:
+// ALTER AGGREGATE name ( argtype [, ...] ) RENAME TO new_name
+// ALTER AGGREGATE name ( argtype [, ...] ) OWNER TO { new_owner | CURRENT_USER | SESSION_USER }
+// ALTER AGGREGATE name ( argtype [, ...] ) SET SCHEMA new_schema
+// %SeeAlso: CREATE AGGREGATE, DROP AGGREGATE
+alter_aggregate_stmt:
+  ALTER AGGREGATE function_with_paramtypes RENAME TO name
   {
-    return unimplemented(sqllex, "alter domain")
+    $$.val = &tree.AlterRoutineRename{
+      Function: $3.functionObj(),
+      NewName: tree.Name($6),
+      Aggregate: true,
+    }
   }
-| ALTER AGGREGATE error
+| ALTER AGGREGATE function_with_paramtypes OWNER TO role_spec
   {
-    return unimplementedWithIssueDetail(sqllex, 74775, "alter aggregate")
+    $$.val = &tree.AlterRoutineSetOwner{
+      Function: $3.functionObj(),
+      NewOwner: $6.roleSpec(),
+      Aggregate: true,
+    }
+  }
+| ALTER AGGREGATE function_with_paramtypes SET SCHEMA schema_name
+  {
+    $$.val = &tree.AlterRoutineSetSchema{
+      Function: $3.functionObj(),
+      NewSchemaName: tree.Name($6),
+      Aggregate: true,
+    }
   }
+| ALTER AGGREGATE error // SHOW HELP: ALTER AGGREGATE
+
+// %Help: ALTER TEXT SEARCH CONFIGURATION - change the definition of a text search configuration
+// %Category: DDL
+// %Text:
+// ALTER TEXT SEARCH CONFIGURATION <name>
+//     ADD MAPPING FOR <token_type> [, ...] WITH <dictionary_name> [, ...]
+// ALTER TEXT SEARCH CONFIGURATION <name>
+//     ALTER MAPPING FOR <token_type> [, ...] WITH <dictionary_name> [, ...]
+// ALTER TEXT SEARCH CONFIGURATION <name>
+//     ALTER MAPPING [ FOR <token_type> [, ...] ] REPLACE <old_dictionary> WITH <new_dictionary>
+// ALTER TEXT SEARCH CONFIGURATION <name>
+//     DROP MAPPING [ IF EXISTS ] FOR <token_type> [, ...]
+// %SeeAlso: CREATE TEXT SEARCH CONFIGURATION, DROP TEXT SEARCH CONFIGURATION
+alter_text_search_config_stmt:
+  ALTER TEXT SEARCH CONFIGURATION name ADD MAPPING FOR name_list WITH name_list
+  {
+    $$.val = &tree.AlterTextSearchConfig{
+      Name: tree.Name($5),
+      Cmd: tree.AlterTextSearchConfigAddMapping,
+      TokenTypes: $9.nameList(),
+      Dictionaries: $11.nameList(),
+    }
+  }
+| ALTER TEXT SEARCH CONFIGURATION name ALTER MAPPING FOR name_list WITH name_list
+  {
+    $$.val = &tree.AlterTextSearchConfig{
+      Name: tree.Name($5),
+      Cmd: tree.AlterTextSearchConfigAlterMapping,
+      TokenTypes: $9.nameList(),
+      Dictionaries: $11.nameList(),
+    }
+  }
+| ALTER TEXT SEARCH CONFIGURATION name ALTER MAPPING REPLACE name WITH name
+  {
+    $$.val = &tree.AlterTextSearchConfig{
+      Name: tree.Name($5),
+      Cmd: tree.AlterTextSearchConfigReplaceDictionary,
+      OldDictionary: tree.Name($9),
+      NewDictionary: tree.Name($11),
+    }
+  }
+| ALTER TEXT SEARCH CONFIGURATION name ALTER MAPPING FOR name_list REPLACE name WITH name
+  {
+    $$.val = &tree.AlterTextSearchConfig{
+      Name: tree.Name($5),
+      Cmd: tree.AlterTextSearchConfigReplaceDictionary,
+      TokenTypes: $9.nameList(),
+      OldDictionary: tree.Name($11),
+      NewDictionary: tree.Name($13),
+    }
+  }
+| ALTER TEXT SEARCH CONFIGURATION name DROP MAPPING FOR name_list
+  {
+    $$.val = &tree.AlterTextSearchConfig{
+      Name: tree.Name($5),
+      Cmd: tree.AlterTextSearchConfigDropMapping,
+      TokenTypes: $9.nameList(),
+    }
+  }
+| ALTER TEXT SEARCH CONFIGURATION name DROP MAPPING IF EXISTS FOR name_list
+  {
+    $$.val = &tree.AlterTextSearchConfig{
+      Name: tree.Name($5),
+      Cmd: tree.AlterTextSearchConfigDropMapping,
+      TokenTypes: $11.nameList(),
+      IfExists: true,
+    }
+  }
+| ALTER TEXT SEARCH CONFIGURATION error // SHOW HELP: ALTER TEXT SEARCH CONFIGURATION
+
+// %Help: ALTER TEXT SEARCH DICTIONARY - change the definition of a text search dictionary
+// %Category: DDL
+// %Text:
+// ALTER TEXT SEARCH DICTIONARY <name> ( <option> = <value> [, ...] )
+// %SeeAlso: CREATE TEXT SEARCH DICTIONARY, DROP TEXT SEARCH DICTIONARY
+alter_text_search_dict_stmt:
+  ALTER TEXT SEARCH DICTIONARY name '(' storage_parameter_list ')'
+  {
+    $$.val = &tree.AlterTextSearchDictionary{Name: tree.Name($5), Options: $7.storageParams()}
+  }
+| ALTER TEXT SEARCH DICTIONARY error // SHOW HELP: ALTER TEXT SEARCH DICTIONARY
 
 // %Help: IMPORT - load data from file in a distributed manner
 // %Category: CCL
@@ -4487,6 +4821,8 @@ create_stmt:
 | create_ddl_stmt        // help texts in sub-rule
 | create_stats_stmt      // EXTEND WITH HELP: CREATE STATISTICS
 | create_changefeed_stmt // EXTEND WITH HELP: CREATE CHANGEFEED
+| create_publication_stmt  // EXTEND WITH HELP: CREATE PUBLICATION
+| create_subscription_stmt // EXTEND WITH HELP: CREATE SUBSCRIPTION
 | create_extension_stmt  // EXTEND WITH HELP: CREATE EXTENSION
 | create_external_connection_stmt // EXTEND WITH HELP: CREATE EXTERNAL CONNECTION
 | create_virtual_cluster_stmt     // EXTEND WITH HELP: CREATE VIRTUAL CLUSTER
@@ -4636,7 +4972,8 @@ create_extension_stmt:
 // %Text:
 // CREATE [ OR REPLACE ] FUNCTION
 //    name ( [ [ argmode ] [ argname ] argtype [, ...] ] )
-//    [ RETURNS rettype ]
+//    [ RETURNS rettype
+//      | RETURNS TABLE ( column_name column_type [, ...] ) ]
 //  { LANGUAGE lang_name
 //    | { IMMUTABLE | STABLE | VOLATILE }
 //    | [ NOT ] LEAKPROOF
@@ -4646,7 +4983,7 @@ create_extension_stmt:
 // %SeeAlso: WEBDOCS/create-function.html
 create_func_stmt:
   CREATE opt_or_replace FUNCTION routine_create_name '(' opt_routine_param_with_default_list ')'
-  RETURNS opt_return_table opt_return_set routine_return_type
+  RETURNS opt_return_set routine_return_type
   opt_create_routine_opt_list opt_routine_body
   {
     name := $4.unresolvedObjectName().ToRoutineName()
@@ -4656,11 +4993,58 @@ create_func_stmt:
       Name: name,
       Params: $6.routineParams(),
       ReturnType: tree.RoutineReturnType{
-        Type: $11.typeReference(),
-        SetOf: $10.bool(),
+        Type: $10.typeReference(),
+        SetOf: $9.bool(),
       },
-      Options: $12.routineOptions(),
-      RoutineBody: $13.routineBody(),
+      Options: $11.routineOptions(),
+      RoutineBody: $12.routineBody(),
+    }
+  }
+| CREATE opt_or_replace FUNCTION routine_create_name '(' opt_routine_param_with_default_list ')'
+  RETURNS TABLE '(' table_func_column_list ')'
+  opt_create_routine_opt_list opt_routine_body
+  {
+    // RETURNS TABLE is shorthand for OUT parameters and RETURNS SETOF RECORD,
+    // or RETURNS SETOF the column type if there is only one column.
+    params := $6.routineParams()
+    for _, param := range params {
+      if param.IsOutParam() {
+        return setErr(sqllex, pgerror.New(pgcode.InvalidFunctionDefinition,
+          "OUT and INOUT arguments aren't allowed in TABLE functions"))
+      }
+    }
+    cols := $11.routineParams()
+    var returnType tree.ResolvableTypeReference = types.AnyTuple
+    if len(cols) == 1 {
+      returnType = cols[0].Type
+    }
+    name := $4.unresolvedObjectName().ToRoutineName()
+    $$.val = &tree.CreateRoutine{
+      IsProcedure: false,
+      Replace: $2.bool(),
+      Name: name,
+      Params: append(params, cols...),
+      ReturnType: tree.RoutineReturnType{
+        Type: returnType,
+        SetOf: true,
+      },
+      Options: $13.routineOptions(),
+      RoutineBody: $14.routineBody(),
+    }
+  }
+| CREATE opt_or_replace FUNCTION routine_create_name '(' opt_routine_param_with_default_list ')'
+  opt_create_routine_opt_list opt_routine_body
+  {
+    // Without a RETURNS clause, the return type is determined by the OUT
+    // parameters.
+    name := $4.unresolvedObjectName().ToRoutineName()
+    $$.val = &tree.CreateRoutine{
+      IsProcedure: false,
+      Replace: $2.bool(),
+      Name: name,
+      Params: $6.routineParams(),
+      Options: $8.routineOptions(),
+      RoutineBody: $9.routineBody(),
     }
   }
 | CREATE opt_or_replace FUNCTION error // SHOW HELP: CREATE FUNCTION
@@ -4693,14 +5077,207 @@ create_proc_stmt:
   }
 | CREATE opt_or_replace PROCEDURE error // SHOW HELP: CREATE PROCEDURE
 
+// %Help: CREATE AGGREGATE - define a new aggregate function
+// %Category: DDL
+// %Text:
+// CREATE [ OR REPLACE ] AGGREGATE name ( [ argmode ] [ argname ] argtype [, ...] ) (
+//    SFUNC = sfunc,
+//    STYPE = state_data_type
+//    [ , FINALFUNC = ffunc ]
+//    [ , COMBINEFUNC = combinefunc ]
+//    [ , INITCOND = initial_condition ]
+// )
+// %SeeAlso: DROP AGGREGATE, ALTER AGGREGATE, CREATE FUNCTION
+create_aggregate_stmt:
+  CREATE opt_or_replace AGGREGATE routine_create_name '(' func_params_list ')' '(' aggregate_option_list ')'
+  {
+    $$.val = &tree.CreateAggregate{
+      Replace: $2.bool(),
+      Name: $4.unresolvedObjectName().ToRoutineName(),
+      Params: $6.routineParams(),
+      Options: $9.aggregateOptions(),
+    }
+  }
+| CREATE opt_or_replace AGGREGATE error // SHOW HELP: CREATE AGGREGATE
+
+aggregate_option_list:
+  aggregate_option
+  {
+    $$.val = tree.AggregateOptions{$1.aggregateOption()}
+  }
+| aggregate_option_list ',' aggregate_option
+  {
+    $$.val = append($1.aggregateOptions(), $3.aggregateOption())
+  }
+
+aggregate_option:
+  name '=' typename
+  {
+    $$.val = tree.AggregateOption{Name: tree.Name($1), Type: $3.typeReference()}
+  }
+| name '=' SCONST
+  {
+    $$.val = tree.AggregateOption{Name: tree.Name($1), Value: tree.NewStrVal($3)}
+  }
+| name '=' numeric_only
+  {
+    $$.val = tree.AggregateOption{Name: tree.Name($1), Value: $3.expr()}
+  }
+
+// %Help: CREATE TRIGGER - define a new trigger
+// %Category: DDL
+// %Text:
+// CREATE [ OR REPLACE ] TRIGGER name
+//    { BEFORE | AFTER | INSTEAD OF } { event [ OR ... ] }
+//    ON table_name
+//    [ FOR [ EACH ] { ROW | STATEMENT } ]
+//    [ WHEN ( condition ) ]
+//    EXECUTE { FUNCTION | PROCEDURE } function_name ( [ arguments ] )
+//
+// where event can be one of:
+//    INSERT
+//    UPDATE [ OF column_name [, ... ] ]
+//    DELETE
+//    TRUNCATE
+// %SeeAlso: CREATE FUNCTION, DROP TRIGGER
+create_trigger_stmt:
+  CREATE opt_or_replace TRIGGER name trigger_action_time trigger_event_list
+  ON table_name opt_trigger_for_each opt_trigger_when
+  EXECUTE function_or_procedure func_name '(' opt_trigger_func_args ')'
+  {
+    $$.val = &tree.CreateTrigger{
+      Replace: $2.bool(),
+      Name: tree.Name($4),
+      ActionTime: $5.triggerActionTime(),
+      Events: $6.triggerEvents(),
+      TableName: $8.unresolvedObjectName().ToTableName(),
+      ForEach: $9.triggerForEach(),
+      When: $10.expr(),
+      FuncName: $13.unresolvedName(),
+      FuncArgs: $15.strs(),
+    }
+  }
+| CREATE opt_or_replace TRIGGER error // SHOW HELP: CREATE TRIGGER
+
+trigger_action_time:
+  BEFORE { $$.val = tree.TriggerActionTimeBefore }
+| AFTER { $$.val = tree.TriggerActionTimeAfter }
+| INSTEAD OF { $$.val = tree.TriggerActionTimeInsteadOf }
+
+trigger_event_list:
+  trigger_event
+  {
+    $$.val = []*tree.TriggerEvent{$1.triggerEvent()}
+  }
+| trigger_event_list OR trigger_event
+  {
+    $$.val = append($1.triggerEvents(), $3.triggerEvent())
+  }
+
+trigger_event:
+  INSERT { $$.val = &tree.TriggerEvent{EventType: tree.TriggerEventInsert} }
+| UPDATE { $$.val = &tree.TriggerEvent{EventType: tree.TriggerEventUpdate} }
+| UPDATE OF name_list
+  {
+    $$.val = &tree.TriggerEvent{EventType: tree.TriggerEventUpdate, Columns: $3.nameList()}
+  }
+| DELETE { $$.val = &tree.TriggerEvent{EventType: tree.TriggerEventDelete} }
+| TRUNCATE { $$.val = &tree.TriggerEvent{EventType: tree.TriggerEventTruncate} }
+
+opt_trigger_for_each:
+  FOR opt_each ROW { $$.val = tree.TriggerForEachRow }
+| FOR opt_each STATEMENT { $$.val = tree.TriggerForEachStatement }
+| /* EMPTY */ { $$.val = tree.TriggerForEachStatement }
+
+opt_each:
+  EACH {}
+| /* EMPTY */ {}
+
+opt_trigger_when:
+  WHEN '(' a_expr ')' { $$.val = $3.expr() }
+| /* EMPTY */ { $$.val = tree.Expr(nil) }
+
+function_or_procedure:
+  FUNCTION {}
+| PROCEDURE {}
+
+opt_trigger_func_args:
+  trigger_func_args
+| /* EMPTY */ { $$.val = []string(nil) }
+
+trigger_func_args:
+  trigger_func_arg
+  {
+    $$.val = []string{$1}
+  }
+| trigger_func_args ',' trigger_func_arg
+  {
+    $$.val = append($1.strs(), $3)
+  }
+
+// Postgres stores trigger function arguments as strings, regardless of
+// whether they were written as string literals, numbers, or identifiers.
+trigger_func_arg:
+  SCONST
+| ICONST { $$ = $1.numVal().OrigString() }
+| FCONST { $$ = $1.numVal().OrigString() }
+| unrestricted_name
+
+// %Help: CREATE POLICY - define a new row-level security policy for a table
+// %Category: DDL
+// %Text:
+// CREATE POLICY name ON table_name
+//    [ AS { PERMISSIVE | RESTRICTIVE } ]
+//    [ FOR { ALL | SELECT | INSERT | UPDATE | DELETE } ]
+//    [ TO { role_name | PUBLIC | CURRENT_USER | SESSION_USER } [, ...] ]
+//    [ USING ( using_expression ) ]
+//    [ WITH CHECK ( check_expression ) ]
+// %SeeAlso: DROP POLICY, ALTER TABLE
+create_policy_stmt:
+  CREATE POLICY name ON table_name opt_policy_type opt_policy_command
+  opt_policy_roles opt_policy_using opt_policy_with_check
+  {
+    $$.val = &tree.CreatePolicy{
+      Name: tree.Name($3),
+      TableName: $5.unresolvedObjectName().ToTableName(),
+      Type: $6.policyType(),
+      Cmd: $7.policyCommand(),
+      Roles: $8.roleSpecList(),
+      Using: $9.expr(),
+      WithCheck: $10.expr(),
+    }
+  }
+| CREATE POLICY error // SHOW HELP: CREATE POLICY
+
+opt_policy_type:
+  AS PERMISSIVE { $$.val = tree.PolicyPermissive }
+| AS RESTRICTIVE { $$.val = tree.PolicyRestrictive }
+| /* EMPTY */ { $$.val = tree.PolicyPermissive }
+
+opt_policy_command:
+  FOR ALL { $$.val = tree.PolicyCommandAll }
+| FOR SELECT { $$.val = tree.PolicyCommandSelect }
+| FOR INSERT { $$.val = tree.PolicyCommandInsert }
+| FOR UPDATE { $$.val = tree.PolicyCommandUpdate }
+| FOR DELETE { $$.val = tree.PolicyCommandDelete }
+| /* EMPTY */ { $$.val = tree.PolicyCommandAll }
+
+opt_policy_roles:
+  TO role_spec_list { $$.val = $2.roleSpecList() }
+| /* EMPTY */ { $$.val = tree.RoleSpecList(nil) }
+
+opt_policy_using:
+  USING '(' a_expr ')' { $$.val = $3.expr() }
+| /* EMPTY */ { $$.val = tree.Expr(nil) }
+
+opt_policy_with_check:
+  WITH CHECK '(' a_expr ')' { $$.val = $4.expr() }
+| /* EMPTY */ { $$.val = tree.Expr(nil) }
+
 opt_or_replace:
   OR REPLACE { $$.val = true }
 | /* EMPTY */ { $$.val = false }
 
-opt_return_table:
-  TABLE { return unimplementedWithIssueDetail(sqllex, 100226, "UDF returning TABLE") }
-| /* EMPTY */ { $$.val = false }
-
 opt_return_set:
   SETOF { $$.val = true}
 | /* EMPTY */ { $$.val = false }
@@ -4776,14 +5353,31 @@ routine_param:
 
 routine_param_class:
   IN { $$.val = tree.RoutineParamIn }
-| OUT { return unimplementedWithIssueDetail(sqllex, 100405, "create function with 'OUT' argument class") }
-| INOUT { return unimplementedWithIssueDetail(sqllex, 100405, "create function with 'INOUT' argument class") }
-| IN OUT { return unimplementedWithIssueDetail(sqllex, 100405, "create function with 'IN OUT' argument class") }
-| VARIADIC { return unimplementedWithIssueDetail(sqllex, 88947, "variadic user-defined functions") }
+| OUT { $$.val = tree.RoutineParamOut }
+| INOUT { $$.val = tree.RoutineParamInOut }
+| IN OUT { $$.val = tree.RoutineParamInOut }
+| VARIADIC { $$.val = tree.RoutineParamVariadic }
 
 routine_param_type:
   typename
 
+table_func_column_list:
+  table_func_column { $$.val = tree.RoutineParams{$1.routineParam()} }
+| table_func_column_list ',' table_func_column
+  {
+    $$.val = append($1.routineParams(), $3.routineParam())
+  }
+
+table_func_column:
+  param_name routine_param_type
+  {
+    $$.val = tree.RoutineParam{
+      Name: tree.Name($1),
+      Type: $2.typeReference(),
+      Class: tree.RoutineParamOut,
+    }
+  }
+
 routine_return_type:
   routine_param_type
 
@@ -4987,6 +5581,78 @@ drop_proc_stmt:
   }
 | DROP PROCEDURE error // SHOW HELP: DROP PROCEDURE
 
+// %Help: DROP AGGREGATE - remove an aggregate function
+// %Category: DDL
+// %Text: DROP AGGREGATE [ IF EXISTS ] name ( argtype [, ...] ) [, ...] [ CASCADE | RESTRICT ]
+// %SeeAlso: CREATE AGGREGATE
+drop_aggregate_stmt:
+  DROP AGGREGATE function_with_paramtypes_list opt_drop_behavior
+  {
+    $$.val = &tree.DropRoutine{
+      Aggregate: true,
+      Routines: $3.routineObjs(),
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP AGGREGATE IF EXISTS function_with_paramtypes_list opt_drop_behavior
+  {
+    $$.val = &tree.DropRoutine{
+      IfExists: true,
+      Aggregate: true,
+      Routines: $5.routineObjs(),
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP AGGREGATE error // SHOW HELP: DROP AGGREGATE
+
+// %Help: DROP TRIGGER - remove a trigger
+// %Category: DDL
+// %Text: DROP TRIGGER [ IF EXISTS ] name ON table_name [ CASCADE | RESTRICT ]
+// %SeeAlso: CREATE TRIGGER
+drop_trigger_stmt:
+  DROP TRIGGER name ON table_name opt_drop_behavior
+  {
+    $$.val = &tree.DropTrigger{
+      Trigger: tree.Name($3),
+      Table: $5.unresolvedObjectName().ToTableName(),
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP TRIGGER IF EXISTS name ON table_name opt_drop_behavior
+  {
+    $$.val = &tree.DropTrigger{
+      IfExists: true,
+      Trigger: tree.Name($5),
+      Table: $7.unresolvedObjectName().ToTableName(),
+      DropBehavior: $8.dropBehavior(),
+    }
+  }
+| DROP TRIGGER error // SHOW HELP: DROP TRIGGER
+
+// %Help: DROP POLICY - remove a row-level security policy from a table
+// %Category: DDL
+// %Text: DROP POLICY [ IF EXISTS ] name ON table_name [ CASCADE | RESTRICT ]
+// %SeeAlso: CREATE POLICY
+drop_policy_stmt:
+  DROP POLICY name ON table_name opt_drop_behavior
+  {
+    $$.val = &tree.DropPolicy{
+      Policy: tree.Name($3),
+      Table: $5.unresolvedObjectName().ToTableName(),
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP POLICY IF EXISTS name ON table_name opt_drop_behavior
+  {
+    $$.val = &tree.DropPolicy{
+      IfExists: true,
+      Policy: tree.Name($5),
+      Table: $7.unresolvedObjectName().ToTableName(),
+      DropBehavior: $8.dropBehavior(),
+    }
+  }
+| DROP POLICY error // SHOW HELP: DROP POLICY
+
 function_with_paramtypes_list:
   function_with_paramtypes
   {
@@ -5134,7 +5800,6 @@ opt_no:
 
 create_unsupported:
   CREATE ACCESS METHOD error { return unimplemented(sqllex, "create access method") }
-| CREATE AGGREGATE error { return unimplementedWithIssueDetail(sqllex, 74775, "create aggregate") }
 | CREATE CAST error { return unimplemented(sqllex, "create cast") }
 | CREATE CONSTRAINT TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "create constraint") }
 | CREATE CONVERSION error { return unimplemented(sqllex, "create conversion") }
@@ -5143,13 +5808,10 @@ create_unsupported:
 | CREATE FOREIGN DATA error { return unimplemented(sqllex, "create fdw") }
 | CREATE opt_or_replace opt_trusted opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "create language " + $6) }
 | CREATE OPERATOR error { return unimplementedWithIssue(sqllex, 65017) }
-| CREATE PUBLICATION error { return unimplemented(sqllex, "create publication") }
 | CREATE opt_or_replace RULE error { return unimplemented(sqllex, "create rule") }
 | CREATE SERVER error { return unimplemented(sqllex, "create server") }
-| CREATE SUBSCRIPTION error { return unimplemented(sqllex, "create subscription") }
 | CREATE TABLESPACE error { return unimplementedWithIssueDetail(sqllex, 54113, "create tablespace") }
 | CREATE TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "create text") }
-| CREATE TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "create trigger") }
 
 opt_trusted:
   TRUSTED {}
@@ -5161,23 +5823,18 @@ opt_procedural:
 
 drop_unsupported:
   DROP ACCESS METHOD error { return unimplemented(sqllex, "drop access method") }
-| DROP AGGREGATE error { return unimplementedWithIssueDetail(sqllex, 74775, "drop aggregate") }
 | DROP CAST error { return unimplemented(sqllex, "drop cast") }
 | DROP COLLATION error { return unimplemented(sqllex, "drop collation") }
 | DROP CONVERSION error { return unimplemented(sqllex, "drop conversion") }
-| DROP DOMAIN error { return unimplementedWithIssueDetail(sqllex, 27796, "drop") }
 | DROP EXTENSION IF EXISTS name error { return unimplementedWithIssueDetail(sqllex, 74777, "drop extension if exists") }
 | DROP EXTENSION name error { return unimplementedWithIssueDetail(sqllex, 74777, "drop extension") }
 | DROP FOREIGN TABLE error { return unimplemented(sqllex, "drop foreign table") }
 | DROP FOREIGN DATA error { return unimplemented(sqllex, "drop fdw") }
 | DROP opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "drop language " + $4) }
 | DROP OPERATOR error { return unimplemented(sqllex, "drop operator") }
-| DROP PUBLICATION error { return unimplemented(sqllex, "drop publication") }
 | DROP RULE error { return unimplemented(sqllex, "drop rule") }
 | DROP SERVER error { return unimplemented(sqllex, "drop server") }
-| DROP SUBSCRIPTION error { return unimplemented(sqllex, "drop subscription") }
 | DROP TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "drop text") }
-| DROP TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "drop") }
 
 create_ddl_stmt:
   create_database_stmt // EXTEND WITH HELP: CREATE DATABASE
@@ -5188,10 +5845,93 @@ create_ddl_stmt:
 // Error case for both CREATE TABLE and CREATE TABLE ... AS in one
 | CREATE opt_persistence_temp_table TABLE error   // SHOW HELP: CREATE TABLE
 | create_type_stmt     // EXTEND WITH HELP: CREATE TYPE
+| create_domain_stmt   // EXTEND WITH HELP: CREATE DOMAIN
 | create_view_stmt     // EXTEND WITH HELP: CREATE VIEW
 | create_sequence_stmt // EXTEND WITH HELP: CREATE SEQUENCE
 | create_func_stmt     // EXTEND WITH HELP: CREATE FUNCTION
 | create_proc_stmt     // EXTEND WITH HELP: CREATE PROCEDURE
+| create_trigger_stmt  // EXTEND WITH HELP: CREATE TRIGGER
+| create_policy_stmt   // EXTEND WITH HELP: CREATE POLICY
+| create_aggregate_stmt // EXTEND WITH HELP: CREATE AGGREGATE
+| create_text_search_config_stmt // EXTEND WITH HELP: CREATE TEXT SEARCH CONFIGURATION
+| create_text_search_dict_stmt   // EXTEND WITH HELP: CREATE TEXT SEARCH DICTIONARY
+
+// %Help: CREATE TEXT SEARCH CONFIGURATION - define a new text search configuration
+// %Category: DDL
+// %Text:
+// CREATE TEXT SEARCH CONFIGURATION <name> ( PARSER = default )
+// CREATE TEXT SEARCH CONFIGURATION <name> ( COPY = <source_config> )
+// %SeeAlso: ALTER TEXT SEARCH CONFIGURATION, DROP TEXT SEARCH CONFIGURATION,
+// CREATE TEXT SEARCH DICTIONARY
+create_text_search_config_stmt:
+  CREATE TEXT SEARCH CONFIGURATION name '(' storage_parameter_list ')'
+  {
+    $$.val = &tree.CreateTextSearchConfig{Name: tree.Name($5), Options: $7.storageParams()}
+  }
+| CREATE TEXT SEARCH CONFIGURATION error // SHOW HELP: CREATE TEXT SEARCH CONFIGURATION
+
+// %Help: CREATE TEXT SEARCH DICTIONARY - define a new text search dictionary
+// %Category: DDL
+// %Text:
+// CREATE TEXT SEARCH DICTIONARY <name> ( TEMPLATE = <template> [, <option> = <value> [, ...] ] )
+//
+// Templates and their options:
+//    simple:   STOPWORDS = <language>, STOPWORD_LIST = '<word>, ...', ACCEPT = <bool>
+//    synonym:  SYNONYM_LIST = '<word> <synonym>, ...'
+//    snowball: LANGUAGE = <language>, STOPWORDS = <language>, STOPWORD_LIST = '<word>, ...'
+// %SeeAlso: ALTER TEXT SEARCH DICTIONARY, DROP TEXT SEARCH DICTIONARY,
+// CREATE TEXT SEARCH CONFIGURATION
+create_text_search_dict_stmt:
+  CREATE TEXT SEARCH DICTIONARY name '(' storage_parameter_list ')'
+  {
+    $$.val = &tree.CreateTextSearchDictionary{Name: tree.Name($5), Options: $7.storageParams()}
+  }
+| CREATE TEXT SEARCH DICTIONARY error // SHOW HELP: CREATE TEXT SEARCH DICTIONARY
+
+// %Help: CREATE PUBLICATION - define a new publication
+// %Category: Misc
+// %Text:
+// CREATE PUBLICATION <name> [ FOR TABLE <tablename> [, ...] | FOR ALL TABLES ]
+// %SeeAlso: DROP PUBLICATION, CREATE SUBSCRIPTION
+create_publication_stmt:
+  CREATE PUBLICATION name
+  {
+    $$.val = &tree.CreatePublication{Name: tree.Name($3)}
+  }
+| CREATE PUBLICATION name FOR TABLE table_name_list
+  {
+    $$.val = &tree.CreatePublication{Name: tree.Name($3), Tables: $6.tableNames()}
+  }
+| CREATE PUBLICATION name FOR ALL TABLES
+  {
+    $$.val = &tree.CreatePublication{Name: tree.Name($3), AllTables: true}
+  }
+| CREATE PUBLICATION error // SHOW HELP: CREATE PUBLICATION
+
+// %Help: CREATE SUBSCRIPTION - define a new subscription
+// %Category: Misc
+// %Text:
+// CREATE SUBSCRIPTION <name>
+//    CONNECTION '<connection string>'
+//    PUBLICATION <publication_name> [, ...]
+//    [ WITH ( <option> = <value> [, ...] ) ]
+//
+// Options:
+//    create_slot = <bool>
+//    slot_name = '<name>'
+//    copy_data = <bool>
+// %SeeAlso: DROP SUBSCRIPTION, SHOW SUBSCRIPTIONS, CREATE PUBLICATION
+create_subscription_stmt:
+  CREATE SUBSCRIPTION name CONNECTION string_or_placeholder PUBLICATION name_list opt_with_storage_parameter_list
+  {
+    $$.val = &tree.CreateSubscription{
+      Name: tree.Name($3),
+      ConnectionURI: $5.expr(),
+      Publications: $7.nameList(),
+      Options: $8.storageParams(),
+    }
+  }
+| CREATE SUBSCRIPTION error // SHOW HELP: CREATE SUBSCRIPTION
 
 // %Help: CREATE STATISTICS - create a new table statistic
 // %Category: Misc
@@ -5565,6 +6305,8 @@ drop_stmt:
 | drop_schedule_stmt            // EXTEND WITH HELP: DROP SCHEDULES
 | drop_external_connection_stmt // EXTEND WITH HELP: DROP EXTERNAL CONNECTION
 | drop_virtual_cluster_stmt     // EXTEND WITH HELP: DROP VIRTUAL CLUSTER
+| drop_publication_stmt         // EXTEND WITH HELP: DROP PUBLICATION
+| drop_subscription_stmt        // EXTEND WITH HELP: DROP SUBSCRIPTION
 | drop_unsupported   {}
 | DROP error                    // SHOW HELP: DROP
 
@@ -5576,8 +6318,74 @@ drop_ddl_stmt:
 | drop_sequence_stmt // EXTEND WITH HELP: DROP SEQUENCE
 | drop_schema_stmt   // EXTEND WITH HELP: DROP SCHEMA
 | drop_type_stmt     // EXTEND WITH HELP: DROP TYPE
+| drop_domain_stmt   // EXTEND WITH HELP: DROP DOMAIN
 | drop_func_stmt     // EXTEND WITH HELP: DROP FUNCTION
 | drop_proc_stmt     // EXTEND WITH HELP: DROP FUNCTION
+| drop_trigger_stmt  // EXTEND WITH HELP: DROP TRIGGER
+| drop_policy_stmt   // EXTEND WITH HELP: DROP POLICY
+| drop_aggregate_stmt // EXTEND WITH HELP: DROP AGGREGATE
+| drop_text_search_config_stmt // EXTEND WITH HELP: DROP TEXT SEARCH CONFIGURATION
+| drop_text_search_dict_stmt   // EXTEND WITH HELP: DROP TEXT SEARCH DICTIONARY
+
+// %Help: DROP TEXT SEARCH CONFIGURATION - remove a text search configuration
+// %Category: DDL
+// %Text: DROP TEXT SEARCH CONFIGURATION [ IF EXISTS ] <name> [, ...] [ CASCADE | RESTRICT ]
+// %SeeAlso: CREATE TEXT SEARCH CONFIGURATION
+drop_text_search_config_stmt:
+  DROP TEXT SEARCH CONFIGURATION name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropTextSearchConfig{Names: $5.nameList(), DropBehavior: $6.dropBehavior()}
+  }
+| DROP TEXT SEARCH CONFIGURATION IF EXISTS name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropTextSearchConfig{Names: $7.nameList(), IfExists: true, DropBehavior: $8.dropBehavior()}
+  }
+| DROP TEXT SEARCH CONFIGURATION error // SHOW HELP: DROP TEXT SEARCH CONFIGURATION
+
+// %Help: DROP TEXT SEARCH DICTIONARY - remove a text search dictionary
+// %Category: DDL
+// %Text: DROP TEXT SEARCH DICTIONARY [ IF EXISTS ] <name> [, ...] [ CASCADE | RESTRICT ]
+// %SeeAlso: CREATE TEXT SEARCH DICTIONARY
+drop_text_search_dict_stmt:
+  DROP TEXT SEARCH DICTIONARY name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropTextSearchDictionary{Names: $5.nameList(), DropBehavior: $6.dropBehavior()}
+  }
+| DROP TEXT SEARCH DICTIONARY IF EXISTS name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropTextSearchDictionary{Names: $7.nameList(), IfExists: true, DropBehavior: $8.dropBehavior()}
+  }
+| DROP TEXT SEARCH DICTIONARY error // SHOW HELP: DROP TEXT SEARCH DICTIONARY
+
+// %Help: DROP PUBLICATION - remove a publication
+// %Category: Misc
+// %Text: DROP PUBLICATION [ IF EXISTS ] <name> [, ...]
+// %SeeAlso: CREATE PUBLICATION
+drop_publication_stmt:
+  DROP PUBLICATION name_list
+  {
+    $$.val = &tree.DropPublication{Names: $3.nameList()}
+  }
+| DROP PUBLICATION IF EXISTS name_list
+  {
+    $$.val = &tree.DropPublication{Names: $5.nameList(), IfExists: true}
+  }
+| DROP PUBLICATION error // SHOW HELP: DROP PUBLICATION
+
+// %Help: DROP SUBSCRIPTION - remove a subscription
+// %Category: Misc
+// %Text: DROP SUBSCRIPTION [ IF EXISTS ] <name>
+// %SeeAlso: CREATE SUBSCRIPTION
+drop_subscription_stmt:
+  DROP SUBSCRIPTION name
+  {
+    $$.val = &tree.DropSubscription{Name: tree.Name($3)}
+  }
+| DROP SUBSCRIPTION IF EXISTS name
+  {
+    $$.val = &tree.DropSubscription{Name: tree.Name($5), IfExists: true}
+  }
+| DROP SUBSCRIPTION error // SHOW HELP: DROP SUBSCRIPTION
 
 // %Help: DROP VIEW - remove a view
 // %Category: DDL
@@ -5712,6 +6520,29 @@ drop_type_stmt:
   }
 | DROP TYPE error // SHOW HELP: DROP TYPE
 
+// %Help: DROP DOMAIN - remove a domain
+// %Category: DDL
+// %Text: DROP DOMAIN [IF EXISTS] <type_name> [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE DOMAIN, ALTER DOMAIN
+drop_domain_stmt:
+  DROP DOMAIN type_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropDomain{
+      Names: $3.unresolvedObjectNames(),
+      IfExists: false,
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP DOMAIN IF EXISTS type_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropDomain{
+      Names: $5.unresolvedObjectNames(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP DOMAIN error // SHOW HELP: DROP DOMAIN
+
 // %Help: DROP VIRTUAL CLUSTER - remove a virtual cluster
 // %Category: Experimental
 // %Text: DROP VIRTUAL CLUSTER [IF EXISTS] <virtual_cluster_spec> [IMMEDIATE]
@@ -5933,6 +6764,7 @@ preparable_stmt:
 | explain_stmt   // EXTEND WITH HELP: EXPLAIN
 | import_stmt    // EXTEND WITH HELP: IMPORT
 | insert_stmt    // EXTEND WITH HELP: INSERT
+| merge_stmt     // EXTEND WITH HELP: MERGE
 | pause_stmt     // help texts in sub-rule
 | reset_stmt     // help texts in sub-rule
 | restore_stmt   // EXTEND WITH HELP: RESTORE
@@ -6646,8 +7478,8 @@ use_stmt:
 // SET remainder, e.g. SET TRANSACTION
 nonpreparable_set_stmt:
   set_transaction_stmt // EXTEND WITH HELP: SET TRANSACTION
+| set_constraints_stmt // EXTEND WITH HELP: SET CONSTRAINTS
 | set_exprs_internal   { /* SKIP DOC */ }
-| SET CONSTRAINTS error { return unimplemented(sqllex, "set constraints") }
 
 // SET SESSION / SET LOCAL / SET CLUSTER SETTING
 preparable_set_stmt:
@@ -7101,6 +7933,36 @@ set_transaction_stmt:
   }
 | SET SESSION TRANSACTION error // SHOW HELP: SET TRANSACTION
 
+// %Help: SET CONSTRAINTS - configure the constraint check timing of the transaction
+// %Category: Txn
+// %Text:
+// SET CONSTRAINTS { ALL | <name> [, ...] } { DEFERRED | IMMEDIATE }
+//
+// DEFERRED postpones the checks for DEFERRABLE constraints until the
+// transaction commits. IMMEDIATE runs any postponed checks and checks the
+// constraints at the end of each subsequent statement. Constraint names are
+// looked up in the schemas of the current search path.
+//
+// %SeeAlso: SET TRANSACTION, CREATE TABLE, ALTER TABLE
+set_constraints_stmt:
+  SET CONSTRAINTS ALL DEFERRED
+  {
+    $$.val = &tree.SetConstraints{Deferred: true}
+  }
+| SET CONSTRAINTS ALL IMMEDIATE
+  {
+    $$.val = &tree.SetConstraints{Deferred: false}
+  }
+| SET CONSTRAINTS name_list DEFERRED
+  {
+    $$.val = &tree.SetConstraints{Names: $3.nameList(), Deferred: true}
+  }
+| SET CONSTRAINTS name_list IMMEDIATE
+  {
+    $$.val = &tree.SetConstraints{Names: $3.nameList(), Deferred: false}
+  }
+| SET CONSTRAINTS error // SHOW HELP: SET CONSTRAINTS
+
 generic_set:
   var_name to_or_eq var_list
   {
@@ -7330,6 +8192,7 @@ show_stmt:
 | show_sequences_stmt        // EXTEND WITH HELP: SHOW SEQUENCES
 | show_session_stmt          // EXTEND WITH HELP: SHOW SESSION
 | show_sessions_stmt         // EXTEND WITH HELP: SHOW SESSIONS
+| show_subscriptions_stmt    // EXTEND WITH HELP: SHOW SUBSCRIPTIONS
 | show_stats_stmt            // EXTEND WITH HELP: SHOW STATISTICS
 | show_syntax_stmt           // EXTEND WITH HELP: SHOW SYNTAX
 | show_tables_stmt           // EXTEND WITH HELP: SHOW TABLES
@@ -8416,6 +9279,17 @@ show_sessions_stmt:
   }
 | SHOW ALL opt_cluster SESSIONS error // SHOW HELP: SHOW SESSIONS
 
+// %Help: SHOW SUBSCRIPTIONS - list subscriptions
+// %Category: Misc
+// %Text: SHOW SUBSCRIPTIONS
+// %SeeAlso: CREATE SUBSCRIPTION
+show_subscriptions_stmt:
+  SHOW SUBSCRIPTIONS
+  {
+    $$.val = &tree.ShowSubscriptions{}
+  }
+| SHOW SUBSCRIPTIONS error // SHOW HELP: SHOW SUBSCRIPTIONS
+
 // %Help: SHOW TABLES - list tables
 // %Category: DDL
 // %Text: SHOW TABLES [FROM <databasename> [ . <schemaname> ] ] [WITH COMMENT]
@@ -9583,7 +10457,7 @@ alter_schema_stmt:
 // %Category: DDL
 // %Text:
 // CREATE [[GLOBAL | LOCAL] {TEMPORARY | TEMP}] TABLE [IF NOT EXISTS] <tablename> ( <elements...> ) [<on_commit>]
-// CREATE [[GLOBAL | LOCAL] {TEMPORARY | TEMP}] TABLE [IF NOT EXISTS] <tablename> [( <colnames...> )] AS <source> [<on commit>]
+// CREATE [[GLOBAL | LOCAL] {TEMPORARY | TEMP}] TABLE [IF NOT EXISTS] <tablename> [( <colnames...> )] AS <source> [WITH [NO] DATA] [<on commit>]
 //
 // Table elements:
 //    <name> <type> [<qualifiers...>]
@@ -9745,6 +10619,7 @@ create_table_as_stmt:
       StorageParams: $6.storageParams(),
       OnCommit: $10.createTableOnCommitSetting(),
       Persistence: $2.persistence(),
+      WithNoData: !$9.bool(),
     }
   }
 | CREATE opt_persistence_temp_table TABLE IF NOT EXISTS table_name create_as_opt_col_list opt_table_with AS select_stmt opt_create_as_data opt_create_table_on_commit
@@ -9758,13 +10633,24 @@ create_table_as_stmt:
       StorageParams: $9.storageParams(),
       OnCommit: $13.createTableOnCommitSetting(),
       Persistence: $2.persistence(),
+      WithNoData: !$12.bool(),
     }
   }
 
 opt_create_as_data:
-  /* EMPTY */  { /* no error */ }
-| WITH DATA    { /* SKIP DOC */ /* This is the default */ }
-| WITH NO DATA { return unimplemented(sqllex, "create table as with no data") }
+  /* EMPTY */
+  {
+    $$.val = true
+  }
+| WITH DATA
+  {
+    /* SKIP DOC */ /* This is the default */
+    $$.val = true
+  }
+| WITH NO DATA
+  {
+    $$.val = false
+  }
 
 /*
  * Redundancy here is needed to avoid shift/reduce conflicts,
@@ -10265,6 +11151,9 @@ table_constraint:
 constraint_elem:
   CHECK '(' a_expr ')' opt_deferrable
   {
+    if $5.constraintDeferrability() != tree.ConstraintNotDeferrable {
+      return setErr(sqllex, pgerror.New(pgcode.FeatureNotSupported, "CHECK constraints cannot be marked DEFERRABLE"))
+    }
     $$.val = &tree.CheckConstraintTableDef{
       Expr: $3.expr(),
     }
@@ -10280,6 +11169,7 @@ constraint_elem:
         PartitionByIndex: $7.partitionByIndex(),
         Predicate: $9.expr(),
       },
+      Deferrable: $8.constraintDeferrability(),
     }
   }
 | PRIMARY KEY '(' index_params ')' opt_hash_sharded opt_with_storage_parameter_list
@@ -10303,11 +11193,56 @@ constraint_elem:
       ToCols: $8.nameList(),
       Match: $9.compositeKeyMatchMethod(),
       Actions: $10.referenceActions(),
+      Deferrable: $11.constraintDeferrability(),
+    }
+  }
+| EXCLUDE opt_exclude_access_method '(' exclude_elem_list ')' opt_where_clause
+  {
+    $$.val = &tree.ExclusionConstraintTableDef{
+      Using: $2,
+      Elems: $4.exclusionConstraintElems(),
+      Predicate: $6.expr(),
+    }
+  }
+
+opt_exclude_access_method:
+  USING name
+  {
+    $$ = $2
+  }
+| /* EMPTY */
+  {
+    $$ = ""
+  }
+
+exclude_elem_list:
+  exclude_elem
+  {
+    $$.val = tree.ExclusionConstraintElems{$1.exclusionConstraintElem()}
+  }
+| exclude_elem_list ',' exclude_elem
+  {
+    $$.val = append($1.exclusionConstraintElems(), $3.exclusionConstraintElem())
+  }
+
+exclude_elem:
+  column_name WITH all_op
+  {
+    op, ok := $3.op().(treecmp.ComparisonOperator)
+    if !ok {
+      sqllex.Error(fmt.Sprintf("operator %s does not return a boolean", $3.op()))
+      return 1
     }
+    $$.val = tree.ExclusionConstraintElem{Column: tree.Name($1), Operator: op}
   }
-| EXCLUDE USING error
+| column_name WITH OPERATOR '(' operator_op ')'
   {
-    return unimplementedWithIssueDetail(sqllex, 46657, "add constraint exclude using")
+    op, ok := $5.op().(treecmp.ComparisonOperator)
+    if !ok {
+      sqllex.Error(fmt.Sprintf("operator %s does not return a boolean", $5.op()))
+      return 1
+    }
+    $$.val = tree.ExclusionConstraintElem{Column: tree.Name($1), Operator: op}
   }
 
 
@@ -10416,12 +11351,30 @@ create_as_col_qualification_elem:
   }
 
 opt_deferrable:
-  /* EMPTY */ { /* no error */ }
-| DEFERRABLE { return unimplementedWithIssueDetail(sqllex, 31632, "deferrable") }
-| DEFERRABLE INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "def initially deferred") }
-| DEFERRABLE INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "def initially immediate") }
-| INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "initially deferred") }
-| INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "initially immediate") }
+  /* EMPTY */
+  {
+    $$.val = tree.ConstraintNotDeferrable
+  }
+| DEFERRABLE
+  {
+    $$.val = tree.ConstraintInitiallyImmediate
+  }
+| DEFERRABLE INITIALLY DEFERRED
+  {
+    $$.val = tree.ConstraintInitiallyDeferred
+  }
+| DEFERRABLE INITIALLY IMMEDIATE
+  {
+    $$.val = tree.ConstraintInitiallyImmediate
+  }
+| INITIALLY DEFERRED
+  {
+    $$.val = tree.ConstraintInitiallyDeferred
+  }
+| INITIALLY IMMEDIATE
+  {
+    $$.val = tree.ConstraintNotDeferrable
+  }
 
 storing:
   COVERING
@@ -10798,11 +11751,15 @@ role_or_group_or_user:
 // %Category: DDL
 // %Text:
 // CREATE [TEMPORARY | TEMP] VIEW [IF NOT EXISTS] <viewname> [( <colnames...> )] AS <source>
+// CREATE [TEMPORARY | TEMP] RECURSIVE VIEW [IF NOT EXISTS] <viewname> ( <colnames...> ) AS <source>
 // CREATE [TEMPORARY | TEMP] MATERIALIZED VIEW [IF NOT EXISTS] <viewname> [( <colnames...> )] AS <source> [WITH [NO] DATA]
 // %SeeAlso: CREATE TABLE, SHOW CREATE, WEBDOCS/create-view.html
 create_view_stmt:
   CREATE opt_temp opt_view_recursive VIEW view_name opt_column_list AS select_stmt
   {
+    if $3.bool() && len($6.nameList()) == 0 {
+      return setErr(sqllex, pgerror.New(pgcode.Syntax, "CREATE RECURSIVE VIEW requires a column list"))
+    }
     name := $5.unresolvedObjectName().ToTableName()
     $$.val = &tree.CreateView{
       Name: name,
@@ -10811,12 +11768,16 @@ create_view_stmt:
       Persistence: $2.persistence(),
       IfNotExists: false,
       Replace: false,
+      Recursive: $3.bool(),
     }
   }
 // We cannot use a rule like opt_or_replace here as that would cause a conflict
 // with the opt_temp rule.
 | CREATE OR REPLACE opt_temp opt_view_recursive VIEW view_name opt_column_list AS select_stmt
   {
+    if $5.bool() && len($8.nameList()) == 0 {
+      return setErr(sqllex, pgerror.New(pgcode.Syntax, "CREATE RECURSIVE VIEW requires a column list"))
+    }
     name := $7.unresolvedObjectName().ToTableName()
     $$.val = &tree.CreateView{
       Name: name,
@@ -10825,10 +11786,14 @@ create_view_stmt:
       Persistence: $4.persistence(),
       IfNotExists: false,
       Replace: true,
+      Recursive: $5.bool(),
     }
   }
 | CREATE opt_temp opt_view_recursive VIEW IF NOT EXISTS view_name opt_column_list AS select_stmt
   {
+    if $3.bool() && len($9.nameList()) == 0 {
+      return setErr(sqllex, pgerror.New(pgcode.Syntax, "CREATE RECURSIVE VIEW requires a column list"))
+    }
     name := $8.unresolvedObjectName().ToTableName()
     $$.val = &tree.CreateView{
       Name: name,
@@ -10837,6 +11802,7 @@ create_view_stmt:
       Persistence: $2.persistence(),
       IfNotExists: true,
       Replace: false,
+      Recursive: $3.bool(),
     }
   }
 | CREATE MATERIALIZED VIEW view_name opt_column_list AS select_stmt opt_with_data
@@ -10985,6 +11951,14 @@ role_option:
   {
     $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
   }
+| BYPASSRLS
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| NOBYPASSRLS
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
 
 role_options:
   role_option
@@ -11017,8 +11991,14 @@ valid_until_clause:
   }
 
 opt_view_recursive:
-  /* EMPTY */ { /* no error */ }
-| RECURSIVE { return unimplemented(sqllex, "create recursive view") }
+  /* EMPTY */
+  {
+    $$.val = false
+  }
+| RECURSIVE
+  {
+    $$.val = true
+  }
 
 
 // %Help: CREATE TYPE - create a type
@@ -11068,8 +12048,76 @@ create_type_stmt:
 | CREATE TYPE type_name '(' error         { return unimplementedWithIssueDetail(sqllex, 27793, "base") }
   // Shell types, gateway to define base types using the previous syntax.
 | CREATE TYPE type_name                   { return unimplementedWithIssueDetail(sqllex, 27793, "shell") }
-  // Domain types.
-| CREATE DOMAIN type_name error           { return unimplementedWithIssueDetail(sqllex, 27796, "create") }
+
+// %Help: CREATE DOMAIN - create a domain
+// %Category: DDL
+// %Text:
+// CREATE DOMAIN <type_name> [AS] <data_type> [ <constraint> [ ... ] ]
+//
+// Constraint:
+//   [ CONSTRAINT <name> ] { NOT NULL | NULL | CHECK (<expr>) }
+//
+// %SeeAlso: ALTER DOMAIN, DROP DOMAIN
+create_domain_stmt:
+  CREATE DOMAIN type_name AS typename opt_domain_constraint_list
+  {
+    $$.val = &tree.CreateType{
+      TypeName: $3.unresolvedObjectName(),
+      Variety: tree.Domain,
+      DomainBaseType: $5.typeReference(),
+      DomainConstraints: $6.domainConstraints(),
+    }
+  }
+| CREATE DOMAIN type_name typename opt_domain_constraint_list
+  {
+    $$.val = &tree.CreateType{
+      TypeName: $3.unresolvedObjectName(),
+      Variety: tree.Domain,
+      DomainBaseType: $4.typeReference(),
+      DomainConstraints: $5.domainConstraints(),
+    }
+  }
+| CREATE DOMAIN error // SHOW HELP: CREATE DOMAIN
+
+opt_domain_constraint_list:
+  domain_constraint_list
+| /* EMPTY */
+  {
+    $$.val = []tree.DomainConstraint(nil)
+  }
+
+domain_constraint_list:
+  domain_constraint
+  {
+    $$.val = []tree.DomainConstraint{$1.domainConstraint()}
+  }
+| domain_constraint_list domain_constraint
+  {
+    $$.val = append($1.domainConstraints(), $2.domainConstraint())
+  }
+
+domain_constraint:
+  CONSTRAINT constraint_name domain_constraint_elem
+  {
+    c := $3.domainConstraint()
+    c.Name = tree.Name($2)
+    $$.val = c
+  }
+| domain_constraint_elem
+
+domain_constraint_elem:
+  NOT NULL
+  {
+    $$.val = tree.DomainConstraint{Nullability: tree.NotNull}
+  }
+| NULL
+  {
+    $$.val = tree.DomainConstraint{Nullability: tree.Null}
+  }
+| CHECK '(' a_expr ')'
+  {
+    $$.val = tree.DomainConstraint{Check: $3.expr(), Nullability: tree.SilentNull}
+  }
 
 opt_enum_val_list:
   enum_val_list
@@ -12358,6 +13406,94 @@ upsert_stmt:
   }
 | opt_with_clause UPSERT error // SHOW HELP: UPSERT
 
+// %Help: MERGE - insert, update or delete rows of a table based on a join
+// %Category: DML
+// %Text:
+// MERGE INTO <tablename> [[AS] <name>]
+//        USING <source> ON <join_condition>
+//        WHEN MATCHED [AND <condition>] THEN
+//          { UPDATE SET ... | DELETE | DO NOTHING }
+//        WHEN NOT MATCHED [AND <condition>] THEN
+//          { INSERT [( <colnames...> )] { VALUES ( <exprs...> ) | DEFAULT VALUES } | DO NOTHING }
+//        [...]
+//        [RETURNING <exprs...>]
+// %SeeAlso: INSERT, UPSERT, UPDATE, DELETE
+merge_stmt:
+  opt_with_clause MERGE INTO table_expr_opt_alias_idx USING table_ref ON a_expr merge_when_list returning_clause
+  {
+    $$.val = &tree.Merge{
+      With: $1.with(),
+      Table: $4.tblExpr(),
+      Source: $6.tblExpr(),
+      On: $8.expr(),
+      Whens: $9.mergeWhens(),
+      Returning: $10.retClause(),
+    }
+  }
+| opt_with_clause MERGE error // SHOW HELP: MERGE
+
+merge_when_list:
+  merge_when_clause
+  {
+    $$.val = tree.MergeWhens{$1.mergeWhen()}
+  }
+| merge_when_list merge_when_clause
+  {
+    $$.val = append($1.mergeWhens(), $2.mergeWhen())
+  }
+
+merge_when_clause:
+  WHEN MATCHED opt_merge_when_cond THEN merge_matched_action
+  {
+    $$.val = &tree.MergeWhen{Matched: true, Cond: $3.expr(), Action: $5.mergeAction()}
+  }
+| WHEN NOT MATCHED opt_merge_when_cond THEN merge_not_matched_action
+  {
+    $$.val = &tree.MergeWhen{Matched: false, Cond: $4.expr(), Action: $6.mergeAction()}
+  }
+
+opt_merge_when_cond:
+  AND a_expr
+  {
+    $$.val = $2.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
+merge_matched_action:
+  UPDATE SET set_clause_list
+  {
+    $$.val = &tree.MergeUpdate{Exprs: $3.updateExprs()}
+  }
+| DELETE
+  {
+    $$.val = &tree.MergeDelete{}
+  }
+| DO NOTHING
+  {
+    $$.val = &tree.MergeDoNothing{}
+  }
+
+merge_not_matched_action:
+  INSERT VALUES '(' expr_list ')'
+  {
+    $$.val = &tree.MergeInsert{Values: $4.exprs()}
+  }
+| INSERT '(' insert_column_list ')' VALUES '(' expr_list ')'
+  {
+    $$.val = &tree.MergeInsert{Columns: $3.nameList(), Values: $7.exprs()}
+  }
+| INSERT DEFAULT VALUES
+  {
+    $$.val = &tree.MergeInsert{}
+  }
+| DO NOTHING
+  {
+    $$.val = &tree.MergeDoNothing{}
+  }
+
 insert_target:
   table_name
   {
@@ -13223,9 +14359,18 @@ group_by_list:
 // rather than reducing the conflicting unreserved_keyword rule.
 group_by_item:
   a_expr { $$.val = $1.expr() }
-| ROLLUP '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "rollup") }
-| CUBE '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "cube") }
-| GROUPING SETS error { return unimplementedWithIssueDetail(sqllex, 46280, "grouping sets") }
+| ROLLUP '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.RollupGroupingSet, Exprs: $3.exprs()}
+  }
+| CUBE '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.CubeGroupingSet, Exprs: $3.exprs()}
+  }
+| GROUPING SETS '(' group_by_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.GroupingSets, Exprs: $4.exprs()}
+  }
 
 having_clause:
   HAVING a_expr
@@ -13403,6 +14548,7 @@ opt_index_flags:
 //   <source> NATURAL [ <jointype> ] JOIN <source>
 //   <source> CROSS JOIN <source>
 //   <source> WITH ORDINALITY
+//   <tablename> [AS <alias>] TABLESAMPLE { BERNOULLI | SYSTEM } ( <percent> ) [ REPEATABLE ( <seed> ) ]
 //   '[' EXPLAIN ... ']'
 //   '[' SHOW ... ']'
 //
@@ -13419,24 +14565,26 @@ opt_index_flags:
 //
 // %SeeAlso: WEBDOCS/table-expressions.html
 table_ref:
-  numeric_table_ref opt_index_flags opt_ordinality opt_alias_clause
+  numeric_table_ref opt_index_flags opt_ordinality opt_alias_clause opt_tablesample_clause
   {
     /* SKIP DOC */
     $$.val = &tree.AliasedTableExpr{
-        Expr:       $1.tblExpr(),
-        IndexFlags: $2.indexFlags(),
-        Ordinality: $3.bool(),
-        As:         $4.aliasClause(),
+        Expr:        $1.tblExpr(),
+        IndexFlags:  $2.indexFlags(),
+        Ordinality:  $3.bool(),
+        As:          $4.aliasClause(),
+        TableSample: $5.tableSample(),
     }
   }
-| relation_expr opt_index_flags opt_ordinality opt_alias_clause
+| relation_expr opt_index_flags opt_ordinality opt_alias_clause opt_tablesample_clause
   {
     name := $1.unresolvedObjectName().ToTableName()
     $$.val = &tree.AliasedTableExpr{
-      Expr:       &name,
-      IndexFlags: $2.indexFlags(),
-      Ordinality: $3.bool(),
-      As:         $4.aliasClause(),
+      Expr:        &name,
+      IndexFlags:  $2.indexFlags(),
+      Ordinality:  $3.bool(),
+      As:          $4.aliasClause(),
+      TableSample: $5.tableSample(),
     }
   }
 | select_with_parens opt_ordinality opt_alias_clause
@@ -13613,6 +14761,26 @@ opt_ordinality:
     $$.val = false
   }
 
+opt_tablesample_clause:
+  TABLESAMPLE name '(' expr_list ')' opt_repeatable_clause
+  {
+    $$.val = &tree.TableSample{Method: tree.Name($2), Args: $4.exprs(), Repeatable: $6.expr()}
+  }
+| /* EMPTY */
+  {
+    $$.val = (*tree.TableSample)(nil)
+  }
+
+opt_repeatable_clause:
+  REPEATABLE '(' a_expr ')'
+  {
+    $$.val = $3.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
 // It may seem silly to separate joined_table from table_ref, but there is
 // method in SQL's madness: if you don't do it this way you get reduce- reduce
 // conflicts, because it's not clear to the parser generator whether to expect
@@ -13800,6 +14968,25 @@ relation_expr_list:
     $$.val = append($1.tableNames(), name)
   }
 
+// LISTEN
+listen_stmt:
+  LISTEN name
+  {
+    $$.val = &tree.Listen{ChannelName: tree.Name($2)}
+  }
+
+// NOTIFY
+notify_stmt:
+  NOTIFY name
+  {
+    $$.val = &tree.Notify{ChannelName: tree.Name($2)}
+  }
+| NOTIFY name ',' SCONST
+  {
+    payload := $4
+    $$.val = &tree.Notify{ChannelName: tree.Name($2), Payload: &payload}
+  }
+
 // UNLISTEN
 unlisten_stmt:
    UNLISTEN type_name
@@ -14689,10 +15876,18 @@ a_expr:
   {
     $$.val = &tree.ComparisonExpr{Operator: treecmp.MakeComparisonOperator(treecmp.Overlaps), Left: $1.expr(), Right: $3.expr()}
   }
+| a_expr ADJACENT a_expr
+  {
+    $$.val = &tree.ComparisonExpr{Operator: treecmp.MakeComparisonOperator(treecmp.Adjacent), Left: $1.expr(), Right: $3.expr()}
+  }
 | a_expr AT_AT a_expr
   {
     $$.val = &tree.ComparisonExpr{Operator: treecmp.MakeComparisonOperator(treecmp.TSMatches), Left: $1.expr(), Right: $3.expr()}
   }
+| a_expr JSON_PATH_EXISTS a_expr
+  {
+    $$.val = &tree.ComparisonExpr{Operator: treecmp.MakeComparisonOperator(treecmp.JSONPathExists), Left: $1.expr(), Right: $3.expr()}
+  }
 | a_expr INET_CONTAINS_OR_EQUALS a_expr
   {
     $$.val = &tree.FuncExpr{Func: tree.WrapFunction("inet_contains_or_equals"), Exprs: tree.Exprs{$1.expr(), $3.expr()}}
@@ -15247,7 +16442,10 @@ d_expr:
   {
     $$.val = $2.expr()
   }
-| GROUPING '(' expr_list ')' { return unimplemented(sqllex, "d_expr grouping") }
+| GROUPING '(' expr_list ')'
+  {
+    $$.val = &tree.FuncExpr{Func: tree.WrapFunction("grouping"), Exprs: $3.exprs()}
+  }
 
 func_application:
   func_application_name '(' ')'
@@ -15258,8 +16456,14 @@ func_application:
   {
     $$.val = &tree.FuncExpr{Func: $1.resolvableFuncRef(), Exprs: $3.exprs(), OrderBy: $4.orderBy(), AggType: tree.GeneralAgg}
   }
-| func_application_name '(' VARIADIC a_expr opt_sort_clause_no_index ')' { return unimplemented(sqllex, "variadic") }
-| func_application_name '(' expr_list ',' VARIADIC a_expr opt_sort_clause_no_index ')' { return unimplemented(sqllex, "variadic") }
+| func_application_name '(' VARIADIC a_expr opt_sort_clause_no_index ')'
+  {
+    $$.val = &tree.FuncExpr{Func: $1.resolvableFuncRef(), Exprs: tree.Exprs{$4.expr()}, OrderBy: $5.orderBy(), AggType: tree.GeneralAgg, Variadic: true}
+  }
+| func_application_name '(' expr_list ',' VARIADIC a_expr opt_sort_clause_no_index ')'
+  {
+    $$.val = &tree.FuncExpr{Func: $1.resolvableFuncRef(), Exprs: append($3.exprs(), $6.expr()), OrderBy: $7.orderBy(), AggType: tree.GeneralAgg, Variadic: true}
+  }
 | func_application_name '(' ALL expr_list opt_sort_clause_no_index ')'
   {
     $$.val = &tree.FuncExpr{Func: $1.resolvableFuncRef(), Type: tree.AllFuncType, Exprs: $4.exprs(), OrderBy: $5.orderBy(), AggType: tree.GeneralAgg}
@@ -15904,7 +17108,9 @@ all_op:
 | REGIMATCH { $$.val = treecmp.MakeComparisonOperator(treecmp.RegIMatch) }
 | NOT_REGIMATCH { $$.val = treecmp.MakeComparisonOperator(treecmp.NotRegIMatch) }
 | AND_AND { $$.val = treecmp.MakeComparisonOperator(treecmp.Overlaps) }
+| ADJACENT { $$.val = treecmp.MakeComparisonOperator(treecmp.Adjacent) }
 | AT_AT { $$.val = treecmp.MakeComparisonOperator(treecmp.TSMatches) }
+| JSON_PATH_EXISTS { $$.val = treecmp.MakeComparisonOperator(treecmp.JSONPathExists) }
 | '~' { $$.val = tree.MakeUnaryOperator(tree.UnaryComplement) }
 | SQRT { $$.val = tree.MakeUnaryOperator(tree.UnarySqrt) }
 | CBRT { $$.val = tree.MakeUnaryOperator(tree.UnaryCbrt) }
@@ -16867,6 +18073,7 @@ unreserved_keyword:
 | BUCKET_COUNT
 | BUNDLE
 | BY
+| BYPASSRLS
 | CACHE
 | CALL
 | CALLED
@@ -16928,10 +18135,14 @@ unreserved_keyword:
 | DESTINATION
 | DETACHED
 | DETAILS
+| DICTIONARY
+| DISABLE
 | DISCARD
 | DOMAIN
 | DOUBLE
 | DROP
+| EACH
+| ENABLE
 | ENCODING
 | ENCRYPTED
 | ENCRYPTION_PASSPHRASE
@@ -17001,12 +18212,14 @@ unreserved_keyword:
 | INCREMENT
 | INCREMENTAL
 | INCREMENTAL_LOCATION
+| INCREMENTALLY
 | INDEX
 | INDEXES
 | INHERITS
 | INJECT
 | INPUT
 | INSERT
+| INSTEAD
 | INTO_DB
 | INVERTED
 | INVISIBLE
@@ -17034,13 +18247,16 @@ unreserved_keyword:
 | LINESTRINGZ
 | LINESTRINGZM
 | LIST
+| LISTEN
 | LOCAL
 | LOCKED
 | LOGIN
 | LOCALITY
 | LOOKUP
 | LOW
+| MAPPING
 | MATCH
+| MATCHED
 | MATERIALIZED
 | MAXVALUE
 | MERGE
@@ -17072,6 +18288,7 @@ unreserved_keyword:
 | NO
 | NORMAL
 | NOTHING
+| NOTIFY
 | NO_INDEX_JOIN
 | NO_ZIGZAG_JOIN
 | NO_FULL_SCAN
@@ -17089,6 +18306,7 @@ unreserved_keyword:
 | NOVIEWACTIVITY
 | NOVIEWACTIVITYREDACTED
 | NOVIEWCLUSTERSETTING
+| NOBYPASSRLS
 | NOWAIT
 | NULLS
 | IGNORE_FOREIGN_KEYS
@@ -17108,12 +18326,14 @@ unreserved_keyword:
 | OWNER
 | PARALLEL
 | PARENT
+| PARSER
 | PARTIAL
 | PARTITION
 | PARTITIONS
 | PASSWORD
 | PAUSE
 | PAUSED
+| PERMISSIVE
 | PHYSICAL
 | PLACEMENT
 | PLAN
@@ -17121,6 +18341,7 @@ unreserved_keyword:
 | POINTM
 | POINTZ
 | POINTZM
+| POLICY
 | POLYGONM
 | POLYGONZ
 | POLYGONZM
@@ -17164,6 +18385,7 @@ unreserved_keyword:
 | RESTORE
 | RESTRICT
 | RESTRICTED
+| RESTRICTIVE
 | RESUME
 | RETENTION
 | RETRY
@@ -17224,6 +18446,7 @@ unreserved_keyword:
 | STABLE
 | START
 | STATE
+| STATEMENT
 | STATEMENTS
 | STATISTICS
 | STDIN
@@ -17236,6 +18459,7 @@ unreserved_keyword:
 | STREAM
 | STRICT
 | SUBSCRIPTION
+| SUBSCRIPTIONS
 | SUPER
 | SUPPORT
 | SURVIVE
@@ -17349,6 +18573,7 @@ bare_label_keywords:
 | BUCKET_COUNT
 | BUNDLE
 | BY
+| BYPASSRLS
 | CACHE
 | CALL
 | CALLED
@@ -17431,13 +18656,17 @@ bare_label_keywords:
 | DESTINATION
 | DETACHED
 | DETAILS
+| DICTIONARY
+| DISABLE
 | DISCARD
 | DISTINCT
 | DO
 | DOMAIN
 | DOUBLE
 | DROP
+| EACH
 | ELSE
+| ENABLE
 | ENCODING
 | ENCRYPTED
 | ENCRYPTION_INFO_DIR
@@ -17524,6 +18753,7 @@ bare_label_keywords:
 | INCREMENT
 | INCREMENTAL
 | INCREMENTAL_LOCATION
+| INCREMENTALLY
 | INDEX
 | INDEXES
 | INDEX_AFTER_ORDER_BY_BEFORE_AT
@@ -17537,6 +18767,7 @@ bare_label_keywords:
 | INPUT
 | INSENSITIVE
 | INSERT
+| INSTEAD
 | INT
 | INTEGER
 | INTERVAL
@@ -17575,6 +18806,7 @@ bare_label_keywords:
 | LINESTRINGZ
 | LINESTRINGZM
 | LIST
+| LISTEN
 | LOCAL
 | LOCALITY
 | LOCALTIME
@@ -17583,7 +18815,9 @@ bare_label_keywords:
 | LOGIN
 | LOOKUP
 | LOW
+| MAPPING
 | MATCH
+| MATCHED
 | MATERIALIZED
 | MAXVALUE
 | MERGE
@@ -17612,6 +18846,7 @@ bare_label_keywords:
 | NEW_KMS
 | NEXT
 | NO
+| NOBYPASSRLS
 | NOCANCELQUERY
 | NOCONTROLCHANGEFEED
 | NOCONTROLJOB
@@ -17628,6 +18863,7 @@ bare_label_keywords:
 | NOT
 | NOTHING
 | NOTHING_AFTER_RETURNING
+| NOTIFY
 | NOVIEWACTIVITY
 | NOVIEWACTIVITYREDACTED
 | NOVIEWCLUSTERSETTING
@@ -17658,12 +18894,14 @@ bare_label_keywords:
 | OWNER
 | PARALLEL
 | PARENT
+| PARSER
 | PARTIAL
 | PARTITION
 | PARTITIONS
 | PASSWORD
 | PAUSE
 | PAUSED
+| PERMISSIVE
 | PHYSICAL
 | PLACEMENT
 | PLACING
@@ -17673,6 +18911,7 @@ bare_label_keywords:
 | POINTM
 | POINTZ
 | POINTZM
+| POLICY
 | POLYGON
 | POLYGONM
 | POLYGONZ
@@ -17721,6 +18960,7 @@ bare_label_keywords:
 | RESTORE
 | RESTRICT
 | RESTRICTED
+| RESTRICTIVE
 | RESUME
 | RETENTION
 | RETRY
@@ -17787,6 +19027,7 @@ bare_label_keywords:
 | STABLE
 | START
 | STATE
+| STATEMENT
 | STATEMENTS
 | STATISTICS
 | STATUS
@@ -17801,6 +19042,7 @@ bare_label_keywords:
 | STRICT
 | STRING
 | SUBSCRIPTION
+| SUBSCRIPTIONS
 | SUBSTRING
 | SUPER
 | SUPPORT
@@ -17811,6 +19053,7 @@ bare_label_keywords:
 | SYSTEM
 | TABLE
 | TABLES
+| TABLESAMPLE
 | TABLESPACE
 | TEMP
 | TEMPLATE
@@ -17992,6 +19235,7 @@ type_func_name_no_crdb_extra_keyword:
 | OVERLAPS
 | RIGHT
 | SIMILAR
+| TABLESAMPLE
 
 // CockroachDB-specific keywords that can be used in type/function
 // identifiers.
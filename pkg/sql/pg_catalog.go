@@ -21,6 +21,8 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
@@ -637,6 +639,10 @@ https://www.postgresql.org/docs/9.5/catalog-pg-authid.html`,
 			if err != nil {
 				return err
 			}
+			bypassRLS, err := options.bypassRLS()
+			if err != nil {
+				return err
+			}
 
 			isSuper, err := userIsSuper(ctx, p, userName)
 			if err != nil {
@@ -652,7 +658,7 @@ https://www.postgresql.org/docs/9.5/catalog-pg-authid.html`,
 				tree.MakeDBool(isRoot || createDB),   // rolcreatedb
 				tree.MakeDBool(roleCanLogin),         // rolcanlogin.
 				tree.DBoolFalse,                      // rolreplication
-				tree.DBoolFalse,                      // rolbypassrls
+				tree.MakeDBool(isRoot || bypassRLS),  // rolbypassrls
 				negOneVal,                            // rolconnlimit
 				passwdStarString,                     // rolpassword
 				rolValidUntil,                        // rolvaliduntil
@@ -747,6 +753,8 @@ https://www.postgresql.org/docs/9.5/catalog-pg-class.html`,
 		if table.IsTemporary() {
 			relPersistence = relPersistenceTemporary
 		}
+		relRowSecurity := tree.MakeDBool(tree.DBool(table.IsRowLevelSecurityEnabled()))
+		relForceRowSecurity := tree.MakeDBool(tree.DBool(table.IsRowLevelSecurityForced()))
 		var relOptions tree.Datum = tree.DNull
 		if storageParams := table.GetStorageParams(false /* spaceBetweenEqual */); len(storageParams) > 0 {
 			relOptionsArr := tree.NewDArray(types.String)
@@ -793,12 +801,12 @@ https://www.postgresql.org/docs/9.5/catalog-pg-class.html`,
 			tree.DNull,      // relacl
 			relOptions,      // reloptions
 			// These columns were automatically created by pg_catalog_test's missing column generator.
-			tree.DNull,                 // relforcerowsecurity
+			relForceRowSecurity,        // relforcerowsecurity
 			tree.DNull,                 // relispartition
 			tree.DNull,                 // relispopulated
 			tree.NewDString(replIdent), // relreplident
 			tree.DNull,                 // relrewrite
-			tree.DNull,                 // relrowsecurity
+			relRowSecurity,             // relrowsecurity
 			tree.DNull,                 // relpartbound
 			// These columns were automatically created by pg_catalog_test's missing column generator.
 			tree.DNull, // relminmxid
@@ -912,7 +920,6 @@ var (
 
 	// Avoid unused warning for constants.
 	_ = conTypeTrigger
-	_ = conTypeExclusion
 
 	fkActionNone       = tree.NewDString("a")
 	fkActionRestrict   = tree.NewDString("r")
@@ -964,6 +971,7 @@ func populateTableConstraints(
 		consrc := tree.DNull
 		conbin := tree.DNull
 		condef := tree.DNull
+		var deferrability semenumpb.Deferrability
 
 		// Determine constraint kind-specific fields.
 		var err error
@@ -1026,6 +1034,7 @@ func populateTableConstraints(
 				conindid = h.IndexOid(referencedTable.GetID(), idx.GetID())
 			}
 			confrelid = tableOid(referencedTable.GetID())
+			deferrability = fk.Deferrability()
 			if r, ok := fkActionMap[fk.OnUpdate()]; ok {
 				confupdtype = r
 			}
@@ -1064,6 +1073,8 @@ func populateTableConstraints(
 			}
 			f.WriteString(strings.Join(colNames, ", "))
 			f.WriteByte(')')
+			deferrability = uwoi.Deferrability()
+			f.FormatNode(tree.ConstraintDeferrabilityType[deferrability])
 			if !uwoi.IsConstraintValidated() {
 				f.WriteString(" NOT VALID")
 			}
@@ -1075,6 +1086,27 @@ func populateTableConstraints(
 				f.WriteString(fmt.Sprintf(" WHERE (%s)", pred))
 			}
 			condef = tree.NewDString(f.CloseAndGetString())
+		} else if excl := c.AsExclusion(); excl != nil {
+			contype = conTypeExclusion
+			conoid = h.ExclusionConstraintOid(db.GetID(), sc.GetID(), table.GetID(), excl)
+			if conkey, err = colIDArrayToDatum(excl.ExclusionDesc().ColumnIDs); err != nil {
+				return err
+			}
+			f := tree.NewFmtCtx(tree.FmtSimple)
+			if err := formatExclusionConstraintElems(table, excl, f); err != nil {
+				return err
+			}
+			if excl.IsPartial() {
+				pred, err := schemaexpr.FormatExprForDisplay(ctx, table, excl.GetPredicate(), p.SemaCtx(), p.SessionData(), tree.FmtPGCatalog)
+				if err != nil {
+					return err
+				}
+				f.WriteString(fmt.Sprintf(" WHERE (%s)", pred))
+			}
+			if !excl.IsConstraintValidated() {
+				f.WriteString(" NOT VALID")
+			}
+			condef = tree.NewDString(f.CloseAndGetString())
 		} else if ck := c.AsCheck(); ck != nil {
 			conoid = h.CheckConstraintOid(db.GetID(), sc.GetID(), table.GetID(), ck)
 			contype = conTypeCheck
@@ -1093,14 +1125,16 @@ func populateTableConstraints(
 			}
 			condef = tree.NewDString(fmt.Sprintf("CHECK ((%s))%s", displayExpr, validity))
 		}
+		condeferrable := tree.MakeDBool(deferrability != semenumpb.Deferrability_NOT_DEFERRABLE)
+		condeferred := tree.MakeDBool(deferrability == semenumpb.Deferrability_INITIALLY_DEFERRED)
 
 		if err := addRow(
 			conoid,                   // oid
 			dNameOrNull(c.GetName()), // conname
 			namespaceOid,             // connamespace
 			contype,                  // contype
-			tree.DBoolFalse,          // condeferrable
-			tree.DBoolFalse,          // condeferred
+			condeferrable,            // condeferrable
+			condeferred,              // condeferred
 			tree.MakeDBool(tree.DBool(!c.IsConstraintUnvalidated())), // convalidated
 			tblOid,         // conrelid
 			oidZero,        // contypid
@@ -1755,6 +1789,13 @@ func getOIDFromConstraint(
 			tableID,
 			uc,
 		)
+	} else if excl := constraint.AsExclusion(); excl != nil {
+		oid = hasher.ExclusionConstraintOid(
+			dbID,
+			scID,
+			tableID,
+			excl,
+		)
 	} else if ic := constraint.AsUniqueWithIndex(); ic != nil {
 		if ic.GetID() == tableDesc.GetPrimaryIndexID() {
 			oid = hasher.PrimaryKeyConstraintOid(
@@ -2127,13 +2168,38 @@ https://www.postgresql.org/docs/9.5/catalog-pg-language.html`,
 }
 
 var pgCatalogLocksTable = virtualSchemaTable{
-	comment: `locks held by active processes (empty - feature does not exist)
+	comment: `advisory locks held or awaited by the sessions on this node
 https://www.postgresql.org/docs/9.6/view-pg-locks.html`,
 	schema: vtable.PGCatalogLocks,
 	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		registry := p.ExecCfg().AdvisoryLockRegistry
+		if registry == nil {
+			return nil
+		}
+		advisoryLockType := tree.NewDString("advisory")
+		for _, l := range registry.locks() {
+			if err := addRow(
+				advisoryLockType,                        // locktype
+				dbOid(l.databaseID),                     // database
+				tree.DNull,                              // relation
+				tree.DNull,                              // page
+				tree.DNull,                              // tuple
+				tree.DNull,                              // virtualxid
+				tree.DNull,                              // transactionid
+				tree.NewDOid(oid.Oid(l.key.ClassID)),    // classid
+				tree.NewDOid(oid.Oid(l.key.ObjID)),      // objid
+				tree.NewDInt(tree.DInt(l.key.ObjSubID)), // objsubid
+				tree.DNull,                              // virtualtransaction
+				tree.NewDInt(tree.DInt(l.pid)),          // pid
+				tree.NewDString(l.mode.String()),        // mode
+				tree.MakeDBool(tree.DBool(l.granted)),   // granted
+				tree.DBoolFalse,                         // fastpath
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
-	unimplemented: true,
 }
 
 var pgCatalogMatViewsTable = virtualSchemaTable{
@@ -2593,17 +2659,39 @@ func addPgProcUDFRow(
 	addRow func(...tree.Datum) error,
 ) error {
 	isStrict := fnDesc.GetNullInputBehavior() != catpb.Function_CALLED_ON_NULL_INPUT
+	// argTypes only contains the types of the parameters in the signature of
+	// the routine, while allArgTypes contains the types of all parameters. The
+	// latter is only reported if there are parameters that are not IN.
 	argTypes := tree.NewDArray(types.Oid)
+	allArgTypes := tree.NewDArray(types.Oid)
+	foundNonInArgs := false
+	variadicType := oidZero
 	argModes := tree.NewDArray(types.String)
 	var argNames tree.Datum
 	argNamesArray := tree.NewDArray(types.String)
 	foundAnyArgNames := false
 	for _, param := range fnDesc.GetParams() {
-		if err := argTypes.Append(tree.NewDOid(param.Type.Oid())); err != nil {
+		typOid := tree.NewDOid(param.Type.Oid())
+		if param.Class != catpb.Function_Param_OUT || fnDesc.IsProcedure() {
+			if err := argTypes.Append(typOid); err != nil {
+				return err
+			}
+		}
+		if err := allArgTypes.Append(typOid); err != nil {
 			return err
 		}
-		// We only support IN arguments at the moment.
-		if err := argModes.Append(tree.NewDString("i")); err != nil {
+		mode := "i"
+		switch param.Class {
+		case catpb.Function_Param_OUT:
+			mode = "o"
+		case catpb.Function_Param_IN_OUT:
+			mode = "b"
+		case catpb.Function_Param_VARIADIC:
+			mode = "v"
+			variadicType = tree.NewDOid(param.Type.ArrayContents().Oid())
+		}
+		foundNonInArgs = foundNonInArgs || mode != "i"
+		if err := argModes.Append(tree.NewDString(mode)); err != nil {
 			return err
 		}
 		if len(param.Name) > 0 {
@@ -2617,10 +2705,16 @@ func addPgProcUDFRow(
 	if foundAnyArgNames {
 		argNames = argNamesArray
 	}
+	var allArgTypesDatum tree.Datum = tree.DNull
+	if foundNonInArgs {
+		allArgTypesDatum = allArgTypes
+	}
 
 	kind := tree.NewDString("f")
 	if fnDesc.IsProcedure() {
 		kind = tree.NewDString("p")
+	} else if fnDesc.IsAggregate() {
+		kind = tree.NewDString("a")
 	}
 
 	lang := languageInternalOid
@@ -2637,7 +2731,7 @@ func addPgProcUDFRow(
 		lang,            // prolang
 		tree.DNull,      // procost
 		tree.DNull,      // prorows
-		oidZero,         // provariadic
+		variadicType,    // provariadic
 		tree.DNull,      // protransform
 		tree.DBoolFalse, // proisagg
 		tree.DBoolFalse, // proiswindow
@@ -2646,21 +2740,21 @@ func addPgProcUDFRow(
 		tree.MakeDBool(tree.DBool(isStrict)),                         // proisstrict
 		tree.MakeDBool(tree.DBool(fnDesc.GetReturnType().ReturnSet)), // proretset
 		tree.NewDString(funcVolatility(fnDesc.GetVolatility())),      // provolatile
-		tree.DNull, // proparallel
-		tree.NewDInt(tree.DInt(len(fnDesc.GetParams()))), // pronargs
-		tree.NewDInt(tree.DInt(0)),                       // pronargdefaults
-		tree.NewDOid(fnDesc.GetReturnType().Type.Oid()),  // prorettype
-		tree.NewDOidVectorFromDArray(argTypes),           // proargtypes
-		tree.DNull,                                       // proallargtypes
-		argModes,                                         // proargmodes
-		argNames,                                         // proargnames
-		tree.DNull,                                       // proargdefaults
-		tree.DNull,                                       // protrftypes
-		tree.NewDString(fnDesc.GetFunctionBody()),        // prosrc
-		tree.DNull,                                       // probin
-		tree.DNull,                                       // proconfig
-		tree.DNull,                                       // proacl
-		kind,                                             // prokind
+		tree.DNull,                                      // proparallel
+		tree.NewDInt(tree.DInt(argTypes.Len())),         // pronargs
+		tree.NewDInt(tree.DInt(0)),                      // pronargdefaults
+		tree.NewDOid(fnDesc.GetReturnType().Type.Oid()), // prorettype
+		tree.NewDOidVectorFromDArray(argTypes),          // proargtypes
+		allArgTypesDatum,                                // proallargtypes
+		argModes,                                        // proargmodes
+		argNames,                                        // proargnames
+		tree.DNull,                                      // proargdefaults
+		tree.DNull,                                      // protrftypes
+		tree.NewDString(fnDesc.GetFunctionBody()),       // prosrc
+		tree.DNull,                                      // probin
+		tree.DNull,                                      // proconfig
+		tree.DNull,                                      // proacl
+		kind,                                            // prokind
 		// These columns were automatically created by pg_catalog_test's missing column generator.
 		tree.DNull, // prosupport
 	)
@@ -2768,16 +2862,24 @@ https://www.postgresql.org/docs/9.5/catalog-pg-proc.html`,
 }
 
 var pgCatalogRangeTable = virtualSchemaTable{
-	comment: `range types (empty - feature does not exist)
+	comment: `range types
 https://www.postgresql.org/docs/9.5/catalog-pg-range.html`,
 	schema: vtable.PGCatalogRange,
 	populate: func(_ context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		// We currently do not support any range types, so this table is empty.
-		// This table should be populated when any range types are added to
-		// oidToDatum (and therefore pg_type).
+		for _, typ := range types.RangeTypes {
+			if err := addRow(
+				tree.NewDOid(typ.Oid()),                 // rngtypid
+				tree.NewDOid(typ.RangeContents().Oid()), // rngsubtype
+				oidZero,                                 // rngcollation
+				oidZero,                                 // rngsubopc
+				oidZero,                                 // rngcanonical
+				oidZero,                                 // rngsubdiff
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
-	unimplemented: true,
 }
 
 var pgCatalogRewriteTable = virtualSchemaTable{
@@ -2849,6 +2951,10 @@ https://www.postgresql.org/docs/9.5/view-pg-roles.html`,
 				if err != nil {
 					return err
 				}
+				bypassRLS, err := options.bypassRLS()
+				if err != nil {
+					return err
+				}
 				isSuper, err := userIsSuper(ctx, p, userName)
 				if err != nil {
 					return err
@@ -2867,7 +2973,7 @@ https://www.postgresql.org/docs/9.5/view-pg-roles.html`,
 					negOneVal,                             // rolconnlimit
 					passwdStarString,                      // rolpassword
 					rolValidUntil,                         // rolvaliduntil
-					tree.DBoolFalse,                       // rolbypassrls
+					tree.MakeDBool(isSuper || bypassRLS),  // rolbypassrls
 					settings,                              // rolconfig
 				)
 			})
@@ -3158,29 +3264,120 @@ https://www.postgresql.org/docs/9.5/catalog-pg-tablespace.html`,
 	},
 }
 
+// Bits of pg_trigger.tgtype, which encodes the type of a trigger.
+// See src/include/catalog/pg_trigger.h in Postgres.
+const (
+	tgTypeRow      = 1 << 0
+	tgTypeBefore   = 1 << 1
+	tgTypeInsert   = 1 << 2
+	tgTypeDelete   = 1 << 3
+	tgTypeUpdate   = 1 << 4
+	tgTypeTruncate = 1 << 5
+	tgTypeInstead  = 1 << 6
+)
+
+var (
+	tgEnabledOrigin   = tree.NewDString("O")
+	tgEnabledDisabled = tree.NewDString("D")
+)
+
 var pgCatalogTriggerTable = virtualSchemaTable{
-	comment: `triggers (empty - feature does not exist)
+	comment: `triggers
 https://www.postgresql.org/docs/9.5/catalog-pg-trigger.html`,
 	schema: vtable.PGCatalogTrigger,
 	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		// Triggers are unsupported.
-		return nil
+		h := makeOidHasher()
+		return forEachTableDesc(ctx, p, dbContext, hideVirtual, /* virtual tables have no triggers */
+			func(_ catalog.DatabaseDescriptor, _ catalog.SchemaDescriptor, table catalog.TableDescriptor) error {
+				triggers := table.GetTriggers()
+				for i := range triggers {
+					trigger := &triggers[i]
+					var tgType int
+					if trigger.ForEachRow {
+						tgType |= tgTypeRow
+					}
+					switch trigger.ActionTime {
+					case semenumpb.TriggerActionTime_BEFORE:
+						tgType |= tgTypeBefore
+					case semenumpb.TriggerActionTime_INSTEAD_OF:
+						tgType |= tgTypeInstead
+					}
+					var updateCols []descpb.ColumnID
+					for _, ev := range trigger.Events {
+						switch ev.Type {
+						case semenumpb.TriggerEventType_INSERT:
+							tgType |= tgTypeInsert
+						case semenumpb.TriggerEventType_UPDATE:
+							tgType |= tgTypeUpdate
+							updateCols = append(updateCols, ev.ColumnIDs...)
+						case semenumpb.TriggerEventType_DELETE:
+							tgType |= tgTypeDelete
+						case semenumpb.TriggerEventType_TRUNCATE:
+							tgType |= tgTypeTruncate
+						}
+					}
+					tgEnabled := tgEnabledOrigin
+					if !trigger.Enabled {
+						tgEnabled = tgEnabledDisabled
+					}
+					tgAttr, err := colIDArrayToVector(updateCols)
+					if err != nil {
+						return err
+					}
+					if tgAttr == tree.DNull {
+						tgAttr = tree.NewDIntVectorFromDArray(tree.NewDArray(types.Int2))
+					}
+					// Postgres stores the arguments as a sequence of null-terminated
+					// strings.
+					var tgArgs []byte
+					for _, arg := range trigger.FuncArgs {
+						tgArgs = append(tgArgs, arg...)
+						tgArgs = append(tgArgs, 0)
+					}
+					tgQual := tree.DNull
+					if trigger.WhenExpr != "" {
+						tgQual = tree.NewDString(trigger.WhenExpr)
+					}
+					if err := addRow(
+						h.TriggerOid(table.GetID(), trigger.ID),         // oid
+						tableOid(table.GetID()),                         // tgrelid
+						tree.NewDName(trigger.Name),                     // tgname
+						tree.NewDOid(catid.FuncIDToOID(trigger.FuncID)), // tgfoid
+						tree.NewDInt(tree.DInt(tgType)),                 // tgtype
+						tgEnabled,                                       // tgenabled
+						tree.DBoolFalse,                                 // tgisinternal
+						oidZero,                                         // tgconstrrelid
+						oidZero,                                         // tgconstrindid
+						oidZero,                                         // tgconstraint
+						tree.DBoolFalse,                                 // tgdeferrable
+						tree.DBoolFalse,                                 // tginitdeferred
+						tree.NewDInt(tree.DInt(len(trigger.FuncArgs))), // tgnargs
+						tgAttr,                              // tgattr
+						tree.NewDBytes(tree.DBytes(tgArgs)), // tgargs
+						tgQual,                              // tgqual
+						tree.DNull,                          // tgoldtable
+						tree.DNull,                          // tgnewtable
+						oidZero,                             // tgparentid
+					); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
 	},
-	unimplemented: true,
 }
 
 var (
-	typTypeBase      = tree.NewDString("b")
-	typTypeComposite = tree.NewDString("c")
-	typTypeDomain    = tree.NewDString("d")
-	typTypeEnum      = tree.NewDString("e")
-	typTypePseudo    = tree.NewDString("p")
-	typTypeRange     = tree.NewDString("r")
+	typTypeBase       = tree.NewDString("b")
+	typTypeComposite  = tree.NewDString("c")
+	typTypeDomain     = tree.NewDString("d")
+	typTypeEnum       = tree.NewDString("e")
+	typTypePseudo     = tree.NewDString("p")
+	typTypeRange      = tree.NewDString("r")
+	typTypeMultirange = tree.NewDString("m")
 
 	// Avoid unused warning for constants.
-	_ = typTypeDomain
 	_ = typTypePseudo
-	_ = typTypeRange
 
 	// See https://www.postgresql.org/docs/9.6/static/catalog-pg-type.html#CATALOG-TYPCATEGORY-TABLE.
 	typCategoryArray       = tree.NewDString("A")
@@ -3202,7 +3399,6 @@ var (
 	// Avoid unused warning for constants.
 	_ = typCategoryEnum
 	_ = typCategoryGeometric
-	_ = typCategoryRange
 	_ = typCategoryBitString
 
 	commaTypDelim = tree.NewDString(",")
@@ -3277,8 +3473,17 @@ func addPGTypeRow(
 	typArray := oidZero
 	builtinPrefix := builtins.PGIOBuiltinPrefix(typ)
 	typrelid := oidZero
-	switch typ.Family() {
-	case types.ArrayFamily:
+	typNotNull := tree.DBoolFalse
+	typBaseType := oidZero
+	switch {
+	case typ.IsDomain():
+		// Domains don't have array types.
+		typType = typTypeDomain
+		typBaseType = tree.NewDOid(typ.Oid())
+		if typ.TypeMeta.DomainData != nil && typ.TypeMeta.DomainData.NotNull {
+			typNotNull = tree.DBoolTrue
+		}
+	case typ.Family() == types.ArrayFamily:
 		switch typ.Oid() {
 		case oid.T_int2vector:
 			// IntVector needs a special case because it's a special snowflake
@@ -3296,11 +3501,11 @@ func addPGTypeRow(
 			builtinPrefix = "array_"
 			typElem = tree.NewDOid(typ.ArrayContents().Oid())
 		}
-	case types.EnumFamily:
+	case typ.Family() == types.EnumFamily:
 		builtinPrefix = "enum_"
 		typType = typTypeEnum
 		typArray = tree.NewDOid(types.CalcArrayOid(typ))
-	case types.TupleFamily:
+	case typ.Family() == types.TupleFamily:
 		builtinPrefix = "record_"
 		typType = typTypeComposite
 		typArray = tree.NewDOid(types.CalcArrayOid(typ))
@@ -3308,8 +3513,14 @@ func addPGTypeRow(
 		if isUDT {
 			typrelid = tree.NewDOid(typ.Oid())
 		}
-	case types.VoidFamily:
-		// void does not have an array type.
+	case typ.Family() == types.RangeFamily:
+		typType = typTypeRange
+		typArray = tree.NewDOid(types.CalcArrayOid(typ))
+	case typ.Family() == types.MultirangeFamily:
+		typType = typTypeMultirange
+		typArray = tree.NewDOid(types.CalcArrayOid(typ))
+	case typ.Family() == types.VoidFamily, typ.Family() == types.TriggerFamily:
+		// void and trigger do not have array types.
 	default:
 		typArray = tree.NewDOid(types.CalcArrayOid(typ))
 	}
@@ -3319,20 +3530,20 @@ func addPGTypeRow(
 	typname := typ.PGName()
 	typDelim := tree.NewDString(typ.Delimiter())
 	return addRow(
-		tree.NewDOid(typ.Oid()), // oid
-		tree.NewDName(typname),  // typname
-		nspOid,                  // typnamespace
-		owner,                   // typowner
-		typLen(typ),             // typlen
-		typByVal(typ),           // typbyval (is it fixedlen or not)
-		typType,                 // typtype
-		cat,                     // typcategory
-		tree.DBoolFalse,         // typispreferred
-		tree.DBoolTrue,          // typisdefined
-		typDelim,                // typdelim
-		typrelid,                // typrelid
-		typElem,                 // typelem
-		typArray,                // typarray
+		typOid(typ),            // oid
+		tree.NewDName(typname), // typname
+		nspOid,                 // typnamespace
+		owner,                  // typowner
+		typLen(typ),            // typlen
+		typByVal(typ),          // typbyval (is it fixedlen or not)
+		typType,                // typtype
+		cat,                    // typcategory
+		tree.DBoolFalse,        // typispreferred
+		tree.DBoolTrue,         // typisdefined
+		typDelim,               // typdelim
+		typrelid,               // typrelid
+		typElem,                // typelem
+		typArray,               // typarray
 
 		// regproc references
 		h.RegProc(builtinPrefix+"in"),   // typinput
@@ -3345,8 +3556,8 @@ func addPGTypeRow(
 
 		tree.DNull,      // typalign
 		tree.DNull,      // typstorage
-		tree.DBoolFalse, // typnotnull
-		oidZero,         // typbasetype
+		typNotNull,      // typnotnull
+		typBaseType,     // typbasetype
 		negOneVal,       // typtypmod
 		zeroVal,         // typndims
 		typColl(typ, h), // typcollation
@@ -3653,6 +3864,10 @@ https://www.postgresql.org/docs/9.5/view-pg-user.html`,
 				if err != nil {
 					return err
 				}
+				bypassRLS, err := options.bypassRLS()
+				if err != nil {
+					return err
+				}
 				isSuper, err := userIsSuper(ctx, p, userName)
 				if err != nil {
 					return err
@@ -3664,7 +3879,7 @@ https://www.postgresql.org/docs/9.5/view-pg-user.html`,
 					tree.MakeDBool(isSuper || createDB),  // usecreatedb
 					tree.MakeDBool(isRoot || isSuper),    // usesuper
 					tree.DBoolFalse,                      // userepl
-					tree.DBoolFalse,                      // usebypassrls
+					tree.MakeDBool(isSuper || bypassRLS), // usebypassrls
 					passwdStarString,                     // passwd
 					validUntil,                           // valuntil
 					settings,                             // useconfig
@@ -3775,6 +3990,10 @@ https://www.postgresql.org/docs/13/view-pg-shadow.html`,
 			if err != nil {
 				return err
 			}
+			bypassRLS, err := options.bypassRLS()
+			if err != nil {
+				return err
+			}
 			isSuper, err := userIsSuper(ctx, p, userName)
 			if err != nil {
 				return err
@@ -3786,7 +4005,7 @@ https://www.postgresql.org/docs/13/view-pg-shadow.html`,
 				tree.MakeDBool(isRoot || createDB),   // usecreatedb
 				tree.MakeDBool(isRoot || isSuper),    // usesuper
 				tree.DBoolFalse,                      // userepl
-				tree.DBoolFalse,                      // usebypassrls
+				tree.MakeDBool(isRoot || bypassRLS),  // usebypassrls
 				passwdStarString,                     // passwd
 				rolValidUntil,                        // valuntil
 				settings,                             // useconfig
@@ -4101,13 +4320,74 @@ var pgCatalogStatProgressBasebackupTable = virtualSchemaTable{
 	unimplemented: true,
 }
 
+var (
+	polCmdAll    = tree.NewDString("*")
+	polCmdSelect = tree.NewDString("r")
+	polCmdInsert = tree.NewDString("a")
+	polCmdUpdate = tree.NewDString("w")
+	polCmdDelete = tree.NewDString("d")
+)
+
 var pgCatalogPolicyTable = virtualSchemaTable{
-	comment: "pg_policy was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPolicy,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `row-level security policies
+https://www.postgresql.org/docs/16/catalog-pg-policy.html`,
+	schema: vtable.PgCatalogPolicy,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachTableDesc(ctx, p, dbContext, hideVirtual, /* virtual tables have no policies */
+			func(_ catalog.DatabaseDescriptor, _ catalog.SchemaDescriptor, table catalog.TableDescriptor) error {
+				policies := table.GetPolicies()
+				for i := range policies {
+					policy := &policies[i]
+					var polCmd tree.Datum
+					switch policy.Command {
+					case descpb.PolicyDescriptor_ALL:
+						polCmd = polCmdAll
+					case descpb.PolicyDescriptor_SELECT:
+						polCmd = polCmdSelect
+					case descpb.PolicyDescriptor_INSERT:
+						polCmd = polCmdInsert
+					case descpb.PolicyDescriptor_UPDATE:
+						polCmd = polCmdUpdate
+					case descpb.PolicyDescriptor_DELETE:
+						polCmd = polCmdDelete
+					}
+					// As in Postgres, the public role is represented by OID 0.
+					polRoles := tree.NewDArray(types.Oid)
+					for _, roleName := range policy.RoleNames {
+						roleOid := oidZero
+						if user := username.MakeSQLUsernameFromPreNormalizedString(roleName); !user.IsPublicRole() {
+							roleOid = h.UserOid(user)
+						}
+						if err := polRoles.Append(roleOid); err != nil {
+							return err
+						}
+					}
+					polQual := tree.DNull
+					if policy.UsingExpr != "" {
+						polQual = tree.NewDString(policy.UsingExpr)
+					}
+					polWithCheck := tree.DNull
+					if policy.WithCheckExpr != "" {
+						polWithCheck = tree.NewDString(policy.WithCheckExpr)
+					}
+					polPermissive := tree.MakeDBool(policy.Type == descpb.PolicyDescriptor_PERMISSIVE)
+					if err := addRow(
+						h.PolicyOid(table.GetID(), policy.ID), // oid
+						tree.NewDName(policy.Name),            // polname
+						tableOid(table.GetID()),               // polrelid
+						polCmd,                                // polcmd
+						polPermissive,                         // polpermissive
+						polRoles,                              // polroles
+						polQual,                               // polqual
+						polWithCheck,                          // polwithcheck
+					); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogStatArchiverTable = virtualSchemaTable{
@@ -4138,12 +4418,42 @@ var pgCatalogStatUserFunctionsTable = virtualSchemaTable{
 }
 
 var pgCatalogPublicationTable = virtualSchemaTable{
-	comment: "pg_publication was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublication,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `publications for logical replication
+https://www.postgresql.org/docs/16/catalog-pg-publication.html`,
+	schema: vtable.PgCatalogPublication,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachDatabaseDesc(ctx, p, dbContext, false, /* requiresPrivileges */
+			func(db catalog.DatabaseDescriptor) error {
+				pubs := db.GetPublications()
+				if len(pubs) == 0 {
+					return nil
+				}
+				// Publications are owned by the owner of their database.
+				ownerOid, err := getOwnerOID(ctx, p, db)
+				if err != nil {
+					return err
+				}
+				for i := range pubs {
+					pub := &pubs[i]
+					pubAllTables := tree.MakeDBool(tree.DBool(pub.AllTables))
+					if err := addRow(
+						h.PublicationOid(db.GetID(), pub.Name), // oid
+						tree.NewDName(pub.Name),                // pubname
+						ownerOid,                               // pubowner
+						pubAllTables,                           // puballtables
+						tree.DBoolTrue,                         // pubinsert
+						tree.DBoolTrue,                         // pubupdate
+						tree.DBoolTrue,                         // pubdelete
+						tree.DBoolFalse,                        // pubtruncate
+						tree.DBoolFalse,                        // pubviaroot
+					); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogAmprocTable = virtualSchemaTable{
@@ -4193,12 +4503,12 @@ https://www.postgresql.org/docs/14/view-pg-cursors.html`,
 				return err
 			}
 			if err := addRow(
-				tree.NewDString(string(name)), /* name */
-				tree.NewDString(c.statement),  /* statement */
-				tree.DBoolFalse,               /* is_holdable */
-				tree.DBoolFalse,               /* is_binary */
-				tree.DBoolFalse,               /* is_scrollable */
-				tz,                            /* creation_date */
+				tree.NewDString(string(name)),            /* name */
+				tree.NewDString(c.statement),             /* statement */
+				tree.MakeDBool(tree.DBool(c.withHold)),   /* is_holdable */
+				tree.DBoolFalse,                          /* is_binary */
+				tree.MakeDBool(tree.DBool(c.scrollable)), /* is_scrollable */
+				tz,                                       /* creation_date */
 			); err != nil {
 				return err
 			}
@@ -4298,12 +4608,31 @@ var pgCatalogStatXactUserTablesTable = virtualSchemaTable{
 }
 
 var pgCatalogPublicationTablesTable = virtualSchemaTable{
-	comment: "pg_publication_tables was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublicationTables,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `tables of publications, including the tables of publications FOR ALL TABLES
+https://www.postgresql.org/docs/16/view-pg-publication-tables.html`,
+	schema: vtable.PgCatalogPublicationTables,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return forEachTableDesc(ctx, p, dbContext, hideVirtual, /* virtual tables are not published */
+			func(db catalog.DatabaseDescriptor, sc catalog.SchemaDescriptor, table catalog.TableDescriptor) error {
+				if !table.IsPhysicalTable() || table.IsSequence() || table.IsTemporary() {
+					return nil
+				}
+				pubs := db.GetPublications()
+				for i := range pubs {
+					if !publicationIncludesTable(&pubs[i], table.GetID()) {
+						continue
+					}
+					if err := addRow(
+						tree.NewDName(pubs[i].Name),    // pubname
+						tree.NewDName(sc.GetName()),    // schemaname
+						tree.NewDName(table.GetName()), // tablename
+					); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogStatProgressClusterTable = virtualSchemaTable{
@@ -4334,12 +4663,61 @@ var pgCatalogLargeobjectMetadataTable = virtualSchemaTable{
 }
 
 var pgCatalogReplicationSlotsTable = virtualSchemaTable{
-	comment: "pg_replication_slots was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogReplicationSlots,
+	comment: `logical replication slots
+https://www.postgresql.org/docs/current/view-pg-replication-slots.html`,
+	schema: vtable.PgCatalogReplicationSlots,
 	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		slots, err := listReplicationSlots(ctx, p.InternalSQLTxn(), p.ExecCfg().ProtectedTimestampProvider)
+		if err != nil {
+			return err
+		}
+		if len(slots) == 0 {
+			return nil
+		}
+		dbs, err := p.Descriptors().GetAllDatabaseDescriptors(ctx, p.txn)
+		if err != nil {
+			return err
+		}
+		dbNames := make(map[descpb.ID]tree.Datum, len(dbs))
+		for _, db := range dbs {
+			dbNames[db.GetID()] = tree.NewDName(db.GetName())
+		}
+		logicalSlotType := tree.NewDString("logical")
+		reservedWALStatus := tree.NewDString("reserved")
+		for i := range slots {
+			slot := &slots[i]
+			dbName, ok := dbNames[slot.DatabaseID]
+			if !ok {
+				dbName = tree.DNull
+			}
+			active, activePID := tree.DBoolFalse, tree.DNull
+			if registry := p.ExecCfg().ReplicationSlotRegistry; registry != nil {
+				if pid, ok := registry.activePID(slot.Name); ok {
+					active, activePID = tree.DBoolTrue, tree.NewDInt(tree.DInt(pid))
+				}
+			}
+			confirmedFlushLSN := tree.NewDString(slot.confirmedFlushLSN().String())
+			if err := addRow(
+				tree.NewDName(slot.Name),   // slot_name
+				tree.NewDName(slot.Plugin), // plugin
+				logicalSlotType,            // slot_type
+				dbOid(slot.DatabaseID),     // datoid
+				dbName,                     // database
+				tree.DBoolFalse,            // temporary
+				active,                     // active
+				activePID,                  // active_pid
+				tree.DNull,                 // xmin
+				tree.DNull,                 // catalog_xmin
+				confirmedFlushLSN,          // restart_lsn
+				confirmedFlushLSN,          // confirmed_flush_lsn
+				reservedWALStatus,          // wal_status
+				tree.DNull,                 // safe_wal_size
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
-	unimplemented: true,
 }
 
 var pgCatalogSubscriptionRelTable = virtualSchemaTable{
@@ -4466,12 +4844,49 @@ var pgCatalogStatUserTablesTable = virtualSchemaTable{
 }
 
 var pgCatalogSubscriptionTable = virtualSchemaTable{
-	comment: "pg_subscription was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogSubscription,
+	comment: `logical replication subscriptions
+https://www.postgresql.org/docs/16/catalog-pg-subscription.html`,
+	schema: vtable.PgCatalogSubscription,
 	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		// As in Postgres, where subconninfo is only readable by superusers, the
+		// subscriptions are only shown to admins.
+		if hasAdmin, err := p.HasAdminRole(ctx); err != nil || !hasAdmin {
+			return err
+		}
+		subs, err := listSubscriptions(ctx, p.InternalSQLTxn())
+		if err != nil {
+			return err
+		}
+		h := makeOidHasher()
+		for i := range subs {
+			sub := &subs[i]
+			conninfo, err := redactConnectionURI(sub.details.ConnectionURI)
+			if err != nil {
+				return err
+			}
+			pubs := tree.NewDArray(types.String)
+			for _, pub := range sub.details.Publications {
+				if err := pubs.Append(tree.NewDString(pub)); err != nil {
+					return err
+				}
+			}
+			subEnabled := tree.MakeDBool(tree.DBool(sub.status != jobs.StatusPaused))
+			if err := addRow(
+				h.SubscriptionOid(sub.id),           // oid
+				dbOid(sub.details.DatabaseID),       // subdbid
+				tree.NewDName(sub.details.Name),     // subname
+				h.UserOid(sub.owner),                // subowner
+				subEnabled,                          // subenabled
+				tree.NewDString(conninfo),           // subconninfo
+				tree.NewDName(sub.details.SlotName), // subslotname
+				tree.NewDString("off"),              // subsynccommit
+				pubs,                                // subpublications
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
-	unimplemented: true,
 }
 
 var pgCatalogShmemAllocationsTable = virtualSchemaTable{
@@ -4610,12 +5025,32 @@ var pgCatalogStatReplicationTable = virtualSchemaTable{
 }
 
 var pgCatalogPublicationRelTable = virtualSchemaTable{
-	comment: "pg_publication_rel was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublicationRel,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `tables explicitly added to publications
+https://www.postgresql.org/docs/16/catalog-pg-publication-rel.html`,
+	schema: vtable.PgCatalogPublicationRel,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		// The tables are looked up rather than read from the publications, since
+		// the publications still refer to the tables that were dropped.
+		return forEachTableDesc(ctx, p, dbContext, hideVirtual, /* virtual tables are not published */
+			func(db catalog.DatabaseDescriptor, _ catalog.SchemaDescriptor, table catalog.TableDescriptor) error {
+				pubs := db.GetPublications()
+				for i := range pubs {
+					pub := &pubs[i]
+					if pub.AllTables || !publicationIncludesTable(pub, table.GetID()) {
+						continue
+					}
+					if err := addRow(
+						h.PublicationRelOid(db.GetID(), pub.Name, table.GetID()), // oid
+						h.PublicationOid(db.GetID(), pub.Name),                   // prpubid
+						tableOid(table.GetID()),                                  // prrelid
+					); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogAvailableExtensionVersionsTable = virtualSchemaTable{
@@ -4631,7 +5066,7 @@ var pgCatalogAvailableExtensionVersionsTable = virtualSchemaTable{
 // object identifiers for types are not arbitrary, but instead need to be kept in
 // sync with Postgres.
 func typOid(typ *types.T) tree.Datum {
-	return tree.NewDOid(typ.Oid())
+	return tree.NewDOid(typ.UserDefinedTypeOID())
 }
 
 func typLen(typ *types.T) *tree.DInt {
@@ -4679,6 +5114,9 @@ var datumToTypeCategory = map[types.Family]*tree.DString{
 	types.GeographyFamily:   typCategoryUserDefined,
 	types.GeometryFamily:    typCategoryUserDefined,
 	types.JsonFamily:        typCategoryUserDefined,
+	types.JsonpathFamily:    typCategoryUserDefined,
+	types.RangeFamily:       typCategoryRange,
+	types.MultirangeFamily:  typCategoryRange,
 	types.DecimalFamily:     typCategoryNumeric,
 	types.StringFamily:      typCategoryString,
 	types.TimestampFamily:   typCategoryDateTime,
@@ -4694,6 +5132,7 @@ var datumToTypeCategory = map[types.Family]*tree.DString{
 	types.INetFamily:        typCategoryNetworkAddr,
 	types.UnknownFamily:     typCategoryUnknown,
 	types.VoidFamily:        typCategoryPseudo,
+	types.TriggerFamily:     typCategoryPseudo,
 }
 
 func typCategory(typ *types.T) tree.Datum {
@@ -4907,6 +5346,12 @@ const (
 	rewriteTypeTag
 	dbSchemaRoleTypeTag
 	castTypeTag
+	triggerTypeTag
+	policyTypeTag
+	exclusionConstraintTypeTag
+	publicationTypeTag
+	publicationRelTypeTag
+	subscriptionTypeTag
 )
 
 func (h oidHasher) writeTypeTag(tag oidTypeTag) {
@@ -5012,6 +5457,17 @@ func (h oidHasher) UniqueWithoutIndexConstraintOid(
 	return h.getOid()
 }
 
+func (h oidHasher) ExclusionConstraintOid(
+	dbID descpb.ID, scID descpb.ID, tableID descpb.ID, excl catalog.ExclusionConstraint,
+) *tree.DOid {
+	h.writeTypeTag(exclusionConstraintTypeTag)
+	h.writeDB(dbID)
+	h.writeSchema(scID)
+	h.writeTable(tableID)
+	h.writeStr(excl.GetName())
+	return h.getOid()
+}
+
 func (h oidHasher) UniqueConstraintOid(
 	dbID descpb.ID, scID descpb.ID, tableID descpb.ID, uwi catalog.UniqueWithIndexConstraint,
 ) *tree.DOid {
@@ -5106,6 +5562,41 @@ func (h oidHasher) CastOid(srcID oid.Oid, tgtID oid.Oid) *tree.DOid {
 	return h.getOid()
 }
 
+func (h oidHasher) TriggerOid(tableID descpb.ID, triggerID descpb.TriggerID) *tree.DOid {
+	h.writeTypeTag(triggerTypeTag)
+	h.writeTable(tableID)
+	h.writeUInt32(uint32(triggerID))
+	return h.getOid()
+}
+
+func (h oidHasher) PolicyOid(tableID descpb.ID, policyID descpb.PolicyID) *tree.DOid {
+	h.writeTypeTag(policyTypeTag)
+	h.writeTable(tableID)
+	h.writeUInt32(uint32(policyID))
+	return h.getOid()
+}
+
+func (h oidHasher) PublicationOid(dbID descpb.ID, name string) *tree.DOid {
+	h.writeTypeTag(publicationTypeTag)
+	h.writeDB(dbID)
+	h.writeStr(name)
+	return h.getOid()
+}
+
+func (h oidHasher) PublicationRelOid(dbID descpb.ID, name string, tableID descpb.ID) *tree.DOid {
+	h.writeTypeTag(publicationRelTypeTag)
+	h.writeDB(dbID)
+	h.writeStr(name)
+	h.writeTable(tableID)
+	return h.getOid()
+}
+
+func (h oidHasher) SubscriptionOid(id jobspb.JobID) *tree.DOid {
+	h.writeTypeTag(subscriptionTypeTag)
+	h.writeUInt64(uint64(id))
+	return h.getOid()
+}
+
 func funcVolatility(v catpb.Function_Volatility) string {
 	switch v {
 	case catpb.Function_IMMUTABLE:
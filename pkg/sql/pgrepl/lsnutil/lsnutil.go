@@ -17,9 +17,34 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 )
 
+// logicalBits is the number of low bits of a LSN used to store the logical
+// component of a HLC timestamp. The remaining high bits store the wall time in
+// microseconds, which leaves enough room for the next few centuries.
+const logicalBits = 10
+
+// maxLogical is the largest logical component that can be represented in a
+// LSN. Larger logical components are clamped, so distinct timestamps that
+// only differ past this point map to the same LSN.
+const maxLogical = 1<<logicalBits - 1
+
 // HLCToLSN converts a HLC to a LSN.
 // It is in a separate package to prevent the `lsn` package importing `log`.
+//
+// The conversion is monotonic: if a <= b, then HLCToLSN(a) <= HLCToLSN(b).
 func HLCToLSN(h hlc.Timestamp) lsn.LSN {
-	// TODO(#105130): correctly populate this field.
-	return lsn.LSN(h.WallTime/int64(time.Millisecond)) << 32
+	logical := h.Logical
+	if logical > maxLogical {
+		logical = maxLogical
+	}
+	return lsn.LSN(h.WallTime/int64(time.Microsecond))<<logicalBits | lsn.LSN(logical)
+}
+
+// LSNToHLC converts a LSN back to a HLC. The returned timestamp is the
+// smallest timestamp that maps to the given LSN, so it never exceeds the
+// timestamp that the LSN was generated from.
+func LSNToHLC(l lsn.LSN) hlc.Timestamp {
+	return hlc.Timestamp{
+		WallTime: int64(l>>logicalBits) * int64(time.Microsecond),
+		Logical:  int32(l & maxLogical),
+	}
 }
@@ -0,0 +1,44 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package lsnutil
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHLCToLSN(t *testing.T) {
+	for _, tc := range []struct {
+		a, b hlc.Timestamp
+	}{
+		{a: hlc.Timestamp{WallTime: 1}, b: hlc.Timestamp{WallTime: 1000}},
+		{a: hlc.Timestamp{WallTime: 1e18}, b: hlc.Timestamp{WallTime: 1e18, Logical: 1}},
+		{a: hlc.Timestamp{WallTime: 1e18, Logical: 5000}, b: hlc.Timestamp{WallTime: 1e18 + 1000}},
+		{a: hlc.Timestamp{WallTime: 1.7e18}, b: hlc.Timestamp{WallTime: 1.7e18 + 1e9}},
+	} {
+		require.Less(t, HLCToLSN(tc.a), HLCToLSN(tc.b), "%s < %s", tc.a, tc.b)
+	}
+
+	// Converting a LSN back to a HLC yields a timestamp that maps to the same
+	// LSN and does not exceed the original timestamp.
+	for _, ts := range []hlc.Timestamp{
+		{WallTime: 1.7e18 + 123456789, Logical: 3},
+		{WallTime: 1.7e18, Logical: 5000},
+		{WallTime: 1000},
+	} {
+		l := HLCToLSN(ts)
+		back := LSNToHLC(l)
+		require.Equal(t, l, HLCToLSN(back))
+		require.True(t, back.LessEq(ts), "%s > %s", back, ts)
+	}
+}
@@ -0,0 +1,535 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package pgoutput encodes and decodes the messages sent while streaming
+// changes from a logical replication slot, using the format of Postgres'
+// pgoutput output plugin (protocol version 1).
+//
+// See https://www.postgresql.org/docs/current/protocol-logicalrep-message-formats.html
+// and https://www.postgresql.org/docs/current/protocol-replication.html.
+package pgoutput
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsn"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// PluginName is the name of the output plugin, as passed to
+// CREATE_REPLICATION_SLOT.
+const PluginName = "pgoutput"
+
+// ProtoVersion is the only supported version of the logical replication
+// protocol.
+const ProtoVersion = 1
+
+// Logical replication message types.
+const (
+	msgBegin    byte = 'B'
+	msgCommit   byte = 'C'
+	msgRelation byte = 'R'
+	msgInsert   byte = 'I'
+	msgUpdate   byte = 'U'
+	msgDelete   byte = 'D'
+)
+
+// Streaming replication message types, sent inside CopyData messages.
+const (
+	msgXLogData            byte = 'w'
+	msgPrimaryKeepalive    byte = 'k'
+	msgStandbyStatusUpdate byte = 'r'
+	msgHotStandbyFeedback  byte = 'h'
+)
+
+// Markers used in the encoding of tuples and relations.
+const (
+	tupleNew                  byte = 'N'
+	tupleKey                  byte = 'K'
+	tupleOld                  byte = 'O'
+	tupleColumnNull           byte = 'n'
+	tupleColumnText           byte = 't'
+	replicaIdentityDefault    byte = 'd'
+	relationColumnFlagKeyPart byte = 1
+)
+
+// pgEpoch is the epoch used by Postgres for the timestamps sent in the
+// replication protocol.
+var pgEpoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Column describes a column of a relation.
+type Column struct {
+	Name    string
+	TypeOID oid.Oid
+	TypeMod int32
+	// Key is set for the columns that are part of the replica identity of the
+	// relation, i.e. its primary key.
+	Key bool
+}
+
+// Relation describes a table whose changes are streamed. A Relation message
+// is sent before the first change of a table, and again whenever its schema
+// changes.
+type Relation struct {
+	ID        uint32
+	Namespace string
+	Name      string
+	Columns   []Column
+}
+
+// Value is the value of a column in a row.
+type Value struct {
+	Null bool
+	// Text is the value in the Postgres text format.
+	Text []byte
+}
+
+// AppendBegin appends a Begin message for a transaction that commits at the
+// given LSN and time.
+func AppendBegin(buf []byte, finalLSN lsn.LSN, commitTime time.Time, xid uint32) []byte {
+	buf = append(buf, msgBegin)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(finalLSN))
+	buf = appendTime(buf, commitTime)
+	return binary.BigEndian.AppendUint32(buf, xid)
+}
+
+// AppendCommit appends a Commit message for a transaction that commits at the
+// given LSN and time. endLSN is the position right after the transaction.
+func AppendCommit(buf []byte, commitLSN, endLSN lsn.LSN, commitTime time.Time) []byte {
+	buf = append(buf, msgCommit, 0 /* flags */)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(commitLSN))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(endLSN))
+	return appendTime(buf, commitTime)
+}
+
+// AppendRelation appends a Relation message describing the given relation.
+func AppendRelation(buf []byte, rel *Relation) []byte {
+	buf = append(buf, msgRelation)
+	buf = binary.BigEndian.AppendUint32(buf, rel.ID)
+	buf = appendString(buf, rel.Namespace)
+	buf = appendString(buf, rel.Name)
+	buf = append(buf, replicaIdentityDefault)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(rel.Columns)))
+	for _, col := range rel.Columns {
+		var flags byte
+		if col.Key {
+			flags |= relationColumnFlagKeyPart
+		}
+		buf = append(buf, flags)
+		buf = appendString(buf, col.Name)
+		buf = binary.BigEndian.AppendUint32(buf, uint32(col.TypeOID))
+		buf = binary.BigEndian.AppendUint32(buf, uint32(col.TypeMod))
+	}
+	return buf
+}
+
+// AppendInsert appends an Insert message for a new row of the given relation.
+func AppendInsert(buf []byte, relID uint32, row []Value) []byte {
+	buf = append(buf, msgInsert)
+	buf = binary.BigEndian.AppendUint32(buf, relID)
+	buf = append(buf, tupleNew)
+	return appendTuple(buf, row)
+}
+
+// AppendUpdate appends an Update message for a row of the given relation whose
+// primary key did not change. A primary key change is represented as the
+// deletion of the old row followed by the insertion of the new one.
+func AppendUpdate(buf []byte, relID uint32, row []Value) []byte {
+	buf = append(buf, msgUpdate)
+	buf = binary.BigEndian.AppendUint32(buf, relID)
+	buf = append(buf, tupleNew)
+	return appendTuple(buf, row)
+}
+
+// AppendDelete appends a Delete message for a row of the given relation. Only
+// the values of the key columns are required; the other values are expected to
+// be NULL.
+func AppendDelete(buf []byte, relID uint32, key []Value) []byte {
+	buf = append(buf, msgDelete)
+	buf = binary.BigEndian.AppendUint32(buf, relID)
+	buf = append(buf, tupleKey)
+	return appendTuple(buf, key)
+}
+
+// AppendXLogData appends the header of a XLogData message, which must be
+// followed by a single logical replication message. start is the position of
+// the message, and end is the current end of the stream.
+func AppendXLogData(buf []byte, start, end lsn.LSN, sendTime time.Time) []byte {
+	buf = append(buf, msgXLogData)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(start))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(end))
+	return appendTime(buf, sendTime)
+}
+
+// AppendKeepalive appends a primary keepalive message. If replyRequested is
+// set, the client should reply with a standby status update immediately.
+func AppendKeepalive(buf []byte, end lsn.LSN, sendTime time.Time, replyRequested bool) []byte {
+	buf = append(buf, msgPrimaryKeepalive)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(end))
+	buf = appendTime(buf, sendTime)
+	if replyRequested {
+		return append(buf, 1)
+	}
+	return append(buf, 0)
+}
+
+// StandbyStatusUpdate is the progress reported by the client.
+type StandbyStatusUpdate struct {
+	// WrittenLSN, FlushedLSN and AppliedLSN are the positions up to which the
+	// client has received, durably stored and applied the changes.
+	WrittenLSN, FlushedLSN, AppliedLSN lsn.LSN
+	ClientTime                         time.Time
+	ReplyRequested                     bool
+}
+
+// ParseClientMessage parses the payload of a CopyData message sent by the
+// client while changes are streamed. ok is false for messages that carry no
+// information relevant to logical replication, such as hot standby feedback.
+func ParseClientMessage(data []byte) (_ StandbyStatusUpdate, ok bool, _ error) {
+	if len(data) == 0 {
+		return StandbyStatusUpdate{}, false, errors.New("empty replication message")
+	}
+	switch data[0] {
+	case msgStandbyStatusUpdate:
+		if len(data) != 34 {
+			return StandbyStatusUpdate{}, false, errors.Newf(
+				"invalid standby status update message length %d", len(data),
+			)
+		}
+		return StandbyStatusUpdate{
+			WrittenLSN:     lsn.LSN(binary.BigEndian.Uint64(data[1:])),
+			FlushedLSN:     lsn.LSN(binary.BigEndian.Uint64(data[9:])),
+			AppliedLSN:     lsn.LSN(binary.BigEndian.Uint64(data[17:])),
+			ClientTime:     pgEpoch.Add(time.Duration(binary.BigEndian.Uint64(data[25:])) * time.Microsecond),
+			ReplyRequested: data[33] != 0,
+		}, true, nil
+	case msgHotStandbyFeedback:
+		return StandbyStatusUpdate{}, false, nil
+	default:
+		return StandbyStatusUpdate{}, false, errors.Newf(
+			"unexpected replication message type %q", data[0],
+		)
+	}
+}
+
+// AppendStandbyStatusUpdate appends a standby status update message, as sent
+// by a replication client.
+func AppendStandbyStatusUpdate(buf []byte, u StandbyStatusUpdate) []byte {
+	buf = append(buf, msgStandbyStatusUpdate)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(u.WrittenLSN))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(u.FlushedLSN))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(u.AppliedLSN))
+	buf = appendTime(buf, u.ClientTime)
+	if u.ReplyRequested {
+		return append(buf, 1)
+	}
+	return append(buf, 0)
+}
+
+// XLogData is a XLogData message sent by the server.
+type XLogData struct {
+	// StartLSN is the position of the message, and EndLSN the current end of
+	// the stream.
+	StartLSN, EndLSN lsn.LSN
+	SendTime         time.Time
+	// Data is the logical replication message. It aliases the parsed buffer.
+	Data []byte
+}
+
+// PrimaryKeepalive is a primary keepalive message sent by the server.
+type PrimaryKeepalive struct {
+	EndLSN         lsn.LSN
+	SendTime       time.Time
+	ReplyRequested bool
+}
+
+// ParseServerMessage parses the payload of a CopyData message sent by the
+// server while changes are streamed. Exactly one of the returned messages is
+// set when there is no error.
+func ParseServerMessage(data []byte) (*XLogData, *PrimaryKeepalive, error) {
+	r := reader{data: data}
+	switch t := r.byte(); t {
+	case msgXLogData:
+		m := &XLogData{
+			StartLSN: lsn.LSN(r.uint64()),
+			EndLSN:   lsn.LSN(r.uint64()),
+			SendTime: r.time(),
+		}
+		m.Data = r.data
+		if r.err != nil {
+			return nil, nil, r.err
+		}
+		return m, nil, nil
+	case msgPrimaryKeepalive:
+		m := &PrimaryKeepalive{
+			EndLSN:         lsn.LSN(r.uint64()),
+			SendTime:       r.time(),
+			ReplyRequested: r.byte() != 0,
+		}
+		if err := r.done(); err != nil {
+			return nil, nil, err
+		}
+		return nil, m, nil
+	default:
+		if r.err != nil {
+			return nil, nil, r.err
+		}
+		return nil, nil, errors.Newf("unexpected replication message type %q", t)
+	}
+}
+
+// Message is a logical replication message: one of *Begin, *Commit,
+// *Relation, *Insert, *Update or *Delete.
+type Message interface {
+	logicalMessage()
+}
+
+// Begin starts the changes of a transaction.
+type Begin struct {
+	// FinalLSN is the position of the commit of the transaction.
+	FinalLSN   lsn.LSN
+	CommitTime time.Time
+	XID        uint32
+}
+
+// Commit ends the changes of a transaction.
+type Commit struct {
+	CommitLSN, EndLSN lsn.LSN
+	CommitTime        time.Time
+}
+
+// Insert is the insertion of a row.
+type Insert struct {
+	RelationID uint32
+	Row        []Value
+}
+
+// Update is the update of a row. Only the new row is decoded.
+type Update struct {
+	RelationID uint32
+	Row        []Value
+}
+
+// Delete is the deletion of a row. Key holds the values of the replica
+// identity of the row; the other values are NULL.
+type Delete struct {
+	RelationID uint32
+	Key        []Value
+}
+
+func (*Begin) logicalMessage()    {}
+func (*Commit) logicalMessage()   {}
+func (*Relation) logicalMessage() {}
+func (*Insert) logicalMessage()   {}
+func (*Update) logicalMessage()   {}
+func (*Delete) logicalMessage()   {}
+
+// ParseMessage parses a logical replication message, as carried by a XLogData
+// message. The values of the returned message alias data.
+func ParseMessage(data []byte) (Message, error) {
+	r := reader{data: data}
+	var m Message
+	switch t := r.byte(); t {
+	case msgBegin:
+		m = &Begin{
+			FinalLSN:   lsn.LSN(r.uint64()),
+			CommitTime: r.time(),
+			XID:        r.uint32(),
+		}
+	case msgCommit:
+		_ = r.byte() // flags
+		m = &Commit{
+			CommitLSN:  lsn.LSN(r.uint64()),
+			EndLSN:     lsn.LSN(r.uint64()),
+			CommitTime: r.time(),
+		}
+	case msgRelation:
+		rel := &Relation{
+			ID:        r.uint32(),
+			Namespace: r.string(),
+			Name:      r.string(),
+		}
+		_ = r.byte() // replica identity
+		rel.Columns = make([]Column, r.uint16())
+		for i := range rel.Columns {
+			flags := r.byte()
+			rel.Columns[i] = Column{
+				Key:     flags&relationColumnFlagKeyPart != 0,
+				Name:    r.string(),
+				TypeOID: oid.Oid(r.uint32()),
+				TypeMod: int32(r.uint32()),
+			}
+		}
+		m = rel
+	case msgInsert:
+		relID := r.uint32()
+		r.expect(tupleNew)
+		m = &Insert{RelationID: relID, Row: r.tuple()}
+	case msgUpdate:
+		relID := r.uint32()
+		// The old row, or its key, is sent before the new row by Postgres when
+		// the replica identity changes.
+		if marker := r.peek(); marker == tupleKey || marker == tupleOld {
+			_ = r.byte()
+			_ = r.tuple()
+		}
+		r.expect(tupleNew)
+		m = &Update{RelationID: relID, Row: r.tuple()}
+	case msgDelete:
+		relID := r.uint32()
+		if marker := r.byte(); marker != tupleKey && marker != tupleOld && r.err == nil {
+			r.err = errors.Newf("unexpected tuple marker %q", marker)
+		}
+		m = &Delete{RelationID: relID, Key: r.tuple()}
+	default:
+		if r.err != nil {
+			return nil, r.err
+		}
+		return nil, errors.Newf("unexpected logical replication message type %q", t)
+	}
+	if err := r.done(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// reader decodes the fields of a message. The first error is recorded, after
+// which all fields are decoded as zero values.
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.data) < n {
+		r.err = errors.New("replication message too short")
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) peek() byte {
+	if r.err != nil || len(r.data) == 0 {
+		return 0
+	}
+	return r.data[0]
+}
+
+func (r *reader) byte() byte {
+	if b := r.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *reader) uint16() uint16 {
+	if b := r.next(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *reader) uint32() uint32 {
+	if b := r.next(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *reader) uint64() uint64 {
+	if b := r.next(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (r *reader) time() time.Time {
+	return pgEpoch.Add(time.Duration(r.uint64()) * time.Microsecond)
+}
+
+func (r *reader) string() string {
+	if r.err != nil {
+		return ""
+	}
+	for i, c := range r.data {
+		if c == 0 {
+			s := string(r.data[:i])
+			r.data = r.data[i+1:]
+			return s
+		}
+	}
+	r.err = errors.New("unterminated string in replication message")
+	return ""
+}
+
+func (r *reader) expect(marker byte) {
+	if b := r.byte(); b != marker && r.err == nil {
+		r.err = errors.Newf("unexpected tuple marker %q", b)
+	}
+}
+
+func (r *reader) tuple() []Value {
+	n := r.uint16()
+	if r.err != nil {
+		return nil
+	}
+	row := make([]Value, n)
+	for i := range row {
+		switch kind := r.byte(); kind {
+		case tupleColumnNull:
+			row[i].Null = true
+		case tupleColumnText:
+			row[i].Text = r.next(int(r.uint32()))
+		default:
+			if r.err == nil {
+				r.err = errors.Newf("unsupported tuple column kind %q", kind)
+			}
+			return nil
+		}
+	}
+	return row
+}
+
+func (r *reader) done() error {
+	if r.err == nil && len(r.data) > 0 {
+		r.err = errors.Newf("%d unexpected trailing bytes in replication message", len(r.data))
+	}
+	return r.err
+}
+
+func appendTuple(buf []byte, row []Value) []byte {
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(row)))
+	for _, v := range row {
+		if v.Null {
+			buf = append(buf, tupleColumnNull)
+			continue
+		}
+		buf = append(buf, tupleColumnText)
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(v.Text)))
+		buf = append(buf, v.Text...)
+	}
+	return buf
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = append(buf, s...)
+	return append(buf, 0)
+}
+
+func appendTime(buf []byte, t time.Time) []byte {
+	return binary.BigEndian.AppendUint64(buf, uint64(t.Sub(pgEpoch)/time.Microsecond))
+}
@@ -0,0 +1,241 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgoutput
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsn"
+	"github.com/lib/pq/oid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	ts := pgEpoch.Add(time.Second)
+	for _, tc := range []struct {
+		name     string
+		buf      []byte
+		expected []byte
+	}{
+		{
+			name: "begin",
+			buf:  AppendBegin(nil, 0x0102, ts, 7),
+			expected: []byte{'B',
+				0, 0, 0, 0, 0, 0, 0x01, 0x02,
+				0, 0, 0, 0, 0, 0x0f, 0x42, 0x40,
+				0, 0, 0, 7},
+		},
+		{
+			name: "commit",
+			buf:  AppendCommit(nil, 0x0102, 0x0103, ts),
+			expected: []byte{'C', 0,
+				0, 0, 0, 0, 0, 0, 0x01, 0x02,
+				0, 0, 0, 0, 0, 0, 0x01, 0x03,
+				0, 0, 0, 0, 0, 0x0f, 0x42, 0x40},
+		},
+		{
+			name: "relation",
+			buf: AppendRelation(nil, &Relation{
+				ID:        104,
+				Namespace: "public",
+				Name:      "t",
+				Columns: []Column{
+					{Name: "k", TypeOID: oid.T_int8, TypeMod: -1, Key: true},
+					{Name: "v", TypeOID: oid.T_text, TypeMod: -1},
+				},
+			}),
+			expected: []byte{'R',
+				0, 0, 0, 104,
+				'p', 'u', 'b', 'l', 'i', 'c', 0,
+				't', 0,
+				'd',
+				0, 2,
+				1, 'k', 0, 0, 0, 0, 20, 0xff, 0xff, 0xff, 0xff,
+				0, 'v', 0, 0, 0, 0, 25, 0xff, 0xff, 0xff, 0xff},
+		},
+		{
+			name: "insert",
+			buf: AppendInsert(nil, 104, []Value{
+				{Text: []byte("1")}, {Null: true}, {Text: []byte{}},
+			}),
+			expected: []byte{'I',
+				0, 0, 0, 104,
+				'N',
+				0, 3,
+				't', 0, 0, 0, 1, '1',
+				'n',
+				't', 0, 0, 0, 0},
+		},
+		{
+			name: "update",
+			buf:  AppendUpdate(nil, 104, []Value{{Text: []byte("1")}}),
+			expected: []byte{'U',
+				0, 0, 0, 104,
+				'N',
+				0, 1,
+				't', 0, 0, 0, 1, '1'},
+		},
+		{
+			name: "delete",
+			buf:  AppendDelete(nil, 104, []Value{{Text: []byte("1")}, {Null: true}}),
+			expected: []byte{'D',
+				0, 0, 0, 104,
+				'K',
+				0, 2,
+				't', 0, 0, 0, 1, '1',
+				'n'},
+		},
+		{
+			name: "xlogdata",
+			buf:  AppendXLogData(nil, 0x10, 0x20, ts),
+			expected: []byte{'w',
+				0, 0, 0, 0, 0, 0, 0, 0x10,
+				0, 0, 0, 0, 0, 0, 0, 0x20,
+				0, 0, 0, 0, 0, 0x0f, 0x42, 0x40},
+		},
+		{
+			name: "keepalive",
+			buf:  AppendKeepalive(nil, 0x20, ts, true),
+			expected: []byte{'k',
+				0, 0, 0, 0, 0, 0, 0, 0x20,
+				0, 0, 0, 0, 0, 0x0f, 0x42, 0x40,
+				1},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.buf)
+		})
+	}
+}
+
+func TestParseClientMessage(t *testing.T) {
+	u := StandbyStatusUpdate{
+		WrittenLSN:     lsn.LSN(3),
+		FlushedLSN:     lsn.LSN(2),
+		AppliedLSN:     lsn.LSN(1),
+		ClientTime:     pgEpoch.Add(time.Hour),
+		ReplyRequested: true,
+	}
+	parsed, ok, err := ParseClientMessage(AppendStandbyStatusUpdate(nil, u))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, u, parsed)
+
+	_, ok, err = ParseClientMessage([]byte{'h', 0, 0})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, _, err = ParseClientMessage([]byte{'r', 0})
+	require.Error(t, err)
+
+	_, _, err = ParseClientMessage([]byte{'x'})
+	require.Error(t, err)
+}
+
+func TestParseMessage(t *testing.T) {
+	ts := pgEpoch.Add(time.Second)
+	rel := &Relation{
+		ID:        104,
+		Namespace: "public",
+		Name:      "t",
+		Columns: []Column{
+			{Name: "k", TypeOID: oid.T_int8, TypeMod: -1, Key: true},
+			{Name: "v", TypeOID: oid.T_varchar, TypeMod: 14},
+		},
+	}
+	row := []Value{{Text: []byte("1")}, {Null: true}}
+	for _, tc := range []struct {
+		name     string
+		buf      []byte
+		expected Message
+	}{
+		{
+			name:     "begin",
+			buf:      AppendBegin(nil, 0x0102, ts, 7),
+			expected: &Begin{FinalLSN: 0x0102, CommitTime: ts, XID: 7},
+		},
+		{
+			name:     "commit",
+			buf:      AppendCommit(nil, 0x0102, 0x0103, ts),
+			expected: &Commit{CommitLSN: 0x0102, EndLSN: 0x0103, CommitTime: ts},
+		},
+		{
+			name:     "relation",
+			buf:      AppendRelation(nil, rel),
+			expected: rel,
+		},
+		{
+			name:     "insert",
+			buf:      AppendInsert(nil, 104, row),
+			expected: &Insert{RelationID: 104, Row: row},
+		},
+		{
+			name:     "update",
+			buf:      AppendUpdate(nil, 104, row),
+			expected: &Update{RelationID: 104, Row: row},
+		},
+		{
+			name: "update with old key",
+			buf: []byte{'U',
+				0, 0, 0, 104,
+				'K', 0, 1, 't', 0, 0, 0, 1, '0',
+				'N', 0, 2, 't', 0, 0, 0, 1, '1', 'n'},
+			expected: &Update{RelationID: 104, Row: row},
+		},
+		{
+			name:     "delete",
+			buf:      AppendDelete(nil, 104, row),
+			expected: &Delete{RelationID: 104, Key: row},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := ParseMessage(tc.buf)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, m)
+		})
+	}
+
+	for _, buf := range [][]byte{
+		nil,
+		{'x'},
+		AppendBegin(nil, 0x0102, ts, 7)[:10],
+		append(AppendCommit(nil, 0x0102, 0x0103, ts), 0),
+		{'I', 0, 0, 0, 104, 'N', 0, 1, 'b', 0, 0, 0, 0},
+		{'R', 0, 0, 0, 104, 'p'},
+	} {
+		_, err := ParseMessage(buf)
+		require.Error(t, err)
+	}
+}
+
+func TestParseServerMessage(t *testing.T) {
+	ts := pgEpoch.Add(time.Second)
+	x, k, err := ParseServerMessage(AppendCommit(AppendXLogData(nil, 0x10, 0x20, ts), 0x10, 0x11, ts))
+	require.NoError(t, err)
+	require.Nil(t, k)
+	require.Equal(t, lsn.LSN(0x10), x.StartLSN)
+	require.Equal(t, lsn.LSN(0x20), x.EndLSN)
+	require.Equal(t, ts, x.SendTime)
+	m, err := ParseMessage(x.Data)
+	require.NoError(t, err)
+	require.Equal(t, &Commit{CommitLSN: 0x10, EndLSN: 0x11, CommitTime: ts}, m)
+
+	x, k, err = ParseServerMessage(AppendKeepalive(nil, 0x20, ts, true))
+	require.NoError(t, err)
+	require.Nil(t, x)
+	require.Equal(t, &PrimaryKeepalive{EndLSN: 0x20, SendTime: ts, ReplyRequested: true}, k)
+
+	_, _, err = ParseServerMessage([]byte{'r'})
+	require.Error(t, err)
+	_, _, err = ParseServerMessage([]byte{'k', 0})
+	require.Error(t, err)
+}
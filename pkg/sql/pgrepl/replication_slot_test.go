@@ -0,0 +1,221 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsn"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/pgoutput"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLogicalReplication streams the changes to a table from a logical
+// replication slot.
+func TestLogicalReplication(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer srv.Stopper().Stop(ctx)
+	s := srv.ApplicationLayer()
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.closed_timestamp.target_duration = '100ms'`)
+	sqlDB.Exec(t, `CREATE TABLE t (a INT PRIMARY KEY, b STRING)`)
+	sqlDB.Exec(t, `CREATE PUBLICATION p FOR TABLE t`)
+
+	pgURL, cleanup := s.PGUrl(
+		t, serverutils.CertsDirPrefix("pgrepl_logical_replication_test"), serverutils.User(username.RootUser),
+	)
+	defer cleanup()
+	cfg, err := pgconn.ParseConfig(pgURL.String())
+	require.NoError(t, err)
+	cfg.RuntimeParams["replication"] = "database"
+	conn, err := pgconn.ConnectConfig(ctx, cfg)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close(ctx) }()
+
+	_, err = conn.Exec(ctx, `CREATE_REPLICATION_SLOT slot_1 LOGICAL pgoutput`).ReadAll()
+	require.NoError(t, err)
+	sqlDB.CheckQueryResults(t,
+		`SELECT slot_name, plugin, slot_type, database, active FROM pg_replication_slots`,
+		[][]string{{"slot_1", "pgoutput", "logical", "defaultdb", "false"}},
+	)
+
+	sqlDB.Exec(t, `INSERT INTO t VALUES (1, 'a')`)
+	sqlDB.Exec(t, `UPDATE t SET b = 'b' WHERE a = 1`)
+	sqlDB.Exec(t, `DELETE FROM t WHERE a = 1`)
+
+	fe := conn.Frontend()
+	fe.Send(&pgproto3.Query{
+		String: `START_REPLICATION SLOT slot_1 LOGICAL 0/0 (proto_version '1', publication_names 'p')`,
+	})
+	require.NoError(t, fe.Flush())
+	msg, err := conn.ReceiveMessage(ctx)
+	require.NoError(t, err)
+	require.IsType(t, &pgproto3.CopyBothResponse{}, msg)
+
+	// Read the changes until the three transactions were received, skipping the
+	// keepalive messages.
+	var changes []string
+	var commitLSN lsn.LSN
+	for commits := 0; commits < 3; {
+		msg, err := conn.ReceiveMessage(ctx)
+		require.NoError(t, err)
+		data, ok := msg.(*pgproto3.CopyData)
+		require.True(t, ok, "unexpected message %#v", msg)
+		if data.Data[0] != 'w' {
+			continue
+		}
+		// Skip the XLogData header.
+		payload := data.Data[25:]
+		if payload[0] == 'C' {
+			commits++
+			commitLSN = lsn.LSN(binary.BigEndian.Uint64(payload[2:]))
+		}
+		changes = append(changes, formatReplicationMessage(payload))
+	}
+	require.Equal(t, []string{
+		"BEGIN",
+		"RELATION public.t (a,b)",
+		"INSERT (1,a)",
+		"COMMIT",
+		"BEGIN",
+		"UPDATE (1,b)",
+		"COMMIT",
+		"BEGIN",
+		"DELETE (1,NULL)",
+		"COMMIT",
+	}, changes)
+	sqlDB.CheckQueryResults(t,
+		`SELECT active FROM pg_replication_slots WHERE slot_name = 'slot_1'`,
+		[][]string{{"true"}},
+	)
+
+	// Confirm the changes, then end the streaming.
+	fe.Send(&pgproto3.CopyData{Data: pgoutput.AppendStandbyStatusUpdate(nil, pgoutput.StandbyStatusUpdate{
+		WrittenLSN: commitLSN,
+		FlushedLSN: commitLSN,
+		AppliedLSN: commitLSN,
+		ClientTime: timeutil.Now(),
+	})})
+	fe.Send(&pgproto3.CopyDone{})
+	require.NoError(t, fe.Flush())
+	var done []string
+	for {
+		msg, err := conn.ReceiveMessage(ctx)
+		require.NoError(t, err)
+		if _, ok := msg.(*pgproto3.CopyData); ok {
+			continue
+		}
+		done = append(done, fmt.Sprintf("%T", msg))
+		if _, ok := msg.(*pgproto3.ReadyForQuery); ok {
+			break
+		}
+	}
+	require.Equal(t, []string{
+		"*pgproto3.CopyDone", "*pgproto3.CommandComplete", "*pgproto3.ReadyForQuery",
+	}, done)
+	sqlDB.CheckQueryResults(t,
+		`SELECT active, confirmed_flush_lsn FROM pg_replication_slots WHERE slot_name = 'slot_1'`,
+		[][]string{{"false", commitLSN.String()}},
+	)
+
+	// The stream fails once the changes it buffers exceed its memory budget.
+	sqlDB.Exec(t, `SET CLUSTER SETTING sql.replication.stream_buffer_size = '1B'`)
+	sqlDB.Exec(t, `INSERT INTO t VALUES (2, 'c')`)
+	fe.Send(&pgproto3.Query{
+		String: `START_REPLICATION SLOT slot_1 LOGICAL 0/0 (proto_version '1', publication_names 'p')`,
+	})
+	require.NoError(t, fe.Flush())
+	var errCode string
+	for {
+		msg, err := conn.ReceiveMessage(ctx)
+		require.NoError(t, err)
+		if e, ok := msg.(*pgproto3.ErrorResponse); ok {
+			errCode = e.Code
+		}
+		if _, ok := msg.(*pgproto3.ReadyForQuery); ok {
+			break
+		}
+	}
+	require.Equal(t, pgcode.OutOfMemory.String(), errCode)
+
+	_, err = conn.Exec(ctx, `DROP_REPLICATION_SLOT slot_1`).ReadAll()
+	require.NoError(t, err)
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM pg_replication_slots`, [][]string{{"0"}})
+}
+
+// formatReplicationMessage formats a pgoutput message for comparison.
+func formatReplicationMessage(msg []byte) string {
+	switch msg[0] {
+	case 'B':
+		return "BEGIN"
+	case 'C':
+		return "COMMIT"
+	case 'R':
+		// Skip the relation ID.
+		rest := msg[5:]
+		readString := func() string {
+			i := bytes.IndexByte(rest, 0)
+			s := string(rest[:i])
+			rest = rest[i+1:]
+			return s
+		}
+		namespace, name := readString(), readString()
+		// Skip the replica identity and the number of columns.
+		rest = rest[3:]
+		var cols []string
+		for len(rest) > 0 {
+			// Skip the flags, then the type OID and modifier after the name.
+			rest = rest[1:]
+			cols = append(cols, readString())
+			rest = rest[8:]
+		}
+		return fmt.Sprintf("RELATION %s.%s (%s)", namespace, name, strings.Join(cols, ","))
+	case 'I', 'U', 'D':
+		kind := map[byte]string{'I': "INSERT", 'U': "UPDATE", 'D': "DELETE"}[msg[0]]
+		// Skip the relation ID, the tuple kind and the number of columns.
+		rest := msg[8:]
+		var vals []string
+		for len(rest) > 0 {
+			if rest[0] == 'n' {
+				vals = append(vals, "NULL")
+				rest = rest[1:]
+				continue
+			}
+			n := binary.BigEndian.Uint32(rest[1:])
+			vals = append(vals, string(rest[5:5+n]))
+			rest = rest[5+n:]
+		}
+		return fmt.Sprintf("%s (%s)", kind, strings.Join(vals, ","))
+	default:
+		return fmt.Sprintf("unexpected message %q", msg[0])
+	}
+}
@@ -41,6 +41,10 @@ const (
 	emptyQueryResponse
 	readyForQuery
 	flush
+	// deliverNotifications is used for the result of a DeliverNotifications
+	// command; closing it delivers the pending notifications if no transaction
+	// is in progress.
+	deliverNotifications
 	// Some commands, like Describe, don't need a completion message.
 	noCompletionMsg
 )
@@ -162,6 +166,11 @@ func (r *commandResult) Close(ctx context.Context, t sql.TransactionStatusIndica
 	case closeComplete:
 		r.conn.bufferCloseComplete()
 	case readyForQuery:
+		if t == sql.IdleTxnBlock {
+			// Notifications are delivered between transactions, before the client
+			// is told that the server is ready for the next query.
+			r.conn.bufferPendingNotifications()
+		}
 		r.conn.bufferReadyForQuery(byte(t))
 		// The error is saved on conn.err.
 		_ /* err */ = r.conn.Flush(r.pos)
@@ -172,6 +181,14 @@ func (r *commandResult) Close(ctx context.Context, t sql.TransactionStatusIndica
 		// The error is saved on conn.err.
 		_ /* err */ = r.conn.Flush(r.pos)
 		r.conn.maybeReallocate()
+	case deliverNotifications:
+		r.conn.notificationDeliveryDequeued()
+		// If a transaction is in progress, the notifications will be delivered
+		// once it finishes.
+		if t == sql.IdleTxnBlock && r.conn.bufferPendingNotifications() {
+			// The error is saved on conn.err.
+			_ /* err */ = r.conn.Flush(r.pos)
+		}
 	case noCompletionMsg:
 		// nothing to do
 	default:
@@ -390,6 +407,22 @@ func (r *commandResult) SendCopyDone(ctx context.Context) error {
 	return r.conn.bufferCopyDone()
 }
 
+// SendCopyBoth is part of the sql.ReplicationResult interface.
+func (r *commandResult) SendCopyBoth(ctx context.Context) error {
+	r.assertNotReleased()
+	r.conn.writerState.fi.registerCmd(r.pos)
+	if err := r.conn.bufferCopyBoth(); err != nil {
+		return err
+	}
+	return r.conn.Flush(r.pos)
+}
+
+// Flush is part of the sql.ReplicationResult interface.
+func (r *commandResult) Flush(ctx context.Context) error {
+	r.assertNotReleased()
+	return r.conn.Flush(r.pos)
+}
+
 // SetRowsAffected is part of the sql.RestrictedCommandResult interface.
 func (r *commandResult) SetRowsAffected(ctx context.Context, n int) {
 	r.assertNotReleased()
@@ -45,6 +45,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/ring"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
@@ -101,6 +102,24 @@ type conn struct {
 		tagBuf [64]byte
 	}
 
+	// notifications holds the asynchronous notifications which haven't been
+	// delivered to the client yet (see SendNotification). Unlike the rest of
+	// the conn, it is accessed by other sessions' goroutines.
+	notifications struct {
+		syncutil.Mutex
+		pending []pendingNotification
+		// deliveryQueued is set while a DeliverNotifications command pushed to
+		// stmtBuf hasn't been executed yet.
+		deliveryQueued bool
+		// droppedLogEvery rate-limits the warnings about dropped notifications.
+		droppedLogEvery log.EveryN
+	}
+
+	// replicationFeedback is set while changes are streamed from a replication
+	// slot. It receives the copy messages sent by the client. It is only
+	// accessed by the connection's reading goroutine.
+	replicationFeedback *sql.ReplicationFeedback
+
 	readBuf    pgwirebase.ReadBuffer
 	msgBuilder writeBuffer
 
@@ -346,6 +365,9 @@ func (c *conn) handleSimpleQuery(
 		return c.stmtBuf.Push(ctx, sql.SendError{Err: err})
 	}
 
+	// A new query ends any previous streaming of changes.
+	c.replicationFeedback = nil
+
 	startParse := timeutil.Now()
 	if c.sessionArgs.ReplicationMode != sessiondatapb.ReplicationMode_REPLICATION_MODE_DISABLED &&
 		pgreplparser.IsReplicationProtocolCommand(query) {
@@ -355,7 +377,24 @@ func (c *conn) handleSimpleQuery(
 			return c.stmtBuf.Push(ctx, sql.SendError{Err: err})
 		}
 		switch stmt.AST.(type) {
-		case *pgrepltree.IdentifySystem:
+		case *pgrepltree.IdentifySystem, *pgrepltree.CreateReplicationSlot,
+			*pgrepltree.DropReplicationSlot, *pgrepltree.ReadReplicationSlot:
+		case *pgrepltree.StartReplication:
+			// START_REPLICATION switches the connection to the Copy-both
+			// subprotocol. From now on, the copy messages sent by the client are
+			// handed to the connExecutor through the feedback.
+			c.replicationFeedback = sql.NewReplicationFeedback()
+			return c.stmtBuf.Push(
+				ctx,
+				sql.StartReplication{
+					ParsedStmt:   stmt,
+					Stmt:         stmt.AST.(*pgrepltree.StartReplication),
+					Feedback:     c.replicationFeedback,
+					TimeReceived: timeReceived,
+					ParseStart:   startParse,
+					ParseEnd:     timeutil.Now(),
+				},
+			)
 		default:
 			log.SqlExec.Infof(ctx, "unhandled replication protocol query: %s", query)
 			return c.stmtBuf.Push(ctx, sql.SendError{
@@ -857,12 +896,12 @@ func cookTag(
 		tag = strconv.AppendInt(tag, int64(rowsAffected), 10)
 
 	case tree.Rows:
-		if tagStr != "SHOW" && tagStr != "EXPLAIN" {
+		if tagStr != "SHOW" && tagStr != "EXPLAIN" && tagStr != "CALL" {
 			tag = append(tag, ' ')
 			tag = strconv.AppendUint(tag, uint64(rowsAffected), 10)
 		}
 
-	case tree.Ack, tree.DDL:
+	case tree.Ack, tree.DDL, tree.Replication:
 		if tagStr == "SELECT" {
 			tag = append(tag, ' ')
 			tag = strconv.AppendInt(tag, int64(rowsAffected), 10)
@@ -1033,6 +1072,81 @@ func (c *conn) bufferReadyForQuery(txnStatus byte) {
 	}
 }
 
+// pendingNotification is an asynchronous notification which hasn't been
+// delivered to the client yet.
+type pendingNotification struct {
+	senderPID uint32
+	channel   string
+	payload   string
+}
+
+// maxPendingNotifications is the maximum number of notifications queued for a
+// client. It is reached if the session stays in a transaction for a long time
+// while notifications keep arriving; further notifications are then dropped.
+const maxPendingNotifications = 1 << 16
+
+// SendNotification is part of the sql.ClientComm interface.
+func (c *conn) SendNotification(
+	ctx context.Context, senderPID uint32, channel, payload string,
+) {
+	c.notifications.Lock()
+	defer c.notifications.Unlock()
+	if len(c.notifications.pending) >= maxPendingNotifications {
+		c.metrics.NotificationsDropped.Inc(1)
+		if c.notifications.droppedLogEvery.ShouldLog() {
+			log.Warningf(ctx, "too many pending notifications, dropping notification on channel %q", channel)
+		}
+		return
+	}
+	c.notifications.pending = append(c.notifications.pending, pendingNotification{
+		senderPID: senderPID,
+		channel:   channel,
+		payload:   payload,
+	})
+	if c.notifications.deliveryQueued {
+		return
+	}
+	// Ask the connExecutor to deliver the notification as soon as it's done
+	// with the commands received so far. The push fails if the connection is
+	// being closed, in which case there's nobody to deliver the notification
+	// to anyway.
+	if err := c.stmtBuf.Push(ctx, sql.DeliverNotifications{}); err == nil {
+		c.notifications.deliveryQueued = true
+	}
+}
+
+// notificationDeliveryDequeued is called when the DeliverNotifications command
+// pushed by SendNotification is executed.
+func (c *conn) notificationDeliveryDequeued() {
+	c.notifications.Lock()
+	defer c.notifications.Unlock()
+	c.notifications.deliveryQueued = false
+}
+
+// bufferPendingNotifications buffers NotificationResponse messages for all the
+// pending notifications. It returns false if there were none.
+func (c *conn) bufferPendingNotifications() bool {
+	c.notifications.Lock()
+	pending := c.notifications.pending
+	c.notifications.pending = nil
+	// A DeliverNotifications command that is still queued has nothing left to
+	// deliver, and one that was skipped (e.g. after an error in the extended
+	// protocol) would never reset deliveryQueued, so further notifications
+	// must queue a new command.
+	c.notifications.deliveryQueued = false
+	c.notifications.Unlock()
+	for _, n := range pending {
+		c.msgBuilder.initMsg(pgwirebase.ServerMsgNotificationResponse)
+		c.msgBuilder.putInt32(int32(n.senderPID))
+		c.msgBuilder.writeTerminatedString(n.channel)
+		c.msgBuilder.writeTerminatedString(n.payload)
+		if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+			panic(errors.NewAssertionErrorWithWrappedErrf(err, "unexpected err from buffer"))
+		}
+	}
+	return len(pending) > 0
+}
+
 func (c *conn) bufferParseComplete() {
 	c.msgBuilder.initMsg(pgwirebase.ServerMsgParseComplete)
 	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
@@ -1175,6 +1289,13 @@ func (c *conn) bufferCopyOut(columns []colinfo.ResultColumn, format pgwirebase.F
 	return c.msgBuilder.finishMsg(&c.writerState.buf)
 }
 
+func (c *conn) bufferCopyBoth() error {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyBothResponse)
+	c.msgBuilder.writeByte(byte(pgwirebase.FormatText))
+	c.msgBuilder.putInt16(0)
+	return c.msgBuilder.finishMsg(&c.writerState.buf)
+}
+
 func (c *conn) bufferCopyData(copyData []byte, res *commandResult) error {
 	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyDataCommand)
 	if _, err := c.msgBuilder.Write(copyData); err != nil {
@@ -1372,6 +1493,11 @@ func (c *conn) CreateDrainResult(pos sql.CmdPos) sql.DrainResult {
 	return c.newMiscResult(pos, noCompletionMsg)
 }
 
+// CreateDeliverNotificationsResult is part of the sql.ClientComm interface.
+func (c *conn) CreateDeliverNotificationsResult(pos sql.CmdPos) sql.DeliverNotificationsResult {
+	return c.newMiscResult(pos, deliverNotifications)
+}
+
 // CreateBindResult is part of the sql.ClientComm interface.
 func (c *conn) CreateBindResult(pos sql.CmdPos) sql.BindResult {
 	return c.newMiscResult(pos, bindComplete)
@@ -1420,6 +1546,16 @@ func (c *conn) CreateCopyOutResult(cmd sql.CopyOut, pos sql.CmdPos) sql.CopyOutR
 	return res
 }
 
+// CreateReplicationResult is part of the sql.ClientComm interface.
+func (c *conn) CreateReplicationResult(
+	cmd sql.StartReplication, pos sql.CmdPos,
+) sql.ReplicationResult {
+	res := c.newMiscResult(pos, commandComplete)
+	res.stmtType = cmd.Stmt.StatementReturnType()
+	res.cmdCompleteTag = cmd.Stmt.StatementTag()
+	return res
+}
+
 // pgwireReader is an io.Reader that wraps a conn, maintaining its metrics as
 // it is consumed.
 type pgwireReader struct {
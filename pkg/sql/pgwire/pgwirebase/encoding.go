@@ -454,6 +454,8 @@ func DecodeDatum(
 				return nil, err
 			}
 			return tree.ParseDJSON(bs)
+		case oidext.T_jsonpath:
+			return tree.ParseDJsonpath(bs)
 		case oid.T_tsquery:
 			ret, err := tsearch.ParseTSQuery(bs)
 			if err != nil {
@@ -468,6 +470,18 @@ func DecodeDatum(
 			return &tree.DTSVector{TSVector: ret}, nil
 		}
 		switch typ.Family() {
+		case types.RangeFamily:
+			d, _, err := tree.ParseDRange(evalCtx, bs, typ)
+			if err != nil {
+				return nil, err
+			}
+			return d, nil
+		case types.MultirangeFamily:
+			d, _, err := tree.ParseDMultirange(evalCtx, bs, typ)
+			if err != nil {
+				return nil, err
+			}
+			return d, nil
 		case types.ArrayFamily, types.TupleFamily:
 			// Arrays and tuples come in in their string form, so we parse them
 			// as such and later convert them to their actual datum form.
@@ -755,6 +769,19 @@ func DecodeDatum(
 			}
 			ba, err := bitarray.FromEncodingParts(words, lastBitsUsed)
 			return &tree.DBitArray{BitArray: ba}, err
+		case oidext.T_jsonpath:
+			if len(b) < 1 {
+				return nil, NewProtocolViolationErrorf("no data to decode")
+			}
+			if b[0] != 1 {
+				return nil, NewProtocolViolationErrorf("expected JSONPATH version 1")
+			}
+			// Skip over the version number.
+			b = b[1:]
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDJsonpath(encoding.UnsafeConvertBytesToString(b))
 		case oid.T_tsquery:
 			ret, err := tsearch.DecodeTSQueryPGBinary(b)
 			if err != nil {
@@ -786,6 +813,12 @@ func DecodeDatum(
 			if typ.Family() == types.TupleFamily {
 				return decodeBinaryTuple(ctx, evalCtx, b)
 			}
+			if typ.Family() == types.RangeFamily {
+				return decodeBinaryRange(ctx, evalCtx, typ, b)
+			}
+			if typ.Family() == types.MultirangeFamily {
+				return decodeBinaryMultirange(ctx, evalCtx, typ, b)
+			}
 			if typ.Family() == types.OidFamily {
 				if len(b) < 4 {
 					return nil, pgerror.Newf(pgcode.ProtocolViolation, "oid requires 4 bytes for binary format")
@@ -1019,6 +1052,79 @@ func decodeBinaryArray(
 	return arr, nil
 }
 
+// decodeBinaryRange decodes the binary format of a range: a byte of flags,
+// followed by each finite bound as a length-prefixed datum of the range's
+// subtype.
+func decodeBinaryRange(
+	ctx context.Context, evalCtx *eval.Context, typ *types.T, b []byte,
+) (tree.Datum, error) {
+	if len(b) < 1 {
+		return nil, pgerror.New(pgcode.ProtocolViolation, "range requires a flags byte for binary format")
+	}
+	flags := b[0]
+	r := bytes.NewBuffer(b[1:])
+	if flags&tree.RangeFlagEmpty != 0 {
+		return tree.NewDEmptyRange(typ), nil
+	}
+	readBound := func() (tree.Datum, error) {
+		var vlen int32
+		if err := binary.Read(r, binary.BigEndian, &vlen); err != nil {
+			return nil, err
+		}
+		if vlen < 0 || int(vlen) > r.Len() {
+			return nil, pgerror.New(pgcode.ProtocolViolation, "invalid range bound length")
+		}
+		return DecodeDatum(ctx, evalCtx, typ.RangeContents(), FormatBinary, r.Next(int(vlen)))
+	}
+	var lower, upper tree.Datum
+	var err error
+	if flags&tree.RangeFlagLowerInf == 0 {
+		if lower, err = readBound(); err != nil {
+			return nil, err
+		}
+	}
+	if flags&tree.RangeFlagUpperInf == 0 {
+		if upper, err = readBound(); err != nil {
+			return nil, err
+		}
+	}
+	return tree.NewDRange(
+		typ, lower, upper, flags&tree.RangeFlagLowerInc != 0, flags&tree.RangeFlagUpperInc != 0,
+	)
+}
+
+// decodeBinaryMultirange decodes the binary format of a multirange: the number
+// of ranges, followed by each range as a length-prefixed binary range.
+func decodeBinaryMultirange(
+	ctx context.Context, evalCtx *eval.Context, typ *types.T, b []byte,
+) (tree.Datum, error) {
+	r := bytes.NewBuffer(b)
+	var n int32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, pgerror.New(pgcode.ProtocolViolation, "multirange must have non-negative number of ranges")
+	}
+	rangeTyp := types.MultirangeRangeType(typ)
+	ranges := make([]*tree.DRange, 0, n)
+	for i := int32(0); i < n; i++ {
+		var vlen int32
+		if err := binary.Read(r, binary.BigEndian, &vlen); err != nil {
+			return nil, err
+		}
+		if vlen < 0 || int(vlen) > r.Len() {
+			return nil, pgerror.New(pgcode.ProtocolViolation, "invalid range length")
+		}
+		d, err := decodeBinaryRange(ctx, evalCtx, rangeTyp, r.Next(int(vlen)))
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, tree.MustBeDRange(d))
+	}
+	return tree.NewDMultirange(typ, ranges)
+}
+
 const tupleHeaderSize, oidSize, elementSize = 4, 4, 4
 
 func decodeBinaryTuple(ctx context.Context, evalCtx *eval.Context, b []byte) (tree.Datum, error) {
@@ -15,12 +15,14 @@ func _() {
 	_ = x[ServerMsgCloseComplete-51]
 	_ = x[ServerMsgCopyInResponse-71]
 	_ = x[ServerMsgCopyOutResponse-72]
+	_ = x[ServerMsgCopyBothResponse-87]
 	_ = x[ServerMsgCopyDataCommand-100]
 	_ = x[ServerMsgCopyDoneCommand-99]
 	_ = x[ServerMsgDataRow-68]
 	_ = x[ServerMsgEmptyQuery-73]
 	_ = x[ServerMsgErrorResponse-69]
 	_ = x[ServerMsgNoticeResponse-78]
+	_ = x[ServerMsgNotificationResponse-65]
 	_ = x[ServerMsgNoData-110]
 	_ = x[ServerMsgParameterDescription-116]
 	_ = x[ServerMsgParameterStatus-83]
@@ -46,6 +48,8 @@ func (i ServerMessageType) String() string {
 		return "ServerMsgCopyInResponse"
 	case ServerMsgCopyOutResponse:
 		return "ServerMsgCopyOutResponse"
+	case ServerMsgCopyBothResponse:
+		return "ServerMsgCopyBothResponse"
 	case ServerMsgCopyDataCommand:
 		return "ServerMsgCopyDataCommand"
 	case ServerMsgCopyDoneCommand:
@@ -58,6 +62,8 @@ func (i ServerMessageType) String() string {
 		return "ServerMsgErrorResponse"
 	case ServerMsgNoticeResponse:
 		return "ServerMsgNoticeResponse"
+	case ServerMsgNotificationResponse:
+		return "ServerMsgNotificationResponse"
 	case ServerMsgNoData:
 		return "ServerMsgNoData"
 	case ServerMsgParameterDescription:
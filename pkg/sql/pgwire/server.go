@@ -166,6 +166,12 @@ var (
 		Measurement: "Requests",
 		Unit:        metric.Unit_COUNT,
 	}
+	MetaNotificationsDropped = metric.Metadata{
+		Name:        "sql.notifications.dropped",
+		Help:        "Number of asynchronous notifications dropped because a client fell too far behind",
+		Measurement: "Notifications",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 const (
@@ -268,6 +274,7 @@ type tenantSpecificMetrics struct {
 	PGWireCancelTotalCount      *metric.Counter
 	PGWireCancelIgnoredCount    *metric.Counter
 	PGWireCancelSuccessfulCount *metric.Counter
+	NotificationsDropped        *metric.Counter
 	ConnMemMetrics              sql.BaseMemoryMetrics
 	SQLMemMetrics               sql.MemoryMetrics
 }
@@ -291,6 +298,7 @@ func newTenantSpecificMetrics(
 		PGWireCancelTotalCount:      metric.NewCounter(MetaPGWireCancelTotal),
 		PGWireCancelIgnoredCount:    metric.NewCounter(MetaPGWireCancelIgnored),
 		PGWireCancelSuccessfulCount: metric.NewCounter(MetaPGWireCancelSuccessful),
+		NotificationsDropped:        metric.NewCounter(MetaNotificationsDropped),
 		ConnMemMetrics:              sql.MakeBaseMemMetrics("conns", histogramWindow),
 		SQLMemMetrics:               sqlMemMetrics,
 	}
@@ -858,6 +866,7 @@ func (s *Server) newConn(
 	c.msgBuilder.init(s.tenantMetrics.BytesOutCount)
 	c.errWriter.sv = sv
 	c.errWriter.msgBuilder = &c.msgBuilder
+	c.notifications.droppedLogEvery = log.Every(time.Minute)
 
 	var sentDrainSignal bool
 	rtc.checkExitConds = func() error {
@@ -1197,6 +1206,17 @@ func (s *Server) serveImpl(
 				return false, isSimpleQuery, c.handleFlush(ctx)
 
 			case pgwirebase.ClientMsgCopyData, pgwirebase.ClientMsgCopyDone, pgwirebase.ClientMsgCopyFail:
+				// While changes are streamed from a replication slot, the client
+				// reports its progress with CopyData messages, and ends the
+				// streaming with a CopyDone message.
+				if c.replicationFeedback != nil {
+					switch typ {
+					case pgwirebase.ClientMsgCopyData:
+						return false, isSimpleQuery, c.replicationFeedback.PushCopyData(ctx, c.readBuf.Msg)
+					case pgwirebase.ClientMsgCopyDone:
+						return false, isSimpleQuery, c.replicationFeedback.PushCopyDone(ctx)
+					}
+				}
 				// We're supposed to ignore these messages, per the protocol spec. This
 				// state will happen when an error occurs on the server-side during a copy
 				// operation: the server will send an error and a ready message back to
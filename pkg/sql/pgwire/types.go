@@ -246,6 +246,10 @@ func writeTextDatumNotNull(
 	case *tree.DJSON:
 		b.writeLengthPrefixedString(v.JSON.String())
 
+	case *tree.DJsonpath:
+		b.textFormatter.FormatNode(v)
+		b.writeFromFmtCtx(b.textFormatter)
+
 	case *tree.DTSQuery:
 		b.textFormatter.FormatNode(v)
 		b.writeFromFmtCtx(b.textFormatter)
@@ -254,6 +258,14 @@ func writeTextDatumNotNull(
 		b.textFormatter.FormatNode(v)
 		b.writeFromFmtCtx(b.textFormatter)
 
+	case *tree.DRange:
+		b.textFormatter.FormatNode(v)
+		b.writeFromFmtCtx(b.textFormatter)
+
+	case *tree.DMultirange:
+		b.textFormatter.FormatNode(v)
+		b.writeFromFmtCtx(b.textFormatter)
+
 	case *tree.DTuple:
 		b.textFormatter.FormatNode(v)
 		b.writeFromFmtCtx(b.textFormatter)
@@ -725,6 +737,42 @@ func writeBinaryDatumNotNull(
 		lengthToWrite := b.Len() - (initialLen + 4)
 		b.putInt32AtIndex(initialLen /* index to write at */, int32(lengthToWrite))
 
+	case *tree.DRange:
+		initialLen := b.Len()
+
+		// Reserve bytes for writing length later.
+		b.putInt32(int32(0))
+
+		// Put the flags, followed by each finite bound.
+		flags := v.Flags()
+		b.writeByte(flags)
+		subtype := v.ResolvedType().RangeContents()
+		if flags&(tree.RangeFlagEmpty|tree.RangeFlagLowerInf) == 0 {
+			b.writeBinaryDatum(ctx, v.Lower, sessionLoc, subtype)
+		}
+		if flags&(tree.RangeFlagEmpty|tree.RangeFlagUpperInf) == 0 {
+			b.writeBinaryDatum(ctx, v.Upper, sessionLoc, subtype)
+		}
+
+		lengthToWrite := b.Len() - (initialLen + 4)
+		b.putInt32AtIndex(initialLen /* index to write at */, int32(lengthToWrite))
+
+	case *tree.DMultirange:
+		initialLen := b.Len()
+
+		// Reserve bytes for writing length later.
+		b.putInt32(int32(0))
+
+		// Put the number of ranges, followed by each length-prefixed range.
+		b.putInt32(int32(len(v.Ranges)))
+		rangeTyp := types.MultirangeRangeType(v.ResolvedType())
+		for _, r := range v.Ranges {
+			b.writeBinaryDatum(ctx, r, sessionLoc, rangeTyp)
+		}
+
+		lengthToWrite := b.Len() - (initialLen + 4)
+		b.putInt32AtIndex(initialLen /* index to write at */, int32(lengthToWrite))
+
 	case *tree.DVoid:
 		b.putInt32(0)
 
@@ -747,6 +795,13 @@ func writeBinaryDatumNotNull(
 		b.putInt32(int32(len(v.EWKB())))
 		b.write(v.EWKB())
 
+	case *tree.DJsonpath:
+		s := v.Jsonpath.String()
+		b.putInt32(int32(len(s) + 1))
+		// Postgres version number, as of writing, `1` is the only valid value.
+		b.writeByte(1)
+		b.writeString(s)
+
 	case *tree.DTSQuery:
 		initialLen := b.Len()
 		// Reserve bytes for writing length later.
@@ -100,6 +100,8 @@ func getPlanColumns(plan planNode, mut bool) colinfo.ResultColumns {
 		return n.columns
 	case *showFingerprintsNode:
 		return n.columns
+	case *callNode:
+		return n.columns
 
 	// Nodes with a fixed schema.
 	case *scrubNode:
@@ -176,6 +178,10 @@ func getPlanColumns(plan planNode, mut bool) colinfo.ResultColumns {
 
 	case *identifySystemNode:
 		return n.getColumns(mut, colinfo.IdentifySystemColumns)
+	case *createReplicationSlotNode:
+		return n.getColumns(mut, colinfo.CreateReplicationSlotColumns)
+	case *readReplicationSlotNode:
+		return n.getColumns(mut, colinfo.ReadReplicationSlotColumns)
 	}
 
 	// Every other node has no columns in their results.
@@ -76,14 +76,15 @@ func (p *planner) prepareUsingOptimizer(
 		*tree.CreateSequence,
 		*tree.CreateStats,
 		*tree.Deallocate, *tree.Discard, *tree.DropDatabase, *tree.DropIndex,
-		*tree.DropTable, *tree.DropView, *tree.DropSequence, *tree.DropType,
+		*tree.DropTable, *tree.DropView, *tree.DropSequence, *tree.DropType, *tree.DropDomain,
 		*tree.Grant, *tree.GrantRole,
+		*tree.Listen, *tree.Notify,
 		*tree.Prepare,
 		*tree.ReleaseSavepoint, *tree.RenameColumn, *tree.RenameDatabase,
 		*tree.RenameIndex, *tree.RenameTable, *tree.Revoke, *tree.RevokeRole,
 		*tree.RollbackToSavepoint, *tree.RollbackTransaction,
 		*tree.Savepoint, *tree.SetTransaction, *tree.SetTracing, *tree.SetSessionAuthorizationDefault,
-		*tree.SetSessionCharacteristics:
+		*tree.SetSessionCharacteristics, *tree.SetConstraints:
 		// These statements do not have result columns and do not support placeholders
 		// so there is no need to do anything during prepare.
 		//
@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catsessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/lease"
@@ -232,6 +233,12 @@ type planner struct {
 
 	createdSequences createdSequences
 
+	deferredConstraints deferredConstraints
+
+	notifier notifier
+
+	advisoryLocker advisoryLocker
+
 	// autoCommit indicates whether the plan is allowed (but not required) to
 	// commit the transaction along with other KV operations. Committing the txn
 	// might be beneficial because it may enable the 1PC optimization. Note that
@@ -275,6 +282,10 @@ type planner struct {
 	trackDependency map[catid.DescID]bool
 
 	reducedAuditConfig *auditlogging.ReducedAuditConfig
+
+	// textSearchCache caches the user-defined text search configurations and
+	// dictionaries of the current database.
+	textSearchCache *dbdesc.TextSearchResolver
 }
 
 // hasFlowForPausablePortal returns true if the planner is for re-executing a
@@ -460,6 +471,9 @@ func newInternalPlanner(
 	p.sqlCursors = emptySqlCursors{}
 	p.preparedStatements = emptyPreparedStatements{}
 	p.createdSequences = emptyCreatedSequences{}
+	p.deferredConstraints = emptyDeferredConstraints{}
+	p.notifier = emptyNotifier{}
+	p.advisoryLocker = emptyAdvisoryLocker{}
 
 	p.schemaResolver.descCollection = p.Descriptors()
 	p.schemaResolver.sessionDataStack = sds
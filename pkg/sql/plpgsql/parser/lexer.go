@@ -490,6 +490,25 @@ func (l *lexer) ParseExpr(sqlStr string) (plpgsqltree.Expr, error) {
 	return exprs[0], nil
 }
 
+// ParseReturnExpr reads and parses the expression of a RETURN statement. The
+// expression is optional, since a bare RETURN is used to return from a routine
+// with output parameters. In that case, ParseReturnExpr returns a nil
+// expression.
+func (l *lexer) ParseReturnExpr() (plpgsqltree.Expr, error) {
+	if l.parser.Lookahead() != -1 {
+		// Push back the lookahead token so that it can be included.
+		l.PushBack(1)
+	}
+	if l.Peek().id == ';' {
+		return nil, nil
+	}
+	sqlStr, _, err := l.ReadSqlExpr(';')
+	if err != nil {
+		return nil, err
+	}
+	return l.ParseExpr(sqlStr)
+}
+
 func checkLoopLabels(start, end string) error {
 	if start == "" && end != "" {
 		return errors.Newf("end label \"%s\" specified for unlabeled block", end)
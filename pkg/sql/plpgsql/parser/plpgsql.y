@@ -759,6 +759,18 @@ stmt_assign: IDENT assign_operator expr_until_semi ';'
       Value: expr,
     }
   }
+| IDENT '.' IDENT assign_operator expr_until_semi ';'
+  {
+    expr, err := plpgsqllex.(*lexer).ParseExpr($5)
+    if err != nil {
+      return setErr(plpgsqllex, err)
+    }
+    $$.val = &plpgsqltree.Assignment{
+      Var: plpgsqltree.Variable($1),
+      Indirection: tree.Name($3),
+      Value: expr,
+    }
+  }
 ;
 
 stmt_getdiag: GET getdiag_area_opt DIAGNOSTICS getdiag_list ';'
@@ -1084,9 +1096,9 @@ query_options:
 ;
 
 
-return_variable: expr_until_semi
+return_variable:
   {
-    expr, err := plpgsqllex.(*lexer).ParseExpr($1)
+    expr, err := plpgsqllex.(*lexer).ParseReturnExpr()
     if err != nil {
       return setErr(plpgsqllex, err)
     }
@@ -66,7 +66,8 @@ const (
 	CREATEDB                 Kind = 34
 	CONTROLJOB               Kind = 35
 	REPAIRCLUSTERMETADATA    Kind = 36
-	largestKind                   = REPAIRCLUSTERMETADATA
+	TRIGGER                  Kind = 37
+	largestKind                   = TRIGGER
 )
 
 var isDeprecatedKind = map[Kind]bool{
@@ -150,6 +151,8 @@ func (k Kind) InternalKey() KindInternalKey {
 		return "CONTROLJOB"
 	case REPAIRCLUSTERMETADATA:
 		return "REPAIRCLUSTERMETADATA"
+	case TRIGGER:
+		return "TRIGGER"
 	default:
 		panic(errors.AssertionFailedf("unhandled kind: %d", int(k)))
 	}
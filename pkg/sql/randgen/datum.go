@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
@@ -293,6 +294,16 @@ func RandDatumWithNullChance(
 		return tree.NewDTSVector(tsearch.RandomTSVector(rng))
 	case types.TSQueryFamily:
 		return tree.NewDTSQuery(tsearch.RandomTSQuery(rng))
+	case types.JsonpathFamily:
+		return tree.NewDJsonpath(jsonpath.RandomJsonpath(rng))
+	case types.RangeFamily:
+		return randRange(rng, typ, func() tree.Datum {
+			return RandDatum(rng, typ.RangeContents(), true /* nullOk */)
+		})
+	case types.MultirangeFamily:
+		return randMultirange(rng, typ, func() tree.Datum {
+			return RandDatum(rng, types.MultirangeRangeType(typ).RangeContents(), true /* nullOk */)
+		})
 	default:
 		panic(errors.AssertionFailedf("invalid type %v", typ.DebugString()))
 	}
@@ -485,10 +496,55 @@ func RandDatumSimple(rng *rand.Rand, typ *types.T) tree.Datum {
 		datum = tree.NewDTSQuery(tsearch.RandomTSQuery(rng))
 	case types.TSVectorFamily:
 		datum = tree.NewDTSVector(tsearch.RandomTSVector(rng))
+	case types.JsonpathFamily:
+		datum = tree.NewDJsonpath(jsonpath.RandomJsonpath(rng))
+	case types.RangeFamily:
+		datum = randRange(rng, typ, func() tree.Datum {
+			return RandDatumSimple(rng, typ.RangeContents())
+		})
+	case types.MultirangeFamily:
+		datum = randMultirange(rng, typ, func() tree.Datum {
+			return RandDatumSimple(rng, types.MultirangeRangeType(typ).RangeContents())
+		})
 	}
 	return datum
 }
 
+// randRange generates a random range of the given type, using randBound to
+// generate its bounds. A NULL bound is infinite.
+func randRange(rng *rand.Rand, typ *types.T, randBound func() tree.Datum) *tree.DRange {
+	if rng.Intn(10) == 0 {
+		return tree.NewDEmptyRange(typ)
+	}
+	lower, upper := randBound(), randBound()
+	lowerInc, upperInc := rng.Intn(2) == 0, rng.Intn(2) == 0
+	r, err := tree.NewDRange(typ, lower, upper, lowerInc, upperInc)
+	if err != nil {
+		// The lower bound is greater than the upper bound.
+		if r, err = tree.NewDRange(typ, upper, lower, lowerInc, upperInc); err != nil {
+			return tree.NewDEmptyRange(typ)
+		}
+	}
+	return r
+}
+
+// randMultirange generates a random multirange of the given type, using
+// randBound to generate the bounds of its ranges.
+func randMultirange(
+	rng *rand.Rand, typ *types.T, randBound func() tree.Datum,
+) *tree.DMultirange {
+	rangeTyp := types.MultirangeRangeType(typ)
+	ranges := make([]*tree.DRange, rng.Intn(4))
+	for i := range ranges {
+		ranges[i] = randRange(rng, rangeTyp, randBound)
+	}
+	m, err := tree.NewDMultirange(typ, ranges)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
 func randStringSimple(rng *rand.Rand) string {
 	return string(rune('A' + rng.Intn(simpleRange)))
 }
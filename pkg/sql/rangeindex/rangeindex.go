@@ -0,0 +1,284 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package rangeindex contains the logic for indexing range and multirange
+// types with inverted indexes, which provide an access path similar to the
+// GiST indexes on ranges in Postgres.
+//
+// The values of the subtype of a range are mapped to keys in a
+// one-dimensional key space of maxLevel bits, such that the mapping
+// preserves the order of the values. The key space is divided into a
+// hierarchy of cells, similar to the S2 cells used by the geoindex package:
+// the single cell at level 0 covers the whole key space, and each cell at
+// level l is divided into two cells at level l+1. A range is indexed by a
+// covering of at most maxCells cells of the same level. Two ranges can only
+// overlap if a cell in the covering of one of them is equal to, an ancestor
+// of, or a descendant of a cell in the covering of the other, so each
+// operator is evaluated by scanning the cells, the ancestors and the
+// descendants of the covering of the constant operand. The coverings are
+// approximations of the ranges, so the resulting expressions are never
+// tight.
+package rangeindex
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// maxLevel is the level of the smallest cells, and the number of bits of
+	// the key space.
+	maxLevel = 62
+	// maxKey is the largest key of the key space.
+	maxKey = 1<<maxLevel - 1
+	// maxCells is the maximum number of cells in the covering of a range.
+	maxCells = 4
+)
+
+// cell identifies a cell in the hierarchy of cells. Like an S2 cell ID, it
+// consists of the bits of the position of the cell within its level,
+// followed by a single 1 bit and as many 0 bits as there are levels below
+// it.
+type cell uint64
+
+// emptyCell is the cell used to index empty ranges and multiranges. It is
+// not a valid cell since all valid cells have a 1 bit.
+const emptyCell cell = 0
+
+// makeCell returns the cell at the given level containing the given key.
+func makeCell(key uint64, level int) cell {
+	shift := uint(maxLevel - level)
+	return cell((key>>shift)<<(shift+1) | 1<<shift)
+}
+
+// lsb returns the lowest 1 bit of the cell.
+func (c cell) lsb() uint64 {
+	return uint64(c) & -uint64(c)
+}
+
+// rangeMin returns the smallest descendant of the cell at maxLevel.
+func (c cell) rangeMin() cell {
+	return c - cell(c.lsb()) + 1
+}
+
+// rangeMax returns the largest descendant of the cell at maxLevel.
+func (c cell) rangeMax() cell {
+	return c + cell(c.lsb()) - 1
+}
+
+// parent returns the parent of the cell, and whether it has one.
+func (c cell) parent() (cell, bool) {
+	lsb := c.lsb()
+	if lsb == 1<<maxLevel {
+		return 0, false
+	}
+	newLsb := lsb << 1
+	return cell(uint64(c)&-newLsb | newLsb), true
+}
+
+// datumKey maps a value of the subtype of a range to its key, such that the
+// mapping preserves the order of the values.
+func datumKey(d tree.Datum) (uint64, error) {
+	var ordered uint64
+	switch t := d.(type) {
+	case *tree.DInt:
+		ordered = uint64(*t) ^ 1<<63
+	case *tree.DDate:
+		ordered = uint64(int64(t.PGEpochDays())) ^ 1<<63
+	case *tree.DTimestamp:
+		ordered = uint64(t.UnixMicro()) ^ 1<<63
+	case *tree.DTimestampTZ:
+		ordered = uint64(t.UnixMicro()) ^ 1<<63
+	case *tree.DDecimal:
+		// Values beyond the range of a float64 are clamped to infinity, which
+		// preserves their order with respect to all other values.
+		f, err := t.Float64()
+		if err != nil {
+			if !errors.Is(err, strconv.ErrRange) {
+				return 0, err
+			}
+			f = math.Inf(1)
+			if t.Negative {
+				f = math.Inf(-1)
+			}
+		}
+		if f == 0 {
+			// Avoid distinguishing negative zero.
+			f = 0
+		}
+		ordered = math.Float64bits(f)
+		if f < 0 {
+			ordered = ^ordered
+		} else {
+			ordered |= 1 << 63
+		}
+	default:
+		return 0, errors.AssertionFailedf("unsupported range subtype %s", d.ResolvedType().SQLStringForError())
+	}
+	return ordered >> (64 - maxLevel), nil
+}
+
+// coverRange appends the cells of the covering of the given non-empty range
+// to cells. The bounds of the range are treated as inclusive, so the
+// covering may be larger than needed.
+func coverRange(cells []cell, r *tree.DRange) ([]cell, error) {
+	lo, hi := uint64(0), uint64(maxKey)
+	var err error
+	if r.Lower != nil {
+		if lo, err = datumKey(r.Lower); err != nil {
+			return nil, err
+		}
+	}
+	if r.Upper != nil {
+		if hi, err = datumKey(r.Upper); err != nil {
+			return nil, err
+		}
+	}
+	// Use the smallest cells such that at most maxCells are needed.
+	level := maxLevel
+	for ; level > 0; level-- {
+		shift := uint(maxLevel - level)
+		if (hi>>shift)-(lo>>shift) < maxCells {
+			break
+		}
+	}
+	shift := uint(maxLevel - level)
+	for pos := lo >> shift; pos <= hi>>shift; pos++ {
+		cells = append(cells, makeCell(pos<<shift, level))
+	}
+	return cells, nil
+}
+
+// covering returns the sorted and de-duplicated cells of the covering of the
+// given range or multirange. It returns no cells if the datum is empty.
+func covering(d tree.Datum) ([]cell, error) {
+	var cells []cell
+	var err error
+	for _, r := range tree.RangesOf(d) {
+		if cells, err = coverRange(cells, r); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(cells, func(i, j int) bool { return cells[i] < cells[j] })
+	res := cells[:0]
+	for i, c := range cells {
+		if i == 0 || c != cells[i-1] {
+			res = append(res, c)
+		}
+	}
+	return res, nil
+}
+
+// encodeCell returns the inverted index key for the given cell.
+func encodeCell(inKey []byte, c cell) []byte {
+	outKey := make([]byte, len(inKey), len(inKey)+encoding.MaxVarintLen)
+	copy(outKey, inKey)
+	return encoding.EncodeUvarintAscending(outKey, uint64(c))
+}
+
+// EncodeInvertedIndexKeys returns the inverted index keys for the given range
+// or multirange, each prefixed by inKey.
+func EncodeInvertedIndexKeys(inKey []byte, d tree.Datum) ([][]byte, error) {
+	cells, err := covering(d)
+	if err != nil {
+		return nil, err
+	}
+	if len(cells) == 0 {
+		return [][]byte{encodeCell(inKey, emptyCell)}, nil
+	}
+	keys := make([][]byte, len(cells))
+	for i, c := range cells {
+		keys[i] = encodeCell(inKey, c)
+	}
+	return keys, nil
+}
+
+// intersectingExpr returns an expression that reads the given cells, along
+// with their ancestors and descendants.
+func intersectingExpr(cells []cell) inverted.Expression {
+	var expr inverted.Expression
+	or := func(span inverted.Span) {
+		spanExpr := inverted.ExprForSpan(span, false /* tight */)
+		if expr == nil {
+			expr = spanExpr
+		} else {
+			expr = inverted.Or(expr, spanExpr)
+		}
+	}
+	ancestors := make(map[cell]struct{})
+	for _, c := range cells {
+		or(inverted.Span{
+			Start: encodeCell(nil /* inKey */, c.rangeMin()),
+			End:   encodeCell(nil /* inKey */, c.rangeMax()+1),
+		})
+		for p, ok := c.parent(); ok; p, ok = p.parent() {
+			if _, seen := ancestors[p]; seen {
+				break
+			}
+			ancestors[p] = struct{}{}
+			or(inverted.MakeSingleValSpan(encodeCell(nil /* inKey */, p)))
+		}
+	}
+	return expr
+}
+
+// Overlaps returns the expression that must be evaluated over an inverted
+// index on ranges or multiranges to find the values that overlap (&&) the
+// given range or multirange. It returns nil if the index cannot be used.
+func Overlaps(d tree.Datum) (inverted.Expression, error) {
+	cells, err := covering(d)
+	if err != nil || len(cells) == 0 {
+		return nil, err
+	}
+	return intersectingExpr(cells), nil
+}
+
+// Contains returns the expression that must be evaluated over an inverted
+// index on ranges or multiranges to find the values that contain (@>) the
+// given element, range or multirange. It returns nil if the index cannot be
+// used.
+func Contains(d tree.Datum) (inverted.Expression, error) {
+	switch d.(type) {
+	case *tree.DRange, *tree.DMultirange:
+		// A value that contains a non-empty range or multirange must overlap
+		// it. Every value contains an empty range, so the index cannot be used
+		// in that case.
+		return Overlaps(d)
+	}
+	key, err := datumKey(d)
+	if err != nil {
+		return nil, err
+	}
+	return intersectingExpr([]cell{makeCell(key, maxLevel)}), nil
+}
+
+// ContainedBy returns the expression that must be evaluated over an inverted
+// index on ranges or multiranges to find the values that are contained by
+// (<@) the given range or multirange.
+func ContainedBy(d tree.Datum) (inverted.Expression, error) {
+	// A value that is contained by the datum is either empty or overlaps it.
+	var expr inverted.Expression = inverted.ExprForSpan(
+		inverted.MakeSingleValSpan(encodeCell(nil /* inKey */, emptyCell)), false, /* tight */
+	)
+	overlapping, err := Overlaps(d)
+	if err != nil {
+		return nil, err
+	}
+	if overlapping != nil {
+		expr = inverted.Or(expr, overlapping)
+	}
+	return expr, nil
+}
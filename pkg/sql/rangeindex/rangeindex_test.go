@@ -0,0 +1,155 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rangeindex
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCell(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	root := makeCell(12345, 0)
+	_, ok := root.parent()
+	require.False(t, ok)
+	require.Equal(t, cell(1), root.rangeMin())
+	require.Equal(t, cell(maxKey<<1|1), root.rangeMax())
+
+	leaf := makeCell(12345, maxLevel)
+	require.Equal(t, leaf, leaf.rangeMin())
+	require.Equal(t, leaf, leaf.rangeMax())
+	level := maxLevel
+	for c, ok := leaf.parent(); ok; c, ok = c.parent() {
+		level--
+		require.Equal(t, makeCell(12345, level), c)
+		require.True(t, c.rangeMin() <= leaf && leaf <= c.rangeMax())
+	}
+	require.Equal(t, 0, level)
+}
+
+func TestDatumKeyOrder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	datums := []tree.Datum{
+		tree.NewDInt(-1 << 62), tree.NewDInt(-5), tree.NewDInt(0), tree.NewDInt(7), tree.NewDInt(1 << 62),
+	}
+	for _, s := range []string{"-1e400", "-1e10", "-2.5", "0", "0.001", "3", "1e100", "1e400"} {
+		d, err := tree.ParseDDecimal(s)
+		require.NoError(t, err)
+		datums = append(datums, d)
+	}
+	var prev uint64
+	for i, d := range datums {
+		key, err := datumKey(d)
+		require.NoError(t, err)
+		require.LessOrEqual(t, key, uint64(maxKey))
+		if i > 0 && d.ResolvedType().Equivalent(datums[i-1].ResolvedType()) {
+			require.LessOrEqual(t, prev, key, "%s", d)
+		}
+		prev = key
+	}
+
+	// Decimals beyond the range of a float64 have the keys of infinity.
+	for _, tc := range []struct{ s, inf string }{
+		{"1e400", "Infinity"},
+		{"-1e400", "-Infinity"},
+	} {
+		d, err := tree.ParseDDecimal(tc.s)
+		require.NoError(t, err)
+		inf, err := tree.ParseDDecimal(tc.inf)
+		require.NoError(t, err)
+		key, err := datumKey(d)
+		require.NoError(t, err)
+		infKey, err := datumKey(inf)
+		require.NoError(t, err)
+		require.Equal(t, infKey, key, "%s", d)
+	}
+}
+
+func TestRangeIndexExpressions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	rng, _ := randutil.NewTestRand()
+	randBound := func() tree.Datum {
+		if rng.Intn(10) == 0 {
+			return tree.DNull
+		}
+		// Mix small and large bounds to exercise coverings at many levels.
+		if rng.Intn(2) == 0 {
+			return tree.NewDInt(tree.DInt(rng.Intn(100) - 50))
+		}
+		return tree.NewDInt(tree.DInt(rng.Int63() - rng.Int63()))
+	}
+	randRange := func() *tree.DRange {
+		if rng.Intn(20) == 0 {
+			return tree.NewDEmptyRange(types.Int8Range)
+		}
+		lower, upper := randBound(), randBound()
+		if lower != tree.DNull && upper != tree.DNull &&
+			*lower.(*tree.DInt) > *upper.(*tree.DInt) {
+			lower, upper = upper, lower
+		}
+		r, err := tree.NewDRange(types.Int8Range, lower, upper, rng.Intn(2) == 0, rng.Intn(2) == 0)
+		require.NoError(t, err)
+		return r
+	}
+	matches := func(expr inverted.Expression, keys [][]byte) bool {
+		if expr == nil {
+			// The index cannot be used, so all values are candidates.
+			return true
+		}
+		require.False(t, expr.IsTight())
+		ok, err := expr.(*inverted.SpanExpression).ContainsKeys(keys)
+		require.NoError(t, err)
+		return ok
+	}
+
+	for i := 0; i < 1000; i++ {
+		indexed, query := randRange(), randRange()
+		keys, err := EncodeInvertedIndexKeys(nil /* inKey */, indexed)
+		require.NoError(t, err)
+
+		overlaps, err := Overlaps(query)
+		require.NoError(t, err)
+		contains, err := Contains(query)
+		require.NoError(t, err)
+		containedBy, err := ContainedBy(query)
+		require.NoError(t, err)
+		elem := randBound()
+		if elem == tree.DNull {
+			elem = tree.DZero
+		}
+		containsElem, err := Contains(elem)
+		require.NoError(t, err)
+
+		// The expressions are not tight, but they must find every value that
+		// satisfies the predicate.
+		if tree.RangeOverlaps(indexed, query) {
+			require.True(t, matches(overlaps, keys), "%s && %s", indexed, query)
+		}
+		if tree.RangeContainsRange(indexed, query) {
+			require.True(t, matches(contains, keys), "%s @> %s", indexed, query)
+		}
+		if tree.RangeContainsRange(query, indexed) {
+			require.True(t, matches(containedBy, keys), "%s <@ %s", indexed, query)
+		}
+		if tree.RangeContainsElem(indexed, elem) {
+			require.True(t, matches(containsElem, keys), "%s @> %s", indexed, elem)
+		}
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/errors"
 )
 
 type refreshMaterializedViewNode struct {
@@ -91,6 +92,20 @@ func (n *refreshMaterializedViewNode) startExec(params runParams) error {
 		)
 	}
 
+	if n.n.Incrementally {
+		err := n.refreshIncrementally(params)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, errIncrementalRefreshUnsupported) {
+			return err
+		}
+		params.p.BufferClientNotice(
+			params.ctx,
+			pgnotice.Newf("materialized view %q is refreshed in full: %v", n.desc.Name, err),
+		)
+	}
+
 	// Prepare the new set of indexes by cloning all existing indexes on the view.
 	newPrimaryIndex := n.desc.GetPrimaryIndex().IndexDescDeepCopy()
 	newIndexes := make([]descpb.IndexDescriptor, len(n.desc.PublicNonPrimaryIndexes()))
@@ -128,6 +143,39 @@ func (n *refreshMaterializedViewNode) startExec(params runParams) error {
 	)
 }
 
+// refreshIncrementally refreshes the view by applying the changes to its
+// source tables since the last refresh in the current transaction. It returns
+// an error marked with errIncrementalRefreshUnsupported if the view must be
+// refreshed in full instead.
+func (n *refreshMaterializedViewNode) refreshIncrementally(params runParams) error {
+	if n.desc.RefreshViewRequired {
+		return incrementalRefreshUnsupportedf("the view has not been populated")
+	}
+	r := incrementalViewRefresh{
+		p:    params.p,
+		view: n.desc,
+		from: n.desc.MaterializedViewRefreshTime,
+		to:   params.p.Txn().ReadTimestamp(),
+	}
+	if err := r.init(params.ctx); err != nil {
+		return err
+	}
+	if err := r.refresh(params.ctx); err != nil {
+		return err
+	}
+	// Record the time of the refresh, so that the next refresh only needs to
+	// consider the changes after it. Note that the changes were read as of the
+	// read timestamp of the transaction, so it is the right time to record
+	// even if the transaction commits at a later timestamp.
+	n.desc.MaterializedViewRefreshTime = r.to
+	return params.p.writeSchemaChange(
+		params.ctx,
+		n.desc,
+		descpb.InvalidMutationID,
+		tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
 func (n *refreshMaterializedViewNode) Next(params runParams) (bool, error) { return false, nil }
 func (n *refreshMaterializedViewNode) Values() tree.Datums                 { return tree.Datums{} }
 func (n *refreshMaterializedViewNode) Close(ctx context.Context)           {}
@@ -0,0 +1,923 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+)
+
+// incrementalRefreshMaxChangedRows limits the number of source table rows
+// that REFRESH MATERIALIZED VIEW ... INCREMENTALLY is willing to process.
+var incrementalRefreshMaxChangedRows = settings.RegisterIntSetting(
+	settings.ApplicationLevel,
+	"sql.materialized_views.incremental_refresh.max_changed_rows",
+	"maximum number of source table rows changed since the last refresh for which "+
+		"REFRESH MATERIALIZED VIEW ... INCREMENTALLY applies the changes to the view; "+
+		"if more rows have changed, the view is refreshed in full",
+	10000,
+	settings.NonNegativeInt,
+)
+
+// errIncrementalRefreshUnsupported marks the errors that explain why a
+// materialized view cannot be refreshed incrementally. These errors are not
+// returned to the user. Instead, the view is refreshed in full.
+var errIncrementalRefreshUnsupported = errors.New("incremental refresh unsupported")
+
+func incrementalRefreshUnsupportedf(format string, args ...interface{}) error {
+	return errors.Mark(errors.Newf(format, args...), errIncrementalRefreshUnsupported)
+}
+
+// viewSource is a table in the FROM clause of a materialized view query.
+type viewSource struct {
+	// alias is the name that the columns of the table are qualified with in
+	// the view query.
+	alias tree.TableName
+	desc  catalog.TableDescriptor
+	// keyCols are the ordinals of the view columns that hold the primary key
+	// columns of the table. They are only set for views without aggregation.
+	keyCols []int
+}
+
+// incrementalViewRefresh refreshes a materialized view by only recomputing
+// the rows of the view that are affected by the changes to its source tables
+// since the last refresh. Two shapes of view queries are supported:
+//
+//   - Select-project-join queries that project the primary key columns of all
+//     their source tables. Every row of the view is derived from exactly one
+//     combination of source rows, so the view rows derived from changed source
+//     rows are deleted, and then recomputed from the current source rows.
+//
+//   - Aggregations with a GROUP BY clause over a select-project-join query,
+//     where every grouping expression is a column of the view. The groups that
+//     contained a changed source row before or after the change are deleted
+//     from the view and then recomputed.
+//
+// The changed source rows are found with an incremental export of the primary
+// index of each source table, which only visits the keys written after the
+// last refresh.
+type incrementalViewRefresh struct {
+	p    *planner
+	view *tabledesc.Mutable
+	// from is the timestamp of the last refresh, and to is the timestamp that
+	// the view is refreshed to.
+	from, to hlc.Timestamp
+
+	sources []viewSource
+	// groupCols are the ordinals of the view columns that hold the grouping
+	// expressions of an aggregation, and groupExprs are the expressions. They
+	// are empty for view queries without aggregation.
+	groupCols  []int
+	groupExprs tree.Exprs
+}
+
+// parseViewQuery parses the query of the view and returns its SELECT clause.
+// A fresh copy is returned on every call, so that callers are free to modify
+// it.
+func (r *incrementalViewRefresh) parseViewQuery() (*tree.Select, *tree.SelectClause, error) {
+	stmt, err := parser.ParseOne(r.view.GetViewQuery())
+	if err != nil {
+		return nil, nil, err
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		return nil, nil, incrementalRefreshUnsupportedf("view query is not a SELECT statement")
+	}
+	if sel.With != nil || sel.Limit != nil || len(sel.Locking) != 0 {
+		return nil, nil, incrementalRefreshUnsupportedf(
+			"view query has a WITH, LIMIT or locking clause",
+		)
+	}
+	clause, ok := sel.Select.(*tree.SelectClause)
+	if !ok {
+		return nil, nil, incrementalRefreshUnsupportedf("view query is not a simple SELECT clause")
+	}
+	return sel, clause, nil
+}
+
+// init checks that the view can be refreshed incrementally, and resolves its
+// source tables. It returns an error marked with
+// errIncrementalRefreshUnsupported if the view must be refreshed in full.
+func (r *incrementalViewRefresh) init(ctx context.Context) error {
+	if r.from.IsEmpty() {
+		return incrementalRefreshUnsupportedf("the time of the last refresh is unknown")
+	}
+	if len(r.view.PartialIndexes()) > 0 {
+		return incrementalRefreshUnsupportedf("view has partial indexes")
+	}
+	_, clause, err := r.parseViewQuery()
+	if err != nil {
+		return err
+	}
+	if clause.Distinct || clause.DistinctOn != nil || len(clause.Window) > 0 ||
+		clause.TableSelect || clause.From.AsOf.Expr != nil {
+		return incrementalRefreshUnsupportedf(
+			"view query has a DISTINCT, WINDOW or AS OF SYSTEM TIME clause",
+		)
+	}
+	if len(clause.From.Tables) == 0 {
+		return incrementalRefreshUnsupportedf("view query has no FROM clause")
+	}
+	for _, t := range clause.From.Tables {
+		if err := r.addSources(ctx, t); err != nil {
+			return err
+		}
+	}
+	if len(clause.Exprs) != len(r.view.VisibleColumns()) {
+		return errors.AssertionFailedf(
+			"view %q has %d columns, but its query has %d", r.view.GetName(),
+			len(r.view.VisibleColumns()), len(clause.Exprs),
+		)
+	}
+	if err := r.checkColumns(); err != nil {
+		return err
+	}
+
+	// Check the expressions of the query.
+	v := incrementalRefreshExprChecker{ctx: ctx, p: r.p}
+	for _, e := range clause.Exprs {
+		switch e.Expr.(type) {
+		case tree.UnqualifiedStar, *tree.AllColumnsSelector, *tree.TupleStar:
+			return incrementalRefreshUnsupportedf("view query has a star expression")
+		}
+		v.check(e.Expr)
+	}
+	if clause.Where != nil {
+		v.check(clause.Where.Expr)
+	}
+	if clause.Having != nil {
+		v.check(clause.Having.Expr)
+	}
+	for _, e := range clause.GroupBy {
+		v.check(e)
+	}
+	for _, t := range clause.From.Tables {
+		r.checkJoinConditions(&v, t)
+	}
+	if v.err != nil {
+		return v.err
+	}
+
+	if v.hasAggregate || len(clause.GroupBy) > 0 || clause.Having != nil {
+		return r.initGroups(clause)
+	}
+	return r.initKeys(clause)
+}
+
+// checkColumns checks that all columns of the view are visible columns
+// computed by the view query, except for the hidden row ID column that forms
+// its primary key.
+func (r *incrementalViewRefresh) checkColumns() error {
+	pk := r.view.GetPrimaryIndex()
+	if pk.NumKeyColumns() != 1 {
+		return incrementalRefreshUnsupportedf("view has an explicit primary key")
+	}
+	for _, col := range r.view.PublicColumns() {
+		if !col.IsHidden() {
+			continue
+		}
+		if col.GetID() != pk.GetKeyColumnID(0) || col.GetType().Family() != types.IntFamily {
+			return incrementalRefreshUnsupportedf("view has hidden column %q", col.GetName())
+		}
+	}
+	return nil
+}
+
+// addSources adds the tables of the given FROM clause expression to the
+// sources of the view.
+func (r *incrementalViewRefresh) addSources(ctx context.Context, expr tree.TableExpr) error {
+	switch t := expr.(type) {
+	case *tree.ParenTableExpr:
+		return r.addSources(ctx, t.Expr)
+
+	case *tree.JoinTableExpr:
+		switch t.JoinType {
+		case "", tree.AstInner, tree.AstCross:
+		default:
+			return incrementalRefreshUnsupportedf("view query has a %s JOIN", t.JoinType)
+		}
+		switch t.Cond.(type) {
+		case nil, *tree.OnJoinCond:
+		default:
+			return incrementalRefreshUnsupportedf("view query has a NATURAL or USING join")
+		}
+		if err := r.addSources(ctx, t.Left); err != nil {
+			return err
+		}
+		return r.addSources(ctx, t.Right)
+
+	case *tree.AliasedTableExpr:
+		tn, ok := t.Expr.(*tree.TableName)
+		if !ok || t.Ordinality || t.Lateral || len(t.As.Cols) > 0 {
+			return incrementalRefreshUnsupportedf(
+				"view query has a source that is not a table: %s", tree.AsString(t),
+			)
+		}
+		un := tn.ToUnresolvedObjectName()
+		desc, err := r.p.ResolveExistingObjectEx(ctx, un, true /* required */, tree.ResolveAnyTableKind)
+		if err != nil {
+			return err
+		}
+		if !desc.IsTable() || desc.IsVirtualTable() {
+			return incrementalRefreshUnsupportedf("%q is not a table", desc.GetName())
+		}
+		// The changes to the source table are found by scanning its primary
+		// index. If the schema of the table has changed since the last refresh,
+		// the primary index may not be the same one that the view was computed
+		// from.
+		if r.from.Less(desc.GetModificationTime()) {
+			return incrementalRefreshUnsupportedf(
+				"the schema of table %q changed after the last refresh", desc.GetName(),
+			)
+		}
+		alias := *tn
+		if t.As.Alias != "" {
+			alias = tree.MakeUnqualifiedTableName(t.As.Alias)
+		}
+		r.sources = append(r.sources, viewSource{alias: alias, desc: desc})
+		return nil
+
+	default:
+		return incrementalRefreshUnsupportedf(
+			"view query has a source that is not a table: %s", tree.AsString(t),
+		)
+	}
+}
+
+// checkJoinConditions checks the ON conditions of the joins in the given FROM
+// clause expression.
+func (r *incrementalViewRefresh) checkJoinConditions(
+	v *incrementalRefreshExprChecker, expr tree.TableExpr,
+) {
+	switch t := expr.(type) {
+	case *tree.ParenTableExpr:
+		r.checkJoinConditions(v, t.Expr)
+	case *tree.JoinTableExpr:
+		if on, ok := t.Cond.(*tree.OnJoinCond); ok {
+			v.check(on.Expr)
+		}
+		r.checkJoinConditions(v, t.Left)
+		r.checkJoinConditions(v, t.Right)
+	}
+}
+
+// initGroups finds the view columns that hold the grouping expressions of an
+// aggregation.
+func (r *incrementalViewRefresh) initGroups(clause *tree.SelectClause) error {
+	if len(clause.GroupBy) == 0 {
+		return incrementalRefreshUnsupportedf("view query has an aggregation without GROUP BY")
+	}
+	for _, g := range clause.GroupBy {
+		col := -1
+		if n, ok := g.(*tree.NumVal); ok {
+			// GROUP BY <ordinal> refers to a column of the SELECT list.
+			if i, err := n.AsInt64(); err == nil && i >= 1 && int(i) <= len(clause.Exprs) {
+				col = int(i) - 1
+			}
+		} else {
+			str := tree.AsString(g)
+			for i := range clause.Exprs {
+				if tree.AsString(clause.Exprs[i].Expr) == str {
+					col = i
+					break
+				}
+			}
+		}
+		if col < 0 {
+			return incrementalRefreshUnsupportedf(
+				"GROUP BY expression %s is not a column of the view", tree.AsString(g),
+			)
+		}
+		r.groupCols = append(r.groupCols, col)
+		r.groupExprs = append(r.groupExprs, clause.Exprs[col].Expr)
+	}
+	return nil
+}
+
+// initKeys finds the view columns that hold the primary key columns of each
+// source table of a view without aggregation.
+func (r *incrementalViewRefresh) initKeys(clause *tree.SelectClause) error {
+	for i := range r.sources {
+		src := &r.sources[i]
+		pk := src.desc.GetPrimaryIndex()
+		for j := 0; j < pk.NumKeyColumns(); j++ {
+			colName := pk.GetKeyColumnName(j)
+			col := -1
+			for k := range clause.Exprs {
+				name, ok := clause.Exprs[k].Expr.(*tree.UnresolvedName)
+				if !ok {
+					continue
+				}
+				if s, c := r.resolveColumn(name); s == i && c == colName {
+					col = k
+					break
+				}
+			}
+			if col < 0 {
+				return incrementalRefreshUnsupportedf(
+					"primary key column %q of table %q is not a column of the view",
+					colName, src.desc.GetName(),
+				)
+			}
+			src.keyCols = append(src.keyCols, col)
+		}
+	}
+	return nil
+}
+
+// resolveColumn returns the ordinal of the source that the given column
+// reference refers to, and the name of the column. It returns -1 if the
+// reference cannot be resolved unambiguously.
+func (r *incrementalViewRefresh) resolveColumn(name *tree.UnresolvedName) (int, string) {
+	if name.Star {
+		return -1, ""
+	}
+	colName := name.Parts[0]
+	res := -1
+	for i := range r.sources {
+		src := &r.sources[i]
+		if name.NumParts > 1 && string(src.alias.ObjectName) != name.Parts[1] {
+			continue
+		}
+		if catalog.FindColumnByName(src.desc, colName) == nil {
+			continue
+		}
+		if res != -1 {
+			return -1, ""
+		}
+		res = i
+	}
+	return res, colName
+}
+
+// incrementalRefreshExprChecker checks that the expressions of a view query
+// are deterministic, so that recomputing a part of the view yields the same
+// rows as a full refresh. It also records whether the query has aggregate
+// functions.
+type incrementalRefreshExprChecker struct {
+	ctx          context.Context
+	p            *planner
+	err          error
+	hasAggregate bool
+}
+
+var _ tree.Visitor = &incrementalRefreshExprChecker{}
+
+func (v *incrementalRefreshExprChecker) check(expr tree.Expr) {
+	if v.err == nil {
+		tree.WalkExprConst(v, expr)
+	}
+}
+
+// VisitPre is part of the tree.Visitor interface.
+func (v *incrementalRefreshExprChecker) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if v.err != nil {
+		return false, expr
+	}
+	switch t := expr.(type) {
+	case *tree.Subquery:
+		v.err = incrementalRefreshUnsupportedf("view query has a subquery")
+		return false, expr
+
+	case *tree.FuncExpr:
+		if t.WindowDef != nil {
+			v.err = incrementalRefreshUnsupportedf("view query has a window function")
+			return false, expr
+		}
+		def, err := t.Func.Resolve(v.ctx, v.p.semaCtx.SearchPath, v.p.semaCtx.FunctionResolver)
+		if err != nil {
+			v.err = err
+			return false, expr
+		}
+		for _, o := range def.Overloads {
+			if o.Type != tree.BuiltinRoutine {
+				v.err = incrementalRefreshUnsupportedf(
+					"view query calls user-defined function %s", def.Name,
+				)
+				return false, expr
+			}
+			switch o.Class {
+			case tree.AggregateClass:
+				v.hasAggregate = true
+			case tree.NormalClass:
+				if o.Volatility > volatility.Immutable {
+					v.err = incrementalRefreshUnsupportedf(
+						"view query calls %s function %s", o.Volatility, def.Name,
+					)
+					return false, expr
+				}
+			default:
+				v.err = incrementalRefreshUnsupportedf("view query calls function %s", def.Name)
+				return false, expr
+			}
+		}
+	}
+	return true, expr
+}
+
+// VisitPost is part of the tree.Visitor interface.
+func (v *incrementalRefreshExprChecker) VisitPost(expr tree.Expr) tree.Expr { return expr }
+
+// refresh applies the changes to the source tables between the last refresh
+// and the refresh timestamp to the view.
+func (r *incrementalViewRefresh) refresh(ctx context.Context) error {
+	// Find the primary keys of the changed rows of every source table.
+	limit := int(incrementalRefreshMaxChangedRows.Get(&r.p.ExecCfg().Settings.SV))
+	changed := make(map[descpb.ID][]tree.Datums)
+	numChanged := 0
+	for _, src := range r.sources {
+		if _, ok := changed[src.desc.GetID()]; ok {
+			continue
+		}
+		keys, err := r.changedPrimaryKeys(ctx, src.desc, limit-numChanged)
+		if err != nil {
+			return err
+		}
+		changed[src.desc.GetID()] = keys
+		numChanged += len(keys)
+	}
+	if numChanged == 0 {
+		return nil
+	}
+
+	// Build a filter over the source tables that selects the changed rows.
+	var changedFilter tree.Expr
+	for _, src := range r.sources {
+		keys := changed[src.desc.GetID()]
+		if len(keys) == 0 {
+			continue
+		}
+		pk := src.desc.GetPrimaryIndex()
+		cols := make(tree.Exprs, pk.NumKeyColumns())
+		for i := range cols {
+			cols[i] = tree.NewColumnItem(&src.alias, tree.Name(pk.GetKeyColumnName(i)))
+		}
+		changedFilter = orExprs(changedFilter, matchTuples(cols, keys))
+	}
+
+	var deleteFilter, insertFilter tree.Expr
+	if len(r.groupExprs) == 0 {
+		// The rows of the view that are derived from a changed source row are
+		// identified by the primary key of the source row.
+		for _, src := range r.sources {
+			keys := changed[src.desc.GetID()]
+			if len(keys) == 0 {
+				continue
+			}
+			deleteFilter = orExprs(deleteFilter, matchTuples(r.viewColumns(src.keyCols), keys))
+		}
+		insertFilter = changedFilter
+	} else {
+		// Find the groups that contained a changed source row before or after
+		// the change.
+		groups, err := r.changedGroups(ctx, changedFilter)
+		if err != nil {
+			return err
+		}
+		if len(groups) == 0 {
+			return nil
+		}
+		deleteFilter = matchTuples(r.viewColumns(r.groupCols), groups)
+		insertFilter = matchTuples(r.groupExprs, groups)
+	}
+
+	// Read the rows of the view that need to be deleted, and compute the rows
+	// that replace them, before writing anything.
+	cols := r.view.PublicColumns()
+	colExprs := make(tree.SelectExprs, len(cols))
+	for i, col := range cols {
+		colExprs[i].Expr = tree.NewColumnItem(&viewTableAlias, tree.Name(col.GetName()))
+	}
+	deleteQuery := &tree.Select{Select: &tree.SelectClause{
+		Exprs: colExprs,
+		From: tree.From{Tables: tree.TableExprs{&tree.AliasedTableExpr{
+			Expr: &tree.TableRef{
+				TableID: int64(r.view.GetID()),
+				As:      tree.AliasClause{Alias: viewTableAlias.ObjectName},
+			},
+		}}},
+		Where: tree.NewWhere(tree.AstWhere, deleteFilter),
+	}}
+	oldRows, err := r.query(ctx, r.p.InternalSQLTxn(), "refresh-view-old-rows", deleteQuery)
+	if err != nil {
+		return err
+	}
+	sel, clause, err := r.parseViewQuery()
+	if err != nil {
+		return err
+	}
+	sel.OrderBy = nil
+	clause.Where = tree.NewWhere(tree.AstWhere, andExprs(whereExpr(clause.Where), insertFilter))
+	newRows, err := r.query(ctx, r.p.InternalSQLTxn(), "refresh-view-new-rows", sel)
+	if err != nil {
+		return err
+	}
+	return r.applyChanges(ctx, oldRows, newRows)
+}
+
+// viewTableAlias is the alias of the view in the queries that read the rows
+// stored for the view.
+var viewTableAlias = tree.MakeUnqualifiedTableName("v")
+
+// viewColumns returns references to the given columns of the view.
+func (r *incrementalViewRefresh) viewColumns(ords []int) tree.Exprs {
+	visible := r.view.VisibleColumns()
+	res := make(tree.Exprs, len(ords))
+	for i, ord := range ords {
+		res[i] = tree.NewColumnItem(&viewTableAlias, tree.Name(visible[ord].GetName()))
+	}
+	return res
+}
+
+// changedGroups returns the distinct values of the grouping expressions of the
+// given changed source rows, both as of the last refresh and as of the refresh
+// timestamp.
+func (r *incrementalViewRefresh) changedGroups(
+	ctx context.Context, changedFilter tree.Expr,
+) ([]tree.Datums, error) {
+	groupQuery := func() (*tree.Select, error) {
+		sel, clause, err := r.parseViewQuery()
+		if err != nil {
+			return nil, err
+		}
+		sel.OrderBy = nil
+		clause.Distinct = true
+		clause.Exprs = make(tree.SelectExprs, len(r.groupExprs))
+		for i := range r.groupExprs {
+			clause.Exprs[i].Expr = r.groupExprs[i]
+		}
+		clause.GroupBy = nil
+		clause.Having = nil
+		clause.Where = tree.NewWhere(tree.AstWhere, andExprs(whereExpr(clause.Where), changedFilter))
+		return sel, nil
+	}
+
+	var oldGroups []tree.Datums
+	if err := r.p.ExecCfg().InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		if err := txn.KV().SetFixedTimestamp(ctx, r.from); err != nil {
+			return err
+		}
+		sel, err := groupQuery()
+		if err != nil {
+			return err
+		}
+		oldGroups, err = r.query(ctx, txn, "refresh-view-old-groups", sel)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	sel, err := groupQuery()
+	if err != nil {
+		return nil, err
+	}
+	newGroups, err := r.query(ctx, r.p.InternalSQLTxn(), "refresh-view-new-groups", sel)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(oldGroups)+len(newGroups))
+	res := make([]tree.Datums, 0, len(oldGroups)+len(newGroups))
+	for _, groups := range [][]tree.Datums{oldGroups, newGroups} {
+		for _, g := range groups {
+			key := tree.AsStringWithFlags(&tree.DTuple{D: g}, tree.FmtParsable)
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				res = append(res, g)
+			}
+		}
+	}
+	return res, nil
+}
+
+// query runs the given query in the given transaction.
+func (r *incrementalViewRefresh) query(
+	ctx context.Context, txn isql.Txn, opName string, sel *tree.Select,
+) ([]tree.Datums, error) {
+	return txn.QueryBufferedEx(
+		ctx, opName, txn.KV(), sessiondata.RootUserSessionDataOverride,
+		tree.AsStringWithFlags(sel, tree.FmtParsable),
+	)
+}
+
+// applyChanges deletes the given old rows of the view and inserts the new
+// rows. The old rows contain all public columns of the view, while the new
+// rows contain its visible columns.
+func (r *incrementalViewRefresh) applyChanges(
+	ctx context.Context, oldRows, newRows []tree.Datums,
+) error {
+	p := r.p
+	desc := r.view.ImmutableCopy().(catalog.TableDescriptor)
+	execCfg := p.ExecCfg()
+	evalCtx := p.EvalContext()
+	internal := p.SessionData().Internal
+	traceKV := p.ExtendedEvalContext().Tracing.KVTracingEnabled()
+	cols := desc.PublicColumns()
+	// Neither the deleter nor the inserter need to maintain partial indexes,
+	// because views with partial indexes are always refreshed in full.
+	var pm row.PartialIndexUpdateHelper
+
+	td := &tableDeleter{
+		rd: row.MakeDeleter(
+			execCfg.Codec, desc, cols, &execCfg.Settings.SV, internal, execCfg.GetRowMetrics(internal),
+		),
+		alloc: &tree.DatumAlloc{},
+	}
+	defer td.close(ctx)
+	if err := td.init(ctx, p.Txn(), evalCtx, &evalCtx.Settings.SV); err != nil {
+		return err
+	}
+	for _, oldRow := range oldRows {
+		if err := p.cancelChecker.Check(); err != nil {
+			return err
+		}
+		if td.currentBatchSize >= td.maxBatchSize ||
+			td.b.ApproximateMutationBytes() >= td.maxBatchByteSize {
+			if err := td.flushAndStartNewBatch(ctx); err != nil {
+				return err
+			}
+		}
+		if err := td.row(ctx, oldRow, pm, traceKV); err != nil {
+			return err
+		}
+	}
+	if err := td.finalize(ctx); err != nil {
+		return err
+	}
+
+	ri, err := row.MakeInserter(
+		ctx, p.Txn(), execCfg.Codec, desc, cols, &tree.DatumAlloc{},
+		&execCfg.Settings.SV, internal, execCfg.GetRowMetrics(internal),
+	)
+	if err != nil {
+		return err
+	}
+	ti := &tableInserter{ri: ri}
+	defer ti.close(ctx)
+	if err := ti.init(ctx, p.Txn(), evalCtx, &evalCtx.Settings.SV); err != nil {
+		return err
+	}
+	rowBuffer := make(tree.Datums, len(cols))
+	for _, newRow := range newRows {
+		if err := p.cancelChecker.Check(); err != nil {
+			return err
+		}
+		if ti.currentBatchSize >= ti.maxBatchSize ||
+			ti.b.ApproximateMutationBytes() >= ti.maxBatchByteSize {
+			if err := ti.flushAndStartNewBatch(ctx); err != nil {
+				return err
+			}
+		}
+		// The visible columns of the view precede its hidden row ID column,
+		// which is populated the same way as the default expression of the
+		// column would.
+		ord := 0
+		for i, col := range cols {
+			if col.IsHidden() {
+				rowBuffer[i] = tree.NewDInt(builtins.GenerateUniqueInt(
+					builtins.ProcessUniqueID(evalCtx.NodeID.SQLInstanceID()),
+				))
+				continue
+			}
+			rowBuffer[i] = newRow[ord]
+			ord++
+		}
+		if err := ti.row(ctx, rowBuffer, pm, traceKV); err != nil {
+			return err
+		}
+	}
+	return ti.finalize(ctx)
+}
+
+// changedPrimaryKeys returns the primary keys of the rows of the given table
+// that were written between the last refresh and the refresh timestamp. An
+// error marked with errIncrementalRefreshUnsupported is returned if there are
+// more than limit such rows, or if the changes cannot be determined.
+//
+// The changes are found with an incremental export of the primary index,
+// which uses an MVCC incremental iterator to only visit the keys that were
+// written in the time interval.
+func (r *incrementalViewRefresh) changedPrimaryKeys(
+	ctx context.Context, desc catalog.TableDescriptor, limit int,
+) ([]tree.Datums, error) {
+	execCfg := r.p.ExecCfg()
+	pk := desc.GetPrimaryIndex()
+	colTypes := make([]*types.T, pk.NumKeyColumns())
+	for i := range colTypes {
+		col, err := catalog.MustFindColumnByID(desc, pk.GetKeyColumnID(i))
+		if err != nil {
+			return nil, err
+		}
+		colTypes[i] = col.GetType()
+	}
+	colDirs := pk.IndexDesc().KeyColumnDirections
+
+	var res []tree.Datums
+	var alloc tree.DatumAlloc
+	seen := make(map[string]struct{})
+	addKey := func(key []byte) error {
+		// All the column families of a row share the same row prefix.
+		prefixLen, err := keys.GetRowPrefixLength(key)
+		if err != nil {
+			return err
+		}
+		rowKey := key[:prefixLen]
+		if _, ok := seen[string(rowKey)]; ok {
+			return nil
+		}
+		seen[string(rowKey)] = struct{}{}
+		if len(res) >= limit {
+			return incrementalRefreshUnsupportedf(
+				"more than %d source table rows changed after the last refresh",
+				incrementalRefreshMaxChangedRows.Get(&execCfg.Settings.SV),
+			)
+		}
+		vals := make([]rowenc.EncDatum, len(colTypes))
+		if _, err := rowenc.DecodeIndexKey(execCfg.Codec, vals, colDirs, rowKey); err != nil {
+			return err
+		}
+		datums := make(tree.Datums, len(vals))
+		for i := range vals {
+			if err := vals[i].EnsureDecoded(colTypes[i], &alloc); err != nil {
+				return err
+			}
+			datums[i] = vals[i].Datum
+		}
+		res = append(res, datums)
+		return nil
+	}
+
+	span := desc.PrimaryIndexSpan(execCfg.Codec)
+	header := kvpb.Header{
+		Timestamp:                   r.to,
+		ReturnElasticCPUResumeSpans: true,
+	}
+	for len(span.Key) != 0 {
+		req := &kvpb.ExportRequest{
+			RequestHeader: kvpb.RequestHeaderFromSpan(span),
+			StartTime:     r.from,
+			MVCCFilter:    kvpb.MVCCFilter_Latest,
+		}
+		resp, pErr := kv.SendWrappedWith(ctx, execCfg.DB.NonTransactionalSender(), header, req)
+		if pErr != nil {
+			err := pErr.GoError()
+			if errors.HasType(err, (*kvpb.BatchTimestampBeforeGCError)(nil)) {
+				return nil, incrementalRefreshUnsupportedf(
+					"the changes to table %q since the last refresh have been garbage collected",
+					desc.GetName(),
+				)
+			}
+			return nil, err
+		}
+		exportResp := resp.(*kvpb.ExportResponse)
+		for _, file := range exportResp.Files {
+			if err := func() error {
+				iter, err := storage.NewMemSSTIterator(file.SST, false /* verify */, storage.IterOptions{
+					KeyTypes:   storage.IterKeyTypePointsAndRanges,
+					LowerBound: file.Span.Key,
+					UpperBound: file.Span.EndKey,
+				})
+				if err != nil {
+					return err
+				}
+				defer iter.Close()
+				for iter.SeekGE(storage.MVCCKey{Key: file.Span.Key}); ; iter.Next() {
+					if ok, err := iter.Valid(); err != nil {
+						return err
+					} else if !ok {
+						return nil
+					}
+					if _, hasRange := iter.HasPointAndRange(); hasRange {
+						return incrementalRefreshUnsupportedf(
+							"a range of table %q was deleted after the last refresh", desc.GetName(),
+						)
+					}
+					if err := addKey(iter.UnsafeKey().Key); err != nil {
+						return err
+					}
+				}
+			}(); err != nil {
+				return nil, err
+			}
+		}
+		if exportResp.ResumeSpan == nil {
+			break
+		}
+		span.Key = exportResp.ResumeSpan.Key
+	}
+	return res, nil
+}
+
+// matchTuples returns an expression that is true if the values of the given
+// expressions are equal to one of the given tuples. NULL values are matched
+// with IS NOT DISTINCT FROM.
+func matchTuples(exprs tree.Exprs, tuples []tree.Datums) tree.Expr {
+	var inList tree.Exprs
+	var res tree.Expr
+	for _, t := range tuples {
+		hasNull := false
+		for _, d := range t {
+			if d == tree.DNull {
+				hasNull = true
+				break
+			}
+		}
+		if !hasNull {
+			if len(exprs) == 1 {
+				inList = append(inList, t[0])
+			} else {
+				inList = append(inList, &tree.Tuple{Exprs: datumsToExprs(t)})
+			}
+			continue
+		}
+		var match tree.Expr
+		for i := range exprs {
+			match = andExprs(match, &tree.ComparisonExpr{
+				Operator: treecmp.MakeComparisonOperator(treecmp.IsNotDistinctFrom),
+				Left:     exprs[i],
+				Right:    t[i],
+			})
+		}
+		res = orExprs(res, match)
+	}
+	if len(inList) > 0 {
+		left := exprs[0]
+		if len(exprs) > 1 {
+			left = &tree.Tuple{Exprs: exprs}
+		}
+		res = orExprs(&tree.ComparisonExpr{
+			Operator: treecmp.MakeComparisonOperator(treecmp.In),
+			Left:     left,
+			Right:    &tree.Tuple{Exprs: inList},
+		}, res)
+	}
+	return res
+}
+
+func datumsToExprs(datums tree.Datums) tree.Exprs {
+	res := make(tree.Exprs, len(datums))
+	for i := range datums {
+		res[i] = datums[i]
+	}
+	return res
+}
+
+// andExprs returns the conjunction of the given expressions, either of which
+// may be nil.
+func andExprs(left, right tree.Expr) tree.Expr {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	return &tree.AndExpr{Left: left, Right: right}
+}
+
+// orExprs returns the disjunction of the given expressions, either of which
+// may be nil.
+func orExprs(left, right tree.Expr) tree.Expr {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	return &tree.OrExpr{Left: left, Right: right}
+}
+
+// whereExpr returns the expression of the given WHERE or HAVING clause, which
+// may be nil.
+func whereExpr(w *tree.Where) tree.Expr {
+	if w == nil {
+		return nil
+	}
+	return w.Expr
+}
@@ -0,0 +1,489 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsn"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsnutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/pgoutput"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/pgrepltree"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+// Logical replication slots are stored as protected timestamp records, which
+// keep the MVCC history of the slot's database from being garbage collected
+// past the slot's confirmed flush position. The record's timestamp is the
+// position up to which the client confirmed having received the changes, and
+// the record's metadata identifies the slot. Since the record is not tied to a
+// job or a session, it is never reconciled: like in Postgres, a slot holds back
+// garbage collection until it is dropped.
+
+// replicationSlotMetaType is the meta type of the protected timestamp records
+// that store replication slots.
+const replicationSlotMetaType = "pg_replication_slot"
+
+// maxReplicationSlotNameLength is the maximum length of a replication slot
+// name, as in Postgres.
+const maxReplicationSlotNameLength = 63
+
+// replicationSlotMeta is the metadata of the protected timestamp record that
+// stores a replication slot.
+type replicationSlotMeta struct {
+	Name       string    `json:"name"`
+	Plugin     string    `json:"plugin"`
+	DatabaseID descpb.ID `json:"database_id"`
+}
+
+// replicationSlot is a logical replication slot.
+type replicationSlot struct {
+	replicationSlotMeta
+	recordID uuid.UUID
+	// confirmedFlush is the timestamp up to which the client confirmed having
+	// received the changes.
+	confirmedFlush hlc.Timestamp
+}
+
+// confirmedFlushLSN is the position up to which the client confirmed having
+// received the changes.
+func (s *replicationSlot) confirmedFlushLSN() lsn.LSN {
+	return lsnutil.HLCToLSN(s.confirmedFlush)
+}
+
+// listReplicationSlots returns the replication slots of the cluster, ordered by
+// name.
+func listReplicationSlots(
+	ctx context.Context, txn isql.Txn, pts protectedts.Manager,
+) ([]replicationSlot, error) {
+	state, err := pts.WithTxn(txn).GetState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var slots []replicationSlot
+	for i := range state.Records {
+		rec := &state.Records[i]
+		if rec.MetaType != replicationSlotMetaType {
+			continue
+		}
+		slot := replicationSlot{confirmedFlush: rec.Timestamp}
+		if err := json.Unmarshal(rec.Meta, &slot.replicationSlotMeta); err != nil {
+			return nil, errors.Wrapf(err, "decoding replication slot record %s", rec.ID)
+		}
+		slot.recordID = rec.ID.GetUUID()
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Name < slots[j].Name })
+	return slots, nil
+}
+
+// getReplicationSlot returns the replication slot with the given name, or nil
+// if it doesn't exist.
+func getReplicationSlot(
+	ctx context.Context, txn isql.Txn, pts protectedts.Manager, name string,
+) (*replicationSlot, error) {
+	slots, err := listReplicationSlots(ctx, txn, pts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range slots {
+		if slots[i].Name == name {
+			return &slots[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func errReplicationSlotDoesNotExist(name string) error {
+	return pgerror.Newf(pgcode.UndefinedObject, "replication slot %q does not exist", name)
+}
+
+func errReplicationSlotActive(name string, pid uint32) error {
+	return pgerror.Newf(pgcode.ObjectInUse, "replication slot %q is active for PID %d", name, pid)
+}
+
+// checkReplicationSlotName checks that the name of a replication slot only
+// contains the characters allowed by Postgres.
+func checkReplicationSlotName(name string) error {
+	if name == "" {
+		return pgerror.Newf(pgcode.InvalidName, "replication slot name %q is too short", name)
+	}
+	if len(name) > maxReplicationSlotNameLength {
+		return pgerror.Newf(pgcode.NameTooLong, "replication slot name %q is too long", name)
+	}
+	for _, c := range name {
+		if !((c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '_') {
+			return pgerror.WithCandidateCode(
+				errors.WithHint(
+					errors.Newf("replication slot name %q contains invalid character", name),
+					"Replication slot names may only contain lower case letters, numbers, and the underscore character.",
+				),
+				pgcode.InvalidName,
+			)
+		}
+	}
+	return nil
+}
+
+// checkLogicalReplicationConnection checks that the session is a replication
+// connection to a database, which is required to use logical replication.
+func (p *planner) checkLogicalReplicationConnection() error {
+	if p.SessionData().ReplicationMode != sessiondatapb.ReplicationMode_REPLICATION_MODE_DATABASE {
+		return pgerror.New(pgcode.ObjectNotInPrerequisiteState,
+			"logical decoding requires a database connection")
+	}
+	if p.CurrentDatabase() == "" {
+		return pgerror.New(pgcode.UndefinedDatabase,
+			"logical decoding requires a database connection")
+	}
+	return nil
+}
+
+// ReplicationSlotRegistry tracks the replication slots from which the sessions
+// on this node are streaming changes. A slot can only be used by one session at
+// a time.
+type ReplicationSlotRegistry struct {
+	mu struct {
+		syncutil.Mutex
+		// active maps the name of the slots in use to the PID of the session
+		// that uses them.
+		active map[string]uint32
+	}
+}
+
+// NewReplicationSlotRegistry creates a new ReplicationSlotRegistry with no
+// active slots.
+func NewReplicationSlotRegistry() *ReplicationSlotRegistry {
+	r := &ReplicationSlotRegistry{}
+	r.mu.active = make(map[string]uint32)
+	return r
+}
+
+// acquire marks the slot as used by the session with the given PID. It fails if
+// the slot is already in use.
+func (r *ReplicationSlotRegistry) acquire(name string, pid uint32) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if activePID, ok := r.mu.active[name]; ok {
+		return errReplicationSlotActive(name, activePID)
+	}
+	r.mu.active[name] = pid
+	return nil
+}
+
+// release marks the slot as no longer in use.
+func (r *ReplicationSlotRegistry) release(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.mu.active, name)
+}
+
+// activePID returns the PID of the session that uses the slot, if any.
+func (r *ReplicationSlotRegistry) activePID(name string) (uint32, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	pid, ok := r.mu.active[name]
+	return pid, ok
+}
+
+type createReplicationSlotNode struct {
+	optColumnsSlot
+	name            string
+	consistentPoint lsn.LSN
+	shown           bool
+}
+
+func (n *createReplicationSlotNode) startExec(params runParams) error {
+	return nil
+}
+
+func (n *createReplicationSlotNode) Next(params runParams) (bool, error) {
+	if n.shown {
+		return false, nil
+	}
+	n.shown = true
+	return true, nil
+}
+
+func (n *createReplicationSlotNode) Values() tree.Datums {
+	return tree.Datums{
+		tree.NewDString(n.name),
+		tree.NewDString(n.consistentPoint.String()),
+		tree.DNull, // snapshot_name
+		tree.NewDString(pgoutput.PluginName),
+	}
+}
+
+func (n *createReplicationSlotNode) Close(ctx context.Context) {}
+
+// CreateReplicationSlot creates a logical replication slot. Changes committed
+// after the slot's consistent point can later be streamed from it with
+// START_REPLICATION.
+func (p *planner) CreateReplicationSlot(
+	ctx context.Context, n *pgrepltree.CreateReplicationSlot,
+) (planNode, error) {
+	name := string(n.Slot)
+	if err := checkReplicationSlotName(name); err != nil {
+		return nil, err
+	}
+	if n.Kind == pgrepltree.PhysicalReplication {
+		return nil, unimplemented.New("physical replication slots",
+			"physical replication slots are not supported")
+	}
+	if n.Temporary {
+		return nil, unimplemented.New("temporary replication slots",
+			"temporary replication slots are not supported")
+	}
+	if n.Plugin != pgoutput.PluginName {
+		return nil, pgerror.WithCandidateCode(
+			errors.WithHint(
+				errors.Newf("output plugin %q is not supported", n.Plugin),
+				"The pgoutput plugin is the only supported output plugin.",
+			),
+			pgcode.FeatureNotSupported,
+		)
+	}
+	for _, o := range n.Options {
+		switch o.Key {
+		case "snapshot":
+			// Exporting a snapshot is accepted, but no snapshot name is returned:
+			// the initial data can be read with AS OF SYSTEM TIME at the consistent
+			// point instead.
+			switch v := replicationOptionString(o); v {
+			case "export", "nothing":
+			case "use":
+				return nil, unimplemented.New("replication slot snapshot use",
+					"using the replication slot's snapshot in the current transaction is not supported")
+			default:
+				return nil, pgerror.Newf(pgcode.Syntax,
+					"unrecognized value for CREATE_REPLICATION_SLOT option %q: %q", o.Key, v)
+			}
+		default:
+			return nil, pgerror.Newf(pgcode.Syntax, "unrecognized option: %s", o.Key)
+		}
+	}
+	if err := p.checkLogicalReplicationConnection(); err != nil {
+		return nil, err
+	}
+	db, err := p.Descriptors().ByNameWithLeased(p.txn).Get().Database(ctx, p.CurrentDatabase())
+	if err != nil {
+		return nil, err
+	}
+
+	pts := p.ExecCfg().ProtectedTimestampProvider
+	existing, err := getReplicationSlot(ctx, p.InternalSQLTxn(), pts, name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, pgerror.Newf(pgcode.DuplicateObject, "replication slot %q already exists", name)
+	}
+	meta, err := json.Marshal(replicationSlotMeta{
+		Name:       name,
+		Plugin:     pgoutput.PluginName,
+		DatabaseID: db.GetID(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	ts := p.Txn().ReadTimestamp()
+	recordID := uuid.MakeV4()
+	if err := pts.WithTxn(p.InternalSQLTxn()).Protect(ctx, &ptpb.Record{
+		ID:        recordID.GetBytesMut(),
+		Timestamp: ts,
+		Mode:      ptpb.PROTECT_AFTER,
+		MetaType:  replicationSlotMetaType,
+		Meta:      meta,
+		Target:    ptpb.MakeSchemaObjectsTarget(descpb.IDs{db.GetID()}),
+	}); err != nil {
+		return nil, err
+	}
+	return &createReplicationSlotNode{
+		name:            name,
+		consistentPoint: lsnutil.HLCToLSN(ts),
+	}, nil
+}
+
+// replicationOptionString returns the value of a replication command option as
+// a string.
+func replicationOptionString(o pgrepltree.Option) string {
+	switch v := o.Value.(type) {
+	case nil:
+		return ""
+	case *tree.StrVal:
+		return v.RawString()
+	case *tree.NumVal:
+		return v.String()
+	default:
+		return tree.AsStringWithFlags(v, tree.FmtBareStrings)
+	}
+}
+
+// replicationSlotDropPollInterval is how often DROP_REPLICATION_SLOT ... WAIT
+// checks whether the slot is still in use.
+var replicationSlotDropPollInterval = 100 * time.Millisecond
+
+type dropReplicationSlotNode struct {
+	name string
+	wait bool
+}
+
+func (n *dropReplicationSlotNode) startExec(params runParams) error {
+	registry := params.ExecCfg().ReplicationSlotRegistry
+	for {
+		pid, active := registry.activePID(n.name)
+		if !active {
+			break
+		}
+		if !n.wait {
+			return errReplicationSlotActive(n.name, pid)
+		}
+		select {
+		case <-params.ctx.Done():
+			return params.ctx.Err()
+		case <-time.After(replicationSlotDropPollInterval):
+		}
+	}
+	pts := params.ExecCfg().ProtectedTimestampProvider
+	slot, err := getReplicationSlot(params.ctx, params.p.InternalSQLTxn(), pts, n.name)
+	if err != nil {
+		return err
+	}
+	if slot == nil {
+		return errReplicationSlotDoesNotExist(n.name)
+	}
+	return pts.WithTxn(params.p.InternalSQLTxn()).Release(params.ctx, slot.recordID)
+}
+
+func (n *dropReplicationSlotNode) Next(_ runParams) (bool, error) { return false, nil }
+func (n *dropReplicationSlotNode) Values() tree.Datums            { return nil }
+func (n *dropReplicationSlotNode) Close(_ context.Context)        {}
+
+// DropReplicationSlot drops a replication slot, which allows the history that
+// it retained to be garbage collected.
+func (p *planner) DropReplicationSlot(
+	ctx context.Context, n *pgrepltree.DropReplicationSlot,
+) (planNode, error) {
+	return &dropReplicationSlotNode{name: string(n.Slot), wait: n.Wait}, nil
+}
+
+type readReplicationSlotNode struct {
+	optColumnsSlot
+	shown bool
+}
+
+func (n *readReplicationSlotNode) startExec(params runParams) error {
+	return nil
+}
+
+func (n *readReplicationSlotNode) Next(params runParams) (bool, error) {
+	if n.shown {
+		return false, nil
+	}
+	n.shown = true
+	return true, nil
+}
+
+func (n *readReplicationSlotNode) Values() tree.Datums {
+	return tree.Datums{tree.DNull, tree.DNull, tree.DNull}
+}
+
+func (n *readReplicationSlotNode) Close(ctx context.Context) {}
+
+// ReadReplicationSlot reads the information of a physical replication slot.
+// Since only logical replication slots are supported, it only returns NULLs
+// for slots that don't exist.
+func (p *planner) ReadReplicationSlot(
+	ctx context.Context, n *pgrepltree.ReadReplicationSlot,
+) (planNode, error) {
+	slot, err := getReplicationSlot(
+		ctx, p.InternalSQLTxn(), p.ExecCfg().ProtectedTimestampProvider, string(n.Slot),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if slot != nil {
+		return nil, pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"cannot use READ_REPLICATION_SLOT with a logical replication slot")
+	}
+	return &readReplicationSlotNode{}, nil
+}
+
+// advanceReplicationSlot records that the client confirmed having received the
+// changes of the slot up to the given timestamp, which allows the history
+// before it to be garbage collected.
+func advanceReplicationSlot(
+	ctx context.Context, execCfg *ExecutorConfig, slot *replicationSlot, ts hlc.Timestamp,
+) error {
+	if ts.LessEq(slot.confirmedFlush) {
+		return nil
+	}
+	if err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		return execCfg.ProtectedTimestampProvider.WithTxn(txn).UpdateTimestamp(ctx, slot.recordID, ts)
+	}); err != nil {
+		if errors.Is(err, protectedts.ErrNotExists) {
+			return errors.Wrapf(errReplicationSlotDoesNotExist(slot.Name), "advancing replication slot")
+		}
+		return err
+	}
+	slot.confirmedFlush = ts
+	return nil
+}
+
+// replicationSlotAdvanceInterval is the minimum interval between two updates
+// of the confirmed flush position of a slot while changes are streamed from it.
+var replicationSlotAdvanceInterval = 10 * time.Second
+
+// replicationSlotAdvancer throttles the updates of the confirmed flush position
+// of a slot.
+type replicationSlotAdvancer struct {
+	execCfg *ExecutorConfig
+	slot    *replicationSlot
+	// pending is the latest position confirmed by the client.
+	pending    hlc.Timestamp
+	lastUpdate time.Time
+}
+
+// confirm records that the client confirmed the changes up to the given
+// timestamp. The slot is updated if enough time has passed since the last
+// update.
+func (a *replicationSlotAdvancer) confirm(ctx context.Context, ts hlc.Timestamp) error {
+	a.pending.Forward(ts)
+	if timeutil.Since(a.lastUpdate) < replicationSlotAdvanceInterval {
+		return nil
+	}
+	return a.flush(ctx)
+}
+
+// flush updates the slot with the latest confirmed position.
+func (a *replicationSlotAdvancer) flush(ctx context.Context) error {
+	if err := advanceReplicationSlot(ctx, a.execCfg, a.slot, a.pending); err != nil {
+		return err
+	}
+	a.lastUpdate = timeutil.Now()
+	return nil
+}
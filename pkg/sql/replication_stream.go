@@ -0,0 +1,777 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangefeed"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsn"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsnutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/pgoutput"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/pgrepltree"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxlog"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/fsm"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// replicationKeepaliveInterval is the interval at which keepalive messages are
+// sent to the client while changes are streamed, which lets the client confirm
+// its progress even if no changes are committed.
+var replicationKeepaliveInterval = 10 * time.Second
+
+// replicationStreamBufferSize limits the memory used by the changes that a
+// replication stream receives from the rangefeed before it can send them.
+var replicationStreamBufferSize = settings.RegisterByteSizeSetting(
+	settings.ApplicationLevel,
+	"sql.replication.stream_buffer_size",
+	"maximum size of the changes that a logical replication stream buffers until "+
+		"the rangefeed's frontier passes them; the stream fails past it",
+	64<<20,
+)
+
+// execStartReplication streams the changes of a logical replication slot to
+// the client, until the client ends the streaming with a CopyDone message.
+func (ex *connExecutor) execStartReplication(
+	ctx context.Context, cmd StartReplication, res ReplicationResult,
+) (fsm.Event, fsm.EventPayload) {
+	defer cmd.Feedback.close()
+	if _, isNoTxn := ex.machine.CurState().(stateNoTxn); !isNoTxn {
+		return ex.makeErrEvent(pgerror.New(pgcode.ActiveSQLTransaction,
+			"START_REPLICATION cannot be executed inside a transaction block"), cmd.ParsedStmt.AST)
+	}
+
+	var cancelQuery context.CancelFunc
+	ctx, cancelQuery = ctxlog.WithCancel(ctx)
+	queryID := ex.server.cfg.GenerateID()
+	ex.addActiveQuery(cmd.ParsedStmt, nil /* placeholders */, queryID, cancelQuery)
+	defer func() {
+		ex.removeActiveQuery(queryID, cmd.Stmt)
+		cancelQuery()
+	}()
+
+	if err := ex.startReplication(ctx, cmd, res); err != nil {
+		log.SqlExec.Warningf(ctx, "error executing %s: %v", cmd, err)
+		return eventNonRetriableErr{IsCommit: fsm.False}, eventNonRetriableErrPayload{err: err}
+	}
+	return nil, nil
+}
+
+func (ex *connExecutor) startReplication(
+	ctx context.Context, cmd StartReplication, res ReplicationResult,
+) error {
+	if cmd.Stmt.Kind == pgrepltree.PhysicalReplication {
+		return unimplemented.New("physical replication",
+			"physical replication is not supported")
+	}
+	if err := ex.planner.checkLogicalReplicationConnection(); err != nil {
+		return err
+	}
+	pubNames, err := checkReplicationOptions(cmd.Stmt.Options)
+	if err != nil {
+		return err
+	}
+	execCfg := ex.server.cfg
+
+	name := string(cmd.Stmt.Slot)
+	var slot *replicationSlot
+	var tables []catalog.TableDescriptor
+	if err := execCfg.InternalDB.DescsTxn(ctx, func(
+		ctx context.Context, txn descs.Txn,
+	) (err error) {
+		slot, err = getReplicationSlot(ctx, txn, execCfg.ProtectedTimestampProvider, name)
+		if err != nil {
+			return err
+		}
+		if slot == nil {
+			return errReplicationSlotDoesNotExist(name)
+		}
+		db, err := txn.Descriptors().ByNameWithLeased(txn.KV()).Get().Database(ctx, ex.planner.CurrentDatabase())
+		if err != nil {
+			return err
+		}
+		if db.GetID() != slot.DatabaseID {
+			return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"replication slot %q was not created in this database", name)
+		}
+		if len(pubNames) == 0 {
+			return pgerror.New(pgcode.InvalidParameterValue, "publication_names parameter missing")
+		}
+		allTables, tableIDs, err := resolvePublications(db, pubNames)
+		if err != nil {
+			return err
+		}
+		all, err := txn.Descriptors().GetAllTablesInDatabase(ctx, txn.KV(), db)
+		if err != nil {
+			return err
+		}
+		tables = tables[:0]
+		return all.ForEachDescriptor(func(desc catalog.Descriptor) error {
+			tbl, ok := desc.(catalog.TableDescriptor)
+			if !ok || !tbl.IsPhysicalTable() || tbl.IsSequence() || !tbl.Public() {
+				return nil
+			}
+			if _, ok := tableIDs[tbl.GetID()]; !ok && !allTables {
+				return nil
+			}
+			if err := checkReplicatedTable(tbl); err != nil {
+				return err
+			}
+			tables = append(tables, tbl)
+			return nil
+		})
+	}); err != nil {
+		return err
+	}
+
+	if !rangefeedsEnabled(execCfg) {
+		return pgerror.New(pgcode.ObjectNotInPrerequisiteState,
+			"logical replication requires the kv.rangefeed.enabled setting")
+	}
+
+	if err := execCfg.ReplicationSlotRegistry.acquire(name, ex.queryCancelKey.GetPGBackendPID()); err != nil {
+		return err
+	}
+	defer execCfg.ReplicationSlotRegistry.release(name)
+
+	startLSN := cmd.Stmt.LSN
+	if confirmed := slot.confirmedFlushLSN(); startLSN < confirmed {
+		startLSN = confirmed
+	}
+	bufferMon := mon.NewMonitorInheritWithLimit(
+		"replication-stream", replicationStreamBufferSize.Get(&execCfg.Settings.SV), ex.sessionMon,
+	)
+	bufferMon.StartNoReserved(ctx, ex.sessionMon)
+	defer bufferMon.Stop(ctx)
+	s := &replicationStream{
+		execCfg:  execCfg,
+		sd:       ex.sessionData(),
+		res:      res,
+		feedback: cmd.Feedback,
+		advancer: replicationSlotAdvancer{
+			execCfg:    execCfg,
+			slot:       slot,
+			lastUpdate: timeutil.Now(),
+		},
+		startLSN:  startLSN,
+		endLSN:    startLSN,
+		decoders:  make(map[replicatedTableVersion]*replicatedTableDecoder),
+		relations: make(map[descpb.ID]descpb.DescriptorVersion),
+		frontierC: make(chan struct{}, 1),
+		errC:      make(chan error, 1),
+	}
+	s.mu.acc = bufferMon.MakeBoundAccount()
+	defer func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.mu.acc.Close(ctx)
+	}()
+	return s.run(ctx, tables)
+}
+
+// checkReplicationOptions checks the options of START_REPLICATION, which are
+// the options of the pgoutput plugin, and returns the names of the
+// publications whose tables are streamed.
+func checkReplicationOptions(opts pgrepltree.Options) (pubNames []string, _ error) {
+	for _, o := range opts {
+		v := replicationOptionString(o)
+		switch o.Key {
+		case "proto_version":
+			if v != fmt.Sprint(pgoutput.ProtoVersion) {
+				return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+					"client sent proto_version=%s but server only supports protocol %d",
+					v, pgoutput.ProtoVersion)
+			}
+		case "publication_names":
+			var err error
+			if pubNames, err = parsePublicationNames(v); err != nil {
+				return nil, err
+			}
+		case "binary", "streaming", "two_phase":
+			switch v {
+			case "false", "off", "0":
+			default:
+				return nil, unimplemented.Newf("pgoutput "+string(o.Key),
+					"pgoutput option %s is not supported", o.Key)
+			}
+		case "messages", "origin":
+			// Logical decoding messages and replication origins are never emitted,
+			// so these options have no effect.
+		default:
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"unrecognized pgoutput option: %s", o.Key)
+		}
+	}
+	return pubNames, nil
+}
+
+// parsePublicationNames parses the comma-separated list of publication names
+// of the publication_names option. As in Postgres, the names are identifiers:
+// they are lowercased unless they are double-quoted.
+func parsePublicationNames(v string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(v, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) >= 2 && name[0] == '"' && name[len(name)-1] == '"' {
+			name = strings.ReplaceAll(name[1:len(name)-1], `""`, `"`)
+		} else {
+			name = strings.ToLower(name)
+		}
+		if name == "" {
+			return nil, pgerror.New(pgcode.InvalidName, "invalid publication_names syntax")
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// resolvePublications returns the tables of the given publications of a
+// database. allTables is set if one of the publications is for all tables.
+func resolvePublications(
+	db catalog.DatabaseDescriptor, pubNames []string,
+) (allTables bool, tableIDs map[descpb.ID]struct{}, _ error) {
+	tableIDs = make(map[descpb.ID]struct{})
+	for _, name := range pubNames {
+		pub := db.GetPublication(name)
+		if pub == nil {
+			return false, nil, errPublicationDoesNotExist(name)
+		}
+		allTables = allTables || pub.AllTables
+		for _, id := range pub.TableIDs {
+			tableIDs[id] = struct{}{}
+		}
+	}
+	return allTables, tableIDs, nil
+}
+
+// checkReplicatedTable checks that the changes of a table can be streamed.
+func checkReplicatedTable(tbl catalog.TableDescriptor) error {
+	if len(tbl.GetFamilies()) > 1 {
+		return unimplemented.Newf("replication multiple column families",
+			"logical replication of table %q with multiple column families is not supported",
+			tbl.GetName())
+	}
+	return nil
+}
+
+// rangefeedsEnabled returns whether the kv.rangefeed.enabled setting is set.
+// The setting is defined in the kvserver package, so it is looked up by name.
+func rangefeedsEnabled(execCfg *ExecutorConfig) bool {
+	s, ok, _ := settings.LookupForLocalAccess("kv.rangefeed.enabled", execCfg.Codec.ForSystemTenant())
+	if !ok {
+		return true
+	}
+	b, ok := s.(*settings.BoolSetting)
+	return !ok || b.Get(&execCfg.Settings.SV)
+}
+
+// replicationStream streams the changes of the published tables, read from
+// a rangefeed, to the client. The changes committed at the same timestamp are
+// sent as a single transaction once the rangefeed's frontier passes their
+// timestamp, i.e. once all the changes at that timestamp are known.
+//
+// Rangefeeds do not expose the transactions that wrote the changes, so a
+// transaction of the stream is the set of changes committed at the same LSN,
+// i.e. at the same timestamp unless the timestamps only differ past the
+// precision of LSNs. It may combine several transactions that committed at the
+// same timestamp. Such transactions do not conflict, so applying them together
+// is equivalent to applying them in any order. A transaction cannot be split:
+// the position of a change is the commit LSN of its transaction, so the
+// client could not confirm that it received only some of the changes at that
+// position.
+type replicationStream struct {
+	execCfg  *ExecutorConfig
+	sd       *sessiondata.SessionData
+	res      ReplicationResult
+	feedback *ReplicationFeedback
+	advancer replicationSlotAdvancer
+
+	// startLSN is the position from which changes are streamed. The changes at
+	// or before it were already confirmed by the client.
+	startLSN lsn.LSN
+	// endLSN is the position up to which all the changes were sent.
+	endLSN lsn.LSN
+	// emitted is the timestamp up to which all the changes were sent.
+	emitted hlc.Timestamp
+	// xid is the identifier of the last transaction sent. Since the
+	// transactions of the stream are not the transactions of the cluster,
+	// they are numbered in the order in which they are sent on the
+	// connection; the same changes may be sent with another xid after a
+	// reconnection. Clients only use the xid of a transaction to match its
+	// messages.
+	xid uint32
+
+	decoders map[replicatedTableVersion]*replicatedTableDecoder
+	// relations maps the tables whose description was sent to the client to the
+	// version of their descriptor at the time.
+	relations map[descpb.ID]descpb.DescriptorVersion
+
+	mu struct {
+		syncutil.Mutex
+		// events are the values received from the rangefeed that were not sent
+		// yet. Their memory is accounted for in acc.
+		events []*kvpb.RangeFeedValue
+		acc    mon.BoundAccount
+		// failed is set once the events exceeded the memory budget, after
+		// which no more events are buffered.
+		failed bool
+		// frontier is the rangefeed's resolved timestamp.
+		frontier hlc.Timestamp
+	}
+	// frontierC is signaled when the rangefeed's frontier advances.
+	frontierC chan struct{}
+	// errC receives the unrecoverable error of the rangefeed.
+	errC chan error
+
+	buf []byte
+}
+
+func (s *replicationStream) run(ctx context.Context, tables []catalog.TableDescriptor) error {
+	spans := make([]roachpb.Span, 0, len(tables))
+	for _, tbl := range tables {
+		spans = append(spans, tbl.TableSpan(s.execCfg.Codec))
+	}
+	s.emitted = lsnutil.LSNToHLC(s.startLSN)
+	if len(spans) > 0 {
+		rf, err := s.execCfg.RangeFeedFactory.RangeFeed(ctx, "pg-replication-slot", spans, s.emitted,
+			func(ctx context.Context, value *kvpb.RangeFeedValue) {
+				s.mu.Lock()
+				defer s.mu.Unlock()
+				if s.mu.failed {
+					return
+				}
+				// The rangefeed cannot be paused until the events are sent,
+				// since it delivers the frontier advances that let them be sent
+				// on the same goroutine, so the stream fails instead.
+				if err := s.mu.acc.Grow(ctx, replicationEventSize(value)); err != nil {
+					s.mu.failed = true
+					s.sendErr(errors.WithHintf(err,
+						"increase %s, or consume the changes of fewer tables",
+						replicationStreamBufferSize.Name()))
+					return
+				}
+				s.mu.events = append(s.mu.events, value)
+			},
+			rangefeed.WithDiff(true),
+			rangefeed.WithOnFrontierAdvance(func(ctx context.Context, ts hlc.Timestamp) {
+				s.mu.Lock()
+				s.mu.frontier.Forward(ts)
+				s.mu.Unlock()
+				select {
+				case s.frontierC <- struct{}{}:
+				default:
+				}
+			}),
+			rangefeed.WithOnInternalError(func(ctx context.Context, err error) {
+				s.sendErr(err)
+			}),
+		)
+		if err != nil {
+			return err
+		}
+		defer rf.Close()
+	}
+
+	if err := s.res.SendCopyBoth(ctx); err != nil {
+		return err
+	}
+	keepalive := time.NewTicker(replicationKeepaliveInterval)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-s.frontierC:
+			if err := s.emit(ctx); err != nil {
+				return err
+			}
+		case msg := <-s.feedback.msgs:
+			if msg.copyDone {
+				if err := s.advancer.flush(ctx); err != nil {
+					return err
+				}
+				return s.res.SendCopyDone(ctx)
+			}
+			if err := s.handleClientMessage(ctx, msg.copyData); err != nil {
+				return err
+			}
+		case <-keepalive.C:
+			if err := s.sendKeepalive(ctx, false /* replyRequested */); err != nil {
+				return err
+			}
+		case err := <-s.errC:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// sendErr makes the stream fail with the given error, unless it already
+// received another one.
+func (s *replicationStream) sendErr(err error) {
+	select {
+	case s.errC <- err:
+	default:
+	}
+}
+
+// replicationEventSize returns the memory used by a buffered event.
+func replicationEventSize(ev *kvpb.RangeFeedValue) int64 {
+	return int64(unsafe.Sizeof(*ev)) + int64(len(ev.Key)) +
+		int64(len(ev.Value.RawBytes)) + int64(len(ev.PrevValue.RawBytes))
+}
+
+// handleClientMessage handles a message sent by the client.
+func (s *replicationStream) handleClientMessage(ctx context.Context, data []byte) error {
+	u, ok, err := pgoutput.ParseClientMessage(data)
+	if err != nil {
+		return pgerror.WithCandidateCode(err, pgcode.ProtocolViolation)
+	}
+	if !ok {
+		return nil
+	}
+	flushed := u.FlushedLSN
+	if flushed > s.endLSN {
+		flushed = s.endLSN
+	}
+	if flushed > s.startLSN {
+		if err := s.advancer.confirm(ctx, lsnutil.LSNToHLC(flushed)); err != nil {
+			return err
+		}
+	}
+	if u.ReplyRequested {
+		return s.sendKeepalive(ctx, false /* replyRequested */)
+	}
+	return nil
+}
+
+// emit sends the changes at or before the rangefeed's frontier to the client.
+func (s *replicationStream) emit(ctx context.Context) error {
+	s.mu.Lock()
+	frontier := s.mu.frontier
+	var ready []*kvpb.RangeFeedValue
+	var readySize int64
+	remaining := s.mu.events[:0]
+	for _, ev := range s.mu.events {
+		if ev.Timestamp().LessEq(frontier) {
+			ready = append(ready, ev)
+			readySize += replicationEventSize(ev)
+		} else {
+			remaining = append(remaining, ev)
+		}
+	}
+	s.mu.events = remaining
+	s.mu.Unlock()
+	// The ready events remain accounted for until they are sent.
+	defer func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.mu.acc.Shrink(ctx, readySize)
+	}()
+
+	// The changes to a key are received in timestamp order, which the stable
+	// sort preserves.
+	sort.SliceStable(ready, func(i, j int) bool {
+		return ready[i].Timestamp().Less(ready[j].Timestamp())
+	})
+	// The changes at the same LSN form a transaction, see replicationStream.
+	for len(ready) > 0 {
+		txnLSN := lsnutil.HLCToLSN(ready[0].Timestamp())
+		n := 1
+		for n < len(ready) && lsnutil.HLCToLSN(ready[n].Timestamp()) == txnLSN {
+			n++
+		}
+		txn := ready[:n]
+		ready = ready[n:]
+		// Skip the changes that were already sent, either before the slot was
+		// last confirmed or by the rangefeed retrying.
+		if txnLSN <= s.startLSN || txn[0].Timestamp().LessEq(s.emitted) {
+			continue
+		}
+		if err := s.emitTxn(ctx, txnLSN, txn); err != nil {
+			return err
+		}
+	}
+	if s.emitted.Less(frontier) {
+		s.emitted = frontier
+	}
+	if frontierLSN := lsnutil.HLCToLSN(frontier); s.endLSN < frontierLSN {
+		s.endLSN = frontierLSN
+	}
+	return s.sendKeepalive(ctx, false /* replyRequested */)
+}
+
+// emitTxn sends the changes committed at the given position as a single
+// transaction.
+func (s *replicationStream) emitTxn(
+	ctx context.Context, txnLSN lsn.LSN, events []*kvpb.RangeFeedValue,
+) error {
+	commitTime := events[0].Timestamp().GoTime()
+	begun := false
+	for _, ev := range events {
+		d, ok, err := s.decoderFor(ctx, ev.Key, ev.Timestamp())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		msg, ok, err := d.decode(ctx, ev, s.sd)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if !begun {
+			s.xid++
+			if err := s.send(ctx, txnLSN, func(buf []byte) []byte {
+				return pgoutput.AppendBegin(buf, txnLSN, commitTime, s.xid)
+			}); err != nil {
+				return err
+			}
+			begun = true
+		}
+		if v, ok := s.relations[descpb.ID(d.rel.ID)]; !ok || v != d.version {
+			if err := s.send(ctx, txnLSN, func(buf []byte) []byte {
+				return pgoutput.AppendRelation(buf, &d.rel)
+			}); err != nil {
+				return err
+			}
+			s.relations[descpb.ID(d.rel.ID)] = d.version
+		}
+		if err := s.send(ctx, txnLSN, msg); err != nil {
+			return err
+		}
+	}
+	if !begun {
+		return nil
+	}
+	if s.endLSN < txnLSN {
+		s.endLSN = txnLSN
+	}
+	if err := s.send(ctx, txnLSN, func(buf []byte) []byte {
+		return pgoutput.AppendCommit(buf, txnLSN, txnLSN, commitTime)
+	}); err != nil {
+		return err
+	}
+	return s.res.Flush(ctx)
+}
+
+// send sends a logical replication message at the given position, wrapped in
+// a XLogData message.
+func (s *replicationStream) send(
+	ctx context.Context, at lsn.LSN, appendMsg func(buf []byte) []byte,
+) error {
+	end := s.endLSN
+	if end < at {
+		end = at
+	}
+	s.buf = pgoutput.AppendXLogData(s.buf[:0], at, end, timeutil.Now())
+	s.buf = appendMsg(s.buf)
+	return s.res.SendCopyData(ctx, s.buf, false /* isHeader */)
+}
+
+func (s *replicationStream) sendKeepalive(ctx context.Context, replyRequested bool) error {
+	s.buf = pgoutput.AppendKeepalive(s.buf[:0], s.endLSN, timeutil.Now(), replyRequested)
+	if err := s.res.SendCopyData(ctx, s.buf, true /* isHeader */); err != nil {
+		return err
+	}
+	return s.res.Flush(ctx)
+}
+
+// replicatedTableVersion identifies a version of a table descriptor.
+type replicatedTableVersion struct {
+	id      descpb.ID
+	version descpb.DescriptorVersion
+}
+
+// decoderFor returns the decoder for the table of the given key, as of the
+// given timestamp. ok is false if the key does not belong to the primary index
+// of a table.
+func (s *replicationStream) decoderFor(
+	ctx context.Context, key roachpb.Key, ts hlc.Timestamp,
+) (_ *replicatedTableDecoder, ok bool, _ error) {
+	_, tableID, indexID, err := s.execCfg.Codec.DecodeIndexPrefix(key)
+	if err != nil {
+		return nil, false, err
+	}
+	leased, err := s.execCfg.LeaseManager.Acquire(ctx, ts, descpb.ID(tableID))
+	if err != nil {
+		return nil, false, err
+	}
+	version := leased.Underlying().GetVersion()
+	// Only the version is needed: the descriptor is read below at the exact
+	// timestamp of the change.
+	leased.Release(ctx)
+
+	k := replicatedTableVersion{id: descpb.ID(tableID), version: version}
+	d, ok := s.decoders[k]
+	if !ok {
+		if d, err = s.newDecoder(ctx, k.id, ts); err != nil {
+			return nil, false, err
+		}
+		s.decoders[k] = d
+	}
+	return d, descpb.IndexID(indexID) == d.primaryIndexID, nil
+}
+
+func (s *replicationStream) newDecoder(
+	ctx context.Context, id descpb.ID, ts hlc.Timestamp,
+) (*replicatedTableDecoder, error) {
+	var tbl catalog.TableDescriptor
+	var schemaName string
+	if err := s.execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		if err := txn.KV().SetFixedTimestamp(ctx, ts); err != nil {
+			return err
+		}
+		var err error
+		tbl, err = txn.Descriptors().ByID(txn.KV()).WithoutNonPublic().Get().Table(ctx, id)
+		if err != nil {
+			return err
+		}
+		sc, err := txn.Descriptors().ByID(txn.KV()).Get().Schema(ctx, tbl.GetParentSchemaID())
+		if err != nil {
+			return err
+		}
+		schemaName = sc.GetName()
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := checkReplicatedTable(tbl); err != nil {
+		return nil, err
+	}
+
+	d := &replicatedTableDecoder{
+		version:        tbl.GetVersion(),
+		primaryIndexID: tbl.GetPrimaryIndexID(),
+	}
+	d.rel.ID = uint32(tbl.GetID())
+	d.rel.Namespace = schemaName
+	d.rel.Name = tbl.GetName()
+	keyCols := tbl.GetPrimaryIndex().CollectKeyColumnIDs()
+	cols := tbl.PublicColumns()
+	colIDs := make(descpb.ColumnIDs, 0, len(cols))
+	for _, col := range cols {
+		// Hidden columns, such as the implicit primary key, and virtual columns
+		// are not part of the relation, like generated columns in Postgres.
+		if col.IsHidden() || col.IsVirtual() {
+			continue
+		}
+		colIDs = append(colIDs, col.GetID())
+		d.rel.Columns = append(d.rel.Columns, pgoutput.Column{
+			Name:    col.GetName(),
+			TypeOID: col.GetType().Oid(),
+			TypeMod: col.GetType().TypeModifier(),
+			Key:     keyCols.Contains(col.GetID()),
+		})
+	}
+	var spec fetchpb.IndexFetchSpec
+	if err := rowenc.InitIndexFetchSpec(
+		&spec, s.execCfg.Codec, tbl, tbl.GetPrimaryIndex(), colIDs,
+	); err != nil {
+		return nil, err
+	}
+	if err := d.fetcher.Init(ctx, row.FetcherInitArgs{
+		WillUseKVProvider: true,
+		Alloc:             &d.alloc,
+		Spec:              &spec,
+	}); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// replicatedTableDecoder decodes the changes to the rows of a version of a
+// table.
+type replicatedTableDecoder struct {
+	rel            pgoutput.Relation
+	version        descpb.DescriptorVersion
+	primaryIndexID descpb.IndexID
+	fetcher        row.Fetcher
+	alloc          tree.DatumAlloc
+	kvs            row.KVProvider
+	row            []pgoutput.Value
+}
+
+// decode decodes a change and returns a function that appends the
+// corresponding Insert, Update or Delete message. ok is false if the change
+// does not need to be sent, i.e. if a row that did not exist was deleted.
+func (d *replicatedTableDecoder) decode(
+	ctx context.Context, ev *kvpb.RangeFeedValue, sd *sessiondata.SessionData,
+) (_ func(buf []byte) []byte, ok bool, _ error) {
+	d.kvs.KVs = append(d.kvs.KVs[:0], roachpb.KeyValue{Key: ev.Key, Value: ev.Value})
+	if err := d.fetcher.ConsumeKVProvider(ctx, &d.kvs); err != nil {
+		return nil, false, err
+	}
+	datums, err := d.fetcher.NextRowDecoded(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if datums == nil {
+		return nil, false, errors.AssertionFailedf("unexpected empty row")
+	}
+	deleted := d.fetcher.RowIsDeleted()
+	existed := ev.PrevValue.IsPresent()
+	if deleted && !existed {
+		return nil, false, nil
+	}
+
+	fmtCtx := tree.NewFmtCtx(
+		tree.FmtPgwireText,
+		tree.FmtDataConversionConfig(sd.DataConversionConfig),
+		tree.FmtLocation(sd.GetLocation()),
+	)
+	defer fmtCtx.Close()
+	d.row = d.row[:0]
+	for i, datum := range datums {
+		if datum == tree.DNull || (deleted && !d.rel.Columns[i].Key) {
+			d.row = append(d.row, pgoutput.Value{Null: true})
+			continue
+		}
+		fmtCtx.Reset()
+		fmtCtx.FormatNode(datum)
+		d.row = append(d.row, pgoutput.Value{Text: []byte(fmtCtx.String())})
+	}
+	relID, vals := d.rel.ID, d.row
+	switch {
+	case deleted:
+		return func(buf []byte) []byte { return pgoutput.AppendDelete(buf, relID, vals) }, true, nil
+	case existed:
+		return func(buf []byte) []byte { return pgoutput.AppendUpdate(buf, relID, vals) }, true, nil
+	default:
+		return func(buf []byte) []byte { return pgoutput.AppendInsert(buf, relID, vals) }, true, nil
+	}
+}
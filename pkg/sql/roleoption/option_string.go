@@ -36,6 +36,8 @@ func _() {
 	_ = x[NOSQLLOGIN-26]
 	_ = x[VIEWCLUSTERSETTING-27]
 	_ = x[NOVIEWCLUSTERSETTING-28]
+	_ = x[BYPASSRLS-29]
+	_ = x[NOBYPASSRLS-30]
 }
 
 func (i Option) String() string {
@@ -96,6 +98,10 @@ func (i Option) String() string {
 		return "VIEWCLUSTERSETTING"
 	case NOVIEWCLUSTERSETTING:
 		return "NOVIEWCLUSTERSETTING"
+	case BYPASSRLS:
+		return "BYPASSRLS"
+	case NOBYPASSRLS:
+		return "NOBYPASSRLS"
 	default:
 		return "Option(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
@@ -72,6 +72,10 @@ const (
 	NOSQLLOGIN
 	VIEWCLUSTERSETTING
 	NOVIEWCLUSTERSETTING
+	// BYPASSRLS allows a role to bypass the row-level security policies of
+	// all tables.
+	BYPASSRLS
+	NOBYPASSRLS
 )
 
 // ControlChangefeedDeprecationNoticeMsg is a user friendly notice which should be shown when CONTROLCHANGEFEED is used
@@ -111,6 +115,8 @@ var toSQLStmts = map[Option]string{
 	NOVIEWACTIVITYREDACTED: `DELETE FROM system.role_options WHERE username = $1 AND user_id = $2 AND option = 'VIEWACTIVITYREDACTED'`,
 	VIEWCLUSTERSETTING:     `INSERT INTO system.role_options (username, option, user_id) VALUES ($1, 'VIEWCLUSTERSETTING', $2) ON CONFLICT DO NOTHING`,
 	NOVIEWCLUSTERSETTING:   `DELETE FROM system.role_options WHERE username = $1 AND user_id = $2 AND option = 'VIEWCLUSTERSETTING'`,
+	BYPASSRLS:              `INSERT INTO system.role_options (username, option, user_id) VALUES ($1, 'BYPASSRLS', $2) ON CONFLICT DO NOTHING`,
+	NOBYPASSRLS:            `DELETE FROM system.role_options WHERE username = $1 AND user_id = $2 AND option = 'BYPASSRLS'`,
 }
 
 // Mask returns the bitmask for a given role option.
@@ -148,6 +154,8 @@ var ByName = map[string]Option{
 	"NOSQLLOGIN":             NOSQLLOGIN,
 	"VIEWCLUSTERSETTING":     VIEWCLUSTERSETTING,
 	"NOVIEWCLUSTERSETTING":   NOVIEWCLUSTERSETTING,
+	"BYPASSRLS":              BYPASSRLS,
+	"NOBYPASSRLS":            NOBYPASSRLS,
 }
 
 // ToOption takes a string and returns the corresponding Option.
@@ -297,7 +305,9 @@ func (rol List) CheckRoleOptionConflicts() error {
 		(roleOptionBits&VIEWCLUSTERSETTING.Mask() != 0 &&
 			roleOptionBits&NOVIEWCLUSTERSETTING.Mask() != 0) ||
 		(roleOptionBits&REPLICATION.Mask() != 0 &&
-			roleOptionBits&NOREPLICATION.Mask() != 0) {
+			roleOptionBits&NOREPLICATION.Mask() != 0) ||
+		(roleOptionBits&BYPASSRLS.Mask() != 0 &&
+			roleOptionBits&NOBYPASSRLS.Mask() != 0) {
 		return pgerror.Newf(pgcode.Syntax, "conflicting role options")
 	}
 	return nil
@@ -31,23 +31,52 @@ import (
 // A callNode executes a procedure.
 type callNode struct {
 	proc *tree.RoutineExpr
+
+	// columns are the result columns of the procedure. A procedure with OUT or
+	// INOUT parameters returns a single row with a column for each output
+	// parameter. Other procedures return no results.
+	columns colinfo.ResultColumns
+
+	// row is the single result row of a procedure with output parameters.
+	row tree.Datums
+	// done is set once row has been returned.
+	done bool
 }
 
 var _ planNode = &callNode{}
 
 // startExec implements the planNode interface.
 func (d *callNode) startExec(params runParams) error {
-	// Until OUT and INOUT parameters are supported, all procedures return no
-	// results, so we can ignore the results of the routine.
-	_, err := eval.Expr(params.ctx, params.EvalContext(), d.proc)
-	return err
+	res, err := eval.Expr(params.ctx, params.EvalContext(), d.proc)
+	if err != nil || len(d.columns) == 0 {
+		return err
+	}
+	d.row = make(tree.Datums, len(d.columns))
+	if res == tree.DNull {
+		for i := range d.row {
+			d.row[i] = tree.DNull
+		}
+		return nil
+	}
+	tup, ok := tree.AsDTuple(res)
+	if !ok || len(tup.D) != len(d.row) {
+		return errors.AssertionFailedf("expected tuple of %d values from procedure, found %s", len(d.row), res)
+	}
+	copy(d.row, tup.D)
+	return nil
 }
 
 // Next implements the planNode interface.
-func (d *callNode) Next(params runParams) (bool, error) { return false, nil }
+func (d *callNode) Next(params runParams) (bool, error) {
+	if d.row == nil || d.done {
+		return false, nil
+	}
+	d.done = true
+	return true, nil
+}
 
 // Values implements the planNode interface.
-func (d *callNode) Values() tree.Datums { return nil }
+func (d *callNode) Values() tree.Datums { return d.row }
 
 // Close implements the planNode interface.
 func (d *callNode) Close(ctx context.Context) {}
@@ -244,10 +273,9 @@ func (g *routineGenerator) startInternal(ctx context.Context, txn *kv.Txn) (err
 
 		var w rowResultWriter
 		openCursor := stmtIdx == 1 && g.expr.CursorDeclaration != nil
-		if isFinalPlan && !g.expr.Procedure {
-			// The result of this statement is the routine's output. This is never the
-			// case for a procedure, which does not output any rows (since we do not
-			// yet support OUT or INOUT parameters).
+		if isFinalPlan && (!g.expr.Procedure || g.expr.ResolvedType().Family() == types.TupleFamily) {
+			// The result of this statement is the routine's output. A procedure only
+			// outputs a row if it has OUT or INOUT parameters.
 			w = rrw
 		} else if openCursor {
 			// The result of the first statement will be used to open a SQL cursor.
@@ -470,6 +498,7 @@ func (g *routineGenerator) newCursorHelper(plan *planComponents) (*plpgsqlCursor
 	cursorHelper := &plpgsqlCursorHelper{
 		ctx:        context.Background(),
 		cursorName: cursorName,
+		scrollable: open.Scroll == tree.Scroll,
 		resultCols: make(colinfo.ResultColumns, len(planCols)),
 	}
 	copy(cursorHelper.resultCols, planCols)
@@ -492,6 +521,7 @@ type plpgsqlCursorHelper struct {
 	cursorName  tree.Name
 	cursorSql   string
 	addedCursor bool
+	scrollable  bool
 
 	// Fields related to implementing the isql.Rows interface.
 	container    rowContainerHelper
@@ -509,12 +539,26 @@ func (h *plpgsqlCursorHelper) createCursor(p *planner, blockState *tree.BlockSta
 		txn:            p.txn,
 		statement:      h.cursorSql,
 		created:        timeutil.Now(),
+		scrollable:     h.scrollable,
 		eagerExecution: true,
 	}
 	if err := p.checkIfCursorExists(h.cursorName); err != nil {
 		return err
 	}
+	if cursor.scrollable {
+		// Move the rows into a buffer that supports random access.
+		monitor, err := p.scrollCursorMonitor()
+		if err != nil {
+			return err
+		}
+		if err := cursor.materialize(h.ctx, p.ExtendedEvalContext(), monitor); err != nil {
+			return err
+		}
+	}
 	if err := p.sqlCursors.addCursor(h.cursorName, cursor); err != nil {
+		if cursor.buf != nil {
+			_ = cursor.Close()
+		}
 		return err
 	}
 	if blockState != nil {
@@ -337,6 +337,9 @@ type FetcherInitArgs struct {
 	// row is being processed. In practice, this means that span IDs must be
 	// passed in when SpansCanOverlap is true.
 	SpansCanOverlap bool
+	// Sampler, if sampling, determines which rows are returned by the fetcher
+	// (TABLESAMPLE BERNOULLI). The KVs of all other rows are skipped.
+	Sampler rowinfra.KeySampler
 }
 
 // Init sets up a Fetcher for a given table and index.
@@ -1122,6 +1125,11 @@ func (rf *Fetcher) processValueBytes(
 //
 // When there are no more rows, the EncDatumRow is nil.
 func (rf *Fetcher) NextRow(ctx context.Context) (row rowenc.EncDatumRow, spanID int, err error) {
+	if rf.args.Sampler.Sampling() {
+		if err := rf.skipUnsampledRows(ctx); err != nil {
+			return nil, 0, err
+		}
+	}
 	if rf.kvEnd {
 		return nil, 0, nil
 	}
@@ -1158,6 +1166,25 @@ func (rf *Fetcher) NextRow(ctx context.Context) (row rowenc.EncDatumRow, spanID
 	}
 }
 
+// skipUnsampledRows skips the KVs of all rows that are not selected by the
+// sampler, until the current KV belongs to a selected row or there are no more
+// KVs. It must only be called when the current KV is the first KV of a row.
+func (rf *Fetcher) skipUnsampledRows(ctx context.Context) error {
+	for !rf.kvEnd && !rf.args.Sampler.Keep(rf.kv.Key[:len(rf.kv.Key)-len(rf.keyRemainingBytes)]) {
+		for {
+			newRow, spanID, err := rf.nextKey(ctx)
+			if err != nil {
+				return err
+			}
+			if newRow {
+				rf.spanID = spanID
+				break
+			}
+		}
+	}
+	return nil
+}
+
 // NextRowInto calls NextRow and copies the results into the given EncDatumRow
 // slice according to the given column map.
 //
@@ -322,7 +322,7 @@ func (ed *EncDatum) Fingerprint(
 	var err error
 	memUsageBefore := ed.Size()
 	switch typ.Family() {
-	case types.JsonFamily, types.TSVectorFamily:
+	case types.JsonFamily, types.TSVectorFamily, types.RangeFamily, types.MultirangeFamily:
 		if err = ed.EnsureDecoded(typ, a); err != nil {
 			return nil, err
 		}
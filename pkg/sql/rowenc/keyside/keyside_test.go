@@ -240,7 +240,8 @@ func hasKeyEncoding(typ *types.T) bool {
 	// Only some types are round-trip key encodable.
 	switch typ.Family() {
 	case types.CollatedStringFamily, types.TupleFamily, types.DecimalFamily,
-		types.GeographyFamily, types.GeometryFamily, types.TSVectorFamily, types.TSQueryFamily:
+		types.GeographyFamily, types.GeometryFamily, types.TSVectorFamily, types.TSQueryFamily,
+		types.JsonpathFamily, types.RangeFamily, types.MultirangeFamily:
 		return false
 	case types.ArrayFamily:
 		return hasKeyEncoding(typ.ArrayContents())
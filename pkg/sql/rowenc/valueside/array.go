@@ -317,6 +317,8 @@ func encodeArrayElement(b []byte, d tree.Datum) ([]byte, error) {
 		return encoding.EncodeUntaggedBytesValue(b, encoded), nil
 	case *tree.DTuple:
 		return encodeUntaggedTuple(t, b, encoding.NoColumnID, nil)
+	case *tree.DJsonpath:
+		return encoding.EncodeUntaggedBytesValue(b, []byte(t.Jsonpath.String())), nil
 	case *tree.DTSQuery:
 		encoded := tsearch.EncodeTSQueryPGBinary(nil, t.TSQuery)
 		return encoding.EncodeUntaggedBytesValue(b, encoded), nil
@@ -202,6 +202,36 @@ func DecodeUntaggedDatum(
 			return nil, b, err
 		}
 		return a.NewDJSON(tree.DJSON{JSON: j}), b, nil
+	case types.JsonpathFamily:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		d, err := tree.ParseDJsonpath(string(data))
+		if err != nil {
+			return nil, b, err
+		}
+		return d, b, nil
+	case types.RangeFamily:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		d, err := decodeRange(a, t, data)
+		if err != nil {
+			return nil, b, err
+		}
+		return d, b, nil
+	case types.MultirangeFamily:
+		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
+		if err != nil {
+			return nil, b, err
+		}
+		d, err := decodeMultirange(a, t, data)
+		if err != nil {
+			return nil, b, err
+		}
+		return d, b, nil
 	case types.TSQueryFamily:
 		b, data, err := encoding.DecodeUntaggedBytesValue(buf)
 		if err != nil {
@@ -83,6 +83,20 @@ func Encode(appendTo []byte, colID ColumnIDDelta, val tree.Datum, scratch []byte
 			return nil, err
 		}
 		return encoding.EncodeJSONValue(appendTo, uint32(colID), encoded), nil
+	case *tree.DJsonpath:
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), []byte(t.Jsonpath.String())), nil
+	case *tree.DRange:
+		encoded, err := encodeRange(scratch, t)
+		if err != nil {
+			return nil, err
+		}
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), encoded), nil
+	case *tree.DMultirange:
+		encoded, err := encodeMultirange(scratch, t)
+		if err != nil {
+			return nil, err
+		}
+		return encoding.EncodeBytesValue(appendTo, uint32(colID), encoded), nil
 	case *tree.DTSQuery:
 		encoded, err := tsearch.EncodeTSQuery(scratch, t.TSQuery)
 		if err != nil {
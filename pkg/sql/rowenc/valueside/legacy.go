@@ -153,6 +153,29 @@ func MarshalLegacy(colType *types.T, val tree.Datum) (roachpb.Value, error) {
 			r.SetBytes(data)
 			return r, nil
 		}
+	case types.JsonpathFamily:
+		if v, ok := val.(*tree.DJsonpath); ok {
+			r.SetString(v.Jsonpath.String())
+			return r, nil
+		}
+	case types.RangeFamily:
+		if v, ok := val.(*tree.DRange); ok {
+			data, err := encodeRange(nil, v)
+			if err != nil {
+				return r, err
+			}
+			r.SetBytes(data)
+			return r, nil
+		}
+	case types.MultirangeFamily:
+		if v, ok := val.(*tree.DMultirange); ok {
+			data, err := encodeMultirange(nil, v)
+			if err != nil {
+				return r, err
+			}
+			r.SetBytes(data)
+			return r, nil
+		}
 	case types.TSQueryFamily:
 		if v, ok := val.(*tree.DTSQuery); ok {
 			data := tsearch.EncodeTSQueryPGBinary(nil, v.TSQuery)
@@ -402,6 +425,24 @@ func UnmarshalLegacy(a *tree.DatumAlloc, typ *types.T, value roachpb.Value) (tre
 			return nil, err
 		}
 		return tree.NewDJSON(jsonDatum), nil
+	case types.JsonpathFamily:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		return tree.ParseDJsonpath(string(v))
+	case types.RangeFamily:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		return decodeRange(a, typ, v)
+	case types.MultirangeFamily:
+		v, err := value.GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		return decodeMultirange(a, typ, v)
 	case types.TSQueryFamily:
 		v, err := value.GetBytes()
 		if err != nil {
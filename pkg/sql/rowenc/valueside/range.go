@@ -0,0 +1,101 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package valueside
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// encodeRange produces the contents of the value encoding for a range: a byte
+// with the range's flags, followed by the value encoding of each finite bound.
+func encodeRange(appendTo []byte, r *tree.DRange) ([]byte, error) {
+	flags := r.Flags()
+	appendTo = append(appendTo, flags)
+	var err error
+	if flags&(tree.RangeFlagEmpty|tree.RangeFlagLowerInf) == 0 {
+		if appendTo, err = Encode(appendTo, NoColumnID, r.Lower, nil /* scratch */); err != nil {
+			return nil, err
+		}
+	}
+	if flags&(tree.RangeFlagEmpty|tree.RangeFlagUpperInf) == 0 {
+		if appendTo, err = Encode(appendTo, NoColumnID, r.Upper, nil /* scratch */); err != nil {
+			return nil, err
+		}
+	}
+	return appendTo, nil
+}
+
+// decodeRange decodes a range from the contents of its value encoding. It is
+// the counterpart of encodeRange().
+func decodeRange(a *tree.DatumAlloc, typ *types.T, b []byte) (*tree.DRange, error) {
+	if len(b) == 0 {
+		return nil, errors.AssertionFailedf("missing range flags")
+	}
+	flags := b[0]
+	b = b[1:]
+	if flags&tree.RangeFlagEmpty != 0 {
+		return tree.NewDEmptyRange(typ), nil
+	}
+	var lower, upper tree.Datum
+	var err error
+	if flags&tree.RangeFlagLowerInf == 0 {
+		if lower, b, err = Decode(a, typ.RangeContents(), b); err != nil {
+			return nil, err
+		}
+	}
+	if flags&tree.RangeFlagUpperInf == 0 {
+		if upper, _, err = Decode(a, typ.RangeContents(), b); err != nil {
+			return nil, err
+		}
+	}
+	return tree.NewDRange(
+		typ, lower, upper, flags&tree.RangeFlagLowerInc != 0, flags&tree.RangeFlagUpperInc != 0,
+	)
+}
+
+// encodeMultirange produces the contents of the value encoding for a
+// multirange: the number of ranges, followed by the encoding of each range as
+// a length-prefixed byte string.
+func encodeMultirange(appendTo []byte, m *tree.DMultirange) ([]byte, error) {
+	appendTo = encoding.EncodeNonsortingUvarint(appendTo, uint64(len(m.Ranges)))
+	for _, r := range m.Ranges {
+		encoded, err := encodeRange(nil, r)
+		if err != nil {
+			return nil, err
+		}
+		appendTo = encoding.EncodeUntaggedBytesValue(appendTo, encoded)
+	}
+	return appendTo, nil
+}
+
+// decodeMultirange decodes a multirange from the contents of its value
+// encoding. It is the counterpart of encodeMultirange().
+func decodeMultirange(a *tree.DatumAlloc, typ *types.T, b []byte) (*tree.DMultirange, error) {
+	b, _, n, err := encoding.DecodeNonsortingUvarint(b)
+	if err != nil {
+		return nil, err
+	}
+	rangeTyp := types.MultirangeRangeType(typ)
+	ranges := make([]*tree.DRange, n)
+	for i := range ranges {
+		var data []byte
+		if b, data, err = encoding.DecodeUntaggedBytesValue(b); err != nil {
+			return nil, err
+		}
+		if ranges[i], err = decodeRange(a, rangeTyp, data); err != nil {
+			return nil, err
+		}
+	}
+	return tree.NewDMultirange(typ, ranges)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/cancelchecker"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/optional"
@@ -75,6 +76,12 @@ type aggregatorBase struct {
 	orderedGroupCols []uint32
 	aggregations     []execinfrapb.AggregatorSpec_Aggregation
 
+	// groupingSets is set if the aggregator computes the grouping sets of a
+	// GROUPING SETS, ROLLUP or CUBE clause. groupingSets[i][j] is true if
+	// groupCols[j] is part of the i-th grouping set. Only the hashAggregator
+	// supports grouping sets.
+	groupingSets [][]bool
+
 	lastOrdGroupCols rowenc.EncDatumRow
 	arena            stringarena.Arena
 	row              rowenc.EncDatumRow
@@ -146,6 +153,30 @@ func (ag *aggregatorBase) init(
 		}
 		ag.outputTypes[i] = outputType
 	}
+	if len(spec.GroupingSets) > 0 {
+		if len(spec.OrderedGroupCols) > 0 || ag.isScalar {
+			return errors.AssertionFailedf("grouping sets require a non-scalar unordered aggregation")
+		}
+		ag.groupingSets = make([][]bool, len(spec.GroupingSets))
+		for i, set := range spec.GroupingSets {
+			ag.groupingSets[i] = make([]bool, len(ag.groupCols))
+			for _, col := range set.Cols {
+				found := false
+				for j, groupCol := range ag.groupCols {
+					if groupCol == col {
+						ag.groupingSets[i][j] = true
+						found = true
+					}
+				}
+				if !found {
+					return errors.AssertionFailedf("grouping set column %d is not a grouping column", col)
+				}
+			}
+		}
+		// The ordinal of the grouping set is output after the aggregations.
+		ag.outputTypes = append(ag.outputTypes, types.Int)
+		ag.row = make(rowenc.EncDatumRow, len(ag.outputTypes))
+	}
 
 	return ag.ProcessorBase.Init(
 		ctx, self, post, ag.outputTypes, flowCtx, processorID, memMonitor,
@@ -214,6 +245,10 @@ type hashAggregator struct {
 	// alreadyAccountedFor tracks the number of items in 'buckets' memory for
 	// which we have already accounted for.
 	alreadyAccountedFor int
+
+	// groupingSetRow is a scratch row used to aggregate an input row for one
+	// of the grouping sets.
+	groupingSetRow rowenc.EncDatumRow
 }
 
 // orderedAggregator is a specialization of aggregatorBase that only needs to
@@ -261,7 +296,7 @@ func newAggregator(
 	if spec.IsRowCount() {
 		return newCountAggregator(ctx, flowCtx, processorID, input, post)
 	}
-	if len(spec.OrderedGroupCols) == len(spec.GroupCols) {
+	if len(spec.OrderedGroupCols) == len(spec.GroupCols) && len(spec.GroupingSets) == 0 {
 		return newOrderedAggregator(ctx, flowCtx, processorID, spec, input, post)
 	}
 	return newHashAggregator(ctx, flowCtx, processorID, spec, input, post)
@@ -296,6 +331,10 @@ func newHashAggregator(
 		return nil, err
 	}
 
+	if ag.groupingSets != nil {
+		ag.groupingSetRow = make(rowenc.EncDatumRow, len(ag.inputTypes))
+	}
+
 	// A new tree.EvalCtx was created during initializing aggregatorBase above
 	// and will be used only by this aggregator, so it is ok to update EvalCtx
 	// directly.
@@ -454,9 +493,15 @@ func (ag *hashAggregator) accumulateRows() (
 		}
 	}
 
-	// Queries like `SELECT MAX(n) FROM t` expect a row of NULLs if nothing was
-	// aggregated.
-	if len(ag.buckets) < 1 && len(ag.groupCols) == 0 {
+	if ag.groupingSets != nil {
+		// Empty grouping sets produce a row even if nothing was aggregated.
+		if err := ag.addEmptyGroupingSets(); err != nil {
+			ag.MoveToDraining(err)
+			return aggStateUnknown, nil, nil
+		}
+	} else if len(ag.buckets) < 1 && len(ag.groupCols) == 0 {
+		// Queries like `SELECT MAX(n) FROM t` expect a row of NULLs if nothing
+		// was aggregated.
 		bucket, err := ag.createAggregateFuncs()
 		if err != nil {
 			ag.MoveToDraining(err)
@@ -638,6 +683,15 @@ func (ag *hashAggregator) emitRow() (
 	// limit. However, we might be under accounting memory usage in other
 	// places, so having some over accounting here might be actually beneficial
 	// as a defensive mechanism against OOM crashes.
+	if ag.groupingSets != nil {
+		// The bucket key starts with the ordinal of the grouping set.
+		_, setIdx, err := encoding.DecodeUvarintAscending([]byte(bucket))
+		if err != nil {
+			ag.MoveToDraining(err)
+			return aggStateUnknown, nil, nil
+		}
+		ag.row[len(ag.row)-1] = rowenc.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(setIdx)))
+	}
 	state, row, meta := ag.getAggResults(ag.buckets[bucket])
 	delete(ag.buckets, bucket)
 	return state, row, meta
@@ -837,6 +891,10 @@ func (ag *hashAggregator) accumulateRow(row rowenc.EncDatumRow) error {
 		return err
 	}
 
+	if ag.groupingSets != nil {
+		return ag.accumulateRowForGroupingSets(row)
+	}
+
 	// The encoding computed here determines which bucket the non-grouping
 	// datums are accumulated to.
 	encoded, err := ag.encode(ag.scratch, row)
@@ -845,28 +903,110 @@ func (ag *hashAggregator) accumulateRow(row rowenc.EncDatumRow) error {
 	}
 	ag.scratch = encoded[:0]
 
-	bucket, ok := ag.buckets[string(encoded)]
-	if !ok {
-		s, err := ag.arena.AllocBytes(ag.Ctx(), encoded)
+	bucket, err := ag.getBucket(encoded)
+	if err != nil {
+		return err
+	}
+	return ag.accumulateRowIntoBucket(row, encoded, bucket)
+}
+
+// accumulateRowForGroupingSets accumulates a single row once for each of the
+// grouping sets. The grouping columns that are not part of a set are replaced
+// by NULL, and the bucket key is prefixed with the ordinal of the set.
+func (ag *hashAggregator) accumulateRowForGroupingSets(row rowenc.EncDatumRow) error {
+	// Decode the arguments of the aggregations up front so that they are
+	// decoded only once rather than once per grouping set.
+	for _, a := range ag.aggregations {
+		if a.FilterColIdx != nil {
+			col := *a.FilterColIdx
+			if err := row[col].EnsureDecoded(ag.inputTypes[col], &ag.datumAlloc); err != nil {
+				return err
+			}
+		}
+		for _, col := range a.ColIdx {
+			if err := row[col].EnsureDecoded(ag.inputTypes[col], &ag.datumAlloc); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, inSet := range ag.groupingSets {
+		copy(ag.groupingSetRow, row)
+		for j, col := range ag.groupCols {
+			if !inSet[j] {
+				ag.groupingSetRow[col] = rowenc.EncDatum{Datum: tree.DNull}
+			}
+		}
+		encoded := encoding.EncodeUvarintAscending(ag.scratch, uint64(i))
+		encoded, err := ag.encode(encoded, ag.groupingSetRow)
 		if err != nil {
 			return err
 		}
-		bucket, err = ag.createAggregateFuncs()
+		ag.scratch = encoded[:0]
+
+		bucket, err := ag.getBucket(encoded)
 		if err != nil {
 			return err
 		}
-		ag.buckets[s] = bucket
-		if len(ag.buckets) == ag.bucketsLenGrowThreshold {
-			toAccountFor := ag.bucketsLenGrowThreshold - ag.alreadyAccountedFor
-			if err := ag.bucketsAcc.Grow(ag.Ctx(), int64(toAccountFor)*memsize.MapEntryOverhead); err != nil {
-				return err
-			}
-			ag.alreadyAccountedFor = ag.bucketsLenGrowThreshold
-			ag.bucketsLenGrowThreshold *= 2
+		if err := ag.accumulateRowIntoBucket(ag.groupingSetRow, encoded, bucket); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	return ag.accumulateRowIntoBucket(row, encoded, bucket)
+// addEmptyGroupingSets creates the buckets of the empty grouping sets that
+// have not been created by any input row.
+func (ag *hashAggregator) addEmptyGroupingSets() error {
+	for i := range ag.groupingSetRow {
+		ag.groupingSetRow[i] = rowenc.EncDatum{Datum: tree.DNull}
+	}
+	for i, inSet := range ag.groupingSets {
+		empty := true
+		for _, b := range inSet {
+			empty = empty && !b
+		}
+		if !empty {
+			continue
+		}
+		encoded := encoding.EncodeUvarintAscending(ag.scratch, uint64(i))
+		encoded, err := ag.encode(encoded, ag.groupingSetRow)
+		if err != nil {
+			return err
+		}
+		ag.scratch = encoded[:0]
+		if _, err := ag.getBucket(encoded); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getBucket returns the bucket with the given key, creating it if it doesn't
+// exist yet.
+func (ag *hashAggregator) getBucket(encoded []byte) (aggregateFuncs, error) {
+	bucket, ok := ag.buckets[string(encoded)]
+	if ok {
+		return bucket, nil
+	}
+	s, err := ag.arena.AllocBytes(ag.Ctx(), encoded)
+	if err != nil {
+		return nil, err
+	}
+	bucket, err = ag.createAggregateFuncs()
+	if err != nil {
+		return nil, err
+	}
+	ag.buckets[s] = bucket
+	if len(ag.buckets) == ag.bucketsLenGrowThreshold {
+		toAccountFor := ag.bucketsLenGrowThreshold - ag.alreadyAccountedFor
+		if err := ag.bucketsAcc.Grow(ag.Ctx(), int64(toAccountFor)*memsize.MapEntryOverhead); err != nil {
+			return nil, err
+		}
+		ag.alreadyAccountedFor = ag.bucketsLenGrowThreshold
+		ag.bucketsLenGrowThreshold *= 2
+	}
+	return bucket, nil
 }
 
 // accumulateRow accumulates a single row, returning an error if accumulation
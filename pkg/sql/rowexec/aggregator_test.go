@@ -391,6 +391,77 @@ func TestAggregator(t *testing.T) {
 				},
 			},
 		},
+		{
+			// SELECT @1, @2, sum(@3) GROUP BY ROLLUP (@1, @2).
+			Name: "SumGroupByRollup",
+			Input: ProcessorTestCaseRows{
+				Rows: [][]interface{}{
+					{1, 1, 1},
+					{1, 2, 2},
+					{1, 2, 3},
+					{2, 1, 4},
+				},
+				Types: types.MakeIntCols(3),
+			},
+			Output: ProcessorTestCaseRows{
+				Rows: [][]interface{}{
+					{1, 1, 1, 0},
+					{1, 2, 5, 0},
+					{2, 1, 4, 0},
+					{1, nil, 6, 1},
+					{2, nil, 4, 1},
+					{nil, nil, 10, 2},
+				},
+				Types: []*types.T{types.Int, types.Int, types.Decimal, types.Int},
+			},
+			ProcessorCore: execinfrapb.ProcessorCoreUnion{
+				Aggregator: &execinfrapb.AggregatorSpec{
+					Type:      execinfrapb.AggregatorSpec_NON_SCALAR,
+					GroupCols: []uint32{0, 1},
+					GroupingSets: []execinfrapb.AggregatorSpec_GroupingSet{
+						{Cols: []uint32{0, 1}},
+						{Cols: []uint32{0}},
+						{},
+					},
+					Aggregations: aggregations([]aggTestSpec{
+						{fname: "ANY_NOT_NULL", colIdx: col0},
+						{fname: "ANY_NOT_NULL", colIdx: col1},
+						{fname: "SUM", colIdx: col2},
+					}),
+				},
+			},
+		},
+		{
+			// SELECT @1, count(@2) GROUP BY GROUPING SETS ((@1), (), ()) (no
+			// rows).
+			Name: "CountGroupByGroupingSetsNoRows",
+			Input: ProcessorTestCaseRows{
+				Rows:  [][]interface{}{},
+				Types: types.MakeIntCols(2),
+			},
+			Output: ProcessorTestCaseRows{
+				Rows: [][]interface{}{
+					{nil, 0, 1},
+					{nil, 0, 2},
+				},
+				Types: types.MakeIntCols(3),
+			},
+			ProcessorCore: execinfrapb.ProcessorCoreUnion{
+				Aggregator: &execinfrapb.AggregatorSpec{
+					Type:      execinfrapb.AggregatorSpec_NON_SCALAR,
+					GroupCols: col0,
+					GroupingSets: []execinfrapb.AggregatorSpec_GroupingSet{
+						{Cols: col0},
+						{},
+						{},
+					},
+					Aggregations: aggregations([]aggTestSpec{
+						{fname: "ANY_NOT_NULL", colIdx: col0},
+						{fname: "COUNT", colIdx: col1},
+					}),
+				},
+			},
+		},
 	}
 
 	ctx := context.Background()
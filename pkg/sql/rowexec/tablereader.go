@@ -145,6 +145,10 @@ func newTableReader(
 		return nil, err
 	}
 
+	var sampler rowinfra.KeySampler
+	if spec.Sample != nil {
+		sampler = rowinfra.MakeKeySampler(spec.Sample.Probability, spec.Sample.Seed)
+	}
 	var fetcher row.Fetcher
 	if err := fetcher.Init(
 		ctx,
@@ -160,6 +164,7 @@ func newTableReader(
 			Spec:                       &spec.FetchSpec,
 			TraceKV:                    flowCtx.TraceKV,
 			ForceProductionKVBatchSize: flowCtx.EvalCtx.TestingKnobs.ForceProductionValues,
+			Sampler:                    sampler,
 		},
 	); err != nil {
 		return nil, err
@@ -0,0 +1,77 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rowinfra
+
+import "math"
+
+// KeySampler deterministically selects a random subset of keys. Each key is
+// selected independently with the configured probability, based on a hash of
+// the key and the seed. It is used to implement TABLESAMPLE: BERNOULLI
+// sampling passes row keys to the fetchers' sampler, and SYSTEM sampling
+// passes range start keys to the physical planner's sampler.
+//
+// The zero value selects every key.
+type KeySampler struct {
+	// threshold is the (exclusive) upper bound of the hashes of the selected
+	// keys. It is only used if sampling is true.
+	threshold uint64
+	seed      uint64
+	// sampling is false if every key is selected.
+	sampling bool
+}
+
+// MakeKeySampler returns a KeySampler that selects each key with the given
+// probability, which must be between 0 and 1.
+func MakeKeySampler(probability float64, seed uint64) KeySampler {
+	if probability >= 1 {
+		return KeySampler{}
+	}
+	s := KeySampler{seed: seed, sampling: true}
+	if probability > 0 {
+		// The threshold is strictly less than 2^64 since probability < 1.
+		s.threshold = uint64(math.Ldexp(probability, 64))
+	}
+	return s
+}
+
+// Sampling returns true if the sampler may reject some keys.
+func (s *KeySampler) Sampling() bool {
+	return s.sampling
+}
+
+// Keep returns true if the given key is part of the sample.
+func (s *KeySampler) Keep(key []byte) bool {
+	if !s.sampling {
+		return true
+	}
+	return hashKey(key, s.seed) < s.threshold
+}
+
+// hashKey computes the 64-bit FNV-1a hash of the key combined with the seed,
+// and then applies the splitmix64 finalizer to it so that the low-entropy FNV
+// hashes of similar keys are uniformly distributed over [0, 2^64).
+func hashKey(key []byte, seed uint64) uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+	h := uint64(offset64) ^ seed
+	for _, c := range key {
+		h ^= uint64(c)
+		h *= prime64
+	}
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
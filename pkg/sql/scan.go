@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -104,6 +105,11 @@ type scanNode struct {
 	// order for this optimization to work, the DistSQL planner must create a
 	// local plan.
 	localityOptimized bool
+
+	// sample is the TABLESAMPLE clause of the scan, if any. BERNOULLI samples
+	// are applied by the fetchers, and SYSTEM samples are applied during
+	// physical planning by skipping whole ranges.
+	sample opt.TableSample
 }
 
 // scanColumnsConfig controls the "schema" of a scan node.
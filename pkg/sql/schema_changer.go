@@ -507,6 +507,11 @@ func (sc *SchemaChanger) maybeMakeAddTablePublic(
 			return nil
 		}
 		mut.State = descpb.DescriptorState_PUBLIC
+		// The data of a materialized view was backfilled as of the creation
+		// time of the view, unless it was created WITH NO DATA.
+		if mut.MaterializedView() && !mut.IsRefreshViewRequired() {
+			mut.MaterializedViewRefreshTime = mut.GetCreateAsOfTime()
+		}
 		return txn.Descriptors().WriteDesc(ctx, true /* kvTrace */, mut, txn.KV())
 	})
 }
@@ -2252,6 +2257,23 @@ func (sc *SchemaChanger) maybeDropValidatingConstraint(
 			"attempted to drop constraint %s, but it hadn't been added to the table descriptor yet",
 			constraint.GetName(),
 		)
+	} else if constraint.AsExclusion() != nil {
+		if constraint.GetConstraintValidity() == descpb.ConstraintValidity_Unvalidated {
+			return nil
+		}
+		for j, c := range desc.Exclusions {
+			if c.Name == constraint.GetName() {
+				desc.Exclusions = append(
+					desc.Exclusions[:j], desc.Exclusions[j+1:]...,
+				)
+				return nil
+			}
+		}
+		log.Infof(
+			ctx,
+			"attempted to drop constraint %s, but it hadn't been added to the table descriptor yet",
+			constraint.GetName(),
+		)
 	} else {
 		return errors.AssertionFailedf("unsupported constraint type: %s", constraint)
 	}
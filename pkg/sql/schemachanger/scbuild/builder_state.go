@@ -44,6 +44,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
 )
@@ -644,6 +645,18 @@ func (b *builderState) WrapExpression(tableID catid.DescID, expr tree.Expr) *scp
 	if expr == nil {
 		return nil
 	}
+	// The references to user-defined text search configurations, and the
+	// back-references to the table, are only maintained by the legacy schema
+	// changer.
+	if _, err := schemaexpr.ReplaceTextSearchConfigs(expr, func(config string) (string, error) {
+		if !tsearch.IsBuiltinConfig(config) {
+			panic(scerrors.NotImplementedErrorf(nil, /* n */
+				"expression using text search configuration %q", config))
+		}
+		return config, nil
+	}); err != nil {
+		panic(err)
+	}
 	// Collect type IDs.
 	var typeIDs catalog.DescriptorIDSet
 	{
@@ -972,6 +985,10 @@ func (b *builderState) ResolveUserDefinedTypeType(
 	case descpb.TypeDescriptor_COMPOSITE:
 		b.ensureDescriptor(typ.GetID())
 		b.mustOwn(typ.GetID())
+	case descpb.TypeDescriptor_DOMAIN:
+		// Domains are not yet supported by the declarative schema changer.
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"type %q (%d) is a domain", typ.GetName(), typ.GetID()))
 	case descpb.TypeDescriptor_TABLE_IMPLICIT_RECORD_TYPE:
 		// Implicit record types are not directly modifiable.
 		panic(pgerror.Newf(pgcode.DependentObjectsStillExist,
@@ -1259,7 +1276,7 @@ func (b *builderState) ResolveRoutine(
 		panic(err)
 	}
 
-	paramTypes, err := routineObj.ParamTypes(b.ctx, b.cr)
+	paramTypes, err := routineObj.ParamTypes(b.ctx, b.cr, routineType)
 	if err != nil {
 		return nil
 	}
@@ -1283,6 +1300,11 @@ func (b *builderState) ResolveRoutine(
 		)
 	}
 
+	// User-defined aggregates are handled by the legacy schema changer.
+	if ol.Class == tree.AggregateClass {
+		panic(scerrors.NotImplementedErrorf(routineObj, "user-defined aggregate"))
+	}
+
 	fnID := funcdesc.UserDefinedFunctionOIDToID(ol.Oid)
 	b.mustOwn(fnID)
 	b.ensureDescriptor(fnID)
@@ -1683,7 +1705,7 @@ func (b *builderState) serializeUserDefinedTypes(
 		if !t.UserDefined() {
 			return typ, nil
 		}
-		return &tree.OIDTypeReference{OID: t.Oid()}, nil
+		return &tree.OIDTypeReference{OID: t.UserDefinedTypeOID()}, nil
 	}
 
 	fmtCtx := tree.NewFmtCtx(tree.FmtSimple)
@@ -41,6 +41,9 @@ func alterTableAddConstraint(
 ) {
 	switch d := t.ConstraintDef.(type) {
 	case *tree.UniqueConstraintTableDef:
+		if d.Deferrable != tree.ConstraintNotDeferrable {
+			panic(scerrors.NotImplementedErrorf(t, "deferrable constraints"))
+		}
 		if d.PrimaryKey {
 			alterTableAddPrimaryKey(b, tn, tbl, t)
 		} else if d.WithoutIndex {
@@ -61,7 +64,12 @@ func alterTableAddConstraint(
 	case *tree.CheckConstraintTableDef:
 		alterTableAddCheck(b, tn, tbl, t)
 	case *tree.ForeignKeyConstraintTableDef:
+		if d.Deferrable != tree.ConstraintNotDeferrable {
+			panic(scerrors.NotImplementedErrorf(t, "deferrable constraints"))
+		}
 		alterTableAddForeignKey(b, tn, tbl, t)
+	case *tree.ExclusionConstraintTableDef:
+		panic(scerrors.NotImplementedErrorf(t, "exclusion constraints"))
 	}
 }
 
@@ -44,7 +44,7 @@ func CreateFunction(b BuildCtx, n *tree.CreateRoutine) {
 	existingFn := b.ResolveRoutine(
 		&tree.RoutineObj{
 			FuncName: n.Name,
-			Params:   n.Params,
+			Params:   n.SignatureParams(),
 		},
 		ResolveParams{
 			IsExistenceOptional: true,
@@ -60,11 +60,15 @@ func CreateFunction(b BuildCtx, n *tree.CreateRoutine) {
 		))
 	}
 
+	returnType, err := n.ResolveReturnType(b, b.SemaCtx().TypeResolver)
+	if err != nil {
+		panic(err)
+	}
 	fnID := b.GenerateUniqueDescID()
 	fn := scpb.Function{
 		FunctionID:  fnID,
 		ReturnSet:   n.ReturnType.SetOf,
-		ReturnType:  b.ResolveTypeRef(n.ReturnType.Type),
+		ReturnType:  b.ResolveTypeRef(returnType),
 		IsProcedure: n.IsProcedure,
 	}
 	fn.Params = make([]scpb.Function_Parameter, len(n.Params))
@@ -363,6 +363,20 @@ func processColNodeType(
 			}
 			invertedKind = catpb.InvertedIndexColumnKind_TRIGRAM
 			b.IncrementSchemaChangeIndexCounter("trigram_inverted")
+		case types.RangeFamily:
+			switch columnNode.OpClass {
+			case "range_ops", "":
+			default:
+				panic(newUndefinedOpclassError(columnNode.OpClass))
+			}
+			b.IncrementSchemaChangeIndexCounter("range_inverted")
+		case types.MultirangeFamily:
+			switch columnNode.OpClass {
+			case "multirange_ops", "":
+			default:
+				panic(newUndefinedOpclassError(columnNode.OpClass))
+			}
+			b.IncrementSchemaChangeIndexCounter("range_inverted")
 
 		}
 		relationElts := b.QueryByID(indexSpec.secondary.TableID)
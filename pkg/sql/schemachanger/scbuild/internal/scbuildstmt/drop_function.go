@@ -27,6 +27,10 @@ func DropFunction(b BuildCtx, n *tree.DropRoutine) {
 		panic(scerrors.NotImplementedErrorf(n, "cascade dropping functions"))
 	}
 
+	if n.Aggregate {
+		panic(scerrors.NotImplementedErrorf(n, "dropping aggregates"))
+	}
+
 	routineType := tree.UDFRoutine
 	if n.Procedure {
 		routineType = tree.ProcedureRoutine
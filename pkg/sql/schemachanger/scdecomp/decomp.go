@@ -217,6 +217,11 @@ func (w *walkCtx) walkType(typ catalog.TypeDescriptor) {
 				Name:            comp.GetElementLabel(i),
 			})
 		}
+	} else if typ.AsDomainTypeDescriptor() != nil {
+		// Domains have no corresponding elements yet, so defer to the legacy
+		// schema changer for them.
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"type %q (%d) is a domain", typ.GetName(), typ.GetID()))
 	} else {
 		panic(errors.AssertionFailedf("unsupported type kind %q", typ.GetKind()))
 	}
@@ -276,6 +281,23 @@ func GetSequenceOptions(
 }
 
 func (w *walkCtx) walkRelation(tbl catalog.TableDescriptor) {
+	// Triggers have no corresponding elements yet, so defer to the legacy
+	// schema changer for tables that have them.
+	if len(tbl.GetTriggers()) > 0 {
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"relation %q (%d) has triggers", tbl.GetName(), tbl.GetID()))
+	}
+	// The same goes for exclusion constraints.
+	if len(tbl.ExclusionConstraints()) > 0 {
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"relation %q (%d) has exclusion constraints", tbl.GetName(), tbl.GetID()))
+	}
+	// And for row-level security policies.
+	if len(tbl.GetPolicies()) > 0 || tbl.IsRowLevelSecurityEnabled() ||
+		tbl.IsRowLevelSecurityForced() {
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"relation %q (%d) uses row-level security", tbl.GetName(), tbl.GetID()))
+	}
 	switch {
 	case tbl.IsSequence():
 		w.ev(descriptorStatus(tbl), &scpb.Sequence{
@@ -856,6 +878,9 @@ func (w *walkCtx) walkFunction(fnDesc catalog.FunctionDescriptor) {
 		UsesTypeIDs: fnDesc.GetDependsOnTypes(),
 		// TODO(chengxiong): add UsesFunctionIDs when UDF usage is allowed.
 	}
+	if agg := fnDesc.FuncDesc().Aggregate; agg != nil {
+		fnBody.UsesFunctionIDs = agg.FuncIDs()
+	}
 	dedupeColIDs := func(colIDs []catid.ColumnID) []catid.ColumnID {
 		ret := catalog.MakeTableColSet()
 		for _, id := range colIDs {
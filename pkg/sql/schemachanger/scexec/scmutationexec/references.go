@@ -18,7 +18,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scop"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
-	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
 	"github.com/cockroachdb/errors"
 )
@@ -213,6 +212,22 @@ func (i *immediateVisitor) RemoveBackReferenceInTypes(
 	return updateBackReferencesInTypes(ctx, i, op.TypeIDs, op.BackReferencedDescriptorID, catalog.DescriptorIDSet{})
 }
 
+func (i *immediateVisitor) RemoveBackReferenceInFunctions(
+	ctx context.Context, op scop.RemoveBackReferenceInFunctions,
+) error {
+	for _, id := range op.FunctionIDs {
+		fn, err := i.checkOutFunction(ctx, id)
+		if err != nil {
+			return err
+		} else if fn.Dropped() {
+			// Skip updating back-references in dropped function descriptors.
+			continue
+		}
+		fn.RemoveReference(op.BackReferencedDescriptorID)
+	}
+	return nil
+}
+
 // updateBackReferencesInTypes updates back references to `backReferencedDescID`
 // in types represented by `typeIDs`, given the expected forward references from
 // the object represented by `backReferencedDescID`.
@@ -563,17 +578,7 @@ func (i *immediateVisitor) SetObjectParentID(ctx context.Context, op scop.SetObj
 		t.ParentID = sc.GetParentID()
 		t.ParentSchemaID = sc.GetID()
 
-		ol := descpb.SchemaDescriptor_FunctionSignature{
-			ID:          obj.GetID(),
-			ArgTypes:    make([]*types.T, len(t.GetParams())),
-			ReturnType:  t.GetReturnType().Type,
-			ReturnSet:   t.GetReturnType().ReturnSet,
-			IsProcedure: t.IsProcedure(),
-		}
-		for i := range t.Params {
-			ol.ArgTypes[i] = t.Params[i].Type
-		}
-		sc.AddFunction(obj.GetName(), ol)
+		sc.AddFunction(obj.GetName(), t.ToFunctionSignature())
 	}
 	return nil
 }
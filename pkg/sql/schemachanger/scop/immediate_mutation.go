@@ -530,6 +530,15 @@ type RemoveBackReferenceInTypes struct {
 	TypeIDs                    []descpb.ID
 }
 
+// RemoveBackReferenceInFunctions removes back references to a descriptor in
+// the specified functions. It is used when dropping a user-defined aggregate,
+// which references its support functions.
+type RemoveBackReferenceInFunctions struct {
+	immediateMutationOp
+	BackReferencedDescriptorID descpb.ID
+	FunctionIDs                []descpb.ID
+}
+
 // UpdateTableBackReferencesInSequences updates back references to a table expression
 // (in a column or a check constraint) in the specified sequences.
 type UpdateTableBackReferencesInSequences struct {
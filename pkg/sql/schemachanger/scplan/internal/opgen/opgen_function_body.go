@@ -56,6 +56,15 @@ func init() {
 						TypeIDs:                    this.UsesTypeIDs,
 					}
 				}),
+				emit(func(this *scpb.FunctionBody) *scop.RemoveBackReferenceInFunctions {
+					if len(this.UsesFunctionIDs) == 0 {
+						return nil
+					}
+					return &scop.RemoveBackReferenceInFunctions{
+						BackReferencedDescriptorID: this.FunctionID,
+						FunctionIDs:                this.UsesFunctionIDs,
+					}
+				}),
 				emit(func(this *scpb.FunctionBody) *scop.RemoveBackReferencesInRelations {
 					var relationIDs []descpb.ID
 					for _, ref := range this.UsesTables {
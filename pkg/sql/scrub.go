@@ -408,7 +408,7 @@ func createIndexCheckOperations(
 // createConstraintCheckOperations will return all of the constraints
 // that are being checked. If constraintNames is nil, then all
 // constraints are returned.
-// Only SQL CHECK, FOREIGN KEY, and UNIQUE constraints are supported.
+// Only SQL CHECK, FOREIGN KEY, UNIQUE and EXCLUDE constraints are supported.
 func createConstraintCheckOperations(
 	ctx context.Context,
 	p *planner,
@@ -447,6 +447,8 @@ func createConstraintCheckOperations(
 			op = newSQLUniqueWithIndexConstraintCheckOperation(tableName, tableDesc, uwi, asOf)
 		} else if uwoi := constraint.AsUniqueWithoutIndex(); uwoi != nil {
 			op = newSQLUniqueWithoutIndexConstraintCheckOperation(tableName, tableDesc, uwoi, asOf)
+		} else if excl := constraint.AsExclusion(); excl != nil {
+			op = newSQLExclusionConstraintCheckOperation(tableName, tableDesc, excl, asOf)
 		} else {
 			return nil, errors.AssertionFailedf("unknown constraint type %T", constraint)
 		}
@@ -0,0 +1,194 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/scrub"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+// sqlExclusionConstraintCheckOperation is a check which validates an
+// EXCLUDE constraint on a table.
+type sqlExclusionConstraintCheckOperation struct {
+	tableName  *tree.TableName
+	tableDesc  catalog.TableDescriptor
+	constraint catalog.ExclusionConstraint
+	asOf       hlc.Timestamp
+
+	// columns is a list of the columns returned in the query result
+	// tree.Datums.
+	columns []catalog.Column
+	// primaryColIdxs maps PrimaryIndex.Columns to the row
+	// indexes in the query result tree.Datums.
+	primaryColIdxs []int
+
+	run sqlCheckConstraintCheckRun
+}
+
+func newSQLExclusionConstraintCheckOperation(
+	tableName *tree.TableName,
+	tableDesc catalog.TableDescriptor,
+	constraint catalog.ExclusionConstraint,
+	asOf hlc.Timestamp,
+) *sqlExclusionConstraintCheckOperation {
+	return &sqlExclusionConstraintCheckOperation{
+		tableName:  tableName,
+		tableDesc:  tableDesc,
+		constraint: constraint,
+		asOf:       asOf,
+	}
+}
+
+// Start implements the checkOperation interface.
+// It creates a SELECT expression and generates a plan from it, which
+// then runs in the distSQL execution engine.
+func (o *sqlExclusionConstraintCheckOperation) Start(params runParams) error {
+	ctx := params.ctx
+	// Create a query of the form:
+	// SELECT l.k, l.a, l.b FROM (SELECT k, a, b FROM db.t [WHERE predicate]) AS l
+	// WHERE EXISTS (
+	//   SELECT 1 FROM (SELECT k, a, b FROM db.t [WHERE predicate]) AS r
+	//   WHERE l.a = r.a AND l.b && r.b AND (l.k) != (r.k)
+	// );
+	// Where k, a and b are all the public columns in table db.t, k is the
+	// primary key, and the constraint is EXCLUDE (a WITH =, b WITH &&). Each row
+	// that conflicts with another row is returned, so both rows of a
+	// conflicting pair are reported.
+
+	// Collect all the columns.
+	o.columns = o.tableDesc.PublicColumns()
+	cols := make([]string, len(o.columns))
+	lCols := make([]string, len(o.columns))
+	for i, col := range o.columns {
+		cols[i] = tree.NameString(col.GetName())
+		lCols[i] = "l." + cols[i]
+	}
+
+	matchers := make([]string, o.constraint.NumKeyColumns(), o.constraint.NumKeyColumns()+1)
+	for i := range matchers {
+		col, err := catalog.MustFindColumnByID(o.tableDesc, o.constraint.GetKeyColumnID(i))
+		if err != nil {
+			return err
+		}
+		matchers[i] = fmt.Sprintf(
+			"l.%[1]s %[2]s r.%[1]s", tree.NameString(col.GetName()), o.constraint.GetOperator(i).String(),
+		)
+	}
+	primaryIndex := o.tableDesc.GetPrimaryIndex()
+	lPK := make([]string, primaryIndex.NumKeyColumns())
+	rPK := make([]string, primaryIndex.NumKeyColumns())
+	for i := range lPK {
+		name := tree.NameString(primaryIndex.GetKeyColumnName(i))
+		lPK[i] = "l." + name
+		rPK[i] = "r." + name
+	}
+	matchers = append(matchers, fmt.Sprintf("(%s) != (%s)", strings.Join(lPK, ", "), strings.Join(rPK, ", ")))
+
+	tn := *o.tableName
+	tn.ExplicitCatalog = true
+	tn.ExplicitSchema = true
+	where := ""
+	if o.constraint.IsPartial() {
+		where = fmt.Sprintf(" WHERE (%s)", o.constraint.GetPredicate())
+	}
+	subquery := fmt.Sprintf(`(SELECT %s FROM %s%s)`,
+		strings.Join(cols, ", "), tree.AsStringWithFlags(&tn, tree.FmtParsable), where,
+	)
+	asOf := ""
+	if o.asOf != hlc.MaxTimestamp {
+		asOf = fmt.Sprintf("AS OF SYSTEM TIME '%s'", o.asOf.AsOfSystemTime())
+	}
+
+	sel := fmt.Sprintf(`SELECT %[1]s
+FROM %[2]s AS l %[3]s
+WHERE EXISTS (SELECT 1 FROM %[2]s AS r WHERE %[4]s)`,
+		strings.Join(lCols, ", "),       // 1
+		subquery,                        // 2
+		asOf,                            // 3
+		strings.Join(matchers, " AND "), // 4
+	)
+
+	rows, err := params.p.InternalSQLTxn().QueryBuffered(
+		ctx, "scrub-exclusion", params.p.txn, sel,
+	)
+	if err != nil {
+		return err
+	}
+
+	o.run.started = true
+	o.run.rows = rows
+	// Find the row indexes for all of the primary index columns.
+	o.primaryColIdxs, err = getPrimaryColIdxs(o.tableDesc, o.columns)
+	return err
+}
+
+// Next implements the checkOperation interface.
+func (o *sqlExclusionConstraintCheckOperation) Next(params runParams) (tree.Datums, error) {
+	row := o.run.rows[o.run.rowIndex]
+	o.run.rowIndex++
+	timestamp, err := tree.MakeDTimestamp(
+		params.extendedEvalCtx.GetStmtTimestamp(),
+		time.Nanosecond,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var primaryKeyDatums tree.Datums
+	for _, rowIdx := range o.primaryColIdxs {
+		primaryKeyDatums = append(primaryKeyDatums, row[rowIdx])
+	}
+
+	details := make(map[string]interface{})
+	rowDetails := make(map[string]interface{})
+	details["row_data"] = rowDetails
+	details["constraint_name"] = o.constraint.GetName()
+	for rowIdx, col := range o.columns {
+		rowDetails[col.GetName()] = row[rowIdx].String()
+	}
+	detailsJSON, err := tree.MakeDJSON(details)
+	if err != nil {
+		return nil, err
+	}
+
+	return tree.Datums{
+		tree.DNull, /* job_uuid */
+		tree.NewDString(scrub.ExclusionConstraintViolation),
+		tree.NewDString(o.tableName.Catalog()),
+		tree.NewDString(o.tableName.Table()),
+		tree.NewDString(primaryKeyDatums.String()),
+		timestamp,
+		tree.DBoolFalse,
+		detailsJSON,
+	}, nil
+}
+
+// Started implements the checkOperation interface.
+func (o *sqlExclusionConstraintCheckOperation) Started() bool {
+	return o.run.started
+}
+
+// Done implements the checkOperation interface.
+func (o *sqlExclusionConstraintCheckOperation) Done(ctx context.Context) bool {
+	return o.run.rows == nil || o.run.rowIndex >= len(o.run.rows)
+}
+
+// Close implements the checkOperation interface.
+func (o *sqlExclusionConstraintCheckOperation) Close(ctx context.Context) {
+	o.run.rows = nil
+}
@@ -721,3 +721,49 @@ INSERT INTO db.t VALUES (1, 2), (2,3);
 	time.Sleep(1 * time.Millisecond)
 	scrubtestutils.RunScrub(t, db, `EXPERIMENTAL SCRUB TABLE db.t AS OF SYSTEM TIME '-1ms' WITH OPTIONS CONSTRAINT ALL`, exp)
 }
+
+// TestScrubExclusionConstraint tests SCRUB on a table that violates an
+// EXCLUDE constraint.
+func TestScrubExclusionConstraint(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(context.Background())
+
+	// Create the table with conflicting rows, and add the constraint without
+	// validating them.
+	if _, err := db.Exec(`
+CREATE DATABASE db;
+CREATE TABLE db.t (
+	id INT PRIMARY KEY,
+	r INT4RANGE
+);
+
+INSERT INTO db.t VALUES (1, '[1,5)'), (2, '[3,7)'), (3, '[10,12)');
+ALTER TABLE db.t ADD CONSTRAINT excl_r EXCLUDE USING gist (r WITH &&) NOT VALID;
+`); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Both rows of the conflicting pair are reported.
+	exp := []scrubtestutils.ExpectedScrubResult{
+		{
+			ErrorType:    scrub.ExclusionConstraintViolation,
+			Database:     "db",
+			Table:        "t",
+			PrimaryKey:   "(1)",
+			DetailsRegex: `{"constraint_name": "excl_r", "row_data": {"id": "1"`,
+		},
+		{
+			ErrorType:    scrub.ExclusionConstraintViolation,
+			Database:     "db",
+			Table:        "t",
+			PrimaryKey:   "(2)",
+			DetailsRegex: `{"constraint_name": "excl_r", "row_data": {"id": "2"`,
+		},
+	}
+	scrubtestutils.RunScrub(t, db, `EXPERIMENTAL SCRUB TABLE db.t WITH OPTIONS CONSTRAINT ALL`, exp)
+	scrubtestutils.RunScrub(t, db, `EXPERIMENTAL SCRUB TABLE db.t WITH OPTIONS CONSTRAINT (excl_r)`, exp)
+	time.Sleep(1 * time.Millisecond)
+	scrubtestutils.RunScrub(t, db, `EXPERIMENTAL SCRUB TABLE db.t AS OF SYSTEM TIME '-1ms' WITH OPTIONS CONSTRAINT ALL`, exp)
+}
@@ -58,7 +58,10 @@ func init() {
 			signature := name + fn.Signature(true)
 			overloads[i].Oid = signatureMustHaveHardcodedOID(signature)
 			tree.OidToBuiltinName[overloads[i].Oid] = name
-			if _, ok := CastBuiltinNames[name]; ok {
+			// The builtins of range and multirange types that share the name of a
+			// cast are constructors, rather than casts, unless they have a single
+			// parameter.
+			if _, ok := CastBuiltinNames[name]; ok && fn.Types.Length() == 1 {
 				retOid := fn.ReturnType(nil).Oid()
 				if _, ok := CastBuiltinOIDs[retOid]; !ok {
 					CastBuiltinOIDs[retOid] = make(map[types.Family]oid.Oid, len(overloads))
@@ -298,14 +298,17 @@ var regularBuiltins = map[string]builtinDefinition{
 	// TODO(pmattis): What string functions should also support types.Bytes?
 
 	"lower": makeBuiltin(tree.FunctionProperties{Category: builtinconstants.CategoryString},
-		stringOverload1(
-			func(_ context.Context, _ *eval.Context, s string) (tree.Datum, error) {
-				return tree.NewDString(strings.ToLower(s)), nil
-			},
-			types.String,
-			"Converts all characters in `val` to their lower-case equivalents.",
-			volatility.Immutable,
-		),
+		append(
+			[]tree.Overload{preferredOverload(stringOverload1(
+				func(_ context.Context, _ *eval.Context, s string) (tree.Datum, error) {
+					return tree.NewDString(strings.ToLower(s)), nil
+				},
+				types.String,
+				"Converts all characters in `val` to their lower-case equivalents.",
+				volatility.Immutable,
+			))},
+			makeRangeBoundOverloads(false /* upper */)...,
+		)...,
 	),
 
 	"unaccent": makeBuiltin(tree.FunctionProperties{Category: builtinconstants.CategoryString},
@@ -329,14 +332,17 @@ var regularBuiltins = map[string]builtinDefinition{
 	),
 
 	"upper": makeBuiltin(tree.FunctionProperties{Category: builtinconstants.CategoryString},
-		stringOverload1(
-			func(_ context.Context, _ *eval.Context, s string) (tree.Datum, error) {
-				return tree.NewDString(strings.ToUpper(s)), nil
-			},
-			types.String,
-			"Converts all characters in `val` to their to their upper-case equivalents.",
-			volatility.Immutable,
-		),
+		append(
+			[]tree.Overload{preferredOverload(stringOverload1(
+				func(_ context.Context, _ *eval.Context, s string) (tree.Datum, error) {
+					return tree.NewDString(strings.ToUpper(s)), nil
+				},
+				types.String,
+				"Converts all characters in `val` to their to their upper-case equivalents.",
+				volatility.Immutable,
+			))},
+			makeRangeBoundOverloads(true /* upper */)...,
+		)...,
 	),
 
 	"prettify_statement": makeBuiltin(tree.FunctionProperties{Category: builtinconstants.CategoryString},
@@ -3924,17 +3930,7 @@ value if you rely on the HLC for accuracy.`,
 	"tsvector_cmp":                   makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: builtinconstants.CategoryFullTextSearch}),
 	"tsvector_concat":                makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: builtinconstants.CategoryFullTextSearch}),
 	"ts_debug":                       makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: builtinconstants.CategoryFullTextSearch}),
-	"ts_headline":                    makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: builtinconstants.CategoryFullTextSearch}),
-	"ts_lexize":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: builtinconstants.CategoryFullTextSearch}),
-	"websearch_to_tsquery":           makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: builtinconstants.CategoryFullTextSearch}),
-	"array_to_tsvector":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: builtinconstants.CategoryFullTextSearch}),
 	"get_current_ts_config":          makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: builtinconstants.CategoryFullTextSearch}),
-	"numnode":                        makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: builtinconstants.CategoryFullTextSearch}),
-	"querytree":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: builtinconstants.CategoryFullTextSearch}),
-	"setweight":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: builtinconstants.CategoryFullTextSearch}),
-	"strip":                          makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: builtinconstants.CategoryFullTextSearch}),
-	"json_to_tsvector":               makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: builtinconstants.CategoryFullTextSearch}),
-	"jsonb_to_tsvector":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: builtinconstants.CategoryFullTextSearch}),
 	"ts_delete":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: builtinconstants.CategoryFullTextSearch}),
 	"ts_filter":                      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: builtinconstants.CategoryFullTextSearch}),
 	"ts_rank_cd":                     makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 7821, Category: builtinconstants.CategoryFullTextSearch}),
@@ -4021,14 +4017,6 @@ value if you rely on the HLC for accuracy.`,
 	// The behavior of both the JSON and JSONB data types in CockroachDB is
 	// similar to the behavior of the JSONB data type in Postgres.
 
-	"jsonb_path_exists":      makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 22513, Category: builtinconstants.CategoryJSON}),
-	"jsonb_path_exists_opr":  makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 22513, Category: builtinconstants.CategoryJSON}),
-	"jsonb_path_match":       makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 22513, Category: builtinconstants.CategoryJSON}),
-	"jsonb_path_match_opr":   makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 22513, Category: builtinconstants.CategoryJSON}),
-	"jsonb_path_query":       makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 22513, Category: builtinconstants.CategoryJSON}),
-	"jsonb_path_query_array": makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 22513, Category: builtinconstants.CategoryJSON}),
-	"jsonb_path_query_first": makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 22513, Category: builtinconstants.CategoryJSON}),
-
 	"json_remove_path": makeBuiltin(jsonProps(),
 		tree.Overload{
 			Types:      tree.ParamTypes{{Name: "val", Typ: types.Jsonb}, {Name: "path", Typ: types.StringArray}},
@@ -6994,6 +6982,30 @@ Parameters:` + randgencfg.ConfigDoc,
 		},
 	),
 
+	// grouping backs the GROUPING(...) syntax. It is replaced by the optimizer
+	// when it is used in a query with a GROUP BY clause.
+	"grouping": makeBuiltin(
+		tree.FunctionProperties{
+			Category:     builtinconstants.CategoryCompatibility,
+			Undocumented: true,
+		},
+		tree.Overload{
+			Types: tree.VariadicType{
+				VarType: types.Any,
+			},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ context.Context, _ *eval.Context, _ tree.Datums) (tree.Datum, error) {
+				return nil, pgerror.New(pgcode.Grouping,
+					"arguments to GROUPING must be grouping expressions of the associated query level")
+			},
+			Info: "Returns a bit mask indicating which of the arguments are not included " +
+				"in the current grouping set. Bits are assigned with the rightmost argument " +
+				"corresponding to the least-significant bit.",
+			Volatility:        volatility.Immutable,
+			CalledOnNullInput: true,
+		},
+	),
+
 	builtinconstants.GatewayRegionBuiltinName: makeBuiltin(
 		tree.FunctionProperties{
 			Category: builtinconstants.CategoryMultiRegion,
@@ -8342,6 +8354,23 @@ specified store on the node it's run from. One of 'mvccGC', 'merge', 'split',
 			CalledOnNullInput: true,
 		},
 	),
+	"crdb_internal.row_level_security_violation": makeBuiltin(tree.FunctionProperties{
+		Category:     builtinconstants.CategorySystemInfo,
+		Undocumented: true,
+	},
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "table_name", Typ: types.String}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return nil, pgerror.Newf(pgcode.InsufficientPrivilege,
+					"new row violates row-level security policy for table %q",
+					string(tree.MustBeDString(args[0])))
+			},
+			Info: "This function is used internally to reject rows that do not satisfy " +
+				"the row-level security policies of a table.",
+			Volatility: volatility.Volatile,
+		},
+	),
 	"bitmask_or": makeBuiltin(tree.FunctionProperties{Category: builtinconstants.CategoryString},
 		stringOverload2(
 			"a",
@@ -9835,6 +9864,13 @@ func stringOverload1(
 	}
 }
 
+// preferredOverload returns the given overload, marked as the preferred one
+// in case of ambiguity during overload resolution.
+func preferredOverload(o tree.Overload) tree.Overload {
+	o.PreferredOverload = true
+	return o
+}
+
 func stringOverload2(
 	a, b string,
 	f func(context.Context, *eval.Context, string, string) (tree.Datum, error),
@@ -1400,7 +1400,7 @@ var builtinOidsArray = []string{
 	1424: `obj_description(object_oid: oid, catalog_name: string) -> string`,
 	1425: `oid(int: int) -> oid`,
 	1426: `shobj_description(object_oid: oid, catalog_name: string) -> string`,
-	1427: `pg_try_advisory_lock(int: int) -> bool`,
+	1427: `pg_try_advisory_lock(key: int) -> bool`,
 	1428: `pg_advisory_unlock(key: int) -> bool`,
 	1429: `pg_client_encoding() -> string`,
 	1430: `pg_function_is_visible(oid: oid) -> bool`,
@@ -2538,6 +2538,315 @@ var builtinOidsArray = []string{
 	2570: `array_position(array: refcursor[], elem: refcursor, start: int) -> int`,
 	2571: `bit_count(val: bytes) -> int`,
 	2572: `bit_count(val: varbit) -> int`,
+	2573: `triggerin(input: anyelement) -> trigger`,
+	2574: `triggerout(trigger: trigger) -> bytes`,
+	2575: `triggersend(trigger: trigger) -> bytes`,
+	2576: `triggerrecv(input: anyelement) -> trigger`,
+	2577: `jsonpath_in(input: anyelement) -> jsonpath`,
+	2578: `jsonpath_out(jsonpath: jsonpath) -> bytes`,
+	2579: `jsonpath_send(jsonpath: jsonpath) -> bytes`,
+	2580: `jsonpath_recv(input: anyelement) -> jsonpath`,
+	2581: `jsonpath(jsonpath: jsonpath) -> jsonpath`,
+	2582: `jsonpath(string: string) -> jsonpath`,
+	2583: `bpchar(jsonpath: jsonpath) -> char`,
+	2584: `char(jsonpath: jsonpath) -> "char"`,
+	2585: `name(jsonpath: jsonpath) -> name`,
+	2586: `text(jsonpath: jsonpath) -> string`,
+	2587: `varchar(jsonpath: jsonpath) -> varchar`,
+	2588: `jsonb_path_exists(target: jsonb, path: jsonpath) -> bool`,
+	2589: `jsonb_path_exists(target: jsonb, path: jsonpath, vars: jsonb) -> bool`,
+	2590: `jsonb_path_exists(target: jsonb, path: jsonpath, vars: jsonb, silent: bool) -> bool`,
+	2591: `jsonb_path_match(target: jsonb, path: jsonpath) -> bool`,
+	2592: `jsonb_path_match(target: jsonb, path: jsonpath, vars: jsonb) -> bool`,
+	2593: `jsonb_path_match(target: jsonb, path: jsonpath, vars: jsonb, silent: bool) -> bool`,
+	2594: `jsonb_path_query_array(target: jsonb, path: jsonpath) -> jsonb`,
+	2595: `jsonb_path_query_array(target: jsonb, path: jsonpath, vars: jsonb) -> jsonb`,
+	2596: `jsonb_path_query_array(target: jsonb, path: jsonpath, vars: jsonb, silent: bool) -> jsonb`,
+	2597: `jsonb_path_query_first(target: jsonb, path: jsonpath) -> jsonb`,
+	2598: `jsonb_path_query_first(target: jsonb, path: jsonpath, vars: jsonb) -> jsonb`,
+	2599: `jsonb_path_query_first(target: jsonb, path: jsonpath, vars: jsonb, silent: bool) -> jsonb`,
+	2600: `jsonb_path_query(target: jsonb, path: jsonpath) -> jsonb`,
+	2601: `jsonb_path_query(target: jsonb, path: jsonpath, vars: jsonb) -> jsonb`,
+	2602: `jsonb_path_query(target: jsonb, path: jsonpath, vars: jsonb, silent: bool) -> jsonb`,
+	2603: `jsonb_path_exists_opr(target: jsonb, path: jsonpath) -> bool`,
+	2604: `jsonb_path_match_opr(target: jsonb, path: jsonpath) -> bool`,
+	2605: `grouping(anyelement...) -> int`,
+	2606: `pg_notify(channel: string, payload: string) -> void`,
+	2607: `pg_listening_channels() -> string`,
+	2608: `int4rangein(input: anyelement) -> int4range`,
+	2609: `int4rangeout(int4range: int4range) -> bytes`,
+	2610: `int4rangesend(int4range: int4range) -> bytes`,
+	2611: `int4rangerecv(input: anyelement) -> int4range`,
+	2612: `int4multirangein(input: anyelement) -> int4multirange`,
+	2613: `int4multirangeout(int4multirange: int4multirange) -> bytes`,
+	2614: `int4multirangesend(int4multirange: int4multirange) -> bytes`,
+	2615: `int4multirangerecv(input: anyelement) -> int4multirange`,
+	2616: `int8rangein(input: anyelement) -> int8range`,
+	2617: `int8rangeout(int8range: int8range) -> bytes`,
+	2618: `int8rangesend(int8range: int8range) -> bytes`,
+	2619: `int8rangerecv(input: anyelement) -> int8range`,
+	2620: `int8multirangein(input: anyelement) -> int8multirange`,
+	2621: `int8multirangeout(int8multirange: int8multirange) -> bytes`,
+	2622: `int8multirangesend(int8multirange: int8multirange) -> bytes`,
+	2623: `int8multirangerecv(input: anyelement) -> int8multirange`,
+	2624: `numrangein(input: anyelement) -> numrange`,
+	2625: `numrangeout(numrange: numrange) -> bytes`,
+	2626: `numrangesend(numrange: numrange) -> bytes`,
+	2627: `numrangerecv(input: anyelement) -> numrange`,
+	2628: `nummultirangein(input: anyelement) -> nummultirange`,
+	2629: `nummultirangeout(nummultirange: nummultirange) -> bytes`,
+	2630: `nummultirangesend(nummultirange: nummultirange) -> bytes`,
+	2631: `nummultirangerecv(input: anyelement) -> nummultirange`,
+	2632: `tsrangein(input: anyelement) -> tsrange`,
+	2633: `tsrangeout(tsrange: tsrange) -> bytes`,
+	2634: `tsrangesend(tsrange: tsrange) -> bytes`,
+	2635: `tsrangerecv(input: anyelement) -> tsrange`,
+	2636: `tsmultirangein(input: anyelement) -> tsmultirange`,
+	2637: `tsmultirangeout(tsmultirange: tsmultirange) -> bytes`,
+	2638: `tsmultirangesend(tsmultirange: tsmultirange) -> bytes`,
+	2639: `tsmultirangerecv(input: anyelement) -> tsmultirange`,
+	2640: `tstzrangein(input: anyelement) -> tstzrange`,
+	2641: `tstzrangeout(tstzrange: tstzrange) -> bytes`,
+	2642: `tstzrangesend(tstzrange: tstzrange) -> bytes`,
+	2643: `tstzrangerecv(input: anyelement) -> tstzrange`,
+	2644: `tstzmultirangein(input: anyelement) -> tstzmultirange`,
+	2645: `tstzmultirangeout(tstzmultirange: tstzmultirange) -> bytes`,
+	2646: `tstzmultirangesend(tstzmultirange: tstzmultirange) -> bytes`,
+	2647: `tstzmultirangerecv(input: anyelement) -> tstzmultirange`,
+	2648: `daterangein(input: anyelement) -> daterange`,
+	2649: `daterangeout(daterange: daterange) -> bytes`,
+	2650: `daterangesend(daterange: daterange) -> bytes`,
+	2651: `daterangerecv(input: anyelement) -> daterange`,
+	2652: `datemultirangein(input: anyelement) -> datemultirange`,
+	2653: `datemultirangeout(datemultirange: datemultirange) -> bytes`,
+	2654: `datemultirangesend(datemultirange: datemultirange) -> bytes`,
+	2655: `datemultirangerecv(input: anyelement) -> datemultirange`,
+	2656: `int4range(string: string) -> int4range`,
+	2657: `int4range(int4range: int4range) -> int4range`,
+	2658: `bpchar(int4range: int4range) -> char`,
+	2659: `char(int4range: int4range) -> "char"`,
+	2660: `name(int4range: int4range) -> name`,
+	2661: `text(int4range: int4range) -> string`,
+	2662: `varchar(int4range: int4range) -> varchar`,
+	2663: `int4multirange(int4range: int4range) -> int4multirange`,
+	2664: `int4range(lower: int4, upper: int4) -> int4range`,
+	2665: `int4range(lower: int4, upper: int4, bounds: string) -> int4range`,
+	2666: `int4multirange(string: string) -> int4multirange`,
+	2667: `int4multirange(int4multirange: int4multirange) -> int4multirange`,
+	2668: `bpchar(int4multirange: int4multirange) -> char`,
+	2669: `char(int4multirange: int4multirange) -> "char"`,
+	2670: `name(int4multirange: int4multirange) -> name`,
+	2671: `text(int4multirange: int4multirange) -> string`,
+	2672: `varchar(int4multirange: int4multirange) -> varchar`,
+	2673: `int4multirange() -> int4multirange`,
+	2674: `int4multirange(int4range, int4range, int4range...) -> int4multirange`,
+	2675: `int8range(string: string) -> int8range`,
+	2676: `int8range(int8range: int8range) -> int8range`,
+	2677: `bpchar(int8range: int8range) -> char`,
+	2678: `char(int8range: int8range) -> "char"`,
+	2679: `name(int8range: int8range) -> name`,
+	2680: `text(int8range: int8range) -> string`,
+	2681: `varchar(int8range: int8range) -> varchar`,
+	2682: `int8multirange(int8range: int8range) -> int8multirange`,
+	2683: `int8range(lower: int, upper: int) -> int8range`,
+	2684: `int8range(lower: int, upper: int, bounds: string) -> int8range`,
+	2685: `int8multirange(string: string) -> int8multirange`,
+	2686: `int8multirange(int8multirange: int8multirange) -> int8multirange`,
+	2687: `bpchar(int8multirange: int8multirange) -> char`,
+	2688: `char(int8multirange: int8multirange) -> "char"`,
+	2689: `name(int8multirange: int8multirange) -> name`,
+	2690: `text(int8multirange: int8multirange) -> string`,
+	2691: `varchar(int8multirange: int8multirange) -> varchar`,
+	2692: `int8multirange() -> int8multirange`,
+	2693: `int8multirange(int8range, int8range, int8range...) -> int8multirange`,
+	2694: `numrange(string: string) -> numrange`,
+	2695: `numrange(numrange: numrange) -> numrange`,
+	2696: `bpchar(numrange: numrange) -> char`,
+	2697: `char(numrange: numrange) -> "char"`,
+	2698: `name(numrange: numrange) -> name`,
+	2699: `text(numrange: numrange) -> string`,
+	2700: `varchar(numrange: numrange) -> varchar`,
+	2701: `nummultirange(numrange: numrange) -> nummultirange`,
+	2702: `numrange(lower: decimal, upper: decimal) -> numrange`,
+	2703: `numrange(lower: decimal, upper: decimal, bounds: string) -> numrange`,
+	2704: `nummultirange(string: string) -> nummultirange`,
+	2705: `nummultirange(nummultirange: nummultirange) -> nummultirange`,
+	2706: `bpchar(nummultirange: nummultirange) -> char`,
+	2707: `char(nummultirange: nummultirange) -> "char"`,
+	2708: `name(nummultirange: nummultirange) -> name`,
+	2709: `text(nummultirange: nummultirange) -> string`,
+	2710: `varchar(nummultirange: nummultirange) -> varchar`,
+	2711: `nummultirange() -> nummultirange`,
+	2712: `nummultirange(numrange, numrange, numrange...) -> nummultirange`,
+	2713: `tsrange(string: string) -> tsrange`,
+	2714: `tsrange(tsrange: tsrange) -> tsrange`,
+	2715: `bpchar(tsrange: tsrange) -> char`,
+	2716: `char(tsrange: tsrange) -> "char"`,
+	2717: `name(tsrange: tsrange) -> name`,
+	2718: `text(tsrange: tsrange) -> string`,
+	2719: `varchar(tsrange: tsrange) -> varchar`,
+	2720: `tsmultirange(tsrange: tsrange) -> tsmultirange`,
+	2721: `tsrange(lower: timestamp, upper: timestamp) -> tsrange`,
+	2722: `tsrange(lower: timestamp, upper: timestamp, bounds: string) -> tsrange`,
+	2723: `tsmultirange(string: string) -> tsmultirange`,
+	2724: `tsmultirange(tsmultirange: tsmultirange) -> tsmultirange`,
+	2725: `bpchar(tsmultirange: tsmultirange) -> char`,
+	2726: `char(tsmultirange: tsmultirange) -> "char"`,
+	2727: `name(tsmultirange: tsmultirange) -> name`,
+	2728: `text(tsmultirange: tsmultirange) -> string`,
+	2729: `varchar(tsmultirange: tsmultirange) -> varchar`,
+	2730: `tsmultirange() -> tsmultirange`,
+	2731: `tsmultirange(tsrange, tsrange, tsrange...) -> tsmultirange`,
+	2732: `tstzrange(string: string) -> tstzrange`,
+	2733: `tstzrange(tstzrange: tstzrange) -> tstzrange`,
+	2734: `bpchar(tstzrange: tstzrange) -> char`,
+	2735: `char(tstzrange: tstzrange) -> "char"`,
+	2736: `name(tstzrange: tstzrange) -> name`,
+	2737: `text(tstzrange: tstzrange) -> string`,
+	2738: `varchar(tstzrange: tstzrange) -> varchar`,
+	2739: `tstzmultirange(tstzrange: tstzrange) -> tstzmultirange`,
+	2740: `tstzrange(lower: timestamptz, upper: timestamptz) -> tstzrange`,
+	2741: `tstzrange(lower: timestamptz, upper: timestamptz, bounds: string) -> tstzrange`,
+	2742: `tstzmultirange(string: string) -> tstzmultirange`,
+	2743: `tstzmultirange(tstzmultirange: tstzmultirange) -> tstzmultirange`,
+	2744: `bpchar(tstzmultirange: tstzmultirange) -> char`,
+	2745: `char(tstzmultirange: tstzmultirange) -> "char"`,
+	2746: `name(tstzmultirange: tstzmultirange) -> name`,
+	2747: `text(tstzmultirange: tstzmultirange) -> string`,
+	2748: `varchar(tstzmultirange: tstzmultirange) -> varchar`,
+	2749: `tstzmultirange() -> tstzmultirange`,
+	2750: `tstzmultirange(tstzrange, tstzrange, tstzrange...) -> tstzmultirange`,
+	2751: `daterange(string: string) -> daterange`,
+	2752: `daterange(daterange: daterange) -> daterange`,
+	2753: `bpchar(daterange: daterange) -> char`,
+	2754: `char(daterange: daterange) -> "char"`,
+	2755: `name(daterange: daterange) -> name`,
+	2756: `text(daterange: daterange) -> string`,
+	2757: `varchar(daterange: daterange) -> varchar`,
+	2758: `datemultirange(daterange: daterange) -> datemultirange`,
+	2759: `daterange(lower: date, upper: date) -> daterange`,
+	2760: `daterange(lower: date, upper: date, bounds: string) -> daterange`,
+	2761: `datemultirange(string: string) -> datemultirange`,
+	2762: `datemultirange(datemultirange: datemultirange) -> datemultirange`,
+	2763: `bpchar(datemultirange: datemultirange) -> char`,
+	2764: `char(datemultirange: datemultirange) -> "char"`,
+	2765: `name(datemultirange: datemultirange) -> name`,
+	2766: `text(datemultirange: datemultirange) -> string`,
+	2767: `varchar(datemultirange: datemultirange) -> varchar`,
+	2768: `datemultirange() -> datemultirange`,
+	2769: `datemultirange(daterange, daterange, daterange...) -> datemultirange`,
+	2770: `lower(val: int4range) -> int4`,
+	2771: `lower(val: int4multirange) -> int4`,
+	2772: `lower(val: int8range) -> int`,
+	2773: `lower(val: int8multirange) -> int`,
+	2774: `lower(val: numrange) -> decimal`,
+	2775: `lower(val: nummultirange) -> decimal`,
+	2776: `lower(val: tsrange) -> timestamp`,
+	2777: `lower(val: tsmultirange) -> timestamp`,
+	2778: `lower(val: tstzrange) -> timestamptz`,
+	2779: `lower(val: tstzmultirange) -> timestamptz`,
+	2780: `lower(val: daterange) -> date`,
+	2781: `lower(val: datemultirange) -> date`,
+	2782: `upper(val: int4range) -> int4`,
+	2783: `upper(val: int4multirange) -> int4`,
+	2784: `upper(val: int8range) -> int`,
+	2785: `upper(val: int8multirange) -> int`,
+	2786: `upper(val: numrange) -> decimal`,
+	2787: `upper(val: nummultirange) -> decimal`,
+	2788: `upper(val: tsrange) -> timestamp`,
+	2789: `upper(val: tsmultirange) -> timestamp`,
+	2790: `upper(val: tstzrange) -> timestamptz`,
+	2791: `upper(val: tstzmultirange) -> timestamptz`,
+	2792: `upper(val: daterange) -> date`,
+	2793: `upper(val: datemultirange) -> date`,
+	2794: `isempty(val: int4range) -> bool`,
+	2795: `isempty(val: int4multirange) -> bool`,
+	2796: `isempty(val: int8range) -> bool`,
+	2797: `isempty(val: int8multirange) -> bool`,
+	2798: `isempty(val: numrange) -> bool`,
+	2799: `isempty(val: nummultirange) -> bool`,
+	2800: `isempty(val: tsrange) -> bool`,
+	2801: `isempty(val: tsmultirange) -> bool`,
+	2802: `isempty(val: tstzrange) -> bool`,
+	2803: `isempty(val: tstzmultirange) -> bool`,
+	2804: `isempty(val: daterange) -> bool`,
+	2805: `isempty(val: datemultirange) -> bool`,
+	2806: `lower_inc(val: int4range) -> bool`,
+	2807: `lower_inc(val: int4multirange) -> bool`,
+	2808: `lower_inc(val: int8range) -> bool`,
+	2809: `lower_inc(val: int8multirange) -> bool`,
+	2810: `lower_inc(val: numrange) -> bool`,
+	2811: `lower_inc(val: nummultirange) -> bool`,
+	2812: `lower_inc(val: tsrange) -> bool`,
+	2813: `lower_inc(val: tsmultirange) -> bool`,
+	2814: `lower_inc(val: tstzrange) -> bool`,
+	2815: `lower_inc(val: tstzmultirange) -> bool`,
+	2816: `lower_inc(val: daterange) -> bool`,
+	2817: `lower_inc(val: datemultirange) -> bool`,
+	2818: `upper_inc(val: int4range) -> bool`,
+	2819: `upper_inc(val: int4multirange) -> bool`,
+	2820: `upper_inc(val: int8range) -> bool`,
+	2821: `upper_inc(val: int8multirange) -> bool`,
+	2822: `upper_inc(val: numrange) -> bool`,
+	2823: `upper_inc(val: nummultirange) -> bool`,
+	2824: `upper_inc(val: tsrange) -> bool`,
+	2825: `upper_inc(val: tsmultirange) -> bool`,
+	2826: `upper_inc(val: tstzrange) -> bool`,
+	2827: `upper_inc(val: tstzmultirange) -> bool`,
+	2828: `upper_inc(val: daterange) -> bool`,
+	2829: `upper_inc(val: datemultirange) -> bool`,
+	2830: `lower_inf(val: int4range) -> bool`,
+	2831: `lower_inf(val: int4multirange) -> bool`,
+	2832: `lower_inf(val: int8range) -> bool`,
+	2833: `lower_inf(val: int8multirange) -> bool`,
+	2834: `lower_inf(val: numrange) -> bool`,
+	2835: `lower_inf(val: nummultirange) -> bool`,
+	2836: `lower_inf(val: tsrange) -> bool`,
+	2837: `lower_inf(val: tsmultirange) -> bool`,
+	2838: `lower_inf(val: tstzrange) -> bool`,
+	2839: `lower_inf(val: tstzmultirange) -> bool`,
+	2840: `lower_inf(val: daterange) -> bool`,
+	2841: `lower_inf(val: datemultirange) -> bool`,
+	2842: `upper_inf(val: int4range) -> bool`,
+	2843: `upper_inf(val: int4multirange) -> bool`,
+	2844: `upper_inf(val: int8range) -> bool`,
+	2845: `upper_inf(val: int8multirange) -> bool`,
+	2846: `upper_inf(val: numrange) -> bool`,
+	2847: `upper_inf(val: nummultirange) -> bool`,
+	2848: `upper_inf(val: tsrange) -> bool`,
+	2849: `upper_inf(val: tsmultirange) -> bool`,
+	2850: `upper_inf(val: tstzrange) -> bool`,
+	2851: `upper_inf(val: tstzmultirange) -> bool`,
+	2852: `upper_inf(val: daterange) -> bool`,
+	2853: `upper_inf(val: datemultirange) -> bool`,
+	2854: `range_merge(a: int4range, b: int4range) -> int4range`,
+	2855: `range_merge(val: int4multirange) -> int4range`,
+	2856: `range_merge(a: int8range, b: int8range) -> int8range`,
+	2857: `range_merge(val: int8multirange) -> int8range`,
+	2858: `range_merge(a: numrange, b: numrange) -> numrange`,
+	2859: `range_merge(val: nummultirange) -> numrange`,
+	2860: `range_merge(a: tsrange, b: tsrange) -> tsrange`,
+	2861: `range_merge(val: tsmultirange) -> tsrange`,
+	2862: `range_merge(a: tstzrange, b: tstzrange) -> tstzrange`,
+	2863: `range_merge(val: tstzmultirange) -> tstzrange`,
+	2864: `range_merge(a: daterange, b: daterange) -> daterange`,
+	2865: `range_merge(val: datemultirange) -> daterange`,
+	2866: `crdb_internal.row_level_security_violation(table_name: string) -> bool`,
+	2867: `pg_advisory_lock(key: int) -> void`,
+	2868: `pg_advisory_lock(key1: int4, key2: int4) -> void`,
+	2869: `pg_advisory_lock_shared(key: int) -> void`,
+	2870: `pg_advisory_lock_shared(key1: int4, key2: int4) -> void`,
+	2871: `pg_advisory_xact_lock(key: int) -> void`,
+	2872: `pg_advisory_xact_lock(key1: int4, key2: int4) -> void`,
+	2873: `pg_advisory_xact_lock_shared(key: int) -> void`,
+	2874: `pg_advisory_xact_lock_shared(key1: int4, key2: int4) -> void`,
+	2875: `pg_try_advisory_lock(key1: int4, key2: int4) -> bool`,
+	2876: `pg_try_advisory_lock_shared(key: int) -> bool`,
+	2877: `pg_try_advisory_lock_shared(key1: int4, key2: int4) -> bool`,
+	2878: `pg_try_advisory_xact_lock(key: int) -> bool`,
+	2879: `pg_try_advisory_xact_lock(key1: int4, key2: int4) -> bool`,
+	2880: `pg_try_advisory_xact_lock_shared(key: int) -> bool`,
+	2881: `pg_try_advisory_xact_lock_shared(key1: int4, key2: int4) -> bool`,
 }
 
 var builtinOidsBySignature map[string]oid.Oid
@@ -0,0 +1,237 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
+)
+
+func init() {
+	for k, v := range jsonpathBuiltins {
+		v.props.Category = builtinconstants.CategoryJSON
+		v.props.AvailableOnPublicSchema = true
+		// Most builtins in this file are of the Normal class, but
+		// jsonb_path_query is of the Generator class.
+		const enforceClass = false
+		registerBuiltin(k, v, tree.NormalClass, enforceClass)
+	}
+}
+
+const jsonpathVarsAndSilentInfo = "\n\nIf `vars` is specified, it must be a JSON object whose fields " +
+	"supply the values of the named variables in the path. If `silent` is true, " +
+	"structural errors and errors raised by missing fields or items are suppressed."
+
+var jsonpathBuiltins = map[string]builtinDefinition{
+	"jsonb_path_exists": makeBuiltin(tree.FunctionProperties{},
+		makeJSONPathOverloads(
+			types.Bool,
+			func(j jsonpath.Jsonpath, target, vars json.JSON, silent bool) (tree.Datum, error) {
+				exists, isNull, err := jsonpath.EvalExists(j, target, vars, silent)
+				if err != nil || isNull {
+					return tree.DNull, err
+				}
+				return tree.MakeDBool(tree.DBool(exists)), nil
+			},
+			"Returns whether the JSON path returns any item for the given JSON value.",
+		)...,
+	),
+	"jsonb_path_match": makeBuiltin(tree.FunctionProperties{},
+		makeJSONPathOverloads(
+			types.Bool,
+			func(j jsonpath.Jsonpath, target, vars json.JSON, silent bool) (tree.Datum, error) {
+				match, isNull, err := jsonpath.EvalMatch(j, target, vars, silent)
+				if err != nil || isNull {
+					return tree.DNull, err
+				}
+				return tree.MakeDBool(tree.DBool(match)), nil
+			},
+			"Returns the result of the JSON path predicate check for the given JSON value. "+
+				"Only the first item of the result is taken into account. If the result is "+
+				"not Boolean, then NULL is returned.",
+		)...,
+	),
+	"jsonb_path_query_array": makeBuiltin(tree.FunctionProperties{},
+		makeJSONPathOverloads(
+			types.Jsonb,
+			func(j jsonpath.Jsonpath, target, vars json.JSON, silent bool) (tree.Datum, error) {
+				items, err := jsonpath.EvalQuery(j, target, vars, silent)
+				if err != nil {
+					return nil, err
+				}
+				b := json.NewArrayBuilder(len(items))
+				for _, item := range items {
+					b.Add(item)
+				}
+				return tree.NewDJSON(b.Build()), nil
+			},
+			"Returns all JSON items returned by the JSON path for the given JSON value, "+
+				"as a JSON array.",
+		)...,
+	),
+	"jsonb_path_query_first": makeBuiltin(tree.FunctionProperties{},
+		makeJSONPathOverloads(
+			types.Jsonb,
+			func(j jsonpath.Jsonpath, target, vars json.JSON, silent bool) (tree.Datum, error) {
+				items, err := jsonpath.EvalQuery(j, target, vars, silent)
+				if err != nil || len(items) == 0 {
+					return tree.DNull, err
+				}
+				return tree.NewDJSON(items[0]), nil
+			},
+			"Returns the first JSON item returned by the JSON path for the given JSON value, "+
+				"or NULL if there are no results.",
+		)...,
+	),
+	"jsonb_path_query": makeBuiltin(genProps(), makeJSONPathQueryOverloads()...),
+
+	// The _opr variants back the @? and @@ operators in Postgres. Like the
+	// operators, they suppress errors.
+	"jsonb_path_exists_opr": makeBuiltin(tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "target", Typ: types.Jsonb}, {Name: "path", Typ: types.Jsonpath}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return eval.BinaryOp(ctx, evalCtx, &tree.JSONPathExistsOp{}, args[0], args[1])
+			},
+			Info:       "Implementation of the `@?` operator.",
+			Volatility: volatility.Immutable,
+		},
+	),
+	"jsonb_path_match_opr": makeBuiltin(tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "target", Typ: types.Jsonb}, {Name: "path", Typ: types.Jsonpath}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return eval.BinaryOp(ctx, evalCtx, &tree.JSONPathMatchOp{}, args[0], args[1])
+			},
+			Info:       "Implementation of the `@@` operator for JSON paths.",
+			Volatility: volatility.Immutable,
+		},
+	),
+}
+
+// jsonpathArgs unpacks the target, path, vars and silent arguments shared by
+// the jsonb_path builtins. The vars and silent arguments are optional. Like in
+// Postgres, vars defaults to an empty object, which (unlike omitting the vars
+// from the @? and @@ operators) affects the ids returned by .keyvalue().
+func jsonpathArgs(args tree.Datums) (j jsonpath.Jsonpath, target, vars json.JSON, silent bool) {
+	target = tree.MustBeDJSON(args[0]).JSON
+	j = tree.MustBeDJsonpath(args[1]).Jsonpath
+	vars = json.NewObjectBuilder(0).Build()
+	if len(args) > 2 {
+		vars = tree.MustBeDJSON(args[2]).JSON
+	}
+	if len(args) > 3 {
+		silent = bool(tree.MustBeDBool(args[3]))
+	}
+	return j, target, vars, silent
+}
+
+// jsonpathParamTypes returns the parameter types of the jsonb_path builtins
+// overloads, which take the optional vars and silent arguments.
+func jsonpathParamTypes() []tree.ParamTypes {
+	return []tree.ParamTypes{
+		{{Name: "target", Typ: types.Jsonb}, {Name: "path", Typ: types.Jsonpath}},
+		{{Name: "target", Typ: types.Jsonb}, {Name: "path", Typ: types.Jsonpath}, {Name: "vars", Typ: types.Jsonb}},
+		{{Name: "target", Typ: types.Jsonb}, {Name: "path", Typ: types.Jsonpath}, {Name: "vars", Typ: types.Jsonb}, {Name: "silent", Typ: types.Bool}},
+	}
+}
+
+func makeJSONPathOverloads(
+	retType *types.T,
+	fn func(j jsonpath.Jsonpath, target, vars json.JSON, silent bool) (tree.Datum, error),
+	info string,
+) []tree.Overload {
+	paramTypes := jsonpathParamTypes()
+	overloads := make([]tree.Overload, len(paramTypes))
+	for i := range paramTypes {
+		overloads[i] = tree.Overload{
+			Types:      paramTypes[i],
+			ReturnType: tree.FixedReturnType(retType),
+			Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return fn(jsonpathArgs(args))
+			},
+			Info:       info + jsonpathVarsAndSilentInfo,
+			Volatility: volatility.Immutable,
+		}
+	}
+	return overloads
+}
+
+func makeJSONPathQueryOverloads() []tree.Overload {
+	paramTypes := jsonpathParamTypes()
+	overloads := make([]tree.Overload, len(paramTypes))
+	for i := range paramTypes {
+		overloads[i] = makeGeneratorOverload(
+			paramTypes[i],
+			types.Jsonb,
+			makeJSONPathQueryGenerator,
+			"Returns all JSON items returned by the JSON path for the given JSON value."+
+				jsonpathVarsAndSilentInfo,
+			volatility.Immutable,
+		)
+	}
+	return overloads
+}
+
+// jsonPathQueryGenerator is a value generator that returns each of the items
+// produced by evaluating a JSON path.
+type jsonPathQueryGenerator struct {
+	path   jsonpath.Jsonpath
+	target json.JSON
+	vars   json.JSON
+	silent bool
+
+	items     []json.JSON
+	nextIndex int
+}
+
+func makeJSONPathQueryGenerator(
+	_ context.Context, _ *eval.Context, args tree.Datums,
+) (eval.ValueGenerator, error) {
+	j, target, vars, silent := jsonpathArgs(args)
+	return &jsonPathQueryGenerator{path: j, target: target, vars: vars, silent: silent}, nil
+}
+
+// ResolvedType implements the eval.ValueGenerator interface.
+func (g *jsonPathQueryGenerator) ResolvedType() *types.T {
+	return types.Jsonb
+}
+
+// Start implements the eval.ValueGenerator interface.
+func (g *jsonPathQueryGenerator) Start(_ context.Context, _ *kv.Txn) (err error) {
+	g.nextIndex = -1
+	g.items, err = jsonpath.EvalQuery(g.path, g.target, g.vars, g.silent)
+	return err
+}
+
+// Next implements the eval.ValueGenerator interface.
+func (g *jsonPathQueryGenerator) Next(_ context.Context) (bool, error) {
+	g.nextIndex++
+	return g.nextIndex < len(g.items), nil
+}
+
+// Values implements the eval.ValueGenerator interface.
+func (g *jsonPathQueryGenerator) Values() (tree.Datums, error) {
+	return tree.Datums{tree.NewDJSON(g.items[g.nextIndex])}, nil
+}
+
+// Close implements the eval.ValueGenerator interface.
+func (g *jsonPathQueryGenerator) Close(_ context.Context) {}
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/valueside"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinconstants"
@@ -63,6 +64,106 @@ func makeNotUsableFalseBuiltin() builtinDefinition {
 	)
 }
 
+// advisoryLockKeyOverloads returns overloads of an advisory lock function for
+// a bigint key and for a pair of int4 keys, as in Postgres.
+func advisoryLockKeyOverloads(
+	fn func(context.Context, *eval.Context, eval.AdvisoryLockKey) (tree.Datum, error),
+	returnType *types.T,
+	info string,
+) []tree.Overload {
+	return []tree.Overload{
+		{
+			Types:      tree.ParamTypes{{Name: "key", Typ: types.Int}},
+			ReturnType: tree.FixedReturnType(returnType),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				key := eval.MakeAdvisoryLockKey(int64(tree.MustBeDInt(args[0])))
+				return fn(ctx, evalCtx, key)
+			},
+			Info:       info,
+			Volatility: volatility.Volatile,
+		},
+		{
+			Types:      tree.ParamTypes{{Name: "key1", Typ: types.Int4}, {Name: "key2", Typ: types.Int4}},
+			ReturnType: tree.FixedReturnType(returnType),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				key := eval.MakeAdvisoryLockKeyPair(
+					int32(tree.MustBeDInt(args[0])), int32(tree.MustBeDInt(args[1])),
+				)
+				return fn(ctx, evalCtx, key)
+			},
+			Info:       info,
+			Volatility: volatility.Volatile,
+		},
+	}
+}
+
+// makeAdvisoryLockBuiltin creates a builtin that acquires an advisory lock in
+// the given mode. Session-level locks are held until they are explicitly
+// released or the session ends, and transaction-level locks until the current
+// transaction ends. If try is true, the builtin returns whether the lock was
+// acquired instead of waiting for it to become available.
+func makeAdvisoryLockBuiltin(mode eval.AdvisoryLockMode, xact, try bool) builtinDefinition {
+	level := "session"
+	if xact {
+		level = "transaction"
+	}
+	modeName := "exclusive"
+	if mode == eval.AdvisoryLockShared {
+		modeName = "shared"
+	}
+	var info string
+	returnType := types.Void
+	if try {
+		info = fmt.Sprintf("Obtains a %s-level %s advisory lock if it is available, "+
+			"and returns whether the lock was obtained.", level, modeName)
+		returnType = types.Bool
+	} else {
+		info = fmt.Sprintf("Obtains a %s-level %s advisory lock, "+
+			"waiting if necessary.", level, modeName)
+	}
+	return makeBuiltin(tree.FunctionProperties{DistsqlBlocklist: true}, advisoryLockKeyOverloads(
+		func(ctx context.Context, evalCtx *eval.Context, key eval.AdvisoryLockKey) (tree.Datum, error) {
+			acquired, err := evalCtx.Planner.AcquireAdvisoryLock(ctx, key, mode, xact, !try /* wait */)
+			if err != nil {
+				return nil, err
+			}
+			if try {
+				return tree.MakeDBool(tree.DBool(acquired)), nil
+			}
+			return tree.DVoidDatum, nil
+		},
+		returnType,
+		info,
+	)...)
+}
+
+// makeAdvisoryUnlockBuiltin creates a builtin that releases a session-level
+// advisory lock held in the given mode. As in Postgres, it returns false and
+// emits a warning if the session does not hold the lock.
+func makeAdvisoryUnlockBuiltin(mode eval.AdvisoryLockMode) builtinDefinition {
+	modeName := "exclusive"
+	if mode == eval.AdvisoryLockShared {
+		modeName = "shared"
+	}
+	return makeBuiltin(tree.FunctionProperties{DistsqlBlocklist: true}, advisoryLockKeyOverloads(
+		func(ctx context.Context, evalCtx *eval.Context, key eval.AdvisoryLockKey) (tree.Datum, error) {
+			released, err := evalCtx.Planner.ReleaseAdvisoryLock(ctx, key, mode)
+			if err != nil {
+				return nil, err
+			}
+			if !released && evalCtx.ClientNoticeSender != nil {
+				evalCtx.ClientNoticeSender.BufferClientNotice(ctx, pgnotice.NewWithSeverityf(
+					"WARNING", "you don't own a lock of type %s", mode,
+				))
+			}
+			return tree.MakeDBool(tree.DBool(released)), nil
+		},
+		types.Bool,
+		fmt.Sprintf("Releases a previously-acquired session-level %s advisory lock, "+
+			"and returns whether the lock was held.", modeName),
+	)...)
+}
+
 // typeBuiltinsHaveUnderscore is a map to keep track of which types have i/o
 // builtins with underscores in between their type name and the i/o builtin
 // name, like date_in vs int8in. There seems to be no other way to
@@ -78,6 +179,7 @@ var typeBuiltinsHaveUnderscore = map[oid.Oid]struct{}{
 	types.Interval.Oid():    {},
 	types.Json.Oid():        {},
 	types.Jsonb.Oid():       {},
+	types.Jsonpath.Oid():    {},
 	types.Uuid.Oid():        {},
 	types.VarBit.Oid():      {},
 	types.Geometry.Oid():    {},
@@ -201,6 +303,12 @@ func init() {
 			},
 		)
 	}
+	// Range and multirange types have constructor functions with the same name
+	// as their cast builtins.
+	for _, typ := range rangeAndMultirangeTypes {
+		def := castBuiltins[typ.Oid()]
+		def.overloads = append(def.overloads, makeRangeConstructorOverloads(typ)...)
+	}
 	for toOID, def := range castBuiltins {
 		n := cast.CastTypeName(types.OidToType[toOID])
 		CastBuiltinNames[n] = struct{}{}
@@ -1159,69 +1267,59 @@ var pgBuiltins = map[string]builtinDefinition{
 		},
 	),
 
-	"pg_try_advisory_lock": makeBuiltin(defProps(),
-		tree.Overload{
-			Types:      tree.ParamTypes{{Name: "int", Typ: types.Int}},
-			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ context.Context, _ *eval.Context, _ tree.Datums) (tree.Datum, error) {
-				return tree.DBoolTrue, nil
-			},
-			Info:       notUsableInfo,
-			Volatility: volatility.Volatile,
-		},
-	),
+	// See https://www.postgresql.org/docs/current/functions-admin.html#FUNCTIONS-ADVISORY-LOCKS.
+	"pg_advisory_lock":                 makeAdvisoryLockBuiltin(eval.AdvisoryLockExclusive, false /* xact */, false /* try */),
+	"pg_advisory_lock_shared":          makeAdvisoryLockBuiltin(eval.AdvisoryLockShared, false /* xact */, false /* try */),
+	"pg_advisory_xact_lock":            makeAdvisoryLockBuiltin(eval.AdvisoryLockExclusive, true /* xact */, false /* try */),
+	"pg_advisory_xact_lock_shared":     makeAdvisoryLockBuiltin(eval.AdvisoryLockShared, true /* xact */, false /* try */),
+	"pg_try_advisory_lock":             makeAdvisoryLockBuiltin(eval.AdvisoryLockExclusive, false /* xact */, true /* try */),
+	"pg_try_advisory_lock_shared":      makeAdvisoryLockBuiltin(eval.AdvisoryLockShared, false /* xact */, true /* try */),
+	"pg_try_advisory_xact_lock":        makeAdvisoryLockBuiltin(eval.AdvisoryLockExclusive, true /* xact */, true /* try */),
+	"pg_try_advisory_xact_lock_shared": makeAdvisoryLockBuiltin(eval.AdvisoryLockShared, true /* xact */, true /* try */),
+	"pg_advisory_unlock":               makeAdvisoryUnlockBuiltin(eval.AdvisoryLockExclusive),
+	"pg_advisory_unlock_shared":        makeAdvisoryUnlockBuiltin(eval.AdvisoryLockShared),
 
-	"pg_advisory_unlock": makeBuiltin(defProps(),
+	"pg_advisory_unlock_all": makeBuiltin(tree.FunctionProperties{DistsqlBlocklist: true},
 		tree.Overload{
-			Types:      tree.ParamTypes{{Name: "key", Typ: types.Int}},
-			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ context.Context, _ *eval.Context, _ tree.Datums) (tree.Datum, error) {
-				return tree.DBoolTrue, nil
-			},
-			Info:       notUsableInfo,
-			Volatility: volatility.Volatile,
-		},
-		tree.Overload{
-			Types:      tree.ParamTypes{{Name: "key1", Typ: types.Int4}, {Name: "key2", Typ: types.Int4}},
-			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ context.Context, _ *eval.Context, _ tree.Datums) (tree.Datum, error) {
-				return tree.DBoolTrue, nil
+			Types:      tree.ParamTypes{},
+			ReturnType: tree.FixedReturnType(types.Void),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, _ tree.Datums) (tree.Datum, error) {
+				if err := evalCtx.Planner.ReleaseAllAdvisoryLocks(ctx); err != nil {
+					return nil, err
+				}
+				return tree.DVoidDatum, nil
 			},
-			Info:       notUsableInfo,
+			Info:       "Releases all session-level advisory locks held by the current session.",
 			Volatility: volatility.Volatile,
 		},
 	),
 
-	"pg_advisory_unlock_shared": makeBuiltin(defProps(),
+	// See https://www.postgresql.org/docs/current/functions-info.html#FUNCTIONS-INFO-SESSION.
+	"pg_notify": makeBuiltin(defProps(),
 		tree.Overload{
-			Types:      tree.ParamTypes{{Name: "key", Typ: types.Int}},
-			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ context.Context, _ *eval.Context, _ tree.Datums) (tree.Datum, error) {
-				return tree.DBoolTrue, nil
-			},
-			Info:       notUsableInfo,
-			Volatility: volatility.Volatile,
-		},
-		tree.Overload{
-			Types:      tree.ParamTypes{{Name: "key1", Typ: types.Int4}, {Name: "key2", Typ: types.Int4}},
-			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ context.Context, _ *eval.Context, _ tree.Datums) (tree.Datum, error) {
-				return tree.DBoolTrue, nil
+			Types: tree.ParamTypes{
+				{Name: "channel", Typ: types.String},
+				{Name: "payload", Typ: types.String},
 			},
-			Info:       notUsableInfo,
-			Volatility: volatility.Volatile,
-		},
-	),
-
-	"pg_advisory_unlock_all": makeBuiltin(defProps(),
-		tree.Overload{
-			Types:      tree.ParamTypes{},
 			ReturnType: tree.FixedReturnType(types.Void),
-			Fn: func(_ context.Context, _ *eval.Context, _ tree.Datums) (tree.Datum, error) {
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				var channel, payload string
+				if args[0] != tree.DNull {
+					channel = string(tree.MustBeDString(args[0]))
+				}
+				if args[1] != tree.DNull {
+					payload = string(tree.MustBeDString(args[1]))
+				}
+				if err := evalCtx.Planner.Notify(ctx, channel, payload); err != nil {
+					return nil, err
+				}
 				return tree.DVoidDatum, nil
 			},
-			Info:       notUsableInfo,
-			Volatility: volatility.Volatile,
+			Info: "Sends a notification with the given payload to the sessions " +
+				"listening on the given channel of the current database. The " +
+				"notification is sent when the current transaction commits.",
+			Volatility:        volatility.Volatile,
+			CalledOnNullInput: true,
 		},
 	),
 
@@ -1734,8 +1832,8 @@ var pgBuiltins = map[string]builtinDefinition{
 				"TRUNCATE WITH GRANT OPTION":   {Kind: privilege.DELETE, GrantOption: true},
 				"REFERENCES":                   {Kind: privilege.SELECT},
 				"REFERENCES WITH GRANT OPTION": {Kind: privilege.SELECT, GrantOption: true},
-				"TRIGGER":                      {Kind: privilege.CREATE},
-				"TRIGGER WITH GRANT OPTION":    {Kind: privilege.CREATE, GrantOption: true},
+				"TRIGGER":                      {Kind: privilege.TRIGGER},
+				"TRIGGER WITH GRANT OPTION":    {Kind: privilege.TRIGGER, GrantOption: true},
 				"RULE":                         {Kind: privilege.RULE},
 				"RULE WITH GRANT OPTION":       {Kind: privilege.RULE, GrantOption: true},
 			})
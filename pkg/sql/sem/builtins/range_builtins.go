@@ -0,0 +1,260 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins/builtinconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+func init() {
+	for k, v := range rangeBuiltins {
+		v.props.Category = builtinconstants.CategoryRange
+		v.props.AvailableOnPublicSchema = true
+		const enforceClass = true
+		registerBuiltin(k, v, tree.NormalClass, enforceClass)
+	}
+}
+
+var rangeBuiltins = map[string]builtinDefinition{
+	"isempty": makeBuiltin(tree.FunctionProperties{},
+		makeRangeOverloads(
+			func(*types.T) *types.T { return types.Bool },
+			func(ranges []*tree.DRange) tree.Datum {
+				return tree.MakeDBool(tree.DBool(len(ranges) == 0))
+			},
+			"Returns whether the range or multirange is empty.",
+		)...,
+	),
+	"lower_inc": makeBuiltin(tree.FunctionProperties{},
+		makeRangeOverloads(
+			func(*types.T) *types.T { return types.Bool },
+			func(ranges []*tree.DRange) tree.Datum {
+				return tree.MakeDBool(tree.DBool(len(ranges) > 0 && ranges[0].LowerInc))
+			},
+			"Returns whether the lower bound of the range or multirange is inclusive.",
+		)...,
+	),
+	"upper_inc": makeBuiltin(tree.FunctionProperties{},
+		makeRangeOverloads(
+			func(*types.T) *types.T { return types.Bool },
+			func(ranges []*tree.DRange) tree.Datum {
+				return tree.MakeDBool(tree.DBool(len(ranges) > 0 && ranges[len(ranges)-1].UpperInc))
+			},
+			"Returns whether the upper bound of the range or multirange is inclusive.",
+		)...,
+	),
+	"lower_inf": makeBuiltin(tree.FunctionProperties{},
+		makeRangeOverloads(
+			func(*types.T) *types.T { return types.Bool },
+			func(ranges []*tree.DRange) tree.Datum {
+				return tree.MakeDBool(tree.DBool(len(ranges) > 0 && ranges[0].Lower == nil))
+			},
+			"Returns whether the lower bound of the range or multirange is infinite.",
+		)...,
+	),
+	"upper_inf": makeBuiltin(tree.FunctionProperties{},
+		makeRangeOverloads(
+			func(*types.T) *types.T { return types.Bool },
+			func(ranges []*tree.DRange) tree.Datum {
+				return tree.MakeDBool(tree.DBool(len(ranges) > 0 && ranges[len(ranges)-1].Upper == nil))
+			},
+			"Returns whether the upper bound of the range or multirange is infinite.",
+		)...,
+	),
+	"range_merge": makeBuiltin(tree.FunctionProperties{}, makeRangeMergeOverloads()...),
+}
+
+// rangeAndMultirangeTypes contains each range type followed by its
+// multirange type.
+var rangeAndMultirangeTypes = func() []*types.T {
+	res := make([]*types.T, 0, 2*len(types.RangeTypes))
+	for _, t := range types.RangeTypes {
+		res = append(res, t, types.MultirangeOf(t))
+	}
+	return res
+}()
+
+// makeRangeOverloads returns an overload of a function of a single range or
+// multirange for each range and multirange type. The function is given the
+// non-empty ranges of its argument, as returned by tree.RangesOf.
+func makeRangeOverloads(
+	retType func(argType *types.T) *types.T, fn func(ranges []*tree.DRange) tree.Datum, info string,
+) []tree.Overload {
+	res := make([]tree.Overload, 0, len(rangeAndMultirangeTypes))
+	for _, t := range rangeAndMultirangeTypes {
+		res = append(res, tree.Overload{
+			Types:      tree.ParamTypes{{Name: "val", Typ: t}},
+			ReturnType: tree.FixedReturnType(retType(t)),
+			Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return fn(tree.RangesOf(args[0])), nil
+			},
+			Info:       info,
+			Volatility: volatility.Immutable,
+		})
+	}
+	return res
+}
+
+// makeRangeBoundOverloads returns the overloads of the lower and upper
+// builtins for ranges and multiranges.
+func makeRangeBoundOverloads(upper bool) []tree.Overload {
+	info := "Returns the lower bound of the range or multirange, or NULL if it is empty or " +
+		"the bound is infinite."
+	if upper {
+		info = "Returns the upper bound of the range or multirange, or NULL if it is empty or " +
+			"the bound is infinite."
+	}
+	return makeRangeOverloads(
+		(*types.T).RangeContents,
+		func(ranges []*tree.DRange) tree.Datum {
+			if len(ranges) == 0 {
+				return tree.DNull
+			}
+			bound := ranges[0].Lower
+			if upper {
+				bound = ranges[len(ranges)-1].Upper
+			}
+			if bound == nil {
+				return tree.DNull
+			}
+			return bound
+		},
+		info,
+	)
+}
+
+func makeRangeMergeOverloads() []tree.Overload {
+	var res []tree.Overload
+	for _, t := range types.RangeTypes {
+		typ := t
+		res = append(res,
+			tree.Overload{
+				Types:      tree.ParamTypes{{Name: "a", Typ: typ}, {Name: "b", Typ: typ}},
+				ReturnType: tree.FixedReturnType(typ),
+				Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+					return tree.RangeMerge(tree.MustBeDRange(args[0]), tree.MustBeDRange(args[1]))
+				},
+				Info:       "Returns the smallest range that contains both ranges.",
+				Volatility: volatility.Immutable,
+			},
+			tree.Overload{
+				Types:      tree.ParamTypes{{Name: "val", Typ: types.MultirangeOf(typ)}},
+				ReturnType: tree.FixedReturnType(typ),
+				Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+					ranges := tree.MustBeDMultirange(args[0]).Ranges
+					if len(ranges) == 0 {
+						return tree.NewDEmptyRange(typ), nil
+					}
+					return tree.RangeMerge(ranges[0], ranges[len(ranges)-1])
+				},
+				Info:       "Returns the smallest range that contains all ranges of the multirange.",
+				Volatility: volatility.Immutable,
+			},
+		)
+	}
+	return res
+}
+
+// makeRangeConstructorOverloads returns the constructor functions of a range
+// or multirange type. These share their name with the cast builtins of the
+// type, so they are added to those in the initialization of the cast
+// builtins.
+func makeRangeConstructorOverloads(typ *types.T) []tree.Overload {
+	if typ.Family() == types.MultirangeFamily {
+		rangeTyp := types.MultirangeRangeType(typ)
+		construct := func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+			ranges := make([]*tree.DRange, 0, len(args))
+			for _, arg := range args {
+				if arg == tree.DNull {
+					return nil, pgerror.New(pgcode.NullValueNotAllowed,
+						"multirange values cannot contain null members")
+				}
+				ranges = append(ranges, tree.MustBeDRange(arg))
+			}
+			return tree.NewDMultirange(typ, ranges)
+		}
+		return []tree.Overload{
+			{
+				Types:      tree.ParamTypes{},
+				ReturnType: tree.FixedReturnType(typ),
+				Fn:         construct,
+				Info:       "Returns an empty multirange.",
+				Volatility: volatility.Immutable,
+			},
+			{
+				// A single range is converted to a multirange by the cast builtin of
+				// the same name, so this overload requires at least two ranges.
+				Types: tree.VariadicType{
+					FixedTypes: []*types.T{rangeTyp, rangeTyp},
+					VarType:    rangeTyp,
+				},
+				ReturnType:        tree.FixedReturnType(typ),
+				Fn:                construct,
+				CalledOnNullInput: true,
+				Info:              "Returns the multirange of the union of the given ranges.",
+				Volatility:        volatility.Immutable,
+			},
+		}
+	}
+	subtype := typ.RangeContents()
+	return []tree.Overload{
+		{
+			Types:             tree.ParamTypes{{Name: "lower", Typ: subtype}, {Name: "upper", Typ: subtype}},
+			ReturnType:        tree.FixedReturnType(typ),
+			CalledOnNullInput: true,
+			Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDRange(typ, args[0], args[1], true /* lowerInc */, false /* upperInc */)
+			},
+			Info: "Returns the range from `lower` (inclusive) to `upper` (exclusive). " +
+				"A NULL bound is infinite.",
+			Volatility: volatility.Immutable,
+		},
+		{
+			Types: tree.ParamTypes{
+				{Name: "lower", Typ: subtype},
+				{Name: "upper", Typ: subtype},
+				{Name: "bounds", Typ: types.String},
+			},
+			ReturnType:        tree.FixedReturnType(typ),
+			CalledOnNullInput: true,
+			Fn: func(_ context.Context, _ *eval.Context, args tree.Datums) (tree.Datum, error) {
+				if args[2] == tree.DNull {
+					return nil, pgerror.New(pgcode.DataException,
+						"range constructor flags argument must not be null")
+				}
+				lowerInc, upperInc, err := parseRangeBoundsFlags(string(tree.MustBeDString(args[2])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDRange(typ, args[0], args[1], lowerInc, upperInc)
+			},
+			Info: "Returns the range from `lower` to `upper`, where `bounds` is one of '[)', '[]', " +
+				"'(]' or '()' and specifies whether each bound is inclusive. A NULL bound is infinite.",
+			Volatility: volatility.Immutable,
+		},
+	}
+}
+
+// parseRangeBoundsFlags parses the bounds argument of a range constructor.
+func parseRangeBoundsFlags(s string) (lowerInc, upperInc bool, _ error) {
+	if len(s) != 2 || (s[0] != '[' && s[0] != '(') || (s[1] != ']' && s[1] != ')') {
+		return false, false, pgerror.New(pgcode.Syntax, "invalid range bound flags")
+	}
+	return s[0] == '[', s[1] == ']', nil
+}
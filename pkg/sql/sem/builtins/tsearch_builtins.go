@@ -12,6 +12,8 @@ package builtins
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -21,7 +23,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
+	"github.com/cockroachdb/errors"
 )
 
 func init() {
@@ -96,11 +100,14 @@ var tsearchBuiltins = map[string]builtinDefinition{
 		tree.Overload{
 			Types:      tree.ParamTypes{{Name: "config", Typ: types.String}, {Name: "text", Typ: types.String}},
 			ReturnType: tree.FixedReturnType(types.TSVector),
-			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
 				// Parse, stem, and stopword the input.
-				config := string(tree.MustBeDString(args[0]))
+				config, err := getTextSearchConfig(ctx, evalCtx, string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
 				document := string(tree.MustBeDString(args[1]))
-				vector, err := tsearch.DocumentToTSVector(config, document)
+				vector, err := config.DocumentToTSVector(document)
 				if err != nil {
 					return nil, err
 				}
@@ -113,10 +120,13 @@ var tsearchBuiltins = map[string]builtinDefinition{
 		tree.Overload{
 			Types:      tree.ParamTypes{{Name: "text", Typ: types.String}},
 			ReturnType: tree.FixedReturnType(types.TSVector),
-			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
-				config := tsearch.GetConfigKey(evalCtx.SessionData().DefaultTextSearchConfig)
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				config, err := getDefaultTextSearchConfig(ctx, evalCtx)
+				if err != nil {
+					return nil, err
+				}
 				document := string(tree.MustBeDString(args[0]))
-				vector, err := tsearch.DocumentToTSVector(config, document)
+				vector, err := config.DocumentToTSVector(document)
 				if err != nil {
 					return nil, err
 				}
@@ -132,10 +142,13 @@ var tsearchBuiltins = map[string]builtinDefinition{
 		tree.Overload{
 			Types:      tree.ParamTypes{{Name: "config", Typ: types.String}, {Name: "text", Typ: types.String}},
 			ReturnType: tree.FixedReturnType(types.TSQuery),
-			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
-				config := string(tree.MustBeDString(args[0]))
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				config, err := getTextSearchConfig(ctx, evalCtx, string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
 				input := string(tree.MustBeDString(args[1]))
-				query, err := tsearch.ToTSQuery(config, input)
+				query, err := config.ToTSQuery(input)
 				if err != nil {
 					return nil, err
 				}
@@ -149,10 +162,13 @@ var tsearchBuiltins = map[string]builtinDefinition{
 		tree.Overload{
 			Types:      tree.ParamTypes{{Name: "text", Typ: types.String}},
 			ReturnType: tree.FixedReturnType(types.TSQuery),
-			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
-				config := tsearch.GetConfigKey(evalCtx.SessionData().DefaultTextSearchConfig)
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				config, err := getDefaultTextSearchConfig(ctx, evalCtx)
+				if err != nil {
+					return nil, err
+				}
 				input := string(tree.MustBeDString(args[0]))
-				query, err := tsearch.ToTSQuery(config, input)
+				query, err := config.ToTSQuery(input)
 				if err != nil {
 					return nil, err
 				}
@@ -169,10 +185,13 @@ var tsearchBuiltins = map[string]builtinDefinition{
 		tree.Overload{
 			Types:      tree.ParamTypes{{Name: "config", Typ: types.String}, {Name: "text", Typ: types.String}},
 			ReturnType: tree.FixedReturnType(types.TSQuery),
-			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
-				config := string(tree.MustBeDString(args[0]))
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				config, err := getTextSearchConfig(ctx, evalCtx, string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
 				input := string(tree.MustBeDString(args[1]))
-				query, err := tsearch.PlainToTSQuery(config, input)
+				query, err := config.PlainToTSQuery(input)
 				if err != nil {
 					return nil, err
 				}
@@ -185,10 +204,13 @@ var tsearchBuiltins = map[string]builtinDefinition{
 		tree.Overload{
 			Types:      tree.ParamTypes{{Name: "text", Typ: types.String}},
 			ReturnType: tree.FixedReturnType(types.TSQuery),
-			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
-				config := tsearch.GetConfigKey(evalCtx.SessionData().DefaultTextSearchConfig)
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				config, err := getDefaultTextSearchConfig(ctx, evalCtx)
+				if err != nil {
+					return nil, err
+				}
 				input := string(tree.MustBeDString(args[0]))
-				query, err := tsearch.PlainToTSQuery(config, input)
+				query, err := config.PlainToTSQuery(input)
 				if err != nil {
 					return nil, err
 				}
@@ -204,10 +226,13 @@ var tsearchBuiltins = map[string]builtinDefinition{
 		tree.Overload{
 			Types:      tree.ParamTypes{{Name: "config", Typ: types.String}, {Name: "text", Typ: types.String}},
 			ReturnType: tree.FixedReturnType(types.TSQuery),
-			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
-				config := string(tree.MustBeDString(args[0]))
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				config, err := getTextSearchConfig(ctx, evalCtx, string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
 				input := string(tree.MustBeDString(args[1]))
-				query, err := tsearch.PhraseToTSQuery(config, input)
+				query, err := config.PhraseToTSQuery(input)
 				if err != nil {
 					return nil, err
 				}
@@ -220,10 +245,13 @@ var tsearchBuiltins = map[string]builtinDefinition{
 		tree.Overload{
 			Types:      tree.ParamTypes{{Name: "text", Typ: types.String}},
 			ReturnType: tree.FixedReturnType(types.TSQuery),
-			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
-				config := tsearch.GetConfigKey(evalCtx.SessionData().DefaultTextSearchConfig)
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				config, err := getDefaultTextSearchConfig(ctx, evalCtx)
+				if err != nil {
+					return nil, err
+				}
 				input := string(tree.MustBeDString(args[0]))
-				query, err := tsearch.PhraseToTSQuery(config, input)
+				query, err := config.PhraseToTSQuery(input)
 				if err != nil {
 					return nil, err
 				}
@@ -333,6 +361,192 @@ var tsearchBuiltins = map[string]builtinDefinition{
 			Volatility: volatility.Immutable,
 		},
 	),
+	"websearch_to_tsquery": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "config", Typ: types.String}, {Name: "text", Typ: types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				config, err := getTextSearchConfig(ctx, evalCtx, string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				input := string(tree.MustBeDString(args[1]))
+				query, err := config.WebSearchToTSQuery(input)
+				if err != nil {
+					return nil, err
+				}
+				return &tree.DTSQuery{TSQuery: query}, nil
+			},
+			Info: "Converts text to a tsquery, normalizing words according to the specified configuration. " +
+				"The input uses the syntax of web search engines: quoted phrases are converted to <->, " +
+				"the word or to |, a leading - to !, and the other words are combined with &.",
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "text", Typ: types.String}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				config, err := getDefaultTextSearchConfig(ctx, evalCtx)
+				if err != nil {
+					return nil, err
+				}
+				input := string(tree.MustBeDString(args[0]))
+				query, err := config.WebSearchToTSQuery(input)
+				if err != nil {
+					return nil, err
+				}
+				return &tree.DTSQuery{TSQuery: query}, nil
+			},
+			Info: "Converts text to a tsquery, normalizing words according to the default configuration. " +
+				"The input uses the syntax of web search engines: quoted phrases are converted to <->, " +
+				"the word or to |, a leading - to !, and the other words are combined with &.",
+			Volatility: volatility.Stable,
+		},
+	),
+	"ts_headline": makeBuiltin(
+		tree.FunctionProperties{},
+		makeTSHeadlineOverload(true /* hasConfig */, true /* hasOptions */),
+		makeTSHeadlineOverload(true /* hasConfig */, false /* hasOptions */),
+		makeTSHeadlineOverload(false /* hasConfig */, true /* hasOptions */),
+		makeTSHeadlineOverload(false /* hasConfig */, false /* hasOptions */),
+	),
+	"ts_lexize": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "dict", Typ: types.String}, {Name: "token", Typ: types.String}},
+			ReturnType: tree.FixedReturnType(types.StringArray),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				dict, err := getTextSearchDictionary(ctx, evalCtx, string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				lexeme, ok, err := dict.Lexize(string(tree.MustBeDString(args[1])))
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					return tree.DNull, nil
+				}
+				arr := tree.NewDArray(types.String)
+				if lexeme != "" {
+					if err := arr.Append(tree.NewDString(lexeme)); err != nil {
+						return nil, err
+					}
+				}
+				return arr, nil
+			},
+			Info: "Normalizes the token with the specified dictionary. Returns an array with the lexeme " +
+				"of the token, an empty array if the token is a stop word, or NULL if the dictionary " +
+				"doesn't recognize the token.",
+			Volatility: volatility.Stable,
+		},
+	),
+	"setweight": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "vector", Typ: types.TSVector}, {Name: "weight", Typ: types.QChar}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				vector := tree.MustBeDTSVector(args[0]).TSVector
+				ret, err := vector.SetWeight(string(tree.MustBeDString(args[1])), nil /* lexemes */)
+				if err != nil {
+					return nil, err
+				}
+				return &tree.DTSVector{TSVector: ret}, nil
+			},
+			Info:       "Assigns the given weight to each element of the input vector.",
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "vector", Typ: types.TSVector},
+				{Name: "weight", Typ: types.QChar},
+				{Name: "lexemes", Typ: types.StringArray},
+			},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				vector := tree.MustBeDTSVector(args[0]).TSVector
+				lexemes, err := getLexemes(tree.MustBeDArray(args[2]))
+				if err != nil {
+					return nil, err
+				}
+				if lexemes == nil {
+					lexemes = []string{}
+				}
+				ret, err := vector.SetWeight(string(tree.MustBeDString(args[1])), lexemes)
+				if err != nil {
+					return nil, err
+				}
+				return &tree.DTSVector{TSVector: ret}, nil
+			},
+			Info:       "Assigns the given weight to the elements of the input vector that are listed in lexemes.",
+			Volatility: volatility.Immutable,
+		},
+	),
+	"strip": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "vector", Typ: types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				vector := tree.MustBeDTSVector(args[0]).TSVector
+				return &tree.DTSVector{TSVector: vector.Strip()}, nil
+			},
+			Info:       "Removes positions and weights from the input vector.",
+			Volatility: volatility.Immutable,
+		},
+	),
+	"array_to_tsvector": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "lexemes", Typ: types.StringArray}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				lexemes, err := getLexemes(tree.MustBeDArray(args[0]))
+				if err != nil {
+					return nil, err
+				}
+				vector, err := tsearch.ArrayToTSVector(lexemes)
+				if err != nil {
+					return nil, err
+				}
+				return &tree.DTSVector{TSVector: vector}, nil
+			},
+			Info: "Converts an array of lexemes to a tsvector. The given strings are used as-is " +
+				"without further processing.",
+			Volatility: volatility.Immutable,
+		},
+	),
+	"json_to_tsvector":  makeJSONToTSVectorBuiltin(),
+	"jsonb_to_tsvector": makeJSONToTSVectorBuiltin(),
+	"querytree": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "query", Typ: types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				query := tree.MustBeDTSQuery(args[0]).TSQuery
+				return tree.NewDString(query.QueryTree()), nil
+			},
+			Info: "Returns the portion of a tsquery that can be used for searching an index, " +
+				"which is the query without its negations, or T if the query cannot be used.",
+			Volatility: volatility.Immutable,
+		},
+	),
+	"numnode": makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types:      tree.ParamTypes{{Name: "query", Typ: types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				query := tree.MustBeDTSQuery(args[0]).TSQuery
+				return tree.NewDInt(tree.DInt(query.NumNodes())), nil
+			},
+			Info:       "Returns the number of lexemes plus operators in a tsquery.",
+			Volatility: volatility.Immutable,
+		},
+	),
 }
 
 func getWeights(arr *tree.DArray) ([]float32, error) {
@@ -348,3 +562,261 @@ func getWeights(arr *tree.DArray) ([]float32, error) {
 	}
 	return ret, nil
 }
+
+// getTextSearchConfig returns the text search configuration with the given
+// name. Configurations that are not built in are resolved in the current
+// database by the planner.
+func getTextSearchConfig(
+	ctx context.Context, evalCtx *eval.Context, name string,
+) (*tsearch.Config, error) {
+	if tsearch.IsBuiltinConfig(name) || evalCtx.Planner == nil {
+		return tsearch.GetBuiltinConfig(name)
+	}
+	return evalCtx.Planner.ResolveTextSearchConfig(ctx, name)
+}
+
+// getDefaultTextSearchConfig returns the text search configuration of the
+// default_text_search_config session variable.
+func getDefaultTextSearchConfig(ctx context.Context, evalCtx *eval.Context) (*tsearch.Config, error) {
+	return getTextSearchConfig(ctx, evalCtx, evalCtx.SessionData().DefaultTextSearchConfig)
+}
+
+// getTextSearchDictionary returns the text search dictionary with the given
+// name. Dictionaries that are not built in are resolved in the current
+// database by the planner.
+func getTextSearchDictionary(
+	ctx context.Context, evalCtx *eval.Context, name string,
+) (*tsearch.Dictionary, error) {
+	if tsearch.IsBuiltinDictionary(name) || evalCtx.Planner == nil {
+		return tsearch.GetBuiltinDictionary(name)
+	}
+	return evalCtx.Planner.ResolveTextSearchDictionary(ctx, name)
+}
+
+// getLexemes returns the strings of an array of lexemes, which must not
+// contain NULLs.
+func getLexemes(arr *tree.DArray) ([]string, error) {
+	var ret []string
+	for _, d := range arr.Array {
+		if d == tree.DNull {
+			return nil, pgerror.New(pgcode.NullValueNotAllowed, "lexeme array may not contain nulls")
+		}
+		ret = append(ret, string(tree.MustBeDString(d)))
+	}
+	return ret, nil
+}
+
+// makeTSHeadlineOverload returns an overload of ts_headline, which optionally
+// takes the text search configuration as its first parameter and the headline
+// options as its last parameter.
+func makeTSHeadlineOverload(hasConfig, hasOptions bool) tree.Overload {
+	var params tree.ParamTypes
+	if hasConfig {
+		params = append(params, tree.ParamType{Name: "config", Typ: types.String})
+	}
+	params = append(params,
+		tree.ParamType{Name: "document", Typ: types.String},
+		tree.ParamType{Name: "query", Typ: types.TSQuery},
+	)
+	if hasOptions {
+		params = append(params, tree.ParamType{Name: "options", Typ: types.String})
+	}
+	info := "Returns an excerpt of the document in which the words that match the query are " +
+		"highlighted. The words are normalized according to the "
+	volatile := volatility.Immutable
+	if hasConfig {
+		info += "specified configuration."
+	} else {
+		info += "default configuration."
+		volatile = volatility.Stable
+	}
+	if hasOptions {
+		info += " The options are a comma-separated list of option=value pairs among MaxWords, " +
+			"MinWords, ShortWord, HighlightAll, MaxFragments, StartSel, StopSel and FragmentDelimiter."
+	}
+	return tree.Overload{
+		Types:      params,
+		ReturnType: tree.FixedReturnType(types.String),
+		Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+			var config *tsearch.Config
+			var err error
+			if hasConfig {
+				config, err = getTextSearchConfig(ctx, evalCtx, string(tree.MustBeDString(args[0])))
+				args = args[1:]
+			} else {
+				config, err = getDefaultTextSearchConfig(ctx, evalCtx)
+			}
+			if err != nil {
+				return nil, err
+			}
+			opts := tsearch.DefaultHeadlineOptions()
+			if hasOptions {
+				opts, err = tsearch.ParseHeadlineOptions(string(tree.MustBeDString(args[2])))
+				if err != nil {
+					return nil, err
+				}
+			}
+			headline, err := config.Headline(
+				string(tree.MustBeDString(args[0])), tree.MustBeDTSQuery(args[1]).TSQuery, opts,
+			)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDString(headline), nil
+		},
+		Info:       info,
+		Volatility: volatile,
+	}
+}
+
+// jsonToTSVectorFilterHint lists the elements of the filter of
+// json_to_tsvector.
+const jsonToTSVectorFilterHint = `Possible values are: "string", "numeric", "boolean", "key", and "all".`
+
+// jsonToTSVectorFilter determines which parts of a JSON document are converted
+// to a tsvector by json_to_tsvector.
+type jsonToTSVectorFilter struct {
+	strings, numerics, booleans, keys bool
+}
+
+// parseJSONToTSVectorFilter parses the filter of json_to_tsvector, which is
+// either one of the strings listed in jsonToTSVectorFilterHint, or an array of
+// them.
+func parseJSONToTSVectorFilter(j json.JSON) (jsonToTSVectorFilter, error) {
+	var f jsonToTSVectorFilter
+	elems, ok := j.AsArray()
+	if !ok {
+		if j.Type() == json.ObjectJSONType {
+			return f, pgerror.New(pgcode.InvalidParameterValue,
+				"wrong flag type, only arrays and scalars are allowed")
+		}
+		elems = []json.JSON{j}
+	}
+	for _, elem := range elems {
+		if elem.Type() != json.StringJSONType {
+			return f, errors.WithHint(pgerror.New(pgcode.InvalidParameterValue,
+				"flag array element is not a string"), jsonToTSVectorFilterHint)
+		}
+		s, err := elem.AsText()
+		if err != nil {
+			return f, err
+		}
+		switch strings.ToLower(*s) {
+		case "string":
+			f.strings = true
+		case "numeric":
+			f.numerics = true
+		case "boolean":
+			f.booleans = true
+		case "key":
+			f.keys = true
+		case "all":
+			f = jsonToTSVectorFilter{strings: true, numerics: true, booleans: true, keys: true}
+		default:
+			return f, errors.WithHint(pgerror.Newf(pgcode.InvalidParameterValue,
+				"wrong flag in flag array: %q", *s), jsonToTSVectorFilterHint)
+		}
+	}
+	return f, nil
+}
+
+// appendJSONDocuments appends to docs the strings of the JSON document that
+// are selected by the filter, in the order in which they appear.
+func (f jsonToTSVectorFilter) appendJSONDocuments(docs []string, j json.JSON) ([]string, error) {
+	switch j.Type() {
+	case json.StringJSONType, json.NumberJSONType, json.TrueJSONType, json.FalseJSONType:
+		if (j.Type() == json.StringJSONType && !f.strings) ||
+			(j.Type() == json.NumberJSONType && !f.numerics) ||
+			((j.Type() == json.TrueJSONType || j.Type() == json.FalseJSONType) && !f.booleans) {
+			return docs, nil
+		}
+		s, err := j.AsText()
+		if err != nil {
+			return nil, err
+		}
+		return append(docs, *s), nil
+	case json.ArrayJSONType:
+		elems, _ := j.AsArray()
+		for _, elem := range elems {
+			var err error
+			if docs, err = f.appendJSONDocuments(docs, elem); err != nil {
+				return nil, err
+			}
+		}
+	case json.ObjectJSONType:
+		iter, err := j.ObjectIter()
+		if err != nil {
+			return nil, err
+		}
+		for iter.Next() {
+			if f.keys {
+				docs = append(docs, iter.Key())
+			}
+			if docs, err = f.appendJSONDocuments(docs, iter.Value()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return docs, nil
+}
+
+// makeJSONToTSVectorBuiltin returns the definition of json_to_tsvector and
+// jsonb_to_tsvector, which convert the parts of a JSON document selected by a
+// filter to a tsvector.
+func makeJSONToTSVectorBuiltin() builtinDefinition {
+	const info = "Converts the strings of a JSON document that are selected by the filter to a " +
+		"tsvector, normalizing words according to the %s configuration. The filter is a JSON " +
+		"string, or an array of JSON strings, among string (all string values), numeric (all " +
+		"numeric values), boolean (all boolean values), key (all keys) and all."
+	fn := func(config *tsearch.Config, doc, filter tree.Datum) (tree.Datum, error) {
+		f, err := parseJSONToTSVectorFilter(tree.MustBeDJSON(filter).JSON)
+		if err != nil {
+			return nil, err
+		}
+		docs, err := f.appendJSONDocuments(nil /* docs */, tree.MustBeDJSON(doc).JSON)
+		if err != nil {
+			return nil, err
+		}
+		vector, err := config.DocumentsToTSVector(docs)
+		if err != nil {
+			return nil, err
+		}
+		return &tree.DTSVector{TSVector: vector}, nil
+	}
+	return makeBuiltin(
+		tree.FunctionProperties{},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "config", Typ: types.String},
+				{Name: "document", Typ: types.Jsonb},
+				{Name: "filter", Typ: types.Jsonb},
+			},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				config, err := getTextSearchConfig(ctx, evalCtx, string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return fn(config, args[1], args[2])
+			},
+			Info:       fmt.Sprintf(info, "specified"),
+			Volatility: volatility.Immutable,
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "document", Typ: types.Jsonb},
+				{Name: "filter", Typ: types.Jsonb},
+			},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				config, err := getDefaultTextSearchConfig(ctx, evalCtx)
+				if err != nil {
+					return nil, err
+				}
+				return fn(config, args[0], args[1])
+			},
+			Info:       fmt.Sprintf(info, "default"),
+			Volatility: volatility.Stable,
+		},
+	)
+}
@@ -333,6 +333,16 @@ func newFramableAggregateWindow(
 	}
 }
 
+// NewFramableAggregateWindowFunc creates a constructor of
+// framableAggregateWindowFunc with the given aggregate function constructor.
+func NewFramableAggregateWindowFunc(
+	aggConstructor func(*eval.Context, tree.Datums) eval.AggregateFunc,
+) func(*eval.Context) eval.WindowFunc {
+	return func(evalCtx *eval.Context) eval.WindowFunc {
+		return newFramableAggregateWindow(aggConstructor(evalCtx, nil /* arguments */), aggConstructor)
+	}
+}
+
 func (w *framableAggregateWindowFunc) Compute(
 	ctx context.Context, evalCtx *eval.Context, wfr *eval.WindowFrameRun,
 ) (tree.Datum, error) {
@@ -205,6 +205,8 @@ const (
 	CrdbInternalRepairableCatalogCorruptionsViewID
 	CrdbInternalKVProtectedTS
 	CrdbInternalKVSessionBasedLeases
+	CrdbInternalClusterAdvisoryLocksTableID
+	CrdbInternalNodeAdvisoryLocksTableID
 	InformationSchemaID
 	InformationSchemaAdministrableRoleAuthorizationsID
 	InformationSchemaApplicableRolesID
@@ -443,6 +445,8 @@ const (
 	ConstraintTypeCheck ConstraintType = "CHECK"
 	// ConstraintTypeUniqueWithoutIndex identifies a UNIQUE_WITHOUT_INDEX constraint.
 	ConstraintTypeUniqueWithoutIndex ConstraintType = "UNIQUE WITHOUT INDEX"
+	// ConstraintTypeExclusion identifies an EXCLUDE constraint.
+	ConstraintTypeExclusion ConstraintType = "EXCLUDE"
 )
 
 // SafeValue implements the redact.SafeValue interface.
@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/trigram"
 	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
@@ -142,6 +143,12 @@ func (e *evaluator) EvalCompareBox2DOp(
 	return tree.MakeDBool(tree.DBool(op.Op(left, right))), nil
 }
 
+func (e *evaluator) EvalCompareRangeOp(
+	ctx context.Context, op *tree.CompareRangeOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	return tree.MakeDBool(tree.DBool(op.Op(left, right))), nil
+}
+
 func (e *evaluator) EvalCompareScalarOp(
 	ctx context.Context, op *tree.CompareScalarOp, left, right tree.Datum,
 ) (tree.Datum, error) {
@@ -570,6 +577,35 @@ func (e *evaluator) EvalJSONAllExistsOp(
 	return tree.DBoolTrue, nil
 }
 
+// EvalJSONPathExistsOp evaluates the @? operator. Like Postgres, errors
+// caused by the contents of the document are suppressed, and make the result
+// NULL.
+func (e *evaluator) EvalJSONPathExistsOp(
+	ctx context.Context, _ *tree.JSONPathExistsOp, a, b tree.Datum,
+) (tree.Datum, error) {
+	exists, isNull, err := jsonpath.EvalExists(
+		tree.MustBeDJsonpath(b).Jsonpath, tree.MustBeDJSON(a).JSON, nil /* vars */, true, /* silent */
+	)
+	if err != nil || isNull {
+		return tree.DNull, err
+	}
+	return tree.MakeDBool(tree.DBool(exists)), nil
+}
+
+// EvalJSONPathMatchOp evaluates the @@ operator with jsonb and jsonpath
+// operands. Errors are suppressed in the same way as for @?.
+func (e *evaluator) EvalJSONPathMatchOp(
+	ctx context.Context, _ *tree.JSONPathMatchOp, a, b tree.Datum,
+) (tree.Datum, error) {
+	match, isNull, err := jsonpath.EvalMatch(
+		tree.MustBeDJsonpath(b).Jsonpath, tree.MustBeDJSON(a).JSON, nil /* vars */, true, /* silent */
+	)
+	if err != nil || isNull {
+		return tree.DNull, err
+	}
+	return tree.MakeDBool(tree.DBool(match)), nil
+}
+
 func (e *evaluator) EvalJSONExistsOp(
 	ctx context.Context, _ *tree.JSONExistsOp, a, b tree.Datum,
 ) (tree.Datum, error) {
@@ -1543,6 +1579,12 @@ func (e *evaluator) EvalRShiftVarBitIntOp(
 	}, nil
 }
 
+func (e *evaluator) EvalRangeSetOp(
+	ctx context.Context, op *tree.RangeSetOp, left, right tree.Datum,
+) (tree.Datum, error) {
+	return op.Op(left, right)
+}
+
 func (e *evaluator) EvalSimilarToOp(
 	ctx context.Context, op *tree.SimilarToOp, left, right tree.Datum,
 ) (tree.Datum, error) {
@@ -101,6 +101,9 @@ func PerformAssignmentCast(
 func performCast(
 	ctx context.Context, evalCtx *Context, d tree.Datum, t *types.T, truncateWidth bool,
 ) (tree.Datum, error) {
+	if t.IsDomain() {
+		return performDomainCast(ctx, evalCtx, d, t, truncateWidth)
+	}
 	d, err := performCastWithoutPrecisionTruncation(ctx, evalCtx, d, t, truncateWidth)
 	if err != nil {
 		return nil, err
@@ -467,6 +470,13 @@ func performCastWithoutPrecisionTruncation(
 				tree.FmtDataConversionConfig(evalCtx.SessionData().DataConversionConfig),
 				tree.FmtLocation(evalCtx.GetLocation()),
 			)
+		case *tree.DRange, *tree.DMultirange:
+			s = tree.AsStringWithFlags(
+				d,
+				tree.FmtPgwireText,
+				tree.FmtDataConversionConfig(evalCtx.SessionData().DataConversionConfig),
+				tree.FmtLocation(evalCtx.GetLocation()),
+			)
 		case *tree.DInterval:
 			// When converting an interval to string, we need a string representation
 			// of the duration (e.g. "5s") and not of the interval itself (e.g.
@@ -500,6 +510,8 @@ func performCastWithoutPrecisionTruncation(
 			s = t.JSON.String()
 		case *tree.DTSQuery:
 			s = t.TSQuery.String()
+		case *tree.DJsonpath:
+			s = t.Jsonpath.String()
 		case *tree.DTSVector:
 			s = t.TSVector.String()
 		case *tree.DEnum:
@@ -920,6 +932,40 @@ func performCastWithoutPrecisionTruncation(
 			}
 			return &tree.DTSVector{TSVector: vec}, nil
 		}
+	case types.JsonpathFamily:
+		if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.V24_1_JsonpathType) {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"version %v must be finalized to use jsonpath",
+				clusterversion.V24_1_JsonpathType.Version())
+		}
+		switch v := d.(type) {
+		case *tree.DString:
+			return tree.ParseDJsonpath(string(*v))
+		}
+	case types.RangeFamily:
+		if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.V24_1_RangeTypes) {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"version %v must be finalized to use range types",
+				clusterversion.V24_1_RangeTypes.Version())
+		}
+		switch v := d.(type) {
+		case *tree.DString:
+			res, _, err := tree.ParseDRange(evalCtx, string(*v), t)
+			return res, err
+		}
+	case types.MultirangeFamily:
+		if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.V24_1_RangeTypes) {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"version %v must be finalized to use range types",
+				clusterversion.V24_1_RangeTypes.Version())
+		}
+		switch v := d.(type) {
+		case *tree.DString:
+			res, _, err := tree.ParseDMultirange(evalCtx, string(*v), t)
+			return res, err
+		case *tree.DRange:
+			return tree.NewDMultirange(t, []*tree.DRange{v})
+		}
 	case types.ArrayFamily:
 		switch v := d.(type) {
 		case *tree.DString:
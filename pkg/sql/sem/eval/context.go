@@ -184,6 +184,7 @@ type Context struct {
 
 	ReCache           *tree.RegexpCache
 	ToCharFormatCache *tochar.FormatCache
+	DomainCheckCache  *DomainCheckCache
 
 	// TODO(mjibson): remove prepareOnly in favor of a 2-step prepare-exec solution
 	// that is also able to save the plan to skip work during the exec step.
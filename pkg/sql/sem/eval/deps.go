@@ -32,6 +32,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/rangedesc"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/lib/pq/oid"
 )
 
@@ -426,11 +427,90 @@ type Planner interface {
 	// PLpgSQL FETCH statement.
 	PLpgSQLFetchCursor(ctx context.Context, cursor *tree.CursorStmt) (res tree.Datums, err error)
 
+	// Notify sends a notification with the given payload on the given channel
+	// when the current transaction commits. It is used to implement
+	// pg_notify().
+	Notify(ctx context.Context, channel, payload string) error
+
+	// ListeningChannels returns the names of the channels the session is
+	// listening on. It is used to implement pg_listening_channels().
+	ListeningChannels() []string
+
+	// AcquireAdvisoryLock acquires the advisory lock with the given key in the
+	// current database on behalf of the session. Session-level locks are held
+	// until they are released, and transaction-level locks until the current
+	// transaction ends. If wait is false and the lock is held in a conflicting
+	// mode by another session, it returns false instead of blocking. It is used
+	// to implement the pg_advisory_lock family of functions.
+	AcquireAdvisoryLock(
+		ctx context.Context, key AdvisoryLockKey, mode AdvisoryLockMode, xact bool, wait bool,
+	) (bool, error)
+
+	// ReleaseAdvisoryLock releases one hold of the session-level advisory lock
+	// with the given key. It returns false if the session doesn't hold the lock
+	// in the given mode. It is used to implement pg_advisory_unlock().
+	ReleaseAdvisoryLock(ctx context.Context, key AdvisoryLockKey, mode AdvisoryLockMode) (bool, error)
+
+	// ReleaseAllAdvisoryLocks releases all the session-level advisory locks
+	// held by the session. It is used to implement pg_advisory_unlock_all().
+	ReleaseAllAdvisoryLocks(ctx context.Context) error
+
+	// ResolveTextSearchConfig returns the text search configuration with the
+	// given name, which is either a built-in configuration or a user-defined
+	// configuration of the current database.
+	ResolveTextSearchConfig(ctx context.Context, name string) (*tsearch.Config, error)
+
+	// ResolveTextSearchDictionary returns the text search dictionary with the
+	// given name, which is either a built-in dictionary or a user-defined
+	// dictionary of the current database.
+	ResolveTextSearchDictionary(ctx context.Context, name string) (*tsearch.Dictionary, error)
+
 	// AutoCommit indicates whether the Planner has flagged the current statement
 	// as eligible for transaction auto-commit.
 	AutoCommit() bool
 }
 
+// AdvisoryLockKey identifies an advisory lock within a database. Its fields
+// match the columns that identify advisory locks in Postgres' pg_locks: a
+// bigint key is split into ClassID (high 32 bits) and ObjID (low 32 bits) with
+// ObjSubID 1, and a pair of int4 keys is stored in ClassID and ObjID with
+// ObjSubID 2, so the two kinds of keys never conflict.
+type AdvisoryLockKey struct {
+	ClassID  uint32
+	ObjID    uint32
+	ObjSubID uint16
+}
+
+// MakeAdvisoryLockKey returns the AdvisoryLockKey for a bigint key.
+func MakeAdvisoryLockKey(key int64) AdvisoryLockKey {
+	return AdvisoryLockKey{ClassID: uint32(uint64(key) >> 32), ObjID: uint32(key), ObjSubID: 1}
+}
+
+// MakeAdvisoryLockKeyPair returns the AdvisoryLockKey for a pair of int4 keys.
+func MakeAdvisoryLockKeyPair(key1, key2 int32) AdvisoryLockKey {
+	return AdvisoryLockKey{ClassID: uint32(key1), ObjID: uint32(key2), ObjSubID: 2}
+}
+
+// AdvisoryLockMode is the mode in which an advisory lock is held.
+type AdvisoryLockMode uint8
+
+const (
+	// AdvisoryLockExclusive locks conflict with all other locks on the same key
+	// held by other sessions.
+	AdvisoryLockExclusive AdvisoryLockMode = iota
+	// AdvisoryLockShared locks only conflict with exclusive locks on the same
+	// key held by other sessions.
+	AdvisoryLockShared
+)
+
+// String returns the name of the lock mode, as shown in pg_locks.
+func (m AdvisoryLockMode) String() string {
+	if m == AdvisoryLockShared {
+		return "ShareLock"
+	}
+	return "ExclusiveLock"
+}
+
 // InternalRows is an iterator interface that's exposed by the internal
 // executor. It provides access to the rows from a query.
 // InternalRows is a copy of the one in sql/internal.go excluding the
@@ -0,0 +1,214 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package eval
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// DomainValueName is the name used to reference the value being checked in
+// the CHECK constraints of a domain.
+const DomainValueName = "value"
+
+// performDomainCast casts d to the base type of the domain t, then checks that
+// the result satisfies the constraints of the domain.
+func performDomainCast(
+	ctx context.Context, evalCtx *Context, d tree.Datum, t *types.T, truncateWidth bool,
+) (tree.Datum, error) {
+	d, err := performCast(ctx, evalCtx, d, t.DomainBaseType(), truncateWidth)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckDomainConstraints(ctx, evalCtx, d, t); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// CheckDomainConstraints returns an error if d, which must be a value of the
+// base type of the domain t, doesn't satisfy the NOT NULL and CHECK
+// constraints of t. As in Postgres, a CHECK constraint is only violated if it
+// evaluates to false.
+func CheckDomainConstraints(
+	ctx context.Context, evalCtx *Context, d tree.Datum, t *types.T,
+) error {
+	domain := t.TypeMeta.DomainData
+	if domain == nil {
+		return errors.AssertionFailedf("domain %s is not hydrated", t.SQLStringForError())
+	}
+	if domain.NotNull && d == tree.DNull {
+		return pgerror.Newf(pgcode.NotNullViolation,
+			"domain %s does not allow null values", t.Name())
+	}
+	for i := range domain.CheckConstraints {
+		c := &domain.CheckConstraints[i]
+		ok, err := evalDomainCheckConstraint(ctx, evalCtx, d, t, i)
+		if err != nil {
+			return errors.Wrapf(err, "evaluating constraint %q of domain %s", c.Name, t.Name())
+		}
+		if !ok {
+			return pgerror.Newf(pgcode.CheckViolation,
+				"value for domain %s violates check constraint %q", t.Name(), c.Name)
+		}
+	}
+	return nil
+}
+
+// evalDomainCheckConstraint evaluates the i-th CHECK constraint of the domain
+// t with VALUE bound to d. It returns false only if the expression evaluates
+// to false.
+func evalDomainCheckConstraint(
+	ctx context.Context, evalCtx *Context, d tree.Datum, t *types.T, i int,
+) (bool, error) {
+	typedExpr, err := evalCtx.DomainCheckCache.lookup(ctx, evalCtx, t, i)
+	if err != nil {
+		return false, err
+	}
+	evalCtx.PushIVarContainer(&domainValueContainer{typ: t.DomainBaseType(), d: d})
+	defer evalCtx.PopIVarContainer()
+	res, err := Expr(ctx, evalCtx, typedExpr)
+	if err != nil {
+		return false, err
+	}
+	return res != tree.DBoolFalse, nil
+}
+
+// typeCheckDomainCheckConstraint parses and type-checks the i-th CHECK
+// constraint of the domain t. VALUE is replaced with an ordinal reference,
+// which is bound to the value being checked during evaluation.
+func typeCheckDomainCheckConstraint(
+	ctx context.Context, evalCtx *Context, t *types.T, i int,
+) (tree.TypedExpr, error) {
+	expr, err := parser.ParseExpr(t.TypeMeta.DomainData.CheckConstraints[i].Expr)
+	if err != nil {
+		return nil, err
+	}
+	expr, err = ReplaceDomainValue(expr, tree.NewOrdinalReference(0))
+	if err != nil {
+		return nil, err
+	}
+	semaCtx := tree.MakeSemaContext()
+	semaCtx.IVarContainer = &domainValueContainer{typ: t.DomainBaseType()}
+	if evalCtx.Planner != nil {
+		semaCtx.TypeResolver = evalCtx.Planner
+		semaCtx.FunctionResolver = evalCtx.Planner
+	}
+	return tree.TypeCheck(ctx, expr, &semaCtx, types.Bool)
+}
+
+// domainValueContainer binds the ordinal reference that replaces VALUE in the
+// CHECK constraints of a domain.
+type domainValueContainer struct {
+	typ *types.T
+	d   tree.Datum
+}
+
+var _ IndexedVarContainer = &domainValueContainer{}
+
+// IndexedVarEval is part of the IndexedVarContainer interface.
+func (c *domainValueContainer) IndexedVarEval(
+	ctx context.Context, idx int, e tree.ExprEvaluator,
+) (tree.Datum, error) {
+	return c.d, nil
+}
+
+// IndexedVarResolvedType is part of the tree.IndexedVarContainer interface.
+func (c *domainValueContainer) IndexedVarResolvedType(idx int) *types.T {
+	return c.typ
+}
+
+// IndexedVarNodeFormatter is part of the tree.IndexedVarContainer interface.
+func (c *domainValueContainer) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	n := tree.Name(DomainValueName)
+	return &n
+}
+
+// domainCheckCacheKey identifies a CHECK constraint of a version of a domain.
+type domainCheckCacheKey struct {
+	typeOID oid.Oid
+	version uint32
+	idx     int
+}
+
+// DomainCheckCache is a cache of the type-checked CHECK constraints of
+// domains, so that they are not parsed and type-checked for every value. The
+// constraints are keyed by the version of the domain descriptor, which
+// changes whenever the constraints do. It is thread safe, and is safe to use
+// by nil caches.
+type DomainCheckCache struct {
+	// mu must be a Mutex, not a RWMutex because Get can modify the LRU cache.
+	mu struct {
+		syncutil.Mutex
+		cache *cache.UnorderedCache
+	}
+}
+
+// NewDomainCheckCache returns a new DomainCheckCache.
+func NewDomainCheckCache(size int) *DomainCheckCache {
+	ret := &DomainCheckCache{}
+	ret.mu.cache = cache.NewUnorderedCache(cache.Config{
+		Policy: cache.CacheLRU,
+		ShouldEvict: func(s int, key, value interface{}) bool {
+			return s > size
+		},
+	})
+	return ret
+}
+
+func (dc *DomainCheckCache) lookup(
+	ctx context.Context, evalCtx *Context, t *types.T, i int,
+) (tree.TypedExpr, error) {
+	if dc == nil {
+		return typeCheckDomainCheckConstraint(ctx, evalCtx, t, i)
+	}
+	key := domainCheckCacheKey{typeOID: t.DomainOID(), version: t.TypeMeta.Version, idx: i}
+	if ret, ok := func() (tree.TypedExpr, bool) {
+		dc.mu.Lock()
+		defer dc.mu.Unlock()
+		ret, ok := dc.mu.cache.Get(key)
+		if ok {
+			return ret.(tree.TypedExpr), true
+		}
+		return nil, false
+	}(); ok {
+		return ret, nil
+	}
+
+	typedExpr, err := typeCheckDomainCheckConstraint(ctx, evalCtx, t, i)
+	if err != nil {
+		return nil, err
+	}
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	dc.mu.cache.Add(key, typedExpr)
+	return typedExpr, nil
+}
+
+// ReplaceDomainValue replaces the references to VALUE in the CHECK constraint
+// expression of a domain with the given expression.
+func ReplaceDomainValue(expr tree.Expr, value tree.Expr) (tree.Expr, error) {
+	return tree.SimpleVisit(expr, func(e tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		if n, ok := e.(*tree.UnresolvedName); ok && n.NumParts == 1 && n.Parts[0] == DomainValueName {
+			return false, value, nil
+		}
+		return true, e, nil
+	})
+}
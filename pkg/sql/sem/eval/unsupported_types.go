@@ -46,5 +46,16 @@ func (tc *unsupportedTypeChecker) CheckType(ctx context.Context, typ *types.T) e
 			"%s not supported until version 23.2", errorTypeString,
 		)
 	}
+	if typ.Family() == types.JsonpathFamily && !tc.version.IsActive(ctx, clusterversion.V24_1_JsonpathType) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"jsonpath not supported until version 24.1",
+		)
+	}
+	if (typ.Family() == types.RangeFamily || typ.Family() == types.MultirangeFamily) &&
+		!tc.version.IsActive(ctx, clusterversion.V24_1_RangeTypes) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"range types not supported until version 24.1",
+		)
+	}
 	return nil
 }
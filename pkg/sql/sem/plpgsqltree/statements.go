@@ -217,8 +217,11 @@ func (s *CursorDeclaration) WalkStmt(visitor StatementVisitor) (newStmt Statemen
 // stmt_assign
 type Assignment struct {
 	Statement
-	Var   Variable
-	Value Expr
+	Var Variable
+	// Indirection, if set, is the name of the field of the composite-typed
+	// Var that is being assigned (e.g. NEW.x := ...).
+	Indirection tree.Name
+	Value       Expr
 }
 
 func (s *Assignment) CopyNode() *Assignment {
@@ -232,6 +235,10 @@ func (s *Assignment) PlpgSQLStatementTag() string {
 
 func (s *Assignment) Format(ctx *tree.FmtCtx) {
 	ctx.FormatNode(&s.Var)
+	if s.Indirection != "" {
+		ctx.WriteByte('.')
+		ctx.FormatNode(&s.Indirection)
+	}
 	ctx.WriteString(" := ")
 	ctx.FormatNode(s.Value)
 	ctx.WriteString(";\n")
@@ -788,10 +795,14 @@ func (s *Return) CopyNode() *Return {
 }
 
 func (s *Return) Format(ctx *tree.FmtCtx) {
-	ctx.WriteString("RETURN ")
+	ctx.WriteString("RETURN")
 	if s.Expr == nil {
-		ctx.FormatNode(&s.RetVar)
+		if s.RetVar != "" {
+			ctx.WriteString(" ")
+			ctx.FormatNode(&s.RetVar)
+		}
 	} else {
+		ctx.WriteString(" ")
 		ctx.FormatNode(s.Expr)
 	}
 	ctx.WriteString(";\n")
@@ -0,0 +1,135 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// AlterDomain represents an ALTER DOMAIN statement.
+type AlterDomain struct {
+	Domain *UnresolvedObjectName
+	Cmd    AlterDomainCmd
+}
+
+var _ Statement = &AlterDomain{}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterDomain) Format(ctx *FmtCtx) {
+	ctx.WriteString("ALTER DOMAIN ")
+	ctx.FormatNode(node.Domain)
+	ctx.FormatNode(node.Cmd)
+}
+
+// AlterDomainCmd represents a domain modification operation.
+type AlterDomainCmd interface {
+	NodeFormatter
+	alterDomainCmd()
+	// TelemetryName returns the counter name to use for telemetry purposes.
+	TelemetryName() string
+}
+
+func (*AlterDomainAddConstraint) alterDomainCmd()      {}
+func (*AlterDomainDropConstraint) alterDomainCmd()     {}
+func (*AlterDomainValidateConstraint) alterDomainCmd() {}
+func (*AlterDomainSetNotNull) alterDomainCmd()         {}
+func (*AlterDomainDropNotNull) alterDomainCmd()        {}
+
+var _ AlterDomainCmd = &AlterDomainAddConstraint{}
+var _ AlterDomainCmd = &AlterDomainDropConstraint{}
+var _ AlterDomainCmd = &AlterDomainValidateConstraint{}
+var _ AlterDomainCmd = &AlterDomainSetNotNull{}
+var _ AlterDomainCmd = &AlterDomainDropNotNull{}
+
+// AlterDomainAddConstraint represents an ALTER DOMAIN ADD CONSTRAINT command.
+type AlterDomainAddConstraint struct {
+	Constraint DomainConstraint
+	NotValid   bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterDomainAddConstraint) Format(ctx *FmtCtx) {
+	ctx.WriteString(" ADD ")
+	ctx.FormatNode(&node.Constraint)
+	if node.NotValid {
+		ctx.WriteString(" NOT VALID")
+	}
+}
+
+// TelemetryName implements the AlterDomainCmd interface.
+func (node *AlterDomainAddConstraint) TelemetryName() string {
+	return "add_constraint"
+}
+
+// AlterDomainDropConstraint represents an ALTER DOMAIN DROP CONSTRAINT
+// command.
+type AlterDomainDropConstraint struct {
+	Constraint   Name
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterDomainDropConstraint) Format(ctx *FmtCtx) {
+	ctx.WriteString(" DROP CONSTRAINT ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Constraint)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
+
+// TelemetryName implements the AlterDomainCmd interface.
+func (node *AlterDomainDropConstraint) TelemetryName() string {
+	return "drop_constraint"
+}
+
+// AlterDomainValidateConstraint represents an ALTER DOMAIN VALIDATE
+// CONSTRAINT command.
+type AlterDomainValidateConstraint struct {
+	Constraint Name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterDomainValidateConstraint) Format(ctx *FmtCtx) {
+	ctx.WriteString(" VALIDATE CONSTRAINT ")
+	ctx.FormatNode(&node.Constraint)
+}
+
+// TelemetryName implements the AlterDomainCmd interface.
+func (node *AlterDomainValidateConstraint) TelemetryName() string {
+	return "validate_constraint"
+}
+
+// AlterDomainSetNotNull represents an ALTER DOMAIN SET NOT NULL command.
+type AlterDomainSetNotNull struct{}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterDomainSetNotNull) Format(ctx *FmtCtx) {
+	ctx.WriteString(" SET NOT NULL")
+}
+
+// TelemetryName implements the AlterDomainCmd interface.
+func (node *AlterDomainSetNotNull) TelemetryName() string {
+	return "set_not_null"
+}
+
+// AlterDomainDropNotNull represents an ALTER DOMAIN DROP NOT NULL command.
+type AlterDomainDropNotNull struct{}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterDomainDropNotNull) Format(ctx *FmtCtx) {
+	ctx.WriteString(" DROP NOT NULL")
+}
+
+// TelemetryName implements the AlterDomainCmd interface.
+func (node *AlterDomainDropNotNull) TelemetryName() string {
+	return "drop_not_null"
+}
@@ -56,26 +56,27 @@ type AlterTableCmd interface {
 	alterTableCmd()
 }
 
-func (*AlterTableAddColumn) alterTableCmd()          {}
-func (*AlterTableAddConstraint) alterTableCmd()      {}
-func (*AlterTableAlterColumnType) alterTableCmd()    {}
-func (*AlterTableAlterPrimaryKey) alterTableCmd()    {}
-func (*AlterTableDropColumn) alterTableCmd()         {}
-func (*AlterTableDropConstraint) alterTableCmd()     {}
-func (*AlterTableDropNotNull) alterTableCmd()        {}
-func (*AlterTableDropStored) alterTableCmd()         {}
-func (*AlterTableSetNotNull) alterTableCmd()         {}
-func (*AlterTableRenameColumn) alterTableCmd()       {}
-func (*AlterTableRenameConstraint) alterTableCmd()   {}
-func (*AlterTableSetAudit) alterTableCmd()           {}
-func (*AlterTableSetDefault) alterTableCmd()         {}
-func (*AlterTableSetOnUpdate) alterTableCmd()        {}
-func (*AlterTableSetVisible) alterTableCmd()         {}
-func (*AlterTableValidateConstraint) alterTableCmd() {}
-func (*AlterTablePartitionByTable) alterTableCmd()   {}
-func (*AlterTableInjectStats) alterTableCmd()        {}
-func (*AlterTableSetStorageParams) alterTableCmd()   {}
-func (*AlterTableResetStorageParams) alterTableCmd() {}
+func (*AlterTableAddColumn) alterTableCmd()           {}
+func (*AlterTableAddConstraint) alterTableCmd()       {}
+func (*AlterTableAlterColumnType) alterTableCmd()     {}
+func (*AlterTableAlterPrimaryKey) alterTableCmd()     {}
+func (*AlterTableDropColumn) alterTableCmd()          {}
+func (*AlterTableDropConstraint) alterTableCmd()      {}
+func (*AlterTableDropNotNull) alterTableCmd()         {}
+func (*AlterTableDropStored) alterTableCmd()          {}
+func (*AlterTableSetNotNull) alterTableCmd()          {}
+func (*AlterTableRenameColumn) alterTableCmd()        {}
+func (*AlterTableRenameConstraint) alterTableCmd()    {}
+func (*AlterTableSetAudit) alterTableCmd()            {}
+func (*AlterTableSetRowLevelSecurity) alterTableCmd() {}
+func (*AlterTableSetDefault) alterTableCmd()          {}
+func (*AlterTableSetOnUpdate) alterTableCmd()         {}
+func (*AlterTableSetVisible) alterTableCmd()          {}
+func (*AlterTableValidateConstraint) alterTableCmd()  {}
+func (*AlterTablePartitionByTable) alterTableCmd()    {}
+func (*AlterTableInjectStats) alterTableCmd()         {}
+func (*AlterTableSetStorageParams) alterTableCmd()    {}
+func (*AlterTableResetStorageParams) alterTableCmd()  {}
 
 var _ AlterTableCmd = &AlterTableAddColumn{}
 var _ AlterTableCmd = &AlterTableAddConstraint{}
@@ -88,6 +89,7 @@ var _ AlterTableCmd = &AlterTableSetNotNull{}
 var _ AlterTableCmd = &AlterTableRenameColumn{}
 var _ AlterTableCmd = &AlterTableRenameConstraint{}
 var _ AlterTableCmd = &AlterTableSetAudit{}
+var _ AlterTableCmd = &AlterTableSetRowLevelSecurity{}
 var _ AlterTableCmd = &AlterTableSetDefault{}
 var _ AlterTableCmd = &AlterTableSetOnUpdate{}
 var _ AlterTableCmd = &AlterTableSetVisible{}
@@ -594,6 +596,52 @@ func (node *AlterTableSetAudit) Format(ctx *FmtCtx) {
 	ctx.WriteString(node.Mode.String())
 }
 
+// RowLevelSecurityMode is the row-level security setting changed by an ALTER
+// TABLE ... ROW LEVEL SECURITY statement.
+type RowLevelSecurityMode uint8
+
+const (
+	// RowLevelSecurityEnable enables the policies of the table.
+	RowLevelSecurityEnable RowLevelSecurityMode = iota
+	// RowLevelSecurityDisable disables the policies of the table.
+	RowLevelSecurityDisable
+	// RowLevelSecurityForce makes the policies of the table apply to its
+	// owner.
+	RowLevelSecurityForce
+	// RowLevelSecurityNoForce exempts the owner of the table from its
+	// policies.
+	RowLevelSecurityNoForce
+)
+
+var rowLevelSecurityModeName = [...]string{
+	RowLevelSecurityEnable:  "ENABLE",
+	RowLevelSecurityDisable: "DISABLE",
+	RowLevelSecurityForce:   "FORCE",
+	RowLevelSecurityNoForce: "NO FORCE",
+}
+
+func (m RowLevelSecurityMode) String() string {
+	return rowLevelSecurityModeName[m]
+}
+
+// AlterTableSetRowLevelSecurity represents an ALTER TABLE ... { ENABLE |
+// DISABLE | FORCE | NO FORCE } ROW LEVEL SECURITY statement.
+type AlterTableSetRowLevelSecurity struct {
+	Mode RowLevelSecurityMode
+}
+
+// TelemetryName implements the AlterTableCmd interface.
+func (node *AlterTableSetRowLevelSecurity) TelemetryName() string {
+	return strings.ReplaceAll(strings.ToLower(node.Mode.String()), " ", "_") + "_row_level_security"
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableSetRowLevelSecurity) Format(ctx *FmtCtx) {
+	ctx.WriteByte(' ')
+	ctx.WriteString(node.Mode.String())
+	ctx.WriteString(" ROW LEVEL SECURITY")
+}
+
 // AlterTableInjectStats represents an ALTER TABLE INJECT STATISTICS statement.
 type AlterTableInjectStats struct {
 	Stats Expr
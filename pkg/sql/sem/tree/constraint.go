@@ -14,6 +14,7 @@ import (
 	"strconv"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/semenumpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 )
 
 // ReferenceAction is the method used to maintain referential integrity through
@@ -127,3 +128,69 @@ func (x CompositeKeyMatchMethod) String() string {
 		return strconv.Itoa(int(x))
 	}
 }
+
+// ConstraintDeferrability specifies whether the checks for a constraint can be
+// deferred until the end of the transaction.
+type ConstraintDeferrability semenumpb.Deferrability
+
+// The values for ConstraintDeferrability. It has a one-to-one mapping to
+// semenumpb.Deferrability.
+const (
+	ConstraintNotDeferrable ConstraintDeferrability = iota
+	ConstraintInitiallyImmediate
+	ConstraintInitiallyDeferred
+)
+
+// ConstraintDeferrabilityType allows the conversion from a
+// semenumpb.Deferrability to a tree.ConstraintDeferrability.
+var ConstraintDeferrabilityType = [...]ConstraintDeferrability{
+	semenumpb.Deferrability_NOT_DEFERRABLE:      ConstraintNotDeferrable,
+	semenumpb.Deferrability_INITIALLY_IMMEDIATE: ConstraintInitiallyImmediate,
+	semenumpb.Deferrability_INITIALLY_DEFERRED:  ConstraintInitiallyDeferred,
+}
+
+// ConstraintDeferrabilityValue allows the conversion from a
+// tree.ConstraintDeferrability to a semenumpb.Deferrability.
+var ConstraintDeferrabilityValue = [...]semenumpb.Deferrability{
+	ConstraintNotDeferrable:      semenumpb.Deferrability_NOT_DEFERRABLE,
+	ConstraintInitiallyImmediate: semenumpb.Deferrability_INITIALLY_IMMEDIATE,
+	ConstraintInitiallyDeferred:  semenumpb.Deferrability_INITIALLY_DEFERRED,
+}
+
+// Format implements the NodeFormatter interface.
+func (x ConstraintDeferrability) Format(ctx *FmtCtx) {
+	switch x {
+	case ConstraintInitiallyImmediate:
+		ctx.WriteString(" DEFERRABLE")
+	case ConstraintInitiallyDeferred:
+		ctx.WriteString(" DEFERRABLE INITIALLY DEFERRED")
+	}
+}
+
+// String implements the fmt.Stringer interface.
+func (x ConstraintDeferrability) String() string {
+	switch x {
+	case ConstraintNotDeferrable:
+		return "NOT DEFERRABLE"
+	case ConstraintInitiallyImmediate:
+		return "DEFERRABLE INITIALLY IMMEDIATE"
+	case ConstraintInitiallyDeferred:
+		return "DEFERRABLE INITIALLY DEFERRED"
+	default:
+		return strconv.Itoa(int(x))
+	}
+}
+
+// IsDeferred returns true if the checks for a constraint with this
+// deferrability should be run when the transaction commits rather than at the
+// end of each statement, given the mode set by SET CONSTRAINTS.
+func (x ConstraintDeferrability) IsDeferred(mode sessiondatapb.ConstraintsMode) bool {
+	switch mode {
+	case sessiondatapb.ConstraintsImmediate:
+		return false
+	case sessiondatapb.ConstraintsDeferred:
+		return x != ConstraintNotDeferrable
+	default:
+		return x == ConstraintInitiallyDeferred
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/collatedstring"
 	"github.com/cockroachdb/cockroach/pkg/util/pretty"
@@ -365,6 +366,36 @@ type CompositeTypeElem struct {
 	Type  ResolvableTypeReference
 }
 
+// DomainConstraint is a constraint of a domain, defined in a CREATE DOMAIN or
+// ALTER DOMAIN ADD CONSTRAINT statement.
+type DomainConstraint struct {
+	Name Name
+	// Check is the expression of a CHECK constraint. It is nil for a NOT NULL
+	// or NULL constraint.
+	Check Expr
+	// Nullability is NotNull or Null for a NOT NULL or NULL constraint.
+	Nullability Nullability
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DomainConstraint) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.WriteString("CONSTRAINT ")
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	switch {
+	case node.Check != nil:
+		ctx.WriteString("CHECK (")
+		ctx.FormatNode(node.Check)
+		ctx.WriteByte(')')
+	case node.Nullability == NotNull:
+		ctx.WriteString("NOT NULL")
+	default:
+		ctx.WriteString("NULL")
+	}
+}
+
 // CreateType represents a CREATE TYPE statement.
 type CreateType struct {
 	TypeName *UnresolvedObjectName
@@ -374,6 +405,10 @@ type CreateType struct {
 	// CompositeTypeList is set when this repesnets a CREATE TYPE ... AS ( )
 	// statement.
 	CompositeTypeList []CompositeTypeElem
+	// DomainBaseType and DomainConstraints are set when this represents a
+	// CREATE DOMAIN statement.
+	DomainBaseType    ResolvableTypeReference
+	DomainConstraints []DomainConstraint
 	// IfNotExists is true if IF NOT EXISTS was requested.
 	IfNotExists bool
 }
@@ -382,6 +417,17 @@ var _ Statement = &CreateType{}
 
 // Format implements the NodeFormatter interface.
 func (node *CreateType) Format(ctx *FmtCtx) {
+	if node.Variety == Domain {
+		ctx.WriteString("CREATE DOMAIN ")
+		ctx.FormatNode(node.TypeName)
+		ctx.WriteString(" AS ")
+		ctx.FormatTypeReference(node.DomainBaseType)
+		for i := range node.DomainConstraints {
+			ctx.WriteByte(' ')
+			ctx.FormatNode(&node.DomainConstraints[i])
+		}
+		return
+	}
 	ctx.WriteString("CREATE TYPE ")
 	if node.IfNotExists {
 		ctx.WriteString("IF NOT EXISTS ")
@@ -426,6 +472,7 @@ func (*IndexTableDef) tableDef()                {}
 func (*FamilyTableDef) tableDef()               {}
 func (*ForeignKeyConstraintTableDef) tableDef() {}
 func (*CheckConstraintTableDef) tableDef()      {}
+func (*ExclusionConstraintTableDef) tableDef()  {}
 func (*LikeTableDef) tableDef()                 {}
 
 // TableDefs represents a list of table definitions.
@@ -1102,6 +1149,7 @@ type ConstraintTableDef interface {
 func (*UniqueConstraintTableDef) constraintTableDef()     {}
 func (*ForeignKeyConstraintTableDef) constraintTableDef() {}
 func (*CheckConstraintTableDef) constraintTableDef()      {}
+func (*ExclusionConstraintTableDef) constraintTableDef()  {}
 
 // UniqueConstraintTableDef represents a unique constraint within a CREATE
 // TABLE statement.
@@ -1110,6 +1158,7 @@ type UniqueConstraintTableDef struct {
 	PrimaryKey   bool
 	WithoutIndex bool
 	IfNotExists  bool
+	Deferrable   ConstraintDeferrability
 }
 
 // SetName implements the TableDef interface.
@@ -1154,6 +1203,7 @@ func (node *UniqueConstraintTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionByIndex != nil {
 		ctx.FormatNode(node.PartitionByIndex)
 	}
+	ctx.FormatNode(node.Deferrable)
 	if node.Predicate != nil {
 		ctx.WriteString(" WHERE ")
 		ctx.FormatNode(node.Predicate)
@@ -1180,6 +1230,7 @@ type ForeignKeyConstraintTableDef struct {
 	Actions     ReferenceActions
 	Match       CompositeKeyMatchMethod
 	IfNotExists bool
+	Deferrable  ConstraintDeferrability
 }
 
 // Format implements the NodeFormatter interface.
@@ -1210,6 +1261,7 @@ func (node *ForeignKeyConstraintTableDef) Format(ctx *FmtCtx) {
 	}
 
 	ctx.FormatNode(&node.Actions)
+	ctx.FormatNode(node.Deferrable)
 }
 
 // SetName implements the ConstraintTableDef interface.
@@ -1256,6 +1308,80 @@ func (node *CheckConstraintTableDef) Format(ctx *FmtCtx) {
 	ctx.WriteByte(')')
 }
 
+// ExclusionConstraintTableDef represents an EXCLUDE constraint within a
+// CREATE TABLE statement. The constraint guarantees that no two rows satisfy
+// all of the comparisons in Elems when compared with each other.
+type ExclusionConstraintTableDef struct {
+	Name Name
+	// Using is the index access method given in the USING clause, if any.
+	Using       string
+	Elems       ExclusionConstraintElems
+	Predicate   Expr
+	IfNotExists bool
+}
+
+// SetName implements the ConstraintTableDef interface.
+func (node *ExclusionConstraintTableDef) SetName(name Name) {
+	node.Name = name
+}
+
+// SetIfNotExists implements the ConstraintTableDef interface.
+func (node *ExclusionConstraintTableDef) SetIfNotExists() {
+	node.IfNotExists = true
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ExclusionConstraintTableDef) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.WriteString("CONSTRAINT ")
+		if node.IfNotExists {
+			ctx.WriteString("IF NOT EXISTS ")
+		}
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteString("EXCLUDE ")
+	if node.Using != "" {
+		ctx.WriteString("USING ")
+		ctx.WriteString(node.Using)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Elems)
+	ctx.WriteByte(')')
+	if node.Predicate != nil {
+		ctx.WriteString(" WHERE ")
+		ctx.FormatNode(node.Predicate)
+	}
+}
+
+// ExclusionConstraintElem is a single column and operator of an EXCLUDE
+// constraint.
+type ExclusionConstraintElem struct {
+	Column   Name
+	Operator treecmp.ComparisonOperator
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ExclusionConstraintElem) Format(ctx *FmtCtx) {
+	ctx.FormatNode(&node.Column)
+	ctx.WriteString(" WITH ")
+	ctx.WriteString(node.Operator.String())
+}
+
+// ExclusionConstraintElems is a list of ExclusionConstraintElem.
+type ExclusionConstraintElems []ExclusionConstraintElem
+
+// Format implements the NodeFormatter interface.
+func (l *ExclusionConstraintElems) Format(ctx *FmtCtx) {
+	for i := range *l {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*l)[i])
+	}
+}
+
 // FamilyTableDef represents a family definition within a CREATE TABLE
 // statement.
 type FamilyTableDef struct {
@@ -1501,7 +1627,11 @@ type CreateTable struct {
 	// these columns.
 	Defs     TableDefs
 	AsSource *Select
-	Locality *Locality
+	// WithNoData is set for CREATE TABLE ... AS ... WITH NO DATA statements,
+	// which create the table without populating it with the result of
+	// AsSource.
+	WithNoData bool
+	Locality   *Locality
 }
 
 // As returns true if this table represents a CREATE TABLE ... AS statement,
@@ -1558,6 +1688,9 @@ func (node *CreateTable) FormatBody(ctx *FmtCtx) {
 		}
 		ctx.WriteString(" AS ")
 		ctx.FormatNode(node.AsSource)
+		if node.WithNoData {
+			ctx.WriteString(" WITH NO DATA")
+		}
 	} else {
 		ctx.WriteString(" (")
 		ctx.FormatNode(&node.Defs)
@@ -1951,6 +2084,11 @@ type CreateView struct {
 	Replace      bool
 	Materialized bool
 	WithData     bool
+	// Recursive is set for CREATE RECURSIVE VIEW statements, in which the
+	// view query can refer to the view itself. Such a view is equivalent to a
+	// view over a WITH RECURSIVE query. ColumnNames is always set for
+	// recursive views.
+	Recursive bool
 }
 
 // Format implements the NodeFormatter interface.
@@ -1969,6 +2107,10 @@ func (node *CreateView) Format(ctx *FmtCtx) {
 		ctx.WriteString("MATERIALIZED ")
 	}
 
+	if node.Recursive {
+		ctx.WriteString("RECURSIVE ")
+	}
+
 	ctx.WriteString("VIEW ")
 
 	if node.IfNotExists {
@@ -1997,6 +2139,10 @@ type RefreshMaterializedView struct {
 	Name              *UnresolvedObjectName
 	Concurrently      bool
 	RefreshDataOption RefreshDataOption
+	// Incrementally is set if the view should be refreshed by applying the
+	// changes to its source tables since the last refresh, rather than by
+	// recomputing the view query.
+	Incrementally bool
 }
 
 // RefreshDataOption corresponds to arguments for the REFRESH MATERIALIZED VIEW
@@ -2028,6 +2174,9 @@ func (node *RefreshMaterializedView) Format(ctx *FmtCtx) {
 	case RefreshDataClear:
 		ctx.WriteString(" WITH NO DATA")
 	}
+	if node.Incrementally {
+		ctx.WriteString(" INCREMENTALLY")
+	}
 }
 
 // CreateStats represents a CREATE STATISTICS statement.
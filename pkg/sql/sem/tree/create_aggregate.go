@@ -0,0 +1,74 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import "strings"
+
+// CreateAggregate represents a CREATE AGGREGATE statement.
+type CreateAggregate struct {
+	Replace bool
+	Name    RoutineName
+	Params  RoutineParams
+	Options AggregateOptions
+}
+
+var _ Statement = &CreateAggregate{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateAggregate) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE ")
+	if node.Replace {
+		ctx.WriteString("OR REPLACE ")
+	}
+	ctx.WriteString("AGGREGATE ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString("(")
+	ctx.FormatNode(node.Params)
+	ctx.WriteString(") (")
+	ctx.FormatNode(&node.Options)
+	ctx.WriteString(")")
+}
+
+// AggregateOptions is the list of options of a CREATE AGGREGATE statement.
+type AggregateOptions []AggregateOption
+
+// Format implements the NodeFormatter interface.
+func (node *AggregateOptions) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
+
+// AggregateOption is an option of a CREATE AGGREGATE statement, like
+// SFUNC = name or INITCOND = '0'. The interpretation of the value depends on
+// the option, so it is kept in the form it was parsed in.
+type AggregateOption struct {
+	Name Name
+	// Exactly one of Type and Value is set. Type holds values that were parsed
+	// as a type name, which includes function names like SFUNC = my_func.
+	// Value holds string and numeric constants.
+	Type  ResolvableTypeReference
+	Value Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AggregateOption) Format(ctx *FmtCtx) {
+	ctx.WriteString(strings.ToUpper(string(node.Name)))
+	ctx.WriteString(" = ")
+	if node.Type != nil {
+		ctx.FormatTypeReference(node.Type)
+	} else {
+		ctx.FormatNode(node.Value)
+	}
+}
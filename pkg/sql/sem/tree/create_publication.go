@@ -0,0 +1,52 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// CreatePublication represents a CREATE PUBLICATION statement.
+type CreatePublication struct {
+	Name Name
+	// AllTables is set for FOR ALL TABLES.
+	AllTables bool
+	// Tables are the tables of a FOR TABLE clause. A publication without
+	// tables and without AllTables is empty.
+	Tables TableNames
+}
+
+var _ Statement = &CreatePublication{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreatePublication) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE PUBLICATION ")
+	ctx.FormatNode(&node.Name)
+	if node.AllTables {
+		ctx.WriteString(" FOR ALL TABLES")
+	} else if len(node.Tables) > 0 {
+		ctx.WriteString(" FOR TABLE ")
+		ctx.FormatNode(&node.Tables)
+	}
+}
+
+// DropPublication represents a DROP PUBLICATION statement.
+type DropPublication struct {
+	Names    NameList
+	IfExists bool
+}
+
+var _ Statement = &DropPublication{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropPublication) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP PUBLICATION ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Names)
+}
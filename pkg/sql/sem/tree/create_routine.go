@@ -12,6 +12,7 @@ package tree
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -114,7 +115,7 @@ func (node *CreateRoutine) Format(ctx *FmtCtx) {
 	ctx.WriteByte('(')
 	ctx.FormatNode(node.Params)
 	ctx.WriteString(")\n\t")
-	if !node.IsProcedure {
+	if !node.IsProcedure && node.ReturnType.Type != nil {
 		ctx.WriteString("RETURNS ")
 		if node.ReturnType.SetOf {
 			ctx.WriteString("SETOF ")
@@ -163,6 +164,85 @@ func (node *CreateRoutine) Format(ctx *FmtCtx) {
 	}
 }
 
+// ResolveReturnType returns the return type of the routine. When the routine
+// has OUT or INOUT parameters, the return type is synthesized from them: a
+// function with a single output parameter returns the type of that parameter,
+// otherwise the routine returns an anonymous record with a field for each
+// output parameter. Unnamed output parameters are named "column<n>", where n is
+// the position among the output parameters. An explicit RETURNS clause must
+// agree with the synthesized type.
+func (node *CreateRoutine) ResolveReturnType(
+	ctx context.Context, res TypeReferenceResolver,
+) (*types.T, error) {
+	var outTypes []*types.T
+	var outLabels []string
+	for i := range node.Params {
+		param := &node.Params[i]
+		if !param.IsOutParam() {
+			continue
+		}
+		typ, err := ResolveType(ctx, param.Type, res)
+		if err != nil {
+			return nil, err
+		}
+		label := string(param.Name)
+		if label == "" {
+			label = fmt.Sprintf("column%d", len(outTypes)+1)
+		}
+		outTypes = append(outTypes, typ)
+		outLabels = append(outLabels, label)
+	}
+	var outType *types.T
+	if len(outTypes) == 1 && !node.IsProcedure {
+		outType = outTypes[0]
+	} else if len(outTypes) > 0 {
+		outType = types.MakeLabeledTuple(outTypes, outLabels)
+	}
+
+	if node.IsProcedure {
+		// Procedures don't have a RETURNS clause.
+		if outType == nil {
+			return types.Void, nil
+		}
+		return outType, nil
+	}
+	if node.ReturnType.Type == nil {
+		if outType == nil {
+			return nil, pgerror.New(pgcode.InvalidFunctionDefinition,
+				"function result type must be specified")
+		}
+		return outType, nil
+	}
+	typ, err := ResolveType(ctx, node.ReturnType.Type, res)
+	if err != nil {
+		return nil, err
+	}
+	if outType == nil {
+		return typ, nil
+	}
+	if len(outTypes) > 1 {
+		if types.IsRecordType(typ) && (types.IsWildcardTupleType(typ) || typ.Equivalent(outType)) {
+			return outType, nil
+		}
+	} else if typ.Identical(outType) {
+		return outType, nil
+	}
+	return nil, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+		"function result type must be %s because of OUT parameters", outType.Name())
+}
+
+// SignatureParams returns the parameters that identify the routine among the
+// overloads with the same name. See RoutineParam.IsSignatureParam.
+func (node *CreateRoutine) SignatureParams() RoutineParams {
+	params := make(RoutineParams, 0, len(node.Params))
+	for i := range node.Params {
+		if node.Params[i].IsSignatureParam(node.IsProcedure) {
+			params = append(params, node.Params[i])
+		}
+	}
+	return params
+}
+
 // RoutineBody represent a list of statements in a UDF body.
 type RoutineBody struct {
 	// Stmts is populated during parsing. Unlike BodyStatements, we don't need
@@ -338,6 +418,17 @@ func (node RoutineParams) Format(ctx *FmtCtx) {
 	}
 }
 
+// HasOutParams returns true if any of the parameters is an OUT or INOUT
+// parameter.
+func (node RoutineParams) HasOutParams() bool {
+	for i := range node {
+		if node[i].IsOutParam() {
+			return true
+		}
+	}
+	return false
+}
+
 // RoutineParam represents a parameter in a UDF signature.
 type RoutineParam struct {
 	Name       Name
@@ -372,6 +463,26 @@ func (node *RoutineParam) Format(ctx *FmtCtx) {
 	}
 }
 
+// IsInParam returns true if the parameter is an input of the routine, i.e. if
+// an argument must be supplied for it when the routine is invoked.
+func (node *RoutineParam) IsInParam() bool {
+	return node.Class == RoutineParamIn || node.Class == RoutineParamInOut ||
+		node.Class == RoutineParamVariadic
+}
+
+// IsOutParam returns true if the parameter is part of the result of the
+// routine.
+func (node *RoutineParam) IsOutParam() bool {
+	return node.Class == RoutineParamOut || node.Class == RoutineParamInOut
+}
+
+// IsSignatureParam returns true if the parameter is part of the signature of
+// the routine, which identifies the routine among the overloads with the same
+// name. OUT parameters are only part of the signature of procedures.
+func (node *RoutineParam) IsSignatureParam(isProcedure bool) bool {
+	return node.IsInParam() || isProcedure
+}
+
 // RoutineParamClass indicates what type of argument an arg is.
 type RoutineParamClass int
 
@@ -392,10 +503,12 @@ type RoutineReturnType struct {
 	SetOf bool
 }
 
-// DropRoutine represents a DROP FUNCTION or DROP PROCEDURE statement.
+// DropRoutine represents a DROP FUNCTION, DROP PROCEDURE or DROP AGGREGATE
+// statement.
 type DropRoutine struct {
 	IfExists     bool
 	Procedure    bool
+	Aggregate    bool
 	Routines     RoutineObjs
 	DropBehavior DropBehavior
 }
@@ -404,6 +517,8 @@ type DropRoutine struct {
 func (node *DropRoutine) Format(ctx *FmtCtx) {
 	if node.Procedure {
 		ctx.WriteString("DROP PROCEDURE ")
+	} else if node.Aggregate {
+		ctx.WriteString("DROP AGGREGATE ")
 	} else {
 		ctx.WriteString("DROP FUNCTION ")
 	}
@@ -447,22 +562,26 @@ func (node *RoutineObj) Format(ctx *FmtCtx) {
 	}
 }
 
-// ParamTypes returns a slice of parameter types of the routine.
+// ParamTypes returns a slice of the types of the parameters that identify the
+// routine among its overloads. OUT parameters are only part of the signature
+// of procedures, so they are skipped if routineType does not include
+// ProcedureRoutine.
 func (node RoutineObj) ParamTypes(
-	ctx context.Context, res TypeReferenceResolver,
+	ctx context.Context, res TypeReferenceResolver, routineType RoutineType,
 ) ([]*types.T, error) {
-	// TODO(#100405): handle INOUT, OUT and VARIADIC argument classes when we
-	// support them. This is because only IN and INOUT arg types need to be
-	// considered to match a overload.
 	var argTypes []*types.T
 	if node.Params != nil {
-		argTypes = make([]*types.T, len(node.Params))
-		for i, arg := range node.Params {
-			typ, err := ResolveType(ctx, arg.Type, res)
+		argTypes = make([]*types.T, 0, len(node.Params))
+		for i := range node.Params {
+			param := &node.Params[i]
+			if !param.IsSignatureParam(routineType&ProcedureRoutine != 0) {
+				continue
+			}
+			typ, err := ResolveType(ctx, param.Type, res)
 			if err != nil {
 				return nil, err
 			}
-			argTypes[i] = typ
+			argTypes = append(argTypes, typ)
 		}
 	}
 	return argTypes, nil
@@ -484,18 +603,21 @@ func (node *AlterFunctionOptions) Format(ctx *FmtCtx) {
 	}
 }
 
-// AlterRoutineRename represents a ALTER FUNCTION...RENAME or
-// ALTER PROCEDURE...RENAME statement.
+// AlterRoutineRename represents a ALTER FUNCTION...RENAME,
+// ALTER PROCEDURE...RENAME or ALTER AGGREGATE...RENAME statement.
 type AlterRoutineRename struct {
 	Function  RoutineObj
 	NewName   Name
 	Procedure bool
+	Aggregate bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *AlterRoutineRename) Format(ctx *FmtCtx) {
 	if node.Procedure {
 		ctx.WriteString("ALTER PROCEDURE ")
+	} else if node.Aggregate {
+		ctx.WriteString("ALTER AGGREGATE ")
 	} else {
 		ctx.WriteString("ALTER FUNCTION ")
 	}
@@ -504,18 +626,21 @@ func (node *AlterRoutineRename) Format(ctx *FmtCtx) {
 	ctx.FormatNode(&node.NewName)
 }
 
-// AlterRoutineSetSchema represents a ALTER FUNCTION...SET SCHEMA or
-// ALTER PROCEDURE...SET SCHEMA statement.
+// AlterRoutineSetSchema represents a ALTER FUNCTION...SET SCHEMA,
+// ALTER PROCEDURE...SET SCHEMA or ALTER AGGREGATE...SET SCHEMA statement.
 type AlterRoutineSetSchema struct {
 	Function      RoutineObj
 	NewSchemaName Name
 	Procedure     bool
+	Aggregate     bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *AlterRoutineSetSchema) Format(ctx *FmtCtx) {
 	if node.Procedure {
 		ctx.WriteString("ALTER PROCEDURE ")
+	} else if node.Aggregate {
+		ctx.WriteString("ALTER AGGREGATE ")
 	} else {
 		ctx.WriteString("ALTER FUNCTION ")
 	}
@@ -524,18 +649,21 @@ func (node *AlterRoutineSetSchema) Format(ctx *FmtCtx) {
 	ctx.FormatNode(&node.NewSchemaName)
 }
 
-// AlterRoutineSetOwner represents the ALTER FUNCTION...OWNER TO or
-// ALTER PROCEDURE...OWNER TO statement.
+// AlterRoutineSetOwner represents the ALTER FUNCTION...OWNER TO,
+// ALTER PROCEDURE...OWNER TO or ALTER AGGREGATE...OWNER TO statement.
 type AlterRoutineSetOwner struct {
 	Function  RoutineObj
 	NewOwner  RoleSpec
 	Procedure bool
+	Aggregate bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *AlterRoutineSetOwner) Format(ctx *FmtCtx) {
 	if node.Procedure {
 		ctx.WriteString("ALTER PROCEDURE ")
+	} else if node.Aggregate {
+		ctx.WriteString("ALTER AGGREGATE ")
 	} else {
 		ctx.WriteString("ALTER FUNCTION ")
 	}
@@ -599,11 +727,14 @@ const (
 	ColumnDefaultExprInSetDefault   SchemaExprContext = "DEFAULT (in SET DEFAULT)"
 	CheckConstraintExpr             SchemaExprContext = "CHECK"
 	UniqueWithoutIndexPredicateExpr SchemaExprContext = "UNIQUE WITHOUT INDEX PREDICATE"
+	ExclusionPredicateExpr          SchemaExprContext = "EXCLUDE PREDICATE"
 	IndexPredicateExpr              SchemaExprContext = "INDEX PREDICATE"
 	ExpressionIndexElementExpr      SchemaExprContext = "EXPRESSION INDEX ELEMENT"
 	TTLExpirationExpr               SchemaExprContext = "TTL EXPIRATION EXPRESSION"
 	TTLDefaultExpr                  SchemaExprContext = "TTL DEFAULT"
 	TTLUpdateExpr                   SchemaExprContext = "TTL UPDATE"
+	PolicyUsingExpr                 SchemaExprContext = "POLICY USING"
+	PolicyWithCheckExpr             SchemaExprContext = "POLICY WITH CHECK"
 )
 
 func ComputedColumnExprContext(isVirtual bool) SchemaExprContext {
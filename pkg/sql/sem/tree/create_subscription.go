@@ -0,0 +1,64 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// CreateSubscription represents a CREATE SUBSCRIPTION statement.
+type CreateSubscription struct {
+	Name Name
+	// ConnectionURI is the connection string of the publishing cluster.
+	ConnectionURI Expr
+	Publications  NameList
+	Options       StorageParams
+}
+
+var _ Statement = &CreateSubscription{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateSubscription) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE SUBSCRIPTION ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" CONNECTION ")
+	ctx.FormatNode(node.ConnectionURI)
+	ctx.WriteString(" PUBLICATION ")
+	ctx.FormatNode(&node.Publications)
+	if len(node.Options) > 0 {
+		ctx.WriteString(" WITH (")
+		ctx.FormatNode(&node.Options)
+		ctx.WriteByte(')')
+	}
+}
+
+// DropSubscription represents a DROP SUBSCRIPTION statement.
+type DropSubscription struct {
+	Name     Name
+	IfExists bool
+}
+
+var _ Statement = &DropSubscription{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropSubscription) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP SUBSCRIPTION ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+}
+
+// ShowSubscriptions represents a SHOW SUBSCRIPTIONS statement.
+type ShowSubscriptions struct{}
+
+var _ Statement = &ShowSubscriptions{}
+
+// Format implements the NodeFormatter interface.
+func (node *ShowSubscriptions) Format(ctx *FmtCtx) {
+	ctx.WriteString("SHOW SUBSCRIPTIONS")
+}
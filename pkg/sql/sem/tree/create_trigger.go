@@ -0,0 +1,233 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/semenumpb"
+	"github.com/cockroachdb/redact"
+)
+
+// CreateTrigger represents a CREATE TRIGGER statement.
+type CreateTrigger struct {
+	Replace    bool
+	Name       Name
+	ActionTime TriggerActionTime
+	Events     []*TriggerEvent
+	TableName  TableName
+	ForEach    TriggerForEach
+	When       Expr
+	FuncName   *UnresolvedName
+	FuncArgs   []string
+}
+
+var _ Statement = &CreateTrigger{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE ")
+	if node.Replace {
+		ctx.WriteString("OR REPLACE ")
+	}
+	ctx.WriteString("TRIGGER ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte(' ')
+	ctx.WriteString(node.ActionTime.String())
+	ctx.WriteByte(' ')
+	for i := range node.Events {
+		if i > 0 {
+			ctx.WriteString(" OR ")
+		}
+		ctx.FormatNode(node.Events[i])
+	}
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.TableName)
+	ctx.WriteString(" FOR EACH ")
+	ctx.WriteString(node.ForEach.String())
+	if node.When != nil {
+		ctx.WriteString(" WHEN (")
+		ctx.FormatNode(node.When)
+		ctx.WriteByte(')')
+	}
+	ctx.WriteString(" EXECUTE FUNCTION ")
+	ctx.FormatNode(node.FuncName)
+	ctx.WriteByte('(')
+	for i, arg := range node.FuncArgs {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		formatTriggerFuncArg(ctx, arg)
+	}
+	ctx.WriteByte(')')
+}
+
+// formatTriggerFuncArg formats a single argument of a trigger function. The
+// arguments are always stored and displayed as string literals, matching
+// Postgres.
+func formatTriggerFuncArg(ctx *FmtCtx, arg string) {
+	f := ctx.flags
+	if f.HasFlags(FmtHideConstants) {
+		ctx.WriteString("'_'")
+	} else if f.HasFlags(FmtMarkRedactionNode) {
+		ctx.WriteString(string(redact.StartMarker()))
+		lexbase.EncodeSQLString(&ctx.Buffer, arg)
+		ctx.WriteString(string(redact.EndMarker()))
+	} else {
+		lexbase.EncodeSQLString(&ctx.Buffer, arg)
+	}
+}
+
+// TriggerActionTime describes when a trigger fires relative to the operation
+// that fired it.
+type TriggerActionTime uint8
+
+const (
+	// TriggerActionTimeBefore indicates that the trigger fires before the
+	// operation is attempted.
+	TriggerActionTimeBefore TriggerActionTime = iota
+	// TriggerActionTimeAfter indicates that the trigger fires after the
+	// operation has completed.
+	TriggerActionTimeAfter
+	// TriggerActionTimeInsteadOf indicates that the trigger fires instead of
+	// the operation. It is only valid for views.
+	TriggerActionTimeInsteadOf
+)
+
+var triggerActionTimeName = [...]string{
+	TriggerActionTimeBefore:    "BEFORE",
+	TriggerActionTimeAfter:     "AFTER",
+	TriggerActionTimeInsteadOf: "INSTEAD OF",
+}
+
+func (t TriggerActionTime) String() string {
+	return triggerActionTimeName[t]
+}
+
+// TriggerActionTimeType allows the conversion between a
+// semenumpb.TriggerActionTime and a tree.TriggerActionTime.
+var TriggerActionTimeType = [...]TriggerActionTime{
+	semenumpb.TriggerActionTime_BEFORE:     TriggerActionTimeBefore,
+	semenumpb.TriggerActionTime_AFTER:      TriggerActionTimeAfter,
+	semenumpb.TriggerActionTime_INSTEAD_OF: TriggerActionTimeInsteadOf,
+}
+
+// TriggerActionTimeValue allows the conversion between a
+// tree.TriggerActionTime and a semenumpb.TriggerActionTime.
+var TriggerActionTimeValue = [...]semenumpb.TriggerActionTime{
+	TriggerActionTimeBefore:    semenumpb.TriggerActionTime_BEFORE,
+	TriggerActionTimeAfter:     semenumpb.TriggerActionTime_AFTER,
+	TriggerActionTimeInsteadOf: semenumpb.TriggerActionTime_INSTEAD_OF,
+}
+
+// TriggerEventType describes the type of operation that fires a trigger.
+type TriggerEventType uint8
+
+const (
+	// TriggerEventInsert indicates that the trigger fires on INSERT.
+	TriggerEventInsert TriggerEventType = iota
+	// TriggerEventUpdate indicates that the trigger fires on UPDATE.
+	TriggerEventUpdate
+	// TriggerEventDelete indicates that the trigger fires on DELETE.
+	TriggerEventDelete
+	// TriggerEventTruncate indicates that the trigger fires on TRUNCATE.
+	TriggerEventTruncate
+)
+
+var triggerEventTypeName = [...]string{
+	TriggerEventInsert:   "INSERT",
+	TriggerEventUpdate:   "UPDATE",
+	TriggerEventDelete:   "DELETE",
+	TriggerEventTruncate: "TRUNCATE",
+}
+
+func (t TriggerEventType) String() string {
+	return triggerEventTypeName[t]
+}
+
+// TriggerEventTypeType allows the conversion between a
+// semenumpb.TriggerEventType and a tree.TriggerEventType.
+var TriggerEventTypeType = [...]TriggerEventType{
+	semenumpb.TriggerEventType_INSERT:   TriggerEventInsert,
+	semenumpb.TriggerEventType_UPDATE:   TriggerEventUpdate,
+	semenumpb.TriggerEventType_DELETE:   TriggerEventDelete,
+	semenumpb.TriggerEventType_TRUNCATE: TriggerEventTruncate,
+}
+
+// TriggerEventTypeValue allows the conversion between a tree.TriggerEventType
+// and a semenumpb.TriggerEventType.
+var TriggerEventTypeValue = [...]semenumpb.TriggerEventType{
+	TriggerEventInsert:   semenumpb.TriggerEventType_INSERT,
+	TriggerEventUpdate:   semenumpb.TriggerEventType_UPDATE,
+	TriggerEventDelete:   semenumpb.TriggerEventType_DELETE,
+	TriggerEventTruncate: semenumpb.TriggerEventType_TRUNCATE,
+}
+
+// TriggerEvent represents one of the events that fires a trigger. Columns is
+// only set for UPDATE OF column_name [, ...].
+type TriggerEvent struct {
+	EventType TriggerEventType
+	Columns   NameList
+}
+
+// Format implements the NodeFormatter interface.
+func (node *TriggerEvent) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.EventType.String())
+	if len(node.Columns) > 0 {
+		ctx.WriteString(" OF ")
+		ctx.FormatNode(&node.Columns)
+	}
+}
+
+// TriggerForEach describes whether a trigger fires once for each row affected
+// by the operation, or once for the entire statement.
+type TriggerForEach uint8
+
+const (
+	// TriggerForEachStatement indicates that the trigger fires once per
+	// statement. This is the default.
+	TriggerForEachStatement TriggerForEach = iota
+	// TriggerForEachRow indicates that the trigger fires once for each row.
+	TriggerForEachRow
+)
+
+var triggerForEachName = [...]string{
+	TriggerForEachStatement: "STATEMENT",
+	TriggerForEachRow:       "ROW",
+}
+
+func (t TriggerForEach) String() string {
+	return triggerForEachName[t]
+}
+
+// DropTrigger represents a DROP TRIGGER statement.
+type DropTrigger struct {
+	IfExists     bool
+	Trigger      Name
+	Table        TableName
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropTrigger{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP TRIGGER ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Trigger)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.Table)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
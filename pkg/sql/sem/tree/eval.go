@@ -456,10 +456,76 @@ func initNonArrayToNonArrayConcatenation() {
 	}
 }
 
+// initRangeOperators initializes the set operators (+, * and -) and the
+// positional operators (<< and >>) for each range type and its corresponding
+// multirange type.
+func initRangeOperators() {
+	rangeSetOp := func(
+		rangeOp func(left, right *DRange) (*DRange, error),
+		multirangeOp func(left, right *DMultirange) (*DMultirange, error),
+	) func(left, right Datum) (Datum, error) {
+		return func(left, right Datum) (Datum, error) {
+			if l, ok := AsDRange(left); ok {
+				return rangeOp(l, MustBeDRange(right))
+			}
+			return multirangeOp(MustBeDMultirange(left), MustBeDMultirange(right))
+		}
+	}
+	for _, rt := range types.RangeTypes {
+		for _, t := range []*types.T{rt, types.MultirangeOf(rt)} {
+			typ := t
+			addBinOp(treebin.Plus, &BinOp{
+				LeftType:   typ,
+				RightType:  typ,
+				ReturnType: typ,
+				EvalOp:     &RangeSetOp{Op: rangeSetOp(RangeUnion, MultirangeUnion)},
+				Volatility: volatility.Immutable,
+			})
+			addBinOp(treebin.Mult, &BinOp{
+				LeftType:   typ,
+				RightType:  typ,
+				ReturnType: typ,
+				EvalOp:     &RangeSetOp{Op: rangeSetOp(RangeIntersection, MultirangeIntersection)},
+				Volatility: volatility.Immutable,
+			})
+			addBinOp(treebin.Minus, &BinOp{
+				LeftType:   typ,
+				RightType:  typ,
+				ReturnType: typ,
+				EvalOp:     &RangeSetOp{Op: rangeSetOp(RangeDifference, MultirangeDifference)},
+				Volatility: volatility.Immutable,
+			})
+		}
+		for _, left := range []*types.T{rt, types.MultirangeOf(rt)} {
+			for _, right := range []*types.T{rt, types.MultirangeOf(rt)} {
+				addBinOp(treebin.LShift, &BinOp{
+					LeftType:   left,
+					RightType:  right,
+					ReturnType: types.Bool,
+					EvalOp: &RangeSetOp{Op: func(left, right Datum) (Datum, error) {
+						return MakeDBool(DBool(RangesBefore(left, right))), nil
+					}},
+					Volatility: volatility.Immutable,
+				})
+				addBinOp(treebin.RShift, &BinOp{
+					LeftType:   left,
+					RightType:  right,
+					ReturnType: types.Bool,
+					EvalOp: &RangeSetOp{Op: func(left, right Datum) (Datum, error) {
+						return MakeDBool(DBool(RangesBefore(right, left))), nil
+					}},
+					Volatility: volatility.Immutable,
+				})
+			}
+		}
+	}
+}
+
 func init() {
 	initArrayElementConcatenation()
 	initArrayToArrayConcatenation()
 	initNonArrayToNonArrayConcatenation()
+	initRangeOperators()
 }
 
 func init() {
@@ -1454,6 +1520,8 @@ func cmpOpFixups(
 		})
 	}
 
+	addRangeCmpOps(cmpOps)
+
 	for _, overloads := range cmpOps {
 		_ = overloads.ForEachCmpOp(func(op *CmpOp) error {
 			op.types = ParamTypes{{"left", op.LeftType}, {"right", op.RightType}}
@@ -1962,9 +2030,67 @@ var CmpOps = cmpOpFixups(map[treecmp.ComparisonOperatorSymbol]*CmpOpOverloads{
 			EvalOp:     &TSMatchesVectorQueryOp{},
 			Volatility: volatility.Immutable,
 		},
+		{
+			LeftType:   types.Jsonb,
+			RightType:  types.Jsonpath,
+			EvalOp:     &JSONPathMatchOp{},
+			Volatility: volatility.Immutable,
+		},
+	}},
+
+	treecmp.JSONPathExists: {overloads: []*CmpOp{
+		{
+			LeftType:   types.Jsonb,
+			RightType:  types.Jsonpath,
+			EvalOp:     &JSONPathExistsOp{},
+			Volatility: volatility.Immutable,
+		},
 	}},
 })
 
+// addRangeCmpOps adds the comparison operators for each range type and its
+// corresponding multirange type to cmpOps.
+func addRangeCmpOps(cmpOps map[treecmp.ComparisonOperatorSymbol]*CmpOpOverloads) {
+	appendCmpOp := func(sym treecmp.ComparisonOperatorSymbol, ops ...*CmpOp) {
+		s, ok := cmpOps[sym]
+		if !ok {
+			s = new(CmpOpOverloads)
+			cmpOps[sym] = s
+		}
+		s.overloads = append(s.overloads, ops...)
+	}
+	makeRangeOp := func(left, right *types.T, op func(left, right Datum) bool) *CmpOp {
+		return &CmpOp{
+			LeftType:   left,
+			RightType:  right,
+			EvalOp:     &CompareRangeOp{Op: op},
+			Volatility: volatility.Immutable,
+		}
+	}
+	flip := func(op func(left, right Datum) bool) func(left, right Datum) bool {
+		return func(left, right Datum) bool { return op(right, left) }
+	}
+	for _, rt := range types.RangeTypes {
+		mrt := types.MultirangeOf(rt)
+		elem := rt.RangeContents()
+		for _, t := range []*types.T{rt, mrt} {
+			appendCmpOp(treecmp.EQ, makeEqFn(t, t, volatility.Immutable))
+			appendCmpOp(treecmp.LT, makeLtFn(t, t, volatility.Immutable))
+			appendCmpOp(treecmp.LE, makeLeFn(t, t, volatility.Immutable))
+			appendCmpOp(treecmp.IsNotDistinctFrom, makeIsFn(t, t, volatility.Immutable))
+			appendCmpOp(treecmp.In, makeEvalTupleIn(t, volatility.Immutable))
+			appendCmpOp(treecmp.Contains, makeRangeOp(t, elem, RangesContainElem))
+			appendCmpOp(treecmp.ContainedBy, makeRangeOp(elem, t, flip(RangesContainElem)))
+			for _, other := range []*types.T{rt, mrt} {
+				appendCmpOp(treecmp.Overlaps, makeRangeOp(t, other, RangesOverlap))
+				appendCmpOp(treecmp.Contains, makeRangeOp(t, other, RangesContain))
+				appendCmpOp(treecmp.ContainedBy, makeRangeOp(t, other, flip(RangesContain)))
+				appendCmpOp(treecmp.Adjacent, makeRangeOp(t, other, RangesAdjacent))
+			}
+		}
+	}
+}
+
 func makeBox2DComparisonOperators(op func(lhs, rhs *geo.CartesianBoundingBox) bool) []*CmpOp {
 	return []*CmpOp{
 		{
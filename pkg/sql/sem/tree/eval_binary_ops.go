@@ -32,6 +32,16 @@ type CompareBox2DOp struct {
 	Op func(left, right Datum) bool
 }
 
+// CompareRangeOp is a BinaryEvalOp.
+type CompareRangeOp struct {
+	Op func(left, right Datum) bool
+}
+
+// RangeSetOp is a BinaryEvalOp.
+type RangeSetOp struct {
+	Op func(left, right Datum) (Datum, error)
+}
+
 // InTupleOp is a BinaryEvalOp.
 type InTupleOp struct{}
 
@@ -370,6 +380,12 @@ type JSONSomeExistsOp struct{}
 // JSONAllExistsOp is a BinaryEvalOp.
 type JSONAllExistsOp struct{}
 
+// JSONPathExistsOp is a BinaryEvalOp.
+type JSONPathExistsOp struct{}
+
+// JSONPathMatchOp is a BinaryEvalOp.
+type JSONPathMatchOp struct{}
+
 // JSONFetchValPathOp is a BinaryEvalOp.
 type JSONFetchValPathOp struct{}
 
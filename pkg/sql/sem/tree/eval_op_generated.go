@@ -65,6 +65,7 @@ type BinaryOpEvaluator interface {
 	EvalBitXorIntOp(context.Context, *BitXorIntOp, Datum, Datum) (Datum, error)
 	EvalBitXorVarBitOp(context.Context, *BitXorVarBitOp, Datum, Datum) (Datum, error)
 	EvalCompareBox2DOp(context.Context, *CompareBox2DOp, Datum, Datum) (Datum, error)
+	EvalCompareRangeOp(context.Context, *CompareRangeOp, Datum, Datum) (Datum, error)
 	EvalCompareScalarOp(context.Context, *CompareScalarOp, Datum, Datum) (Datum, error)
 	EvalCompareTupleOp(context.Context, *CompareTupleOp, Datum, Datum) (Datum, error)
 	EvalConcatArraysOp(context.Context, *ConcatArraysOp, Datum, Datum) (Datum, error)
@@ -98,6 +99,8 @@ type BinaryOpEvaluator interface {
 	EvalJSONFetchValIntOp(context.Context, *JSONFetchValIntOp, Datum, Datum) (Datum, error)
 	EvalJSONFetchValPathOp(context.Context, *JSONFetchValPathOp, Datum, Datum) (Datum, error)
 	EvalJSONFetchValStringOp(context.Context, *JSONFetchValStringOp, Datum, Datum) (Datum, error)
+	EvalJSONPathExistsOp(context.Context, *JSONPathExistsOp, Datum, Datum) (Datum, error)
+	EvalJSONPathMatchOp(context.Context, *JSONPathMatchOp, Datum, Datum) (Datum, error)
 	EvalJSONSomeExistsOp(context.Context, *JSONSomeExistsOp, Datum, Datum) (Datum, error)
 	EvalLShiftINetOp(context.Context, *LShiftINetOp, Datum, Datum) (Datum, error)
 	EvalLShiftIntOp(context.Context, *LShiftIntOp, Datum, Datum) (Datum, error)
@@ -184,6 +187,7 @@ type BinaryOpEvaluator interface {
 	EvalRShiftINetOp(context.Context, *RShiftINetOp, Datum, Datum) (Datum, error)
 	EvalRShiftIntOp(context.Context, *RShiftIntOp, Datum, Datum) (Datum, error)
 	EvalRShiftVarBitIntOp(context.Context, *RShiftVarBitIntOp, Datum, Datum) (Datum, error)
+	EvalRangeSetOp(context.Context, *RangeSetOp, Datum, Datum) (Datum, error)
 	EvalSimilarToOp(context.Context, *SimilarToOp, Datum, Datum) (Datum, error)
 	EvalTSMatchesQueryVectorOp(context.Context, *TSMatchesQueryVectorOp, Datum, Datum) (Datum, error)
 	EvalTSMatchesVectorQueryOp(context.Context, *TSMatchesVectorQueryOp, Datum, Datum) (Datum, error)
@@ -295,6 +299,11 @@ func (op *CompareBox2DOp) Eval(ctx context.Context, e OpEvaluator, a, b Datum) (
 	return e.EvalCompareBox2DOp(ctx, op, a, b)
 }
 
+// Eval is part of the BinaryEvalOp interface.
+func (op *CompareRangeOp) Eval(ctx context.Context, e OpEvaluator, a, b Datum) (Datum, error) {
+	return e.EvalCompareRangeOp(ctx, op, a, b)
+}
+
 // Eval is part of the BinaryEvalOp interface.
 func (op *CompareScalarOp) Eval(ctx context.Context, e OpEvaluator, a, b Datum) (Datum, error) {
 	return e.EvalCompareScalarOp(ctx, op, a, b)
@@ -460,6 +469,16 @@ func (op *JSONFetchValStringOp) Eval(ctx context.Context, e OpEvaluator, a, b Da
 	return e.EvalJSONFetchValStringOp(ctx, op, a, b)
 }
 
+// Eval is part of the BinaryEvalOp interface.
+func (op *JSONPathExistsOp) Eval(ctx context.Context, e OpEvaluator, a, b Datum) (Datum, error) {
+	return e.EvalJSONPathExistsOp(ctx, op, a, b)
+}
+
+// Eval is part of the BinaryEvalOp interface.
+func (op *JSONPathMatchOp) Eval(ctx context.Context, e OpEvaluator, a, b Datum) (Datum, error) {
+	return e.EvalJSONPathMatchOp(ctx, op, a, b)
+}
+
 // Eval is part of the BinaryEvalOp interface.
 func (op *JSONSomeExistsOp) Eval(ctx context.Context, e OpEvaluator, a, b Datum) (Datum, error) {
 	return e.EvalJSONSomeExistsOp(ctx, op, a, b)
@@ -890,6 +909,11 @@ func (op *RShiftVarBitIntOp) Eval(ctx context.Context, e OpEvaluator, a, b Datum
 	return e.EvalRShiftVarBitIntOp(ctx, op, a, b)
 }
 
+// Eval is part of the BinaryEvalOp interface.
+func (op *RangeSetOp) Eval(ctx context.Context, e OpEvaluator, a, b Datum) (Datum, error) {
+	return e.EvalRangeSetOp(ctx, op, a, b)
+}
+
 // Eval is part of the BinaryEvalOp interface.
 func (op *SimilarToOp) Eval(ctx context.Context, e OpEvaluator, a, b Datum) (Datum, error) {
 	return e.EvalSimilarToOp(ctx, op, a, b)
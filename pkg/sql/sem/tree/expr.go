@@ -1283,6 +1283,10 @@ type FuncExpr struct {
 	// InCall is true when the FuncExpr is part of a CALL statement.
 	InCall bool
 
+	// Variadic is true when the last argument is marked VARIADIC, in which
+	// case it is an array passed as the VARIADIC parameter of the function.
+	Variadic bool
+
 	typeAnnotation
 	fnProps *FunctionProperties
 	fn      *Overload
@@ -1394,7 +1398,18 @@ func (node *FuncExpr) Format(ctx *FmtCtx) {
 
 	ctx.WriteByte('(')
 	ctx.WriteString(typ)
-	ctx.FormatNode(&node.Exprs)
+	if node.Variadic && len(node.Exprs) > 0 {
+		last := len(node.Exprs) - 1
+		if last > 0 {
+			exprs := node.Exprs[:last]
+			ctx.FormatNode(&exprs)
+			ctx.WriteString(", ")
+		}
+		ctx.WriteString("VARIADIC ")
+		ctx.FormatNode(node.Exprs[last])
+	} else {
+		ctx.FormatNode(&node.Exprs)
+	}
 	if node.AggType == GeneralAgg && len(node.OrderBy) > 0 {
 		ctx.WriteByte(' ')
 		ctx.FormatNode(&node.OrderBy)
@@ -1758,6 +1773,8 @@ func (node *DTimestamp) String() string       { return AsString(node) }
 func (node *DTimestampTZ) String() string     { return AsString(node) }
 func (node *DTuple) String() string           { return AsString(node) }
 func (node *DArray) String() string           { return AsString(node) }
+func (node *DRange) String() string           { return AsString(node) }
+func (node *DMultirange) String() string      { return AsString(node) }
 func (node *DOid) String() string             { return AsString(node) }
 func (node *DOidWrapper) String() string      { return AsString(node) }
 func (node *DVoid) String() string            { return AsString(node) }
@@ -1768,6 +1785,7 @@ func (node *IfExpr) String() string           { return AsString(node) }
 func (node *IfErrExpr) String() string        { return AsString(node) }
 func (node *IndexedVar) String() string       { return AsString(node) }
 func (node *IndirectionExpr) String() string  { return AsString(node) }
+func (node *GroupingSet) String() string      { return AsString(node) }
 func (node *IsOfTypeExpr) String() string     { return AsString(node) }
 func (node *Name) String() string             { return AsString(node) }
 func (node *UnrestrictedName) String() string { return AsString(node) }
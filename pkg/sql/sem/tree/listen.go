@@ -0,0 +1,29 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// Listen represents a LISTEN statement.
+type Listen struct {
+	ChannelName Name
+}
+
+var _ Statement = &Listen{}
+
+// Format implements the NodeFormatter interface.
+func (node *Listen) Format(ctx *FmtCtx) {
+	ctx.WriteString("LISTEN ")
+	ctx.FormatNode(&node.ChannelName)
+}
+
+// String implements the Statement interface.
+func (node *Listen) String() string {
+	return AsString(node)
+}
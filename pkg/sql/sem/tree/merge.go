@@ -0,0 +1,147 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// Merge represents a MERGE statement.
+type Merge struct {
+	With      *With
+	Table     TableExpr
+	Source    TableExpr
+	On        Expr
+	Whens     MergeWhens
+	Returning ReturningClause
+}
+
+// Format implements the NodeFormatter interface.
+func (node *Merge) Format(ctx *FmtCtx) {
+	ctx.FormatNode(node.With)
+	ctx.WriteString("MERGE INTO ")
+	ctx.FormatNode(node.Table)
+	ctx.WriteString(" USING ")
+	ctx.FormatNode(node.Source)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(node.On)
+	ctx.WriteByte(' ')
+	ctx.FormatNode(&node.Whens)
+	if HasReturningClause(node.Returning) {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(node.Returning)
+	}
+}
+
+// MergeWhens represents the list of WHEN clauses of a MERGE statement.
+type MergeWhens []*MergeWhen
+
+// Format implements the NodeFormatter interface.
+func (node *MergeWhens) Format(ctx *FmtCtx) {
+	for i, n := range *node {
+		if i > 0 {
+			ctx.WriteByte(' ')
+		}
+		ctx.FormatNode(n)
+	}
+}
+
+// MergeWhen represents a WHEN [NOT] MATCHED clause of a MERGE statement. The
+// clauses are evaluated in order, and the action of the first clause whose
+// condition is satisfied is applied to the row.
+type MergeWhen struct {
+	// Matched is true for WHEN MATCHED clauses, which apply to source rows
+	// that join to a row of the target table, and false for WHEN NOT MATCHED
+	// clauses, which apply to source rows that do not.
+	Matched bool
+	// Cond is the optional AND condition of the clause.
+	Cond   Expr
+	Action MergeAction
+}
+
+// Format implements the NodeFormatter interface.
+func (node *MergeWhen) Format(ctx *FmtCtx) {
+	ctx.WriteString("WHEN ")
+	if !node.Matched {
+		ctx.WriteString("NOT ")
+	}
+	ctx.WriteString("MATCHED")
+	if node.Cond != nil {
+		ctx.WriteString(" AND ")
+		ctx.FormatNode(node.Cond)
+	}
+	ctx.WriteString(" THEN ")
+	ctx.FormatNode(node.Action)
+}
+
+// MergeAction represents the action taken by a WHEN clause of a MERGE
+// statement.
+type MergeAction interface {
+	NodeFormatter
+	mergeAction()
+}
+
+func (*MergeUpdate) mergeAction()    {}
+func (*MergeDelete) mergeAction()    {}
+func (*MergeInsert) mergeAction()    {}
+func (*MergeDoNothing) mergeAction() {}
+
+// MergeUpdate represents an UPDATE SET action of a WHEN MATCHED clause.
+type MergeUpdate struct {
+	Exprs UpdateExprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *MergeUpdate) Format(ctx *FmtCtx) {
+	ctx.WriteString("UPDATE SET ")
+	ctx.FormatNode(&node.Exprs)
+}
+
+// MergeDelete represents a DELETE action of a WHEN MATCHED clause.
+type MergeDelete struct{}
+
+// Format implements the NodeFormatter interface.
+func (node *MergeDelete) Format(ctx *FmtCtx) {
+	ctx.WriteString("DELETE")
+}
+
+// MergeInsert represents an INSERT action of a WHEN NOT MATCHED clause.
+type MergeInsert struct {
+	Columns NameList
+	// Values is nil for INSERT DEFAULT VALUES.
+	Values Exprs
+}
+
+// DefaultValues returns true iff the action is INSERT DEFAULT VALUES.
+func (node *MergeInsert) DefaultValues() bool {
+	return node.Values == nil
+}
+
+// Format implements the NodeFormatter interface.
+func (node *MergeInsert) Format(ctx *FmtCtx) {
+	ctx.WriteString("INSERT")
+	if len(node.Columns) > 0 {
+		ctx.WriteString(" (")
+		ctx.FormatNode(&node.Columns)
+		ctx.WriteByte(')')
+	}
+	if node.DefaultValues() {
+		ctx.WriteString(" DEFAULT VALUES")
+	} else {
+		ctx.WriteString(" VALUES (")
+		ctx.FormatNode(&node.Values)
+		ctx.WriteByte(')')
+	}
+}
+
+// MergeDoNothing represents a DO NOTHING action of a WHEN clause.
+type MergeDoNothing struct{}
+
+// Format implements the NodeFormatter interface.
+func (node *MergeDoNothing) Format(ctx *FmtCtx) {
+	ctx.WriteString("DO NOTHING")
+}
@@ -271,6 +271,31 @@ type Overload struct {
 	// Language is the function language that was used to define the UDF.
 	// This is currently either SQL or PL/pgSQL.
 	Language RoutineLanguage
+	// RoutineParams are all the parameters of a user-defined routine, in the
+	// order they were declared. Unlike Types, they include the OUT parameters
+	// of functions, and the type of a VARIADIC parameter is its array type.
+	// Only set for UDFs, and only if UDFContainsOnlySignature is false.
+	RoutineParams RoutineParams
+	// UserDefinedAggregate describes the functions that implement a
+	// user-defined aggregate. It is only set for user-defined aggregates, and
+	// only if UDFContainsOnlySignature is false.
+	UserDefinedAggregate *UserDefinedAggregate
+}
+
+// UserDefinedAggregate describes a user-defined aggregate function, which is
+// evaluated by calling a transition function for each input row, and then a
+// final function, if any, on the resulting state.
+type UserDefinedAggregate struct {
+	// TransitionFunc is the OID of the state transition function.
+	TransitionFunc oid.Oid
+	// FinalFunc is the OID of the final function, or zero if the result of the
+	// aggregate is the final state.
+	FinalFunc oid.Oid
+	// StateType is the type of the aggregate state.
+	StateType *types.T
+	// InitCond is the text representation of the initial state. If it is nil,
+	// the initial state is NULL.
+	InitCond *string
 }
 
 // params implements the overloadImpl interface.
@@ -558,8 +583,18 @@ func (v VariadicType) Match(types []*types.T) bool {
 	return true
 }
 
-// MatchIdentical is part of the TypeList interface.
-func (VariadicType) MatchIdentical(types []*types.T) bool {
+// MatchIdentical is part of the TypeList interface. The variadic parameter is
+// matched against its array type, since that is how it is declared in the
+// signature of a user-defined routine.
+func (v VariadicType) MatchIdentical(types []*types.T) bool {
+	if len(types) != len(v.FixedTypes)+1 {
+		return false
+	}
+	for i := range types {
+		if !v.MatchAtIdentical(types[i], i) {
+			return false
+		}
+	}
 	return true
 }
 
@@ -572,9 +607,14 @@ func (v VariadicType) MatchAt(typ *types.T, i int) bool {
 }
 
 // MatchAtIdentical is part of the TypeList interface.
-// TODO(mgartner): This will be incorrect once we add support for variadic UDFs
-func (VariadicType) MatchAtIdentical(typ *types.T, i int) bool {
-	return true
+func (v VariadicType) MatchAtIdentical(typ *types.T, i int) bool {
+	if typ.Family() == types.UnknownFamily {
+		return true
+	}
+	if i < len(v.FixedTypes) {
+		return v.FixedTypes[i].Identical(typ)
+	}
+	return i == len(v.FixedTypes) && types.MakeArray(v.VarType).Identical(typ)
 }
 
 // MatchLen is part of the TypeList interface.
@@ -618,6 +658,44 @@ func (v VariadicType) String() string {
 	return s.String()
 }
 
+// MakeRoutineTypeList returns the TypeList of a user-defined routine, given
+// the types of the parameters in its signature. If variadic is true, the last
+// parameter is a VARIADIC parameter of an array type, which accepts any number
+// of trailing arguments of the array's element type.
+func MakeRoutineTypeList(names []string, typs []*types.T, variadic bool) TypeList {
+	if variadic && len(typs) > 0 {
+		last := len(typs) - 1
+		return VariadicType{FixedTypes: typs[:last], VarType: typs[last].ArrayContents()}
+	}
+	ret := make(ParamTypes, len(typs))
+	for i := range typs {
+		ret[i] = ParamType{Typ: typs[i]}
+		if names != nil {
+			ret[i].Name = names[i]
+		}
+	}
+	return ret
+}
+
+// variadicCallParams returns the parameters of an overload which the
+// arguments of a call are matched against when its last argument is marked
+// VARIADIC. That argument is an array passed as the VARIADIC parameter of a
+// user-defined routine, so it is matched against the array type of the
+// parameter. Other overloads have no parameters, so that they match no such
+// call.
+func variadicCallParams(o *Overload) TypeList {
+	v, ok := o.Types.(VariadicType)
+	if !ok || o.Type == BuiltinRoutine {
+		return ParamTypes{}
+	}
+	ret := make(ParamTypes, len(v.FixedTypes)+1)
+	for i, typ := range v.FixedTypes {
+		ret[i] = ParamType{Typ: typ}
+	}
+	ret[len(v.FixedTypes)] = ParamType{Typ: types.MakeArray(v.VarType)}
+	return ret
+}
+
 // UnknownReturnType is returned from ReturnTypers when the arguments provided are
 // not sufficient to determine a return type. This is necessary for cases like overload
 // resolution, where the argument types are not resolved yet so the type-level function
@@ -1473,8 +1551,12 @@ func formatCandidates(prefix string, candidates []overloadImpl, filter []uint8)
 // TODO(chengxiong): unify this method with Overload.Signature method if possible.
 func getFuncSig(expr *FuncExpr, typedInputExprs []TypedExpr, desiredType *types.T) string {
 	typeNames := make([]string, 0, len(expr.Exprs))
-	for _, expr := range typedInputExprs {
-		typeNames = append(typeNames, expr.ResolvedType().String())
+	for i, typedExpr := range typedInputExprs {
+		typeName := typedExpr.ResolvedType().String()
+		if expr.Variadic && i == len(typedInputExprs)-1 {
+			typeName = "VARIADIC " + typeName
+		}
+		typeNames = append(typeNames, typeName)
 	}
 	var desStr string
 	if desiredType.Family() != types.AnyFamily {
@@ -79,6 +79,8 @@ func ParseAndRequireString(
 		d, err = ParseDGeometry(s)
 	case types.JsonFamily:
 		d, err = ParseDJSON(s)
+	case types.JsonpathFamily:
+		d, err = ParseDJsonpath(s)
 	case types.OidFamily:
 		if t.Oid() != oid.T_oid && s == ZeroOidValue {
 			d = WrapAsZeroOid(t)
@@ -106,6 +108,10 @@ func ParseAndRequireString(
 		if err == nil {
 			d = NewDEnum(e)
 		}
+	case types.RangeFamily:
+		d, dependsOnContext, err = ParseDRange(ctx, s, t)
+	case types.MultirangeFamily:
+		d, dependsOnContext, err = ParseDMultirange(ctx, s, t)
 	case types.TSQueryFamily:
 		d, err = ParseDTSQuery(s)
 	case types.TSVectorFamily:
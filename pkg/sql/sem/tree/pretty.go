@@ -721,6 +721,23 @@ func (node *AliasedTableExpr) doc(p *PrettyCfg) pretty.Doc {
 			),
 		)
 	}
+	if node.TableSample != nil {
+		d = p.nestUnder(d, p.Doc(node.TableSample))
+	}
+	return d
+}
+
+func (node *TableSample) doc(p *PrettyCfg) pretty.Doc {
+	d := pretty.ConcatSpace(
+		pretty.Keyword("TABLESAMPLE"),
+		pretty.ConcatSpace(p.Doc(&node.Method), p.bracket("(", p.Doc(&node.Args), ")")),
+	)
+	if node.Repeatable != nil {
+		d = pretty.ConcatSpace(
+			d,
+			pretty.ConcatSpace(pretty.Keyword("REPEATABLE"), p.bracket("(", p.Doc(node.Repeatable), ")")),
+		)
+	}
 	return d
 }
 
@@ -729,6 +746,16 @@ func (node *FuncExpr) doc(p *PrettyCfg) pretty.Doc {
 
 	if len(node.Exprs) > 0 {
 		args := node.Exprs.doc(p)
+		if node.Variadic {
+			last := len(node.Exprs) - 1
+			exprs := node.Exprs[:last]
+			variadic := pretty.ConcatSpace(pretty.Keyword("VARIADIC"), p.Doc(node.Exprs[last]))
+			if last > 0 {
+				args = p.commaSeparated(exprs.doc(p), variadic)
+			} else {
+				args = variadic
+			}
+		}
 		if node.Type != 0 {
 			args = pretty.ConcatLine(
 				pretty.Text(funcTypeName[node.Type]),
@@ -1289,6 +1316,9 @@ func (node *CreateTable) doc(p *PrettyCfg) pretty.Doc {
 	clauses := make([]pretty.Doc, 0, 4)
 	if node.As() {
 		clauses = append(clauses, p.Doc(node.AsSource))
+		if node.WithNoData {
+			clauses = append(clauses, pretty.Keyword("WITH NO DATA"))
+		}
 	}
 	if node.PartitionByTable != nil {
 		clauses = append(clauses, p.Doc(node.PartitionByTable))
@@ -1314,7 +1344,7 @@ func (node *CreateTable) doc(p *PrettyCfg) pretty.Doc {
 func (node *CreateView) doc(p *PrettyCfg) pretty.Doc {
 	// Final layout:
 	//
-	// CREATE [TEMP] VIEW name ( ... ) AS
+	// CREATE [TEMP] [RECURSIVE] VIEW name ( ... ) AS
 	//     SELECT ...
 	//
 	title := pretty.Keyword("CREATE")
@@ -1327,6 +1357,9 @@ func (node *CreateView) doc(p *PrettyCfg) pretty.Doc {
 	if node.Materialized {
 		title = pretty.ConcatSpace(title, pretty.Keyword("MATERIALIZED"))
 	}
+	if node.Recursive {
+		title = pretty.ConcatSpace(title, pretty.Keyword("RECURSIVE"))
+	}
 	title = pretty.ConcatSpace(title, pretty.Keyword("VIEW"))
 	if node.IfNotExists {
 		title = pretty.ConcatSpace(title, pretty.Keyword("IF NOT EXISTS"))
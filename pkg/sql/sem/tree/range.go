@@ -0,0 +1,1070 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// DRange is the Datum for range types, such as int4range and tstzrange. A
+// range is a contiguous set of values of its subtype, described by a lower
+// and an upper bound. Like in Postgres, ranges are always stored in a
+// canonical form:
+//   - an unbounded (infinite) bound is never inclusive;
+//   - ranges of discrete subtypes (integers and dates) use the [) form;
+//   - a range that contains no values is represented as the empty range.
+type DRange struct {
+	typ *types.T
+	// Lower and Upper are the bounds of the range. A nil bound is infinite.
+	Lower, Upper Datum
+	// LowerInc and UpperInc are true if the respective bound is inclusive.
+	LowerInc, UpperInc bool
+	// Empty is true for the empty range. All other fields except the type are
+	// unset in that case.
+	Empty bool
+}
+
+// Flags describing the shape of a range, as used by the Postgres binary format
+// for ranges.
+const (
+	// RangeFlagEmpty is set for the empty range.
+	RangeFlagEmpty = 0x01
+	// RangeFlagLowerInc is set if the lower bound is inclusive.
+	RangeFlagLowerInc = 0x02
+	// RangeFlagUpperInc is set if the upper bound is inclusive.
+	RangeFlagUpperInc = 0x04
+	// RangeFlagLowerInf is set if the lower bound is infinite.
+	RangeFlagLowerInf = 0x08
+	// RangeFlagUpperInf is set if the upper bound is infinite.
+	RangeFlagUpperInf = 0x10
+)
+
+// Flags returns the RangeFlag bits describing the range.
+func (r *DRange) Flags() byte {
+	if r.Empty {
+		return RangeFlagEmpty
+	}
+	var flags byte
+	if r.LowerInc {
+		flags |= RangeFlagLowerInc
+	}
+	if r.UpperInc {
+		flags |= RangeFlagUpperInc
+	}
+	if r.Lower == nil {
+		flags |= RangeFlagLowerInf
+	}
+	if r.Upper == nil {
+		flags |= RangeFlagUpperInf
+	}
+	return flags
+}
+
+// NewDEmptyRange returns the empty range of the given range type.
+func NewDEmptyRange(typ *types.T) *DRange {
+	return &DRange{typ: typ, Empty: true}
+}
+
+// NewDRange returns a range of the given range type with the given bounds,
+// converted to canonical form. DNull or nil bounds are infinite. An error is
+// returned if the lower bound is greater than the upper bound.
+func NewDRange(typ *types.T, lower, upper Datum, lowerInc, upperInc bool) (*DRange, error) {
+	if lower == DNull {
+		lower = nil
+	}
+	if upper == DNull {
+		upper = nil
+	}
+	if lower == nil {
+		lowerInc = false
+	}
+	if upper == nil {
+		upperInc = false
+	}
+	if lower != nil && upper != nil {
+		switch c := compareRangeValues(lower, upper); {
+		case c > 0:
+			return nil, pgerror.New(pgcode.DataException,
+				"range lower bound must be less than or equal to range upper bound")
+		case c == 0 && !(lowerInc && upperInc):
+			return NewDEmptyRange(typ), nil
+		}
+	}
+	if isDiscreteRangeType(typ) {
+		var err error
+		if lower != nil && !lowerInc {
+			if lower, err = nextDiscreteRangeValue(typ, lower); err != nil {
+				return nil, err
+			}
+			lowerInc = true
+		}
+		if upper != nil && upperInc {
+			if upper, err = nextDiscreteRangeValue(typ, upper); err != nil {
+				return nil, err
+			}
+			upperInc = false
+		}
+		if lower != nil && upper != nil && compareRangeValues(lower, upper) >= 0 {
+			return NewDEmptyRange(typ), nil
+		}
+	}
+	return &DRange{typ: typ, Lower: lower, Upper: upper, LowerInc: lowerInc, UpperInc: upperInc}, nil
+}
+
+// isDiscreteRangeType returns true if the subtype of the given range type has
+// a well-defined successor function, in which case ranges are canonicalized to
+// the [) form.
+func isDiscreteRangeType(typ *types.T) bool {
+	switch typ.RangeContents().Family() {
+	case types.IntFamily, types.DateFamily:
+		return true
+	}
+	return false
+}
+
+// nextDiscreteRangeValue returns the successor of a bound of a discrete range
+// type.
+func nextDiscreteRangeValue(typ *types.T, d Datum) (Datum, error) {
+	switch t := d.(type) {
+	case *DInt:
+		max := int64(math.MaxInt64)
+		if typ.RangeContents().Width() == 32 {
+			max = math.MaxInt32
+		}
+		if int64(*t) >= max {
+			return nil, pgerror.New(pgcode.NumericValueOutOfRange, "integer out of range")
+		}
+		return NewDInt(*t + 1), nil
+	case *DDate:
+		if !t.IsFinite() {
+			return t, nil
+		}
+		n, err := t.AddDays(1)
+		if err != nil {
+			return nil, err
+		}
+		return NewDDate(n), nil
+	}
+	return nil, errors.AssertionFailedf("unexpected range bound %T", d)
+}
+
+// compareRangeValues compares two bound values of the same range subtype.
+// Range bounds always have the same concrete type, so unlike Datum.Compare
+// this does not need a CompareContext.
+func compareRangeValues(a, b Datum) int {
+	switch l := a.(type) {
+	case *DInt:
+		r := *b.(*DInt)
+		if *l < r {
+			return -1
+		} else if *l > r {
+			return 1
+		}
+		return 0
+	case *DDecimal:
+		return l.Decimal.Cmp(&b.(*DDecimal).Decimal)
+	case *DDate:
+		return l.Date.Compare(b.(*DDate).Date)
+	case *DTimestamp:
+		return compareRangeTimes(l.Time, b.(*DTimestamp).Time)
+	case *DTimestampTZ:
+		return compareRangeTimes(l.Time, b.(*DTimestampTZ).Time)
+	}
+	panic(errors.AssertionFailedf("unexpected range bound %T", a))
+}
+
+func compareRangeTimes(a, b time.Time) int {
+	if a.Before(b) {
+		return -1
+	} else if a.After(b) {
+		return 1
+	}
+	return 0
+}
+
+// rangeBound is one of the bounds of a non-empty range. A nil val is an
+// infinite bound.
+type rangeBound struct {
+	val   Datum
+	inc   bool
+	lower bool
+}
+
+func (r *DRange) lowerBound() rangeBound {
+	return rangeBound{val: r.Lower, inc: r.LowerInc, lower: true}
+}
+
+func (r *DRange) upperBound() rangeBound {
+	return rangeBound{val: r.Upper, inc: r.UpperInc, lower: false}
+}
+
+// compareRangeBounds compares two range bounds, taking into account whether
+// they are lower or upper bounds, and whether they are inclusive. For example,
+// the lower bound of (1,2] sorts after the lower bound of [1,2], which sorts
+// after the upper bound of [0,1).
+func compareRangeBounds(b1, b2 rangeBound) int {
+	if b1.val == nil && b2.val == nil {
+		if b1.lower == b2.lower {
+			return 0
+		}
+		if b1.lower {
+			return -1
+		}
+		return 1
+	}
+	if b1.val == nil {
+		if b1.lower {
+			return -1
+		}
+		return 1
+	}
+	if b2.val == nil {
+		if b2.lower {
+			return 1
+		}
+		return -1
+	}
+	if c := compareRangeValues(b1.val, b2.val); c != 0 {
+		return c
+	}
+	switch {
+	case !b1.inc && !b2.inc:
+		if b1.lower == b2.lower {
+			return 0
+		}
+		if b1.lower {
+			return 1
+		}
+		return -1
+	case !b1.inc:
+		if b1.lower {
+			return 1
+		}
+		return -1
+	case !b2.inc:
+		if b2.lower {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// rangeBoundsAdjacent returns true if the given upper bound of one range and
+// lower bound of another range touch without overlapping.
+func rangeBoundsAdjacent(upper, lower rangeBound) bool {
+	if upper.val == nil || lower.val == nil {
+		return false
+	}
+	return compareRangeValues(upper.val, lower.val) == 0 && upper.inc != lower.inc
+}
+
+// makeRangeFromBounds returns the range with the given bounds.
+func makeRangeFromBounds(typ *types.T, lower, upper rangeBound) (*DRange, error) {
+	return NewDRange(typ, lower.val, upper.val, lower.inc, upper.inc)
+}
+
+// RangeOverlaps returns true if the two ranges have a value in common.
+func RangeOverlaps(r1, r2 *DRange) bool {
+	if r1.Empty || r2.Empty {
+		return false
+	}
+	return compareRangeBounds(r1.lowerBound(), r2.upperBound()) <= 0 &&
+		compareRangeBounds(r2.lowerBound(), r1.upperBound()) <= 0
+}
+
+// RangeContainsRange returns true if every value of r2 is in r1.
+func RangeContainsRange(r1, r2 *DRange) bool {
+	if r2.Empty {
+		return true
+	}
+	if r1.Empty {
+		return false
+	}
+	return compareRangeBounds(r1.lowerBound(), r2.lowerBound()) <= 0 &&
+		compareRangeBounds(r1.upperBound(), r2.upperBound()) >= 0
+}
+
+// RangeContainsElem returns true if the value d is in r.
+func RangeContainsElem(r *DRange, d Datum) bool {
+	if r.Empty {
+		return false
+	}
+	if r.Lower != nil {
+		if c := compareRangeValues(r.Lower, d); c > 0 || (c == 0 && !r.LowerInc) {
+			return false
+		}
+	}
+	if r.Upper != nil {
+		if c := compareRangeValues(r.Upper, d); c < 0 || (c == 0 && !r.UpperInc) {
+			return false
+		}
+	}
+	return true
+}
+
+// RangeAdjacent returns true if the two ranges touch without overlapping.
+func RangeAdjacent(r1, r2 *DRange) bool {
+	if r1.Empty || r2.Empty {
+		return false
+	}
+	return rangeBoundsAdjacent(r1.upperBound(), r2.lowerBound()) ||
+		rangeBoundsAdjacent(r2.upperBound(), r1.lowerBound())
+}
+
+// RangeBefore returns true if every value of r1 is less than every value of
+// r2.
+func RangeBefore(r1, r2 *DRange) bool {
+	if r1.Empty || r2.Empty {
+		return false
+	}
+	return compareRangeBounds(r1.upperBound(), r2.lowerBound()) < 0
+}
+
+// RangeUnion returns the union of two ranges. An error is returned if the
+// result would not be contiguous.
+func RangeUnion(r1, r2 *DRange) (*DRange, error) {
+	if !RangeOverlaps(r1, r2) && !RangeAdjacent(r1, r2) && !r1.Empty && !r2.Empty {
+		return nil, pgerror.New(pgcode.DataException,
+			"result of range union would not be contiguous")
+	}
+	return RangeMerge(r1, r2)
+}
+
+// RangeMerge returns the smallest range that contains both ranges.
+func RangeMerge(r1, r2 *DRange) (*DRange, error) {
+	if r1.Empty {
+		return r2, nil
+	}
+	if r2.Empty {
+		return r1, nil
+	}
+	lower, upper := r1.lowerBound(), r1.upperBound()
+	if compareRangeBounds(r2.lowerBound(), lower) < 0 {
+		lower = r2.lowerBound()
+	}
+	if compareRangeBounds(r2.upperBound(), upper) > 0 {
+		upper = r2.upperBound()
+	}
+	return makeRangeFromBounds(r1.typ, lower, upper)
+}
+
+// RangeIntersection returns the intersection of two ranges.
+func RangeIntersection(r1, r2 *DRange) (*DRange, error) {
+	if !RangeOverlaps(r1, r2) {
+		return NewDEmptyRange(r1.typ), nil
+	}
+	lower, upper := r1.lowerBound(), r1.upperBound()
+	if compareRangeBounds(r2.lowerBound(), lower) > 0 {
+		lower = r2.lowerBound()
+	}
+	if compareRangeBounds(r2.upperBound(), upper) < 0 {
+		upper = r2.upperBound()
+	}
+	return makeRangeFromBounds(r1.typ, lower, upper)
+}
+
+// rangeMinus returns the ranges of values of r1 that are not in r2. The
+// result has zero, one or two non-empty ranges.
+func rangeMinus(r1, r2 *DRange) ([]*DRange, error) {
+	if r1.Empty {
+		return nil, nil
+	}
+	if !RangeOverlaps(r1, r2) {
+		return []*DRange{r1}, nil
+	}
+	var res []*DRange
+	if compareRangeBounds(r1.lowerBound(), r2.lowerBound()) < 0 {
+		upper := r2.lowerBound()
+		upper.inc, upper.lower = !upper.inc, false
+		left, err := makeRangeFromBounds(r1.typ, r1.lowerBound(), upper)
+		if err != nil {
+			return nil, err
+		}
+		if !left.Empty {
+			res = append(res, left)
+		}
+	}
+	if compareRangeBounds(r1.upperBound(), r2.upperBound()) > 0 {
+		lower := r2.upperBound()
+		lower.inc, lower.lower = !lower.inc, true
+		right, err := makeRangeFromBounds(r1.typ, lower, r1.upperBound())
+		if err != nil {
+			return nil, err
+		}
+		if !right.Empty {
+			res = append(res, right)
+		}
+	}
+	return res, nil
+}
+
+// RangeDifference returns the values of r1 that are not in r2. An error is
+// returned if the result would not be contiguous.
+func RangeDifference(r1, r2 *DRange) (*DRange, error) {
+	res, err := rangeMinus(r1, r2)
+	if err != nil {
+		return nil, err
+	}
+	switch len(res) {
+	case 0:
+		return NewDEmptyRange(r1.typ), nil
+	case 1:
+		return res[0], nil
+	}
+	return nil, pgerror.New(pgcode.DataException,
+		"result of range difference would not be contiguous")
+}
+
+// compareRanges orders ranges first by their lower bound and then by their
+// upper bound. The empty range sorts before all other ranges.
+func compareRanges(r1, r2 *DRange) int {
+	switch {
+	case r1.Empty && r2.Empty:
+		return 0
+	case r1.Empty:
+		return -1
+	case r2.Empty:
+		return 1
+	}
+	if c := compareRangeBounds(r1.lowerBound(), r2.lowerBound()); c != 0 {
+		return c
+	}
+	return compareRangeBounds(r1.upperBound(), r2.upperBound())
+}
+
+// Format implements the NodeFormatter interface.
+func (r *DRange) Format(ctx *FmtCtx) {
+	bareStrings := ctx.HasFlags(FmtFlags(lexbase.EncBareStrings))
+	if !bareStrings {
+		ctx.WriteByte('\'')
+	}
+	r.formatRange(ctx, !bareStrings /* escapeQuotes */)
+	if !bareStrings {
+		ctx.WriteByte('\'')
+	}
+}
+
+// formatRange writes the Postgres text representation of the range, such as
+// [1,5) or empty.
+func (r *DRange) formatRange(ctx *FmtCtx, escapeQuotes bool) {
+	if r.Empty {
+		ctx.WriteString("empty")
+		return
+	}
+	if r.LowerInc {
+		ctx.WriteByte('[')
+	} else {
+		ctx.WriteByte('(')
+	}
+	formatRangeBound(ctx, r.Lower, escapeQuotes)
+	ctx.WriteByte(',')
+	formatRangeBound(ctx, r.Upper, escapeQuotes)
+	if r.UpperInc {
+		ctx.WriteByte(']')
+	} else {
+		ctx.WriteByte(')')
+	}
+}
+
+// formatRangeBound writes a bound of a range. Infinite bounds are written as
+// the empty string, and bounds that contain special characters are quoted.
+func formatRangeBound(ctx *FmtCtx, d Datum, escapeQuotes bool) {
+	if d == nil {
+		return
+	}
+	s := AsStringWithFlags(
+		d, FmtBareStrings, FmtDataConversionConfig(ctx.dataConversionConfig), FmtLocation(ctx.location),
+	)
+	if s != "" && !strings.ContainsAny(s, " \t\v\f\r\n()[],\"\\") {
+		ctx.WriteString(s)
+		return
+	}
+	ctx.WriteByte('"')
+	for _, c := range s {
+		switch c {
+		case '"', '\\':
+			ctx.WriteRune(c)
+		case '\'':
+			if escapeQuotes {
+				ctx.WriteRune(c)
+			}
+		}
+		ctx.WriteRune(c)
+	}
+	ctx.WriteByte('"')
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (r *DRange) ResolvedType() *types.T {
+	return r.typ
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (r *DRange) AmbiguousFormat() bool { return true }
+
+// Compare implements the Datum interface.
+func (r *DRange) Compare(ctx CompareContext, other Datum) int {
+	res, err := r.CompareError(ctx, other)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// CompareError implements the Datum interface.
+func (r *DRange) CompareError(ctx CompareContext, other Datum) (int, error) {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1, nil
+	}
+	v, ok := ctx.UnwrapDatum(other).(*DRange)
+	if !ok || v.typ.Oid() != r.typ.Oid() {
+		return 0, makeUnsupportedComparisonMessage(r, other)
+	}
+	return compareRanges(r, v), nil
+}
+
+// Prev implements the Datum interface.
+func (r *DRange) Prev(_ CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (r *DRange) Next(_ CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMin implements the Datum interface.
+func (r *DRange) IsMin(_ CompareContext) bool {
+	return r.Empty
+}
+
+// IsMax implements the Datum interface.
+func (r *DRange) IsMax(_ CompareContext) bool {
+	return false
+}
+
+// Max implements the Datum interface.
+func (r *DRange) Max(_ CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (r *DRange) Min(_ CompareContext) (Datum, bool) {
+	return NewDEmptyRange(r.typ), true
+}
+
+// Size implements the Datum interface.
+func (r *DRange) Size() uintptr {
+	sz := unsafe.Sizeof(*r)
+	if r.Lower != nil {
+		sz += r.Lower.Size()
+	}
+	if r.Upper != nil {
+		sz += r.Upper.Size()
+	}
+	return sz
+}
+
+// AsDRange attempts to retrieve a DRange from an Expr, returning a DRange and
+// a flag signifying whether the assertion was successful. The function should
+// be used instead of direct type assertions wherever a *DRange wrapped by a
+// *DOidWrapper is possible.
+func AsDRange(e Expr) (*DRange, bool) {
+	switch t := e.(type) {
+	case *DRange:
+		return t, true
+	case *DOidWrapper:
+		return AsDRange(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDRange attempts to retrieve a DRange from an Expr, panicking if the
+// assertion fails.
+func MustBeDRange(e Expr) *DRange {
+	v, ok := AsDRange(e)
+	if !ok {
+		panic(errors.AssertionFailedf("expected *DRange, found %T", e))
+	}
+	return v
+}
+
+// ParseDRange parses the Postgres text representation of a range of the given
+// range type, such as [1,5), (,"2020-01-01 00:00") or empty.
+func ParseDRange(ctx ParseContext, s string, typ *types.T) (_ Datum, dependsOnContext bool, _ error) {
+	p := rangeParser{s: s}
+	r, dependsOnContext, err := p.parseRange(ctx, typ)
+	if err != nil {
+		return nil, false, err
+	}
+	p.skipSpace()
+	if !p.eof() {
+		return nil, false, p.malformed()
+	}
+	return r, dependsOnContext, nil
+}
+
+// rangeParser parses the text representation of ranges and multiranges.
+type rangeParser struct {
+	s   string
+	pos int
+}
+
+func (p *rangeParser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *rangeParser) peek() byte {
+	return p.s[p.pos]
+}
+
+func (p *rangeParser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(rune(p.peek())) {
+		p.pos++
+	}
+}
+
+func (p *rangeParser) malformed() error {
+	return pgerror.Newf(pgcode.InvalidTextRepresentation, "malformed range literal: %q", p.s)
+}
+
+// parseRange parses a single range starting at the current position.
+func (p *rangeParser) parseRange(ctx ParseContext, typ *types.T) (*DRange, bool, error) {
+	p.skipSpace()
+	const empty = "empty"
+	if len(p.s)-p.pos >= len(empty) && strings.EqualFold(p.s[p.pos:p.pos+len(empty)], empty) {
+		p.pos += len(empty)
+		return NewDEmptyRange(typ), false, nil
+	}
+	if p.eof() || (p.peek() != '[' && p.peek() != '(') {
+		return nil, false, p.malformed()
+	}
+	lowerInc := p.peek() == '['
+	p.pos++
+	lower, lowerInf, err := p.parseBound(',')
+	if err != nil {
+		return nil, false, err
+	}
+	p.pos++
+	upper, upperInf, err := p.parseBound(']', ')')
+	if err != nil {
+		return nil, false, err
+	}
+	upperInc := p.peek() == ']'
+	p.pos++
+
+	var lowerDatum, upperDatum Datum
+	var dependsOnContext bool
+	subtype := typ.RangeContents()
+	if !lowerInf {
+		var dep bool
+		if lowerDatum, dep, err = ParseAndRequireString(subtype, lower, ctx); err != nil {
+			return nil, false, err
+		}
+		dependsOnContext = dependsOnContext || dep
+	}
+	if !upperInf {
+		var dep bool
+		if upperDatum, dep, err = ParseAndRequireString(subtype, upper, ctx); err != nil {
+			return nil, false, err
+		}
+		dependsOnContext = dependsOnContext || dep
+	}
+	r, err := NewDRange(typ, lowerDatum, upperDatum, lowerInc, upperInc)
+	return r, dependsOnContext, err
+}
+
+// parseBound parses a range bound up to, but not including, one of the given
+// terminators. Double quotes may be used to include special characters in the
+// bound, and a backslash escapes the following character. An empty unquoted
+// bound is infinite.
+func (p *rangeParser) parseBound(terminators ...byte) (_ string, infinite bool, _ error) {
+	var sb strings.Builder
+	quoted, inQuotes := false, false
+	for {
+		if p.eof() {
+			return "", false, p.malformed()
+		}
+		c := p.peek()
+		switch {
+		case c == '\\':
+			p.pos++
+			if p.eof() {
+				return "", false, p.malformed()
+			}
+			sb.WriteByte(p.peek())
+		case c == '"':
+			quoted = true
+			if inQuotes && p.pos+1 < len(p.s) && p.s[p.pos+1] == '"' {
+				// A doubled quote inside quotes is a literal quote.
+				sb.WriteByte('"')
+				p.pos++
+			} else {
+				inQuotes = !inQuotes
+			}
+		case !inQuotes && strings.IndexByte(string(terminators), c) >= 0:
+			if sb.Len() == 0 && !quoted {
+				return "", true, nil
+			}
+			return sb.String(), false, nil
+		case !inQuotes && strings.IndexByte("[](),", c) >= 0:
+			return "", false, p.malformed()
+		default:
+			sb.WriteByte(c)
+		}
+		p.pos++
+	}
+}
+
+// DMultirange is the Datum for multirange types, such as int4multirange. A
+// multirange is a set of values of its subtype, stored as an ordered list of
+// non-empty ranges that neither overlap nor are adjacent to each other.
+type DMultirange struct {
+	typ    *types.T
+	Ranges []*DRange
+}
+
+// NewDMultirange returns a multirange of the given multirange type containing
+// the values of the given ranges. The ranges are sorted and merged.
+func NewDMultirange(typ *types.T, ranges []*DRange) (*DMultirange, error) {
+	sorted := make([]*DRange, 0, len(ranges))
+	for _, r := range ranges {
+		if !r.Empty {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compareRanges(sorted[i], sorted[j]) < 0
+	})
+	res := &DMultirange{typ: typ, Ranges: sorted[:0]}
+	for _, r := range sorted {
+		if n := len(res.Ranges); n > 0 {
+			last := res.Ranges[n-1]
+			if RangeOverlaps(last, r) || RangeAdjacent(last, r) {
+				merged, err := RangeMerge(last, r)
+				if err != nil {
+					return nil, err
+				}
+				res.Ranges[n-1] = merged
+				continue
+			}
+		}
+		res.Ranges = append(res.Ranges, r)
+	}
+	return res, nil
+}
+
+// RangesOf returns the non-empty ranges of a range or multirange datum. The
+// result is ordered and normalized in the same way as the ranges of a
+// multirange.
+func RangesOf(d Datum) []*DRange {
+	d = UnwrapDOidWrapper(d)
+	if mr, ok := d.(*DMultirange); ok {
+		return mr.Ranges
+	}
+	if r := d.(*DRange); !r.Empty {
+		return []*DRange{r}
+	}
+	return nil
+}
+
+// RangesOverlap returns true if the two sets of ranges have a value in common.
+// Each set is either a range or a multirange.
+func RangesOverlap(left, right Datum) bool {
+	for _, r1 := range RangesOf(left) {
+		for _, r2 := range RangesOf(right) {
+			if RangeOverlaps(r1, r2) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RangesContain returns true if every value of right is in left. Each side is
+// either a range or a multirange.
+func RangesContain(left, right Datum) bool {
+	ranges := RangesOf(left)
+	for _, r2 := range RangesOf(right) {
+		// Since the ranges of left are neither overlapping nor adjacent, a
+		// contiguous range must be contained in a single one of them.
+		found := false
+		for _, r1 := range ranges {
+			if RangeContainsRange(r1, r2) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// RangesContainElem returns true if the value elem is in the given range or
+// multirange.
+func RangesContainElem(d Datum, elem Datum) bool {
+	for _, r := range RangesOf(d) {
+		if RangeContainsElem(r, elem) {
+			return true
+		}
+	}
+	return false
+}
+
+// RangesAdjacent returns true if the two sets of ranges touch without
+// overlapping. Each set is either a range or a multirange.
+func RangesAdjacent(left, right Datum) bool {
+	l, r := RangesOf(left), RangesOf(right)
+	if len(l) == 0 || len(r) == 0 {
+		return false
+	}
+	return RangeAdjacent(l[len(l)-1], r[0]) || RangeAdjacent(r[len(r)-1], l[0])
+}
+
+// RangesBefore returns true if every value of left is less than every value
+// of right. Each side is either a range or a multirange.
+func RangesBefore(left, right Datum) bool {
+	l, r := RangesOf(left), RangesOf(right)
+	if len(l) == 0 || len(r) == 0 {
+		return false
+	}
+	return RangeBefore(l[len(l)-1], r[0])
+}
+
+// MultirangeUnion returns the union of two multiranges.
+func MultirangeUnion(left, right *DMultirange) (*DMultirange, error) {
+	ranges := make([]*DRange, 0, len(left.Ranges)+len(right.Ranges))
+	ranges = append(ranges, left.Ranges...)
+	ranges = append(ranges, right.Ranges...)
+	return NewDMultirange(left.typ, ranges)
+}
+
+// MultirangeIntersection returns the intersection of two multiranges.
+func MultirangeIntersection(left, right *DMultirange) (*DMultirange, error) {
+	var ranges []*DRange
+	for _, r1 := range left.Ranges {
+		for _, r2 := range right.Ranges {
+			r, err := RangeIntersection(r1, r2)
+			if err != nil {
+				return nil, err
+			}
+			ranges = append(ranges, r)
+		}
+	}
+	return NewDMultirange(left.typ, ranges)
+}
+
+// MultirangeDifference returns the values of left that are not in right.
+func MultirangeDifference(left, right *DMultirange) (*DMultirange, error) {
+	remaining := append([]*DRange(nil), left.Ranges...)
+	for _, r2 := range right.Ranges {
+		var next []*DRange
+		for _, r1 := range remaining {
+			pieces, err := rangeMinus(r1, r2)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, pieces...)
+		}
+		remaining = next
+	}
+	return NewDMultirange(left.typ, remaining)
+}
+
+// Format implements the NodeFormatter interface.
+func (m *DMultirange) Format(ctx *FmtCtx) {
+	bareStrings := ctx.HasFlags(FmtFlags(lexbase.EncBareStrings))
+	if !bareStrings {
+		ctx.WriteByte('\'')
+	}
+	ctx.WriteByte('{')
+	for i, r := range m.Ranges {
+		if i > 0 {
+			ctx.WriteByte(',')
+		}
+		r.formatRange(ctx, !bareStrings /* escapeQuotes */)
+	}
+	ctx.WriteByte('}')
+	if !bareStrings {
+		ctx.WriteByte('\'')
+	}
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (m *DMultirange) ResolvedType() *types.T {
+	return m.typ
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (m *DMultirange) AmbiguousFormat() bool { return true }
+
+// Compare implements the Datum interface.
+func (m *DMultirange) Compare(ctx CompareContext, other Datum) int {
+	res, err := m.CompareError(ctx, other)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// CompareError implements the Datum interface.
+func (m *DMultirange) CompareError(ctx CompareContext, other Datum) (int, error) {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1, nil
+	}
+	v, ok := ctx.UnwrapDatum(other).(*DMultirange)
+	if !ok || v.typ.Oid() != m.typ.Oid() {
+		return 0, makeUnsupportedComparisonMessage(m, other)
+	}
+	for i := 0; i < len(m.Ranges) && i < len(v.Ranges); i++ {
+		if c := compareRanges(m.Ranges[i], v.Ranges[i]); c != 0 {
+			return c, nil
+		}
+	}
+	switch {
+	case len(m.Ranges) < len(v.Ranges):
+		return -1, nil
+	case len(m.Ranges) > len(v.Ranges):
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Prev implements the Datum interface.
+func (m *DMultirange) Prev(_ CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (m *DMultirange) Next(_ CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMin implements the Datum interface.
+func (m *DMultirange) IsMin(_ CompareContext) bool {
+	return len(m.Ranges) == 0
+}
+
+// IsMax implements the Datum interface.
+func (m *DMultirange) IsMax(_ CompareContext) bool {
+	return false
+}
+
+// Max implements the Datum interface.
+func (m *DMultirange) Max(_ CompareContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (m *DMultirange) Min(_ CompareContext) (Datum, bool) {
+	return &DMultirange{typ: m.typ}, true
+}
+
+// Size implements the Datum interface.
+func (m *DMultirange) Size() uintptr {
+	sz := unsafe.Sizeof(*m)
+	for _, r := range m.Ranges {
+		sz += r.Size()
+	}
+	return sz
+}
+
+// AsDMultirange attempts to retrieve a DMultirange from an Expr, returning a
+// DMultirange and a flag signifying whether the assertion was successful. The
+// function should be used instead of direct type assertions wherever a
+// *DMultirange wrapped by a *DOidWrapper is possible.
+func AsDMultirange(e Expr) (*DMultirange, bool) {
+	switch t := e.(type) {
+	case *DMultirange:
+		return t, true
+	case *DOidWrapper:
+		return AsDMultirange(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDMultirange attempts to retrieve a DMultirange from an Expr,
+// panicking if the assertion fails.
+func MustBeDMultirange(e Expr) *DMultirange {
+	v, ok := AsDMultirange(e)
+	if !ok {
+		panic(errors.AssertionFailedf("expected *DMultirange, found %T", e))
+	}
+	return v
+}
+
+// ParseDMultirange parses the Postgres text representation of a multirange
+// of the given multirange type, such as {[1,3),[5,7)}.
+func ParseDMultirange(
+	ctx ParseContext, s string, typ *types.T,
+) (_ Datum, dependsOnContext bool, _ error) {
+	p := rangeParser{s: s}
+	malformed := func() error {
+		return pgerror.Newf(pgcode.InvalidTextRepresentation, "malformed multirange literal: %q", s)
+	}
+	p.skipSpace()
+	if p.eof() || p.peek() != '{' {
+		return nil, false, malformed()
+	}
+	p.pos++
+	rangeTyp := types.MultirangeRangeType(typ)
+	var ranges []*DRange
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return nil, false, malformed()
+		}
+		if p.peek() == '}' && len(ranges) == 0 {
+			p.pos++
+			break
+		}
+		r, dep, err := p.parseRange(ctx, rangeTyp)
+		if err != nil {
+			return nil, false, err
+		}
+		dependsOnContext = dependsOnContext || dep
+		ranges = append(ranges, r)
+		p.skipSpace()
+		if p.eof() {
+			return nil, false, malformed()
+		}
+		c := p.peek()
+		p.pos++
+		if c == '}' {
+			break
+		}
+		if c != ',' {
+			return nil, false, malformed()
+		}
+	}
+	p.skipSpace()
+	if !p.eof() {
+		return nil, false, malformed()
+	}
+	mr, err := NewDMultirange(typ, ranges)
+	return mr, dependsOnContext, err
+}
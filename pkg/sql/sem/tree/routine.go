@@ -199,6 +199,29 @@ type RoutineExceptionHandler struct {
 	Actions []*RoutineExpr
 }
 
+// AggregateRoutines contains the routines that are used to evaluate a
+// user-defined aggregate function. The arguments of the routines are supplied
+// when they are invoked, so the Args of Transition and Final are ignored.
+type AggregateRoutines struct {
+	// Name is the name of the aggregate function.
+	Name string
+
+	// Transition computes the next state from the current state and the
+	// arguments of the aggregate for an input row.
+	Transition *RoutineExpr
+
+	// Final computes the result of the aggregate from the final state. If it is
+	// nil, the result is the final state.
+	Final *RoutineExpr
+
+	// StateType is the type of the aggregate state.
+	StateType *types.T
+
+	// InitCond is the initial state. If it is nil, the state is NULL until the
+	// first input row is processed.
+	InitCond Datum
+}
+
 // RoutineOpenCursor stores the information needed to correctly open a cursor
 // with the output of a routine.
 type RoutineOpenCursor struct {
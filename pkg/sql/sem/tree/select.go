@@ -573,6 +573,8 @@ type AliasedTableExpr struct {
 	Ordinality bool
 	Lateral    bool
 	As         AliasClause
+	// TableSample is set if the table is scanned with a TABLESAMPLE clause.
+	TableSample *TableSample
 }
 
 // Format implements the NodeFormatter interface.
@@ -591,6 +593,38 @@ func (node *AliasedTableExpr) Format(ctx *FmtCtx) {
 		ctx.WriteString(" AS ")
 		ctx.FormatNode(&node.As)
 	}
+	if node.TableSample != nil {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(node.TableSample)
+	}
+}
+
+// TableSample represents a TABLESAMPLE clause, which scans a random sample of
+// the rows of a table. Method is the sampling method, e.g. BERNOULLI or
+// SYSTEM, and Args are the arguments of the method.
+type TableSample struct {
+	Method Name
+	Args   Exprs
+	// Repeatable is the seed of the REPEATABLE clause, if any.
+	Repeatable Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *TableSample) Format(ctx *FmtCtx) {
+	ctx.WriteString("TABLESAMPLE ")
+	// NB: we do not anonymize the sampling method, which is one of a fixed
+	// set of built-in methods.
+	ctx.WithFlags(ctx.flags&^FmtAnonymize, func() {
+		ctx.FormatNode(&node.Method)
+	})
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Args)
+	ctx.WriteByte(')')
+	if node.Repeatable != nil {
+		ctx.WriteString(" REPEATABLE (")
+		ctx.FormatNode(node.Repeatable)
+		ctx.WriteByte(')')
+	}
 }
 
 // ParenTableExpr represents a parenthesized TableExpr.
@@ -758,6 +792,47 @@ func (node *GroupBy) Format(ctx *FmtCtx) {
 	}
 }
 
+// GroupingSetType represents the kind of a GroupingSet.
+type GroupingSetType int
+
+const (
+	// GroupingSets represents GROUPING SETS (...).
+	GroupingSets GroupingSetType = iota
+	// RollupGroupingSet represents ROLLUP (...).
+	RollupGroupingSet
+	// CubeGroupingSet represents CUBE (...).
+	CubeGroupingSet
+)
+
+var groupingSetTypeName = [...]string{
+	GroupingSets:      "GROUPING SETS",
+	RollupGroupingSet: "ROLLUP",
+	CubeGroupingSet:   "CUBE",
+}
+
+func (t GroupingSetType) String() string {
+	return groupingSetTypeName[t]
+}
+
+// GroupingSet represents a GROUPING SETS, ROLLUP or CUBE item in a GROUP BY
+// clause. For ROLLUP and CUBE, each of the Exprs is either a single
+// expression or a parenthesized list of expressions that is treated as a
+// unit. For GROUPING SETS, each of the Exprs is a grouping set itself: an
+// expression, a parenthesized (and possibly empty) list of expressions, or
+// a nested GroupingSet.
+type GroupingSet struct {
+	Type  GroupingSetType
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingSet) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.Type.String())
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // DistinctOn represents a DISTINCT ON clause.
 type DistinctOn []Expr
 
@@ -143,6 +143,30 @@ func (node *SetSessionCharacteristics) Format(ctx *FmtCtx) {
 	ctx.FormatNode(&node.Modes)
 }
 
+// SetConstraints represents a SET CONSTRAINTS { ALL | name [, ...] }
+// { DEFERRED | IMMEDIATE } statement.
+type SetConstraints struct {
+	// Names is empty for SET CONSTRAINTS ALL.
+	Names    NameList
+	Deferred bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *SetConstraints) Format(ctx *FmtCtx) {
+	ctx.WriteString("SET CONSTRAINTS ")
+	if len(node.Names) == 0 {
+		ctx.WriteString("ALL")
+	} else {
+		ctx.FormatNode(&node.Names)
+	}
+	ctx.WriteByte(' ')
+	if node.Deferred {
+		ctx.WriteString("DEFERRED")
+	} else {
+		ctx.WriteString("IMMEDIATE")
+	}
+}
+
 // SetTracing represents a SET TRACING statement.
 type SetTracing struct {
 	Values Exprs
@@ -164,7 +164,7 @@ func CanWriteData(stmt Statement) bool {
 	}
 	switch stmt.(type) {
 	// Normal write operations.
-	case *Insert, *Delete, *Update, *Truncate:
+	case *Insert, *Delete, *Update, *Merge, *Truncate:
 		return true
 	// Import operations.
 	case *CopyFrom, *Import, *Restore:
@@ -417,6 +417,15 @@ func (*AlterDefaultPrivileges) StatementType() StatementType { return TypeDCL }
 // StatementTag returns a short string identifying the type of statement.
 func (*AlterDefaultPrivileges) StatementTag() string { return "ALTER DEFAULT PRIVILEGES" }
 
+// StatementReturnType implements the Statement interface.
+func (*AlterDomain) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*AlterDomain) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*AlterDomain) StatementTag() string { return "ALTER DOMAIN" }
+
 // StatementReturnType implements the Statement interface.
 func (*AlterIndex) StatementReturnType() StatementReturnType { return DDL }
 
@@ -653,7 +662,7 @@ func (*BeginTransaction) StatementType() StatementType { return TypeTCL }
 func (*BeginTransaction) StatementTag() string { return "BEGIN" }
 
 // StatementReturnType implements the Statement interface.
-func (*Call) StatementReturnType() StatementReturnType { return Ack }
+func (*Call) StatementReturnType() StatementReturnType { return Rows }
 
 // StatementType implements the Statement interface.
 func (*Call) StatementType() StatementType { return TypeTCL }
@@ -956,10 +965,51 @@ func (*CreateType) StatementReturnType() StatementReturnType { return DDL }
 func (*CreateType) StatementType() StatementType { return TypeDDL }
 
 // StatementTag implements the Statement interface.
-func (*CreateType) StatementTag() string { return "CREATE TYPE" }
+func (n *CreateType) StatementTag() string {
+	if n.Variety == Domain {
+		return "CREATE DOMAIN"
+	}
+	return "CREATE TYPE"
+}
 
 func (*CreateType) modifiesSchema() bool { return true }
 
+// StatementReturnType implements the Statement interface.
+func (*CreatePolicy) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*CreatePolicy) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreatePolicy) StatementTag() string { return "CREATE POLICY" }
+
+// StatementReturnType implements the Statement interface.
+func (*CreatePublication) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*CreatePublication) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreatePublication) StatementTag() string { return "CREATE PUBLICATION" }
+
+// StatementReturnType implements the Statement interface.
+func (*CreateSubscription) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*CreateSubscription) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateSubscription) StatementTag() string { return "CREATE SUBSCRIPTION" }
+
+// StatementReturnType implements the Statement interface.
+func (*CreateTrigger) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*CreateTrigger) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateTrigger) StatementTag() string { return "CREATE TRIGGER" }
+
 // StatementReturnType implements the Statement interface.
 func (*CreateRole) StatementReturnType() StatementReturnType { return DDL }
 
@@ -1055,6 +1105,15 @@ func (*DropDatabase) StatementType() StatementType { return TypeDDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*DropDatabase) StatementTag() string { return DropDatabaseTag }
 
+// StatementReturnType implements the Statement interface.
+func (*DropDomain) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*DropDomain) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropDomain) StatementTag() string { return "DROP DOMAIN" }
+
 // StatementReturnType implements the Statement interface.
 func (*DropIndex) StatementReturnType() StatementReturnType { return DDL }
 
@@ -1111,6 +1170,42 @@ func (*DropType) StatementType() StatementType { return TypeDDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*DropType) StatementTag() string { return DropTypeTag }
 
+// StatementReturnType implements the Statement interface.
+func (*DropPolicy) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*DropPolicy) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropPolicy) StatementTag() string { return "DROP POLICY" }
+
+// StatementReturnType implements the Statement interface.
+func (*DropPublication) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*DropPublication) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropPublication) StatementTag() string { return "DROP PUBLICATION" }
+
+// StatementReturnType implements the Statement interface.
+func (*DropSubscription) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*DropSubscription) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropSubscription) StatementTag() string { return "DROP SUBSCRIPTION" }
+
+// StatementReturnType implements the Statement interface.
+func (*DropTrigger) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*DropTrigger) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropTrigger) StatementTag() string { return "DROP TRIGGER" }
+
 // StatementReturnType implements the Statement interface.
 func (*DropSchema) StatementReturnType() StatementReturnType { return DDL }
 
@@ -1223,6 +1318,15 @@ func (*Import) StatementTag() string { return ImportTag }
 
 func (*Import) cclOnlyStatement() {}
 
+// StatementReturnType implements the Statement interface.
+func (*Listen) StatementReturnType() StatementReturnType { return Ack }
+
+// StatementType implements the Statement interface.
+func (*Listen) StatementType() StatementType { return TypeTCL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*Listen) StatementTag() string { return "LISTEN" }
+
 // StatementReturnType implements the Statement interface.
 func (*LiteralValuesClause) StatementReturnType() StatementReturnType { return Rows }
 
@@ -1232,6 +1336,24 @@ func (*LiteralValuesClause) StatementType() StatementType { return TypeDML }
 // StatementTag returns a short string identifying the type of statement.
 func (*LiteralValuesClause) StatementTag() string { return "VALUES" }
 
+// StatementReturnType implements the Statement interface.
+func (n *Merge) StatementReturnType() StatementReturnType { return n.Returning.statementReturnType() }
+
+// StatementType implements the Statement interface.
+func (*Merge) StatementType() StatementType { return TypeDML }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*Merge) StatementTag() string { return "MERGE" }
+
+// StatementReturnType implements the Statement interface.
+func (*Notify) StatementReturnType() StatementReturnType { return Ack }
+
+// StatementType implements the Statement interface.
+func (*Notify) StatementType() StatementType { return TypeTCL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*Notify) StatementTag() string { return "NOTIFY" }
+
 // StatementReturnType implements the Statement interface.
 func (*ParenSelect) StatementReturnType() StatementReturnType { return Rows }
 
@@ -1484,6 +1606,15 @@ func (*SetClusterSetting) StatementType() StatementType { return TypeDCL }
 // StatementTag returns a short string identifying the type of statement.
 func (*SetClusterSetting) StatementTag() string { return "SET CLUSTER SETTING" }
 
+// StatementReturnType implements the Statement interface.
+func (*SetConstraints) StatementReturnType() StatementReturnType { return Ack }
+
+// StatementType implements the Statement interface.
+func (*SetConstraints) StatementType() StatementType { return TypeTCL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*SetConstraints) StatementTag() string { return "SET CONSTRAINTS" }
+
 // StatementReturnType implements the Statement interface.
 func (*SetTransaction) StatementReturnType() StatementReturnType { return Ack }
 
@@ -1759,6 +1890,15 @@ func (*ShowSessions) StatementType() StatementType { return TypeDML }
 // StatementTag returns a short string identifying the type of statement.
 func (*ShowSessions) StatementTag() string { return "SHOW SESSIONS" }
 
+// StatementReturnType implements the Statement interface.
+func (*ShowSubscriptions) StatementReturnType() StatementReturnType { return Rows }
+
+// StatementType implements the Statement interface.
+func (*ShowSubscriptions) StatementType() StatementType { return TypeDML }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*ShowSubscriptions) StatementTag() string { return "SHOW SUBSCRIPTIONS" }
+
 // StatementReturnType implements the Statement interface.
 func (*ShowTableStats) StatementReturnType() StatementReturnType { return Rows }
 
@@ -2129,6 +2269,69 @@ func (n *CreateRoutine) StatementTag() string {
 	return CreateFunctionTag
 }
 
+// StatementReturnType implements the Statement interface.
+func (*CreateAggregate) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*CreateAggregate) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateAggregate) StatementTag() string { return "CREATE AGGREGATE" }
+
+// StatementReturnType implements the Statement interface.
+func (*CreateTextSearchConfig) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*CreateTextSearchConfig) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateTextSearchConfig) StatementTag() string { return "CREATE TEXT SEARCH CONFIGURATION" }
+
+// StatementReturnType implements the Statement interface.
+func (*CreateTextSearchDictionary) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*CreateTextSearchDictionary) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateTextSearchDictionary) StatementTag() string { return "CREATE TEXT SEARCH DICTIONARY" }
+
+// StatementReturnType implements the Statement interface.
+func (*AlterTextSearchConfig) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*AlterTextSearchConfig) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*AlterTextSearchConfig) StatementTag() string { return "ALTER TEXT SEARCH CONFIGURATION" }
+
+// StatementReturnType implements the Statement interface.
+func (*AlterTextSearchDictionary) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*AlterTextSearchDictionary) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*AlterTextSearchDictionary) StatementTag() string { return "ALTER TEXT SEARCH DICTIONARY" }
+
+// StatementReturnType implements the Statement interface.
+func (*DropTextSearchConfig) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*DropTextSearchConfig) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropTextSearchConfig) StatementTag() string { return "DROP TEXT SEARCH CONFIGURATION" }
+
+// StatementReturnType implements the Statement interface.
+func (*DropTextSearchDictionary) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*DropTextSearchDictionary) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropTextSearchDictionary) StatementTag() string { return "DROP TEXT SEARCH DICTIONARY" }
+
 // StatementReturnType implements the Statement interface.
 func (*RoutineReturn) StatementReturnType() StatementReturnType { return Rows }
 
@@ -2149,6 +2352,9 @@ func (n *DropRoutine) StatementTag() string {
 	if n.Procedure {
 		return DropProcedureTag
 	}
+	if n.Aggregate {
+		return "DROP AGGREGATE"
+	}
 	return DropFunctionTag
 }
 
@@ -2171,6 +2377,8 @@ func (*AlterRoutineRename) StatementType() StatementType { return TypeDDL }
 func (n *AlterRoutineRename) StatementTag() string {
 	if n.Procedure {
 		return "ALTER PROCEDURE"
+	} else if n.Aggregate {
+		return "ALTER AGGREGATE"
 	} else {
 		return "ALTER FUNCTION"
 	}
@@ -2186,6 +2394,8 @@ func (*AlterRoutineSetSchema) StatementType() StatementType { return TypeDDL }
 func (n *AlterRoutineSetSchema) StatementTag() string {
 	if n.Procedure {
 		return "ALTER PROCEDURE"
+	} else if n.Aggregate {
+		return "ALTER AGGREGATE"
 	} else {
 		return "ALTER FUNCTION"
 	}
@@ -2201,6 +2411,8 @@ func (*AlterRoutineSetOwner) StatementType() StatementType { return TypeDDL }
 func (n *AlterRoutineSetOwner) StatementTag() string {
 	if n.Procedure {
 		return "ALTER PROCEDURE"
+	} else if n.Aggregate {
+		return "ALTER AGGREGATE"
 	} else {
 		return "ALTER FUNCTION"
 	}
@@ -2235,6 +2447,7 @@ func (n *AlterDatabaseSecondaryRegion) String() string        { return AsString(
 func (n *AlterDatabaseDropSecondaryRegion) String() string    { return AsString(n) }
 func (n *AlterDatabaseSetZoneConfigExtension) String() string { return AsString(n) }
 func (n *AlterDefaultPrivileges) String() string              { return AsString(n) }
+func (n *AlterDomain) String() string                         { return AsString(n) }
 func (n *AlterFunctionOptions) String() string                { return AsString(n) }
 func (n *AlterRoutineRename) String() string                  { return AsString(n) }
 func (n *AlterRoutineSetSchema) String() string               { return AsString(n) }
@@ -2258,6 +2471,8 @@ func (n *AlterTableOwner) String() string                     { return AsString(
 func (n *AlterTableSetSchema) String() string                 { return AsString(n) }
 func (n *AlterTenantCapability) String() string               { return AsString(n) }
 func (n *AlterTenantSetClusterSetting) String() string        { return AsString(n) }
+func (n *AlterTextSearchConfig) String() string               { return AsString(n) }
+func (n *AlterTextSearchDictionary) String() string           { return AsString(n) }
 func (n *AlterTenantRename) String() string                   { return AsString(n) }
 func (n *AlterTenantReplication) String() string              { return AsString(n) }
 func (n *AlterTenantService) String() string                  { return AsString(n) }
@@ -2286,33 +2501,47 @@ func (n *CommentOnTable) String() string                      { return AsString(
 func (n *CommitTransaction) String() string                   { return AsString(n) }
 func (n *CopyFrom) String() string                            { return AsString(n) }
 func (n *CopyTo) String() string                              { return AsString(n) }
+func (n *CreateAggregate) String() string                     { return AsString(n) }
 func (n *CreateChangefeed) String() string                    { return AsString(n) }
 func (n *CreateDatabase) String() string                      { return AsString(n) }
 func (n *CreateExtension) String() string                     { return AsString(n) }
 func (n *CreateRoutine) String() string                       { return AsString(n) }
 func (n *CreateIndex) String() string                         { return AsString(n) }
+func (n *CreatePolicy) String() string                        { return AsString(n) }
+func (n *CreatePublication) String() string                   { return AsString(n) }
 func (n *CreateRole) String() string                          { return AsString(n) }
 func (n *CreateTable) String() string                         { return AsString(n) }
+func (n *CreateTrigger) String() string                       { return AsString(n) }
 func (n *CreateTenant) String() string                        { return AsString(n) }
 func (n *CreateTenantFromReplication) String() string         { return AsString(n) }
 func (n *CreateSchema) String() string                        { return AsString(n) }
 func (n *CreateSequence) String() string                      { return AsString(n) }
 func (n *CreateStats) String() string                         { return AsString(n) }
+func (n *CreateSubscription) String() string                  { return AsString(n) }
+func (n *CreateTextSearchConfig) String() string              { return AsString(n) }
+func (n *CreateTextSearchDictionary) String() string          { return AsString(n) }
 func (n *CreateView) String() string                          { return AsString(n) }
 func (n *Deallocate) String() string                          { return AsString(n) }
 func (n *Delete) String() string                              { return AsString(n) }
 func (n *DeclareCursor) String() string                       { return AsString(n) }
 func (n *DropDatabase) String() string                        { return AsString(n) }
+func (n *DropDomain) String() string                          { return AsString(n) }
 func (n *DropRoutine) String() string                         { return AsString(n) }
 func (n *DropIndex) String() string                           { return AsString(n) }
 func (n *DropOwnedBy) String() string                         { return AsString(n) }
 func (n *DropSchema) String() string                          { return AsString(n) }
 func (n *DropSequence) String() string                        { return AsString(n) }
 func (n *DropTable) String() string                           { return AsString(n) }
+func (n *DropTrigger) String() string                         { return AsString(n) }
 func (n *DropType) String() string                            { return AsString(n) }
 func (n *DropView) String() string                            { return AsString(n) }
+func (n *DropPolicy) String() string                          { return AsString(n) }
+func (n *DropPublication) String() string                     { return AsString(n) }
 func (n *DropRole) String() string                            { return AsString(n) }
+func (n *DropSubscription) String() string                    { return AsString(n) }
 func (n *DropTenant) String() string                          { return AsString(n) }
+func (n *DropTextSearchConfig) String() string                { return AsString(n) }
+func (n *DropTextSearchDictionary) String() string            { return AsString(n) }
 func (n *Execute) String() string                             { return AsString(n) }
 func (n *Explain) String() string                             { return AsString(n) }
 func (n *ExplainAnalyze) String() string                      { return AsString(n) }
@@ -2326,6 +2555,7 @@ func (n *MoveCursor) String() string                          { return AsString(
 func (n *Insert) String() string                              { return AsString(n) }
 func (n *Import) String() string                              { return AsString(n) }
 func (n *LiteralValuesClause) String() string                 { return AsString(n) }
+func (n *Merge) String() string                               { return AsString(n) }
 func (n *ParenSelect) String() string                         { return AsString(n) }
 func (n *Prepare) String() string                             { return AsString(n) }
 func (n *ReassignOwnedBy) String() string                     { return AsString(n) }
@@ -2351,6 +2581,7 @@ func (n *Scrub) String() string                               { return AsString(
 func (n *Select) String() string                              { return AsString(n) }
 func (n *SelectClause) String() string                        { return AsString(n) }
 func (n *SetClusterSetting) String() string                   { return AsString(n) }
+func (n *SetConstraints) String() string                      { return AsString(n) }
 func (n *SetZoneConfig) String() string                       { return AsString(n) }
 func (n *SetSessionAuthorizationDefault) String() string      { return AsString(n) }
 func (n *SetSessionCharacteristics) String() string           { return AsString(n) }
@@ -2394,6 +2625,7 @@ func (n *ShowSavepointStatus) String() string                 { return AsString(
 func (n *ShowSchemas) String() string                         { return AsString(n) }
 func (n *ShowSequences) String() string                       { return AsString(n) }
 func (n *ShowSessions) String() string                        { return AsString(n) }
+func (n *ShowSubscriptions) String() string                   { return AsString(n) }
 func (n *ShowSurvivalGoal) String() string                    { return AsString(n) }
 func (n *ShowSyntax) String() string                          { return AsString(n) }
 func (n *ShowTableStats) String() string                      { return AsString(n) }
@@ -0,0 +1,187 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// CreateTextSearchConfig represents a CREATE TEXT SEARCH CONFIGURATION
+// statement.
+type CreateTextSearchConfig struct {
+	Name Name
+	// Options are either PARSER = name or COPY = config.
+	Options StorageParams
+}
+
+var _ Statement = &CreateTextSearchConfig{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateTextSearchConfig) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE TEXT SEARCH CONFIGURATION ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Options)
+	ctx.WriteString(")")
+}
+
+// CreateTextSearchDictionary represents a CREATE TEXT SEARCH DICTIONARY
+// statement.
+type CreateTextSearchDictionary struct {
+	Name Name
+	// Options include the TEMPLATE of the dictionary, and the options of the
+	// template.
+	Options StorageParams
+}
+
+var _ Statement = &CreateTextSearchDictionary{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateTextSearchDictionary) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE TEXT SEARCH DICTIONARY ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Options)
+	ctx.WriteString(")")
+}
+
+// AlterTextSearchConfigCmd is the command of an ALTER TEXT SEARCH
+// CONFIGURATION statement.
+type AlterTextSearchConfigCmd int
+
+const (
+	// AlterTextSearchConfigAddMapping is ADD MAPPING FOR ... WITH ...
+	AlterTextSearchConfigAddMapping AlterTextSearchConfigCmd = iota
+	// AlterTextSearchConfigAlterMapping is ALTER MAPPING FOR ... WITH ...
+	AlterTextSearchConfigAlterMapping
+	// AlterTextSearchConfigReplaceDictionary is ALTER MAPPING [FOR ...] REPLACE
+	// ... WITH ...
+	AlterTextSearchConfigReplaceDictionary
+	// AlterTextSearchConfigDropMapping is DROP MAPPING [IF EXISTS] FOR ...
+	AlterTextSearchConfigDropMapping
+)
+
+// AlterTextSearchConfig represents an ALTER TEXT SEARCH CONFIGURATION
+// statement.
+type AlterTextSearchConfig struct {
+	Name Name
+	Cmd  AlterTextSearchConfigCmd
+	// TokenTypes are the token types of the FOR clause, which is optional for
+	// AlterTextSearchConfigReplaceDictionary.
+	TokenTypes NameList
+	// Dictionaries are the dictionaries of the WITH clause of
+	// AlterTextSearchConfigAddMapping and AlterTextSearchConfigAlterMapping.
+	Dictionaries NameList
+	// OldDictionary and NewDictionary are set for
+	// AlterTextSearchConfigReplaceDictionary.
+	OldDictionary Name
+	NewDictionary Name
+	// IfExists is set for DROP MAPPING IF EXISTS.
+	IfExists bool
+}
+
+var _ Statement = &AlterTextSearchConfig{}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTextSearchConfig) Format(ctx *FmtCtx) {
+	ctx.WriteString("ALTER TEXT SEARCH CONFIGURATION ")
+	ctx.FormatNode(&node.Name)
+	switch node.Cmd {
+	case AlterTextSearchConfigAddMapping:
+		ctx.WriteString(" ADD MAPPING FOR ")
+		ctx.FormatNode(&node.TokenTypes)
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.Dictionaries)
+	case AlterTextSearchConfigAlterMapping:
+		ctx.WriteString(" ALTER MAPPING FOR ")
+		ctx.FormatNode(&node.TokenTypes)
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.Dictionaries)
+	case AlterTextSearchConfigReplaceDictionary:
+		ctx.WriteString(" ALTER MAPPING")
+		if len(node.TokenTypes) > 0 {
+			ctx.WriteString(" FOR ")
+			ctx.FormatNode(&node.TokenTypes)
+		}
+		ctx.WriteString(" REPLACE ")
+		ctx.FormatNode(&node.OldDictionary)
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.NewDictionary)
+	case AlterTextSearchConfigDropMapping:
+		ctx.WriteString(" DROP MAPPING ")
+		if node.IfExists {
+			ctx.WriteString("IF EXISTS ")
+		}
+		ctx.WriteString("FOR ")
+		ctx.FormatNode(&node.TokenTypes)
+	}
+}
+
+// AlterTextSearchDictionary represents an ALTER TEXT SEARCH DICTIONARY
+// statement.
+type AlterTextSearchDictionary struct {
+	Name Name
+	// Options are the options of the template of the dictionary to change.
+	Options StorageParams
+}
+
+var _ Statement = &AlterTextSearchDictionary{}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTextSearchDictionary) Format(ctx *FmtCtx) {
+	ctx.WriteString("ALTER TEXT SEARCH DICTIONARY ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Options)
+	ctx.WriteString(")")
+}
+
+// DropTextSearchConfig represents a DROP TEXT SEARCH CONFIGURATION statement.
+type DropTextSearchConfig struct {
+	Names        NameList
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropTextSearchConfig{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropTextSearchConfig) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP TEXT SEARCH CONFIGURATION ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Names)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
+
+// DropTextSearchDictionary represents a DROP TEXT SEARCH DICTIONARY statement.
+type DropTextSearchDictionary struct {
+	Names    NameList
+	IfExists bool
+	// DropBehavior CASCADE removes the dictionaries from the mappings of the
+	// text search configurations that use them.
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropTextSearchDictionary{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropTextSearchDictionary) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP TEXT SEARCH DICTIONARY ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Names)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
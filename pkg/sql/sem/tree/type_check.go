@@ -984,11 +984,12 @@ func (expr *ComparisonExpr) TypeCheck(
 }
 
 var (
-	errStarNotAllowed      = pgerror.New(pgcode.Syntax, "cannot use \"*\" in this context")
-	errInvalidDefaultUsage = pgerror.New(pgcode.Syntax, "DEFAULT can only appear in a VALUES list within INSERT or on the right side of a SET")
-	errInvalidMaxUsage     = pgerror.New(pgcode.Syntax, "MAXVALUE can only appear within a range partition expression")
-	errInvalidMinUsage     = pgerror.New(pgcode.Syntax, "MINVALUE can only appear within a range partition expression")
-	errPrivateFunction     = pgerror.New(pgcode.ReservedName, "function reserved for internal use")
+	errStarNotAllowed          = pgerror.New(pgcode.Syntax, "cannot use \"*\" in this context")
+	errInvalidDefaultUsage     = pgerror.New(pgcode.Syntax, "DEFAULT can only appear in a VALUES list within INSERT or on the right side of a SET")
+	errInvalidMaxUsage         = pgerror.New(pgcode.Syntax, "MAXVALUE can only appear within a range partition expression")
+	errInvalidGroupingSetUsage = pgerror.New(pgcode.Syntax, "GROUPING SETS, ROLLUP and CUBE can only appear in a GROUP BY clause")
+	errInvalidMinUsage         = pgerror.New(pgcode.Syntax, "MINVALUE can only appear within a range partition expression")
+	errPrivateFunction         = pgerror.New(pgcode.ReservedName, "function reserved for internal use")
 )
 
 // NewAggInAggError creates an error for the case when an aggregate function is
@@ -1180,6 +1181,11 @@ func (expr *FuncExpr) TypeCheck(
 		(*qualifiedOverloads)(&def.Overloads), expr.Exprs...,
 	)
 	defer s.release()
+	if expr.Variadic {
+		for i := range s.params {
+			s.params[i] = variadicCallParams(def.Overloads[i].Overload)
+		}
+	}
 
 	if err := func() error {
 		// Disallow procedures in function arguments.
@@ -1269,6 +1275,14 @@ func (expr *FuncExpr) TypeCheck(
 		if procErr := procedureDoesNotExistErr(expr.Func.String(), semaCtx); procErr != nil {
 			return nil, procErr
 		}
+		if expr.Variadic {
+			for _, o := range def.Overloads {
+				if _, ok := o.Types.(VariadicType); ok && o.Type == BuiltinRoutine {
+					return nil, unimplemented.Newf("variadic builtin",
+						"VARIADIC arguments are not supported for builtin function %s()", def.Name)
+				}
+			}
+		}
 		return nil, errors.WithHint(
 			pgerror.Newf(pgcode.UndefinedFunction, "unknown signature: %s", getFuncSig(expr, s.typedExprs, desired)),
 			"No function matches the given name and argument types. You might need to add explicit type casts.",
@@ -1757,6 +1771,13 @@ func (expr DefaultVal) TypeCheck(
 	return nil, errInvalidDefaultUsage
 }
 
+// TypeCheck implements the Expr interface.
+func (expr *GroupingSet) TypeCheck(
+	_ context.Context, _ *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	return nil, errInvalidGroupingSetUsage
+}
+
 // TypeCheck implements the Expr interface.
 func (expr PartitionMinVal) TypeCheck(
 	_ context.Context, _ *SemaContext, desired *types.T,
@@ -2075,6 +2096,24 @@ func (d *DJSON) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedE
 	return d, nil
 }
 
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DJsonpath) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
+	return d, nil
+}
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DRange) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
+	return d, nil
+}
+
+// TypeCheck implements the Expr interface. It is implemented as an idempotent
+// identity function for Datum.
+func (d *DMultirange) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
+	return d, nil
+}
+
 // TypeCheck implements the Expr interface. It is implemented as an idempotent
 // identity function for Datum.
 func (d *DTSQuery) TypeCheck(_ context.Context, _ *SemaContext, _ *types.T) (TypedExpr, error) {
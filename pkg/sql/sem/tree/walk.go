@@ -534,13 +534,29 @@ func (expr *Subquery) WalkTableExpr(v Visitor) TableExpr {
 
 // WalkTableExpr implements the TableExpr interface.
 func (expr *AliasedTableExpr) WalkTableExpr(v Visitor) TableExpr {
+	ret := expr
 	newExpr, changed := walkTableExpr(v, expr.Expr)
 	if changed {
 		exprCopy := *expr
 		exprCopy.Expr = newExpr
-		return &exprCopy
+		ret = &exprCopy
 	}
-	return expr
+	if expr.TableSample != nil {
+		sample := expr.TableSample
+		args, changedArgs := walkExprSlice(v, sample.Args)
+		repeatable, changedRepeatable := sample.Repeatable, false
+		if sample.Repeatable != nil {
+			repeatable, changedRepeatable = WalkExpr(v, sample.Repeatable)
+		}
+		if changedArgs || changedRepeatable {
+			if ret == expr {
+				exprCopy := *expr
+				ret = &exprCopy
+			}
+			ret.TableSample = &TableSample{Method: sample.Method, Args: args, Repeatable: repeatable}
+		}
+	}
+	return ret
 }
 
 // WalkTableExpr implements the TableExpr interface.
@@ -705,6 +721,16 @@ func (expr *ColumnItem) Walk(_ Visitor) Expr {
 // Walk implements the Expr interface.
 func (expr DefaultVal) Walk(_ Visitor) Expr { return expr }
 
+// Walk implements the Expr interface.
+func (expr *GroupingSet) Walk(v Visitor) Expr {
+	if exprs, changed := walkExprSlice(v, expr.Exprs); changed {
+		exprCopy := *expr
+		exprCopy.Exprs = exprs
+		return &exprCopy
+	}
+	return expr
+}
+
 // Walk implements the Expr interface.
 func (expr PartitionMaxVal) Walk(_ Visitor) Expr { return expr }
 
@@ -771,6 +797,15 @@ func (expr *DGeometry) Walk(_ Visitor) Expr { return expr }
 // Walk implements the Expr interface.
 func (expr *DJSON) Walk(_ Visitor) Expr { return expr }
 
+// Walk implements the Expr interface.
+func (expr *DJsonpath) Walk(_ Visitor) Expr { return expr }
+
+// Walk implements the Expr interface.
+func (expr *DRange) Walk(_ Visitor) Expr { return expr }
+
+// Walk implements the Expr interface.
+func (expr *DMultirange) Walk(_ Visitor) Expr { return expr }
+
 // Walk implements the Expr interface.
 func (expr *DTSQuery) Walk(_ Visitor) Expr { return expr }
 
@@ -1418,6 +1453,72 @@ func (stmt *Insert) walkStmt(v Visitor) Statement {
 	return ret
 }
 
+// copyNode makes a copy of this Statement without recursing in any child Statements.
+func (stmt *Merge) copyNode() *Merge {
+	stmtCopy := *stmt
+	stmtCopy.Whens = make(MergeWhens, len(stmt.Whens))
+	for i, w := range stmt.Whens {
+		wCopy := *w
+		switch t := w.Action.(type) {
+		case *MergeUpdate:
+			exprs := make(UpdateExprs, len(t.Exprs))
+			for j, e := range t.Exprs {
+				eCopy := *e
+				exprs[j] = &eCopy
+			}
+			wCopy.Action = &MergeUpdate{Exprs: exprs}
+		case *MergeInsert:
+			insCopy := *t
+			insCopy.Values = append(Exprs(nil), t.Values...)
+			wCopy.Action = &insCopy
+		}
+		stmtCopy.Whens[i] = &wCopy
+	}
+	return &stmtCopy
+}
+
+// walkStmt is part of the walkableStmt interface.
+func (stmt *Merge) walkStmt(v Visitor) Statement {
+	ret := stmt
+	copyOnce := func() {
+		if ret == stmt {
+			ret = stmt.copyNode()
+		}
+	}
+	if e, changed := WalkExpr(v, stmt.On); changed {
+		copyOnce()
+		ret.On = e
+	}
+	for i, w := range stmt.Whens {
+		if w.Cond != nil {
+			if e, changed := WalkExpr(v, w.Cond); changed {
+				copyOnce()
+				ret.Whens[i].Cond = e
+			}
+		}
+		switch t := w.Action.(type) {
+		case *MergeUpdate:
+			for j, expr := range t.Exprs {
+				if e, changed := WalkExpr(v, expr.Expr); changed {
+					copyOnce()
+					ret.Whens[i].Action.(*MergeUpdate).Exprs[j].Expr = e
+				}
+			}
+		case *MergeInsert:
+			if exprs, changed := walkExprSlice(v, t.Values); changed {
+				copyOnce()
+				ret.Whens[i].Action.(*MergeInsert).Values = exprs
+			}
+		}
+	}
+	returning, changed := walkReturningClause(v, stmt.Returning)
+	if changed {
+		copyOnce()
+		ret.Returning = returning
+	}
+	return ret
+}
+
 // copyNode makes a copy of this Statement without recursing in any child Statements.
 func (stmt *CreateTable) copyNode() *CreateTable {
 	stmtCopy := *stmt
@@ -2034,6 +2135,7 @@ var _ walkableStmt = &DropTenant{}
 var _ walkableStmt = &Explain{}
 var _ walkableStmt = &Import{}
 var _ walkableStmt = &Insert{}
+var _ walkableStmt = &Merge{}
 var _ walkableStmt = &ParenSelect{}
 var _ walkableStmt = &Restore{}
 var _ walkableStmt = &SelectClause{}
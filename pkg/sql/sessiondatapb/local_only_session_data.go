@@ -271,6 +271,62 @@ func DescriptorValidationModeFromString(val string) (_ DescriptorValidationMode,
 	}
 }
 
+// ConstraintsMode is the mode set by SET CONSTRAINTS ALL, which controls when
+// the checks for DEFERRABLE constraints are run.
+type ConstraintsMode int64
+
+const (
+	// ConstraintsDefault means that each constraint is checked according to
+	// its INITIALLY IMMEDIATE or INITIALLY DEFERRED declaration.
+	ConstraintsDefault ConstraintsMode = iota
+	// ConstraintsImmediate means that all constraints are checked at the end of
+	// each statement.
+	ConstraintsImmediate
+	// ConstraintsDeferred means that all DEFERRABLE constraints are checked
+	// when the transaction commits.
+	ConstraintsDeferred
+)
+
+func (m ConstraintsMode) String() string {
+	switch m {
+	case ConstraintsDefault:
+		return "default"
+	case ConstraintsImmediate:
+		return "immediate"
+	case ConstraintsDeferred:
+		return "deferred"
+	default:
+		return fmt.Sprintf("invalid (%d)", m)
+	}
+}
+
+// ConstraintsModeFor returns the SET CONSTRAINTS mode that applies to the
+// constraint with the given name on the table with the given ID. A mode set
+// for the constraint by name takes precedence over the one set for ALL
+// constraints.
+func (m *LocalOnlySessionData) ConstraintsModeFor(tableID uint32, name string) ConstraintsMode {
+	for i := range m.ConstraintsModeOverrides {
+		if o := &m.ConstraintsModeOverrides[i]; o.TableID == tableID && o.ConstraintName == name {
+			return o.Mode
+		}
+	}
+	return m.ConstraintsMode
+}
+
+// ConstraintsModeOverridesEqual returns whether the given lists of modes set
+// by SET CONSTRAINTS for individual constraints are the same.
+func ConstraintsModeOverridesEqual(a, b []ConstraintsModeOverride) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // QoSLevel controls the level of admission control to use for new SQL requests.
 type QoSLevel admissionpb.WorkPriority
 
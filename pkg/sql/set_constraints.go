@@ -0,0 +1,219 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/semenumpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+)
+
+// SetConstraints sets the checking mode of the deferrable constraints for the
+// remainder of the current transaction.
+func (p *planner) SetConstraints(ctx context.Context, n *tree.SetConstraints) (planNode, error) {
+	if err := checkDeferrableConstraintsActive(ctx, p.ExecCfg().Settings.Version); err != nil {
+		return nil, err
+	}
+	return &setConstraintsNode{names: n.Names, deferred: n.Deferred}, nil
+}
+
+type setConstraintsNode struct {
+	// names are the constraints to set the mode of, or nil for ALL.
+	names    tree.NameList
+	deferred bool
+}
+
+func (n *setConstraintsNode) Next(_ runParams) (bool, error) { return false, nil }
+func (n *setConstraintsNode) Values() tree.Datums            { return nil }
+func (n *setConstraintsNode) Close(_ context.Context)        {}
+func (n *setConstraintsNode) startExec(params runParams) error {
+	p := params.p
+	if p.extendedEvalCtx.TxnImplicit {
+		// This no-ops in postgres with a warning, so copy accordingly.
+		p.BufferClientNotice(
+			params.ctx,
+			pgnotice.NewWithSeverityf(
+				"WARNING",
+				"SET CONSTRAINTS can only be used in transaction blocks",
+			),
+		)
+		return nil
+	}
+	mode := sessiondatapb.ConstraintsImmediate
+	if n.deferred {
+		mode = sessiondatapb.ConstraintsDeferred
+	}
+	if n.names == nil {
+		if err := p.applyOnSessionDataMutators(
+			params.ctx, true /* local */, func(m sessionDataMutator) error {
+				m.SetConstraintsMode(mode)
+				m.SetConstraintsModeOverrides(nil)
+				return nil
+			},
+		); err != nil {
+			return err
+		}
+		if n.deferred {
+			return nil
+		}
+		// Switching to IMMEDIATE checks all the constraints whose checks were
+		// deferred so far, as if the transaction were committing.
+		return p.validateDeferredConstraints(params.ctx, nil /* filter */)
+	}
+
+	targets, err := p.resolveSetConstraintsTargets(params.ctx, n.names)
+	if err != nil {
+		return err
+	}
+	// Copy the overrides so that the ones saved for the end of the transaction
+	// are left untouched.
+	old := p.SessionData().ConstraintsModeOverrides
+	overrides := make([]sessiondatapb.ConstraintsModeOverride, 0, len(old)+len(targets))
+	for _, o := range old {
+		if _, ok := targets[setConstraintsTarget{tableID: descpb.ID(o.TableID), name: o.ConstraintName}]; !ok {
+			overrides = append(overrides, o)
+		}
+	}
+	targetIDs := make(map[deferredConstraint]struct{}, len(targets))
+	for t, id := range targets {
+		overrides = append(overrides, sessiondatapb.ConstraintsModeOverride{
+			TableID:        uint32(t.tableID),
+			ConstraintName: t.name,
+			Mode:           mode,
+		})
+		targetIDs[deferredConstraint{tableID: t.tableID, constraintID: id}] = struct{}{}
+	}
+	sort.Slice(overrides, func(i, j int) bool {
+		if overrides[i].TableID != overrides[j].TableID {
+			return overrides[i].TableID < overrides[j].TableID
+		}
+		return overrides[i].ConstraintName < overrides[j].ConstraintName
+	})
+	if err := p.applyOnSessionDataMutators(
+		params.ctx, true /* local */, func(m sessionDataMutator) error {
+			m.SetConstraintsModeOverrides(overrides)
+			return nil
+		},
+	); err != nil {
+		return err
+	}
+	if n.deferred {
+		return nil
+	}
+	// Switching the named constraints to IMMEDIATE checks the ones whose checks
+	// were deferred so far.
+	return p.validateDeferredConstraints(params.ctx, func(dc deferredConstraint) bool {
+		_, ok := targetIDs[dc]
+		return ok
+	})
+}
+
+// setConstraintsTarget identifies a constraint named by SET CONSTRAINTS.
+type setConstraintsTarget struct {
+	tableID descpb.ID
+	name    string
+}
+
+// resolveSetConstraintsTargets resolves the constraint names of a SET
+// CONSTRAINTS statement, returning the IDs of the matching constraints. Like
+// in Postgres, each name is looked up in the schemas of the search path in
+// order, and refers to all the constraints with that name in the first schema
+// which has any.
+func (p *planner) resolveSetConstraintsTargets(
+	ctx context.Context, names tree.NameList,
+) (map[setConstraintsTarget]descpb.ConstraintID, error) {
+	db, err := p.Descriptors().ByNameWithLeased(p.txn).Get().Database(ctx, p.CurrentDatabase())
+	if err != nil {
+		return nil, err
+	}
+	// Collect the tables of the schemas on the search path lazily, since most
+	// names are found in the first one.
+	var schemaTables [][]catalog.TableDescriptor
+	var schemasDone bool
+	iter := p.CurrentSearchPath().Iter()
+	nextSchema := func() (bool, error) {
+		for !schemasDone {
+			scName, ok := iter.Next()
+			if !ok {
+				schemasDone = true
+				break
+			}
+			sc, err := p.Descriptors().ByName(p.txn).MaybeGet().Schema(ctx, db, scName)
+			if err != nil {
+				return false, err
+			}
+			if sc == nil || sc.SchemaKind() == catalog.SchemaVirtual {
+				continue
+			}
+			objs, err := p.Descriptors().GetAllObjectsInSchema(ctx, p.txn, db, sc)
+			if err != nil {
+				return false, err
+			}
+			var tables []catalog.TableDescriptor
+			if err := objs.ForEachDescriptor(func(desc catalog.Descriptor) error {
+				if tbl, ok := desc.(catalog.TableDescriptor); ok && !tbl.Dropped() {
+					tables = append(tables, tbl)
+				}
+				return nil
+			}); err != nil {
+				return false, err
+			}
+			schemaTables = append(schemaTables, tables)
+			return true, nil
+		}
+		return false, nil
+	}
+
+	targets := make(map[setConstraintsTarget]descpb.ConstraintID)
+	for _, name := range names {
+		found := false
+		for i := 0; !found; i++ {
+			if i == len(schemaTables) {
+				if ok, err := nextSchema(); err != nil {
+					return nil, err
+				} else if !ok {
+					break
+				}
+			}
+			for _, tbl := range schemaTables[i] {
+				c := catalog.FindConstraintByName(tbl, string(name))
+				if c == nil {
+					continue
+				}
+				found = true
+				deferrable := false
+				if fk := c.AsForeignKey(); fk != nil {
+					deferrable = fk.Deferrability() != semenumpb.Deferrability_NOT_DEFERRABLE
+				} else if uwi := c.AsUniqueWithoutIndex(); uwi != nil {
+					deferrable = uwi.Deferrability() != semenumpb.Deferrability_NOT_DEFERRABLE
+				}
+				if !deferrable {
+					return nil, pgerror.Newf(pgcode.WrongObjectType,
+						"constraint %q is not deferrable", string(name))
+				}
+				targets[setConstraintsTarget{tableID: tbl.GetID(), name: c.GetName()}] = c.GetConstraintID()
+			}
+		}
+		if !found {
+			return nil, pgerror.Newf(pgcode.UndefinedObject,
+				"constraint %q does not exist", string(name))
+		}
+	}
+	return targets, nil
+}
@@ -586,6 +586,7 @@ func showForeignKeyConstraint(
 		buf.WriteString(" ON UPDATE ")
 		buf.WriteString(tree.ForeignKeyReferenceActionType[fk.OnUpdate].String())
 	}
+	buf.WriteString(tree.AsString(tree.ConstraintDeferrabilityType[fk.Deferrability]))
 	if fk.Validity != descpb.ConstraintValidity_Validated {
 		buf.WriteString(" NOT VALID")
 	}
@@ -797,15 +798,9 @@ func ShowCreatePartitioning(
 	return nil
 }
 
-// showConstraintClause creates the CONSTRAINT clauses for a CREATE statement,
-// writing them to tree.FmtCtx f
-func showConstraintClause(
-	ctx context.Context,
-	desc catalog.TableDescriptor,
-	semaCtx *tree.SemaContext,
-	sessionData *sessiondata.SessionData,
-	f *tree.FmtCtx,
-) error {
+// showCreateExprFmtFlags returns the flags with which the expressions stored in
+// a table descriptor are formatted when writing a CREATE statement to f.
+func showCreateExprFmtFlags(f *tree.FmtCtx) tree.FmtFlags {
 	exprFmtFlags := tree.FmtParsable
 	if f.HasFlags(tree.FmtPGCatalog) {
 		exprFmtFlags = tree.FmtPGCatalog
@@ -817,6 +812,85 @@ func showConstraintClause(
 			exprFmtFlags |= tree.FmtOmitNameRedaction
 		}
 	}
+	return exprFmtFlags
+}
+
+// showRowLevelSecurity writes the ALTER TABLE ... ROW LEVEL SECURITY and
+// CREATE POLICY statements that recreate the row-level security configuration
+// of the table to tree.FmtCtx f.
+func showRowLevelSecurity(
+	ctx context.Context,
+	tn *tree.TableName,
+	desc catalog.TableDescriptor,
+	semaCtx *tree.SemaContext,
+	sessionData *sessiondata.SessionData,
+	f *tree.FmtCtx,
+) error {
+	writeMode := func(mode tree.RowLevelSecurityMode) {
+		f.WriteString(";\n")
+		f.FormatNode(&tree.AlterTable{
+			Table: tn.ToUnresolvedObjectName(),
+			Cmds:  tree.AlterTableCmds{&tree.AlterTableSetRowLevelSecurity{Mode: mode}},
+		})
+	}
+	if desc.IsRowLevelSecurityEnabled() {
+		writeMode(tree.RowLevelSecurityEnable)
+	}
+	if desc.IsRowLevelSecurityForced() {
+		writeMode(tree.RowLevelSecurityForce)
+	}
+
+	exprFmtFlags := showCreateExprFmtFlags(f)
+	for i := range desc.GetPolicies() {
+		policy := &desc.GetPolicies()[i]
+		f.WriteString(";\nCREATE POLICY ")
+		f.FormatNameP(&policy.Name)
+		f.WriteString(" ON ")
+		f.FormatNode(tn)
+		f.WriteString(" AS ")
+		f.WriteString(policy.Type.String())
+		f.WriteString(" FOR ")
+		f.WriteString(policy.Command.String())
+		f.WriteString(" TO ")
+		for j := range policy.RoleNames {
+			if j > 0 {
+				f.WriteString(", ")
+			}
+			f.FormatNameP(&policy.RoleNames[j])
+		}
+		for _, clause := range []struct {
+			keyword, expr string
+		}{
+			{keyword: " USING (", expr: policy.UsingExpr},
+			{keyword: " WITH CHECK (", expr: policy.WithCheckExpr},
+		} {
+			if clause.expr == "" {
+				continue
+			}
+			expr, err := schemaexpr.FormatExprForDisplay(
+				ctx, desc, clause.expr, semaCtx, sessionData, exprFmtFlags,
+			)
+			if err != nil {
+				return err
+			}
+			f.WriteString(clause.keyword)
+			f.WriteString(expr)
+			f.WriteString(")")
+		}
+	}
+	return nil
+}
+
+// showConstraintClause creates the CONSTRAINT clauses for a CREATE statement,
+// writing them to tree.FmtCtx f
+func showConstraintClause(
+	ctx context.Context,
+	desc catalog.TableDescriptor,
+	semaCtx *tree.SemaContext,
+	sessionData *sessiondata.SessionData,
+	f *tree.FmtCtx,
+) error {
+	exprFmtFlags := showCreateExprFmtFlags(f)
 	for _, e := range desc.CheckConstraints() {
 		if e.IsHashShardingConstraint() && !e.IsConstraintUnvalidated() {
 			continue
@@ -854,6 +928,31 @@ func showConstraintClause(
 		}
 		f.WriteString(strings.Join(colNames, ", "))
 		f.WriteString(")")
+		f.FormatNode(tree.ConstraintDeferrabilityType[c.Deferrability()])
+		if c.IsPartial() {
+			f.WriteString(" WHERE ")
+			pred, err := schemaexpr.FormatExprForDisplay(
+				ctx, desc, c.GetPredicate(), semaCtx, sessionData, exprFmtFlags,
+			)
+			if err != nil {
+				return err
+			}
+			f.WriteString(pred)
+		}
+		if !c.IsConstraintValidated() {
+			f.WriteString(" NOT VALID")
+		}
+	}
+	for _, c := range desc.EnforcedExclusionConstraints() {
+		f.WriteString(",\n\t")
+		if len(c.GetName()) > 0 {
+			f.WriteString("CONSTRAINT ")
+			formatQuoteNames(&f.Buffer, c.GetName())
+			f.WriteString(" ")
+		}
+		if err := formatExclusionConstraintElems(desc, c, f); err != nil {
+			return err
+		}
 		if c.IsPartial() {
 			f.WriteString(" WHERE ")
 			pred, err := schemaexpr.FormatExprForDisplay(
@@ -871,3 +970,27 @@ func showConstraintClause(
 	f.WriteString("\n)")
 	return nil
 }
+
+// formatExclusionConstraintElems writes the EXCLUDE USING clause of the given
+// exclusion constraint, without its predicate, to f.
+func formatExclusionConstraintElems(
+	desc catalog.TableDescriptor, c catalog.ExclusionConstraint, f *tree.FmtCtx,
+) error {
+	f.WriteString("EXCLUDE USING ")
+	f.WriteString(c.GetIndexMethod())
+	f.WriteString(" (")
+	for i, n := 0, c.NumKeyColumns(); i < n; i++ {
+		if i > 0 {
+			f.WriteString(", ")
+		}
+		col, err := catalog.MustFindColumnByID(desc, c.GetKeyColumnID(i))
+		if err != nil {
+			return err
+		}
+		formatQuoteNames(&f.Buffer, col.GetName())
+		f.WriteString(" WITH ")
+		f.WriteString(c.GetOperator(i).String())
+	}
+	f.WriteString(")")
+	return nil
+}
@@ -16,15 +16,21 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/clusterunique"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser/statements"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
@@ -35,17 +41,14 @@ func (p *planner) DeclareCursor(ctx context.Context, s *tree.DeclareCursor) (pla
 	if s.Binary {
 		return nil, unimplemented.NewWithIssue(77099, "DECLARE BINARY CURSOR")
 	}
-	if s.Scroll == tree.Scroll {
-		return nil, unimplemented.NewWithIssue(77102, "DECLARE SCROLL CURSOR")
-	}
 
 	return &delayedNode{
 		name: s.String(),
 		constructor: func(ctx context.Context, p *planner) (_ planNode, _ error) {
-			if p.extendedEvalCtx.TxnImplicit {
-				if s.Hold {
-					return nil, unimplemented.NewWithIssue(77101, "DECLARE CURSOR WITH HOLD can only be used in transaction blocks")
-				}
+			// A WITH HOLD cursor can be declared outside of a transaction block,
+			// since its rows are materialized when the implicit transaction
+			// commits.
+			if p.extendedEvalCtx.TxnImplicit && !s.Hold {
 				return nil, pgerror.Newf(pgcode.NoActiveSQLTransaction, "DECLARE CURSOR can only be used in transaction blocks")
 			}
 
@@ -116,6 +119,20 @@ func (p *planner) DeclareCursor(ctx context.Context, s *tree.DeclareCursor) (pla
 				statement:  statement,
 				created:    timeutil.Now(),
 				withHold:   s.Hold,
+				scrollable: s.Scroll == tree.Scroll,
+			}
+			if cursor.scrollable {
+				// Scrollable cursors are materialized eagerly, so that their rows
+				// can be revisited in any order.
+				monitor, err := p.scrollCursorMonitor()
+				if err != nil {
+					_ = cursor.Close()
+					return nil, err
+				}
+				if err := cursor.materialize(ctx, p.ExtendedEvalContext(), monitor); err != nil {
+					_ = cursor.Close()
+					return nil, errors.Wrap(err, "failed to DECLARE CURSOR")
+				}
 			}
 			if err := p.sqlCursors.addCursor(s.Name, cursor); err != nil {
 				// This case shouldn't happen because cursor names are scoped to a session,
@@ -158,7 +175,7 @@ func (p *planner) newFetchNode(s *tree.CursorStmt) (*fetchNode, error) {
 			pgcode.InvalidCursorName, "cursor %q does not exist", s.Name,
 		)
 	}
-	if s.Count < 0 || s.FetchType == tree.FetchBackwardAll {
+	if !cursor.scrollable && (s.Count < 0 || s.FetchType == tree.FetchBackwardAll) {
 		return nil, errBackwardScan
 	}
 	node := &fetchNode{
@@ -204,6 +221,9 @@ func (f *fetchNode) startInternal() error {
 }
 
 func (f *fetchNode) nextInternal(ctx context.Context) (bool, error) {
+	if f.cursor.scrollable {
+		return f.nextScrollable(ctx)
+	}
 	if f.fetchType == tree.FetchAll {
 		return f.cursor.Next(ctx)
 	}
@@ -252,6 +272,57 @@ func (f *fetchNode) nextInternal(ctx context.Context) (bool, error) {
 	return f.cursor.Next(ctx)
 }
 
+// nextScrollable implements nextInternal for SCROLL cursors, which can move in
+// either direction. Moving past either end of the result positions the cursor
+// before the first row or after the last row, like in Postgres.
+func (f *fetchNode) nextScrollable(ctx context.Context) (bool, error) {
+	c := f.cursor
+	switch f.fetchType {
+	case tree.FetchNormal:
+		if f.n == 0 {
+			// Like in Postgres, FETCH 0 fetches the current row again, if the
+			// cursor is positioned on one.
+			if f.seeked {
+				return false, nil
+			}
+			f.seeked = true
+			return c.seek(ctx, c.curRow)
+		}
+		f.seeked = true
+		if f.n < 0 {
+			f.n++
+			return c.seek(ctx, c.curRow-1)
+		}
+		f.n--
+		return c.seek(ctx, c.curRow+1)
+	case tree.FetchAll:
+		return c.seek(ctx, c.curRow+1)
+	case tree.FetchBackwardAll:
+		return c.seek(ctx, c.curRow-1)
+	}
+
+	// The remaining fetch types return at most one row.
+	if f.seeked {
+		return false, nil
+	}
+	f.seeked = true
+	switch f.fetchType {
+	case tree.FetchFirst:
+		return c.seek(ctx, 1)
+	case tree.FetchLast:
+		return c.seek(ctx, c.numRows())
+	case tree.FetchAbsolute:
+		if f.offset < 0 {
+			// Negative positions count backwards from the end of the result.
+			return c.seek(ctx, c.numRows()+1+f.offset)
+		}
+		return c.seek(ctx, f.offset)
+	case tree.FetchRelative:
+		return c.seek(ctx, c.curRow+f.offset)
+	}
+	return false, errors.AssertionFailedf("unexpected fetch type %s", f.fetchType)
+}
+
 func (f *fetchNode) startExec(params runParams) error {
 	return f.startInternal()
 }
@@ -284,7 +355,7 @@ func (p *planner) CloseCursor(ctx context.Context, n *tree.CloseCursor) (planNod
 		name: n.String(),
 		constructor: func(ctx context.Context, p *planner) (planNode, error) {
 			if n.All {
-				return newZeroNode(nil /* columns */), p.sqlCursors.closeAll(false /* errorOnWithHold */)
+				return newZeroNode(nil /* columns */), p.sqlCursors.closeAll(false /* keepHeld */)
 			}
 			return newZeroNode(nil /* columns */), p.sqlCursors.closeCursor(n.Name)
 		},
@@ -341,14 +412,32 @@ type sqlCursor struct {
 	created    time.Time
 	curRow     int64
 	withHold   bool
+	// scrollable is true if the cursor was declared with SCROLL, which allows
+	// it to be fetched from in any direction.
+	scrollable bool
+	// held is true if the cursor is a WITH HOLD cursor that outlived the
+	// transaction that declared it.
+	held bool
 	// eagerExecution indicates that the cursor's query was executed eagerly and
 	// stored in a row container. If true, there is no need to set the transaction
 	// sequence number, since the query is no longer active.
 	eagerExecution bool
+
+	// buf, if set, contains the rows of a materialized cursor, which no longer
+	// reads from Rows. The first row in buf is at position bufStart+1, since
+	// the rows that were fetched before the cursor was materialized are not
+	// kept.
+	buf      *cursorRowBuffer
+	bufStart int64
+	// cur is the current row of a materialized cursor.
+	cur tree.Datums
 }
 
 // Next implements the Rows interface.
 func (s *sqlCursor) Next(ctx context.Context) (bool, error) {
+	if s.buf != nil {
+		return s.seek(ctx, s.curRow+1)
+	}
 	more, err := s.Rows.Next(ctx)
 	if err == nil {
 		s.curRow++
@@ -356,12 +445,170 @@ func (s *sqlCursor) Next(ctx context.Context) (bool, error) {
 	return more, err
 }
 
+// Cur implements the Rows interface.
+func (s *sqlCursor) Cur() tree.Datums {
+	if s.buf != nil {
+		return s.cur
+	}
+	return s.Rows.Cur()
+}
+
+// Close implements the Rows interface.
+func (s *sqlCursor) Close() error {
+	if s.buf != nil {
+		// The buffer may outlive the statement and the transaction that created
+		// it, so it is not tied to their contexts. The underlying iterator was
+		// already closed when the cursor was materialized.
+		s.buf.Close(context.Background())
+		s.buf = nil
+		return nil
+	}
+	return s.Rows.Close()
+}
+
+// materialize reads the remaining rows of the cursor into a row buffer whose
+// memory is accounted for by the given monitor, and closes the underlying
+// iterator. Afterwards, the cursor no longer depends on the transaction that
+// declared it.
+func (s *sqlCursor) materialize(
+	ctx context.Context, evalCtx *extendedEvalContext, monitor *mon.BytesMonitor,
+) (retErr error) {
+	if s.buf != nil {
+		return nil
+	}
+	if !s.eagerExecution {
+		// Read at the sequence number the cursor was declared with, like
+		// FETCH does.
+		origTxnSeqNum := s.txn.GetReadSeqNum()
+		if err := s.txn.SetReadSeqNum(s.readSeqNum); err != nil {
+			return err
+		}
+		defer func() {
+			if err := s.txn.SetReadSeqNum(origTxnSeqNum); err != nil && retErr == nil {
+				retErr = err
+			}
+		}()
+	}
+	var cur tree.Datums
+	if s.curRow > 0 {
+		cur = s.Rows.Cur()
+	}
+	more, err := s.Rows.Next(ctx)
+	if err != nil {
+		return err
+	}
+	buf := newCursorRowBuffer(ctx, getTypesFromResultColumns(s.Rows.Types()), evalCtx, monitor)
+	for ; more; more, err = s.Rows.Next(ctx) {
+		if err := buf.AddRow(ctx, s.Rows.Cur()); err != nil {
+			buf.Close(ctx)
+			return err
+		}
+	}
+	if err == nil {
+		err = s.Rows.Close()
+	}
+	if err != nil {
+		buf.Close(ctx)
+		return err
+	}
+	s.buf, s.bufStart, s.cur = buf, s.curRow, cur
+	s.eagerExecution = true
+	return nil
+}
+
+// numRows returns the number of rows in a materialized cursor.
+func (s *sqlCursor) numRows() int64 {
+	return s.bufStart + s.buf.Len()
+}
+
+// seek moves a materialized cursor to the given position, and returns whether
+// the cursor is positioned on a row. Position 0 is before the first row, and
+// position numRows()+1 is after the last row; positions outside of that range
+// are clamped to it.
+func (s *sqlCursor) seek(ctx context.Context, pos int64) (bool, error) {
+	if pos <= s.bufStart {
+		if s.bufStart > 0 {
+			// The rows before the buffer are no longer available.
+			return false, errBackwardScan
+		}
+		s.curRow, s.cur = 0, nil
+		return false, nil
+	}
+	if n := s.numRows(); pos > n {
+		s.curRow, s.cur = n+1, nil
+		return false, nil
+	}
+	row, err := s.buf.GetRow(ctx, pos-s.bufStart-1)
+	if err != nil {
+		return false, err
+	}
+	s.curRow, s.cur = pos, row
+	return true, nil
+}
+
+// cursorRowBuffer materializes the rows of a cursor into a disk-backed row
+// container, which supports random access. It is used by SCROLL cursors and by
+// WITH HOLD cursors that outlive their transaction.
+type cursorRowBuffer struct {
+	memMonitor  *mon.BytesMonitor
+	diskMonitor *mon.BytesMonitor
+	rows        *rowcontainer.DiskBackedIndexedRowContainer
+	scratch     rowenc.EncDatumRow
+}
+
+func newCursorRowBuffer(
+	ctx context.Context, typs []*types.T, evalCtx *extendedEvalContext, monitor *mon.BytesMonitor,
+) *cursorRowBuffer {
+	distSQLCfg := &evalCtx.DistSQLPlanner.distSQLSrv.ServerConfig
+	b := &cursorRowBuffer{
+		memMonitor: execinfra.NewLimitedMonitorNoFlowCtx(
+			ctx, monitor, distSQLCfg, evalCtx.SessionData(), "sql-cursor-limited",
+		),
+		diskMonitor: execinfra.NewMonitor(ctx, distSQLCfg.ParentDiskMonitor, "sql-cursor-disk"),
+		scratch:     make(rowenc.EncDatumRow, len(typs)),
+	}
+	b.rows = rowcontainer.NewDiskBackedIndexedRowContainer(
+		colinfo.NoOrdering, typs, &evalCtx.Context,
+		distSQLCfg.TempStorage, b.memMonitor, b.diskMonitor,
+	)
+	return b
+}
+
+// AddRow appends the given row to the buffer.
+func (b *cursorRowBuffer) AddRow(ctx context.Context, row tree.Datums) error {
+	for i := range row {
+		b.scratch[i].Datum = row[i]
+	}
+	return b.rows.AddRow(ctx, b.scratch)
+}
+
+// GetRow returns the row at the given zero-based index.
+func (b *cursorRowBuffer) GetRow(ctx context.Context, idx int64) (tree.Datums, error) {
+	row, err := b.rows.GetRow(ctx, int(idx))
+	if err != nil {
+		return nil, err
+	}
+	return row.GetDatums(0, len(b.scratch))
+}
+
+// Len returns the number of rows in the buffer.
+func (b *cursorRowBuffer) Len() int64 {
+	return int64(b.rows.Len())
+}
+
+// Close releases the resources held by the buffer.
+func (b *cursorRowBuffer) Close(ctx context.Context) {
+	b.rows.Close(ctx)
+	b.memMonitor.Stop(ctx)
+	b.diskMonitor.Stop(ctx)
+}
+
 // sqlCursors contains a set of active cursors for a session.
 type sqlCursors interface {
-	// closeAll closes all cursors in the set. If any of the cursors were
-	// created WITH HOLD, and the errorOnWithHold flag is true, an error is
-	// returned.
-	closeAll(errorOnWithHold bool) error
+	// closeAll closes all cursors in the set. If keepHeld is true, the WITH
+	// HOLD cursors that outlived the transaction that declared them are kept
+	// open.
+	closeAll(keepHeld bool) error
 	// closeCursor closes the named cursor, returning an error if that cursor
 	// didn't exist in the set.
 	closeCursor(tree.Name) error
@@ -376,6 +623,10 @@ type sqlCursors interface {
 	// genUniqueName is used to generate a name for an unnamed PLpgSQL cursor that
 	// will not conflict with other cursors currently defined on the session.
 	genUniqueName() tree.Name
+	// memMonitor returns the monitor that accounts for the memory of
+	// materialized cursors. Since WITH HOLD cursors can outlive transactions,
+	// this is a session-level monitor. It is nil if there is no session.
+	memMonitor() *mon.BytesMonitor
 }
 
 // emptySqlCursors is the default impl used by the planner when the
@@ -408,6 +659,22 @@ func (e emptySqlCursors) genUniqueName() tree.Name {
 	return ""
 }
 
+func (e emptySqlCursors) memMonitor() *mon.BytesMonitor {
+	return nil
+}
+
+// scrollCursorMonitor returns the monitor that accounts for the rows of a
+// SCROLL cursor, which are materialized when it is declared. SCROLL cursors
+// cannot be declared without a session, which could not keep them anyway.
+func (p *planner) scrollCursorMonitor() (*mon.BytesMonitor, error) {
+	monitor := p.sqlCursors.memMonitor()
+	if monitor == nil {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"SCROLL cursors cannot be declared in this context")
+	}
+	return monitor, nil
+}
+
 // cursorMap is a sqlCursors that's backed by an actual map.
 type cursorMap struct {
 	cursors map[tree.Name]*sqlCursor
@@ -416,16 +683,35 @@ type cursorMap struct {
 	nameCounter int
 }
 
-func (c *cursorMap) closeAll(errorOnWithHold bool) error {
-	for n, c := range c.cursors {
-		if c.withHold && errorOnWithHold {
-			return unimplemented.NewWithIssuef(77101, "cursor %s WITH HOLD must be closed before committing", n)
+func (c *cursorMap) closeAll(keepHeld bool) error {
+	for n, cursor := range c.cursors {
+		if keepHeld && cursor.held {
+			continue
 		}
-		if err := c.Close(); err != nil {
+		delete(c.cursors, n)
+		if err := cursor.Close(); err != nil {
 			return err
 		}
 	}
-	c.cursors = nil
+	return nil
+}
+
+// holdCursors materializes the WITH HOLD cursors declared in the current
+// transaction, which allows them to outlive it. It must be called before the
+// transaction commits.
+func (c *cursorMap) holdCursors(
+	ctx context.Context, evalCtx *extendedEvalContext, monitor *mon.BytesMonitor,
+) error {
+	for _, cursor := range c.cursors {
+		if !cursor.withHold || cursor.held {
+			continue
+		}
+		if err := cursor.materialize(ctx, evalCtx, monitor); err != nil {
+			return err
+		}
+		cursor.held = true
+		cursor.txn = nil
+	}
 	return nil
 }
 
@@ -475,8 +761,8 @@ type connExCursorAccessor struct {
 	ex *connExecutor
 }
 
-func (c connExCursorAccessor) closeAll(errorOnWithHold bool) error {
-	return c.ex.extraTxnState.sqlCursors.closeAll(errorOnWithHold)
+func (c connExCursorAccessor) closeAll(keepHeld bool) error {
+	return c.ex.extraTxnState.sqlCursors.closeAll(keepHeld)
 }
 
 func (c connExCursorAccessor) closeCursor(s tree.Name) error {
@@ -499,6 +785,10 @@ func (c connExCursorAccessor) genUniqueName() tree.Name {
 	return c.ex.extraTxnState.sqlCursors.genUniqueName()
 }
 
+func (c connExCursorAccessor) memMonitor() *mon.BytesMonitor {
+	return c.ex.sessionMon
+}
+
 // checkNoConflictingCursors returns an error if the input schema changing
 // statement conflicts with any open SQL cursors in the current planner.
 func (p *planner) checkNoConflictingCursors(stmt tree.Statement) error {
@@ -508,9 +798,12 @@ func (p *planner) checkNoConflictingCursors(stmt tree.Statement) error {
 	// We could improve this by matching the memo metadata's list of dependent
 	// schema objects in each open cursor with the objects being changed in the
 	// schema change.
-	if len(p.sqlCursors.list()) > 0 {
-		return unimplemented.NewWithIssue(74608, "cannot run schema change "+
-			"in a transaction with open DECLARE cursors")
+	for _, c := range p.sqlCursors.list() {
+		// Held cursors are materialized and don't read from the transaction.
+		if !c.held {
+			return unimplemented.NewWithIssue(74608, "cannot run schema change "+
+				"in a transaction with open DECLARE cursors")
+		}
 	}
 	return nil
 }
@@ -0,0 +1,889 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsn"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/lsnutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl/pgoutput"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgproto3"
+)
+
+// A subscription streams the changes of the tables of its publications from a
+// replication slot of the publishing cluster, and applies them to the tables
+// with the same names in its database with SQL writes. The conflicts between
+// replicated and local writes are resolved by keeping the last writer: a
+// replicated change is only applied to a row if the change was committed on
+// the publisher after the row was last written.
+//
+// To know when a row was last written, the subscription keeps, for each row it
+// replicated a change to, a jobspb.SubscriptionRowOrigin in the info of its
+// job, keyed by the encoded primary key of the row. It records the commit
+// timestamp of the change on the publisher, and the MVCC timestamp of the row
+// written by the subscription: if the row has another MVCC timestamp, it was
+// last written locally, at that timestamp. The origin of a replicated deletion
+// is kept as a tombstone. A local deletion leaves no trace, so a replicated
+// change to a row deleted locally is only known to be older than the deletion
+// if it was committed before the subscription last wrote the row; it is
+// applied otherwise. The origin state is dropped along with the job's record.
+//
+// The changes of a transaction of the publisher are applied in a single
+// transaction, in batches of consecutive changes of the same kind to the same
+// table, each applied with a constant number of statements.
+
+// subscriptionStatusInterval is the interval at which a subscription persists
+// its progress and confirms it to the publisher.
+var subscriptionStatusInterval = 5 * time.Second
+
+// subscriptionCopyBatchSize is the number of rows copied per transaction while
+// copying the existing rows of the published tables.
+const subscriptionCopyBatchSize = 100
+
+// subscriptionApplyBatchSize is the maximum number of changes applied per
+// batch.
+const subscriptionApplyBatchSize = 100
+
+// subscriptionOriginInfoKeyPrefix is the prefix of the keys of the job info of
+// a subscription which store the origin state of the rows.
+const subscriptionOriginInfoKeyPrefix = "~subscription-origin-"
+
+// subscription is the job of a subscription that did not reach a terminal
+// status.
+type subscription struct {
+	id      jobspb.JobID
+	status  jobs.Status
+	owner   username.SQLUsername
+	details jobspb.SubscriptionDetails
+}
+
+// listSubscriptions returns the subscriptions of the cluster. The jobs that
+// were requested to be canceled are omitted, since their subscription was
+// dropped.
+func listSubscriptions(ctx context.Context, txn isql.Txn) ([]subscription, error) {
+	rows, err := txn.QueryBufferedEx(ctx, "list-subscriptions", txn.KV(),
+		sessiondata.NodeUserSessionDataOverride,
+		fmt.Sprintf(`SELECT id, status, payload FROM "".crdb_internal.system_jobs
+WHERE job_type = '%s' AND status NOT IN ('%s', '%s', '%s', '%s', '%s', '%s')`,
+			jobspb.TypeSubscription, jobs.StatusSucceeded, jobs.StatusFailed, jobs.StatusCanceled,
+			jobs.StatusRevertFailed, jobs.StatusCancelRequested, jobs.StatusReverting,
+		))
+	if err != nil {
+		return nil, err
+	}
+	subs := make([]subscription, 0, len(rows))
+	for _, row := range rows {
+		payload, err := jobs.UnmarshalPayload(row[2])
+		if err != nil {
+			return nil, err
+		}
+		details := payload.GetSubscription()
+		if details == nil {
+			return nil, errors.AssertionFailedf("job %s is not a subscription", row[0])
+		}
+		subs = append(subs, subscription{
+			id:      jobspb.JobID(tree.MustBeDInt(row[0])),
+			status:  jobs.Status(tree.MustBeDString(row[1])),
+			owner:   payload.UsernameProto.Decode(),
+			details: *details,
+		})
+	}
+	return subs, nil
+}
+
+// checkSubscribedTable checks that the changes replicated by a subscription
+// can be applied to a table.
+func checkSubscribedTable(tbl catalog.TableDescriptor) error {
+	if !tbl.IsPhysicalTable() || tbl.IsSequence() || tbl.IsTemporary() {
+		return pgerror.Newf(pgcode.WrongObjectType,
+			"cannot replicate into relation %q", tbl.GetName())
+	}
+	return nil
+}
+
+// subscribedRelation is a table of the publisher whose changes are applied to
+// the table with the same name in the subscription's database.
+type subscribedRelation struct {
+	tableID        descpb.ID
+	primaryIndexID descpb.IndexID
+	// tableName is the fully-qualified name of the local table.
+	tableName string
+	// cols are the local columns of the replicated columns, whose ordinals in
+	// the publisher's rows are colOrdinals.
+	cols        []catalog.Column
+	colOrdinals []int
+	// keyCols are the primary key columns, in the order of the primary index,
+	// whose ordinals in the publisher's rows are keyOrdinals.
+	keyCols     []catalog.Column
+	keyOrdinals []int
+}
+
+// newSubscribedRelation prepares the application of the changes of a table of
+// the publisher. The key columns of the relation must be the primary key
+// columns of the local table. If the key columns of the relation are not
+// known, e.g. for the initial copy, the local primary key is used.
+func newSubscribedRelation(
+	ctx context.Context, execCfg *ExecutorConfig, dbID descpb.ID, rel *pgoutput.Relation,
+) (*subscribedRelation, error) {
+	var tbl catalog.TableDescriptor
+	var tn tree.TableName
+	if err := execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		db, err := txn.Descriptors().ByIDWithLeased(txn.KV()).Get().Database(ctx, dbID)
+		if err != nil {
+			return err
+		}
+		sc, err := txn.Descriptors().ByNameWithLeased(txn.KV()).Get().Schema(ctx, db, rel.Namespace)
+		if err != nil {
+			return err
+		}
+		tbl, err = txn.Descriptors().ByNameWithLeased(txn.KV()).Get().Table(ctx, db, sc, rel.Name)
+		if err != nil {
+			return err
+		}
+		tn = tree.MakeTableNameWithSchema(
+			tree.Name(db.GetName()), tree.Name(sc.GetName()), tree.Name(tbl.GetName()),
+		)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := checkSubscribedTable(tbl); err != nil {
+		return nil, err
+	}
+
+	primaryIndex := tbl.GetPrimaryIndex()
+	pkCols := primaryIndex.CollectKeyColumnIDs()
+	hasKeys := false
+	for _, col := range rel.Columns {
+		hasKeys = hasKeys || col.Key
+	}
+	r := &subscribedRelation{
+		tableID:        tbl.GetID(),
+		primaryIndexID: primaryIndex.GetID(),
+		tableName:      tn.FQString(),
+	}
+	ordinals := make(map[descpb.ColumnID]int, len(rel.Columns))
+	for i, remoteCol := range rel.Columns {
+		col := catalog.FindColumnByName(tbl, remoteCol.Name)
+		if col == nil || !col.Public() || col.IsComputed() {
+			return nil, pgerror.Newf(pgcode.UndefinedColumn,
+				"table %s has no writable column %q replicated by the subscription",
+				tn.FQString(), remoteCol.Name)
+		}
+		if hasKeys && pkCols.Contains(col.GetID()) != remoteCol.Key {
+			return nil, pgerror.Newf(pgcode.InvalidTableDefinition,
+				"the primary key of table %s does not match the primary key of the publisher",
+				tn.FQString())
+		}
+		ordinals[col.GetID()] = i
+		r.colOrdinals = append(r.colOrdinals, i)
+		r.cols = append(r.cols, col)
+	}
+	for i := 0; i < primaryIndex.NumKeyColumns(); i++ {
+		id := primaryIndex.GetKeyColumnID(i)
+		ord, ok := ordinals[id]
+		if !ok {
+			return nil, pgerror.Newf(pgcode.InvalidTableDefinition,
+				"the primary key of table %s does not match the primary key of the publisher",
+				tn.FQString())
+		}
+		r.keyOrdinals = append(r.keyOrdinals, ord)
+		r.keyCols = append(r.keyCols, r.cols[ord])
+	}
+	return r, nil
+}
+
+// subscribedValuePlaceholder returns the placeholder of a value of a column.
+// The values are sent in the text format, which is parsed by casting it to the
+// type of the local column.
+func subscribedValuePlaceholder(idx int, col catalog.Column) string {
+	typ := col.GetType()
+	if typ.UserDefined() {
+		return fmt.Sprintf("$%d::STRING::@%d", idx, typ.Oid())
+	}
+	return fmt.Sprintf("$%d::STRING::%s", idx, typ.SQLString())
+}
+
+// appendValues appends to buf a tuple of placeholders for the values of the
+// columns at the given ordinals of a row of the publisher, and appends the
+// values to args.
+func appendValues(
+	buf *bytes.Buffer, args []interface{}, row []pgoutput.Value, cols []catalog.Column, ordinals []int,
+) []interface{} {
+	buf.WriteString("(")
+	for i, ord := range ordinals {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		if ord >= len(row) || row[ord].Null {
+			args = append(args, nil)
+		} else {
+			args = append(args, string(row[ord].Text))
+		}
+		buf.WriteString(subscribedValuePlaceholder(len(args), cols[i]))
+	}
+	buf.WriteString(")")
+	return args
+}
+
+// lookup returns the encoded primary keys of rows of the publisher, and the
+// MVCC timestamps of the local rows with these keys, which are empty for the
+// rows that don't exist.
+func (r *subscribedRelation) lookup(
+	ctx context.Context, txn isql.Txn, rows [][]pgoutput.Value,
+) (keys []string, timestamps []hlc.Timestamp, _ error) {
+	var values, aliases, keyTuple, joinPreds bytes.Buffer
+	for i, col := range r.keyCols {
+		if i > 0 {
+			aliases.WriteString(", ")
+			keyTuple.WriteString(", ")
+			joinPreds.WriteString(" AND ")
+		}
+		fmt.Fprintf(&aliases, "k%d", i)
+		fmt.Fprintf(&keyTuple, "v.k%d", i)
+		fmt.Fprintf(&joinPreds, "t.%s = v.k%d", tree.NameString(col.GetName()), i)
+	}
+	var args []interface{}
+	for i, row := range rows {
+		if i > 0 {
+			values.WriteString(", ")
+		}
+		args = appendValues(&values, args, row, r.keyCols, r.keyOrdinals)
+		// The ordinal of the row is appended to the tuple.
+		values.Truncate(values.Len() - 1)
+		fmt.Fprintf(&values, ", %d)", i)
+	}
+	res, err := txn.QueryBufferedEx(ctx, "subscription-lookup", txn.KV(),
+		sessiondata.NodeUserSessionDataOverride,
+		fmt.Sprintf(`SELECT crdb_internal.encode_key(%d, %d, ROW(%s)), t.crdb_internal_mvcc_timestamp
+FROM (VALUES %s) AS v (%s, ord) LEFT JOIN %s AS t ON %s ORDER BY v.ord`,
+			r.tableID, r.primaryIndexID, keyTuple.String(),
+			values.String(), aliases.String(), r.tableName, joinPreds.String(),
+		), args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(res) != len(rows) {
+		return nil, nil, errors.AssertionFailedf("looked up %d rows, expected %d", len(res), len(rows))
+	}
+	keys = make([]string, len(rows))
+	timestamps = make([]hlc.Timestamp, len(rows))
+	for i, row := range res {
+		keys[i] = string(tree.MustBeDBytes(row[0]))
+		if row[1] != tree.DNull {
+			if timestamps[i], err = hlc.DecimalToHLC(&tree.MustBeDDecimal(row[1]).Decimal); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return keys, timestamps, nil
+}
+
+// write inserts or updates the given rows of the publisher, or deletes the
+// rows with their keys.
+func (r *subscribedRelation) write(
+	ctx context.Context, txn isql.Txn, rows [][]pgoutput.Value, isDelete bool,
+) error {
+	var buf bytes.Buffer
+	var args []interface{}
+	if isDelete {
+		fmt.Fprintf(&buf, "DELETE FROM %s WHERE (", r.tableName)
+		for i, col := range r.keyCols {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(tree.NameString(col.GetName()))
+		}
+		buf.WriteString(") IN (")
+		for i, row := range rows {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			args = appendValues(&buf, args, row, r.keyCols, r.keyOrdinals)
+		}
+		buf.WriteString(")")
+	} else {
+		fmt.Fprintf(&buf, "UPSERT INTO %s (", r.tableName)
+		for i, col := range r.cols {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(tree.NameString(col.GetName()))
+		}
+		buf.WriteString(") VALUES ")
+		for i, row := range rows {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			args = appendValues(&buf, args, row, r.cols, r.colOrdinals)
+		}
+	}
+	_, err := txn.ExecEx(ctx, "subscription-apply", txn.KV(),
+		sessiondata.NodeUserSessionDataOverride, buf.String(), args...)
+	return err
+}
+
+// subscribedChangeWins returns whether a change committed on the publisher at
+// origin must be applied to a row, given the origin state of the row, if any,
+// and the MVCC timestamp of the row, which is empty if it doesn't exist.
+func subscribedChangeWins(
+	origin hlc.Timestamp, state *jobspb.SubscriptionRowOrigin, rowTimestamp hlc.Timestamp,
+) bool {
+	exists := !rowTimestamp.IsEmpty()
+	switch {
+	case exists && state != nil && !state.Deleted && rowTimestamp == state.WrittenTimestamp:
+		// The row was last written by the subscription.
+		return state.OriginTimestamp.Less(origin)
+	case exists:
+		// The row was last written locally.
+		return rowTimestamp.Less(origin)
+	case state != nil && state.Deleted:
+		// The row was last deleted by the subscription.
+		return state.OriginTimestamp.Less(origin)
+	case state != nil:
+		// The row was deleted locally, after the subscription last wrote it.
+		return state.WrittenTimestamp.Less(origin)
+	default:
+		return true
+	}
+}
+
+// subscriptionApplier applies the changes replicated by a subscription.
+type subscriptionApplier struct {
+	execCfg *ExecutorConfig
+	job     *jobs.Job
+	dbID    descpb.ID
+	// relations are the tables of the publisher, by relation ID.
+	relations map[uint32]*subscribedRelation
+}
+
+// setRelation prepares the application of the changes of a table of the
+// publisher, replacing its previous description.
+func (a *subscriptionApplier) setRelation(ctx context.Context, rel *pgoutput.Relation) error {
+	r, err := newSubscribedRelation(ctx, a.execCfg, a.dbID, rel)
+	if err != nil {
+		return err
+	}
+	a.relations[rel.ID] = r
+	return nil
+}
+
+// apply applies the changes of a transaction of the publisher, committed at
+// the given timestamp, in a single transaction. Consecutive changes of the
+// same kind to the same relation are applied in batches, which preserves the
+// order of the changes to different relations, e.g. for foreign keys.
+func (a *subscriptionApplier) apply(
+	ctx context.Context, origin hlc.Timestamp, changes []pgoutput.Message,
+) error {
+	return a.execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		var rel *subscribedRelation
+		var isDelete bool
+		var batch [][]pgoutput.Value
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			err := a.applyBatch(ctx, txn, rel, origin, batch, isDelete)
+			batch = batch[:0]
+			return err
+		}
+		for _, c := range changes {
+			var relID uint32
+			var row []pgoutput.Value
+			del := false
+			switch c := c.(type) {
+			case *pgoutput.Insert:
+				relID, row = c.RelationID, c.Row
+			case *pgoutput.Update:
+				relID, row = c.RelationID, c.Row
+			case *pgoutput.Delete:
+				relID, row, del = c.RelationID, c.Key, true
+			default:
+				return errors.AssertionFailedf("unexpected change %T", c)
+			}
+			r, ok := a.relations[relID]
+			if !ok {
+				return errors.Newf("change to unknown relation %d", relID)
+			}
+			if r != rel || del != isDelete || len(batch) == subscriptionApplyBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+				rel, isDelete = r, del
+			}
+			batch = append(batch, row)
+		}
+		return flush()
+	})
+}
+
+// applyBatch applies changes of the same kind to a relation, committed on the
+// publisher at origin, and updates the origin state of the rows. Only the
+// changes committed after their row was last written are applied, and only the
+// last change to each row.
+func (a *subscriptionApplier) applyBatch(
+	ctx context.Context,
+	txn isql.Txn,
+	rel *subscribedRelation,
+	origin hlc.Timestamp,
+	rows [][]pgoutput.Value,
+	isDelete bool,
+) error {
+	keys, timestamps, err := rel.lookup(ctx, txn, rows)
+	if err != nil {
+		return err
+	}
+	last := make(map[string]int, len(keys))
+	for i, k := range keys {
+		last[k] = i
+	}
+	var infoKeys []string
+	var ordinals []int
+	for i, k := range keys {
+		if last[k] == i {
+			infoKeys = append(infoKeys, fmt.Sprintf("%s%x", subscriptionOriginInfoKeyPrefix, k))
+			ordinals = append(ordinals, i)
+		}
+	}
+	infoStorage := a.job.InfoStorage(txn)
+	values, err := infoStorage.GetMany(ctx, infoKeys)
+	if err != nil {
+		return err
+	}
+
+	newState := jobspb.SubscriptionRowOrigin{OriginTimestamp: origin, Deleted: isDelete}
+	if !isDelete {
+		// The origin state records the MVCC timestamp of the rows written by
+		// the subscription, so the commit timestamp of the transaction is fixed.
+		if newState.WrittenTimestamp, err = txn.KV().CommitTimestamp(); err != nil {
+			return err
+		}
+	}
+	newValue, err := protoutil.Marshal(&newState)
+	if err != nil {
+		return err
+	}
+	var applied [][]pgoutput.Value
+	var appliedKeys []string
+	var appliedValues [][]byte
+	for j, i := range ordinals {
+		var state *jobspb.SubscriptionRowOrigin
+		if values[j] != nil {
+			state = &jobspb.SubscriptionRowOrigin{}
+			if err := protoutil.Unmarshal(values[j], state); err != nil {
+				return err
+			}
+		}
+		if !subscribedChangeWins(origin, state, timestamps[i]) {
+			continue
+		}
+		applied = append(applied, rows[i])
+		appliedKeys = append(appliedKeys, infoKeys[j])
+		appliedValues = append(appliedValues, newValue)
+	}
+	if len(applied) == 0 {
+		return nil
+	}
+	if err := rel.write(ctx, txn, applied, isDelete); err != nil {
+		return err
+	}
+	return infoStorage.WriteMany(ctx, appliedKeys, appliedValues)
+}
+
+// subscriptionResumer implements the jobs.Resumer interface for
+// subscriptions.
+type subscriptionResumer struct {
+	job *jobs.Job
+
+	// appliedLSN is the position of the publisher up to which all the changes
+	// were applied, and reportedLSN the position last persisted in the job's
+	// progress.
+	appliedLSN, reportedLSN lsn.LSN
+}
+
+var _ jobs.Resumer = (*subscriptionResumer)(nil)
+
+// Resume implements the jobs.Resumer interface. The subscription runs until it
+// is dropped, reconnecting to the publisher on errors.
+func (r *subscriptionResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	execCfg := execCtx.(JobExecContext).ExecCfg()
+	details := r.job.Details().(jobspb.SubscriptionDetails)
+	progress := r.job.Progress()
+	r.appliedLSN = lsn.LSN(progress.GetSubscription().AppliedLSN)
+	r.reportedLSN = r.appliedLSN
+
+	opts := retry.Options{InitialBackoff: time.Second, MaxBackoff: time.Minute, Multiplier: 2}
+	for rt := retry.StartWithCtx(ctx, opts); rt.Next(); {
+		lastApplied := r.appliedLSN
+		err := r.run(ctx, execCfg, details)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if jobs.IsPermanentJobError(err) {
+			return err
+		}
+		log.Warningf(ctx, "subscription %q failed, retrying: %v", details.Name, err)
+		if statusErr := r.job.NoTxn().RunningStatus(ctx,
+			jobs.RunningStatus(fmt.Sprintf("retrying after error: %v", err)),
+		); statusErr != nil {
+			return statusErr
+		}
+		if r.appliedLSN > lastApplied {
+			rt.Reset()
+		}
+	}
+	return ctx.Err()
+}
+
+func (r *subscriptionResumer) run(
+	ctx context.Context, execCfg *ExecutorConfig, details jobspb.SubscriptionDetails,
+) error {
+	if details.CopyData && !r.job.Progress().GetSubscription().CopyDone {
+		if err := r.job.NoTxn().RunningStatus(ctx, "copying the existing rows"); err != nil {
+			return err
+		}
+		if err := r.copyData(ctx, execCfg, details); err != nil {
+			return err
+		}
+	}
+	if err := r.job.NoTxn().RunningStatus(ctx, "streaming changes"); err != nil {
+		return err
+	}
+	return r.stream(ctx, execCfg, details)
+}
+
+// copyData copies the rows of the published tables as of the consistent point
+// of the replication slot. The copied rows are applied like changes committed
+// at that point.
+func (r *subscriptionResumer) copyData(
+	ctx context.Context, execCfg *ExecutorConfig, details jobspb.SubscriptionDetails,
+) error {
+	conn, err := connectToPublisher(ctx, details.ConnectionURI, false /* replication */)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close(ctx) }()
+	tables, err := publishedTables(ctx, conn, details.Publications)
+	if err != nil {
+		return err
+	}
+	snapshot := lsnutil.LSNToHLC(lsn.LSN(details.SnapshotLSN))
+	a := &subscriptionApplier{
+		execCfg:   execCfg,
+		job:       r.job,
+		dbID:      details.DatabaseID,
+		relations: make(map[uint32]*subscribedRelation),
+	}
+	for i, t := range tables {
+		result := conn.Exec(ctx, fmt.Sprintf("SELECT * FROM %s.%s AS OF SYSTEM TIME %s",
+			tree.NameString(t.schema), tree.NameString(t.name),
+			lexbase.EscapeSQLString(snapshot.AsOfSystemTime())))
+		if !result.NextResult() {
+			return result.Close()
+		}
+		rr := result.ResultReader()
+		rel := &pgoutput.Relation{ID: uint32(i), Namespace: t.schema, Name: t.name}
+		for _, f := range rr.FieldDescriptions() {
+			rel.Columns = append(rel.Columns, pgoutput.Column{Name: f.Name})
+		}
+		if err := a.setRelation(ctx, rel); err != nil {
+			_ = result.Close()
+			return err
+		}
+		var batch []pgoutput.Message
+		for rr.NextRow() {
+			values := rr.Values()
+			row := make([]pgoutput.Value, len(values))
+			for j, v := range values {
+				if v == nil {
+					row[j].Null = true
+				} else {
+					// The values are only valid until the next row is read.
+					row[j].Text = append([]byte(nil), v...)
+				}
+			}
+			batch = append(batch, &pgoutput.Insert{RelationID: rel.ID, Row: row})
+			if len(batch) == subscriptionCopyBatchSize {
+				if err := a.apply(ctx, snapshot, batch); err != nil {
+					_ = result.Close()
+					return err
+				}
+				batch = batch[:0]
+			}
+		}
+		if _, err := rr.Close(); err != nil {
+			_ = result.Close()
+			return err
+		}
+		if err := result.Close(); err != nil {
+			return err
+		}
+		if err := a.apply(ctx, snapshot, batch); err != nil {
+			return err
+		}
+	}
+	return r.job.NoTxn().Update(ctx, func(
+		txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		if err := md.CheckRunningOrReverting(); err != nil {
+			return err
+		}
+		md.Progress.GetSubscription().CopyDone = true
+		md.Progress.Progress = &jobspb.Progress_HighWater{HighWater: &snapshot}
+		ju.UpdateProgress(md.Progress)
+		return nil
+	})
+}
+
+// stream applies the changes streamed from the replication slot, starting
+// after the last applied position.
+func (r *subscriptionResumer) stream(
+	ctx context.Context, execCfg *ExecutorConfig, details jobspb.SubscriptionDetails,
+) error {
+	conn, err := connectToPublisher(ctx, details.ConnectionURI, true /* replication */)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close(ctx) }()
+
+	start := r.appliedLSN
+	if snapshot := lsn.LSN(details.SnapshotLSN); start < snapshot {
+		start = snapshot
+	}
+	pubNames := make([]string, len(details.Publications))
+	for i, name := range details.Publications {
+		pubNames[i] = `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	}
+	fe := conn.Frontend()
+	fe.Send(&pgproto3.Query{String: fmt.Sprintf(
+		"START_REPLICATION SLOT %s LOGICAL %s (proto_version '%d', publication_names %s)",
+		details.SlotName, start, pgoutput.ProtoVersion,
+		lexbase.EscapeSQLString(strings.Join(pubNames, ",")),
+	)})
+	if err := fe.Flush(); err != nil {
+		return err
+	}
+	for started := false; !started; {
+		msg, err := conn.ReceiveMessage(ctx)
+		if err != nil {
+			return err
+		}
+		switch msg := msg.(type) {
+		case *pgproto3.CopyBothResponse:
+			started = true
+		case *pgproto3.ErrorResponse:
+			return pgconn.ErrorResponseToPgError(msg)
+		case *pgproto3.NoticeResponse, *pgproto3.ParameterStatus:
+		default:
+			return errors.Newf("unexpected message %T from the publisher", msg)
+		}
+	}
+
+	a := &subscriptionApplier{
+		execCfg:   execCfg,
+		job:       r.job,
+		dbID:      details.DatabaseID,
+		relations: make(map[uint32]*subscribedRelation),
+	}
+	// changes are the changes of the transaction being received, if inTxn is
+	// set.
+	var changes []pgoutput.Message
+	inTxn := false
+	nextStatus := timeutil.Now().Add(subscriptionStatusInterval)
+	for {
+		if now := timeutil.Now(); !now.Before(nextStatus) {
+			if err := r.reportProgress(ctx, conn); err != nil {
+				return err
+			}
+			nextStatus = now.Add(subscriptionStatusInterval)
+		}
+		receiveCtx, cancel := context.WithDeadline(ctx, nextStatus)
+		msg, err := conn.ReceiveMessage(receiveCtx)
+		cancel()
+		if err != nil {
+			if pgconn.Timeout(err) && ctx.Err() == nil {
+				continue
+			}
+			return err
+		}
+		var data []byte
+		switch msg := msg.(type) {
+		case *pgproto3.CopyData:
+			data = msg.Data
+		case *pgproto3.ErrorResponse:
+			return pgconn.ErrorResponseToPgError(msg)
+		case *pgproto3.CopyDone:
+			return errors.New("the publisher ended the replication stream")
+		case *pgproto3.NoticeResponse, *pgproto3.ParameterStatus:
+			continue
+		default:
+			return errors.Newf("unexpected message %T from the publisher", msg)
+		}
+
+		xlog, keepalive, err := pgoutput.ParseServerMessage(data)
+		if err != nil {
+			return err
+		}
+		if keepalive != nil {
+			// All the changes up to the end of the stream were received, since the
+			// messages are received in order.
+			if !inTxn && r.appliedLSN < keepalive.EndLSN {
+				r.appliedLSN = keepalive.EndLSN
+			}
+			if keepalive.ReplyRequested {
+				nextStatus = time.Time{}
+			}
+			continue
+		}
+		// The data of the received messages is reused by the connection, so the
+		// message is copied as the changes are buffered until their commit.
+		m, err := pgoutput.ParseMessage(append([]byte(nil), xlog.Data...))
+		if err != nil {
+			return err
+		}
+		switch m := m.(type) {
+		case *pgoutput.Begin:
+			inTxn = true
+			changes = changes[:0]
+		case *pgoutput.Relation:
+			if err := a.setRelation(ctx, m); err != nil {
+				return err
+			}
+		case *pgoutput.Insert, *pgoutput.Update, *pgoutput.Delete:
+			if !inTxn {
+				return errors.Newf("change received outside of a transaction")
+			}
+			changes = append(changes, m)
+		case *pgoutput.Commit:
+			if err := a.apply(ctx, lsnutil.LSNToHLC(m.CommitLSN), changes); err != nil {
+				return err
+			}
+			inTxn = false
+			changes = changes[:0]
+			if r.appliedLSN < m.CommitLSN {
+				r.appliedLSN = m.CommitLSN
+			}
+		}
+	}
+}
+
+// reportProgress persists the applied position in the job's progress, whose
+// high water is the commit time of the last applied transaction, and confirms
+// the position to the publisher so that the replication slot can advance.
+func (r *subscriptionResumer) reportProgress(ctx context.Context, conn *pgconn.PgConn) error {
+	applied := r.appliedLSN
+	if applied > r.reportedLSN {
+		if err := r.job.NoTxn().Update(ctx, func(
+			txn isql.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+		) error {
+			if err := md.CheckRunningOrReverting(); err != nil {
+				return err
+			}
+			highWater := lsnutil.LSNToHLC(applied)
+			md.Progress.GetSubscription().AppliedLSN = uint64(applied)
+			md.Progress.Progress = &jobspb.Progress_HighWater{HighWater: &highWater}
+			ju.UpdateProgress(md.Progress)
+			return nil
+		}); err != nil {
+			return err
+		}
+		r.reportedLSN = applied
+	}
+	fe := conn.Frontend()
+	fe.Send(&pgproto3.CopyData{Data: pgoutput.AppendStandbyStatusUpdate(nil, pgoutput.StandbyStatusUpdate{
+		WrittenLSN: applied,
+		FlushedLSN: applied,
+		AppliedLSN: applied,
+		ClientTime: timeutil.Now(),
+	})})
+	return fe.Flush()
+}
+
+// OnFailOrCancel implements the jobs.Resumer interface. It drops the
+// replication slot if it was created for the subscription. Failing to drop the
+// slot does not fail the job, since the publisher may be unreachable: the slot
+// must then be dropped manually.
+func (r *subscriptionResumer) OnFailOrCancel(
+	ctx context.Context, execCtx interface{}, jobErr error,
+) error {
+	details := r.job.Details().(jobspb.SubscriptionDetails)
+	if !details.CreatedSlot {
+		return nil
+	}
+	// The slot remains active until the publisher notices that the streaming
+	// connection was closed, so dropping it is retried.
+	var err error
+	opts := retry.Options{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, MaxRetries: 10}
+	for rt := retry.StartWithCtx(ctx, opts); rt.Next(); {
+		if err = dropPublisherSlot(ctx, details); err == nil {
+			return nil
+		}
+		if pgerror.GetPGCode(err) == pgcode.UndefinedObject {
+			return nil
+		}
+	}
+	log.Warningf(ctx, "could not drop the replication slot %q of subscription %q: %v",
+		details.SlotName, details.Name, err)
+	return nil
+}
+
+func dropPublisherSlot(ctx context.Context, details jobspb.SubscriptionDetails) error {
+	conn, err := connectToPublisher(ctx, details.ConnectionURI, true /* replication */)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close(ctx) }()
+	_, err = queryPublisher(ctx, conn, "DROP_REPLICATION_SLOT "+details.SlotName)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgerror.WithCandidateCode(err, pgcode.MakeCode(pgErr.Code))
+	}
+	return err
+}
+
+// CollectProfile implements the jobs.Resumer interface.
+func (r *subscriptionResumer) CollectProfile(context.Context, interface{}) error {
+	return nil
+}
+
+func init() {
+	jobs.RegisterConstructor(jobspb.TypeSubscription,
+		func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+			return &subscriptionResumer{job: job}
+		},
+		jobs.UsesTenantCostControl,
+	)
+}
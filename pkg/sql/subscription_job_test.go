@@ -0,0 +1,52 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribedChangeWins(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ts := func(wallTime int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wallTime} }
+	written := &jobspb.SubscriptionRowOrigin{OriginTimestamp: ts(10), WrittenTimestamp: ts(20)}
+	deleted := &jobspb.SubscriptionRowOrigin{OriginTimestamp: ts(10), Deleted: true}
+
+	for _, tc := range []struct {
+		name   string
+		origin hlc.Timestamp
+		state  *jobspb.SubscriptionRowOrigin
+		row    hlc.Timestamp
+		exp    bool
+	}{
+		{name: "new row", origin: ts(5), exp: true},
+		{name: "local row, newer change", origin: ts(40), row: ts(30), exp: true},
+		{name: "local row, older change", origin: ts(25), row: ts(30), exp: false},
+		{name: "replicated row, newer change", origin: ts(15), state: written, row: ts(20), exp: true},
+		{name: "replicated row, older change", origin: ts(5), state: written, row: ts(20), exp: false},
+		{name: "replicated row written locally", origin: ts(25), state: written, row: ts(30), exp: false},
+		{name: "tombstone, newer change", origin: ts(15), state: deleted, exp: true},
+		{name: "tombstone, older change", origin: ts(5), state: deleted, exp: false},
+		{name: "local row over tombstone", origin: ts(15), state: deleted, row: ts(30), exp: false},
+		{name: "deleted locally, newer change", origin: ts(25), state: written, exp: true},
+		{name: "deleted locally, older change", origin: ts(15), state: written, exp: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.exp, subscribedChangeWins(tc.origin, tc.state, tc.row))
+		})
+	}
+}
@@ -148,6 +148,9 @@ type tableWriterBase struct {
 	sv *settings.Values
 	// Adapter to make expose a kv.Batch as a Putter
 	putter row.KVBatchAdapter
+	// deferredChecks, if set, records the keys of the written rows that must
+	// be checked for deferred constraints before the transaction commits.
+	deferredChecks *deferredCheckRecorder
 }
 
 var maxBatchBytes = settings.RegisterByteSizeSetting(
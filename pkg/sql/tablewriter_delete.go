@@ -52,7 +52,10 @@ func (td *tableDeleter) row(
 	ctx context.Context, values tree.Datums, pm row.PartialIndexUpdateHelper, traceKV bool,
 ) error {
 	td.currentBatchSize++
-	return td.rd.DeleteRow(ctx, td.b, values, pm, traceKV)
+	if err := td.rd.DeleteRow(ctx, td.b, values, pm, traceKV); err != nil {
+		return err
+	}
+	return td.deferredChecks.recordDelete(ctx, values, td.rd.FetchColIDtoRowIndex)
 }
 
 // deleteIndex runs the kv operations necessary to delete all kv entries in the
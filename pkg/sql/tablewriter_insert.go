@@ -44,7 +44,10 @@ func (ti *tableInserter) row(
 	ctx context.Context, values tree.Datums, pm row.PartialIndexUpdateHelper, traceKV bool,
 ) error {
 	ti.currentBatchSize++
-	return ti.ri.InsertRow(ctx, &ti.putter, values, pm, false /* overwrite */, traceKV)
+	if err := ti.ri.InsertRow(ctx, &ti.putter, values, pm, false /* overwrite */, traceKV); err != nil {
+		return err
+	}
+	return ti.deferredChecks.recordInsert(ctx, values, ti.ri.InsertColIDtoRowIndex)
 }
 
 // tableDesc is part of the tableWriter interface.
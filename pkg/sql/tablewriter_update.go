@@ -57,7 +57,14 @@ func (tu *tableUpdater) rowForUpdate(
 	traceKV bool,
 ) (tree.Datums, error) {
 	tu.currentBatchSize++
-	return tu.ru.UpdateRow(ctx, tu.b, oldValues, updateValues, pm, traceKV)
+	newValues, err := tu.ru.UpdateRow(ctx, tu.b, oldValues, updateValues, pm, traceKV)
+	if err != nil {
+		return nil, err
+	}
+	if err := tu.deferredChecks.recordUpdate(ctx, oldValues, newValues, tu.ru.FetchColIDtoRowIndex); err != nil {
+		return nil, err
+	}
+	return newValues, nil
 }
 
 // tableDesc is part of the tableWriter interface.
@@ -18,10 +18,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
 )
 
 // optTableUpserter implements the upsert operation when it is planned by the
@@ -46,6 +48,11 @@ import (
 //
 // For more details on how the CBO compiles UPSERT statements, see the block
 // comment on Builder.buildInsert in opt/optbuilder/insert.go.
+//
+// optTableUpserter also implements MERGE statements. In that case, the CBO
+// projects an additional action column that decides whether each input row
+// is inserted, updates the existing row, or deletes the existing row. See the
+// block comment on Builder.buildMerge in opt/optbuilder/merge.go.
 type optTableUpserter struct {
 	tableWriterBase
 
@@ -80,12 +87,21 @@ type optTableUpserter struct {
 	// an update is performed. This column will always be one of the fetchCols.
 	canaryOrdinal int
 
+	// mergeActionOrdinal is the ordinal position of the column within the input
+	// row that holds the opt.MergeAction to take for the row when the upsert
+	// implements a MERGE statement. It is -1 for all other statements, in which
+	// case the canary column decides whether to insert or update.
+	mergeActionOrdinal int
+
 	// resultRow is a reusable slice of Datums used to store result rows.
 	resultRow tree.Datums
 
 	// ru is used when updating rows.
 	ru row.Updater
 
+	// rd is used when deleting rows for a MERGE statement.
+	rd row.Deleter
+
 	// tabColIdxToRetIdx is the mapping from the columns in the table to the
 	// columns in the resultRowBuffer. A value of -1 is used to indicate
 	// that the table column at that index is not part of the resultRowBuffer
@@ -169,6 +185,10 @@ func (tu *optTableUpserter) row(
 ) error {
 	tu.currentBatchSize++
 
+	if tu.mergeActionOrdinal != -1 {
+		return tu.mergeRow(ctx, row, pm, traceKV)
+	}
+
 	// Consult the canary column to determine whether to insert or update. For
 	// more details on how canary columns work, see the block comment on
 	// Builder.buildInsert in opt/optbuilder/insert.go.
@@ -205,6 +225,56 @@ func (tu *optTableUpserter) row(
 	)
 }
 
+// mergeRow applies the action that a MERGE statement chose for the given
+// source row: the row is either inserted, or the existing row it matched is
+// updated or deleted.
+func (tu *optTableUpserter) mergeRow(
+	ctx context.Context, row tree.Datums, pm row.PartialIndexUpdateHelper, traceKV bool,
+) error {
+	insertEnd := len(tu.ri.InsertCols)
+	fetchEnd := insertEnd + len(tu.fetchCols)
+	switch opt.MergeAction(tree.MustBeDInt(row[tu.mergeActionOrdinal])) {
+	case opt.MergeActionInsert:
+		return tu.insertNonConflictingRow(ctx, row[:insertEnd], pm, false /* overwrite */, traceKV)
+
+	case opt.MergeActionUpdate:
+		updateEnd := fetchEnd + len(tu.updateCols)
+		return tu.updateConflictingRow(
+			ctx,
+			tu.b,
+			row[insertEnd:fetchEnd],
+			row[fetchEnd:updateEnd],
+			pm,
+			traceKV,
+		)
+
+	case opt.MergeActionDelete:
+		fetchRow := row[insertEnd:fetchEnd]
+		if err := tu.rd.DeleteRow(ctx, tu.b, fetchRow, pm, traceKV); err != nil {
+			return err
+		}
+		if err := tu.deferredChecks.recordDelete(ctx, fetchRow, tu.rd.FetchColIDtoRowIndex); err != nil {
+			return err
+		}
+		if !tu.rowsNeeded {
+			return nil
+		}
+
+		// A deleted row is returned with its existing values.
+		tableRow := tu.makeResultFromRow(fetchRow, tu.rd.FetchColIDtoRowIndex)
+		for tabIdx := range tableRow {
+			if retIdx := tu.tabColIdxToRetIdx[tabIdx]; retIdx >= 0 {
+				tu.resultRow[retIdx] = tableRow[tabIdx]
+			}
+		}
+		_, err := tu.rows.AddRow(ctx, tu.resultRow)
+		return err
+
+	default:
+		return errors.AssertionFailedf("unexpected merge action %s", row[tu.mergeActionOrdinal])
+	}
+}
+
 // insertNonConflictingRow inserts the given source row into the table when
 // there was no conflict. If the RETURNING clause was specified, then the
 // inserted row is stored in the rowsUpserted collection.
@@ -218,6 +288,9 @@ func (tu *optTableUpserter) insertNonConflictingRow(
 	if err := tu.ri.InsertRow(ctx, &tu.putter, insertRow, pm, overwrite, traceKV); err != nil {
 		return err
 	}
+	if err := tu.deferredChecks.recordInsert(ctx, insertRow, tu.ri.InsertColIDtoRowIndex); err != nil {
+		return err
+	}
 
 	if !tu.rowsNeeded {
 		return nil
@@ -277,10 +350,13 @@ func (tu *optTableUpserter) updateConflictingRow(
 	// Queue the update in KV. This also returns an "update row"
 	// containing the updated values for every column in the
 	// table. This is useful for RETURNING, which we collect below.
-	_, err := tu.ru.UpdateRow(ctx, b, fetchRow, updateValues, pm, traceKV)
+	newValues, err := tu.ru.UpdateRow(ctx, b, fetchRow, updateValues, pm, traceKV)
 	if err != nil {
 		return err
 	}
+	if err := tu.deferredChecks.recordUpdate(ctx, fetchRow, newValues, tu.ru.FetchColIDtoRowIndex); err != nil {
+		return err
+	}
 
 	// We only need a result row if we're collecting rows.
 	if !tu.rowsNeeded {
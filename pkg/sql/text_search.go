@@ -0,0 +1,982 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/paramparse"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
+	"github.com/cockroachdb/errors"
+)
+
+// Text search configurations and dictionaries are stored in the descriptor of
+// the database in which they are created, and can be used by name in the
+// sessions connected to that database. Their names cannot shadow the names of
+// the built-in configurations and dictionaries. The expressions of tables and
+// views refer to the configurations of their database by ID, so that they are
+// evaluated the same way in all sessions, and the configurations keep
+// back-references to the tables and views that use them.
+
+// textSearchDDLNode implements the text search DDL statements, which all
+// rewrite the text search objects of the current database.
+type textSearchDDLNode struct {
+	n      tree.Statement
+	dbDesc *dbdesc.Mutable
+	// apply modifies the database descriptor. It returns false if nothing
+	// was changed, for example because of IF EXISTS.
+	apply func() (bool, error)
+}
+
+// mutableTextSearchDatabase returns the current database, in which text
+// search configurations and dictionaries are created, altered and dropped,
+// after checking that the cluster version allows storing them and that the
+// user has the CREATE privilege on it.
+func (p *planner) mutableTextSearchDatabase(
+	ctx context.Context, op string,
+) (*dbdesc.Mutable, error) {
+	if err := checkSchemaChangeEnabled(ctx, p.ExecCfg(), op); err != nil {
+		return nil, err
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V24_1_TextSearchConfigurations) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use %s",
+			clusterversion.V24_1_TextSearchConfigurations.Version(), op)
+	}
+	if p.CurrentDatabase() == "" {
+		return nil, pgerror.New(pgcode.UndefinedDatabase,
+			"cannot use text search configurations without being connected to a database")
+	}
+	dbDesc, err := p.Descriptors().MutableByName(p.txn).Database(ctx, p.CurrentDatabase())
+	if err != nil {
+		return nil, err
+	}
+	if dbDesc.GetID() == keys.SystemDatabaseID {
+		return nil, pgerror.New(pgcode.InvalidObjectDefinition,
+			"cannot use text search configurations in the system database")
+	}
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	return dbDesc, nil
+}
+
+// CreateTextSearchConfig creates a text search configuration in the current
+// database.
+// Privileges: CREATE on the database.
+//
+//	notes: postgres requires CREATE on the schema.
+func (p *planner) CreateTextSearchConfig(
+	ctx context.Context, n *tree.CreateTextSearchConfig,
+) (planNode, error) {
+	dbDesc, err := p.mutableTextSearchDatabase(ctx, "CREATE TEXT SEARCH CONFIGURATION")
+	if err != nil {
+		return nil, err
+	}
+	name := string(n.Name)
+	if tsearch.IsBuiltinConfig(name) || dbDesc.GetTextSearchConfiguration(name) != nil {
+		return nil, pgerror.Newf(pgcode.DuplicateObject,
+			"text search configuration %q already exists", name)
+	}
+	config := descpb.TextSearchConfigurationDescriptor{Name: name}
+	var parser, source string
+	eval := p.ExprEvaluator("CREATE TEXT SEARCH CONFIGURATION")
+	seen := make(map[string]struct{}, len(n.Options))
+	for _, opt := range n.Options {
+		key := string(opt.Key)
+		if _, ok := seen[key]; ok {
+			return nil, pgerror.Newf(pgcode.Syntax, "conflicting or redundant options")
+		}
+		seen[key] = struct{}{}
+		switch key {
+		case "parser":
+			if _, ok := opt.Value.(tree.DefaultVal); ok {
+				parser = "default"
+				break
+			}
+			if parser, err = eval.String(ctx, paramparse.UnresolvedNameToStrVal(opt.Value)); err != nil {
+				return nil, err
+			}
+			if tsearch.GetConfigKey(parser) != "default" {
+				return nil, pgerror.Newf(pgcode.UndefinedObject,
+					"text search parser %q does not exist", parser)
+			}
+		case "copy":
+			if source, err = eval.String(ctx, paramparse.UnresolvedNameToStrVal(opt.Value)); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, pgerror.Newf(pgcode.Syntax,
+				"text search configuration parameter %q not recognized", key)
+		}
+	}
+	switch {
+	case parser != "" && source != "":
+		return nil, pgerror.New(pgcode.Syntax, "cannot specify both PARSER and COPY options")
+	case source != "":
+		if config.Mappings, err = textSearchConfigMappings(dbDesc, source); err != nil {
+			return nil, err
+		}
+	case parser == "":
+		return nil, pgerror.New(pgcode.InvalidObjectDefinition, "text search parser is required")
+	}
+	return &textSearchDDLNode{n: n, dbDesc: dbDesc, apply: func() (bool, error) {
+		dbDesc.AddTextSearchConfiguration(config)
+		return true, nil
+	}}, nil
+}
+
+// textSearchConfigMappings returns a copy of the mappings of the built-in or
+// user-defined text search configuration with the given name.
+func textSearchConfigMappings(
+	db catalog.DatabaseDescriptor, name string,
+) ([]descpb.TextSearchConfigurationDescriptor_Mapping, error) {
+	if tsearch.IsBuiltinConfig(name) {
+		config, err := tsearch.GetBuiltinConfig(name)
+		if err != nil {
+			return nil, err
+		}
+		var mappings []descpb.TextSearchConfigurationDescriptor_Mapping
+		for _, t := range tsearch.TokenTypes() {
+			dicts := config.Mappings[t]
+			if len(dicts) == 0 {
+				continue
+			}
+			m := descpb.TextSearchConfigurationDescriptor_Mapping{TokenType: t.String()}
+			for _, d := range dicts {
+				m.Dictionaries = append(m.Dictionaries, d.Name)
+			}
+			mappings = append(mappings, m)
+		}
+		return mappings, nil
+	}
+	config := db.GetTextSearchConfiguration(name)
+	if config == nil {
+		return nil, errTextSearchConfigDoesNotExist(name)
+	}
+	mappings := make([]descpb.TextSearchConfigurationDescriptor_Mapping, len(config.Mappings))
+	for i, m := range config.Mappings {
+		mappings[i] = descpb.TextSearchConfigurationDescriptor_Mapping{
+			TokenType:    m.TokenType,
+			Dictionaries: append([]string(nil), m.Dictionaries...),
+		}
+	}
+	return mappings, nil
+}
+
+// CreateTextSearchDictionary creates a text search dictionary in the current
+// database.
+// Privileges: CREATE on the database.
+//
+//	notes: postgres requires CREATE on the schema.
+func (p *planner) CreateTextSearchDictionary(
+	ctx context.Context, n *tree.CreateTextSearchDictionary,
+) (planNode, error) {
+	dbDesc, err := p.mutableTextSearchDatabase(ctx, "CREATE TEXT SEARCH DICTIONARY")
+	if err != nil {
+		return nil, err
+	}
+	name := string(n.Name)
+	if tsearch.IsBuiltinDictionary(name) || dbDesc.GetTextSearchDictionary(name) != nil {
+		return nil, pgerror.Newf(pgcode.DuplicateObject,
+			"text search dictionary %q already exists", name)
+	}
+	dict := descpb.TextSearchDictionaryDescriptor{Name: name}
+	if err := p.evalTextSearchDictionaryOptions(
+		ctx, "CREATE TEXT SEARCH DICTIONARY", n.Options, &dict, true, /* create */
+	); err != nil {
+		return nil, err
+	}
+	return &textSearchDDLNode{n: n, dbDesc: dbDesc, apply: func() (bool, error) {
+		dbDesc.AddTextSearchDictionary(dict)
+		return true, nil
+	}}, nil
+}
+
+// AlterTextSearchDictionary changes the options of a text search dictionary
+// of the current database.
+// Privileges: CREATE on the database.
+//
+//	notes: postgres requires ownership of the dictionary.
+func (p *planner) AlterTextSearchDictionary(
+	ctx context.Context, n *tree.AlterTextSearchDictionary,
+) (planNode, error) {
+	dbDesc, err := p.mutableTextSearchDatabase(ctx, "ALTER TEXT SEARCH DICTIONARY")
+	if err != nil {
+		return nil, err
+	}
+	name := string(n.Name)
+	if tsearch.IsBuiltinDictionary(name) {
+		return nil, errBuiltinTextSearchObject("dictionary", name)
+	}
+	existing := dbDesc.GetTextSearchDictionary(name)
+	if existing == nil {
+		return nil, errTextSearchDictionaryDoesNotExist(name)
+	}
+	dict := cloneTextSearchDictionary(existing)
+	if err := p.evalTextSearchDictionaryOptions(
+		ctx, "ALTER TEXT SEARCH DICTIONARY", n.Options, &dict, false, /* create */
+	); err != nil {
+		return nil, err
+	}
+	return &textSearchDDLNode{n: n, dbDesc: dbDesc, apply: func() (bool, error) {
+		*dbDesc.GetTextSearchDictionary(name) = dict
+		return true, nil
+	}}, nil
+}
+
+// cloneTextSearchDictionary returns a deep copy of a dictionary descriptor.
+func cloneTextSearchDictionary(
+	dict *descpb.TextSearchDictionaryDescriptor,
+) descpb.TextSearchDictionaryDescriptor {
+	clone := *dict
+	clone.StopWordList = append([]string(nil), dict.StopWordList...)
+	clone.Synonyms = append([]descpb.TextSearchDictionaryDescriptor_Synonym(nil), dict.Synonyms...)
+	return clone
+}
+
+// evalTextSearchDictionaryOptions evaluates the options of CREATE and ALTER
+// TEXT SEARCH DICTIONARY into the dictionary descriptor, and validates the
+// result. The template of a dictionary is set when it is created and cannot
+// be changed. An option set to DEFAULT is reset.
+func (p *planner) evalTextSearchDictionaryOptions(
+	ctx context.Context,
+	op string,
+	opts tree.StorageParams,
+	dict *descpb.TextSearchDictionaryDescriptor,
+	create bool,
+) error {
+	eval := p.ExprEvaluator(op)
+	seen := make(map[string]struct{}, len(opts))
+	for _, opt := range opts {
+		key := string(opt.Key)
+		if _, ok := seen[key]; ok {
+			return pgerror.Newf(pgcode.Syntax, "conflicting or redundant options")
+		}
+		seen[key] = struct{}{}
+		_, reset := opt.Value.(tree.DefaultVal)
+		var value string
+		if !reset && key != "accept" {
+			var err error
+			if value, err = eval.String(ctx, paramparse.UnresolvedNameToStrVal(opt.Value)); err != nil {
+				return err
+			}
+		}
+		switch key {
+		case "template":
+			if !create {
+				return pgerror.New(pgcode.InvalidParameterValue,
+					"cannot change template of text search dictionary")
+			}
+			if reset {
+				return pgerror.New(pgcode.InvalidParameterValue, "text search template is required")
+			}
+			template, err := tsearch.DictionaryTemplateFromName(value)
+			if err != nil {
+				return err
+			}
+			dict.Template = template.String()
+		case "language":
+			dict.Language = strings.ToLower(value)
+		case "stopwords":
+			dict.StopWords = strings.ToLower(value)
+		case "stopword_list":
+			dict.StopWordList = nil
+			for _, word := range strings.Split(value, ",") {
+				if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+					dict.StopWordList = append(dict.StopWordList, word)
+				}
+			}
+		case "synonym_list":
+			dict.Synonyms = nil
+			for _, entry := range strings.Split(value, ",") {
+				words := strings.Fields(strings.ToLower(entry))
+				if len(words) == 0 {
+					continue
+				}
+				if len(words) != 2 {
+					return pgerror.Newf(pgcode.InvalidParameterValue,
+						"invalid synonym %q: expected a word and its synonym", strings.TrimSpace(entry))
+				}
+				dict.Synonyms = append(dict.Synonyms, descpb.TextSearchDictionaryDescriptor_Synonym{
+					Word: words[0], Synonym: words[1],
+				})
+			}
+		case "accept":
+			if reset {
+				dict.Accept = true
+				break
+			}
+			accept, err := eval.Bool(ctx, paramparse.UnresolvedNameToStrVal(opt.Value))
+			if err != nil {
+				return err
+			}
+			dict.Accept = accept
+		default:
+			return pgerror.Newf(pgcode.Syntax, "unrecognized text search dictionary parameter: %q", key)
+		}
+	}
+	if create {
+		if _, ok := seen["template"]; !ok {
+			return pgerror.New(pgcode.InvalidParameterValue, "text search template is required")
+		}
+		if _, ok := seen["accept"]; !ok {
+			dict.Accept = true
+		}
+	}
+	return validateTextSearchDictionary(dict)
+}
+
+// validateTextSearchDictionary checks that the options of a dictionary are
+// valid for its template.
+func validateTextSearchDictionary(dict *descpb.TextSearchDictionaryDescriptor) error {
+	template, err := tsearch.DictionaryTemplateFromName(dict.Template)
+	if err != nil {
+		return err
+	}
+	checkNotSet := func(set bool, param string) error {
+		if set {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"unrecognized %s dictionary parameter: %q", template, param)
+		}
+		return nil
+	}
+	switch template {
+	case tsearch.SimpleTemplate:
+		if err := checkNotSet(dict.Language != "", "language"); err != nil {
+			return err
+		}
+		if err := checkNotSet(len(dict.Synonyms) > 0, "synonym_list"); err != nil {
+			return err
+		}
+	case tsearch.SynonymTemplate:
+		if err := checkNotSet(dict.Language != "", "language"); err != nil {
+			return err
+		}
+		if err := checkNotSet(dict.StopWords != "", "stopwords"); err != nil {
+			return err
+		}
+		if err := checkNotSet(len(dict.StopWordList) > 0, "stopword_list"); err != nil {
+			return err
+		}
+		if err := checkNotSet(!dict.Accept, "accept"); err != nil {
+			return err
+		}
+		if len(dict.Synonyms) == 0 {
+			return pgerror.New(pgcode.InvalidParameterValue, "missing synonym_list parameter")
+		}
+	case tsearch.SnowballTemplate:
+		if err := checkNotSet(len(dict.Synonyms) > 0, "synonym_list"); err != nil {
+			return err
+		}
+		if err := checkNotSet(!dict.Accept, "accept"); err != nil {
+			return err
+		}
+		if dict.Language == "" {
+			return pgerror.New(pgcode.InvalidParameterValue, "missing language parameter")
+		}
+		if !tsearch.IsSnowballLanguage(dict.Language) {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"unrecognized snowball language: %q", dict.Language)
+		}
+	}
+	if dict.StopWords != "" {
+		if _, err := tsearch.GetStopWords(dict.StopWords); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AlterTextSearchConfig changes the mappings of a text search configuration of
+// the current database.
+// Privileges: CREATE on the database.
+//
+//	notes: postgres requires ownership of the configuration.
+func (p *planner) AlterTextSearchConfig(
+	ctx context.Context, n *tree.AlterTextSearchConfig,
+) (planNode, error) {
+	dbDesc, err := p.mutableTextSearchDatabase(ctx, "ALTER TEXT SEARCH CONFIGURATION")
+	if err != nil {
+		return nil, err
+	}
+	name := string(n.Name)
+	if tsearch.IsBuiltinConfig(name) {
+		return nil, errBuiltinTextSearchObject("configuration", name)
+	}
+	if dbDesc.GetTextSearchConfiguration(name) == nil {
+		return nil, errTextSearchConfigDoesNotExist(name)
+	}
+	tokenTypes := make([]string, len(n.TokenTypes))
+	for i, t := range n.TokenTypes {
+		tokenType, err := tsearch.TokenTypeFromName(string(t))
+		if err != nil {
+			return nil, err
+		}
+		tokenTypes[i] = tokenType.String()
+	}
+	resolveDict := func(name tree.Name) (string, error) {
+		if tsearch.IsBuiltinDictionary(string(name)) {
+			return tsearch.GetConfigKey(string(name)), nil
+		}
+		if dbDesc.GetTextSearchDictionary(string(name)) == nil {
+			return "", errTextSearchDictionaryDoesNotExist(string(name))
+		}
+		return string(name), nil
+	}
+	dicts := make([]string, len(n.Dictionaries))
+	for i, d := range n.Dictionaries {
+		if dicts[i], err = resolveDict(d); err != nil {
+			return nil, err
+		}
+	}
+	var oldDict, newDict string
+	if n.Cmd == tree.AlterTextSearchConfigReplaceDictionary {
+		if oldDict, err = resolveDict(n.OldDictionary); err != nil {
+			return nil, err
+		}
+		if newDict, err = resolveDict(n.NewDictionary); err != nil {
+			return nil, err
+		}
+	}
+	return &textSearchDDLNode{n: n, dbDesc: dbDesc, apply: func() (bool, error) {
+		config := dbDesc.GetTextSearchConfiguration(name)
+		findMapping := func(tokenType string) int {
+			for i := range config.Mappings {
+				if config.Mappings[i].TokenType == tokenType {
+					return i
+				}
+			}
+			return -1
+		}
+		switch n.Cmd {
+		case tree.AlterTextSearchConfigAddMapping:
+			for _, t := range tokenTypes {
+				if findMapping(t) >= 0 {
+					return false, pgerror.Newf(pgcode.DuplicateObject,
+						"mapping for token type %q already exists", t)
+				}
+				config.Mappings = append(config.Mappings, descpb.TextSearchConfigurationDescriptor_Mapping{
+					TokenType: t, Dictionaries: append([]string(nil), dicts...),
+				})
+			}
+			sortTextSearchMappings(config)
+		case tree.AlterTextSearchConfigAlterMapping:
+			for _, t := range tokenTypes {
+				i := findMapping(t)
+				if i < 0 {
+					return false, errTextSearchMappingDoesNotExist(t)
+				}
+				config.Mappings[i].Dictionaries = append([]string(nil), dicts...)
+			}
+		case tree.AlterTextSearchConfigReplaceDictionary:
+			for i := range config.Mappings {
+				m := &config.Mappings[i]
+				if len(tokenTypes) > 0 && findString(tokenTypes, m.TokenType) < 0 {
+					continue
+				}
+				for j := range m.Dictionaries {
+					if m.Dictionaries[j] == oldDict {
+						m.Dictionaries[j] = newDict
+					}
+				}
+			}
+		case tree.AlterTextSearchConfigDropMapping:
+			for _, t := range tokenTypes {
+				i := findMapping(t)
+				if i < 0 {
+					if n.IfExists {
+						continue
+					}
+					return false, errTextSearchMappingDoesNotExist(t)
+				}
+				config.Mappings = append(config.Mappings[:i], config.Mappings[i+1:]...)
+			}
+		default:
+			return false, errors.AssertionFailedf("unknown command %d", n.Cmd)
+		}
+		return true, nil
+	}}, nil
+}
+
+// sortTextSearchMappings sorts the mappings of a configuration by token type.
+func sortTextSearchMappings(config *descpb.TextSearchConfigurationDescriptor) {
+	sort.SliceStable(config.Mappings, func(i, j int) bool {
+		// The token types were validated when the mappings were added.
+		a, _ := tsearch.TokenTypeFromName(config.Mappings[i].TokenType)
+		b, _ := tsearch.TokenTypeFromName(config.Mappings[j].TokenType)
+		return a < b
+	})
+}
+
+func findString(s []string, v string) int {
+	for i := range s {
+		if s[i] == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// DropTextSearchConfig drops text search configurations of the current
+// database.
+// Privileges: CREATE on the database.
+//
+//	notes: postgres requires ownership of the configuration.
+func (p *planner) DropTextSearchConfig(
+	ctx context.Context, n *tree.DropTextSearchConfig,
+) (planNode, error) {
+	dbDesc, err := p.mutableTextSearchDatabase(ctx, "DROP TEXT SEARCH CONFIGURATION")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(n.Names))
+	for _, name := range n.Names {
+		if tsearch.IsBuiltinConfig(string(name)) {
+			return nil, errBuiltinTextSearchObject("configuration", string(name))
+		}
+		if dbDesc.GetTextSearchConfiguration(string(name)) == nil {
+			if n.IfExists {
+				continue
+			}
+			return nil, errTextSearchConfigDoesNotExist(string(name))
+		}
+		names = append(names, string(name))
+	}
+	if err := p.checkTextSearchConfigDependents(ctx, dbDesc, names, n.DropBehavior); err != nil {
+		return nil, err
+	}
+	return &textSearchDDLNode{n: n, dbDesc: dbDesc, apply: func() (bool, error) {
+		var dropped bool
+		for _, name := range n.Names {
+			if dbDesc.GetTextSearchConfiguration(string(name)) != nil {
+				dbDesc.RemoveTextSearchConfiguration(string(name))
+				dropped = true
+			}
+		}
+		return dropped, nil
+	}}, nil
+}
+
+// checkTextSearchConfigDependents returns an error if a table or view uses one
+// of the given configurations of the given database. The dependents are found
+// with the back-references of the configurations, which are checked against
+// the expressions of the tables and views, since they may be stale.
+func (p *planner) checkTextSearchConfigDependents(
+	ctx context.Context,
+	dbDesc catalog.DatabaseDescriptor,
+	names []string,
+	behavior tree.DropBehavior,
+) error {
+	for _, name := range names {
+		config := dbDesc.GetTextSearchConfiguration(name)
+		if config == nil {
+			continue
+		}
+		for _, id := range config.DependedOnBy {
+			desc, err := p.Descriptors().ByID(p.txn).Get().Desc(ctx, id)
+			if err != nil {
+				if errors.Is(err, catalog.ErrDescriptorNotFound) {
+					continue
+				}
+				return err
+			}
+			tableDesc, ok := desc.(catalog.TableDescriptor)
+			if !ok || tableDesc.Dropped() {
+				continue
+			}
+			used, err := textSearchConfigsUsedByTable(tableDesc)
+			if err != nil {
+				return err
+			}
+			if _, ok := used[name]; !ok {
+				continue
+			}
+			kind := "table"
+			if tableDesc.IsView() {
+				kind = "view"
+			}
+			if behavior == tree.DropCascade {
+				return unimplemented.Newf("drop text search configuration cascade",
+					"cannot drop text search configuration %q with CASCADE because %s %q uses it",
+					name, kind, tableDesc.GetName())
+			}
+			return errors.WithHint(
+				pgerror.Newf(pgcode.DependentObjectsStillExist,
+					"cannot drop text search configuration %q because %s %q uses it",
+					name, kind, tableDesc.GetName()),
+				"Drop or alter the dependent objects first.",
+			)
+		}
+	}
+	return nil
+}
+
+// updateTextSearchConfigReferences replaces the names of the text search
+// configurations of the database of the given table or view used by its
+// expressions or query with references returned by dbdesc.TextSearchConfigRef,
+// so that they are resolved in the same database in all sessions, and updates
+// the back-references of the configurations to the table or view. A
+// configuration is used if it is the constant config argument of a text search
+// builtin in a computed column, default or on update expression, a partial
+// index predicate or a check constraint, or in the view query. Expression
+// indexes are covered by their inaccessible computed columns.
+func (p *planner) updateTextSearchConfigReferences(
+	ctx context.Context, desc *tabledesc.Mutable,
+) error {
+	db, err := p.Descriptors().ByIDWithLeased(p.txn).WithoutNonPublic().Get().Database(ctx, desc.GetParentID())
+	if err != nil {
+		return err
+	}
+	if err := replaceTextSearchConfigsInTable(desc, func(name string) (string, error) {
+		if dbID, _, ok := dbdesc.ParseTextSearchConfigRef(name); ok {
+			if dbID != db.GetID() {
+				return "", pgerror.Newf(pgcode.FeatureNotSupported,
+					"cross database text search configuration references are not supported: %s", name)
+			}
+			return name, nil
+		}
+		// Names that are not configurations of the database are left alone:
+		// they are either built-in configurations or not configurations at
+		// all, like the document passed to an overload of ts_headline.
+		if db.GetTextSearchConfiguration(name) == nil {
+			return name, nil
+		}
+		return dbdesc.TextSearchConfigRef(db.GetID(), name), nil
+	}); err != nil {
+		return err
+	}
+	used, err := textSearchConfigsUsedByTable(desc)
+	if err != nil {
+		return err
+	}
+	if !textSearchConfigReferencesChanged(db, desc.GetID(), used) {
+		return nil
+	}
+	dbDesc, err := p.Descriptors().MutableByID(p.txn).Database(ctx, db.GetID())
+	if err != nil {
+		return err
+	}
+	for name := range used {
+		if dbDesc.GetTextSearchConfiguration(name) == nil {
+			return errTextSearchConfigDoesNotExist(name)
+		}
+		dbDesc.AddTextSearchConfigurationReference(name, desc.GetID())
+	}
+	for _, config := range dbDesc.GetTextSearchConfigurations() {
+		if _, ok := used[config.Name]; !ok {
+			dbDesc.RemoveTextSearchConfigurationReference(config.Name, desc.GetID())
+		}
+	}
+	return p.writeNonDropDatabaseChange(ctx, dbDesc,
+		fmt.Sprintf("updating text search configuration references of %q", desc.GetName()))
+}
+
+// textSearchConfigReferencesChanged returns true if the back-references of the
+// text search configurations of the given database to the table or view with
+// the given ID differ from the given used configurations.
+func textSearchConfigReferencesChanged(
+	db catalog.DatabaseDescriptor, id descpb.ID, used map[string]struct{},
+) bool {
+	var n int
+	for _, config := range db.GetTextSearchConfigurations() {
+		for _, ref := range config.DependedOnBy {
+			if ref != id {
+				continue
+			}
+			if _, ok := used[config.Name]; !ok {
+				return true
+			}
+			n++
+		}
+	}
+	return n != len(used)
+}
+
+// textSearchConfigsUsedByTable returns the names of the text search
+// configurations of the database of the given table or view that are
+// referenced by its expressions or query.
+func textSearchConfigsUsedByTable(desc catalog.TableDescriptor) (map[string]struct{}, error) {
+	used := make(map[string]struct{})
+	visit := func(ref string) (string, error) {
+		if dbID, name, ok := dbdesc.ParseTextSearchConfigRef(ref); ok && dbID == desc.GetParentID() {
+			used[name] = struct{}{}
+		}
+		return ref, nil
+	}
+	if desc.IsView() {
+		stmts, err := parser.Parse(desc.GetViewQuery())
+		if err != nil {
+			return nil, err
+		}
+		for _, stmt := range stmts {
+			if _, err := schemaexpr.ReplaceTextSearchConfigsInStmt(stmt.AST, visit); err != nil {
+				return nil, err
+			}
+		}
+		return used, nil
+	}
+	var exprs []string
+	for _, col := range desc.AllColumns() {
+		if col.IsComputed() {
+			exprs = append(exprs, col.GetComputeExpr())
+		}
+		if col.HasDefault() {
+			exprs = append(exprs, col.GetDefaultExpr())
+		}
+		if col.HasOnUpdate() {
+			exprs = append(exprs, col.GetOnUpdateExpr())
+		}
+	}
+	for _, idx := range desc.AllIndexes() {
+		if idx.IsPartial() {
+			exprs = append(exprs, idx.GetPredicate())
+		}
+	}
+	for _, c := range desc.CheckConstraints() {
+		exprs = append(exprs, c.GetExpr())
+	}
+	for _, s := range exprs {
+		expr, err := parser.ParseExpr(s)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := schemaexpr.ReplaceTextSearchConfigs(expr, visit); err != nil {
+			return nil, err
+		}
+	}
+	return used, nil
+}
+
+// replaceTextSearchConfigsInTable replaces the text search configurations used
+// by the expressions or the query of the given table or view, as described by
+// schemaexpr.ReplaceTextSearchConfigs.
+func replaceTextSearchConfigsInTable(
+	desc *tabledesc.Mutable, replace func(config string) (string, error),
+) error {
+	if desc.IsView() {
+		stmt, err := parser.ParseOne(desc.GetViewQuery())
+		if err != nil {
+			return err
+		}
+		newStmt, err := schemaexpr.ReplaceTextSearchConfigsInStmt(stmt.AST, replace)
+		if err != nil {
+			return err
+		}
+		if newStmt != stmt.AST {
+			desc.ViewQuery = tree.AsString(newStmt)
+		}
+		return nil
+	}
+	var exprs []*string
+	for _, col := range desc.AllColumns() {
+		d := col.ColumnDesc()
+		exprs = append(exprs, d.ComputeExpr, d.DefaultExpr, d.OnUpdateExpr)
+	}
+	for _, idx := range desc.AllIndexes() {
+		if idx.IsPartial() {
+			exprs = append(exprs, &idx.IndexDesc().Predicate)
+		}
+	}
+	for _, c := range desc.CheckConstraints() {
+		exprs = append(exprs, &c.CheckDesc().Expr)
+	}
+	for _, s := range exprs {
+		if s == nil {
+			continue
+		}
+		expr, err := parser.ParseExpr(*s)
+		if err != nil {
+			return err
+		}
+		newExpr, err := schemaexpr.ReplaceTextSearchConfigs(expr, replace)
+		if err != nil {
+			return err
+		}
+		if newExpr != expr {
+			*s = tree.Serialize(newExpr)
+		}
+	}
+	return nil
+}
+
+// DropTextSearchDictionary drops text search dictionaries of the current
+// database. With CASCADE, the dictionaries are removed from the mappings of
+// the configurations that use them, and the mappings that are left without
+// dictionaries are dropped.
+// Privileges: CREATE on the database.
+//
+//	notes: postgres requires ownership of the dictionary, and drops the
+//	       mappings that use the dictionary with CASCADE.
+func (p *planner) DropTextSearchDictionary(
+	ctx context.Context, n *tree.DropTextSearchDictionary,
+) (planNode, error) {
+	dbDesc, err := p.mutableTextSearchDatabase(ctx, "DROP TEXT SEARCH DICTIONARY")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range n.Names {
+		if tsearch.IsBuiltinDictionary(string(name)) {
+			return nil, errBuiltinTextSearchObject("dictionary", string(name))
+		}
+		if dbDesc.GetTextSearchDictionary(string(name)) == nil {
+			if n.IfExists {
+				continue
+			}
+			return nil, errTextSearchDictionaryDoesNotExist(string(name))
+		}
+		if n.DropBehavior == tree.DropCascade {
+			continue
+		}
+		for _, config := range dbDesc.GetTextSearchConfigurations() {
+			for _, m := range config.Mappings {
+				if findString(m.Dictionaries, string(name)) >= 0 {
+					return nil, errors.WithHint(
+						pgerror.Newf(pgcode.DependentObjectsStillExist,
+							"cannot drop text search dictionary %q because text search configuration %q uses it",
+							name, config.Name),
+						"Use DROP ... CASCADE to drop the dependent objects too.",
+					)
+				}
+			}
+		}
+	}
+	return &textSearchDDLNode{n: n, dbDesc: dbDesc, apply: func() (bool, error) {
+		var dropped bool
+		for _, name := range n.Names {
+			if dbDesc.GetTextSearchDictionary(string(name)) == nil {
+				continue
+			}
+			dbDesc.RemoveTextSearchDictionary(string(name))
+			dropped = true
+			for i := range dbDesc.TextSearchConfigurations {
+				config := &dbDesc.TextSearchConfigurations[i]
+				mappings := config.Mappings[:0]
+				for _, m := range config.Mappings {
+					if j := findString(m.Dictionaries, string(name)); j >= 0 {
+						m.Dictionaries = append(m.Dictionaries[:j:j], m.Dictionaries[j+1:]...)
+					}
+					if len(m.Dictionaries) > 0 {
+						mappings = append(mappings, m)
+					}
+				}
+				config.Mappings = mappings
+			}
+		}
+		return dropped, nil
+	}}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because the text search DDL statements perform multiple KV
+// operations on descriptors and expect to see their own writes.
+func (n *textSearchDDLNode) ReadingOwnWrites() {}
+
+func (n *textSearchDDLNode) startExec(params runParams) error {
+	changed, err := n.apply()
+	if err != nil || !changed {
+		return err
+	}
+	if err := validateDescriptor(params.ctx, params.p, n.dbDesc); err != nil {
+		return err
+	}
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (n *textSearchDDLNode) Next(runParams) (bool, error) { return false, nil }
+func (n *textSearchDDLNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *textSearchDDLNode) Close(context.Context)        {}
+
+func errTextSearchConfigDoesNotExist(name string) error {
+	return pgerror.Newf(pgcode.UndefinedObject, "text search configuration %q does not exist", name)
+}
+
+func errTextSearchDictionaryDoesNotExist(name string) error {
+	return pgerror.Newf(pgcode.UndefinedObject, "text search dictionary %q does not exist", name)
+}
+
+func errTextSearchMappingDoesNotExist(tokenType string) error {
+	return pgerror.Newf(pgcode.UndefinedObject, "mapping for token type %q does not exist", tokenType)
+}
+
+func errBuiltinTextSearchObject(kind, name string) error {
+	return pgerror.Newf(pgcode.InsufficientPrivilege,
+		"cannot modify built-in text search %s %q", kind, name)
+}
+
+// textSearchResolver returns the resolver of the text search objects of the
+// current database, which is rebuilt if the database descriptor changed.
+// Descriptors are immutable, so the objects cached by the resolver are valid
+// as long as the same descriptor is returned by the descriptor collection.
+func (p *planner) textSearchResolver(ctx context.Context) (*dbdesc.TextSearchResolver, error) {
+	var db catalog.DatabaseDescriptor
+	if p.CurrentDatabase() != "" {
+		var err error
+		db, err = p.Descriptors().ByNameWithLeased(p.txn).Get().Database(ctx, p.CurrentDatabase())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if p.textSearchCache == nil || p.textSearchCache.Database() != db {
+		p.textSearchCache = dbdesc.NewTextSearchResolver(db, func(
+			ctx context.Context, id descpb.ID,
+		) (catalog.DatabaseDescriptor, error) {
+			return p.Descriptors().ByIDWithLeased(p.txn).WithoutNonPublic().Get().Database(ctx, id)
+		})
+	}
+	return p.textSearchCache, nil
+}
+
+// ResolveTextSearchConfig is part of the eval.Planner interface.
+func (p *planner) ResolveTextSearchConfig(
+	ctx context.Context, name string,
+) (*tsearch.Config, error) {
+	if tsearch.IsBuiltinConfig(name) {
+		return tsearch.GetBuiltinConfig(name)
+	}
+	r, err := p.textSearchResolver(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.Config(ctx, name)
+}
+
+// ResolveTextSearchDictionary is part of the eval.Planner interface.
+func (p *planner) ResolveTextSearchDictionary(
+	ctx context.Context, name string,
+) (*tsearch.Dictionary, error) {
+	if tsearch.IsBuiltinDictionary(name) {
+		return tsearch.GetBuiltinDictionary(name)
+	}
+	r, err := p.textSearchResolver(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.Dictionary(name)
+}
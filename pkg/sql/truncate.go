@@ -358,6 +358,8 @@ func checkTableForDisallowedMutationsWithTruncate(desc *tabledesc.Mutable) error
 				constraintType = descpb.ConstraintToUpdate_FOREIGN_KEY
 			} else if c.AsUniqueWithoutIndex() != nil {
 				constraintType = descpb.ConstraintToUpdate_UNIQUE_WITHOUT_INDEX
+			} else if c.AsExclusion() != nil {
+				constraintType = descpb.ConstraintToUpdate_EXCLUSION
 			} else {
 				return errors.AssertionFailedf("cannot perform TRUNCATE due to "+
 					"unknown constraint type %s on mutation %d in %v", c, i, desc)
@@ -98,6 +98,46 @@ func findTransitioningMembers(desc *typedesc.Mutable) ([][]byte, bool) {
 	return transitioningMembers, beingDropped
 }
 
+// findTransitioningDomainConstraints returns the names of the CHECK constraints
+// of a domain that are being added and of those that are being validated in
+// the current txn, and whether a NOT NULL constraint is being added, by
+// diffing the mutated type descriptor against the one read from the cluster.
+// The constraints of the domain that were already being validated before the
+// current txn are the responsibility of another job.
+func findTransitioningDomainConstraints(
+	desc *typedesc.Mutable,
+) (adding, validating []string, notNull bool) {
+	if desc.Domain == nil {
+		return nil, nil, false
+	}
+	var clusterDomain *descpb.TypeDescriptor_Domain
+	if !desc.IsNew() {
+		clusterDomain = desc.ClusterVersion.Domain
+	}
+	for _, c := range desc.Domain.CheckConstraints {
+		if c.Validity != descpb.ConstraintValidity_Validating {
+			continue
+		}
+		found := false
+		if clusterDomain != nil {
+			for _, clusterCheck := range clusterDomain.CheckConstraints {
+				if clusterCheck.Name == c.Name {
+					found = true
+					if clusterCheck.Validity == descpb.ConstraintValidity_Unvalidated {
+						validating = append(validating, c.Name)
+					}
+					break
+				}
+			}
+		}
+		if !found {
+			adding = append(adding, c.Name)
+		}
+	}
+	notNull = desc.Domain.ValidatingNotNull && (clusterDomain == nil || !clusterDomain.ValidatingNotNull)
+	return adding, validating, notNull
+}
+
 // writeTypeSchemaChange should be called on a mutated type descriptor to ensure that
 // the descriptor gets written to a batch, as well as ensuring that a job is
 // created to perform the schema change on the type.
@@ -107,13 +147,20 @@ func (p *planner) writeTypeSchemaChange(
 	// Check if there is a cached specification for this type, otherwise create one.
 	record, recordExists := p.extendedEvalCtx.jobs.uniqueToCreate[typeDesc.ID]
 	transitioningMembers, beingDropped := findTransitioningMembers(typeDesc)
+	addingChecks, validatingChecks, validatingNotNull := findTransitioningDomainConstraints(typeDesc)
+	// A failed validation of the constraints of a domain must be rolled back,
+	// just like a failed drop of an enum member.
+	cancelable := beingDropped || len(addingChecks) > 0 || len(validatingChecks) > 0 || validatingNotNull
+	details := jobspb.TypeSchemaChangeDetails{
+		TypeID:                      typeDesc.ID,
+		TransitioningMembers:        transitioningMembers,
+		AddingDomainConstraints:     addingChecks,
+		ValidatingDomainConstraints: validatingChecks,
+		ValidatingDomainNotNull:     validatingNotNull,
+	}
 	if recordExists {
 		// Update it.
-		newDetails := jobspb.TypeSchemaChangeDetails{
-			TypeID:               typeDesc.ID,
-			TransitioningMembers: transitioningMembers,
-		}
-		record.Details = newDetails
+		record.Details = details
 		record.AppendDescription(jobDesc)
 		record.SetNonCancelable(ctx,
 			func(ctx context.Context, nonCancelable bool) bool {
@@ -124,8 +171,8 @@ func (p *planner) writeTypeSchemaChange(
 					return nonCancelable
 				}
 				// Type change jobs are non-cancelable unless an enum member is being
-				// dropped.
-				return !beingDropped
+				// dropped or the constraints of a domain are being validated.
+				return !cancelable
 			})
 		log.Infof(ctx, "job %d: updated with type change for type %d", record.JobID, typeDesc.ID)
 	} else {
@@ -135,14 +182,12 @@ func (p *planner) writeTypeSchemaChange(
 			Description:   jobDesc,
 			Username:      p.User(),
 			DescriptorIDs: descpb.IDs{typeDesc.ID},
-			Details: jobspb.TypeSchemaChangeDetails{
-				TypeID:               typeDesc.ID,
-				TransitioningMembers: transitioningMembers,
-			},
-			Progress: jobspb.TypeSchemaChangeProgress{},
+			Details:       details,
+			Progress:      jobspb.TypeSchemaChangeProgress{},
 			// Type change jobs in general are not cancelable, unless they include
-			// a transition that drops an enum member.
-			NonCancelable: !beingDropped,
+			// a transition that drops an enum member or validates the constraints
+			// of a domain.
+			NonCancelable: !cancelable,
 		}
 		p.extendedEvalCtx.jobs.uniqueToCreate[typeDesc.ID] = &newRecord
 		log.Infof(ctx, "queued new type change job %d for type %d", newRecord.JobID, typeDesc.ID)
@@ -170,7 +215,28 @@ type typeSchemaChanger struct {
 	// for a typeSchemaChanger. This is used to group transitions together and
 	// ensure proper rollback semantics on job failure.
 	transitioningMembers [][]byte
-	execCfg              *ExecutorConfig
+	// addingDomainConstraints, validatingDomainConstraints and
+	// validatingDomainNotNull describe the constraints of a domain that need to
+	// be validated in the job created for a typeSchemaChanger, so that only
+	// those are rolled back on job failure. See jobspb.TypeSchemaChangeDetails.
+	addingDomainConstraints     []string
+	validatingDomainConstraints []string
+	validatingDomainNotNull     bool
+	execCfg                     *ExecutorConfig
+}
+
+// newTypeSchemaChanger returns a typeSchemaChanger for the given job details.
+func newTypeSchemaChanger(
+	details jobspb.TypeSchemaChangeDetails, execCfg *ExecutorConfig,
+) *typeSchemaChanger {
+	return &typeSchemaChanger{
+		typeID:                      details.TypeID,
+		transitioningMembers:        details.TransitioningMembers,
+		addingDomainConstraints:     details.AddingDomainConstraints,
+		validatingDomainConstraints: details.ValidatingDomainConstraints,
+		validatingDomainNotNull:     details.ValidatingDomainNotNull,
+		execCfg:                     execCfg,
+	}
 }
 
 // TypeSchemaChangerTestingKnobs contains testing knobs for the typeSchemaChanger.
@@ -266,6 +332,18 @@ func (t *typeSchemaChanger) exec(ctx context.Context) error {
 		return err
 	}
 
+	// Validate the existing values of a domain against the constraints that
+	// the current job is responsible for. Now that all the leases have been
+	// updated, every new value of the domain is checked against them.
+	if typeDesc.AsDomainTypeDescriptor() != nil && t.hasTransitioningDomainConstraints() {
+		if err := t.validateDomainConstraints(ctx); err != nil {
+			return err
+		}
+		if err := refreshTypeDescriptorLeases(ctx, leaseMgr, t.execCfg.DB, typeDesc); err != nil {
+			return err
+		}
+	}
+
 	// For all the read only members the current job is responsible for, either
 	// promote them to writeable or remove them from the descriptor entirely,
 	// as dictated by the direction.
@@ -520,6 +598,141 @@ func (t *typeSchemaChanger) isTransitioningInCurrentJob(
 	return false
 }
 
+// hasTransitioningDomainConstraints returns true if the current job is
+// responsible for validating constraints of a domain.
+func (t *typeSchemaChanger) hasTransitioningDomainConstraints() bool {
+	return len(t.addingDomainConstraints) > 0 || len(t.validatingDomainConstraints) > 0 ||
+		t.validatingDomainNotNull
+}
+
+// isTransitioningDomainConstraintInCurrentJob returns true if the given CHECK
+// constraint of a domain is being validated by the current job.
+func (t *typeSchemaChanger) isTransitioningDomainConstraintInCurrentJob(
+	check *descpb.TypeDescriptor_Domain_CheckConstraint,
+) bool {
+	if check.Validity != descpb.ConstraintValidity_Validating {
+		return false
+	}
+	for _, names := range [][]string{t.addingDomainConstraints, t.validatingDomainConstraints} {
+		for _, name := range names {
+			if check.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateDomainConstraints validates the existing values of a domain against
+// the constraints that the current job is responsible for, and then marks the
+// constraints as validated. The validation is done in a separate txn to the
+// one that mutates the descriptor, as it can take arbitrarily long.
+func (t *typeSchemaChanger) validateDomainConstraints(ctx context.Context) error {
+	var validated []descpb.TypeDescriptor_Domain_CheckConstraint
+	var validatedNotNull bool
+	validate := func(ctx context.Context, txn descs.Txn) error {
+		validated, validatedNotNull = nil, false
+		typeDesc, err := txn.Descriptors().ByID(txn.KV()).Get().Type(ctx, t.typeID)
+		if err != nil {
+			return err
+		}
+		domain := typeDesc.TypeDesc().Domain
+		for i := range domain.CheckConstraints {
+			check := &domain.CheckConstraints[i]
+			if !t.isTransitioningDomainConstraintInCurrentJob(check) {
+				continue
+			}
+			if err := validateDomainCheckConstraint(ctx, txn, typeDesc, check.Expr); err != nil {
+				return err
+			}
+			validated = append(validated, *check)
+		}
+		if t.validatingDomainNotNull && domain.ValidatingNotNull {
+			if err := validateDomainNotNull(ctx, txn, typeDesc); err != nil {
+				return err
+			}
+			validatedNotNull = true
+		}
+		return nil
+	}
+	if err := t.execCfg.InternalDB.DescsTxn(ctx, validate); err != nil {
+		return err
+	}
+	if len(validated) == 0 && !validatedNotNull {
+		return nil
+	}
+
+	promote := func(ctx context.Context, txn descs.Txn) error {
+		typeDesc, err := txn.Descriptors().MutableByID(txn.KV()).Type(ctx, t.typeID)
+		if err != nil {
+			return err
+		}
+		domain := typeDesc.Domain
+		for i := range domain.CheckConstraints {
+			check := &domain.CheckConstraints[i]
+			if !t.isTransitioningDomainConstraintInCurrentJob(check) {
+				continue
+			}
+			// Only promote the constraint if it hasn't been replaced since it was
+			// validated.
+			for j := range validated {
+				if validated[j].Name == check.Name && validated[j].Expr == check.Expr {
+					check.Validity = descpb.ConstraintValidity_Validated
+					break
+				}
+			}
+		}
+		if validatedNotNull && domain.ValidatingNotNull {
+			domain.NotNull, domain.ValidatingNotNull = true, false
+		}
+		return txn.Descriptors().WriteDesc(ctx, true /* kvTrace */, typeDesc, txn.KV())
+	}
+	return t.execCfg.InternalDB.DescsTxn(ctx, promote)
+}
+
+// cleanupDomainConstraints rolls back the constraints of a domain that the
+// current job failed to validate. The constraints that were being added are
+// dropped, and those that were being validated revert to NOT VALID.
+func (t *typeSchemaChanger) cleanupDomainConstraints(ctx context.Context) error {
+	if !t.hasTransitioningDomainConstraints() {
+		return nil
+	}
+	cleanup := func(ctx context.Context, txn descs.Txn) error {
+		typeDesc, err := txn.Descriptors().MutableByID(txn.KV()).Type(ctx, t.typeID)
+		if err != nil {
+			return err
+		}
+		domain := typeDesc.Domain
+		if domain == nil {
+			return nil
+		}
+		isAdding := func(name string) bool {
+			for _, adding := range t.addingDomainConstraints {
+				if name == adding {
+					return true
+				}
+			}
+			return false
+		}
+		checks := domain.CheckConstraints[:0]
+		for _, check := range domain.CheckConstraints {
+			if t.isTransitioningDomainConstraintInCurrentJob(&check) {
+				if isAdding(check.Name) {
+					continue
+				}
+				check.Validity = descpb.ConstraintValidity_Unvalidated
+			}
+			checks = append(checks, check)
+		}
+		domain.CheckConstraints = checks
+		if t.validatingDomainNotNull {
+			domain.ValidatingNotNull = false
+		}
+		return txn.Descriptors().WriteDesc(ctx, true /* kvTrace */, typeDesc, txn.KV())
+	}
+	return t.execCfg.InternalDB.DescsTxn(ctx, cleanup)
+}
+
 // applyFilterOnEnumMembers modifies the supplied typeDesc by removing all enum
 // members as dictated by shouldRemove.
 func applyFilterOnEnumMembers(
@@ -986,7 +1199,7 @@ func findUsagesOfEnumValueInPartitioning(
 		if !typT.UserDefined() {
 			continue
 		}
-		id := typedesc.UserDefinedTypeOIDToID(typT.Oid())
+		id := typedesc.GetUserDefinedTypeDescID(typT)
 		if id != typ.GetID() {
 			continue
 		}
@@ -1260,11 +1473,7 @@ func (t *typeChangeResumer) Resume(ctx context.Context, execCtx interface{}) err
 			return nil
 		}
 	}
-	tc := &typeSchemaChanger{
-		typeID:               t.job.Details().(jobspb.TypeSchemaChangeDetails).TypeID,
-		transitioningMembers: t.job.Details().(jobspb.TypeSchemaChangeDetails).TransitioningMembers,
-		execCfg:              p.ExecCfg(),
-	}
+	tc := newTypeSchemaChanger(t.job.Details().(jobspb.TypeSchemaChangeDetails), p.ExecCfg())
 	return tc.execWithRetry(ctx)
 }
 
@@ -1273,16 +1482,17 @@ func (t *typeChangeResumer) OnFailOrCancel(
 	ctx context.Context, execCtx interface{}, _ error,
 ) error {
 	// If the job failed, just try again to clean up any draining names.
-	tc := &typeSchemaChanger{
-		typeID:               t.job.Details().(jobspb.TypeSchemaChangeDetails).TypeID,
-		transitioningMembers: t.job.Details().(jobspb.TypeSchemaChangeDetails).TransitioningMembers,
-		execCfg:              execCtx.(JobExecContext).ExecCfg(),
-	}
+	tc := newTypeSchemaChanger(
+		t.job.Details().(jobspb.TypeSchemaChangeDetails), execCtx.(JobExecContext).ExecCfg(),
+	)
 
 	if rollbackErr := func() error {
 		if err := tc.cleanupEnumValues(ctx); err != nil {
 			return err
 		}
+		if err := tc.cleanupDomainConstraints(ctx); err != nil {
+			return err
+		}
 
 		if fn := tc.execCfg.TypeSchemaChangerTestingKnobs.RunAfterOnFailOrCancel; fn != nil {
 			return fn()
@@ -66,6 +66,7 @@ var OidToType = map[oid.Oid]*T{
 	oid.T_bytea:      Bytes,
 	oid.T_char:       QChar,
 	oid.T_date:       Date,
+	oid.T_daterange:  DateRange,
 	oid.T_float4:     Float4,
 	oid.T_float8:     Float,
 	oid.T_int2:       Int2,
@@ -73,6 +74,8 @@ var OidToType = map[oid.Oid]*T{
 	oid.T_int4:       Int4,
 	oid.T_int8:       Int,
 	oid.T_inet:       INet,
+	oid.T_int4range:  Int4Range,
+	oid.T_int8range:  Int8Range,
 	oid.T_interval:   Interval,
 	// NOTE(sql-exp): Uncomment the line below if we support the JSON type.
 	// This would potentially require us to convert the type descriptors of
@@ -81,6 +84,7 @@ var OidToType = map[oid.Oid]*T{
 	oid.T_jsonb:        Jsonb,
 	oid.T_name:         Name,
 	oid.T_numeric:      Decimal,
+	oid.T_numrange:     NumRange,
 	oid.T_oid:          Oid,
 	oid.T_oidvector:    OidVector,
 	oid.T_pg_lsn:       PGLSN,
@@ -97,6 +101,9 @@ var OidToType = map[oid.Oid]*T{
 	oid.T_timetz:       TimeTZ,
 	oid.T_timestamp:    Timestamp,
 	oid.T_timestamptz:  TimestampTZ,
+	oid.T_trigger:      Trigger,
+	oid.T_tsrange:      TSRange,
+	oid.T_tstzrange:    TSTZRange,
 	oid.T_tsquery:      TSQuery,
 	oid.T_tsvector:     TSVector,
 	oid.T_unknown:      Unknown,
@@ -108,6 +115,14 @@ var OidToType = map[oid.Oid]*T{
 	oidext.T_geometry:  Geometry,
 	oidext.T_geography: Geography,
 	oidext.T_box2d:     Box2D,
+	oidext.T_jsonpath:  Jsonpath,
+
+	oidext.T_int4multirange: Int4Multirange,
+	oidext.T_int8multirange: Int8Multirange,
+	oidext.T_nummultirange:  NumMultirange,
+	oidext.T_tsmultirange:   TSMultirange,
+	oidext.T_tstzmultirange: TSTZMultirange,
+	oidext.T_datemultirange: DateMultirange,
 }
 
 // oidToArrayOid maps scalar type Oids to their corresponding array type Oid.
@@ -155,6 +170,21 @@ var oidToArrayOid = map[oid.Oid]oid.Oid{
 	oidext.T_geometry:  oidext.T__geometry,
 	oidext.T_geography: oidext.T__geography,
 	oidext.T_box2d:     oidext.T__box2d,
+	oidext.T_jsonpath:  oidext.T__jsonpath,
+
+	oid.T_int4range: oid.T__int4range,
+	oid.T_int8range: oid.T__int8range,
+	oid.T_numrange:  oid.T__numrange,
+	oid.T_tsrange:   oid.T__tsrange,
+	oid.T_tstzrange: oid.T__tstzrange,
+	oid.T_daterange: oid.T__daterange,
+
+	oidext.T_int4multirange: oidext.T__int4multirange,
+	oidext.T_int8multirange: oidext.T__int8multirange,
+	oidext.T_nummultirange:  oidext.T__nummultirange,
+	oidext.T_tsmultirange:   oidext.T__tsmultirange,
+	oidext.T_tstzmultirange: oidext.T__tstzmultirange,
+	oidext.T_datemultirange: oidext.T__datemultirange,
 }
 
 // familyToOid maps each type family to a default OID value that is used when
@@ -191,6 +221,10 @@ var familyToOid = map[Family]oid.Oid{
 	GeometryFamily:  oidext.T_geometry,
 	GeographyFamily: oidext.T_geography,
 	Box2DFamily:     oidext.T_box2d,
+	JsonpathFamily:  oidext.T_jsonpath,
+
+	RangeFamily:      oid.T_int8range,
+	MultirangeFamily: oidext.T_int8multirange,
 }
 
 // ArrayOids is a set of all oids which correspond to an array type.
@@ -203,6 +203,27 @@ type UserDefinedTypeMetadata struct {
 	// for a table. Note: this can be deleted if we migrate implicit record types
 	// to ordinary persisted composite types.
 	ImplicitRecordType bool
+
+	// DomainData is non-nil iff the metadata is for a DOMAIN type.
+	DomainData *DomainMetadata
+}
+
+// DomainMetadata is metadata about a DOMAIN needed for evaluation.
+type DomainMetadata struct {
+	// NotNull is true if the domain does not allow NULL values.
+	NotNull bool
+	// CheckConstraints are the CHECK constraints of the domain. Values of the
+	// domain must satisfy all of them.
+	CheckConstraints []DomainCheckConstraint
+}
+
+// DomainCheckConstraint is a CHECK constraint of a DOMAIN.
+type DomainCheckConstraint struct {
+	// Name is the name of the constraint.
+	Name string
+	// Expr is the serialized expression of the constraint, in which the value
+	// being checked is referenced as VALUE.
+	Expr string
 }
 
 // EnumMetadata is metadata about an ENUM needed for evaluation.
@@ -507,6 +528,65 @@ var (
 		},
 	}
 
+	// Jsonpath is the jsonpath type, which represents an SQL/JSON path
+	// expression.
+	Jsonpath = &T{
+		InternalType: InternalType{
+			Family: JsonpathFamily,
+			Oid:    oidext.T_jsonpath,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// Int4Range is the type of a range of 32-bit integers.
+	Int4Range = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_int4range, Locale: &emptyLocale}}
+
+	// Int8Range is the type of a range of 64-bit integers.
+	Int8Range = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_int8range, Locale: &emptyLocale}}
+
+	// NumRange is the type of a range of decimals.
+	NumRange = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_numrange, Locale: &emptyLocale}}
+
+	// TSRange is the type of a range of timestamps without time zone.
+	TSRange = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_tsrange, Locale: &emptyLocale}}
+
+	// TSTZRange is the type of a range of timestamps with time zone.
+	TSTZRange = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_tstzrange, Locale: &emptyLocale}}
+
+	// DateRange is the type of a range of dates.
+	DateRange = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_daterange, Locale: &emptyLocale}}
+
+	// Int4Multirange is the type of a multirange of 32-bit integers.
+	Int4Multirange = &T{InternalType: InternalType{
+		Family: MultirangeFamily, Oid: oidext.T_int4multirange, Locale: &emptyLocale}}
+
+	// Int8Multirange is the type of a multirange of 64-bit integers.
+	Int8Multirange = &T{InternalType: InternalType{
+		Family: MultirangeFamily, Oid: oidext.T_int8multirange, Locale: &emptyLocale}}
+
+	// NumMultirange is the type of a multirange of decimals.
+	NumMultirange = &T{InternalType: InternalType{
+		Family: MultirangeFamily, Oid: oidext.T_nummultirange, Locale: &emptyLocale}}
+
+	// TSMultirange is the type of a multirange of timestamps without time
+	// zone.
+	TSMultirange = &T{InternalType: InternalType{
+		Family: MultirangeFamily, Oid: oidext.T_tsmultirange, Locale: &emptyLocale}}
+
+	// TSTZMultirange is the type of a multirange of timestamps with time zone.
+	TSTZMultirange = &T{InternalType: InternalType{
+		Family: MultirangeFamily, Oid: oidext.T_tstzmultirange, Locale: &emptyLocale}}
+
+	// DateMultirange is the type of a multirange of dates.
+	DateMultirange = &T{InternalType: InternalType{
+		Family: MultirangeFamily, Oid: oidext.T_datemultirange, Locale: &emptyLocale}}
+
 	// RefCursor is the type for a variable representing the name of a cursor in a
 	// PLpgSQL routine. The underlying value is a string.
 	RefCursor = &T{
@@ -517,6 +597,16 @@ var (
 		},
 	}
 
+	// Trigger is the pseudo-type that must be used as the return type of a
+	// function that is executed by a trigger. It is not a valid column type.
+	Trigger = &T{
+		InternalType: InternalType{
+			Family: TriggerFamily,
+			Oid:    oid.T_trigger,
+			Locale: &emptyLocale,
+		},
+	}
+
 	// Scalar contains all types that meet this criteria:
 	//
 	//   1. Scalar type (no ArrayFamily or TupleFamily types).
@@ -548,6 +638,16 @@ var (
 		VarBit,
 	}
 
+	// RangeTypes contains all of the concrete range types.
+	RangeTypes = []*T{
+		Int4Range,
+		Int8Range,
+		NumRange,
+		TSRange,
+		TSTZRange,
+		DateRange,
+	}
+
 	// Any is a special type used only during static analysis as a wildcard type
 	// that matches any other type, including scalar, array, and tuple types.
 	// Execution-time values should never have this type. As an example of its
@@ -562,6 +662,18 @@ var (
 	AnyArray = &T{InternalType: InternalType{
 		Family: ArrayFamily, ArrayContents: Any, Oid: oid.T_anyarray, Locale: &emptyLocale}}
 
+	// AnyRange is a special type used only during static analysis as a wildcard
+	// type that matches a range of any subtype. Execution-time values should
+	// never have this type.
+	AnyRange = &T{InternalType: InternalType{
+		Family: RangeFamily, Oid: oid.T_anyrange, Locale: &emptyLocale}}
+
+	// AnyMultirange is a special type used only during static analysis as a
+	// wildcard type that matches a multirange of any subtype. Execution-time
+	// values should never have this type.
+	AnyMultirange = &T{InternalType: InternalType{
+		Family: MultirangeFamily, Oid: oidext.T_anymultirange, Locale: &emptyLocale}}
+
 	// AnyEnum is a special type only used during static analysis as a wildcard
 	// type that matches an possible enum value. Execution-time values should
 	// never have this type.
@@ -1154,6 +1266,19 @@ func MakeEnum(typeOID, arrayTypeOID oid.Oid) *T {
 	}}
 }
 
+// MakeDomain constructs a new DOMAIN type with the given stable type ID over
+// the given base type. The domain has the same representation as its base
+// type, including its OID, so that values of the domain behave like values of
+// the base type everywhere but in casts. Note that it does not hydrate cached
+// fields on the type.
+func MakeDomain(typeOID oid.Oid, base *T) *T {
+	typ := &T{InternalType: base.InternalType}
+	typ.InternalType.UDTMetadata = &PersistentUserDefinedTypeMetadata{
+		DomainTypeOID: typeOID,
+	}
+	return typ
+}
+
 // MakeArray constructs a new instance of an ArrayFamily type with the given
 // element type (which may itself be an ArrayFamily type).
 func MakeArray(typ *T) *T {
@@ -1431,6 +1556,85 @@ func (t *T) TupleLabels() []string {
 	return t.InternalType.TupleLabels
 }
 
+// rangeSubtypes maps the OID of each range type to the type of its bounds.
+var rangeSubtypes = map[oid.Oid]*T{
+	oid.T_int4range:        Int4,
+	oid.T_int8range:        Int,
+	oid.T_numrange:         Decimal,
+	oid.T_tsrange:          Timestamp,
+	oid.T_tstzrange:        TimestampTZ,
+	oid.T_daterange:        Date,
+	oid.T_anyrange:         Any,
+	oidext.T_anymultirange: Any,
+}
+
+// rangeToMultirange maps the OID of each range type to the OID of the
+// corresponding multirange type.
+var rangeToMultirange = map[oid.Oid]oid.Oid{
+	oid.T_int4range: oidext.T_int4multirange,
+	oid.T_int8range: oidext.T_int8multirange,
+	oid.T_numrange:  oidext.T_nummultirange,
+	oid.T_tsrange:   oidext.T_tsmultirange,
+	oid.T_tstzrange: oidext.T_tstzmultirange,
+	oid.T_daterange: oidext.T_datemultirange,
+	oid.T_anyrange:  oidext.T_anymultirange,
+}
+
+// multirangeToRange is the inverse of rangeToMultirange.
+var multirangeToRange = func() map[oid.Oid]oid.Oid {
+	m := make(map[oid.Oid]oid.Oid, len(rangeToMultirange))
+	for r, mr := range rangeToMultirange {
+		m[mr] = r
+	}
+	return m
+}()
+
+// RangeContents returns the type of the bounds of a range type, or of the
+// bounds of the ranges of a multirange type. This is nil for types that are
+// not in the RangeFamily or MultirangeFamily.
+func (t *T) RangeContents() *T {
+	switch t.Family() {
+	case RangeFamily:
+		return rangeSubtypes[t.Oid()]
+	case MultirangeFamily:
+		return rangeSubtypes[multirangeToRange[t.Oid()]]
+	}
+	return nil
+}
+
+// MultirangeOf returns the multirange type whose ranges have the given range
+// type.
+func MultirangeOf(rangeTyp *T) *T {
+	return OidToType[rangeToMultirange[rangeTyp.Oid()]]
+}
+
+// MultirangeRangeType returns the type of the ranges in the given multirange
+// type.
+func MultirangeRangeType(multirangeTyp *T) *T {
+	return OidToType[multirangeToRange[multirangeTyp.Oid()]]
+}
+
+// RangeOf returns the range type with the given subtype, or nil if there is
+// no such range type.
+func RangeOf(subtype *T) *T {
+	switch subtype.Family() {
+	case IntFamily:
+		if subtype.Width() == 32 {
+			return Int4Range
+		}
+		return Int8Range
+	case DecimalFamily:
+		return NumRange
+	case TimestampFamily:
+		return TSRange
+	case TimestampTZFamily:
+		return TSTZRange
+	case DateFamily:
+		return DateRange
+	}
+	return nil
+}
+
 // UserDefinedArrayOID returns the OID of the array type that corresponds to
 // this user defined type. This function only can only be called on user
 // defined types and returns non-zero data only for user defined types that
@@ -1458,7 +1662,42 @@ func RemapUserDefinedTypeOIDs(t *T, newOID, newArrayOID oid.Oid) {
 
 // UserDefined returns whether or not t is a user defined type.
 func (t *T) UserDefined() bool {
-	return IsOIDUserDefinedType(t.Oid())
+	return IsOIDUserDefinedType(t.Oid()) || t.IsDomain()
+}
+
+// IsDomain returns whether or not t is a DOMAIN type.
+func (t *T) IsDomain() bool {
+	return t.InternalType.UDTMetadata != nil && t.InternalType.UDTMetadata.DomainTypeOID != 0
+}
+
+// DomainOID returns the OID of the DOMAIN type t, or 0 if t is not a domain.
+// Note that the Oid method of a domain returns the OID of its base type.
+func (t *T) DomainOID() oid.Oid {
+	if !t.IsDomain() {
+		return 0
+	}
+	return t.InternalType.UDTMetadata.DomainTypeOID
+}
+
+// UserDefinedTypeOID returns the OID of the user defined type t. It is the
+// same as Oid, except for domains, for which it returns the OID of the domain
+// rather than the OID of its base type.
+func (t *T) UserDefinedTypeOID() oid.Oid {
+	if t.IsDomain() {
+		return t.DomainOID()
+	}
+	return t.Oid()
+}
+
+// DomainBaseType returns the base type of the DOMAIN type t. It returns t
+// itself if t is not a domain.
+func (t *T) DomainBaseType() *T {
+	if !t.IsDomain() {
+		return t
+	}
+	base := &T{InternalType: t.InternalType}
+	base.InternalType.UDTMetadata = nil
+	return base
 }
 
 // IsOIDUserDefinedType returns whether or not o corresponds to a user
@@ -1485,14 +1724,18 @@ var familyNames = map[Family]redact.SafeString{
 	IntFamily:            "int",
 	IntervalFamily:       "interval",
 	JsonFamily:           "jsonb",
+	JsonpathFamily:       "jsonpath",
+	MultirangeFamily:     "multirange",
 	OidFamily:            "oid",
 	PGLSNFamily:          "pg_lsn",
+	RangeFamily:          "range",
 	RefCursorFamily:      "refcursor",
 	StringFamily:         "string",
 	TimeFamily:           "time",
 	TimestampFamily:      "timestamp",
 	TimestampTZFamily:    "timestamptz",
 	TimeTZFamily:         "timetz",
+	TriggerFamily:        "trigger",
 	TSQueryFamily:        "tsquery",
 	TSVectorFamily:       "tsvector",
 	TupleFamily:          "tuple",
@@ -1521,6 +1764,9 @@ func (f Family) Name() redact.SafeString {
 //
 // TODO(andyk): Should these be changed to be the same as SQLStandardName?
 func (t *T) Name() string {
+	if t.IsDomain() {
+		return t.domainName()
+	}
 	switch fam := t.Family(); fam {
 	case AnyFamily:
 		return "anyelement"
@@ -1584,6 +1830,9 @@ func (t *T) Name() string {
 	case TupleFamily:
 		return t.SQLStandardName()
 
+	case RangeFamily, MultirangeFamily:
+		return t.PGName()
+
 	case EnumFamily:
 		if t.Oid() == oid.T_anyenum {
 			return "anyenum"
@@ -1610,6 +1859,9 @@ func (t *T) Name() string {
 //	bytes        bytea
 //	int4[]       _int4
 func (t *T) PGName() string {
+	if t.IsDomain() {
+		return t.domainName()
+	}
 	name, ok := oidext.TypeName(t.Oid())
 	if ok {
 		return strings.ToLower(name)
@@ -1628,6 +1880,14 @@ func (t *T) PGName() string {
 	return "_unknown"
 }
 
+// domainName returns the name of the DOMAIN type t.
+func (t *T) domainName() string {
+	if t.TypeMeta.Name == nil {
+		return fmt.Sprintf("@%d", t.DomainOID())
+	}
+	return t.TypeMeta.Name.Basename()
+}
+
 // SQLStandardName returns the type's name as it is specified in the SQL
 // standard (or by Postgres for any non-standard types). This can be looked up
 // for any type in Postgres using a query similar to this:
@@ -1658,6 +1918,9 @@ func (t *T) TelemetryName() string {
 // This function is full of special cases. See backend/utils/adt/format_type.c
 // in Postgres.
 func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
+	if t.IsDomain() {
+		return t.domainName()
+	}
 	var buf strings.Builder
 	switch t.Family() {
 	case AnyFamily:
@@ -1827,6 +2090,12 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 		return "tsquery"
 	case TSVectorFamily:
 		return "tsvector"
+	case JsonpathFamily:
+		return "jsonpath"
+	case RangeFamily, MultirangeFamily:
+		return t.PGName()
+	case TriggerFamily:
+		return "trigger"
 	case TupleFamily:
 		if t.UserDefined() {
 			// If we have a user-defined tuple type, use its user-defined name.
@@ -1852,6 +2121,10 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 // This is different from SQLString() in that it must report SQL standard names
 // that are compatible with PostgreSQL client expectations.
 func (t *T) InformationSchemaName() string {
+	// As in Postgres, columns of a domain report the type of its base type.
+	if t.IsDomain() {
+		return t.DomainBaseType().InformationSchemaName()
+	}
 	// This is the same as SQLStandardName, except for the case of arrays.
 	if t.Family() == ArrayFamily {
 		return "ARRAY"
@@ -1867,6 +2140,12 @@ func (t *T) InformationSchemaName() string {
 // reproduce the type via parsing the string as a type. It is used in error
 // messages and also to produce the output of SHOW CREATE.
 func (t *T) SQLString() string {
+	if t.IsDomain() {
+		if t.TypeMeta.Name == nil {
+			return fmt.Sprintf("@%d", t.DomainOID())
+		}
+		return t.TypeMeta.Name.FQName()
+	}
 	switch t.Family() {
 	case BitFamily:
 		o := t.Oid()
@@ -1982,12 +2261,14 @@ func (t *T) SQLStringForError() redact.RedactableString {
 		// Show the redacted SQLString output with an un-redacted prefix to indicate
 		// that the type is user defined (and possibly enum or record).
 		prefix := "TYPE"
-		switch t.Family() {
-		case EnumFamily:
+		switch {
+		case t.IsDomain():
+			prefix = "DOMAIN"
+		case t.Family() == EnumFamily:
 			prefix = "ENUM"
-		case TupleFamily:
+		case t.Family() == TupleFamily:
 			prefix = "RECORD"
-		case ArrayFamily:
+		case t.Family() == ArrayFamily:
 			prefix = "ARRAY"
 		}
 		return redact.Sprintf("USER DEFINED %s: %s", redact.Safe(prefix), t.SQLString())
@@ -2002,7 +2283,8 @@ func (t *T) SQLStringForError() redact.RedactableString {
 		IntervalFamily, StringFamily, BytesFamily, TimestampTZFamily, CollatedStringFamily, OidFamily,
 		UnknownFamily, UuidFamily, INetFamily, TimeFamily, JsonFamily, TimeTZFamily, BitFamily,
 		GeometryFamily, GeographyFamily, Box2DFamily, VoidFamily, EncodedKeyFamily, TSQueryFamily,
-		TSVectorFamily, AnyFamily, PGLSNFamily, RefCursorFamily:
+		TSVectorFamily, AnyFamily, PGLSNFamily, RefCursorFamily, TriggerFamily, JsonpathFamily,
+		RangeFamily, MultirangeFamily:
 		// These types do not contain other types, and do not require redaction.
 		return redact.Sprint(redact.SafeString(t.SQLString()))
 	}
@@ -2068,6 +2350,16 @@ func (t *T) Equivalent(other *T) bool {
 			return false
 		}
 
+	case RangeFamily, MultirangeFamily:
+		// If one of the types is anyrange or anymultirange, then allow the
+		// comparison to go through -- these are used when matching overloads.
+		if IsWildcardRangeType(t) || IsWildcardRangeType(other) {
+			return true
+		}
+		if t.Oid() != other.Oid() {
+			return false
+		}
+
 	case EnumFamily:
 		// If one of the types is anyenum, then allow the comparison to
 		// go through -- anyenum is used when matching overloads.
@@ -2236,6 +2528,9 @@ func (t *InternalType) Identical(other *InternalType) bool {
 		if t.UDTMetadata.ArrayTypeOID != other.UDTMetadata.ArrayTypeOID {
 			return false
 		}
+		if t.UDTMetadata.DomainTypeOID != other.UDTMetadata.DomainTypeOID {
+			return false
+		}
 	} else if t.UDTMetadata != nil {
 		return false
 	} else if other.UDTMetadata != nil {
@@ -2755,6 +3050,10 @@ func IsValidArrayElementType(t *T) (valid bool, issueNum int) {
 		return false, 90886
 	case TSVectorFamily:
 		return false, 90886
+	case JsonpathFamily:
+		return false, 22513
+	case RangeFamily, MultirangeFamily:
+		return false, 27791
 	default:
 		return true, 0
 	}
@@ -2811,6 +3110,12 @@ func IsWildcardTupleType(t *T) bool {
 	return len(t.TupleContents()) == 1 && t.TupleContents()[0].Family() == AnyFamily
 }
 
+// IsWildcardRangeType returns true if this is the wildcard AnyRange or
+// AnyMultirange type.
+func IsWildcardRangeType(t *T) bool {
+	return t.Oid() == oid.T_anyrange || t.Oid() == oidext.T_anymultirange
+}
+
 // IsRecordType returns true if this is a RECORD type. This should only be used
 // when processing UDFs. A record differs from AnyTuple in that the tuple
 // contents may contain types other than Any.
@@ -2974,7 +3279,6 @@ var postgresPredefinedTypeIssues = map[string]int{
 	"box":           21286,
 	"cidr":          18846,
 	"circle":        21286,
-	"jsonpath":      22513,
 	"line":          21286,
 	"lseg":          21286,
 	"macaddr":       45813,
@@ -135,7 +135,16 @@ func (u *updateNode) startExec(params runParams) error {
 			colinfo.ColTypeInfoFromResCols(u.columns),
 		)
 	}
-	return u.run.tu.init(params.ctx, params.p.txn, params.EvalContext(), &params.EvalContext().Settings.SV)
+	if err := u.run.tu.init(params.ctx, params.p.txn, params.EvalContext(), &params.EvalContext().Settings.SV); err != nil {
+		return err
+	}
+	if rec, err := params.p.deferConstraintChecks(u.run.tu.tableDesc(), deferUpdateChecks); err != nil {
+		return err
+	} else if rec != nil {
+		u.run.tu.autoCommit = autoCommitDisabled
+		u.run.tu.deferredChecks = rec
+	}
+	return nil
 }
 
 // Next is required because batchedPlanNode inherits from planNode, but
@@ -16,6 +16,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 )
@@ -59,7 +60,17 @@ func (n *upsertNode) startExec(params runParams) error {
 	// cache traceKV during execution, to avoid re-evaluating it for every row.
 	n.run.traceKV = params.p.ExtendedEvalContext().Tracing.KVTracingEnabled()
 
-	return n.run.tw.init(params.ctx, params.p.txn, params.EvalContext(), &params.EvalContext().Settings.SV)
+	if err := n.run.tw.init(params.ctx, params.p.txn, params.EvalContext(), &params.EvalContext().Settings.SV); err != nil {
+		return err
+	}
+	// A MERGE statement may also delete rows.
+	if rec, err := params.p.deferConstraintChecks(n.run.tw.tableDesc(), deferInsertChecks|deferUpdateChecks|deferDeleteChecks); err != nil {
+		return err
+	} else if rec != nil {
+		n.run.tw.autoCommit = autoCommitDisabled
+		n.run.tw.deferredChecks = rec
+	}
+	return nil
 }
 
 // Next is required because batchedPlanNode inherits from planNode, but
@@ -138,8 +149,17 @@ func (n *upsertNode) BatchedNext(params runParams) (bool, error) {
 // processSourceRow processes one row from the source for upsertion.
 // The table writer is in charge of accumulating the result rows.
 func (n *upsertNode) processSourceRow(params runParams, rowVals tree.Datums) error {
-	if err := enforceLocalColumnConstraints(rowVals, n.run.insertCols); err != nil {
-		return err
+	// A MERGE statement only inserts the rows for which it chose the insert
+	// action, and it does not write any new values for deleted rows.
+	mergeAction := opt.MergeAction(0)
+	if n.run.tw.mergeActionOrdinal != -1 {
+		mergeAction = opt.MergeAction(tree.MustBeDInt(rowVals[n.run.tw.mergeActionOrdinal]))
+	}
+
+	if mergeAction == 0 || mergeAction == opt.MergeActionInsert {
+		if err := enforceLocalColumnConstraints(rowVals, n.run.insertCols); err != nil {
+			return err
+		}
 	}
 
 	// Create a set of partial index IDs to not add or remove entries from.
@@ -149,6 +169,9 @@ func (n *upsertNode) processSourceRow(params runParams, rowVals tree.Datums) err
 		if n.run.tw.canaryOrdinal != -1 {
 			offset++
 		}
+		if n.run.tw.mergeActionOrdinal != -1 {
+			offset++
+		}
 		partialIndexVals := rowVals[offset:]
 		partialIndexPutVals := partialIndexVals[:numPartialIndexes]
 		partialIndexDelVals := partialIndexVals[numPartialIndexes : numPartialIndexes*2]
@@ -170,11 +193,16 @@ func (n *upsertNode) processSourceRow(params runParams, rowVals tree.Datums) err
 		if n.run.tw.canaryOrdinal != -1 {
 			ord++
 		}
-		checkVals := rowVals[ord:]
-		if err := checkMutationInput(
-			params.ctx, &params.p.semaCtx, params.p.SessionData(), n.run.tw.tableDesc(), n.run.checkOrds, checkVals,
-		); err != nil {
-			return err
+		if n.run.tw.mergeActionOrdinal != -1 {
+			ord++
+		}
+		if mergeAction != opt.MergeActionDelete {
+			checkVals := rowVals[ord:]
+			if err := checkMutationInput(
+				params.ctx, &params.p.semaCtx, params.p.SessionData(), n.run.tw.tableDesc(), n.run.checkOrds, checkVals,
+			); err != nil {
+				return err
+			}
 		}
 		rowVals = rowVals[:ord]
 	}
@@ -1137,7 +1137,8 @@ CREATE TABLE pg_catalog.pg_ts_template (
 	tmpllexize REGPROC
 )`
 
-// PgCatalogPublicationRel is an empty table in the pg_catalog that is not implemented yet
+// PgCatalogPublicationRel describes the schema of the pg_catalog.pg_publication_rel table.
+// https://www.postgresql.org/docs/16/catalog-pg-publication-rel.html
 const PgCatalogPublicationRel = `
 CREATE TABLE pg_catalog.pg_publication_rel (
 	oid OID,
@@ -1502,7 +1503,8 @@ CREATE TABLE pg_catalog.pg_stat_progress_basebackup (
 	tablespaces_streamed INT
 )`
 
-// PgCatalogPolicy is an empty table in the pg_catalog that is not implemented yet
+// PgCatalogPolicy describes the schema of the pg_catalog.pg_policy table.
+// https://www.postgresql.org/docs/16/catalog-pg-policy.html
 const PgCatalogPolicy = `
 CREATE TABLE pg_catalog.pg_policy (
 	oid OID,
@@ -1557,7 +1559,8 @@ CREATE TABLE pg_catalog.pg_hba_file_rules (
 	error STRING
 )`
 
-// PgCatalogPublication is an empty table in the pg_catalog that is not implemented yet
+// PgCatalogPublication describes the schema of the pg_catalog.pg_publication table.
+// https://www.postgresql.org/docs/16/catalog-pg-publication.html
 const PgCatalogPublication = `
 CREATE TABLE pg_catalog.pg_publication (
 	oid OID,
@@ -1684,7 +1687,8 @@ CREATE TABLE pg_catalog.pg_stat_xact_user_tables (
 	n_tup_hot_upd INT
 )`
 
-// PgCatalogPublicationTables is an empty table in the pg_catalog that is not implemented yet
+// PgCatalogPublicationTables describes the schema of the pg_catalog.pg_publication_tables table.
+// https://www.postgresql.org/docs/16/view-pg-publication-tables.html
 const PgCatalogPublicationTables = `
 CREATE TABLE pg_catalog.pg_publication_tables (
 	pubname NAME,
@@ -1909,7 +1913,8 @@ CREATE TABLE pg_catalog.pg_stat_user_tables (
 	autoanalyze_count INT
 )`
 
-// PgCatalogSubscription is an empty table in the pg_catalog that is not implemented yet
+// PgCatalogSubscription describes the schema of the pg_catalog.pg_subscription table.
+// https://www.postgresql.org/docs/16/catalog-pg-subscription.html
 const PgCatalogSubscription = `
 CREATE TABLE pg_catalog.pg_subscription (
 	oid OID,
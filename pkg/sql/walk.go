@@ -221,7 +221,10 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 	case *createViewNode:
 	case *setVarNode:
 	case *setClusterSettingNode:
+	case *setConstraintsNode:
 	case *resetAllNode:
+	case *listenNode:
+	case *notifyNode:
 
 	case *delayedNode:
 		if n.plan != nil {
@@ -354,6 +357,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&alterFunctionSetOwnerNode{}):               "alter function owner",
 	reflect.TypeOf(&alterFunctionSetSchemaNode{}):              "alter function set schema",
 	reflect.TypeOf(&alterFunctionDepExtensionNode{}):           "alter function depends on extension",
+	reflect.TypeOf(&alterDomainNode{}):                         "alter domain",
 	reflect.TypeOf(&alterIndexNode{}):                          "alter index",
 	reflect.TypeOf(&alterIndexVisibleNode{}):                   "alter index visibility",
 	reflect.TypeOf(&alterSequenceNode{}):                       "alter sequence",
@@ -385,14 +389,18 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&completionsNode{}):                         "show completions",
 	reflect.TypeOf(&controlJobsNode{}):                         "control jobs",
 	reflect.TypeOf(&controlSchedulesNode{}):                    "control schedules",
+	reflect.TypeOf(&createAggregateNode{}):                     "create aggregate",
 	reflect.TypeOf(&createDatabaseNode{}):                      "create database",
 	reflect.TypeOf(&createExtensionNode{}):                     "create extension",
 	reflect.TypeOf(&createExternalConnectionNode{}):            "create external connection",
 	reflect.TypeOf(&createFunctionNode{}):                      "create function",
 	reflect.TypeOf(&createIndexNode{}):                         "create index",
+	reflect.TypeOf(&createPolicyNode{}):                        "create policy",
+	reflect.TypeOf(&createPublicationNode{}):                   "create publication",
 	reflect.TypeOf(&createSequenceNode{}):                      "create sequence",
 	reflect.TypeOf(&createSchemaNode{}):                        "create schema",
 	reflect.TypeOf(&createStatsNode{}):                         "create statistics",
+	reflect.TypeOf(&createSubscriptionNode{}):                  "create subscription",
 	reflect.TypeOf(&createTableNode{}):                         "create table",
 	reflect.TypeOf(&createTenantNode{}):                        "create tenant",
 	reflect.TypeOf(&createTypeNode{}):                          "create type",
@@ -407,8 +415,11 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&dropExternalConnectionNode{}):              "drop external connection",
 	reflect.TypeOf(&dropFunctionNode{}):                        "drop function",
 	reflect.TypeOf(&dropIndexNode{}):                           "drop index",
+	reflect.TypeOf(&dropPolicyNode{}):                          "drop policy",
+	reflect.TypeOf(&dropPublicationNode{}):                     "drop publication",
 	reflect.TypeOf(&dropSequenceNode{}):                        "drop sequence",
 	reflect.TypeOf(&dropSchemaNode{}):                          "drop schema",
+	reflect.TypeOf(&dropSubscriptionNode{}):                    "drop subscription",
 	reflect.TypeOf(&dropTableNode{}):                           "drop table",
 	reflect.TypeOf(&dropTenantNode{}):                          "drop tenant",
 	reflect.TypeOf(&dropTypeNode{}):                            "drop type",
@@ -431,8 +442,10 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&invertedJoinNode{}):                        "inverted join",
 	reflect.TypeOf(&joinNode{}):                                "join",
 	reflect.TypeOf(&limitNode{}):                               "limit",
+	reflect.TypeOf(&listenNode{}):                              "listen",
 	reflect.TypeOf(&lookupJoinNode{}):                          "lookup join",
 	reflect.TypeOf(&max1RowNode{}):                             "max1row",
+	reflect.TypeOf(&notifyNode{}):                              "notify",
 	reflect.TypeOf(&ordinalityNode{}):                          "ordinality",
 	reflect.TypeOf(&projectSetNode{}):                          "project set",
 	reflect.TypeOf(&reassignOwnedByNode{}):                     "reassign owned by",
@@ -459,6 +472,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&sequenceSelectNode{}):                      "sequence select",
 	reflect.TypeOf(&serializeNode{}):                           "run",
 	reflect.TypeOf(&setClusterSettingNode{}):                   "set cluster setting",
+	reflect.TypeOf(&setConstraintsNode{}):                      "set constraints",
 	reflect.TypeOf(&setSessionAuthorizationDefaultNode{}):      "set session authorization",
 	reflect.TypeOf(&setVarNode{}):                              "set",
 	reflect.TypeOf(&setZoneConfigNode{}):                       "configure zone",
@@ -469,6 +483,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&showVarNode{}):                             "show",
 	reflect.TypeOf(&sortNode{}):                                "sort",
 	reflect.TypeOf(&splitNode{}):                               "split",
+	reflect.TypeOf(&textSearchDDLNode{}):                       "text search ddl",
 	reflect.TypeOf(&topKNode{}):                                "top-k",
 	reflect.TypeOf(&unsplitNode{}):                             "unsplit",
 	reflect.TypeOf(&unsplitAllNode{}):                          "unsplit all",
@@ -486,4 +501,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&zigzagJoinNode{}):                          "zigzag join",
 	reflect.TypeOf(&schemaChangePlanNode{}):                    "schema change",
 	reflect.TypeOf(&identifySystemNode{}):                      "identify system",
+	reflect.TypeOf(&createReplicationSlotNode{}):               "create replication slot",
+	reflect.TypeOf(&dropReplicationSlotNode{}):                 "drop replication slot",
+	reflect.TypeOf(&readReplicationSlotNode{}):                 "read replication slot",
 }
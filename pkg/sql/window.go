@@ -76,6 +76,10 @@ type windowFuncHolder struct {
 	partitionIdxs  []int
 	columnOrdering colinfo.ColumnOrdering
 	frame          *tree.WindowFrame
+
+	// userDefined is set if the window function is a user-defined aggregate,
+	// which is evaluated by invoking its routines.
+	userDefined *tree.AggregateRoutines
 }
 
 // samePartition returns whether w and other have the same PARTITION BY clause.
@@ -1116,6 +1116,80 @@ func EncodeExistsInvertedIndexSpans(
 	), nil
 }
 
+// InvertedIndexPathStep is a step along a path through a JSON document. It
+// refers to an element of an array if Array is true, and to the value of the
+// object key Key otherwise.
+type InvertedIndexPathStep struct {
+	Key   string
+	Array bool
+}
+
+// EncodePathExistsInvertedIndexSpans takes in a key prefix and returns the
+// spans that must be scanned in the inverted index to find the JSON documents
+// that have any value at the end of the given path, which must end with an
+// object key.
+//
+// The input inKey is prefixed to the keys in all returned spans.
+func EncodePathExistsInvertedIndexSpans(
+	b []byte, path []InvertedIndexPathStep,
+) (invertedExpr inverted.Expression, err error) {
+	if len(path) == 0 || path[len(path)-1].Array {
+		return nil, errors.AssertionFailedf("path must end with an object key")
+	}
+	b = encoding.EncodeJSONAscending(b)
+	for _, step := range path[:len(path)-1] {
+		if step.Array {
+			b = encoding.EncodeArrayAscending(b)
+		} else {
+			b = encoding.EncodeJSONKeyStringAscending(b, step.Key, false /* end */)
+		}
+	}
+	// As in EncodeExistsInvertedIndexSpans, we encode the last key with
+	// end=true and limit the span to the keys prefixed by the key and a
+	// separator, so that the span includes both scalar and non-scalar values
+	// but not longer keys that begin with the last key.
+	key := encoding.EncodeJSONKeyStringAscending(b, path[len(path)-1].Key, true /* end */)
+	span := inverted.Span{
+		Start: key,
+		End:   keysbase.PrefixEnd(encoding.AddJSONPathSeparator(key[:len(key):len(key)])),
+	}
+	return inverted.ExprForSpan(span, true /* tight */), nil
+}
+
+// EncodePathValueInvertedIndexSpans takes in a key prefix and returns the
+// spans that must be scanned in the inverted index to find the JSON documents
+// that have the given scalar value at the end of the given path.
+//
+// The input inKey is prefixed to the keys in all returned spans.
+func EncodePathValueInvertedIndexSpans(
+	b []byte, path []InvertedIndexPathStep, val JSON,
+) (invertedExpr inverted.Expression, err error) {
+	if !val.isScalar() {
+		return nil, errors.AssertionFailedf("value must be a scalar")
+	}
+	// Build the document that consists of only the path and the value, which
+	// has exactly one inverted index key.
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i].Array {
+			builder := NewArrayBuilder(1)
+			builder.Add(val)
+			val = builder.Build()
+		} else {
+			builder := NewObjectBuilder(1)
+			builder.Add(path[i].Key, val)
+			val = builder.Build()
+		}
+	}
+	keys, err := EncodeInvertedIndexKeys(b, val)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) != 1 {
+		return nil, errors.AssertionFailedf("unexpectedly found %d inverted index keys for a single path", len(keys))
+	}
+	return inverted.ExprForSpan(inverted.MakeSingleValSpan(keys[0]), true /* tight */), nil
+}
+
 func (j jsonNull) encodeInvertedIndexKeys(b []byte) ([][]byte, error) {
 	b = encoding.AddJSONPathTerminator(b)
 	return [][]byte{encoding.EncodeNullAscending(b)}, nil
@@ -2038,6 +2038,67 @@ func TestEncodeExistsJSONInvertedIndexSpans(t *testing.T) {
 	}
 }
 
+func TestEncodePathJSONInvertedIndexSpans(t *testing.T) {
+	a := InvertedIndexPathStep{Key: "a"}
+	b := InvertedIndexPathStep{Key: "b"}
+	arr := InvertedIndexPathStep{Array: true}
+
+	testCases := []struct {
+		indexedValue string
+		path         []InvertedIndexPathStep
+		// value is the scalar value at the end of the path, or empty to test for
+		// any value.
+		value    string
+		expected bool
+	}{
+		{`{"a": 1}`, []InvertedIndexPathStep{a}, ``, true},
+		{`{"a": null}`, []InvertedIndexPathStep{a}, ``, true},
+		{`{"a": []}`, []InvertedIndexPathStep{a}, ``, true},
+		{`{"a": {}}`, []InvertedIndexPathStep{a}, ``, true},
+		{`{"a": {"b": [1, 2]}}`, []InvertedIndexPathStep{a}, ``, true},
+		{`{"ab": 1}`, []InvertedIndexPathStep{a}, ``, false},
+		{`{"b": {"a": 1}}`, []InvertedIndexPathStep{a}, ``, false},
+		{`["a"]`, []InvertedIndexPathStep{a}, ``, false},
+		{`{"a": {"b": 1}}`, []InvertedIndexPathStep{a, b}, ``, true},
+		{`{"a": {"b": {"c": 1}}}`, []InvertedIndexPathStep{a, b}, ``, true},
+		{`{"a": [{"b": 1}]}`, []InvertedIndexPathStep{a, b}, ``, false},
+		{`{"a": [{"b": 1}]}`, []InvertedIndexPathStep{a, arr, b}, ``, true},
+		{`[{"a": 1}, 2]`, []InvertedIndexPathStep{arr, a}, ``, true},
+		{`{"a": 1}`, []InvertedIndexPathStep{a}, `1`, true},
+		{`{"a": 1.0}`, []InvertedIndexPathStep{a}, `1`, true},
+		{`{"a": 2}`, []InvertedIndexPathStep{a}, `1`, false},
+		{`{"a": "1"}`, []InvertedIndexPathStep{a}, `1`, false},
+		{`{"a": [1]}`, []InvertedIndexPathStep{a}, `1`, false},
+		{`{"a": [1, 2]}`, []InvertedIndexPathStep{a, arr}, `1`, true},
+		{`{"a": {"b": null}}`, []InvertedIndexPathStep{a, b}, `null`, true},
+		{`[[true]]`, []InvertedIndexPathStep{arr, arr}, `true`, true},
+		{`"x"`, nil, `"x"`, true},
+	}
+
+	for _, c := range testCases {
+		keys, err := EncodeInvertedIndexKeys(nil, parseJSON(t, c.indexedValue))
+		require.NoError(t, err)
+
+		var invertedExpr inverted.Expression
+		if c.value == "" {
+			invertedExpr, err = EncodePathExistsInvertedIndexSpans(nil, c.path)
+		} else {
+			invertedExpr, err = EncodePathValueInvertedIndexSpans(nil, c.path, parseJSON(t, c.value))
+		}
+		require.NoError(t, err)
+
+		spanExpr, ok := invertedExpr.(*inverted.SpanExpression)
+		if !ok {
+			t.Fatalf("invertedExpr %v is not a SpanExpression", invertedExpr)
+		}
+		containsKeys, err := spanExpr.ContainsKeys(keys)
+		require.NoError(t, err)
+		if containsKeys != c.expected {
+			t.Errorf("%s at %v: expected %t, got %t", c.indexedValue, c.path, c.expected, containsKeys)
+		}
+	}
+}
+
 func TestNumInvertedIndexEntries(t *testing.T) {
 	testCases := []struct {
 		value    string
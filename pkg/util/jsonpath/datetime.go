@@ -0,0 +1,333 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+// datetimeKind is the type of a datetime item.
+type datetimeKind int
+
+const (
+	kindDate datetimeKind = iota
+	kindTime
+	kindTimeTZ
+	kindTimestamp
+	kindTimestampTZ
+)
+
+// String returns the name of the kind, as returned by .type().
+func (k datetimeKind) String() string {
+	switch k {
+	case kindDate:
+		return "date"
+	case kindTime:
+		return "time without time zone"
+	case kindTimeTZ:
+		return "time with time zone"
+	case kindTimestamp:
+		return "timestamp without time zone"
+	default:
+		return "timestamp with time zone"
+	}
+}
+
+// datetimeFormats lists the ISO 8601 formats that .datetime() recognizes, in
+// the order in which they are tried.
+var datetimeFormats = []struct {
+	kind   datetimeKind
+	layout string
+}{
+	{kindTimestampTZ, "2006-01-02T15:04:05.999999999Z07:00"},
+	{kindTimestampTZ, "2006-01-02 15:04:05.999999999Z07:00"},
+	{kindTimestampTZ, "2006-01-02T15:04:05.999999999Z07"},
+	{kindTimestampTZ, "2006-01-02 15:04:05.999999999Z07"},
+	{kindTimestamp, "2006-01-02T15:04:05.999999999"},
+	{kindTimestamp, "2006-01-02 15:04:05.999999999"},
+	{kindDate, "2006-01-02"},
+	{kindTimeTZ, "15:04:05.999999999Z07:00"},
+	{kindTimeTZ, "15:04:05.999999999Z07"},
+	{kindTime, "15:04:05.999999999"},
+}
+
+// datetime is a datetime item, produced by the .datetime() method.
+type datetime struct {
+	kind datetimeKind
+	t    time.Time
+}
+
+func parseDatetime(s string) (*datetime, error) {
+	trimmed := strings.TrimSpace(s)
+	for _, f := range datetimeFormats {
+		if t, err := time.Parse(f.layout, trimmed); err == nil {
+			return &datetime{kind: f.kind, t: t}, nil
+		}
+	}
+	return nil, suppressibleErrorf(pgcode.InvalidArgumentForSQLJSONDatetimeFunction,
+		"datetime format is not recognized: %q", s)
+}
+
+// datetimeTemplatePatterns lists the patterns that may be used in the template
+// of .datetime(template), longest first so that no pattern is mistaken for
+// another one which is its prefix. They have the same meaning as in the
+// templates of to_timestamp.
+var datetimeTemplatePatterns = []string{
+	"HH24", "HH12", "YYYY", "A.M.", "P.M.",
+	"TZH", "TZM", "FF1", "FF2", "FF3", "FF4", "FF5", "FF6",
+	"HH", "MM", "DD", "MI", "SS", "MS", "US", "AM", "PM",
+}
+
+// parseDatetimeTemplate parses s according to the template of
+// .datetime(template). Characters of the template which are not part of a
+// pattern, and text in double quotes, must match s exactly. The kind of the
+// result is determined by the patterns: it has a date if the template has a
+// year, month or day, a time if it has a time of day, and a time zone if it
+// has TZH or TZM.
+func parseDatetimeTemplate(s, template string) (*datetime, error) {
+	year, month, day := 0, 1, 1
+	var hour, minute, sec, nsec, tzHour, tzMinute int
+	var hasDate, hasTime, hasTZ, clock12, pm, tzNegative bool
+
+	pos := 0
+	// readInt reads an unsigned number of up to maxDigits digits for the
+	// given pattern, and returns it along with the number of digits read.
+	readInt := func(pattern string, maxDigits int) (int, int, error) {
+		start := pos
+		for pos < len(s) && pos-start < maxDigits && s[pos] >= '0' && s[pos] <= '9' {
+			pos++
+		}
+		if pos == start {
+			return 0, 0, suppressibleErrorf(pgcode.InvalidDatetimeFormat,
+				"invalid value %q for %q", s[start:], pattern)
+		}
+		v, err := strconv.Atoi(s[start:pos])
+		return v, pos - start, err
+	}
+
+	for i := 0; i < len(template); {
+		c := template[i]
+		if c == '"' {
+			// Quoted text is matched literally.
+			end := strings.IndexByte(template[i+1:], '"')
+			if end < 0 {
+				end = len(template) - i - 1
+			}
+			lit := template[i+1 : i+1+end]
+			if !strings.HasPrefix(s[pos:], lit) {
+				return nil, suppressibleErrorf(pgcode.InvalidDatetimeFormat,
+					"unmatched format character %q", lit)
+			}
+			pos += len(lit)
+			i += end + 2
+			continue
+		}
+		if !unicode.IsLetter(rune(c)) {
+			if pos >= len(s) || s[pos] != c {
+				return nil, suppressibleErrorf(pgcode.InvalidDatetimeFormat,
+					"unmatched format character %q", string(c))
+			}
+			pos++
+			i++
+			continue
+		}
+		var pattern string
+		for _, p := range datetimeTemplatePatterns {
+			if len(template)-i >= len(p) && strings.EqualFold(template[i:i+len(p)], p) {
+				pattern = p
+				break
+			}
+		}
+		if pattern == "" {
+			end := i
+			for end < len(template) && unicode.IsLetter(rune(template[end])) {
+				end++
+			}
+			return nil, unimplemented.NewWithIssuef(22513,
+				"datetime format pattern %q is not supported", template[i:end])
+		}
+		i += len(pattern)
+
+		var err error
+		switch pattern {
+		case "YYYY":
+			year, _, err = readInt(pattern, 4)
+			hasDate = true
+		case "MM":
+			month, _, err = readInt(pattern, 2)
+			hasDate = true
+		case "DD":
+			day, _, err = readInt(pattern, 2)
+			hasDate = true
+		case "HH24":
+			hour, _, err = readInt(pattern, 2)
+			hasTime = true
+		case "HH", "HH12":
+			hour, _, err = readInt(pattern, 2)
+			hasTime, clock12 = true, true
+		case "MI":
+			minute, _, err = readInt(pattern, 2)
+			hasTime = true
+		case "SS":
+			sec, _, err = readInt(pattern, 2)
+			hasTime = true
+		case "MS", "US", "FF1", "FF2", "FF3", "FF4", "FF5", "FF6":
+			// The digits are the fraction of a second, so that "SS.MS" reads
+			// "12.3" as 12.3 seconds rather than 12 seconds and 3 milliseconds.
+			var maxDigits int
+			switch pattern {
+			case "MS":
+				maxDigits = 3
+			case "US":
+				maxDigits = 6
+			default:
+				maxDigits = int(pattern[2] - '0')
+			}
+			var frac, n int
+			frac, n, err = readInt(pattern, maxDigits)
+			for ; n < 9; n++ {
+				frac *= 10
+			}
+			nsec = frac
+			hasTime = true
+		case "AM", "PM", "A.M.", "P.M.":
+			var matched bool
+			for _, m := range []string{"AM", "PM", "A.M.", "P.M."} {
+				if len(m) == len(pattern) && len(s)-pos >= len(m) && strings.EqualFold(s[pos:pos+len(m)], m) {
+					pm, matched = m[0] == 'P', true
+					break
+				}
+			}
+			if !matched {
+				return nil, suppressibleErrorf(pgcode.InvalidDatetimeFormat,
+					"invalid value %q for %q", s[pos:], pattern)
+			}
+			pos += len(pattern)
+			clock12 = true
+		case "TZH":
+			if pos < len(s) && (s[pos] == '+' || s[pos] == '-') {
+				tzNegative = s[pos] == '-'
+				pos++
+			}
+			tzHour, _, err = readInt(pattern, 2)
+			hasTZ = true
+		case "TZM":
+			tzMinute, _, err = readInt(pattern, 2)
+			hasTZ = true
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if pos < len(s) {
+		return nil, suppressibleErrorf(pgcode.InvalidDatetimeFormat,
+			"trailing characters remain in input string after datetime format")
+	}
+
+	if clock12 {
+		if hour < 1 || hour > 12 {
+			return nil, suppressibleErrorf(pgcode.InvalidDatetimeFormat,
+				"hour \"%d\" is invalid for the 12-hour clock", hour)
+		}
+		if pm && hour < 12 {
+			hour += 12
+		} else if !pm && hour == 12 {
+			hour = 0
+		}
+	}
+	loc := time.UTC
+	if hasTZ {
+		offset := tzHour*3600 + tzMinute*60
+		if tzNegative {
+			offset = -offset
+		}
+		loc = time.FixedZone("", offset)
+	}
+	t := time.Date(year, time.Month(month), day, hour, minute, sec, nsec, loc)
+	if month < 1 || month > 12 || day < 1 || t.Day() != day || hour > 23 ||
+		minute > 59 || sec > 59 || tzMinute > 59 || tzHour > 15 {
+		return nil, suppressibleErrorf(pgcode.DatetimeFieldOverflow,
+			"date/time field value out of range: %q", s)
+	}
+
+	var kind datetimeKind
+	switch {
+	case hasDate && (hasTime || hasTZ):
+		kind = kindTimestamp
+		if hasTZ {
+			kind = kindTimestampTZ
+		}
+	case hasDate:
+		kind = kindDate
+	case hasTZ:
+		kind = kindTimeTZ
+	default:
+		kind = kindTime
+	}
+	return &datetime{kind: kind, t: t}, nil
+}
+
+// String formats the datetime in the same way as Postgres formats datetime
+// values in JSON.
+func (d *datetime) String() string {
+	switch d.kind {
+	case kindDate:
+		return d.t.Format("2006-01-02")
+	case kindTime:
+		return d.t.Format("15:04:05.999999")
+	case kindTimeTZ:
+		return d.t.Format("15:04:05.999999-07:00")
+	case kindTimestamp:
+		return d.t.Format("2006-01-02T15:04:05.999999")
+	default:
+		return d.t.Format("2006-01-02T15:04:05.999999-07:00")
+	}
+}
+
+// compare compares two datetime items. Dates are comparable with timestamps,
+// and otherwise only items of the same kind are comparable, since comparing
+// values with and without time zones requires a session time zone. The second
+// return value is false if the items are incomparable.
+func (d *datetime) compare(other *datetime) (int, bool) {
+	isNaive := func(k datetimeKind) bool { return k == kindDate || k == kindTimestamp }
+	if d.kind != other.kind && !(isNaive(d.kind) && isNaive(other.kind)) {
+		return 0, false
+	}
+	l, r := d.t, other.t
+	if d.kind == kindTimeTZ {
+		// Times with time zones are ordered by their UTC time, then by their
+		// offsets.
+		if l.Equal(r) {
+			_, lOffset := l.Zone()
+			_, rOffset := r.Zone()
+			switch {
+			case lOffset < rOffset:
+				return 1, true
+			case lOffset > rOffset:
+				return -1, true
+			}
+			return 0, true
+		}
+	}
+	switch {
+	case l.Before(r):
+		return -1, true
+	case l.After(r):
+		return 1, true
+	}
+	return 0, true
+}
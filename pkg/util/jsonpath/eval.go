@@ -0,0 +1,1050 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/apd/v3"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/errors"
+)
+
+var (
+	// exactCtx is used for addition, subtraction and multiplication, which
+	// never lose precision.
+	exactCtx = &apd.Context{
+		Precision:   0,
+		Rounding:    apd.RoundHalfUp,
+		MaxExponent: 2000,
+		MinExponent: -2000,
+		Traps:       apd.DefaultTraps,
+	}
+	// divCtx is used for division and modulo, and matches the precision of
+	// the DECIMAL type.
+	divCtx = exactCtx.WithPrecision(20)
+	// truncCtx is used to truncate array subscripts towards zero.
+	truncCtx = func() *apd.Context {
+		ctx := *exactCtx
+		ctx.Rounding = apd.RoundDown
+		return &ctx
+	}()
+)
+
+// errSuppressible marks the errors that are suppressed when a path is
+// evaluated with silent set to true. These are the errors caused by the
+// structure or the values of the queried document, as opposed to errors in
+// the path or the variables themselves.
+var errSuppressible = errors.New("suppressible jsonpath error")
+
+func suppressibleErrorf(code pgcode.Code, format string, args ...interface{}) error {
+	return errors.Mark(pgerror.Newf(code, format, args...), errSuppressible)
+}
+
+// item is a single SQL/JSON item produced while evaluating a path. Items are
+// JSON values, except for the datetime items produced by .datetime(), which
+// have no JSON representation until they are returned.
+type item struct {
+	json json.JSON
+	dt   *datetime
+	// loc is the location of the item, from which .keyvalue() computes the id
+	// of an object. It is only tracked if the path uses .keyvalue(); see
+	// keyvalue.go.
+	loc *itemLoc
+}
+
+func jsonItem(j json.JSON) item {
+	return item{json: j}
+}
+
+func numberItem(d *apd.Decimal) item {
+	return item{json: json.FromDecimal(*d)}
+}
+
+func boolItem(b bool) item {
+	return item{json: json.FromBool(b)}
+}
+
+// toJSON converts the item to a JSON value. Datetime items are converted to
+// strings.
+func (it item) toJSON() json.JSON {
+	if it.dt != nil {
+		return json.FromString(it.dt.String())
+	}
+	return it.json
+}
+
+func (it item) isArray() bool {
+	return it.dt == nil && it.json.Type() == json.ArrayJSONType
+}
+
+func (it item) isObject() bool {
+	return it.dt == nil && it.json.Type() == json.ObjectJSONType
+}
+
+func (it item) asDecimal() (*apd.Decimal, bool) {
+	if it.dt != nil {
+		return nil, false
+	}
+	return it.json.AsDecimal()
+}
+
+func (it item) asString() (string, bool) {
+	if it.dt != nil || it.json.Type() != json.StringJSONType {
+		return "", false
+	}
+	s, err := it.json.AsText()
+	if err != nil || s == nil {
+		return "", false
+	}
+	return *s, true
+}
+
+// elems returns the elements of an array item.
+func (it item) elems() []item {
+	arr, _ := it.json.AsArray()
+	var offsets []int
+	if it.loc != nil {
+		offsets = jsonbElemOffsets(it.json, it.loc.offset)
+	}
+	res := make([]item, len(arr))
+	for i := range arr {
+		res[i] = jsonItem(arr[i])
+		if offsets != nil {
+			res[i].loc = it.childLoc(offsets[i])
+		}
+	}
+	return res
+}
+
+// member returns the value of the given key of an object item, if it has
+// one.
+func (it item) member(key string) (_ item, ok bool, _ error) {
+	v, err := it.json.FetchValKey(key)
+	if err != nil || v == nil {
+		return item{}, false, err
+	}
+	res := jsonItem(v)
+	if it.loc != nil {
+		res.loc = it.childLoc(jsonbMemberOffsets(it.json, it.loc.offset)[key])
+	}
+	return res, true, nil
+}
+
+// members returns the values of an object item.
+func (it item) members() ([]item, error) {
+	iter, err := it.json.ObjectIter()
+	if err != nil {
+		return nil, err
+	}
+	var offsets map[string]int
+	if it.loc != nil {
+		offsets = jsonbMemberOffsets(it.json, it.loc.offset)
+	}
+	var res []item
+	for iter.Next() {
+		child := jsonItem(iter.Value())
+		if offsets != nil {
+			child.loc = it.childLoc(offsets[iter.Key()])
+		}
+		res = append(res, child)
+	}
+	return res, nil
+}
+
+// typeName returns the name of the item's type, as returned by .type().
+func (it item) typeName() string {
+	if it.dt != nil {
+		return it.dt.kind.String()
+	}
+	switch it.json.Type() {
+	case json.NullJSONType:
+		return "null"
+	case json.TrueJSONType, json.FalseJSONType:
+		return "boolean"
+	case json.NumberJSONType:
+		return "number"
+	case json.StringJSONType:
+		return "string"
+	case json.ArrayJSONType:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// tribool is the result of a predicate, which may be unknown if evaluating it
+// raised an error.
+type tribool int
+
+const (
+	triFalse tribool = iota
+	triTrue
+	triUnknown
+)
+
+func makeTribool(b bool) tribool {
+	if b {
+		return triTrue
+	}
+	return triFalse
+}
+
+// evaluator evaluates a jsonpath expression against a JSON document.
+type evaluator struct {
+	root   json.JSON
+	vars   json.JSON
+	strict bool
+	// current is the value of @, set while evaluating a filter predicate.
+	current item
+	// last is the value of the last keyword, set while evaluating an array
+	// subscript. It is -1 outside of subscripts.
+	last int
+	// trackLocs is true if the path uses .keyvalue(), in which case the
+	// locations of items are tracked.
+	trackLocs bool
+	// lastGeneratedID is the id of the base object most recently generated by
+	// .keyvalue().
+	lastGeneratedID int64
+}
+
+// EvalQuery evaluates the path against target and returns the resulting items.
+// The vars argument must be a JSON object whose fields supply the values of
+// the path's named variables, or nil. If silent is true, errors caused by the
+// contents of target are suppressed and no items are returned.
+func EvalQuery(j Jsonpath, target, vars json.JSON, silent bool) ([]json.JSON, error) {
+	items, err := eval(j, target, vars)
+	if err != nil {
+		if silent && errors.Is(err, errSuppressible) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	res := make([]json.JSON, len(items))
+	for i := range items {
+		res[i] = items[i].toJSON()
+	}
+	return res, nil
+}
+
+// EvalExists evaluates the path against target and returns whether it produced
+// any items. The result is NULL if evaluation raised an error that was
+// suppressed because silent was true.
+func EvalExists(j Jsonpath, target, vars json.JSON, silent bool) (exists bool, isNull bool, _ error) {
+	items, err := eval(j, target, vars)
+	if err != nil {
+		if silent && errors.Is(err, errSuppressible) {
+			return false, true, nil
+		}
+		return false, false, err
+	}
+	return len(items) > 0, false, nil
+}
+
+// EvalMatch evaluates a predicate check path against target and returns its
+// result. The result is NULL if the predicate is unknown, or if evaluation
+// raised an error that was suppressed because silent was true.
+func EvalMatch(j Jsonpath, target, vars json.JSON, silent bool) (match bool, isNull bool, _ error) {
+	items, err := eval(j, target, vars)
+	if err == nil && len(items) == 1 {
+		switch items[0].toJSON().Type() {
+		case json.TrueJSONType:
+			return true, false, nil
+		case json.FalseJSONType:
+			return false, false, nil
+		case json.NullJSONType:
+			return false, true, nil
+		}
+	}
+	if err == nil {
+		err = suppressibleErrorf(pgcode.SingletonSQLJSONItemRequired,
+			"single boolean result is expected")
+	}
+	if silent && errors.Is(err, errSuppressible) {
+		return false, true, nil
+	}
+	return false, false, err
+}
+
+func eval(j Jsonpath, target, vars json.JSON) ([]item, error) {
+	if vars != nil && vars.Type() != json.ObjectJSONType {
+		return nil, pgerror.New(pgcode.InvalidParameterValue,
+			`"vars" argument is not an object`)
+	}
+	e := evaluator{
+		root:      target,
+		vars:      vars,
+		strict:    j.Strict,
+		last:      -1,
+		trackLocs: usesKeyValue(j.Expr),
+	}
+	// Like Postgres, number the objects generated by .keyvalue() from 2, or
+	// from 3 if there are vars.
+	e.lastGeneratedID = 1
+	if vars != nil {
+		e.lastGeneratedID = 2
+	}
+	return e.eval(j.Expr)
+}
+
+func (e *evaluator) eval(expr Expr) ([]item, error) {
+	switch t := expr.(type) {
+	case Root:
+		it := jsonItem(e.root)
+		if e.trackLocs {
+			it.loc = &itemLoc{baseID: rootBaseID}
+		}
+		return []item{it}, nil
+
+	case Current:
+		return []item{e.current}, nil
+
+	case Last:
+		if e.last < 0 {
+			return nil, pgerror.New(pgcode.Syntax,
+				"evaluating jsonpath LAST outside of array subscript")
+		}
+		return []item{numberItem(apd.New(int64(e.last), 0))}, nil
+
+	case Variable:
+		var v item
+		var ok bool
+		if e.vars != nil {
+			vars := jsonItem(e.vars)
+			if e.trackLocs {
+				vars.loc = &itemLoc{baseID: varsBaseID}
+			}
+			var err error
+			if v, ok, err = vars.member(t.Name); err != nil {
+				return nil, err
+			}
+		}
+		if !ok {
+			return nil, pgerror.Newf(pgcode.UndefinedObject,
+				"could not find jsonpath variable %q", t.Name)
+		}
+		return []item{v}, nil
+
+	case Scalar:
+		return []item{jsonItem(t.Value)}, nil
+
+	case Accessor:
+		items, err := e.eval(t.Base)
+		if err != nil {
+			return nil, err
+		}
+		for _, step := range t.Chain {
+			if items, err = e.evalStep(step, items); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+
+	case Binary:
+		if t.Op.isArithmetic() {
+			return e.evalArithmetic(t)
+		}
+
+	case Unary:
+		if t.Op != OpNot {
+			return e.evalUnaryArithmetic(t)
+		}
+	}
+
+	// The expression is a predicate, whose result is returned as a boolean
+	// item, or null if it is unknown.
+	res, err := e.evalPredicate(expr)
+	if err != nil {
+		return nil, err
+	}
+	if res == triUnknown {
+		return []item{jsonItem(json.NullJSONValue)}, nil
+	}
+	return []item{boolItem(res == triTrue)}, nil
+}
+
+// evalUnwrapped evaluates the expression, and in lax mode replaces any array
+// items in the result with their elements.
+func (e *evaluator) evalUnwrapped(expr Expr) ([]item, error) {
+	items, err := e.eval(expr)
+	if err != nil || e.strict {
+		return items, err
+	}
+	return unwrap(items), nil
+}
+
+func unwrap(items []item) []item {
+	var res []item
+	for _, it := range items {
+		if it.isArray() {
+			res = append(res, it.elems()...)
+		} else {
+			res = append(res, it)
+		}
+	}
+	return res
+}
+
+func (e *evaluator) evalStep(step Step, items []item) ([]item, error) {
+	var res []item
+	for _, it := range items {
+		var err error
+		if res, err = e.evalStepOnItem(step, it, res, true /* autoUnwrap */); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// evalStepOnItem applies a single accessor to an item, appending the results
+// to res. If autoUnwrap is true and the path is in lax mode, accessors which
+// cannot be applied to arrays are applied to each element of an array item
+// instead.
+func (e *evaluator) evalStepOnItem(
+	step Step, it item, res []item, autoUnwrap bool,
+) ([]item, error) {
+	canUnwrap := autoUnwrap && !e.strict && it.isArray()
+	switch t := step.(type) {
+	case Member:
+		if it.isObject() {
+			v, ok, err := it.member(t.Key)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				return append(res, v), nil
+			}
+			if e.strict {
+				return nil, suppressibleErrorf(pgcode.SQLJSONMemberNotFound,
+					"JSON object does not contain key %q", t.Key)
+			}
+			return res, nil
+		}
+		if canUnwrap {
+			return e.evalStepOnElems(step, it, res)
+		}
+		if e.strict {
+			return nil, suppressibleErrorf(pgcode.SQLJSONMemberNotFound,
+				"jsonpath member accessor can only be applied to an object")
+		}
+		return res, nil
+
+	case AnyMember:
+		if it.isObject() {
+			members, err := it.members()
+			if err != nil {
+				return nil, err
+			}
+			return append(res, members...), nil
+		}
+		if canUnwrap {
+			return e.evalStepOnElems(step, it, res)
+		}
+		if e.strict {
+			return nil, suppressibleErrorf(pgcode.SQLJSONObjectNotFound,
+				"jsonpath wildcard member accessor can only be applied to an object")
+		}
+		return res, nil
+
+	case AnyArray:
+		if it.isArray() {
+			return append(res, it.elems()...), nil
+		}
+		if e.strict {
+			return nil, suppressibleErrorf(pgcode.SQLJSONArrayNotFound,
+				"jsonpath wildcard array accessor can only be applied to an array")
+		}
+		return append(res, it), nil
+
+	case ArrayIndex:
+		return e.evalArrayIndex(t, it, res)
+
+	case AnyRecursive:
+		return e.evalRecursive(t, it, 0 /* level */, res)
+
+	case Filter:
+		if canUnwrap {
+			return e.evalStepOnElems(step, it, res)
+		}
+		saved := e.current
+		e.current = it
+		pred, err := e.evalPredicate(t.Predicate)
+		e.current = saved
+		if err != nil {
+			return nil, err
+		}
+		if pred == triTrue {
+			res = append(res, it)
+		}
+		return res, nil
+
+	case Method:
+		if canUnwrap && t.Name != MethodType && t.Name != MethodSize {
+			return e.evalStepOnElems(step, it, res)
+		}
+		return e.evalMethod(t, it, res)
+	}
+	return nil, errors.AssertionFailedf("unhandled jsonpath accessor %T", step)
+}
+
+// evalStepOnElems applies an accessor to each element of an array item,
+// without unwrapping nested arrays.
+func (e *evaluator) evalStepOnElems(step Step, it item, res []item) ([]item, error) {
+	for _, elem := range it.elems() {
+		var err error
+		if res, err = e.evalStepOnItem(step, elem, res, false /* autoUnwrap */); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (e *evaluator) evalArrayIndex(t ArrayIndex, it item, res []item) ([]item, error) {
+	var elems []item
+	if it.isArray() {
+		elems = it.elems()
+	} else if e.strict {
+		return nil, suppressibleErrorf(pgcode.SQLJSONArrayNotFound,
+			"jsonpath array accessor can only be applied to an array")
+	} else {
+		elems = []item{it}
+	}
+
+	savedLast := e.last
+	defer func() { e.last = savedLast }()
+	e.last = len(elems) - 1
+	for _, sub := range t.Subscripts {
+		from, err := e.evalSubscript(sub.From)
+		if err != nil {
+			return nil, err
+		}
+		to := from
+		if sub.To != nil {
+			if to, err = e.evalSubscript(sub.To); err != nil {
+				return nil, err
+			}
+		}
+		if from < 0 || from > to || to >= len(elems) {
+			if e.strict {
+				return nil, suppressibleErrorf(pgcode.InvalidSQLJSONSubscript,
+					"jsonpath array subscript is out of bounds")
+			}
+			if from < 0 {
+				from = 0
+			}
+			if to >= len(elems) {
+				to = len(elems) - 1
+			}
+		}
+		for i := from; i <= to; i++ {
+			res = append(res, elems[i])
+		}
+	}
+	return res, nil
+}
+
+// evalSubscript evaluates an array subscript, which must be a single number.
+// The number is truncated to an integer.
+func (e *evaluator) evalSubscript(expr Expr) (int, error) {
+	items, err := e.evalUnwrapped(expr)
+	if err != nil {
+		return 0, err
+	}
+	var d *apd.Decimal
+	if len(items) == 1 {
+		d, _ = items[0].asDecimal()
+	}
+	if d == nil {
+		return 0, suppressibleErrorf(pgcode.InvalidSQLJSONSubscript,
+			"jsonpath array subscript is not a single numeric value")
+	}
+	var truncated apd.Decimal
+	if _, err := truncCtx.RoundToIntegralValue(&truncated, d); err != nil {
+		return 0, err
+	}
+	i, err := truncated.Int64()
+	if err != nil || i > math.MaxInt32 || i < math.MinInt32 {
+		return 0, suppressibleErrorf(pgcode.InvalidSQLJSONSubscript,
+			"jsonpath array subscript is out of integer range")
+	}
+	return int(i), nil
+}
+
+// evalRecursive implements the .** accessor, appending the item and its
+// descendants which are between the accessor's nesting levels to res.
+func (e *evaluator) evalRecursive(t AnyRecursive, it item, level int, res []item) ([]item, error) {
+	first, last := t.First, t.Last
+	if first == RecursiveLevelLast {
+		first = math.MaxInt32
+	}
+	if last == RecursiveLevelLast {
+		last = math.MaxInt32
+	}
+	if level >= first && level <= last {
+		res = append(res, it)
+	}
+	if level >= last || it.dt != nil {
+		return res, nil
+	}
+	var children []item
+	switch {
+	case it.isArray():
+		children = it.elems()
+	case it.isObject():
+		var err error
+		if children, err = it.members(); err != nil {
+			return nil, err
+		}
+	}
+	for _, child := range children {
+		var err error
+		if res, err = e.evalRecursive(t, child, level+1, res); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (e *evaluator) evalArithmetic(t Binary) ([]item, error) {
+	left, err := e.evalSingleNumber(t.Left, "left", t.Op)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.evalSingleNumber(t.Right, "right", t.Op)
+	if err != nil {
+		return nil, err
+	}
+	var res apd.Decimal
+	switch t.Op {
+	case OpAdd:
+		_, err = exactCtx.Add(&res, left, right)
+	case OpSub:
+		_, err = exactCtx.Sub(&res, left, right)
+	case OpMul:
+		_, err = exactCtx.Mul(&res, left, right)
+	case OpDiv, OpMod:
+		if right.IsZero() {
+			return nil, suppressibleErrorf(pgcode.DivisionByZero, "division by zero")
+		}
+		if t.Op == OpDiv {
+			_, err = divCtx.Quo(&res, left, right)
+		} else {
+			_, err = divCtx.Rem(&res, left, right)
+		}
+	}
+	if err != nil {
+		return nil, errors.Mark(
+			pgerror.Wrap(err, pgcode.NumericValueOutOfRange, "numeric value out of range"),
+			errSuppressible,
+		)
+	}
+	return []item{numberItem(&res)}, nil
+}
+
+// evalSingleNumber evaluates an operand of an arithmetic operator, which must
+// produce a single number.
+func (e *evaluator) evalSingleNumber(expr Expr, side string, op BinaryOperator) (*apd.Decimal, error) {
+	items, err := e.evalUnwrapped(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 1 {
+		if d, ok := items[0].asDecimal(); ok {
+			return d, nil
+		}
+	}
+	return nil, suppressibleErrorf(pgcode.SingletonSQLJSONItemRequired,
+		"%s operand of jsonpath operator %s is not a single numeric value", side, op)
+}
+
+func (e *evaluator) evalUnaryArithmetic(t Unary) ([]item, error) {
+	items, err := e.evalUnwrapped(t.Operand)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]item, 0, len(items))
+	for _, it := range items {
+		d, ok := it.asDecimal()
+		if !ok {
+			op := "+"
+			if t.Op == OpMinus {
+				op = "-"
+			}
+			return nil, suppressibleErrorf(pgcode.NonNumericSQLJSONItem,
+				"operand of unary jsonpath operator %s is not a numeric value", op)
+		}
+		if t.Op == OpMinus {
+			var neg apd.Decimal
+			neg.Neg(d)
+			d = &neg
+		}
+		res = append(res, numberItem(d))
+	}
+	return res, nil
+}
+
+// evalMethod applies an item method to an item, appending the results to res.
+func (e *evaluator) evalMethod(m Method, it item, res []item) ([]item, error) {
+	switch m.Name {
+	case MethodType:
+		return append(res, jsonItem(json.FromString(it.typeName()))), nil
+
+	case MethodSize:
+		if it.isArray() {
+			return append(res, numberItem(apd.New(int64(it.json.Len()), 0))), nil
+		}
+		if e.strict {
+			return nil, suppressibleErrorf(pgcode.SQLJSONArrayNotFound,
+				"jsonpath item method .size() can only be applied to an array")
+		}
+		return append(res, numberItem(apd.New(1, 0))), nil
+
+	case MethodDouble:
+		var f float64
+		if d, ok := it.asDecimal(); ok {
+			var err error
+			if f, err = d.Float64(); err != nil {
+				return nil, err
+			}
+		} else if s, ok := it.asString(); ok {
+			var err error
+			if f, err = strconv.ParseFloat(strings.TrimSpace(s), 64); err != nil {
+				return nil, suppressibleErrorf(pgcode.NonNumericSQLJSONItem,
+					"string argument of jsonpath item method .double() is not a "+
+						"valid representation of a double precision number")
+			}
+		} else {
+			return nil, suppressibleErrorf(pgcode.NonNumericSQLJSONItem,
+				"jsonpath item method .double() can only be applied to a string or numeric value")
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return nil, suppressibleErrorf(pgcode.NonNumericSQLJSONItem,
+				"numeric argument of jsonpath item method .double() is out of range "+
+					"for type double precision")
+		}
+		j, err := json.FromFloat64(f)
+		if err != nil {
+			return nil, err
+		}
+		return append(res, jsonItem(j)), nil
+
+	case MethodCeiling, MethodFloor, MethodAbs:
+		d, ok := it.asDecimal()
+		if !ok {
+			return nil, suppressibleErrorf(pgcode.NonNumericSQLJSONItem,
+				"jsonpath item method .%s() can only be applied to a numeric value", m.Name)
+		}
+		var out apd.Decimal
+		var err error
+		switch m.Name {
+		case MethodCeiling:
+			_, err = exactCtx.Ceil(&out, d)
+		case MethodFloor:
+			_, err = exactCtx.Floor(&out, d)
+		default:
+			out.Abs(d)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return append(res, numberItem(&out)), nil
+
+	case MethodKeyValue:
+		if !it.isObject() {
+			return nil, suppressibleErrorf(pgcode.SQLJSONObjectNotFound,
+				"jsonpath item method .keyvalue() can only be applied to an object")
+		}
+		iter, err := it.json.ObjectIter()
+		if err != nil {
+			return nil, err
+		}
+		id := json.FromInt64(it.keyValueID())
+		for iter.Next() {
+			ob := json.NewObjectBuilder(3)
+			ob.Add("id", id)
+			ob.Add("key", json.FromString(iter.Key()))
+			ob.Add("value", iter.Value())
+			pair := jsonItem(ob.Build())
+			if e.trackLocs {
+				// Each generated object is the base object of the items
+				// taken from it.
+				e.lastGeneratedID++
+				pair.loc = &itemLoc{baseID: e.lastGeneratedID}
+			}
+			res = append(res, pair)
+		}
+		return res, nil
+
+	case MethodDatetime:
+		s, ok := it.asString()
+		if !ok {
+			return nil, suppressibleErrorf(pgcode.InvalidArgumentForSQLJSONDatetimeFunction,
+				"jsonpath item method .datetime() can only be applied to a string")
+		}
+		var dt *datetime
+		var err error
+		if m.Arg != nil {
+			dt, err = parseDatetimeTemplate(s, *m.Arg)
+		} else {
+			dt, err = parseDatetime(s)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return append(res, item{dt: dt}), nil
+	}
+	return nil, errors.AssertionFailedf("unhandled jsonpath method %s", m.Name)
+}
+
+// evalPredicate evaluates a predicate expression. Errors raised while
+// evaluating the operands of a predicate make its result unknown rather than
+// being returned.
+func (e *evaluator) evalPredicate(expr Expr) (tribool, error) {
+	switch t := expr.(type) {
+	case Binary:
+		switch t.Op {
+		case OpAnd:
+			left, err := e.evalPredicate(t.Left)
+			if err != nil || left == triFalse {
+				return left, err
+			}
+			right, err := e.evalPredicate(t.Right)
+			if err != nil || right != triTrue {
+				return right, err
+			}
+			return left, nil
+		case OpOr:
+			left, err := e.evalPredicate(t.Left)
+			if err != nil || left == triTrue {
+				return left, err
+			}
+			right, err := e.evalPredicate(t.Right)
+			if err != nil || right != triFalse {
+				return right, err
+			}
+			return left, nil
+		case OpStartsWith:
+			return e.evalComparison(t.Left, t.Right, func(l, r item) tribool {
+				ls, ok := l.asString()
+				rs, ok2 := r.asString()
+				if !ok || !ok2 {
+					return triUnknown
+				}
+				return makeTribool(strings.HasPrefix(ls, rs))
+			})
+		default:
+			return e.evalComparison(t.Left, t.Right, func(l, r item) tribool {
+				return compareItems(t.Op, l, r)
+			})
+		}
+
+	case Unary:
+		operand, err := e.evalPredicate(t.Operand)
+		if err != nil {
+			return triUnknown, err
+		}
+		switch operand {
+		case triTrue:
+			return triFalse, nil
+		case triFalse:
+			return triTrue, nil
+		}
+		return triUnknown, nil
+
+	case IsUnknown:
+		res, err := e.evalPredicate(t.Predicate)
+		if err != nil {
+			return triUnknown, err
+		}
+		return makeTribool(res == triUnknown), nil
+
+	case Exists:
+		items, err := e.eval(t.Path)
+		if err != nil {
+			if errors.Is(err, errSuppressible) {
+				return triUnknown, nil
+			}
+			return triUnknown, err
+		}
+		return makeTribool(len(items) > 0), nil
+
+	case LikeRegex:
+		re, err := compileRegex(t.Pattern, t.Flags)
+		if err != nil {
+			return triUnknown, err
+		}
+		return e.evalComparison(t.Expr, nil /* right */, func(l, _ item) tribool {
+			s, ok := l.asString()
+			if !ok {
+				return triUnknown
+			}
+			return makeTribool(re.MatchString(s))
+		})
+	}
+	return triUnknown, errors.AssertionFailedf("%T is not a jsonpath predicate", expr)
+}
+
+// evalComparison evaluates a predicate over each pair of items produced by
+// the left and right expressions. If right is nil, the predicate is applied to
+// each item produced by left. In lax mode, the predicate is true if it is true
+// for any pair. In strict mode, it is unknown if it is unknown for any pair.
+func (e *evaluator) evalComparison(
+	left, right Expr, fn func(l, r item) tribool,
+) (tribool, error) {
+	lItems, err := e.evalUnwrapped(left)
+	if err != nil {
+		if errors.Is(err, errSuppressible) {
+			return triUnknown, nil
+		}
+		return triUnknown, err
+	}
+	rItems := []item{{}}
+	if right != nil {
+		if rItems, err = e.evalUnwrapped(right); err != nil {
+			if errors.Is(err, errSuppressible) {
+				return triUnknown, nil
+			}
+			return triUnknown, err
+		}
+	}
+	found, sawUnknown := false, false
+	for _, l := range lItems {
+		for _, r := range rItems {
+			switch fn(l, r) {
+			case triUnknown:
+				if e.strict {
+					return triUnknown, nil
+				}
+				sawUnknown = true
+			case triTrue:
+				if !e.strict {
+					return triTrue, nil
+				}
+				found = true
+			}
+		}
+	}
+	if found {
+		return triTrue, nil
+	}
+	if sawUnknown {
+		return triUnknown, nil
+	}
+	return triFalse, nil
+}
+
+// compareItems applies a comparison operator to two items. Items of different
+// types are incomparable, except that null is unequal to any other item.
+func compareItems(op BinaryOperator, l, r item) tribool {
+	var cmp int
+	switch {
+	case l.dt != nil || r.dt != nil:
+		if l.dt == nil || r.dt == nil {
+			return triUnknown
+		}
+		var ok bool
+		if cmp, ok = l.dt.compare(r.dt); !ok {
+			return triUnknown
+		}
+	case l.json.Type() == json.NullJSONType || r.json.Type() == json.NullJSONType:
+		bothNull := l.json.Type() == r.json.Type()
+		switch op {
+		case OpEqual:
+			return makeTribool(bothNull)
+		case OpNotEqual:
+			return makeTribool(!bothNull)
+		case OpLessEqual, OpGreaterEqual:
+			return makeTribool(bothNull)
+		}
+		return triFalse
+	case l.json.Type() == json.TrueJSONType || l.json.Type() == json.FalseJSONType:
+		if r.json.Type() != json.TrueJSONType && r.json.Type() != json.FalseJSONType {
+			return triUnknown
+		}
+		lb, _ := l.json.AsBool()
+		rb, _ := r.json.AsBool()
+		switch {
+		case lb == rb:
+			cmp = 0
+		case lb:
+			cmp = 1
+		default:
+			cmp = -1
+		}
+	case l.json.Type() == json.NumberJSONType:
+		ld, _ := l.json.AsDecimal()
+		rd, ok := r.json.AsDecimal()
+		if !ok {
+			return triUnknown
+		}
+		cmp = ld.Cmp(rd)
+	case l.json.Type() == json.StringJSONType:
+		ls, _ := l.asString()
+		rs, ok := r.asString()
+		if !ok {
+			return triUnknown
+		}
+		cmp = strings.Compare(ls, rs)
+	default:
+		// Arrays and objects cannot be compared.
+		return triUnknown
+	}
+	switch op {
+	case OpEqual:
+		return makeTribool(cmp == 0)
+	case OpNotEqual:
+		return makeTribool(cmp != 0)
+	case OpLess:
+		return makeTribool(cmp < 0)
+	case OpLessEqual:
+		return makeTribool(cmp <= 0)
+	case OpGreater:
+		return makeTribool(cmp > 0)
+	case OpGreaterEqual:
+		return makeTribool(cmp >= 0)
+	}
+	return triUnknown
+}
+
+// compileRegex compiles the pattern of a like_regex predicate. The supported
+// flags are i (case-insensitive), s (. matches newlines), m (^ and $ match at
+// newlines) and q (the pattern is a literal string).
+func compileRegex(pattern, flags string) (*regexp.Regexp, error) {
+	var goFlags strings.Builder
+	for _, f := range flags {
+		switch f {
+		case 'i', 's', 'm':
+			goFlags.WriteRune(f)
+		case 'q':
+			pattern = regexp.QuoteMeta(pattern)
+		case 'x':
+			return nil, unimplemented.NewWithIssue(22513,
+				`XQuery "x" flag (expanded regular expressions) is not implemented`)
+		default:
+			return nil, pgerror.Newf(pgcode.Syntax,
+				"invalid input syntax for type jsonpath: unrecognized flag character %q in LIKE_REGEX predicate", f)
+		}
+	}
+	if goFlags.Len() > 0 {
+		pattern = "(?" + goFlags.String() + ")" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, pgerror.Wrap(err, pgcode.InvalidRegularExpression, "invalid regular expression")
+	}
+	return re, nil
+}
@@ -0,0 +1,241 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalQuery(t *testing.T) {
+	for _, tc := range []struct {
+		target   string
+		path     string
+		vars     string
+		expected string
+		err      string
+	}{
+		// Accessors.
+		{target: `{"a": 1}`, path: `$`, expected: `{"a": 1}`},
+		{target: `{"a": 1}`, path: `$.a`, expected: `1`},
+		{target: `{"a": {"b": [1, 2]}}`, path: `$.a.b`, expected: `[1, 2]`},
+		{target: `{"a": [1, 2, 3]}`, path: `$.a[*]`, expected: `1 2 3`},
+		{target: `{"a": [1, 2, 3]}`, path: `$.a[1 to last]`, expected: `2 3`},
+		{target: `{"a": [1, 2, 3]}`, path: `$.a[last, 0]`, expected: `3 1`},
+		{target: `{"a": [1, 2, 3]}`, path: `$.a[1.9]`, expected: `2`},
+		{target: `{"a": [1, 2, 3]}`, path: `$.a[5]`, expected: ``},
+		{target: `{"a": [1, 2, 3]}`, path: `strict $.a[5]`,
+			err: `jsonpath array subscript is out of bounds`},
+		{target: `{"a": 1, "b": "x"}`, path: `$.*`, expected: `1 "x"`},
+		{target: `{"a": {"b": 1}}`, path: `$.**`, expected: `{"a": {"b": 1}} {"b": 1} 1`},
+		{target: `{"a": {"b": 1}}`, path: `$.**{2}`, expected: `1`},
+		{target: `{"a": {"b": 1}}`, path: `$.**{1 to last}`, expected: `{"b": 1} 1`},
+
+		// Lax and strict mode.
+		{target: `{"a": 1}`, path: `$.b`, expected: ``},
+		{target: `{"a": 1}`, path: `strict $.b`, err: `JSON object does not contain key "b"`},
+		{target: `[{"a": 1}, {"a": 2}]`, path: `$.a`, expected: `1 2`},
+		{target: `[{"a": 1}, {"a": 2}]`, path: `strict $.a`,
+			err: `jsonpath member accessor can only be applied to an object`},
+		{target: `[[{"a": 1}]]`, path: `$.a`, expected: ``},
+		{target: `{"a": 1}`, path: `$.a[0]`, expected: `1`},
+		{target: `{"a": 1}`, path: `strict $.a[0]`,
+			err: `jsonpath array accessor can only be applied to an array`},
+		{target: `{"a": 1}`, path: `$.a[*]`, expected: `1`},
+		{target: `1`, path: `$.*`, expected: ``},
+
+		// Filters.
+		{target: `{"a": [1, 2, 3]}`, path: `$.a[*] ? (@ > 1)`, expected: `2 3`},
+		{target: `{"a": [1, 2, 3]}`, path: `$.a ? (@ > 1)`, expected: `2 3`},
+		{target: `{"a": [1, 2, 3]}`, path: `strict $.a ? (@ > 1)`, expected: ``},
+		{target: `[{"a": 1, "b": "x"}, {"a": 2, "b": "y"}]`,
+			path: `$[*] ? (@.a >= 2 || @.b == "x").b`, expected: `"x" "y"`},
+		{target: `[{"a": 1, "b": "x"}, {"a": 2, "b": "y"}]`,
+			path: `$[*] ? (@.a >= 2 && @.b == "x")`, expected: ``},
+		{target: `[1, "a", null]`, path: `$[*] ? ((@ > 0) is unknown)`, expected: `"a"`},
+		{target: `[1, "a", null]`, path: `$[*] ? (@ == null)`, expected: `null`},
+		{target: `[1, "a", null]`, path: `$[*] ? (@ != null)`, expected: `1 "a"`},
+		{target: `[1, "a", null]`, path: `$[*] ? (!(@ == 1))`, expected: `null`},
+		{target: `[{"a": [1, 5]}, {"a": [2]}]`, path: `$[*] ? (@.a[*] > 4)`, expected: `{"a": [1, 5]}`},
+		{target: `[{"a": [1, 5]}, {"b": 1}]`, path: `$[*] ? (exists (@.a))`, expected: `{"a": [1, 5]}`},
+		{target: `["abc", "bcd", 1]`, path: `$[*] ? (@ starts with "b")`, expected: `"bcd"`},
+		{target: `["abc", "ABD", 1]`, path: `$[*] ? (@ like_regex "^ab")`, expected: `"abc"`},
+		{target: `["abc", "ABD", 1]`, path: `$[*] ? (@ like_regex "^ab" flag "i")`, expected: `"abc" "ABD"`},
+		{target: `["a.c", "abc"]`, path: `$[*] ? (@ like_regex "a.c" flag "q")`, expected: `"a.c"`},
+
+		// Arithmetic.
+		{target: `{"a": 2}`, path: `$.a * 3 + 1`, expected: `7`},
+		{target: `{"a": 2}`, path: `$.a / 4`, expected: `0.5`},
+		{target: `{"a": 7}`, path: `$.a % 4`, expected: `3`},
+		{target: `{"a": [1, 2]}`, path: `-$.a[*]`, expected: `-1 -2`},
+		{target: `{"a": [1, 2]}`, path: `$.a + 1`,
+			err: `left operand of jsonpath operator + is not a single numeric value`},
+		{target: `{"a": "x"}`, path: `1 - $.a`,
+			err: `right operand of jsonpath operator - is not a single numeric value`},
+		{target: `{}`, path: `1 / 0`, err: `division by zero`},
+		{target: `{"a": "x"}`, path: `-$.a`,
+			err: `operand of unary jsonpath operator - is not a numeric value`},
+
+		// Item methods.
+		{target: `{"a": 1, "b": "x", "c": null, "d": [], "e": {}, "f": true}`,
+			path: `$.*.type()`, expected: `"number" "string" "null" "array" "object" "boolean"`},
+		{target: `{"a": [1, 2]}`, path: `$.a.size()`, expected: `2`},
+		{target: `{"a": 1}`, path: `$.a.size()`, expected: `1`},
+		{target: `{"a": 1}`, path: `strict $.a.size()`,
+			err: `jsonpath item method .size() can only be applied to an array`},
+		{target: `{"a": -1.5}`, path: `$.a.abs()`, expected: `1.5`},
+		{target: `{"a": -1.5}`, path: `$.a.floor()`, expected: `-2`},
+		{target: `{"a": -1.5}`, path: `$.a.ceiling()`, expected: `-1`},
+		{target: `{"a": [1.5, 2.5]}`, path: `$.a.floor()`, expected: `1 2`},
+		{target: `{"a": "x"}`, path: `$.a.abs()`,
+			err: `jsonpath item method .abs() can only be applied to a numeric value`},
+		{target: `{"a": "1.5"}`, path: `$.a.double()`, expected: `1.5`},
+		{target: `{"a": "x"}`, path: `$.a.double()`,
+			err: `string argument of jsonpath item method .double() is not a valid representation`},
+		{target: `{"a": {"b": 1, "c": "x"}}`, path: `$.a.keyvalue()`,
+			expected: `{"id": 16, "key": "b", "value": 1} {"id": 16, "key": "c", "value": "x"}`},
+		{target: `{"a": {"b": 1, "c": "x"}}`, path: `$.a.keyvalue().key`, expected: `"b" "c"`},
+		// The ids are the offsets of the objects in the Postgres encoding of the
+		// document, or of the vars or a generated object.
+		{target: `[{"a": 1, "b": [1, 2]}, {"c": {"a": "bbb"}}]`, path: `$[*].keyvalue()`,
+			expected: `{"id": 12, "key": "a", "value": 1} {"id": 12, "key": "b", "value": [1, 2]} ` +
+				`{"id": 72, "key": "c", "value": {"a": "bbb"}}`},
+		{target: `{"a": {"b": 1}}`, path: `$.keyvalue().value.keyvalue()`,
+			expected: `{"id": 20000000048, "key": "b", "value": 1}`},
+		{target: `{}`, path: `$x.keyvalue()`, vars: `{"x": {"a": 1}}`,
+			expected: `{"id": 10000000016, "key": "a", "value": 1}`},
+
+		// Datetimes.
+		{target: `"2023-08-15"`, path: `$.datetime()`, expected: `"2023-08-15"`},
+		{target: `"2023-08-15"`, path: `$.datetime().type()`, expected: `"date"`},
+		{target: `"2023-08-15 12:34:56"`, path: `$.datetime()`, expected: `"2023-08-15T12:34:56"`},
+		{target: `"2023-08-15T12:34:56.5+02"`, path: `$.datetime()`,
+			expected: `"2023-08-15T12:34:56.5+02:00"`},
+		{target: `"2023-08-15T12:34:56+02:00"`, path: `$.datetime().type()`,
+			expected: `"timestamp with time zone"`},
+		{target: `"12:34:56"`, path: `$.datetime().type()`, expected: `"time without time zone"`},
+		{target: `"12:34:56-05:00"`, path: `$.datetime()`, expected: `"12:34:56-05:00"`},
+		{target: `["2023-08-15", "2023-08-16 12:00:00", "12:00:00"]`,
+			path:     `$[*] ? (@.datetime() > "2023-08-15 06:00:00".datetime())`,
+			expected: `"2023-08-16 12:00:00"`},
+		{target: `"15/08/2023"`, path: `$.datetime("DD/MM/YYYY")`, expected: `"2023-08-15"`},
+		{target: `"2023-08-15 12:34"`, path: `$.datetime("YYYY-MM-DD HH24:MI").type()`,
+			expected: `"timestamp without time zone"`},
+		{target: `"2023-08-15T01:02:03.5"`, path: `$.datetime("YYYY-MM-DD\"T\"HH24:MI:SS.MS")`,
+			expected: `"2023-08-15T01:02:03.5"`},
+		{target: `"10:30 PM +05"`, path: `$.datetime("HH12:MI AM TZH")`, expected: `"22:30:00+05:00"`},
+		{target: `"2023-08-15 -03:30"`, path: `$.datetime("YYYY-MM-DD TZH:TZM").type()`,
+			expected: `"timestamp with time zone"`},
+		{target: `"2023-13-01"`, path: `$.datetime("YYYY-MM-DD")`,
+			err: `date/time field value out of range: "2023-13-01"`},
+		{target: `"2023-08-15x"`, path: `$.datetime("YYYY-MM-DD")`,
+			err: `trailing characters remain in input string after datetime format`},
+		{target: `"13:00"`, path: `$.datetime("HH:MI")`,
+			err: `hour "13" is invalid for the 12-hour clock`},
+		{target: `"2023-Aug"`, path: `$.datetime("YYYY-Mon")`,
+			err: `datetime format pattern "Mon" is not supported`},
+		{target: `"not a date"`, path: `$.datetime()`,
+			err: `datetime format is not recognized: "not a date"`},
+		{target: `1`, path: `$.datetime()`,
+			err: `jsonpath item method .datetime() can only be applied to a string`},
+
+		// Predicates at the top level return booleans.
+		{target: `{"a": 1}`, path: `$.a == 1`, expected: `true`},
+		{target: `{"a": 1}`, path: `$.a == 2`, expected: `false`},
+		{target: `{"a": 1}`, path: `$.a == "1"`, expected: `null`},
+		{target: `{"a": [1, "x"]}`, path: `$.a[*] == 1`, expected: `true`},
+		{target: `{"a": [1, "x"]}`, path: `strict $.a[*] == 1`, expected: `null`},
+		{target: `{"a": 1}`, path: `exists($.b)`, expected: `false`},
+
+		// Variables.
+		{target: `{"a": 1}`, path: `$.a > $min`, vars: `{"min": 0}`, expected: `true`},
+		{target: `[1, 2, 3]`, path: `$[*] ? (@ >= $min && @ <= $max)`,
+			vars: `{"min": 2, "max": 5}`, expected: `2 3`},
+		{target: `{"a": 1}`, path: `$.a > $min`, err: `could not find jsonpath variable "min"`},
+		{target: `{"a": 1}`, path: `$.a`, vars: `[]`, err: `"vars" argument is not an object`},
+	} {
+		t.Run(tc.target+" "+tc.path, func(t *testing.T) {
+			target, err := json.ParseJSON(tc.target)
+			require.NoError(t, err)
+			var vars json.JSON
+			if tc.vars != "" {
+				vars, err = json.ParseJSON(tc.vars)
+				require.NoError(t, err)
+			}
+			res, err := EvalQuery(MustParse(tc.path), target, vars, false /* silent */)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			strs := make([]string, len(res))
+			for i := range res {
+				strs[i] = res[i].String()
+			}
+			require.Equal(t, tc.expected, strings.Join(strs, " "))
+		})
+	}
+}
+
+func TestEvalSilent(t *testing.T) {
+	target := json.FromString("x")
+
+	// Errors caused by the target are suppressed.
+	res, err := EvalQuery(MustParse(`strict $.a`), target, nil /* vars */, true /* silent */)
+	require.NoError(t, err)
+	require.Empty(t, res)
+	_, isNull, err := EvalExists(MustParse(`$ + 1`), target, nil /* vars */, true /* silent */)
+	require.NoError(t, err)
+	require.True(t, isNull)
+
+	// Errors caused by the path or variables are not.
+	_, err = EvalQuery(MustParse(`$x`), target, nil /* vars */, true /* silent */)
+	require.ErrorContains(t, err, `could not find jsonpath variable "x"`)
+}
+
+func TestEvalExistsAndMatch(t *testing.T) {
+	target, err := json.ParseJSON(`{"a": [1, 2], "b": "x"}`)
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		path   string
+		exists string
+		match  string
+	}{
+		{path: `$.a`, exists: `true`, match: `error`},
+		{path: `$.c`, exists: `false`, match: `error`},
+		{path: `strict $.c`, exists: `error`, match: `error`},
+		{path: `$.a[*] ? (@ > 1)`, exists: `true`, match: `error`},
+		{path: `$.a[*] ? (@ > 5)`, exists: `false`, match: `error`},
+		{path: `$.a[*] > 1`, exists: `true`, match: `true`},
+		{path: `$.a[*] > 5`, exists: `true`, match: `false`},
+		{path: `$.b > 5`, exists: `true`, match: `null`},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			format := func(b, isNull bool, err error) string {
+				switch {
+				case err != nil:
+					return "error"
+				case isNull:
+					return "null"
+				case b:
+					return "true"
+				}
+				return "false"
+			}
+			path := MustParse(tc.path)
+			require.Equal(t, tc.exists, format(EvalExists(path, target, nil /* vars */, false /* silent */)))
+			require.Equal(t, tc.match, format(EvalMatch(path, target, nil /* vars */, false /* silent */)))
+		})
+	}
+}
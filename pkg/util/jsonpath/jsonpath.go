@@ -0,0 +1,476 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package jsonpath implements the SQL/JSON path language, which is used to
+// query JSON documents with the jsonb_path_* builtins and the @? and @@
+// operators.
+package jsonpath
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/apd/v3"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+)
+
+// Jsonpath is a parsed SQL/JSON path expression.
+type Jsonpath struct {
+	// Strict is true if the path is evaluated in strict mode, in which
+	// structural errors are raised rather than suppressed.
+	Strict bool
+	// Expr is the root expression of the path.
+	Expr Expr
+}
+
+// String returns the canonical text representation of the path, in the same
+// format as Postgres.
+func (j Jsonpath) String() string {
+	var sb strings.Builder
+	if j.Strict {
+		sb.WriteString("strict ")
+	}
+	formatExpr(&sb, j.Expr, true /* parens */)
+	return sb.String()
+}
+
+// IsPredicate returns true if the path is a predicate check expression, i.e.
+// its root is a boolean predicate rather than a path that returns items.
+func (j Jsonpath) IsPredicate() bool {
+	return isPredicate(j.Expr)
+}
+
+// Expr is a node in a jsonpath expression tree.
+type Expr interface {
+	jsonpathExpr()
+}
+
+// Root is the $ variable, which refers to the JSON document being queried.
+type Root struct{}
+
+// Current is the @ variable, which refers to the item being tested by the
+// enclosing filter expression.
+type Current struct{}
+
+// Last is the last keyword, which refers to the last index of the array being
+// subscripted.
+type Last struct{}
+
+// Variable is a named variable, such as $x, whose value is supplied by the
+// vars argument of the jsonb_path_* builtins.
+type Variable struct {
+	Name string
+}
+
+// Scalar is a literal value: a number, string, boolean or null.
+type Scalar struct {
+	Value json.JSON
+}
+
+// Accessor is an expression followed by a chain of accessors, such as
+// $.a[*].b or (@ + 1).abs().
+type Accessor struct {
+	Base  Expr
+	Chain []Step
+}
+
+// BinaryOperator is an enum of the binary operators in the jsonpath language.
+type BinaryOperator int
+
+const (
+	_ BinaryOperator = iota
+	OpAnd
+	OpOr
+	OpEqual
+	OpNotEqual
+	OpLess
+	OpLessEqual
+	OpGreater
+	OpGreaterEqual
+	OpStartsWith
+	OpAdd
+	OpSub
+	OpMul
+	OpDiv
+	OpMod
+)
+
+var binaryOperatorStrings = [...]string{
+	OpAnd:          "&&",
+	OpOr:           "||",
+	OpEqual:        "==",
+	OpNotEqual:     "!=",
+	OpLess:         "<",
+	OpLessEqual:    "<=",
+	OpGreater:      ">",
+	OpGreaterEqual: ">=",
+	OpStartsWith:   "starts with",
+	OpAdd:          "+",
+	OpSub:          "-",
+	OpMul:          "*",
+	OpDiv:          "/",
+	OpMod:          "%",
+}
+
+func (o BinaryOperator) String() string {
+	return binaryOperatorStrings[o]
+}
+
+// isComparison returns true if the operator compares its operands.
+func (o BinaryOperator) isComparison() bool {
+	return o >= OpEqual && o <= OpStartsWith
+}
+
+// isArithmetic returns true if the operator is an arithmetic operator.
+func (o BinaryOperator) isArithmetic() bool {
+	return o >= OpAdd && o <= OpMod
+}
+
+// Binary is a binary operator expression.
+type Binary struct {
+	Op    BinaryOperator
+	Left  Expr
+	Right Expr
+}
+
+// UnaryOperator is an enum of the unary operators in the jsonpath language.
+type UnaryOperator int
+
+const (
+	_ UnaryOperator = iota
+	OpNot
+	OpPlus
+	OpMinus
+)
+
+// Unary is a unary operator expression.
+type Unary struct {
+	Op      UnaryOperator
+	Operand Expr
+}
+
+// Exists is the exists (path) predicate, which is true if the path returns
+// any items.
+type Exists struct {
+	Path Expr
+}
+
+// IsUnknown is the (predicate) is unknown predicate.
+type IsUnknown struct {
+	Predicate Expr
+}
+
+// LikeRegex is the like_regex predicate, which matches string items against
+// a regular expression.
+type LikeRegex struct {
+	Expr    Expr
+	Pattern string
+	Flags   string
+}
+
+func (Root) jsonpathExpr()      {}
+func (Current) jsonpathExpr()   {}
+func (Last) jsonpathExpr()      {}
+func (Variable) jsonpathExpr()  {}
+func (Scalar) jsonpathExpr()    {}
+func (Accessor) jsonpathExpr()  {}
+func (Binary) jsonpathExpr()    {}
+func (Unary) jsonpathExpr()     {}
+func (Exists) jsonpathExpr()    {}
+func (IsUnknown) jsonpathExpr() {}
+func (LikeRegex) jsonpathExpr() {}
+
+// Step is a single accessor in an Accessor chain.
+type Step interface {
+	jsonpathStep()
+}
+
+// Member is the .key accessor.
+type Member struct {
+	Key string
+}
+
+// AnyMember is the .* accessor.
+type AnyMember struct{}
+
+// Subscript is a single subscript in an array accessor. To is nil unless the
+// subscript is a range of the form [from to to].
+type Subscript struct {
+	From Expr
+	To   Expr
+}
+
+// ArrayIndex is the [subscript, ...] accessor.
+type ArrayIndex struct {
+	Subscripts []Subscript
+}
+
+// AnyArray is the [*] accessor.
+type AnyArray struct{}
+
+// RecursiveLevelLast is used for the upper bound of a .** accessor which
+// has no limit on its depth.
+const RecursiveLevelLast = -1
+
+// AnyRecursive is the .** accessor, which returns the item and all of its
+// descendants between the given nesting levels.
+type AnyRecursive struct {
+	// First and Last are the inclusive bounds of the nesting levels that are
+	// returned. Either may be RecursiveLevelLast.
+	First, Last int
+}
+
+// Filter is the ?(predicate) accessor.
+type Filter struct {
+	Predicate Expr
+}
+
+// Method is an item method, such as .size() or .datetime().
+type Method struct {
+	Name MethodName
+	// Arg is the optional template argument of .datetime().
+	Arg *string
+}
+
+func (Member) jsonpathStep()       {}
+func (AnyMember) jsonpathStep()    {}
+func (ArrayIndex) jsonpathStep()   {}
+func (AnyArray) jsonpathStep()     {}
+func (AnyRecursive) jsonpathStep() {}
+func (Filter) jsonpathStep()       {}
+func (Method) jsonpathStep()       {}
+
+// MethodName is an enum of the item methods supported by the jsonpath
+// language.
+type MethodName int
+
+const (
+	_ MethodName = iota
+	MethodType
+	MethodSize
+	MethodDouble
+	MethodCeiling
+	MethodFloor
+	MethodAbs
+	MethodKeyValue
+	MethodDatetime
+)
+
+var methodNames = [...]string{
+	MethodType:     "type",
+	MethodSize:     "size",
+	MethodDouble:   "double",
+	MethodCeiling:  "ceiling",
+	MethodFloor:    "floor",
+	MethodAbs:      "abs",
+	MethodKeyValue: "keyvalue",
+	MethodDatetime: "datetime",
+}
+
+func (m MethodName) String() string {
+	return methodNames[m]
+}
+
+func isPredicate(e Expr) bool {
+	switch t := e.(type) {
+	case Binary:
+		return t.Op == OpAnd || t.Op == OpOr || t.Op.isComparison()
+	case Unary:
+		return t.Op == OpNot
+	case Exists, IsUnknown, LikeRegex:
+		return true
+	}
+	return false
+}
+
+// priority returns the operator precedence of the expression, which is used
+// to decide whether it must be parenthesized when formatted. A higher
+// priority binds more tightly.
+func priority(e Expr) int {
+	switch t := e.(type) {
+	case Binary:
+		switch {
+		case t.Op == OpOr:
+			return 0
+		case t.Op == OpAnd:
+			return 1
+		case t.Op.isComparison():
+			return 2
+		case t.Op == OpAdd || t.Op == OpSub:
+			return 3
+		default:
+			return 4
+		}
+	case Unary:
+		if t.Op != OpNot {
+			return 5
+		}
+	case LikeRegex:
+		return 2
+	}
+	return 6
+}
+
+// formatExpr writes the text representation of e to sb. If parens is true,
+// operator expressions are wrapped in parentheses.
+func formatExpr(sb *strings.Builder, e Expr, parens bool) {
+	switch t := e.(type) {
+	case Root:
+		sb.WriteByte('$')
+	case Current:
+		sb.WriteByte('@')
+	case Last:
+		sb.WriteString("last")
+	case Variable:
+		sb.WriteByte('$')
+		writeQuoted(sb, t.Name)
+	case Scalar:
+		formatScalar(sb, t.Value)
+	case Accessor:
+		switch t.Base.(type) {
+		case Binary, Unary, LikeRegex, Exists, IsUnknown:
+			formatExpr(sb, t.Base, true /* parens */)
+		default:
+			formatExpr(sb, t.Base, false /* parens */)
+		}
+		for _, step := range t.Chain {
+			formatStep(sb, step)
+		}
+	case Binary:
+		if parens {
+			sb.WriteByte('(')
+		}
+		formatExpr(sb, t.Left, priority(t.Left) <= priority(t))
+		sb.WriteByte(' ')
+		sb.WriteString(t.Op.String())
+		sb.WriteByte(' ')
+		formatExpr(sb, t.Right, priority(t.Right) <= priority(t))
+		if parens {
+			sb.WriteByte(')')
+		}
+	case Unary:
+		if t.Op == OpNot {
+			sb.WriteString("!(")
+			formatExpr(sb, t.Operand, false /* parens */)
+			sb.WriteByte(')')
+			return
+		}
+		if parens {
+			sb.WriteByte('(')
+		}
+		if t.Op == OpMinus {
+			sb.WriteByte('-')
+		} else {
+			sb.WriteByte('+')
+		}
+		formatExpr(sb, t.Operand, priority(t.Operand) <= priority(t))
+		if parens {
+			sb.WriteByte(')')
+		}
+	case Exists:
+		sb.WriteString("exists (")
+		formatExpr(sb, t.Path, false /* parens */)
+		sb.WriteByte(')')
+	case IsUnknown:
+		sb.WriteByte('(')
+		formatExpr(sb, t.Predicate, false /* parens */)
+		sb.WriteString(") is unknown")
+	case LikeRegex:
+		if parens {
+			sb.WriteByte('(')
+		}
+		formatExpr(sb, t.Expr, priority(t.Expr) <= priority(t))
+		sb.WriteString(" like_regex ")
+		writeQuoted(sb, t.Pattern)
+		if t.Flags != "" {
+			sb.WriteString(" flag ")
+			writeQuoted(sb, t.Flags)
+		}
+		if parens {
+			sb.WriteByte(')')
+		}
+	}
+}
+
+func formatStep(sb *strings.Builder, step Step) {
+	switch t := step.(type) {
+	case Member:
+		sb.WriteByte('.')
+		writeQuoted(sb, t.Key)
+	case AnyMember:
+		sb.WriteString(".*")
+	case ArrayIndex:
+		sb.WriteByte('[')
+		for i, sub := range t.Subscripts {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			formatExpr(sb, sub.From, false /* parens */)
+			if sub.To != nil {
+				sb.WriteString(" to ")
+				formatExpr(sb, sub.To, false /* parens */)
+			}
+		}
+		sb.WriteByte(']')
+	case AnyArray:
+		sb.WriteString("[*]")
+	case AnyRecursive:
+		sb.WriteString(".**")
+		if t.First == 0 && t.Last == RecursiveLevelLast {
+			return
+		}
+		sb.WriteByte('{')
+		formatLevel(sb, t.First)
+		if t.First != t.Last {
+			sb.WriteString(" to ")
+			formatLevel(sb, t.Last)
+		}
+		sb.WriteByte('}')
+	case Filter:
+		sb.WriteString("?(")
+		formatExpr(sb, t.Predicate, false /* parens */)
+		sb.WriteByte(')')
+	case Method:
+		sb.WriteByte('.')
+		sb.WriteString(t.Name.String())
+		sb.WriteByte('(')
+		if t.Arg != nil {
+			writeQuoted(sb, *t.Arg)
+		}
+		sb.WriteByte(')')
+	}
+}
+
+func formatLevel(sb *strings.Builder, level int) {
+	if level == RecursiveLevelLast {
+		sb.WriteString("last")
+	} else {
+		sb.WriteString(strconv.Itoa(level))
+	}
+}
+
+func formatScalar(sb *strings.Builder, j json.JSON) {
+	if d, ok := j.AsDecimal(); ok {
+		sb.WriteString(d.Text('f'))
+		return
+	}
+	sb.WriteString(j.String())
+}
+
+// writeQuoted writes s as a double-quoted string, escaped in the same way as a
+// JSON string.
+func writeQuoted(sb *strings.Builder, s string) {
+	sb.WriteString(json.FromString(s).String())
+}
+
+// decimalScalar returns a Scalar holding the given number.
+func decimalScalar(d *apd.Decimal) Scalar {
+	return Scalar{Value: json.FromDecimal(*d)}
+}
@@ -0,0 +1,239 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/apd/v3"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+)
+
+// The .keyvalue() method returns an id along with each key/value pair, which
+// identifies the object that the pair was taken from. Postgres computes the id
+// from the byte offset of the object within the binary jsonb encoding of its
+// base object, plus the id of the base object multiplied by 10^10. The base
+// object is the queried document (id 0), the vars object (id 1), or one of the
+// objects generated by .keyvalue() itself, which are numbered in the order in
+// which they are generated.
+//
+// To return the same ids, the evaluator tracks the base object and the offset
+// of the items that it produces whenever the path uses .keyvalue(). The offsets
+// are computed by replaying the jsonb encoding: a container is a 4-byte header
+// followed by a 4-byte entry for each element (or two for each pair of an
+// object), and then by the keys, sorted by length and then bytewise, and the
+// values. Containers and numbers are aligned to 4 bytes.
+
+// keyValueBaseIDMultiplier is the factor by which the id of the base object is
+// multiplied in the ids returned by .keyvalue().
+const keyValueBaseIDMultiplier = 10000000000
+
+// Ids of the base objects.
+const (
+	rootBaseID int64 = 0
+	varsBaseID int64 = 1
+)
+
+// itemLoc locates an item within the jsonb encoding of its base object.
+type itemLoc struct {
+	baseID int64
+	offset int
+}
+
+// keyValueID returns the id of the object item as returned by .keyvalue().
+func (it item) keyValueID() int64 {
+	if it.loc == nil {
+		return 0
+	}
+	return it.loc.baseID*keyValueBaseIDMultiplier + int64(it.loc.offset)
+}
+
+// childLoc returns the location of the child of the item which is encoded at
+// the given offset.
+func (it item) childLoc(offset int) *itemLoc {
+	if it.loc == nil {
+		return nil
+	}
+	return &itemLoc{baseID: it.loc.baseID, offset: offset}
+}
+
+// usesKeyValue returns true if the expression calls the .keyvalue() method.
+func usesKeyValue(expr Expr) bool {
+	switch t := expr.(type) {
+	case Accessor:
+		if usesKeyValue(t.Base) {
+			return true
+		}
+		for _, step := range t.Chain {
+			switch s := step.(type) {
+			case Method:
+				if s.Name == MethodKeyValue {
+					return true
+				}
+			case Filter:
+				if usesKeyValue(s.Predicate) {
+					return true
+				}
+			case ArrayIndex:
+				for _, sub := range s.Subscripts {
+					if usesKeyValue(sub.From) || (sub.To != nil && usesKeyValue(sub.To)) {
+						return true
+					}
+				}
+			}
+		}
+	case Binary:
+		return usesKeyValue(t.Left) || usesKeyValue(t.Right)
+	case Unary:
+		return usesKeyValue(t.Operand)
+	case Exists:
+		return usesKeyValue(t.Path)
+	case IsUnknown:
+		return usesKeyValue(t.Predicate)
+	case LikeRegex:
+		return usesKeyValue(t.Expr)
+	}
+	return false
+}
+
+func alignJSONB(offset int) int {
+	return (offset + 3) &^ 3
+}
+
+// jsonbLayout returns the offsets at which the jsonb encoding of j starts and
+// ends, if it is appended at the given offset. The start is after any
+// alignment padding.
+func jsonbLayout(j json.JSON, offset int) (start, end int) {
+	switch j.Type() {
+	case json.NullJSONType, json.TrueJSONType, json.FalseJSONType:
+		return offset, offset
+	case json.StringJSONType:
+		s, _ := j.AsText()
+		return offset, offset + len(*s)
+	case json.NumberJSONType:
+		d, _ := j.AsDecimal()
+		start = alignJSONB(offset)
+		return start, start + numericSize(d)
+	case json.ArrayJSONType:
+		elems, _ := j.AsArray()
+		start = alignJSONB(offset)
+		end = start + 4 + 4*len(elems)
+		for _, elem := range elems {
+			_, end = jsonbLayout(elem, end)
+		}
+		return start, end
+	default:
+		keys, vals := jsonbObjectOrder(j)
+		start = alignJSONB(offset)
+		end = start + 4 + 8*len(keys)
+		for _, k := range keys {
+			end += len(k)
+		}
+		for _, v := range vals {
+			_, end = jsonbLayout(v, end)
+		}
+		return start, end
+	}
+}
+
+// jsonbElemOffsets returns the offsets of the elements of the array j, whose
+// encoding starts at the given offset.
+func jsonbElemOffsets(j json.JSON, start int) []int {
+	elems, _ := j.AsArray()
+	res := make([]int, len(elems))
+	end := start + 4 + 4*len(elems)
+	for i, elem := range elems {
+		res[i], end = jsonbLayout(elem, end)
+	}
+	return res
+}
+
+// jsonbMemberOffsets returns the offsets of the values of the object j, whose
+// encoding starts at the given offset, keyed by their keys.
+func jsonbMemberOffsets(j json.JSON, start int) map[string]int {
+	keys, vals := jsonbObjectOrder(j)
+	res := make(map[string]int, len(keys))
+	end := start + 4 + 8*len(keys)
+	for _, k := range keys {
+		end += len(k)
+	}
+	for i, v := range vals {
+		res[keys[i]], end = jsonbLayout(v, end)
+	}
+	return res
+}
+
+// jsonbObjectOrder returns the keys and values of the object j in the order in
+// which they are encoded by Postgres.
+func jsonbObjectOrder(j json.JSON) (keys []string, vals []json.JSON) {
+	iter, _ := j.ObjectIter()
+	for iter != nil && iter.Next() {
+		keys = append(keys, iter.Key())
+		vals = append(vals, iter.Value())
+	}
+	sort.Sort(jsonbKeyOrder{keys: keys, vals: vals})
+	return keys, vals
+}
+
+type jsonbKeyOrder struct {
+	keys []string
+	vals []json.JSON
+}
+
+func (o jsonbKeyOrder) Len() int { return len(o.keys) }
+
+func (o jsonbKeyOrder) Less(i, j int) bool {
+	if len(o.keys[i]) != len(o.keys[j]) {
+		return len(o.keys[i]) < len(o.keys[j])
+	}
+	return o.keys[i] < o.keys[j]
+}
+
+func (o jsonbKeyOrder) Swap(i, j int) {
+	o.keys[i], o.keys[j] = o.keys[j], o.keys[i]
+	o.vals[i], o.vals[j] = o.vals[j], o.vals[i]
+}
+
+// numericSize returns the size of the Postgres encoding of a numeric value:
+// a 4-byte length, a 2-byte header (or a 4-byte header if the scale or the
+// weight is too large for the short format) and 2 bytes for each base-10000
+// digit between the most and the least significant nonzero ones.
+func numericSize(d *apd.Decimal) int {
+	var scale, weight, ndigits int
+	if d.Exponent < 0 {
+		scale = int(-d.Exponent)
+	}
+	if !d.IsZero() {
+		digits := d.Coeff.String()
+		trailingZeros := 0
+		for trailingZeros < len(digits) && digits[len(digits)-1-trailingZeros] == '0' {
+			trailingZeros++
+		}
+		// The powers of ten of the most and the least significant nonzero
+		// decimal digits determine the base-10000 digits they belong to.
+		high := int(d.Exponent) + len(digits) - 1
+		low := int(d.Exponent) + trailingZeros
+		weight = floorDiv(high, 4)
+		ndigits = weight - floorDiv(low, 4) + 1
+	}
+	if scale <= 63 && weight >= -64 && weight <= 63 {
+		return 6 + 2*ndigits
+	}
+	return 8 + 2*ndigits
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
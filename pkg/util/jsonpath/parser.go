@@ -0,0 +1,904 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/apd/v3"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/errors"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	// tokIdent is an unquoted identifier, which may be a keyword, a key or a
+	// method name depending on its context.
+	tokIdent
+	// tokString is a double-quoted string.
+	tokString
+	// tokNumber is a numeric literal.
+	tokNumber
+	// tokVariable is $ or a named variable such as $x. The value is empty for $.
+	tokVariable
+	// tokPunct is an operator or a punctuation character.
+	tokPunct
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+	// quoted is true if a tokVariable was written as $"name".
+	quoted bool
+}
+
+// punctuation lists the operators and punctuation characters of the jsonpath
+// language, with the longest tokens first.
+var punctuation = []string{
+	"==", "!=", "<>", "<=", ">=", "&&", "||", "**",
+	"$", "@", ".", ",", "[", "]", "(", ")", "{", "}", "?", "!",
+	"<", ">", "+", "-", "*", "/", "%",
+}
+
+// lexer splits a jsonpath string into tokens.
+type lexer struct {
+	input string
+	pos   int
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || r >= utf8.RuneSelf
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.input) {
+		switch l.input[l.pos] {
+		case ' ', '\t', '\n', '\r', '\f', '\v':
+			l.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipSpace()
+	if l.pos >= len(l.input) {
+		return token{kind: tokEOF}, nil
+	}
+	c := l.input[l.pos]
+	switch {
+	case c == '"':
+		s, err := l.scanString()
+		return token{kind: tokString, val: s}, err
+	case c >= '0' && c <= '9',
+		c == '.' && l.pos+1 < len(l.input) && l.input[l.pos+1] >= '0' && l.input[l.pos+1] <= '9':
+		return token{kind: tokNumber, val: l.scanNumber()}, nil
+	case c == '$':
+		l.pos++
+		if l.pos < len(l.input) && l.input[l.pos] == '"' {
+			s, err := l.scanString()
+			return token{kind: tokVariable, val: s, quoted: true}, err
+		}
+		return token{kind: tokVariable, val: l.scanIdent()}, nil
+	}
+	if r, _ := utf8.DecodeRuneInString(l.input[l.pos:]); isIdentRune(r) {
+		return token{kind: tokIdent, val: l.scanIdent()}, nil
+	}
+	for _, p := range punctuation {
+		if strings.HasPrefix(l.input[l.pos:], p) {
+			l.pos += len(p)
+			return token{kind: tokPunct, val: p}, nil
+		}
+	}
+	return token{}, syntaxError(l.input[l.pos:])
+}
+
+func (l *lexer) scanIdent() string {
+	start := l.pos
+	for l.pos < len(l.input) {
+		r, size := utf8.DecodeRuneInString(l.input[l.pos:])
+		if !isIdentRune(r) {
+			break
+		}
+		l.pos += size
+	}
+	return l.input[start:l.pos]
+}
+
+func (l *lexer) scanDigits() {
+	for l.pos < len(l.input) && l.input[l.pos] >= '0' && l.input[l.pos] <= '9' {
+		l.pos++
+	}
+}
+
+func (l *lexer) scanNumber() string {
+	start := l.pos
+	l.scanDigits()
+	if l.pos+1 < len(l.input) && l.input[l.pos] == '.' &&
+		l.input[l.pos+1] >= '0' && l.input[l.pos+1] <= '9' {
+		l.pos++
+		l.scanDigits()
+	}
+	if l.pos < len(l.input) && (l.input[l.pos] == 'e' || l.input[l.pos] == 'E') {
+		save := l.pos
+		l.pos++
+		if l.pos < len(l.input) && (l.input[l.pos] == '+' || l.input[l.pos] == '-') {
+			l.pos++
+		}
+		if l.pos < len(l.input) && l.input[l.pos] >= '0' && l.input[l.pos] <= '9' {
+			l.scanDigits()
+		} else {
+			l.pos = save
+		}
+	}
+	return l.input[start:l.pos]
+}
+
+// scanString scans a double-quoted string, interpreting the same escape
+// sequences as Postgres.
+func (l *lexer) scanString() (string, error) {
+	var sb strings.Builder
+	l.pos++
+	for l.pos < len(l.input) {
+		c := l.input[l.pos]
+		switch c {
+		case '"':
+			l.pos++
+			return sb.String(), nil
+		case '\\':
+			l.pos++
+			if l.pos >= len(l.input) {
+				return "", syntaxError("")
+			}
+			e := l.input[l.pos]
+			l.pos++
+			switch e {
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'v':
+				sb.WriteByte('\v')
+			case 'x':
+				r, err := l.scanHex(2, 2)
+				if err != nil {
+					return "", err
+				}
+				sb.WriteRune(r)
+			case 'u':
+				var r rune
+				var err error
+				if l.pos < len(l.input) && l.input[l.pos] == '{' {
+					l.pos++
+					r, err = l.scanHex(1, 6)
+					if err == nil && (l.pos >= len(l.input) || l.input[l.pos] != '}') {
+						err = pgerror.New(pgcode.Syntax, "invalid Unicode escape sequence")
+					}
+					l.pos++
+				} else {
+					r, err = l.scanHex(4, 4)
+				}
+				if err != nil {
+					return "", err
+				}
+				sb.WriteRune(r)
+			default:
+				sb.WriteByte(e)
+			}
+		default:
+			sb.WriteByte(c)
+			l.pos++
+		}
+	}
+	return "", pgerror.New(pgcode.Syntax, "unexpected end of quoted string")
+}
+
+func (l *lexer) scanHex(minDigits, maxDigits int) (rune, error) {
+	start := l.pos
+	for l.pos < len(l.input) && l.pos-start < maxDigits && isHexDigit(l.input[l.pos]) {
+		l.pos++
+	}
+	if l.pos-start < minDigits {
+		return 0, pgerror.New(pgcode.Syntax, "invalid hexadecimal character sequence")
+	}
+	v, err := strconv.ParseUint(l.input[start:l.pos], 16, 32)
+	if err != nil {
+		return 0, pgerror.Wrap(err, pgcode.Syntax, "invalid hexadecimal character sequence")
+	}
+	return rune(v), nil
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func syntaxError(near string) error {
+	if near == "" {
+		return pgerror.New(pgcode.Syntax, "syntax error at end of jsonpath input")
+	}
+	return pgerror.Newf(pgcode.Syntax, "syntax error at or near %q of jsonpath input", near)
+}
+
+// parser is a recursive descent parser for the jsonpath language. The
+// operator precedence, from lowest to highest, is ||, &&, !, comparisons, +
+// and -, *, / and %, unary + and -, and finally accessors.
+type parser struct {
+	lex lexer
+	tok token
+	// inFilter and inSubscript track whether @ and last are allowed.
+	inFilter    int
+	inSubscript int
+}
+
+// Parse parses a jsonpath string.
+func Parse(s string) (Jsonpath, error) {
+	p := parser{lex: lexer{input: s}}
+	if err := p.advance(); err != nil {
+		return Jsonpath{}, err
+	}
+	var j Jsonpath
+	if p.tok.kind == tokIdent && (p.tok.val == "strict" || p.tok.val == "lax") {
+		j.Strict = p.tok.val == "strict"
+		if err := p.advance(); err != nil {
+			return Jsonpath{}, err
+		}
+	}
+	if p.tok.kind == tokEOF {
+		return Jsonpath{}, syntaxError("")
+	}
+	expr, err := p.parseOr()
+	if err != nil {
+		return Jsonpath{}, err
+	}
+	if p.tok.kind != tokEOF {
+		return Jsonpath{}, p.unexpected()
+	}
+	j.Expr = expr
+	return j, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) isPunct(s string) bool {
+	return p.tok.kind == tokPunct && p.tok.val == s
+}
+
+func (p *parser) isKeyword(s string) bool {
+	return p.tok.kind == tokIdent && p.tok.val == s
+}
+
+func (p *parser) expectPunct(s string) error {
+	if !p.isPunct(s) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+func (p *parser) expectKeyword(s string) error {
+	if !p.isKeyword(s) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokEOF {
+		return syntaxError("")
+	}
+	return syntaxError(p.tok.val)
+}
+
+func (p *parser) requirePredicate(e Expr) error {
+	if !isPredicate(e) {
+		return p.unexpected()
+	}
+	return nil
+}
+
+func (p *parser) requireNotPredicate(e Expr) error {
+	if isPredicate(e) {
+		return p.unexpected()
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("||") {
+		if err := p.requirePredicate(left); err != nil {
+			return nil, err
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.requirePredicate(right); err != nil {
+			return nil, err
+		}
+		left = Binary{Op: OpOr, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("&&") {
+		if err := p.requirePredicate(left); err != nil {
+			return nil, err
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.requirePredicate(right); err != nil {
+			return nil, err
+		}
+		left = Binary{Op: OpAnd, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (Expr, error) {
+	if !p.isPunct("!") {
+		return p.parseComparison()
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	operand, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.requirePredicate(operand); err != nil {
+		return nil, err
+	}
+	return Unary{Op: OpNot, Operand: operand}, nil
+}
+
+var comparisonOperators = map[string]BinaryOperator{
+	"==": OpEqual,
+	"!=": OpNotEqual,
+	"<>": OpNotEqual,
+	"<":  OpLess,
+	"<=": OpLessEqual,
+	">":  OpGreater,
+	">=": OpGreaterEqual,
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case p.tok.kind == tokPunct:
+		op, ok := comparisonOperators[p.tok.val]
+		if !ok {
+			return left, nil
+		}
+		if err := p.requireNotPredicate(left); err != nil {
+			return nil, err
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.requireNotPredicate(right); err != nil {
+			return nil, err
+		}
+		return Binary{Op: op, Left: left, Right: right}, nil
+
+	case p.isKeyword("starts"):
+		if err := p.requireNotPredicate(left); err != nil {
+			return nil, err
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("with"); err != nil {
+			return nil, err
+		}
+		// The initial substring must be a string literal or a variable.
+		var right Expr
+		switch p.tok.kind {
+		case tokString:
+			right = Scalar{Value: json.FromString(p.tok.val)}
+		case tokVariable:
+			if p.tok.val == "" && !p.tok.quoted {
+				return nil, p.unexpected()
+			}
+			right = Variable{Name: p.tok.val}
+		default:
+			return nil, p.unexpected()
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return Binary{Op: OpStartsWith, Left: left, Right: right}, nil
+
+	case p.isKeyword("like_regex"):
+		if err := p.requireNotPredicate(left); err != nil {
+			return nil, err
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokString {
+			return nil, p.unexpected()
+		}
+		lr := LikeRegex{Expr: left, Pattern: p.tok.val}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.isKeyword("flag") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.tok.kind != tokString {
+				return nil, p.unexpected()
+			}
+			lr.Flags = p.tok.val
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := compileRegex(lr.Pattern, lr.Flags); err != nil {
+			return nil, err
+		}
+		return lr, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (Expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("+") || p.isPunct("-") {
+		op := OpAdd
+		if p.tok.val == "-" {
+			op = OpSub
+		}
+		if err := p.requireNotPredicate(left); err != nil {
+			return nil, err
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.requireNotPredicate(right); err != nil {
+			return nil, err
+		}
+		left = Binary{Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("*") || p.isPunct("/") || p.isPunct("%") {
+		var op BinaryOperator
+		switch p.tok.val {
+		case "*":
+			op = OpMul
+		case "/":
+			op = OpDiv
+		default:
+			op = OpMod
+		}
+		if err := p.requireNotPredicate(left); err != nil {
+			return nil, err
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.requireNotPredicate(right); err != nil {
+			return nil, err
+		}
+		left = Binary{Op: op, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if !p.isPunct("+") && !p.isPunct("-") {
+		return p.parseAccessor()
+	}
+	minus := p.tok.val == "-"
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.requireNotPredicate(operand); err != nil {
+		return nil, err
+	}
+	// Fold the sign into numeric literals, as Postgres does.
+	if s, ok := operand.(Scalar); ok {
+		if d, ok := s.Value.AsDecimal(); ok {
+			if minus {
+				var neg apd.Decimal
+				neg.Neg(d)
+				return decimalScalar(&neg), nil
+			}
+			return s, nil
+		}
+	}
+	if minus {
+		return Unary{Op: OpMinus, Operand: operand}, nil
+	}
+	return Unary{Op: OpPlus, Operand: operand}, nil
+}
+
+func (p *parser) parseAccessor() (Expr, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	var chain []Step
+	for {
+		var step Step
+		switch {
+		case p.isPunct("."):
+			step, err = p.parseDotStep()
+		case p.isPunct("["):
+			step, err = p.parseArrayStep()
+		case p.isPunct("?"):
+			step, err = p.parseFilter()
+		default:
+			if len(chain) == 0 {
+				return base, nil
+			}
+			return Accessor{Base: base, Chain: chain}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, step)
+	}
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokVariable:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if tok.val == "" && !tok.quoted {
+			return Root{}, nil
+		}
+		return Variable{Name: tok.val}, nil
+
+	case tokString:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return Scalar{Value: json.FromString(tok.val)}, nil
+
+	case tokNumber:
+		d, _, err := apd.NewFromString(tok.val)
+		if err != nil {
+			return nil, syntaxError(tok.val)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return decimalScalar(d), nil
+
+	case tokIdent:
+		switch tok.val {
+		case "true", "false", "null":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			switch tok.val {
+			case "true":
+				return Scalar{Value: json.TrueJSONValue}, nil
+			case "false":
+				return Scalar{Value: json.FalseJSONValue}, nil
+			default:
+				return Scalar{Value: json.NullJSONValue}, nil
+			}
+		case "last":
+			if p.inSubscript == 0 {
+				return nil, pgerror.New(pgcode.Syntax, "LAST is allowed only in array subscripts")
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			return Last{}, nil
+		case "exists":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if err := p.expectPunct("("); err != nil {
+				return nil, err
+			}
+			path, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.requireNotPredicate(path); err != nil {
+				return nil, err
+			}
+			if err := p.expectPunct(")"); err != nil {
+				return nil, err
+			}
+			return Exists{Path: path}, nil
+		}
+
+	case tokPunct:
+		switch tok.val {
+		case "@":
+			if p.inFilter == 0 {
+				return nil, pgerror.New(pgcode.Syntax, "@ is not allowed in root expressions")
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			return Current{}, nil
+		case "(":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectPunct(")"); err != nil {
+				return nil, err
+			}
+			if p.isKeyword("is") {
+				if err := p.requirePredicate(inner); err != nil {
+					return nil, err
+				}
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+				if err := p.expectKeyword("unknown"); err != nil {
+					return nil, err
+				}
+				return IsUnknown{Predicate: inner}, nil
+			}
+			return inner, nil
+		}
+	}
+	return nil, p.unexpected()
+}
+
+var methodsByName = map[string]MethodName{
+	"type":     MethodType,
+	"size":     MethodSize,
+	"double":   MethodDouble,
+	"ceiling":  MethodCeiling,
+	"floor":    MethodFloor,
+	"abs":      MethodAbs,
+	"keyvalue": MethodKeyValue,
+	"datetime": MethodDatetime,
+}
+
+// parseDotStep parses an accessor that starts with a period: .key, ."key",
+// .*, .** or a method call.
+func (p *parser) parseDotStep() (Step, error) {
+	// The token following the period must not be separated by whitespace.
+	if p.lex.pos < len(p.lex.input) && p.lex.input[p.lex.pos] <= ' ' {
+		return nil, p.unexpected()
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	tok := p.tok
+	switch {
+	case tok.kind == tokPunct && tok.val == "*":
+		return AnyMember{}, p.advance()
+
+	case tok.kind == tokPunct && tok.val == "**":
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return p.parseRecursiveLevels()
+
+	case tok.kind == tokString:
+		return Member{Key: tok.val}, p.advance()
+
+	case tok.kind == tokIdent:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, isMethod := methodsByName[tok.val]
+		if !isMethod || !p.isPunct("(") {
+			return Member{Key: tok.val}, nil
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		m := Method{Name: name}
+		if name == MethodDatetime && p.tok.kind == tokString {
+			arg := p.tok.val
+			m.Arg = &arg
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		return m, p.expectPunct(")")
+	}
+	return nil, p.unexpected()
+}
+
+// parseRecursiveLevels parses the optional {level} or {first to last} suffix
+// of a .** accessor.
+func (p *parser) parseRecursiveLevels() (Step, error) {
+	step := AnyRecursive{First: 0, Last: RecursiveLevelLast}
+	if !p.isPunct("{") {
+		return step, nil
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	first, err := p.parseLevel()
+	if err != nil {
+		return nil, err
+	}
+	step.First, step.Last = first, first
+	if p.isKeyword("to") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if step.Last, err = p.parseLevel(); err != nil {
+			return nil, err
+		}
+	}
+	return step, p.expectPunct("}")
+}
+
+func (p *parser) parseLevel() (int, error) {
+	tok := p.tok
+	if tok.kind == tokIdent && tok.val == "last" {
+		return RecursiveLevelLast, p.advance()
+	}
+	if tok.kind != tokNumber {
+		return 0, p.unexpected()
+	}
+	level, err := strconv.Atoi(tok.val)
+	if err != nil || level < 0 {
+		return 0, syntaxError(tok.val)
+	}
+	return level, p.advance()
+}
+
+// parseArrayStep parses the [*] and [subscript, ...] accessors.
+func (p *parser) parseArrayStep() (Step, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.isPunct("*") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return AnyArray{}, p.expectPunct("]")
+	}
+	p.inSubscript++
+	defer func() { p.inSubscript-- }()
+	var step ArrayIndex
+	for {
+		from, err := p.parseSubscriptExpr()
+		if err != nil {
+			return nil, err
+		}
+		sub := Subscript{From: from}
+		if p.isKeyword("to") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if sub.To, err = p.parseSubscriptExpr(); err != nil {
+				return nil, err
+			}
+		}
+		step.Subscripts = append(step.Subscripts, sub)
+		if !p.isPunct(",") {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return step, p.expectPunct("]")
+}
+
+func (p *parser) parseSubscriptExpr() (Expr, error) {
+	e, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.requireNotPredicate(e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (p *parser) parseFilter() (Step, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	p.inFilter++
+	pred, err := p.parseOr()
+	p.inFilter--
+	if err != nil {
+		return nil, err
+	}
+	if err := p.requirePredicate(pred); err != nil {
+		return nil, err
+	}
+	return Filter{Predicate: pred}, p.expectPunct(")")
+}
+
+// MustParse parses a jsonpath string, panicking on error. It is intended for
+// use in tests.
+func MustParse(s string) Jsonpath {
+	j, err := Parse(s)
+	if err != nil {
+		panic(errors.NewAssertionErrorWithWrappedErrf(err, "invalid jsonpath %q", s))
+	}
+	return j
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected string
+	}{
+		{`$`, `$`},
+		{`  $  `, `$`},
+		{`strict $.a`, `strict $."a"`},
+		{`lax $.a[*]`, `$."a"[*]`},
+		{`$.a.b`, `$."a"."b"`},
+		{`$."key with space"`, `$."key with space"`},
+		{`$."A\x42\n"`, `$."AB\n"`},
+		{`$.*`, `$.*`},
+		{`$[*]`, `$[*]`},
+		{`$[0]`, `$[0]`},
+		{`$[1, 2 to last]`, `$[1,2 to last]`},
+		{`$[last - 1]`, `$[last - 1]`},
+		{`$.**`, `$.**`},
+		{`$.**{2}`, `$.**{2}`},
+		{`$.**{1 to last}`, `$.**{1 to last}`},
+		{`$x`, `$"x"`},
+		{`$"my var"`, `$"my var"`},
+		{`1`, `1`},
+		{`-1`, `-1`},
+		{`1.5e1`, `15`},
+		{`0.5`, `0.5`},
+		{`"str"`, `"str"`},
+		{`true`, `true`},
+		{`null`, `null`},
+
+		// Arithmetic.
+		{`$.a + 1`, `($."a" + 1)`},
+		{`1 + 2 * 3`, `(1 + 2 * 3)`},
+		{`(1 + 2) * 3`, `((1 + 2) * 3)`},
+		{`1 - (2 - 3)`, `(1 - (2 - 3))`},
+		{`-$.a`, `(-$."a")`},
+		{`$.a % 2`, `($."a" % 2)`},
+
+		// Methods.
+		{`$.a.type()`, `$."a".type()`},
+		{`$.size().double()`, `$.size().double()`},
+		{`$.keyvalue()`, `$.keyvalue()`},
+		{`$.datetime()`, `$.datetime()`},
+		{`$.datetime("HH24:MI")`, `$.datetime("HH24:MI")`},
+		// Method names are keys unless they are followed by parentheses.
+		{`$.size`, `$."size"`},
+		{`$.last`, `$."last"`},
+
+		// Predicates and filters.
+		{`$.a > 1`, `($."a" > 1)`},
+		{`$ ? (@.a > 1 && @.b == "x")`, `$?(@."a" > 1 && @."b" == "x")`},
+		{`$ ? (@ > 1 || @ < 0 && @ != 5)`, `$?(@ > 1 || @ < 0 && @ != 5)`},
+		{`$ ? ((@ > 1 || @ < 0) && @ <> 5)`, `$?((@ > 1 || @ < 0) && @ != 5)`},
+		{`$.a ? (!(@ > 1))`, `$."a"?(!(@ > 1))`},
+		{`$ ? ((@ > 1) is unknown)`, `$?((@ > 1) is unknown)`},
+		{`exists($.a)`, `exists ($."a")`},
+		{`$ ? (exists (@.a ? (@ > 1)))`, `$?(exists (@."a"?(@ > 1)))`},
+		{`$ ? (@ starts with "a")`, `$?(@ starts with "a")`},
+		{`$ ? (@ starts with $prefix)`, `$?(@ starts with $"prefix")`},
+		{`$ ? (@ like_regex "^a")`, `$?(@ like_regex "^a")`},
+		{`$ ? (@ like_regex "^a" flag "i")`, `$?(@ like_regex "^a" flag "i")`},
+		{`$ ? (@.a + 1 >= $x * 2)`, `$?(@."a" + 1 >= $"x" * 2)`},
+		{`$.a[*] ? (@ > 1).abs()`, `$."a"[*]?(@ > 1).abs()`},
+		{`($.a + 1).abs()`, `($."a" + 1).abs()`},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			j, err := Parse(tc.input)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, j.String())
+
+			// The formatted path must parse to the same path.
+			j2, err := Parse(j.String())
+			require.NoError(t, err)
+			require.Equal(t, j.String(), j2.String())
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		err   string
+	}{
+		{``, `syntax error at end of jsonpath input`},
+		{`strict`, `syntax error at end of jsonpath input`},
+		{`$.a +`, `syntax error at end of jsonpath input`},
+		{`$.a )`, `syntax error at or near ")" of jsonpath input`},
+		{`$ ? (@ + 1)`, `syntax error at or near ")" of jsonpath input`},
+		{`$ > 1 > 2`, `syntax error at or near ">" of jsonpath input`},
+		{`($ > 1) + 1`, `syntax error at or near "+" of jsonpath input`},
+		{`!$.a`, `syntax error at end of jsonpath input`},
+		{`$. a`, `syntax error at or near "." of jsonpath input`},
+		{`$ # 1`, `syntax error at or near "# 1" of jsonpath input`},
+		{`"abc`, `unexpected end of quoted string`},
+		{`@`, `@ is not allowed in root expressions`},
+		{`last`, `LAST is allowed only in array subscripts`},
+		{`$ ? (@ like_regex "(")`, `invalid regular expression`},
+		{`$ ? (@ like_regex "a" flag "z")`, `unrecognized flag character`},
+		{`$ ? (@ like_regex "a" flag "x")`, `XQuery "x" flag (expanded regular expressions) is not implemented`},
+		{`$ ? (@ starts with 1)`, `syntax error at or near "1" of jsonpath input`},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := Parse(tc.input)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
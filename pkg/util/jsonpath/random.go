@@ -0,0 +1,59 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+var alphabet = "abcdefghijklmnopqrstuvwxyz"
+
+// RandomJsonpath returns a random Jsonpath made up of a chain of member and
+// array accessors, optionally followed by a filter on the current item.
+func RandomJsonpath(rng *rand.Rand) Jsonpath {
+	var sb strings.Builder
+	if rng.Intn(2) == 0 {
+		sb.WriteString("strict ")
+	}
+	sb.WriteString("$")
+	nSteps := rng.Intn(4)
+	for i := 0; i < nSteps; i++ {
+		switch rng.Intn(4) {
+		case 0:
+			sb.WriteString("[*]")
+		case 1:
+			sb.WriteString("[")
+			sb.WriteString(strconv.Itoa(rng.Intn(5)))
+			sb.WriteString("]")
+		default:
+			sb.WriteString(".")
+			sb.WriteString(randomKey(rng))
+		}
+	}
+	if rng.Intn(3) == 0 {
+		sb.WriteString(" ? (@.")
+		sb.WriteString(randomKey(rng))
+		sb.WriteString(" > ")
+		sb.WriteString(strconv.Itoa(rng.Intn(100)))
+		sb.WriteString(")")
+	}
+	return MustParse(sb.String())
+}
+
+func randomKey(rng *rand.Rand) string {
+	l := make([]byte, 1+rng.Intn(5))
+	for i := range l {
+		l[i] = alphabet[rng.Intn(len(alphabet))]
+	}
+	return string(l)
+}
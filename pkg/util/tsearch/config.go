@@ -10,13 +10,21 @@
 
 package tsearch
 
-import "strings"
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/blevesearch/snowballstem"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
 
 // ValidConfig returns an error if the input string is not a supported and valid
 // text search config.
 func ValidConfig(input string) error {
-	input = GetConfigKey(input)
-	_, err := getStemmer(input)
+	_, err := GetBuiltinConfig(input)
 	return err
 }
 
@@ -24,8 +32,315 @@ func ValidConfig(input string) error {
 // and stopwords from an input config value. This is simulating the more
 // advanced customizable dictionaries and configs that Postgres has, which
 // allows user-defined text search configurations: because of this, configs can
-// have schema prefixes. Because we don't (yet?) allow this, we just have to
-// trim off any `pg_catalog.` prefix if it exists.
+// have schema prefixes. The built-in configurations live in pg_catalog, so we
+// just have to trim off any `pg_catalog.` prefix if it exists.
 func GetConfigKey(config string) string {
 	return strings.TrimPrefix(config, "pg_catalog.")
 }
+
+// TokenType is the type of a token produced by the text search parser. The
+// token types and their IDs are the ones of the default parser of Postgres,
+// although our parser only produces tokens of the asciiword, word, numword and
+// uint types.
+type TokenType int
+
+const (
+	tokenAsciiWord TokenType = iota + 1
+	tokenWord
+	tokenNumWord
+	tokenAsciiHWord
+	tokenHWord
+	tokenNumHWord
+	tokenHWordAsciiPart
+	tokenHWordPart
+	tokenHWordNumPart
+	tokenEmail
+	tokenProtocol
+	tokenURL
+	tokenHost
+	tokenURLPath
+	tokenFile
+	tokenSFloat
+	tokenFloat
+	tokenInt
+	tokenUint
+	tokenVersion
+	tokenTag
+	tokenEntity
+	tokenBlank
+	numTokenTypes = iota + 1
+)
+
+var tokenTypeNames = [numTokenTypes]string{
+	"", "asciiword", "word", "numword", "asciihword", "hword", "numhword",
+	"hword_asciipart", "hword_part", "hword_numpart", "email", "protocol", "url",
+	"host", "url_path", "file", "sfloat", "float", "int", "uint", "version",
+	"tag", "entity", "blank",
+}
+
+func (t TokenType) String() string {
+	return tokenTypeNames[t]
+}
+
+// TokenTypes returns all the token types, ordered by ID.
+func TokenTypes() []TokenType {
+	ret := make([]TokenType, 0, numTokenTypes-1)
+	for t := tokenAsciiWord; t < numTokenTypes; t++ {
+		ret = append(ret, t)
+	}
+	return ret
+}
+
+// TokenTypeFromName returns the token type with the given name.
+func TokenTypeFromName(name string) (TokenType, error) {
+	for t := tokenAsciiWord; t < numTokenTypes; t++ {
+		if tokenTypeNames[t] == name {
+			return t, nil
+		}
+	}
+	return 0, pgerror.Newf(pgcode.InvalidParameterValue, "token type %q does not exist", name)
+}
+
+// getTokenType returns the type of a token produced by TSParse, which only
+// consists of letters and numbers.
+func getTokenType(token string) TokenType {
+	var letters, digits, nonASCII bool
+	for _, r := range token {
+		if unicode.IsLetter(r) {
+			letters = true
+		} else {
+			digits = true
+		}
+		if r >= utf8.RuneSelf {
+			nonASCII = true
+		}
+	}
+	switch {
+	case letters && digits:
+		return tokenNumWord
+	case digits && !nonASCII:
+		return tokenUint
+	case digits:
+		return tokenNumWord
+	case nonASCII:
+		return tokenWord
+	}
+	return tokenAsciiWord
+}
+
+// DictionaryTemplate is the template of a text search dictionary, which
+// determines how the dictionary normalizes tokens into lexemes.
+type DictionaryTemplate int
+
+const (
+	// SimpleTemplate is the template of dictionaries that lower-case tokens and
+	// discard the stop words.
+	SimpleTemplate DictionaryTemplate = iota
+	// SynonymTemplate is the template of dictionaries that replace words with
+	// their synonyms.
+	SynonymTemplate
+	// SnowballTemplate is the template of dictionaries that discard the stop
+	// words and reduce the other words to their stem with the snowball stemmer
+	// of a language.
+	SnowballTemplate
+)
+
+func (t DictionaryTemplate) String() string {
+	switch t {
+	case SimpleTemplate:
+		return "simple"
+	case SynonymTemplate:
+		return "synonym"
+	case SnowballTemplate:
+		return "snowball"
+	}
+	panic(errors.AssertionFailedf("unknown dictionary template %d", t))
+}
+
+// DictionaryTemplateFromName returns the dictionary template with the given
+// name.
+func DictionaryTemplateFromName(name string) (DictionaryTemplate, error) {
+	switch GetConfigKey(name) {
+	case "simple":
+		return SimpleTemplate, nil
+	case "synonym":
+		return SynonymTemplate, nil
+	case "snowball":
+		return SnowballTemplate, nil
+	case "ispell", "thesaurus":
+		return 0, pgerror.Newf(pgcode.FeatureNotSupported, "text search template %q is not supported", name)
+	}
+	return 0, pgerror.Newf(pgcode.UndefinedObject, "text search template %q does not exist", name)
+}
+
+// Dictionary is a text search dictionary, which normalizes tokens into
+// lexemes.
+type Dictionary struct {
+	Name     string
+	Template DictionaryTemplate
+	// Language is the language of the stemmer of a snowball dictionary.
+	Language string
+	// StopWords are the lower-cased words that are recognized as stop words by
+	// simple and snowball dictionaries.
+	StopWords map[string]struct{}
+	// Synonyms maps lower-cased words to their synonyms in a synonym
+	// dictionary.
+	Synonyms map[string]string
+	// Accept is set if a simple dictionary recognizes the words that are not
+	// stop words. Otherwise, these words are passed on to the next dictionary.
+	Accept bool
+}
+
+// Lexize normalizes a token into a lexeme. It returns false if the dictionary
+// doesn't recognize the token, in which case the token is passed on to the
+// next dictionary. The lexeme of a stop word is empty.
+func (d *Dictionary) Lexize(token string) (lexeme string, ok bool, err error) {
+	lower := strings.ToLower(token)
+	switch d.Template {
+	case SimpleTemplate:
+		if _, ok := d.StopWords[lower]; ok {
+			return "", true, nil
+		}
+		if !d.Accept {
+			return "", false, nil
+		}
+		return lower, true, nil
+	case SynonymTemplate:
+		synonym, ok := d.Synonyms[lower]
+		return synonym, ok, nil
+	case SnowballTemplate:
+		if _, ok := d.StopWords[lower]; ok {
+			return "", true, nil
+		}
+		stemmer, err := getStemmer(d.Language)
+		if err != nil {
+			return "", false, err
+		}
+		env := snowballstem.NewEnv(lower)
+		stemmer(env)
+		return env.Current(), true, nil
+	}
+	return "", false, errors.AssertionFailedf("unknown dictionary template %d", d.Template)
+}
+
+// Config is a text search configuration. It maps each token type to the
+// dictionaries that normalize the tokens of that type into lexemes: the
+// dictionaries are consulted in order, and the first one that recognizes a
+// token determines its lexeme. Tokens that are not recognized by any
+// dictionary are ignored.
+type Config struct {
+	Name     string
+	Mappings map[TokenType][]*Dictionary
+}
+
+// lexize normalizes a token into a lexeme. It returns false if no dictionary
+// recognizes the token. The lexeme of a stop word is empty.
+func (c *Config) lexize(token string) (lexeme string, ok bool, err error) {
+	for _, d := range c.Mappings[getTokenType(token)] {
+		lexeme, ok, err = d.Lexize(token)
+		if err != nil || ok {
+			return lexeme, ok, err
+		}
+	}
+	return "", false, nil
+}
+
+var (
+	builtinDictionaries map[string]*Dictionary
+	builtinConfigs      map[string]*Config
+)
+
+// initBuiltinConfigs initializes the built-in text search configurations and
+// dictionaries: the simple configuration and dictionary, which lower-case all
+// tokens, and for each snowball language, a configuration named after the
+// language which uses the <language>_stem dictionary. It must be called after
+// the stop words are loaded.
+func initBuiltinConfigs() {
+	simple := &Dictionary{Name: "simple", Template: SimpleTemplate, Accept: true}
+	builtinDictionaries = map[string]*Dictionary{simple.Name: simple}
+	builtinConfigs = map[string]*Config{"simple": makeBuiltinConfig("simple", simple, simple)}
+	for _, language := range snowballLanguages {
+		stem := &Dictionary{
+			Name:      language + "_stem",
+			Template:  SnowballTemplate,
+			Language:  language,
+			StopWords: stopwordsMap[language],
+		}
+		builtinDictionaries[stem.Name] = stem
+		builtinConfigs[language] = makeBuiltinConfig(language, stem, simple)
+	}
+}
+
+// makeBuiltinConfig returns a configuration that maps the words to the given
+// dictionary, and the numbers, URLs and the like to the simple dictionary, like
+// the built-in configurations of Postgres. Unlike in Postgres, words with
+// digits are also mapped to the word dictionary, since our parser doesn't
+// distinguish them from the other words.
+func makeBuiltinConfig(name string, words, simple *Dictionary) *Config {
+	c := &Config{Name: name, Mappings: make(map[TokenType][]*Dictionary)}
+	for _, t := range []TokenType{
+		tokenAsciiWord, tokenWord, tokenNumWord, tokenAsciiHWord, tokenHWord,
+		tokenNumHWord, tokenHWordAsciiPart, tokenHWordPart, tokenHWordNumPart,
+	} {
+		c.Mappings[t] = []*Dictionary{words}
+	}
+	for _, t := range []TokenType{
+		tokenEmail, tokenURL, tokenHost, tokenURLPath, tokenFile, tokenSFloat,
+		tokenFloat, tokenInt, tokenUint, tokenVersion,
+	} {
+		c.Mappings[t] = []*Dictionary{simple}
+	}
+	return c
+}
+
+// GetBuiltinConfig returns the built-in text search configuration with the
+// given name, which may be prefixed with pg_catalog.
+func GetBuiltinConfig(name string) (*Config, error) {
+	if c, ok := builtinConfigs[GetConfigKey(name)]; ok {
+		return c, nil
+	}
+	return nil, pgerror.Newf(pgcode.UndefinedObject, "text search configuration %q does not exist", name)
+}
+
+// IsBuiltinConfig returns whether the given name is the name of a built-in
+// text search configuration.
+func IsBuiltinConfig(name string) bool {
+	_, ok := builtinConfigs[GetConfigKey(name)]
+	return ok
+}
+
+// GetBuiltinDictionary returns the built-in text search dictionary with the
+// given name, which may be prefixed with pg_catalog.
+func GetBuiltinDictionary(name string) (*Dictionary, error) {
+	if d, ok := builtinDictionaries[GetConfigKey(name)]; ok {
+		return d, nil
+	}
+	return nil, pgerror.Newf(pgcode.UndefinedObject, "text search dictionary %q does not exist", name)
+}
+
+// IsBuiltinDictionary returns whether the given name is the name of a built-in
+// text search dictionary.
+func IsBuiltinDictionary(name string) bool {
+	_, ok := builtinDictionaries[GetConfigKey(name)]
+	return ok
+}
+
+// GetStopWords returns the built-in list of stop words with the given name,
+// which is the name of a language.
+func GetStopWords(name string) (map[string]struct{}, error) {
+	if stopwords, ok := stopwordsMap[name]; ok && name != "simple" {
+		return stopwords, nil
+	}
+	return nil, pgerror.Newf(pgcode.UndefinedObject, "stop word list %q does not exist", name)
+}
+
+// IsSnowballLanguage returns whether there is a snowball stemmer for the given
+// language.
+func IsSnowballLanguage(language string) bool {
+	for _, l := range snowballLanguages {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
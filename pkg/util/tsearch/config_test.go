@@ -0,0 +1,140 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDictionaryLexize(t *testing.T) {
+	english, err := GetBuiltinDictionary("english_stem")
+	require.NoError(t, err)
+	simple, err := GetBuiltinDictionary("pg_catalog.simple")
+	require.NoError(t, err)
+	stopWords := &Dictionary{
+		Name:      "stop",
+		Template:  SimpleTemplate,
+		StopWords: map[string]struct{}{"foo": {}},
+	}
+	synonyms := &Dictionary{
+		Name:     "syn",
+		Template: SynonymTemplate,
+		Synonyms: map[string]string{"postgres": "pgsql", "cockroach": "crdb"},
+	}
+	for _, tc := range []struct {
+		d      *Dictionary
+		token  string
+		lexeme string
+		ok     bool
+	}{
+		{english, "Running", "run", true},
+		{english, "the", "", true},
+		{simple, "The", "the", true},
+		{stopWords, "FOO", "", true},
+		{stopWords, "bar", "", false},
+		{synonyms, "Postgres", "pgsql", true},
+		{synonyms, "mysql", "", false},
+	} {
+		lexeme, ok, err := tc.d.Lexize(tc.token)
+		require.NoError(t, err)
+		assert.Equal(t, tc.lexeme, lexeme, tc.token)
+		assert.Equal(t, tc.ok, ok, tc.token)
+	}
+
+	_, err = GetBuiltinDictionary("klingon_stem")
+	assert.EqualError(t, err, `text search dictionary "klingon_stem" does not exist`)
+	_, err = GetBuiltinConfig("klingon")
+	assert.EqualError(t, err, `text search configuration "klingon" does not exist`)
+}
+
+func TestConfigDocumentToTSVector(t *testing.T) {
+	english, err := GetBuiltinDictionary("english_stem")
+	require.NoError(t, err)
+	c := &Config{
+		Name: "custom",
+		Mappings: map[TokenType][]*Dictionary{
+			tokenAsciiWord: {
+				{Name: "syn", Template: SynonymTemplate, Synonyms: map[string]string{"databases": "db"}},
+				english,
+			},
+		},
+	}
+	for _, tc := range []struct {
+		input    string
+		expected string
+	}{
+		// Numbers aren't mapped to any dictionary, so they are ignored.
+		{"The 2 best databases are running", "'best':2 'db':3 'run':5"},
+		{"", ""},
+	} {
+		v, err := c.DocumentToTSVector(tc.input)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, v.String(), tc.input)
+	}
+
+	v, err := c.DocumentsToTSVector([]string{"databases", "the", "best running"})
+	require.NoError(t, err)
+	assert.Equal(t, "'best':5 'db':1 'run':6", v.String())
+}
+
+func TestTokenTypes(t *testing.T) {
+	types := TokenTypes()
+	require.Len(t, types, 23)
+	for i, tt := range types {
+		actual, err := TokenTypeFromName(tt.String())
+		require.NoError(t, err)
+		assert.Equal(t, tt, actual)
+		assert.Equal(t, TokenType(i+1), tt)
+	}
+	_, err := TokenTypeFromName("foo")
+	assert.EqualError(t, err, `token type "foo" does not exist`)
+
+	for _, tc := range []struct {
+		token    string
+		expected string
+	}{
+		{"foo", "asciiword"},
+		{"föo", "word"},
+		{"foo1", "numword"},
+		{"123", "uint"},
+		{"١٢٣", "numword"},
+	} {
+		assert.Equal(t, tc.expected, getTokenType(tc.token).String(), tc.token)
+	}
+}
+
+func TestTSVectorSetWeightAndStrip(t *testing.T) {
+	v, err := ParseTSVector("a:1 b:2,3C c")
+	require.NoError(t, err)
+	w, err := v.SetWeight("a", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "'a':1A 'b':2A,3A 'c'", w.String())
+	w, err = v.SetWeight("B", []string{"b", "c", "d"})
+	require.NoError(t, err)
+	assert.Equal(t, "'a':1 'b':2B,3B 'c'", w.String())
+	w, err = w.SetWeight("d", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "'a':1 'b':2,3 'c'", w.String())
+	_, err = v.SetWeight("e", nil)
+	assert.EqualError(t, err, `unrecognized weight: "e"`)
+	assert.Equal(t, "'a' 'b' 'c'", v.Strip().String())
+	// The receiver is not modified.
+	assert.Equal(t, "'a':1 'b':2,3C 'c'", v.String())
+
+	v, err = ArrayToTSVector([]string{"b", "a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, "'a' 'b'", v.String())
+	_, err = ArrayToTSVector([]string{"a", ""})
+	assert.EqualError(t, err, "lexeme array may not contain empty strings")
+}
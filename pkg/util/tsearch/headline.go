@@ -0,0 +1,496 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// HeadlineOptions are the options of ts_headline, which control how a
+// document is shortened and how its matches are highlighted.
+type HeadlineOptions struct {
+	// MaxWords and MinWords bound the number of words of the headline, or of
+	// each fragment if MaxFragments is positive.
+	MaxWords int
+	MinWords int
+	// ShortWord is the length of the words that are dropped at the end of a
+	// headline, unless they match the query.
+	ShortWord int
+	// HighlightAll, if set, makes the headline the whole document, ignoring the
+	// other options that bound its length.
+	HighlightAll bool
+	// MaxFragments, if positive, makes the headline consist of up to that many
+	// fragments of the document separated by FragmentDelimiter.
+	MaxFragments int
+	// StartSel and StopSel delimit the words that match the query.
+	StartSel          string
+	StopSel           string
+	FragmentDelimiter string
+}
+
+// DefaultHeadlineOptions returns the default options of ts_headline.
+func DefaultHeadlineOptions() HeadlineOptions {
+	return HeadlineOptions{
+		MaxWords:          35,
+		MinWords:          15,
+		ShortWord:         3,
+		StartSel:          "<b>",
+		StopSel:           "</b>",
+		FragmentDelimiter: " ... ",
+	}
+}
+
+// ParseHeadlineOptions parses the options argument of ts_headline, which is a
+// comma-separated list of option=value pairs, like in
+// "MaxWords=10, StartSel=<em>, StopSel=</em>". Values containing spaces or
+// commas can be double-quoted. Unspecified options have their default value.
+func ParseHeadlineOptions(input string) (HeadlineOptions, error) {
+	opts := DefaultHeadlineOptions()
+	p := headlineOptionsParser{input: input}
+	for {
+		key, value, ok, err := p.next()
+		if err != nil {
+			return opts, err
+		}
+		if !ok {
+			break
+		}
+		var intVal *int
+		switch strings.ToLower(key) {
+		case "maxwords":
+			intVal = &opts.MaxWords
+		case "minwords":
+			intVal = &opts.MinWords
+		case "shortword":
+			intVal = &opts.ShortWord
+		case "maxfragments":
+			intVal = &opts.MaxFragments
+		case "startsel":
+			opts.StartSel = value
+		case "stopsel":
+			opts.StopSel = value
+		case "fragmentdelimiter":
+			opts.FragmentDelimiter = value
+		case "highlightall":
+			switch strings.ToLower(value) {
+			case "true", "t", "yes", "y", "on", "1":
+				opts.HighlightAll = true
+			case "false", "f", "no", "n", "off", "0":
+				opts.HighlightAll = false
+			default:
+				return opts, pgerror.Newf(pgcode.InvalidParameterValue,
+					"invalid value for parameter %q: %q", key, value)
+			}
+		default:
+			return opts, pgerror.Newf(pgcode.InvalidParameterValue,
+				"unrecognized headline parameter: %q", key)
+		}
+		if intVal != nil {
+			v, err := strconv.Atoi(value)
+			if err != nil {
+				return opts, pgerror.Newf(pgcode.InvalidTextRepresentation,
+					"invalid input syntax for type integer: %q", value)
+			}
+			*intVal = v
+		}
+	}
+	if !opts.HighlightAll {
+		if opts.MinWords >= opts.MaxWords {
+			return opts, pgerror.New(pgcode.InvalidParameterValue, "MinWords should be less than MaxWords")
+		}
+		if opts.MinWords <= 0 {
+			return opts, pgerror.New(pgcode.InvalidParameterValue, "MinWords should be positive")
+		}
+		if opts.ShortWord < 0 {
+			return opts, pgerror.New(pgcode.InvalidParameterValue, "ShortWord should be >= 0")
+		}
+		if opts.MaxFragments < 0 {
+			return opts, pgerror.New(pgcode.InvalidParameterValue, "MaxFragments should be >= 0")
+		}
+	}
+	return opts, nil
+}
+
+type headlineOptionsParser struct {
+	input string
+	pos   int
+}
+
+func (p *headlineOptionsParser) skip(f func(r rune) bool) {
+	for p.pos < len(p.input) {
+		r, size := utf8.DecodeRuneInString(p.input[p.pos:])
+		if !f(r) {
+			return
+		}
+		p.pos += size
+	}
+}
+
+// next returns the next option=value pair, or false if there are none left.
+func (p *headlineOptionsParser) next() (key, value string, ok bool, err error) {
+	p.skip(func(r rune) bool { return unicode.IsSpace(r) || r == ',' })
+	if p.pos == len(p.input) {
+		return "", "", false, nil
+	}
+	start := p.pos
+	p.skip(func(r rune) bool { return !unicode.IsSpace(r) && r != '=' && r != ',' })
+	key = p.input[start:p.pos]
+	p.skip(unicode.IsSpace)
+	if key == "" || p.pos == len(p.input) || p.input[p.pos] != '=' {
+		return "", "", false, pgerror.Newf(pgcode.Syntax,
+			"invalid headline options: %q", p.input)
+	}
+	p.pos++
+	p.skip(unicode.IsSpace)
+	if p.pos < len(p.input) && p.input[p.pos] == '"' {
+		end := strings.IndexByte(p.input[p.pos+1:], '"')
+		if end < 0 {
+			return "", "", false, pgerror.Newf(pgcode.Syntax,
+				"unterminated quoted value in headline options: %q", p.input)
+		}
+		value = p.input[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return key, value, true, nil
+	}
+	start = p.pos
+	p.skip(func(r rune) bool { return !unicode.IsSpace(r) && r != ',' })
+	return key, p.input[start:p.pos], true, nil
+}
+
+// headlineWord is a token of the document of a headline.
+type headlineWord struct {
+	// start and end are the byte offsets of the token in the document.
+	start, end int
+	// lexeme is the normalized token, which is empty for stop words and the
+	// tokens that are not recognized by any dictionary.
+	lexeme string
+	// pos is the position of the lexeme, like in the result of to_tsvector.
+	pos int
+	// match is set if the lexeme matches one of the lexemes of the query.
+	match bool
+}
+
+// Headline implements the ts_headline builtin, which returns an excerpt of
+// the document in which the words that match the query are highlighted. The
+// words are normalized according to the receiver.
+//
+// Like in Postgres, the excerpt is built around the shortest parts of the
+// document that match the query, called covers: unless MaxFragments is
+// positive, the cover with the most matches is extended with the surrounding
+// words up to MinWords, or truncated to MaxWords. Otherwise, the covers with the
+// most matches are extended to MaxWords and concatenated. If the query doesn't
+// match the document, the excerpt is its first MinWords words.
+func (c *Config) Headline(doc string, q TSQuery, opts HeadlineOptions) (string, error) {
+	words, err := c.headlineWords(doc, q)
+	if err != nil {
+		return "", err
+	}
+	if len(words) == 0 {
+		return doc, nil
+	}
+	h := headliner{doc: doc, words: words, opts: opts}
+	if opts.HighlightAll {
+		return h.generate([][2]int{{0, len(words) - 1}}), nil
+	}
+	covers, err := h.covers(q)
+	if err != nil {
+		return "", err
+	}
+	var fragments [][2]int
+	if opts.MaxFragments > 0 {
+		fragments = h.selectFragments(covers)
+	} else if len(covers) > 0 {
+		fragments = [][2]int{h.selectCover(covers)}
+	}
+	if len(fragments) == 0 {
+		end := opts.MinWords - 1
+		if end >= len(words) {
+			end = len(words) - 1
+		}
+		fragments = [][2]int{{0, end}}
+	}
+	return h.generate(fragments), nil
+}
+
+// headlineWords tokenizes the document of a headline, and marks the tokens
+// that match the lexemes of the query.
+func (c *Config) headlineWords(doc string, q TSQuery) ([]headlineWord, error) {
+	var queryTerms []tsTerm
+	var collect func(n *tsNode)
+	collect = func(n *tsNode) {
+		if n == nil {
+			return
+		}
+		if n.op == invalid {
+			queryTerms = append(queryTerms, n.term)
+			return
+		}
+		collect(n.l)
+		collect(n.r)
+	}
+	collect(q.root)
+
+	var words []headlineWord
+	pos := 0
+	start := -1
+	for i := 0; i <= len(doc); {
+		var r rune
+		size := 1
+		if i < len(doc) {
+			r, size = utf8.DecodeRuneInString(doc[i:])
+		}
+		if i < len(doc) && unicode.IsOneOf(validCharTables, r) {
+			if start < 0 {
+				start = i
+			}
+			i += size
+			continue
+		}
+		if start >= 0 {
+			w := headlineWord{start: start, end: i}
+			lexeme, ok, err := c.lexize(doc[start:i])
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				pos++
+				w.lexeme = lexeme
+				w.pos = pos
+			}
+			if w.lexeme != "" {
+				for _, t := range queryTerms {
+					if t.lexeme == w.lexeme || (t.isPrefixMatch() && strings.HasPrefix(w.lexeme, t.lexeme)) {
+						w.match = true
+						break
+					}
+				}
+			}
+			words = append(words, w)
+			start = -1
+		}
+		i += size
+	}
+	return words, nil
+}
+
+type headliner struct {
+	doc   string
+	words []headlineWord
+	opts  HeadlineOptions
+}
+
+// matches returns whether the words between the given indexes, inclusive,
+// match the query.
+func (h *headliner) matches(q TSQuery, start, end int) (bool, error) {
+	var v TSVector
+	for _, w := range h.words[start : end+1] {
+		if w.lexeme == "" {
+			continue
+		}
+		pos := w.pos
+		if pos > maxTSVectorPosition {
+			pos = maxTSVectorPosition
+		}
+		v = append(v, tsTerm{lexeme: w.lexeme, positions: []tsPosition{{position: uint16(pos)}}})
+	}
+	v, err := normalizeTSVector(v)
+	if err != nil {
+		return false, err
+	}
+	return EvalTSQuery(q, v)
+}
+
+// covers returns the covers of the query in the document, which are the
+// shortest ranges of words that match the query, ordered by position. Each
+// cover starts with and ends with words that match the query.
+func (h *headliner) covers(q TSQuery) ([][2]int, error) {
+	if q.root == nil {
+		return nil, nil
+	}
+	var matching []int
+	for i, w := range h.words {
+		if w.match {
+			matching = append(matching, i)
+		}
+	}
+	var covers [][2]int
+	for s := 0; s < len(matching); s++ {
+		// Find the first word that ends a cover starting at or after s.
+		e := -1
+		for j := s; j < len(matching); j++ {
+			ok, err := h.matches(q, matching[s], matching[j])
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				e = j
+				break
+			}
+		}
+		if e < 0 {
+			break
+		}
+		// Find the last word that starts a cover ending at e.
+		for j := e; j >= s; j-- {
+			ok, err := h.matches(q, matching[j], matching[e])
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				s = j
+				break
+			}
+		}
+		covers = append(covers, [2]int{matching[s], matching[e]})
+	}
+	return covers, nil
+}
+
+// numMatches returns the number of words between the given indexes, inclusive,
+// that match the query.
+func (h *headliner) numMatches(start, end int) int {
+	n := 0
+	for _, w := range h.words[start : end+1] {
+		if w.match {
+			n++
+		}
+	}
+	return n
+}
+
+// isShort returns whether the given word is a short word that should not end
+// a headline.
+func (h *headliner) isShort(i int) bool {
+	w := h.words[i]
+	return !w.match && utf8.RuneCountInString(h.doc[w.start:w.end]) <= h.opts.ShortWord
+}
+
+// selectCover returns the range of words of a headline without fragments,
+// which is the cover with the most matches extended to MinWords, or truncated
+// to MaxWords.
+func (h *headliner) selectCover(covers [][2]int) [2]int {
+	best, bestMatches := [2]int{}, -1
+	for _, cover := range covers {
+		start, end := cover[0], cover[1]
+		if end-start+1 > h.opts.MaxWords {
+			end = start + h.opts.MaxWords - 1
+			for end-start+1 > h.opts.MinWords && h.isShort(end) {
+				end--
+			}
+		} else {
+			for end-start+1 < h.opts.MinWords && end < len(h.words)-1 {
+				end++
+			}
+			for end-start+1 < h.opts.MinWords && start > 0 {
+				start--
+			}
+		}
+		// Prefer the ranges with the most matches, and then the ones that don't
+		// end with a short word.
+		n := h.numMatches(start, end)
+		if bestMatches < 0 || n > bestMatches || (h.isShort(best[1]) && !h.isShort(end)) {
+			best, bestMatches = [2]int{start, end}, n
+		}
+	}
+	return best
+}
+
+// selectFragments returns the ranges of words of a headline with fragments,
+// which are the non-overlapping covers with the most matches, extended to
+// MaxWords with the surrounding words. Covers longer than MaxWords are split.
+func (h *headliner) selectFragments(covers [][2]int) [][2]int {
+	var candidates [][2]int
+	for _, cover := range covers {
+		for start := cover[0]; start <= cover[1]; start += h.opts.MaxWords {
+			end := start + h.opts.MaxWords - 1
+			if end > cover[1] {
+				end = cover[1]
+			}
+			candidates = append(candidates, [2]int{start, end})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return h.numMatches(candidates[i][0], candidates[i][1]) >
+			h.numMatches(candidates[j][0], candidates[j][1])
+	})
+	used := make([]bool, len(h.words))
+	var fragments [][2]int
+	for _, c := range candidates {
+		if len(fragments) == h.opts.MaxFragments {
+			break
+		}
+		overlaps := false
+		for i := c[0]; i <= c[1]; i++ {
+			overlaps = overlaps || used[i]
+		}
+		if overlaps {
+			continue
+		}
+		// Extend the fragment evenly on both sides, without overlapping the
+		// other fragments.
+		start, end := c[0], c[1]
+		stretch := (h.opts.MaxWords - (end - start + 1)) / 2
+		for ; stretch > 0 && start > 0 && !used[start-1]; stretch-- {
+			start--
+		}
+		for end-start+1 < h.opts.MaxWords && end < len(h.words)-1 && !used[end+1] {
+			end++
+		}
+		for end > c[1] && h.isShort(end) {
+			end--
+		}
+		for i := start; i <= end; i++ {
+			used[i] = true
+		}
+		fragments = append(fragments, [2]int{start, end})
+	}
+	sort.Slice(fragments, func(i, j int) bool { return fragments[i][0] < fragments[j][0] })
+	return fragments
+}
+
+// generate returns the headline consisting of the given ranges of words, which
+// must be ordered and must not overlap. A range that starts with the first
+// word or ends with the last word of the document also includes the
+// punctuation before or after it.
+func (h *headliner) generate(fragments [][2]int) string {
+	var buf strings.Builder
+	for i, f := range fragments {
+		if i > 0 {
+			buf.WriteString(h.opts.FragmentDelimiter)
+		}
+		pos := h.words[f[0]].start
+		if f[0] == 0 {
+			pos = 0
+		}
+		for _, w := range h.words[f[0] : f[1]+1] {
+			buf.WriteString(h.doc[pos:w.start])
+			if w.match {
+				buf.WriteString(h.opts.StartSel)
+				buf.WriteString(h.doc[w.start:w.end])
+				buf.WriteString(h.opts.StopSel)
+			} else {
+				buf.WriteString(h.doc[w.start:w.end])
+			}
+			pos = w.end
+		}
+		if f[1] == len(h.words)-1 {
+			buf.WriteString(h.doc[pos:])
+		}
+	}
+	return buf.String()
+}
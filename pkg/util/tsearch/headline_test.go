@@ -0,0 +1,113 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeadline(t *testing.T) {
+	const doc = `The most common type of search is to find all documents containing ` +
+		`given query terms and return them in order of their similarity to the query.`
+	tcs := []struct {
+		doc      string
+		query    string
+		opts     string
+		expected string
+	}{
+		{
+			doc:   doc,
+			query: "query & similarity",
+			expected: `containing given <b>query</b> terms and return them in order of ` +
+				`their <b>similarity</b> to the <b>query</b>.`,
+		},
+		{
+			doc:      doc,
+			query:    "query & similarity",
+			opts:     "MaxWords=5, MinWords=2",
+			expected: `<b>similarity</b> to the <b>query</b>.`,
+		},
+		{
+			doc:   doc,
+			query: "search & term",
+			opts:  "MaxFragments=10, MaxWords=7, MinWords=3, StartSel=<<, StopSel=>>",
+			expected: `<<search>> is to find all documents containing ... ` +
+				`given query <<terms>> and return them`,
+		},
+		{
+			doc:      doc,
+			query:    "search <-> query",
+			opts:     "MaxWords=5, MinWords=3",
+			expected: `The most common`,
+		},
+		{
+			doc:      "Fat cats ate fat rats.",
+			query:    "fat:*",
+			opts:     `HighlightAll=true, StartSel="[", StopSel="]"`,
+			expected: "[Fat] cats ate [fat] rats.",
+		},
+		{
+			doc:      "Fat cats ate fat rats.",
+			query:    "rat <-> !cat",
+			expected: "Fat <b>cats</b> ate fat <b>rats</b>.",
+		},
+		{
+			doc:      "",
+			query:    "rat",
+			expected: "",
+		},
+	}
+	c, err := GetBuiltinConfig("english")
+	require.NoError(t, err)
+	for _, tc := range tcs {
+		q, err := c.ToTSQuery(tc.query)
+		require.NoError(t, err)
+		opts, err := ParseHeadlineOptions(tc.opts)
+		require.NoError(t, err)
+		actual, err := c.Headline(tc.doc, q, opts)
+		require.NoError(t, err)
+		assert.Equalf(t, tc.expected, actual, "Headline(%q, %q, %q)", tc.doc, tc.query, tc.opts)
+	}
+}
+
+func TestParseHeadlineOptions(t *testing.T) {
+	opts, err := ParseHeadlineOptions(
+		` maxwords = 10,MinWords=1 ShortWord=0, FragmentDelimiter=" , ", StartSel=<em>`)
+	require.NoError(t, err)
+	expected := DefaultHeadlineOptions()
+	expected.MaxWords = 10
+	expected.MinWords = 1
+	expected.ShortWord = 0
+	expected.FragmentDelimiter = " , "
+	expected.StartSel = "<em>"
+	assert.Equal(t, expected, opts)
+
+	for _, tc := range []struct {
+		input    string
+		expected string
+	}{
+		{`MaxWords=10, MinWords=10`, "MinWords should be less than MaxWords"},
+		{`MinWords=0`, "MinWords should be positive"},
+		{`ShortWord=-1`, "ShortWord should be >= 0"},
+		{`MaxFragments=-1`, "MaxFragments should be >= 0"},
+		{`MaxWords=a`, `invalid input syntax for type integer: "a"`},
+		{`HighlightAll=maybe`, `invalid value for parameter "HighlightAll": "maybe"`},
+		{`Foo=1`, `unrecognized headline parameter: "Foo"`},
+		{`MaxWords`, `invalid headline options: "MaxWords"`},
+		{`StartSel="<em>`, `unterminated quoted value in headline options: "StartSel=\"<em>"`},
+	} {
+		_, err := ParseHeadlineOptions(tc.input)
+		assert.EqualError(t, err, tc.expected, tc.input)
+	}
+}
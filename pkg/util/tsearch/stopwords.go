@@ -45,4 +45,5 @@ func init() {
 	}
 	// The simple text search config has no stopwords.
 	stopwordsMap["simple"] = nil
+	initBuiltinConfigs()
 }
@@ -13,6 +13,8 @@ package tsearch
 import (
 	"fmt"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/cockroachdb/cockroach/pkg/keysbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
@@ -416,28 +418,58 @@ func (p *tsQueryParser) syntaxError() (*tsNode, error) {
 // ToTSQuery implements the to_tsquery builtin, which lexes an input, performs
 // stopwording and normalization on the tokens, and returns a parsed query.
 func ToTSQuery(config string, input string) (TSQuery, error) {
-	return toTSQuery(config, invalid, input)
+	c, err := GetBuiltinConfig(config)
+	if err != nil {
+		return TSQuery{}, err
+	}
+	return c.ToTSQuery(input)
 }
 
 // PlainToTSQuery implements the plainto_tsquery builtin, which lexes an input,
 // performs stopwording and normalization on the tokens, and returns a parsed
 // query, interposing the & operator between each token.
 func PlainToTSQuery(config string, input string) (TSQuery, error) {
-	return toTSQuery(config, and, input)
+	c, err := GetBuiltinConfig(config)
+	if err != nil {
+		return TSQuery{}, err
+	}
+	return c.PlainToTSQuery(input)
 }
 
 // PhraseToTSQuery implements the phraseto_tsquery builtin, which lexes an input,
 // performs stopwording and normalization on the tokens, and returns a parsed
 // query, interposing the <-> operator between each token.
 func PhraseToTSQuery(config string, input string) (TSQuery, error) {
-	return toTSQuery(config, followedby, input)
+	c, err := GetBuiltinConfig(config)
+	if err != nil {
+		return TSQuery{}, err
+	}
+	return c.PhraseToTSQuery(input)
+}
+
+// ToTSQuery is like the ToTSQuery function, but normalizes the tokens
+// according to the receiver.
+func (c *Config) ToTSQuery(input string) (TSQuery, error) {
+	return c.toTSQuery(invalid, input)
+}
+
+// PlainToTSQuery is like the PlainToTSQuery function, but normalizes the
+// tokens according to the receiver.
+func (c *Config) PlainToTSQuery(input string) (TSQuery, error) {
+	return c.toTSQuery(and, input)
+}
+
+// PhraseToTSQuery is like the PhraseToTSQuery function, but normalizes the
+// tokens according to the receiver.
+func (c *Config) PhraseToTSQuery(input string) (TSQuery, error) {
+	return c.toTSQuery(followedby, input)
 }
 
 // toTSQuery implements the to_tsquery builtin, which lexes an input,
 // performs stopwording and normalization on the tokens, and returns a parsed
 // query. If the interpose operator is not invalid, it's interposed between each
 // token in the input.
-func toTSQuery(config string, interpose tsOperator, input string) (TSQuery, error) {
+func (c *Config) toTSQuery(interpose tsOperator, input string) (TSQuery, error) {
 	vector, err := lexTSQuery(input)
 	if err != nil {
 		return TSQuery{}, err
@@ -499,11 +531,11 @@ func toTSQuery(config string, interpose tsOperator, input string) (TSQuery, erro
 				}
 				tokens = append(tokens, term)
 			}
-			lexeme, stopWord, err := TSLexize(config, lexemeTokens[j])
+			lexeme, err := c.lexizeQueryToken(lexemeTokens[j])
 			if err != nil {
 				return TSQuery{}, err
 			}
-			if stopWord {
+			if lexeme == "" {
 				foundStopwords = true
 			}
 			tokens = append(tokens, tsTerm{lexeme: lexeme, positions: tok.positions})
@@ -526,6 +558,209 @@ func toTSQuery(config string, interpose tsOperator, input string) (TSQuery, erro
 	return query, err
 }
 
+// lexizeQueryToken normalizes a token of a query. Like stop words, the tokens
+// that aren't recognized by any dictionary have an empty lexeme, which is
+// removed from the query by cleanupStopwords.
+func (c *Config) lexizeQueryToken(token string) (string, error) {
+	lexeme, ok, err := c.lexize(token)
+	if err != nil || !ok {
+		return "", err
+	}
+	return lexeme, nil
+}
+
+// WebSearchToTSQuery implements the websearch_to_tsquery builtin, which
+// converts a query written in the syntax of web search engines into a TSQuery.
+// The words of the input are normalized according to the receiver, and
+// combined with the & operator, except that:
+//   - the words in double quotes are combined with the <-> operator,
+//   - the "or" word combines the words or quoted phrases on its sides with the
+//     | operator,
+//   - a dash before a word or quoted phrase negates it with the ! operator.
+//
+// All other punctuation is ignored, so this never returns a syntax error.
+func (c *Config) WebSearchToTSQuery(input string) (TSQuery, error) {
+	items := parseWebSearch(input)
+	var tokens TSVector
+	foundStopwords := false
+	needOp := false
+	pendingOr := false
+	for _, item := range items {
+		if item.or {
+			pendingOr = true
+			continue
+		}
+		if needOp {
+			op := and
+			if pendingOr {
+				op = or
+			}
+			tokens = append(tokens, tsTerm{operator: op})
+		}
+		pendingOr = false
+		if item.negated {
+			tokens = append(tokens, tsTerm{operator: not})
+		}
+		tokens = append(tokens, tsTerm{operator: lparen})
+		for i, word := range item.words {
+			if i > 0 {
+				tokens = append(tokens, tsTerm{operator: followedby, followedN: 1})
+			}
+			lexeme, err := c.lexizeQueryToken(word)
+			if err != nil {
+				return TSQuery{}, err
+			}
+			if lexeme == "" {
+				foundStopwords = true
+			}
+			tokens = append(tokens, tsTerm{lexeme: lexeme})
+		}
+		tokens = append(tokens, tsTerm{operator: rparen})
+		needOp = true
+	}
+
+	queryParser := tsQueryParser{terms: tokens, input: input}
+	query, err := queryParser.parse()
+	if err != nil {
+		return query, err
+	}
+	if foundStopwords {
+		query = cleanupStopwords(query)
+		if query.root == nil {
+			return query, pgerror.Newf(pgcode.Syntax, "text-search query doesn't contain lexemes: %s", input)
+		}
+	}
+	return query, nil
+}
+
+// webSearchItem is a word, a quoted phrase or the "or" operator in the input of
+// websearch_to_tsquery.
+type webSearchItem struct {
+	// words are the tokens of the word or phrase.
+	words   []string
+	negated bool
+	or      bool
+}
+
+// parseWebSearch splits the input of websearch_to_tsquery into words, quoted
+// phrases and "or" operators. The words and phrases without tokens are
+// omitted, and so are the "or" operators that are not between two words or
+// phrases.
+func parseWebSearch(input string) []webSearchItem {
+	var items []webSearchItem
+	negated := false
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+			negated = false
+			continue
+		case r == '-':
+			i += size
+			negated = true
+			continue
+		case r == '"':
+			text := input[i+size:]
+			end := strings.IndexByte(text, '"')
+			if end < 0 {
+				// An unterminated quote is ignored.
+				i += size
+				continue
+			}
+			i += size + end + 1
+			items = append(items, webSearchItem{words: TSParse(text[:end]), negated: negated})
+		default:
+			end := strings.IndexFunc(input[i:], func(r rune) bool {
+				return unicode.IsSpace(r) || r == '"'
+			})
+			if end < 0 {
+				end = len(input) - i
+			}
+			word := input[i : i+end]
+			i += end
+			if !negated && strings.EqualFold(word, "or") {
+				items = append(items, webSearchItem{words: []string{word}, or: true})
+			} else {
+				items = append(items, webSearchItem{words: TSParse(word), negated: negated})
+			}
+		}
+		negated = false
+	}
+
+	// Remove the items without tokens, and treat the "or" operators that don't
+	// have a word or phrase on both sides as words.
+	ret := items[:0]
+	for i, item := range items {
+		if item.or {
+			hasRight := false
+			for _, next := range items[i+1:] {
+				if !next.or && len(next.words) > 0 {
+					hasRight = true
+					break
+				}
+			}
+			hasLeft := false
+			for _, prev := range ret {
+				if !prev.or {
+					hasLeft = true
+					break
+				}
+			}
+			item.or = hasLeft && hasRight
+		}
+		if len(item.words) > 0 {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
+
+// NumNodes returns the number of lexemes and operators in the query.
+func (q TSQuery) NumNodes() int {
+	var count func(n *tsNode) int
+	count = func(n *tsNode) int {
+		if n == nil {
+			return 0
+		}
+		return 1 + count(n.l) + count(n.r)
+	}
+	return count(q.root)
+}
+
+// QueryTree returns the string representation of the part of the query that
+// can be used to search an index, which is the query without its negations.
+// It returns "T" if no such part exists.
+func (q TSQuery) QueryTree() string {
+	root := withoutNegations(q.root)
+	if root == nil {
+		return "T"
+	}
+	return root.String()
+}
+
+// withoutNegations returns a copy of the query tree without the negated
+// subtrees. The operands of an or operator are both removed if one of them is
+// negated, since the operator cannot be evaluated without it.
+func withoutNegations(n *tsNode) *tsNode {
+	if n == nil || n.op == not {
+		return nil
+	}
+	if n.op == invalid {
+		return n
+	}
+	l, r := withoutNegations(n.l), withoutNegations(n.r)
+	switch {
+	case l != nil && r != nil:
+		return &tsNode{op: n.op, followedN: n.followedN, l: l, r: r}
+	case n.op == or:
+		return nil
+	case l != nil:
+		return l
+	}
+	return r
+}
+
 func cleanupStopwords(query TSQuery) TSQuery {
 	query.root, _, _ = cleanupStopword(query.root)
 	if query.root == nil {
@@ -295,3 +295,54 @@ func TestParseTSQueryError(t *testing.T) {
 		assert.Error(t, err)
 	}
 }
+
+func TestWebSearchToTSQuery(t *testing.T) {
+	c, err := GetBuiltinConfig("english")
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		input    string
+		expected string
+	}{
+		{`The fat rats`, `'fat' & 'rat'`},
+		{`"supernovae stars" -crab`, `'supernova' <-> 'star' & !'crab'`},
+		{`"sad cat" or "fat rat"`, `'sad' <-> 'cat' | 'fat' <-> 'rat'`},
+		{`signal -"segmentation fault"`, `'signal' & !( 'segment' <-> 'fault' )`},
+		{`""" )( dummy \\ query <->`, `'dummi' & 'queri'`},
+		{`or cat or`, `'cat'`},
+		{`cat OR -dog rat`, `'cat' | !'dog' & 'rat'`},
+		{`-the cats`, `'cat'`},
+		{`web-search`, `'web' <-> 'search'`},
+	} {
+		q, err := c.WebSearchToTSQuery(tc.input)
+		require.NoError(t, err, tc.input)
+		assert.Equal(t, tc.expected, q.String(), tc.input)
+	}
+
+	for _, input := range []string{``, `the "or"`, `-`} {
+		_, err := c.WebSearchToTSQuery(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestTSQueryNumNodesAndQueryTree(t *testing.T) {
+	for _, tc := range []struct {
+		input     string
+		numNodes  int
+		queryTree string
+	}{
+		{`foo`, 1, `'foo'`},
+		{`(fat & rat) | cat`, 5, `'fat' & 'rat' | 'cat'`},
+		{`foo & !bar`, 4, `'foo'`},
+		{`!foo`, 2, `T`},
+		{`!foo | bar`, 4, `T`},
+		{`foo <-> !bar & baz`, 6, `'foo' & 'baz'`},
+		{`foo:* & !(bar | baz)`, 6, `'foo':*`},
+	} {
+		q, err := ParseTSQuery(tc.input)
+		require.NoError(t, err)
+		assert.Equal(t, tc.numNodes, q.NumNodes(), tc.input)
+		assert.Equal(t, tc.queryTree, q.QueryTree(), tc.input)
+		// QueryTree doesn't modify the query.
+		assert.Equal(t, tc.numNodes, q.NumNodes(), tc.input)
+	}
+}
@@ -18,7 +18,6 @@ import (
 	"unicode"
 	"unicode/utf8"
 
-	"github.com/blevesearch/snowballstem"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/errors"
@@ -406,47 +405,154 @@ func TSParse(input string) []string {
 // routines like to_tsvector and to_tsquery.
 // It can return true in the second parameter to indicate a stopword was found.
 func TSLexize(config string, token string) (lexeme string, stopWord bool, err error) {
-	stopwords, ok := stopwordsMap[config]
-	if !ok {
-		return "", false, pgerror.Newf(pgcode.UndefinedObject, "text search configuration %q does not exist", config)
-	}
-
-	lower := strings.ToLower(token)
-	if _, ok := stopwords[lower]; ok {
-		return "", true, nil
+	c, err := GetBuiltinConfig(config)
+	if err != nil {
+		return "", false, err
 	}
-	stemmer, err := getStemmer(config)
+	lexeme, ok, err := c.lexize(token)
 	if err != nil {
 		return "", false, err
 	}
-	env := snowballstem.NewEnv(lower)
-	stemmer(env)
-	return env.Current(), false, nil
+	return lexeme, !ok || lexeme == "", nil
 }
 
 // DocumentToTSVector parses an input document into lexemes, removes stop words,
 // stems and normalizes the lexemes, and returns a TSVector annotated with
 // lexeme positions according to a text search configuration passed by name.
 func DocumentToTSVector(config string, input string) (TSVector, error) {
-	tokens := TSParse(input)
-	vector := make(TSVector, 0, len(tokens))
-	for i := range tokens {
-		lexeme, stopWord, err := TSLexize(config, tokens[i])
+	c, err := GetBuiltinConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return c.DocumentToTSVector(input)
+}
+
+// DocumentToTSVector parses an input document into lexemes, removes stop words,
+// normalizes the lexemes according to the receiver, and returns a TSVector
+// annotated with lexeme positions.
+func (c *Config) DocumentToTSVector(input string) (TSVector, error) {
+	vector, _, err := c.appendDocument(nil /* vector */, 0 /* pos */, input)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeTSVector(vector)
+}
+
+// DocumentsToTSVector is like DocumentToTSVector, but for a list of documents
+// whose lexemes are numbered consecutively. Like in Postgres, the positions of
+// the lexemes of adjacent documents are separated by a gap, so that phrases
+// don't span documents.
+func (c *Config) DocumentsToTSVector(inputs []string) (TSVector, error) {
+	var vector TSVector
+	var pos int
+	for _, input := range inputs {
+		prevPos := pos
+		var err error
+		vector, pos, err = c.appendDocument(vector, pos, input)
 		if err != nil {
 			return nil, err
 		}
-		if stopWord {
-			continue
+		if pos > prevPos {
+			pos++
 		}
+	}
+	return normalizeTSVector(vector)
+}
 
-		term := tsTerm{lexeme: lexeme}
-		pos := i + 1
-		if i > maxTSVectorPosition {
-			// Postgres silently truncates positions larger than 16383 to 16383.
-			pos = maxTSVectorPosition
+// appendDocument appends the lexemes of a document to the vector. Every
+// recognized token, including stop words, is assigned the position after the
+// given one. It returns the position of the last token.
+func (c *Config) appendDocument(vector TSVector, pos int, input string) (TSVector, int, error) {
+	for _, token := range TSParse(input) {
+		lexeme, ok, err := c.lexize(token)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !ok {
+			continue
+		}
+		pos++
+		if lexeme == "" {
+			// Skip stop words.
+			continue
+		}
+		term, err := newLexemeTerm(lexeme)
+		if err != nil {
+			return nil, 0, err
+		}
+		// Postgres silently truncates positions larger than 16383 to 16383.
+		termPos := pos
+		if termPos > maxTSVectorPosition {
+			termPos = maxTSVectorPosition
 		}
-		term.positions = []tsPosition{{position: uint16(pos)}}
+		term.positions = []tsPosition{{position: uint16(termPos)}}
 		vector = append(vector, term)
 	}
-	return normalizeTSVector(vector)
+	return vector, pos, nil
+}
+
+// SetWeight returns a copy of the vector in which the positions of the given
+// lexemes, or of all lexemes if lexemes is nil, are assigned the given weight,
+// which is one of A, B, C or D.
+func (t TSVector) SetWeight(weight string, lexemes []string) (TSVector, error) {
+	var w tsWeight
+	switch strings.ToUpper(weight) {
+	case "A":
+		w = weightA
+	case "B":
+		w = weightB
+	case "C":
+		w = weightC
+	case "D":
+		// Weight D is the default, which is stored as 0.
+	default:
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue, "unrecognized weight: %q", weight)
+	}
+	var set map[string]struct{}
+	if lexemes != nil {
+		set = make(map[string]struct{}, len(lexemes))
+		for _, lexeme := range lexemes {
+			set[lexeme] = struct{}{}
+		}
+	}
+	ret := make(TSVector, len(t))
+	for i, term := range t {
+		ret[i] = term
+		if len(term.positions) == 0 {
+			continue
+		}
+		if _, ok := set[term.lexeme]; set != nil && !ok {
+			continue
+		}
+		ret[i].positions = make([]tsPosition, len(term.positions))
+		for j, pos := range term.positions {
+			ret[i].positions[j] = tsPosition{position: pos.position, weight: w}
+		}
+	}
+	return ret, nil
+}
+
+// Strip returns a copy of the vector without positions and weights.
+func (t TSVector) Strip() TSVector {
+	ret := make(TSVector, len(t))
+	for i, term := range t {
+		ret[i] = tsTerm{lexeme: term.lexeme}
+	}
+	return ret
+}
+
+// ArrayToTSVector returns a vector with the given lexemes, without positions.
+func ArrayToTSVector(lexemes []string) (TSVector, error) {
+	ret := make(TSVector, len(lexemes))
+	for i, lexeme := range lexemes {
+		if lexeme == "" {
+			return nil, pgerror.New(pgcode.ZeroLengthCharacterString, "lexeme array may not contain empty strings")
+		}
+		term, err := newLexemeTerm(lexeme)
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = term
+	}
+	return normalizeTSVector(ret)
 }